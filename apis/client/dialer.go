@@ -0,0 +1,198 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/apis/model"
+	"golang.org/x/net/proxy"
+)
+
+// Dialer connects to destinations through the proxy servers of a mieru
+// client. Its Dial and DialContext methods have the same signature as
+// net.Dialer, so it can be used by golang.org/x/net/proxy and as the
+// DialContext function of net/http.Transport.
+//
+// The destination host can be an IP address or a domain name.
+// Domain names are resolved by the proxy server.
+type Dialer struct {
+	client Client
+}
+
+var (
+	_ proxy.Dialer        = (*Dialer)(nil)
+	_ proxy.ContextDialer = (*Dialer)(nil)
+)
+
+// NewDialer returns a dialer that uses the client.
+// The client must be started before dialing.
+func NewDialer(c Client) *Dialer {
+	return &Dialer{client: c}
+}
+
+// StartDialer creates a mieru client with the config, starts it,
+// and returns a dialer that uses the client.
+// Call Close to stop the client.
+func StartDialer(config *ClientConfig) (*Dialer, error) {
+	c := NewClient()
+	if err := c.Store(config); err != nil {
+		return nil, err
+	}
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	return NewDialer(c), nil
+}
+
+// Dial connects to the address on the named network.
+// It is the same as DialContext with a background context.
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to the address on the named network.
+//
+// Supported networks are "tcp", "tcp4", "tcp6", "udp", "udp4" and "udp6".
+// A UDP connection sends and receives the packets of the destination
+// through a socks5 UDP association, and each Read returns one packet.
+// Packets from other addresses are dropped.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	destination, err := parseDestination(network, address)
+	if err != nil {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: err}
+	}
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		return d.client.DialContext(ctx, destination)
+	case "udp", "udp4", "udp6":
+		conn, err := d.client.DialContext(ctx, destination)
+		if err != nil {
+			return nil, err
+		}
+		return newUDPConn(conn, destination), nil
+	default:
+		return nil, &net.OpError{Op: "dial", Net: network, Err: net.UnknownNetworkError(network)}
+	}
+}
+
+// Close stops the client used by the dialer.
+// Established connections are not terminated.
+func (d *Dialer) Close() error {
+	return d.client.Stop()
+}
+
+// parseDestination returns the network address of a "host:port" string.
+func parseDestination(network, address string) (model.NetAddrSpec, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return model.NetAddrSpec{}, err
+	}
+	if host == "" {
+		return model.NetAddrSpec{}, fmt.Errorf("host is empty")
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 0 || port > 65535 {
+		return model.NetAddrSpec{}, fmt.Errorf("port %q is invalid", portStr)
+	}
+	destination := model.NetAddrSpec{Net: network}
+	destination.Port = port
+	if ip := net.ParseIP(host); ip != nil {
+		destination.IP = ip
+	} else {
+		destination.FQDN = host
+	}
+	return destination, nil
+}
+
+// udpConn is a connected UDP socket over a socks5 UDP association.
+type udpConn struct {
+	net.Conn
+	tunnel      *apicommon.PacketOverStreamTunnel
+	destination model.NetAddrSpec
+	header      []byte // socks5 UDP header of the destination
+}
+
+var _ net.Conn = (*udpConn)(nil)
+
+func newUDPConn(conn net.Conn, destination model.NetAddrSpec) *udpConn {
+	header := bytes.NewBuffer([]byte{0, 0, 0})
+	destination.WriteToSocks5(header)
+	return &udpConn{
+		Conn:        conn,
+		tunnel:      apicommon.NewPacketOverStreamTunnel(conn),
+		destination: destination,
+		header:      header.Bytes(),
+	}
+}
+
+// Read receives a packet from the destination. If the packet is larger
+// than b, it is dropped and io.ErrShortBuffer is returned.
+func (c *udpConn) Read(b []byte) (int, error) {
+	// A socks5 UDP header has at most 262 bytes.
+	buf := make([]byte, len(b)+262)
+	for {
+		n, err := c.tunnel.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		if n <= 3 || buf[0] != 0 || buf[1] != 0 || buf[2] != 0 {
+			return 0, fmt.Errorf("invalid socks5 UDP header")
+		}
+		var from model.AddrSpec
+		r := bytes.NewReader(buf[3:n])
+		if err := from.ReadFromSocks5(r); err != nil {
+			return 0, fmt.Errorf("invalid socks5 UDP header: %w", err)
+		}
+		if from.String() != c.destination.AddrSpec.String() {
+			continue
+		}
+		payload := buf[n-r.Len() : n]
+		if len(payload) > len(b) {
+			return 0, io.ErrShortBuffer
+		}
+		return copy(b, payload), nil
+	}
+}
+
+// Write sends a packet to the destination.
+func (c *udpConn) Write(b []byte) (int, error) {
+	if len(b) > constant.Socks5UDPMaxPayload {
+		return 0, fmt.Errorf("packet size %d is larger than maximum UDP associate payload %d", len(b), constant.Socks5UDPMaxPayload)
+	}
+	packet := make([]byte, 0, len(c.header)+len(b))
+	packet = append(packet, c.header...)
+	packet = append(packet, b...)
+	if _, err := c.tunnel.Write(packet); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// RemoteAddr returns the destination.
+func (c *udpConn) RemoteAddr() net.Addr {
+	if c.destination.IP != nil {
+		return &net.UDPAddr{IP: c.destination.IP, Port: c.destination.Port}
+	}
+	return c.destination
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/apis/model"
+)
+
+// pipeClient returns one end of a pipe for each dial, and records
+// the destination.
+type pipeClient struct {
+	Client
+	destination net.Addr
+	server      net.Conn
+}
+
+func (c *pipeClient) DialContext(_ context.Context, addr net.Addr) (net.Conn, error) {
+	c.destination = addr
+	client, server := net.Pipe()
+	c.server = server
+	return client, nil
+}
+
+func TestDialerTCP(t *testing.T) {
+	c := &pipeClient{}
+	d := NewDialer(c)
+	conn, err := d.Dial("tcp", "example.com:443")
+	if err != nil {
+		t.Fatalf("Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	got, ok := c.destination.(model.NetAddrSpec)
+	if !ok || got.Network() != "tcp" || got.FQDN != "example.com" || got.Port != 443 {
+		t.Errorf("destination = %v, want tcp example.com:443", c.destination)
+	}
+
+	for _, address := range []string{"example.com", ":443", "example.com:65536"} {
+		if _, err := d.Dial("tcp", address); err == nil {
+			t.Errorf("Dial() with address %q returned no error", address)
+		}
+	}
+	if _, err := d.Dial("unix", "/tmp/mieru.sock"); err == nil {
+		t.Errorf("Dial() with unix network returned no error")
+	}
+}
+
+func TestDialerUDP(t *testing.T) {
+	c := &pipeClient{}
+	d := NewDialer(c)
+	conn, err := d.DialContext(context.Background(), "udp", "192.0.2.1:53")
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	if got := conn.RemoteAddr().String(); got != "192.0.2.1:53" {
+		t.Errorf("RemoteAddr() = %s, want 192.0.2.1:53", got)
+	}
+	server := apicommon.NewPacketOverStreamTunnel(c.server)
+
+	// The packet is sent with the socks5 UDP header of the destination.
+	go conn.Write([]byte("query"))
+	b := make([]byte, 64)
+	n, err := server.Read(b)
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	want := append([]byte{0, 0, 0, 1, 192, 0, 2, 1, 0, 53}, []byte("query")...)
+	if !bytes.Equal(b[:n], want) {
+		t.Errorf("packet = %v, want %v", b[:n], want)
+	}
+
+	// Packets from other addresses are dropped.
+	go func() {
+		server.Write(append([]byte{0, 0, 0, 1, 192, 0, 2, 2, 0, 53}, []byte("other")...))
+		server.Write(append([]byte{0, 0, 0, 1, 192, 0, 2, 1, 0, 53}, []byte("answer")...))
+		server.Write(append([]byte{0, 0, 0, 1, 192, 0, 2, 1, 0, 53}, []byte("long answer")...))
+	}()
+	n, err = conn.Read(b)
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if string(b[:n]) != "answer" {
+		t.Errorf("Read() = %q, want %q", b[:n], "answer")
+	}
+	if _, err := conn.Read(make([]byte, 4)); err != io.ErrShortBuffer {
+		t.Errorf("Read() with short buffer returned %v, want %v", err, io.ErrShortBuffer)
+	}
+}
@@ -15,4 +15,21 @@
 
 // Package client provides mieru client APIs for third party applications
 // to integrate mieru protocol.
+//
+// A Go program can connect to destinations through mieru proxy servers
+// without running the mieru command or a local socks5 proxy. Create a
+// Dialer with the client profile, and use it like a net.Dialer:
+//
+//	dialer, err := client.StartDialer(&client.ClientConfig{Profile: profile})
+//	if err != nil {
+//		return err
+//	}
+//	defer dialer.Close()
+//	httpClient := &http.Client{
+//		Transport: &http.Transport{DialContext: dialer.DialContext},
+//	}
+//
+// Dialer also implements the Dialer and ContextDialer interfaces of
+// golang.org/x/net/proxy. To manage the lifecycle of the client
+// separately, create it with NewClient and use NewDialer.
 package client
@@ -88,7 +88,11 @@ type ClientConfig struct {
 
 	// A dialer to connect to proxy server via stream-oriented network connections.
 	//
-	// If this field is not set, a default dialer is used.
+	// If this field is not set, a default dialer is used, which
+	// connects with the bind interface, bind IP and routing mark
+	// of the profile.
+	// If the profile has a transport plugin or SIP003 plugins,
+	// the plugins are used and this field is ignored.
 	Dialer apicommon.Dialer
 
 	// If set, the resolver translates proxy server domain name into IP addresses.
@@ -36,6 +36,7 @@ import (
 	"github.com/enfein/mieru/v3/pkg/log"
 	"github.com/enfein/mieru/v3/pkg/protocol"
 	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/transportplugin"
 )
 
 // This package should not depends on github.com/enfein/mieru/v3/pkg/appctl,
@@ -48,6 +49,8 @@ type mieruClient struct {
 
 	config *ClientConfig
 	mux    *protocol.Mux
+	plugin *transportplugin.Plugin
+	sip003 *transportplugin.SIP003Dialer
 
 	running bool
 }
@@ -102,8 +105,25 @@ func (mc *mieruClient) Start() error {
 	activeProfile := mc.config.Profile
 
 	// Set dialer.
-	if mc.config.Dialer != nil {
+	if activeProfile.GetTransportPlugin() != nil {
+		mc.plugin, err = transportplugin.Start(activeProfile.GetTransportPlugin())
+		if err != nil {
+			return fmt.Errorf("transportplugin.Start() failed: %w", err)
+		}
+		mc.mux.SetDialer(mc.plugin)
+	} else if len(activeProfile.GetSip003Plugins()) > 0 {
+		mc.sip003 = transportplugin.NewSIP003Dialer(activeProfile.GetSip003Plugins())
+		mc.mux.SetDialer(mc.sip003)
+	} else if activeProfile.GetUpstreamProxy() != nil {
+		var next apicommon.Dialer = mc.config.Dialer
+		if next == nil {
+			next = protocol.NewLocalBindingDialer(appctlcommon.ClientLocalBinding(activeProfile))
+		}
+		mc.mux.SetDialer(appctlcommon.UpstreamProxyDialer(activeProfile.GetUpstreamProxy(), next))
+	} else if mc.config.Dialer != nil {
 		mc.mux.SetDialer(mc.config.Dialer)
+	} else {
+		mc.mux.SetDialer(protocol.NewLocalBindingDialer(appctlcommon.ClientLocalBinding(activeProfile)))
 	}
 
 	// Set DNS resolver.
@@ -128,6 +148,7 @@ func (mc *mieruClient) Start() error {
 		hashedPassword = cipher.HashPassword([]byte(user.GetPassword()), []byte(user.GetName()))
 	}
 	mc.mux = mc.mux.SetClientUserNamePassword(user.GetName(), hashedPassword)
+	mc.mux = mc.mux.SetClientCipherSuite(user.GetCipherSuite())
 
 	// Set multiplex factor.
 	multiplexFactor := 1
@@ -142,6 +163,14 @@ func (mc *mieruClient) Start() error {
 		multiplexFactor = 3
 	}
 	mc.mux = mc.mux.SetClientMultiplexFactor(multiplexFactor)
+	mc.mux = mc.mux.SetClientMaxUnderlays(int(activeProfile.GetMultiplexing().GetMaxConnections()))
+	mc.mux = mc.mux.SetClientPathMTUDiscovery(activeProfile.GetPathMTUDiscovery())
+	mc.mux = mc.mux.SetClientHybridKeyExchange(activeProfile.GetHybridKeyExchange())
+	mc.mux = mc.mux.SetClientMultipathDevices(activeProfile.GetMultipath().GetInterfaces())
+	mc.mux = mc.mux.SetClientLocalBinding(appctlcommon.ClientLocalBinding(activeProfile))
+	mc.mux = mc.mux.SetClientHeartbeat(appctlcommon.HeartbeatFromConfig(activeProfile.GetHeartbeat()))
+	mc.mux = mc.mux.SetClientReconnect(appctlcommon.ReconnectPolicyFromConfig(activeProfile.GetReconnect()))
+	mc.mux = mc.mux.SetKeyRotation(appctlcommon.KeyRotationFromConfig(activeProfile.GetKeyRotation()))
 
 	// Set server endpoints.
 	mtu := common.DefaultMTU
@@ -149,58 +178,112 @@ func (mc *mieruClient) Start() error {
 		mtu = int(activeProfile.GetMtu())
 	}
 	endpoints := make([]protocol.UnderlayProperties, 0)
+	serverResolver := resolver
+	if activeProfile.GetBootstrapDoHURL() != "" {
+		enableDNS = true
+		serverResolver = &common.DoHResolver{URL: activeProfile.GetBootstrapDoHURL()}
+	}
 	for _, serverInfo := range activeProfile.GetServers() {
-		var proxyHost string
-		var proxyIP net.IP
-		if serverInfo.GetDomainName() != "" {
-			proxyHost = serverInfo.GetDomainName()
-			if enableDNS {
-				proxyIPs, err := resolver.LookupIP(context.Background(), "ip", proxyHost)
-				if err != nil {
-					return fmt.Errorf(stderror.LookupIPFailedErr, err)
-				}
-				if len(proxyIPs) == 0 {
-					return fmt.Errorf(stderror.IPAddressNotFound, proxyHost)
-				}
-				proxyIP = proxyIPs[0]
-			}
-		} else {
-			proxyHost = serverInfo.GetIpAddress()
-			proxyIP = net.ParseIP(proxyHost)
-			if proxyIP == nil {
-				return fmt.Errorf(stderror.ParseIPFailed)
+		// If DNS is disabled, the domain name is resolved when dialing.
+		proxyHost := serverInfo.GetDomainName()
+		proxyIPs := []net.IP{nil}
+		if proxyHost == "" || enableDNS {
+			var err error
+			proxyIPs, err = appctlcommon.ResolveServerIPs(context.Background(), serverInfo, serverResolver)
+			if err != nil {
+				return err
 			}
 		}
-		portBindings, err := appctlcommon.FlatPortBindings(serverInfo.GetPortBindings())
-		if err != nil {
+		if _, err := appctlcommon.FlatPortBindings(serverInfo.GetPortBindings()); err != nil {
 			return fmt.Errorf(stderror.InvalidPortBindingsErr, err)
 		}
-		for _, bindingInfo := range portBindings {
-			proxyPort := bindingInfo.GetPort()
-			var endpoint protocol.UnderlayProperties
-			switch bindingInfo.GetProtocol() {
-			case appctlpb.TransportProtocol_TCP:
+		portBindings, _ := appctlcommon.FlatPortBindings(appctlcommon.PortBindingsWithoutHopping(serverInfo.GetPortBindings()))
+		hoppingOpts := appctlcommon.PortHoppingOptionsFromConfig(serverInfo.GetPortBindings())
+		knockingOpts := appctlcommon.PortKnockingOptionsFromConfig(serverInfo.GetPortKnocking())
+		for _, proxyIP := range proxyIPs {
+			for _, opts := range hoppingOpts {
+				var endpoint protocol.UnderlayProperties
 				if proxyIP != nil {
-					endpoint = protocol.NewUnderlayProperties(mtu, common.StreamTransport, nil, &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)})
-				} else {
-					endpoint = protocol.NewUnderlayProperties(mtu, common.StreamTransport, nil,
-						&model.NetAddrSpec{Net: "tcp", AddrSpec: model.AddrSpec{FQDN: proxyHost, Port: int(proxyPort)}},
-					)
-				}
-			case appctlpb.TransportProtocol_UDP:
-				if proxyIP != nil {
-					endpoint = protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil, &net.UDPAddr{IP: proxyIP, Port: int(proxyPort)})
+					endpoint = protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil, &net.UDPAddr{IP: proxyIP, Port: opts.BeginPort})
 				} else {
 					endpoint = protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil,
-						&model.NetAddrSpec{Net: "udp", AddrSpec: model.AddrSpec{FQDN: proxyHost, Port: int(proxyPort)}},
+						&model.NetAddrSpec{Net: "udp", AddrSpec: model.AddrSpec{FQDN: proxyHost, Port: opts.BeginPort}},
 					)
 				}
-			default:
-				return fmt.Errorf(stderror.InvalidTransportProtocol)
-			}
-			if endpoint != nil {
+				endpoint = protocol.WithPortHopping(endpoint, opts)
+				if knockingOpts != nil {
+					endpoint = protocol.WithPortKnocking(endpoint, knockingOpts)
+				}
 				endpoints = append(endpoints, endpoint)
 			}
+			for _, bindingInfo := range portBindings {
+				proxyPort := bindingInfo.GetPort()
+				var endpoint protocol.UnderlayProperties
+				switch bindingInfo.GetProtocol() {
+				case appctlpb.TransportProtocol_TCP:
+					var remoteAddr net.Addr
+					if proxyIP != nil {
+						remoteAddr = &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)}
+					} else {
+						remoteAddr = &model.NetAddrSpec{Net: "tcp", AddrSpec: model.AddrSpec{FQDN: proxyHost, Port: int(proxyPort)}}
+					}
+					if opts := appctlcommon.ClientTLSCamouflageOptions(serverInfo); opts != nil {
+						endpoint = protocol.NewTLSCamouflageUnderlayProperties(mtu, nil, remoteAddr, opts)
+					} else {
+						endpoint = protocol.NewUnderlayProperties(mtu, common.StreamTransport, nil, remoteAddr)
+					}
+				case appctlpb.TransportProtocol_UDP:
+					if proxyIP != nil {
+						endpoint = protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil, &net.UDPAddr{IP: proxyIP, Port: int(proxyPort)})
+					} else {
+						endpoint = protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil,
+							&model.NetAddrSpec{Net: "udp", AddrSpec: model.AddrSpec{FQDN: proxyHost, Port: int(proxyPort)}},
+						)
+					}
+				case appctlpb.TransportProtocol_WEBSOCKET:
+					webSocketOpts := appctlcommon.ClientWebSocketOptions(serverInfo)
+					if proxyIP != nil {
+						endpoint = protocol.NewWebSocketUnderlayProperties(mtu, nil, &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)}, webSocketOpts)
+					} else {
+						endpoint = protocol.NewWebSocketUnderlayProperties(mtu, nil,
+							&model.NetAddrSpec{Net: "tcp", AddrSpec: model.AddrSpec{FQDN: proxyHost, Port: int(proxyPort)}},
+							webSocketOpts,
+						)
+					}
+				case appctlpb.TransportProtocol_AUTO:
+					// The multiplexer selects TCP or UDP by latency.
+					var tcpAddr, udpAddr net.Addr
+					if proxyIP != nil {
+						tcpAddr = &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)}
+						udpAddr = &net.UDPAddr{IP: proxyIP, Port: int(proxyPort)}
+					} else {
+						tcpAddr = &model.NetAddrSpec{Net: "tcp", AddrSpec: model.AddrSpec{FQDN: proxyHost, Port: int(proxyPort)}}
+						udpAddr = &model.NetAddrSpec{Net: "udp", AddrSpec: model.AddrSpec{FQDN: proxyHost, Port: int(proxyPort)}}
+					}
+					var tcpEndpoint protocol.UnderlayProperties
+					if opts := appctlcommon.ClientTLSCamouflageOptions(serverInfo); opts != nil {
+						tcpEndpoint = protocol.NewTLSCamouflageUnderlayProperties(mtu, nil, tcpAddr, opts)
+					} else {
+						tcpEndpoint = protocol.NewUnderlayProperties(mtu, common.StreamTransport, nil, tcpAddr)
+					}
+					udpEndpoint := protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil, udpAddr)
+					for _, endpoint := range []protocol.UnderlayProperties{tcpEndpoint, udpEndpoint} {
+						endpoint = protocol.WithAutoTransport(endpoint)
+						if knockingOpts != nil {
+							endpoint = protocol.WithPortKnocking(endpoint, knockingOpts)
+						}
+						endpoints = append(endpoints, endpoint)
+					}
+				default:
+					return fmt.Errorf(stderror.InvalidTransportProtocol)
+				}
+				if endpoint != nil {
+					if knockingOpts != nil {
+						endpoint = protocol.WithPortKnocking(endpoint, knockingOpts)
+					}
+					endpoints = append(endpoints, endpoint)
+				}
+			}
 		}
 	}
 	mc.mux.SetEndpoints(endpoints)
@@ -217,6 +300,12 @@ func (mc *mieruClient) Stop() error {
 	if mc.mux != nil {
 		mc.mux.Close()
 	}
+	if mc.plugin != nil {
+		mc.plugin.Close()
+	}
+	if mc.sip003 != nil {
+		mc.sip003.Close()
+	}
 	return nil
 }
 
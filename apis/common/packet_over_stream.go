@@ -32,10 +32,17 @@ import (
 // the length is encoded with big endian.
 //
 // The destination of packets are negotiated separately.
+//
+// If the buffer given to Read is smaller than the packet, the packet is
+// discarded and io.ErrShortBuffer is returned. The next Read returns the
+// next packet.
 type PacketOverStreamTunnel struct {
 	net.Conn
 }
 
+// maxPacketLength is the maximum length of a packet in the tunnel.
+const maxPacketLength = 65535
+
 var _ net.PacketConn = (*PacketOverStreamTunnel)(nil)
 
 func (c *PacketOverStreamTunnel) Read(p []byte) (n int, err error) {
@@ -53,6 +60,10 @@ func (c *PacketOverStreamTunnel) Read(p []byte) (n int, err error) {
 	}
 	length := int(binary.BigEndian.Uint16(lengthBytes))
 	if length > len(p) {
+		// Discard the packet to keep the packet boundary.
+		if _, err = io.CopyN(io.Discard, c.Conn, int64(length)+1); err != nil {
+			return 0, err
+		}
 		return 0, io.ErrShortBuffer
 	}
 
@@ -70,8 +81,8 @@ func (c *PacketOverStreamTunnel) Read(p []byte) (n int, err error) {
 }
 
 func (c *PacketOverStreamTunnel) Write(p []byte) (int, error) {
-	if len(p) > 65535 {
-		return 0, fmt.Errorf("packet length %d is larger than maximum length 65535", len(p))
+	if len(p) > maxPacketLength {
+		return 0, fmt.Errorf("packet length %d is larger than maximum length %d", len(p), maxPacketLength)
 	}
 	data := make([]byte, 4+len(p))
 	data[0] = 0x00
@@ -16,6 +16,8 @@
 package common_test
 
 import (
+	"errors"
+	"io"
 	"testing"
 
 	"github.com/enfein/mieru/v3/apis/common"
@@ -52,3 +54,34 @@ func TestUDPAssociateTunnelConn(t *testing.T) {
 		t.Errorf("Close() failed: %v", err)
 	}
 }
+
+func TestPacketOverStreamTunnelShortBuffer(t *testing.T) {
+	in, out := testtool.BufPipe()
+	inConn := common.NewPacketOverStreamTunnel(in)
+	outConn := common.NewPacketOverStreamTunnel(out)
+	defer inConn.Close()
+	defer outConn.Close()
+
+	if _, err := inConn.Write(make([]byte, 32)); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if _, err := inConn.Write([]byte{1, 2}); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if _, err := inConn.Write(make([]byte, 65536)); err == nil {
+		t.Errorf("Write() succeeded with a packet larger than 65535 bytes")
+	}
+
+	// The large packet is dropped, and the next packet is still readable.
+	buf := make([]byte, 16)
+	if _, err := outConn.Read(buf); !errors.Is(err, io.ErrShortBuffer) {
+		t.Fatalf("Read() returns error %v, want %v", err, io.ErrShortBuffer)
+	}
+	n, err := outConn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if n != 2 || buf[0] != 1 || buf[1] != 2 {
+		t.Errorf("Read() got %v, want [1 2]", buf[:n])
+	}
+}
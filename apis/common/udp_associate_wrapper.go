@@ -18,8 +18,10 @@ package common
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net"
 
+	"github.com/enfein/mieru/v3/apis/constant"
 	"github.com/enfein/mieru/v3/apis/model"
 )
 
@@ -29,6 +31,8 @@ type UDPAssociateWrapper struct {
 }
 
 // ReadFrom receives a packet and strips the socks5 UDP associate header.
+// If the payload is larger than p, the packet is dropped and
+// io.ErrShortBuffer is returned.
 func (w *UDPAssociateWrapper) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
 	b := make([]byte, len(p)+256) // add some space to parse the header
 	n, addr, err = w.PacketConn.ReadFrom(b)
@@ -61,7 +65,12 @@ func (w *UDPAssociateWrapper) ReadFrom(p []byte) (n int, addr net.Addr, err erro
 		return
 	}
 
-	n, err = r.Read(p)
+	if r.Len() > len(p) {
+		n = 0
+		err = io.ErrShortBuffer
+		return
+	}
+	n, _ = r.Read(p)
 	// Caller may expect the returned address to be *net.UDPAddr.
 	addr = &net.UDPAddr{
 		IP:   destination.IP,
@@ -71,7 +80,12 @@ func (w *UDPAssociateWrapper) ReadFrom(p []byte) (n int, addr net.Addr, err erro
 }
 
 // WriteTo adds a socks5 UDP associate header and sends the packet.
+// The packet must not be larger than constant.Socks5UDPMaxPayload.
 func (w *UDPAssociateWrapper) WriteTo(p []byte, addr net.Addr) (n int, err error) {
+	if len(p) > constant.Socks5UDPMaxPayload {
+		err = fmt.Errorf("packet size %d is larger than maximum UDP associate payload %d", len(p), constant.Socks5UDPMaxPayload)
+		return
+	}
 	var destination model.NetAddrSpec
 	if err = destination.From(addr); err != nil {
 		return
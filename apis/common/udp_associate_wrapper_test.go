@@ -16,10 +16,13 @@
 package common_test
 
 import (
+	"errors"
+	"io"
 	"net"
 	"testing"
 
 	"github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/apis/constant"
 	"github.com/enfein/mieru/v3/apis/model"
 	"github.com/enfein/mieru/v3/pkg/testtool"
 )
@@ -63,3 +66,25 @@ func TestUDPAssociateWrapper(t *testing.T) {
 		t.Errorf("Close() failed: %v", err)
 	}
 }
+
+func TestUDPAssociateWrapperPayloadLimit(t *testing.T) {
+	in, out := testtool.BufPipe()
+	inConn := common.NewUDPAssociateWrapper(common.NewPacketOverStreamTunnel(in))
+	outConn := common.NewUDPAssociateWrapper(common.NewPacketOverStreamTunnel(out))
+	defer inConn.Close()
+	defer outConn.Close()
+
+	addr := model.NetAddrSpec{Net: "udp", AddrSpec: model.AddrSpec{IP: net.IPv4(1, 1, 1, 1), Port: 8964}}
+	if _, err := inConn.WriteTo(make([]byte, constant.Socks5UDPMaxPayload+1), addr); err == nil {
+		t.Errorf("WriteTo() succeeded with a payload larger than %d bytes", constant.Socks5UDPMaxPayload)
+	}
+	if _, err := inConn.WriteTo(make([]byte, 32), addr); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+
+	// The payload is not truncated.
+	buf := make([]byte, 16)
+	if _, _, err := outConn.ReadFrom(buf); !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("ReadFrom() returns error %v, want %v", err, io.ErrShortBuffer)
+	}
+}
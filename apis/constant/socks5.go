@@ -37,6 +37,7 @@ const (
 // socks5 authentication options.
 const (
 	Socks5NoAuth           byte = 0
+	Socks5GSSAPIAuth       byte = 1
 	Socks5UserPassAuth     byte = 2
 	Socks5NoAcceptableAuth byte = 255
 
@@ -45,3 +46,40 @@ const (
 	Socks5AuthSuccess byte = 0
 	Socks5AuthFailure byte = 1
 )
+
+// socks5 GSSAPI authentication messages defined in RFC 1961.
+const (
+	Socks5GSSAPIVersion byte = 1
+
+	Socks5GSSAPIAuthMessage       byte = 1
+	Socks5GSSAPIProtectionMessage byte = 2
+	Socks5GSSAPIDataMessage       byte = 3
+	Socks5GSSAPIAbortMessage      byte = 255
+
+	Socks5GSSAPINoProtection        byte = 0
+	Socks5GSSAPIIntegrityProtection byte = 1
+)
+
+// socks5 UDP associate limits.
+const (
+	// Socks5UDPMaxHeaderLength is the maximum length of socks5 UDP
+	// associate header, which has a domain name of 255 bytes.
+	Socks5UDPMaxHeaderLength = 3 + 1 + 1 + 255 + 2
+
+	// Socks5UDPMaxPayload is the maximum payload of a UDP datagram relayed
+	// by socks5 UDP associate. The payload and a header of any address
+	// type must fit into one encapsulated packet of at most 65535 bytes.
+	// Larger datagrams are dropped.
+	Socks5UDPMaxPayload = 65535 - Socks5UDPMaxHeaderLength
+)
+
+// Reserved socks5 destinations served by mita server itself for testing.
+// They are only available if test endpoints are enabled in server config,
+// and the user is allowed to use them.
+const (
+	// Data sent to the echo destination is sent back.
+	Socks5EchoDestination = "echo.mieru.test"
+
+	// Data sent to the sink destination is discarded.
+	Socks5SinkDestination = "sink.mieru.test"
+)
@@ -26,8 +26,12 @@ import (
 	"github.com/enfein/mieru/v3/apis/constant"
 )
 
+// MaxFQDNLength is the maximum length of a domain name in socks5 protocol.
+const MaxFQDNLength = 255
+
 var (
 	ErrUnrecognizedAddrType = errors.New("unrecognized address type")
+	ErrInvalidFQDN          = errors.New("invalid domain name")
 )
 
 // AddrSpec is used to specify an IPv4, IPv6, or a FQDN address
@@ -78,6 +82,9 @@ func (a *AddrSpec) ReadFromSocks5(r io.Reader) error {
 		if _, err := io.ReadFull(r, fqdn); err != nil {
 			return err
 		}
+		if err := validateFQDN(string(fqdn)); err != nil {
+			return err
+		}
 		a.FQDN = string(fqdn)
 	default:
 		return ErrUnrecognizedAddrType
@@ -106,6 +113,9 @@ func (a AddrSpec) WriteToSocks5(w io.Writer) error {
 		b.WriteByte(constant.Socks5IPv6Address)
 		b.Write(a.IP.To16())
 	case a.FQDN != "":
+		if err := validateFQDN(a.FQDN); err != nil {
+			return err
+		}
 		b.WriteByte(constant.Socks5FQDNAddress)
 		b.WriteByte(byte(len(a.FQDN)))
 		b.Write([]byte(a.FQDN))
@@ -120,6 +130,25 @@ func (a AddrSpec) WriteToSocks5(w io.Writer) error {
 	return err
 }
 
+// validateFQDN returns ErrInvalidFQDN if the domain name is empty,
+// too long, or has a space or control character. An empty domain name
+// is dialed as the local system, and a space or control character
+// is never used by a valid domain name.
+func validateFQDN(fqdn string) error {
+	if len(fqdn) == 0 {
+		return fmt.Errorf("%w: domain name is empty", ErrInvalidFQDN)
+	}
+	if len(fqdn) > MaxFQDNLength {
+		return fmt.Errorf("%w: domain name length %d exceeds %d", ErrInvalidFQDN, len(fqdn), MaxFQDNLength)
+	}
+	for i := 0; i < len(fqdn); i++ {
+		if fqdn[i] <= ' ' || fqdn[i] == 0x7f {
+			return fmt.Errorf("%w: domain name has character 0x%02x", ErrInvalidFQDN, fqdn[i])
+		}
+	}
+	return nil
+}
+
 // NetAddrSpec is a AddrSpec with a network type.
 // It implements the net.Addr interface.
 type NetAddrSpec struct {
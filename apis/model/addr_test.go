@@ -17,8 +17,10 @@ package model
 
 import (
 	"bytes"
+	"errors"
 	"net"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/enfein/mieru/v3/apis/constant"
@@ -121,6 +123,33 @@ func TestAddrSpecReadWrite(t *testing.T) {
 	}
 }
 
+func TestAddrSpecInvalidFQDN(t *testing.T) {
+	inputs := [][]byte{
+		{constant.Socks5FQDNAddress, 0, 0, 80},
+		{constant.Socks5FQDNAddress, 4, 'a', ' ', 'b', 'c', 0, 80},
+		{constant.Socks5FQDNAddress, 4, 'a', 0, 'b', 'c', 0, 80},
+	}
+	for _, input := range inputs {
+		addr := &AddrSpec{}
+		if err := addr.ReadFromSocks5(bytes.NewBuffer(input)); !errors.Is(err, ErrInvalidFQDN) {
+			t.Errorf("ReadFromSocks5(%v) returned error %v, want %v", input, err, ErrInvalidFQDN)
+		}
+	}
+
+	addr := AddrSpec{FQDN: strings.Repeat("a", MaxFQDNLength+1), Port: 80}
+	if err := addr.WriteToSocks5(&bytes.Buffer{}); !errors.Is(err, ErrInvalidFQDN) {
+		t.Errorf("WriteToSocks5() returned error %v, want %v", err, ErrInvalidFQDN)
+	}
+	addr.FQDN = strings.Repeat("a", MaxFQDNLength)
+	var b bytes.Buffer
+	if err := addr.WriteToSocks5(&b); err != nil {
+		t.Fatalf("WriteToSocks5() failed: %v", err)
+	}
+	if b.Len() != 1+1+MaxFQDNLength+2 {
+		t.Errorf("WriteToSocks5() wrote %d bytes, want %d", b.Len(), 1+1+MaxFQDNLength+2)
+	}
+}
+
 func TestNetAddrSpecFrom(t *testing.T) {
 	testCases := []struct {
 		netAddrSpec NetAddrSpec
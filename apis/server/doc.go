@@ -0,0 +1,42 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package server provides mieru server APIs for third party applications
+// to embed a mieru proxy server.
+//
+// A Go program can run a proxy server without the mita command, for
+// example in a larger service or in tests. Users can be added and
+// removed while the server is running:
+//
+//	s := server.NewServer()
+//	if err := s.Store(&server.ServerConfig{Config: config}); err != nil {
+//		return err
+//	}
+//	if err := s.Start(); err != nil {
+//		return err
+//	}
+//	defer s.Stop()
+//	err := s.AddUser(&appctlpb.User{
+//		Name:     proto.String("alice"),
+//		Password: proto.String("secret"),
+//	})
+//
+// The server config is validated in the same way as mita. Settings of
+// the whole process, such as logging, dropping privileges, sandbox and
+// management services, are not applied.
+//
+// Unlike package client, this package depends on package appctl to share
+// the server config conversion with mita, which introduces gRPC dependency.
+package server
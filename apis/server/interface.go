@@ -0,0 +1,123 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"errors"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+)
+
+var (
+	ErrNoServerConfig              = errors.New("no server config")
+	ErrInvalidServerConfig         = errors.New("invalid server config")
+	ErrServerIsNotRunning          = errors.New("server is not running")
+	ErrStoreServerConfigAfterStart = errors.New("can't store server config after start")
+	ErrUserNotFound                = errors.New("user not found")
+)
+
+// Server contains methods supported by a mieru server.
+type Server interface {
+	ServerConfigurationService
+	ServerLifecycleService
+	ServerUserService
+	ServerMetricsService
+}
+
+// ServerConfigurationService contains methods to manage proxy server configuration.
+type ServerConfigurationService interface {
+	// Load returns the server config.
+	// It returns ErrNoServerConfig if server config is never stored.
+	Load() (*ServerConfig, error)
+
+	// Store saves the server config.
+	// It returns wrapped ErrInvalidServerConfig error
+	// if the provided server config is invalid.
+	// It returns ErrStoreServerConfigAfterStart error
+	// if it is called after start.
+	Store(*ServerConfig) error
+}
+
+// ServerLifecycleService contains methods to manage proxy server lifecycle.
+type ServerLifecycleService interface {
+	// Start listens to the port bindings and serves the proxy clients
+	// with the stored configuration. At least one user must be registered.
+	// It returns after the server has tried to listen to all the port bindings.
+	// Calling Start function more than once has undefined behavior.
+	Start() error
+
+	// Stop stops listening to the port bindings and closes the
+	// established connections.
+	// After stop, the server can't be reused.
+	Stop() error
+
+	// IsRunning returns true if the server has been started
+	// and has not been stopped.
+	IsRunning() bool
+}
+
+// ServerUserService contains methods to manage the users of proxy server.
+// The changes are applied to the stored server config, and take effect
+// immediately if the server is running. Like mita, established
+// connections of a removed user are not terminated.
+type ServerUserService interface {
+	// AddUser registers a user. If a user with the same name exists,
+	// it is replaced.
+	// It returns ErrNoServerConfig if server config is never stored.
+	// It returns wrapped ErrInvalidServerConfig error
+	// if the provided user is invalid.
+	AddUser(*appctlpb.User) error
+
+	// RemoveUser removes the user with the name from the users and
+	// the user groups.
+	// It returns ErrUserNotFound if the user doesn't exist.
+	RemoveUser(name string) error
+
+	// Users returns a copy of the registered users.
+	Users() []*appctlpb.User
+}
+
+// ServerMetricsService contains methods to read proxy server metrics.
+// Metrics are shared by all the mieru servers and clients in the same process.
+type ServerMetricsService interface {
+	// Metrics returns all the metrics in JSON format.
+	Metrics() ([]byte, error)
+
+	// UserMetrics returns the metrics of the user, indexed by metric name.
+	// It returns nil if the user has no metrics.
+	UserMetrics(name string) map[string]int64
+}
+
+// ServerConfig stores proxy server configuration.
+type ServerConfig struct {
+	Config *appctlpb.ServerConfig
+
+	// If set, MetricsHook is called periodically while the server is running,
+	// for example to export the metrics to a monitoring system.
+	MetricsHook func(ServerMetricsService)
+
+	// The interval to call MetricsHook. If this field is not set,
+	// the interval is 1 minute.
+	MetricsHookInterval time.Duration
+}
+
+// NewServer creates a blank mieru server with no server config.
+func NewServer() Server {
+	ms := &mieruServer{}
+	ms.initOnce()
+	return ms
+}
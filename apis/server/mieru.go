@@ -0,0 +1,364 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/appctl"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/blocklist"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"github.com/enfein/mieru/v3/pkg/socks5"
+	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/transportplugin"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultMetricsHookInterval = time.Minute
+
+// mieruServer is the official implementation of mieru server APIs.
+type mieruServer struct {
+	initTask sync.Once
+	mu       sync.RWMutex
+
+	config       *ServerConfig
+	mux          *protocol.Mux
+	sip003       *transportplugin.SIP003Server
+	socks5Server *socks5.Server
+	blocklist    *blocklist.Blocklist
+	auditLog     *socks5.AuditLog
+	done         chan struct{}
+
+	running bool
+}
+
+var _ Server = &mieruServer{}
+
+// initOnce should be called when constructing the mieru server.
+func (ms *mieruServer) initOnce() {
+	ms.initTask.Do(func() {
+		// Disable log.
+		log.SetFormatter(&log.NilFormatter{})
+	})
+}
+
+func (ms *mieruServer) Load() (*ServerConfig, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.config == nil {
+		return nil, ErrNoServerConfig
+	}
+	return ms.config, nil
+}
+
+func (ms *mieruServer) Store(config *ServerConfig) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if config == nil || config.Config == nil {
+		return fmt.Errorf("%w: server config is nil", ErrInvalidServerConfig)
+	}
+	if config.MetricsHookInterval < 0 {
+		return fmt.Errorf("%w: metrics hook interval is negative", ErrInvalidServerConfig)
+	}
+	if ms.running {
+		return ErrStoreServerConfigAfterStart
+	}
+	if err := appctl.ValidateFullServerConfig(config.Config); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidServerConfig, err.Error())
+	}
+	// Users are changed by AddUser and RemoveUser,
+	// so don't modify the config of the caller.
+	stored := *config
+	stored.Config = proto.Clone(config.Config).(*appctlpb.ServerConfig)
+	ms.config = &stored
+	return nil
+}
+
+func (ms *mieruServer) Start() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.config == nil {
+		return ErrNoServerConfig
+	}
+	config := ms.config.Config
+
+	if err := appctl.ApplyReplayCacheConfig(config.GetAdvancedSettings().GetReplayCache()); err != nil {
+		return err
+	}
+	mux := protocol.NewMux(false).
+		SetServerUsers(appctl.UserListToMap(config.GetUsers())).
+		SetServerUserGroups(appctl.UserGroupsByUserName(config.GetUserGroups())).
+		SetServerOverloadLimits(appctlcommon.OverloadLimitsFromConfig(config.GetAdvancedSettings())).
+		SetKeyRotation(appctlcommon.KeyRotationFromConfig(config.GetKeyRotation()))
+	mtu := common.DefaultMTU
+	if config.GetMtu() != 0 {
+		mtu = int(config.GetMtu())
+	}
+	endpoints, err := appctl.PortBindingsToUnderlayProperties(config.GetPortBindings(), mtu, config.GetWebSocket(), config.GetTlsCamouflage(), config.GetDecoy(), config.GetProxyProtocol())
+	if err != nil {
+		return err
+	}
+	if err := mux.SetServerPortKnocking(appctlcommon.PortKnockingOptionsFromConfig(config.GetPortKnocking())); err != nil {
+		return err
+	}
+	udpRelay, err := appctl.UDPRelayToSocks5(config.GetUdpRelay())
+	if err != nil {
+		return err
+	}
+	countryStats, geoIP, err := appctl.NewServerCountryStats(config.GetCountryTraffic())
+	if err != nil {
+		return err
+	}
+	egressGeoIP, egressGeosite, err := appctlcommon.LoadGeoDatabases(config.GetGeoDatabases())
+	if err != nil {
+		return err
+	}
+	serverBlocklist := blocklist.New()
+	if err := appctl.UpdateServerBlocklist(serverBlocklist, config.GetBlocklist()); err != nil {
+		return err
+	}
+	auditLog, err := appctl.NewServerAuditLog(config.GetAuditLog())
+	if err != nil {
+		return err
+	}
+
+	// Create the egress socks5 server.
+	socks5Server, err := socks5.New(&socks5.Config{
+		AuthOpts: socks5.Auth{
+			ClientSideAuthentication: true,
+		},
+		DualStackPreference: common.DualStackPreference(config.GetDns().GetDualStack()),
+		Egress:              config.GetEgress(),
+		EnableTestEndpoints: config.GetAdvancedSettings().GetEnableTestEndpoints(),
+		HandshakeTimeout:    10 * time.Second,
+		Users:               appctl.UserListToMap(config.GetUsers()),
+		UserGroups:          appctl.UserGroupsByUserName(config.GetUserGroups()),
+		UDPRelay:            udpRelay,
+		CountryStats:        countryStats,
+		GeoIP:               geoIP,
+		EgressGeoIP:         egressGeoIP,
+		EgressGeosite:       egressGeosite,
+		Blocklist:           serverBlocklist,
+		AuditLog:            auditLog,
+	})
+	if err != nil {
+		if auditLog != nil {
+			auditLog.Close()
+		}
+		return fmt.Errorf(stderror.CreateSocks5ServerFailedErr, err)
+	}
+
+	// Run the SIP003 plugins in front of the port bindings.
+	sip003 := transportplugin.NewSIP003Server()
+	closeOnError := func() {
+		mux.Close()
+		sip003.Close()
+		if auditLog != nil {
+			auditLog.Close()
+		}
+	}
+	endpoints, err = sip003.Update(config.GetSip003Plugins(), endpoints)
+	if err != nil {
+		closeOnError()
+		return err
+	}
+	mux.SetEndpoints(endpoints)
+	if err := mux.Start(); err != nil {
+		closeOnError()
+		return fmt.Errorf("mux.Start() failed: %w", err)
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+	if err := mux.WaitListening(ctx); err != nil {
+		closeOnError()
+		return fmt.Errorf("mux.WaitListening() failed: %w", err)
+	}
+	serverBlocklist.Start()
+	go func() {
+		if err := socks5Server.Serve(mux); err != nil {
+			log.Errorf("run socks5 server failed: %v", err)
+		}
+	}()
+
+	ms.mux = mux
+	ms.sip003 = sip003
+	ms.socks5Server = socks5Server
+	ms.blocklist = serverBlocklist
+	ms.auditLog = auditLog
+	ms.done = make(chan struct{})
+	if ms.config.MetricsHook != nil {
+		interval := ms.config.MetricsHookInterval
+		if interval == 0 {
+			interval = defaultMetricsHookInterval
+		}
+		go ms.runMetricsHook(ms.config.MetricsHook, interval, ms.done)
+	}
+	ms.running = true
+	return nil
+}
+
+func (ms *mieruServer) Stop() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.running = false
+	if ms.done != nil {
+		close(ms.done)
+		ms.done = nil
+	}
+	if ms.socks5Server != nil {
+		ms.socks5Server.Close()
+		ms.socks5Server = nil
+	}
+	if ms.mux != nil {
+		ms.mux.Close()
+		ms.mux = nil
+	}
+	if ms.sip003 != nil {
+		ms.sip003.Close()
+		ms.sip003 = nil
+	}
+	if ms.blocklist != nil {
+		ms.blocklist.Close()
+		ms.blocklist = nil
+	}
+	if ms.auditLog != nil {
+		ms.auditLog.Close()
+		ms.auditLog = nil
+	}
+	return nil
+}
+
+func (ms *mieruServer) IsRunning() bool {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.running
+}
+
+func (ms *mieruServer) AddUser(user *appctlpb.User) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.config == nil {
+		return ErrNoServerConfig
+	}
+	if user == nil {
+		return fmt.Errorf("%w: user is nil", ErrInvalidServerConfig)
+	}
+	if err := appctl.ValidateServerConfigPatch(&appctlpb.ServerConfig{Users: []*appctlpb.User{user}}); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidServerConfig, err.Error())
+	}
+	user = proto.Clone(user).(*appctlpb.User)
+	config := ms.config.Config
+	replaced := false
+	for i, u := range config.GetUsers() {
+		if u.GetName() == user.GetName() {
+			config.Users[i] = user
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		config.Users = append(config.Users, user)
+	}
+	ms.applyUsers()
+	return nil
+}
+
+func (ms *mieruServer) RemoveUser(name string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.config == nil {
+		return ErrUserNotFound
+	}
+	config := ms.config.Config
+	remaining := make([]*appctlpb.User, 0, len(config.GetUsers()))
+	for _, u := range config.GetUsers() {
+		if u.GetName() != name {
+			remaining = append(remaining, u)
+		}
+	}
+	if len(remaining) == len(config.GetUsers()) {
+		return ErrUserNotFound
+	}
+	config.Users = remaining
+	appctl.RemoveUsersFromGroups(config.GetUserGroups(), []string{name})
+	ms.applyUsers()
+	return nil
+}
+
+func (ms *mieruServer) Users() []*appctlpb.User {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	if ms.config == nil {
+		return nil
+	}
+	users := make([]*appctlpb.User, 0, len(ms.config.Config.GetUsers()))
+	for _, u := range ms.config.Config.GetUsers() {
+		users = append(users, proto.Clone(u).(*appctlpb.User))
+	}
+	return users
+}
+
+// applyUsers updates the users and user groups of the running server.
+// It must be called with the lock held.
+func (ms *mieruServer) applyUsers() {
+	if !ms.running {
+		return
+	}
+	config := ms.config.Config
+	ms.mux.SetServerUsers(appctl.UserListToMap(config.GetUsers()))
+	ms.mux.SetServerUserGroups(appctl.UserGroupsByUserName(config.GetUserGroups()))
+	ms.socks5Server.SetUsers(appctl.UserListToMap(config.GetUsers()))
+	ms.socks5Server.SetUserGroups(appctl.UserGroupsByUserName(config.GetUserGroups()))
+}
+
+func (ms *mieruServer) Metrics() ([]byte, error) {
+	return metrics.GetMetricsAsJSON()
+}
+
+func (ms *mieruServer) UserMetrics(name string) map[string]int64 {
+	userMetrics := metrics.GetMetricsForUser(name)
+	if len(userMetrics) == 0 {
+		return nil
+	}
+	res := make(map[string]int64, len(userMetrics))
+	for _, m := range userMetrics {
+		res[m.Name()] = m.Load()
+	}
+	return res
+}
+
+// runMetricsHook calls the metrics hook with the interval until done is closed.
+func (ms *mieruServer) runMetricsHook(hook func(ServerMetricsService), interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			hook(ms)
+		case <-done:
+			return
+		}
+	}
+}
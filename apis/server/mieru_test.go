@@ -0,0 +1,193 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/apis/client"
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/apis/model"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestServerStoreInvalidConfig(t *testing.T) {
+	s := NewServer()
+	if _, err := s.Load(); !errors.Is(err, ErrNoServerConfig) {
+		t.Errorf("Load() error = %v, want %v", err, ErrNoServerConfig)
+	}
+	if err := s.Start(); !errors.Is(err, ErrNoServerConfig) {
+		t.Errorf("Start() error = %v, want %v", err, ErrNoServerConfig)
+	}
+	for _, config := range []*ServerConfig{
+		nil,
+		{},
+		{Config: &appctlpb.ServerConfig{}},
+	} {
+		if err := s.Store(config); !errors.Is(err, ErrInvalidServerConfig) {
+			t.Errorf("Store(%v) error = %v, want %v", config, err, ErrInvalidServerConfig)
+		}
+	}
+}
+
+func TestServerWithClient(t *testing.T) {
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverConfig := &appctlpb.ServerConfig{
+		PortBindings: []*appctlpb.PortBinding{
+			{
+				Port:     proto.Int32(int32(port)),
+				Protocol: appctlpb.TransportProtocol_TCP.Enum(),
+			},
+		},
+		Users: []*appctlpb.User{
+			{
+				Name:     proto.String("bob"),
+				Password: proto.String("builder"),
+			},
+		},
+		AdvancedSettings: &appctlpb.ServerAdvancedSettings{
+			EnableTestEndpoints: proto.Bool(true),
+		},
+	}
+	hookCalled := make(chan struct{}, 1)
+	s := NewServer()
+	if err := s.Store(&ServerConfig{
+		Config: serverConfig,
+		MetricsHook: func(ServerMetricsService) {
+			select {
+			case hookCalled <- struct{}{}:
+			default:
+			}
+		},
+		MetricsHookInterval: 100 * time.Millisecond,
+	}); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer s.Stop()
+	if !s.IsRunning() {
+		t.Errorf("IsRunning() = false after Start()")
+	}
+	if err := s.Store(&ServerConfig{Config: serverConfig}); !errors.Is(err, ErrStoreServerConfigAfterStart) {
+		t.Errorf("Store() error = %v, want %v", err, ErrStoreServerConfigAfterStart)
+	}
+
+	// Register the user after the server is started.
+	if err := s.AddUser(&appctlpb.User{Name: proto.String("alice")}); !errors.Is(err, ErrInvalidServerConfig) {
+		t.Errorf("AddUser() without password error = %v, want %v", err, ErrInvalidServerConfig)
+	}
+	if err := s.AddUser(&appctlpb.User{
+		Name:               proto.String("alice"),
+		Password:           proto.String("wonderland"),
+		AllowTestEndpoints: proto.Bool(true),
+	}); err != nil {
+		t.Fatalf("AddUser() failed: %v", err)
+	}
+	if len(serverConfig.GetUsers()) != 1 {
+		t.Errorf("AddUser() modified the config of the caller")
+	}
+
+	c := client.NewClient()
+	if err := c.Store(&client.ClientConfig{
+		Profile: &appctlpb.ClientProfile{
+			ProfileName: proto.String("default"),
+			User: &appctlpb.User{
+				Name:     proto.String("alice"),
+				Password: proto.String("wonderland"),
+			},
+			Servers: []*appctlpb.ServerEndpoint{
+				{
+					IpAddress: proto.String("127.0.0.1"),
+					PortBindings: []*appctlpb.PortBinding{
+						{
+							Port:     proto.Int32(int32(port)),
+							Protocol: appctlpb.TransportProtocol_TCP.Enum(),
+						},
+					},
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("client Store() failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("client Start() failed: %v", err)
+	}
+	defer c.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := c.DialContext(ctx, model.NetAddrSpec{
+		AddrSpec: model.AddrSpec{FQDN: constant.Socks5EchoDestination, Port: 80},
+		Net:      "tcp",
+	})
+	if err != nil {
+		t.Fatalf("client DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	data := []byte("hello mieru")
+	if _, err := conn.Write(data); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	got := make([]byte, len(data))
+	if _, err := io.ReadFull(conn, got); err != nil {
+		t.Fatalf("ReadFull() failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("echo = %q, want %q", got, data)
+	}
+
+	if len(s.UserMetrics("alice")) == 0 {
+		t.Errorf("UserMetrics() returned no metrics")
+	}
+	if _, err := s.Metrics(); err != nil {
+		t.Errorf("Metrics() failed: %v", err)
+	}
+	select {
+	case <-hookCalled:
+	case <-time.After(5 * time.Second):
+		t.Errorf("metrics hook is not called")
+	}
+
+	if err := s.RemoveUser("carol"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("RemoveUser() error = %v, want %v", err, ErrUserNotFound)
+	}
+	if err := s.RemoveUser("bob"); err != nil {
+		t.Errorf("RemoveUser() failed: %v", err)
+	}
+	if users := s.Users(); len(users) != 1 || users[0].GetName() != "alice" {
+		t.Errorf("Users() = %v, want alice", users)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Errorf("Stop() failed: %v", err)
+	}
+	if s.IsRunning() {
+		t.Errorf("IsRunning() = true after Stop()")
+	}
+}
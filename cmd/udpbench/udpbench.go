@@ -0,0 +1,584 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// udpbench measures the performance of mieru UDP protocol over loopback.
+//
+// The proxy server runs in a child process. The proxy client in this
+// process sends traffic to it through a relay that emulates packet loss
+// and latency. The result is printed as a JSON report, so performance of
+// different releases can be compared on the same hardware.
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	mrand "math/rand"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/cipher"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"github.com/enfein/mieru/v3/pkg/version"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	benchUser    = "udpbench"
+	readyMessage = "udpbench server is ready"
+
+	modeUpload byte = 'U'
+	modeEcho   byte = 'E'
+
+	uploadChunkSize = 32 * 1024
+	relayQueueSize  = 4096
+)
+
+var (
+	role     = flag.String("role", "client", "Run as \"client\" or \"server\". The server is started by the client automatically.")
+	port     = flag.Int("port", 0, "UDP port of the server. Only used by the server.")
+	password = flag.String("password", "", "Password of the benchmark user. Only used by the server.")
+	mtu      = flag.Int("mtu", common.DefaultMTU, "Maximum transmission unit of UDP packets.")
+	size     = flag.Int("bytes", 64*1024*1024, "Number of bytes to upload when measuring throughput.")
+	pings    = flag.Int("pings", 1000, "Number of round trips when measuring latency.")
+	pingSize = flag.Int("ping-size", 64, "Number of bytes of each round trip when measuring latency.")
+	loss     = flag.Float64("loss", 0, "Probability to drop a packet in each direction, from 0 to 1.")
+	delay    = flag.Duration("delay", 0, "Delay of a packet in each direction, e.g. 20ms.")
+	timeout  = flag.Duration("timeout", 5*time.Minute, "Maximum time to run the benchmark.")
+	output   = flag.String("output", "", "File to write the JSON report. If not set, the report is printed to stdout.")
+)
+
+// Report is the result of a benchmark.
+type Report struct {
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	CPUs    int    `json:"cpus"`
+
+	Config ReportConfig `json:"config"`
+
+	UploadBytes     int64   `json:"uploadBytes"`
+	UploadSeconds   float64 `json:"uploadSeconds"`
+	ThroughputMbps  float64 `json:"throughputMbps"`
+	Latency         Latency `json:"latency"`
+	SegmentsSent    int64   `json:"segmentsSent"`
+	Retransmissions int64   `json:"retransmissions"`
+	RetransmitRatio float64 `json:"retransmitRatio"`
+	PacketsRelayed  int64   `json:"packetsRelayed"`
+	PacketsDropped  int64   `json:"packetsDropped"`
+}
+
+// ReportConfig is the configuration of a benchmark.
+type ReportConfig struct {
+	MTU      int     `json:"mtu"`
+	Bytes    int     `json:"bytes"`
+	Pings    int     `json:"pings"`
+	PingSize int     `json:"pingSize"`
+	Loss     float64 `json:"loss"`
+	DelayMs  float64 `json:"delayMs"`
+}
+
+// Latency is the distribution of round trip time in microseconds.
+type Latency struct {
+	Samples   int   `json:"samples"`
+	MinMicros int64 `json:"minMicros"`
+	P50Micros int64 `json:"p50Micros"`
+	P90Micros int64 `json:"p90Micros"`
+	P99Micros int64 `json:"p99Micros"`
+	MaxMicros int64 `json:"maxMicros"`
+}
+
+func main() {
+	log.SetOutput(os.Stderr)
+	log.SetLevel("WARN")
+	flag.Parse()
+
+	switch *role {
+	case "server":
+		if err := runServer(); err != nil {
+			log.Fatalf("%v", err)
+		}
+	case "client":
+		if *loss < 0 || *loss >= 1 {
+			log.Fatalf("Packet loss %v is out of range [0, 1)", *loss)
+		}
+		report, err := runClient()
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		b, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			log.Fatalf("json.MarshalIndent() failed: %v", err)
+		}
+		b = append(b, '\n')
+		if *output == "" {
+			os.Stdout.Write(b)
+		} else if err := os.WriteFile(*output, b, 0644); err != nil {
+			log.Fatalf("os.WriteFile() failed: %v", err)
+		}
+	default:
+		log.Fatalf("Unknown role %q", *role)
+	}
+}
+
+// runServer runs the proxy server until stdin is closed by the client.
+func runServer() error {
+	if *port <= 0 || *port >= 65536 {
+		return fmt.Errorf("invalid UDP port %d", *port)
+	}
+	mux := protocol.NewMux(false).
+		SetServerUsers(map[string]*appctlpb.User{
+			benchUser: {
+				Name:     proto.String(benchUser),
+				Password: proto.String(*password),
+			},
+		}).
+		SetEndpoints([]protocol.UnderlayProperties{
+			protocol.NewUnderlayProperties(*mtu, common.PacketTransport, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: *port}, nil),
+		})
+	if err := mux.Start(); err != nil {
+		return fmt.Errorf("Start() failed: %w", err)
+	}
+	defer mux.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := mux.WaitListening(ctx); err != nil {
+		return fmt.Errorf("WaitListening() failed: %w", err)
+	}
+	go func() {
+		io.Copy(io.Discard, os.Stdin)
+		mux.Close()
+	}()
+	fmt.Println(readyMessage)
+
+	for {
+		conn, err := mux.Accept()
+		if err != nil {
+			return nil
+		}
+		go serveConn(conn)
+	}
+}
+
+// serveConn handles a benchmark connection. The first byte is the mode,
+// followed by the number of bytes to upload in big endian. The server
+// acknowledges the header with one byte.
+func serveConn(conn net.Conn) {
+	defer conn.Close()
+	header := make([]byte, 9)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte{0}); err != nil {
+		return
+	}
+	switch header[0] {
+	case modeUpload:
+		n := int64(binary.BigEndian.Uint64(header[1:]))
+		if _, err := io.CopyN(io.Discard, conn, n); err != nil {
+			return
+		}
+		conn.Write([]byte{0})
+	case modeEcho:
+		io.Copy(conn, conn)
+	}
+}
+
+// runClient starts the server in a child process and runs the benchmark.
+func runClient() (*Report, error) {
+	serverPort, err := common.UnusedUDPPort()
+	if err != nil {
+		return nil, fmt.Errorf("UnusedUDPPort() failed: %w", err)
+	}
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("rand.Read() failed: %w", err)
+	}
+	serverPassword := hex.EncodeToString(secret)
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("os.Executable() failed: %w", err)
+	}
+	cmd := exec.Command(exe, "-role=server", "-port="+strconv.Itoa(serverPort), "-password="+serverPassword, "-mtu="+strconv.Itoa(*mtu))
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("StdinPipe() failed: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("StdoutPipe() failed: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start server process failed: %w", err)
+	}
+	defer func() {
+		stdin.Close()
+		cmd.Wait()
+	}()
+	ready := make(chan bool, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if scanner.Text() == readyMessage {
+				ready <- true
+				io.Copy(io.Discard, stdout)
+				return
+			}
+		}
+		ready <- false
+	}()
+	select {
+	case ok := <-ready:
+		if !ok {
+			return nil, fmt.Errorf("server process exited before it is ready")
+		}
+	case <-time.After(10 * time.Second):
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("server process is not ready after 10 seconds")
+	}
+
+	relay, err := newLossyRelay(&net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: serverPort}, *loss, *delay)
+	if err != nil {
+		return nil, err
+	}
+	defer relay.Close()
+
+	mux := protocol.NewMux(true).
+		SetClientUserNamePassword(benchUser, cipher.HashPassword([]byte(serverPassword), []byte(benchUser))).
+		SetEndpoints([]protocol.UnderlayProperties{
+			protocol.NewUnderlayProperties(*mtu, common.PacketTransport, nil, relay.Addr()),
+		})
+	defer mux.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	report := &Report{
+		Version: version.AppVersion,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		CPUs:    runtime.NumCPU(),
+		Config: ReportConfig{
+			MTU:      *mtu,
+			Bytes:    *size,
+			Pings:    *pings,
+			PingSize: *pingSize,
+			Loss:     *loss,
+			DelayMs:  float64(*delay) / float64(time.Millisecond),
+		},
+	}
+	sent := protocol.PacketSegmentsSent.Load()
+	retransmitted := protocol.PacketSegmentsRetransmitted.Load()
+
+	elapsed, err := measureUpload(ctx, mux, int64(*size))
+	if err != nil {
+		return nil, fmt.Errorf("measure throughput failed: %w", err)
+	}
+	report.UploadBytes = int64(*size)
+	report.UploadSeconds = elapsed.Seconds()
+	report.ThroughputMbps = float64(*size) * 8 / 1e6 / elapsed.Seconds()
+
+	samples, err := measureLatency(ctx, mux, *pings, *pingSize)
+	if err != nil {
+		return nil, fmt.Errorf("measure latency failed: %w", err)
+	}
+	report.Latency = latencyOf(samples)
+
+	report.SegmentsSent = protocol.PacketSegmentsSent.Load() - sent
+	report.Retransmissions = protocol.PacketSegmentsRetransmitted.Load() - retransmitted
+	if report.SegmentsSent > 0 {
+		report.RetransmitRatio = float64(report.Retransmissions) / float64(report.SegmentsSent)
+	}
+	report.PacketsRelayed, report.PacketsDropped = relay.stats()
+	return report, nil
+}
+
+// measureUpload returns the time to upload n bytes to the server.
+func measureUpload(ctx context.Context, mux *protocol.Mux, n int64) (time.Duration, error) {
+	conn, err := mux.DialContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("DialContext() failed: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	header := make([]byte, 9)
+	header[0] = modeUpload
+	binary.BigEndian.PutUint64(header[1:], uint64(n))
+	chunk := make([]byte, uploadChunkSize)
+	if _, err := rand.Read(chunk); err != nil {
+		return 0, fmt.Errorf("rand.Read() failed: %w", err)
+	}
+
+	start := time.Now()
+	if err := writeHeader(conn, header); err != nil {
+		return 0, err
+	}
+	for remaining := n; remaining > 0; {
+		b := chunk
+		if remaining < int64(len(b)) {
+			b = b[:remaining]
+		}
+		if _, err := conn.Write(b); err != nil {
+			return 0, fmt.Errorf("Write() failed: %w", err)
+		}
+		remaining -= int64(len(b))
+	}
+	if _, err := io.ReadFull(conn, make([]byte, 1)); err != nil {
+		return 0, fmt.Errorf("read upload confirmation failed: %w", err)
+	}
+	return time.Since(start), nil
+}
+
+// measureLatency returns the round trip time of each ping.
+func measureLatency(ctx context.Context, mux *protocol.Mux, n, payloadSize int) ([]time.Duration, error) {
+	conn, err := mux.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("DialContext() failed: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	header := make([]byte, 9)
+	header[0] = modeEcho
+	if err := writeHeader(conn, header); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, payloadSize)
+	resp := make([]byte, payloadSize)
+	samples := make([]time.Duration, 0, n)
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		if _, err := conn.Write(payload); err != nil {
+			return nil, fmt.Errorf("Write() failed: %w", err)
+		}
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			return nil, fmt.Errorf("io.ReadFull() failed: %w", err)
+		}
+		samples = append(samples, time.Since(start))
+	}
+	return samples, nil
+}
+
+// writeHeader sends the header and waits for the acknowledgement.
+// The client must not write again before the session is opened.
+func writeHeader(conn net.Conn, header []byte) error {
+	if _, err := conn.Write(header); err != nil {
+		return fmt.Errorf("Write() failed: %w", err)
+	}
+	if _, err := io.ReadFull(conn, make([]byte, 1)); err != nil {
+		return fmt.Errorf("read header acknowledgement failed: %w", err)
+	}
+	return nil
+}
+
+// latencyOf returns the distribution of the round trip time samples.
+func latencyOf(samples []time.Duration) Latency {
+	if len(samples) == 0 {
+		return Latency{}
+	}
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p float64) int64 {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return sorted[i].Microseconds()
+	}
+	return Latency{
+		Samples:   len(sorted),
+		MinMicros: sorted[0].Microseconds(),
+		P50Micros: percentile(0.5),
+		P90Micros: percentile(0.9),
+		P99Micros: percentile(0.99),
+		MaxMicros: sorted[len(sorted)-1].Microseconds(),
+	}
+}
+
+// lossyRelay forwards UDP packets between the clients and the server.
+// Each packet is dropped with the given probability, and delayed by
+// the given duration in each direction.
+type lossyRelay struct {
+	conn   *net.UDPConn
+	server *net.UDPAddr
+	loss   float64
+	delay  time.Duration
+
+	mu        sync.Mutex
+	upstreams map[string]*net.UDPConn
+	rng       *mrand.Rand
+	relayed   int64
+	dropped   int64
+
+	toServer chan delayedPacket
+	toClient chan delayedPacket
+	done     chan struct{}
+}
+
+type delayedPacket struct {
+	due  time.Time
+	data []byte
+	send func([]byte)
+}
+
+func newLossyRelay(server *net.UDPAddr, loss float64, delay time.Duration) (*lossyRelay, error) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		return nil, fmt.Errorf("ListenUDP() failed: %w", err)
+	}
+	r := &lossyRelay{
+		conn:      conn,
+		server:    server,
+		loss:      loss,
+		delay:     delay,
+		upstreams: make(map[string]*net.UDPConn),
+		rng:       mrand.New(mrand.NewSource(time.Now().UnixNano())),
+		toServer:  make(chan delayedPacket, relayQueueSize),
+		toClient:  make(chan delayedPacket, relayQueueSize),
+		done:      make(chan struct{}),
+	}
+	go r.deliverLoop(r.toServer)
+	go r.deliverLoop(r.toClient)
+	go r.clientLoop()
+	return r, nil
+}
+
+// Addr returns the address that clients send packets to.
+func (r *lossyRelay) Addr() net.Addr {
+	return r.conn.LocalAddr()
+}
+
+func (r *lossyRelay) Close() error {
+	close(r.done)
+	r.mu.Lock()
+	for _, upstream := range r.upstreams {
+		upstream.Close()
+	}
+	r.mu.Unlock()
+	return r.conn.Close()
+}
+
+func (r *lossyRelay) stats() (relayed, dropped int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.relayed, r.dropped
+}
+
+// clientLoop receives packets from clients. Each client address
+// uses its own socket to talk to the server.
+func (r *lossyRelay) clientLoop() {
+	buf := make([]byte, 65536)
+	for {
+		n, clientAddr, err := r.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		upstream, err := r.upstream(clientAddr)
+		if err != nil {
+			log.Warnf("%v", err)
+			continue
+		}
+		r.enqueue(r.toServer, buf[:n], func(b []byte) { upstream.Write(b) })
+	}
+}
+
+func (r *lossyRelay) upstream(clientAddr *net.UDPAddr) (*net.UDPConn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if upstream, found := r.upstreams[clientAddr.String()]; found {
+		return upstream, nil
+	}
+	upstream, err := net.DialUDP("udp", nil, r.server)
+	if err != nil {
+		return nil, fmt.Errorf("DialUDP() failed: %w", err)
+	}
+	r.upstreams[clientAddr.String()] = upstream
+	go func() {
+		buf := make([]byte, 65536)
+		for {
+			n, err := upstream.Read(buf)
+			if err != nil {
+				return
+			}
+			r.enqueue(r.toClient, buf[:n], func(b []byte) { r.conn.WriteToUDP(b, clientAddr) })
+		}
+	}()
+	return upstream, nil
+}
+
+// enqueue drops the packet, or schedules it to be sent after the delay.
+// The packet is also dropped if the queue is full.
+func (r *lossyRelay) enqueue(queue chan delayedPacket, data []byte, send func([]byte)) {
+	r.mu.Lock()
+	drop := r.loss > 0 && r.rng.Float64() < r.loss
+	if drop {
+		r.dropped++
+	} else {
+		r.relayed++
+	}
+	r.mu.Unlock()
+	if drop {
+		return
+	}
+	p := delayedPacket{
+		due:  time.Now().Add(r.delay),
+		data: append([]byte(nil), data...),
+		send: send,
+	}
+	select {
+	case queue <- p:
+	default:
+		r.mu.Lock()
+		r.relayed--
+		r.dropped++
+		r.mu.Unlock()
+	}
+}
+
+// deliverLoop sends the packets in the queue in order when they are due.
+func (r *lossyRelay) deliverLoop(queue chan delayedPacket) {
+	for {
+		select {
+		case p := <-queue:
+			if wait := time.Until(p.due); wait > 0 {
+				time.Sleep(wait)
+			}
+			p.send(p.data)
+		case <-r.done:
+			return
+		}
+	}
+}
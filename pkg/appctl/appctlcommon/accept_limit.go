@@ -0,0 +1,68 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+)
+
+// defaultAcceptHandshakeTimeout is the maximum time to finish the handshake
+// of a new connection if it is not set.
+const defaultAcceptHandshakeTimeout = 30 * time.Second
+
+// ValidateAcceptLimitConfig checks the handshake timeout, the maximum number
+// of half-open connections and the accept rate.
+func ValidateAcceptLimitConfig(config *pb.AcceptLimit) error {
+	if config == nil {
+		return nil
+	}
+	if config.GetHandshakeTimeout() != "" {
+		d, err := time.ParseDuration(config.GetHandshakeTimeout())
+		if err != nil {
+			return fmt.Errorf("accept limit handshake timeout %q is invalid: %w", config.GetHandshakeTimeout(), err)
+		}
+		if d < time.Second {
+			return fmt.Errorf("accept limit handshake timeout %q is less than 1 second", config.GetHandshakeTimeout())
+		}
+	}
+	if config.GetMaxHalfOpen() < 0 {
+		return fmt.Errorf("accept limit max half-open connections %d is negative", config.GetMaxHalfOpen())
+	}
+	if config.GetAcceptRate() < 0 {
+		return fmt.Errorf("accept limit accept rate %d is negative", config.GetAcceptRate())
+	}
+	return nil
+}
+
+// AcceptLimitFromConfig returns the limits of a listener. The handshake
+// timeout is always set. The config must be valid.
+func AcceptLimitFromConfig(config *pb.AcceptLimit) common.AcceptLimit {
+	limit := common.AcceptLimit{
+		HandshakeTimeout: defaultAcceptHandshakeTimeout,
+		MaxHalfOpen:      int(config.GetMaxHalfOpen()),
+		AcceptRate:       int(config.GetAcceptRate()),
+	}
+	if config.GetHandshakeTimeout() != "" {
+		if d, err := time.ParseDuration(config.GetHandshakeTimeout()); err == nil {
+			limit.HandshakeTimeout = d
+		}
+	}
+	return limit
+}
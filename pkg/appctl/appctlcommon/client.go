@@ -16,10 +16,17 @@
 package appctlcommon
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"strings"
 
+	apicommon "github.com/enfein/mieru/v3/apis/common"
 	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/cipher"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/stderror"
 )
 
 // ValidateClientConfigSingleProfile validates
@@ -34,7 +41,29 @@ import (
 // 5.1. the server has either IP address or domain name
 // 5.2. if set, server's IP address is parsable
 // 5.3. the server has at least 1 port binding, and all port bindings are valid
+// 5.4. if set, server's pinned IP addresses are parsable, and domain name is set
+// 5.5. if set, server's port knocking sequence is valid
 // 6. if set, MTU is valid
+// 7. multiplexing max connections is not negative
+// 8. if set, bootstrap DoH URL is valid
+// 9. if set, transport plugin is valid
+// 9.1. name and command are not empty
+// 9.2. each option is in "key=value" format
+// 9.3. all the port bindings of servers use TCP protocol
+// 10. if set, multipath interfaces are not empty and not duplicated
+// 11. if set, key rotation period and overlap are valid
+// 12. if set, upstream proxy has protocol and address, it is not used with
+// transport plugin or SIP003 plugins, and servers don't use UDP protocol
+// 13. if set, bind IP is parsable, and bind interface, bind IP and routing
+// mark are not used with multipath, transport plugin or SIP003 plugins
+// 14. if set, heartbeat interval and dead peer timeout are valid
+// 15. if set, reconnect delays and maximum wait time are valid
+// 16. if set, SIP003 plugins are valid
+// 16.1. each command is not empty
+// 16.2. transport plugin is not set
+// 16.3. all the port bindings of servers use TCP or WEBSOCKET protocol
+// 17. if hybrid key exchange is enabled, it is supported by this build,
+// and all the port bindings of servers use TCP or WEBSOCKET protocol
 func ValidateClientConfigSingleProfile(profile *pb.ClientProfile) error {
 	name := profile.GetProfileName()
 	if name == "" {
@@ -61,16 +90,257 @@ func ValidateClientConfigSingleProfile(profile *pb.ClientProfile) error {
 		if server.GetIpAddress() != "" && net.ParseIP(server.GetIpAddress()) == nil {
 			return fmt.Errorf("failed to parse IP address %q", server.GetIpAddress())
 		}
+		if len(server.GetPinnedIpAddresses()) > 0 && server.GetDomainName() == "" {
+			return fmt.Errorf("pinned IP addresses are set without server domain name")
+		}
+		for _, pinned := range server.GetPinnedIpAddresses() {
+			if net.ParseIP(pinned) == nil {
+				return fmt.Errorf("failed to parse pinned IP address %q", pinned)
+			}
+		}
 		portBindings := server.GetPortBindings()
 		if len(portBindings) == 0 {
 			return fmt.Errorf("server port binding is not set")
 		}
-		if _, err := FlatPortBindings(portBindings); err != nil {
+		flatBindings, err := FlatPortBindings(portBindings)
+		if err != nil {
+			return err
+		}
+		if err := ValidateWebSocketConfig(server.GetWebSocket()); err != nil {
+			return err
+		}
+		if err := ValidateTLSCamouflageConfig(server.GetTlsCamouflage()); err != nil {
+			return err
+		}
+		if err := ValidatePortKnockingConfig(server.GetPortKnocking(), flatBindings); err != nil {
 			return err
 		}
 	}
 	if profile.GetMtu() != 0 && (profile.GetMtu() < 1280 || profile.GetMtu() > 1500) {
 		return fmt.Errorf("MTU value %d is out of range, valid range is [1280, 1500]", profile.GetMtu())
 	}
+	if profile.GetMultiplexing().GetMaxConnections() < 0 {
+		return fmt.Errorf("multiplexing max connections %d is negative", profile.GetMultiplexing().GetMaxConnections())
+	}
+	if profile.GetBootstrapDoHURL() != "" {
+		if err := common.ValidateDoHURL(profile.GetBootstrapDoHURL()); err != nil {
+			return err
+		}
+	}
+	if plugin := profile.GetTransportPlugin(); plugin != nil {
+		if plugin.GetName() == "" {
+			return fmt.Errorf("transport plugin name is not set")
+		}
+		if plugin.GetCommand() == "" {
+			return fmt.Errorf("transport plugin command is not set")
+		}
+		for _, option := range plugin.GetOptions() {
+			if key, _, ok := strings.Cut(option, "="); !ok || key == "" {
+				return fmt.Errorf("transport plugin option %q is not in \"key=value\" format", option)
+			}
+		}
+		for _, server := range servers {
+			for _, binding := range server.GetPortBindings() {
+				if binding.GetProtocol() != pb.TransportProtocol_TCP {
+					return fmt.Errorf("transport plugin only supports TCP protocol")
+				}
+			}
+		}
+	}
+	if plugins := profile.GetSip003Plugins(); len(plugins) > 0 {
+		for _, plugin := range plugins {
+			if plugin.GetCommand() == "" {
+				return fmt.Errorf("SIP003 plugin command is not set")
+			}
+		}
+		if profile.GetTransportPlugin() != nil {
+			return fmt.Errorf("SIP003 plugins can't be used with transport plugin")
+		}
+		for _, server := range servers {
+			for _, binding := range server.GetPortBindings() {
+				if binding.GetProtocol() != pb.TransportProtocol_TCP && binding.GetProtocol() != pb.TransportProtocol_WEBSOCKET {
+					return fmt.Errorf("SIP003 plugins only support TCP and WEBSOCKET protocols")
+				}
+			}
+		}
+	}
+	if multipath := profile.GetMultipath(); multipath != nil {
+		if len(multipath.GetInterfaces()) == 0 {
+			return fmt.Errorf("multipath interfaces are not set")
+		}
+		interfaces := make(map[string]struct{})
+		for _, name := range multipath.GetInterfaces() {
+			if name == "" {
+				return fmt.Errorf("multipath interface name is empty")
+			}
+			if _, found := interfaces[name]; found {
+				return fmt.Errorf("multipath interface %q is duplicated", name)
+			}
+			interfaces[name] = struct{}{}
+		}
+	}
+	if err := ValidateKeyRotationConfig(profile.GetKeyRotation()); err != nil {
+		return err
+	}
+	if upstream := profile.GetUpstreamProxy(); upstream != nil {
+		if upstream.GetProtocol() == pb.UpstreamProxyProtocol_UNKNOWN_UPSTREAM_PROXY_PROTOCOL {
+			return fmt.Errorf("upstream proxy protocol is not set")
+		}
+		if _, _, err := net.SplitHostPort(upstream.GetAddress()); err != nil {
+			return fmt.Errorf("upstream proxy address %q is invalid: %w", upstream.GetAddress(), err)
+		}
+		if upstream.GetAuth() != nil && upstream.GetAuth().GetUser() == "" {
+			return fmt.Errorf("upstream proxy user name is not set")
+		}
+		if profile.GetTransportPlugin() != nil {
+			return fmt.Errorf("upstream proxy can't be used with transport plugin")
+		}
+		if len(profile.GetSip003Plugins()) > 0 {
+			return fmt.Errorf("upstream proxy can't be used with SIP003 plugins")
+		}
+		for _, server := range servers {
+			for _, binding := range server.GetPortBindings() {
+				if binding.GetProtocol() == pb.TransportProtocol_UDP || binding.GetProtocol() == pb.TransportProtocol_AUTO {
+					return fmt.Errorf("upstream proxy doesn't support %s protocol", binding.GetProtocol().String())
+				}
+			}
+			for _, step := range server.GetPortKnocking().GetSequence() {
+				if step.GetProtocol() == pb.TransportProtocol_UDP {
+					return fmt.Errorf("upstream proxy doesn't support UDP port knocking")
+				}
+			}
+		}
+	}
+	if profile.GetBindInterface() != "" || profile.GetBindIP() != "" || profile.GetRoutingMark() != 0 {
+		if profile.GetBindIP() != "" && net.ParseIP(profile.GetBindIP()) == nil {
+			return fmt.Errorf("failed to parse bind IP address %q", profile.GetBindIP())
+		}
+		if profile.GetMultipath() != nil {
+			return fmt.Errorf("bind interface, bind IP and routing mark can't be used with multipath")
+		}
+		if profile.GetTransportPlugin() != nil {
+			return fmt.Errorf("bind interface, bind IP and routing mark can't be used with transport plugin")
+		}
+		if len(profile.GetSip003Plugins()) > 0 {
+			return fmt.Errorf("bind interface, bind IP and routing mark can't be used with SIP003 plugins")
+		}
+	}
+	if err := ValidateHeartbeatConfig(profile.GetHeartbeat()); err != nil {
+		return err
+	}
+	if err := ValidateReconnectConfig(profile.GetReconnect()); err != nil {
+		return err
+	}
+	if profile.GetHybridKeyExchange() {
+		if !cipher.HybridKeyExchangeSupported {
+			return fmt.Errorf("hybrid key exchange is not supported by this build of mieru")
+		}
+		for _, server := range servers {
+			for _, binding := range server.GetPortBindings() {
+				if binding.GetProtocol() != pb.TransportProtocol_TCP && binding.GetProtocol() != pb.TransportProtocol_WEBSOCKET {
+					return fmt.Errorf("hybrid key exchange only supports TCP and WEBSOCKET protocols")
+				}
+			}
+		}
+	}
 	return nil
 }
+
+// ClientLocalBinding returns the local network interface, the local
+// IP address and the firewall mark to connect to the proxy servers
+// of the profile.
+func ClientLocalBinding(profile *pb.ClientProfile) common.LocalBinding {
+	return common.LocalBinding{
+		Interface: profile.GetBindInterface(),
+		IP:        net.ParseIP(profile.GetBindIP()),
+		Mark:      int(profile.GetRoutingMark()),
+	}
+}
+
+// ResolveServerIPs returns the IP addresses to connect to the proxy server.
+// If the server is specified by domain name, one IP address returned by the
+// resolver is used, see selectServerIP. If the domain name can't be
+// resolved, the pinned IP addresses of the server are returned instead.
+// On IPv6-only networks, IPv4 addresses are replaced by NAT64 addresses
+// if the resolver is a DNS64 resolver.
+func ResolveServerIPs(ctx context.Context, server *pb.ServerEndpoint, resolver apicommon.DNSResolver) ([]net.IP, error) {
+	families := common.DetectAddressFamilies()
+	if server.GetDomainName() == "" {
+		ip := net.ParseIP(server.GetIpAddress())
+		if ip == nil {
+			return nil, fmt.Errorf(stderror.ParseIPFailed)
+		}
+		return synthesizeServerIPs(ctx, []net.IP{ip}, families, resolver), nil
+	}
+
+	host := server.GetDomainName()
+	ips, err := resolver.LookupIP(ctx, "ip", host)
+	if err == nil && len(ips) > 0 {
+		return synthesizeServerIPs(ctx, []net.IP{selectServerIP(ips, families)}, families, resolver), nil
+	}
+	if len(server.GetPinnedIpAddresses()) == 0 {
+		if err != nil {
+			return nil, fmt.Errorf(stderror.LookupIPFailedErr, err)
+		}
+		return nil, fmt.Errorf(stderror.IPAddressNotFound, host)
+	}
+	pinned := make([]net.IP, 0, len(server.GetPinnedIpAddresses()))
+	for _, s := range server.GetPinnedIpAddresses() {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf(stderror.ParseIPFailed)
+		}
+		pinned = append(pinned, ip)
+	}
+	log.Warnf("Failed to resolve proxy server %s, using pinned IP addresses %v", host, pinned)
+	return synthesizeServerIPs(ctx, pinned, families, resolver), nil
+}
+
+// selectServerIP returns the IP address to connect to the proxy server
+// from the resolved IP addresses. IPv6 is preferred on IPv6-only networks,
+// and IPv4 is preferred on IPv4-only networks. Otherwise, the first IP
+// address is used.
+func selectServerIP(ips []net.IP, families common.AddressFamilies) net.IP {
+	switch {
+	case families.IPv6Only():
+		return common.SelectIPFromList(ips, common.PREFER_IPv6)
+	case families.IPv4Only():
+		return common.SelectIPFromList(ips, common.PREFER_IPv4)
+	default:
+		return ips[0]
+	}
+}
+
+// synthesizeServerIPs replaces the IPv4 addresses with NAT64 addresses on
+// IPv6-only networks. The NAT64 prefix is discovered from the resolver.
+// If the resolver is not a DNS64 resolver, the IP addresses are returned
+// as is.
+func synthesizeServerIPs(ctx context.Context, ips []net.IP, families common.AddressFamilies, resolver apicommon.DNSResolver) []net.IP {
+	if !families.IPv6Only() {
+		return ips
+	}
+	hasIPv4 := false
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			hasIPv4 = true
+		}
+	}
+	if !hasIPv4 {
+		return ips
+	}
+	prefix, err := common.DetectNAT64Prefix(ctx, resolver)
+	if err != nil {
+		log.Warnf("Network is IPv6-only, but failed to discover NAT64 prefix to reach proxy server %v: %v", ips, err)
+		return ips
+	}
+	if prefix == nil {
+		log.Warnf("Network is IPv6-only, but DNS64 is not available to reach proxy server %v", ips)
+		return ips
+	}
+	res := make([]net.IP, 0, len(ips))
+	for _, ip := range ips {
+		res = append(res, common.SynthesizeNAT64(prefix, ip))
+	}
+	log.Debugf("Proxy server IP addresses %v are synthesized to %v with NAT64 prefix %v", ips, res, prefix)
+	return res
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"google.golang.org/protobuf/proto"
+)
+
+type dns64Resolver struct{}
+
+func (dns64Resolver) LookupIP(_ context.Context, _, host string) ([]net.IP, error) {
+	if host == "ipv4only.arpa" {
+		return []net.IP{net.ParseIP("64:ff9b::c000:aa")}, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestSelectServerIP(t *testing.T) {
+	ips := []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")}
+	testCases := []struct {
+		families common.AddressFamilies
+		want     string
+	}{
+		{common.AddressFamilies{IPv4: true, IPv6: true}, "192.0.2.1"},
+		{common.AddressFamilies{IPv4: true}, "192.0.2.1"},
+		{common.AddressFamilies{IPv6: true}, "2001:db8::1"},
+	}
+	for _, tc := range testCases {
+		if got := selectServerIP(ips, tc.families); got.String() != tc.want {
+			t.Errorf("selectServerIP() with %v = %v, want %s", tc.families, got, tc.want)
+		}
+	}
+}
+
+func TestSynthesizeServerIPs(t *testing.T) {
+	ips := []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")}
+	ipv6Only := common.AddressFamilies{IPv6: true}
+
+	got := synthesizeServerIPs(context.Background(), ips, ipv6Only, dns64Resolver{})
+	want := []string{"64:ff9b::c000:201", "2001:db8::1"}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("synthesizeServerIPs()[%d] = %v, want %s", i, got[i], want[i])
+		}
+	}
+
+	// No change without DNS64, or if IPv4 is reachable.
+	got = synthesizeServerIPs(context.Background(), ips, ipv6Only, apicommon.NilDNSResolver{})
+	if !got[0].Equal(ips[0]) {
+		t.Errorf("synthesizeServerIPs() without DNS64 = %v, want %v", got[0], ips[0])
+	}
+	got = synthesizeServerIPs(context.Background(), ips, common.AddressFamilies{IPv4: true, IPv6: true}, dns64Resolver{})
+	if !got[0].Equal(ips[0]) {
+		t.Errorf("synthesizeServerIPs() on dual stack network = %v, want %v", got[0], ips[0])
+	}
+}
+
+func TestFlatPortBindingsPortHopping(t *testing.T) {
+	hopping := func(portRange string, protocol pb.TransportProtocol, interval string) *pb.PortBinding {
+		return &pb.PortBinding{
+			PortRange:           proto.String(portRange),
+			Protocol:            protocol.Enum(),
+			PortHoppingInterval: proto.String(interval),
+		}
+	}
+	testCases := []struct {
+		name     string
+		bindings []*pb.PortBinding
+		wantErr  bool
+	}{
+		{"valid", []*pb.PortBinding{hopping("20000-20999", pb.TransportProtocol_UDP, "30s"), hopping("30000-30999", pb.TransportProtocol_UDP, "1m")}, false},
+		{"single port", []*pb.PortBinding{{Port: proto.Int32(20000), Protocol: pb.TransportProtocol_UDP.Enum(), PortHoppingInterval: proto.String("30s")}}, true},
+		{"TCP", []*pb.PortBinding{hopping("20000-20999", pb.TransportProtocol_TCP, "30s")}, true},
+		{"invalid interval", []*pb.PortBinding{hopping("20000-20999", pb.TransportProtocol_UDP, "30")}, true},
+		{"short interval", []*pb.PortBinding{hopping("20000-20999", pb.TransportProtocol_UDP, "1s")}, true},
+		{"overlap", []*pb.PortBinding{hopping("20000-20999", pb.TransportProtocol_UDP, "30s"), hopping("20999-21999", pb.TransportProtocol_UDP, "30s")}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := FlatPortBindings(tc.bindings)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("FlatPortBindings() error = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	bindings := []*pb.PortBinding{
+		{Port: proto.Int32(8964), Protocol: pb.TransportProtocol_TCP.Enum()},
+		hopping("20000-20999", pb.TransportProtocol_UDP, "30s"),
+	}
+	if got := PortBindingsWithoutHopping(bindings); len(got) != 1 || got[0].GetPort() != 8964 {
+		t.Errorf("PortBindingsWithoutHopping() = %v, want the TCP port binding", got)
+	}
+	opts := PortHoppingOptionsFromConfig(bindings)
+	if len(opts) != 1 || opts[0].BeginPort != 20000 || opts[0].EndPort != 20999 || opts[0].Interval.String() != "30s" {
+		t.Errorf("PortHoppingOptionsFromConfig() = %v, want one range 20000-20999 with interval 30s", opts)
+	}
+}
+
+func TestFlatPortBindingsAuto(t *testing.T) {
+	bindings := []*pb.PortBinding{
+		{Port: proto.Int32(8964), Protocol: pb.TransportProtocol_TCP.Enum()},
+		{PortRange: proto.String("9000-9001"), Protocol: pb.TransportProtocol_AUTO.Enum()},
+	}
+	got, err := FlatPortBindings(bindings)
+	if err != nil {
+		t.Fatalf("FlatPortBindings() failed: %v", err)
+	}
+	if len(got) != 3 || got[1].GetPort() != 9000 || got[1].GetProtocol() != pb.TransportProtocol_AUTO || got[2].GetPort() != 9001 {
+		t.Errorf("FlatPortBindings() = %v, want TCP port 8964 and AUTO ports 9000 and 9001", got)
+	}
+
+	for _, other := range []pb.TransportProtocol{pb.TransportProtocol_TCP, pb.TransportProtocol_UDP, pb.TransportProtocol_WEBSOCKET} {
+		bindings := []*pb.PortBinding{
+			{Port: proto.Int32(9000), Protocol: other.Enum()},
+			{Port: proto.Int32(9000), Protocol: pb.TransportProtocol_AUTO.Enum()},
+		}
+		if _, err := FlatPortBindings(bindings); err == nil {
+			t.Errorf("FlatPortBindings() with AUTO and %s on the same port returned no error", other.String())
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/geoip"
+	"github.com/enfein/mieru/v3/pkg/geosite"
+	"github.com/enfein/mieru/v3/pkg/minisign"
+)
+
+// geoDatabaseDownloadTimeout is the maximum time to download a database.
+const geoDatabaseDownloadTimeout = 5 * time.Minute
+
+// GeoDatabaseInfo describes a GeoIP or geosite database file.
+type GeoDatabaseInfo struct {
+	Name     string // "GeoIP" or "geosite"
+	Path     string
+	Version  string // empty if the database can't be loaded
+	Size     int64
+	Modified time.Time
+	Err      error // error to load the database
+}
+
+// ValidateGeoDatabasesConfig validates the GeoIP and geosite databases.
+//
+// The config must satisfy:
+// 1. if set, the database paths are absolute paths
+// 2. if set, the download URLs are HTTP or HTTPS URLs
+// 3. if signature verification is enabled, a download URL is set
+func ValidateGeoDatabasesConfig(config *pb.GeoDatabases) error {
+	for _, path := range []string{config.GetGeoIPDatabase(), config.GetGeositeDatabase()} {
+		if path != "" && !filepath.IsAbs(path) {
+			return fmt.Errorf("geo database %q is not an absolute path", path)
+		}
+	}
+	for _, downloadURL := range []string{config.GetGeoIPDownloadURL(), config.GetGeositeDownloadURL()} {
+		if downloadURL == "" {
+			continue
+		}
+		u, err := url.Parse(downloadURL)
+		if err != nil {
+			return fmt.Errorf("geo database download URL %q is invalid: %w", downloadURL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("geo database download URL %q is not an HTTP or HTTPS URL", downloadURL)
+		}
+	}
+	if config.GetGeoIPDownloadURL() != "" && config.GetGeoIPDatabase() == "" {
+		return fmt.Errorf("GeoIP download URL is set, but GeoIP database is not set")
+	}
+	if config.GetGeositeDownloadURL() != "" && config.GetGeositeDatabase() == "" {
+		return fmt.Errorf("geosite download URL is set, but geosite database is not set")
+	}
+	if config.GetVerifySignature() && config.GetGeoIPDownloadURL() == "" && config.GetGeositeDownloadURL() == "" {
+		return fmt.Errorf("geo database signature verification is enabled, but no download URL is set")
+	}
+	return nil
+}
+
+// LoadGeoDatabases loads the GeoIP and geosite databases.
+// A database that is not configured is nil.
+func LoadGeoDatabases(config *pb.GeoDatabases) (*geoip.Database, *geosite.Database, error) {
+	var geoIP *geoip.Database
+	var site *geosite.Database
+	var err error
+	if config.GetGeoIPDatabase() != "" {
+		geoIP, err = geoip.Load(config.GetGeoIPDatabase())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load GeoIP database %q: %w", config.GetGeoIPDatabase(), err)
+		}
+	}
+	if config.GetGeositeDatabase() != "" {
+		site, err = geosite.Load(config.GetGeositeDatabase())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load geosite database %q: %w", config.GetGeositeDatabase(), err)
+		}
+	}
+	return geoIP, site, nil
+}
+
+// DescribeGeoDatabases returns the information of the configured
+// GeoIP and geosite databases.
+func DescribeGeoDatabases(config *pb.GeoDatabases) []GeoDatabaseInfo {
+	var res []GeoDatabaseInfo
+	if path := config.GetGeoIPDatabase(); path != "" {
+		info := GeoDatabaseInfo{Name: "GeoIP", Path: path}
+		if db, err := geoip.Load(path); err != nil {
+			info.Err = err
+		} else {
+			info.Version = db.Version()
+		}
+		res = append(res, info.withFileInfo())
+	}
+	if path := config.GetGeositeDatabase(); path != "" {
+		info := GeoDatabaseInfo{Name: "geosite", Path: path}
+		if db, err := geosite.Load(path); err != nil {
+			info.Err = err
+		} else {
+			info.Version = db.Version()
+		}
+		res = append(res, info.withFileInfo())
+	}
+	return res
+}
+
+func (info GeoDatabaseInfo) withFileInfo() GeoDatabaseInfo {
+	if stat, err := os.Stat(info.Path); err == nil {
+		info.Size = stat.Size()
+		info.Modified = stat.ModTime()
+	}
+	return info
+}
+
+// UpdateGeoDatabases downloads the GeoIP and geosite databases that have
+// a download URL. A downloaded database replaces the existing file only
+// if it can be loaded. If signature verification is enabled, it must also
+// be signed by a public key in the trust store.
+func UpdateGeoDatabases(ctx context.Context, config *pb.GeoDatabases, trust *minisign.TrustStore) error {
+	if config.GetGeoIPDownloadURL() == "" && config.GetGeositeDownloadURL() == "" {
+		return fmt.Errorf("no geo database download URL is set")
+	}
+	if !config.GetVerifySignature() {
+		trust = nil
+	} else if trust == nil || trust.Empty() {
+		return fmt.Errorf("geo database signature verification is enabled, but no public key is trusted")
+	}
+	if config.GetGeoIPDownloadURL() != "" {
+		err := downloadGeoDatabase(ctx, config.GetGeoIPDownloadURL(), config.GetGeoIPDatabase(), trust, func(path string) error {
+			_, err := geoip.Load(path)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update GeoIP database: %w", err)
+		}
+	}
+	if config.GetGeositeDownloadURL() != "" {
+		err := downloadGeoDatabase(ctx, config.GetGeositeDownloadURL(), config.GetGeositeDatabase(), trust, func(path string) error {
+			_, err := geosite.Load(path)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update geosite database: %w", err)
+		}
+	}
+	return nil
+}
+
+// downloadGeoDatabase downloads the URL to a temporary file in the
+// directory of the destination, checks it, and renames it to the destination.
+// If the trust store is not nil, the signature is verified.
+func downloadGeoDatabase(ctx context.Context, downloadURL, dst string, trust *minisign.TrustStore, check func(string) error) error {
+	ctx, cancelFunc := context.WithTimeout(ctx, geoDatabaseDownloadTimeout)
+	defer cancelFunc()
+	resp, err := httpGet(ctx, downloadURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	f, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp() failed: %w", err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("download %q failed: %w", downloadURL, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Close() failed: %w", err)
+	}
+	if trust != nil {
+		if err := verifyGeoDatabaseSignature(ctx, downloadURL, tmp, trust); err != nil {
+			return err
+		}
+	}
+	if err := check(tmp); err != nil {
+		return fmt.Errorf("downloaded file is invalid: %w", err)
+	}
+	if err := os.Chmod(tmp, 0644); err != nil {
+		return fmt.Errorf("os.Chmod() failed: %w", err)
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return fmt.Errorf("os.Rename() failed: %w", err)
+	}
+	return nil
+}
+
+// verifyGeoDatabaseSignature downloads the signature of the database
+// from the download URL with ".minisig" suffix, and verifies the
+// downloaded database file with the trust store.
+func verifyGeoDatabaseSignature(ctx context.Context, downloadURL, path string, trust *minisign.TrustStore) error {
+	resp, err := httpGet(ctx, downloadURL+".minisig")
+	if err != nil {
+		return fmt.Errorf("failed to download signature: %w", err)
+	}
+	defer resp.Body.Close()
+	signature, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return fmt.Errorf("failed to download signature: %w", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("os.ReadFile() failed: %w", err)
+	}
+	if _, err := trust.Verify(b, signature); err != nil {
+		return fmt.Errorf("failed to verify signature of %q: %w", downloadURL, err)
+	}
+	return nil
+}
+
+// httpGet sends a GET request to the URL. It returns an error if the
+// HTTP status is not 200.
+func httpGet(ctx context.Context, downloadURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext() failed: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %q failed: %w", downloadURL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download %q failed: HTTP status %q", downloadURL, resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+)
+
+const (
+	// minHeartbeatInterval and maxHeartbeatInterval are the range of
+	// heartbeat interval. The proxy server closes a UDP session if it
+	// receives nothing for 1 minute, so the interval must be much shorter.
+	minHeartbeatInterval = time.Second
+	maxHeartbeatInterval = 30 * time.Second
+
+	// minDeadPeerTimeout is the minimum dead peer timeout. The proxy server
+	// sends heartbeats every 5 seconds on idle UDP sessions.
+	minDeadPeerTimeout = 10 * time.Second
+)
+
+// ValidateHeartbeatConfig checks the interval and dead peer timeout of
+// heartbeats.
+func ValidateHeartbeatConfig(config *pb.HeartbeatConfig) error {
+	if config == nil {
+		return nil
+	}
+	var interval time.Duration
+	if config.GetInterval() != "" {
+		var err error
+		interval, err = time.ParseDuration(config.GetInterval())
+		if err != nil {
+			return fmt.Errorf("heartbeat interval %q is invalid: %w", config.GetInterval(), err)
+		}
+		if interval < minHeartbeatInterval || interval > maxHeartbeatInterval {
+			return fmt.Errorf("heartbeat interval %q is out of range, valid range is [%v, %v]", config.GetInterval(), minHeartbeatInterval, maxHeartbeatInterval)
+		}
+	}
+	if config.GetDeadPeerTimeout() != "" {
+		timeout, err := time.ParseDuration(config.GetDeadPeerTimeout())
+		if err != nil {
+			return fmt.Errorf("dead peer timeout %q is invalid: %w", config.GetDeadPeerTimeout(), err)
+		}
+		if timeout < minDeadPeerTimeout {
+			return fmt.Errorf("dead peer timeout %q is less than %v", config.GetDeadPeerTimeout(), minDeadPeerTimeout)
+		}
+		if timeout <= interval {
+			return fmt.Errorf("dead peer timeout %q is not larger than heartbeat interval %q", config.GetDeadPeerTimeout(), config.GetInterval())
+		}
+	}
+	return nil
+}
+
+// HeartbeatFromConfig returns the heartbeat interval and the dead peer
+// timeout. 0 is returned for the values that are not set.
+func HeartbeatFromConfig(config *pb.HeartbeatConfig) (interval, deadPeerTimeout time.Duration) {
+	if d, err := time.ParseDuration(config.GetInterval()); err == nil {
+		interval = d
+	}
+	if d, err := time.ParseDuration(config.GetDeadPeerTimeout()); err == nil {
+		deadPeerTimeout = d
+	}
+	return interval, deadPeerTimeout
+}
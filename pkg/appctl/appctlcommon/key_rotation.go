@@ -0,0 +1,82 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/cipher"
+)
+
+const (
+	// minKeyRotationPeriod is the minimum length of a key epoch.
+	minKeyRotationPeriod = 10 * time.Minute
+
+	// defaultKeyRotationOverlap is the default overlap window of
+	// adjacent key epochs.
+	defaultKeyRotationOverlap = 10 * time.Minute
+)
+
+// ValidateKeyRotationConfig checks the period and overlap of key rotation.
+func ValidateKeyRotationConfig(config *pb.KeyRotationConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.GetPeriod() == "" {
+		return fmt.Errorf("key rotation period is not set")
+	}
+	period, err := time.ParseDuration(config.GetPeriod())
+	if err != nil {
+		return fmt.Errorf("key rotation period %q is invalid: %w", config.GetPeriod(), err)
+	}
+	if period < minKeyRotationPeriod {
+		return fmt.Errorf("key rotation period %q is less than %v", config.GetPeriod(), minKeyRotationPeriod)
+	}
+	if config.GetOverlap() != "" {
+		overlap, err := time.ParseDuration(config.GetOverlap())
+		if err != nil {
+			return fmt.Errorf("key rotation overlap %q is invalid: %w", config.GetOverlap(), err)
+		}
+		if overlap < 0 {
+			return fmt.Errorf("key rotation overlap %q is negative", config.GetOverlap())
+		}
+		if overlap > period/2 {
+			return fmt.Errorf("key rotation overlap %q is larger than half of the period", config.GetOverlap())
+		}
+	}
+	return nil
+}
+
+// KeyRotationFromConfig returns the key rotation schedule of the config,
+// or nil if key rotation is not enabled. The config must be valid.
+func KeyRotationFromConfig(config *pb.KeyRotationConfig) *cipher.KeyRotation {
+	if config == nil {
+		return nil
+	}
+	period, err := time.ParseDuration(config.GetPeriod())
+	if err != nil || period <= 0 {
+		return nil
+	}
+	overlap := defaultKeyRotationOverlap
+	if config.GetOverlap() != "" {
+		if d, err := time.ParseDuration(config.GetOverlap()); err == nil {
+			overlap = d
+		}
+	}
+	return cipher.NewKeyRotation(period, overlap)
+}
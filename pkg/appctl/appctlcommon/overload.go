@@ -0,0 +1,48 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"fmt"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+// ValidateOverloadLimits checks the maximum number of sessions, the maximum
+// CPU usage and the maximum bandwidth of the server.
+func ValidateOverloadLimits(settings *pb.ServerAdvancedSettings) error {
+	if settings.GetMaxSessions() < 0 {
+		return fmt.Errorf("max sessions %d is negative", settings.GetMaxSessions())
+	}
+	if settings.GetMaxCPUPercent() < 0 || settings.GetMaxCPUPercent() > 100 {
+		return fmt.Errorf("max CPU percent %d is not between 0 and 100", settings.GetMaxCPUPercent())
+	}
+	if settings.GetMaxBandwidthMbps() < 0 {
+		return fmt.Errorf("max bandwidth %d Mbps is negative", settings.GetMaxBandwidthMbps())
+	}
+	return nil
+}
+
+// OverloadLimitsFromConfig returns the overload limits of the server.
+// The settings must be valid.
+func OverloadLimitsFromConfig(settings *pb.ServerAdvancedSettings) protocol.OverloadLimits {
+	return protocol.OverloadLimits{
+		MaxSessions:      int(settings.GetMaxSessions()),
+		MaxCPUPercent:    int(settings.GetMaxCPUPercent()),
+		MaxBandwidthMbps: int(settings.GetMaxBandwidthMbps()),
+	}
+}
@@ -37,6 +37,11 @@ func FlatPortBindings(bindings []*pb.PortBinding) ([]*pb.PortBinding, error) {
 	}
 	tcp := make(map[int32]struct{})
 	udp := make(map[int32]struct{})
+	ws := make(map[int32]struct{})
+	auto := make(map[int32]struct{})
+	if err := validatePortHopping(bindings); err != nil {
+		return res, err
+	}
 	for _, binding := range bindings {
 		if binding.GetProtocol() == pb.TransportProtocol_UNKNOWN_TRANSPORT_PROTOCOL {
 			return res, fmt.Errorf("protocol is not set")
@@ -50,30 +55,17 @@ func FlatPortBindings(bindings []*pb.PortBinding) ([]*pb.PortBinding, error) {
 				tcp[binding.GetPort()] = struct{}{}
 			case pb.TransportProtocol_UDP:
 				udp[binding.GetPort()] = struct{}{}
+			case pb.TransportProtocol_WEBSOCKET:
+				ws[binding.GetPort()] = struct{}{}
+			case pb.TransportProtocol_AUTO:
+				auto[binding.GetPort()] = struct{}{}
 			default:
 				return res, fmt.Errorf("unknown protocol %s", binding.GetProtocol().String())
 			}
 		} else {
-			matches := validPortRange.FindStringSubmatch(binding.GetPortRange())
-			if len(matches) != 3 {
-				return res, fmt.Errorf("unable to parse port range %q", binding.GetPortRange())
-			}
-			small, err := strconv.Atoi(matches[1])
-			if err != nil {
-				return res, fmt.Errorf("unable to parse int from %q", matches[1])
-			}
-			big, err := strconv.Atoi(matches[2])
+			small, big, err := ParsePortRange(binding.GetPortRange())
 			if err != nil {
-				return res, fmt.Errorf("unable to parse int from %q", matches[2])
-			}
-			if small < 1 || small > 65535 {
-				return res, fmt.Errorf("port number %d is invalid", small)
-			}
-			if big < 1 || big > 65535 {
-				return res, fmt.Errorf("port number %d is invalid", big)
-			}
-			if small > big {
-				return res, fmt.Errorf("begin of port range %d is bigger than end of port range %d", small, big)
+				return res, err
 			}
 			switch binding.GetProtocol() {
 			case pb.TransportProtocol_TCP:
@@ -84,6 +76,14 @@ func FlatPortBindings(bindings []*pb.PortBinding) ([]*pb.PortBinding, error) {
 				for i := small; i <= big; i++ {
 					udp[int32(i)] = struct{}{}
 				}
+			case pb.TransportProtocol_WEBSOCKET:
+				for i := small; i <= big; i++ {
+					ws[int32(i)] = struct{}{}
+				}
+			case pb.TransportProtocol_AUTO:
+				for i := small; i <= big; i++ {
+					auto[int32(i)] = struct{}{}
+				}
 			default:
 				return res, fmt.Errorf("unknown protocol %s", binding.GetProtocol().String())
 			}
@@ -91,14 +91,37 @@ func FlatPortBindings(bindings []*pb.PortBinding) ([]*pb.PortBinding, error) {
 	}
 	tcpList := make([]int32, 0)
 	udpList := make([]int32, 0)
+	wsList := make([]int32, 0)
+	autoList := make([]int32, 0)
 	for port := range tcp {
 		tcpList = append(tcpList, port)
 	}
 	for port := range udp {
 		udpList = append(udpList, port)
 	}
+	for port := range ws {
+		// WebSocket and TCP can't listen to the same port.
+		if _, found := tcp[port]; found {
+			return res, fmt.Errorf("port %d is used by both TCP and WEBSOCKET protocols", port)
+		}
+		wsList = append(wsList, port)
+	}
+	for port := range auto {
+		// AUTO already uses both TCP and UDP.
+		for _, other := range []struct {
+			name  string
+			ports map[int32]struct{}
+		}{{"TCP", tcp}, {"UDP", udp}, {"WEBSOCKET", ws}} {
+			if _, found := other.ports[port]; found {
+				return res, fmt.Errorf("port %d is used by both AUTO and %s protocols", port, other.name)
+			}
+		}
+		autoList = append(autoList, port)
+	}
 	sort.Slice(tcpList, func(i, j int) bool { return tcpList[i] < tcpList[j] })
 	sort.Slice(udpList, func(i, j int) bool { return udpList[i] < udpList[j] })
+	sort.Slice(wsList, func(i, j int) bool { return wsList[i] < wsList[j] })
+	sort.Slice(autoList, func(i, j int) bool { return autoList[i] < autoList[j] })
 	for _, port := range tcpList {
 		res = append(res, &pb.PortBinding{
 			Port:     proto.Int32(port),
@@ -111,5 +134,44 @@ func FlatPortBindings(bindings []*pb.PortBinding) ([]*pb.PortBinding, error) {
 			Protocol: pb.TransportProtocol_UDP.Enum(),
 		})
 	}
+	for _, port := range wsList {
+		res = append(res, &pb.PortBinding{
+			Port:     proto.Int32(port),
+			Protocol: pb.TransportProtocol_WEBSOCKET.Enum(),
+		})
+	}
+	for _, port := range autoList {
+		res = append(res, &pb.PortBinding{
+			Port:     proto.Int32(port),
+			Protocol: pb.TransportProtocol_AUTO.Enum(),
+		})
+	}
 	return res, nil
 }
+
+// ParsePortRange parses a port range in "<begin>-<end>" format,
+// e.g. "2000-2010", and returns the begin and end ports.
+func ParsePortRange(portRange string) (int, int, error) {
+	matches := validPortRange.FindStringSubmatch(portRange)
+	if len(matches) != 3 {
+		return 0, 0, fmt.Errorf("unable to parse port range %q", portRange)
+	}
+	small, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to parse int from %q", matches[1])
+	}
+	big, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to parse int from %q", matches[2])
+	}
+	if small < 1 || small > 65535 {
+		return 0, 0, fmt.Errorf("port number %d is invalid", small)
+	}
+	if big < 1 || big > 65535 {
+		return 0, 0, fmt.Errorf("port number %d is invalid", big)
+	}
+	if small > big {
+		return 0, 0, fmt.Errorf("begin of port range %d is bigger than end of port range %d", small, big)
+	}
+	return small, big, nil
+}
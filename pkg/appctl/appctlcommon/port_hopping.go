@@ -0,0 +1,102 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+// minPortHoppingInterval is the minimum interval to change the active
+// port. Each change creates new underlays.
+const minPortHoppingInterval = 10 * time.Second
+
+// validatePortHopping checks the port bindings with port hopping.
+// Port hopping requires a UDP port range, a valid interval, and the port
+// ranges with port hopping must not overlap.
+func validatePortHopping(bindings []*pb.PortBinding) error {
+	var ranges [][2]int
+	for _, binding := range bindings {
+		if binding.GetPortHoppingInterval() == "" {
+			continue
+		}
+		if binding.GetPortRange() == "" {
+			return fmt.Errorf("port hopping interval is set without port range")
+		}
+		if binding.GetProtocol() != pb.TransportProtocol_UDP {
+			return fmt.Errorf("port hopping is not supported by protocol %s", binding.GetProtocol().String())
+		}
+		d, err := time.ParseDuration(binding.GetPortHoppingInterval())
+		if err != nil {
+			return fmt.Errorf("port hopping interval %q is invalid: %w", binding.GetPortHoppingInterval(), err)
+		}
+		if d < minPortHoppingInterval {
+			return fmt.Errorf("port hopping interval %q is less than %v", binding.GetPortHoppingInterval(), minPortHoppingInterval)
+		}
+		begin, end, err := ParsePortRange(binding.GetPortRange())
+		if err != nil {
+			return err
+		}
+		for _, r := range ranges {
+			if begin <= r[1] && r[0] <= end {
+				return fmt.Errorf("port hopping range %q overlaps with port hopping range %d-%d", binding.GetPortRange(), r[0], r[1])
+			}
+		}
+		ranges = append(ranges, [2]int{begin, end})
+	}
+	return nil
+}
+
+// PortHoppingOptionsFromConfig returns the port hopping options of the
+// port bindings with port hopping. Invalid port bindings are ignored.
+func PortHoppingOptionsFromConfig(bindings []*pb.PortBinding) []*protocol.PortHoppingOptions {
+	var res []*protocol.PortHoppingOptions
+	for _, binding := range bindings {
+		if binding.GetPortHoppingInterval() == "" {
+			continue
+		}
+		d, err := time.ParseDuration(binding.GetPortHoppingInterval())
+		if err != nil || d <= 0 {
+			continue
+		}
+		begin, end, err := ParsePortRange(binding.GetPortRange())
+		if err != nil {
+			continue
+		}
+		res = append(res, &protocol.PortHoppingOptions{
+			BeginPort: begin,
+			EndPort:   end,
+			Interval:  d,
+		})
+	}
+	return res
+}
+
+// PortBindingsWithoutHopping returns the port bindings without port
+// hopping. Proxy client connects to a port range with port hopping
+// as a single endpoint.
+func PortBindingsWithoutHopping(bindings []*pb.PortBinding) []*pb.PortBinding {
+	res := make([]*pb.PortBinding, 0, len(bindings))
+	for _, binding := range bindings {
+		if binding.GetPortHoppingInterval() == "" {
+			res = append(res, binding)
+		}
+	}
+	return res
+}
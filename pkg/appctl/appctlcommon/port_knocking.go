@@ -0,0 +1,103 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+const (
+	// defaultPortKnockingTimeout is the default maximum time to finish
+	// the port knocking sequence.
+	defaultPortKnockingTimeout = 10 * time.Second
+
+	// defaultPortKnockingOpenDuration is the default time that proxy server
+	// accepts new connections after the port knocking sequence is finished.
+	defaultPortKnockingOpenDuration = 30 * time.Second
+)
+
+// ValidatePortKnockingConfig checks the sequence and durations of port
+// knocking. The ports of the sequence can't be used by port bindings.
+func ValidatePortKnockingConfig(config *pb.PortKnockingConfig, portBindings []*pb.PortBinding) error {
+	if config == nil {
+		return nil
+	}
+	if len(config.GetSequence()) < 2 {
+		return fmt.Errorf("port knocking sequence must have at least 2 steps")
+	}
+	used := make(map[int32]pb.TransportProtocol)
+	for _, binding := range portBindings {
+		used[binding.GetPort()] = binding.GetProtocol()
+	}
+	for _, step := range config.GetSequence() {
+		if step.GetPortRange() != "" {
+			return fmt.Errorf("port knocking step can't use port range")
+		}
+		if step.GetPort() < 1 || step.GetPort() > 65535 {
+			return fmt.Errorf("port knocking port number %d is invalid", step.GetPort())
+		}
+		if step.GetProtocol() != pb.TransportProtocol_TCP && step.GetProtocol() != pb.TransportProtocol_UDP {
+			return fmt.Errorf("port knocking step only supports TCP and UDP protocol")
+		}
+		if protocol, found := used[step.GetPort()]; found && (protocol == step.GetProtocol() || protocol == pb.TransportProtocol_AUTO ||
+			(protocol == pb.TransportProtocol_WEBSOCKET && step.GetProtocol() == pb.TransportProtocol_TCP)) {
+			return fmt.Errorf("port knocking port %d is also used by port bindings", step.GetPort())
+		}
+	}
+	for name, value := range map[string]string{"timeout": config.GetTimeout(), "open duration": config.GetOpenDuration()} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("port knocking %s %q is invalid: %w", name, value, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("port knocking %s %q is not positive", name, value)
+		}
+	}
+	return nil
+}
+
+// PortKnockingOptionsFromConfig returns the port knocking options of the
+// config, or nil if port knocking is not enabled. The config must be valid.
+func PortKnockingOptionsFromConfig(config *pb.PortKnockingConfig) *protocol.PortKnockingOptions {
+	if config == nil || len(config.GetSequence()) == 0 {
+		return nil
+	}
+	opts := &protocol.PortKnockingOptions{
+		Timeout:      defaultPortKnockingTimeout,
+		OpenDuration: defaultPortKnockingOpenDuration,
+	}
+	for _, step := range config.GetSequence() {
+		network := "tcp"
+		if step.GetProtocol() == pb.TransportProtocol_UDP {
+			network = "udp"
+		}
+		opts.Sequence = append(opts.Sequence, protocol.KnockStep{Network: network, Port: int(step.GetPort())})
+	}
+	if d, err := time.ParseDuration(config.GetTimeout()); err == nil && d > 0 {
+		opts.Timeout = d
+	}
+	if d, err := time.ParseDuration(config.GetOpenDuration()); err == nil && d > 0 {
+		opts.OpenDuration = d
+	}
+	return opts
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+const (
+	defaultReconnectInitialDelay = 500 * time.Millisecond
+	defaultReconnectMaxDelay     = 10 * time.Second
+	defaultReconnectMaxWait      = 30 * time.Second
+)
+
+// ValidateReconnectConfig checks the delays and the maximum wait time
+// of reconnect.
+func ValidateReconnectConfig(config *pb.ReconnectConfig) error {
+	if config == nil {
+		return nil
+	}
+	durations := []struct {
+		name  string
+		value string
+	}{
+		{"initial delay", config.GetInitialDelay()},
+		{"max delay", config.GetMaxDelay()},
+		{"max wait", config.GetMaxWait()},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("reconnect %s %q is invalid: %w", d.name, d.value, err)
+		}
+		if v <= 0 {
+			return fmt.Errorf("reconnect %s %q is not positive", d.name, d.value)
+		}
+	}
+	policy := ReconnectPolicyFromConfig(config)
+	if policy.MaxDelay < policy.InitialDelay {
+		return fmt.Errorf("reconnect max delay %v is less than initial delay %v", policy.MaxDelay, policy.InitialDelay)
+	}
+	return nil
+}
+
+// ReconnectPolicyFromConfig returns the reconnect policy of the config.
+// If the config is nil, reconnect is disabled.
+func ReconnectPolicyFromConfig(config *pb.ReconnectConfig) protocol.ReconnectPolicy {
+	if config == nil {
+		return protocol.ReconnectPolicy{}
+	}
+	policy := protocol.ReconnectPolicy{
+		InitialDelay: defaultReconnectInitialDelay,
+		MaxDelay:     defaultReconnectMaxDelay,
+		MaxWait:      defaultReconnectMaxWait,
+	}
+	if d, err := time.ParseDuration(config.GetInitialDelay()); err == nil {
+		policy.InitialDelay = d
+	}
+	if d, err := time.ParseDuration(config.GetMaxDelay()); err == nil {
+		policy.MaxDelay = d
+	}
+	if d, err := time.ParseDuration(config.GetMaxWait()); err == nil {
+		policy.MaxWait = d
+	}
+	return policy
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+// ValidateTLSCamouflageConfig checks the options of TLS camouflage.
+func ValidateTLSCamouflageConfig(config *pb.TLSCamouflageConfig) error {
+	if config == nil {
+		return nil
+	}
+	if name := config.GetServerName(); name != "" {
+		if strings.ContainsAny(name, "/: ") || net.ParseIP(name) != nil {
+			return fmt.Errorf("TLS camouflage server name %q is not a valid domain name", name)
+		}
+	}
+	for _, alpn := range config.GetAlpn() {
+		if alpn == "" {
+			return fmt.Errorf("TLS camouflage ALPN is empty")
+		}
+	}
+	if (config.GetCertificateFile() == "") != (config.GetPrivateKeyFile() == "") {
+		return fmt.Errorf("TLS camouflage certificate file and private key file must be set together")
+	}
+	if addr := config.GetFallbackAddress(); addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("TLS camouflage fallback address %q is invalid: %w", addr, err)
+		}
+	}
+	return nil
+}
+
+// ClientTLSCamouflageOptions returns the options used by proxy client to
+// connect to the TCP port bindings of the server, or nil if TLS camouflage
+// is not used by the server.
+func ClientTLSCamouflageOptions(server *pb.ServerEndpoint) *protocol.TLSCamouflageOptions {
+	config := server.GetTlsCamouflage()
+	if config == nil {
+		return nil
+	}
+	alpn := config.GetAlpn()
+	if len(alpn) == 0 {
+		alpn = []string{"h2", "http/1.1"}
+	}
+	return &protocol.TLSCamouflageOptions{
+		TLSConfig: &tls.Config{
+			ServerName: config.GetServerName(),
+			NextProtos: alpn,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+}
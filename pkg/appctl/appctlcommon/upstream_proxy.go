@@ -0,0 +1,43 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/socks5"
+)
+
+// UpstreamProxyDialer returns the dialer to connect to proxy servers
+// through the upstream proxy. The dialer connects to the upstream proxy
+// with the next dialer. The config must be valid.
+func UpstreamProxyDialer(config *pb.UpstreamProxy, next apicommon.Dialer) apicommon.Dialer {
+	d := &socks5.UpstreamDialer{
+		Protocol: socks5.UpstreamSocks5,
+		Address:  config.GetAddress(),
+		Dialer:   next,
+	}
+	if config.GetProtocol() == pb.UpstreamProxyProtocol_UPSTREAM_HTTP {
+		d.Protocol = socks5.UpstreamHTTP
+	}
+	if config.GetAuth() != nil {
+		d.Credential = &socks5.Credential{
+			User:     config.GetAuth().GetUser(),
+			Password: config.GetAuth().GetPassword(),
+		}
+	}
+	return d
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctlcommon
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+// ValidateWebSocketConfig checks the options of WEBSOCKET port bindings.
+func ValidateWebSocketConfig(config *pb.WebSocketConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.GetPath() != "" && !strings.HasPrefix(config.GetPath(), "/") {
+		return fmt.Errorf("WebSocket path %q must begin with \"/\"", config.GetPath())
+	}
+	if strings.ContainsAny(config.GetHost(), "/ ") {
+		return fmt.Errorf("WebSocket host %q is invalid", config.GetHost())
+	}
+	if (config.GetCertificateFile() == "") != (config.GetPrivateKeyFile() == "") {
+		return fmt.Errorf("WebSocket certificate file and private key file must be set together")
+	}
+	return nil
+}
+
+// ClientWebSocketOptions returns the options used by proxy client to
+// connect to the WEBSOCKET port bindings of the server.
+// If the host is not set, the server domain name is used.
+func ClientWebSocketOptions(server *pb.ServerEndpoint) *protocol.WebSocketOptions {
+	config := server.GetWebSocket()
+	opts := &protocol.WebSocketOptions{
+		Path: config.GetPath(),
+		Host: config.GetHost(),
+	}
+	if opts.Host == "" {
+		opts.Host = server.GetDomainName()
+	}
+	if !config.GetDisableTLS() {
+		opts.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return opts
+}
@@ -49,7 +49,7 @@ var file_appctl_proto_rpc_proto_rawDesc = []byte{
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x28, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x32, 0xc0, 0x05, 0x0a, 0x17, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x61, 0x6e, 0x61, 0x67,
+	0x32, 0xc0, 0x08, 0x0a, 0x17, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x61, 0x6e, 0x61, 0x67,
 	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3f, 0x0a, 0x09,
 	0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
 	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
@@ -58,42 +58,66 @@ var file_appctl_proto_rpc_proto_rawDesc = []byte{
 	0x04, 0x45, 0x78, 0x69, 0x74, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e,
 	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3b, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x72,
-	0x69, 0x63, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x6d, 0x69,
-	0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69,
-	0x63, 0x73, 0x12, 0x4b, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x43, 0x0a, 0x06, 0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x12,
+	0x21, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x52,
+	0x65, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x3b, 0x0a, 0x0a, 0x47, 0x65,
+	0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
 	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x1a, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
-	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12,
-	0x41, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70,
-	0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x18, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
-	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75,
-	0x6d, 0x70, 0x12, 0x48, 0x0a, 0x0f, 0x53, 0x74, 0x61, 0x72, 0x74, 0x43, 0x50, 0x55, 0x50, 0x72,
-	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
-	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x61, 0x76, 0x65,
-	0x50, 0x61, 0x74, 0x68, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x40, 0x0a, 0x0e,
-	0x53, 0x74, 0x6f, 0x70, 0x43, 0x50, 0x55, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x16,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x47,
-	0x0a, 0x0e, 0x47, 0x65, 0x74, 0x48, 0x65, 0x61, 0x70, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
-	0x12, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
-	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x61, 0x76, 0x65, 0x50, 0x61, 0x74, 0x68, 0x1a,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4d, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4d, 0x65,
-	0x6d, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x16,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1e, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61,
-	0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74,
-	0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x3b, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x6d,
-	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x56, 0x65, 0x72, 0x73,
-	0x69, 0x6f, 0x6e, 0x32, 0xb8, 0x08, 0x0a, 0x17, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4d, 0x61,
+	0x1a, 0x15, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x4b, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x55, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x44, 0x65, 0x73, 0x74, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x24, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x50, 0x0a, 0x13, 0x47,
+	0x65, 0x74, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x21, 0x2e, 0x6d, 0x69, 0x65,
+	0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x47, 0x0a,
+	0x0c, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4c, 0x61, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x41, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x54, 0x68, 0x72,
+	0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
+	0x18, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54,
+	0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x48, 0x0a, 0x0f, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x43, 0x50, 0x55, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x53, 0x61, 0x76, 0x65, 0x50, 0x61, 0x74, 0x68, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x12, 0x40, 0x0a, 0x0e, 0x53, 0x74, 0x6f, 0x70, 0x43, 0x50, 0x55, 0x50, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x47, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x48, 0x65, 0x61, 0x70,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
+	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x61,
+	0x76, 0x65, 0x50, 0x61, 0x74, 0x68, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4d,
+	0x0a, 0x13, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69,
+	0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1e, 0x2e,
+	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x3b, 0x0a,
+	0x0a, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x47, 0x0a, 0x0a, 0x46, 0x6f,
+	0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6d, 0x69, 0x65, 0x72,
+	0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x30, 0x01, 0x32, 0xac, 0x0d, 0x0a, 0x17, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4d, 0x61,
 	0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
 	0x3f, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x2e, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
@@ -130,110 +154,190 @@ var file_appctl_proto_rpc_proto_rawDesc = []byte{
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
 	0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70,
 	0x63, 0x74, 0x6c, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x4c,
-	0x69, 0x73, 0x74, 0x12, 0x45, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x21, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
-	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x57, 0x69, 0x74, 0x68, 0x4d,
-	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x41, 0x0a, 0x0d, 0x47, 0x65,
-	0x74, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x16, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x1a, 0x18, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
-	0x74, 0x6c, 0x2e, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x48, 0x0a,
-	0x0f, 0x53, 0x74, 0x61, 0x72, 0x74, 0x43, 0x50, 0x55, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
-	0x12, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
-	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x61, 0x76, 0x65, 0x50, 0x61, 0x74, 0x68, 0x1a,
+	0x69, 0x73, 0x74, 0x12, 0x47, 0x0a, 0x0b, 0x4b, 0x69, 0x6c, 0x6c, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x20, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74,
+	0x6c, 0x2e, 0x4b, 0x69, 0x6c, 0x6c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x45, 0x0a, 0x08,
+	0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x21, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x57, 0x69, 0x74, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x4c,
+	0x69, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x55, 0x73, 0x65, 0x72, 0x12, 0x12,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x38, 0x0a, 0x0a, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x12, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x1a, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x12, 0x41, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73,
+	0x65, 0x72, 0x73, 0x12, 0x1a, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x1a,
 	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x40, 0x0a, 0x0e, 0x53, 0x74, 0x6f, 0x70, 0x43,
-	0x50, 0x55, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x47, 0x0a, 0x0e, 0x47, 0x65, 0x74,
-	0x48, 0x65, 0x61, 0x70, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x6d, 0x69,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4f, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x55, 0x73,
+	0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x26, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x57, 0x69, 0x74, 0x68, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x4d, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x12, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x20, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x54, 0x72, 0x61, 0x66,
+	0x66, 0x69, 0x63, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x4e, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x50, 0x6f,
+	0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x1a, 0x23, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74,
+	0x6c, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x3c, 0x0a, 0x0a, 0x52, 0x6f, 0x74, 0x61, 0x74,
+	0x65, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e,
+	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4b, 0x65, 0x79,
+	0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x41, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x54, 0x68, 0x72, 0x65,
+	0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x18,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54, 0x68,
+	0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x48, 0x0a, 0x0f, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x43, 0x50, 0x55, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x6d, 0x69,
 	0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69,
 	0x6c, 0x65, 0x53, 0x61, 0x76, 0x65, 0x50, 0x61, 0x74, 0x68, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
 	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x12, 0x4d, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53,
-	0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x1a, 0x1e, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
-	0x2e, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63,
-	0x73, 0x12, 0x3b, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
-	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
-	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x32,
-	0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6e, 0x66,
-	0x65, 0x69, 0x6e, 0x2f, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2f, 0x76, 0x33, 0x2f, 0x70, 0x6b, 0x67,
-	0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x67, 0x72,
-	0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x79, 0x12, 0x40, 0x0a, 0x0e, 0x53, 0x74, 0x6f, 0x70, 0x43, 0x50, 0x55, 0x50, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x16, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45,
+	0x6d, 0x70, 0x74, 0x79, 0x12, 0x47, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x48, 0x65, 0x61, 0x70, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61,
+	0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x61, 0x76,
+	0x65, 0x50, 0x61, 0x74, 0x68, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x4d, 0x0a,
+	0x13, 0x47, 0x65, 0x74, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73,
+	0x74, 0x69, 0x63, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1e, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x3b, 0x0a, 0x0a,
+	0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x1a, 0x15, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74,
+	0x6c, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x47, 0x0a, 0x0a, 0x46, 0x6f, 0x6c,
+	0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
+	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x46, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x67,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x30, 0x01, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x65, 0x6e, 0x66, 0x65, 0x69, 0x6e, 0x2f, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2f, 0x76, 0x33,
+	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_appctl_proto_rpc_proto_goTypes = []interface{}{
-	(*emptypb.Empty)(nil),                // 0: google.protobuf.Empty
-	(*appctlpb.ProfileSavePath)(nil),     // 1: mieru.appctl.ProfileSavePath
-	(*appctlpb.ServerConfig)(nil),        // 2: mieru.appctl.ServerConfig
-	(*appctlpb.AppStatusMsg)(nil),        // 3: mieru.appctl.AppStatusMsg
-	(*appctlpb.Metrics)(nil),             // 4: mieru.appctl.Metrics
-	(*appctlpb.SessionInfoList)(nil),     // 5: mieru.appctl.SessionInfoList
-	(*appctlpb.ThreadDump)(nil),          // 6: mieru.appctl.ThreadDump
-	(*appctlpb.MemoryStatistics)(nil),    // 7: mieru.appctl.MemoryStatistics
-	(*appctlpb.Version)(nil),             // 8: mieru.appctl.Version
-	(*appctlpb.UserWithMetricsList)(nil), // 9: mieru.appctl.UserWithMetricsList
+	(*emptypb.Empty)(nil),                     // 0: google.protobuf.Empty
+	(*appctlpb.ReloadClientRequest)(nil),      // 1: mieru.appctl.ReloadClientRequest
+	(*appctlpb.ProfileSavePath)(nil),          // 2: mieru.appctl.ProfileSavePath
+	(*appctlpb.FollowLogsRequest)(nil),        // 3: mieru.appctl.FollowLogsRequest
+	(*appctlpb.ServerConfig)(nil),             // 4: mieru.appctl.ServerConfig
+	(*appctlpb.KillSessionRequest)(nil),       // 5: mieru.appctl.KillSessionRequest
+	(*appctlpb.User)(nil),                     // 6: mieru.appctl.User
+	(*appctlpb.UserNameList)(nil),             // 7: mieru.appctl.UserNameList
+	(*appctlpb.AppStatusMsg)(nil),             // 8: mieru.appctl.AppStatusMsg
+	(*appctlpb.Metrics)(nil),                  // 9: mieru.appctl.Metrics
+	(*appctlpb.SessionInfoList)(nil),          // 10: mieru.appctl.SessionInfoList
+	(*appctlpb.DestinationTrafficList)(nil),   // 11: mieru.appctl.DestinationTrafficList
+	(*appctlpb.ProxyConnectionList)(nil),      // 12: mieru.appctl.ProxyConnectionList
+	(*appctlpb.ServerLatencyList)(nil),        // 13: mieru.appctl.ServerLatencyList
+	(*appctlpb.ThreadDump)(nil),               // 14: mieru.appctl.ThreadDump
+	(*appctlpb.MemoryStatistics)(nil),         // 15: mieru.appctl.MemoryStatistics
+	(*appctlpb.Version)(nil),                  // 16: mieru.appctl.Version
+	(*appctlpb.LogEntry)(nil),                 // 17: mieru.appctl.LogEntry
+	(*appctlpb.UserWithMetricsList)(nil),      // 18: mieru.appctl.UserWithMetricsList
+	(*appctlpb.UserGroupWithMetricsList)(nil), // 19: mieru.appctl.UserGroupWithMetricsList
+	(*appctlpb.CountryTrafficList)(nil),       // 20: mieru.appctl.CountryTrafficList
+	(*appctlpb.PortBindingStatusList)(nil),    // 21: mieru.appctl.PortBindingStatusList
+	(*appctlpb.KeyEpoch)(nil),                 // 22: mieru.appctl.KeyEpoch
 }
 var file_appctl_proto_rpc_proto_depIdxs = []int32{
 	0,  // 0: mieru.appctl.ClientManagementService.GetStatus:input_type -> google.protobuf.Empty
 	0,  // 1: mieru.appctl.ClientManagementService.Exit:input_type -> google.protobuf.Empty
-	0,  // 2: mieru.appctl.ClientManagementService.GetMetrics:input_type -> google.protobuf.Empty
-	0,  // 3: mieru.appctl.ClientManagementService.GetSessionInfoList:input_type -> google.protobuf.Empty
-	0,  // 4: mieru.appctl.ClientManagementService.GetThreadDump:input_type -> google.protobuf.Empty
-	1,  // 5: mieru.appctl.ClientManagementService.StartCPUProfile:input_type -> mieru.appctl.ProfileSavePath
-	0,  // 6: mieru.appctl.ClientManagementService.StopCPUProfile:input_type -> google.protobuf.Empty
-	1,  // 7: mieru.appctl.ClientManagementService.GetHeapProfile:input_type -> mieru.appctl.ProfileSavePath
-	0,  // 8: mieru.appctl.ClientManagementService.GetMemoryStatistics:input_type -> google.protobuf.Empty
-	0,  // 9: mieru.appctl.ClientManagementService.GetVersion:input_type -> google.protobuf.Empty
-	0,  // 10: mieru.appctl.ServerManagementService.GetStatus:input_type -> google.protobuf.Empty
-	0,  // 11: mieru.appctl.ServerManagementService.Start:input_type -> google.protobuf.Empty
-	0,  // 12: mieru.appctl.ServerManagementService.Stop:input_type -> google.protobuf.Empty
-	0,  // 13: mieru.appctl.ServerManagementService.GetConfig:input_type -> google.protobuf.Empty
-	2,  // 14: mieru.appctl.ServerManagementService.SetConfig:input_type -> mieru.appctl.ServerConfig
-	0,  // 15: mieru.appctl.ServerManagementService.Reload:input_type -> google.protobuf.Empty
-	0,  // 16: mieru.appctl.ServerManagementService.Exit:input_type -> google.protobuf.Empty
-	0,  // 17: mieru.appctl.ServerManagementService.GetMetrics:input_type -> google.protobuf.Empty
-	0,  // 18: mieru.appctl.ServerManagementService.GetSessionInfoList:input_type -> google.protobuf.Empty
-	0,  // 19: mieru.appctl.ServerManagementService.GetUsers:input_type -> google.protobuf.Empty
-	0,  // 20: mieru.appctl.ServerManagementService.GetThreadDump:input_type -> google.protobuf.Empty
-	1,  // 21: mieru.appctl.ServerManagementService.StartCPUProfile:input_type -> mieru.appctl.ProfileSavePath
-	0,  // 22: mieru.appctl.ServerManagementService.StopCPUProfile:input_type -> google.protobuf.Empty
-	1,  // 23: mieru.appctl.ServerManagementService.GetHeapProfile:input_type -> mieru.appctl.ProfileSavePath
-	0,  // 24: mieru.appctl.ServerManagementService.GetMemoryStatistics:input_type -> google.protobuf.Empty
-	0,  // 25: mieru.appctl.ServerManagementService.GetVersion:input_type -> google.protobuf.Empty
-	3,  // 26: mieru.appctl.ClientManagementService.GetStatus:output_type -> mieru.appctl.AppStatusMsg
-	0,  // 27: mieru.appctl.ClientManagementService.Exit:output_type -> google.protobuf.Empty
-	4,  // 28: mieru.appctl.ClientManagementService.GetMetrics:output_type -> mieru.appctl.Metrics
-	5,  // 29: mieru.appctl.ClientManagementService.GetSessionInfoList:output_type -> mieru.appctl.SessionInfoList
-	6,  // 30: mieru.appctl.ClientManagementService.GetThreadDump:output_type -> mieru.appctl.ThreadDump
-	0,  // 31: mieru.appctl.ClientManagementService.StartCPUProfile:output_type -> google.protobuf.Empty
-	0,  // 32: mieru.appctl.ClientManagementService.StopCPUProfile:output_type -> google.protobuf.Empty
-	0,  // 33: mieru.appctl.ClientManagementService.GetHeapProfile:output_type -> google.protobuf.Empty
-	7,  // 34: mieru.appctl.ClientManagementService.GetMemoryStatistics:output_type -> mieru.appctl.MemoryStatistics
-	8,  // 35: mieru.appctl.ClientManagementService.GetVersion:output_type -> mieru.appctl.Version
-	3,  // 36: mieru.appctl.ServerManagementService.GetStatus:output_type -> mieru.appctl.AppStatusMsg
-	0,  // 37: mieru.appctl.ServerManagementService.Start:output_type -> google.protobuf.Empty
-	0,  // 38: mieru.appctl.ServerManagementService.Stop:output_type -> google.protobuf.Empty
-	2,  // 39: mieru.appctl.ServerManagementService.GetConfig:output_type -> mieru.appctl.ServerConfig
-	2,  // 40: mieru.appctl.ServerManagementService.SetConfig:output_type -> mieru.appctl.ServerConfig
-	0,  // 41: mieru.appctl.ServerManagementService.Reload:output_type -> google.protobuf.Empty
-	0,  // 42: mieru.appctl.ServerManagementService.Exit:output_type -> google.protobuf.Empty
-	4,  // 43: mieru.appctl.ServerManagementService.GetMetrics:output_type -> mieru.appctl.Metrics
-	5,  // 44: mieru.appctl.ServerManagementService.GetSessionInfoList:output_type -> mieru.appctl.SessionInfoList
-	9,  // 45: mieru.appctl.ServerManagementService.GetUsers:output_type -> mieru.appctl.UserWithMetricsList
-	6,  // 46: mieru.appctl.ServerManagementService.GetThreadDump:output_type -> mieru.appctl.ThreadDump
-	0,  // 47: mieru.appctl.ServerManagementService.StartCPUProfile:output_type -> google.protobuf.Empty
-	0,  // 48: mieru.appctl.ServerManagementService.StopCPUProfile:output_type -> google.protobuf.Empty
-	0,  // 49: mieru.appctl.ServerManagementService.GetHeapProfile:output_type -> google.protobuf.Empty
-	7,  // 50: mieru.appctl.ServerManagementService.GetMemoryStatistics:output_type -> mieru.appctl.MemoryStatistics
-	8,  // 51: mieru.appctl.ServerManagementService.GetVersion:output_type -> mieru.appctl.Version
-	26, // [26:52] is the sub-list for method output_type
-	0,  // [0:26] is the sub-list for method input_type
+	1,  // 2: mieru.appctl.ClientManagementService.Reload:input_type -> mieru.appctl.ReloadClientRequest
+	0,  // 3: mieru.appctl.ClientManagementService.GetMetrics:input_type -> google.protobuf.Empty
+	0,  // 4: mieru.appctl.ClientManagementService.GetSessionInfoList:input_type -> google.protobuf.Empty
+	0,  // 5: mieru.appctl.ClientManagementService.GetDestinationTraffic:input_type -> google.protobuf.Empty
+	0,  // 6: mieru.appctl.ClientManagementService.GetProxyConnections:input_type -> google.protobuf.Empty
+	0,  // 7: mieru.appctl.ClientManagementService.ProbeServers:input_type -> google.protobuf.Empty
+	0,  // 8: mieru.appctl.ClientManagementService.GetThreadDump:input_type -> google.protobuf.Empty
+	2,  // 9: mieru.appctl.ClientManagementService.StartCPUProfile:input_type -> mieru.appctl.ProfileSavePath
+	0,  // 10: mieru.appctl.ClientManagementService.StopCPUProfile:input_type -> google.protobuf.Empty
+	2,  // 11: mieru.appctl.ClientManagementService.GetHeapProfile:input_type -> mieru.appctl.ProfileSavePath
+	0,  // 12: mieru.appctl.ClientManagementService.GetMemoryStatistics:input_type -> google.protobuf.Empty
+	0,  // 13: mieru.appctl.ClientManagementService.GetVersion:input_type -> google.protobuf.Empty
+	3,  // 14: mieru.appctl.ClientManagementService.FollowLogs:input_type -> mieru.appctl.FollowLogsRequest
+	0,  // 15: mieru.appctl.ServerManagementService.GetStatus:input_type -> google.protobuf.Empty
+	0,  // 16: mieru.appctl.ServerManagementService.Start:input_type -> google.protobuf.Empty
+	0,  // 17: mieru.appctl.ServerManagementService.Stop:input_type -> google.protobuf.Empty
+	0,  // 18: mieru.appctl.ServerManagementService.GetConfig:input_type -> google.protobuf.Empty
+	4,  // 19: mieru.appctl.ServerManagementService.SetConfig:input_type -> mieru.appctl.ServerConfig
+	0,  // 20: mieru.appctl.ServerManagementService.Reload:input_type -> google.protobuf.Empty
+	0,  // 21: mieru.appctl.ServerManagementService.Exit:input_type -> google.protobuf.Empty
+	0,  // 22: mieru.appctl.ServerManagementService.GetMetrics:input_type -> google.protobuf.Empty
+	0,  // 23: mieru.appctl.ServerManagementService.GetSessionInfoList:input_type -> google.protobuf.Empty
+	5,  // 24: mieru.appctl.ServerManagementService.KillSession:input_type -> mieru.appctl.KillSessionRequest
+	0,  // 25: mieru.appctl.ServerManagementService.GetUsers:input_type -> google.protobuf.Empty
+	6,  // 26: mieru.appctl.ServerManagementService.AddUser:input_type -> mieru.appctl.User
+	6,  // 27: mieru.appctl.ServerManagementService.UpdateUser:input_type -> mieru.appctl.User
+	7,  // 28: mieru.appctl.ServerManagementService.DeleteUsers:input_type -> mieru.appctl.UserNameList
+	0,  // 29: mieru.appctl.ServerManagementService.GetUserGroups:input_type -> google.protobuf.Empty
+	0,  // 30: mieru.appctl.ServerManagementService.GetCountryTraffic:input_type -> google.protobuf.Empty
+	0,  // 31: mieru.appctl.ServerManagementService.GetPortBindings:input_type -> google.protobuf.Empty
+	0,  // 32: mieru.appctl.ServerManagementService.RotateKeys:input_type -> google.protobuf.Empty
+	0,  // 33: mieru.appctl.ServerManagementService.GetThreadDump:input_type -> google.protobuf.Empty
+	2,  // 34: mieru.appctl.ServerManagementService.StartCPUProfile:input_type -> mieru.appctl.ProfileSavePath
+	0,  // 35: mieru.appctl.ServerManagementService.StopCPUProfile:input_type -> google.protobuf.Empty
+	2,  // 36: mieru.appctl.ServerManagementService.GetHeapProfile:input_type -> mieru.appctl.ProfileSavePath
+	0,  // 37: mieru.appctl.ServerManagementService.GetMemoryStatistics:input_type -> google.protobuf.Empty
+	0,  // 38: mieru.appctl.ServerManagementService.GetVersion:input_type -> google.protobuf.Empty
+	3,  // 39: mieru.appctl.ServerManagementService.FollowLogs:input_type -> mieru.appctl.FollowLogsRequest
+	8,  // 40: mieru.appctl.ClientManagementService.GetStatus:output_type -> mieru.appctl.AppStatusMsg
+	0,  // 41: mieru.appctl.ClientManagementService.Exit:output_type -> google.protobuf.Empty
+	0,  // 42: mieru.appctl.ClientManagementService.Reload:output_type -> google.protobuf.Empty
+	9,  // 43: mieru.appctl.ClientManagementService.GetMetrics:output_type -> mieru.appctl.Metrics
+	10, // 44: mieru.appctl.ClientManagementService.GetSessionInfoList:output_type -> mieru.appctl.SessionInfoList
+	11, // 45: mieru.appctl.ClientManagementService.GetDestinationTraffic:output_type -> mieru.appctl.DestinationTrafficList
+	12, // 46: mieru.appctl.ClientManagementService.GetProxyConnections:output_type -> mieru.appctl.ProxyConnectionList
+	13, // 47: mieru.appctl.ClientManagementService.ProbeServers:output_type -> mieru.appctl.ServerLatencyList
+	14, // 48: mieru.appctl.ClientManagementService.GetThreadDump:output_type -> mieru.appctl.ThreadDump
+	0,  // 49: mieru.appctl.ClientManagementService.StartCPUProfile:output_type -> google.protobuf.Empty
+	0,  // 50: mieru.appctl.ClientManagementService.StopCPUProfile:output_type -> google.protobuf.Empty
+	0,  // 51: mieru.appctl.ClientManagementService.GetHeapProfile:output_type -> google.protobuf.Empty
+	15, // 52: mieru.appctl.ClientManagementService.GetMemoryStatistics:output_type -> mieru.appctl.MemoryStatistics
+	16, // 53: mieru.appctl.ClientManagementService.GetVersion:output_type -> mieru.appctl.Version
+	17, // 54: mieru.appctl.ClientManagementService.FollowLogs:output_type -> mieru.appctl.LogEntry
+	8,  // 55: mieru.appctl.ServerManagementService.GetStatus:output_type -> mieru.appctl.AppStatusMsg
+	0,  // 56: mieru.appctl.ServerManagementService.Start:output_type -> google.protobuf.Empty
+	0,  // 57: mieru.appctl.ServerManagementService.Stop:output_type -> google.protobuf.Empty
+	4,  // 58: mieru.appctl.ServerManagementService.GetConfig:output_type -> mieru.appctl.ServerConfig
+	4,  // 59: mieru.appctl.ServerManagementService.SetConfig:output_type -> mieru.appctl.ServerConfig
+	0,  // 60: mieru.appctl.ServerManagementService.Reload:output_type -> google.protobuf.Empty
+	0,  // 61: mieru.appctl.ServerManagementService.Exit:output_type -> google.protobuf.Empty
+	9,  // 62: mieru.appctl.ServerManagementService.GetMetrics:output_type -> mieru.appctl.Metrics
+	10, // 63: mieru.appctl.ServerManagementService.GetSessionInfoList:output_type -> mieru.appctl.SessionInfoList
+	0,  // 64: mieru.appctl.ServerManagementService.KillSession:output_type -> google.protobuf.Empty
+	18, // 65: mieru.appctl.ServerManagementService.GetUsers:output_type -> mieru.appctl.UserWithMetricsList
+	0,  // 66: mieru.appctl.ServerManagementService.AddUser:output_type -> google.protobuf.Empty
+	0,  // 67: mieru.appctl.ServerManagementService.UpdateUser:output_type -> google.protobuf.Empty
+	0,  // 68: mieru.appctl.ServerManagementService.DeleteUsers:output_type -> google.protobuf.Empty
+	19, // 69: mieru.appctl.ServerManagementService.GetUserGroups:output_type -> mieru.appctl.UserGroupWithMetricsList
+	20, // 70: mieru.appctl.ServerManagementService.GetCountryTraffic:output_type -> mieru.appctl.CountryTrafficList
+	21, // 71: mieru.appctl.ServerManagementService.GetPortBindings:output_type -> mieru.appctl.PortBindingStatusList
+	22, // 72: mieru.appctl.ServerManagementService.RotateKeys:output_type -> mieru.appctl.KeyEpoch
+	14, // 73: mieru.appctl.ServerManagementService.GetThreadDump:output_type -> mieru.appctl.ThreadDump
+	0,  // 74: mieru.appctl.ServerManagementService.StartCPUProfile:output_type -> google.protobuf.Empty
+	0,  // 75: mieru.appctl.ServerManagementService.StopCPUProfile:output_type -> google.protobuf.Empty
+	0,  // 76: mieru.appctl.ServerManagementService.GetHeapProfile:output_type -> google.protobuf.Empty
+	15, // 77: mieru.appctl.ServerManagementService.GetMemoryStatistics:output_type -> mieru.appctl.MemoryStatistics
+	16, // 78: mieru.appctl.ServerManagementService.GetVersion:output_type -> mieru.appctl.Version
+	17, // 79: mieru.appctl.ServerManagementService.FollowLogs:output_type -> mieru.appctl.LogEntry
+	40, // [40:80] is the sub-list for method output_type
+	0,  // [0:40] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -36,16 +36,21 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ClientManagementService_GetStatus_FullMethodName           = "/mieru.appctl.ClientManagementService/GetStatus"
-	ClientManagementService_Exit_FullMethodName                = "/mieru.appctl.ClientManagementService/Exit"
-	ClientManagementService_GetMetrics_FullMethodName          = "/mieru.appctl.ClientManagementService/GetMetrics"
-	ClientManagementService_GetSessionInfoList_FullMethodName  = "/mieru.appctl.ClientManagementService/GetSessionInfoList"
-	ClientManagementService_GetThreadDump_FullMethodName       = "/mieru.appctl.ClientManagementService/GetThreadDump"
-	ClientManagementService_StartCPUProfile_FullMethodName     = "/mieru.appctl.ClientManagementService/StartCPUProfile"
-	ClientManagementService_StopCPUProfile_FullMethodName      = "/mieru.appctl.ClientManagementService/StopCPUProfile"
-	ClientManagementService_GetHeapProfile_FullMethodName      = "/mieru.appctl.ClientManagementService/GetHeapProfile"
-	ClientManagementService_GetMemoryStatistics_FullMethodName = "/mieru.appctl.ClientManagementService/GetMemoryStatistics"
-	ClientManagementService_GetVersion_FullMethodName          = "/mieru.appctl.ClientManagementService/GetVersion"
+	ClientManagementService_GetStatus_FullMethodName             = "/mieru.appctl.ClientManagementService/GetStatus"
+	ClientManagementService_Exit_FullMethodName                  = "/mieru.appctl.ClientManagementService/Exit"
+	ClientManagementService_Reload_FullMethodName                = "/mieru.appctl.ClientManagementService/Reload"
+	ClientManagementService_GetMetrics_FullMethodName            = "/mieru.appctl.ClientManagementService/GetMetrics"
+	ClientManagementService_GetSessionInfoList_FullMethodName    = "/mieru.appctl.ClientManagementService/GetSessionInfoList"
+	ClientManagementService_GetDestinationTraffic_FullMethodName = "/mieru.appctl.ClientManagementService/GetDestinationTraffic"
+	ClientManagementService_GetProxyConnections_FullMethodName   = "/mieru.appctl.ClientManagementService/GetProxyConnections"
+	ClientManagementService_ProbeServers_FullMethodName          = "/mieru.appctl.ClientManagementService/ProbeServers"
+	ClientManagementService_GetThreadDump_FullMethodName         = "/mieru.appctl.ClientManagementService/GetThreadDump"
+	ClientManagementService_StartCPUProfile_FullMethodName       = "/mieru.appctl.ClientManagementService/StartCPUProfile"
+	ClientManagementService_StopCPUProfile_FullMethodName        = "/mieru.appctl.ClientManagementService/StopCPUProfile"
+	ClientManagementService_GetHeapProfile_FullMethodName        = "/mieru.appctl.ClientManagementService/GetHeapProfile"
+	ClientManagementService_GetMemoryStatistics_FullMethodName   = "/mieru.appctl.ClientManagementService/GetMemoryStatistics"
+	ClientManagementService_GetVersion_FullMethodName            = "/mieru.appctl.ClientManagementService/GetVersion"
+	ClientManagementService_FollowLogs_FullMethodName            = "/mieru.appctl.ClientManagementService/FollowLogs"
 )
 
 // ClientManagementServiceClient is the client API for ClientManagementService service.
@@ -56,10 +61,18 @@ type ClientManagementServiceClient interface {
 	GetStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.AppStatusMsg, error)
 	// Quit client daemon.
 	Exit(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Reload client configuration without stopping proxy service.
+	Reload(ctx context.Context, in *appctlpb.ReloadClientRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Get client metrics.
 	GetMetrics(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.Metrics, error)
 	// Get client session information.
 	GetSessionInfoList(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.SessionInfoList, error)
+	// Get client traffic statistics of each destination.
+	GetDestinationTraffic(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.DestinationTrafficList, error)
+	// Get the active proxied connections of client.
+	GetProxyConnections(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.ProxyConnectionList, error)
+	// Measure the latency of each proxy server in the active profile.
+	ProbeServers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.ServerLatencyList, error)
 	// Generate a thread dump of client daemon.
 	GetThreadDump(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.ThreadDump, error)
 	// Start CPU profiling.
@@ -72,6 +85,8 @@ type ClientManagementServiceClient interface {
 	GetMemoryStatistics(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.MemoryStatistics, error)
 	// Get client version.
 	GetVersion(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.Version, error)
+	// Stream live log entries of client daemon.
+	FollowLogs(ctx context.Context, in *appctlpb.FollowLogsRequest, opts ...grpc.CallOption) (ClientManagementService_FollowLogsClient, error)
 }
 
 type clientManagementServiceClient struct {
@@ -100,6 +115,15 @@ func (c *clientManagementServiceClient) Exit(ctx context.Context, in *emptypb.Em
 	return out, nil
 }
 
+func (c *clientManagementServiceClient) Reload(ctx context.Context, in *appctlpb.ReloadClientRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ClientManagementService_Reload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *clientManagementServiceClient) GetMetrics(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.Metrics, error) {
 	out := new(appctlpb.Metrics)
 	err := c.cc.Invoke(ctx, ClientManagementService_GetMetrics_FullMethodName, in, out, opts...)
@@ -118,6 +142,33 @@ func (c *clientManagementServiceClient) GetSessionInfoList(ctx context.Context,
 	return out, nil
 }
 
+func (c *clientManagementServiceClient) GetDestinationTraffic(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.DestinationTrafficList, error) {
+	out := new(appctlpb.DestinationTrafficList)
+	err := c.cc.Invoke(ctx, ClientManagementService_GetDestinationTraffic_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientManagementServiceClient) GetProxyConnections(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.ProxyConnectionList, error) {
+	out := new(appctlpb.ProxyConnectionList)
+	err := c.cc.Invoke(ctx, ClientManagementService_GetProxyConnections_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clientManagementServiceClient) ProbeServers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.ServerLatencyList, error) {
+	out := new(appctlpb.ServerLatencyList)
+	err := c.cc.Invoke(ctx, ClientManagementService_ProbeServers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *clientManagementServiceClient) GetThreadDump(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.ThreadDump, error) {
 	out := new(appctlpb.ThreadDump)
 	err := c.cc.Invoke(ctx, ClientManagementService_GetThreadDump_FullMethodName, in, out, opts...)
@@ -172,6 +223,38 @@ func (c *clientManagementServiceClient) GetVersion(ctx context.Context, in *empt
 	return out, nil
 }
 
+func (c *clientManagementServiceClient) FollowLogs(ctx context.Context, in *appctlpb.FollowLogsRequest, opts ...grpc.CallOption) (ClientManagementService_FollowLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ClientManagementService_ServiceDesc.Streams[0], ClientManagementService_FollowLogs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &clientManagementServiceFollowLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ClientManagementService_FollowLogsClient interface {
+	Recv() (*appctlpb.LogEntry, error)
+	grpc.ClientStream
+}
+
+type clientManagementServiceFollowLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *clientManagementServiceFollowLogsClient) Recv() (*appctlpb.LogEntry, error) {
+	m := new(appctlpb.LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ClientManagementServiceServer is the server API for ClientManagementService service.
 // All implementations must embed UnimplementedClientManagementServiceServer
 // for forward compatibility
@@ -180,10 +263,18 @@ type ClientManagementServiceServer interface {
 	GetStatus(context.Context, *emptypb.Empty) (*appctlpb.AppStatusMsg, error)
 	// Quit client daemon.
 	Exit(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// Reload client configuration without stopping proxy service.
+	Reload(context.Context, *appctlpb.ReloadClientRequest) (*emptypb.Empty, error)
 	// Get client metrics.
 	GetMetrics(context.Context, *emptypb.Empty) (*appctlpb.Metrics, error)
 	// Get client session information.
 	GetSessionInfoList(context.Context, *emptypb.Empty) (*appctlpb.SessionInfoList, error)
+	// Get client traffic statistics of each destination.
+	GetDestinationTraffic(context.Context, *emptypb.Empty) (*appctlpb.DestinationTrafficList, error)
+	// Get the active proxied connections of client.
+	GetProxyConnections(context.Context, *emptypb.Empty) (*appctlpb.ProxyConnectionList, error)
+	// Measure the latency of each proxy server in the active profile.
+	ProbeServers(context.Context, *emptypb.Empty) (*appctlpb.ServerLatencyList, error)
 	// Generate a thread dump of client daemon.
 	GetThreadDump(context.Context, *emptypb.Empty) (*appctlpb.ThreadDump, error)
 	// Start CPU profiling.
@@ -196,6 +287,8 @@ type ClientManagementServiceServer interface {
 	GetMemoryStatistics(context.Context, *emptypb.Empty) (*appctlpb.MemoryStatistics, error)
 	// Get client version.
 	GetVersion(context.Context, *emptypb.Empty) (*appctlpb.Version, error)
+	// Stream live log entries of client daemon.
+	FollowLogs(*appctlpb.FollowLogsRequest, ClientManagementService_FollowLogsServer) error
 	mustEmbedUnimplementedClientManagementServiceServer()
 }
 
@@ -209,12 +302,24 @@ func (UnimplementedClientManagementServiceServer) GetStatus(context.Context, *em
 func (UnimplementedClientManagementServiceServer) Exit(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Exit not implemented")
 }
+func (UnimplementedClientManagementServiceServer) Reload(context.Context, *appctlpb.ReloadClientRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reload not implemented")
+}
 func (UnimplementedClientManagementServiceServer) GetMetrics(context.Context, *emptypb.Empty) (*appctlpb.Metrics, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMetrics not implemented")
 }
 func (UnimplementedClientManagementServiceServer) GetSessionInfoList(context.Context, *emptypb.Empty) (*appctlpb.SessionInfoList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSessionInfoList not implemented")
 }
+func (UnimplementedClientManagementServiceServer) GetDestinationTraffic(context.Context, *emptypb.Empty) (*appctlpb.DestinationTrafficList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDestinationTraffic not implemented")
+}
+func (UnimplementedClientManagementServiceServer) GetProxyConnections(context.Context, *emptypb.Empty) (*appctlpb.ProxyConnectionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProxyConnections not implemented")
+}
+func (UnimplementedClientManagementServiceServer) ProbeServers(context.Context, *emptypb.Empty) (*appctlpb.ServerLatencyList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProbeServers not implemented")
+}
 func (UnimplementedClientManagementServiceServer) GetThreadDump(context.Context, *emptypb.Empty) (*appctlpb.ThreadDump, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetThreadDump not implemented")
 }
@@ -233,6 +338,9 @@ func (UnimplementedClientManagementServiceServer) GetMemoryStatistics(context.Co
 func (UnimplementedClientManagementServiceServer) GetVersion(context.Context, *emptypb.Empty) (*appctlpb.Version, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
 }
+func (UnimplementedClientManagementServiceServer) FollowLogs(*appctlpb.FollowLogsRequest, ClientManagementService_FollowLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method FollowLogs not implemented")
+}
 func (UnimplementedClientManagementServiceServer) mustEmbedUnimplementedClientManagementServiceServer() {
 }
 
@@ -283,6 +391,24 @@ func _ClientManagementService_Exit_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClientManagementService_Reload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(appctlpb.ReloadClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientManagementServiceServer).Reload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientManagementService_Reload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientManagementServiceServer).Reload(ctx, req.(*appctlpb.ReloadClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ClientManagementService_GetMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -319,6 +445,60 @@ func _ClientManagementService_GetSessionInfoList_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClientManagementService_GetDestinationTraffic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientManagementServiceServer).GetDestinationTraffic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientManagementService_GetDestinationTraffic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientManagementServiceServer).GetDestinationTraffic(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientManagementService_GetProxyConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientManagementServiceServer).GetProxyConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientManagementService_GetProxyConnections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientManagementServiceServer).GetProxyConnections(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClientManagementService_ProbeServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClientManagementServiceServer).ProbeServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClientManagementService_ProbeServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClientManagementServiceServer).ProbeServers(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ClientManagementService_GetThreadDump_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -427,6 +607,27 @@ func _ClientManagementService_GetVersion_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClientManagementService_FollowLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(appctlpb.FollowLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClientManagementServiceServer).FollowLogs(m, &clientManagementServiceFollowLogsServer{stream})
+}
+
+type ClientManagementService_FollowLogsServer interface {
+	Send(*appctlpb.LogEntry) error
+	grpc.ServerStream
+}
+
+type clientManagementServiceFollowLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *clientManagementServiceFollowLogsServer) Send(m *appctlpb.LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ClientManagementService_ServiceDesc is the grpc.ServiceDesc for ClientManagementService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -442,6 +643,10 @@ var ClientManagementService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Exit",
 			Handler:    _ClientManagementService_Exit_Handler,
 		},
+		{
+			MethodName: "Reload",
+			Handler:    _ClientManagementService_Reload_Handler,
+		},
 		{
 			MethodName: "GetMetrics",
 			Handler:    _ClientManagementService_GetMetrics_Handler,
@@ -450,6 +655,18 @@ var ClientManagementService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSessionInfoList",
 			Handler:    _ClientManagementService_GetSessionInfoList_Handler,
 		},
+		{
+			MethodName: "GetDestinationTraffic",
+			Handler:    _ClientManagementService_GetDestinationTraffic_Handler,
+		},
+		{
+			MethodName: "GetProxyConnections",
+			Handler:    _ClientManagementService_GetProxyConnections_Handler,
+		},
+		{
+			MethodName: "ProbeServers",
+			Handler:    _ClientManagementService_ProbeServers_Handler,
+		},
 		{
 			MethodName: "GetThreadDump",
 			Handler:    _ClientManagementService_GetThreadDump_Handler,
@@ -475,7 +692,13 @@ var ClientManagementService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ClientManagementService_GetVersion_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FollowLogs",
+			Handler:       _ClientManagementService_FollowLogs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "appctl/proto/rpc.proto",
 }
 
@@ -489,13 +712,22 @@ const (
 	ServerManagementService_Exit_FullMethodName                = "/mieru.appctl.ServerManagementService/Exit"
 	ServerManagementService_GetMetrics_FullMethodName          = "/mieru.appctl.ServerManagementService/GetMetrics"
 	ServerManagementService_GetSessionInfoList_FullMethodName  = "/mieru.appctl.ServerManagementService/GetSessionInfoList"
+	ServerManagementService_KillSession_FullMethodName         = "/mieru.appctl.ServerManagementService/KillSession"
 	ServerManagementService_GetUsers_FullMethodName            = "/mieru.appctl.ServerManagementService/GetUsers"
+	ServerManagementService_AddUser_FullMethodName             = "/mieru.appctl.ServerManagementService/AddUser"
+	ServerManagementService_UpdateUser_FullMethodName          = "/mieru.appctl.ServerManagementService/UpdateUser"
+	ServerManagementService_DeleteUsers_FullMethodName         = "/mieru.appctl.ServerManagementService/DeleteUsers"
+	ServerManagementService_GetUserGroups_FullMethodName       = "/mieru.appctl.ServerManagementService/GetUserGroups"
+	ServerManagementService_GetCountryTraffic_FullMethodName   = "/mieru.appctl.ServerManagementService/GetCountryTraffic"
+	ServerManagementService_GetPortBindings_FullMethodName     = "/mieru.appctl.ServerManagementService/GetPortBindings"
+	ServerManagementService_RotateKeys_FullMethodName          = "/mieru.appctl.ServerManagementService/RotateKeys"
 	ServerManagementService_GetThreadDump_FullMethodName       = "/mieru.appctl.ServerManagementService/GetThreadDump"
 	ServerManagementService_StartCPUProfile_FullMethodName     = "/mieru.appctl.ServerManagementService/StartCPUProfile"
 	ServerManagementService_StopCPUProfile_FullMethodName      = "/mieru.appctl.ServerManagementService/StopCPUProfile"
 	ServerManagementService_GetHeapProfile_FullMethodName      = "/mieru.appctl.ServerManagementService/GetHeapProfile"
 	ServerManagementService_GetMemoryStatistics_FullMethodName = "/mieru.appctl.ServerManagementService/GetMemoryStatistics"
 	ServerManagementService_GetVersion_FullMethodName          = "/mieru.appctl.ServerManagementService/GetVersion"
+	ServerManagementService_FollowLogs_FullMethodName          = "/mieru.appctl.ServerManagementService/FollowLogs"
 )
 
 // ServerManagementServiceClient is the client API for ServerManagementService service.
@@ -520,8 +752,26 @@ type ServerManagementServiceClient interface {
 	GetMetrics(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.Metrics, error)
 	// Get server session information.
 	GetSessionInfoList(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.SessionInfoList, error)
+	// Close a server session.
+	KillSession(ctx context.Context, in *appctlpb.KillSessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Get users setting and runtime information.
 	GetUsers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.UserWithMetricsList, error)
+	// Add a user to the running server and the server config.
+	// The user must not exist.
+	AddUser(ctx context.Context, in *appctlpb.User, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Replace the settings of an existing user in the running server
+	// and the server config.
+	UpdateUser(ctx context.Context, in *appctlpb.User, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Delete users from the running server and the server config.
+	DeleteUsers(ctx context.Context, in *appctlpb.UserNameList, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Get user groups setting and runtime information.
+	GetUserGroups(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.UserGroupWithMetricsList, error)
+	// Get server egress traffic statistics of each destination country.
+	GetCountryTraffic(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.CountryTrafficList, error)
+	// Get the status of server port bindings.
+	GetPortBindings(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.PortBindingStatusList, error)
+	// Stop accepting keys of the previous key epochs immediately.
+	RotateKeys(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.KeyEpoch, error)
 	// Generate a thread dump of server daemon.
 	GetThreadDump(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.ThreadDump, error)
 	// Start CPU profiling.
@@ -534,6 +784,8 @@ type ServerManagementServiceClient interface {
 	GetMemoryStatistics(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.MemoryStatistics, error)
 	// Get server version.
 	GetVersion(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.Version, error)
+	// Stream live log entries of server daemon.
+	FollowLogs(ctx context.Context, in *appctlpb.FollowLogsRequest, opts ...grpc.CallOption) (ServerManagementService_FollowLogsClient, error)
 }
 
 type serverManagementServiceClient struct {
@@ -625,6 +877,15 @@ func (c *serverManagementServiceClient) GetSessionInfoList(ctx context.Context,
 	return out, nil
 }
 
+func (c *serverManagementServiceClient) KillSession(ctx context.Context, in *appctlpb.KillSessionRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ServerManagementService_KillSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *serverManagementServiceClient) GetUsers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.UserWithMetricsList, error) {
 	out := new(appctlpb.UserWithMetricsList)
 	err := c.cc.Invoke(ctx, ServerManagementService_GetUsers_FullMethodName, in, out, opts...)
@@ -634,6 +895,69 @@ func (c *serverManagementServiceClient) GetUsers(ctx context.Context, in *emptyp
 	return out, nil
 }
 
+func (c *serverManagementServiceClient) AddUser(ctx context.Context, in *appctlpb.User, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ServerManagementService_AddUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverManagementServiceClient) UpdateUser(ctx context.Context, in *appctlpb.User, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ServerManagementService_UpdateUser_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverManagementServiceClient) DeleteUsers(ctx context.Context, in *appctlpb.UserNameList, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ServerManagementService_DeleteUsers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverManagementServiceClient) GetUserGroups(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.UserGroupWithMetricsList, error) {
+	out := new(appctlpb.UserGroupWithMetricsList)
+	err := c.cc.Invoke(ctx, ServerManagementService_GetUserGroups_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverManagementServiceClient) GetCountryTraffic(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.CountryTrafficList, error) {
+	out := new(appctlpb.CountryTrafficList)
+	err := c.cc.Invoke(ctx, ServerManagementService_GetCountryTraffic_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverManagementServiceClient) GetPortBindings(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.PortBindingStatusList, error) {
+	out := new(appctlpb.PortBindingStatusList)
+	err := c.cc.Invoke(ctx, ServerManagementService_GetPortBindings_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverManagementServiceClient) RotateKeys(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.KeyEpoch, error) {
+	out := new(appctlpb.KeyEpoch)
+	err := c.cc.Invoke(ctx, ServerManagementService_RotateKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *serverManagementServiceClient) GetThreadDump(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*appctlpb.ThreadDump, error) {
 	out := new(appctlpb.ThreadDump)
 	err := c.cc.Invoke(ctx, ServerManagementService_GetThreadDump_FullMethodName, in, out, opts...)
@@ -688,6 +1012,38 @@ func (c *serverManagementServiceClient) GetVersion(ctx context.Context, in *empt
 	return out, nil
 }
 
+func (c *serverManagementServiceClient) FollowLogs(ctx context.Context, in *appctlpb.FollowLogsRequest, opts ...grpc.CallOption) (ServerManagementService_FollowLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ServerManagementService_ServiceDesc.Streams[0], ServerManagementService_FollowLogs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &serverManagementServiceFollowLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ServerManagementService_FollowLogsClient interface {
+	Recv() (*appctlpb.LogEntry, error)
+	grpc.ClientStream
+}
+
+type serverManagementServiceFollowLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *serverManagementServiceFollowLogsClient) Recv() (*appctlpb.LogEntry, error) {
+	m := new(appctlpb.LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ServerManagementServiceServer is the server API for ServerManagementService service.
 // All implementations must embed UnimplementedServerManagementServiceServer
 // for forward compatibility
@@ -710,8 +1066,26 @@ type ServerManagementServiceServer interface {
 	GetMetrics(context.Context, *emptypb.Empty) (*appctlpb.Metrics, error)
 	// Get server session information.
 	GetSessionInfoList(context.Context, *emptypb.Empty) (*appctlpb.SessionInfoList, error)
+	// Close a server session.
+	KillSession(context.Context, *appctlpb.KillSessionRequest) (*emptypb.Empty, error)
 	// Get users setting and runtime information.
 	GetUsers(context.Context, *emptypb.Empty) (*appctlpb.UserWithMetricsList, error)
+	// Add a user to the running server and the server config.
+	// The user must not exist.
+	AddUser(context.Context, *appctlpb.User) (*emptypb.Empty, error)
+	// Replace the settings of an existing user in the running server
+	// and the server config.
+	UpdateUser(context.Context, *appctlpb.User) (*emptypb.Empty, error)
+	// Delete users from the running server and the server config.
+	DeleteUsers(context.Context, *appctlpb.UserNameList) (*emptypb.Empty, error)
+	// Get user groups setting and runtime information.
+	GetUserGroups(context.Context, *emptypb.Empty) (*appctlpb.UserGroupWithMetricsList, error)
+	// Get server egress traffic statistics of each destination country.
+	GetCountryTraffic(context.Context, *emptypb.Empty) (*appctlpb.CountryTrafficList, error)
+	// Get the status of server port bindings.
+	GetPortBindings(context.Context, *emptypb.Empty) (*appctlpb.PortBindingStatusList, error)
+	// Stop accepting keys of the previous key epochs immediately.
+	RotateKeys(context.Context, *emptypb.Empty) (*appctlpb.KeyEpoch, error)
 	// Generate a thread dump of server daemon.
 	GetThreadDump(context.Context, *emptypb.Empty) (*appctlpb.ThreadDump, error)
 	// Start CPU profiling.
@@ -724,6 +1098,8 @@ type ServerManagementServiceServer interface {
 	GetMemoryStatistics(context.Context, *emptypb.Empty) (*appctlpb.MemoryStatistics, error)
 	// Get server version.
 	GetVersion(context.Context, *emptypb.Empty) (*appctlpb.Version, error)
+	// Stream live log entries of server daemon.
+	FollowLogs(*appctlpb.FollowLogsRequest, ServerManagementService_FollowLogsServer) error
 	mustEmbedUnimplementedServerManagementServiceServer()
 }
 
@@ -758,9 +1134,33 @@ func (UnimplementedServerManagementServiceServer) GetMetrics(context.Context, *e
 func (UnimplementedServerManagementServiceServer) GetSessionInfoList(context.Context, *emptypb.Empty) (*appctlpb.SessionInfoList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSessionInfoList not implemented")
 }
+func (UnimplementedServerManagementServiceServer) KillSession(context.Context, *appctlpb.KillSessionRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KillSession not implemented")
+}
 func (UnimplementedServerManagementServiceServer) GetUsers(context.Context, *emptypb.Empty) (*appctlpb.UserWithMetricsList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUsers not implemented")
 }
+func (UnimplementedServerManagementServiceServer) AddUser(context.Context, *appctlpb.User) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddUser not implemented")
+}
+func (UnimplementedServerManagementServiceServer) UpdateUser(context.Context, *appctlpb.User) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedServerManagementServiceServer) DeleteUsers(context.Context, *appctlpb.UserNameList) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUsers not implemented")
+}
+func (UnimplementedServerManagementServiceServer) GetUserGroups(context.Context, *emptypb.Empty) (*appctlpb.UserGroupWithMetricsList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserGroups not implemented")
+}
+func (UnimplementedServerManagementServiceServer) GetCountryTraffic(context.Context, *emptypb.Empty) (*appctlpb.CountryTrafficList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCountryTraffic not implemented")
+}
+func (UnimplementedServerManagementServiceServer) GetPortBindings(context.Context, *emptypb.Empty) (*appctlpb.PortBindingStatusList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPortBindings not implemented")
+}
+func (UnimplementedServerManagementServiceServer) RotateKeys(context.Context, *emptypb.Empty) (*appctlpb.KeyEpoch, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateKeys not implemented")
+}
 func (UnimplementedServerManagementServiceServer) GetThreadDump(context.Context, *emptypb.Empty) (*appctlpb.ThreadDump, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetThreadDump not implemented")
 }
@@ -779,6 +1179,9 @@ func (UnimplementedServerManagementServiceServer) GetMemoryStatistics(context.Co
 func (UnimplementedServerManagementServiceServer) GetVersion(context.Context, *emptypb.Empty) (*appctlpb.Version, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
 }
+func (UnimplementedServerManagementServiceServer) FollowLogs(*appctlpb.FollowLogsRequest, ServerManagementService_FollowLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method FollowLogs not implemented")
+}
 func (UnimplementedServerManagementServiceServer) mustEmbedUnimplementedServerManagementServiceServer() {
 }
 
@@ -955,6 +1358,24 @@ func _ServerManagementService_GetSessionInfoList_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ServerManagementService_KillSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(appctlpb.KillSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerManagementServiceServer).KillSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerManagementService_KillSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerManagementServiceServer).KillSession(ctx, req.(*appctlpb.KillSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ServerManagementService_GetUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -973,6 +1394,132 @@ func _ServerManagementService_GetUsers_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ServerManagementService_AddUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(appctlpb.User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerManagementServiceServer).AddUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerManagementService_AddUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerManagementServiceServer).AddUser(ctx, req.(*appctlpb.User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerManagementService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(appctlpb.User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerManagementServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerManagementService_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerManagementServiceServer).UpdateUser(ctx, req.(*appctlpb.User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerManagementService_DeleteUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(appctlpb.UserNameList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerManagementServiceServer).DeleteUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerManagementService_DeleteUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerManagementServiceServer).DeleteUsers(ctx, req.(*appctlpb.UserNameList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerManagementService_GetUserGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerManagementServiceServer).GetUserGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerManagementService_GetUserGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerManagementServiceServer).GetUserGroups(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerManagementService_GetCountryTraffic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerManagementServiceServer).GetCountryTraffic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerManagementService_GetCountryTraffic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerManagementServiceServer).GetCountryTraffic(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerManagementService_GetPortBindings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerManagementServiceServer).GetPortBindings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerManagementService_GetPortBindings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerManagementServiceServer).GetPortBindings(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerManagementService_RotateKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerManagementServiceServer).RotateKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerManagementService_RotateKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerManagementServiceServer).RotateKeys(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ServerManagementService_GetThreadDump_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -1081,6 +1628,27 @@ func _ServerManagementService_GetVersion_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ServerManagementService_FollowLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(appctlpb.FollowLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ServerManagementServiceServer).FollowLogs(m, &serverManagementServiceFollowLogsServer{stream})
+}
+
+type ServerManagementService_FollowLogsServer interface {
+	Send(*appctlpb.LogEntry) error
+	grpc.ServerStream
+}
+
+type serverManagementServiceFollowLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *serverManagementServiceFollowLogsServer) Send(m *appctlpb.LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ServerManagementService_ServiceDesc is the grpc.ServiceDesc for ServerManagementService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1124,10 +1692,42 @@ var ServerManagementService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSessionInfoList",
 			Handler:    _ServerManagementService_GetSessionInfoList_Handler,
 		},
+		{
+			MethodName: "KillSession",
+			Handler:    _ServerManagementService_KillSession_Handler,
+		},
 		{
 			MethodName: "GetUsers",
 			Handler:    _ServerManagementService_GetUsers_Handler,
 		},
+		{
+			MethodName: "AddUser",
+			Handler:    _ServerManagementService_AddUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _ServerManagementService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUsers",
+			Handler:    _ServerManagementService_DeleteUsers_Handler,
+		},
+		{
+			MethodName: "GetUserGroups",
+			Handler:    _ServerManagementService_GetUserGroups_Handler,
+		},
+		{
+			MethodName: "GetCountryTraffic",
+			Handler:    _ServerManagementService_GetCountryTraffic_Handler,
+		},
+		{
+			MethodName: "GetPortBindings",
+			Handler:    _ServerManagementService_GetPortBindings_Handler,
+		},
+		{
+			MethodName: "RotateKeys",
+			Handler:    _ServerManagementService_RotateKeys_Handler,
+		},
 		{
 			MethodName: "GetThreadDump",
 			Handler:    _ServerManagementService_GetThreadDump_Handler,
@@ -1153,6 +1753,12 @@ var ServerManagementService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ServerManagementService_GetVersion_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FollowLogs",
+			Handler:       _ServerManagementService_FollowLogs_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "appctl/proto/rpc.proto",
 }
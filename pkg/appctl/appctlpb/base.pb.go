@@ -155,6 +155,54 @@ func (LoggingLevel) EnumDescriptor() ([]byte, []int) {
 	return file_appctl_proto_base_proto_rawDescGZIP(), []int{1}
 }
 
+type LogFormat int32
+
+const (
+	// Human readable text.
+	LogFormat_LOG_FORMAT_TEXT LogFormat = 0
+	// A JSON object in each line.
+	LogFormat_LOG_FORMAT_JSON LogFormat = 1
+)
+
+// Enum value maps for LogFormat.
+var (
+	LogFormat_name = map[int32]string{
+		0: "LOG_FORMAT_TEXT",
+		1: "LOG_FORMAT_JSON",
+	}
+	LogFormat_value = map[string]int32{
+		"LOG_FORMAT_TEXT": 0,
+		"LOG_FORMAT_JSON": 1,
+	}
+)
+
+func (x LogFormat) Enum() *LogFormat {
+	p := new(LogFormat)
+	*p = x
+	return p
+}
+
+func (x LogFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LogFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_base_proto_enumTypes[2].Descriptor()
+}
+
+func (LogFormat) Type() protoreflect.EnumType {
+	return &file_appctl_proto_base_proto_enumTypes[2]
+}
+
+func (x LogFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LogFormat.Descriptor instead.
+func (LogFormat) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{2}
+}
+
 type DualStack int32
 
 const (
@@ -194,11 +242,11 @@ func (x DualStack) String() string {
 }
 
 func (DualStack) Descriptor() protoreflect.EnumDescriptor {
-	return file_appctl_proto_base_proto_enumTypes[2].Descriptor()
+	return file_appctl_proto_base_proto_enumTypes[3].Descriptor()
 }
 
 func (DualStack) Type() protoreflect.EnumType {
-	return &file_appctl_proto_base_proto_enumTypes[2]
+	return &file_appctl_proto_base_proto_enumTypes[3]
 }
 
 func (x DualStack) Number() protoreflect.EnumNumber {
@@ -207,7 +255,7 @@ func (x DualStack) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use DualStack.Descriptor instead.
 func (DualStack) EnumDescriptor() ([]byte, []int) {
-	return file_appctl_proto_base_proto_rawDescGZIP(), []int{2}
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{3}
 }
 
 type TransportProtocol int32
@@ -216,6 +264,13 @@ const (
 	TransportProtocol_UNKNOWN_TRANSPORT_PROTOCOL TransportProtocol = 0
 	TransportProtocol_UDP                        TransportProtocol = 1
 	TransportProtocol_TCP                        TransportProtocol = 2
+	// The mieru protocol is tunneled inside WebSocket, optionally over TLS.
+	// This allows the server to hide behind a CDN.
+	TransportProtocol_WEBSOCKET TransportProtocol = 4
+	// Only used by proxy client. The client connects to the port
+	// with both TCP and UDP, and uses the one with lower latency.
+	// The proxy server must listen to the port with both protocols.
+	TransportProtocol_AUTO TransportProtocol = 5
 )
 
 // Enum value maps for TransportProtocol.
@@ -224,11 +279,15 @@ var (
 		0: "UNKNOWN_TRANSPORT_PROTOCOL",
 		1: "UDP",
 		2: "TCP",
+		4: "WEBSOCKET",
+		5: "AUTO",
 	}
 	TransportProtocol_value = map[string]int32{
 		"UNKNOWN_TRANSPORT_PROTOCOL": 0,
 		"UDP":                        1,
 		"TCP":                        2,
+		"WEBSOCKET":                  4,
+		"AUTO":                       5,
 	}
 )
 
@@ -243,11 +302,11 @@ func (x TransportProtocol) String() string {
 }
 
 func (TransportProtocol) Descriptor() protoreflect.EnumDescriptor {
-	return file_appctl_proto_base_proto_enumTypes[3].Descriptor()
+	return file_appctl_proto_base_proto_enumTypes[4].Descriptor()
 }
 
 func (TransportProtocol) Type() protoreflect.EnumType {
-	return &file_appctl_proto_base_proto_enumTypes[3]
+	return &file_appctl_proto_base_proto_enumTypes[4]
 }
 
 func (x TransportProtocol) Number() protoreflect.EnumNumber {
@@ -256,7 +315,182 @@ func (x TransportProtocol) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use TransportProtocol.Descriptor instead.
 func (TransportProtocol) EnumDescriptor() ([]byte, []int) {
-	return file_appctl_proto_base_proto_rawDescGZIP(), []int{3}
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{4}
+}
+
+type DestinationAction int32
+
+const (
+	DestinationAction_DEFAULT_DESTINATION_ACTION DestinationAction = 0
+	// Allow the user to connect to the destination.
+	DestinationAction_ALLOW DestinationAction = 1
+	// Do not allow the user to connect to the destination.
+	DestinationAction_DENY DestinationAction = 2
+)
+
+// Enum value maps for DestinationAction.
+var (
+	DestinationAction_name = map[int32]string{
+		0: "DEFAULT_DESTINATION_ACTION",
+		1: "ALLOW",
+		2: "DENY",
+	}
+	DestinationAction_value = map[string]int32{
+		"DEFAULT_DESTINATION_ACTION": 0,
+		"ALLOW":                      1,
+		"DENY":                       2,
+	}
+)
+
+func (x DestinationAction) Enum() *DestinationAction {
+	p := new(DestinationAction)
+	*p = x
+	return p
+}
+
+func (x DestinationAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DestinationAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_base_proto_enumTypes[5].Descriptor()
+}
+
+func (DestinationAction) Type() protoreflect.EnumType {
+	return &file_appctl_proto_base_proto_enumTypes[5]
+}
+
+func (x DestinationAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DestinationAction.Descriptor instead.
+func (DestinationAction) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{5}
+}
+
+type CipherSuite int32
+
+const (
+	CipherSuite_DEFAULT_CIPHER_SUITE CipherSuite = 0
+	// Fast on all devices. This is the default value.
+	CipherSuite_XCHACHA20_POLY1305 CipherSuite = 1
+	// Faster on CPUs with AES instructions, such as AES-NI.
+	CipherSuite_AES_128_GCM CipherSuite = 2
+	CipherSuite_AES_256_GCM CipherSuite = 3
+	// Fastest on CPUs without AES instructions, such as low-end
+	// ARM routers, because it doesn't derive a subkey for each message.
+	CipherSuite_CHACHA20_POLY1305 CipherSuite = 4
+	// Proxy client picks the fastest algorithm on the device.
+	// Proxy server accepts all the algorithms, which uses more CPU
+	// to authenticate new connections of this user.
+	CipherSuite_AUTO_CIPHER_SUITE CipherSuite = 5
+)
+
+// Enum value maps for CipherSuite.
+var (
+	CipherSuite_name = map[int32]string{
+		0: "DEFAULT_CIPHER_SUITE",
+		1: "XCHACHA20_POLY1305",
+		2: "AES_128_GCM",
+		3: "AES_256_GCM",
+		4: "CHACHA20_POLY1305",
+		5: "AUTO_CIPHER_SUITE",
+	}
+	CipherSuite_value = map[string]int32{
+		"DEFAULT_CIPHER_SUITE": 0,
+		"XCHACHA20_POLY1305":   1,
+		"AES_128_GCM":          2,
+		"AES_256_GCM":          3,
+		"CHACHA20_POLY1305":    4,
+		"AUTO_CIPHER_SUITE":    5,
+	}
+)
+
+func (x CipherSuite) Enum() *CipherSuite {
+	p := new(CipherSuite)
+	*p = x
+	return p
+}
+
+func (x CipherSuite) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CipherSuite) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_base_proto_enumTypes[6].Descriptor()
+}
+
+func (CipherSuite) Type() protoreflect.EnumType {
+	return &file_appctl_proto_base_proto_enumTypes[6]
+}
+
+func (x CipherSuite) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CipherSuite.Descriptor instead.
+func (CipherSuite) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{6}
+}
+
+type ErrorCode int32
+
+const (
+	ErrorCode_UNKNOWN_ERROR_CODE ErrorCode = 0
+	// The configuration is missing or invalid.
+	ErrorCode_INVALID_CONFIG ErrorCode = 1
+	// The proxy service is not running, or it is starting or stopping.
+	ErrorCode_SERVICE_UNAVAILABLE ErrorCode = 2
+	// The request requires a feature that is not enabled in the configuration.
+	ErrorCode_FEATURE_NOT_ENABLED ErrorCode = 3
+	// An unexpected error happened inside the application.
+	ErrorCode_INTERNAL_ERROR ErrorCode = 4
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0: "UNKNOWN_ERROR_CODE",
+		1: "INVALID_CONFIG",
+		2: "SERVICE_UNAVAILABLE",
+		3: "FEATURE_NOT_ENABLED",
+		4: "INTERNAL_ERROR",
+	}
+	ErrorCode_value = map[string]int32{
+		"UNKNOWN_ERROR_CODE":  0,
+		"INVALID_CONFIG":      1,
+		"SERVICE_UNAVAILABLE": 2,
+		"FEATURE_NOT_ENABLED": 3,
+		"INTERNAL_ERROR":      4,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_base_proto_enumTypes[7].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_appctl_proto_base_proto_enumTypes[7]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{7}
 }
 
 type AppStatusMsg struct {
@@ -265,6 +499,19 @@ type AppStatusMsg struct {
 	unknownFields protoimpl.UnknownFields
 
 	Status *AppStatus `protobuf:"varint,1,opt,name=status,proto3,enum=mieru.appctl.AppStatus,oneof" json:"status,omitempty"`
+	// Time in UNIX second when the proxy is started.
+	StartTimeUnix *int64 `protobuf:"varint,2,opt,name=startTimeUnix,proto3,oneof" json:"startTimeUnix,omitempty"`
+	// Name of the client profile in use.
+	Profile *string `protobuf:"bytes,3,opt,name=profile,proto3,oneof" json:"profile,omitempty"`
+	// Network connections to proxy servers.
+	Servers []*ServerConnectionStatus `protobuf:"bytes,4,rep,name=servers,proto3" json:"servers,omitempty"`
+	// The last error to establish a proxy tunnel.
+	LastError *string `protobuf:"bytes,5,opt,name=lastError,proto3,oneof" json:"lastError,omitempty"`
+	// Time in UNIX second when the last error happened.
+	LastErrorTimeUnix *int64 `protobuf:"varint,6,opt,name=lastErrorTimeUnix,proto3,oneof" json:"lastErrorTimeUnix,omitempty"`
+	// Recent download and upload speed.
+	DownloadBytesPerSecond *int64 `protobuf:"varint,7,opt,name=downloadBytesPerSecond,proto3,oneof" json:"downloadBytesPerSecond,omitempty"`
+	UploadBytesPerSecond   *int64 `protobuf:"varint,8,opt,name=uploadBytesPerSecond,proto3,oneof" json:"uploadBytesPerSecond,omitempty"`
 }
 
 func (x *AppStatusMsg) Reset() {
@@ -306,6 +553,121 @@ func (x *AppStatusMsg) GetStatus() AppStatus {
 	return AppStatus_UNKNOWN
 }
 
+func (x *AppStatusMsg) GetStartTimeUnix() int64 {
+	if x != nil && x.StartTimeUnix != nil {
+		return *x.StartTimeUnix
+	}
+	return 0
+}
+
+func (x *AppStatusMsg) GetProfile() string {
+	if x != nil && x.Profile != nil {
+		return *x.Profile
+	}
+	return ""
+}
+
+func (x *AppStatusMsg) GetServers() []*ServerConnectionStatus {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *AppStatusMsg) GetLastError() string {
+	if x != nil && x.LastError != nil {
+		return *x.LastError
+	}
+	return ""
+}
+
+func (x *AppStatusMsg) GetLastErrorTimeUnix() int64 {
+	if x != nil && x.LastErrorTimeUnix != nil {
+		return *x.LastErrorTimeUnix
+	}
+	return 0
+}
+
+func (x *AppStatusMsg) GetDownloadBytesPerSecond() int64 {
+	if x != nil && x.DownloadBytesPerSecond != nil {
+		return *x.DownloadBytesPerSecond
+	}
+	return 0
+}
+
+func (x *AppStatusMsg) GetUploadBytesPerSecond() int64 {
+	if x != nil && x.UploadBytesPerSecond != nil {
+		return *x.UploadBytesPerSecond
+	}
+	return 0
+}
+
+type ServerConnectionStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Transport protocol, e.g. TCP.
+	Protocol *string `protobuf:"bytes,1,opt,name=protocol,proto3,oneof" json:"protocol,omitempty"`
+	// Address of the proxy server.
+	RemoteAddr *string `protobuf:"bytes,2,opt,name=remoteAddr,proto3,oneof" json:"remoteAddr,omitempty"`
+	// Number of sessions carried by the connections to this address.
+	Sessions *int32 `protobuf:"varint,3,opt,name=sessions,proto3,oneof" json:"sessions,omitempty"`
+}
+
+func (x *ServerConnectionStatus) Reset() {
+	*x = ServerConnectionStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerConnectionStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerConnectionStatus) ProtoMessage() {}
+
+func (x *ServerConnectionStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerConnectionStatus.ProtoReflect.Descriptor instead.
+func (*ServerConnectionStatus) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ServerConnectionStatus) GetProtocol() string {
+	if x != nil && x.Protocol != nil {
+		return *x.Protocol
+	}
+	return ""
+}
+
+func (x *ServerConnectionStatus) GetRemoteAddr() string {
+	if x != nil && x.RemoteAddr != nil {
+		return *x.RemoteAddr
+	}
+	return ""
+}
+
+func (x *ServerConnectionStatus) GetSessions() int32 {
+	if x != nil && x.Sessions != nil {
+		return *x.Sessions
+	}
+	return 0
+}
+
 type ServerEndpoint struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -319,12 +681,22 @@ type ServerEndpoint struct {
 	DomainName *string `protobuf:"bytes,2,opt,name=domainName,proto3,oneof" json:"domainName,omitempty"`
 	// Server's port-protocol bindings.
 	PortBindings []*PortBinding `protobuf:"bytes,3,rep,name=portBindings,proto3" json:"portBindings,omitempty"`
+	// IP addresses of the server to use if the domain name can't be resolved.
+	// This field is only used when `domainName` is set.
+	PinnedIpAddresses []string `protobuf:"bytes,4,rep,name=pinnedIpAddresses,proto3" json:"pinnedIpAddresses,omitempty"`
+	// Options of port bindings using the WEBSOCKET protocol.
+	WebSocket *WebSocketConfig `protobuf:"bytes,5,opt,name=webSocket,proto3,oneof" json:"webSocket,omitempty"`
+	// Wrap TCP port bindings in a TLS layer.
+	TlsCamouflage *TLSCamouflageConfig `protobuf:"bytes,6,opt,name=tlsCamouflage,proto3,oneof" json:"tlsCamouflage,omitempty"`
+	// Send the port knocking sequence before connecting to the server.
+	// It must be the same as the setting of the proxy server.
+	PortKnocking *PortKnockingConfig `protobuf:"bytes,7,opt,name=portKnocking,proto3,oneof" json:"portKnocking,omitempty"`
 }
 
 func (x *ServerEndpoint) Reset() {
 	*x = ServerEndpoint{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_base_proto_msgTypes[1]
+		mi := &file_appctl_proto_base_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -337,7 +709,7 @@ func (x *ServerEndpoint) String() string {
 func (*ServerEndpoint) ProtoMessage() {}
 
 func (x *ServerEndpoint) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_base_proto_msgTypes[1]
+	mi := &file_appctl_proto_base_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -350,7 +722,7 @@ func (x *ServerEndpoint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerEndpoint.ProtoReflect.Descriptor instead.
 func (*ServerEndpoint) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_base_proto_rawDescGZIP(), []int{1}
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ServerEndpoint) GetIpAddress() string {
@@ -374,6 +746,34 @@ func (x *ServerEndpoint) GetPortBindings() []*PortBinding {
 	return nil
 }
 
+func (x *ServerEndpoint) GetPinnedIpAddresses() []string {
+	if x != nil {
+		return x.PinnedIpAddresses
+	}
+	return nil
+}
+
+func (x *ServerEndpoint) GetWebSocket() *WebSocketConfig {
+	if x != nil {
+		return x.WebSocket
+	}
+	return nil
+}
+
+func (x *ServerEndpoint) GetTlsCamouflage() *TLSCamouflageConfig {
+	if x != nil {
+		return x.TlsCamouflage
+	}
+	return nil
+}
+
+func (x *ServerEndpoint) GetPortKnocking() *PortKnockingConfig {
+	if x != nil {
+		return x.PortKnocking
+	}
+	return nil
+}
+
 type PortBinding struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -387,12 +787,19 @@ type PortBinding struct {
 	// For example, "8000-9000" contains 1001 ports from 8000 to 9000.
 	// This field can't be set with port at the same time.
 	PortRange *string `protobuf:"bytes,3,opt,name=portRange,proto3,oneof" json:"portRange,omitempty"`
+	// If set, proxy client uses one port of the UDP port range at a time,
+	// and the port changes at this interval. The port is derived from the
+	// user key and the time, so proxy client and proxy server hop ports
+	// in sync. It must be the same on proxy client and proxy server.
+	// This field requires portRange and the UDP protocol.
+	// Examples: 30s, 5m.
+	PortHoppingInterval *string `protobuf:"bytes,4,opt,name=portHoppingInterval,proto3,oneof" json:"portHoppingInterval,omitempty"`
 }
 
 func (x *PortBinding) Reset() {
 	*x = PortBinding{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_base_proto_msgTypes[2]
+		mi := &file_appctl_proto_base_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -405,7 +812,7 @@ func (x *PortBinding) String() string {
 func (*PortBinding) ProtoMessage() {}
 
 func (x *PortBinding) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_base_proto_msgTypes[2]
+	mi := &file_appctl_proto_base_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -418,7 +825,7 @@ func (x *PortBinding) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PortBinding.ProtoReflect.Descriptor instead.
 func (*PortBinding) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_base_proto_rawDescGZIP(), []int{2}
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PortBinding) GetPort() int32 {
@@ -442,48 +849,54 @@ func (x *PortBinding) GetPortRange() string {
 	return ""
 }
 
-type User struct {
+func (x *PortBinding) GetPortHoppingInterval() string {
+	if x != nil && x.PortHoppingInterval != nil {
+		return *x.PortHoppingInterval
+	}
+	return ""
+}
+
+type WebSocketConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// User name is also the ID of user.
-	// Typically this is an email address.
-	Name *string `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
-	// Raw password.
-	// For safety this shouldn't be persisted at the server side.
-	Password *string `protobuf:"bytes,2,opt,name=password,proto3,oneof" json:"password,omitempty"`
-	// Hashed password.
-	// Stored with hex encoding.
-	HashedPassword *string `protobuf:"bytes,3,opt,name=hashedPassword,proto3,oneof" json:"hashedPassword,omitempty"`
-	// User quotas.
-	// This field has no effect at the client side.
-	Quotas []*Quota `protobuf:"bytes,4,rep,name=quotas,proto3" json:"quotas,omitempty"`
-	// Allow user to use private IP as the destination.
-	// This field has no effect at the client side.
-	AllowPrivateIP *bool `protobuf:"varint,5,opt,name=allowPrivateIP,proto3,oneof" json:"allowPrivateIP,omitempty"`
-	// Allow user to use loopback IP as the destination.
-	// This field has no effect at the client side.
-	AllowLoopbackIP *bool `protobuf:"varint,6,opt,name=allowLoopbackIP,proto3,oneof" json:"allowLoopbackIP,omitempty"`
-}
-
-func (x *User) Reset() {
-	*x = User{}
+	// HTTP path of the WebSocket endpoint, for example "/ws".
+	// If not set, "/" is used.
+	Path *string `protobuf:"bytes,1,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	// Host header of the WebSocket handshake request.
+	// It is also the server name of TLS handshake.
+	// If not set, the server domain name or IP address is used.
+	Host *string `protobuf:"bytes,2,opt,name=host,proto3,oneof" json:"host,omitempty"`
+	// Connect to the server without TLS.
+	// This should only be used when the server is not behind a CDN.
+	DisableTLS *bool `protobuf:"varint,3,opt,name=disableTLS,proto3,oneof" json:"disableTLS,omitempty"`
+	// Path to the TLS certificate file in PEM format.
+	// If both certificate and private key files are not set, the server
+	// accepts WebSocket without TLS, and a CDN or reverse proxy in front
+	// of the server is expected to terminate TLS.
+	CertificateFile *string `protobuf:"bytes,4,opt,name=certificateFile,proto3,oneof" json:"certificateFile,omitempty"`
+	// Path to the TLS private key file in PEM format.
+	PrivateKeyFile *string `protobuf:"bytes,5,opt,name=privateKeyFile,proto3,oneof" json:"privateKeyFile,omitempty"`
+}
+
+func (x *WebSocketConfig) Reset() {
+	*x = WebSocketConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_base_proto_msgTypes[3]
+		mi := &file_appctl_proto_base_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *User) String() string {
+func (x *WebSocketConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*User) ProtoMessage() {}
+func (*WebSocketConfig) ProtoMessage() {}
 
-func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_base_proto_msgTypes[3]
+func (x *WebSocketConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -494,82 +907,736 @@ func (x *User) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use User.ProtoReflect.Descriptor instead.
-func (*User) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_base_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use WebSocketConfig.ProtoReflect.Descriptor instead.
+func (*WebSocketConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WebSocketConfig) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *WebSocketConfig) GetHost() string {
+	if x != nil && x.Host != nil {
+		return *x.Host
+	}
+	return ""
+}
+
+func (x *WebSocketConfig) GetDisableTLS() bool {
+	if x != nil && x.DisableTLS != nil {
+		return *x.DisableTLS
+	}
+	return false
+}
+
+func (x *WebSocketConfig) GetCertificateFile() string {
+	if x != nil && x.CertificateFile != nil {
+		return *x.CertificateFile
+	}
+	return ""
+}
+
+func (x *WebSocketConfig) GetPrivateKeyFile() string {
+	if x != nil && x.PrivateKeyFile != nil {
+		return *x.PrivateKeyFile
+	}
+	return ""
+}
+
+type TLSCamouflageConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Server name indication of the TLS handshake, e.g. "www.example.com".
+	// The proxy server uses it to create a self-signed certificate
+	// if the certificate file is not set.
+	ServerName *string `protobuf:"bytes,1,opt,name=serverName,proto3,oneof" json:"serverName,omitempty"`
+	// Application layer protocols of the TLS handshake.
+	// If not set, proxy client uses "h2" and "http/1.1",
+	// and proxy server uses "http/1.1".
+	Alpn []string `protobuf:"bytes,2,rep,name=alpn,proto3" json:"alpn,omitempty"`
+	// Path to the TLS certificate file in PEM format.
+	CertificateFile *string `protobuf:"bytes,3,opt,name=certificateFile,proto3,oneof" json:"certificateFile,omitempty"`
+	// Path to the TLS private key file in PEM format.
+	PrivateKeyFile *string `protobuf:"bytes,4,opt,name=privateKeyFile,proto3,oneof" json:"privateKeyFile,omitempty"`
+	// Address of a web server, e.g. "127.0.0.1:80". Connections that fail
+	// authentication after the TLS handshake are forwarded to it.
+	// If not set, these connections are closed.
+	FallbackAddress *string `protobuf:"bytes,5,opt,name=fallbackAddress,proto3,oneof" json:"fallbackAddress,omitempty"`
+}
+
+func (x *TLSCamouflageConfig) Reset() {
+	*x = TLSCamouflageConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TLSCamouflageConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TLSCamouflageConfig) ProtoMessage() {}
+
+func (x *TLSCamouflageConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TLSCamouflageConfig.ProtoReflect.Descriptor instead.
+func (*TLSCamouflageConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TLSCamouflageConfig) GetServerName() string {
+	if x != nil && x.ServerName != nil {
+		return *x.ServerName
+	}
+	return ""
+}
+
+func (x *TLSCamouflageConfig) GetAlpn() []string {
+	if x != nil {
+		return x.Alpn
+	}
+	return nil
+}
+
+func (x *TLSCamouflageConfig) GetCertificateFile() string {
+	if x != nil && x.CertificateFile != nil {
+		return *x.CertificateFile
+	}
+	return ""
+}
+
+func (x *TLSCamouflageConfig) GetPrivateKeyFile() string {
+	if x != nil && x.PrivateKeyFile != nil {
+		return *x.PrivateKeyFile
+	}
+	return ""
+}
+
+func (x *TLSCamouflageConfig) GetFallbackAddress() string {
+	if x != nil && x.FallbackAddress != nil {
+		return *x.FallbackAddress
+	}
+	return ""
+}
+
+type User struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// User name is also the ID of user.
+	// Typically this is an email address.
+	Name *string `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	// Raw password.
+	// For safety this shouldn't be persisted at the server side.
+	Password *string `protobuf:"bytes,2,opt,name=password,proto3,oneof" json:"password,omitempty"`
+	// Hashed password.
+	// Stored with hex encoding.
+	HashedPassword *string `protobuf:"bytes,3,opt,name=hashedPassword,proto3,oneof" json:"hashedPassword,omitempty"`
+	// User quotas.
+	// This field has no effect at the client side.
+	Quotas []*Quota `protobuf:"bytes,4,rep,name=quotas,proto3" json:"quotas,omitempty"`
+	// Allow user to use private IP as the destination.
+	// This field has no effect at the client side.
+	AllowPrivateIP *bool `protobuf:"varint,5,opt,name=allowPrivateIP,proto3,oneof" json:"allowPrivateIP,omitempty"`
+	// Allow user to use loopback IP as the destination.
+	// This field has no effect at the client side.
+	AllowLoopbackIP *bool `protobuf:"varint,6,opt,name=allowLoopbackIP,proto3,oneof" json:"allowLoopbackIP,omitempty"`
+	// Allow user to use the echo and sink test endpoints.
+	// Test endpoints must also be enabled in server advanced settings.
+	// This field has no effect at the client side.
+	AllowTestEndpoints *bool `protobuf:"varint,7,opt,name=allowTestEndpoints,proto3,oneof" json:"allowTestEndpoints,omitempty"`
+	// The AEAD algorithm to encrypt the traffic of this user.
+	// Proxy client and proxy server must use the same value.
+	// If unset, XCHACHA20_POLY1305 is used.
+	CipherSuite *CipherSuite `protobuf:"varint,8,opt,name=cipherSuite,proto3,enum=mieru.appctl.CipherSuite,oneof" json:"cipherSuite,omitempty"`
+	// If true, the user can't open new sessions.
+	// This field has no effect at the client side.
+	Disabled *bool `protobuf:"varint,9,opt,name=disabled,proto3,oneof" json:"disabled,omitempty"`
+	// The time when the user expires, in RFC 3339 format,
+	// e.g. "2025-12-31T23:59:59Z". After that, the user can't open
+	// new sessions. If unset, the user never expires.
+	// This field has no effect at the client side.
+	ExpireTime *string `protobuf:"bytes,10,opt,name=expireTime,proto3,oneof" json:"expireTime,omitempty"`
+	// Rules to allow or deny the destinations of this user.
+	// The rules are checked in order, and the first matched rule is used.
+	// If no rule is matched, the destination is allowed.
+	// This field has no effect at the client side.
+	DestinationRules []*DestinationRule `protobuf:"bytes,11,rep,name=destinationRules,proto3" json:"destinationRules,omitempty"`
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *User) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *User) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *User) GetHashedPassword() string {
+	if x != nil && x.HashedPassword != nil {
+		return *x.HashedPassword
+	}
+	return ""
+}
+
+func (x *User) GetQuotas() []*Quota {
+	if x != nil {
+		return x.Quotas
+	}
+	return nil
+}
+
+func (x *User) GetAllowPrivateIP() bool {
+	if x != nil && x.AllowPrivateIP != nil {
+		return *x.AllowPrivateIP
+	}
+	return false
+}
+
+func (x *User) GetAllowLoopbackIP() bool {
+	if x != nil && x.AllowLoopbackIP != nil {
+		return *x.AllowLoopbackIP
+	}
+	return false
+}
+
+func (x *User) GetAllowTestEndpoints() bool {
+	if x != nil && x.AllowTestEndpoints != nil {
+		return *x.AllowTestEndpoints
+	}
+	return false
+}
+
+func (x *User) GetCipherSuite() CipherSuite {
+	if x != nil && x.CipherSuite != nil {
+		return *x.CipherSuite
+	}
+	return CipherSuite_DEFAULT_CIPHER_SUITE
+}
+
+func (x *User) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+func (x *User) GetExpireTime() string {
+	if x != nil && x.ExpireTime != nil {
+		return *x.ExpireTime
+	}
+	return ""
+}
+
+func (x *User) GetDestinationRules() []*DestinationRule {
+	if x != nil {
+		return x.DestinationRules
+	}
+	return nil
+}
+
+type DestinationRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A list of CIDR to match the rule.
+	// Use "*" to match all IP addresses.
+	IpRanges []string `protobuf:"bytes,1,rep,name=ipRanges,proto3" json:"ipRanges,omitempty"`
+	// A list of domain names to match the rule. A domain name also
+	// matches its subdomains. Use "*" to match all domain names.
+	// If both ipRanges and domainNames are empty, the rule matches
+	// all destinations.
+	DomainNames []string `protobuf:"bytes,2,rep,name=domainNames,proto3" json:"domainNames,omitempty"`
+	// A list of ports or port ranges to match the rule,
+	// e.g. "443" or "8000-9000". If empty, the rule matches all ports.
+	Ports []string `protobuf:"bytes,3,rep,name=ports,proto3" json:"ports,omitempty"`
+	// The action to do when the rule is matched.
+	Action *DestinationAction `protobuf:"varint,4,opt,name=action,proto3,enum=mieru.appctl.DestinationAction,oneof" json:"action,omitempty"`
+}
+
+func (x *DestinationRule) Reset() {
+	*x = DestinationRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DestinationRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationRule) ProtoMessage() {}
+
+func (x *DestinationRule) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationRule.ProtoReflect.Descriptor instead.
+func (*DestinationRule) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DestinationRule) GetIpRanges() []string {
+	if x != nil {
+		return x.IpRanges
+	}
+	return nil
+}
+
+func (x *DestinationRule) GetDomainNames() []string {
+	if x != nil {
+		return x.DomainNames
+	}
+	return nil
+}
+
+func (x *DestinationRule) GetPorts() []string {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+func (x *DestinationRule) GetAction() DestinationAction {
+	if x != nil && x.Action != nil {
+		return *x.Action
+	}
+	return DestinationAction_DEFAULT_DESTINATION_ACTION
+}
+
+type Quota struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Number of days to renew the quota.
+	// The renew is rolling based.
+	Days *int32 `protobuf:"varint,1,opt,name=days,proto3,oneof" json:"days,omitempty"`
+	// Number of megabytes the user allowed to send and receive.
+	Megabytes *int32 `protobuf:"varint,2,opt,name=megabytes,proto3,oneof" json:"megabytes,omitempty"`
+}
+
+func (x *Quota) Reset() {
+	*x = Quota{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Quota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quota) ProtoMessage() {}
+
+func (x *Quota) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quota.ProtoReflect.Descriptor instead.
+func (*Quota) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Quota) GetDays() int32 {
+	if x != nil && x.Days != nil {
+		return *x.Days
+	}
+	return 0
+}
+
+func (x *Quota) GetMegabytes() int32 {
+	if x != nil && x.Megabytes != nil {
+		return *x.Megabytes
+	}
+	return 0
+}
+
+type KeyRotationConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The length of a key epoch. Keys are derived from the password
+	// and the epoch, so keys of an old epoch stop working.
+	// Examples: 12h, 168h. It must not be less than 10 minutes.
+	// If unset, keys are not rotated.
+	Period *string `protobuf:"bytes,1,opt,name=period,proto3,oneof" json:"period,omitempty"`
+	// The time window before and after the epoch boundary when
+	// keys of the adjacent epoch are still accepted.
+	// It must not be larger than half of the period.
+	// If unset, the default value 10m is used.
+	Overlap *string `protobuf:"bytes,2,opt,name=overlap,proto3,oneof" json:"overlap,omitempty"`
+}
+
+func (x *KeyRotationConfig) Reset() {
+	*x = KeyRotationConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyRotationConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyRotationConfig) ProtoMessage() {}
+
+func (x *KeyRotationConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyRotationConfig.ProtoReflect.Descriptor instead.
+func (*KeyRotationConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *KeyRotationConfig) GetPeriod() string {
+	if x != nil && x.Period != nil {
+		return *x.Period
+	}
+	return ""
+}
+
+func (x *KeyRotationConfig) GetOverlap() string {
+	if x != nil && x.Overlap != nil {
+		return *x.Overlap
+	}
+	return ""
+}
+
+type Auth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Username used for authentication.
+	User *string `protobuf:"bytes,1,opt,name=user,proto3,oneof" json:"user,omitempty"`
+	// Password used for authentication.
+	Password *string `protobuf:"bytes,2,opt,name=password,proto3,oneof" json:"password,omitempty"`
+}
+
+func (x *Auth) Reset() {
+	*x = Auth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Auth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Auth) ProtoMessage() {}
+
+func (x *Auth) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Auth.ProtoReflect.Descriptor instead.
+func (*Auth) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Auth) GetUser() string {
+	if x != nil && x.User != nil {
+		return *x.User
+	}
+	return ""
+}
+
+func (x *Auth) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+// ErrorDetail is attached to the errors returned by the management RPC,
+// so the caller can show consistent guidance to the user.
+type ErrorDetail struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code *ErrorCode `protobuf:"varint,1,opt,name=code,proto3,enum=mieru.appctl.ErrorCode,oneof" json:"code,omitempty"`
+	// A message that can be shown to the user.
+	Message *string `protobuf:"bytes,2,opt,name=message,proto3,oneof" json:"message,omitempty"`
+	// If true, the same request may succeed later without any change.
+	Retriable *bool `protobuf:"varint,3,opt,name=retriable,proto3,oneof" json:"retriable,omitempty"`
+	// What the user can do to fix the error.
+	SuggestedAction *string `protobuf:"bytes,4,opt,name=suggestedAction,proto3,oneof" json:"suggestedAction,omitempty"`
+}
+
+func (x *ErrorDetail) Reset() {
+	*x = ErrorDetail{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ErrorDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorDetail) ProtoMessage() {}
+
+func (x *ErrorDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorDetail.ProtoReflect.Descriptor instead.
+func (*ErrorDetail) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ErrorDetail) GetCode() ErrorCode {
+	if x != nil && x.Code != nil {
+		return *x.Code
+	}
+	return ErrorCode_UNKNOWN_ERROR_CODE
+}
+
+func (x *ErrorDetail) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
 }
 
-func (x *User) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
+func (x *ErrorDetail) GetRetriable() bool {
+	if x != nil && x.Retriable != nil {
+		return *x.Retriable
 	}
-	return ""
+	return false
 }
 
-func (x *User) GetPassword() string {
-	if x != nil && x.Password != nil {
-		return *x.Password
+func (x *ErrorDetail) GetSuggestedAction() string {
+	if x != nil && x.SuggestedAction != nil {
+		return *x.SuggestedAction
 	}
 	return ""
 }
 
-func (x *User) GetHashedPassword() string {
-	if x != nil && x.HashedPassword != nil {
-		return *x.HashedPassword
+type PortKnockingConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ports to probe in order. Each step must have a single port
+	// and the TCP or UDP protocol.
+	Sequence []*PortBinding `protobuf:"bytes,1,rep,name=sequence,proto3" json:"sequence,omitempty"`
+	// The maximum time to finish the sequence.
+	// If unset, the default value 10s is used.
+	// This is only used by proxy server.
+	Timeout *string `protobuf:"bytes,2,opt,name=timeout,proto3,oneof" json:"timeout,omitempty"`
+	// The time that proxy server accepts new connections from an IP address
+	// after it finishes the sequence.
+	// If unset, the default value 30s is used.
+	// This is only used by proxy server.
+	OpenDuration *string `protobuf:"bytes,3,opt,name=openDuration,proto3,oneof" json:"openDuration,omitempty"`
+}
+
+func (x *PortKnockingConfig) Reset() {
+	*x = PortKnockingConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-func (x *User) GetQuotas() []*Quota {
+func (x *PortKnockingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortKnockingConfig) ProtoMessage() {}
+
+func (x *PortKnockingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortKnockingConfig.ProtoReflect.Descriptor instead.
+func (*PortKnockingConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PortKnockingConfig) GetSequence() []*PortBinding {
 	if x != nil {
-		return x.Quotas
+		return x.Sequence
 	}
 	return nil
 }
 
-func (x *User) GetAllowPrivateIP() bool {
-	if x != nil && x.AllowPrivateIP != nil {
-		return *x.AllowPrivateIP
+func (x *PortKnockingConfig) GetTimeout() string {
+	if x != nil && x.Timeout != nil {
+		return *x.Timeout
 	}
-	return false
+	return ""
 }
 
-func (x *User) GetAllowLoopbackIP() bool {
-	if x != nil && x.AllowLoopbackIP != nil {
-		return *x.AllowLoopbackIP
+func (x *PortKnockingConfig) GetOpenDuration() string {
+	if x != nil && x.OpenDuration != nil {
+		return *x.OpenDuration
 	}
-	return false
+	return ""
 }
 
-type Quota struct {
+// Limits of a listener to protect it from resource exhaustion attacks,
+// such as slowloris, which open many connections and never finish
+// the handshake.
+type AcceptLimit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Number of days to renew the quota.
-	// The renew is rolling based.
-	Days *int32 `protobuf:"varint,1,opt,name=days,proto3,oneof" json:"days,omitempty"`
-	// Number of megabytes the user allowed to send and receive.
-	Megabytes *int32 `protobuf:"varint,2,opt,name=megabytes,proto3,oneof" json:"megabytes,omitempty"`
-}
-
-func (x *Quota) Reset() {
-	*x = Quota{}
+	// The maximum time to finish the handshake of a new connection.
+	// The connection is closed after the timeout.
+	// If unset, the default value 30s is used.
+	HandshakeTimeout *string `protobuf:"bytes,1,opt,name=handshakeTimeout,proto3,oneof" json:"handshakeTimeout,omitempty"`
+	// The maximum number of accepted connections that haven't finished
+	// the handshake. New connections beyond the limit are closed.
+	// If unset or 0, the number is not limited.
+	MaxHalfOpen *int32 `protobuf:"varint,2,opt,name=maxHalfOpen,proto3,oneof" json:"maxHalfOpen,omitempty"`
+	// The maximum number of new connections accepted per second.
+	// New connections beyond the rate are closed.
+	// If unset or 0, the rate is not limited.
+	AcceptRate *int32 `protobuf:"varint,3,opt,name=acceptRate,proto3,oneof" json:"acceptRate,omitempty"`
+}
+
+func (x *AcceptLimit) Reset() {
+	*x = AcceptLimit{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_base_proto_msgTypes[4]
+		mi := &file_appctl_proto_base_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Quota) String() string {
+func (x *AcceptLimit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Quota) ProtoMessage() {}
+func (*AcceptLimit) ProtoMessage() {}
 
-func (x *Quota) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_base_proto_msgTypes[4]
+func (x *AcceptLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -580,53 +1647,158 @@ func (x *Quota) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Quota.ProtoReflect.Descriptor instead.
-func (*Quota) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_base_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use AcceptLimit.ProtoReflect.Descriptor instead.
+func (*AcceptLimit) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *Quota) GetDays() int32 {
-	if x != nil && x.Days != nil {
-		return *x.Days
+func (x *AcceptLimit) GetHandshakeTimeout() string {
+	if x != nil && x.HandshakeTimeout != nil {
+		return *x.HandshakeTimeout
+	}
+	return ""
+}
+
+func (x *AcceptLimit) GetMaxHalfOpen() int32 {
+	if x != nil && x.MaxHalfOpen != nil {
+		return *x.MaxHalfOpen
 	}
 	return 0
 }
 
-func (x *Quota) GetMegabytes() int32 {
-	if x != nil && x.Megabytes != nil {
-		return *x.Megabytes
+func (x *AcceptLimit) GetAcceptRate() int32 {
+	if x != nil && x.AcceptRate != nil {
+		return *x.AcceptRate
 	}
 	return 0
 }
 
-type Auth struct {
+type GeoDatabases struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Username used for authentication.
-	User *string `protobuf:"bytes,1,opt,name=user,proto3,oneof" json:"user,omitempty"`
-	// Password used for authentication.
-	Password *string `protobuf:"bytes,2,opt,name=password,proto3,oneof" json:"password,omitempty"`
+	// Absolute path of the GeoIP database file. It can be a MaxMind DB
+	// file like GeoLite2-Country.mmdb, or a CSV file with the first
+	// IP address, the last IP address and the country code of an
+	// IP address range in each line.
+	GeoIPDatabase *string `protobuf:"bytes,1,opt,name=geoIPDatabase,proto3,oneof" json:"geoIPDatabase,omitempty"`
+	// Absolute path of the geosite database file in the geosite.dat
+	// format used by V2Ray.
+	GeositeDatabase *string `protobuf:"bytes,2,opt,name=geositeDatabase,proto3,oneof" json:"geositeDatabase,omitempty"`
+	// URL to download the GeoIP database from.
+	// This is used by the "update geo-databases" command.
+	GeoIPDownloadURL *string `protobuf:"bytes,3,opt,name=geoIPDownloadURL,proto3,oneof" json:"geoIPDownloadURL,omitempty"`
+	// URL to download the geosite database from.
+	// This is used by the "update geo-databases" command.
+	GeositeDownloadURL *string `protobuf:"bytes,4,opt,name=geositeDownloadURL,proto3,oneof" json:"geositeDownloadURL,omitempty"`
+	// If set, a downloaded database must have a minisign signature at
+	// the download URL with ".minisig" suffix, created by a public key
+	// trusted by the "trust" commands.
+	VerifySignature *bool `protobuf:"varint,5,opt,name=verifySignature,proto3,oneof" json:"verifySignature,omitempty"`
+}
+
+func (x *GeoDatabases) Reset() {
+	*x = GeoDatabases{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_base_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (x *Auth) Reset() {
-	*x = Auth{}
+func (x *GeoDatabases) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoDatabases) ProtoMessage() {}
+
+func (x *GeoDatabases) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoDatabases.ProtoReflect.Descriptor instead.
+func (*GeoDatabases) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GeoDatabases) GetGeoIPDatabase() string {
+	if x != nil && x.GeoIPDatabase != nil {
+		return *x.GeoIPDatabase
+	}
+	return ""
+}
+
+func (x *GeoDatabases) GetGeositeDatabase() string {
+	if x != nil && x.GeositeDatabase != nil {
+		return *x.GeositeDatabase
+	}
+	return ""
+}
+
+func (x *GeoDatabases) GetGeoIPDownloadURL() string {
+	if x != nil && x.GeoIPDownloadURL != nil {
+		return *x.GeoIPDownloadURL
+	}
+	return ""
+}
+
+func (x *GeoDatabases) GetGeositeDownloadURL() string {
+	if x != nil && x.GeositeDownloadURL != nil {
+		return *x.GeositeDownloadURL
+	}
+	return ""
+}
+
+func (x *GeoDatabases) GetVerifySignature() bool {
+	if x != nil && x.VerifySignature != nil {
+		return *x.VerifySignature
+	}
+	return false
+}
+
+// A plugin that follows the SIP003 specification of shadowsocks, such as
+// simple-obfs and v2ray-plugin. It carries the TCP connections between
+// proxy client and proxy server.
+type SIP003Plugin struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Path of the plugin executable.
+	Command *string `protobuf:"bytes,1,opt,name=command,proto3,oneof" json:"command,omitempty"`
+	// Plugin options, passed to the plugin by the SS_PLUGIN_OPTIONS
+	// environment variable. Example: "obfs=http;obfs-host=www.bing.com".
+	Options *string `protobuf:"bytes,2,opt,name=options,proto3,oneof" json:"options,omitempty"`
+	// Command line arguments of the plugin executable.
+	Args []string `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+}
+
+func (x *SIP003Plugin) Reset() {
+	*x = SIP003Plugin{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_base_proto_msgTypes[5]
+		mi := &file_appctl_proto_base_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Auth) String() string {
+func (x *SIP003Plugin) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Auth) ProtoMessage() {}
+func (*SIP003Plugin) ProtoMessage() {}
 
-func (x *Auth) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_base_proto_msgTypes[5]
+func (x *SIP003Plugin) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_base_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -637,115 +1809,354 @@ func (x *Auth) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Auth.ProtoReflect.Descriptor instead.
-func (*Auth) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_base_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use SIP003Plugin.ProtoReflect.Descriptor instead.
+func (*SIP003Plugin) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_base_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *Auth) GetUser() string {
-	if x != nil && x.User != nil {
-		return *x.User
+func (x *SIP003Plugin) GetCommand() string {
+	if x != nil && x.Command != nil {
+		return *x.Command
 	}
 	return ""
 }
 
-func (x *Auth) GetPassword() string {
-	if x != nil && x.Password != nil {
-		return *x.Password
+func (x *SIP003Plugin) GetOptions() string {
+	if x != nil && x.Options != nil {
+		return *x.Options
 	}
 	return ""
 }
 
+func (x *SIP003Plugin) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
 var File_appctl_proto_base_proto protoreflect.FileDescriptor
 
 var file_appctl_proto_base_proto_rawDesc = []byte{
 	0x0a, 0x17, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62,
 	0x61, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x6d, 0x69, 0x65, 0x72, 0x75,
-	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x22, 0x4f, 0x0a, 0x0c, 0x41, 0x70, 0x70, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x34, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
-	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x41, 0x70, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a,
-	0x07, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xb4, 0x01, 0x0a, 0x0e, 0x53, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x09, 0x69,
-	0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
-	0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x23,
-	0x0a, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x48, 0x01, 0x52, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65,
-	0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x0c, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69,
-	0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x69, 0x65, 0x72,
-	0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e,
-	0x64, 0x69, 0x6e, 0x67, 0x52, 0x0c, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e,
-	0x67, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x22,
-	0xaf, 0x01, 0x0a, 0x0b, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12,
-	0x17, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52,
-	0x04, 0x70, 0x6f, 0x72, 0x74, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x6d, 0x69, 0x65,
-	0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70,
-	0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x48, 0x01, 0x52, 0x08, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x70, 0x6f,
-	0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
-	0x09, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a,
-	0x05, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x63, 0x6f, 0x6c, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67,
-	0x65, 0x22, 0xc6, 0x02, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
-	0x64, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x68, 0x61, 0x73, 0x68, 0x65, 0x64, 0x50, 0x61,
-	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0e,
-	0x68, 0x61, 0x73, 0x68, 0x65, 0x64, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x88, 0x01,
-	0x01, 0x12, 0x2b, 0x0a, 0x06, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
-	0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x06, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x73, 0x12, 0x2b,
-	0x0a, 0x0e, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x49, 0x50,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x0e, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x50,
-	0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x49, 0x50, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x61,
-	0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x6f, 0x70, 0x62, 0x61, 0x63, 0x6b, 0x49, 0x50, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x6f,
-	0x70, 0x62, 0x61, 0x63, 0x6b, 0x49, 0x50, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e,
-	0x61, 0x6d, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
-	0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x64, 0x50, 0x61, 0x73, 0x73, 0x77,
-	0x6f, 0x72, 0x64, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x50, 0x72, 0x69,
-	0x76, 0x61, 0x74, 0x65, 0x49, 0x50, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77,
-	0x4c, 0x6f, 0x6f, 0x70, 0x62, 0x61, 0x63, 0x6b, 0x49, 0x50, 0x22, 0x5a, 0x0a, 0x05, 0x51, 0x75,
-	0x6f, 0x74, 0x61, 0x12, 0x17, 0x0a, 0x04, 0x64, 0x61, 0x79, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x79, 0x73, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09,
-	0x6d, 0x65, 0x67, 0x61, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48,
-	0x01, 0x52, 0x09, 0x6d, 0x65, 0x67, 0x61, 0x62, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42,
-	0x07, 0x0a, 0x05, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6d, 0x65, 0x67,
-	0x61, 0x62, 0x79, 0x74, 0x65, 0x73, 0x22, 0x56, 0x0a, 0x04, 0x41, 0x75, 0x74, 0x68, 0x12, 0x17,
-	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04,
-	0x75, 0x73, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77,
-	0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x70, 0x61, 0x73,
-	0x73, 0x77, 0x6f, 0x72, 0x64, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73, 0x65,
-	0x72, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x2a, 0x4b,
-	0x0a, 0x09, 0x41, 0x70, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55,
-	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x44, 0x4c, 0x45,
-	0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x54, 0x41, 0x52, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x02,
-	0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x12, 0x0c, 0x0a,
-	0x08, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x49, 0x4e, 0x47, 0x10, 0x04, 0x2a, 0x5b, 0x0a, 0x0c, 0x4c,
-	0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x0b, 0x0a, 0x07, 0x44,
-	0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x41, 0x54, 0x41,
-	0x4c, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x08,
-	0x0a, 0x04, 0x57, 0x41, 0x52, 0x4e, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e, 0x46, 0x4f,
-	0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x45, 0x42, 0x55, 0x47, 0x10, 0x05, 0x12, 0x09, 0x0a,
-	0x05, 0x54, 0x52, 0x41, 0x43, 0x45, 0x10, 0x06, 0x2a, 0x5d, 0x0a, 0x09, 0x44, 0x75, 0x61, 0x6c,
-	0x53, 0x74, 0x61, 0x63, 0x6b, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x53, 0x45, 0x5f, 0x46, 0x49, 0x52,
-	0x53, 0x54, 0x5f, 0x49, 0x50, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52, 0x45, 0x46, 0x45,
-	0x52, 0x5f, 0x49, 0x50, 0x76, 0x34, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52, 0x45, 0x46,
-	0x45, 0x52, 0x5f, 0x49, 0x50, 0x76, 0x36, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x4f, 0x4e, 0x4c,
-	0x59, 0x5f, 0x49, 0x50, 0x76, 0x34, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x4f, 0x4e, 0x4c, 0x59,
-	0x5f, 0x49, 0x50, 0x76, 0x36, 0x10, 0x04, 0x2a, 0x45, 0x0a, 0x11, 0x54, 0x72, 0x61, 0x6e, 0x73,
-	0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x1e, 0x0a, 0x1a,
-	0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x50, 0x4f, 0x52,
-	0x54, 0x5f, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03,
-	0x55, 0x44, 0x50, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x43, 0x50, 0x10, 0x02, 0x42, 0x30,
-	0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6e, 0x66,
-	0x65, 0x69, 0x6e, 0x2f, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2f, 0x76, 0x33, 0x2f, 0x70, 0x6b, 0x67,
-	0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x70, 0x62,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x22, 0x9b, 0x04, 0x0a, 0x0c, 0x41, 0x70, 0x70, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x34, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x41, 0x70, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x88, 0x01, 0x01, 0x12, 0x29,
+	0x0a, 0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x70, 0x72, 0x6f,
+	0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x07, 0x70, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3e, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x6d, 0x69, 0x65, 0x72,
+	0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x07, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x21, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x09, 0x6c,
+	0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x11, 0x6c,
+	0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x48, 0x04, 0x52, 0x11, 0x6c, 0x61, 0x73, 0x74, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x88, 0x01, 0x01, 0x12, 0x3b,
+	0x0a, 0x16, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50,
+	0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x48, 0x05,
+	0x52, 0x16, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50,
+	0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x14, 0x75,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x48, 0x06, 0x52, 0x14, 0x75, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x42,
+	0x10, 0x0a, 0x0e, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69,
+	0x78, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x0c, 0x0a,
+	0x0a, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x14, 0x0a, 0x12, 0x5f,
+	0x6c, 0x61, 0x73, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69,
+	0x78, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x42, 0x17, 0x0a, 0x15,
+	0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x22, 0xa8, 0x01, 0x0a, 0x16, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x1f, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01,
+	0x01, 0x12, 0x23, 0x0a, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41,
+	0x64, 0x64, 0x72, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x08, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41,
+	0x64, 0x64, 0x72, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x22, 0xee, 0x03, 0x0a, 0x0e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x6e, 0x64, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0a, 0x64, 0x6f,
+	0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x0c, 0x70,
+	0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
+	0x2e, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x0c, 0x70, 0x6f,
+	0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x70, 0x69,
+	0x6e, 0x6e, 0x65, 0x64, 0x49, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x11, 0x70, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x49, 0x70, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x40, 0x0a, 0x09, 0x77, 0x65, 0x62, 0x53,
+	0x6f, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x69,
+	0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x57, 0x65, 0x62, 0x53, 0x6f,
+	0x63, 0x6b, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x02, 0x52, 0x09, 0x77, 0x65,
+	0x62, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x4c, 0x0a, 0x0d, 0x74, 0x6c,
+	0x73, 0x43, 0x61, 0x6d, 0x6f, 0x75, 0x66, 0x6c, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
+	0x2e, 0x54, 0x4c, 0x53, 0x43, 0x61, 0x6d, 0x6f, 0x75, 0x66, 0x6c, 0x61, 0x67, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x48, 0x03, 0x52, 0x0d, 0x74, 0x6c, 0x73, 0x43, 0x61, 0x6d, 0x6f, 0x75,
+	0x66, 0x6c, 0x61, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a, 0x0c, 0x70, 0x6f, 0x72, 0x74,
+	0x4b, 0x6e, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x6f,
+	0x72, 0x74, 0x4b, 0x6e, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x48, 0x04, 0x52, 0x0c, 0x70, 0x6f, 0x72, 0x74, 0x4b, 0x6e, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67,
+	0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65,
+	0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x77, 0x65, 0x62, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x42, 0x10,
+	0x0a, 0x0e, 0x5f, 0x74, 0x6c, 0x73, 0x43, 0x61, 0x6d, 0x6f, 0x75, 0x66, 0x6c, 0x61, 0x67, 0x65,
+	0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x4b, 0x6e, 0x6f, 0x63, 0x6b, 0x69, 0x6e,
+	0x67, 0x22, 0xfe, 0x01, 0x0a, 0x0b, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x00, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x08, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x48, 0x01, 0x52,
+	0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09,
+	0x70, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x02, 0x52, 0x09, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x35, 0x0a, 0x13, 0x70, 0x6f, 0x72, 0x74, 0x48, 0x6f, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x13,
+	0x70, 0x6f, 0x72, 0x74, 0x48, 0x6f, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x42,
+	0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x70,
+	0x6f, 0x72, 0x74, 0x48, 0x6f, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x22, 0x8c, 0x02, 0x0a, 0x0f, 0x57, 0x65, 0x62, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12,
+	0x17, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52,
+	0x04, 0x68, 0x6f, 0x73, 0x74, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x64, 0x69, 0x73, 0x61,
+	0x62, 0x6c, 0x65, 0x54, 0x4c, 0x53, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x0a,
+	0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x54, 0x4c, 0x53, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a,
+	0x0f, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0f, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e,
+	0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x0e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b,
+	0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x61,
+	0x74, 0x68, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x42, 0x0d, 0x0a, 0x0b, 0x5f,
+	0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x54, 0x4c, 0x53, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x63,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x11,
+	0x0a, 0x0f, 0x5f, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c,
+	0x65, 0x22, 0xa3, 0x02, 0x0a, 0x13, 0x54, 0x4c, 0x53, 0x43, 0x61, 0x6d, 0x6f, 0x75, 0x66, 0x6c,
+	0x61, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x23, 0x0a, 0x0a, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x0a, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x12,
+	0x0a, 0x04, 0x61, 0x6c, 0x70, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x6c,
+	0x70, 0x6e, 0x12, 0x2d, 0x0a, 0x0f, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0f, 0x63,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46,
+	0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0e, 0x70, 0x72, 0x69,
+	0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2d,
+	0x0a, 0x0f, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0f, 0x66, 0x61, 0x6c, 0x6c, 0x62,
+	0x61, 0x63, 0x6b, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a,
+	0x0b, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x42, 0x12, 0x0a, 0x10,
+	0x5f, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65,
+	0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46,
+	0x69, 0x6c, 0x65, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x91, 0x05, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72,
+	0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x68, 0x61,
+	0x73, 0x68, 0x65, 0x64, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x02, 0x52, 0x0e, 0x68, 0x61, 0x73, 0x68, 0x65, 0x64, 0x50, 0x61, 0x73, 0x73,
+	0x77, 0x6f, 0x72, 0x64, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x06, 0x71, 0x75, 0x6f, 0x74, 0x61,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
+	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x06, 0x71, 0x75,
+	0x6f, 0x74, 0x61, 0x73, 0x12, 0x2b, 0x0a, 0x0e, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x50, 0x72, 0x69,
+	0x76, 0x61, 0x74, 0x65, 0x49, 0x50, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x0e,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x49, 0x50, 0x88, 0x01,
+	0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x6f, 0x70, 0x62, 0x61,
+	0x63, 0x6b, 0x49, 0x50, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x0f, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x6f, 0x70, 0x62, 0x61, 0x63, 0x6b, 0x49, 0x50, 0x88, 0x01, 0x01,
+	0x12, 0x33, 0x0a, 0x12, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x54, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x48, 0x05, 0x52, 0x12,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x54, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x0b, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53,
+	0x75, 0x69, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x6d, 0x69, 0x65,
+	0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72,
+	0x53, 0x75, 0x69, 0x74, 0x65, 0x48, 0x06, 0x52, 0x0b, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53,
+	0x75, 0x69, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x07, 0x52, 0x08, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x48, 0x08, 0x52, 0x0a,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a,
+	0x10, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x75, 0x6c, 0x65,
+	0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
+	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x10, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x42, 0x11,
+	0x0a, 0x0f, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x65, 0x64, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x50, 0x72, 0x69, 0x76, 0x61,
+	0x74, 0x65, 0x49, 0x50, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f,
+	0x6f, 0x70, 0x62, 0x61, 0x63, 0x6b, 0x49, 0x50, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x54, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x42,
+	0x0e, 0x0a, 0x0c, 0x5f, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65, 0x42,
+	0x0b, 0x0a, 0x09, 0x5f, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xae, 0x01, 0x0a, 0x0f,
+	0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x75, 0x6c, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x69, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x08, 0x69, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x6f,
+	0x72, 0x74, 0x73, 0x12, 0x3c, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01,
+	0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x5a, 0x0a, 0x05,
+	0x51, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x17, 0x0a, 0x04, 0x64, 0x61, 0x79, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x79, 0x73, 0x88, 0x01, 0x01, 0x12, 0x21,
+	0x0a, 0x09, 0x6d, 0x65, 0x67, 0x61, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x01, 0x52, 0x09, 0x6d, 0x65, 0x67, 0x61, 0x62, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01,
+	0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6d,
+	0x65, 0x67, 0x61, 0x62, 0x79, 0x74, 0x65, 0x73, 0x22, 0x66, 0x0a, 0x11, 0x4b, 0x65, 0x79, 0x52,
+	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1b, 0x0a,
+	0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x6f, 0x76,
+	0x65, 0x72, 0x6c, 0x61, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x07, 0x6f,
+	0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70, 0x65,
+	0x72, 0x69, 0x6f, 0x64, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70,
+	0x22, 0x56, 0x0a, 0x04, 0x41, 0x75, 0x74, 0x68, 0x12, 0x17, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x88, 0x01,
+	0x01, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x88,
+	0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0xe7, 0x01, 0x0a, 0x0b, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x30, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61,
+	0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x48,
+	0x00, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x72, 0x65, 0x74,
+	0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x09,
+	0x72, 0x65, 0x74, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f,
+	0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0f, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x65, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x63, 0x6f, 0x64, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x12,
+	0x0a, 0x10, 0x5f, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0xb0, 0x01, 0x0a, 0x12, 0x50, 0x6f, 0x72, 0x74, 0x4b, 0x6e, 0x6f, 0x63, 0x6b,
+	0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x35, 0x0a, 0x08, 0x73, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x69,
+	0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x42,
+	0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x12, 0x1d, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x27, 0x0a, 0x0c, 0x6f, 0x70, 0x65, 0x6e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0c, 0x6f, 0x70, 0x65, 0x6e, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xbe, 0x01, 0x0a, 0x0b, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x2f, 0x0a, 0x10, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61,
+	0x6b, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x10, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x48, 0x61, 0x6c,
+	0x66, 0x4f, 0x70, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0b, 0x6d,
+	0x61, 0x78, 0x48, 0x61, 0x6c, 0x66, 0x4f, 0x70, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a,
+	0x0a, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x02, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x52, 0x61, 0x74, 0x65, 0x88,
+	0x01, 0x01, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x48,
+	0x61, 0x6c, 0x66, 0x4f, 0x70, 0x65, 0x6e, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x61, 0x63, 0x63, 0x65,
+	0x70, 0x74, 0x52, 0x61, 0x74, 0x65, 0x22, 0xe3, 0x02, 0x0a, 0x0c, 0x47, 0x65, 0x6f, 0x44, 0x61,
+	0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x0d, 0x67, 0x65, 0x6f, 0x49, 0x50,
+	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x0d, 0x67, 0x65, 0x6f, 0x49, 0x50, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x67, 0x65, 0x6f, 0x73, 0x69, 0x74, 0x65, 0x44, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0f, 0x67,
+	0x65, 0x6f, 0x73, 0x69, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x2f, 0x0a, 0x10, 0x67, 0x65, 0x6f, 0x49, 0x50, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f,
+	0x61, 0x64, 0x55, 0x52, 0x4c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x10, 0x67,
+	0x65, 0x6f, 0x49, 0x50, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x55, 0x52, 0x4c, 0x88,
+	0x01, 0x01, 0x12, 0x33, 0x0a, 0x12, 0x67, 0x65, 0x6f, 0x73, 0x69, 0x74, 0x65, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x55, 0x52, 0x4c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03,
+	0x52, 0x12, 0x67, 0x65, 0x6f, 0x73, 0x69, 0x74, 0x65, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61,
+	0x64, 0x55, 0x52, 0x4c, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x76, 0x65, 0x72, 0x69, 0x66,
+	0x79, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x04, 0x52, 0x0f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x67, 0x65, 0x6f, 0x49, 0x50,
+	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x67, 0x65, 0x6f,
+	0x73, 0x69, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x42, 0x13, 0x0a, 0x11,
+	0x5f, 0x67, 0x65, 0x6f, 0x49, 0x50, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x55, 0x52,
+	0x4c, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x67, 0x65, 0x6f, 0x73, 0x69, 0x74, 0x65, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x55, 0x52, 0x4c, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x76, 0x65, 0x72,
+	0x69, 0x66, 0x79, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x78, 0x0a, 0x0c,
+	0x53, 0x49, 0x50, 0x30, 0x30, 0x33, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x1d, 0x0a, 0x07,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x07,
+	0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72,
+	0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x42, 0x0a,
+	0x0a, 0x08, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2a, 0x4b, 0x0a, 0x09, 0x41, 0x70, 0x70, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00,
+	0x12, 0x08, 0x0a, 0x04, 0x49, 0x44, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x54,
+	0x41, 0x52, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x55, 0x4e, 0x4e,
+	0x49, 0x4e, 0x47, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x49, 0x4e,
+	0x47, 0x10, 0x04, 0x2a, 0x5b, 0x0a, 0x0c, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x4c, 0x65,
+	0x76, 0x65, 0x6c, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00,
+	0x12, 0x09, 0x0a, 0x05, 0x46, 0x41, 0x54, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45,
+	0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x57, 0x41, 0x52, 0x4e, 0x10, 0x03,
+	0x12, 0x08, 0x0a, 0x04, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x45,
+	0x42, 0x55, 0x47, 0x10, 0x05, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x52, 0x41, 0x43, 0x45, 0x10, 0x06,
+	0x2a, 0x35, 0x0a, 0x09, 0x4c, 0x6f, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x13, 0x0a,
+	0x0f, 0x4c, 0x4f, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x54, 0x45, 0x58, 0x54,
+	0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x4c, 0x4f, 0x47, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54,
+	0x5f, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x01, 0x2a, 0x5d, 0x0a, 0x09, 0x44, 0x75, 0x61, 0x6c, 0x53,
+	0x74, 0x61, 0x63, 0x6b, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x53, 0x45, 0x5f, 0x46, 0x49, 0x52, 0x53,
+	0x54, 0x5f, 0x49, 0x50, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52, 0x45, 0x46, 0x45, 0x52,
+	0x5f, 0x49, 0x50, 0x76, 0x34, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x52, 0x45, 0x46, 0x45,
+	0x52, 0x5f, 0x49, 0x50, 0x76, 0x36, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x4f, 0x4e, 0x4c, 0x59,
+	0x5f, 0x49, 0x50, 0x76, 0x34, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x4f, 0x4e, 0x4c, 0x59, 0x5f,
+	0x49, 0x50, 0x76, 0x36, 0x10, 0x04, 0x2a, 0x5e, 0x0a, 0x11, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70,
+	0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x1e, 0x0a, 0x1a, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x50, 0x4f, 0x52, 0x54,
+	0x5f, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x55,
+	0x44, 0x50, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x43, 0x50, 0x10, 0x02, 0x12, 0x0d, 0x0a,
+	0x09, 0x57, 0x45, 0x42, 0x53, 0x4f, 0x43, 0x4b, 0x45, 0x54, 0x10, 0x04, 0x12, 0x08, 0x0a, 0x04,
+	0x41, 0x55, 0x54, 0x4f, 0x10, 0x05, 0x2a, 0x48, 0x0a, 0x11, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x1a, 0x44,
+	0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x44, 0x45, 0x53, 0x54, 0x49, 0x4e, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x41,
+	0x4c, 0x4c, 0x4f, 0x57, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x45, 0x4e, 0x59, 0x10, 0x02,
+	0x2a, 0x8f, 0x01, 0x0a, 0x0b, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x53, 0x75, 0x69, 0x74, 0x65,
+	0x12, 0x18, 0x0a, 0x14, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x43, 0x49, 0x50, 0x48,
+	0x45, 0x52, 0x5f, 0x53, 0x55, 0x49, 0x54, 0x45, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x58, 0x43,
+	0x48, 0x41, 0x43, 0x48, 0x41, 0x32, 0x30, 0x5f, 0x50, 0x4f, 0x4c, 0x59, 0x31, 0x33, 0x30, 0x35,
+	0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x45, 0x53, 0x5f, 0x31, 0x32, 0x38, 0x5f, 0x47, 0x43,
+	0x4d, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x45, 0x53, 0x5f, 0x32, 0x35, 0x36, 0x5f, 0x47,
+	0x43, 0x4d, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x48, 0x41, 0x43, 0x48, 0x41, 0x32, 0x30,
+	0x5f, 0x50, 0x4f, 0x4c, 0x59, 0x31, 0x33, 0x30, 0x35, 0x10, 0x04, 0x12, 0x15, 0x0a, 0x11, 0x41,
+	0x55, 0x54, 0x4f, 0x5f, 0x43, 0x49, 0x50, 0x48, 0x45, 0x52, 0x5f, 0x53, 0x55, 0x49, 0x54, 0x45,
+	0x10, 0x05, 0x2a, 0x7d, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12,
+	0x16, 0x0a, 0x12, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52,
+	0x5f, 0x43, 0x4f, 0x44, 0x45, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x49, 0x4e, 0x56, 0x41, 0x4c,
+	0x49, 0x44, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x53,
+	0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x41, 0x56, 0x41, 0x49, 0x4c, 0x41, 0x42,
+	0x4c, 0x45, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x46, 0x45, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x4e, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x12, 0x12, 0x0a,
+	0x0e, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10,
+	0x04, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x65, 0x6e, 0x66, 0x65, 0x69, 0x6e, 0x2f, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2f, 0x76, 0x33, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74,
+	0x6c, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -760,30 +2171,53 @@ func file_appctl_proto_base_proto_rawDescGZIP() []byte {
 	return file_appctl_proto_base_proto_rawDescData
 }
 
-var file_appctl_proto_base_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_appctl_proto_base_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_appctl_proto_base_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_appctl_proto_base_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_appctl_proto_base_proto_goTypes = []interface{}{
-	(AppStatus)(0),         // 0: mieru.appctl.AppStatus
-	(LoggingLevel)(0),      // 1: mieru.appctl.LoggingLevel
-	(DualStack)(0),         // 2: mieru.appctl.DualStack
-	(TransportProtocol)(0), // 3: mieru.appctl.TransportProtocol
-	(*AppStatusMsg)(nil),   // 4: mieru.appctl.AppStatusMsg
-	(*ServerEndpoint)(nil), // 5: mieru.appctl.ServerEndpoint
-	(*PortBinding)(nil),    // 6: mieru.appctl.PortBinding
-	(*User)(nil),           // 7: mieru.appctl.User
-	(*Quota)(nil),          // 8: mieru.appctl.Quota
-	(*Auth)(nil),           // 9: mieru.appctl.Auth
+	(AppStatus)(0),                 // 0: mieru.appctl.AppStatus
+	(LoggingLevel)(0),              // 1: mieru.appctl.LoggingLevel
+	(LogFormat)(0),                 // 2: mieru.appctl.LogFormat
+	(DualStack)(0),                 // 3: mieru.appctl.DualStack
+	(TransportProtocol)(0),         // 4: mieru.appctl.TransportProtocol
+	(DestinationAction)(0),         // 5: mieru.appctl.DestinationAction
+	(CipherSuite)(0),               // 6: mieru.appctl.CipherSuite
+	(ErrorCode)(0),                 // 7: mieru.appctl.ErrorCode
+	(*AppStatusMsg)(nil),           // 8: mieru.appctl.AppStatusMsg
+	(*ServerConnectionStatus)(nil), // 9: mieru.appctl.ServerConnectionStatus
+	(*ServerEndpoint)(nil),         // 10: mieru.appctl.ServerEndpoint
+	(*PortBinding)(nil),            // 11: mieru.appctl.PortBinding
+	(*WebSocketConfig)(nil),        // 12: mieru.appctl.WebSocketConfig
+	(*TLSCamouflageConfig)(nil),    // 13: mieru.appctl.TLSCamouflageConfig
+	(*User)(nil),                   // 14: mieru.appctl.User
+	(*DestinationRule)(nil),        // 15: mieru.appctl.DestinationRule
+	(*Quota)(nil),                  // 16: mieru.appctl.Quota
+	(*KeyRotationConfig)(nil),      // 17: mieru.appctl.KeyRotationConfig
+	(*Auth)(nil),                   // 18: mieru.appctl.Auth
+	(*ErrorDetail)(nil),            // 19: mieru.appctl.ErrorDetail
+	(*PortKnockingConfig)(nil),     // 20: mieru.appctl.PortKnockingConfig
+	(*AcceptLimit)(nil),            // 21: mieru.appctl.AcceptLimit
+	(*GeoDatabases)(nil),           // 22: mieru.appctl.GeoDatabases
+	(*SIP003Plugin)(nil),           // 23: mieru.appctl.SIP003Plugin
 }
 var file_appctl_proto_base_proto_depIdxs = []int32{
-	0, // 0: mieru.appctl.AppStatusMsg.status:type_name -> mieru.appctl.AppStatus
-	6, // 1: mieru.appctl.ServerEndpoint.portBindings:type_name -> mieru.appctl.PortBinding
-	3, // 2: mieru.appctl.PortBinding.protocol:type_name -> mieru.appctl.TransportProtocol
-	8, // 3: mieru.appctl.User.quotas:type_name -> mieru.appctl.Quota
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: mieru.appctl.AppStatusMsg.status:type_name -> mieru.appctl.AppStatus
+	9,  // 1: mieru.appctl.AppStatusMsg.servers:type_name -> mieru.appctl.ServerConnectionStatus
+	11, // 2: mieru.appctl.ServerEndpoint.portBindings:type_name -> mieru.appctl.PortBinding
+	12, // 3: mieru.appctl.ServerEndpoint.webSocket:type_name -> mieru.appctl.WebSocketConfig
+	13, // 4: mieru.appctl.ServerEndpoint.tlsCamouflage:type_name -> mieru.appctl.TLSCamouflageConfig
+	20, // 5: mieru.appctl.ServerEndpoint.portKnocking:type_name -> mieru.appctl.PortKnockingConfig
+	4,  // 6: mieru.appctl.PortBinding.protocol:type_name -> mieru.appctl.TransportProtocol
+	16, // 7: mieru.appctl.User.quotas:type_name -> mieru.appctl.Quota
+	6,  // 8: mieru.appctl.User.cipherSuite:type_name -> mieru.appctl.CipherSuite
+	15, // 9: mieru.appctl.User.destinationRules:type_name -> mieru.appctl.DestinationRule
+	5,  // 10: mieru.appctl.DestinationRule.action:type_name -> mieru.appctl.DestinationAction
+	7,  // 11: mieru.appctl.ErrorDetail.code:type_name -> mieru.appctl.ErrorCode
+	11, // 12: mieru.appctl.PortKnockingConfig.sequence:type_name -> mieru.appctl.PortBinding
+	13, // [13:13] is the sub-list for method output_type
+	13, // [13:13] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_appctl_proto_base_proto_init() }
@@ -805,7 +2239,7 @@ func file_appctl_proto_base_proto_init() {
 			}
 		}
 		file_appctl_proto_base_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ServerEndpoint); i {
+			switch v := v.(*ServerConnectionStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -817,7 +2251,7 @@ func file_appctl_proto_base_proto_init() {
 			}
 		}
 		file_appctl_proto_base_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PortBinding); i {
+			switch v := v.(*ServerEndpoint); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -829,7 +2263,7 @@ func file_appctl_proto_base_proto_init() {
 			}
 		}
 		file_appctl_proto_base_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*User); i {
+			switch v := v.(*PortBinding); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -841,7 +2275,7 @@ func file_appctl_proto_base_proto_init() {
 			}
 		}
 		file_appctl_proto_base_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Quota); i {
+			switch v := v.(*WebSocketConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -853,6 +2287,66 @@ func file_appctl_proto_base_proto_init() {
 			}
 		}
 		file_appctl_proto_base_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TLSCamouflageConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_base_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*User); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_base_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DestinationRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_base_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Quota); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_base_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyRotationConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_base_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Auth); i {
 			case 0:
 				return &v.state
@@ -864,6 +2358,66 @@ func file_appctl_proto_base_proto_init() {
 				return nil
 			}
 		}
+		file_appctl_proto_base_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ErrorDetail); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_base_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PortKnockingConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_base_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AcceptLimit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_base_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GeoDatabases); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_base_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SIP003Plugin); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_appctl_proto_base_proto_msgTypes[0].OneofWrappers = []interface{}{}
 	file_appctl_proto_base_proto_msgTypes[1].OneofWrappers = []interface{}{}
@@ -871,13 +2425,23 @@ func file_appctl_proto_base_proto_init() {
 	file_appctl_proto_base_proto_msgTypes[3].OneofWrappers = []interface{}{}
 	file_appctl_proto_base_proto_msgTypes[4].OneofWrappers = []interface{}{}
 	file_appctl_proto_base_proto_msgTypes[5].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[9].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[14].OneofWrappers = []interface{}{}
+	file_appctl_proto_base_proto_msgTypes[15].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_appctl_proto_base_proto_rawDesc,
-			NumEnums:      4,
-			NumMessages:   6,
+			NumEnums:      8,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -35,6 +35,226 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ConfigKeyStore int32
+
+const (
+	// Use the keychain of the operating system if it is available.
+	// Otherwise, use a passphrase. The choice is stored in the client
+	// config when encryption is enabled.
+	ConfigKeyStore_CONFIG_KEY_STORE_DEFAULT ConfigKeyStore = 0
+	// The key is stored in the keychain of the operating system:
+	// Keychain in macOS, Secret Service (libsecret) in Linux,
+	// and DPAPI in Windows.
+	ConfigKeyStore_CONFIG_KEY_STORE_OS_KEYCHAIN ConfigKeyStore = 1
+	// The key is derived from the passphrase in the
+	// MIERU_CONFIG_PASSPHRASE environment variable.
+	ConfigKeyStore_CONFIG_KEY_STORE_PASSPHRASE ConfigKeyStore = 2
+)
+
+// Enum value maps for ConfigKeyStore.
+var (
+	ConfigKeyStore_name = map[int32]string{
+		0: "CONFIG_KEY_STORE_DEFAULT",
+		1: "CONFIG_KEY_STORE_OS_KEYCHAIN",
+		2: "CONFIG_KEY_STORE_PASSPHRASE",
+	}
+	ConfigKeyStore_value = map[string]int32{
+		"CONFIG_KEY_STORE_DEFAULT":     0,
+		"CONFIG_KEY_STORE_OS_KEYCHAIN": 1,
+		"CONFIG_KEY_STORE_PASSPHRASE":  2,
+	}
+)
+
+func (x ConfigKeyStore) Enum() *ConfigKeyStore {
+	p := new(ConfigKeyStore)
+	*p = x
+	return p
+}
+
+func (x ConfigKeyStore) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConfigKeyStore) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_clientcfg_proto_enumTypes[0].Descriptor()
+}
+
+func (ConfigKeyStore) Type() protoreflect.EnumType {
+	return &file_appctl_proto_clientcfg_proto_enumTypes[0]
+}
+
+func (x ConfigKeyStore) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConfigKeyStore.Descriptor instead.
+func (ConfigKeyStore) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{0}
+}
+
+type DNSMode int32
+
+const (
+	// Same as DNS_MODE_REMOTE.
+	DNSMode_DNS_MODE_DEFAULT DNSMode = 0
+	// Domain names are resolved by the proxy server.
+	DNSMode_DNS_MODE_REMOTE DNSMode = 1
+	// Domain names are resolved by the client, and the proxy server
+	// connects to the IP address.
+	DNSMode_DNS_MODE_LOCAL DNSMode = 2
+)
+
+// Enum value maps for DNSMode.
+var (
+	DNSMode_name = map[int32]string{
+		0: "DNS_MODE_DEFAULT",
+		1: "DNS_MODE_REMOTE",
+		2: "DNS_MODE_LOCAL",
+	}
+	DNSMode_value = map[string]int32{
+		"DNS_MODE_DEFAULT": 0,
+		"DNS_MODE_REMOTE":  1,
+		"DNS_MODE_LOCAL":   2,
+	}
+)
+
+func (x DNSMode) Enum() *DNSMode {
+	p := new(DNSMode)
+	*p = x
+	return p
+}
+
+func (x DNSMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DNSMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_clientcfg_proto_enumTypes[1].Descriptor()
+}
+
+func (DNSMode) Type() protoreflect.EnumType {
+	return &file_appctl_proto_clientcfg_proto_enumTypes[1]
+}
+
+func (x DNSMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DNSMode.Descriptor instead.
+func (DNSMode) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{1}
+}
+
+type UDPSourceFilter int32
+
+const (
+	// Use the default filter, which is UDP_SOURCE_FILTER_SAME_IP.
+	UDPSourceFilter_UDP_SOURCE_FILTER_DEFAULT UDPSourceFilter = 0
+	// Only accept datagrams from the address and port declared in the
+	// UDP ASSOCIATE request. If the declared IP address is all zeros,
+	// the IP address of the socks5 TCP connection is used. If the declared
+	// port is 0, any port is accepted.
+	UDPSourceFilter_UDP_SOURCE_FILTER_STRICT UDPSourceFilter = 1
+	// Only accept datagrams from the IP address of the socks5 TCP connection.
+	UDPSourceFilter_UDP_SOURCE_FILTER_SAME_IP UDPSourceFilter = 2
+	// Accept datagrams from any address.
+	UDPSourceFilter_UDP_SOURCE_FILTER_OFF UDPSourceFilter = 3
+)
+
+// Enum value maps for UDPSourceFilter.
+var (
+	UDPSourceFilter_name = map[int32]string{
+		0: "UDP_SOURCE_FILTER_DEFAULT",
+		1: "UDP_SOURCE_FILTER_STRICT",
+		2: "UDP_SOURCE_FILTER_SAME_IP",
+		3: "UDP_SOURCE_FILTER_OFF",
+	}
+	UDPSourceFilter_value = map[string]int32{
+		"UDP_SOURCE_FILTER_DEFAULT": 0,
+		"UDP_SOURCE_FILTER_STRICT":  1,
+		"UDP_SOURCE_FILTER_SAME_IP": 2,
+		"UDP_SOURCE_FILTER_OFF":     3,
+	}
+)
+
+func (x UDPSourceFilter) Enum() *UDPSourceFilter {
+	p := new(UDPSourceFilter)
+	*p = x
+	return p
+}
+
+func (x UDPSourceFilter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UDPSourceFilter) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_clientcfg_proto_enumTypes[2].Descriptor()
+}
+
+func (UDPSourceFilter) Type() protoreflect.EnumType {
+	return &file_appctl_proto_clientcfg_proto_enumTypes[2]
+}
+
+func (x UDPSourceFilter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UDPSourceFilter.Descriptor instead.
+func (UDPSourceFilter) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{2}
+}
+
+type UpstreamProxyProtocol int32
+
+const (
+	UpstreamProxyProtocol_UNKNOWN_UPSTREAM_PROXY_PROTOCOL UpstreamProxyProtocol = 0
+	// socks5 proxy with the CONNECT command.
+	UpstreamProxyProtocol_UPSTREAM_SOCKS5 UpstreamProxyProtocol = 1
+	// HTTP proxy with the CONNECT method.
+	UpstreamProxyProtocol_UPSTREAM_HTTP UpstreamProxyProtocol = 2
+)
+
+// Enum value maps for UpstreamProxyProtocol.
+var (
+	UpstreamProxyProtocol_name = map[int32]string{
+		0: "UNKNOWN_UPSTREAM_PROXY_PROTOCOL",
+		1: "UPSTREAM_SOCKS5",
+		2: "UPSTREAM_HTTP",
+	}
+	UpstreamProxyProtocol_value = map[string]int32{
+		"UNKNOWN_UPSTREAM_PROXY_PROTOCOL": 0,
+		"UPSTREAM_SOCKS5":                 1,
+		"UPSTREAM_HTTP":                   2,
+	}
+)
+
+func (x UpstreamProxyProtocol) Enum() *UpstreamProxyProtocol {
+	p := new(UpstreamProxyProtocol)
+	*p = x
+	return p
+}
+
+func (x UpstreamProxyProtocol) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UpstreamProxyProtocol) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_clientcfg_proto_enumTypes[3].Descriptor()
+}
+
+func (UpstreamProxyProtocol) Type() protoreflect.EnumType {
+	return &file_appctl_proto_clientcfg_proto_enumTypes[3]
+}
+
+func (x UpstreamProxyProtocol) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UpstreamProxyProtocol.Descriptor instead.
+func (UpstreamProxyProtocol) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{3}
+}
+
 type MultiplexingLevel int32
 
 const (
@@ -74,11 +294,11 @@ func (x MultiplexingLevel) String() string {
 }
 
 func (MultiplexingLevel) Descriptor() protoreflect.EnumDescriptor {
-	return file_appctl_proto_clientcfg_proto_enumTypes[0].Descriptor()
+	return file_appctl_proto_clientcfg_proto_enumTypes[4].Descriptor()
 }
 
 func (MultiplexingLevel) Type() protoreflect.EnumType {
-	return &file_appctl_proto_clientcfg_proto_enumTypes[0]
+	return &file_appctl_proto_clientcfg_proto_enumTypes[4]
 }
 
 func (x MultiplexingLevel) Number() protoreflect.EnumNumber {
@@ -87,7 +307,55 @@ func (x MultiplexingLevel) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use MultiplexingLevel.Descriptor instead.
 func (MultiplexingLevel) EnumDescriptor() ([]byte, []int) {
-	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{0}
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{4}
+}
+
+type UpdateChannel int32
+
+const (
+	// Releases that are not marked as pre-release.
+	UpdateChannel_UPDATE_CHANNEL_STABLE UpdateChannel = 0
+	// All releases, including pre-releases.
+	UpdateChannel_UPDATE_CHANNEL_BETA UpdateChannel = 1
+)
+
+// Enum value maps for UpdateChannel.
+var (
+	UpdateChannel_name = map[int32]string{
+		0: "UPDATE_CHANNEL_STABLE",
+		1: "UPDATE_CHANNEL_BETA",
+	}
+	UpdateChannel_value = map[string]int32{
+		"UPDATE_CHANNEL_STABLE": 0,
+		"UPDATE_CHANNEL_BETA":   1,
+	}
+)
+
+func (x UpdateChannel) Enum() *UpdateChannel {
+	p := new(UpdateChannel)
+	*p = x
+	return p
+}
+
+func (x UpdateChannel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UpdateChannel) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_clientcfg_proto_enumTypes[5].Descriptor()
+}
+
+func (UpdateChannel) Type() protoreflect.EnumType {
+	return &file_appctl_proto_clientcfg_proto_enumTypes[5]
+}
+
+func (x UpdateChannel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UpdateChannel.Descriptor instead.
+func (UpdateChannel) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{5}
 }
 
 type ClientConfig struct {
@@ -116,6 +384,54 @@ type ClientConfig struct {
 	// A list of accounts that can authenticate mieru socks5 proxy service.
 	// If the list is empty, authentication is not required.
 	Socks5Authentication []*Auth `protobuf:"bytes,10,rep,name=socks5Authentication,proto3" json:"socks5Authentication,omitempty"`
+	// Automatically switch to a backup profile when the proxy servers
+	// of the current profile are not reachable.
+	Failover *ProfileFailover `protobuf:"bytes,11,opt,name=failover,proto3,oneof" json:"failover,omitempty"`
+	// If set, the download bandwidth in megabits per second is shared
+	// equally by the source IP addresses of active proxy clients, so one
+	// device on the LAN can't starve the others.
+	FairShareBandwidthMbps *int32 `protobuf:"varint,12,opt,name=fairShareBandwidthMbps,proto3,oneof" json:"fairShareBandwidthMbps,omitempty"`
+	// Which source addresses can send datagrams to the UDP relay
+	// created by a socks5 UDP ASSOCIATE request.
+	Socks5UDPSourceFilter *UDPSourceFilter `protobuf:"varint,13,opt,name=socks5UDPSourceFilter,proto3,enum=mieru.appctl.UDPSourceFilter,oneof" json:"socks5UDPSourceFilter,omitempty"`
+	// Send keepalives on idle proxied TCP connections that match a rule,
+	// so NAT devices and firewalls between the client and the proxy
+	// server don't expire long-lived idle connections. Keepalives are
+	// not forwarded to the destination. The first matching rule is used.
+	KeepaliveRules []*KeepaliveRule `protobuf:"bytes,14,rep,name=keepaliveRules,proto3" json:"keepaliveRules,omitempty"`
+	// Destinations that are connected directly instead of through
+	// the proxy tunnel.
+	Bypass *BypassConfig `protobuf:"bytes,15,opt,name=bypass,proto3,oneof" json:"bypass,omitempty"`
+	// GeoIP and geosite databases used by bypass rules.
+	GeoDatabases *GeoDatabases `protobuf:"bytes,16,opt,name=geoDatabases,proto3,oneof" json:"geoDatabases,omitempty"`
+	// If set, TCP connections redirected by the firewall are forwarded to
+	// their original destinations through the proxy. It is only supported
+	// on Linux.
+	TransparentProxy *TransparentProxy `protobuf:"bytes,17,opt,name=transparentProxy,proto3,oneof" json:"transparentProxy,omitempty"`
+	// If set, the client captures the IP packets of a TUN device, and
+	// forwards the TCP and UDP traffic through the proxy. It is only
+	// supported on Linux.
+	TunDevice *TUNDevice `protobuf:"bytes,18,opt,name=tunDevice,proto3,oneof" json:"tunDevice,omitempty"`
+	// If set, the socks5 proxy also listens to this unix domain socket.
+	Socks5UnixSocket *UnixSocket `protobuf:"bytes,19,opt,name=socks5UnixSocket,proto3,oneof" json:"socks5UnixSocket,omitempty"`
+	// If set, the HTTP / HTTPS proxy also listens to this unix domain socket.
+	HttpProxyUnixSocket *UnixSocket `protobuf:"bytes,20,opt,name=httpProxyUnixSocket,proto3,oneof" json:"httpProxyUnixSocket,omitempty"`
+	// Additional local socks5 listeners. Each listener can use a different
+	// profile, bypass rules and DNS mode.
+	Socks5Listeners []*Socks5Listener `protobuf:"bytes,21,rep,name=socks5Listeners,proto3" json:"socks5Listeners,omitempty"`
+	// Client log format.
+	LogFormat *LogFormat `protobuf:"varint,22,opt,name=logFormat,proto3,enum=mieru.appctl.LogFormat,oneof" json:"logFormat,omitempty"`
+	// Profiles fetched periodically from HTTPS subscription documents.
+	Subscriptions []*Subscription `protobuf:"bytes,23,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	// Encrypt the passwords and subscription URLs in the stored client config.
+	ConfigEncryption *ConfigEncryption `protobuf:"bytes,24,opt,name=configEncryption,proto3,oneof" json:"configEncryption,omitempty"`
+	// If set, socks5 clients can authenticate with Kerberos using the
+	// GSSAPI authentication method of RFC 1961.
+	Socks5GSSAPI *Socks5GSSAPI `protobuf:"bytes,25,opt,name=socks5GSSAPI,proto3,oneof" json:"socks5GSSAPI,omitempty"`
+	// Version of the client config layout. It is set by mieru when the
+	// config is stored, and used to upgrade a config stored by an older
+	// version of mieru. Don't set it manually.
+	SchemaVersion *uint32 `protobuf:"varint,26,opt,name=schemaVersion,proto3,oneof" json:"schemaVersion,omitempty"`
 }
 
 func (x *ClientConfig) Reset() {
@@ -203,58 +519,1411 @@ func (x *ClientConfig) GetHttpProxyPort() int32 {
 	if x != nil && x.HttpProxyPort != nil {
 		return *x.HttpProxyPort
 	}
-	return 0
+	return 0
+}
+
+func (x *ClientConfig) GetHttpProxyListenLAN() bool {
+	if x != nil && x.HttpProxyListenLAN != nil {
+		return *x.HttpProxyListenLAN
+	}
+	return false
+}
+
+func (x *ClientConfig) GetSocks5Authentication() []*Auth {
+	if x != nil {
+		return x.Socks5Authentication
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetFailover() *ProfileFailover {
+	if x != nil {
+		return x.Failover
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetFairShareBandwidthMbps() int32 {
+	if x != nil && x.FairShareBandwidthMbps != nil {
+		return *x.FairShareBandwidthMbps
+	}
+	return 0
+}
+
+func (x *ClientConfig) GetSocks5UDPSourceFilter() UDPSourceFilter {
+	if x != nil && x.Socks5UDPSourceFilter != nil {
+		return *x.Socks5UDPSourceFilter
+	}
+	return UDPSourceFilter_UDP_SOURCE_FILTER_DEFAULT
+}
+
+func (x *ClientConfig) GetKeepaliveRules() []*KeepaliveRule {
+	if x != nil {
+		return x.KeepaliveRules
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetBypass() *BypassConfig {
+	if x != nil {
+		return x.Bypass
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetGeoDatabases() *GeoDatabases {
+	if x != nil {
+		return x.GeoDatabases
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetTransparentProxy() *TransparentProxy {
+	if x != nil {
+		return x.TransparentProxy
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetTunDevice() *TUNDevice {
+	if x != nil {
+		return x.TunDevice
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetSocks5UnixSocket() *UnixSocket {
+	if x != nil {
+		return x.Socks5UnixSocket
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetHttpProxyUnixSocket() *UnixSocket {
+	if x != nil {
+		return x.HttpProxyUnixSocket
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetSocks5Listeners() []*Socks5Listener {
+	if x != nil {
+		return x.Socks5Listeners
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetLogFormat() LogFormat {
+	if x != nil && x.LogFormat != nil {
+		return *x.LogFormat
+	}
+	return LogFormat_LOG_FORMAT_TEXT
+}
+
+func (x *ClientConfig) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetConfigEncryption() *ConfigEncryption {
+	if x != nil {
+		return x.ConfigEncryption
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetSocks5GSSAPI() *Socks5GSSAPI {
+	if x != nil {
+		return x.Socks5GSSAPI
+	}
+	return nil
+}
+
+func (x *ClientConfig) GetSchemaVersion() uint32 {
+	if x != nil && x.SchemaVersion != nil {
+		return *x.SchemaVersion
+	}
+	return 0
+}
+
+type TransparentProxy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Port of the transparent proxy in localhost. The firewall rules
+	// printed by "mieru get transparent-proxy-rules" redirect TCP
+	// connections to this port.
+	Port *int32 `protobuf:"varint,1,opt,name=port,proto3,oneof" json:"port,omitempty"`
+	// If not empty, only the connections of processes run by these users
+	// are redirected. Each user is a user name or a numeric user ID.
+	Users []string `protobuf:"bytes,2,rep,name=users,proto3" json:"users,omitempty"`
+	// If not empty, only the connections of processes in these cgroups
+	// are redirected. Each cgroup is a path in the cgroup v2 hierarchy,
+	// e.g. "user.slice/user-1000.slice/app-firefox.scope".
+	Cgroups []string `protobuf:"bytes,3,rep,name=cgroups,proto3" json:"cgroups,omitempty"`
+}
+
+func (x *TransparentProxy) Reset() {
+	*x = TransparentProxy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransparentProxy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransparentProxy) ProtoMessage() {}
+
+func (x *TransparentProxy) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransparentProxy.ProtoReflect.Descriptor instead.
+func (*TransparentProxy) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TransparentProxy) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *TransparentProxy) GetUsers() []string {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *TransparentProxy) GetCgroups() []string {
+	if x != nil {
+		return x.Cgroups
+	}
+	return nil
+}
+
+type TUNDevice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the TUN device. If not set, the default name "mieru0" is used.
+	Name *string `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	// IPv4 address and prefix length of the TUN device. The next address
+	// in the prefix is also used by the client, so it must be in the
+	// prefix. If not set, the default value "198.18.0.1/30" is used.
+	Ipv4Address *string `protobuf:"bytes,2,opt,name=ipv4Address,proto3,oneof" json:"ipv4Address,omitempty"`
+	// IPv6 address and prefix length of the TUN device, for example
+	// "fdfe:dcba:9876::1/126". The next address in the prefix is also used
+	// by the client. If not set, IPv6 traffic is not captured and it is
+	// blocked by the routing rules.
+	Ipv6Address *string `protobuf:"bytes,3,opt,name=ipv6Address,proto3,oneof" json:"ipv6Address,omitempty"`
+	// MTU of the TUN device. If not set, the default value 1500 is used.
+	Mtu *int32 `protobuf:"varint,4,opt,name=mtu,proto3,oneof" json:"mtu,omitempty"`
+}
+
+func (x *TUNDevice) Reset() {
+	*x = TUNDevice{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TUNDevice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TUNDevice) ProtoMessage() {}
+
+func (x *TUNDevice) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TUNDevice.ProtoReflect.Descriptor instead.
+func (*TUNDevice) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TUNDevice) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *TUNDevice) GetIpv4Address() string {
+	if x != nil && x.Ipv4Address != nil {
+		return *x.Ipv4Address
+	}
+	return ""
+}
+
+func (x *TUNDevice) GetIpv6Address() string {
+	if x != nil && x.Ipv6Address != nil {
+		return *x.Ipv6Address
+	}
+	return ""
+}
+
+func (x *TUNDevice) GetMtu() int32 {
+	if x != nil && x.Mtu != nil {
+		return *x.Mtu
+	}
+	return 0
+}
+
+type ConfigEncryption struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set to true, the passwords and subscription URLs in the stored
+	// client config are encrypted. They are decrypted when the client
+	// config is loaded.
+	Enabled *bool `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	// Where the encryption key is stored.
+	KeyStore *ConfigKeyStore `protobuf:"varint,2,opt,name=keyStore,proto3,enum=mieru.appctl.ConfigKeyStore,oneof" json:"keyStore,omitempty"`
+	// Salt to derive the encryption key from the passphrase, in hex.
+	// It is generated automatically.
+	PassphraseSalt *string `protobuf:"bytes,3,opt,name=passphraseSalt,proto3,oneof" json:"passphraseSalt,omitempty"`
+}
+
+func (x *ConfigEncryption) Reset() {
+	*x = ConfigEncryption{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigEncryption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigEncryption) ProtoMessage() {}
+
+func (x *ConfigEncryption) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigEncryption.ProtoReflect.Descriptor instead.
+func (*ConfigEncryption) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ConfigEncryption) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *ConfigEncryption) GetKeyStore() ConfigKeyStore {
+	if x != nil && x.KeyStore != nil {
+		return *x.KeyStore
+	}
+	return ConfigKeyStore_CONFIG_KEY_STORE_DEFAULT
+}
+
+func (x *ConfigEncryption) GetPassphraseSalt() string {
+	if x != nil && x.PassphraseSalt != nil {
+		return *x.PassphraseSalt
+	}
+	return ""
+}
+
+type Socks5GSSAPI struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Absolute path of the keytab file that contains the key of
+	// the service principal.
+	KeytabPath *string `protobuf:"bytes,1,opt,name=keytabPath,proto3,oneof" json:"keytabPath,omitempty"`
+	// The service principal of the socks5 proxy without the realm,
+	// for example "rcmd/proxy.example.com". If not set, the service
+	// principal in the ticket is used to find the key in the keytab.
+	ServicePrincipal *string `protobuf:"bytes,2,opt,name=servicePrincipal,proto3,oneof" json:"servicePrincipal,omitempty"`
+	// Client principals that can use the socks5 proxy, for example
+	// "alice@EXAMPLE.COM". If the list is empty, any client principal
+	// with a valid ticket can use the socks5 proxy.
+	AllowedPrincipals []string `protobuf:"bytes,3,rep,name=allowedPrincipals,proto3" json:"allowedPrincipals,omitempty"`
+	// If set, socks5 clients must protect the proxied TCP stream with
+	// GSSAPI integrity tokens. Otherwise, the traffic after
+	// authentication is not protected.
+	RequireIntegrity *bool `protobuf:"varint,4,opt,name=requireIntegrity,proto3,oneof" json:"requireIntegrity,omitempty"`
+}
+
+func (x *Socks5GSSAPI) Reset() {
+	*x = Socks5GSSAPI{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Socks5GSSAPI) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Socks5GSSAPI) ProtoMessage() {}
+
+func (x *Socks5GSSAPI) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Socks5GSSAPI.ProtoReflect.Descriptor instead.
+func (*Socks5GSSAPI) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Socks5GSSAPI) GetKeytabPath() string {
+	if x != nil && x.KeytabPath != nil {
+		return *x.KeytabPath
+	}
+	return ""
+}
+
+func (x *Socks5GSSAPI) GetServicePrincipal() string {
+	if x != nil && x.ServicePrincipal != nil {
+		return *x.ServicePrincipal
+	}
+	return ""
+}
+
+func (x *Socks5GSSAPI) GetAllowedPrincipals() []string {
+	if x != nil {
+		return x.AllowedPrincipals
+	}
+	return nil
+}
+
+func (x *Socks5GSSAPI) GetRequireIntegrity() bool {
+	if x != nil && x.RequireIntegrity != nil {
+		return *x.RequireIntegrity
+	}
+	return false
+}
+
+type Subscription struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the subscription. Profiles from the subscription are named
+	// "<subscription name>/<profile name>" in the client config.
+	Name *string `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	// HTTPS URL of the subscription document.
+	Url *string `protobuf:"bytes,2,opt,name=url,proto3,oneof" json:"url,omitempty"`
+	// The interval to fetch the subscription document.
+	// Examples: 30m, 6h, 24h. It can't be less than 5m.
+	// If not set, the default interval 6h is used.
+	RefreshInterval *string `protobuf:"bytes,3,opt,name=refreshInterval,proto3,oneof" json:"refreshInterval,omitempty"`
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Subscription) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Subscription) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
+}
+
+func (x *Subscription) GetRefreshInterval() string {
+	if x != nil && x.RefreshInterval != nil {
+		return *x.RefreshInterval
+	}
+	return ""
+}
+
+// SubscriptionDocument is the JSON document returned by a subscription URL.
+type SubscriptionDocument struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Client profiles provided by the subscription.
+	Profiles []*ClientProfile `protobuf:"bytes,1,rep,name=profiles,proto3" json:"profiles,omitempty"`
+}
+
+func (x *SubscriptionDocument) Reset() {
+	*x = SubscriptionDocument{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscriptionDocument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionDocument) ProtoMessage() {}
+
+func (x *SubscriptionDocument) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionDocument.ProtoReflect.Descriptor instead.
+func (*SubscriptionDocument) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubscriptionDocument) GetProfiles() []*ClientProfile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+type Socks5Listener struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Local port number of the socks5 listener.
+	Port *int32 `protobuf:"varint,1,opt,name=port,proto3,oneof" json:"port,omitempty"`
+	// If set to true, the listener accepts connections from LAN.
+	ListenLAN *bool `protobuf:"varint,2,opt,name=listenLAN,proto3,oneof" json:"listenLAN,omitempty"`
+	// Name of the profile used by the listener. If unset, the active
+	// profile is used, and the listener follows profile failover.
+	Profile *string `protobuf:"bytes,3,opt,name=profile,proto3,oneof" json:"profile,omitempty"`
+	// Destinations that are connected directly instead of through
+	// the proxy tunnel. If unset, the bypass rules of the client are used.
+	Bypass *BypassConfig `protobuf:"bytes,4,opt,name=bypass,proto3,oneof" json:"bypass,omitempty"`
+	// How domain names of the requests are resolved.
+	DnsMode *DNSMode `protobuf:"varint,5,opt,name=dnsMode,proto3,enum=mieru.appctl.DNSMode,oneof" json:"dnsMode,omitempty"`
+	// If set to true, connect to the destination directly when
+	// the proxy servers of the profile are not reachable.
+	DirectFallback *bool `protobuf:"varint,6,opt,name=directFallback,proto3,oneof" json:"directFallback,omitempty"`
+}
+
+func (x *Socks5Listener) Reset() {
+	*x = Socks5Listener{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Socks5Listener) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Socks5Listener) ProtoMessage() {}
+
+func (x *Socks5Listener) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Socks5Listener.ProtoReflect.Descriptor instead.
+func (*Socks5Listener) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Socks5Listener) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *Socks5Listener) GetListenLAN() bool {
+	if x != nil && x.ListenLAN != nil {
+		return *x.ListenLAN
+	}
+	return false
+}
+
+func (x *Socks5Listener) GetProfile() string {
+	if x != nil && x.Profile != nil {
+		return *x.Profile
+	}
+	return ""
+}
+
+func (x *Socks5Listener) GetBypass() *BypassConfig {
+	if x != nil {
+		return x.Bypass
+	}
+	return nil
+}
+
+func (x *Socks5Listener) GetDnsMode() DNSMode {
+	if x != nil && x.DnsMode != nil {
+		return *x.DnsMode
+	}
+	return DNSMode_DNS_MODE_DEFAULT
+}
+
+func (x *Socks5Listener) GetDirectFallback() bool {
+	if x != nil && x.DirectFallback != nil {
+		return *x.DirectFallback
+	}
+	return false
+}
+
+type UnixSocket struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Absolute path of the unix domain socket.
+	Path *string `protobuf:"bytes,1,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	// File permission of the unix domain socket in octal, e.g. "0660".
+	// If unset, the default value "0600" is used.
+	Mode *string `protobuf:"bytes,2,opt,name=mode,proto3,oneof" json:"mode,omitempty"`
+}
+
+func (x *UnixSocket) Reset() {
+	*x = UnixSocket{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UnixSocket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnixSocket) ProtoMessage() {}
+
+func (x *UnixSocket) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnixSocket.ProtoReflect.Descriptor instead.
+func (*UnixSocket) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UnixSocket) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *UnixSocket) GetMode() string {
+	if x != nil && x.Mode != nil {
+		return *x.Mode
+	}
+	return ""
+}
+
+type BypassConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Inline bypass rules. Each rule is a domain name like "example.com",
+	// "domain:example.com" or "full:example.com", or an IP address or
+	// CIDR range like "192.168.0.0/16" or "ip:10.0.0.0/8", or a country
+	// like "country:CN", or a geosite category like "category:cn".
+	Rules []string `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	// Absolute paths of files that contain bypass rules, one rule per line.
+	// Lines starting with "#" are comments.
+	RuleFiles []string `protobuf:"bytes,2,rep,name=ruleFiles,proto3" json:"ruleFiles,omitempty"`
+}
+
+func (x *BypassConfig) Reset() {
+	*x = BypassConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BypassConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BypassConfig) ProtoMessage() {}
+
+func (x *BypassConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BypassConfig.ProtoReflect.Descriptor instead.
+func (*BypassConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BypassConfig) GetRules() []string {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *BypassConfig) GetRuleFiles() []string {
+	if x != nil {
+		return x.RuleFiles
+	}
+	return nil
+}
+
+type KeepaliveRule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Destination ports that the rule applies to. Each item is a single
+	// port like "993", or a port range like "8000-9000".
+	// If empty, the rule applies to all the destination ports.
+	DestinationPorts []string `protobuf:"bytes,1,rep,name=destinationPorts,proto3" json:"destinationPorts,omitempty"`
+	// Send a keepalive after the connection is idle for this duration.
+	// Examples: 30s, 5m. It must not be less than 1 second.
+	Interval *string `protobuf:"bytes,2,opt,name=interval,proto3,oneof" json:"interval,omitempty"`
+}
+
+func (x *KeepaliveRule) Reset() {
+	*x = KeepaliveRule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeepaliveRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeepaliveRule) ProtoMessage() {}
+
+func (x *KeepaliveRule) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeepaliveRule.ProtoReflect.Descriptor instead.
+func (*KeepaliveRule) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *KeepaliveRule) GetDestinationPorts() []string {
+	if x != nil {
+		return x.DestinationPorts
+	}
+	return nil
+}
+
+func (x *KeepaliveRule) GetInterval() string {
+	if x != nil && x.Interval != nil {
+		return *x.Interval
+	}
+	return ""
+}
+
+type ProfileFailover struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Names of the backup profiles, from the highest priority to the lowest.
+	// The active profile always has the highest priority.
+	BackupProfiles []string `protobuf:"bytes,1,rep,name=backupProfiles,proto3" json:"backupProfiles,omitempty"`
+	// Number of consecutive tunnel failures before switching to the next profile.
+	// If not set, the default value 3 is used.
+	MaxFailures *int32 `protobuf:"varint,2,opt,name=maxFailures,proto3,oneof" json:"maxFailures,omitempty"`
+	// The interval to check if a profile with higher priority is healthy again.
+	// Examples: 30s, 5m, 2h.
+	// If not set, the default interval 1m is used.
+	HealthCheckInterval *string `protobuf:"bytes,3,opt,name=healthCheckInterval,proto3,oneof" json:"healthCheckInterval,omitempty"`
+}
+
+func (x *ProfileFailover) Reset() {
+	*x = ProfileFailover{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProfileFailover) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileFailover) ProtoMessage() {}
+
+func (x *ProfileFailover) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileFailover.ProtoReflect.Descriptor instead.
+func (*ProfileFailover) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ProfileFailover) GetBackupProfiles() []string {
+	if x != nil {
+		return x.BackupProfiles
+	}
+	return nil
+}
+
+func (x *ProfileFailover) GetMaxFailures() int32 {
+	if x != nil && x.MaxFailures != nil {
+		return *x.MaxFailures
+	}
+	return 0
+}
+
+func (x *ProfileFailover) GetHealthCheckInterval() string {
+	if x != nil && x.HealthCheckInterval != nil {
+		return *x.HealthCheckInterval
+	}
+	return ""
+}
+
+type ClientProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Client profile name.
+	ProfileName *string `protobuf:"bytes,1,opt,name=profileName,proto3,oneof" json:"profileName,omitempty"`
+	// User information.
+	User *User `protobuf:"bytes,2,opt,name=user,proto3,oneof" json:"user,omitempty"`
+	// A list of servers to connect.
+	Servers []*ServerEndpoint `protobuf:"bytes,3,rep,name=servers,proto3" json:"servers,omitempty"`
+	// Maximum transmission unit of L2 payload.
+	// This setting only applies to UDP protocol egress traffic.
+	Mtu *int32 `protobuf:"varint,4,opt,name=mtu,proto3,oneof" json:"mtu,omitempty"`
+	// Multiplexing behaviors.
+	Multiplexing *MultiplexingConfig `protobuf:"bytes,5,opt,name=multiplexing,proto3,oneof" json:"multiplexing,omitempty"`
+	// If set, resolve the domain names of proxy servers with this
+	// DNS over HTTPS server instead of the resolver of operating system.
+	// The host of the URL must be an IP address.
+	// Example: https://1.1.1.1/dns-query
+	BootstrapDoHURL *string `protobuf:"bytes,6,opt,name=bootstrapDoHURL,proto3,oneof" json:"bootstrapDoHURL,omitempty"`
+	// If set, connect to proxy servers through this pluggable transport.
+	// Only TCP port bindings are supported.
+	TransportPlugin *TransportPlugin `protobuf:"bytes,7,opt,name=transportPlugin,proto3,oneof" json:"transportPlugin,omitempty"`
+	// If set, search the path MTU to proxy servers when using UDP protocol.
+	// The search starts from 1280 and the mtu value is the upper bound.
+	PathMTUDiscovery *bool `protobuf:"varint,8,opt,name=pathMTUDiscovery,proto3,oneof" json:"pathMTUDiscovery,omitempty"`
+	// If set, send UDP protocol traffic over multiple network interfaces.
+	Multipath *MultipathConfig `protobuf:"bytes,9,opt,name=multipath,proto3,oneof" json:"multipath,omitempty"`
+	// Rotate the keys derived from the user password periodically.
+	// It must be the same as the setting of proxy servers.
+	KeyRotation *KeyRotationConfig `protobuf:"bytes,10,opt,name=keyRotation,proto3,oneof" json:"keyRotation,omitempty"`
+	// If set, connect to proxy servers through this upstream proxy,
+	// for example when only a corporate proxy can reach the Internet.
+	// Only TCP port bindings are supported.
+	UpstreamProxy *UpstreamProxy `protobuf:"bytes,11,opt,name=upstreamProxy,proto3,oneof" json:"upstreamProxy,omitempty"`
+	// Name of the local network interface to connect to proxy servers,
+	// e.g. "eth1". On Linux, the connections are bound to the interface.
+	// On other operating systems, an IP address of the interface is used.
+	// This can't be used together with multipath.
+	BindInterface *string `protobuf:"bytes,12,opt,name=bindInterface,proto3,oneof" json:"bindInterface,omitempty"`
+	// Local IP address to connect to proxy servers.
+	// This can't be used together with multipath.
+	BindIP *string `protobuf:"bytes,13,opt,name=bindIP,proto3,oneof" json:"bindIP,omitempty"`
+	// Firewall mark (SO_MARK) of the connections to proxy servers,
+	// to be matched by policy routing rules. It is only supported on
+	// Linux. This can't be used together with multipath.
+	RoutingMark *uint32 `protobuf:"varint,14,opt,name=routingMark,proto3,oneof" json:"routingMark,omitempty"`
+	// Heartbeats and dead peer detection of the proxy tunnel.
+	Heartbeat *HeartbeatConfig `protobuf:"bytes,15,opt,name=heartbeat,proto3,oneof" json:"heartbeat,omitempty"`
+	// If set, retry to establish the proxy tunnel when it is broken,
+	// instead of failing the pending requests immediately.
+	Reconnect *ReconnectConfig `protobuf:"bytes,16,opt,name=reconnect,proto3,oneof" json:"reconnect,omitempty"`
+	// If set, connect to proxy servers through this chain of SIP003
+	// plugins. The first plugin is the closest to mieru. The proxy servers
+	// must use the same chain. Only TCP port bindings are supported.
+	Sip003Plugins []*SIP003Plugin `protobuf:"bytes,17,rep,name=sip003Plugins,proto3" json:"sip003Plugins,omitempty"`
+	// If set to true, each TCP or WEBSOCKET connection to the proxy server
+	// starts with a hybrid X25519 + ML-KEM-768 key exchange, and the traffic
+	// is encrypted with a key derived from both the password and the
+	// exchanged secret. Only TCP and WEBSOCKET port bindings are supported.
+	HybridKeyExchange *bool `protobuf:"varint,18,opt,name=hybridKeyExchange,proto3,oneof" json:"hybridKeyExchange,omitempty"`
+}
+
+func (x *ClientProfile) Reset() {
+	*x = ClientProfile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientProfile) ProtoMessage() {}
+
+func (x *ClientProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientProfile.ProtoReflect.Descriptor instead.
+func (*ClientProfile) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClientProfile) GetProfileName() string {
+	if x != nil && x.ProfileName != nil {
+		return *x.ProfileName
+	}
+	return ""
+}
+
+func (x *ClientProfile) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetServers() []*ServerEndpoint {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetMtu() int32 {
+	if x != nil && x.Mtu != nil {
+		return *x.Mtu
+	}
+	return 0
+}
+
+func (x *ClientProfile) GetMultiplexing() *MultiplexingConfig {
+	if x != nil {
+		return x.Multiplexing
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetBootstrapDoHURL() string {
+	if x != nil && x.BootstrapDoHURL != nil {
+		return *x.BootstrapDoHURL
+	}
+	return ""
+}
+
+func (x *ClientProfile) GetTransportPlugin() *TransportPlugin {
+	if x != nil {
+		return x.TransportPlugin
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetPathMTUDiscovery() bool {
+	if x != nil && x.PathMTUDiscovery != nil {
+		return *x.PathMTUDiscovery
+	}
+	return false
+}
+
+func (x *ClientProfile) GetMultipath() *MultipathConfig {
+	if x != nil {
+		return x.Multipath
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetKeyRotation() *KeyRotationConfig {
+	if x != nil {
+		return x.KeyRotation
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetUpstreamProxy() *UpstreamProxy {
+	if x != nil {
+		return x.UpstreamProxy
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetBindInterface() string {
+	if x != nil && x.BindInterface != nil {
+		return *x.BindInterface
+	}
+	return ""
+}
+
+func (x *ClientProfile) GetBindIP() string {
+	if x != nil && x.BindIP != nil {
+		return *x.BindIP
+	}
+	return ""
+}
+
+func (x *ClientProfile) GetRoutingMark() uint32 {
+	if x != nil && x.RoutingMark != nil {
+		return *x.RoutingMark
+	}
+	return 0
+}
+
+func (x *ClientProfile) GetHeartbeat() *HeartbeatConfig {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetReconnect() *ReconnectConfig {
+	if x != nil {
+		return x.Reconnect
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetSip003Plugins() []*SIP003Plugin {
+	if x != nil {
+		return x.Sip003Plugins
+	}
+	return nil
+}
+
+func (x *ClientProfile) GetHybridKeyExchange() bool {
+	if x != nil && x.HybridKeyExchange != nil {
+		return *x.HybridKeyExchange
+	}
+	return false
+}
+
+type ReconnectConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Delay before the first retry. Examples: 200ms, 1s.
+	// If not set, the default delay 500ms is used.
+	InitialDelay *string `protobuf:"bytes,1,opt,name=initialDelay,proto3,oneof" json:"initialDelay,omitempty"`
+	// The delay doubles after each consecutive failure, up to this value.
+	// The actual delay is randomized between half of it and itself.
+	// Examples: 5s, 30s. If not set, the default value 10s is used.
+	MaxDelay *string `protobuf:"bytes,2,opt,name=maxDelay,proto3,oneof" json:"maxDelay,omitempty"`
+	// Maximum time a pending request waits for the proxy tunnel.
+	// Examples: 30s, 2m. If not set, the default value 30s is used.
+	MaxWait *string `protobuf:"bytes,3,opt,name=maxWait,proto3,oneof" json:"maxWait,omitempty"`
+}
+
+func (x *ReconnectConfig) Reset() {
+	*x = ReconnectConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReconnectConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReconnectConfig) ProtoMessage() {}
+
+func (x *ReconnectConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReconnectConfig.ProtoReflect.Descriptor instead.
+func (*ReconnectConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReconnectConfig) GetInitialDelay() string {
+	if x != nil && x.InitialDelay != nil {
+		return *x.InitialDelay
+	}
+	return ""
+}
+
+func (x *ReconnectConfig) GetMaxDelay() string {
+	if x != nil && x.MaxDelay != nil {
+		return *x.MaxDelay
+	}
+	return ""
+}
+
+func (x *ReconnectConfig) GetMaxWait() string {
+	if x != nil && x.MaxWait != nil {
+		return *x.MaxWait
+	}
+	return ""
+}
+
+type HeartbeatConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Send a heartbeat after the tunnel has sent nothing for this duration.
+	// With UDP protocol, a session sends an ACK packet as heartbeat.
+	// With TCP protocol, this is the TCP keepalive interval.
+	// Examples: 2s, 15s. It must be in range [1s, 30s].
+	// If not set, the default interval 5s is used with UDP protocol, and
+	// the default TCP keepalive interval is used with TCP protocol.
+	Interval *string `protobuf:"bytes,1,opt,name=interval,proto3,oneof" json:"interval,omitempty"`
+	// Close the tunnel if nothing is received from the proxy server for
+	// this duration. With UDP protocol, this applies to each session.
+	// With TCP protocol, this is the TCP user timeout, which is only
+	// supported on Linux. Examples: 20s, 2m. It must not be less than 10s,
+	// and it must be larger than the interval.
+	// If not set, the default timeout 1m is used with UDP protocol.
+	DeadPeerTimeout *string `protobuf:"bytes,2,opt,name=deadPeerTimeout,proto3,oneof" json:"deadPeerTimeout,omitempty"`
+}
+
+func (x *HeartbeatConfig) Reset() {
+	*x = HeartbeatConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HeartbeatConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatConfig) ProtoMessage() {}
+
+func (x *HeartbeatConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatConfig.ProtoReflect.Descriptor instead.
+func (*HeartbeatConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HeartbeatConfig) GetInterval() string {
+	if x != nil && x.Interval != nil {
+		return *x.Interval
+	}
+	return ""
+}
+
+func (x *HeartbeatConfig) GetDeadPeerTimeout() string {
+	if x != nil && x.DeadPeerTimeout != nil {
+		return *x.DeadPeerTimeout
+	}
+	return ""
+}
+
+type UpstreamProxy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Protocol *UpstreamProxyProtocol `protobuf:"varint,1,opt,name=protocol,proto3,enum=mieru.appctl.UpstreamProxyProtocol,oneof" json:"protocol,omitempty"`
+	// Address of the upstream proxy in "host:port" format.
+	// Example: 10.0.0.1:8080
+	Address *string `protobuf:"bytes,2,opt,name=address,proto3,oneof" json:"address,omitempty"`
+	// User name and password to authenticate with the upstream proxy.
+	// If unset, authentication is not used.
+	Auth *Auth `protobuf:"bytes,3,opt,name=auth,proto3,oneof" json:"auth,omitempty"`
+}
+
+func (x *UpstreamProxy) Reset() {
+	*x = UpstreamProxy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpstreamProxy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpstreamProxy) ProtoMessage() {}
+
+func (x *UpstreamProxy) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpstreamProxy.ProtoReflect.Descriptor instead.
+func (*UpstreamProxy) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpstreamProxy) GetProtocol() UpstreamProxyProtocol {
+	if x != nil && x.Protocol != nil {
+		return *x.Protocol
+	}
+	return UpstreamProxyProtocol_UNKNOWN_UPSTREAM_PROXY_PROTOCOL
+}
+
+func (x *UpstreamProxy) GetAddress() string {
+	if x != nil && x.Address != nil {
+		return *x.Address
+	}
+	return ""
+}
+
+func (x *UpstreamProxy) GetAuth() *Auth {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+type MultipathConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Names of the local network interfaces, e.g. "wlan0", "eth0".
+	// UDP packets to proxy servers are sent over these interfaces in turn.
+	Interfaces []string `protobuf:"bytes,1,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+}
+
+func (x *MultipathConfig) Reset() {
+	*x = MultipathConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MultipathConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MultipathConfig) ProtoMessage() {}
+
+func (x *MultipathConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (x *ClientConfig) GetHttpProxyListenLAN() bool {
-	if x != nil && x.HttpProxyListenLAN != nil {
-		return *x.HttpProxyListenLAN
-	}
-	return false
+// Deprecated: Use MultipathConfig.ProtoReflect.Descriptor instead.
+func (*MultipathConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *ClientConfig) GetSocks5Authentication() []*Auth {
+func (x *MultipathConfig) GetInterfaces() []string {
 	if x != nil {
-		return x.Socks5Authentication
+		return x.Interfaces
 	}
 	return nil
 }
 
-type ClientProfile struct {
+type TransportPlugin struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Client profile name.
-	ProfileName *string `protobuf:"bytes,1,opt,name=profileName,proto3,oneof" json:"profileName,omitempty"`
-	// User information.
-	User *User `protobuf:"bytes,2,opt,name=user,proto3,oneof" json:"user,omitempty"`
-	// A list of servers to connect.
-	Servers []*ServerEndpoint `protobuf:"bytes,3,rep,name=servers,proto3" json:"servers,omitempty"`
-	// Maximum transmission unit of L2 payload.
-	// This setting only applies to UDP protocol egress traffic.
-	Mtu *int32 `protobuf:"varint,4,opt,name=mtu,proto3,oneof" json:"mtu,omitempty"`
-	// Multiplexing behaviors.
-	Multiplexing *MultiplexingConfig `protobuf:"bytes,5,opt,name=multiplexing,proto3,oneof" json:"multiplexing,omitempty"`
+	// Name of the transport provided by the plugin, e.g. "obfs4".
+	Name *string `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	// Path of the plugin executable.
+	Command *string `protobuf:"bytes,2,opt,name=command,proto3,oneof" json:"command,omitempty"`
+	// Command line arguments of the plugin executable.
+	Args []string `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+	// Per-connection arguments sent to the plugin, in "key=value" format.
+	// Example: "cert=AAAA", "iat-mode=0".
+	Options []string `protobuf:"bytes,4,rep,name=options,proto3" json:"options,omitempty"`
+	// Directory where the plugin can store its persistent state.
+	// If unset, a directory in the system temporary directory is used.
+	StateDirectory *string `protobuf:"bytes,5,opt,name=stateDirectory,proto3,oneof" json:"stateDirectory,omitempty"`
 }
 
-func (x *ClientProfile) Reset() {
-	*x = ClientProfile{}
+func (x *TransportPlugin) Reset() {
+	*x = TransportPlugin{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_clientcfg_proto_msgTypes[1]
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ClientProfile) String() string {
+func (x *TransportPlugin) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClientProfile) ProtoMessage() {}
+func (*TransportPlugin) ProtoMessage() {}
 
-func (x *ClientProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_clientcfg_proto_msgTypes[1]
+func (x *TransportPlugin) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -265,44 +1934,44 @@ func (x *ClientProfile) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClientProfile.ProtoReflect.Descriptor instead.
-func (*ClientProfile) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use TransportPlugin.ProtoReflect.Descriptor instead.
+func (*TransportPlugin) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *ClientProfile) GetProfileName() string {
-	if x != nil && x.ProfileName != nil {
-		return *x.ProfileName
+func (x *TransportPlugin) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
 	}
 	return ""
 }
 
-func (x *ClientProfile) GetUser() *User {
-	if x != nil {
-		return x.User
+func (x *TransportPlugin) GetCommand() string {
+	if x != nil && x.Command != nil {
+		return *x.Command
 	}
-	return nil
+	return ""
 }
 
-func (x *ClientProfile) GetServers() []*ServerEndpoint {
+func (x *TransportPlugin) GetArgs() []string {
 	if x != nil {
-		return x.Servers
+		return x.Args
 	}
 	return nil
 }
 
-func (x *ClientProfile) GetMtu() int32 {
-	if x != nil && x.Mtu != nil {
-		return *x.Mtu
+func (x *TransportPlugin) GetOptions() []string {
+	if x != nil {
+		return x.Options
 	}
-	return 0
+	return nil
 }
 
-func (x *ClientProfile) GetMultiplexing() *MultiplexingConfig {
-	if x != nil {
-		return x.Multiplexing
+func (x *TransportPlugin) GetStateDirectory() string {
+	if x != nil && x.StateDirectory != nil {
+		return *x.StateDirectory
 	}
-	return nil
+	return ""
 }
 
 type MultiplexingConfig struct {
@@ -312,12 +1981,17 @@ type MultiplexingConfig struct {
 
 	// How frequent a network connection is reused.
 	Level *MultiplexingLevel `protobuf:"varint,1,opt,name=level,proto3,enum=mieru.appctl.MultiplexingLevel,oneof" json:"level,omitempty"`
+	// Maximum number of network connections to proxy servers.
+	// When this number is reached, new sessions always reuse the existing
+	// connection with the fewest sessions, regardless of the level.
+	// 0 means unlimited.
+	MaxConnections *int32 `protobuf:"varint,2,opt,name=maxConnections,proto3,oneof" json:"maxConnections,omitempty"`
 }
 
 func (x *MultiplexingConfig) Reset() {
 	*x = MultiplexingConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_clientcfg_proto_msgTypes[2]
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -330,7 +2004,7 @@ func (x *MultiplexingConfig) String() string {
 func (*MultiplexingConfig) ProtoMessage() {}
 
 func (x *MultiplexingConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_clientcfg_proto_msgTypes[2]
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -343,7 +2017,7 @@ func (x *MultiplexingConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MultiplexingConfig.ProtoReflect.Descriptor instead.
 func (*MultiplexingConfig) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{2}
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *MultiplexingConfig) GetLevel() MultiplexingLevel {
@@ -353,6 +2027,13 @@ func (x *MultiplexingConfig) GetLevel() MultiplexingLevel {
 	return MultiplexingLevel_MULTIPLEXING_DEFAULT
 }
 
+func (x *MultiplexingConfig) GetMaxConnections() int32 {
+	if x != nil && x.MaxConnections != nil {
+		return *x.MaxConnections
+	}
+	return 0
+}
+
 type ClientAdvancedSettings struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -364,12 +2045,37 @@ type ClientAdvancedSettings struct {
 	// Examples: 30s, 5m, 2h.
 	// If empty, the default interval is used.
 	MetricsLoggingInterval *string `protobuf:"bytes,2,opt,name=metricsLoggingInterval,proto3,oneof" json:"metricsLoggingInterval,omitempty"`
+	// If set, collect the traffic of each destination in the last 24 hours.
+	// The statistics can be displayed by "mieru describe traffic" command.
+	CollectDestinationTraffic *bool `protobuf:"varint,3,opt,name=collectDestinationTraffic,proto3,oneof" json:"collectDestinationTraffic,omitempty"`
+	// If set, export traces of socks5 requests to this OpenTelemetry
+	// collector endpoint using OTLP/HTTP protocol.
+	// Example: http://127.0.0.1:4318
+	OtlpTraceEndpoint *string `protobuf:"bytes,4,opt,name=otlpTraceEndpoint,proto3,oneof" json:"otlpTraceEndpoint,omitempty"`
+	// If set, measure the latency of proxy servers periodically, and
+	// create new connections to the proxy server with the lowest latency.
+	SelectServerByLatency *bool `protobuf:"varint,5,opt,name=selectServerByLatency,proto3,oneof" json:"selectServerByLatency,omitempty"`
+	// This field has been deprecated, has no effect, and will be removed.
+	// The client always waits for the proxy server to connect to the
+	// destination before it replies to the socks5 connection request.
+	ZeroRTT *bool `protobuf:"varint,6,opt,name=zeroRTT,proto3,oneof" json:"zeroRTT,omitempty"`
+	// If set, watch the network interfaces and IP addresses of the system.
+	// When the network is changed, for example from Wi-Fi to a mobile
+	// hotspot, close the connections to proxy servers established on the
+	// previous network, so new requests use the current network.
+	DetectNetworkChange *bool `protobuf:"varint,7,opt,name=detectNetworkChange,proto3,oneof" json:"detectNetworkChange,omitempty"`
+	// The release channel used to check update and update the client.
+	UpdateChannel *UpdateChannel `protobuf:"varint,8,opt,name=updateChannel,proto3,enum=mieru.appctl.UpdateChannel,oneof" json:"updateChannel,omitempty"`
+	// Limits of the socks5 listeners. The handshake is finished when
+	// the socks5 request is accepted and data transfer starts.
+	// This takes effect after the client restarts.
+	AcceptLimit *AcceptLimit `protobuf:"bytes,9,opt,name=acceptLimit,proto3,oneof" json:"acceptLimit,omitempty"`
 }
 
 func (x *ClientAdvancedSettings) Reset() {
 	*x = ClientAdvancedSettings{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_clientcfg_proto_msgTypes[3]
+		mi := &file_appctl_proto_clientcfg_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -382,7 +2088,7 @@ func (x *ClientAdvancedSettings) String() string {
 func (*ClientAdvancedSettings) ProtoMessage() {}
 
 func (x *ClientAdvancedSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_clientcfg_proto_msgTypes[3]
+	mi := &file_appctl_proto_clientcfg_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -395,7 +2101,7 @@ func (x *ClientAdvancedSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ClientAdvancedSettings.ProtoReflect.Descriptor instead.
 func (*ClientAdvancedSettings) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{3}
+	return file_appctl_proto_clientcfg_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ClientAdvancedSettings) GetNoCheckUpdate() bool {
@@ -412,6 +2118,55 @@ func (x *ClientAdvancedSettings) GetMetricsLoggingInterval() string {
 	return ""
 }
 
+func (x *ClientAdvancedSettings) GetCollectDestinationTraffic() bool {
+	if x != nil && x.CollectDestinationTraffic != nil {
+		return *x.CollectDestinationTraffic
+	}
+	return false
+}
+
+func (x *ClientAdvancedSettings) GetOtlpTraceEndpoint() string {
+	if x != nil && x.OtlpTraceEndpoint != nil {
+		return *x.OtlpTraceEndpoint
+	}
+	return ""
+}
+
+func (x *ClientAdvancedSettings) GetSelectServerByLatency() bool {
+	if x != nil && x.SelectServerByLatency != nil {
+		return *x.SelectServerByLatency
+	}
+	return false
+}
+
+func (x *ClientAdvancedSettings) GetZeroRTT() bool {
+	if x != nil && x.ZeroRTT != nil {
+		return *x.ZeroRTT
+	}
+	return false
+}
+
+func (x *ClientAdvancedSettings) GetDetectNetworkChange() bool {
+	if x != nil && x.DetectNetworkChange != nil {
+		return *x.DetectNetworkChange
+	}
+	return false
+}
+
+func (x *ClientAdvancedSettings) GetUpdateChannel() UpdateChannel {
+	if x != nil && x.UpdateChannel != nil {
+		return *x.UpdateChannel
+	}
+	return UpdateChannel_UPDATE_CHANNEL_STABLE
+}
+
+func (x *ClientAdvancedSettings) GetAcceptLimit() *AcceptLimit {
+	if x != nil {
+		return x.AcceptLimit
+	}
+	return nil
+}
+
 var File_appctl_proto_clientcfg_proto protoreflect.FileDescriptor
 
 var file_appctl_proto_clientcfg_proto_rawDesc = []byte{
@@ -419,7 +2174,7 @@ var file_appctl_proto_clientcfg_proto_rawDesc = []byte{
 	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x63, 0x66, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c,
 	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x1a, 0x17, 0x61, 0x70,
 	0x70, 0x63, 0x74, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x61, 0x73, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb9, 0x05, 0x0a, 0x0c, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf7, 0x0f, 0x0a, 0x0c, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74,
 	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x37, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c,
 	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
 	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x50, 0x72,
@@ -454,64 +2209,435 @@ var file_appctl_proto_clientcfg_proto_rawDesc = []byte{
 	0x6e, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
 	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x14, 0x73, 0x6f, 0x63,
 	0x6b, 0x73, 0x35, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x50, 0x72, 0x6f, 0x66,
-	0x69, 0x6c, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x72, 0x70, 0x63, 0x50, 0x6f, 0x72, 0x74, 0x42,
-	0x0d, 0x0a, 0x0b, 0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x13,
-	0x0a, 0x11, 0x5f, 0x61, 0x64, 0x76, 0x61, 0x6e, 0x63, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69,
-	0x6e, 0x67, 0x73, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x4c,
-	0x65, 0x76, 0x65, 0x6c, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x4c,
-	0x69, 0x73, 0x74, 0x65, 0x6e, 0x4c, 0x41, 0x4e, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x68, 0x74, 0x74,
-	0x70, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x68,
-	0x74, 0x74, 0x70, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x4c, 0x41,
-	0x4e, 0x22, 0xaf, 0x02, 0x0a, 0x0d, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x66,
-	0x69, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x66,
-	0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x04, 0x75, 0x73,
-	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
-	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x48, 0x01, 0x52, 0x04,
-	0x75, 0x73, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x36, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
-	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x6e,
-	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12,
-	0x15, 0x0a, 0x03, 0x6d, 0x74, 0x75, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x03,
-	0x6d, 0x74, 0x75, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a, 0x0c, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70,
-	0x6c, 0x65, 0x78, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6d,
-	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x75, 0x6c, 0x74,
-	0x69, 0x70, 0x6c, 0x65, 0x78, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x03,
-	0x52, 0x0c, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x78, 0x69, 0x6e, 0x67, 0x88, 0x01,
-	0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d,
-	0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6d,
-	0x74, 0x75, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x78,
-	0x69, 0x6e, 0x67, 0x22, 0x5a, 0x0a, 0x12, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x78,
-	0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x3a, 0x0a, 0x05, 0x6c, 0x65, 0x76,
-	0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x6e, 0x12, 0x3e, 0x0a, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x6f, 0x76, 0x65, 0x72, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x6f, 0x76,
+	0x65, 0x72, 0x48, 0x08, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x6f, 0x76, 0x65, 0x72, 0x88, 0x01,
+	0x01, 0x12, 0x3b, 0x0a, 0x16, 0x66, 0x61, 0x69, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x42, 0x61,
+	0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4d, 0x62, 0x70, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x09, 0x52, 0x16, 0x66, 0x61, 0x69, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x42, 0x61,
+	0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4d, 0x62, 0x70, 0x73, 0x88, 0x01, 0x01, 0x12, 0x58,
+	0x0a, 0x15, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x55, 0x44, 0x50, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e,
+	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x44, 0x50,
+	0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x48, 0x0a, 0x52, 0x15,
+	0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x55, 0x44, 0x50, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x43, 0x0a, 0x0e, 0x6b, 0x65, 0x65, 0x70,
+	0x61, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
+	0x4b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0e, 0x6b,
+	0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x37, 0x0a,
+	0x06, 0x62, 0x79, 0x70, 0x61, 0x73, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x42, 0x79, 0x70,
+	0x61, 0x73, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x0b, 0x52, 0x06, 0x62, 0x79, 0x70,
+	0x61, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x43, 0x0a, 0x0c, 0x67, 0x65, 0x6f, 0x44, 0x61, 0x74,
+	0x61, 0x62, 0x61, 0x73, 0x65, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x47, 0x65, 0x6f, 0x44,
+	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x73, 0x48, 0x0c, 0x52, 0x0c, 0x67, 0x65, 0x6f, 0x44,
+	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x4f, 0x0a, 0x10, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74,
+	0x50, 0x72, 0x6f, 0x78, 0x79, 0x48, 0x0d, 0x52, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a, 0x09,
+	0x74, 0x75, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54,
+	0x55, 0x4e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x48, 0x0e, 0x52, 0x09, 0x74, 0x75, 0x6e, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a, 0x10, 0x73, 0x6f, 0x63, 0x6b,
+	0x73, 0x35, 0x55, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74,
+	0x6c, 0x2e, 0x55, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x48, 0x0f, 0x52, 0x10,
+	0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x55, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x4f, 0x0a, 0x13, 0x68, 0x74, 0x74, 0x70, 0x50, 0x72, 0x6f, 0x78, 0x79,
+	0x55, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
+	0x55, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x48, 0x10, 0x52, 0x13, 0x68, 0x74,
+	0x74, 0x70, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65,
+	0x74, 0x88, 0x01, 0x01, 0x12, 0x46, 0x0a, 0x0f, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x4c, 0x69,
+	0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x73, 0x18, 0x15, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x6f, 0x63,
+	0x6b, 0x73, 0x35, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x52, 0x0f, 0x73, 0x6f, 0x63,
+	0x6b, 0x73, 0x35, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x73, 0x12, 0x3a, 0x0a, 0x09,
+	0x6c, 0x6f, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x17, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4c,
+	0x6f, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x48, 0x11, 0x52, 0x09, 0x6c, 0x6f, 0x67, 0x46,
+	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x0d, 0x73, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x17, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x73, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x4f, 0x0a, 0x10, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x18,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70,
+	0x63, 0x74, 0x6c, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x12, 0x52, 0x10, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x45, 0x6e,
+	0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x43, 0x0a, 0x0c, 0x73,
+	0x6f, 0x63, 0x6b, 0x73, 0x35, 0x47, 0x53, 0x53, 0x41, 0x50, 0x49, 0x18, 0x19, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
+	0x2e, 0x53, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x47, 0x53, 0x53, 0x41, 0x50, 0x49, 0x48, 0x13, 0x52,
+	0x0c, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x47, 0x53, 0x53, 0x41, 0x50, 0x49, 0x88, 0x01, 0x01,
+	0x12, 0x29, 0x0a, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x14, 0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d,
+	0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x0a, 0x0a,
+	0x08, 0x5f, 0x72, 0x70, 0x63, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x73, 0x6f,
+	0x63, 0x6b, 0x73, 0x35, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x61, 0x64, 0x76,
+	0x61, 0x6e, 0x63, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x42, 0x0f, 0x0a,
+	0x0d, 0x5f, 0x6c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x42, 0x12,
+	0x0a, 0x10, 0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x4c,
+	0x41, 0x4e, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x68, 0x74, 0x74, 0x70, 0x50, 0x72, 0x6f, 0x78, 0x79,
+	0x50, 0x6f, 0x72, 0x74, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x68, 0x74, 0x74, 0x70, 0x50, 0x72, 0x6f,
+	0x78, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x4c, 0x41, 0x4e, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x66, 0x61, 0x69, 0x6c, 0x6f, 0x76, 0x65, 0x72, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x66, 0x61, 0x69,
+	0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4d,
+	0x62, 0x70, 0x73, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x55, 0x44,
+	0x50, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x42, 0x09, 0x0a,
+	0x07, 0x5f, 0x62, 0x79, 0x70, 0x61, 0x73, 0x73, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x67, 0x65, 0x6f,
+	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x73, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x42, 0x0c,
+	0x0a, 0x0a, 0x5f, 0x74, 0x75, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x42, 0x13, 0x0a, 0x11,
+	0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x55, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65,
+	0x74, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x68, 0x74, 0x74, 0x70, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x55,
+	0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6c, 0x6f,
+	0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0f, 0x0a, 0x0d,
+	0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x47, 0x53, 0x53, 0x41, 0x50, 0x49, 0x42, 0x10, 0x0a,
+	0x0e, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22,
+	0x64, 0x0a, 0x10, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x72,
+	0x6f, 0x78, 0x79, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x00, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05,
+	0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x75, 0x73, 0x65,
+	0x72, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x70, 0x6f, 0x72, 0x74, 0x22, 0xba, 0x01, 0x0a, 0x09, 0x54, 0x55, 0x4e, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b,
+	0x69, 0x70, 0x76, 0x34, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x01, 0x52, 0x0b, 0x69, 0x70, 0x76, 0x34, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x69, 0x70, 0x76, 0x36, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0b, 0x69, 0x70, 0x76, 0x36,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x6d, 0x74,
+	0x75, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x03, 0x6d, 0x74, 0x75, 0x88, 0x01,
+	0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x69,
+	0x70, 0x76, 0x34, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x69,
+	0x70, 0x76, 0x36, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6d,
+	0x74, 0x75, 0x22, 0xc9, 0x01, 0x0a, 0x10, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x45, 0x6e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4b, 0x65,
+	0x79, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x48, 0x01, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x53, 0x74, 0x6f,
+	0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x70, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72,
+	0x61, 0x73, 0x65, 0x53, 0x61, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x0e, 0x70, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x53, 0x61, 0x6c, 0x74, 0x88,
+	0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x6b, 0x65, 0x79, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f,
+	0x70, 0x61, 0x73, 0x73, 0x70, 0x68, 0x72, 0x61, 0x73, 0x65, 0x53, 0x61, 0x6c, 0x74, 0x22, 0xfc,
+	0x01, 0x0a, 0x0c, 0x53, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x47, 0x53, 0x53, 0x41, 0x50, 0x49, 0x12,
+	0x23, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x74, 0x61, 0x62, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x6b, 0x65, 0x79, 0x74, 0x61, 0x62, 0x50, 0x61, 0x74,
+	0x68, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x10, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x50,
+	0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01,
+	0x52, 0x10, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70,
+	0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64,
+	0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70,
+	0x61, 0x6c, 0x73, 0x12, 0x2f, 0x0a, 0x10, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x49, 0x6e,
+	0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52,
+	0x10, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74,
+	0x79, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6b, 0x65, 0x79, 0x74, 0x61, 0x62, 0x50,
+	0x61, 0x74, 0x68, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x50,
+	0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x72, 0x65, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x22, 0x92, 0x01,
+	0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x17,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2d,
+	0x0a, 0x0f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0f, 0x72, 0x65, 0x66, 0x72, 0x65,
+	0x73, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x75, 0x72, 0x6c, 0x42, 0x12,
+	0x0a, 0x10, 0x5f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x22, 0x4f, 0x0a, 0x14, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x08, 0x70, 0x72,
+	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x22, 0xd4, 0x02, 0x0a, 0x0e, 0x53, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x4c, 0x69,
+	0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x21, 0x0a, 0x09, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x4c, 0x41, 0x4e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x01, 0x52, 0x09, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x4c, 0x41, 0x4e, 0x88,
+	0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x37, 0x0a, 0x06, 0x62, 0x79, 0x70, 0x61, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
+	0x2e, 0x42, 0x79, 0x70, 0x61, 0x73, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x03, 0x52,
+	0x06, 0x62, 0x79, 0x70, 0x61, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x07, 0x64, 0x6e,
+	0x73, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x6d, 0x69,
+	0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x44, 0x4e, 0x53, 0x4d, 0x6f,
+	0x64, 0x65, 0x48, 0x04, 0x52, 0x07, 0x64, 0x6e, 0x73, 0x4d, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x2b, 0x0a, 0x0e, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x46, 0x61, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x05, 0x52, 0x0e, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x46, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x65,
+	0x6e, 0x4c, 0x41, 0x4e, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62, 0x79, 0x70, 0x61, 0x73, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x5f,
+	0x64, 0x6e, 0x73, 0x4d, 0x6f, 0x64, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x46, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x22, 0x50, 0x0a, 0x0a, 0x55, 0x6e,
+	0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01,
+	0x01, 0x12, 0x17, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x01, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70,
+	0x61, 0x74, 0x68, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x42, 0x0a, 0x0c,
+	0x42, 0x79, 0x70, 0x61, 0x73, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x14, 0x0a, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75, 0x6c,
+	0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x75, 0x6c, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x72, 0x75, 0x6c, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x22, 0x69, 0x0a, 0x0d, 0x4b, 0x65, 0x65, 0x70, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x52, 0x75, 0x6c,
+	0x65, 0x12, 0x2a, 0x0a, 0x10, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x50, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x64, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x1f, 0x0a,
+	0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0xbf, 0x01, 0x0a, 0x0f,
+	0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x6f, 0x76, 0x65, 0x72, 0x12,
+	0x26, 0x0a, 0x0e, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x46, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0b,
+	0x6d, 0x61, 0x78, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x35,
+	0x0a, 0x13, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x13, 0x68,
+	0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x46, 0x61, 0x69,
+	0x6c, 0x75, 0x72, 0x65, 0x73, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0xe3, 0x09,
+	0x0a, 0x0d, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12,
+	0x25, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70,
+	0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x48, 0x01, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x88, 0x01, 0x01, 0x12, 0x36, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70,
+	0x63, 0x74, 0x6c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x15, 0x0a, 0x03, 0x6d,
+	0x74, 0x75, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x03, 0x6d, 0x74, 0x75, 0x88,
+	0x01, 0x01, 0x12, 0x49, 0x0a, 0x0c, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x78, 0x69,
+	0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
 	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65,
-	0x78, 0x69, 0x6e, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x65, 0x76,
-	0x65, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22,
-	0xad, 0x01, 0x0a, 0x16, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x41, 0x64, 0x76, 0x61, 0x6e, 0x63,
-	0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x29, 0x0a, 0x0d, 0x6e, 0x6f,
-	0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x08, 0x48, 0x00, 0x52, 0x0d, 0x6e, 0x6f, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x16, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
-	0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x16, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
-	0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88,
-	0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6e, 0x6f, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
-	0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x2a,
-	0x89, 0x01, 0x0a, 0x11, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x78, 0x69, 0x6e, 0x67,
-	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x14, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c,
-	0x45, 0x58, 0x49, 0x4e, 0x47, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12,
-	0x14, 0x0a, 0x10, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c, 0x45, 0x58, 0x49, 0x4e, 0x47, 0x5f,
-	0x4f, 0x46, 0x46, 0x10, 0x01, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c,
-	0x45, 0x58, 0x49, 0x4e, 0x47, 0x5f, 0x4c, 0x4f, 0x57, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x4d,
-	0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c, 0x45, 0x58, 0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x49, 0x44, 0x44,
-	0x4c, 0x45, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c, 0x45,
-	0x58, 0x49, 0x4e, 0x47, 0x5f, 0x48, 0x49, 0x47, 0x48, 0x10, 0x04, 0x42, 0x30, 0x5a, 0x2e, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6e, 0x66, 0x65, 0x69, 0x6e,
-	0x2f, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2f, 0x76, 0x33, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70,
-	0x70, 0x63, 0x74, 0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x70, 0x62, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x78, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x03, 0x52, 0x0c, 0x6d, 0x75,
+	0x6c, 0x74, 0x69, 0x70, 0x6c, 0x65, 0x78, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a,
+	0x0f, 0x62, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x44, 0x6f, 0x48, 0x55, 0x52, 0x4c,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x0f, 0x62, 0x6f, 0x6f, 0x74, 0x73, 0x74,
+	0x72, 0x61, 0x70, 0x44, 0x6f, 0x48, 0x55, 0x52, 0x4c, 0x88, 0x01, 0x01, 0x12, 0x4c, 0x0a, 0x0f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x48, 0x05, 0x52, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x10, 0x70, 0x61,
+	0x74, 0x68, 0x4d, 0x54, 0x55, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x06, 0x52, 0x10, 0x70, 0x61, 0x74, 0x68, 0x4d, 0x54, 0x55, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x09, 0x6d,
+	0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x74, 0x68, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x75,
+	0x6c, 0x74, 0x69, 0x70, 0x61, 0x74, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x07, 0x52,
+	0x09, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x46, 0x0a,
+	0x0b, 0x6b, 0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74,
+	0x6c, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x48, 0x08, 0x52, 0x0b, 0x6b, 0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x46, 0x0a, 0x0d, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x70, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x48, 0x09, 0x52, 0x0d, 0x75, 0x70, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a,
+	0x0d, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x0a, 0x52, 0x0d, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x64,
+	0x49, 0x50, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x48, 0x0b, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x64,
+	0x49, 0x50, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67,
+	0x4d, 0x61, 0x72, 0x6b, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x0c, 0x52, 0x0b, 0x72, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x72, 0x6b, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x09,
+	0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x48,
+	0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x0d,
+	0x52, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x88, 0x01, 0x01, 0x12, 0x40,
+	0x0a, 0x09, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
+	0x2e, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x48, 0x0e, 0x52, 0x09, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x88, 0x01, 0x01,
+	0x12, 0x40, 0x0a, 0x0d, 0x73, 0x69, 0x70, 0x30, 0x30, 0x33, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
+	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x49, 0x50, 0x30, 0x30, 0x33, 0x50, 0x6c, 0x75,
+	0x67, 0x69, 0x6e, 0x52, 0x0d, 0x73, 0x69, 0x70, 0x30, 0x30, 0x33, 0x50, 0x6c, 0x75, 0x67, 0x69,
+	0x6e, 0x73, 0x12, 0x31, 0x0a, 0x11, 0x68, 0x79, 0x62, 0x72, 0x69, 0x64, 0x4b, 0x65, 0x79, 0x45,
+	0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x48, 0x0f, 0x52,
+	0x11, 0x68, 0x79, 0x62, 0x72, 0x69, 0x64, 0x4b, 0x65, 0x79, 0x45, 0x78, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x42, 0x06,
+	0x0a, 0x04, 0x5f, 0x6d, 0x74, 0x75, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x75, 0x6c, 0x74, 0x69,
+	0x70, 0x6c, 0x65, 0x78, 0x69, 0x6e, 0x67, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x62, 0x6f, 0x6f, 0x74,
+	0x73, 0x74, 0x72, 0x61, 0x70, 0x44, 0x6f, 0x48, 0x55, 0x52, 0x4c, 0x42, 0x12, 0x0a, 0x10, 0x5f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x42,
+	0x13, 0x0a, 0x11, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x4d, 0x54, 0x55, 0x44, 0x69, 0x73, 0x63, 0x6f,
+	0x76, 0x65, 0x72, 0x79, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61,
+	0x74, 0x68, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6b, 0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50,
+	0x72, 0x6f, 0x78, 0x79, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62, 0x69, 0x6e, 0x64, 0x49,
+	0x50, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x72,
+	0x6b, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x42,
+	0x0c, 0x0a, 0x0a, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x42, 0x14, 0x0a,
+	0x12, 0x5f, 0x68, 0x79, 0x62, 0x72, 0x69, 0x64, 0x4b, 0x65, 0x79, 0x45, 0x78, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x22, 0xa4, 0x01, 0x0a, 0x0f, 0x52, 0x65, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x27, 0x0a, 0x0c, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x61, 0x6c, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x0c, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x88, 0x01, 0x01,
+	0x12, 0x1f, 0x0a, 0x08, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x88, 0x01,
+	0x01, 0x12, 0x1d, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x57, 0x61, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x02, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x57, 0x61, 0x69, 0x74, 0x88, 0x01, 0x01,
+	0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x44, 0x65, 0x6c, 0x61,
+	0x79, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x42, 0x0a,
+	0x0a, 0x08, 0x5f, 0x6d, 0x61, 0x78, 0x57, 0x61, 0x69, 0x74, 0x22, 0x82, 0x01, 0x0a, 0x0f, 0x48,
+	0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1f,
+	0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12,
+	0x2d, 0x0a, 0x0f, 0x64, 0x65, 0x61, 0x64, 0x50, 0x65, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x6f,
+	0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0f, 0x64, 0x65, 0x61, 0x64,
+	0x50, 0x65, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x88, 0x01, 0x01, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x12, 0x0a, 0x10, 0x5f,
+	0x64, 0x65, 0x61, 0x64, 0x50, 0x65, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22,
+	0xc3, 0x01, 0x0a, 0x0d, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50, 0x72, 0x6f, 0x78,
+	0x79, 0x12, 0x44, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2e, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50, 0x72, 0x6f, 0x78, 0x79,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x48, 0x00, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x04, 0x61, 0x75, 0x74, 0x68, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70,
+	0x63, 0x74, 0x6c, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x48, 0x02, 0x52, 0x04, 0x61, 0x75, 0x74, 0x68,
+	0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x61, 0x75, 0x74, 0x68, 0x22, 0x31, 0x0a, 0x0f, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x61,
+	0x74, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x22, 0xcc, 0x01, 0x0a, 0x0f, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12, 0x17, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x2b, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0e, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x88, 0x01, 0x01, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x9a, 0x01, 0x0a, 0x12, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x70, 0x6c, 0x65, 0x78, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x3a,
+	0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e,
+	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x75, 0x6c,
+	0x74, 0x69, 0x70, 0x6c, 0x65, 0x78, 0x69, 0x6e, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x48, 0x00,
+	0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x6d, 0x61,
+	0x78, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x01, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x65, 0x76, 0x65,
+	0x6c, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0xd2, 0x05, 0x0a, 0x16, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x41,
+	0x64, 0x76, 0x61, 0x6e, 0x63, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12,
+	0x29, 0x0a, 0x0d, 0x6e, 0x6f, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0d, 0x6e, 0x6f, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x16, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x16, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x41, 0x0a, 0x19, 0x63, 0x6f, 0x6c, 0x6c, 0x65,
+	0x63, 0x74, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61,
+	0x66, 0x66, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x19, 0x63, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x11, 0x6f, 0x74,
+	0x6c, 0x70, 0x54, 0x72, 0x61, 0x63, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x11, 0x6f, 0x74, 0x6c, 0x70, 0x54, 0x72, 0x61,
+	0x63, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x39, 0x0a,
+	0x15, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x42, 0x79, 0x4c,
+	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x15,
+	0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x42, 0x79, 0x4c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x7a, 0x65, 0x72, 0x6f,
+	0x52, 0x54, 0x54, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x05, 0x52, 0x07, 0x7a, 0x65, 0x72,
+	0x6f, 0x52, 0x54, 0x54, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a, 0x13, 0x64, 0x65, 0x74, 0x65, 0x63,
+	0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x06, 0x52, 0x13, 0x64, 0x65, 0x74, 0x65, 0x63, 0x74, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x46,
+	0x0a, 0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x48, 0x07, 0x52, 0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x69,
+	0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70,
+	0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x48, 0x08, 0x52, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6e, 0x6f, 0x43,
+	0x68, 0x65, 0x63, 0x6b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x6d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x1c, 0x0a, 0x1a, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x66,
+	0x66, 0x69, 0x63, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x6f, 0x74, 0x6c, 0x70, 0x54, 0x72, 0x61, 0x63,
+	0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x73, 0x65,
+	0x6c, 0x65, 0x63, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x42, 0x79, 0x4c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x7a, 0x65, 0x72, 0x6f, 0x52, 0x54, 0x54, 0x42,
+	0x16, 0x0a, 0x14, 0x5f, 0x64, 0x65, 0x74, 0x65, 0x63, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x61, 0x63,
+	0x63, 0x65, 0x70, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x2a, 0x71, 0x0a, 0x0e, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x4b, 0x65, 0x79, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x1c, 0x0a, 0x18, 0x43,
+	0x4f, 0x4e, 0x46, 0x49, 0x47, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x5f,
+	0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x20, 0x0a, 0x1c, 0x43, 0x4f, 0x4e,
+	0x46, 0x49, 0x47, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x5f, 0x4f, 0x53,
+	0x5f, 0x4b, 0x45, 0x59, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x43,
+	0x4f, 0x4e, 0x46, 0x49, 0x47, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x53, 0x54, 0x4f, 0x52, 0x45, 0x5f,
+	0x50, 0x41, 0x53, 0x53, 0x50, 0x48, 0x52, 0x41, 0x53, 0x45, 0x10, 0x02, 0x2a, 0x48, 0x0a, 0x07,
+	0x44, 0x4e, 0x53, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x4e, 0x53, 0x5f, 0x4d,
+	0x4f, 0x44, 0x45, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x13, 0x0a,
+	0x0f, 0x44, 0x4e, 0x53, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x52, 0x45, 0x4d, 0x4f, 0x54, 0x45,
+	0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x44, 0x4e, 0x53, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4c,
+	0x4f, 0x43, 0x41, 0x4c, 0x10, 0x02, 0x2a, 0x88, 0x01, 0x0a, 0x0f, 0x55, 0x44, 0x50, 0x53, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x19, 0x55, 0x44,
+	0x50, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x4c, 0x54, 0x45, 0x52, 0x5f,
+	0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x55, 0x44, 0x50,
+	0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x4c, 0x54, 0x45, 0x52, 0x5f, 0x53,
+	0x54, 0x52, 0x49, 0x43, 0x54, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x55, 0x44, 0x50, 0x5f, 0x53,
+	0x4f, 0x55, 0x52, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x4c, 0x54, 0x45, 0x52, 0x5f, 0x53, 0x41, 0x4d,
+	0x45, 0x5f, 0x49, 0x50, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x55, 0x44, 0x50, 0x5f, 0x53, 0x4f,
+	0x55, 0x52, 0x43, 0x45, 0x5f, 0x46, 0x49, 0x4c, 0x54, 0x45, 0x52, 0x5f, 0x4f, 0x46, 0x46, 0x10,
+	0x03, 0x2a, 0x64, 0x0a, 0x15, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x50, 0x72, 0x6f,
+	0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x23, 0x0a, 0x1f, 0x55, 0x4e,
+	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x55, 0x50, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x50,
+	0x52, 0x4f, 0x58, 0x59, 0x5f, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x10, 0x00, 0x12,
+	0x13, 0x0a, 0x0f, 0x55, 0x50, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x5f, 0x53, 0x4f, 0x43, 0x4b,
+	0x53, 0x35, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x55, 0x50, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d,
+	0x5f, 0x48, 0x54, 0x54, 0x50, 0x10, 0x02, 0x2a, 0x89, 0x01, 0x0a, 0x11, 0x4d, 0x75, 0x6c, 0x74,
+	0x69, 0x70, 0x6c, 0x65, 0x78, 0x69, 0x6e, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x18, 0x0a,
+	0x14, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c, 0x45, 0x58, 0x49, 0x4e, 0x47, 0x5f, 0x44, 0x45,
+	0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x55, 0x4c, 0x54, 0x49,
+	0x50, 0x4c, 0x45, 0x58, 0x49, 0x4e, 0x47, 0x5f, 0x4f, 0x46, 0x46, 0x10, 0x01, 0x12, 0x14, 0x0a,
+	0x10, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c, 0x45, 0x58, 0x49, 0x4e, 0x47, 0x5f, 0x4c, 0x4f,
+	0x57, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c, 0x45, 0x58,
+	0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x49, 0x44, 0x44, 0x4c, 0x45, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11,
+	0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c, 0x45, 0x58, 0x49, 0x4e, 0x47, 0x5f, 0x48, 0x49, 0x47,
+	0x48, 0x10, 0x04, 0x2a, 0x43, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x19, 0x0a, 0x15, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x43,
+	0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x53, 0x54, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x00, 0x12,
+	0x17, 0x0a, 0x13, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45,
+	0x4c, 0x5f, 0x42, 0x45, 0x54, 0x41, 0x10, 0x01, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6e, 0x66, 0x65, 0x69, 0x6e, 0x2f, 0x6d, 0x69,
+	0x65, 0x72, 0x75, 0x2f, 0x76, 0x33, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74,
+	0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -526,33 +2652,88 @@ func file_appctl_proto_clientcfg_proto_rawDescGZIP() []byte {
 	return file_appctl_proto_clientcfg_proto_rawDescData
 }
 
-var file_appctl_proto_clientcfg_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_appctl_proto_clientcfg_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_appctl_proto_clientcfg_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_appctl_proto_clientcfg_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_appctl_proto_clientcfg_proto_goTypes = []interface{}{
-	(MultiplexingLevel)(0),         // 0: mieru.appctl.MultiplexingLevel
-	(*ClientConfig)(nil),           // 1: mieru.appctl.ClientConfig
-	(*ClientProfile)(nil),          // 2: mieru.appctl.ClientProfile
-	(*MultiplexingConfig)(nil),     // 3: mieru.appctl.MultiplexingConfig
-	(*ClientAdvancedSettings)(nil), // 4: mieru.appctl.ClientAdvancedSettings
-	(LoggingLevel)(0),              // 5: mieru.appctl.LoggingLevel
-	(*Auth)(nil),                   // 6: mieru.appctl.Auth
-	(*User)(nil),                   // 7: mieru.appctl.User
-	(*ServerEndpoint)(nil),         // 8: mieru.appctl.ServerEndpoint
+	(ConfigKeyStore)(0),            // 0: mieru.appctl.ConfigKeyStore
+	(DNSMode)(0),                   // 1: mieru.appctl.DNSMode
+	(UDPSourceFilter)(0),           // 2: mieru.appctl.UDPSourceFilter
+	(UpstreamProxyProtocol)(0),     // 3: mieru.appctl.UpstreamProxyProtocol
+	(MultiplexingLevel)(0),         // 4: mieru.appctl.MultiplexingLevel
+	(UpdateChannel)(0),             // 5: mieru.appctl.UpdateChannel
+	(*ClientConfig)(nil),           // 6: mieru.appctl.ClientConfig
+	(*TransparentProxy)(nil),       // 7: mieru.appctl.TransparentProxy
+	(*TUNDevice)(nil),              // 8: mieru.appctl.TUNDevice
+	(*ConfigEncryption)(nil),       // 9: mieru.appctl.ConfigEncryption
+	(*Socks5GSSAPI)(nil),           // 10: mieru.appctl.Socks5GSSAPI
+	(*Subscription)(nil),           // 11: mieru.appctl.Subscription
+	(*SubscriptionDocument)(nil),   // 12: mieru.appctl.SubscriptionDocument
+	(*Socks5Listener)(nil),         // 13: mieru.appctl.Socks5Listener
+	(*UnixSocket)(nil),             // 14: mieru.appctl.UnixSocket
+	(*BypassConfig)(nil),           // 15: mieru.appctl.BypassConfig
+	(*KeepaliveRule)(nil),          // 16: mieru.appctl.KeepaliveRule
+	(*ProfileFailover)(nil),        // 17: mieru.appctl.ProfileFailover
+	(*ClientProfile)(nil),          // 18: mieru.appctl.ClientProfile
+	(*ReconnectConfig)(nil),        // 19: mieru.appctl.ReconnectConfig
+	(*HeartbeatConfig)(nil),        // 20: mieru.appctl.HeartbeatConfig
+	(*UpstreamProxy)(nil),          // 21: mieru.appctl.UpstreamProxy
+	(*MultipathConfig)(nil),        // 22: mieru.appctl.MultipathConfig
+	(*TransportPlugin)(nil),        // 23: mieru.appctl.TransportPlugin
+	(*MultiplexingConfig)(nil),     // 24: mieru.appctl.MultiplexingConfig
+	(*ClientAdvancedSettings)(nil), // 25: mieru.appctl.ClientAdvancedSettings
+	(LoggingLevel)(0),              // 26: mieru.appctl.LoggingLevel
+	(*Auth)(nil),                   // 27: mieru.appctl.Auth
+	(*GeoDatabases)(nil),           // 28: mieru.appctl.GeoDatabases
+	(LogFormat)(0),                 // 29: mieru.appctl.LogFormat
+	(*User)(nil),                   // 30: mieru.appctl.User
+	(*ServerEndpoint)(nil),         // 31: mieru.appctl.ServerEndpoint
+	(*KeyRotationConfig)(nil),      // 32: mieru.appctl.KeyRotationConfig
+	(*SIP003Plugin)(nil),           // 33: mieru.appctl.SIP003Plugin
+	(*AcceptLimit)(nil),            // 34: mieru.appctl.AcceptLimit
 }
 var file_appctl_proto_clientcfg_proto_depIdxs = []int32{
-	2, // 0: mieru.appctl.ClientConfig.profiles:type_name -> mieru.appctl.ClientProfile
-	4, // 1: mieru.appctl.ClientConfig.advancedSettings:type_name -> mieru.appctl.ClientAdvancedSettings
-	5, // 2: mieru.appctl.ClientConfig.loggingLevel:type_name -> mieru.appctl.LoggingLevel
-	6, // 3: mieru.appctl.ClientConfig.socks5Authentication:type_name -> mieru.appctl.Auth
-	7, // 4: mieru.appctl.ClientProfile.user:type_name -> mieru.appctl.User
-	8, // 5: mieru.appctl.ClientProfile.servers:type_name -> mieru.appctl.ServerEndpoint
-	3, // 6: mieru.appctl.ClientProfile.multiplexing:type_name -> mieru.appctl.MultiplexingConfig
-	0, // 7: mieru.appctl.MultiplexingConfig.level:type_name -> mieru.appctl.MultiplexingLevel
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	18, // 0: mieru.appctl.ClientConfig.profiles:type_name -> mieru.appctl.ClientProfile
+	25, // 1: mieru.appctl.ClientConfig.advancedSettings:type_name -> mieru.appctl.ClientAdvancedSettings
+	26, // 2: mieru.appctl.ClientConfig.loggingLevel:type_name -> mieru.appctl.LoggingLevel
+	27, // 3: mieru.appctl.ClientConfig.socks5Authentication:type_name -> mieru.appctl.Auth
+	17, // 4: mieru.appctl.ClientConfig.failover:type_name -> mieru.appctl.ProfileFailover
+	2,  // 5: mieru.appctl.ClientConfig.socks5UDPSourceFilter:type_name -> mieru.appctl.UDPSourceFilter
+	16, // 6: mieru.appctl.ClientConfig.keepaliveRules:type_name -> mieru.appctl.KeepaliveRule
+	15, // 7: mieru.appctl.ClientConfig.bypass:type_name -> mieru.appctl.BypassConfig
+	28, // 8: mieru.appctl.ClientConfig.geoDatabases:type_name -> mieru.appctl.GeoDatabases
+	7,  // 9: mieru.appctl.ClientConfig.transparentProxy:type_name -> mieru.appctl.TransparentProxy
+	8,  // 10: mieru.appctl.ClientConfig.tunDevice:type_name -> mieru.appctl.TUNDevice
+	14, // 11: mieru.appctl.ClientConfig.socks5UnixSocket:type_name -> mieru.appctl.UnixSocket
+	14, // 12: mieru.appctl.ClientConfig.httpProxyUnixSocket:type_name -> mieru.appctl.UnixSocket
+	13, // 13: mieru.appctl.ClientConfig.socks5Listeners:type_name -> mieru.appctl.Socks5Listener
+	29, // 14: mieru.appctl.ClientConfig.logFormat:type_name -> mieru.appctl.LogFormat
+	11, // 15: mieru.appctl.ClientConfig.subscriptions:type_name -> mieru.appctl.Subscription
+	9,  // 16: mieru.appctl.ClientConfig.configEncryption:type_name -> mieru.appctl.ConfigEncryption
+	10, // 17: mieru.appctl.ClientConfig.socks5GSSAPI:type_name -> mieru.appctl.Socks5GSSAPI
+	0,  // 18: mieru.appctl.ConfigEncryption.keyStore:type_name -> mieru.appctl.ConfigKeyStore
+	18, // 19: mieru.appctl.SubscriptionDocument.profiles:type_name -> mieru.appctl.ClientProfile
+	15, // 20: mieru.appctl.Socks5Listener.bypass:type_name -> mieru.appctl.BypassConfig
+	1,  // 21: mieru.appctl.Socks5Listener.dnsMode:type_name -> mieru.appctl.DNSMode
+	30, // 22: mieru.appctl.ClientProfile.user:type_name -> mieru.appctl.User
+	31, // 23: mieru.appctl.ClientProfile.servers:type_name -> mieru.appctl.ServerEndpoint
+	24, // 24: mieru.appctl.ClientProfile.multiplexing:type_name -> mieru.appctl.MultiplexingConfig
+	23, // 25: mieru.appctl.ClientProfile.transportPlugin:type_name -> mieru.appctl.TransportPlugin
+	22, // 26: mieru.appctl.ClientProfile.multipath:type_name -> mieru.appctl.MultipathConfig
+	32, // 27: mieru.appctl.ClientProfile.keyRotation:type_name -> mieru.appctl.KeyRotationConfig
+	21, // 28: mieru.appctl.ClientProfile.upstreamProxy:type_name -> mieru.appctl.UpstreamProxy
+	20, // 29: mieru.appctl.ClientProfile.heartbeat:type_name -> mieru.appctl.HeartbeatConfig
+	19, // 30: mieru.appctl.ClientProfile.reconnect:type_name -> mieru.appctl.ReconnectConfig
+	33, // 31: mieru.appctl.ClientProfile.sip003Plugins:type_name -> mieru.appctl.SIP003Plugin
+	3,  // 32: mieru.appctl.UpstreamProxy.protocol:type_name -> mieru.appctl.UpstreamProxyProtocol
+	27, // 33: mieru.appctl.UpstreamProxy.auth:type_name -> mieru.appctl.Auth
+	4,  // 34: mieru.appctl.MultiplexingConfig.level:type_name -> mieru.appctl.MultiplexingLevel
+	5,  // 35: mieru.appctl.ClientAdvancedSettings.updateChannel:type_name -> mieru.appctl.UpdateChannel
+	34, // 36: mieru.appctl.ClientAdvancedSettings.acceptLimit:type_name -> mieru.appctl.AcceptLimit
+	37, // [37:37] is the sub-list for method output_type
+	37, // [37:37] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
 }
 
 func init() { file_appctl_proto_clientcfg_proto_init() }
@@ -575,7 +2756,7 @@ func file_appctl_proto_clientcfg_proto_init() {
 			}
 		}
 		file_appctl_proto_clientcfg_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ClientProfile); i {
+			switch v := v.(*TransparentProxy); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -587,7 +2768,7 @@ func file_appctl_proto_clientcfg_proto_init() {
 			}
 		}
 		file_appctl_proto_clientcfg_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MultiplexingConfig); i {
+			switch v := v.(*TUNDevice); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -599,6 +2780,198 @@ func file_appctl_proto_clientcfg_proto_init() {
 			}
 		}
 		file_appctl_proto_clientcfg_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigEncryption); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Socks5GSSAPI); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Subscription); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscriptionDocument); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Socks5Listener); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UnixSocket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BypassConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeepaliveRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProfileFailover); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientProfile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReconnectConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HeartbeatConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpstreamProxy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultipathConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransportPlugin); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MultiplexingConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_clientcfg_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ClientAdvancedSettings); i {
 			case 0:
 				return &v.state
@@ -615,13 +2988,26 @@ func file_appctl_proto_clientcfg_proto_init() {
 	file_appctl_proto_clientcfg_proto_msgTypes[1].OneofWrappers = []interface{}{}
 	file_appctl_proto_clientcfg_proto_msgTypes[2].OneofWrappers = []interface{}{}
 	file_appctl_proto_clientcfg_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[4].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[5].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[14].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[15].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[17].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[18].OneofWrappers = []interface{}{}
+	file_appctl_proto_clientcfg_proto_msgTypes[19].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_appctl_proto_clientcfg_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   4,
+			NumEnums:      6,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -37,6 +37,62 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type PortBindingState int32
+
+const (
+	PortBindingState_UNKNOWN_PORT_BINDING_STATE PortBindingState = 0
+	// The server is creating the listening sockets.
+	PortBindingState_PORT_BINDING_STARTING PortBindingState = 1
+	// All the transport protocols are listening.
+	PortBindingState_PORT_BINDING_LISTENING PortBindingState = 2
+	// At least one transport protocol is unable to listen,
+	// and all the transport protocols are stopped.
+	PortBindingState_PORT_BINDING_FAILED PortBindingState = 3
+)
+
+// Enum value maps for PortBindingState.
+var (
+	PortBindingState_name = map[int32]string{
+		0: "UNKNOWN_PORT_BINDING_STATE",
+		1: "PORT_BINDING_STARTING",
+		2: "PORT_BINDING_LISTENING",
+		3: "PORT_BINDING_FAILED",
+	}
+	PortBindingState_value = map[string]int32{
+		"UNKNOWN_PORT_BINDING_STATE": 0,
+		"PORT_BINDING_STARTING":      1,
+		"PORT_BINDING_LISTENING":     2,
+		"PORT_BINDING_FAILED":        3,
+	}
+)
+
+func (x PortBindingState) Enum() *PortBindingState {
+	p := new(PortBindingState)
+	*p = x
+	return p
+}
+
+func (x PortBindingState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PortBindingState) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_misc_proto_enumTypes[0].Descriptor()
+}
+
+func (PortBindingState) Type() protoreflect.EnumType {
+	return &file_appctl_proto_misc_proto_enumTypes[0]
+}
+
+func (x PortBindingState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PortBindingState.Descriptor instead.
+func (PortBindingState) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{0}
+}
+
 type Metrics struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -189,6 +245,157 @@ func (x *UserWithMetricsList) GetItems() []*UserWithMetrics {
 	return nil
 }
 
+type UserNameList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+}
+
+func (x *UserNameList) Reset() {
+	*x = UserNameList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserNameList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserNameList) ProtoMessage() {}
+
+func (x *UserNameList) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserNameList.ProtoReflect.Descriptor instead.
+func (*UserNameList) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UserNameList) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type UserGroupWithMetrics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// User group information.
+	Group *UserGroup `protobuf:"bytes,1,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	// User group runtime information.
+	Metrics []*metricspb.Metric `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
+}
+
+func (x *UserGroupWithMetrics) Reset() {
+	*x = UserGroupWithMetrics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserGroupWithMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserGroupWithMetrics) ProtoMessage() {}
+
+func (x *UserGroupWithMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserGroupWithMetrics.ProtoReflect.Descriptor instead.
+func (*UserGroupWithMetrics) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UserGroupWithMetrics) GetGroup() *UserGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+func (x *UserGroupWithMetrics) GetMetrics() []*metricspb.Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type UserGroupWithMetricsList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*UserGroupWithMetrics `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *UserGroupWithMetricsList) Reset() {
+	*x = UserGroupWithMetricsList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserGroupWithMetricsList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserGroupWithMetricsList) ProtoMessage() {}
+
+func (x *UserGroupWithMetricsList) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserGroupWithMetricsList.ProtoReflect.Descriptor instead.
+func (*UserGroupWithMetricsList) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UserGroupWithMetricsList) GetItems() []*UserGroupWithMetrics {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
 type ProfileSavePath struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -201,7 +408,7 @@ type ProfileSavePath struct {
 func (x *ProfileSavePath) Reset() {
 	*x = ProfileSavePath{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_misc_proto_msgTypes[3]
+		mi := &file_appctl_proto_misc_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -214,7 +421,7 @@ func (x *ProfileSavePath) String() string {
 func (*ProfileSavePath) ProtoMessage() {}
 
 func (x *ProfileSavePath) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_misc_proto_msgTypes[3]
+	mi := &file_appctl_proto_misc_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -227,7 +434,7 @@ func (x *ProfileSavePath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProfileSavePath.ProtoReflect.Descriptor instead.
 func (*ProfileSavePath) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{3}
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ProfileSavePath) GetFilePath() string {
@@ -255,12 +462,21 @@ type SessionInfo struct {
 	LastRecvSeq  *uint32                `protobuf:"varint,11,opt,name=lastRecvSeq,proto3,oneof" json:"lastRecvSeq,omitempty"`
 	LastSendTime *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=lastSendTime,proto3,oneof" json:"lastSendTime,omitempty"`
 	LastSendSeq  *uint32                `protobuf:"varint,13,opt,name=lastSendSeq,proto3,oneof" json:"lastSendSeq,omitempty"`
+	// User that owns this session. Only available at the server side.
+	User      *string                `protobuf:"bytes,14,opt,name=user,proto3,oneof" json:"user,omitempty"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=startTime,proto3,oneof" json:"startTime,omitempty"`
+	// Number of bytes read from and written to the session.
+	RecvBytes *int64 `protobuf:"varint,16,opt,name=recvBytes,proto3,oneof" json:"recvBytes,omitempty"`
+	SendBytes *int64 `protobuf:"varint,17,opt,name=sendBytes,proto3,oneof" json:"sendBytes,omitempty"`
+	// Destination of the proxy request in this session.
+	// Only available at the server side.
+	Destination *string `protobuf:"bytes,18,opt,name=destination,proto3,oneof" json:"destination,omitempty"`
 }
 
 func (x *SessionInfo) Reset() {
 	*x = SessionInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_misc_proto_msgTypes[4]
+		mi := &file_appctl_proto_misc_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -273,7 +489,7 @@ func (x *SessionInfo) String() string {
 func (*SessionInfo) ProtoMessage() {}
 
 func (x *SessionInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_misc_proto_msgTypes[4]
+	mi := &file_appctl_proto_misc_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,7 +502,7 @@ func (x *SessionInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SessionInfo.ProtoReflect.Descriptor instead.
 func (*SessionInfo) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{4}
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *SessionInfo) GetId() string {
@@ -380,31 +596,67 @@ func (x *SessionInfo) GetLastSendSeq() uint32 {
 	return 0
 }
 
-type SessionInfoList struct {
+func (x *SessionInfo) GetUser() string {
+	if x != nil && x.User != nil {
+		return *x.User
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetRecvBytes() int64 {
+	if x != nil && x.RecvBytes != nil {
+		return *x.RecvBytes
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetSendBytes() int64 {
+	if x != nil && x.SendBytes != nil {
+		return *x.SendBytes
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetDestination() string {
+	if x != nil && x.Destination != nil {
+		return *x.Destination
+	}
+	return ""
+}
+
+type KillSessionRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Items []*SessionInfo `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// ID of the session to close.
+	Id *uint32 `protobuf:"varint,1,opt,name=id,proto3,oneof" json:"id,omitempty"`
 }
 
-func (x *SessionInfoList) Reset() {
-	*x = SessionInfoList{}
+func (x *KillSessionRequest) Reset() {
+	*x = KillSessionRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_misc_proto_msgTypes[5]
+		mi := &file_appctl_proto_misc_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SessionInfoList) String() string {
+func (x *KillSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SessionInfoList) ProtoMessage() {}
+func (*KillSessionRequest) ProtoMessage() {}
 
-func (x *SessionInfoList) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_misc_proto_msgTypes[5]
+func (x *KillSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -415,44 +667,46 @@ func (x *SessionInfoList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SessionInfoList.ProtoReflect.Descriptor instead.
-func (*SessionInfoList) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use KillSessionRequest.ProtoReflect.Descriptor instead.
+func (*KillSessionRequest) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *SessionInfoList) GetItems() []*SessionInfo {
-	if x != nil {
-		return x.Items
+func (x *KillSessionRequest) GetId() uint32 {
+	if x != nil && x.Id != nil {
+		return *x.Id
 	}
-	return nil
+	return 0
 }
 
-type ThreadDump struct {
+type ReloadClientRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Full thread dump of the application.
-	ThreadDump *string `protobuf:"bytes,1,opt,name=threadDump,proto3,oneof" json:"threadDump,omitempty"`
+	// If true, close existing connections to proxy servers that are
+	// removed from the active profile, instead of keeping them until
+	// all the sessions on them are finished.
+	MigrateNow *bool `protobuf:"varint,1,opt,name=migrateNow,proto3,oneof" json:"migrateNow,omitempty"`
 }
 
-func (x *ThreadDump) Reset() {
-	*x = ThreadDump{}
+func (x *ReloadClientRequest) Reset() {
+	*x = ReloadClientRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_misc_proto_msgTypes[6]
+		mi := &file_appctl_proto_misc_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ThreadDump) String() string {
+func (x *ReloadClientRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ThreadDump) ProtoMessage() {}
+func (*ReloadClientRequest) ProtoMessage() {}
 
-func (x *ThreadDump) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_misc_proto_msgTypes[6]
+func (x *ReloadClientRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -463,47 +717,43 @@ func (x *ThreadDump) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ThreadDump.ProtoReflect.Descriptor instead.
-func (*ThreadDump) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ReloadClientRequest.ProtoReflect.Descriptor instead.
+func (*ReloadClientRequest) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *ThreadDump) GetThreadDump() string {
-	if x != nil && x.ThreadDump != nil {
-		return *x.ThreadDump
+func (x *ReloadClientRequest) GetMigrateNow() bool {
+	if x != nil && x.MigrateNow != nil {
+		return *x.MigrateNow
 	}
-	return ""
+	return false
 }
 
-type MemoryStatistics struct {
+type SessionInfoList struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	HeapBytes       *uint64 `protobuf:"varint,2,opt,name=heapBytes,proto3,oneof" json:"heapBytes,omitempty"`
-	HeapObjects     *uint64 `protobuf:"varint,3,opt,name=heapObjects,proto3,oneof" json:"heapObjects,omitempty"`
-	MaxHeapBytes    *uint64 `protobuf:"varint,4,opt,name=maxHeapBytes,proto3,oneof" json:"maxHeapBytes,omitempty"`
-	TargetHeapBytes *uint64 `protobuf:"varint,5,opt,name=targetHeapBytes,proto3,oneof" json:"targetHeapBytes,omitempty"`
-	StackBytes      *uint64 `protobuf:"varint,6,opt,name=stackBytes,proto3,oneof" json:"stackBytes,omitempty"`
+	Items []*SessionInfo `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
 }
 
-func (x *MemoryStatistics) Reset() {
-	*x = MemoryStatistics{}
+func (x *SessionInfoList) Reset() {
+	*x = SessionInfoList{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_misc_proto_msgTypes[7]
+		mi := &file_appctl_proto_misc_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *MemoryStatistics) String() string {
+func (x *SessionInfoList) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MemoryStatistics) ProtoMessage() {}
+func (*SessionInfoList) ProtoMessage() {}
 
-func (x *MemoryStatistics) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_misc_proto_msgTypes[7]
+func (x *SessionInfoList) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -514,23 +764,781 @@ func (x *MemoryStatistics) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MemoryStatistics.ProtoReflect.Descriptor instead.
-func (*MemoryStatistics) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use SessionInfoList.ProtoReflect.Descriptor instead.
+func (*SessionInfoList) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *MemoryStatistics) GetHeapBytes() uint64 {
-	if x != nil && x.HeapBytes != nil {
-		return *x.HeapBytes
+func (x *SessionInfoList) GetItems() []*SessionInfo {
+	if x != nil {
+		return x.Items
 	}
-	return 0
+	return nil
 }
 
-func (x *MemoryStatistics) GetHeapObjects() uint64 {
-	if x != nil && x.HeapObjects != nil {
-		return *x.HeapObjects
+type DestinationTraffic struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Domain name or IP address of the destination.
+	Destination   *string `protobuf:"bytes,1,opt,name=destination,proto3,oneof" json:"destination,omitempty"`
+	UploadBytes   *int64  `protobuf:"varint,2,opt,name=uploadBytes,proto3,oneof" json:"uploadBytes,omitempty"`
+	DownloadBytes *int64  `protobuf:"varint,3,opt,name=downloadBytes,proto3,oneof" json:"downloadBytes,omitempty"`
+}
+
+func (x *DestinationTraffic) Reset() {
+	*x = DestinationTraffic{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
+}
+
+func (x *DestinationTraffic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationTraffic) ProtoMessage() {}
+
+func (x *DestinationTraffic) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationTraffic.ProtoReflect.Descriptor instead.
+func (*DestinationTraffic) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DestinationTraffic) GetDestination() string {
+	if x != nil && x.Destination != nil {
+		return *x.Destination
+	}
+	return ""
+}
+
+func (x *DestinationTraffic) GetUploadBytes() int64 {
+	if x != nil && x.UploadBytes != nil {
+		return *x.UploadBytes
+	}
+	return 0
+}
+
+func (x *DestinationTraffic) GetDownloadBytes() int64 {
+	if x != nil && x.DownloadBytes != nil {
+		return *x.DownloadBytes
+	}
+	return 0
+}
+
+type DestinationTrafficList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*DestinationTraffic `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *DestinationTrafficList) Reset() {
+	*x = DestinationTrafficList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DestinationTrafficList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationTrafficList) ProtoMessage() {}
+
+func (x *DestinationTrafficList) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationTrafficList.ProtoReflect.Descriptor instead.
+func (*DestinationTrafficList) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DestinationTrafficList) GetItems() []*DestinationTraffic {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ProxyConnection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Session ID of the socks5 request.
+	SessionID *uint32 `protobuf:"varint,1,opt,name=sessionID,proto3,oneof" json:"sessionID,omitempty"`
+	// Address of the socks5 client.
+	Source *string `protobuf:"bytes,2,opt,name=source,proto3,oneof" json:"source,omitempty"`
+	// Destination host and port.
+	Destination *string `protobuf:"bytes,3,opt,name=destination,proto3,oneof" json:"destination,omitempty"`
+	// "TCP" or "UDP".
+	Protocol *string `protobuf:"bytes,4,opt,name=protocol,proto3,oneof" json:"protocol,omitempty"`
+	// "PROXY" if the connection is sent through the proxy tunnel,
+	// "BYPASS" if the destination matches a bypass rule, or
+	// "DIRECT_FALLBACK" if the proxy tunnel is unavailable.
+	Route *string `protobuf:"bytes,5,opt,name=route,proto3,oneof" json:"route,omitempty"`
+	// Bypass rule that matches the destination.
+	Rule          *string                `protobuf:"bytes,6,opt,name=rule,proto3,oneof" json:"rule,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=startTime,proto3,oneof" json:"startTime,omitempty"`
+	UploadBytes   *int64                 `protobuf:"varint,8,opt,name=uploadBytes,proto3,oneof" json:"uploadBytes,omitempty"`
+	DownloadBytes *int64                 `protobuf:"varint,9,opt,name=downloadBytes,proto3,oneof" json:"downloadBytes,omitempty"`
+}
+
+func (x *ProxyConnection) Reset() {
+	*x = ProxyConnection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProxyConnection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProxyConnection) ProtoMessage() {}
+
+func (x *ProxyConnection) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProxyConnection.ProtoReflect.Descriptor instead.
+func (*ProxyConnection) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ProxyConnection) GetSessionID() uint32 {
+	if x != nil && x.SessionID != nil {
+		return *x.SessionID
+	}
+	return 0
+}
+
+func (x *ProxyConnection) GetSource() string {
+	if x != nil && x.Source != nil {
+		return *x.Source
+	}
+	return ""
+}
+
+func (x *ProxyConnection) GetDestination() string {
+	if x != nil && x.Destination != nil {
+		return *x.Destination
+	}
+	return ""
+}
+
+func (x *ProxyConnection) GetProtocol() string {
+	if x != nil && x.Protocol != nil {
+		return *x.Protocol
+	}
+	return ""
+}
+
+func (x *ProxyConnection) GetRoute() string {
+	if x != nil && x.Route != nil {
+		return *x.Route
+	}
+	return ""
+}
+
+func (x *ProxyConnection) GetRule() string {
+	if x != nil && x.Rule != nil {
+		return *x.Rule
+	}
+	return ""
+}
+
+func (x *ProxyConnection) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ProxyConnection) GetUploadBytes() int64 {
+	if x != nil && x.UploadBytes != nil {
+		return *x.UploadBytes
+	}
+	return 0
+}
+
+func (x *ProxyConnection) GetDownloadBytes() int64 {
+	if x != nil && x.DownloadBytes != nil {
+		return *x.DownloadBytes
+	}
+	return 0
+}
+
+type ProxyConnectionList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*ProxyConnection `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *ProxyConnectionList) Reset() {
+	*x = ProxyConnectionList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProxyConnectionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProxyConnectionList) ProtoMessage() {}
+
+func (x *ProxyConnectionList) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProxyConnectionList.ProtoReflect.Descriptor instead.
+func (*ProxyConnectionList) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ProxyConnectionList) GetItems() []*ProxyConnection {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type CountryTraffic struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ISO 3166-1 alpha-2 country code, or "(unknown)" if the country
+	// is not found from the GeoIP database.
+	Country       *string `protobuf:"bytes,1,opt,name=country,proto3,oneof" json:"country,omitempty"`
+	UploadBytes   *int64  `protobuf:"varint,2,opt,name=uploadBytes,proto3,oneof" json:"uploadBytes,omitempty"`
+	DownloadBytes *int64  `protobuf:"varint,3,opt,name=downloadBytes,proto3,oneof" json:"downloadBytes,omitempty"`
+}
+
+func (x *CountryTraffic) Reset() {
+	*x = CountryTraffic{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountryTraffic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountryTraffic) ProtoMessage() {}
+
+func (x *CountryTraffic) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountryTraffic.ProtoReflect.Descriptor instead.
+func (*CountryTraffic) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CountryTraffic) GetCountry() string {
+	if x != nil && x.Country != nil {
+		return *x.Country
+	}
+	return ""
+}
+
+func (x *CountryTraffic) GetUploadBytes() int64 {
+	if x != nil && x.UploadBytes != nil {
+		return *x.UploadBytes
+	}
+	return 0
+}
+
+func (x *CountryTraffic) GetDownloadBytes() int64 {
+	if x != nil && x.DownloadBytes != nil {
+		return *x.DownloadBytes
+	}
+	return 0
+}
+
+type CountryTrafficList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*CountryTraffic `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *CountryTrafficList) Reset() {
+	*x = CountryTrafficList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountryTrafficList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountryTrafficList) ProtoMessage() {}
+
+func (x *CountryTrafficList) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountryTrafficList.ProtoReflect.Descriptor instead.
+func (*CountryTrafficList) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CountryTrafficList) GetItems() []*CountryTraffic {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type PortBindingStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Local address that the server listens to.
+	Address *string `protobuf:"bytes,1,opt,name=address,proto3,oneof" json:"address,omitempty"`
+	// Transport protocols that share the local address.
+	Protocols []TransportProtocol `protobuf:"varint,2,rep,packed,name=protocols,proto3,enum=mieru.appctl.TransportProtocol" json:"protocols,omitempty"`
+	State     *PortBindingState   `protobuf:"varint,3,opt,name=state,proto3,enum=mieru.appctl.PortBindingState,oneof" json:"state,omitempty"`
+	// The error that stops the port binding from listening.
+	Error *string `protobuf:"bytes,4,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	// Number of underlays accepted from the port binding.
+	Underlays *int64 `protobuf:"varint,5,opt,name=underlays,proto3,oneof" json:"underlays,omitempty"`
+	// Number of sessions accepted from the port binding.
+	Sessions *int64 `protobuf:"varint,6,opt,name=sessions,proto3,oneof" json:"sessions,omitempty"`
+}
+
+func (x *PortBindingStatus) Reset() {
+	*x = PortBindingStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PortBindingStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortBindingStatus) ProtoMessage() {}
+
+func (x *PortBindingStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortBindingStatus.ProtoReflect.Descriptor instead.
+func (*PortBindingStatus) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PortBindingStatus) GetAddress() string {
+	if x != nil && x.Address != nil {
+		return *x.Address
+	}
+	return ""
+}
+
+func (x *PortBindingStatus) GetProtocols() []TransportProtocol {
+	if x != nil {
+		return x.Protocols
+	}
+	return nil
+}
+
+func (x *PortBindingStatus) GetState() PortBindingState {
+	if x != nil && x.State != nil {
+		return *x.State
+	}
+	return PortBindingState_UNKNOWN_PORT_BINDING_STATE
+}
+
+func (x *PortBindingStatus) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *PortBindingStatus) GetUnderlays() int64 {
+	if x != nil && x.Underlays != nil {
+		return *x.Underlays
+	}
+	return 0
+}
+
+func (x *PortBindingStatus) GetSessions() int64 {
+	if x != nil && x.Sessions != nil {
+		return *x.Sessions
+	}
+	return 0
+}
+
+type PortBindingStatusList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*PortBindingStatus `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *PortBindingStatusList) Reset() {
+	*x = PortBindingStatusList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PortBindingStatusList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortBindingStatusList) ProtoMessage() {}
+
+func (x *PortBindingStatusList) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortBindingStatusList.ProtoReflect.Descriptor instead.
+func (*PortBindingStatusList) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PortBindingStatusList) GetItems() []*PortBindingStatus {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ServerLatency struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// IP address of the proxy server.
+	IpAddress *string `protobuf:"bytes,1,opt,name=ipAddress,proto3,oneof" json:"ipAddress,omitempty"`
+	// The port used to measure the latency.
+	Port     *int32             `protobuf:"varint,2,opt,name=port,proto3,oneof" json:"port,omitempty"`
+	Protocol *TransportProtocol `protobuf:"varint,3,opt,name=protocol,proto3,enum=mieru.appctl.TransportProtocol,oneof" json:"protocol,omitempty"`
+	// Handshake round trip time in microseconds.
+	// It is not set if the proxy server can't be reached.
+	RttMicros *int64 `protobuf:"varint,4,opt,name=rttMicros,proto3,oneof" json:"rttMicros,omitempty"`
+	// The error when the proxy server can't be reached.
+	Error *string `protobuf:"bytes,5,opt,name=error,proto3,oneof" json:"error,omitempty"`
+}
+
+func (x *ServerLatency) Reset() {
+	*x = ServerLatency{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerLatency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerLatency) ProtoMessage() {}
+
+func (x *ServerLatency) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerLatency.ProtoReflect.Descriptor instead.
+func (*ServerLatency) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ServerLatency) GetIpAddress() string {
+	if x != nil && x.IpAddress != nil {
+		return *x.IpAddress
+	}
+	return ""
+}
+
+func (x *ServerLatency) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *ServerLatency) GetProtocol() TransportProtocol {
+	if x != nil && x.Protocol != nil {
+		return *x.Protocol
+	}
+	return TransportProtocol_UNKNOWN_TRANSPORT_PROTOCOL
+}
+
+func (x *ServerLatency) GetRttMicros() int64 {
+	if x != nil && x.RttMicros != nil {
+		return *x.RttMicros
+	}
+	return 0
+}
+
+func (x *ServerLatency) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+type ServerLatencyList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*ServerLatency `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *ServerLatencyList) Reset() {
+	*x = ServerLatencyList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerLatencyList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerLatencyList) ProtoMessage() {}
+
+func (x *ServerLatencyList) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerLatencyList.ProtoReflect.Descriptor instead.
+func (*ServerLatencyList) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ServerLatencyList) GetItems() []*ServerLatency {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ThreadDump struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Full thread dump of the application.
+	ThreadDump *string `protobuf:"bytes,1,opt,name=threadDump,proto3,oneof" json:"threadDump,omitempty"`
+}
+
+func (x *ThreadDump) Reset() {
+	*x = ThreadDump{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThreadDump) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreadDump) ProtoMessage() {}
+
+func (x *ThreadDump) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreadDump.ProtoReflect.Descriptor instead.
+func (*ThreadDump) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ThreadDump) GetThreadDump() string {
+	if x != nil && x.ThreadDump != nil {
+		return *x.ThreadDump
+	}
+	return ""
+}
+
+type MemoryStatistics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HeapBytes       *uint64 `protobuf:"varint,2,opt,name=heapBytes,proto3,oneof" json:"heapBytes,omitempty"`
+	HeapObjects     *uint64 `protobuf:"varint,3,opt,name=heapObjects,proto3,oneof" json:"heapObjects,omitempty"`
+	MaxHeapBytes    *uint64 `protobuf:"varint,4,opt,name=maxHeapBytes,proto3,oneof" json:"maxHeapBytes,omitempty"`
+	TargetHeapBytes *uint64 `protobuf:"varint,5,opt,name=targetHeapBytes,proto3,oneof" json:"targetHeapBytes,omitempty"`
+	StackBytes      *uint64 `protobuf:"varint,6,opt,name=stackBytes,proto3,oneof" json:"stackBytes,omitempty"`
+}
+
+func (x *MemoryStatistics) Reset() {
+	*x = MemoryStatistics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MemoryStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemoryStatistics) ProtoMessage() {}
+
+func (x *MemoryStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemoryStatistics.ProtoReflect.Descriptor instead.
+func (*MemoryStatistics) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *MemoryStatistics) GetHeapBytes() uint64 {
+	if x != nil && x.HeapBytes != nil {
+		return *x.HeapBytes
+	}
+	return 0
+}
+
+func (x *MemoryStatistics) GetHeapObjects() uint64 {
+	if x != nil && x.HeapObjects != nil {
+		return *x.HeapObjects
+	}
+	return 0
 }
 
 func (x *MemoryStatistics) GetMaxHeapBytes() uint64 {
@@ -540,47 +1548,339 @@ func (x *MemoryStatistics) GetMaxHeapBytes() uint64 {
 	return 0
 }
 
-func (x *MemoryStatistics) GetTargetHeapBytes() uint64 {
-	if x != nil && x.TargetHeapBytes != nil {
-		return *x.TargetHeapBytes
+func (x *MemoryStatistics) GetTargetHeapBytes() uint64 {
+	if x != nil && x.TargetHeapBytes != nil {
+		return *x.TargetHeapBytes
+	}
+	return 0
+}
+
+func (x *MemoryStatistics) GetStackBytes() uint64 {
+	if x != nil && x.StackBytes != nil {
+		return *x.StackBytes
+	}
+	return 0
+}
+
+type Version struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Major *uint32 `protobuf:"varint,1,opt,name=Major,proto3,oneof" json:"Major,omitempty"`
+	Minor *uint32 `protobuf:"varint,2,opt,name=Minor,proto3,oneof" json:"Minor,omitempty"`
+	Patch *uint32 `protobuf:"varint,3,opt,name=Patch,proto3,oneof" json:"Patch,omitempty"`
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Version) GetMajor() uint32 {
+	if x != nil && x.Major != nil {
+		return *x.Major
+	}
+	return 0
+}
+
+func (x *Version) GetMinor() uint32 {
+	if x != nil && x.Minor != nil {
+		return *x.Minor
+	}
+	return 0
+}
+
+func (x *Version) GetPatch() uint32 {
+	if x != nil && x.Patch != nil {
+		return *x.Patch
+	}
+	return 0
+}
+
+type KeyEpoch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The current key epoch.
+	Epoch *int64 `protobuf:"varint,1,opt,name=epoch,proto3,oneof" json:"epoch,omitempty"`
+}
+
+func (x *KeyEpoch) Reset() {
+	*x = KeyEpoch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyEpoch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyEpoch) ProtoMessage() {}
+
+func (x *KeyEpoch) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyEpoch.ProtoReflect.Descriptor instead.
+func (*KeyEpoch) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *KeyEpoch) GetEpoch() int64 {
+	if x != nil && x.Epoch != nil {
+		return *x.Epoch
+	}
+	return 0
+}
+
+type FollowLogsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only stream the log entries at this level or more severe.
+	// If it is not set, INFO level is used.
+	Level *LoggingLevel `protobuf:"varint,1,opt,name=level,proto3,enum=mieru.appctl.LoggingLevel,oneof" json:"level,omitempty"`
+}
+
+func (x *FollowLogsRequest) Reset() {
+	*x = FollowLogsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FollowLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FollowLogsRequest) ProtoMessage() {}
+
+func (x *FollowLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FollowLogsRequest.ProtoReflect.Descriptor instead.
+func (*FollowLogsRequest) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *FollowLogsRequest) GetLevel() LoggingLevel {
+	if x != nil && x.Level != nil {
+		return *x.Level
+	}
+	return LoggingLevel_DEFAULT
+}
+
+type LogEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3,oneof" json:"time,omitempty"`
+	Level   *LoggingLevel          `protobuf:"varint,2,opt,name=level,proto3,enum=mieru.appctl.LoggingLevel,oneof" json:"level,omitempty"`
+	Message *string                `protobuf:"bytes,3,opt,name=message,proto3,oneof" json:"message,omitempty"`
+	// Structured fields of the log entry, for example session_id.
+	Fields map[string]string `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *LogEntry) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *LogEntry) GetLevel() LoggingLevel {
+	if x != nil && x.Level != nil {
+		return *x.Level
+	}
+	return LoggingLevel_DEFAULT
+}
+
+func (x *LogEntry) GetMessage() string {
+	if x != nil && x.Message != nil {
+		return *x.Message
+	}
+	return ""
+}
+
+func (x *LogEntry) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ClusterSyncRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the replica server in the cluster.
+	NodeName *string `protobuf:"bytes,1,opt,name=nodeName,proto3,oneof" json:"nodeName,omitempty"`
+	// Traffic of users and user groups that have quotas on the replica server.
+	Traffic []*metricspb.MetricGroup `protobuf:"bytes,2,rep,name=traffic,proto3" json:"traffic,omitempty"`
+}
+
+func (x *ClusterSyncRequest) Reset() {
+	*x = ClusterSyncRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_misc_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClusterSyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterSyncRequest) ProtoMessage() {}
+
+func (x *ClusterSyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterSyncRequest.ProtoReflect.Descriptor instead.
+func (*ClusterSyncRequest) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ClusterSyncRequest) GetNodeName() string {
+	if x != nil && x.NodeName != nil {
+		return *x.NodeName
 	}
-	return 0
+	return ""
 }
 
-func (x *MemoryStatistics) GetStackBytes() uint64 {
-	if x != nil && x.StackBytes != nil {
-		return *x.StackBytes
+func (x *ClusterSyncRequest) GetTraffic() []*metricspb.MetricGroup {
+	if x != nil {
+		return x.Traffic
 	}
-	return 0
+	return nil
 }
 
-type Version struct {
+type ClusterSyncResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Major *uint32 `protobuf:"varint,1,opt,name=Major,proto3,oneof" json:"Major,omitempty"`
-	Minor *uint32 `protobuf:"varint,2,opt,name=Minor,proto3,oneof" json:"Minor,omitempty"`
-	Patch *uint32 `protobuf:"varint,3,opt,name=Patch,proto3,oneof" json:"Patch,omitempty"`
+	// Users of the cluster. Only hashed passwords are included.
+	Users []*User `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// User groups of the cluster.
+	UserGroups []*UserGroup `protobuf:"bytes,2,rep,name=userGroups,proto3" json:"userGroups,omitempty"`
+	// Traffic of users and user groups on the other servers of the cluster.
+	Traffic []*metricspb.MetricGroup `protobuf:"bytes,3,rep,name=traffic,proto3" json:"traffic,omitempty"`
 }
 
-func (x *Version) Reset() {
-	*x = Version{}
+func (x *ClusterSyncResponse) Reset() {
+	*x = ClusterSyncResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_misc_proto_msgTypes[8]
+		mi := &file_appctl_proto_misc_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Version) String() string {
+func (x *ClusterSyncResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Version) ProtoMessage() {}
+func (*ClusterSyncResponse) ProtoMessage() {}
 
-func (x *Version) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_misc_proto_msgTypes[8]
+func (x *ClusterSyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_misc_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -591,30 +1891,30 @@ func (x *Version) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Version.ProtoReflect.Descriptor instead.
-func (*Version) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ClusterSyncResponse.ProtoReflect.Descriptor instead.
+func (*ClusterSyncResponse) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_misc_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *Version) GetMajor() uint32 {
-	if x != nil && x.Major != nil {
-		return *x.Major
+func (x *ClusterSyncResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
 	}
-	return 0
+	return nil
 }
 
-func (x *Version) GetMinor() uint32 {
-	if x != nil && x.Minor != nil {
-		return *x.Minor
+func (x *ClusterSyncResponse) GetUserGroups() []*UserGroup {
+	if x != nil {
+		return x.UserGroups
 	}
-	return 0
+	return nil
 }
 
-func (x *Version) GetPatch() uint32 {
-	if x != nil && x.Patch != nil {
-		return *x.Patch
+func (x *ClusterSyncResponse) GetTraffic() []*metricspb.MetricGroup {
+	if x != nil {
+		return x.Traffic
 	}
-	return 0
+	return nil
 }
 
 var File_appctl_proto_misc_proto protoreflect.FileDescriptor
@@ -624,111 +1924,322 @@ var file_appctl_proto_misc_proto_rawDesc = []byte{
 	0x69, 0x73, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x6d, 0x69, 0x65, 0x72, 0x75,
 	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x1a, 0x17, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x61, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x1a, 0x2c, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b,
-	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x65,
-	0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2b, 0x0a, 0x07, 0x4d,
-	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x17, 0x0a, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42,
-	0x07, 0x0a, 0x05, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x22, 0x78, 0x0a, 0x0f, 0x55, 0x73, 0x65, 0x72,
-	0x57, 0x69, 0x74, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x2b, 0x0a, 0x04, 0x75,
-	0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x65, 0x72,
-	0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52,
-	0x04, 0x75, 0x73, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x72,
-	0x69, 0x63, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x69, 0x65, 0x72,
-	0x75, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
-	0x52, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73,
-	0x65, 0x72, 0x22, 0x4a, 0x0a, 0x13, 0x55, 0x73, 0x65, 0x72, 0x57, 0x69, 0x74, 0x68, 0x4d, 0x65,
-	0x74, 0x72, 0x69, 0x63, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x05, 0x69, 0x74, 0x65,
-	0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
-	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x57, 0x69, 0x74, 0x68,
-	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x3f,
-	0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x61, 0x76, 0x65, 0x50, 0x61, 0x74,
-	0x68, 0x12, 0x1f, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x88,
-	0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x22,
-	0x9b, 0x05, 0x0a, 0x0b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12,
-	0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x02, 0x69,
-	0x64, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
-	0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x41, 0x64,
-	0x64, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x09, 0x6c, 0x6f, 0x63, 0x61,
-	0x6c, 0x41, 0x64, 0x64, 0x72, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x72, 0x65, 0x6d, 0x6f,
-	0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0a,
-	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a,
-	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x05,
-	0x73, 0x74, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x72, 0x65, 0x63, 0x76,
-	0x51, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x05, 0x52, 0x05, 0x72, 0x65, 0x63, 0x76, 0x51,
-	0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x76, 0x42, 0x75, 0x66, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x0d, 0x48, 0x06, 0x52, 0x07, 0x72, 0x65, 0x63, 0x76, 0x42, 0x75, 0x66, 0x88,
-	0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x65, 0x6e, 0x64, 0x51, 0x18, 0x08, 0x20, 0x01, 0x28,
-	0x0d, 0x48, 0x07, 0x52, 0x05, 0x73, 0x65, 0x6e, 0x64, 0x51, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a,
-	0x07, 0x73, 0x65, 0x6e, 0x64, 0x42, 0x75, 0x66, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x08,
-	0x52, 0x07, 0x73, 0x65, 0x6e, 0x64, 0x42, 0x75, 0x66, 0x88, 0x01, 0x01, 0x12, 0x43, 0x0a, 0x0c,
-	0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01,
+	0x1a, 0x1c, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x63, 0x66, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x2c,
+	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x2b, 0x0a, 0x07, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x12, 0x17, 0x0a, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x22, 0x78, 0x0a, 0x0f, 0x55, 0x73, 0x65, 0x72, 0x57, 0x69,
+	0x74, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x2b, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
+	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x48, 0x00, 0x52, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52, 0x07,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73, 0x65, 0x72,
+	0x22, 0x4a, 0x0a, 0x13, 0x55, 0x73, 0x65, 0x72, 0x57, 0x69, 0x74, 0x68, 0x4d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61,
+	0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x57, 0x69, 0x74, 0x68, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x24, 0x0a, 0x0c,
+	0x55, 0x73, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d,
+	0x65, 0x73, 0x22, 0x85, 0x01, 0x0a, 0x14, 0x55, 0x73, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x57, 0x69, 0x74, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x32, 0x0a, 0x05, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x69, 0x65,
+	0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x48, 0x00, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x88, 0x01, 0x01, 0x12,
+	0x2f, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x22, 0x54, 0x0a, 0x18, 0x55, 0x73,
+	0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x57, 0x69, 0x74, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x57, 0x69,
+	0x74, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73,
+	0x22, 0x3f, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x61, 0x76, 0x65, 0x50,
+	0x61, 0x74, 0x68, 0x12, 0x1f, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74,
+	0x68, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74,
+	0x68, 0x22, 0xa3, 0x07, 0x0a, 0x0b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x13, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x02, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x41, 0x64, 0x64, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x09, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03,
+	0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x88, 0x01, 0x01, 0x12,
+	0x19, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x72, 0x65,
+	0x63, 0x76, 0x51, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x05, 0x52, 0x05, 0x72, 0x65, 0x63,
+	0x76, 0x51, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x76, 0x42, 0x75, 0x66,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x06, 0x52, 0x07, 0x72, 0x65, 0x63, 0x76, 0x42, 0x75,
+	0x66, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x65, 0x6e, 0x64, 0x51, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x07, 0x52, 0x05, 0x73, 0x65, 0x6e, 0x64, 0x51, 0x88, 0x01, 0x01, 0x12,
+	0x1d, 0x0a, 0x07, 0x73, 0x65, 0x6e, 0x64, 0x42, 0x75, 0x66, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x08, 0x52, 0x07, 0x73, 0x65, 0x6e, 0x64, 0x42, 0x75, 0x66, 0x88, 0x01, 0x01, 0x12, 0x43,
+	0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x48, 0x09, 0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76, 0x54, 0x69, 0x6d, 0x65,
+	0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76, 0x53,
+	0x65, 0x71, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x0a, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74,
+	0x52, 0x65, 0x63, 0x76, 0x53, 0x65, 0x71, 0x88, 0x01, 0x01, 0x12, 0x43, 0x0a, 0x0c, 0x6c, 0x61,
+	0x73, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x0b, 0x52, 0x0c,
+	0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x25, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x65, 0x71, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x0c, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x6e, 0x64,
+	0x53, 0x65, 0x71, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x0d, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12,
+	0x3d, 0x0a, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x0f, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x09,
-	0x52, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01,
-	0x01, 0x12, 0x25, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76, 0x53, 0x65, 0x71,
-	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x0a, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65,
-	0x63, 0x76, 0x53, 0x65, 0x71, 0x88, 0x01, 0x01, 0x12, 0x43, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74,
-	0x53, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x0b, 0x52, 0x0c, 0x6c, 0x61,
-	0x73, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a,
-	0x0b, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x65, 0x71, 0x18, 0x0d, 0x20, 0x01,
-	0x28, 0x0d, 0x48, 0x0c, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x65,
-	0x71, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6c, 0x6f, 0x63,
-	0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74,
-	0x65, 0x41, 0x64, 0x64, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x42,
-	0x08, 0x0a, 0x06, 0x5f, 0x72, 0x65, 0x63, 0x76, 0x51, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x72, 0x65,
-	0x63, 0x76, 0x42, 0x75, 0x66, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x65, 0x6e, 0x64, 0x51, 0x42,
-	0x0a, 0x0a, 0x08, 0x5f, 0x73, 0x65, 0x6e, 0x64, 0x42, 0x75, 0x66, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
-	0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76, 0x54, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c,
-	0x5f, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76, 0x53, 0x65, 0x71, 0x42, 0x0f, 0x0a, 0x0d,
-	0x5f, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a,
-	0x0c, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x65, 0x71, 0x22, 0x42, 0x0a,
-	0x0f, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74,
-	0x12, 0x2f, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x19, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d,
-	0x73, 0x22, 0x40, 0x0a, 0x0a, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x12,
-	0x23, 0x0a, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d,
-	0x70, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44,
-	0x75, 0x6d, 0x70, 0x22, 0xb1, 0x02, 0x0a, 0x10, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53, 0x74,
-	0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x21, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x70,
-	0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x09, 0x68,
-	0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x68,
-	0x65, 0x61, 0x70, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
-	0x48, 0x01, 0x52, 0x0b, 0x68, 0x65, 0x61, 0x70, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x88,
-	0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x48, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74,
-	0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x48, 0x02, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x48,
-	0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x74,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x04, 0x48, 0x03, 0x52, 0x0f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x65,
-	0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x73, 0x74,
-	0x61, 0x63, 0x6b, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x48, 0x04,
-	0x52, 0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42,
-	0x0c, 0x0a, 0x0a, 0x5f, 0x68, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x0e, 0x0a,
-	0x0c, 0x5f, 0x68, 0x65, 0x61, 0x70, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x42, 0x0f, 0x0a,
-	0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x48, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x12,
-	0x0a, 0x10, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74,
-	0x65, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x42, 0x79, 0x74, 0x65,
-	0x73, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0x78, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x05, 0x4d, 0x61, 0x6a, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0d, 0x48, 0x00, 0x52, 0x05, 0x4d, 0x61, 0x6a, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a,
-	0x05, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x05,
-	0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x50, 0x61, 0x74, 0x63,
-	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x05, 0x50, 0x61, 0x74, 0x63, 0x68,
-	0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x4d, 0x61, 0x6a, 0x6f, 0x72, 0x42, 0x08, 0x0a,
-	0x06, 0x5f, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x50, 0x61, 0x74, 0x63,
-	0x68, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x65, 0x6e, 0x66, 0x65, 0x69, 0x6e, 0x2f, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2f, 0x76, 0x33, 0x2f,
-	0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74,
-	0x6c, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x0e,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x21,
+	0x0a, 0x09, 0x72, 0x65, 0x63, 0x76, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x03, 0x48, 0x0f, 0x52, 0x09, 0x72, 0x65, 0x63, 0x76, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01,
+	0x01, 0x12, 0x21, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x11,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x10, 0x52, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x48, 0x11, 0x52, 0x0b, 0x64, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f,
+	0x69, 0x64, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x42,
+	0x0c, 0x0a, 0x0a, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x42, 0x0d, 0x0a,
+	0x0b, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x72, 0x65, 0x63, 0x76, 0x51,
+	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x72, 0x65, 0x63, 0x76, 0x42, 0x75, 0x66, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x73, 0x65, 0x6e, 0x64, 0x51, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x73, 0x65, 0x6e, 0x64, 0x42,
+	0x75, 0x66, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76, 0x54,
+	0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x63, 0x76,
+	0x53, 0x65, 0x71, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x6e, 0x64,
+	0x54, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x6e,
+	0x64, 0x53, 0x65, 0x71, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x42, 0x0c, 0x0a,
+	0x0a, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
+	0x72, 0x65, 0x63, 0x76, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x65,
+	0x6e, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x64, 0x65, 0x73, 0x74,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x30, 0x0a, 0x12, 0x4b, 0x69, 0x6c, 0x6c, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x13, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x88,
+	0x01, 0x01, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x69, 0x64, 0x22, 0x49, 0x0a, 0x13, 0x52, 0x65, 0x6c,
+	0x6f, 0x61, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x23, 0x0a, 0x0a, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x77, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x0a, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x4e,
+	0x6f, 0x77, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x69, 0x67, 0x72, 0x61, 0x74,
+	0x65, 0x4e, 0x6f, 0x77, 0x22, 0x42, 0x0a, 0x0f, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49,
+	0x6e, 0x66, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61,
+	0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xbf, 0x01, 0x0a, 0x12, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x12,
+	0x25, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x0b, 0x75,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a,
+	0x0d, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x02, 0x52, 0x0d, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x64, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x75, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x64, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x50, 0x0a, 0x16, 0x44, 0x65,
+	0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x4c, 0x69, 0x73, 0x74, 0x12, 0x36, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x72,
+	0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xd7, 0x03, 0x0a,
+	0x0f, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x21, 0x0a, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x44,
+	0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x25, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x08, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x05, 0x52, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x09,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x06, 0x52, 0x09, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x75,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03,
+	0x48, 0x07, 0x52, 0x0b, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88,
+	0x01, 0x01, 0x12, 0x29, 0x0a, 0x0d, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x48, 0x08, 0x52, 0x0d, 0x64, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a,
+	0x0a, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x44, 0x42, 0x09, 0x0a, 0x07, 0x5f,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x64, 0x65, 0x73, 0x74, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x54, 0x69, 0x6d, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61,
+	0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x4a, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x33, 0x0a,
+	0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x78,
+	0x79, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x22, 0xaf, 0x01, 0x0a, 0x0e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x54, 0x72,
+	0x61, 0x66, 0x66, 0x69, 0x63, 0x12, 0x1d, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x0b, 0x75, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0d, 0x64,
+	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x02, 0x52, 0x0d, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x72, 0x79, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x22, 0x48, 0x0a, 0x12, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x54,
+	0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x05, 0x69, 0x74,
+	0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x69, 0x65, 0x72,
+	0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79,
+	0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xc6,
+	0x02, 0x0a, 0x11, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61,
+	0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x73, 0x12, 0x39, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1e, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
+	0x2e, 0x50, 0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x48, 0x01, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x75, 0x6e, 0x64, 0x65,
+	0x72, 0x6c, 0x61, 0x79, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03, 0x52, 0x09, 0x75,
+	0x6e, 0x64, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x48, 0x04, 0x52,
+	0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x75, 0x6e, 0x64, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x4e, 0x0a, 0x15, 0x50, 0x6f, 0x72, 0x74, 0x42,
+	0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x35, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50,
+	0x6f, 0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x87, 0x02, 0x0a, 0x0d, 0x53, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x09, 0x69, 0x70, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09,
+	0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04,
+	0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x04, 0x70, 0x6f,
+	0x72, 0x74, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
+	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x48, 0x02, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x72, 0x74, 0x74, 0x4d, 0x69,
+	0x63, 0x72, 0x6f, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03, 0x52, 0x09, 0x72, 0x74,
+	0x74, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x69, 0x70, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x0b, 0x0a, 0x09,
+	0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x72, 0x74,
+	0x74, 0x4d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x46, 0x0a, 0x11, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4c, 0x61, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4c, 0x61, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x40, 0x0a, 0x0a, 0x54, 0x68, 0x72,
+	0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x23, 0x0a, 0x0a, 0x74, 0x68, 0x72, 0x65, 0x61,
+	0x64, 0x44, 0x75, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x74,
+	0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x75, 0x6d, 0x70, 0x22, 0xb1, 0x02, 0x0a, 0x10,
+	0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73,
+	0x12, 0x21, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x09, 0x68, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x68, 0x65, 0x61, 0x70, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x48, 0x01, 0x52, 0x0b, 0x68, 0x65, 0x61, 0x70,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x6d, 0x61,
+	0x78, 0x48, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x02, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x48, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x65, 0x61,
+	0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x48, 0x03, 0x52, 0x0f,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x88,
+	0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x48, 0x04, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x68, 0x65, 0x61, 0x70,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x68, 0x65, 0x61, 0x70, 0x4f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x73, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x48, 0x65, 0x61,
+	0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x48, 0x65, 0x61, 0x70, 0x42, 0x79, 0x74, 0x65, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x73,
+	0x74, 0x61, 0x63, 0x6b, 0x42, 0x79, 0x74, 0x65, 0x73, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22,
+	0x78, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x05, 0x4d, 0x61,
+	0x6a, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x05, 0x4d, 0x61, 0x6a,
+	0x6f, 0x72, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x05, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x88, 0x01, 0x01,
+	0x12, 0x19, 0x0a, 0x05, 0x50, 0x61, 0x74, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48,
+	0x02, 0x52, 0x05, 0x50, 0x61, 0x74, 0x63, 0x68, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f,
+	0x4d, 0x61, 0x6a, 0x6f, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x42,
+	0x08, 0x0a, 0x06, 0x5f, 0x50, 0x61, 0x74, 0x63, 0x68, 0x22, 0x2f, 0x0a, 0x08, 0x4b, 0x65, 0x79,
+	0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x19, 0x0a, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x05, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x88, 0x01, 0x01,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x65, 0x70, 0x6f, 0x63, 0x68, 0x22, 0x54, 0x0a, 0x11, 0x46, 0x6f,
+	0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x35, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4c, 0x6f,
+	0x67, 0x67, 0x69, 0x6e, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x65,
+	0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c,
+	0x22, 0xab, 0x02, 0x0a, 0x08, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x33, 0x0a,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x00, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x35, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1a, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
+	0x2e, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x48, 0x01, 0x52,
+	0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6c, 0x65, 0x76,
+	0x65, 0x6c, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x78,
+	0x0a, 0x12, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x6d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x52, 0x07, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xae, 0x01, 0x0a, 0x13, 0x43, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x53, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x28, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x12, 0x37, 0x0a, 0x0a, 0x75, 0x73,
+	0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x52, 0x07, 0x74, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x2a, 0x82, 0x01, 0x0a, 0x10, 0x50, 0x6f,
+	0x72, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1e,
+	0x0a, 0x1a, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x50, 0x4f, 0x52, 0x54, 0x5f, 0x42,
+	0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x10, 0x00, 0x12, 0x19,
+	0x0a, 0x15, 0x50, 0x4f, 0x52, 0x54, 0x5f, 0x42, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x53,
+	0x54, 0x41, 0x52, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x1a, 0x0a, 0x16, 0x50, 0x4f, 0x52,
+	0x54, 0x5f, 0x42, 0x49, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x45, 0x4e,
+	0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x50, 0x4f, 0x52, 0x54, 0x5f, 0x42, 0x49,
+	0x4e, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x42, 0x30,
+	0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6e, 0x66,
+	0x65, 0x69, 0x6e, 0x2f, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2f, 0x76, 0x33, 0x2f, 0x70, 0x6b, 0x67,
+	0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -743,33 +2254,81 @@ func file_appctl_proto_misc_proto_rawDescGZIP() []byte {
 	return file_appctl_proto_misc_proto_rawDescData
 }
 
-var file_appctl_proto_misc_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_appctl_proto_misc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_appctl_proto_misc_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
 var file_appctl_proto_misc_proto_goTypes = []interface{}{
-	(*Metrics)(nil),               // 0: mieru.appctl.Metrics
-	(*UserWithMetrics)(nil),       // 1: mieru.appctl.UserWithMetrics
-	(*UserWithMetricsList)(nil),   // 2: mieru.appctl.UserWithMetricsList
-	(*ProfileSavePath)(nil),       // 3: mieru.appctl.ProfileSavePath
-	(*SessionInfo)(nil),           // 4: mieru.appctl.SessionInfo
-	(*SessionInfoList)(nil),       // 5: mieru.appctl.SessionInfoList
-	(*ThreadDump)(nil),            // 6: mieru.appctl.ThreadDump
-	(*MemoryStatistics)(nil),      // 7: mieru.appctl.MemoryStatistics
-	(*Version)(nil),               // 8: mieru.appctl.Version
-	(*User)(nil),                  // 9: mieru.appctl.User
-	(*metricspb.Metric)(nil),      // 10: mieru.metrics.Metric
-	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+	(PortBindingState)(0),            // 0: mieru.appctl.PortBindingState
+	(*Metrics)(nil),                  // 1: mieru.appctl.Metrics
+	(*UserWithMetrics)(nil),          // 2: mieru.appctl.UserWithMetrics
+	(*UserWithMetricsList)(nil),      // 3: mieru.appctl.UserWithMetricsList
+	(*UserNameList)(nil),             // 4: mieru.appctl.UserNameList
+	(*UserGroupWithMetrics)(nil),     // 5: mieru.appctl.UserGroupWithMetrics
+	(*UserGroupWithMetricsList)(nil), // 6: mieru.appctl.UserGroupWithMetricsList
+	(*ProfileSavePath)(nil),          // 7: mieru.appctl.ProfileSavePath
+	(*SessionInfo)(nil),              // 8: mieru.appctl.SessionInfo
+	(*KillSessionRequest)(nil),       // 9: mieru.appctl.KillSessionRequest
+	(*ReloadClientRequest)(nil),      // 10: mieru.appctl.ReloadClientRequest
+	(*SessionInfoList)(nil),          // 11: mieru.appctl.SessionInfoList
+	(*DestinationTraffic)(nil),       // 12: mieru.appctl.DestinationTraffic
+	(*DestinationTrafficList)(nil),   // 13: mieru.appctl.DestinationTrafficList
+	(*ProxyConnection)(nil),          // 14: mieru.appctl.ProxyConnection
+	(*ProxyConnectionList)(nil),      // 15: mieru.appctl.ProxyConnectionList
+	(*CountryTraffic)(nil),           // 16: mieru.appctl.CountryTraffic
+	(*CountryTrafficList)(nil),       // 17: mieru.appctl.CountryTrafficList
+	(*PortBindingStatus)(nil),        // 18: mieru.appctl.PortBindingStatus
+	(*PortBindingStatusList)(nil),    // 19: mieru.appctl.PortBindingStatusList
+	(*ServerLatency)(nil),            // 20: mieru.appctl.ServerLatency
+	(*ServerLatencyList)(nil),        // 21: mieru.appctl.ServerLatencyList
+	(*ThreadDump)(nil),               // 22: mieru.appctl.ThreadDump
+	(*MemoryStatistics)(nil),         // 23: mieru.appctl.MemoryStatistics
+	(*Version)(nil),                  // 24: mieru.appctl.Version
+	(*KeyEpoch)(nil),                 // 25: mieru.appctl.KeyEpoch
+	(*FollowLogsRequest)(nil),        // 26: mieru.appctl.FollowLogsRequest
+	(*LogEntry)(nil),                 // 27: mieru.appctl.LogEntry
+	(*ClusterSyncRequest)(nil),       // 28: mieru.appctl.ClusterSyncRequest
+	(*ClusterSyncResponse)(nil),      // 29: mieru.appctl.ClusterSyncResponse
+	nil,                              // 30: mieru.appctl.LogEntry.FieldsEntry
+	(*User)(nil),                     // 31: mieru.appctl.User
+	(*metricspb.Metric)(nil),         // 32: mieru.metrics.Metric
+	(*UserGroup)(nil),                // 33: mieru.appctl.UserGroup
+	(*timestamppb.Timestamp)(nil),    // 34: google.protobuf.Timestamp
+	(TransportProtocol)(0),           // 35: mieru.appctl.TransportProtocol
+	(LoggingLevel)(0),                // 36: mieru.appctl.LoggingLevel
+	(*metricspb.MetricGroup)(nil),    // 37: mieru.metrics.MetricGroup
 }
 var file_appctl_proto_misc_proto_depIdxs = []int32{
-	9,  // 0: mieru.appctl.UserWithMetrics.user:type_name -> mieru.appctl.User
-	10, // 1: mieru.appctl.UserWithMetrics.metrics:type_name -> mieru.metrics.Metric
-	1,  // 2: mieru.appctl.UserWithMetricsList.items:type_name -> mieru.appctl.UserWithMetrics
-	11, // 3: mieru.appctl.SessionInfo.lastRecvTime:type_name -> google.protobuf.Timestamp
-	11, // 4: mieru.appctl.SessionInfo.lastSendTime:type_name -> google.protobuf.Timestamp
-	4,  // 5: mieru.appctl.SessionInfoList.items:type_name -> mieru.appctl.SessionInfo
-	6,  // [6:6] is the sub-list for method output_type
-	6,  // [6:6] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	31, // 0: mieru.appctl.UserWithMetrics.user:type_name -> mieru.appctl.User
+	32, // 1: mieru.appctl.UserWithMetrics.metrics:type_name -> mieru.metrics.Metric
+	2,  // 2: mieru.appctl.UserWithMetricsList.items:type_name -> mieru.appctl.UserWithMetrics
+	33, // 3: mieru.appctl.UserGroupWithMetrics.group:type_name -> mieru.appctl.UserGroup
+	32, // 4: mieru.appctl.UserGroupWithMetrics.metrics:type_name -> mieru.metrics.Metric
+	5,  // 5: mieru.appctl.UserGroupWithMetricsList.items:type_name -> mieru.appctl.UserGroupWithMetrics
+	34, // 6: mieru.appctl.SessionInfo.lastRecvTime:type_name -> google.protobuf.Timestamp
+	34, // 7: mieru.appctl.SessionInfo.lastSendTime:type_name -> google.protobuf.Timestamp
+	34, // 8: mieru.appctl.SessionInfo.startTime:type_name -> google.protobuf.Timestamp
+	8,  // 9: mieru.appctl.SessionInfoList.items:type_name -> mieru.appctl.SessionInfo
+	12, // 10: mieru.appctl.DestinationTrafficList.items:type_name -> mieru.appctl.DestinationTraffic
+	34, // 11: mieru.appctl.ProxyConnection.startTime:type_name -> google.protobuf.Timestamp
+	14, // 12: mieru.appctl.ProxyConnectionList.items:type_name -> mieru.appctl.ProxyConnection
+	16, // 13: mieru.appctl.CountryTrafficList.items:type_name -> mieru.appctl.CountryTraffic
+	35, // 14: mieru.appctl.PortBindingStatus.protocols:type_name -> mieru.appctl.TransportProtocol
+	0,  // 15: mieru.appctl.PortBindingStatus.state:type_name -> mieru.appctl.PortBindingState
+	18, // 16: mieru.appctl.PortBindingStatusList.items:type_name -> mieru.appctl.PortBindingStatus
+	35, // 17: mieru.appctl.ServerLatency.protocol:type_name -> mieru.appctl.TransportProtocol
+	20, // 18: mieru.appctl.ServerLatencyList.items:type_name -> mieru.appctl.ServerLatency
+	36, // 19: mieru.appctl.FollowLogsRequest.level:type_name -> mieru.appctl.LoggingLevel
+	34, // 20: mieru.appctl.LogEntry.time:type_name -> google.protobuf.Timestamp
+	36, // 21: mieru.appctl.LogEntry.level:type_name -> mieru.appctl.LoggingLevel
+	30, // 22: mieru.appctl.LogEntry.fields:type_name -> mieru.appctl.LogEntry.FieldsEntry
+	37, // 23: mieru.appctl.ClusterSyncRequest.traffic:type_name -> mieru.metrics.MetricGroup
+	31, // 24: mieru.appctl.ClusterSyncResponse.users:type_name -> mieru.appctl.User
+	33, // 25: mieru.appctl.ClusterSyncResponse.userGroups:type_name -> mieru.appctl.UserGroup
+	37, // 26: mieru.appctl.ClusterSyncResponse.traffic:type_name -> mieru.metrics.MetricGroup
+	27, // [27:27] is the sub-list for method output_type
+	27, // [27:27] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_appctl_proto_misc_proto_init() }
@@ -778,6 +2337,7 @@ func file_appctl_proto_misc_proto_init() {
 		return
 	}
 	file_appctl_proto_base_proto_init()
+	file_appctl_proto_servercfg_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_appctl_proto_misc_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Metrics); i {
@@ -816,7 +2376,7 @@ func file_appctl_proto_misc_proto_init() {
 			}
 		}
 		file_appctl_proto_misc_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProfileSavePath); i {
+			switch v := v.(*UserNameList); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -828,7 +2388,7 @@ func file_appctl_proto_misc_proto_init() {
 			}
 		}
 		file_appctl_proto_misc_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SessionInfo); i {
+			switch v := v.(*UserGroupWithMetrics); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -840,7 +2400,7 @@ func file_appctl_proto_misc_proto_init() {
 			}
 		}
 		file_appctl_proto_misc_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SessionInfoList); i {
+			switch v := v.(*UserGroupWithMetricsList); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -852,7 +2412,7 @@ func file_appctl_proto_misc_proto_init() {
 			}
 		}
 		file_appctl_proto_misc_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ThreadDump); i {
+			switch v := v.(*ProfileSavePath); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -864,7 +2424,7 @@ func file_appctl_proto_misc_proto_init() {
 			}
 		}
 		file_appctl_proto_misc_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MemoryStatistics); i {
+			switch v := v.(*SessionInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -876,6 +2436,186 @@ func file_appctl_proto_misc_proto_init() {
 			}
 		}
 		file_appctl_proto_misc_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KillSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReloadClientRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionInfoList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DestinationTraffic); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DestinationTrafficList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProxyConnection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProxyConnectionList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountryTraffic); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountryTrafficList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PortBindingStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PortBindingStatusList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerLatency); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerLatencyList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ThreadDump); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MemoryStatistics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Version); i {
 			case 0:
 				return &v.state
@@ -887,26 +2627,99 @@ func file_appctl_proto_misc_proto_init() {
 				return nil
 			}
 		}
+		file_appctl_proto_misc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyEpoch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FollowLogsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClusterSyncRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_misc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClusterSyncResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_appctl_proto_misc_proto_msgTypes[0].OneofWrappers = []interface{}{}
 	file_appctl_proto_misc_proto_msgTypes[1].OneofWrappers = []interface{}{}
-	file_appctl_proto_misc_proto_msgTypes[3].OneofWrappers = []interface{}{}
 	file_appctl_proto_misc_proto_msgTypes[4].OneofWrappers = []interface{}{}
 	file_appctl_proto_misc_proto_msgTypes[6].OneofWrappers = []interface{}{}
 	file_appctl_proto_misc_proto_msgTypes[7].OneofWrappers = []interface{}{}
 	file_appctl_proto_misc_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[9].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[15].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[17].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[19].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[21].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[22].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[23].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[24].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[25].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[26].OneofWrappers = []interface{}{}
+	file_appctl_proto_misc_proto_msgTypes[27].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_appctl_proto_misc_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      1,
+			NumMessages:   30,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_appctl_proto_misc_proto_goTypes,
 		DependencyIndexes: file_appctl_proto_misc_proto_depIdxs,
+		EnumInfos:         file_appctl_proto_misc_proto_enumTypes,
 		MessageInfos:      file_appctl_proto_misc_proto_msgTypes,
 	}.Build()
 	File_appctl_proto_misc_proto = out.File
@@ -35,6 +35,55 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MaintenanceAction int32
+
+const (
+	// Stop and start the proxy. All the sessions are closed.
+	MaintenanceAction_RESTART_PROXY MaintenanceAction = 0
+	// Reload server config, e.g. to apply new user passwords.
+	// Sessions using unchanged port bindings are not closed.
+	MaintenanceAction_RELOAD_CONFIG MaintenanceAction = 1
+)
+
+// Enum value maps for MaintenanceAction.
+var (
+	MaintenanceAction_name = map[int32]string{
+		0: "RESTART_PROXY",
+		1: "RELOAD_CONFIG",
+	}
+	MaintenanceAction_value = map[string]int32{
+		"RESTART_PROXY": 0,
+		"RELOAD_CONFIG": 1,
+	}
+)
+
+func (x MaintenanceAction) Enum() *MaintenanceAction {
+	p := new(MaintenanceAction)
+	*p = x
+	return p
+}
+
+func (x MaintenanceAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MaintenanceAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_appctl_proto_servercfg_proto_enumTypes[0].Descriptor()
+}
+
+func (MaintenanceAction) Type() protoreflect.EnumType {
+	return &file_appctl_proto_servercfg_proto_enumTypes[0]
+}
+
+func (x MaintenanceAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MaintenanceAction.Descriptor instead.
+func (MaintenanceAction) EnumDescriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{0}
+}
+
 type ProxyProtocol int32
 
 const (
@@ -65,11 +114,11 @@ func (x ProxyProtocol) String() string {
 }
 
 func (ProxyProtocol) Descriptor() protoreflect.EnumDescriptor {
-	return file_appctl_proto_servercfg_proto_enumTypes[0].Descriptor()
+	return file_appctl_proto_servercfg_proto_enumTypes[1].Descriptor()
 }
 
 func (ProxyProtocol) Type() protoreflect.EnumType {
-	return &file_appctl_proto_servercfg_proto_enumTypes[0]
+	return &file_appctl_proto_servercfg_proto_enumTypes[1]
 }
 
 func (x ProxyProtocol) Number() protoreflect.EnumNumber {
@@ -78,7 +127,7 @@ func (x ProxyProtocol) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ProxyProtocol.Descriptor instead.
 func (ProxyProtocol) EnumDescriptor() ([]byte, []int) {
-	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{0}
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{1}
 }
 
 type EgressAction int32
@@ -117,11 +166,11 @@ func (x EgressAction) String() string {
 }
 
 func (EgressAction) Descriptor() protoreflect.EnumDescriptor {
-	return file_appctl_proto_servercfg_proto_enumTypes[1].Descriptor()
+	return file_appctl_proto_servercfg_proto_enumTypes[2].Descriptor()
 }
 
 func (EgressAction) Type() protoreflect.EnumType {
-	return &file_appctl_proto_servercfg_proto_enumTypes[1]
+	return &file_appctl_proto_servercfg_proto_enumTypes[2]
 }
 
 func (x EgressAction) Number() protoreflect.EnumNumber {
@@ -130,7 +179,7 @@ func (x EgressAction) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use EgressAction.Descriptor instead.
 func (EgressAction) EnumDescriptor() ([]byte, []int) {
-	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{1}
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{2}
 }
 
 type ServerConfig struct {
@@ -154,6 +203,67 @@ type ServerConfig struct {
 	// How to resolve IP address when the client send a request
 	// with a domain name.
 	Dns *DNS `protobuf:"bytes,7,opt,name=dns,proto3,oneof" json:"dns,omitempty"`
+	// User groups. Each group has its own egress proxies and rules,
+	// quotas and metrics.
+	UserGroups []*UserGroup `protobuf:"bytes,8,rep,name=userGroups,proto3" json:"userGroups,omitempty"`
+	// Daily maintenance window of the server.
+	Maintenance *MaintenanceWindow `protobuf:"bytes,9,opt,name=maintenance,proto3,oneof" json:"maintenance,omitempty"`
+	// Where the server listens to relay UDP packets of socks5
+	// UDP associate requests.
+	UdpRelay *UDPRelay `protobuf:"bytes,10,opt,name=udpRelay,proto3,oneof" json:"udpRelay,omitempty"`
+	// Collect egress traffic of each destination country.
+	CountryTraffic *CountryTrafficStatistics `protobuf:"bytes,11,opt,name=countryTraffic,proto3,oneof" json:"countryTraffic,omitempty"`
+	// Drop privileges after listening to the port bindings.
+	// This is only supported on Linux.
+	DropPrivileges *DropPrivileges `protobuf:"bytes,12,opt,name=dropPrivileges,proto3,oneof" json:"dropPrivileges,omitempty"`
+	// Sandbox the proxy server after it starts.
+	// This is only supported on Linux.
+	Sandbox *Sandbox `protobuf:"bytes,13,opt,name=sandbox,proto3,oneof" json:"sandbox,omitempty"`
+	// Options of port bindings using the WEBSOCKET protocol.
+	WebSocket *WebSocketConfig `protobuf:"bytes,14,opt,name=webSocket,proto3,oneof" json:"webSocket,omitempty"`
+	// Wrap TCP port bindings in a TLS layer.
+	TlsCamouflage *TLSCamouflageConfig `protobuf:"bytes,15,opt,name=tlsCamouflage,proto3,oneof" json:"tlsCamouflage,omitempty"`
+	// Serve a web site to the connections that fail authentication
+	// on TCP and WEBSOCKET port bindings.
+	Decoy *DecoyConfig `protobuf:"bytes,16,opt,name=decoy,proto3,oneof" json:"decoy,omitempty"`
+	// Rotate the keys derived from user passwords periodically.
+	// Proxy clients must use the same setting.
+	KeyRotation *KeyRotationConfig `protobuf:"bytes,17,opt,name=keyRotation,proto3,oneof" json:"keyRotation,omitempty"`
+	// Only accept new connections to the port bindings from IP addresses
+	// that probed the port knocking sequence. Proxy clients must use
+	// the same sequence.
+	PortKnocking *PortKnockingConfig `protobuf:"bytes,18,opt,name=portKnocking,proto3,oneof" json:"portKnocking,omitempty"`
+	// GeoIP and geosite databases used by egress rules.
+	GeoDatabases *GeoDatabases `protobuf:"bytes,19,opt,name=geoDatabases,proto3,oneof" json:"geoDatabases,omitempty"`
+	// Reject the requests to the domain names of ad and malware sites.
+	Blocklist *Blocklist `protobuf:"bytes,20,opt,name=blocklist,proto3,oneof" json:"blocklist,omitempty"`
+	// Accept PROXY protocol headers on TCP and WEBSOCKET port bindings
+	// from a TCP load balancer in front of the proxy server.
+	ProxyProtocol *ProxyProtocolConfig `protobuf:"bytes,21,opt,name=proxyProtocol,proto3,oneof" json:"proxyProtocol,omitempty"`
+	// Record each proxied connection in an audit log file.
+	AuditLog *AuditLog `protobuf:"bytes,22,opt,name=auditLog,proto3,oneof" json:"auditLog,omitempty"`
+	// Server log format.
+	LogFormat *LogFormat `protobuf:"varint,23,opt,name=logFormat,proto3,enum=mieru.appctl.LogFormat,oneof" json:"logFormat,omitempty"`
+	// Write server logs to a file with rotation.
+	LogFile *LogFile `protobuf:"bytes,24,opt,name=logFile,proto3,oneof" json:"logFile,omitempty"`
+	// Expose the management RPC on a TCP port secured with mutual TLS,
+	// so the server can be administered remotely.
+	RemoteManagement *RemoteManagement `protobuf:"bytes,25,opt,name=remoteManagement,proto3,oneof" json:"remoteManagement,omitempty"`
+	// Serve an HTTP JSON API that manages the server,
+	// for web dashboards and scripts.
+	ManagementGateway *ManagementGateway `protobuf:"bytes,26,opt,name=managementGateway,proto3,oneof" json:"managementGateway,omitempty"`
+	// If set, TCP and WEBSOCKET port bindings are served by this chain of
+	// SIP003 plugins. The plugins listen to the port bindings, and mieru
+	// listens to loopback addresses. The first plugin is the closest to
+	// mieru. The proxy clients must use the same chain.
+	Sip003Plugins []*SIP003Plugin `protobuf:"bytes,27,rep,name=sip003Plugins,proto3" json:"sip003Plugins,omitempty"`
+	// Version of the server config layout. It is set by mieru when the
+	// config is stored, and used to upgrade a config stored by an older
+	// version of mieru. Don't set it manually.
+	SchemaVersion *uint32 `protobuf:"varint,28,opt,name=schemaVersion,proto3,oneof" json:"schemaVersion,omitempty"`
+	// Synchronize users, user groups and quota counters with the primary
+	// server of a cluster, so multiple servers share the same users.
+	Cluster *ClusterConfig `protobuf:"bytes,29,opt,name=cluster,proto3,oneof" json:"cluster,omitempty"`
 }
 
 func (x *ServerConfig) Reset() {
@@ -209,67 +319,1474 @@ func (x *ServerConfig) GetAdvancedSettings() *ServerAdvancedSettings {
 	return nil
 }
 
-func (x *ServerConfig) GetLoggingLevel() LoggingLevel {
-	if x != nil && x.LoggingLevel != nil {
-		return *x.LoggingLevel
+func (x *ServerConfig) GetLoggingLevel() LoggingLevel {
+	if x != nil && x.LoggingLevel != nil {
+		return *x.LoggingLevel
+	}
+	return LoggingLevel_DEFAULT
+}
+
+func (x *ServerConfig) GetMtu() int32 {
+	if x != nil && x.Mtu != nil {
+		return *x.Mtu
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetEgress() *Egress {
+	if x != nil {
+		return x.Egress
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetDns() *DNS {
+	if x != nil {
+		return x.Dns
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetUserGroups() []*UserGroup {
+	if x != nil {
+		return x.UserGroups
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetMaintenance() *MaintenanceWindow {
+	if x != nil {
+		return x.Maintenance
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetUdpRelay() *UDPRelay {
+	if x != nil {
+		return x.UdpRelay
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetCountryTraffic() *CountryTrafficStatistics {
+	if x != nil {
+		return x.CountryTraffic
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetDropPrivileges() *DropPrivileges {
+	if x != nil {
+		return x.DropPrivileges
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetSandbox() *Sandbox {
+	if x != nil {
+		return x.Sandbox
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetWebSocket() *WebSocketConfig {
+	if x != nil {
+		return x.WebSocket
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetTlsCamouflage() *TLSCamouflageConfig {
+	if x != nil {
+		return x.TlsCamouflage
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetDecoy() *DecoyConfig {
+	if x != nil {
+		return x.Decoy
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetKeyRotation() *KeyRotationConfig {
+	if x != nil {
+		return x.KeyRotation
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetPortKnocking() *PortKnockingConfig {
+	if x != nil {
+		return x.PortKnocking
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetGeoDatabases() *GeoDatabases {
+	if x != nil {
+		return x.GeoDatabases
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetBlocklist() *Blocklist {
+	if x != nil {
+		return x.Blocklist
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetProxyProtocol() *ProxyProtocolConfig {
+	if x != nil {
+		return x.ProxyProtocol
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetAuditLog() *AuditLog {
+	if x != nil {
+		return x.AuditLog
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetLogFormat() LogFormat {
+	if x != nil && x.LogFormat != nil {
+		return *x.LogFormat
+	}
+	return LogFormat_LOG_FORMAT_TEXT
+}
+
+func (x *ServerConfig) GetLogFile() *LogFile {
+	if x != nil {
+		return x.LogFile
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetRemoteManagement() *RemoteManagement {
+	if x != nil {
+		return x.RemoteManagement
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetManagementGateway() *ManagementGateway {
+	if x != nil {
+		return x.ManagementGateway
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetSip003Plugins() []*SIP003Plugin {
+	if x != nil {
+		return x.Sip003Plugins
+	}
+	return nil
+}
+
+func (x *ServerConfig) GetSchemaVersion() uint32 {
+	if x != nil && x.SchemaVersion != nil {
+		return *x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *ServerConfig) GetCluster() *ClusterConfig {
+	if x != nil {
+		return x.Cluster
+	}
+	return nil
+}
+
+type ClusterConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// URL of the management gateway of the primary server, e.g.
+	// "https://primary.example.com:8080". If set, this server is a replica,
+	// and its users and user groups are replaced by the primary server.
+	// It must use HTTPS, unless the host is a loopback address.
+	PrimaryURL *string `protobuf:"bytes,1,opt,name=primaryURL,proto3,oneof" json:"primaryURL,omitempty"`
+	// One of the cluster tokens of the management gateway of the primary server.
+	Token *string `protobuf:"bytes,2,opt,name=token,proto3,oneof" json:"token,omitempty"`
+	// Name of this server in the cluster. It must be unique in the cluster.
+	// If unset, the host name is used.
+	NodeName *string `protobuf:"bytes,3,opt,name=nodeName,proto3,oneof" json:"nodeName,omitempty"`
+	// Interval to synchronize with the primary server.
+	// If unset, the default value is "1m". The minimum value is "10s".
+	SyncInterval *string `protobuf:"bytes,4,opt,name=syncInterval,proto3,oneof" json:"syncInterval,omitempty"`
+	// Absolute path of the CA certificate file in PEM format that verifies
+	// the TLS certificate of the primary server. If unset, the system
+	// certificate pool is used.
+	CaFile *string `protobuf:"bytes,5,opt,name=caFile,proto3,oneof" json:"caFile,omitempty"`
+}
+
+func (x *ClusterConfig) Reset() {
+	*x = ClusterConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClusterConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterConfig) ProtoMessage() {}
+
+func (x *ClusterConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterConfig.ProtoReflect.Descriptor instead.
+func (*ClusterConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ClusterConfig) GetPrimaryURL() string {
+	if x != nil && x.PrimaryURL != nil {
+		return *x.PrimaryURL
+	}
+	return ""
+}
+
+func (x *ClusterConfig) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+func (x *ClusterConfig) GetNodeName() string {
+	if x != nil && x.NodeName != nil {
+		return *x.NodeName
+	}
+	return ""
+}
+
+func (x *ClusterConfig) GetSyncInterval() string {
+	if x != nil && x.SyncInterval != nil {
+		return *x.SyncInterval
+	}
+	return ""
+}
+
+func (x *ClusterConfig) GetCaFile() string {
+	if x != nil && x.CaFile != nil {
+		return *x.CaFile
+	}
+	return ""
+}
+
+type AuditLog struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Absolute path of the audit log file. If empty, the audit log is disabled.
+	Path *string `protobuf:"bytes,1,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	// Rotate the audit log file when its size exceeds this number of megabytes.
+	// If it is 0, the file is not rotated.
+	MaxSizeMB *int32 `protobuf:"varint,2,opt,name=maxSizeMB,proto3,oneof" json:"maxSizeMB,omitempty"`
+	// Number of rotated audit log files to keep.
+	MaxBackups *int32 `protobuf:"varint,3,opt,name=maxBackups,proto3,oneof" json:"maxBackups,omitempty"`
+	// Remove the rotated audit log files older than this number of days.
+	// If it is 0, rotated files are not removed by age.
+	MaxAgeDays *int32 `protobuf:"varint,4,opt,name=maxAgeDays,proto3,oneof" json:"maxAgeDays,omitempty"`
+	// Compress the rotated audit log files with gzip.
+	Compress *bool `protobuf:"varint,5,opt,name=compress,proto3,oneof" json:"compress,omitempty"`
+}
+
+func (x *AuditLog) Reset() {
+	*x = AuditLog{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLog) ProtoMessage() {}
+
+func (x *AuditLog) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLog.ProtoReflect.Descriptor instead.
+func (*AuditLog) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AuditLog) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *AuditLog) GetMaxSizeMB() int32 {
+	if x != nil && x.MaxSizeMB != nil {
+		return *x.MaxSizeMB
+	}
+	return 0
+}
+
+func (x *AuditLog) GetMaxBackups() int32 {
+	if x != nil && x.MaxBackups != nil {
+		return *x.MaxBackups
+	}
+	return 0
+}
+
+func (x *AuditLog) GetMaxAgeDays() int32 {
+	if x != nil && x.MaxAgeDays != nil {
+		return *x.MaxAgeDays
+	}
+	return 0
+}
+
+func (x *AuditLog) GetCompress() bool {
+	if x != nil && x.Compress != nil {
+		return *x.Compress
+	}
+	return false
+}
+
+type LogFile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Absolute path of the server log file. If empty, the server
+	// writes logs to standard output, which is collected by systemd.
+	Path *string `protobuf:"bytes,1,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	// Rotate the log file when its size exceeds this number of megabytes.
+	// If it is 0, the file is not rotated.
+	MaxSizeMB *int32 `protobuf:"varint,2,opt,name=maxSizeMB,proto3,oneof" json:"maxSizeMB,omitempty"`
+	// Number of rotated log files to keep.
+	MaxBackups *int32 `protobuf:"varint,3,opt,name=maxBackups,proto3,oneof" json:"maxBackups,omitempty"`
+	// Remove the rotated log files older than this number of days.
+	// If it is 0, rotated files are not removed by age.
+	MaxAgeDays *int32 `protobuf:"varint,4,opt,name=maxAgeDays,proto3,oneof" json:"maxAgeDays,omitempty"`
+	// Compress the rotated log files with gzip.
+	Compress *bool `protobuf:"varint,5,opt,name=compress,proto3,oneof" json:"compress,omitempty"`
+}
+
+func (x *LogFile) Reset() {
+	*x = LogFile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LogFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogFile) ProtoMessage() {}
+
+func (x *LogFile) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogFile.ProtoReflect.Descriptor instead.
+func (*LogFile) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LogFile) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *LogFile) GetMaxSizeMB() int32 {
+	if x != nil && x.MaxSizeMB != nil {
+		return *x.MaxSizeMB
+	}
+	return 0
+}
+
+func (x *LogFile) GetMaxBackups() int32 {
+	if x != nil && x.MaxBackups != nil {
+		return *x.MaxBackups
+	}
+	return 0
+}
+
+func (x *LogFile) GetMaxAgeDays() int32 {
+	if x != nil && x.MaxAgeDays != nil {
+		return *x.MaxAgeDays
+	}
+	return 0
+}
+
+func (x *LogFile) GetCompress() bool {
+	if x != nil && x.Compress != nil {
+		return *x.Compress
+	}
+	return false
+}
+
+type RemoteManagement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// TCP port of the management endpoint. If it is 0, the endpoint is disabled.
+	Port *int32 `protobuf:"varint,1,opt,name=port,proto3,oneof" json:"port,omitempty"`
+	// IP address to listen to. If empty, listen to all the IP addresses.
+	BindIP *string `protobuf:"bytes,2,opt,name=bindIP,proto3,oneof" json:"bindIP,omitempty"`
+	// Absolute path of the server TLS certificate file in PEM format.
+	CertificateFile *string `protobuf:"bytes,3,opt,name=certificateFile,proto3,oneof" json:"certificateFile,omitempty"`
+	// Absolute path of the server TLS private key file in PEM format.
+	PrivateKeyFile *string `protobuf:"bytes,4,opt,name=privateKeyFile,proto3,oneof" json:"privateKeyFile,omitempty"`
+	// Absolute path of the CA certificates in PEM format that issue
+	// the client certificates of the administrators.
+	ClientCAFile *string `protobuf:"bytes,5,opt,name=clientCAFile,proto3,oneof" json:"clientCAFile,omitempty"`
+	// Common names of the client certificates that are allowed to call
+	// the management RPC. If empty, any client certificate issued by
+	// the CA is allowed.
+	Admins []string `protobuf:"bytes,6,rep,name=admins,proto3" json:"admins,omitempty"`
+}
+
+func (x *RemoteManagement) Reset() {
+	*x = RemoteManagement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoteManagement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoteManagement) ProtoMessage() {}
+
+func (x *RemoteManagement) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoteManagement.ProtoReflect.Descriptor instead.
+func (*RemoteManagement) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RemoteManagement) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *RemoteManagement) GetBindIP() string {
+	if x != nil && x.BindIP != nil {
+		return *x.BindIP
+	}
+	return ""
+}
+
+func (x *RemoteManagement) GetCertificateFile() string {
+	if x != nil && x.CertificateFile != nil {
+		return *x.CertificateFile
+	}
+	return ""
+}
+
+func (x *RemoteManagement) GetPrivateKeyFile() string {
+	if x != nil && x.PrivateKeyFile != nil {
+		return *x.PrivateKeyFile
+	}
+	return ""
+}
+
+func (x *RemoteManagement) GetClientCAFile() string {
+	if x != nil && x.ClientCAFile != nil {
+		return *x.ClientCAFile
+	}
+	return ""
+}
+
+func (x *RemoteManagement) GetAdmins() []string {
+	if x != nil {
+		return x.Admins
+	}
+	return nil
+}
+
+type ManagementGateway struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// TCP port of the HTTP JSON API. If it is 0, the API is disabled.
+	Port *int32 `protobuf:"varint,1,opt,name=port,proto3,oneof" json:"port,omitempty"`
+	// IP address to listen to. If TLS certificate is not set,
+	// it must be a loopback address.
+	BindIP *string `protobuf:"bytes,2,opt,name=bindIP,proto3,oneof" json:"bindIP,omitempty"`
+	// Absolute path of the TLS certificate file in PEM format.
+	// If set, the API is served over HTTPS.
+	CertificateFile *string `protobuf:"bytes,3,opt,name=certificateFile,proto3,oneof" json:"certificateFile,omitempty"`
+	// Absolute path of the TLS private key file in PEM format.
+	PrivateKeyFile *string `protobuf:"bytes,4,opt,name=privateKeyFile,proto3,oneof" json:"privateKeyFile,omitempty"`
+	// Bearer tokens accepted by the API, except the cluster sync API.
+	// At least one token or cluster token is required.
+	// Each token must have at least 16 characters.
+	Tokens []string `protobuf:"bytes,5,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	// Serve a web dashboard at the root path, which shows the server
+	// status, error counters, user traffic and sessions.
+	Dashboard *bool `protobuf:"varint,6,opt,name=dashboard,proto3,oneof" json:"dashboard,omitempty"`
+	// Bearer tokens of the replica servers in the cluster. They are only
+	// accepted by the cluster sync API. Each token must have at least
+	// 16 characters, and must be different from the other tokens.
+	ClusterTokens []string `protobuf:"bytes,7,rep,name=clusterTokens,proto3" json:"clusterTokens,omitempty"`
+}
+
+func (x *ManagementGateway) Reset() {
+	*x = ManagementGateway{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ManagementGateway) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManagementGateway) ProtoMessage() {}
+
+func (x *ManagementGateway) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManagementGateway.ProtoReflect.Descriptor instead.
+func (*ManagementGateway) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ManagementGateway) GetPort() int32 {
+	if x != nil && x.Port != nil {
+		return *x.Port
+	}
+	return 0
+}
+
+func (x *ManagementGateway) GetBindIP() string {
+	if x != nil && x.BindIP != nil {
+		return *x.BindIP
+	}
+	return ""
+}
+
+func (x *ManagementGateway) GetCertificateFile() string {
+	if x != nil && x.CertificateFile != nil {
+		return *x.CertificateFile
+	}
+	return ""
+}
+
+func (x *ManagementGateway) GetPrivateKeyFile() string {
+	if x != nil && x.PrivateKeyFile != nil {
+		return *x.PrivateKeyFile
+	}
+	return ""
+}
+
+func (x *ManagementGateway) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *ManagementGateway) GetDashboard() bool {
+	if x != nil && x.Dashboard != nil {
+		return *x.Dashboard
+	}
+	return false
+}
+
+func (x *ManagementGateway) GetClusterTokens() []string {
+	if x != nil {
+		return x.ClusterTokens
+	}
+	return nil
+}
+
+type ProxyProtocolConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set to true, close the connections without a PROXY protocol header.
+	Require *bool `protobuf:"varint,1,opt,name=require,proto3,oneof" json:"require,omitempty"`
+	// IP addresses or CIDR ranges of the load balancers. Headers from other
+	// addresses are not accepted. If empty, headers from any address are accepted.
+	TrustedSources []string `protobuf:"bytes,2,rep,name=trustedSources,proto3" json:"trustedSources,omitempty"`
+}
+
+func (x *ProxyProtocolConfig) Reset() {
+	*x = ProxyProtocolConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProxyProtocolConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProxyProtocolConfig) ProtoMessage() {}
+
+func (x *ProxyProtocolConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProxyProtocolConfig.ProtoReflect.Descriptor instead.
+func (*ProxyProtocolConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProxyProtocolConfig) GetRequire() bool {
+	if x != nil && x.Require != nil {
+		return *x.Require
+	}
+	return false
+}
+
+func (x *ProxyProtocolConfig) GetTrustedSources() []string {
+	if x != nil {
+		return x.TrustedSources
+	}
+	return nil
+}
+
+type DecoyConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Absolute path of a directory of static web pages to serve.
+	StaticDirectory *string `protobuf:"bytes,1,opt,name=staticDirectory,proto3,oneof" json:"staticDirectory,omitempty"`
+	// URL of a web site, e.g. "https://www.example.com".
+	// HTTP requests are forwarded to it.
+	// Only one of staticDirectory and reverseProxyURL can be set.
+	ReverseProxyURL *string `protobuf:"bytes,2,opt,name=reverseProxyURL,proto3,oneof" json:"reverseProxyURL,omitempty"`
+}
+
+func (x *DecoyConfig) Reset() {
+	*x = DecoyConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecoyConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecoyConfig) ProtoMessage() {}
+
+func (x *DecoyConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecoyConfig.ProtoReflect.Descriptor instead.
+func (*DecoyConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DecoyConfig) GetStaticDirectory() string {
+	if x != nil && x.StaticDirectory != nil {
+		return *x.StaticDirectory
+	}
+	return ""
+}
+
+func (x *DecoyConfig) GetReverseProxyURL() string {
+	if x != nil && x.ReverseProxyURL != nil {
+		return *x.ReverseProxyURL
+	}
+	return ""
+}
+
+type Sandbox struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Enable the sandbox. After the server starts, it can't gain new
+	// privileges, the file system access is limited to the files used by
+	// the server, and the system calls not needed by a proxy are blocked.
+	// The features not supported by the kernel are skipped.
+	Enable *bool `protobuf:"varint,1,opt,name=enable,proto3,oneof" json:"enable,omitempty"`
+	// Additional absolute paths of files and directories that the server
+	// can read and write, e.g. the directory to save CPU and heap profiles.
+	WritablePaths []string `protobuf:"bytes,2,rep,name=writablePaths,proto3" json:"writablePaths,omitempty"`
+	// Only allow the system calls needed by the server, instead of blocking
+	// the system calls known to be dangerous. The other system calls fail
+	// with a permission error. This is the stricter seccomp profile.
+	SeccompAllowlist *bool `protobuf:"varint,3,opt,name=seccompAllowlist,proto3,oneof" json:"seccompAllowlist,omitempty"`
+}
+
+func (x *Sandbox) Reset() {
+	*x = Sandbox{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Sandbox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sandbox) ProtoMessage() {}
+
+func (x *Sandbox) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sandbox.ProtoReflect.Descriptor instead.
+func (*Sandbox) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Sandbox) GetEnable() bool {
+	if x != nil && x.Enable != nil {
+		return *x.Enable
+	}
+	return false
+}
+
+func (x *Sandbox) GetWritablePaths() []string {
+	if x != nil {
+		return x.WritablePaths
+	}
+	return nil
+}
+
+func (x *Sandbox) GetSeccompAllowlist() bool {
+	if x != nil && x.SeccompAllowlist != nil {
+		return *x.SeccompAllowlist
+	}
+	return false
+}
+
+type DropPrivileges struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Enable dropping privileges. After the server listens to all the
+	// port bindings, Linux capabilities are dropped, except the ones
+	// explicitly kept.
+	Enable *bool `protobuf:"varint,1,opt,name=enable,proto3,oneof" json:"enable,omitempty"`
+	// If set, switch to this user and its primary group.
+	// This requires the server to be started by root.
+	// Example: "nobody".
+	User *string `protobuf:"bytes,2,opt,name=user,proto3,oneof" json:"user,omitempty"`
+	// Keep CAP_NET_BIND_SERVICE capability, so the server can listen to
+	// new port bindings below 1024 after reloading server config.
+	KeepNetBindService *bool `protobuf:"varint,3,opt,name=keepNetBindService,proto3,oneof" json:"keepNetBindService,omitempty"`
+	// If set, switch to this group instead of the primary group of the user.
+	// This requires the server to be started by root.
+	// Example: "nogroup".
+	Group *string `protobuf:"bytes,4,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	// If set, change the root directory of the server to this absolute path
+	// before switching the user. The directory must provide the files used
+	// by the server, such as the server config and data directories.
+	// This requires the server to be started by root.
+	// Example: "/var/lib/mita/chroot".
+	Chroot *string `protobuf:"bytes,5,opt,name=chroot,proto3,oneof" json:"chroot,omitempty"`
+}
+
+func (x *DropPrivileges) Reset() {
+	*x = DropPrivileges{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DropPrivileges) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropPrivileges) ProtoMessage() {}
+
+func (x *DropPrivileges) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropPrivileges.ProtoReflect.Descriptor instead.
+func (*DropPrivileges) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DropPrivileges) GetEnable() bool {
+	if x != nil && x.Enable != nil {
+		return *x.Enable
+	}
+	return false
+}
+
+func (x *DropPrivileges) GetUser() string {
+	if x != nil && x.User != nil {
+		return *x.User
+	}
+	return ""
+}
+
+func (x *DropPrivileges) GetKeepNetBindService() bool {
+	if x != nil && x.KeepNetBindService != nil {
+		return *x.KeepNetBindService
+	}
+	return false
+}
+
+func (x *DropPrivileges) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *DropPrivileges) GetChroot() string {
+	if x != nil && x.Chroot != nil {
+		return *x.Chroot
+	}
+	return ""
+}
+
+type CountryTrafficStatistics struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Enable the statistics. It is disabled by default.
+	// Only the traffic volume of each country in the last 7 days is kept.
+	// Users and destination addresses are not recorded.
+	Enable *bool `protobuf:"varint,1,opt,name=enable,proto3,oneof" json:"enable,omitempty"`
+	// Path of the GeoIP database file in CSV format. Each line has
+	// the first IP address, the last IP address and the country code
+	// of an IP address range. Example: "1.0.0.0,1.0.0.255,AU".
+	GeoIPDatabase *string `protobuf:"bytes,2,opt,name=geoIPDatabase,proto3,oneof" json:"geoIPDatabase,omitempty"`
+}
+
+func (x *CountryTrafficStatistics) Reset() {
+	*x = CountryTrafficStatistics{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountryTrafficStatistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountryTrafficStatistics) ProtoMessage() {}
+
+func (x *CountryTrafficStatistics) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountryTrafficStatistics.ProtoReflect.Descriptor instead.
+func (*CountryTrafficStatistics) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CountryTrafficStatistics) GetEnable() bool {
+	if x != nil && x.Enable != nil {
+		return *x.Enable
+	}
+	return false
+}
+
+func (x *CountryTrafficStatistics) GetGeoIPDatabase() string {
+	if x != nil && x.GeoIPDatabase != nil {
+		return *x.GeoIPDatabase
+	}
+	return ""
+}
+
+type Blocklist struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Absolute paths of blocklist files. Each line of a file is either
+	// in hosts file format, e.g. "0.0.0.0 ads.example.com", which blocks
+	// the host name, or a domain name, e.g. "example.com", which blocks
+	// the domain and all its subdomains.
+	Files []string `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	// HTTP or HTTPS URLs of blocklists in the same formats as files.
+	// They are downloaded when the proxy starts, and downloaded again
+	// periodically.
+	Urls []string `protobuf:"bytes,2,rep,name=urls,proto3" json:"urls,omitempty"`
+	// The interval to download blocklists from URLs again.
+	// Examples: 12h, 24h. It must not be less than 10 minutes.
+	// If unset, the default value 24h is used.
+	RefreshInterval *string `protobuf:"bytes,3,opt,name=refreshInterval,proto3,oneof" json:"refreshInterval,omitempty"`
+}
+
+func (x *Blocklist) Reset() {
+	*x = Blocklist{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Blocklist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Blocklist) ProtoMessage() {}
+
+func (x *Blocklist) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Blocklist.ProtoReflect.Descriptor instead.
+func (*Blocklist) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Blocklist) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *Blocklist) GetUrls() []string {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+func (x *Blocklist) GetRefreshInterval() string {
+	if x != nil && x.RefreshInterval != nil {
+		return *x.RefreshInterval
+	}
+	return ""
+}
+
+type UDPRelay struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Range of local UDP ports to relay UDP packets, in "<begin>-<end>"
+	// format. Example: "20000-20999".
+	// If unset, a random port is used.
+	PortRange *string `protobuf:"bytes,1,opt,name=portRange,proto3,oneof" json:"portRange,omitempty"`
+	// Local IP address to relay UDP packets.
+	// If unset, listen to all the network interfaces.
+	BindIP *string `protobuf:"bytes,2,opt,name=bindIP,proto3,oneof" json:"bindIP,omitempty"`
+	// Name of the network interface to relay UDP packets.
+	// The first IP address of this interface is used.
+	// This can't be used together with bindIP.
+	BindInterface *string `protobuf:"bytes,3,opt,name=bindInterface,proto3,oneof" json:"bindInterface,omitempty"`
+}
+
+func (x *UDPRelay) Reset() {
+	*x = UDPRelay{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UDPRelay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UDPRelay) ProtoMessage() {}
+
+func (x *UDPRelay) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UDPRelay.ProtoReflect.Descriptor instead.
+func (*UDPRelay) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UDPRelay) GetPortRange() string {
+	if x != nil && x.PortRange != nil {
+		return *x.PortRange
+	}
+	return ""
+}
+
+func (x *UDPRelay) GetBindIP() string {
+	if x != nil && x.BindIP != nil {
+		return *x.BindIP
+	}
+	return ""
+}
+
+func (x *UDPRelay) GetBindInterface() string {
+	if x != nil && x.BindInterface != nil {
+		return *x.BindInterface
+	}
+	return ""
+}
+
+type MaintenanceWindow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Time to start the maintenance every day, in "HH:MM" format and UTC.
+	// Example: "20:00".
+	DailyStartTime *string `protobuf:"bytes,1,opt,name=dailyStartTime,proto3,oneof" json:"dailyStartTime,omitempty"`
+	// Number of minutes to announce the maintenance to clients before it
+	// starts. Clients stop opening new sessions to the server during the
+	// announcement, so existing sessions can drain.
+	// If unset or 0, the default value 10 is used.
+	AnnounceMinutes *int32 `protobuf:"varint,2,opt,name=announceMinutes,proto3,oneof" json:"announceMinutes,omitempty"`
+	// What to do when the maintenance starts.
+	Action *MaintenanceAction `protobuf:"varint,3,opt,name=action,proto3,enum=mieru.appctl.MaintenanceAction,oneof" json:"action,omitempty"`
+}
+
+func (x *MaintenanceWindow) Reset() {
+	*x = MaintenanceWindow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaintenanceWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceWindow) ProtoMessage() {}
+
+func (x *MaintenanceWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceWindow.ProtoReflect.Descriptor instead.
+func (*MaintenanceWindow) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MaintenanceWindow) GetDailyStartTime() string {
+	if x != nil && x.DailyStartTime != nil {
+		return *x.DailyStartTime
+	}
+	return ""
+}
+
+func (x *MaintenanceWindow) GetAnnounceMinutes() int32 {
+	if x != nil && x.AnnounceMinutes != nil {
+		return *x.AnnounceMinutes
+	}
+	return 0
+}
+
+func (x *MaintenanceWindow) GetAction() MaintenanceAction {
+	if x != nil && x.Action != nil {
+		return *x.Action
+	}
+	return MaintenanceAction_RESTART_PROXY
+}
+
+type UserGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Group name.
+	Name *string `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	// Names of the users in this group.
+	// A user can belong to at most one group.
+	Users []string `protobuf:"bytes,2,rep,name=users,proto3" json:"users,omitempty"`
+	// Egress proxies and rules of the users in this group.
+	// If set, it is used instead of the egress of server config.
+	// Use a rule with REJECT action to deny access to destinations.
+	Egress *Egress `protobuf:"bytes,3,opt,name=egress,proto3,oneof" json:"egress,omitempty"`
+	// Quotas shared by all the users in this group.
+	// User quotas still apply to each user.
+	Quotas []*Quota `protobuf:"bytes,4,rep,name=quotas,proto3" json:"quotas,omitempty"`
+}
+
+func (x *UserGroup) Reset() {
+	*x = UserGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserGroup) ProtoMessage() {}
+
+func (x *UserGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserGroup.ProtoReflect.Descriptor instead.
+func (*UserGroup) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UserGroup) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UserGroup) GetUsers() []string {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *UserGroup) GetEgress() *Egress {
+	if x != nil {
+		return x.Egress
+	}
+	return nil
+}
+
+func (x *UserGroup) GetQuotas() []*Quota {
+	if x != nil {
+		return x.Quotas
+	}
+	return nil
+}
+
+type ServerAdvancedSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Allow using socks5 to access resources served in localhost.
+	// This option should be set to false unless required due to testing purpose.
+	// This field has been deprecated, has no effect, and will be removed.
+	// Instead, use allowPrivateIP and allowLoopbackIP in User configuration.
+	AllowLocalDestination *bool `protobuf:"varint,1,opt,name=allowLocalDestination,proto3,oneof" json:"allowLocalDestination,omitempty"`
+	// The interval to log metrics.
+	// Examples: 30s, 5m, 2h.
+	// If empty, the default interval is used.
+	MetricsLoggingInterval *string `protobuf:"bytes,2,opt,name=metricsLoggingInterval,proto3,oneof" json:"metricsLoggingInterval,omitempty"`
+	// Maximum number of concurrent sessions the server accepts.
+	// When the limit is reached, new sessions are rejected with a
+	// "retry later" signal, so the client can back off or use
+	// another server endpoint.
+	// If unset or 0, the number of sessions is not limited.
+	MaxSessions *int32 `protobuf:"varint,3,opt,name=maxSessions,proto3,oneof" json:"maxSessions,omitempty"`
+	// If set, export traces of socks5 requests to this OpenTelemetry
+	// collector endpoint using OTLP/HTTP protocol.
+	// Example: http://127.0.0.1:4318
+	OtlpTraceEndpoint *string `protobuf:"bytes,4,opt,name=otlpTraceEndpoint,proto3,oneof" json:"otlpTraceEndpoint,omitempty"`
+	// Serve the echo and sink test endpoints. They are used by speed test,
+	// latency probe and integration tests. Each user also needs to set
+	// allowTestEndpoints to use them.
+	EnableTestEndpoints *bool `protobuf:"varint,5,opt,name=enableTestEndpoints,proto3,oneof" json:"enableTestEndpoints,omitempty"`
+	// Options of the replay cache, which detects replay attacks.
+	ReplayCache *ReplayCacheConfig `protobuf:"bytes,6,opt,name=replayCache,proto3,oneof" json:"replayCache,omitempty"`
+	// Limits of the TCP and WEBSOCKET port bindings. The handshake is
+	// finished when the first segment from the client is decrypted.
+	// This takes effect after the proxy server restarts.
+	AcceptLimit *AcceptLimit `protobuf:"bytes,7,opt,name=acceptLimit,proto3,oneof" json:"acceptLimit,omitempty"`
+	// Maximum CPU usage of the proxy server process, in percent of all
+	// CPU cores, from 1 to 100. When it is reached, new sessions are
+	// rejected with a "retry later" signal.
+	// If unset or 0, the CPU usage is not limited.
+	MaxCPUPercent *int32 `protobuf:"varint,8,opt,name=maxCPUPercent,proto3,oneof" json:"maxCPUPercent,omitempty"`
+	// Maximum download plus upload traffic of the proxy server, in
+	// megabits per second. When it is reached, new sessions are rejected
+	// with a "retry later" signal.
+	// If unset or 0, the bandwidth is not limited.
+	MaxBandwidthMbps *int32 `protobuf:"varint,9,opt,name=maxBandwidthMbps,proto3,oneof" json:"maxBandwidthMbps,omitempty"`
+}
+
+func (x *ServerAdvancedSettings) Reset() {
+	*x = ServerAdvancedSettings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServerAdvancedSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerAdvancedSettings) ProtoMessage() {}
+
+func (x *ServerAdvancedSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerAdvancedSettings.ProtoReflect.Descriptor instead.
+func (*ServerAdvancedSettings) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ServerAdvancedSettings) GetAllowLocalDestination() bool {
+	if x != nil && x.AllowLocalDestination != nil {
+		return *x.AllowLocalDestination
+	}
+	return false
+}
+
+func (x *ServerAdvancedSettings) GetMetricsLoggingInterval() string {
+	if x != nil && x.MetricsLoggingInterval != nil {
+		return *x.MetricsLoggingInterval
+	}
+	return ""
+}
+
+func (x *ServerAdvancedSettings) GetMaxSessions() int32 {
+	if x != nil && x.MaxSessions != nil {
+		return *x.MaxSessions
+	}
+	return 0
+}
+
+func (x *ServerAdvancedSettings) GetOtlpTraceEndpoint() string {
+	if x != nil && x.OtlpTraceEndpoint != nil {
+		return *x.OtlpTraceEndpoint
 	}
-	return LoggingLevel_DEFAULT
+	return ""
 }
 
-func (x *ServerConfig) GetMtu() int32 {
-	if x != nil && x.Mtu != nil {
-		return *x.Mtu
+func (x *ServerAdvancedSettings) GetEnableTestEndpoints() bool {
+	if x != nil && x.EnableTestEndpoints != nil {
+		return *x.EnableTestEndpoints
 	}
-	return 0
+	return false
 }
 
-func (x *ServerConfig) GetEgress() *Egress {
+func (x *ServerAdvancedSettings) GetReplayCache() *ReplayCacheConfig {
 	if x != nil {
-		return x.Egress
+		return x.ReplayCache
 	}
 	return nil
 }
 
-func (x *ServerConfig) GetDns() *DNS {
+func (x *ServerAdvancedSettings) GetAcceptLimit() *AcceptLimit {
 	if x != nil {
-		return x.Dns
+		return x.AcceptLimit
 	}
 	return nil
 }
 
-type ServerAdvancedSettings struct {
+func (x *ServerAdvancedSettings) GetMaxCPUPercent() int32 {
+	if x != nil && x.MaxCPUPercent != nil {
+		return *x.MaxCPUPercent
+	}
+	return 0
+}
+
+func (x *ServerAdvancedSettings) GetMaxBandwidthMbps() int32 {
+	if x != nil && x.MaxBandwidthMbps != nil {
+		return *x.MaxBandwidthMbps
+	}
+	return 0
+}
+
+type ReplayCacheConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Allow using socks5 to access resources served in localhost.
-	// This option should be set to false unless required due to testing purpose.
-	// This field has been deprecated, has no effect, and will be removed.
-	// Instead, use allowPrivateIP and allowLoopbackIP in User configuration.
-	AllowLocalDestination *bool `protobuf:"varint,1,opt,name=allowLocalDestination,proto3,oneof" json:"allowLocalDestination,omitempty"`
-	// The interval to log metrics.
-	// Examples: 30s, 5m, 2h.
-	// If empty, the default interval is used.
-	MetricsLoggingInterval *string `protobuf:"bytes,2,opt,name=metricsLoggingInterval,proto3,oneof" json:"metricsLoggingInterval,omitempty"`
-}
-
-func (x *ServerAdvancedSettings) Reset() {
-	*x = ServerAdvancedSettings{}
+	// Maximum number of signatures in each of the current and the previous
+	// set of the replay cache. A set is replaced when it is full.
+	// If unset or 0, the default value 4194304 is used.
+	Capacity *int32 `protobuf:"varint,1,opt,name=capacity,proto3,oneof" json:"capacity,omitempty"`
+	// The interval to replace the previous set of signatures with
+	// the current set. Examples: 10m, 1h.
+	// It must not be less than 6 minutes, which is the default value.
+	ExpireInterval *string `protobuf:"bytes,2,opt,name=expireInterval,proto3,oneof" json:"expireInterval,omitempty"`
+	// The interval to save the replay cache to disk, so it is restored
+	// after the server restarts. Examples: 30s, 5m.
+	// If empty, the default interval 1 minute is used.
+	DumpInterval *string `protobuf:"bytes,3,opt,name=dumpInterval,proto3,oneof" json:"dumpInterval,omitempty"`
+	// Don't save the replay cache to disk.
+	DisableDump *bool `protobuf:"varint,4,opt,name=disableDump,proto3,oneof" json:"disableDump,omitempty"`
+}
+
+func (x *ReplayCacheConfig) Reset() {
+	*x = ReplayCacheConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_servercfg_proto_msgTypes[1]
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ServerAdvancedSettings) String() string {
+func (x *ReplayCacheConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServerAdvancedSettings) ProtoMessage() {}
+func (*ReplayCacheConfig) ProtoMessage() {}
 
-func (x *ServerAdvancedSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_servercfg_proto_msgTypes[1]
+func (x *ReplayCacheConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -280,25 +1797,39 @@ func (x *ServerAdvancedSettings) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServerAdvancedSettings.ProtoReflect.Descriptor instead.
-func (*ServerAdvancedSettings) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use ReplayCacheConfig.ProtoReflect.Descriptor instead.
+func (*ReplayCacheConfig) Descriptor() ([]byte, []int) {
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *ServerAdvancedSettings) GetAllowLocalDestination() bool {
-	if x != nil && x.AllowLocalDestination != nil {
-		return *x.AllowLocalDestination
+func (x *ReplayCacheConfig) GetCapacity() int32 {
+	if x != nil && x.Capacity != nil {
+		return *x.Capacity
 	}
-	return false
+	return 0
 }
 
-func (x *ServerAdvancedSettings) GetMetricsLoggingInterval() string {
-	if x != nil && x.MetricsLoggingInterval != nil {
-		return *x.MetricsLoggingInterval
+func (x *ReplayCacheConfig) GetExpireInterval() string {
+	if x != nil && x.ExpireInterval != nil {
+		return *x.ExpireInterval
+	}
+	return ""
+}
+
+func (x *ReplayCacheConfig) GetDumpInterval() string {
+	if x != nil && x.DumpInterval != nil {
+		return *x.DumpInterval
 	}
 	return ""
 }
 
+func (x *ReplayCacheConfig) GetDisableDump() bool {
+	if x != nil && x.DisableDump != nil {
+		return *x.DisableDump
+	}
+	return false
+}
+
 type Egress struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -309,12 +1840,24 @@ type Egress struct {
 	// A list of rules.
 	// If no rule is matched, the default action is DIRECT.
 	Rules []*EgressRule `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	// Name of the local network interface to connect to destinations and
+	// egress proxies, e.g. "eth1". On Linux, the connections are bound to
+	// the interface. On other operating systems, an IP address of the
+	// interface is used. This doesn't apply to UDP associate.
+	BindInterface *string `protobuf:"bytes,3,opt,name=bindInterface,proto3,oneof" json:"bindInterface,omitempty"`
+	// Local IP address to connect to destinations and egress proxies.
+	// This doesn't apply to UDP associate.
+	BindIP *string `protobuf:"bytes,4,opt,name=bindIP,proto3,oneof" json:"bindIP,omitempty"`
+	// Firewall mark (SO_MARK) of the connections to destinations and
+	// egress proxies, to be matched by policy routing rules. It is only
+	// supported on Linux. This doesn't apply to UDP associate.
+	RoutingMark *uint32 `protobuf:"varint,5,opt,name=routingMark,proto3,oneof" json:"routingMark,omitempty"`
 }
 
 func (x *Egress) Reset() {
 	*x = Egress{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_servercfg_proto_msgTypes[2]
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -327,7 +1870,7 @@ func (x *Egress) String() string {
 func (*Egress) ProtoMessage() {}
 
 func (x *Egress) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_servercfg_proto_msgTypes[2]
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -340,7 +1883,7 @@ func (x *Egress) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Egress.ProtoReflect.Descriptor instead.
 func (*Egress) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{2}
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *Egress) GetProxies() []*EgressProxy {
@@ -357,6 +1900,27 @@ func (x *Egress) GetRules() []*EgressRule {
 	return nil
 }
 
+func (x *Egress) GetBindInterface() string {
+	if x != nil && x.BindInterface != nil {
+		return *x.BindInterface
+	}
+	return ""
+}
+
+func (x *Egress) GetBindIP() string {
+	if x != nil && x.BindIP != nil {
+		return *x.BindIP
+	}
+	return ""
+}
+
+func (x *Egress) GetRoutingMark() uint32 {
+	if x != nil && x.RoutingMark != nil {
+		return *x.RoutingMark
+	}
+	return 0
+}
+
 type EgressProxy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -376,7 +1940,7 @@ type EgressProxy struct {
 func (x *EgressProxy) Reset() {
 	*x = EgressProxy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_servercfg_proto_msgTypes[3]
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -389,7 +1953,7 @@ func (x *EgressProxy) String() string {
 func (*EgressProxy) ProtoMessage() {}
 
 func (x *EgressProxy) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_servercfg_proto_msgTypes[3]
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -402,7 +1966,7 @@ func (x *EgressProxy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EgressProxy.ProtoReflect.Descriptor instead.
 func (*EgressProxy) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{3}
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *EgressProxy) GetName() string {
@@ -458,12 +2022,20 @@ type EgressRule struct {
 	// When multiple proxies are provided, a random one is selected
 	// for each request.
 	ProxyNames []string `protobuf:"bytes,4,rep,name=proxyNames,proto3" json:"proxyNames,omitempty"`
+	// A list of country codes to match the rule, e.g. "CN".
+	// The country of the destination IP address is looked up from
+	// the GeoIP database. Requests with a domain name don't match.
+	Countries []string `protobuf:"bytes,5,rep,name=countries,proto3" json:"countries,omitempty"`
+	// A list of geosite categories to match the rule, e.g. "ads".
+	// The domain name is looked up from the geosite database.
+	// Requests with an IP address don't match.
+	SiteCategories []string `protobuf:"bytes,6,rep,name=siteCategories,proto3" json:"siteCategories,omitempty"`
 }
 
 func (x *EgressRule) Reset() {
 	*x = EgressRule{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_servercfg_proto_msgTypes[4]
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -476,7 +2048,7 @@ func (x *EgressRule) String() string {
 func (*EgressRule) ProtoMessage() {}
 
 func (x *EgressRule) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_servercfg_proto_msgTypes[4]
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -489,7 +2061,7 @@ func (x *EgressRule) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EgressRule.ProtoReflect.Descriptor instead.
 func (*EgressRule) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{4}
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *EgressRule) GetIpRanges() []string {
@@ -520,6 +2092,20 @@ func (x *EgressRule) GetProxyNames() []string {
 	return nil
 }
 
+func (x *EgressRule) GetCountries() []string {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+func (x *EgressRule) GetSiteCategories() []string {
+	if x != nil {
+		return x.SiteCategories
+	}
+	return nil
+}
+
 type DNS struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -532,7 +2118,7 @@ type DNS struct {
 func (x *DNS) Reset() {
 	*x = DNS{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_appctl_proto_servercfg_proto_msgTypes[5]
+		mi := &file_appctl_proto_servercfg_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -545,7 +2131,7 @@ func (x *DNS) String() string {
 func (*DNS) ProtoMessage() {}
 
 func (x *DNS) ProtoReflect() protoreflect.Message {
-	mi := &file_appctl_proto_servercfg_proto_msgTypes[5]
+	mi := &file_appctl_proto_servercfg_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -558,7 +2144,7 @@ func (x *DNS) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DNS.ProtoReflect.Descriptor instead.
 func (*DNS) Descriptor() ([]byte, []int) {
-	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{5}
+	return file_appctl_proto_servercfg_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *DNS) GetDualStack() DualStack {
@@ -575,7 +2161,7 @@ var file_appctl_proto_servercfg_proto_rawDesc = []byte{
 	0x65, 0x72, 0x76, 0x65, 0x72, 0x63, 0x66, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c,
 	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x1a, 0x17, 0x61, 0x70,
 	0x70, 0x63, 0x74, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x61, 0x73, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc8, 0x03, 0x0a, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb4, 0x11, 0x0a, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
 	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x3d, 0x0a, 0x0c, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x69,
 	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d,
 	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50, 0x6f, 0x72, 0x74,
@@ -599,77 +2185,432 @@ var file_appctl_proto_servercfg_proto_rawDesc = []byte{
 	0x6c, 0x2e, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x03, 0x52, 0x06, 0x65, 0x67, 0x72, 0x65,
 	0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x03, 0x64, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74,
-	0x6c, 0x2e, 0x44, 0x4e, 0x53, 0x48, 0x04, 0x52, 0x03, 0x64, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x42,
-	0x13, 0x0a, 0x11, 0x5f, 0x61, 0x64, 0x76, 0x61, 0x6e, 0x63, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74,
-	0x69, 0x6e, 0x67, 0x73, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67,
-	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6d, 0x74, 0x75, 0x42, 0x09, 0x0a,
-	0x07, 0x5f, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x64, 0x6e, 0x73,
-	0x22, 0xc5, 0x01, 0x0a, 0x16, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x76, 0x61, 0x6e,
-	0x63, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x39, 0x0a, 0x15, 0x61,
-	0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x15, 0x61, 0x6c,
-	0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x16, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
-	0x73, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x16, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
-	0x73, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
-	0x88, 0x01, 0x01, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x63,
-	0x61, 0x6c, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x19, 0x0a,
-	0x17, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67,
-	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0x6d, 0x0a, 0x06, 0x45, 0x67, 0x72, 0x65,
-	0x73, 0x73, 0x12, 0x33, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
-	0x74, 0x6c, 0x2e, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x52, 0x07,
-	0x70, 0x72, 0x6f, 0x78, 0x69, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61,
-	0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65,
-	0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x22, 0xa4, 0x02, 0x0a, 0x0b, 0x45, 0x67, 0x72, 0x65,
-	0x73, 0x73, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01,
-	0x12, 0x3c, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74,
-	0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x48,
-	0x01, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x17,
-	0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x04,
-	0x68, 0x6f, 0x73, 0x74, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x88, 0x01, 0x01,
-	0x12, 0x4b, 0x0a, 0x14, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e,
-	0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
-	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x41, 0x75,
-	0x74, 0x68, 0x48, 0x04, 0x52, 0x14, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a,
-	0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x63, 0x6f, 0x6c, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x42, 0x07, 0x0a, 0x05,
-	0x5f, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35,
-	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xae,
-	0x01, 0x0a, 0x0a, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a,
-	0x08, 0x69, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x08, 0x69, 0x70, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x6f, 0x6d,
-	0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b,
-	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x37, 0x0a, 0x06, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6d, 0x69,
+	0x6c, 0x2e, 0x44, 0x4e, 0x53, 0x48, 0x04, 0x52, 0x03, 0x64, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x37, 0x0a, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x08, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x0a, 0x75, 0x73,
+	0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x46, 0x0a, 0x0b, 0x6d, 0x61, 0x69, 0x6e,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
+	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x61, 0x69,
+	0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x48, 0x05,
+	0x52, 0x0b, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x37, 0x0a, 0x08, 0x75, 0x64, 0x70, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74,
+	0x6c, 0x2e, 0x55, 0x44, 0x50, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x48, 0x06, 0x52, 0x08, 0x75, 0x64,
+	0x70, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x88, 0x01, 0x01, 0x12, 0x53, 0x0a, 0x0e, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x72, 0x79, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x26, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c,
+	0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x53,
+	0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x48, 0x07, 0x52, 0x0e, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x72, 0x79, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x88, 0x01, 0x01, 0x12, 0x49,
+	0x0a, 0x0e, 0x64, 0x72, 0x6f, 0x70, 0x50, 0x72, 0x69, 0x76, 0x69, 0x6c, 0x65, 0x67, 0x65, 0x73,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61,
+	0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x44, 0x72, 0x6f, 0x70, 0x50, 0x72, 0x69, 0x76, 0x69, 0x6c,
+	0x65, 0x67, 0x65, 0x73, 0x48, 0x08, 0x52, 0x0e, 0x64, 0x72, 0x6f, 0x70, 0x50, 0x72, 0x69, 0x76,
+	0x69, 0x6c, 0x65, 0x67, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x07, 0x73, 0x61, 0x6e,
+	0x64, 0x62, 0x6f, 0x78, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x69, 0x65,
+	0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x61, 0x6e, 0x64, 0x62, 0x6f,
+	0x78, 0x48, 0x09, 0x52, 0x07, 0x73, 0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78, 0x88, 0x01, 0x01, 0x12,
+	0x40, 0x0a, 0x09, 0x77, 0x65, 0x62, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74,
+	0x6c, 0x2e, 0x57, 0x65, 0x62, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x48, 0x0a, 0x52, 0x09, 0x77, 0x65, 0x62, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x88, 0x01,
+	0x01, 0x12, 0x4c, 0x0a, 0x0d, 0x74, 0x6c, 0x73, 0x43, 0x61, 0x6d, 0x6f, 0x75, 0x66, 0x6c, 0x61,
+	0x67, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x54, 0x4c, 0x53, 0x43, 0x61, 0x6d, 0x6f, 0x75,
+	0x66, 0x6c, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x0b, 0x52, 0x0d, 0x74,
+	0x6c, 0x73, 0x43, 0x61, 0x6d, 0x6f, 0x75, 0x66, 0x6c, 0x61, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x34, 0x0a, 0x05, 0x64, 0x65, 0x63, 0x6f, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x44, 0x65,
+	0x63, 0x6f, 0x79, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x0c, 0x52, 0x05, 0x64, 0x65, 0x63,
+	0x6f, 0x79, 0x88, 0x01, 0x01, 0x12, 0x46, 0x0a, 0x0b, 0x6b, 0x65, 0x79, 0x52, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6d, 0x69, 0x65,
+	0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x0d, 0x52, 0x0b, 0x6b,
+	0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x49, 0x0a,
+	0x0c, 0x70, 0x6f, 0x72, 0x74, 0x4b, 0x6e, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x18, 0x12, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x4b, 0x6e, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x0e, 0x52, 0x0c, 0x70, 0x6f, 0x72, 0x74, 0x4b, 0x6e, 0x6f,
+	0x63, 0x6b, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12, 0x43, 0x0a, 0x0c, 0x67, 0x65, 0x6f, 0x44,
+	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x47, 0x65,
+	0x6f, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x73, 0x48, 0x0f, 0x52, 0x0c, 0x67, 0x65,
+	0x6f, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a,
+	0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x48, 0x10, 0x52, 0x09, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x88, 0x01, 0x01, 0x12, 0x4c, 0x0a, 0x0d, 0x70, 0x72, 0x6f,
+	0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x21, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
+	0x50, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x48, 0x11, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x08, 0x61, 0x75, 0x64, 0x69, 0x74,
+	0x4c, 0x6f, 0x67, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x69, 0x65, 0x72,
+	0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f,
+	0x67, 0x48, 0x12, 0x52, 0x08, 0x61, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x88, 0x01, 0x01,
+	0x12, 0x3a, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x17, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2e, 0x4c, 0x6f, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x48, 0x13, 0x52, 0x09,
+	0x6c, 0x6f, 0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x07,
+	0x6c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4c, 0x6f, 0x67,
+	0x46, 0x69, 0x6c, 0x65, 0x48, 0x14, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x4f, 0x0a, 0x10, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x52, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x15, 0x52, 0x10,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74,
+	0x88, 0x01, 0x01, 0x12, 0x52, 0x0a, 0x11, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x61,
+	0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x48,
+	0x16, 0x52, 0x11, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x47, 0x61, 0x74,
+	0x65, 0x77, 0x61, 0x79, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x0d, 0x73, 0x69, 0x70, 0x30, 0x30,
+	0x33, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x18, 0x1b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x53, 0x49,
+	0x50, 0x30, 0x30, 0x33, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x52, 0x0d, 0x73, 0x69, 0x70, 0x30,
+	0x30, 0x33, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x12, 0x29, 0x0a, 0x0d, 0x73, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x17, 0x52, 0x0d, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x88, 0x01, 0x01, 0x12, 0x3a, 0x0a, 0x07, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x18,
+	0x1d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70,
+	0x70, 0x63, 0x74, 0x6c, 0x2e, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x48, 0x18, 0x52, 0x07, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x88, 0x01, 0x01,
+	0x42, 0x13, 0x0a, 0x11, 0x5f, 0x61, 0x64, 0x76, 0x61, 0x6e, 0x63, 0x65, 0x64, 0x53, 0x65, 0x74,
+	0x74, 0x69, 0x6e, 0x67, 0x73, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6c, 0x6f, 0x67, 0x67, 0x69, 0x6e,
+	0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6d, 0x74, 0x75, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x64, 0x6e,
+	0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63,
+	0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x75, 0x64, 0x70, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x42, 0x11,
+	0x0a, 0x0f, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69,
+	0x63, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x64, 0x72, 0x6f, 0x70, 0x50, 0x72, 0x69, 0x76, 0x69, 0x6c,
+	0x65, 0x67, 0x65, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x73, 0x61, 0x6e, 0x64, 0x62, 0x6f, 0x78,
+	0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x77, 0x65, 0x62, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x42, 0x10,
+	0x0a, 0x0e, 0x5f, 0x74, 0x6c, 0x73, 0x43, 0x61, 0x6d, 0x6f, 0x75, 0x66, 0x6c, 0x61, 0x67, 0x65,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x64, 0x65, 0x63, 0x6f, 0x79, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6b,
+	0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x70,
+	0x6f, 0x72, 0x74, 0x4b, 0x6e, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x67, 0x65, 0x6f, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x73, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x70,
+	0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x42, 0x0b, 0x0a, 0x09,
+	0x5f, 0x61, 0x75, 0x64, 0x69, 0x74, 0x4c, 0x6f, 0x67, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6c, 0x6f,
+	0x67, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6c, 0x6f, 0x67, 0x46,
+	0x69, 0x6c, 0x65, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4d, 0x61,
+	0x6e, 0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x6d, 0x61, 0x6e,
+	0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x42, 0x10,
+	0x0a, 0x0e, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x22, 0xf8, 0x01, 0x0a,
+	0x0d, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x23,
+	0x0a, 0x0a, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x55, 0x52, 0x4c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x55, 0x52, 0x4c,
+	0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1f,
+	0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x02, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x27, 0x0a, 0x0c, 0x73, 0x79, 0x6e, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0c, 0x73, 0x79, 0x6e, 0x63, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x63, 0x61, 0x46, 0x69,
+	0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x06, 0x63, 0x61, 0x46, 0x69,
+	0x6c, 0x65, 0x88, 0x01, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72,
+	0x79, 0x55, 0x52, 0x4c, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x73, 0x79, 0x6e, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x09, 0x0a, 0x07,
+	0x5f, 0x63, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x22, 0xf3, 0x01, 0x0a, 0x08, 0x41, 0x75, 0x64, 0x69,
+	0x74, 0x4c, 0x6f, 0x67, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a,
+	0x09, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x4d, 0x42, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x01, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x4d, 0x42, 0x88, 0x01, 0x01,
+	0x12, 0x23, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x63, 0x6b, 0x75,
+	0x70, 0x73, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x41, 0x67, 0x65, 0x44,
+	0x61, 0x79, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03, 0x52, 0x0a, 0x6d, 0x61, 0x78,
+	0x41, 0x67, 0x65, 0x44, 0x61, 0x79, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x63, 0x6f,
+	0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x08,
+	0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x70, 0x61, 0x74, 0x68, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65,
+	0x4d, 0x42, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70,
+	0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x41, 0x67, 0x65, 0x44, 0x61, 0x79, 0x73,
+	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x22, 0xf2, 0x01,
+	0x0a, 0x07, 0x4c, 0x6f, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88,
+	0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x4d, 0x42, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65,
+	0x4d, 0x42, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x63, 0x6b,
+	0x75, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x0a, 0x6d, 0x61, 0x78,
+	0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a, 0x6d, 0x61,
+	0x78, 0x41, 0x67, 0x65, 0x44, 0x61, 0x79, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x03,
+	0x52, 0x0a, 0x6d, 0x61, 0x78, 0x41, 0x67, 0x65, 0x44, 0x61, 0x79, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x1f, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x04, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01,
+	0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6d, 0x61,
+	0x78, 0x53, 0x69, 0x7a, 0x65, 0x4d, 0x42, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x42,
+	0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x41, 0x67,
+	0x65, 0x44, 0x61, 0x79, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x22, 0xb1, 0x02, 0x0a, 0x10, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4d, 0x61, 0x6e,
+	0x61, 0x67, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x88, 0x01, 0x01,
+	0x12, 0x1b, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x50, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x01, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x50, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a,
+	0x0f, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0f, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e,
+	0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b,
+	0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x43, 0x41, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x04, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x41, 0x46, 0x69, 0x6c, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x06, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70,
+	0x6f, 0x72, 0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x50, 0x42, 0x12,
+	0x0a, 0x10, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69,
+	0x6c, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65,
+	0x79, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x43, 0x41, 0x46, 0x69, 0x6c, 0x65, 0x22, 0xcf, 0x02, 0x0a, 0x11, 0x4d, 0x61, 0x6e, 0x61, 0x67,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x17, 0x0a, 0x04,
+	0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x04, 0x70, 0x6f,
+	0x72, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x50, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x50, 0x88,
+	0x01, 0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x46, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0f, 0x63,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46,
+	0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0e, 0x70, 0x72, 0x69,
+	0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x16,
+	0x0a, 0x06, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x21, 0x0a, 0x09, 0x64, 0x61, 0x73, 0x68, 0x62, 0x6f,
+	0x61, 0x72, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x09, 0x64, 0x61, 0x73,
+	0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0d, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0d, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62, 0x69, 0x6e,
+	0x64, 0x49, 0x50, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x70, 0x72, 0x69, 0x76,
+	0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x64,
+	0x61, 0x73, 0x68, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x22, 0x68, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x78,
+	0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x1d, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x00, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x88, 0x01, 0x01, 0x12, 0x26,
+	0x0a, 0x0e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x22, 0x93, 0x01, 0x0a, 0x0b, 0x44, 0x65, 0x63, 0x6f, 0x79, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x2d, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x74, 0x69, 0x63, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0f, 0x73,
+	0x74, 0x61, 0x74, 0x69, 0x63, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x88, 0x01,
+	0x01, 0x12, 0x2d, 0x0a, 0x0f, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65, 0x50, 0x72, 0x6f, 0x78,
+	0x79, 0x55, 0x52, 0x4c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0f, 0x72, 0x65,
+	0x76, 0x65, 0x72, 0x73, 0x65, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x52, 0x4c, 0x88, 0x01, 0x01,
+	0x42, 0x12, 0x0a, 0x10, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x69, 0x63, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x65,
+	0x50, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x52, 0x4c, 0x22, 0x9d, 0x01, 0x0a, 0x07, 0x53, 0x61, 0x6e,
+	0x64, 0x62, 0x6f, 0x78, 0x12, 0x1b, 0x0a, 0x06, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x06, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x24, 0x0a, 0x0d, 0x77, 0x72, 0x69, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x50, 0x61, 0x74,
+	0x68, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x77, 0x72, 0x69, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x2f, 0x0a, 0x10, 0x73, 0x65, 0x63, 0x63, 0x6f,
+	0x6d, 0x70, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x01, 0x52, 0x10, 0x73, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x41, 0x6c, 0x6c, 0x6f,
+	0x77, 0x6c, 0x69, 0x73, 0x74, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x65, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x73, 0x65, 0x63, 0x63, 0x6f, 0x6d, 0x70, 0x41,
+	0x6c, 0x6c, 0x6f, 0x77, 0x6c, 0x69, 0x73, 0x74, 0x22, 0xf3, 0x01, 0x0a, 0x0e, 0x44, 0x72, 0x6f,
+	0x70, 0x50, 0x72, 0x69, 0x76, 0x69, 0x6c, 0x65, 0x67, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x06, 0x65,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x06, 0x65,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x88, 0x01,
+	0x01, 0x12, 0x33, 0x0a, 0x12, 0x6b, 0x65, 0x65, 0x70, 0x4e, 0x65, 0x74, 0x42, 0x69, 0x6e, 0x64,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52,
+	0x12, 0x6b, 0x65, 0x65, 0x70, 0x4e, 0x65, 0x74, 0x42, 0x69, 0x6e, 0x64, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x88, 0x01,
+	0x01, 0x12, 0x1b, 0x0a, 0x06, 0x63, 0x68, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x04, 0x52, 0x06, 0x63, 0x68, 0x72, 0x6f, 0x6f, 0x74, 0x88, 0x01, 0x01, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73,
+	0x65, 0x72, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x6b, 0x65, 0x65, 0x70, 0x4e, 0x65, 0x74, 0x42, 0x69,
+	0x6e, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x63, 0x68, 0x72, 0x6f, 0x6f, 0x74, 0x22, 0x7f,
+	0x0a, 0x18, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x53, 0x74, 0x61, 0x74, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x1b, 0x0a, 0x06, 0x65, 0x6e,
+	0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x06, 0x65, 0x6e,
+	0x61, 0x62, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0d, 0x67, 0x65, 0x6f, 0x49, 0x50,
+	0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01,
+	0x52, 0x0d, 0x67, 0x65, 0x6f, 0x49, 0x50, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x88,
+	0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x10, 0x0a,
+	0x0e, 0x5f, 0x67, 0x65, 0x6f, 0x49, 0x50, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x22,
+	0x78, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c,
+	0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x75, 0x72, 0x6c, 0x73, 0x12, 0x2d, 0x0a, 0x0f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73,
+	0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x0f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73,
+	0x68, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0xa0, 0x01, 0x0a, 0x08, 0x55, 0x44,
+	0x50, 0x52, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x21, 0x0a, 0x09, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x62, 0x69, 0x6e,
+	0x64, 0x49, 0x50, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x62, 0x69, 0x6e,
+	0x64, 0x49, 0x50, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0d, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x0d, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x88, 0x01,
+	0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x50, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x62,
+	0x69, 0x6e, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x22, 0xdf, 0x01, 0x0a,
+	0x11, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x57, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x12, 0x2b, 0x0a, 0x0e, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x54, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0e, 0x64, 0x61,
+	0x69, 0x6c, 0x79, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x2d, 0x0a, 0x0f, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x4d, 0x69, 0x6e, 0x75, 0x74,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0f, 0x61, 0x6e, 0x6e, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x4d, 0x69, 0x6e, 0x75, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x3c,
+	0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x4d, 0x61,
+	0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48,
+	0x02, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x11, 0x0a, 0x0f,
+	0x5f, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x53, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x42,
+	0x12, 0x0a, 0x10, 0x5f, 0x61, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x4d, 0x69, 0x6e, 0x75,
+	0x74, 0x65, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xae,
+	0x01, 0x0a, 0x09, 0x55, 0x73, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x17, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x12, 0x31, 0x0a, 0x06, 0x65,
+	0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x69,
 	0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x45, 0x67, 0x72, 0x65, 0x73,
-	0x73, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x4e, 0x61, 0x6d,
-	0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x4e,
-	0x61, 0x6d, 0x65, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22,
-	0x4f, 0x0a, 0x03, 0x44, 0x4e, 0x53, 0x12, 0x3a, 0x0a, 0x09, 0x64, 0x75, 0x61, 0x6c, 0x53, 0x74,
-	0x61, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6d, 0x69, 0x65, 0x72,
-	0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x44, 0x75, 0x61, 0x6c, 0x53, 0x74, 0x61,
-	0x63, 0x6b, 0x48, 0x00, 0x52, 0x09, 0x64, 0x75, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x88,
-	0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x64, 0x75, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x63, 0x6b,
-	0x2a, 0x46, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
-	0x6c, 0x12, 0x1a, 0x0a, 0x16, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x50, 0x52, 0x4f,
-	0x58, 0x59, 0x5f, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x10, 0x00, 0x12, 0x19, 0x0a,
-	0x15, 0x53, 0x4f, 0x43, 0x4b, 0x53, 0x35, 0x5f, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x50, 0x52,
-	0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x10, 0x01, 0x2a, 0x31, 0x0a, 0x0c, 0x45, 0x67, 0x72, 0x65,
-	0x73, 0x73, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x09, 0x0a, 0x05, 0x50, 0x52, 0x4f, 0x58,
-	0x59, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x10, 0x01, 0x12,
-	0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x10, 0x02, 0x42, 0x30, 0x5a, 0x2e, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6e, 0x66, 0x65, 0x69, 0x6e,
-	0x2f, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2f, 0x76, 0x33, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70,
-	0x70, 0x63, 0x74, 0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x70, 0x62, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x48, 0x01, 0x52, 0x06, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x2b,
+	0x0a, 0x06, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x51, 0x75,
+	0x6f, 0x74, 0x61, 0x52, 0x06, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x65, 0x67, 0x72, 0x65, 0x73, 0x73, 0x22,
+	0xc1, 0x05, 0x0a, 0x16, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x76, 0x61, 0x6e, 0x63,
+	0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x39, 0x0a, 0x15, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x15, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x16, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x16, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88,
+	0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x02, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a, 0x11, 0x6f, 0x74, 0x6c,
+	0x70, 0x54, 0x72, 0x61, 0x63, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x11, 0x6f, 0x74, 0x6c, 0x70, 0x54, 0x72, 0x61, 0x63,
+	0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a, 0x13,
+	0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x54, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x13, 0x65, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x54, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x46, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x43, 0x61, 0x63,
+	0x68, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x43, 0x61,
+	0x63, 0x68, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x48, 0x05, 0x52, 0x0b, 0x72, 0x65, 0x70,
+	0x6c, 0x61, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x88, 0x01, 0x01, 0x12, 0x40, 0x0a, 0x0b, 0x61,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e,
+	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x48, 0x06, 0x52, 0x0b, 0x61,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a,
+	0x0d, 0x6d, 0x61, 0x78, 0x43, 0x50, 0x55, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x07, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x43, 0x50, 0x55, 0x50, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x42,
+	0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4d, 0x62, 0x70, 0x73, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x08, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64,
+	0x74, 0x68, 0x4d, 0x62, 0x70, 0x73, 0x88, 0x01, 0x01, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x4c,
+	0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x0e,
+	0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x14,
+	0x0a, 0x12, 0x5f, 0x6f, 0x74, 0x6c, 0x70, 0x54, 0x72, 0x61, 0x63, 0x65, 0x45, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x54,
+	0x65, 0x73, 0x74, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x42, 0x0e, 0x0a, 0x0c,
+	0x5f, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x42, 0x0e, 0x0a, 0x0c,
+	0x5f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x42, 0x10, 0x0a, 0x0e,
+	0x5f, 0x6d, 0x61, 0x78, 0x43, 0x50, 0x55, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x42, 0x13,
+	0x0a, 0x11, 0x5f, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x4d,
+	0x62, 0x70, 0x73, 0x22, 0xf2, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x43, 0x61,
+	0x63, 0x68, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1f, 0x0a, 0x08, 0x63, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x08, 0x63,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0e, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x0e, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x64, 0x75, 0x6d, 0x70, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x0c, 0x64, 0x75, 0x6d, 0x70, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01,
+	0x12, 0x25, 0x0a, 0x0b, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65,
+	0x44, 0x75, 0x6d, 0x70, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x63, 0x61, 0x70, 0x61,
+	0x63, 0x69, 0x74, 0x79, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x64, 0x75, 0x6d, 0x70,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x64, 0x69, 0x73,
+	0x61, 0x62, 0x6c, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x22, 0x89, 0x02, 0x0a, 0x06, 0x45, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x33, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70,
+	0x63, 0x74, 0x6c, 0x2e, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x52,
+	0x07, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e,
+	0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c,
+	0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x0d, 0x62, 0x69, 0x6e, 0x64,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x0d, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x50, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x64, 0x49, 0x50, 0x88, 0x01, 0x01,
+	0x12, 0x25, 0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x72, 0x6b, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67,
+	0x4d, 0x61, 0x72, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x62, 0x69, 0x6e, 0x64,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x62, 0x69,
+	0x6e, 0x64, 0x49, 0x50, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67,
+	0x4d, 0x61, 0x72, 0x6b, 0x22, 0xa4, 0x02, 0x0a, 0x0b, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x50,
+	0x72, 0x6f, 0x78, 0x79, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x3c, 0x0a,
+	0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1b, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x50,
+	0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x48, 0x01, 0x52, 0x08,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x68,
+	0x6f, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x04, 0x68, 0x6f, 0x73,
+	0x74, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x48, 0x03, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x88, 0x01, 0x01, 0x12, 0x4b, 0x0a,
+	0x14, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69,
+	0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x48,
+	0x04, 0x52, 0x14, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x42, 0x07, 0x0a, 0x05, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x6f,
+	0x72, 0x74, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x73, 0x35, 0x41, 0x75, 0x74,
+	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xf4, 0x01, 0x0a, 0x0a,
+	0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x70,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x69, 0x70,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x6f, 0x6d,
+	0x61, 0x69, 0x6e, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x37, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75,
+	0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01,
+	0x01, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x4e, 0x61, 0x6d, 0x65,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x26, 0x0a, 0x0e, 0x73, 0x69, 0x74, 0x65, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x69, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x69, 0x74, 0x65, 0x43, 0x61, 0x74,
+	0x65, 0x67, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x4f, 0x0a, 0x03, 0x44, 0x4e, 0x53, 0x12, 0x3a, 0x0a, 0x09, 0x64, 0x75, 0x61,
+	0x6c, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x2e, 0x44, 0x75, 0x61, 0x6c,
+	0x53, 0x74, 0x61, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x09, 0x64, 0x75, 0x61, 0x6c, 0x53, 0x74, 0x61,
+	0x63, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x64, 0x75, 0x61, 0x6c, 0x53, 0x74,
+	0x61, 0x63, 0x6b, 0x2a, 0x39, 0x0a, 0x11, 0x4d, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x6e, 0x61, 0x6e,
+	0x63, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x11, 0x0a, 0x0d, 0x52, 0x45, 0x53, 0x54,
+	0x41, 0x52, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x52,
+	0x45, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x01, 0x2a, 0x46,
+	0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12,
+	0x1a, 0x0a, 0x16, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x50, 0x52, 0x4f, 0x58, 0x59,
+	0x5f, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x53,
+	0x4f, 0x43, 0x4b, 0x53, 0x35, 0x5f, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x5f, 0x50, 0x52, 0x4f, 0x54,
+	0x4f, 0x43, 0x4f, 0x4c, 0x10, 0x01, 0x2a, 0x31, 0x0a, 0x0c, 0x45, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x09, 0x0a, 0x05, 0x50, 0x52, 0x4f, 0x58, 0x59, 0x10,
+	0x00, 0x12, 0x0a, 0x0a, 0x06, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x10, 0x01, 0x12, 0x0a, 0x0a,
+	0x06, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x10, 0x02, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6e, 0x66, 0x65, 0x69, 0x6e, 0x2f, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2f, 0x76, 0x33, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x70, 0x63,
+	0x74, 0x6c, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x74, 0x6c, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -684,41 +2625,92 @@ func file_appctl_proto_servercfg_proto_rawDescGZIP() []byte {
 	return file_appctl_proto_servercfg_proto_rawDescData
 }
 
-var file_appctl_proto_servercfg_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_appctl_proto_servercfg_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_appctl_proto_servercfg_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_appctl_proto_servercfg_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_appctl_proto_servercfg_proto_goTypes = []interface{}{
-	(ProxyProtocol)(0),             // 0: mieru.appctl.ProxyProtocol
-	(EgressAction)(0),              // 1: mieru.appctl.EgressAction
-	(*ServerConfig)(nil),           // 2: mieru.appctl.ServerConfig
-	(*ServerAdvancedSettings)(nil), // 3: mieru.appctl.ServerAdvancedSettings
-	(*Egress)(nil),                 // 4: mieru.appctl.Egress
-	(*EgressProxy)(nil),            // 5: mieru.appctl.EgressProxy
-	(*EgressRule)(nil),             // 6: mieru.appctl.EgressRule
-	(*DNS)(nil),                    // 7: mieru.appctl.DNS
-	(*PortBinding)(nil),            // 8: mieru.appctl.PortBinding
-	(*User)(nil),                   // 9: mieru.appctl.User
-	(LoggingLevel)(0),              // 10: mieru.appctl.LoggingLevel
-	(*Auth)(nil),                   // 11: mieru.appctl.Auth
-	(DualStack)(0),                 // 12: mieru.appctl.DualStack
+	(MaintenanceAction)(0),           // 0: mieru.appctl.MaintenanceAction
+	(ProxyProtocol)(0),               // 1: mieru.appctl.ProxyProtocol
+	(EgressAction)(0),                // 2: mieru.appctl.EgressAction
+	(*ServerConfig)(nil),             // 3: mieru.appctl.ServerConfig
+	(*ClusterConfig)(nil),            // 4: mieru.appctl.ClusterConfig
+	(*AuditLog)(nil),                 // 5: mieru.appctl.AuditLog
+	(*LogFile)(nil),                  // 6: mieru.appctl.LogFile
+	(*RemoteManagement)(nil),         // 7: mieru.appctl.RemoteManagement
+	(*ManagementGateway)(nil),        // 8: mieru.appctl.ManagementGateway
+	(*ProxyProtocolConfig)(nil),      // 9: mieru.appctl.ProxyProtocolConfig
+	(*DecoyConfig)(nil),              // 10: mieru.appctl.DecoyConfig
+	(*Sandbox)(nil),                  // 11: mieru.appctl.Sandbox
+	(*DropPrivileges)(nil),           // 12: mieru.appctl.DropPrivileges
+	(*CountryTrafficStatistics)(nil), // 13: mieru.appctl.CountryTrafficStatistics
+	(*Blocklist)(nil),                // 14: mieru.appctl.Blocklist
+	(*UDPRelay)(nil),                 // 15: mieru.appctl.UDPRelay
+	(*MaintenanceWindow)(nil),        // 16: mieru.appctl.MaintenanceWindow
+	(*UserGroup)(nil),                // 17: mieru.appctl.UserGroup
+	(*ServerAdvancedSettings)(nil),   // 18: mieru.appctl.ServerAdvancedSettings
+	(*ReplayCacheConfig)(nil),        // 19: mieru.appctl.ReplayCacheConfig
+	(*Egress)(nil),                   // 20: mieru.appctl.Egress
+	(*EgressProxy)(nil),              // 21: mieru.appctl.EgressProxy
+	(*EgressRule)(nil),               // 22: mieru.appctl.EgressRule
+	(*DNS)(nil),                      // 23: mieru.appctl.DNS
+	(*PortBinding)(nil),              // 24: mieru.appctl.PortBinding
+	(*User)(nil),                     // 25: mieru.appctl.User
+	(LoggingLevel)(0),                // 26: mieru.appctl.LoggingLevel
+	(*WebSocketConfig)(nil),          // 27: mieru.appctl.WebSocketConfig
+	(*TLSCamouflageConfig)(nil),      // 28: mieru.appctl.TLSCamouflageConfig
+	(*KeyRotationConfig)(nil),        // 29: mieru.appctl.KeyRotationConfig
+	(*PortKnockingConfig)(nil),       // 30: mieru.appctl.PortKnockingConfig
+	(*GeoDatabases)(nil),             // 31: mieru.appctl.GeoDatabases
+	(LogFormat)(0),                   // 32: mieru.appctl.LogFormat
+	(*SIP003Plugin)(nil),             // 33: mieru.appctl.SIP003Plugin
+	(*Quota)(nil),                    // 34: mieru.appctl.Quota
+	(*AcceptLimit)(nil),              // 35: mieru.appctl.AcceptLimit
+	(*Auth)(nil),                     // 36: mieru.appctl.Auth
+	(DualStack)(0),                   // 37: mieru.appctl.DualStack
 }
 var file_appctl_proto_servercfg_proto_depIdxs = []int32{
-	8,  // 0: mieru.appctl.ServerConfig.portBindings:type_name -> mieru.appctl.PortBinding
-	9,  // 1: mieru.appctl.ServerConfig.users:type_name -> mieru.appctl.User
-	3,  // 2: mieru.appctl.ServerConfig.advancedSettings:type_name -> mieru.appctl.ServerAdvancedSettings
-	10, // 3: mieru.appctl.ServerConfig.loggingLevel:type_name -> mieru.appctl.LoggingLevel
-	4,  // 4: mieru.appctl.ServerConfig.egress:type_name -> mieru.appctl.Egress
-	7,  // 5: mieru.appctl.ServerConfig.dns:type_name -> mieru.appctl.DNS
-	5,  // 6: mieru.appctl.Egress.proxies:type_name -> mieru.appctl.EgressProxy
-	6,  // 7: mieru.appctl.Egress.rules:type_name -> mieru.appctl.EgressRule
-	0,  // 8: mieru.appctl.EgressProxy.protocol:type_name -> mieru.appctl.ProxyProtocol
-	11, // 9: mieru.appctl.EgressProxy.socks5Authentication:type_name -> mieru.appctl.Auth
-	1,  // 10: mieru.appctl.EgressRule.action:type_name -> mieru.appctl.EgressAction
-	12, // 11: mieru.appctl.DNS.dualStack:type_name -> mieru.appctl.DualStack
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	24, // 0: mieru.appctl.ServerConfig.portBindings:type_name -> mieru.appctl.PortBinding
+	25, // 1: mieru.appctl.ServerConfig.users:type_name -> mieru.appctl.User
+	18, // 2: mieru.appctl.ServerConfig.advancedSettings:type_name -> mieru.appctl.ServerAdvancedSettings
+	26, // 3: mieru.appctl.ServerConfig.loggingLevel:type_name -> mieru.appctl.LoggingLevel
+	20, // 4: mieru.appctl.ServerConfig.egress:type_name -> mieru.appctl.Egress
+	23, // 5: mieru.appctl.ServerConfig.dns:type_name -> mieru.appctl.DNS
+	17, // 6: mieru.appctl.ServerConfig.userGroups:type_name -> mieru.appctl.UserGroup
+	16, // 7: mieru.appctl.ServerConfig.maintenance:type_name -> mieru.appctl.MaintenanceWindow
+	15, // 8: mieru.appctl.ServerConfig.udpRelay:type_name -> mieru.appctl.UDPRelay
+	13, // 9: mieru.appctl.ServerConfig.countryTraffic:type_name -> mieru.appctl.CountryTrafficStatistics
+	12, // 10: mieru.appctl.ServerConfig.dropPrivileges:type_name -> mieru.appctl.DropPrivileges
+	11, // 11: mieru.appctl.ServerConfig.sandbox:type_name -> mieru.appctl.Sandbox
+	27, // 12: mieru.appctl.ServerConfig.webSocket:type_name -> mieru.appctl.WebSocketConfig
+	28, // 13: mieru.appctl.ServerConfig.tlsCamouflage:type_name -> mieru.appctl.TLSCamouflageConfig
+	10, // 14: mieru.appctl.ServerConfig.decoy:type_name -> mieru.appctl.DecoyConfig
+	29, // 15: mieru.appctl.ServerConfig.keyRotation:type_name -> mieru.appctl.KeyRotationConfig
+	30, // 16: mieru.appctl.ServerConfig.portKnocking:type_name -> mieru.appctl.PortKnockingConfig
+	31, // 17: mieru.appctl.ServerConfig.geoDatabases:type_name -> mieru.appctl.GeoDatabases
+	14, // 18: mieru.appctl.ServerConfig.blocklist:type_name -> mieru.appctl.Blocklist
+	9,  // 19: mieru.appctl.ServerConfig.proxyProtocol:type_name -> mieru.appctl.ProxyProtocolConfig
+	5,  // 20: mieru.appctl.ServerConfig.auditLog:type_name -> mieru.appctl.AuditLog
+	32, // 21: mieru.appctl.ServerConfig.logFormat:type_name -> mieru.appctl.LogFormat
+	6,  // 22: mieru.appctl.ServerConfig.logFile:type_name -> mieru.appctl.LogFile
+	7,  // 23: mieru.appctl.ServerConfig.remoteManagement:type_name -> mieru.appctl.RemoteManagement
+	8,  // 24: mieru.appctl.ServerConfig.managementGateway:type_name -> mieru.appctl.ManagementGateway
+	33, // 25: mieru.appctl.ServerConfig.sip003Plugins:type_name -> mieru.appctl.SIP003Plugin
+	4,  // 26: mieru.appctl.ServerConfig.cluster:type_name -> mieru.appctl.ClusterConfig
+	0,  // 27: mieru.appctl.MaintenanceWindow.action:type_name -> mieru.appctl.MaintenanceAction
+	20, // 28: mieru.appctl.UserGroup.egress:type_name -> mieru.appctl.Egress
+	34, // 29: mieru.appctl.UserGroup.quotas:type_name -> mieru.appctl.Quota
+	19, // 30: mieru.appctl.ServerAdvancedSettings.replayCache:type_name -> mieru.appctl.ReplayCacheConfig
+	35, // 31: mieru.appctl.ServerAdvancedSettings.acceptLimit:type_name -> mieru.appctl.AcceptLimit
+	21, // 32: mieru.appctl.Egress.proxies:type_name -> mieru.appctl.EgressProxy
+	22, // 33: mieru.appctl.Egress.rules:type_name -> mieru.appctl.EgressRule
+	1,  // 34: mieru.appctl.EgressProxy.protocol:type_name -> mieru.appctl.ProxyProtocol
+	36, // 35: mieru.appctl.EgressProxy.socks5Authentication:type_name -> mieru.appctl.Auth
+	2,  // 36: mieru.appctl.EgressRule.action:type_name -> mieru.appctl.EgressAction
+	37, // 37: mieru.appctl.DNS.dualStack:type_name -> mieru.appctl.DualStack
+	38, // [38:38] is the sub-list for method output_type
+	38, // [38:38] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_appctl_proto_servercfg_proto_init() }
@@ -741,7 +2733,7 @@ func file_appctl_proto_servercfg_proto_init() {
 			}
 		}
 		file_appctl_proto_servercfg_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ServerAdvancedSettings); i {
+			switch v := v.(*ClusterConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -753,7 +2745,7 @@ func file_appctl_proto_servercfg_proto_init() {
 			}
 		}
 		file_appctl_proto_servercfg_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Egress); i {
+			switch v := v.(*AuditLog); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -765,7 +2757,7 @@ func file_appctl_proto_servercfg_proto_init() {
 			}
 		}
 		file_appctl_proto_servercfg_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EgressProxy); i {
+			switch v := v.(*LogFile); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -777,7 +2769,7 @@ func file_appctl_proto_servercfg_proto_init() {
 			}
 		}
 		file_appctl_proto_servercfg_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EgressRule); i {
+			switch v := v.(*RemoteManagement); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -789,6 +2781,186 @@ func file_appctl_proto_servercfg_proto_init() {
 			}
 		}
 		file_appctl_proto_servercfg_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ManagementGateway); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProxyProtocolConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecoyConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Sandbox); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DropPrivileges); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountryTrafficStatistics); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Blocklist); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UDPRelay); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaintenanceWindow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ServerAdvancedSettings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayCacheConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Egress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EgressProxy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EgressRule); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_appctl_proto_servercfg_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DNS); i {
 			case 0:
 				return &v.state
@@ -803,16 +2975,32 @@ func file_appctl_proto_servercfg_proto_init() {
 	}
 	file_appctl_proto_servercfg_proto_msgTypes[0].OneofWrappers = []interface{}{}
 	file_appctl_proto_servercfg_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[2].OneofWrappers = []interface{}{}
 	file_appctl_proto_servercfg_proto_msgTypes[3].OneofWrappers = []interface{}{}
 	file_appctl_proto_servercfg_proto_msgTypes[4].OneofWrappers = []interface{}{}
 	file_appctl_proto_servercfg_proto_msgTypes[5].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[8].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[9].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[13].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[14].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[15].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[16].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[17].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[18].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[19].OneofWrappers = []interface{}{}
+	file_appctl_proto_servercfg_proto_msgTypes[20].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_appctl_proto_servercfg_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   6,
+			NumEnums:      3,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
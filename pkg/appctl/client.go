@@ -17,8 +17,10 @@ package appctl
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"sort"
@@ -28,20 +30,27 @@ import (
 	"sync/atomic"
 	"time"
 
+	apicommon "github.com/enfein/mieru/v3/apis/common"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlgrpc"
 	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/cipher"
 	"github.com/enfein/mieru/v3/pkg/common"
 	"github.com/enfein/mieru/v3/pkg/log"
 	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/minisign"
 	"github.com/enfein/mieru/v3/pkg/protocol"
 	"github.com/enfein/mieru/v3/pkg/socks5"
 	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/tracing"
 	"github.com/enfein/mieru/v3/pkg/version"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/skip2/go-qrcode"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -49,6 +58,29 @@ const (
 	EnvMieruConfigJSONFile = "MIERU_CONFIG_JSON_FILE"
 )
 
+// Formats to export client config.
+const (
+	ExportFormatJSON = "json"
+	ExportFormatYAML = "yaml"
+	ExportFormatURI  = "uri"
+)
+
+// Formats to export a client profile.
+const (
+	ProfileExportFormatURL    = "url"
+	ProfileExportFormatQRCode = "qrcode"
+	ProfileExportFormatJSON   = "json"
+)
+
+// Ports used by ImportClientProfile if the client config doesn't have them.
+const (
+	defaultImportRPCPort    = 8964
+	defaultImportSocks5Port = 1080
+)
+
+// RedactedSecret replaces the secrets in a redacted config.
+const RedactedSecret = "REDACTED"
+
 var (
 	// ClientRPCServerStarted is closed when client RPC server is started.
 	ClientRPCServerStarted chan struct{} = make(chan struct{})
@@ -79,6 +111,18 @@ var (
 
 	// clientMuxRef holds a pointer to client multiplexier.
 	clientMuxRef atomic.Pointer[protocol.Mux]
+
+	// clientDestinationStatsRef holds a pointer to client destination statistics.
+	clientDestinationStatsRef atomic.Pointer[metrics.DestinationStats]
+
+	// clientConnectionsRef holds a pointer to client connection tracker.
+	clientConnectionsRef atomic.Pointer[socks5.ConnectionTracker]
+
+	// clientFailoverRef holds a pointer to client profile failover.
+	clientFailoverRef atomic.Pointer[ClientFailover]
+
+	// clientSocks5ListenersRef holds a pointer to additional client socks5 listeners.
+	clientSocks5ListenersRef atomic.Pointer[ClientSocks5Listeners]
 )
 
 func SetClientRPCServerRef(server *grpc.Server) {
@@ -93,6 +137,31 @@ func SetClientMuxRef(mux *protocol.Mux) {
 	clientMuxRef.Store(mux)
 }
 
+func SetClientDestinationStatsRef(stats *metrics.DestinationStats) {
+	clientDestinationStatsRef.Store(stats)
+}
+
+func SetClientConnectionsRef(tracker *socks5.ConnectionTracker) {
+	clientConnectionsRef.Store(tracker)
+}
+
+func SetClientFailoverRef(failover *ClientFailover) {
+	clientFailoverRef.Store(failover)
+}
+
+func SetClientSocks5ListenersRef(listeners *ClientSocks5Listeners) {
+	clientSocks5ListenersRef.Store(listeners)
+}
+
+// SetClientLogFormat sets the formatter of client logs.
+func SetClientLogFormat(format pb.LogFormat) {
+	if format == pb.LogFormat_LOG_FORMAT_JSON {
+		log.SetFormatter(&log.JSONFormatter{Component: "mieru"})
+	} else {
+		log.SetFormatter(&log.DaemonFormatter{})
+	}
+}
+
 // clientManagementService implements ClientManagementService defined in rpc.proto.
 type clientManagementService struct {
 	appctlgrpc.UnimplementedClientManagementServiceServer
@@ -101,7 +170,11 @@ type clientManagementService struct {
 func (c *clientManagementService) GetStatus(ctx context.Context, req *emptypb.Empty) (*pb.AppStatusMsg, error) {
 	status := GetAppStatus()
 	log.Infof("return app status %s back to RPC caller", status.String())
-	return &pb.AppStatusMsg{Status: &status}, nil
+	msg := &pb.AppStatusMsg{Status: &status}
+	if status == pb.AppStatus_RUNNING {
+		fillClientStatus(msg)
+	}
+	return msg, nil
 }
 
 func (c *clientManagementService) Exit(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
@@ -119,6 +192,7 @@ func (c *clientManagementService) Exit(ctx context.Context, req *emptypb.Empty)
 	grpcServer := clientRPCServerRef.Load()
 	if grpcServer != nil {
 		log.Infof("stopping RPC server")
+		stopFollowLogs()
 		go grpcServer.GracefulStop()
 	} else {
 		log.Infof("RPC server reference not found")
@@ -127,6 +201,15 @@ func (c *clientManagementService) Exit(ctx context.Context, req *emptypb.Empty)
 	return &emptypb.Empty{}, nil
 }
 
+func (c *clientManagementService) Reload(ctx context.Context, req *pb.ReloadClientRequest) (*emptypb.Empty, error) {
+	log.Infof("received Reload request from RPC caller")
+	if err := ReloadClientConfig(req.GetMigrateNow()); err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, "Fix the client config with `mieru apply config <FILE>`, then run `mieru reload` again.", err)
+	}
+	log.Infof("completed Reload request from RPC caller")
+	return &emptypb.Empty{}, nil
+}
+
 func (c *clientManagementService) GetMetrics(ctx context.Context, req *emptypb.Empty) (*pb.Metrics, error) {
 	b, err := metrics.GetMetricsAsJSON()
 	if err != nil {
@@ -138,11 +221,60 @@ func (c *clientManagementService) GetMetrics(ctx context.Context, req *emptypb.E
 func (c *clientManagementService) GetSessionInfoList(context.Context, *emptypb.Empty) (*pb.SessionInfoList, error) {
 	mux := clientMuxRef.Load()
 	if mux == nil {
-		return &pb.SessionInfoList{}, fmt.Errorf("client multiplexier is unavailable")
+		return &pb.SessionInfoList{}, NewRPCError(pb.ErrorCode_SERVICE_UNAVAILABLE, true, clientStartAction, fmt.Errorf("client multiplexier is unavailable"))
 	}
 	return mux.ExportSessionInfoList(), nil
 }
 
+func (c *clientManagementService) GetDestinationTraffic(context.Context, *emptypb.Empty) (*pb.DestinationTrafficList, error) {
+	stats := clientDestinationStatsRef.Load()
+	if stats == nil {
+		return &pb.DestinationTrafficList{}, NewRPCError(pb.ErrorCode_FEATURE_NOT_ENABLED, false, "Set collectDestinationTraffic in advancedSettings of the client config, then restart the proxy client with `mieru stop` and `mieru start`.", fmt.Errorf("destination traffic statistics is not enabled"))
+	}
+	list := &pb.DestinationTrafficList{}
+	for _, t := range stats.Top(0) {
+		list.Items = append(list.Items, &pb.DestinationTraffic{
+			Destination:   proto.String(t.Destination),
+			UploadBytes:   proto.Int64(t.UploadBytes),
+			DownloadBytes: proto.Int64(t.DownloadBytes),
+		})
+	}
+	return list, nil
+}
+
+func (c *clientManagementService) GetProxyConnections(context.Context, *emptypb.Empty) (*pb.ProxyConnectionList, error) {
+	tracker := clientConnectionsRef.Load()
+	if tracker == nil {
+		return &pb.ProxyConnectionList{}, NewRPCError(pb.ErrorCode_SERVICE_UNAVAILABLE, true, clientStartAction, fmt.Errorf("client connection tracker is unavailable"))
+	}
+	list := &pb.ProxyConnectionList{}
+	for _, conn := range tracker.List() {
+		item := &pb.ProxyConnection{
+			SessionID:     proto.Uint32(conn.SessionID),
+			Source:        proto.String(conn.Source),
+			Destination:   proto.String(conn.Destination),
+			Protocol:      proto.String(conn.Protocol),
+			Route:         proto.String(conn.Route),
+			StartTime:     timestamppb.New(conn.StartTime),
+			UploadBytes:   proto.Int64(conn.UploadBytes),
+			DownloadBytes: proto.Int64(conn.DownloadBytes),
+		}
+		if conn.Rule != "" {
+			item.Rule = proto.String(conn.Rule)
+		}
+		list.Items = append(list.Items, item)
+	}
+	return list, nil
+}
+
+func (c *clientManagementService) ProbeServers(ctx context.Context, req *emptypb.Empty) (*pb.ServerLatencyList, error) {
+	mux := clientMuxRef.Load()
+	if mux == nil {
+		return &pb.ServerLatencyList{}, NewRPCError(pb.ErrorCode_SERVICE_UNAVAILABLE, true, clientStartAction, fmt.Errorf("client multiplexier is unavailable"))
+	}
+	return &pb.ServerLatencyList{Items: ProbeClientServers(ctx, mux)}, nil
+}
+
 func (c *clientManagementService) GetThreadDump(ctx context.Context, req *emptypb.Empty) (*pb.ThreadDump, error) {
 	return &pb.ThreadDump{ThreadDump: proto.String(common.GetAllStackTrace())}, nil
 }
@@ -178,6 +310,10 @@ func (c *clientManagementService) GetVersion(ctx context.Context, req *emptypb.E
 	}, nil
 }
 
+func (c *clientManagementService) FollowLogs(req *pb.FollowLogsRequest, stream appctlgrpc.ClientManagementService_FollowLogsServer) error {
+	return followLogs(req, stream)
+}
+
 // NewClientManagementService creates a new ClientManagementService RPC server.
 func NewClientManagementService() *clientManagementService {
 	return &clientManagementService{}
@@ -241,6 +377,190 @@ func GetURLClientConfig() (string, error) {
 	return u, nil
 }
 
+// ExportClientConfig returns the client config in the given format.
+// The same config always produces the same output.
+// If redact is true, the passwords are replaced by RedactedSecret.
+func ExportClientConfig(format string, redact bool) (string, error) {
+	config, err := LoadClientConfig()
+	if err != nil {
+		return "", fmt.Errorf("LoadClientConfig() failed: %w", err)
+	}
+	if redact {
+		config = RedactClientConfig(config)
+	}
+	switch format {
+	case ExportFormatJSON:
+		b, err := common.MarshalCanonicalJSON(config)
+		if err != nil {
+			return "", fmt.Errorf("common.MarshalCanonicalJSON() failed: %w", err)
+		}
+		return strings.TrimSuffix(string(b), "\n"), nil
+	case ExportFormatYAML:
+		b, err := common.MarshalYAML(config)
+		if err != nil {
+			return "", fmt.Errorf("common.MarshalYAML() failed: %w", err)
+		}
+		return strings.TrimSuffix(string(b), "\n"), nil
+	case ExportFormatURI:
+		u, err := ClientConfigToURL(config)
+		if err != nil {
+			return "", fmt.Errorf("ClientConfigToURL() failed: %w", err)
+		}
+		return u, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ExportClientProfile returns a client profile in the given format.
+// If the profile name is empty, the active profile is exported.
+func ExportClientProfile(profileName, format string) (string, error) {
+	config, err := LoadClientConfig()
+	if err != nil {
+		return "", fmt.Errorf("LoadClientConfig() failed: %w", err)
+	}
+	if profileName == "" {
+		profileName = config.GetActiveProfile()
+	}
+	profile, err := GetActiveProfileFromConfig(config, profileName)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case ProfileExportFormatJSON:
+		p := proto.Clone(profile).(*pb.ClientProfile)
+		if p.GetUser().GetPassword() != "" {
+			p.User.HashedPassword = nil
+		}
+		b, err := common.MarshalCanonicalJSON(p)
+		if err != nil {
+			return "", fmt.Errorf("common.MarshalCanonicalJSON() failed: %w", err)
+		}
+		return strings.TrimSuffix(string(b), "\n"), nil
+	case ProfileExportFormatURL, ProfileExportFormatQRCode:
+		u, err := ClientProfileToURL(profile)
+		if err != nil {
+			return "", fmt.Errorf("ClientProfileToURL() failed: %w", err)
+		}
+		if format == ProfileExportFormatURL {
+			return u, nil
+		}
+		q, err := qrcode.New(u, qrcode.Low)
+		if err != nil {
+			return "", fmt.Errorf("qrcode.New() failed: %w", err)
+		}
+		return strings.TrimSuffix(q.ToSmallString(false), "\n"), nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// ImportClientProfile adds a client profile to the client config, or
+// replaces the profile with the same name. The source is a URL created by
+// ClientProfileToURL or ClientProfileToMultiURLs, or a JSON file created by
+// ExportClientProfile. The imported profile becomes the active profile.
+// If the client config doesn't have RPC port and socks5 port, the default
+// ports are used. The name of the imported profile is returned.
+func ImportClientProfile(source string) (string, error) {
+	var profile *pb.ClientProfile
+	if strings.HasPrefix(source, "mieru://") {
+		c, err := URLToClientConfig(source)
+		if err != nil {
+			return "", fmt.Errorf("URLToClientConfig() failed: %w", err)
+		}
+		if len(c.GetProfiles()) != 1 {
+			return "", fmt.Errorf("URL has %d profiles, use import config to import more than 1 profile", len(c.GetProfiles()))
+		}
+		profile = c.GetProfiles()[0]
+	} else if strings.HasPrefix(source, "mierus://") {
+		p, err := URLToClientProfile(source)
+		if err != nil {
+			return "", fmt.Errorf("URLToClientProfile() failed: %w", err)
+		}
+		profile = p
+	} else {
+		b, err := os.ReadFile(source)
+		if err != nil {
+			return "", fmt.Errorf("os.ReadFile(%q) failed: %w", source, err)
+		}
+		profile = &pb.ClientProfile{}
+		if err := common.UnmarshalJSON(b, profile); err != nil {
+			return "", fmt.Errorf("common.UnmarshalJSON() failed: %w", err)
+		}
+	}
+
+	patch := &pb.ClientConfig{
+		Profiles:      []*pb.ClientProfile{profile},
+		ActiveProfile: proto.String(profile.GetProfileName()),
+	}
+	if err := ValidateClientConfigPatch(patch); err != nil {
+		return "", fmt.Errorf("ValidateClientConfigPatch() failed: %w", err)
+	}
+	config, err := LoadClientConfig()
+	if err == stderror.ErrFileNotExist {
+		config = &pb.ClientConfig{}
+	} else if err != nil {
+		return "", fmt.Errorf("LoadClientConfig() failed: %w", err)
+	}
+	mergeClientConfigByProfile(config, patch)
+	if config.RpcPort == nil {
+		config.RpcPort = proto.Int32(defaultImportRPCPort)
+	}
+	if config.GetSocks5Port() == 0 {
+		config.Socks5Port = proto.Int32(defaultImportSocks5Port)
+	}
+	if err = ValidateFullClientConfig(config); err != nil {
+		return "", fmt.Errorf("ValidateFullClientConfig() failed: %w", err)
+	}
+	if err = StoreClientConfig(config); err != nil {
+		return "", fmt.Errorf("StoreClientConfig() failed: %w", err)
+	}
+	return profile.GetProfileName(), nil
+}
+
+// RedactClientConfig returns a copy of the client config, where the
+// passwords and subscription URLs are replaced by RedactedSecret.
+func RedactClientConfig(config *pb.ClientConfig) *pb.ClientConfig {
+	redacted := proto.Clone(config).(*pb.ClientConfig)
+	forEachClientConfigSecret(redacted, func(secret *string) error {
+		*secret = RedactedSecret
+		return nil
+	})
+	return redacted
+}
+
+// forEachClientConfigSecret calls f with each secret in the client config.
+// The secrets are the passwords and the subscription URLs, which usually
+// contain an access token. Empty secrets are skipped.
+//
+// The same secrets are redacted and encrypted. Add new secret fields here.
+func forEachClientConfigSecret(config *pb.ClientConfig, f func(secret *string) error) error {
+	var secrets []*string
+	for _, profile := range config.GetProfiles() {
+		if user := profile.GetUser(); user != nil {
+			secrets = append(secrets, user.Password, user.HashedPassword)
+		}
+		if auth := profile.GetUpstreamProxy().GetAuth(); auth != nil {
+			secrets = append(secrets, auth.Password)
+		}
+	}
+	for _, auth := range config.GetSocks5Authentication() {
+		secrets = append(secrets, auth.Password)
+	}
+	for _, subscription := range config.GetSubscriptions() {
+		secrets = append(secrets, subscription.Url)
+	}
+	for _, secret := range secrets {
+		if secret == nil || *secret == "" {
+			continue
+		}
+		if err := f(secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // LoadClientConfig reads client config from disk.
 func LoadClientConfig() (*pb.ClientConfig, error) {
 	clientIOLock.Lock()
@@ -281,6 +601,19 @@ func LoadClientConfig() (*pb.ClientConfig, error) {
 	default:
 		return nil, fmt.Errorf("config file type is invalid")
 	}
+	if version := c.GetSchemaVersion(); version > ConfigSchemaVersion {
+		log.Warnf("client config is stored by a newer version of mieru with schema version %d, some settings may be ignored", version)
+	} else if version < ConfigSchemaVersion {
+		if err := migrateClientConfig(c); err != nil {
+			return nil, fmt.Errorf("migrateClientConfig() failed: %w", err)
+		}
+		if err := rewriteMigratedConfigFile(fileName, fileType, b, version, c); err != nil {
+			log.Warnf("failed to store upgraded client config: %v", err)
+		}
+	}
+	if err := decryptClientConfigSecrets(c); err != nil {
+		return nil, fmt.Errorf("decryptClientConfigSecrets() failed: %w", err)
+	}
 
 	return c, nil
 }
@@ -301,15 +634,20 @@ func StoreClientConfig(config *pb.ClientConfig) error {
 	for _, profile := range config.GetProfiles() {
 		profile.User = HashUserPassword(profile.GetUser(), true)
 	}
+	config.SchemaVersion = proto.Uint32(ConfigSchemaVersion)
+	stored, err := encryptClientConfigSecrets(config)
+	if err != nil {
+		return fmt.Errorf("encryptClientConfigSecrets() failed: %w", err)
+	}
 
 	var b []byte
 	switch fileType {
 	case PROTOBUF_CONFIG_FILE_TYPE:
-		if b, err = proto.Marshal(config); err != nil {
+		if b, err = proto.Marshal(stored); err != nil {
 			return fmt.Errorf("proto.Marshal() failed: %w", err)
 		}
 	case JSON_CONFIG_FILE_TYPE:
-		if b, err = common.MarshalJSON(config); err != nil {
+		if b, err = common.MarshalJSON(stored); err != nil {
 			return fmt.Errorf("common.MarshalJSON() failed: %w", err)
 		}
 	default:
@@ -324,15 +662,19 @@ func StoreClientConfig(config *pb.ClientConfig) error {
 	return nil
 }
 
-// ApplyJSONClientConfig applies user provided JSON client config from the given file.
+// ApplyJSONClientConfig applies user provided client config from the given file.
+// The file can be JSON, YAML or TOML.
 func ApplyJSONClientConfig(path string) error {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("os.ReadFile(%q) failed: %w", path, err)
 	}
 	c := &pb.ClientConfig{}
-	if err = common.UnmarshalJSON(b, c); err != nil {
-		return fmt.Errorf("common.UnmarshalJSON() failed: %w", err)
+	if err = common.UnmarshalConfig(path, b, c); err != nil {
+		return fmt.Errorf("common.UnmarshalConfig() failed: %w", err)
+	}
+	if err = migrateConfigPatch(c); err != nil {
+		return fmt.Errorf("migrateConfigPatch() failed: %w", err)
 	}
 	return applyClientConfig(c)
 }
@@ -369,6 +711,11 @@ func DeleteClientConfigProfile(profileName string) error {
 	if config.GetActiveProfile() == profileName {
 		return fmt.Errorf("activeProfile %q can't be deleted", profileName)
 	}
+	for _, backup := range config.GetFailover().GetBackupProfiles() {
+		if backup == profileName {
+			return fmt.Errorf("backup profile %q can't be deleted", profileName)
+		}
+	}
 	profiles := config.GetProfiles()
 	updated := make([]*pb.ClientProfile, 0)
 	for _, profile := range profiles {
@@ -384,6 +731,184 @@ func DeleteClientConfigProfile(profileName string) error {
 	return nil
 }
 
+// ReloadClientConfig reads the client config from disk, and applies
+// the logging level, the user and proxy servers of the active profile,
+// the failover profiles, socks5 authentication and bypass list to the running proxy.
+//
+// Existing sessions keep using the current proxy servers,
+// unless migrateNow is true.
+func ReloadClientConfig(migrateNow bool) error {
+	config, err := LoadClientConfigWithOverrides()
+	if err != nil {
+		return fmt.Errorf("LoadClientConfigWithOverrides() failed: %w", err)
+	}
+	if err = ValidateFullClientConfig(config); err != nil {
+		return fmt.Errorf("ValidateFullClientConfig() failed: %w", err)
+	}
+
+	// Adjust loggingLevel and logFormat.
+	loggingLevel := config.GetLoggingLevel().String()
+	if loggingLevel != pb.LoggingLevel_DEFAULT.String() {
+		log.SetLevel(loggingLevel)
+	}
+	SetClientLogFormat(config.GetLogFormat())
+
+	// Adjust user and proxy servers of the active profile.
+	mux := clientMuxRef.Load()
+	if mux == nil {
+		return fmt.Errorf("client multiplexier is unavailable")
+	}
+	activeProfile, err := GetActiveProfileFromConfig(config, config.GetActiveProfile())
+	if err != nil {
+		return fmt.Errorf("GetActiveProfileFromConfig() failed: %w", err)
+	}
+	if err = applyClientProfileToMux(mux, activeProfile, &net.Resolver{}); err != nil {
+		return err
+	}
+	if migrateNow {
+		mux.CloseStaleUnderlays()
+	}
+
+	// Adjust failover profiles.
+	if failover := clientFailoverRef.Load(); failover != nil {
+		if err = failover.Update(config); err != nil {
+			return err
+		}
+	}
+
+	// Adjust socks5 authentication and bypass list.
+	gssapiAcceptor, err := Socks5GSSAPIFromConfig(config.GetSocks5GSSAPI())
+	if err != nil {
+		return err
+	}
+	if socks5Server := clientSocks5ServerRef.Load(); socks5Server != nil {
+		socks5Server.SetIngressCredentials(Socks5AuthenticationToCredentials(config.GetSocks5Authentication()))
+		socks5Server.SetGSSAPIAcceptor(gssapiAcceptor)
+		bypass, err := BypassListFromConfig(config.GetBypass(), config.GetGeoDatabases())
+		if err != nil {
+			return err
+		}
+		socks5Server.SetBypass(bypass)
+	}
+	if listeners := clientSocks5ListenersRef.Load(); listeners != nil {
+		listeners.SetIngressCredentials(Socks5AuthenticationToCredentials(config.GetSocks5Authentication()))
+		listeners.SetGSSAPIAcceptor(gssapiAcceptor)
+	}
+	return nil
+}
+
+// ClientUserHashedPassword returns the hashed password of a client user.
+func ClientUserHashedPassword(user *pb.User) ([]byte, error) {
+	if user.GetHashedPassword() != "" {
+		hashedPassword, err := hex.DecodeString(user.GetHashedPassword())
+		if err != nil {
+			return nil, fmt.Errorf(stderror.DecodeHashedPasswordFailedErr, err)
+		}
+		return hashedPassword, nil
+	}
+	return cipher.HashPassword([]byte(user.GetPassword()), []byte(user.GetName())), nil
+}
+
+// Socks5AuthenticationToCredentials converts socks5 authentication
+// in the client config to socks5 credentials.
+func Socks5AuthenticationToCredentials(auths []*pb.Auth) []socks5.Credential {
+	var credentials []socks5.Credential
+	for _, auth := range auths {
+		credentials = append(credentials, socks5.Credential{
+			User:     auth.GetUser(),
+			Password: auth.GetPassword(),
+		})
+	}
+	return credentials
+}
+
+// Socks5GSSAPIFromConfig loads the keytab of socks5 GSSAPI authentication
+// in the client config, and returns the GSSAPI acceptor. It returns nil
+// if GSSAPI authentication is not configured.
+func Socks5GSSAPIFromConfig(config *pb.Socks5GSSAPI) (*socks5.GSSAPIAcceptor, error) {
+	if config == nil {
+		return nil, nil
+	}
+	kt, err := keytab.Load(config.GetKeytabPath())
+	if err != nil {
+		return nil, fmt.Errorf("load keytab %q failed: %w", config.GetKeytabPath(), err)
+	}
+	return socks5.NewGSSAPIAcceptor(kt, config.GetServicePrincipal(), config.GetAllowedPrincipals(), config.GetRequireIntegrity()), nil
+}
+
+// KeepaliveRulesFromConfig converts the keepalive rules in client
+// config to socks5 keepalive rules. Invalid rules are skipped.
+func KeepaliveRulesFromConfig(rules []*pb.KeepaliveRule) []socks5.KeepaliveRule {
+	var res []socks5.KeepaliveRule
+	for _, rule := range rules {
+		r, err := parseKeepaliveRule(rule)
+		if err != nil {
+			log.Warnf("Skipping keepalive rule: %v", err)
+			continue
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// BypassListFromConfig creates the socks5 bypass list from the inline
+// rules and rule files in client config. The GeoIP and geosite databases
+// are loaded if the bypass list has country or category rules.
+func BypassListFromConfig(config *pb.BypassConfig, geo *pb.GeoDatabases) (*socks5.BypassList, error) {
+	bypass, err := socks5.NewBypassList(config.GetRules())
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range config.GetRuleFiles() {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open bypass rule file: %w", err)
+		}
+		err = bypass.Load(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load bypass rule file %q: %w", path, err)
+		}
+	}
+	if bypass.NeedsGeoDatabases() {
+		geoIP, site, err := appctlcommon.LoadGeoDatabases(geo)
+		if err != nil {
+			return nil, err
+		}
+		if err := bypass.UseDatabases(geoIP, site); err != nil {
+			return nil, err
+		}
+	}
+	return bypass, nil
+}
+
+func parseKeepaliveRule(rule *pb.KeepaliveRule) (socks5.KeepaliveRule, error) {
+	interval, err := time.ParseDuration(rule.GetInterval())
+	if err != nil {
+		return socks5.KeepaliveRule{}, fmt.Errorf("keepalive interval %q is invalid: %w", rule.GetInterval(), err)
+	}
+	if interval < time.Second {
+		return socks5.KeepaliveRule{}, fmt.Errorf("keepalive interval %q is less than 1 second", rule.GetInterval())
+	}
+	r := socks5.KeepaliveRule{Interval: interval}
+	for _, ports := range rule.GetDestinationPorts() {
+		if strings.Contains(ports, "-") {
+			begin, end, err := appctlcommon.ParsePortRange(ports)
+			if err != nil {
+				return socks5.KeepaliveRule{}, fmt.Errorf("keepalive destination ports %q is invalid: %w", ports, err)
+			}
+			r.Ports = append(r.Ports, [2]int{begin, end})
+			continue
+		}
+		port, err := strconv.Atoi(ports)
+		if err != nil || port < 1 || port > 65535 {
+			return socks5.KeepaliveRule{}, fmt.Errorf("keepalive destination port %q is invalid", ports)
+		}
+		r.Ports = append(r.Ports, [2]int{port, port})
+	}
+	return r, nil
+}
+
 // ValidateClientConfigPatch validates a patch of client config.
 //
 // A client config patch must satisfy:
@@ -391,6 +916,21 @@ func DeleteClientConfigProfile(profileName string) error {
 // 2. validate each profile
 // 3. for each socks5 authentication, the user and password are not empty
 // 4. metrics logging interval is valid, and it is not less than 1 second
+// 5. if set, OTLP trace endpoint is valid
+// 6. failover max failures is not negative, and health check interval is valid
+// 7. fair share bandwidth is not negative
+// 8. each keepalive rule has valid destination ports and interval
+// 9. each bypass rule is valid, and each bypass rule file is an absolute path
+// 10. if set, GeoIP and geosite database paths are absolute paths, and download URLs
+// are HTTP or HTTPS URLs
+// 11. if set, socks5 and HTTP proxy unix domain socket paths are different absolute
+// paths, and modes are octal file permissions
+// 12. each additional socks5 listener has valid bypass rules, and each bypass rule
+// file is an absolute path
+// 13. if set, socks5 GSSAPI keytab path is an absolute path, and allowed
+// principals are not empty
+// 14. if set, accept limit handshake timeout is valid and not less than 1 second,
+// and max half-open connections and accept rate are not negative
 func ValidateClientConfigPatch(patch *pb.ClientConfig) error {
 	for _, profile := range patch.GetProfiles() {
 		if err := appctlcommon.ValidateClientConfigSingleProfile(profile); err != nil {
@@ -414,6 +954,97 @@ func ValidateClientConfigPatch(patch *pb.ClientConfig) error {
 			return fmt.Errorf("metrics logging interval %q is less than 1 second", patch.GetAdvancedSettings().GetMetricsLoggingInterval())
 		}
 	}
+	if patch.GetAdvancedSettings().GetOtlpTraceEndpoint() != "" {
+		if err := tracing.ValidateEndpoint(patch.GetAdvancedSettings().GetOtlpTraceEndpoint()); err != nil {
+			return err
+		}
+	}
+	if err := appctlcommon.ValidateAcceptLimitConfig(patch.GetAdvancedSettings().GetAcceptLimit()); err != nil {
+		return err
+	}
+	if patch.GetFailover().GetMaxFailures() < 0 {
+		return fmt.Errorf("failover max failures %d is negative", patch.GetFailover().GetMaxFailures())
+	}
+	if patch.GetFailover().GetHealthCheckInterval() != "" {
+		d, err := time.ParseDuration(patch.GetFailover().GetHealthCheckInterval())
+		if err != nil {
+			return fmt.Errorf("failover health check interval %q is invalid: %w", patch.GetFailover().GetHealthCheckInterval(), err)
+		}
+		if d < time.Second {
+			return fmt.Errorf("failover health check interval %q is less than 1 second", patch.GetFailover().GetHealthCheckInterval())
+		}
+	}
+	if patch.GetFairShareBandwidthMbps() < 0 {
+		return fmt.Errorf("fair share bandwidth %d Mbps is negative", patch.GetFairShareBandwidthMbps())
+	}
+	for _, rule := range patch.GetKeepaliveRules() {
+		if _, err := parseKeepaliveRule(rule); err != nil {
+			return err
+		}
+	}
+	if _, err := socks5.NewBypassList(patch.GetBypass().GetRules()); err != nil {
+		return err
+	}
+	for _, path := range patch.GetBypass().GetRuleFiles() {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("bypass rule file %q is not an absolute path", path)
+		}
+	}
+	if err := appctlcommon.ValidateGeoDatabasesConfig(patch.GetGeoDatabases()); err != nil {
+		return err
+	}
+	if err := validateTransparentProxy(patch.GetTransparentProxy()); err != nil {
+		return err
+	}
+	if patch.GetTunDevice() != nil {
+		if _, err := GetTUNDeviceOptions(patch.GetTunDevice()); err != nil {
+			return err
+		}
+	}
+	for _, socket := range []*pb.UnixSocket{patch.GetSocks5UnixSocket(), patch.GetHttpProxyUnixSocket()} {
+		if socket == nil {
+			continue
+		}
+		if !filepath.IsAbs(socket.GetPath()) {
+			return fmt.Errorf("unix domain socket path %q is not an absolute path", socket.GetPath())
+		}
+		if _, err := common.ParseUnixSocketMode(socket.GetMode()); err != nil {
+			return err
+		}
+	}
+	if patch.GetSocks5UnixSocket() != nil && patch.GetSocks5UnixSocket().GetPath() == patch.GetHttpProxyUnixSocket().GetPath() {
+		return fmt.Errorf("socks5 and HTTP proxy can't listen to the same unix domain socket %q", patch.GetSocks5UnixSocket().GetPath())
+	}
+	for _, listener := range patch.GetSocks5Listeners() {
+		if _, err := socks5.NewBypassList(listener.GetBypass().GetRules()); err != nil {
+			return err
+		}
+		for _, path := range listener.GetBypass().GetRuleFiles() {
+			if !filepath.IsAbs(path) {
+				return fmt.Errorf("bypass rule file %q is not an absolute path", path)
+			}
+		}
+	}
+	if gssapi := patch.GetSocks5GSSAPI(); gssapi != nil {
+		if !filepath.IsAbs(gssapi.GetKeytabPath()) {
+			return fmt.Errorf("socks5 GSSAPI keytab path %q is not an absolute path", gssapi.GetKeytabPath())
+		}
+		for _, principal := range gssapi.GetAllowedPrincipals() {
+			if principal == "" {
+				return fmt.Errorf("socks5 GSSAPI allowed principal is empty")
+			}
+		}
+	}
+	subscriptionNames := make(map[string]struct{})
+	for _, subscription := range patch.GetSubscriptions() {
+		if err := validateSubscription(subscription); err != nil {
+			return err
+		}
+		if _, found := subscriptionNames[subscription.GetName()]; found {
+			return fmt.Errorf("subscription %q is duplicated", subscription.GetName())
+		}
+		subscriptionNames[subscription.GetName()] = struct{}{}
+	}
 	return nil
 }
 
@@ -426,6 +1057,11 @@ func ValidateClientConfigPatch(patch *pb.ClientConfig) error {
 // 4. socks5 port is valid
 // 5. RPC port, socks5 port, http proxy port are different
 // 6. if set, metrics logging interval is valid, and it is not less than 1 second
+// 7. each failover backup profile is available, and it is not the active profile
+// 8. each additional socks5 listener has a valid port that is different from
+// other ports, and its profile is available
+// 9. if set, transparent proxy has a valid port that is different from other ports
+// 10. transparent proxy and TUN device are not both enabled
 func ValidateFullClientConfig(config *pb.ClientConfig) error {
 	if err := ValidateClientConfigPatch(config); err != nil {
 		return err
@@ -447,6 +1083,19 @@ func ValidateFullClientConfig(config *pb.ClientConfig) error {
 	if !foundActiveProfile {
 		return fmt.Errorf("active profile is not found in the profile list")
 	}
+	backupProfiles := map[string]struct{}{}
+	for _, name := range config.GetFailover().GetBackupProfiles() {
+		if name == config.GetActiveProfile() {
+			return fmt.Errorf("backup profile %q is the active profile", name)
+		}
+		if _, found := backupProfiles[name]; found {
+			return fmt.Errorf("backup profile %q is duplicated", name)
+		}
+		if _, err := GetActiveProfileFromConfig(config, name); err != nil {
+			return fmt.Errorf("backup profile %q is not found in the profile list", name)
+		}
+		backupProfiles[name] = struct{}{}
+	}
 	// RPC port is allowed to be 0, which means disable RPC.
 	if config.GetRpcPort() < 0 || config.GetRpcPort() > 65535 {
 		return fmt.Errorf("RPC port number %d is invalid", config.GetRpcPort())
@@ -468,6 +1117,36 @@ func ValidateFullClientConfig(config *pb.ClientConfig) error {
 			return fmt.Errorf("HTTP proxy port number %d is the same as socks5 port number", config.GetHttpProxyPort())
 		}
 	}
+	usedPorts := map[int32]struct{}{
+		config.GetRpcPort():       {},
+		config.GetSocks5Port():    {},
+		config.GetHttpProxyPort(): {},
+	}
+	for _, listener := range config.GetSocks5Listeners() {
+		if listener.GetPort() < 1 || listener.GetPort() > 65535 {
+			return fmt.Errorf("socks5 listener port number %d is invalid", listener.GetPort())
+		}
+		if _, found := usedPorts[listener.GetPort()]; found {
+			return fmt.Errorf("socks5 listener port number %d is already used", listener.GetPort())
+		}
+		usedPorts[listener.GetPort()] = struct{}{}
+		if listener.GetProfile() != "" {
+			if _, err := GetActiveProfileFromConfig(config, listener.GetProfile()); err != nil {
+				return fmt.Errorf("profile %q of socks5 listener is not found in the profile list", listener.GetProfile())
+			}
+		}
+	}
+	if tproxy := config.GetTransparentProxy(); tproxy != nil {
+		if tproxy.GetPort() < 1 || tproxy.GetPort() > 65535 {
+			return fmt.Errorf("transparent proxy port number %d is invalid", tproxy.GetPort())
+		}
+		if _, found := usedPorts[tproxy.GetPort()]; found {
+			return fmt.Errorf("transparent proxy port number %d is already used", tproxy.GetPort())
+		}
+	}
+	if config.GetTransparentProxy() != nil && config.GetTunDevice() != nil {
+		return fmt.Errorf("transparent proxy and TUN device can't be enabled at the same time")
+	}
 	return nil
 }
 
@@ -484,6 +1163,82 @@ func GetActiveProfileFromConfig(config *pb.ClientConfig, name string) (*pb.Clien
 	return nil, fmt.Errorf("profile %q is not found", name)
 }
 
+// ClientProfileToUnderlayProperties converts the proxy servers in a client
+// profile to underlay properties. Domain names are resolved to IP addresses.
+// If the profile has a bootstrap DoH URL, it is used instead of the resolver.
+func ClientProfileToUnderlayProperties(profile *pb.ClientProfile, resolver apicommon.DNSResolver) ([]protocol.UnderlayProperties, error) {
+	mtu := common.DefaultMTU
+	if profile.GetMtu() != 0 {
+		mtu = int(profile.GetMtu())
+	}
+	if profile.GetBootstrapDoHURL() != "" {
+		resolver = &common.DoHResolver{URL: profile.GetBootstrapDoHURL()}
+	}
+	endpoints := make([]protocol.UnderlayProperties, 0)
+	for _, serverInfo := range profile.GetServers() {
+		proxyIPs, err := appctlcommon.ResolveServerIPs(context.Background(), serverInfo, resolver)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := appctlcommon.FlatPortBindings(serverInfo.GetPortBindings()); err != nil {
+			return nil, fmt.Errorf(stderror.InvalidPortBindingsErr, err)
+		}
+		portBindings, _ := appctlcommon.FlatPortBindings(appctlcommon.PortBindingsWithoutHopping(serverInfo.GetPortBindings()))
+		hoppingOpts := appctlcommon.PortHoppingOptionsFromConfig(serverInfo.GetPortBindings())
+		knockingOpts := appctlcommon.PortKnockingOptionsFromConfig(serverInfo.GetPortKnocking())
+		for _, proxyIP := range proxyIPs {
+			for _, opts := range hoppingOpts {
+				endpoint := protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil, &net.UDPAddr{IP: proxyIP, Port: opts.BeginPort})
+				endpoint = protocol.WithPortHopping(endpoint, opts)
+				if knockingOpts != nil {
+					endpoint = protocol.WithPortKnocking(endpoint, knockingOpts)
+				}
+				endpoints = append(endpoints, endpoint)
+			}
+			for _, bindingInfo := range portBindings {
+				proxyPort := bindingInfo.GetPort()
+				var endpoint protocol.UnderlayProperties
+				switch bindingInfo.GetProtocol() {
+				case pb.TransportProtocol_TCP:
+					if opts := appctlcommon.ClientTLSCamouflageOptions(serverInfo); opts != nil {
+						endpoint = protocol.NewTLSCamouflageUnderlayProperties(mtu, nil, &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)}, opts)
+					} else {
+						endpoint = protocol.NewUnderlayProperties(mtu, common.StreamTransport, nil, &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)})
+					}
+				case pb.TransportProtocol_UDP:
+					endpoint = protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil, &net.UDPAddr{IP: proxyIP, Port: int(proxyPort)})
+				case pb.TransportProtocol_WEBSOCKET:
+					endpoint = protocol.NewWebSocketUnderlayProperties(mtu, nil, &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)}, appctlcommon.ClientWebSocketOptions(serverInfo))
+				case pb.TransportProtocol_AUTO:
+					// The multiplexer selects TCP or UDP by latency.
+					var tcpEndpoint protocol.UnderlayProperties
+					if opts := appctlcommon.ClientTLSCamouflageOptions(serverInfo); opts != nil {
+						tcpEndpoint = protocol.NewTLSCamouflageUnderlayProperties(mtu, nil, &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)}, opts)
+					} else {
+						tcpEndpoint = protocol.NewUnderlayProperties(mtu, common.StreamTransport, nil, &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)})
+					}
+					udpEndpoint := protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil, &net.UDPAddr{IP: proxyIP, Port: int(proxyPort)})
+					for _, endpoint := range []protocol.UnderlayProperties{tcpEndpoint, udpEndpoint} {
+						endpoint = protocol.WithAutoTransport(endpoint)
+						if knockingOpts != nil {
+							endpoint = protocol.WithPortKnocking(endpoint, knockingOpts)
+						}
+						endpoints = append(endpoints, endpoint)
+					}
+					continue
+				default:
+					return nil, fmt.Errorf(stderror.InvalidTransportProtocol)
+				}
+				if knockingOpts != nil {
+					endpoint = protocol.WithPortKnocking(endpoint, knockingOpts)
+				}
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	return endpoints, nil
+}
+
 // ClientUpdaterHistoryPath returns the file path to retrieve
 // client updater history.
 func ClientUpdaterHistoryPath() (string, error) {
@@ -493,6 +1248,15 @@ func ClientUpdaterHistoryPath() (string, error) {
 	return filepath.Join(cachedClientConfigDir, "client.updater.pb"), nil
 }
 
+// ClientTrustStore returns the public keys trusted by the client
+// to verify signatures.
+func ClientTrustStore() (*minisign.TrustStore, error) {
+	if err := prepareClientConfigDir(); err != nil {
+		return nil, err
+	}
+	return minisign.LoadTrustStore(filepath.Join(cachedClientConfigDir, "trusted_keys.json"), version.ReleasePublicKeys)
+}
+
 // newClientManagementRPCClient creates a new ClientManagementService RPC client
 // and connects to the given server address.
 func newClientManagementRPCClient(serverAddr string) (appctlgrpc.ClientManagementServiceClient, error) {
@@ -551,6 +1315,20 @@ func applyClientConfig(c *pb.ClientConfig) error {
 		return fmt.Errorf("LoadClientConfig() failed: %w", err)
 	}
 	mergeClientConfigByProfile(config, c)
+
+	// Fetch the subscriptions in the patch, so their profiles can be used.
+	for _, subscription := range c.GetSubscriptions() {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), subscriptionFetchTimeout)
+		doc, err := fetchSubscription(ctx, subscription)
+		cancelFunc()
+		if err != nil {
+			return err
+		}
+		if err = mergeSubscription(config, subscription, doc); err != nil {
+			return err
+		}
+	}
+
 	if err = ValidateFullClientConfig(config); err != nil {
 		return fmt.Errorf("ValidateFullClientConfig() failed: %w", err)
 	}
@@ -635,6 +1413,67 @@ func mergeClientConfigByProfile(dst, src *pb.ClientConfig) {
 	if src.Socks5Authentication != nil {
 		socks5Authentication = src.Socks5Authentication
 	}
+	var failover *pb.ProfileFailover = dst.Failover
+	if src.Failover != nil {
+		failover = src.Failover
+	}
+	var fairShareBandwidthMbps *int32 = dst.FairShareBandwidthMbps
+	if src.FairShareBandwidthMbps != nil {
+		fairShareBandwidthMbps = src.FairShareBandwidthMbps
+	}
+	var socks5UDPSourceFilter *pb.UDPSourceFilter = dst.Socks5UDPSourceFilter
+	if src.Socks5UDPSourceFilter != nil {
+		socks5UDPSourceFilter = src.Socks5UDPSourceFilter
+	}
+	var keepaliveRules []*pb.KeepaliveRule = dst.KeepaliveRules
+	if src.KeepaliveRules != nil {
+		keepaliveRules = src.KeepaliveRules
+	}
+
+	var bypass *pb.BypassConfig = dst.Bypass
+	if src.Bypass != nil {
+		bypass = src.Bypass
+	}
+	var geoDatabases *pb.GeoDatabases = dst.GeoDatabases
+	if src.GeoDatabases != nil {
+		geoDatabases = src.GeoDatabases
+	}
+	var transparentProxy *pb.TransparentProxy = dst.TransparentProxy
+	if src.TransparentProxy != nil {
+		transparentProxy = src.TransparentProxy
+	}
+	var tunDevice *pb.TUNDevice = dst.TunDevice
+	if src.TunDevice != nil {
+		tunDevice = src.TunDevice
+	}
+	var socks5UnixSocket *pb.UnixSocket = dst.Socks5UnixSocket
+	if src.Socks5UnixSocket != nil {
+		socks5UnixSocket = src.Socks5UnixSocket
+	}
+	var httpProxyUnixSocket *pb.UnixSocket = dst.HttpProxyUnixSocket
+	if src.HttpProxyUnixSocket != nil {
+		httpProxyUnixSocket = src.HttpProxyUnixSocket
+	}
+	var socks5Listeners []*pb.Socks5Listener = dst.Socks5Listeners
+	if src.Socks5Listeners != nil {
+		socks5Listeners = src.Socks5Listeners
+	}
+	var logFormat *pb.LogFormat = dst.LogFormat
+	if src.LogFormat != nil {
+		logFormat = src.LogFormat
+	}
+	var subscriptions []*pb.Subscription = dst.Subscriptions
+	if src.Subscriptions != nil {
+		subscriptions = src.Subscriptions
+	}
+	var configEncryption *pb.ConfigEncryption = dst.ConfigEncryption
+	if src.ConfigEncryption != nil {
+		configEncryption = src.ConfigEncryption
+	}
+	var socks5GSSAPI *pb.Socks5GSSAPI = dst.Socks5GSSAPI
+	if src.Socks5GSSAPI != nil {
+		socks5GSSAPI = src.Socks5GSSAPI
+	}
 
 	proto.Reset(dst)
 
@@ -649,6 +1488,21 @@ func mergeClientConfigByProfile(dst, src *pb.ClientConfig) {
 	dst.HttpProxyPort = httpProxyPort
 	dst.HttpProxyListenLAN = httpProxyListenLAN
 	dst.Socks5Authentication = socks5Authentication
+	dst.Failover = failover
+	dst.FairShareBandwidthMbps = fairShareBandwidthMbps
+	dst.Socks5UDPSourceFilter = socks5UDPSourceFilter
+	dst.KeepaliveRules = keepaliveRules
+	dst.Bypass = bypass
+	dst.GeoDatabases = geoDatabases
+	dst.TransparentProxy = transparentProxy
+	dst.TunDevice = tunDevice
+	dst.Socks5UnixSocket = socks5UnixSocket
+	dst.HttpProxyUnixSocket = httpProxyUnixSocket
+	dst.Socks5Listeners = socks5Listeners
+	dst.LogFormat = logFormat
+	dst.Subscriptions = subscriptions
+	dst.ConfigEncryption = configEncryption
+	dst.Socks5GSSAPI = socks5GSSAPI
 }
 
 // deleteClientConfigFile deletes the client config file.
@@ -17,11 +17,21 @@ package appctl
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	apicommon "github.com/enfein/mieru/v3/apis/common"
 	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
 	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"github.com/enfein/mieru/v3/pkg/testtool"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -66,15 +76,67 @@ func TestApply2ClientConfig(t *testing.T) {
 	afterClientTest(t)
 }
 
+func TestApplyClientConfigYAMLAndTOML(t *testing.T) {
+	beforeClientTest(t)
+
+	jsonFile := "testdata/client_apply_config_1.json"
+	if err := ApplyJSONClientConfig(jsonFile); err != nil {
+		t.Fatalf("ApplyJSONClientConfig(%q) failed: %v", jsonFile, err)
+	}
+	want, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+
+	for _, configFile := range []string{"testdata/client_apply_config_1.yaml", "testdata/client_apply_config_1.toml"} {
+		if err := deleteClientConfigFile(); err != nil {
+			t.Fatalf("failed to delete client config file")
+		}
+		if err := StoreClientConfig(&pb.ClientConfig{}); err != nil {
+			t.Fatalf("failed to create empty client config file")
+		}
+		if err := ApplyJSONClientConfig(configFile); err != nil {
+			t.Fatalf("ApplyJSONClientConfig(%q) failed: %v", configFile, err)
+		}
+		got, err := LoadClientConfig()
+		if err != nil {
+			t.Fatalf("LoadClientConfig() failed: %v", err)
+		}
+		if !proto.Equal(got, want) {
+			gotJSON, _ := common.MarshalJSON(got)
+			wantJSON, _ := common.MarshalJSON(want)
+			t.Errorf("client config from %q doesn't equal:\ngot = %v\nwant = %v", configFile, string(gotJSON), string(wantJSON))
+		}
+	}
+
+	afterClientTest(t)
+}
+
 func TestClientApplyReject(t *testing.T) {
 	cases := []string{
 		"testdata/client_reject_active_profile_mismatch.json",
+		"testdata/client_reject_failover_backup_is_active_profile.json",
+		"testdata/client_reject_failover_backup_not_found.json",
+		"testdata/client_reject_geo_databases_relative_path.json",
+		"testdata/client_reject_hybrid_key_exchange_udp.json",
+		"testdata/client_reject_invalid_bootstrap_doh_url.json",
+		"testdata/client_reject_invalid_bypass_rule.json",
+		"testdata/client_reject_invalid_failover_health_check_interval.json",
 		"testdata/client_reject_invalid_http_port.json",
+		"testdata/client_reject_invalid_keepalive_port.json",
 		"testdata/client_reject_invalid_metrics_logging_interval.json",
+		"testdata/client_reject_invalid_otlp_trace_endpoint.json",
 		"testdata/client_reject_invalid_rpc_port.json",
+		"testdata/client_reject_keepalive_interval_too_small.json",
+		"testdata/client_reject_key_rotation_short_period.json",
+		"testdata/client_reject_port_knocking_single_step.json",
+		"testdata/client_reject_upstream_proxy_udp.json",
 		"testdata/client_reject_metrics_logging_interval_too_small.json",
 		"testdata/client_reject_mtu_too_big.json",
 		"testdata/client_reject_mtu_too_small.json",
+		"testdata/client_reject_multipath_duplicate_interface.json",
+		"testdata/client_reject_negative_fair_share_bandwidth.json",
+		"testdata/client_reject_negative_max_connections.json",
 		"testdata/client_reject_no_active_profile.json",
 		"testdata/client_reject_no_password.json",
 		"testdata/client_reject_no_port_binding.json",
@@ -91,9 +153,23 @@ func TestClientApplyReject(t *testing.T) {
 		"testdata/client_reject_same_port_rpc_socks5.json",
 		"testdata/client_reject_socks5_auth_no_password.json",
 		"testdata/client_reject_socks5_auth_no_user.json",
+		"testdata/client_reject_socks5_listener_profile_not_found.json",
+		"testdata/client_reject_socks5_listener_same_port.json",
+		"testdata/client_reject_tls_camouflage_ip_server_name.json",
+		"testdata/client_reject_transparent_proxy_invalid_cgroup.json",
+		"testdata/client_reject_transparent_proxy_invalid_user.json",
+		"testdata/client_reject_transparent_proxy_same_port.json",
+		"testdata/client_reject_transport_plugin_no_command.json",
+		"testdata/client_reject_transport_plugin_udp.json",
+		"testdata/client_reject_tun_device_invalid_address.json",
+		"testdata/client_reject_tun_device_with_transparent_proxy.json",
+		"testdata/client_reject_unix_socket_relative_path.json",
+		"testdata/client_reject_unix_socket_same_path.json",
 		"testdata/client_reject_user_has_quota.json",
+		"testdata/client_reject_websocket_invalid_path.json",
 		"testdata/client_reject_wrong_ipv4_address.json",
 		"testdata/client_reject_wrong_ipv6_address.json",
+		"testdata/client_reject_wrong_pinned_ip_address.json",
 	}
 	for _, c := range cases {
 		t.Run(c, func(t *testing.T) {
@@ -215,6 +291,158 @@ func TestClientDeleteProfileRejectActiveProfile(t *testing.T) {
 	afterClientTest(t)
 }
 
+func TestClientExportConfigRedact(t *testing.T) {
+	beforeClientTest(t)
+
+	configFile := "testdata/client_before_delete_profile.json"
+	if err := ApplyJSONClientConfig(configFile); err != nil {
+		t.Fatalf("ApplyJSONClientConfig(%q) failed: %v", configFile, err)
+	}
+	config, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+	for _, format := range []string{ExportFormatJSON, ExportFormatYAML, ExportFormatURI} {
+		out, err := ExportClientConfig(format, true)
+		if err != nil {
+			t.Fatalf("ExportClientConfig(%q) failed: %v", format, err)
+		}
+		again, err := ExportClientConfig(format, true)
+		if err != nil {
+			t.Fatalf("ExportClientConfig(%q) failed: %v", format, err)
+		}
+		if out != again {
+			t.Errorf("ExportClientConfig(%q) output is not stable", format)
+		}
+		if format == ExportFormatURI {
+			continue
+		}
+		for _, profile := range config.GetProfiles() {
+			if password := profile.GetUser().GetPassword(); password != "" && strings.Contains(out, password) {
+				t.Errorf("ExportClientConfig(%q) output contains password %q", format, profile.GetUser().GetPassword())
+			}
+		}
+		if !strings.Contains(out, RedactedSecret) {
+			t.Errorf("ExportClientConfig(%q) output doesn't contain %q", format, RedactedSecret)
+		}
+	}
+
+	// Redaction doesn't modify the original config.
+	redacted := RedactClientConfig(config)
+	if proto.Equal(redacted, config) {
+		t.Errorf("RedactClientConfig() doesn't change passwords")
+	}
+	if config.GetProfiles()[0].GetUser().GetPassword() == RedactedSecret {
+		t.Errorf("RedactClientConfig() modified the original config")
+	}
+
+	afterClientTest(t)
+}
+
+func TestRedactClientConfigUpstreamProxy(t *testing.T) {
+	config := &pb.ClientConfig{
+		Profiles: []*pb.ClientProfile{
+			{
+				ProfileName: proto.String("default"),
+				User: &pb.User{
+					Name:     proto.String("user1"),
+					Password: proto.String("fa7206ed2a94"),
+				},
+				UpstreamProxy: &pb.UpstreamProxy{
+					Protocol: pb.UpstreamProxyProtocol_UPSTREAM_SOCKS5.Enum(),
+					Address:  proto.String("10.0.0.1:1080"),
+					Auth: &pb.Auth{
+						User:     proto.String("proxyuser"),
+						Password: proto.String("proxypassword"),
+					},
+				},
+			},
+		},
+	}
+	redacted := RedactClientConfig(config)
+	auth := redacted.GetProfiles()[0].GetUpstreamProxy().GetAuth()
+	if auth.GetPassword() != RedactedSecret {
+		t.Errorf("upstream proxy password is %q, want %q", auth.GetPassword(), RedactedSecret)
+	}
+	if auth.GetUser() != "proxyuser" {
+		t.Errorf("upstream proxy user is %q, want %q", auth.GetUser(), "proxyuser")
+	}
+	if config.GetProfiles()[0].GetUpstreamProxy().GetAuth().GetPassword() != "proxypassword" {
+		t.Errorf("RedactClientConfig() modified the original config")
+	}
+}
+
+func TestClientExportImportProfile(t *testing.T) {
+	beforeClientTest(t)
+
+	configFile := "testdata/client_before_delete_profile.json"
+	if err := ApplyJSONClientConfig(configFile); err != nil {
+		t.Fatalf("ApplyJSONClientConfig(%q) failed: %v", configFile, err)
+	}
+	config, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+	// Export a profile that is not active.
+	var want *pb.ClientProfile
+	for _, profile := range config.GetProfiles() {
+		if profile.GetProfileName() != config.GetActiveProfile() {
+			want = profile
+		}
+	}
+	if want == nil {
+		t.Fatalf("test requires a profile that is not active")
+	}
+	name := want.GetProfileName()
+	u, err := ExportClientProfile(name, ProfileExportFormatURL)
+	if err != nil {
+		t.Fatalf("ExportClientProfile() failed: %v", err)
+	}
+	j, err := ExportClientProfile(name, ProfileExportFormatJSON)
+	if err != nil {
+		t.Fatalf("ExportClientProfile() failed: %v", err)
+	}
+	if _, err := ExportClientProfile(name, ProfileExportFormatQRCode); err != nil {
+		t.Fatalf("ExportClientProfile() failed: %v", err)
+	}
+	if _, err := ExportClientProfile("not-exist", ProfileExportFormatURL); err == nil {
+		t.Errorf("ExportClientProfile() didn't reject unknown profile")
+	}
+	jsonFile := filepath.Join(t.TempDir(), "profile.json")
+	if err := os.WriteFile(jsonFile, []byte(j), 0600); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	// Import to an empty client config.
+	for _, source := range []string{u, jsonFile} {
+		if err := deleteClientConfigFile(); err != nil {
+			t.Fatalf("failed to delete client config file")
+		}
+		got, err := ImportClientProfile(source)
+		if err != nil {
+			t.Fatalf("ImportClientProfile() failed: %v", err)
+		}
+		if got != name {
+			t.Errorf("ImportClientProfile() = %q, want %q", got, name)
+		}
+		imported, err := LoadClientConfig()
+		if err != nil {
+			t.Fatalf("LoadClientConfig() failed: %v", err)
+		}
+		if imported.GetActiveProfile() != name {
+			t.Errorf("active profile is %q, want %q", imported.GetActiveProfile(), name)
+		}
+		if imported.GetSocks5Port() != defaultImportSocks5Port || imported.GetRpcPort() != defaultImportRPCPort {
+			t.Errorf("got socks5 port %d and RPC port %d, want default ports", imported.GetSocks5Port(), imported.GetRpcPort())
+		}
+		if len(imported.GetProfiles()) != 1 || !proto.Equal(imported.GetProfiles()[0], want) {
+			t.Errorf("imported profiles %v, want %v", imported.GetProfiles(), want)
+		}
+	}
+
+	afterClientTest(t)
+}
+
 func TestClientGetVersion(t *testing.T) {
 	rpcServer := NewClientManagementService()
 	_, err := rpcServer.GetVersion(context.Background(), &emptypb.Empty{})
@@ -242,3 +470,215 @@ func afterClientTest(t *testing.T) {
 		t.Fatalf("failed to clean client config file after the test")
 	}
 }
+
+func TestReloadClientConfigAfterDial(t *testing.T) {
+	beforeClientTest(t)
+	defer afterClientTest(t)
+
+	_, port := startTestProxyServer(t, []*pb.User{
+		{Name: proto.String("user1"), Password: proto.String("fa7206ed2a94")},
+		{Name: proto.String("user2"), Password: proto.String("6a1b7e0c93d5")},
+	})
+	clientConfig := func(name, password string) *pb.ClientConfig {
+		return &pb.ClientConfig{
+			Profiles:      []*pb.ClientProfile{testClientProfile("default", name, password, port)},
+			ActiveProfile: proto.String("default"),
+			RpcPort:       proto.Int32(1989),
+			Socks5Port:    proto.Int32(1080),
+		}
+	}
+	config := clientConfig("user1", "fa7206ed2a94")
+	if err := StoreClientConfig(config); err != nil {
+		t.Fatalf("StoreClientConfig() failed: %v", err)
+	}
+	mux := protocol.NewMux(true)
+	defer mux.Close()
+	if err := applyClientProfileToMux(mux, config.GetProfiles()[0], &net.Resolver{}); err != nil {
+		t.Fatalf("applyClientProfileToMux() failed: %v", err)
+	}
+	SetClientMuxRef(mux)
+	defer SetClientMuxRef(nil)
+
+	// The mux is used before the user is changed.
+	if err := echoThroughMux(mux); err != nil {
+		t.Fatalf("echoThroughMux() failed: %v", err)
+	}
+	if err := StoreClientConfig(clientConfig("user2", "6a1b7e0c93d5")); err != nil {
+		t.Fatalf("StoreClientConfig() failed: %v", err)
+	}
+	if err := ReloadClientConfig(true); err != nil {
+		t.Fatalf("ReloadClientConfig() failed: %v", err)
+	}
+	if err := echoThroughMux(mux); err != nil {
+		t.Fatalf("echoThroughMux() failed: %v", err)
+	}
+}
+
+// startTestProxyServer starts a proxy server with the users, which echoes
+// the data of each connection in rot13. It returns the server multiplexer
+// and the TCP port it listens to.
+func startTestProxyServer(t *testing.T, users []*pb.User) (*protocol.Mux, int) {
+	t.Helper()
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverMux := protocol.NewMux(false).
+		SetServerUsers(UserListToMap(users)).
+		SetEndpoints([]protocol.UnderlayProperties{
+			protocol.NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil),
+		})
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	t.Cleanup(func() { serverMux.Close() })
+	if err := serverMux.WaitListening(context.Background()); err != nil {
+		t.Fatalf("WaitListening() failed: %v", err)
+	}
+	testServer := testtool.NewTestHelperServer()
+	go testServer.Serve(serverMux)
+	t.Cleanup(func() { testServer.Close() })
+	return serverMux, port
+}
+
+// testClientProfile returns a profile that connects to a local proxy server.
+func testClientProfile(profileName, userName, password string, port int) *pb.ClientProfile {
+	return &pb.ClientProfile{
+		ProfileName: proto.String(profileName),
+		User:        &pb.User{Name: proto.String(userName), Password: proto.String(password)},
+		Servers: []*pb.ServerEndpoint{
+			{
+				IpAddress:    proto.String("127.0.0.1"),
+				PortBindings: []*pb.PortBinding{{Port: proto.Int32(int32(port)), Protocol: pb.TransportProtocol_TCP.Enum()}},
+			},
+		},
+	}
+}
+
+// echoThroughMux sends data to the test proxy server and reads the response.
+func echoThroughMux(mux *protocol.Mux) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := mux.DialContext(ctx)
+	if err != nil {
+		return fmt.Errorf("DialContext() failed: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		return fmt.Errorf("Write() failed: %w", err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return fmt.Errorf("io.ReadFull() failed: %w", err)
+	}
+	return nil
+}
+
+func TestServerConnectionStatus(t *testing.T) {
+	sessions := []*pb.SessionInfo{
+		{Protocol: proto.String("TCP"), RemoteAddr: proto.String("1.2.3.4:2027")},
+		{Protocol: proto.String("UDP"), RemoteAddr: proto.String("1.2.3.4:2028")},
+		{Protocol: proto.String("TCP"), RemoteAddr: proto.String("1.2.3.4:2027")},
+	}
+	got := serverConnectionStatus(sessions)
+	if len(got) != 2 {
+		t.Fatalf("got %d servers, want 2", len(got))
+	}
+	if got[0].GetProtocol() != "TCP" || got[0].GetRemoteAddr() != "1.2.3.4:2027" || got[0].GetSessions() != 2 {
+		t.Errorf("got %v, want TCP 1.2.3.4:2027 with 2 sessions", got[0])
+	}
+	if got[1].GetSessions() != 1 {
+		t.Errorf("got %v, want 1 session", got[1])
+	}
+}
+
+func TestClientProfileToUnderlayPropertiesPinnedIP(t *testing.T) {
+	profile := &pb.ClientProfile{
+		Servers: []*pb.ServerEndpoint{
+			{
+				DomainName:        proto.String("mieru.example.com"),
+				PinnedIpAddresses: []string{"192.0.2.1", "2001:db8::1"},
+				PortBindings: []*pb.PortBinding{
+					{Port: proto.Int32(2027), Protocol: pb.TransportProtocol_TCP.Enum()},
+				},
+			},
+		},
+	}
+	endpoints, err := ClientProfileToUnderlayProperties(profile, apicommon.NilDNSResolver{})
+	if err != nil {
+		t.Fatalf("ClientProfileToUnderlayProperties() failed: %v", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("got %d endpoints, want 2", len(endpoints))
+	}
+	want := []string{"192.0.2.1:2027", "[2001:db8::1]:2027"}
+	for i, endpoint := range endpoints {
+		if got := endpoint.RemoteAddr().String(); got != want[i] {
+			t.Errorf("endpoint %d remote address = %s, want %s", i, got, want[i])
+		}
+	}
+
+	profile.Servers[0].PinnedIpAddresses = nil
+	if _, err := ClientProfileToUnderlayProperties(profile, apicommon.NilDNSResolver{}); err == nil {
+		t.Errorf("ClientProfileToUnderlayProperties() succeeded without pinned IP addresses")
+	}
+}
+
+func TestClientProfileToUnderlayPropertiesWebSocket(t *testing.T) {
+	profile := &pb.ClientProfile{
+		Servers: []*pb.ServerEndpoint{
+			{
+				IpAddress: proto.String("192.0.2.1"),
+				PortBindings: []*pb.PortBinding{
+					{Port: proto.Int32(443), Protocol: pb.TransportProtocol_WEBSOCKET.Enum()},
+					{Port: proto.Int32(2027), Protocol: pb.TransportProtocol_UDP.Enum()},
+				},
+				WebSocket: &pb.WebSocketConfig{
+					Path: proto.String("/ws"),
+					Host: proto.String("cdn.example.com"),
+				},
+			},
+		},
+	}
+	endpoints, err := ClientProfileToUnderlayProperties(profile, apicommon.NilDNSResolver{})
+	if err != nil {
+		t.Fatalf("ClientProfileToUnderlayProperties() failed: %v", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("got %d endpoints, want 2", len(endpoints))
+	}
+	if endpoints[0].TransportProtocol() != common.PacketTransport {
+		t.Errorf("endpoint 0 transport protocol = %v, want %v", endpoints[0].TransportProtocol(), common.PacketTransport)
+	}
+	if endpoints[1].TransportProtocol() != common.WebSocketTransport {
+		t.Errorf("endpoint 1 transport protocol = %v, want %v", endpoints[1].TransportProtocol(), common.WebSocketTransport)
+	}
+	if got := endpoints[1].RemoteAddr().String(); got != "192.0.2.1:443" {
+		t.Errorf("endpoint 1 remote address = %s, want 192.0.2.1:443", got)
+	}
+}
+
+func TestKeepaliveRulesFromConfig(t *testing.T) {
+	rules := KeepaliveRulesFromConfig([]*pb.KeepaliveRule{
+		{DestinationPorts: []string{"22", "8000-9000"}, Interval: proto.String("30s")},
+		{DestinationPorts: []string{"abc"}, Interval: proto.String("30s")},
+		{Interval: proto.String("5m")},
+	})
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(rules))
+	}
+	want := [][2]int{{22, 22}, {8000, 9000}}
+	if !reflect.DeepEqual(rules[0].Ports, want) {
+		t.Errorf("rule 0 ports = %v, want %v", rules[0].Ports, want)
+	}
+	if rules[0].Interval != 30*time.Second {
+		t.Errorf("rule 0 interval = %v, want %v", rules[0].Interval, 30*time.Second)
+	}
+	if len(rules[1].Ports) != 0 {
+		t.Errorf("rule 1 ports = %v, want empty", rules[1].Ports)
+	}
+	if rules[1].Interval != 5*time.Minute {
+		t.Errorf("rule 1 interval = %v, want %v", rules[1].Interval, 5*time.Minute)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"google.golang.org/protobuf/proto"
+)
+
+// throughputSampleInterval is the interval to measure the client
+// download and upload speed.
+const throughputSampleInterval = 5 * time.Second
+
+// clientStatusTracker collects the client states that are displayed
+// by the status command.
+type clientStatusTracker struct {
+	mu            sync.Mutex
+	startTime     time.Time
+	lastError     string
+	lastErrorTime time.Time
+	downloadRate  int64 // bytes per second
+	uploadRate    int64 // bytes per second
+}
+
+var clientStatus clientStatusTracker
+
+// StartClientStatusTracking records the client start time, and measures
+// the download and upload speed in the background.
+func StartClientStatusTracking() {
+	clientStatus.mu.Lock()
+	clientStatus.startTime = time.Now()
+	clientStatus.mu.Unlock()
+
+	go func() {
+		download := metrics.DownloadBytes.Load()
+		upload := metrics.UploadBytes.Load()
+		ticker := time.NewTicker(throughputSampleInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			newDownload := metrics.DownloadBytes.Load()
+			newUpload := metrics.UploadBytes.Load()
+			clientStatus.mu.Lock()
+			clientStatus.downloadRate = (newDownload - download) / int64(throughputSampleInterval/time.Second)
+			clientStatus.uploadRate = (newUpload - upload) / int64(throughputSampleInterval/time.Second)
+			clientStatus.mu.Unlock()
+			download = newDownload
+			upload = newUpload
+		}
+	}()
+}
+
+// ReportClientTunnelError records the last error to establish
+// a proxy tunnel. Nil error is ignored.
+func ReportClientTunnelError(err error) {
+	if err == nil {
+		return
+	}
+	clientStatus.mu.Lock()
+	defer clientStatus.mu.Unlock()
+	clientStatus.lastError = err.Error()
+	clientStatus.lastErrorTime = time.Now()
+}
+
+// fillClientStatus adds the client states to the status message.
+func fillClientStatus(msg *pb.AppStatusMsg) {
+	clientStatus.mu.Lock()
+	if !clientStatus.startTime.IsZero() {
+		msg.StartTimeUnix = proto.Int64(clientStatus.startTime.Unix())
+	}
+	if clientStatus.lastError != "" {
+		msg.LastError = proto.String(clientStatus.lastError)
+		msg.LastErrorTimeUnix = proto.Int64(clientStatus.lastErrorTime.Unix())
+	}
+	msg.DownloadBytesPerSecond = proto.Int64(clientStatus.downloadRate)
+	msg.UploadBytesPerSecond = proto.Int64(clientStatus.uploadRate)
+	clientStatus.mu.Unlock()
+
+	if failover := clientFailoverRef.Load(); failover != nil {
+		msg.Profile = proto.String(failover.CurrentProfile())
+	}
+	if mux := clientMuxRef.Load(); mux != nil {
+		msg.Servers = serverConnectionStatus(mux.ExportSessionInfoList().GetItems())
+	}
+}
+
+// serverConnectionStatus counts the sessions to each proxy server address.
+func serverConnectionStatus(sessions []*pb.SessionInfo) []*pb.ServerConnectionStatus {
+	byAddr := make(map[string]*pb.ServerConnectionStatus)
+	for _, session := range sessions {
+		key := session.GetProtocol() + " " + session.GetRemoteAddr()
+		status, found := byAddr[key]
+		if !found {
+			status = &pb.ServerConnectionStatus{
+				Protocol:   proto.String(session.GetProtocol()),
+				RemoteAddr: proto.String(session.GetRemoteAddr()),
+				Sessions:   proto.Int32(0),
+			}
+			byAddr[key] = status
+		}
+		status.Sessions = proto.Int32(status.GetSessions() + 1)
+	}
+	res := make([]*pb.ServerConnectionStatus, 0, len(byAddr))
+	for _, status := range byAddr {
+		res = append(res, status)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].GetSessions() != res[j].GetSessions() {
+			return res[i].GetSessions() > res[j].GetSessions()
+		}
+		return res[i].GetRemoteAddr() < res[j].GetRemoteAddr()
+	})
+	return res
+}
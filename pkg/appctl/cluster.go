@@ -0,0 +1,419 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// clusterSyncRoute is the route of the cluster sync API
+	// in the management gateway of the primary server.
+	clusterSyncRoute = "cluster/sync"
+
+	// defaultClusterSyncInterval is the default interval to synchronize
+	// with the primary server.
+	defaultClusterSyncInterval = time.Minute
+
+	// minClusterSyncInterval is the minimum interval to synchronize
+	// with the primary server.
+	minClusterSyncInterval = 10 * time.Second
+
+	// clusterSyncTimeout is the timeout of a single synchronization.
+	clusterSyncTimeout = 30 * time.Second
+
+	// clusterMaxMessageSize is the maximum size of a cluster sync
+	// request or response. It is larger than other requests of the
+	// management gateway, because it carries the traffic history.
+	clusterMaxMessageSize = 16 * 1024 * 1024
+
+	// clusterPrimarySource is the source name of the traffic received
+	// from the primary server.
+	clusterPrimarySource = "primary"
+)
+
+var (
+	// ClusterSyncSuccess is the number of successful synchronizations
+	// of a replica server with the primary server.
+	ClusterSyncSuccess = metrics.RegisterMetric("cluster", "SyncSuccess", metrics.COUNTER)
+
+	// ClusterSyncFailed is the number of failed synchronizations
+	// of a replica server with the primary server.
+	ClusterSyncFailed = metrics.RegisterMetric("cluster", "SyncFailed", metrics.COUNTER)
+
+	// ClusterSyncRequests is the number of synchronization requests
+	// served by the primary server.
+	ClusterSyncRequests = metrics.RegisterMetric("cluster", "SyncRequests", metrics.COUNTER)
+
+	// serverClusterSyncRef holds a pointer to the cluster synchronization
+	// of a replica server.
+	serverClusterSyncRef atomic.Pointer[serverClusterSync]
+
+	// errClusterUsersUnchanged is returned when the users from the
+	// primary server are the same as the server config.
+	errClusterUsersUnchanged = errors.New("cluster users are unchanged")
+)
+
+// validateCluster validates the cluster config of a replica server.
+func validateCluster(config *pb.ClusterConfig) error {
+	if config.GetPrimaryURL() == "" {
+		if config.GetToken() != "" || config.GetNodeName() != "" || config.GetSyncInterval() != "" || config.GetCaFile() != "" {
+			return fmt.Errorf("cluster primary URL is not set")
+		}
+		return nil
+	}
+	u, err := url.Parse(config.GetPrimaryURL())
+	if err != nil {
+		return fmt.Errorf("cluster primary URL %q is invalid: %w", config.GetPrimaryURL(), err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("cluster primary URL %q is not an HTTP or HTTPS URL", config.GetPrimaryURL())
+	}
+	if u.Scheme == "http" && !isLoopbackHost(u.Hostname()) {
+		return fmt.Errorf("cluster primary URL %q must use HTTPS, unless the host is a loopback address", config.GetPrimaryURL())
+	}
+	if len(config.GetToken()) < gatewayMinTokenLength {
+		return fmt.Errorf("cluster token must have at least %d characters", gatewayMinTokenLength)
+	}
+	if _, err := clusterSyncInterval(config); err != nil {
+		return err
+	}
+	if config.GetCaFile() != "" && !filepath.IsAbs(config.GetCaFile()) {
+		return fmt.Errorf("cluster CA file %q is not an absolute path", config.GetCaFile())
+	}
+	return nil
+}
+
+// isLoopbackHost returns true if the host name or IP address is loopback.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// clusterSyncInterval returns the interval to synchronize with the primary server.
+func clusterSyncInterval(config *pb.ClusterConfig) (time.Duration, error) {
+	if config.GetSyncInterval() == "" {
+		return defaultClusterSyncInterval, nil
+	}
+	d, err := time.ParseDuration(config.GetSyncInterval())
+	if err != nil {
+		return 0, fmt.Errorf("cluster sync interval %q is invalid: %w", config.GetSyncInterval(), err)
+	}
+	if d < minClusterSyncInterval {
+		return 0, fmt.Errorf("cluster sync interval %v is less than %v", d, minClusterSyncInterval)
+	}
+	return d, nil
+}
+
+// clusterTrafficSince returns the start time of the traffic that must be
+// shared with the cluster, which is the longest quota of users and user
+// groups. It returns false if there is no quota.
+func clusterTrafficSince(config *pb.ServerConfig, now time.Time) (time.Time, bool) {
+	var days int32
+	for _, user := range config.GetUsers() {
+		for _, quota := range user.GetQuotas() {
+			if quota.GetDays() > days {
+				days = quota.GetDays()
+			}
+		}
+	}
+	for _, group := range config.GetUserGroups() {
+		for _, quota := range group.GetQuotas() {
+			if quota.GetDays() > days {
+				days = quota.GetDays()
+			}
+		}
+	}
+	if days == 0 {
+		return time.Time{}, false
+	}
+	// Traffic is rolled up to days, so keep one more day.
+	return now.Add(-time.Duration(days+1) * 24 * time.Hour), true
+}
+
+// clusterSyncResponse returns the users, user groups, and the traffic of
+// the cluster except the given replica server.
+func clusterSyncResponse(config *pb.ServerConfig, nodeName string) *pb.ClusterSyncResponse {
+	resp := &pb.ClusterSyncResponse{
+		UserGroups: config.GetUserGroups(),
+	}
+	for _, user := range config.GetUsers() {
+		u := proto.Clone(user).(*pb.User)
+		u.Password = nil
+		resp.Users = append(resp.Users, u)
+	}
+	if since, ok := clusterTrafficSince(config, time.Now()); ok {
+		resp.Traffic = metrics.MergeTraffic(metrics.LocalUserTraffic(since), metrics.RemoteTraffic(nodeName))
+	}
+	return resp
+}
+
+// clusterSync serves the cluster sync API of the primary server.
+// It records the traffic of the replica server, and returns the users,
+// user groups, and the traffic of the other servers.
+func (g *managementGateway) clusterSync(w http.ResponseWriter, r *http.Request) {
+	b, err := io.ReadAll(io.LimitReader(r.Body, clusterMaxMessageSize))
+	if err != nil {
+		writeGatewayError(w, status.Errorf(codes.InvalidArgument, "read request body failed: %v", err))
+		return
+	}
+	req := &pb.ClusterSyncRequest{}
+	if err := common.UnmarshalJSON(b, req); err != nil {
+		writeGatewayError(w, status.Errorf(codes.InvalidArgument, "invalid cluster sync request: %v", err))
+		return
+	}
+	if req.GetNodeName() == "" || req.GetNodeName() == clusterPrimarySource {
+		writeGatewayError(w, status.Errorf(codes.InvalidArgument, "cluster node name %q is invalid", req.GetNodeName()))
+		return
+	}
+	config, err := LoadServerConfig()
+	if err != nil {
+		writeGatewayError(w, status.Errorf(codes.Internal, "LoadServerConfig() failed: %v", err))
+		return
+	}
+	ClusterSyncRequests.Add(1)
+	metrics.SetRemoteTraffic(req.GetNodeName(), req.GetTraffic())
+	writeGatewayResponse(w, clusterSyncResponse(config, req.GetNodeName()), nil)
+}
+
+// serverClusterSync synchronizes a replica server with the primary server
+// of the cluster. It sends the traffic of this server to the primary server,
+// and replaces the users and user groups with the primary server.
+type serverClusterSync struct {
+	syncURL  string
+	token    string
+	nodeName string
+	interval time.Duration
+	client   *http.Client
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newServerClusterSync creates the synchronization of a replica server.
+// It returns nil if the primary server is not set.
+func newServerClusterSync(config *pb.ClusterConfig) (*serverClusterSync, error) {
+	if config.GetPrimaryURL() == "" {
+		return nil, nil
+	}
+	interval, err := clusterSyncInterval(config)
+	if err != nil {
+		return nil, err
+	}
+	nodeName := config.GetNodeName()
+	if nodeName == "" {
+		if nodeName, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("os.Hostname() failed: %w", err)
+		}
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.GetCaFile() != "" {
+		b, err := os.ReadFile(config.GetCaFile())
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadFile() failed: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("cluster CA file %q has no valid certificate", config.GetCaFile())
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return &serverClusterSync{
+		syncURL:  strings.TrimSuffix(config.GetPrimaryURL(), "/") + gatewayPathPrefix + clusterSyncRoute,
+		token:    config.GetToken(),
+		nodeName: nodeName,
+		interval: interval,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+			// Don't follow redirects, which may send the token to another URL.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+			Timeout: clusterSyncTimeout,
+		},
+		done: make(chan struct{}),
+	}, nil
+}
+
+// setServerClusterSync stops the previous synchronization, and starts
+// the new one in the background if it is not nil.
+func setServerClusterSync(s *serverClusterSync) {
+	if old := serverClusterSyncRef.Swap(s); old != nil {
+		old.Close()
+	}
+	if s != nil {
+		s.Start()
+	}
+}
+
+// stopServerClusterSync stops the synchronization if it is running.
+func stopServerClusterSync() {
+	setServerClusterSync(nil)
+}
+
+// Start synchronizes with the primary server in the background
+// until Close is called.
+func (s *serverClusterSync) Start() {
+	log.Infof("synchronizing with cluster primary server %s as node %q every %v", s.syncURL, s.nodeName, s.interval)
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			if err := s.sync(context.Background()); err != nil {
+				ClusterSyncFailed.Add(1)
+				log.Warnf("synchronize with cluster primary server failed: %v", err)
+			} else {
+				ClusterSyncSuccess.Add(1)
+			}
+			select {
+			case <-ticker.C:
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the synchronization.
+func (s *serverClusterSync) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// sync sends the traffic of this server to the primary server, and applies
+// the users, user groups and traffic of the cluster returned by the primary server.
+func (s *serverClusterSync) sync(ctx context.Context) error {
+	config, err := LoadServerConfig()
+	if err != nil {
+		return fmt.Errorf("LoadServerConfig() failed: %w", err)
+	}
+	req := &pb.ClusterSyncRequest{NodeName: proto.String(s.nodeName)}
+	if since, ok := clusterTrafficSince(config, time.Now()); ok {
+		req.Traffic = metrics.LocalUserTraffic(since)
+	}
+	b, err := common.MarshalJSON(req)
+	if err != nil {
+		return fmt.Errorf("common.MarshalJSON() failed: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.syncURL, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext() failed: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+s.token)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := s.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("send request to %q failed: %w", s.syncURL, err)
+	}
+	defer httpResp.Body.Close()
+	b, err = io.ReadAll(io.LimitReader(httpResp.Body, clusterMaxMessageSize))
+	if err != nil {
+		return fmt.Errorf("read response from %q failed: %w", s.syncURL, err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%q returned HTTP status %q: %s", s.syncURL, httpResp.Status, strings.TrimSpace(string(b)))
+	}
+	resp := &pb.ClusterSyncResponse{}
+	if err := common.UnmarshalJSON(b, resp); err != nil {
+		return fmt.Errorf("invalid cluster sync response: %w", err)
+	}
+	metrics.SetRemoteTraffic(clusterPrimarySource, resp.GetTraffic())
+	return applyClusterUsers(resp.GetUsers(), resp.GetUserGroups())
+}
+
+// applyClusterUsers replaces the users and user groups in the server config,
+// and applies them to the running proxy. The server config is not written
+// if they are unchanged.
+func applyClusterUsers(users []*pb.User, groups []*pb.UserGroup) error {
+	config, err := updateServerConfig(func(config *pb.ServerConfig) error {
+		if clusterUsersEqual(config.GetUsers(), users) && clusterUserGroupsEqual(config.GetUserGroups(), groups) {
+			return errClusterUsersUnchanged
+		}
+		config.Users = users
+		config.UserGroups = groups
+		return nil
+	})
+	if errors.Is(err, errClusterUsersUnchanged) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	applyServerUsers(config)
+	log.Infof("%d users and %d user groups are synchronized from cluster primary server", len(users), len(groups))
+	return nil
+}
+
+// clusterUsersEqual returns true if the users are the same,
+// ignoring the raw passwords.
+func clusterUsersEqual(a, b []*pb.User) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x := proto.Clone(a[i]).(*pb.User)
+		y := proto.Clone(b[i]).(*pb.User)
+		x.Password = nil
+		y.Password = nil
+		if !proto.Equal(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// clusterUserGroupsEqual returns true if the user groups are the same.
+func clusterUserGroupsEqual(a, b []*pb.UserGroup) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
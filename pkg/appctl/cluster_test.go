@@ -0,0 +1,233 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	metricspb "github.com/enfein/mieru/v3/pkg/metrics/metricspb"
+	"google.golang.org/protobuf/proto"
+)
+
+const clusterTestToken = "0123456789abcdef"
+
+func clusterTestTraffic(userName string, delta int64) []*metricspb.MetricGroup {
+	return []*metricspb.MetricGroup{
+		{
+			Name: proto.String(fmt.Sprintf(metrics.UserMetricGroupFormat, userName)),
+			Metrics: []*metricspb.Metric{
+				{
+					Name:  proto.String(metrics.UserMetricDownloadBytes),
+					Type:  metricspb.MetricType_COUNTER_TIME_SERIES.Enum(),
+					Value: proto.Int64(delta),
+					History: []*metricspb.History{
+						{TimeUnixMilli: proto.Int64(time.Now().Add(-time.Minute).UnixMilli()), Delta: proto.Int64(delta)},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateCluster(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  *pb.ClusterConfig
+		wantErr bool
+	}{
+		{"not set", nil, false},
+		{"replica", &pb.ClusterConfig{PrimaryURL: proto.String("https://primary.example.com:8080"), Token: proto.String(clusterTestToken)}, false},
+		{"sync interval", &pb.ClusterConfig{PrimaryURL: proto.String("https://primary.example.com"), Token: proto.String(clusterTestToken), SyncInterval: proto.String("30s")}, false},
+		{"HTTP loopback IP", &pb.ClusterConfig{PrimaryURL: proto.String("http://127.0.0.1:8080"), Token: proto.String(clusterTestToken)}, false},
+		{"HTTP localhost", &pb.ClusterConfig{PrimaryURL: proto.String("http://localhost:8080"), Token: proto.String(clusterTestToken)}, false},
+		{"HTTP public host", &pb.ClusterConfig{PrimaryURL: proto.String("http://primary.example.com:8080"), Token: proto.String(clusterTestToken)}, true},
+		{"token without primary URL", &pb.ClusterConfig{Token: proto.String(clusterTestToken)}, true},
+		{"invalid scheme", &pb.ClusterConfig{PrimaryURL: proto.String("ftp://primary.example.com"), Token: proto.String(clusterTestToken)}, true},
+		{"short token", &pb.ClusterConfig{PrimaryURL: proto.String("https://primary.example.com"), Token: proto.String("short")}, true},
+		{"short sync interval", &pb.ClusterConfig{PrimaryURL: proto.String("https://primary.example.com"), Token: proto.String(clusterTestToken), SyncInterval: proto.String("1s")}, true},
+		{"relative CA file", &pb.ClusterConfig{PrimaryURL: proto.String("https://primary.example.com"), Token: proto.String(clusterTestToken), CaFile: proto.String("ca.pem")}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCluster(tc.config)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("validateCluster() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestClusterSyncPrimary(t *testing.T) {
+	beforeServerTest(t)
+	defer afterServerTest(t)
+	if err := StoreServerConfig(&pb.ServerConfig{
+		PortBindings: []*pb.PortBinding{{Port: proto.Int32(8000), Protocol: pb.TransportProtocol_TCP.Enum()}},
+		Users: []*pb.User{
+			{
+				Name:     proto.String("alice"),
+				Password: proto.String("c2c8f2b0a5e1"),
+				Quotas:   []*pb.Quota{{Days: proto.Int32(30), Megabytes: proto.Int32(1024)}},
+			},
+		},
+	}); err != nil {
+		t.Fatalf("StoreServerConfig() failed: %v", err)
+	}
+	defer metrics.SetRemoteTraffic("replica-1", nil)
+
+	const adminToken = "fedcba9876543210"
+	g := newManagementGateway(NewServerManagementService(), &pb.ManagementGateway{
+		Tokens:        []string{adminToken},
+		ClusterTokens: []string{clusterTestToken},
+	})
+	b, err := common.MarshalJSON(&pb.ClusterSyncRequest{
+		NodeName: proto.String("replica-1"),
+		Traffic:  clusterTestTraffic("alice", 4096),
+	})
+	if err != nil {
+		t.Fatalf("common.MarshalJSON() failed: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/cluster/sync", strings.NewReader(string(b)))
+	req.Header.Set("Authorization", "Bearer "+clusterTestToken)
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	resp := &pb.ClusterSyncResponse{}
+	if err := common.UnmarshalJSON(rec.Body.Bytes(), resp); err != nil {
+		t.Fatalf("common.UnmarshalJSON() failed: %v", err)
+	}
+	if len(resp.GetUsers()) != 1 || resp.GetUsers()[0].GetName() != "alice" {
+		t.Fatalf("got users %v, want alice", resp.GetUsers())
+	}
+	if resp.GetUsers()[0].GetPassword() != "" || resp.GetUsers()[0].GetHashedPassword() == "" {
+		t.Errorf("cluster sync response must only include hashed password")
+	}
+
+	now := time.Now()
+	groupName := fmt.Sprintf(metrics.UserMetricGroupFormat, "alice")
+	if got := metrics.RemoteDeltaBetween(groupName, metrics.UserMetricDownloadBytes, now.Add(-2*time.Hour), now); got != 4096 {
+		t.Errorf("traffic of replica is %d, want 4096", got)
+	}
+
+	// The node name is required.
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/cluster/sync", strings.NewReader(`{}`))
+	req.Header.Set("Authorization", "Bearer "+clusterTestToken)
+	rec = httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got status %d without node name, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	// The cluster token can only access the cluster sync API,
+	// and the other tokens can't access it.
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+	req.Header.Set("Authorization", "Bearer "+clusterTestToken)
+	rec = httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d with cluster token, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/cluster/sync", strings.NewReader(string(b)))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rec = httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d with management token, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestClusterSyncReplica(t *testing.T) {
+	beforeServerTest(t)
+	defer afterServerTest(t)
+	if err := StoreServerConfig(&pb.ServerConfig{
+		PortBindings: []*pb.PortBinding{{Port: proto.Int32(8000), Protocol: pb.TransportProtocol_TCP.Enum()}},
+		Users:        []*pb.User{{Name: proto.String("alice"), Password: proto.String("c2c8f2b0a5e1")}},
+	}); err != nil {
+		t.Fatalf("StoreServerConfig() failed: %v", err)
+	}
+	defer metrics.SetRemoteTraffic(clusterPrimarySource, nil)
+
+	bob := HashUserPassword(&pb.User{
+		Name:     proto.String("bob"),
+		Password: proto.String("e5a1c2b0f8c2"),
+		Quotas:   []*pb.Quota{{Days: proto.Int32(30), Megabytes: proto.Int32(1024)}},
+	}, false)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/cluster/sync" || r.Header.Get("Authorization") != "Bearer "+clusterTestToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		req := &pb.ClusterSyncRequest{}
+		if err := common.UnmarshalJSON(b, req); err != nil || req.GetNodeName() != "replica-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		b, _ = common.MarshalJSON(&pb.ClusterSyncResponse{
+			Users:   []*pb.User{bob},
+			Traffic: clusterTestTraffic("bob", 8192),
+		})
+		w.Write(b)
+	}))
+	defer primary.Close()
+
+	s, err := newServerClusterSync(&pb.ClusterConfig{
+		PrimaryURL: proto.String(primary.URL),
+		Token:      proto.String(clusterTestToken),
+		NodeName:   proto.String("replica-1"),
+	})
+	if err != nil {
+		t.Fatalf("newServerClusterSync() failed: %v", err)
+	}
+	if err := s.sync(context.Background()); err != nil {
+		t.Fatalf("sync() failed: %v", err)
+	}
+	config, err := LoadServerConfig()
+	if err != nil {
+		t.Fatalf("LoadServerConfig() failed: %v", err)
+	}
+	if len(config.GetUsers()) != 1 || config.GetUsers()[0].GetName() != "bob" {
+		t.Errorf("got users %v, want bob", config.GetUsers())
+	}
+	now := time.Now()
+	groupName := fmt.Sprintf(metrics.UserMetricGroupFormat, "bob")
+	if got := metrics.RemoteDeltaBetween(groupName, metrics.UserMetricDownloadBytes, now.Add(-2*time.Hour), now); got != 8192 {
+		t.Errorf("traffic of cluster is %d, want 8192", got)
+	}
+
+	// Synchronize again without changes.
+	if err := s.sync(context.Background()); err != nil {
+		t.Fatalf("sync() failed: %v", err)
+	}
+
+	// Wrong token.
+	s.token = "fedcba9876543210"
+	if err := s.sync(context.Background()); err == nil {
+		t.Errorf("sync() with wrong token succeeded")
+	}
+}
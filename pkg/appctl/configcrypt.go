@@ -0,0 +1,174 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/keychain"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/pbkdf2"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// EnvMieruConfigPassphrase is the environment variable of the
+	// passphrase to encrypt the client config.
+	EnvMieruConfigPassphrase = "MIERU_CONFIG_PASSPHRASE"
+
+	// encryptedSecretPrefix marks an encrypted secret in the stored
+	// client config. It is followed by the base64 encoded nonce and
+	// ciphertext.
+	encryptedSecretPrefix = "encrypted:"
+
+	configKeychainService = "mieru"
+	configKeychainAccount = "client-config-key"
+
+	configKeySize            = chacha20poly1305.KeySize
+	configPassphraseIter     = 200000
+	configPassphraseSaltSize = 16
+)
+
+// resolveConfigEncryption chooses the key store if it is the default,
+// and generates the passphrase salt if it is missing.
+func resolveConfigEncryption(enc *pb.ConfigEncryption) error {
+	if enc.GetKeyStore() == pb.ConfigKeyStore_CONFIG_KEY_STORE_DEFAULT {
+		if keychain.Available() {
+			enc.KeyStore = pb.ConfigKeyStore_CONFIG_KEY_STORE_OS_KEYCHAIN.Enum()
+		} else {
+			enc.KeyStore = pb.ConfigKeyStore_CONFIG_KEY_STORE_PASSPHRASE.Enum()
+		}
+	}
+	if enc.GetKeyStore() == pb.ConfigKeyStore_CONFIG_KEY_STORE_PASSPHRASE && enc.GetPassphraseSalt() == "" {
+		salt := make([]byte, configPassphraseSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("rand.Read() failed: %w", err)
+		}
+		enc.PassphraseSalt = proto.String(hex.EncodeToString(salt))
+	}
+	return nil
+}
+
+// clientConfigKey returns the key to encrypt the client config.
+// If create is true, a missing key in the OS keychain is created.
+func clientConfigKey(enc *pb.ConfigEncryption, create bool) ([]byte, error) {
+	switch enc.GetKeyStore() {
+	case pb.ConfigKeyStore_CONFIG_KEY_STORE_OS_KEYCHAIN:
+		key, err := keychain.Get(configKeychainService, configKeychainAccount)
+		if errors.Is(err, keychain.ErrNotFound) && create {
+			key = make([]byte, configKeySize)
+			if _, err := rand.Read(key); err != nil {
+				return nil, fmt.Errorf("rand.Read() failed: %w", err)
+			}
+			if err := keychain.Set(configKeychainService, configKeychainAccount, key); err != nil {
+				return nil, fmt.Errorf("failed to store client config key in OS keychain: %w", err)
+			}
+			return key, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client config key from OS keychain: %w", err)
+		}
+		if len(key) != configKeySize {
+			return nil, fmt.Errorf("client config key in OS keychain has %d bytes, want %d bytes", len(key), configKeySize)
+		}
+		return key, nil
+	case pb.ConfigKeyStore_CONFIG_KEY_STORE_PASSPHRASE:
+		passphrase := os.Getenv(EnvMieruConfigPassphrase)
+		if passphrase == "" {
+			return nil, fmt.Errorf("client config is encrypted with a passphrase, but environment variable %s is not set", EnvMieruConfigPassphrase)
+		}
+		salt, err := hex.DecodeString(enc.GetPassphraseSalt())
+		if err != nil || len(salt) == 0 {
+			return nil, fmt.Errorf("passphrase salt %q is invalid", enc.GetPassphraseSalt())
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, configPassphraseIter, configKeySize, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("client config key store %s is not supported", enc.GetKeyStore().String())
+	}
+}
+
+// encryptClientConfigSecrets returns a copy of the client config where
+// the secrets are encrypted. If encryption is not enabled, the config
+// is returned as is.
+func encryptClientConfigSecrets(config *pb.ClientConfig) (*pb.ClientConfig, error) {
+	if !config.GetConfigEncryption().GetEnabled() {
+		return config, nil
+	}
+	if err := resolveConfigEncryption(config.GetConfigEncryption()); err != nil {
+		return nil, err
+	}
+	key, err := clientConfigKey(config.GetConfigEncryption(), true)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf("chacha20poly1305.NewX() failed: %w", err)
+	}
+	encrypted := proto.Clone(config).(*pb.ClientConfig)
+	err = forEachClientConfigSecret(encrypted, func(secret *string) error {
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("rand.Read() failed: %w", err)
+		}
+		sealed := aead.Seal(nonce, nonce, []byte(*secret), nil)
+		*secret = encryptedSecretPrefix + base64.StdEncoding.EncodeToString(sealed)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return encrypted, nil
+}
+
+// decryptClientConfigSecrets decrypts the secrets in the client config.
+func decryptClientConfigSecrets(config *pb.ClientConfig) error {
+	if !config.GetConfigEncryption().GetEnabled() {
+		return nil
+	}
+	key, err := clientConfigKey(config.GetConfigEncryption(), false)
+	if err != nil {
+		return err
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return fmt.Errorf("chacha20poly1305.NewX() failed: %w", err)
+	}
+	return forEachClientConfigSecret(config, func(secret *string) error {
+		if !strings.HasPrefix(*secret, encryptedSecretPrefix) {
+			// Written before encryption is enabled.
+			return nil
+		}
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(*secret, encryptedSecretPrefix))
+		if err != nil || len(sealed) < aead.NonceSize() {
+			return fmt.Errorf("encrypted secret in client config is invalid")
+		}
+		plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt client config, the key or the passphrase is wrong")
+		}
+		*secret = string(plaintext)
+		return nil
+	})
+}
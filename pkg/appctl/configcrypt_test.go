@@ -0,0 +1,153 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestClientConfigEncryptionWithPassphrase(t *testing.T) {
+	beforeClientTest(t)
+	t.Setenv(EnvMieruConfigPassphrase, "correct horse battery staple")
+
+	configFile := "testdata/client_apply_config_1.json"
+	if err := ApplyJSONClientConfig(configFile); err != nil {
+		t.Fatalf("ApplyJSONClientConfig(%q) failed: %v", configFile, err)
+	}
+	want, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+	patch := &pb.ClientConfig{
+		ConfigEncryption: &pb.ConfigEncryption{
+			Enabled:  proto.Bool(true),
+			KeyStore: pb.ConfigKeyStore_CONFIG_KEY_STORE_PASSPHRASE.Enum(),
+		},
+	}
+	if err := applyClientConfig(patch); err != nil {
+		t.Fatalf("applyClientConfig() failed: %v", err)
+	}
+
+	// The passwords are not stored in plaintext.
+	path, _, err := clientConfigFilePath()
+	if err != nil {
+		t.Fatalf("clientConfigFilePath() failed: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	password := want.GetProfiles()[0].GetUser().GetPassword()
+	if bytes.Contains(b, []byte(password)) || bytes.Contains(b, []byte(want.GetProfiles()[0].GetUser().GetHashedPassword())) {
+		t.Errorf("stored client config contains the password")
+	}
+
+	got, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+	if got.GetConfigEncryption().GetPassphraseSalt() == "" {
+		t.Errorf("passphrase salt is not generated")
+	}
+	got.ConfigEncryption = nil
+	if !proto.Equal(got, want) {
+		t.Errorf("decrypted client config doesn't equal:\ngot = %v\nwant = %v", got, want)
+	}
+
+	// Store again with the same salt.
+	if err := applyClientConfig(&pb.ClientConfig{LoggingLevel: pb.LoggingLevel_INFO.Enum()}); err != nil {
+		t.Fatalf("applyClientConfig() failed: %v", err)
+	}
+	if _, err := LoadClientConfig(); err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+
+	t.Setenv(EnvMieruConfigPassphrase, "wrong passphrase")
+	if _, err := LoadClientConfig(); err == nil {
+		t.Errorf("LoadClientConfig() didn't fail with wrong passphrase")
+	}
+	t.Setenv(EnvMieruConfigPassphrase, "")
+	if _, err := LoadClientConfig(); err == nil {
+		t.Errorf("LoadClientConfig() didn't fail without passphrase")
+	}
+
+	afterClientTest(t)
+}
+
+func TestClientConfigEncryptionCoversAllSecrets(t *testing.T) {
+	t.Setenv(EnvMieruConfigPassphrase, "correct horse battery staple")
+	secrets := []string{"userpassword", "proxypassword", "socks5password", "https://example.com/sub?token=subscriptiontoken"}
+	config := &pb.ClientConfig{
+		Profiles: []*pb.ClientProfile{
+			{
+				ProfileName: proto.String("default"),
+				User: &pb.User{
+					Name:     proto.String("user1"),
+					Password: proto.String(secrets[0]),
+				},
+				UpstreamProxy: &pb.UpstreamProxy{
+					Protocol: pb.UpstreamProxyProtocol_UPSTREAM_SOCKS5.Enum(),
+					Address:  proto.String("10.0.0.1:1080"),
+					Auth: &pb.Auth{
+						User:     proto.String("proxyuser"),
+						Password: proto.String(secrets[1]),
+					},
+				},
+			},
+		},
+		Socks5Authentication: []*pb.Auth{
+			{
+				User:     proto.String("socks5user"),
+				Password: proto.String(secrets[2]),
+			},
+		},
+		Subscriptions: []*pb.Subscription{
+			{
+				Name: proto.String("sub"),
+				Url:  proto.String(secrets[3]),
+			},
+		},
+		ConfigEncryption: &pb.ConfigEncryption{
+			Enabled:  proto.Bool(true),
+			KeyStore: pb.ConfigKeyStore_CONFIG_KEY_STORE_PASSPHRASE.Enum(),
+		},
+	}
+
+	encrypted, err := encryptClientConfigSecrets(config)
+	if err != nil {
+		t.Fatalf("encryptClientConfigSecrets() failed: %v", err)
+	}
+	redacted := RedactClientConfig(config)
+	for _, c := range []*pb.ClientConfig{encrypted, redacted} {
+		text := []byte(c.String())
+		for _, secret := range secrets {
+			if bytes.Contains(text, []byte(secret)) {
+				t.Errorf("%q is not protected", secret)
+			}
+		}
+	}
+	if err := decryptClientConfigSecrets(encrypted); err != nil {
+		t.Fatalf("decryptClientConfigSecrets() failed: %v", err)
+	}
+	if !proto.Equal(encrypted, config) {
+		t.Errorf("decrypted client config doesn't equal:\ngot = %v\nwant = %v", encrypted, config)
+	}
+}
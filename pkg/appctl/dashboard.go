@@ -0,0 +1,68 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	_ "embed"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/version/updater"
+	"github.com/enfein/mieru/v3/pkg/version/updater/updaterpb"
+)
+
+// dashboardUpdateCheckInterval is the minimum interval to query
+// the latest release for the dashboard.
+const dashboardUpdateCheckInterval = time.Hour
+
+// dashboardHTML is a single page web UI that calls the HTTP JSON API
+// of the management gateway.
+//
+//go:embed dashboard/index.html
+var dashboardHTML []byte
+
+// serveDashboard writes the web dashboard. The page doesn't contain
+// any data, so it is served without a token.
+func serveDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeMethodNotAllowed(w, http.MethodGet)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'; frame-ancestors 'none'")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(dashboardHTML)
+}
+
+// updateChecker caches the result of checking the latest release,
+// so refreshing the dashboard doesn't query GitHub each time.
+type updateChecker struct {
+	mu     sync.Mutex
+	record *updaterpb.UpdateRecord
+	last   time.Time
+}
+
+func (c *updateChecker) check() *updaterpb.UpdateRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.record != nil && time.Since(c.last) < dashboardUpdateCheckInterval {
+		return c.record
+	}
+	c.record, _, _ = updater.CheckUpdate(updater.CheckUpdateOptions{App: "mita"})
+	c.last = time.Now()
+	return c.record
+}
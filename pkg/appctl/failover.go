@@ -0,0 +1,386 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+const (
+	defaultFailoverMaxFailures         = 3
+	defaultFailoverHealthCheckInterval = time.Minute
+
+	// failoverGracePeriod is the duration after a switch when tunnel
+	// failures are ignored. Those failures are likely from the requests
+	// sent to the previous profile.
+	failoverGracePeriod = 10 * time.Second
+
+	// failoverProbeTimeout is the maximum duration of a health check.
+	failoverProbeTimeout = 10 * time.Second
+)
+
+var (
+	FailoverSwitches            = metrics.RegisterMetric("profile failover", "Switches", metrics.COUNTER)
+	FailoverTunnelFailures      = metrics.RegisterMetric("profile failover", "TunnelFailures", metrics.COUNTER)
+	FailoverHealthCheckFailures = metrics.RegisterMetric("profile failover", "HealthCheckFailures", metrics.COUNTER)
+
+	// FailoverCurrentPriority is 0 when the active profile is used,
+	// 1 when the first backup profile is used, and so on.
+	FailoverCurrentPriority = metrics.RegisterMetric("profile failover", "CurrentPriority", metrics.GAUGE)
+)
+
+// ClientFailover switches the client between the active profile and the
+// backup profiles.
+//
+// When the proxy tunnel of the current profile fails repeatedly, the
+// profile with the next priority is used. While a backup profile is used,
+// the profiles with higher priority are checked periodically, and the
+// client switches back to the first healthy one.
+type ClientFailover struct {
+	mu                  sync.Mutex
+	profiles            []*pb.ClientProfile // from the highest priority to the lowest
+	current             int
+	failures            int
+	maxFailures         int
+	healthCheckInterval time.Duration
+	switchTime          time.Time
+	switching           bool // a profile is being resolved to switch to
+
+	// resolve returns the settings of the profile. It may resolve the
+	// proxy server addresses, so it is called without holding mu lock.
+	resolve func(*pb.ClientProfile) (*clientMuxSettings, error)
+
+	// apply makes the client use the settings of a profile.
+	apply func(*clientMuxSettings)
+
+	// probe returns nil if the proxy servers of the profile are healthy.
+	probe func(context.Context, *pb.ClientProfile) error
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewClientFailover creates a ClientFailover that applies the profiles
+// to the client multiplexer. The client must already use the active profile.
+func NewClientFailover(config *pb.ClientConfig, mux *protocol.Mux) (*ClientFailover, error) {
+	f := &ClientFailover{
+		resolve: func(profile *pb.ClientProfile) (*clientMuxSettings, error) {
+			return newClientMuxSettings(profile, &net.Resolver{})
+		},
+		apply: func(settings *clientMuxSettings) {
+			settings.applyTo(mux)
+			// Underlays of the previous profile are not working.
+			mux.CloseStaleUnderlays()
+		},
+		probe: probeClientProfile,
+		done:  make(chan struct{}),
+	}
+	if err := f.Update(config); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Update replaces the profiles and the failover settings with the client
+// config. The priority is reset, because the client uses the active profile
+// after the config is reloaded.
+func (f *ClientFailover) Update(config *pb.ClientConfig) error {
+	activeProfile, err := GetActiveProfileFromConfig(config, config.GetActiveProfile())
+	if err != nil {
+		return fmt.Errorf("GetActiveProfileFromConfig() failed: %w", err)
+	}
+	profiles := []*pb.ClientProfile{activeProfile}
+	for _, name := range config.GetFailover().GetBackupProfiles() {
+		profile, err := GetActiveProfileFromConfig(config, name)
+		if err != nil {
+			return fmt.Errorf("GetActiveProfileFromConfig() failed: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+	maxFailures := defaultFailoverMaxFailures
+	if config.GetFailover().GetMaxFailures() > 0 {
+		maxFailures = int(config.GetFailover().GetMaxFailures())
+	}
+	healthCheckInterval := defaultFailoverHealthCheckInterval
+	if config.GetFailover().GetHealthCheckInterval() != "" {
+		healthCheckInterval, err = time.ParseDuration(config.GetFailover().GetHealthCheckInterval())
+		if err != nil {
+			return fmt.Errorf("failover health check interval %q is invalid: %w", config.GetFailover().GetHealthCheckInterval(), err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.profiles = profiles
+	f.current = 0
+	f.failures = 0
+	f.maxFailures = maxFailures
+	f.healthCheckInterval = healthCheckInterval
+	FailoverCurrentPriority.Store(0)
+	return nil
+}
+
+// CurrentProfile returns the name of the profile used by the client.
+func (f *ClientFailover) CurrentProfile() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.profiles[f.current].GetProfileName()
+}
+
+// ReportTunnelResult records the result of a proxy tunnel handshake.
+// After too many consecutive failures, the client switches to the
+// profile with the next priority. After the profile with the lowest
+// priority, the active profile is used again.
+func (f *ClientFailover) ReportTunnelResult(ok bool) {
+	f.mu.Lock()
+	if ok {
+		f.failures = 0
+		f.mu.Unlock()
+		return
+	}
+	FailoverTunnelFailures.Add(1)
+	if len(f.profiles) < 2 || f.switching || time.Since(f.switchTime) < failoverGracePeriod {
+		f.mu.Unlock()
+		return
+	}
+	f.failures++
+	if f.failures < f.maxFailures {
+		f.mu.Unlock()
+		return
+	}
+	next := (f.current + 1) % len(f.profiles)
+	profile := f.profiles[next]
+	log.Warnf("Proxy tunnel of profile %q failed %d times in a row, switching to profile %q", f.profiles[f.current].GetProfileName(), f.failures, profile.GetProfileName())
+	f.switching = true
+	f.mu.Unlock()
+	f.switchTo(next, profile)
+}
+
+// OnNetworkChange applies the current profile again, so the proxy servers
+// are resolved on the current network, which may have different IP address
+// families and DNS resolver. Tunnel failures on the previous network are
+// forgotten.
+func (f *ClientFailover) OnNetworkChange() {
+	f.mu.Lock()
+	f.failures = 0
+	profile := f.profiles[f.current]
+	f.mu.Unlock()
+
+	settings, err := f.resolve(profile)
+	if err != nil {
+		log.Errorf("Failed to apply profile %q on network change: %v", profile.GetProfileName(), err)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// Don't override a switch or an update made during the resolution.
+	if f.switching || f.profiles[f.current] != profile {
+		return
+	}
+	f.apply(settings)
+}
+
+// Start runs the health checks in the background until Close is called.
+func (f *ClientFailover) Start() {
+	go func() {
+		for {
+			f.mu.Lock()
+			interval := f.healthCheckInterval
+			f.mu.Unlock()
+			select {
+			case <-time.After(interval):
+				f.healthCheck()
+			case <-f.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the health checks.
+func (f *ClientFailover) Close() {
+	f.closeOnce.Do(func() {
+		close(f.done)
+	})
+}
+
+// healthCheck switches back to the profile with the highest priority
+// that is healthy, if it has a higher priority than the current profile.
+func (f *ClientFailover) healthCheck() {
+	f.mu.Lock()
+	candidates := make([]*pb.ClientProfile, f.current)
+	copy(candidates, f.profiles[:f.current])
+	f.mu.Unlock()
+
+	for i, profile := range candidates {
+		ctx, cancel := context.WithTimeout(context.Background(), failoverProbeTimeout)
+		err := f.probe(ctx, profile)
+		cancel()
+		if err != nil {
+			FailoverHealthCheckFailures.Add(1)
+			log.Debugf("Health check of profile %q failed: %v", profile.GetProfileName(), err)
+			continue
+		}
+
+		f.mu.Lock()
+		// The profiles may be updated during the health check.
+		if i < f.current && f.profiles[i] == profile && !f.switching {
+			log.Infof("Profile %q is healthy again, switching back from profile %q", profile.GetProfileName(), f.profiles[f.current].GetProfileName())
+			f.switching = true
+			f.mu.Unlock()
+			f.switchTo(i, profile)
+			return
+		}
+		f.mu.Unlock()
+		return
+	}
+}
+
+// switchTo makes the client use the profile with the given priority.
+// The profile is resolved without holding the mu lock, and it is not used
+// if the profiles are updated meanwhile. The caller MUST set switching
+// to true before calling this method.
+func (f *ClientFailover) switchTo(priority int, profile *pb.ClientProfile) {
+	settings, err := f.resolve(profile)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.switching = false
+	f.failures = 0
+	f.switchTime = time.Now()
+	if err != nil {
+		log.Errorf("Failed to switch to profile %q: %v", profile.GetProfileName(), err)
+		return
+	}
+	if priority >= len(f.profiles) || f.profiles[priority] != profile {
+		log.Infof("Profiles are updated, not switching to profile %q", profile.GetProfileName())
+		return
+	}
+	f.apply(settings)
+	f.current = priority
+	FailoverSwitches.Add(1)
+	FailoverCurrentPriority.Store(int64(priority))
+}
+
+// clientMuxSettings are the settings of the client multiplexer that
+// come from a profile, with the proxy server addresses resolved.
+type clientMuxSettings struct {
+	profile        *pb.ClientProfile
+	hashedPassword []byte
+	endpoints      []protocol.UnderlayProperties
+	dialer         apicommon.Dialer
+}
+
+// newClientMuxSettings returns the settings of the profile. It may use the
+// resolver to look up the proxy servers.
+func newClientMuxSettings(profile *pb.ClientProfile, resolver apicommon.DNSResolver) (*clientMuxSettings, error) {
+	hashedPassword, err := ClientUserHashedPassword(profile.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := ClientProfileToUnderlayProperties(profile, resolver)
+	if err != nil {
+		return nil, err
+	}
+	dialer, err := ClientProfileDialer(profile)
+	if err != nil {
+		return nil, err
+	}
+	return &clientMuxSettings{
+		profile:        profile,
+		hashedPassword: hashedPassword,
+		endpoints:      endpoints,
+		dialer:         dialer,
+	}, nil
+}
+
+// applyTo makes the client multiplexer use the settings.
+// It doesn't do any network I/O. Existing underlays are not closed.
+func (s *clientMuxSettings) applyTo(mux *protocol.Mux) {
+	profile := s.profile
+	mux.SetDialer(s.dialer)
+	mux.SetClientLocalBinding(appctlcommon.ClientLocalBinding(profile))
+	mux.SetClientHeartbeat(appctlcommon.HeartbeatFromConfig(profile.GetHeartbeat()))
+	mux.SetClientReconnect(appctlcommon.ReconnectPolicyFromConfig(profile.GetReconnect()))
+	mux.SetClientCredentials(profile.GetUser().GetName(), s.hashedPassword, profile.GetUser().GetCipherSuite())
+	mux.SetClientHybridKeyExchange(profile.GetHybridKeyExchange())
+	mux.SetKeyRotation(appctlcommon.KeyRotationFromConfig(profile.GetKeyRotation()))
+	mux.SetEndpoints(s.endpoints)
+}
+
+// applyClientProfileToMux makes the client multiplexer use the user,
+// proxy servers and transport plugin of the profile.
+// Existing underlays are not closed.
+func applyClientProfileToMux(mux *protocol.Mux, profile *pb.ClientProfile, resolver apicommon.DNSResolver) error {
+	settings, err := newClientMuxSettings(profile, resolver)
+	if err != nil {
+		return err
+	}
+	settings.applyTo(mux)
+	return nil
+}
+
+// probeClientProfile sends a socks5 BIND request to the proxy servers of
+// the profile. The proxy server doesn't support BIND command, so any
+// response means the proxy tunnel works.
+func probeClientProfile(ctx context.Context, profile *pb.ClientProfile) error {
+	mux := protocol.NewMux(true)
+	defer mux.Close()
+	if err := applyClientProfileToMux(mux, profile, &net.Resolver{}); err != nil {
+		return err
+	}
+	conn, err := mux.DialContext(ctx)
+	if err != nil {
+		return fmt.Errorf("mux DialContext() failed: %w", err)
+	}
+	return probeProxyConn(ctx, conn)
+}
+
+// probeProxyConn sends a socks5 BIND request over the proxy connection,
+// and waits for the response. The connection is closed when it returns.
+func probeProxyConn(ctx context.Context, conn net.Conn) error {
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	// BIND request to 0.0.0.0:0.
+	req := []byte{5, 2, 0, 1, 0, 0, 0, 0, 0, 0}
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("failed to write socks5 request: %w", err)
+	}
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return fmt.Errorf("failed to read socks5 response: %w", err)
+	}
+	if resp[0] != 5 {
+		return fmt.Errorf("unexpected socks5 version %d in response", resp[0])
+	}
+	return nil
+}
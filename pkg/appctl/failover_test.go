@@ -0,0 +1,202 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestClientFailover(t *testing.T, unhealthy map[string]bool) (*ClientFailover, *[]string) {
+	t.Helper()
+	config := &pb.ClientConfig{
+		Profiles: []*pb.ClientProfile{
+			{ProfileName: proto.String("primary")},
+			{ProfileName: proto.String("backup1")},
+			{ProfileName: proto.String("backup2")},
+		},
+		ActiveProfile: proto.String("primary"),
+		Failover: &pb.ProfileFailover{
+			BackupProfiles: []string{"backup1", "backup2"},
+			MaxFailures:    proto.Int32(2),
+		},
+	}
+	applied := []string{}
+	f := &ClientFailover{
+		resolve: func(profile *pb.ClientProfile) (*clientMuxSettings, error) {
+			return &clientMuxSettings{profile: profile}, nil
+		},
+		apply: func(settings *clientMuxSettings) {
+			applied = append(applied, settings.profile.GetProfileName())
+		},
+		probe: func(_ context.Context, profile *pb.ClientProfile) error {
+			if unhealthy[profile.GetProfileName()] {
+				return fmt.Errorf("profile %s is unhealthy", profile.GetProfileName())
+			}
+			return nil
+		},
+		done: make(chan struct{}),
+	}
+	if err := f.Update(config); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	return f, &applied
+}
+
+func TestClientFailoverSwitch(t *testing.T) {
+	f, applied := newTestClientFailover(t, nil)
+
+	f.ReportTunnelResult(false)
+	f.ReportTunnelResult(true)
+	f.ReportTunnelResult(false)
+	if f.CurrentProfile() != "primary" {
+		t.Fatalf("switched profile before reaching max failures")
+	}
+	f.ReportTunnelResult(false)
+	if f.CurrentProfile() != "backup1" {
+		t.Fatalf("current profile is %q, want %q", f.CurrentProfile(), "backup1")
+	}
+
+	// Failures right after the switch are ignored.
+	f.ReportTunnelResult(false)
+	f.ReportTunnelResult(false)
+	if f.CurrentProfile() != "backup1" {
+		t.Fatalf("switched profile during grace period")
+	}
+
+	f.switchTime = time.Now().Add(-failoverGracePeriod)
+	f.ReportTunnelResult(false)
+	f.ReportTunnelResult(false)
+	if f.CurrentProfile() != "backup2" {
+		t.Fatalf("current profile is %q, want %q", f.CurrentProfile(), "backup2")
+	}
+
+	// Wrap around to the active profile.
+	f.switchTime = time.Now().Add(-failoverGracePeriod)
+	f.ReportTunnelResult(false)
+	f.ReportTunnelResult(false)
+	if f.CurrentProfile() != "primary" {
+		t.Fatalf("current profile is %q, want %q", f.CurrentProfile(), "primary")
+	}
+	want := []string{"backup1", "backup2", "primary"}
+	if fmt.Sprint(*applied) != fmt.Sprint(want) {
+		t.Errorf("applied profiles %v, want %v", *applied, want)
+	}
+}
+
+func TestClientFailoverHealthCheck(t *testing.T) {
+	f, applied := newTestClientFailover(t, map[string]bool{"primary": true})
+	f.switching = true
+	f.switchTo(2, f.profiles[2])
+
+	// The primary profile is unhealthy, switch back to the first backup.
+	f.healthCheck()
+	if f.CurrentProfile() != "backup1" {
+		t.Fatalf("current profile is %q, want %q", f.CurrentProfile(), "backup1")
+	}
+
+	// Stay with the first backup.
+	f.healthCheck()
+	if f.CurrentProfile() != "backup1" {
+		t.Fatalf("current profile is %q, want %q", f.CurrentProfile(), "backup1")
+	}
+	want := []string{"backup2", "backup1"}
+	if fmt.Sprint(*applied) != fmt.Sprint(want) {
+		t.Errorf("applied profiles %v, want %v", *applied, want)
+	}
+}
+
+func TestClientFailoverNoBackup(t *testing.T) {
+	f, applied := newTestClientFailover(t, nil)
+	if err := f.Update(&pb.ClientConfig{
+		Profiles:      []*pb.ClientProfile{{ProfileName: proto.String("primary")}},
+		ActiveProfile: proto.String("primary"),
+	}); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		f.ReportTunnelResult(false)
+	}
+	if f.CurrentProfile() != "primary" || len(*applied) != 0 {
+		t.Errorf("switched profile without backup profiles")
+	}
+}
+
+func TestClientFailoverOnNetworkChange(t *testing.T) {
+	f, applied := newTestClientFailover(t, nil)
+	f.ReportTunnelResult(false)
+	f.OnNetworkChange()
+
+	// The failure on the previous network is forgotten.
+	f.ReportTunnelResult(false)
+	if f.CurrentProfile() != "primary" {
+		t.Fatalf("current profile is %q, want %q", f.CurrentProfile(), "primary")
+	}
+	want := []string{"primary"}
+	if fmt.Sprint(*applied) != fmt.Sprint(want) {
+		t.Errorf("applied profiles %v, want %v", *applied, want)
+	}
+}
+
+func TestClientFailoverUsedMux(t *testing.T) {
+	primaryServer, primaryPort := startTestProxyServer(t, []*pb.User{
+		{Name: proto.String("user1"), Password: proto.String("fa7206ed2a94")},
+	})
+	_, backupPort := startTestProxyServer(t, []*pb.User{
+		{Name: proto.String("user2"), Password: proto.String("6a1b7e0c93d5")},
+	})
+	config := &pb.ClientConfig{
+		Profiles: []*pb.ClientProfile{
+			testClientProfile("primary", "user1", "fa7206ed2a94", primaryPort),
+			testClientProfile("backup", "user2", "6a1b7e0c93d5", backupPort),
+		},
+		ActiveProfile: proto.String("primary"),
+		Failover: &pb.ProfileFailover{
+			BackupProfiles: []string{"backup"},
+			MaxFailures:    proto.Int32(1),
+		},
+	}
+	mux := protocol.NewMux(true)
+	defer mux.Close()
+	if err := applyClientProfileToMux(mux, config.GetProfiles()[0], &net.Resolver{}); err != nil {
+		t.Fatalf("applyClientProfileToMux() failed: %v", err)
+	}
+	if err := echoThroughMux(mux); err != nil {
+		t.Fatalf("echoThroughMux() failed: %v", err)
+	}
+	f, err := NewClientFailover(config, mux)
+	if err != nil {
+		t.Fatalf("NewClientFailover() failed: %v", err)
+	}
+	defer f.Close()
+
+	// The proxy server of the primary profile stops working.
+	primaryServer.Close()
+	f.ReportTunnelResult(false)
+	if f.CurrentProfile() != "backup" {
+		t.Fatalf("current profile is %q, want %q", f.CurrentProfile(), "backup")
+	}
+	if err := echoThroughMux(mux); err != nil {
+		t.Errorf("echoThroughMux() failed after switching profile: %v", err)
+	}
+}
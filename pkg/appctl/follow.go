@@ -0,0 +1,100 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"sync"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	// followLogsDone is closed when the RPC server is stopping.
+	// GracefulStop() waits for the FollowLogs RPCs, which otherwise never end.
+	followLogsDone     = make(chan struct{})
+	followLogsDoneOnce sync.Once
+)
+
+// stopFollowLogs ends all the FollowLogs RPCs.
+func stopFollowLogs() {
+	followLogsDoneOnce.Do(func() { close(followLogsDone) })
+}
+
+// logEntrySender is implemented by the server streams of FollowLogs RPC.
+type logEntrySender interface {
+	Send(*pb.LogEntry) error
+	Context() context.Context
+}
+
+// followLogs sends the live log entries at the level or more severe
+// to the stream, until the RPC client cancels it.
+func followLogs(req *pb.FollowLogsRequest, stream logEntrySender) error {
+	level := log.InfoLevel
+	if req.GetLevel() != pb.LoggingLevel_DEFAULT {
+		var err error
+		if level, err = log.ParseLevel(req.GetLevel().String()); err != nil {
+			return err
+		}
+	}
+	entries, stop := log.Follow(level)
+	defer func() {
+		if dropped := stop(); dropped > 0 {
+			log.Infof("%d log entries are not sent to the follower because it is too slow", dropped)
+		}
+	}()
+	for {
+		select {
+		case e := <-entries:
+			if err := stream.Send(logEntryToProto(e)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		case <-followLogsDone:
+			return nil
+		}
+	}
+}
+
+func logEntryToProto(e log.FollowedEntry) *pb.LogEntry {
+	return &pb.LogEntry{
+		Time:    timestamppb.New(e.Time),
+		Level:   loggingLevelToProto(e.Level).Enum(),
+		Message: proto.String(e.Message),
+		Fields:  e.Fields,
+	}
+}
+
+func loggingLevelToProto(level log.Level) pb.LoggingLevel {
+	switch level {
+	case log.PanicLevel, log.FatalLevel:
+		return pb.LoggingLevel_FATAL
+	case log.ErrorLevel:
+		return pb.LoggingLevel_ERROR
+	case log.WarnLevel:
+		return pb.LoggingLevel_WARN
+	case log.InfoLevel:
+		return pb.LoggingLevel_INFO
+	case log.DebugLevel:
+		return pb.LoggingLevel_DEBUG
+	default:
+		return pb.LoggingLevel_TRACE
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/systemd"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// gatewayPathPrefix is the path prefix of the HTTP JSON API.
+	gatewayPathPrefix = "/api/v1/"
+
+	// gatewayMinTokenLength is the minimum length of a bearer token.
+	gatewayMinTokenLength = 16
+
+	// gatewayMaxRequestSize is the maximum size of a request body.
+	gatewayMaxRequestSize = 1024 * 1024
+)
+
+var (
+	// ManagementGatewayRequests is the number of authorized requests
+	// to the HTTP JSON API.
+	ManagementGatewayRequests = metrics.RegisterMetric("management gateway", "Requests", metrics.COUNTER)
+
+	// ManagementGatewayUnauthorized is the number of requests to the
+	// HTTP JSON API rejected because the bearer token is missing or wrong.
+	ManagementGatewayUnauthorized = metrics.RegisterMetric("management gateway", "Unauthorized", metrics.COUNTER)
+
+	// serverGatewayRef holds a pointer to the HTTP server of the management gateway.
+	serverGatewayRef atomic.Pointer[http.Server]
+)
+
+// StartServerManagementGateway listens to the TCP port of the management
+// gateway, and serves the HTTP JSON API in the background.
+// It does nothing if the gateway is not enabled.
+func StartServerManagementGateway(config *pb.ManagementGateway) error {
+	if config.GetPort() == 0 {
+		return nil
+	}
+	addr := net.JoinHostPort(config.GetBindIP(), strconv.Itoa(int(config.GetPort())))
+	listener, err := systemd.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen on management gateway address %q failed: %w", addr, err)
+	}
+	server := &http.Server{
+		Handler:           newManagementGateway(NewServerManagementService(), config),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serverGatewayRef.Store(server)
+	useTLS := config.GetCertificateFile() != ""
+	go func() {
+		log.Infof("mita server management gateway %v is running", addr)
+		var err error
+		if useTLS {
+			err = server.ServeTLS(listener, config.GetCertificateFile(), config.GetPrivateKeyFile())
+		} else {
+			err = server.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("run management gateway failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// stopServerManagementGateway stops the management gateway if it is running.
+func stopServerManagementGateway() {
+	if server := serverGatewayRef.Swap(nil); server != nil {
+		log.Infof("stopping management gateway")
+		go server.Close()
+	}
+}
+
+// validateManagementGateway validates the management gateway.
+func validateManagementGateway(config *pb.ManagementGateway) error {
+	if config.GetPort() == 0 {
+		return nil
+	}
+	if config.GetPort() < 1 || config.GetPort() > 65535 {
+		return fmt.Errorf("management gateway port %d is invalid", config.GetPort())
+	}
+	if config.GetBindIP() != "" && net.ParseIP(config.GetBindIP()) == nil {
+		return fmt.Errorf("management gateway bind IP %q is invalid", config.GetBindIP())
+	}
+	if (config.GetCertificateFile() == "") != (config.GetPrivateKeyFile() == "") {
+		return fmt.Errorf("management gateway certificate file and private key file must be set together")
+	}
+	if config.GetCertificateFile() == "" {
+		if ip := net.ParseIP(config.GetBindIP()); ip == nil || !ip.IsLoopback() {
+			return fmt.Errorf("management gateway without TLS certificate must bind to a loopback IP address")
+		}
+	} else {
+		if !filepath.IsAbs(config.GetCertificateFile()) {
+			return fmt.Errorf("management gateway certificate file %q is not an absolute path", config.GetCertificateFile())
+		}
+		if !filepath.IsAbs(config.GetPrivateKeyFile()) {
+			return fmt.Errorf("management gateway private key file %q is not an absolute path", config.GetPrivateKeyFile())
+		}
+	}
+	if len(config.GetTokens()) == 0 && len(config.GetClusterTokens()) == 0 {
+		return fmt.Errorf("management gateway tokens are not set")
+	}
+	for _, token := range config.GetTokens() {
+		if len(token) < gatewayMinTokenLength {
+			return fmt.Errorf("management gateway token must have at least %d characters", gatewayMinTokenLength)
+		}
+	}
+	for _, token := range config.GetClusterTokens() {
+		if len(token) < gatewayMinTokenLength {
+			return fmt.Errorf("management gateway cluster token must have at least %d characters", gatewayMinTokenLength)
+		}
+		for _, t := range config.GetTokens() {
+			if token == t {
+				return fmt.Errorf("management gateway cluster token must be different from the tokens")
+			}
+		}
+	}
+	return nil
+}
+
+// managementGateway translates HTTP JSON requests to the calls of
+// the server management service.
+type managementGateway struct {
+	service       *serverManagementService
+	tokens        [][]byte
+	clusterTokens [][]byte // only accepted by the cluster sync API
+	dashboard     bool
+	updates       updateChecker
+}
+
+var _ http.Handler = &managementGateway{}
+
+func newManagementGateway(service *serverManagementService, config *pb.ManagementGateway) *managementGateway {
+	g := &managementGateway{service: service, dashboard: config.GetDashboard()}
+	for _, token := range config.GetTokens() {
+		g.tokens = append(g.tokens, []byte(token))
+	}
+	for _, token := range config.GetClusterTokens() {
+		g.clusterTokens = append(g.clusterTokens, []byte(token))
+	}
+	return g
+}
+
+func (g *managementGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if g.dashboard && (r.URL.Path == "/" || r.URL.Path == "/index.html") {
+		serveDashboard(w, r)
+		return
+	}
+	// The cluster tokens are only accepted by the cluster sync API,
+	// so a replica server can't manage the primary server.
+	route := strings.TrimPrefix(r.URL.Path, gatewayPathPrefix)
+	tokens := g.tokens
+	if route == clusterSyncRoute {
+		tokens = g.clusterTokens
+	}
+	if !g.authorized(r, tokens) {
+		ManagementGatewayUnauthorized.Add(1)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeGatewayError(w, status.Error(codes.Unauthenticated, "bearer token is missing or invalid"))
+		return
+	}
+	ManagementGatewayRequests.Add(1)
+	log.Infof("management gateway request %s %s from %v", r.Method, r.URL.Path, r.RemoteAddr)
+
+	ctx := r.Context()
+	empty := &emptypb.Empty{}
+	if route == r.URL.Path {
+		writeGatewayError(w, status.Errorf(codes.NotFound, "path %q is not found", r.URL.Path))
+		return
+	}
+
+	if name, found := strings.CutPrefix(route, "users/"); found && name != "" {
+		switch r.Method {
+		case http.MethodPut:
+			g.putUser(w, r, name)
+		case http.MethodDelete:
+			writeGatewayResponse(w, empty, userRPCError(DeleteServerUsers([]string{name})))
+		default:
+			writeMethodNotAllowed(w, "PUT, DELETE")
+		}
+		return
+	}
+
+	switch route {
+	case "status":
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		resp, err := g.service.GetStatus(ctx, empty)
+		writeGatewayResponse(w, resp, err)
+	case "version":
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		resp, err := g.service.GetVersion(ctx, empty)
+		writeGatewayResponse(w, resp, err)
+	case "metrics":
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		resp, err := g.service.GetMetrics(ctx, empty)
+		if err != nil {
+			writeGatewayError(w, err)
+			return
+		}
+		// Metrics are already in JSON format.
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, resp.GetJson())
+	case "sessions":
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		resp, err := g.service.GetSessionInfoList(ctx, empty)
+		writeGatewayResponse(w, resp, err)
+	case "update":
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		writeGatewayResponse(w, g.updates.check(), nil)
+	case "users":
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w, http.MethodGet)
+			return
+		}
+		resp, err := g.service.GetUsers(ctx, empty)
+		writeGatewayResponse(w, gatewayUsers(resp), err)
+	case clusterSyncRoute:
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w, http.MethodPost)
+			return
+		}
+		g.clusterSync(w, r)
+	case "start", "stop", "reload":
+		if r.Method != http.MethodPost {
+			writeMethodNotAllowed(w, http.MethodPost)
+			return
+		}
+		var err error
+		switch route {
+		case "start":
+			_, err = g.service.Start(ctx, empty)
+		case "stop":
+			_, err = g.service.Stop(ctx, empty)
+		case "reload":
+			_, err = g.service.Reload(ctx, empty)
+		}
+		writeGatewayResponse(w, empty, err)
+	default:
+		writeGatewayError(w, status.Errorf(codes.NotFound, "path %q is not found", r.URL.Path))
+	}
+}
+
+// authorized returns true if the request has one of the bearer tokens.
+func (g *managementGateway) authorized(r *http.Request, tokens [][]byte) bool {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return false
+	}
+	ok := false
+	for _, t := range tokens {
+		// Check all the tokens to avoid leaking which one matches by timing.
+		if subtle.ConstantTimeCompare([]byte(token), t) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// putUser creates or updates a user. The user name in the path
+// overrides the name in the request body.
+func (g *managementGateway) putUser(w http.ResponseWriter, r *http.Request, name string) {
+	b, err := io.ReadAll(io.LimitReader(r.Body, gatewayMaxRequestSize))
+	if err != nil {
+		writeGatewayError(w, status.Errorf(codes.InvalidArgument, "read request body failed: %v", err))
+		return
+	}
+	user := &pb.User{}
+	if err := common.UnmarshalJSON(b, user); err != nil {
+		writeGatewayError(w, status.Errorf(codes.InvalidArgument, "invalid user: %v", err))
+		return
+	}
+	user.Name = proto.String(name)
+	writeGatewayResponse(w, &emptypb.Empty{}, userRPCError(setServerUser(user, true, true)))
+}
+
+// gatewayUsers returns the names and metrics of the users. The other
+// settings, including the passwords and hashed passwords, are not
+// returned, so the holders of a bearer token can't get the credentials
+// of the users.
+func gatewayUsers(list *pb.UserWithMetricsList) *pb.UserWithMetricsList {
+	resp := &pb.UserWithMetricsList{}
+	for _, item := range list.GetItems() {
+		resp.Items = append(resp.Items, &pb.UserWithMetrics{
+			User:    &pb.User{Name: proto.String(item.GetUser().GetName())},
+			Metrics: item.GetMetrics(),
+		})
+	}
+	return resp
+}
+
+// writeGatewayResponse writes the response in JSON format,
+// or the error if it is not nil.
+func writeGatewayResponse(w http.ResponseWriter, resp protoreflect.ProtoMessage, err error) {
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	b, err := common.MarshalJSON(resp)
+	if err != nil {
+		writeGatewayError(w, status.Errorf(codes.Internal, "common.MarshalJSON() failed: %v", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// writeGatewayError writes the error detail in JSON format,
+// with the HTTP status code matching the gRPC code of the error.
+func writeGatewayError(w http.ResponseWriter, err error) {
+	st, _ := status.FromError(err)
+	detail := GetRPCErrorDetail(err)
+	if detail == nil {
+		detail = &pb.ErrorDetail{
+			Code:    pb.ErrorCode_UNKNOWN_ERROR_CODE.Enum(),
+			Message: proto.String(st.Message()),
+		}
+	}
+	b, _ := common.MarshalJSON(detail)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(grpcCodeToHTTPStatus(st.Code()))
+	w.Write(b)
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	b, _ := common.MarshalJSON(&pb.ErrorDetail{
+		Code:    pb.ErrorCode_UNKNOWN_ERROR_CODE.Enum(),
+		Message: proto.String("method is not allowed"),
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusMethodNotAllowed)
+	w.Write(b)
+}
+
+func grpcCodeToHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
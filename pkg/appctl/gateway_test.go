@@ -0,0 +1,104 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestManagementGateway(t *testing.T) {
+	beforeServerTest(t)
+	defer afterServerTest(t)
+	if err := StoreServerConfig(&pb.ServerConfig{
+		PortBindings: []*pb.PortBinding{{Port: proto.Int32(8000), Protocol: pb.TransportProtocol_TCP.Enum()}},
+	}); err != nil {
+		t.Fatalf("StoreServerConfig() failed: %v", err)
+	}
+
+	const token = "0123456789abcdef"
+	g := newManagementGateway(NewServerManagementService(), &pb.ManagementGateway{
+		Tokens:    []string{token},
+		Dashboard: proto.Bool(true),
+	})
+	do := func(method, path, body, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if auth != "" {
+			req.Header.Set("Authorization", "Bearer "+auth)
+		}
+		rec := httptest.NewRecorder()
+		g.ServeHTTP(rec, req)
+		return rec
+	}
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		auth       string
+		wantStatus int
+	}{
+		{"dashboard", http.MethodGet, "/", "", "", http.StatusOK},
+		{"no token", http.MethodGet, "/api/v1/status", "", "", http.StatusUnauthorized},
+		{"wrong token", http.MethodGet, "/api/v1/status", "", "fedcba9876543210", http.StatusUnauthorized},
+		{"status", http.MethodGet, "/api/v1/status", "", token, http.StatusOK},
+		{"metrics", http.MethodGet, "/api/v1/metrics", "", token, http.StatusOK},
+		{"sessions without proxy", http.MethodGet, "/api/v1/sessions", "", token, http.StatusServiceUnavailable},
+		{"method not allowed", http.MethodPost, "/api/v1/status", "", token, http.StatusMethodNotAllowed},
+		{"not found", http.MethodGet, "/api/v1/unknown", "", token, http.StatusNotFound},
+		{"add user", http.MethodPut, "/api/v1/users/alice", `{"password": "c2c8f2b0a5e1"}`, token, http.StatusOK},
+		{"add invalid user", http.MethodPut, "/api/v1/users/bob", `{}`, token, http.StatusBadRequest},
+		{"update user", http.MethodPut, "/api/v1/users/alice", `{"password": "e5a1c2b0f8c2"}`, token, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := do(tc.method, tc.path, tc.body, tc.auth)
+			if rec.Code != tc.wantStatus {
+				t.Errorf("%s %s got status %d, want %d: %s", tc.method, tc.path, rec.Code, tc.wantStatus, rec.Body.String())
+			}
+		})
+	}
+
+	rec := do(http.MethodGet, "/api/v1/users", "", token)
+	users := &pb.UserWithMetricsList{}
+	if err := common.UnmarshalJSON(rec.Body.Bytes(), users); err != nil {
+		t.Fatalf("common.UnmarshalJSON() failed: %v", err)
+	}
+	if len(users.GetItems()) != 1 || users.GetItems()[0].GetUser().GetName() != "alice" {
+		t.Errorf("got users %s, want alice", rec.Body.String())
+	}
+	if user := users.GetItems()[0].GetUser(); !proto.Equal(user, &pb.User{Name: proto.String("alice")}) {
+		t.Errorf("users response has more than the user name: %s", rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "e5a1c2b0f8c2") {
+		t.Errorf("users response has the password: %s", rec.Body.String())
+	}
+
+	if rec := do(http.MethodDelete, "/api/v1/users/alice", "", token); rec.Code != http.StatusOK {
+		t.Fatalf("DELETE user got status %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = do(http.MethodGet, "/api/v1/users", "", token)
+	if strings.Contains(rec.Body.String(), "alice") {
+		t.Errorf("user is not deleted: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// latencyProbeInterval is the interval to measure the latency of
+	// proxy servers, when server selection by latency is enabled.
+	latencyProbeInterval = 5 * time.Minute
+
+	// latencyProbeTimeout is the maximum duration to measure the latency
+	// of a proxy server.
+	latencyProbeTimeout = 5 * time.Second
+)
+
+var (
+	LatencyProbes        = metrics.RegisterMetric("latency probe", "Probes", metrics.COUNTER)
+	LatencyProbeFailures = metrics.RegisterMetric("latency probe", "Failures", metrics.COUNTER)
+)
+
+// ProbeClientServers measures the handshake latency of the proxy servers
+// used by the client multiplexer. The proxy servers are probed concurrently.
+// The ports of the same IP address and transport protocol share the result
+// of one probe. The result is recorded in the multiplexer.
+func ProbeClientServers(ctx context.Context, mux *protocol.Mux) []*pb.ServerLatency {
+	targets := mux.ProbeTargets()
+	results := make([]*pb.ServerLatency, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target protocol.UnderlayProperties) {
+			defer wg.Done()
+			results[i] = probeClientServer(ctx, mux, target)
+		}(i, target)
+	}
+	wg.Wait()
+	return results
+}
+
+// StartClientLatencyProbe measures the latency of the proxy servers
+// periodically in the background, until the context is done.
+func StartClientLatencyProbe(ctx context.Context, mux *protocol.Mux) {
+	go runClientLatencyProbe(ctx, mux, latencyProbeInterval)
+}
+
+func runClientLatencyProbe(ctx context.Context, mux *protocol.Mux, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ProbeClientServers(ctx, mux)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ClientConfigUsesAutoTransport returns true if a proxy server of any
+// client profile has a port binding with AUTO protocol.
+func ClientConfigUsesAutoTransport(config *pb.ClientConfig) bool {
+	for _, profile := range config.GetProfiles() {
+		for _, server := range profile.GetServers() {
+			for _, binding := range server.GetPortBindings() {
+				if binding.GetProtocol() == pb.TransportProtocol_AUTO {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func probeClientServer(ctx context.Context, mux *protocol.Mux, target protocol.UnderlayProperties) *pb.ServerLatency {
+	LatencyProbes.Add(1)
+	result := &pb.ServerLatency{}
+	switch addr := target.RemoteAddr().(type) {
+	case *net.TCPAddr:
+		result.IpAddress = proto.String(addr.IP.String())
+		result.Port = proto.Int32(int32(addr.Port))
+	case *net.UDPAddr:
+		result.IpAddress = proto.String(addr.IP.String())
+		result.Port = proto.Int32(int32(addr.Port))
+	}
+	switch target.TransportProtocol() {
+	case common.StreamTransport:
+		result.Protocol = pb.TransportProtocol_TCP.Enum()
+	case common.PacketTransport:
+		result.Protocol = pb.TransportProtocol_UDP.Enum()
+	case common.WebSocketTransport:
+		result.Protocol = pb.TransportProtocol_WEBSOCKET.Enum()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, latencyProbeTimeout)
+	defer cancel()
+	start := time.Now()
+	conn, err := mux.DialEndpointContext(ctx, target)
+	if err == nil {
+		err = probeProxyConn(ctx, conn)
+	}
+	if err != nil {
+		LatencyProbeFailures.Add(1)
+		log.Debugf("Failed to measure latency of %v: %v", target.RemoteAddr(), err)
+		mux.SetPathLatency(target, 0, false)
+		result.Error = proto.String(err.Error())
+		return result
+	}
+	latency := time.Since(start)
+	log.Debugf("Latency of %v is %v", target.RemoteAddr(), latency)
+	mux.SetPathLatency(target, latency, true)
+	result.RttMicros = proto.Int64(latency.Microseconds())
+	return result
+}
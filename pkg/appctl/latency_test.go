@@ -0,0 +1,39 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+func TestClientLatencyProbeStopsWithMux(t *testing.T) {
+	mux := protocol.NewMux(true)
+	done := make(chan struct{})
+	go func() {
+		runClientLatencyProbe(mux.Context(), mux, time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	mux.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("latency probe is still running after the mux is closed")
+	}
+}
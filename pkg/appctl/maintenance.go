@@ -0,0 +1,218 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	defaultMaintenanceAnnounceMinutes = 10
+	maxMaintenanceAnnounceMinutes     = 720
+
+	// maintenanceCheckInterval is the interval to check if the
+	// maintenance should be announced or started.
+	maintenanceCheckInterval = 10 * time.Second
+
+	// maintenanceTimeLayout is the layout of daily start time.
+	maintenanceTimeLayout = "15:04"
+)
+
+var (
+	MaintenanceAnnouncements = metrics.RegisterMetric("maintenance", "Announcements", metrics.COUNTER)
+	MaintenanceCompleted     = metrics.RegisterMetric("maintenance", "Completed", metrics.COUNTER)
+	MaintenanceFailed        = metrics.RegisterMetric("maintenance", "Failed", metrics.COUNTER)
+)
+
+// ServerMaintenance runs the daily maintenance window of the server.
+//
+// Before the maintenance starts, the server announces it to the clients,
+// so they stop opening new sessions to the server, and the existing
+// sessions can drain. When the maintenance starts, the proxy is restarted
+// or the server config is reloaded. Each step is written to the log.
+type ServerMaintenance struct {
+	mu        sync.Mutex
+	window    *pb.MaintenanceWindow
+	next      time.Time // start time of the next maintenance, zero if not scheduled
+	announced bool
+
+	// announce starts or stops announcing the maintenance to clients.
+	announce func(bool)
+
+	// run executes the maintenance action.
+	run func(pb.MaintenanceAction) error
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewServerMaintenance creates a ServerMaintenance that announces the
+// maintenance with the server multiplexer.
+func NewServerMaintenance(config *pb.ServerConfig) *ServerMaintenance {
+	m := &ServerMaintenance{
+		announce: func(announce bool) {
+			if mux := serverMuxRef.Load(); mux != nil {
+				mux.SetServerMaintenance(announce)
+			}
+		},
+		run:  runMaintenanceAction,
+		done: make(chan struct{}),
+	}
+	m.Update(config)
+	return m
+}
+
+// Update replaces the maintenance window with the server config.
+// The next maintenance is scheduled again.
+func (m *ServerMaintenance) Update(config *pb.ServerConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.window = config.GetMaintenance()
+	m.next = time.Time{}
+}
+
+// Start checks the maintenance window in the background until Close is called.
+func (m *ServerMaintenance) Start() {
+	go func() {
+		ticker := time.NewTicker(maintenanceCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				m.tick(now)
+			case <-m.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops checking the maintenance window.
+func (m *ServerMaintenance) Close() {
+	m.closeOnce.Do(func() {
+		close(m.done)
+	})
+}
+
+// tick announces or starts the maintenance based on the current time.
+func (m *ServerMaintenance) tick(now time.Time) {
+	m.mu.Lock()
+	if m.window.GetDailyStartTime() == "" {
+		m.next = time.Time{}
+		m.stopAnnounceLocked()
+		m.mu.Unlock()
+		return
+	}
+	if m.next.IsZero() {
+		next, err := nextMaintenanceTime(m.window.GetDailyStartTime(), now)
+		if err != nil {
+			log.Warnf("Failed to schedule server maintenance: %v", err)
+			m.mu.Unlock()
+			return
+		}
+		m.next = next
+		log.Infof("Next server maintenance is scheduled at %s", next.Format(time.RFC3339))
+	}
+	announceMinutes := defaultMaintenanceAnnounceMinutes
+	if m.window.GetAnnounceMinutes() > 0 {
+		announceMinutes = int(m.window.GetAnnounceMinutes())
+	}
+	if now.Before(m.next.Add(-time.Duration(announceMinutes) * time.Minute)) {
+		m.stopAnnounceLocked()
+		m.mu.Unlock()
+		return
+	}
+	if now.Before(m.next) {
+		if !m.announced {
+			m.announced = true
+			MaintenanceAnnouncements.Add(1)
+			log.Infof("Announcing server maintenance at %s to clients", m.next.Format(time.RFC3339))
+			m.announce(true)
+		}
+		m.mu.Unlock()
+		return
+	}
+
+	// Start the maintenance. The next one is scheduled in the next tick.
+	action := m.window.GetAction()
+	m.next = time.Time{}
+	m.mu.Unlock()
+	log.Infof("Server maintenance is started with action %s", action.String())
+	if err := m.run(action); err != nil {
+		MaintenanceFailed.Add(1)
+		log.Errorf("Server maintenance failed: %v", err)
+	} else {
+		MaintenanceCompleted.Add(1)
+		log.Infof("Server maintenance is completed")
+	}
+	m.mu.Lock()
+	m.stopAnnounceLocked()
+	m.mu.Unlock()
+}
+
+// stopAnnounceLocked stops announcing the maintenance to clients.
+// This method MUST be called only when holding the mu lock.
+func (m *ServerMaintenance) stopAnnounceLocked() {
+	if m.announced {
+		m.announced = false
+		m.announce(false)
+	}
+}
+
+// nextMaintenanceTime returns the first time after now that matches
+// the daily start time in UTC.
+func nextMaintenanceTime(dailyStartTime string, now time.Time) (time.Time, error) {
+	t, err := time.Parse(maintenanceTimeLayout, dailyStartTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("maintenance daily start time %q is invalid: %w", dailyStartTime, err)
+	}
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next, nil
+}
+
+// runMaintenanceAction restarts the proxy or reloads the server config.
+func runMaintenanceAction(action pb.MaintenanceAction) error {
+	switch action {
+	case pb.MaintenanceAction_RESTART_PROXY:
+		service := NewServerManagementService()
+		if _, err := service.Stop(context.Background(), &emptypb.Empty{}); err != nil {
+			return fmt.Errorf("Stop() failed: %w", err)
+		}
+		if _, err := service.Start(context.Background(), &emptypb.Empty{}); err != nil {
+			return fmt.Errorf("Start() failed: %w", err)
+		}
+		return nil
+	case pb.MaintenanceAction_RELOAD_CONFIG:
+		if err := ReloadServerConfig(); err != nil {
+			return fmt.Errorf("ReloadServerConfig() failed: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown maintenance action %s", action.String())
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNextMaintenanceTime(t *testing.T) {
+	now := time.Date(2024, 6, 4, 20, 0, 0, 0, time.UTC)
+	cases := []struct {
+		dailyStartTime string
+		want           time.Time
+	}{
+		{"21:30", time.Date(2024, 6, 4, 21, 30, 0, 0, time.UTC)},
+		{"20:00", time.Date(2024, 6, 5, 20, 0, 0, 0, time.UTC)},
+		{"03:15", time.Date(2024, 6, 5, 3, 15, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		got, err := nextMaintenanceTime(c.dailyStartTime, now)
+		if err != nil {
+			t.Fatalf("nextMaintenanceTime(%q) failed: %v", c.dailyStartTime, err)
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("nextMaintenanceTime(%q) = %v, want %v", c.dailyStartTime, got, c.want)
+		}
+	}
+
+	if _, err := nextMaintenanceTime("25:00", now); err == nil {
+		t.Errorf("want error for invalid daily start time, got no error")
+	}
+}
+
+func TestServerMaintenanceTick(t *testing.T) {
+	announced := false
+	actions := []pb.MaintenanceAction{}
+	m := &ServerMaintenance{
+		announce: func(announce bool) {
+			announced = announce
+		},
+		run: func(action pb.MaintenanceAction) error {
+			actions = append(actions, action)
+			return nil
+		},
+		done: make(chan struct{}),
+	}
+	m.Update(&pb.ServerConfig{
+		Maintenance: &pb.MaintenanceWindow{
+			DailyStartTime:  proto.String("04:00"),
+			AnnounceMinutes: proto.Int32(30),
+			Action:          pb.MaintenanceAction_RELOAD_CONFIG.Enum(),
+		},
+	})
+
+	day := time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC)
+	m.tick(day.Add(3 * time.Hour))
+	if announced {
+		t.Fatalf("maintenance is announced too early")
+	}
+	m.tick(day.Add(3*time.Hour + 40*time.Minute))
+	if !announced {
+		t.Fatalf("maintenance is not announced")
+	}
+	if len(actions) != 0 {
+		t.Fatalf("maintenance is started before the start time")
+	}
+	m.tick(day.Add(4 * time.Hour))
+	if announced {
+		t.Errorf("maintenance is still announced after it is completed")
+	}
+	if len(actions) != 1 || actions[0] != pb.MaintenanceAction_RELOAD_CONFIG {
+		t.Fatalf("got actions %v, want [RELOAD_CONFIG]", actions)
+	}
+
+	// The next maintenance is on the next day.
+	m.tick(day.Add(4*time.Hour + time.Minute))
+	if want := day.Add(28 * time.Hour); !m.next.Equal(want) {
+		t.Errorf("next maintenance is at %v, want %v", m.next, want)
+	}
+
+	// Stop announcing if the maintenance window is removed.
+	m.tick(day.Add(27*time.Hour + 50*time.Minute))
+	if !announced {
+		t.Fatalf("maintenance is not announced")
+	}
+	m.Update(&pb.ServerConfig{})
+	m.tick(day.Add(27*time.Hour + 51*time.Minute))
+	if announced {
+		t.Errorf("maintenance is still announced after it is removed")
+	}
+	if len(actions) != 1 {
+		t.Errorf("got %d actions, want 1", len(actions))
+	}
+}
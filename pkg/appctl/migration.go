@@ -0,0 +1,163 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package appctl
+
+import (
+	"fmt"
+	"os"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"google.golang.org/protobuf/proto"
+)
+
+// ConfigSchemaVersion is the layout version of the client config and
+// the server config stored by this version of mieru. A config stored by
+// an older version of mieru is upgraded when it is loaded.
+//
+// To make a breaking change to the config layout, increase this number
+// and append a migration to clientConfigMigrations and
+// serverConfigMigrations.
+const ConfigSchemaVersion uint32 = 1
+
+// configMigration upgrades a config from one schema version to the next.
+type configMigration[T proto.Message] struct {
+	// description is logged when the migration is applied.
+	description string
+
+	// migrate changes the config in place.
+	migrate func(config T) error
+}
+
+// clientConfigMigrations[i] upgrades the client config from
+// schema version i to schema version i+1.
+var clientConfigMigrations = []configMigration[*pb.ClientConfig]{
+	{
+		// Client config stored before schema version is introduced
+		// has the same layout.
+		description: "add schema version",
+		migrate:     func(*pb.ClientConfig) error { return nil },
+	},
+}
+
+// serverConfigMigrations[i] upgrades the server config from
+// schema version i to schema version i+1.
+var serverConfigMigrations = []configMigration[*pb.ServerConfig]{
+	{
+		description: "remove deprecated advancedSettings.allowLocalDestination",
+		migrate: func(config *pb.ServerConfig) error {
+			if config.GetAdvancedSettings() != nil {
+				config.AdvancedSettings.AllowLocalDestination = nil
+			}
+			return nil
+		},
+	},
+}
+
+// migrateConfig applies the migrations to upgrade the config from the
+// given schema version to ConfigSchemaVersion. It returns an error if
+// the config is stored by a newer version of mieru.
+func migrateConfig[T proto.Message](name string, config T, version uint32, migrations []configMigration[T]) error {
+	if version > ConfigSchemaVersion {
+		return fmt.Errorf("%s schema version %d is newer than the supported schema version %d, upgrade mieru to use this config", name, version, ConfigSchemaVersion)
+	}
+	if int(ConfigSchemaVersion) > len(migrations) {
+		return fmt.Errorf("%s migration to schema version %d is not found", name, len(migrations)+1)
+	}
+	for v := version; v < ConfigSchemaVersion; v++ {
+		m := migrations[v]
+		if err := m.migrate(config); err != nil {
+			return fmt.Errorf("%s migration from schema version %d to %d (%s) failed: %w", name, v, v+1, m.description, err)
+		}
+		log.Infof("%s is upgraded from schema version %d to %d: %s", name, v, v+1, m.description)
+	}
+	return nil
+}
+
+// migrateClientConfig upgrades the client config to ConfigSchemaVersion.
+func migrateClientConfig(config *pb.ClientConfig) error {
+	if err := migrateConfig("client config", config, config.GetSchemaVersion(), clientConfigMigrations); err != nil {
+		return err
+	}
+	config.SchemaVersion = proto.Uint32(ConfigSchemaVersion)
+	return nil
+}
+
+// migrateServerConfig upgrades the server config to ConfigSchemaVersion.
+func migrateServerConfig(config *pb.ServerConfig) error {
+	if err := migrateConfig("server config", config, config.GetSchemaVersion(), serverConfigMigrations); err != nil {
+		return err
+	}
+	config.SchemaVersion = proto.Uint32(ConfigSchemaVersion)
+	return nil
+}
+
+// migrateConfigPatch upgrades a client config or server config provided
+// by the user. A user written config usually doesn't have a schema
+// version, and it is assumed to use the current layout. A config exported
+// by an older version of mieru has a schema version, and it is upgraded.
+func migrateConfigPatch(config proto.Message) error {
+	switch c := config.(type) {
+	case *pb.ClientConfig:
+		if c.SchemaVersion == nil {
+			return nil
+		}
+		return migrateClientConfig(c)
+	case *pb.ServerConfig:
+		if c.SchemaVersion == nil {
+			return nil
+		}
+		return migrateServerConfig(c)
+	default:
+		return fmt.Errorf("config type %T is not supported", config)
+	}
+}
+
+// configBackupFilePath returns the path of the backup file that keeps
+// the content of the config file with the given schema version.
+func configBackupFilePath(fileName string, version uint32) string {
+	return fmt.Sprintf("%s.v%d.bak", fileName, version)
+}
+
+// rewriteMigratedConfigFile keeps the original content of the config
+// file in a backup file, and writes the upgraded config to the config file.
+// The caller must hold the I/O lock of the config file.
+func rewriteMigratedConfigFile(fileName string, fileType ConfigFileType, original []byte, version uint32, config proto.Message) error {
+	var b []byte
+	var err error
+	switch fileType {
+	case PROTOBUF_CONFIG_FILE_TYPE:
+		if b, err = proto.Marshal(config); err != nil {
+			return fmt.Errorf("proto.Marshal() failed: %w", err)
+		}
+	case JSON_CONFIG_FILE_TYPE:
+		if b, err = common.MarshalJSON(config); err != nil {
+			return fmt.Errorf("common.MarshalJSON() failed: %w", err)
+		}
+	default:
+		return fmt.Errorf("config file type is invalid")
+	}
+
+	backup := configBackupFilePath(fileName, version)
+	if err := os.WriteFile(backup, original, 0660); err != nil {
+		return fmt.Errorf("os.WriteFile(%q) failed: %w", backup, err)
+	}
+	if err := os.WriteFile(fileName, b, 0660); err != nil {
+		return fmt.Errorf("os.WriteFile(%q) failed: %w", fileName, err)
+	}
+	log.Infof("upgraded config is written to %q, the original config is kept in %q", fileName, backup)
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package appctl
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConfigMigrationsMatchSchemaVersion(t *testing.T) {
+	if len(clientConfigMigrations) != int(ConfigSchemaVersion) {
+		t.Errorf("got %d client config migrations, want %d", len(clientConfigMigrations), ConfigSchemaVersion)
+	}
+	if len(serverConfigMigrations) != int(ConfigSchemaVersion) {
+		t.Errorf("got %d server config migrations, want %d", len(serverConfigMigrations), ConfigSchemaVersion)
+	}
+}
+
+func TestMigrateConfigRejectNewerVersion(t *testing.T) {
+	client := &pb.ClientConfig{SchemaVersion: proto.Uint32(ConfigSchemaVersion + 1)}
+	if err := migrateClientConfig(client); err == nil {
+		t.Errorf("migrateClientConfig() didn't reject newer schema version")
+	}
+	server := &pb.ServerConfig{SchemaVersion: proto.Uint32(ConfigSchemaVersion + 1)}
+	if err := migrateConfigPatch(server); err == nil {
+		t.Errorf("migrateConfigPatch() didn't reject newer schema version")
+	}
+}
+
+func TestMigrateConfigPatch(t *testing.T) {
+	// A patch without schema version uses the current layout.
+	patch := &pb.ServerConfig{
+		AdvancedSettings: &pb.ServerAdvancedSettings{
+			AllowLocalDestination: proto.Bool(true),
+		},
+	}
+	if err := migrateConfigPatch(patch); err != nil {
+		t.Fatalf("migrateConfigPatch() failed: %v", err)
+	}
+	if patch.SchemaVersion != nil || patch.GetAdvancedSettings().AllowLocalDestination == nil {
+		t.Errorf("patch without schema version is changed")
+	}
+
+	// A patch with an old schema version is upgraded.
+	patch.SchemaVersion = proto.Uint32(0)
+	if err := migrateConfigPatch(patch); err != nil {
+		t.Fatalf("migrateConfigPatch() failed: %v", err)
+	}
+	if patch.GetSchemaVersion() != ConfigSchemaVersion {
+		t.Errorf("got schema version %d, want %d", patch.GetSchemaVersion(), ConfigSchemaVersion)
+	}
+	if patch.GetAdvancedSettings().AllowLocalDestination != nil {
+		t.Errorf("allowLocalDestination is not removed")
+	}
+}
+
+func TestLoadServerConfigMigration(t *testing.T) {
+	beforeServerTest(t)
+	backup := configBackupFilePath(cachedServerConfigFilePath, 0)
+	defer os.Remove(backup)
+
+	// Write a server config without schema version.
+	old := &pb.ServerConfig{
+		AdvancedSettings: &pb.ServerAdvancedSettings{
+			AllowLocalDestination:  proto.Bool(true),
+			MetricsLoggingInterval: proto.String("1m"),
+		},
+	}
+	b, err := proto.Marshal(old)
+	if err != nil {
+		t.Fatalf("proto.Marshal() failed: %v", err)
+	}
+	if err := os.WriteFile(cachedServerConfigFilePath, b, 0660); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	config, err := LoadServerConfig()
+	if err != nil {
+		t.Fatalf("LoadServerConfig() failed: %v", err)
+	}
+	if config.GetSchemaVersion() != ConfigSchemaVersion {
+		t.Errorf("got schema version %d, want %d", config.GetSchemaVersion(), ConfigSchemaVersion)
+	}
+	if config.GetAdvancedSettings().AllowLocalDestination != nil {
+		t.Errorf("allowLocalDestination is not removed")
+	}
+	if config.GetAdvancedSettings().GetMetricsLoggingInterval() != "1m" {
+		t.Errorf("metricsLoggingInterval is changed")
+	}
+
+	// The original config is kept in the backup file.
+	got, err := os.ReadFile(backup)
+	if err != nil {
+		t.Fatalf("os.ReadFile(%q) failed: %v", backup, err)
+	}
+	if !bytes.Equal(got, b) {
+		t.Errorf("backup file content doesn't match the original config")
+	}
+
+	// The upgraded config is stored.
+	if err := os.Remove(backup); err != nil {
+		t.Fatalf("os.Remove() failed: %v", err)
+	}
+	reloaded, err := LoadServerConfig()
+	if err != nil {
+		t.Fatalf("LoadServerConfig() failed: %v", err)
+	}
+	if !proto.Equal(config, reloaded) {
+		t.Errorf("upgraded server config is not stored")
+	}
+	if _, err := os.Stat(backup); !os.IsNotExist(err) {
+		t.Errorf("upgraded server config is migrated again")
+	}
+
+	afterServerTest(t)
+}
+
+func TestLoadClientConfigMigration(t *testing.T) {
+	beforeClientTest(t)
+
+	b, err := os.ReadFile("testdata/client_apply_config_1.json")
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	configFilePath := cachedClientConfigDir + string(os.PathSeparator) + "client_migrate.json"
+	if err := os.WriteFile(configFilePath, b, 0660); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	defer os.Remove(configFilePath)
+	backup := configBackupFilePath(configFilePath, 0)
+	defer os.Remove(backup)
+	cachedClientConfigFilePath = ""
+	if err := os.Setenv("MIERU_CONFIG_JSON_FILE", configFilePath); err != nil {
+		t.Fatalf("os.Setenv() failed: %v", err)
+	}
+	defer os.Unsetenv("MIERU_CONFIG_JSON_FILE")
+
+	config, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+	if config.GetSchemaVersion() != ConfigSchemaVersion {
+		t.Errorf("got schema version %d, want %d", config.GetSchemaVersion(), ConfigSchemaVersion)
+	}
+	if config.GetActiveProfile() != "default" {
+		t.Errorf("client config data is unexpected")
+	}
+	got, err := os.ReadFile(backup)
+	if err != nil {
+		t.Fatalf("os.ReadFile(%q) failed: %v", backup, err)
+	}
+	if !bytes.Equal(got, b) {
+		t.Errorf("backup file content doesn't match the original config")
+	}
+	reloaded, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+	if !proto.Equal(config, reloaded) {
+		t.Errorf("upgraded client config is not stored")
+	}
+
+	afterClientTest(t)
+}
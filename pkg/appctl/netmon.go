@@ -0,0 +1,40 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+
+	"github.com/enfein/mieru/v3/pkg/netmon"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+// StartClientNetworkMonitor closes the connections to proxy servers when
+// the network of the system is changed. The proxy servers of the current
+// profile are resolved again. If probeLatency is true, the latency of
+// proxy servers is measured again on the current network.
+// The returned monitor should be closed when the client stops.
+func StartClientNetworkMonitor(mux *protocol.Mux, failover *ClientFailover, probeLatency bool) *netmon.Monitor {
+	monitor := netmon.New(func() {
+		failover.OnNetworkChange()
+		mux.CloseUnderlaysOnNetworkChange()
+		if probeLatency {
+			go ProbeClientServers(context.Background(), mux)
+		}
+	})
+	monitor.Start()
+	return monitor
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/stderror"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// clientEnvOverridePrefix is the prefix of environment variables that
+// override client config fields.
+const clientEnvOverridePrefix = "MIERU_"
+
+// clientConfigOverridesRef holds the client config fields from environment
+// variables and command line flags. They override the stored client config
+// of the running client.
+var clientConfigOverridesRef atomic.Pointer[pb.ClientConfig]
+
+// configOverrideField is a config field that can be overridden.
+type configOverrideField struct {
+	// path is the field descriptors from the root message.
+	path []protoreflect.FieldDescriptor
+
+	// env is the name of environment variable without prefix,
+	// for example SOCKS5_PORT.
+	env string
+
+	// flag is the name of command line flag without "--",
+	// for example socks5-port.
+	flag string
+}
+
+// configOverrideFields returns all the fields of the message that can be
+// overridden, sorted by the environment variable name. A singular message
+// field can be overridden as a whole, or by each of its fields. If two
+// fields have the same name, the one closer to the root message wins.
+func configOverrideFields(md protoreflect.MessageDescriptor) []configOverrideField {
+	var res []configOverrideField
+	seen := make(map[string]struct{})
+	type item struct {
+		path    []protoreflect.FieldDescriptor
+		prefix  string
+		message protoreflect.MessageDescriptor
+	}
+	queue := []item{{message: md}}
+	for len(queue) > 0 {
+		it := queue[0]
+		queue = queue[1:]
+		fields := it.message.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			path := append(append([]protoreflect.FieldDescriptor{}, it.path...), fd)
+			env := it.prefix + camelToUpperSnake(fd.JSONName())
+			if _, found := seen[env]; !found {
+				seen[env] = struct{}{}
+				res = append(res, configOverrideField{
+					path: path,
+					env:  env,
+					flag: strings.ToLower(strings.ReplaceAll(env, "_", "-")),
+				})
+			}
+			if fd.Message() != nil && !fd.IsList() && !fd.IsMap() && !isRecursiveField(path) {
+				queue = append(queue, item{path: path, prefix: env + "_", message: fd.Message()})
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].env < res[j].env
+	})
+	return res
+}
+
+// isRecursiveField returns true if the message type of the last field
+// already appears in the path.
+func isRecursiveField(path []protoreflect.FieldDescriptor) bool {
+	last := path[len(path)-1].Message().FullName()
+	for _, fd := range path {
+		if fd.ContainingMessage().FullName() == last {
+			return true
+		}
+	}
+	return false
+}
+
+// camelToUpperSnake converts a camel case name to upper snake case,
+// for example socks5ListenLAN to SOCKS5_LISTEN_LAN.
+func camelToUpperSnake(s string) string {
+	r := []rune(s)
+	var sb strings.Builder
+	for i, c := range r {
+		if i > 0 && unicode.IsUpper(c) {
+			prev := r[i-1]
+			nextIsLower := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToUpper(c))
+	}
+	return sb.String()
+}
+
+// setConfigOverrideField parses the value and sets the field in the
+// message. Strings and enums are used as is. Other values, including
+// lists, maps and messages, are JSON.
+func setConfigOverrideField(m protoreflect.Message, field configOverrideField, value string) error {
+	for _, fd := range field.path[:len(field.path)-1] {
+		m = m.Mutable(fd).Message()
+	}
+	fd := field.path[len(field.path)-1]
+	raw := json.RawMessage(value)
+	if !fd.IsList() && !fd.IsMap() && (fd.Kind() == protoreflect.StringKind || fd.Kind() == protoreflect.EnumKind) {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		raw = b
+	}
+	b, err := json.Marshal(map[string]json.RawMessage{fd.JSONName(): raw})
+	if err != nil {
+		return fmt.Errorf("value %q is not valid JSON", value)
+	}
+	holder := m.New()
+	if err := protojson.Unmarshal(b, holder.Interface()); err != nil {
+		return fmt.Errorf("invalid value %q: %w", value, err)
+	}
+	if holder.Has(fd) {
+		m.Set(fd, holder.Get(fd))
+	} else {
+		// An empty list or an empty map can't override the stored value.
+		m.Clear(fd)
+	}
+	return nil
+}
+
+// ParseClientConfigOverrides returns the client config fields set by
+// environment variables with MIERU_ prefix and command line flags.
+// Environment variables use the format MIERU_SOCKS5_PORT=1080, and
+// command line flags use the format --socks5-port=1080. Flags take
+// precedence over environment variables. Unknown environment variables
+// are ignored, and unknown flags are rejected.
+func ParseClientConfigOverrides(env []string, flags []string) (*pb.ClientConfig, error) {
+	overrides := &pb.ClientConfig{}
+	fields := configOverrideFields(overrides.ProtoReflect().Descriptor())
+	byEnv := make(map[string]configOverrideField)
+	byFlag := make(map[string]configOverrideField)
+	for _, field := range fields {
+		byEnv[field.env] = field
+		byFlag[field.flag] = field
+	}
+
+	for _, kv := range env {
+		k, v, found := strings.Cut(kv, "=")
+		if !found || !strings.HasPrefix(k, clientEnvOverridePrefix) {
+			continue
+		}
+		field, ok := byEnv[strings.TrimPrefix(k, clientEnvOverridePrefix)]
+		if !ok {
+			continue
+		}
+		if err := setConfigOverrideField(overrides.ProtoReflect(), field, v); err != nil {
+			return nil, fmt.Errorf("environment variable %s: %w", k, err)
+		}
+	}
+	for _, flag := range flags {
+		k, v, found := strings.Cut(strings.TrimPrefix(flag, "--"), "=")
+		if !strings.HasPrefix(flag, "--") || !found {
+			return nil, fmt.Errorf("flag %q is not in the format --NAME=VALUE", flag)
+		}
+		field, ok := byFlag[k]
+		if !ok {
+			return nil, fmt.Errorf("flag --%s is not a client config field", k)
+		}
+		if err := setConfigOverrideField(overrides.ProtoReflect(), field, v); err != nil {
+			return nil, fmt.Errorf("flag --%s: %w", k, err)
+		}
+	}
+	return overrides, nil
+}
+
+// SetClientConfigOverrides sets the client config fields that override
+// the stored client config of the running client.
+func SetClientConfigOverrides(overrides *pb.ClientConfig) {
+	clientConfigOverridesRef.Store(overrides)
+}
+
+// LoadClientConfigWithOverrides reads client config from disk, and
+// applies the overrides set by SetClientConfigOverrides. If the client
+// config file doesn't exist, the overrides are used as the full client
+// config. The overrides are never stored to disk.
+func LoadClientConfigWithOverrides() (*pb.ClientConfig, error) {
+	overrides := clientConfigOverridesRef.Load()
+	config, err := LoadClientConfig()
+	if err == stderror.ErrFileNotExist && overrides != nil && !proto.Equal(overrides, &pb.ClientConfig{}) {
+		config, err = &pb.ClientConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if overrides != nil {
+		overrideMessage(config.ProtoReflect(), overrides.ProtoReflect())
+		log.Debugf("client config is overridden by environment variables and command line flags")
+	}
+	return config, nil
+}
+
+// overrideMessage sets the populated fields of src to dst. Singular
+// message fields are overridden field by field. Other fields, including
+// lists and maps, are replaced.
+func overrideMessage(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() != nil && !fd.IsList() && !fd.IsMap() {
+			overrideMessage(dst.Mutable(fd).Message(), v.Message())
+		} else {
+			dst.Set(fd, v)
+		}
+		return true
+	})
+}
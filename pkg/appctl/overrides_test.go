@@ -0,0 +1,164 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"testing"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/stderror"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCamelToUpperSnake(t *testing.T) {
+	testCases := map[string]string{
+		"socks5Port":          "SOCKS5_PORT",
+		"socks5ListenLAN":     "SOCKS5_LISTEN_LAN",
+		"rpcPort":             "RPC_PORT",
+		"httpProxyListenLAN":  "HTTP_PROXY_LISTEN_LAN",
+		"healthCheckInterval": "HEALTH_CHECK_INTERVAL",
+		"mtu":                 "MTU",
+	}
+	for input, want := range testCases {
+		if got := camelToUpperSnake(input); got != want {
+			t.Errorf("camelToUpperSnake(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestParseClientConfigOverrides(t *testing.T) {
+	env := []string{
+		"MIERU_SOCKS5_PORT=1080",
+		"MIERU_SOCKS5_LISTEN_LAN=true",
+		"MIERU_LOGGING_LEVEL=DEBUG",
+		"MIERU_ACTIVE_PROFILE=env",
+		"MIERU_SOCKS5_AUTHENTICATION=[{\"user\": \"alice\", \"password\": \"secret\"}]",
+		"MIERU_UNKNOWN_FIELD=1",
+		"PATH=/usr/bin",
+	}
+	flags := []string{
+		"--active-profile=flag",
+		"--rpc-port=8964",
+	}
+	got, err := ParseClientConfigOverrides(env, flags)
+	if err != nil {
+		t.Fatalf("ParseClientConfigOverrides() failed: %v", err)
+	}
+	want := &pb.ClientConfig{
+		ActiveProfile:   proto.String("flag"),
+		RpcPort:         proto.Int32(8964),
+		Socks5Port:      proto.Int32(1080),
+		Socks5ListenLAN: proto.Bool(true),
+		LoggingLevel:    pb.LoggingLevel_DEBUG.Enum(),
+		Socks5Authentication: []*pb.Auth{
+			{User: proto.String("alice"), Password: proto.String("secret")},
+		},
+	}
+	if !proto.Equal(got, want) {
+		gotJSON, _ := common.MarshalJSON(got)
+		wantJSON, _ := common.MarshalJSON(want)
+		t.Errorf("ParseClientConfigOverrides() = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestParseClientConfigOverridesNestedField(t *testing.T) {
+	got, err := ParseClientConfigOverrides([]string{"MIERU_FAILOVER_HEALTH_CHECK_INTERVAL=30s"}, nil)
+	if err != nil {
+		t.Fatalf("ParseClientConfigOverrides() failed: %v", err)
+	}
+	if got.GetFailover().GetHealthCheckInterval() != "30s" {
+		t.Errorf("failover health check interval = %q, want %q", got.GetFailover().GetHealthCheckInterval(), "30s")
+	}
+}
+
+func TestParseClientConfigOverridesReject(t *testing.T) {
+	testCases := []struct {
+		env   []string
+		flags []string
+	}{
+		{env: []string{"MIERU_SOCKS5_PORT=abc"}},
+		{env: []string{"MIERU_LOGGING_LEVEL=LOUD"}},
+		{flags: []string{"--unknown-field=1"}},
+		{flags: []string{"--socks5-port"}},
+		{flags: []string{"socks5-port=1080"}},
+	}
+	for _, tc := range testCases {
+		if _, err := ParseClientConfigOverrides(tc.env, tc.flags); err == nil {
+			t.Errorf("ParseClientConfigOverrides(%v, %v) succeeded, want error", tc.env, tc.flags)
+		}
+	}
+}
+
+func TestLoadClientConfigWithOverrides(t *testing.T) {
+	beforeClientTest(t)
+	defer afterClientTest(t)
+	defer SetClientConfigOverrides(nil)
+
+	configFile := "testdata/client_apply_config_1.json"
+	if err := ApplyJSONClientConfig(configFile); err != nil {
+		t.Fatalf("ApplyJSONClientConfig(%q) failed: %v", configFile, err)
+	}
+	overrides, err := ParseClientConfigOverrides([]string{"MIERU_SOCKS5_PORT=1081"}, []string{"--profiles=[{\"profileName\": \"flag\"}]"})
+	if err != nil {
+		t.Fatalf("ParseClientConfigOverrides() failed: %v", err)
+	}
+	SetClientConfigOverrides(overrides)
+	config, err := LoadClientConfigWithOverrides()
+	if err != nil {
+		t.Fatalf("LoadClientConfigWithOverrides() failed: %v", err)
+	}
+	if config.GetSocks5Port() != 1081 {
+		t.Errorf("socks5 port = %d, want 1081", config.GetSocks5Port())
+	}
+	if len(config.GetProfiles()) != 1 || config.GetProfiles()[0].GetProfileName() != "flag" {
+		t.Errorf("got %d profiles, want profiles replaced by the flag", len(config.GetProfiles()))
+	}
+	if config.GetRpcPort() != 1989 {
+		t.Errorf("rpc port = %d, want 1989 from stored config", config.GetRpcPort())
+	}
+
+	// Overrides are not stored.
+	stored, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+	if stored.GetSocks5Port() != 1080 || len(stored.GetProfiles()) != 1 {
+		t.Errorf("overrides are stored to client config")
+	}
+}
+
+func TestLoadClientConfigWithOverridesNoConfigFile(t *testing.T) {
+	beforeClientTest(t)
+	defer afterClientTest(t)
+	defer SetClientConfigOverrides(nil)
+
+	if err := deleteClientConfigFile(); err != nil {
+		t.Fatalf("failed to delete client config file")
+	}
+	SetClientConfigOverrides(nil)
+	if _, err := LoadClientConfigWithOverrides(); err != stderror.ErrFileNotExist {
+		t.Errorf("LoadClientConfigWithOverrides() without overrides got error %v, want %v", err, stderror.ErrFileNotExist)
+	}
+	SetClientConfigOverrides(&pb.ClientConfig{Socks5Port: proto.Int32(1080)})
+	config, err := LoadClientConfigWithOverrides()
+	if err != nil {
+		t.Fatalf("LoadClientConfigWithOverrides() failed: %v", err)
+	}
+	if config.GetSocks5Port() != 1080 {
+		t.Errorf("socks5 port = %d, want 1080", config.GetSocks5Port())
+	}
+}
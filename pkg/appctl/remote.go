@@ -0,0 +1,238 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlgrpc"
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/systemd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// RemoteManagementCalls is the number of RPCs called by administrators
+	// from the remote management endpoint.
+	RemoteManagementCalls = metrics.RegisterMetric("remote management", "Calls", metrics.COUNTER)
+
+	// RemoteManagementDenied is the number of RPCs rejected by the remote
+	// management endpoint because the client certificate is not an admin.
+	RemoteManagementDenied = metrics.RegisterMetric("remote management", "Denied", metrics.COUNTER)
+
+	// serverRemoteRPCServerRef holds a pointer to server remote management RPC server.
+	serverRemoteRPCServerRef atomic.Pointer[grpc.Server]
+)
+
+// StartServerRemoteManagement listens to the TCP port of the remote
+// management endpoint, and serves the management RPC in the background.
+// It does nothing if the endpoint is not enabled.
+func StartServerRemoteManagement(config *pb.RemoteManagement) error {
+	if config.GetPort() == 0 {
+		return nil
+	}
+	tlsConfig, err := remoteManagementServerTLSConfig(config)
+	if err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(config.GetBindIP(), strconv.Itoa(int(config.GetPort())))
+	listener, err := systemd.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen on remote management address %q failed: %w", addr, err)
+	}
+	a := &remoteAdmins{names: make(map[string]struct{})}
+	for _, name := range config.GetAdmins() {
+		a.names[name] = struct{}{}
+	}
+	grpcServer := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.MaxRecvMsgSize(MaxRecvMsgSize),
+		grpc.ChainUnaryInterceptor(a.unaryInterceptor, RPCErrorInterceptor),
+		grpc.StreamInterceptor(a.streamInterceptor),
+	)
+	appctlgrpc.RegisterServerManagementServiceServer(grpcServer, NewServerManagementService())
+	serverRemoteRPCServerRef.Store(grpcServer)
+	go func() {
+		log.Infof("mita server remote management endpoint %v is running", addr)
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Errorf("run remote management endpoint failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// stopServerRemoteManagement stops the remote management endpoint if it is running.
+func stopServerRemoteManagement() {
+	if grpcServer := serverRemoteRPCServerRef.Swap(nil); grpcServer != nil {
+		log.Infof("stopping remote management RPC server")
+		go grpcServer.GracefulStop()
+	}
+}
+
+// validateRemoteManagement validates the remote management endpoint.
+func validateRemoteManagement(config *pb.RemoteManagement) error {
+	if config.GetPort() == 0 {
+		return nil
+	}
+	if config.GetPort() < 1 || config.GetPort() > 65535 {
+		return fmt.Errorf("remote management port %d is invalid", config.GetPort())
+	}
+	if config.GetBindIP() != "" && net.ParseIP(config.GetBindIP()) == nil {
+		return fmt.Errorf("remote management bind IP %q is invalid", config.GetBindIP())
+	}
+	files := []struct {
+		name string
+		path string
+	}{
+		{"certificate file", config.GetCertificateFile()},
+		{"private key file", config.GetPrivateKeyFile()},
+		{"client CA file", config.GetClientCAFile()},
+	}
+	for _, f := range files {
+		if f.path == "" {
+			return fmt.Errorf("remote management %s is not set", f.name)
+		}
+		if !filepath.IsAbs(f.path) {
+			return fmt.Errorf("remote management %s %q is not an absolute path", f.name, f.path)
+		}
+	}
+	for _, admin := range config.GetAdmins() {
+		if admin == "" {
+			return fmt.Errorf("remote management admin name is empty")
+		}
+	}
+	return nil
+}
+
+// remoteManagementServerTLSConfig requires the clients to present
+// a certificate issued by the client CA.
+func remoteManagementServerTLSConfig(config *pb.RemoteManagement) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(config.GetCertificateFile(), config.GetPrivateKeyFile())
+	if err != nil {
+		return nil, fmt.Errorf("load remote management certificate failed: %w", err)
+	}
+	clientCAs, err := loadCertPool(config.GetClientCAFile())
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// newServerRemoteManagementRPCClient creates a ServerManagementService
+// RPC client that connects to the remote management endpoint of a mita
+// server. The client certificate, private key and the CA of the server
+// certificate are read from environment variables.
+func newServerRemoteManagementRPCClient(addr string) (appctlgrpc.ServerManagementServiceClient, error) {
+	certFile, keyFile, caFile := os.Getenv("MITA_REMOTE_CERT"), os.Getenv("MITA_REMOTE_KEY"), os.Getenv("MITA_REMOTE_CA")
+	if certFile == "" || keyFile == "" || caFile == "" {
+		return nil, fmt.Errorf("MITA_REMOTE_CERT, MITA_REMOTE_KEY and MITA_REMOTE_CA must be set to use remote management address %q", addr)
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load admin certificate failed: %w", err)
+	}
+	rootCAs, err := loadCertPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS13,
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(MaxRecvMsgSize)))
+	if err != nil {
+		return nil, fmt.Errorf("grpc.NewClient() failed: %w", err)
+	}
+	return appctlgrpc.NewServerManagementServiceClient(conn), nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile() failed: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificate is found in %q", path)
+	}
+	return pool, nil
+}
+
+// remoteAdmins authorizes the RPCs from the remote management endpoint
+// by the common name of the client certificate.
+type remoteAdmins struct {
+	names map[string]struct{} // allow all verified clients if empty
+}
+
+func (a *remoteAdmins) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *remoteAdmins) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorize returns an error if the caller is not an admin.
+// Each authorized call is logged with the admin name.
+func (a *remoteAdmins) authorize(ctx context.Context, method string) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		RemoteManagementDenied.Add(1)
+		return status.Error(codes.Unauthenticated, "peer is not found")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		RemoteManagementDenied.Add(1)
+		return status.Error(codes.Unauthenticated, "client certificate is not verified")
+	}
+	admin := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+	if len(a.names) > 0 {
+		if _, found := a.names[admin]; !found {
+			RemoteManagementDenied.Add(1)
+			log.Warnf("remote management RPC %s from %v is denied: %q is not an admin", method, p.Addr, admin)
+			return status.Errorf(codes.PermissionDenied, "%q is not an admin", admin)
+		}
+	}
+	RemoteManagementCalls.Add(1)
+	log.Infof("remote management RPC %s is called by admin %q from %v", method, admin, p.Addr)
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testCertIssuer creates certificates signed by a test CA.
+type testCertIssuer struct {
+	t      *testing.T
+	dir    string
+	caCert *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+	serial int64
+}
+
+func newTestCertIssuer(t *testing.T) *testCertIssuer {
+	i := &testCertIssuer{t: t, dir: t.TempDir()}
+	i.caCert, i.caKey = i.issue("test CA", nil, true)
+	return i
+}
+
+// issue creates a certificate. It is self-signed if the issuer has no CA yet.
+func (i *testCertIssuer) issue(commonName string, ips []net.IP, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		i.t.Fatalf("ecdsa.GenerateKey() failed: %v", err)
+	}
+	i.serial++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(i.serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		IPAddresses:  ips,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:         isCA,
+
+		BasicConstraintsValid: true,
+	}
+	if isCA {
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	parent, signer := template, key
+	if i.caCert != nil {
+		parent, signer = i.caCert, i.caKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		i.t.Fatalf("x509.CreateCertificate() failed: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		i.t.Fatalf("x509.ParseCertificate() failed: %v", err)
+	}
+	return cert, key
+}
+
+// writeFiles saves the certificate and the private key in PEM format.
+func (i *testCertIssuer) writeFiles(name string, cert *x509.Certificate, key *ecdsa.PrivateKey) (certFile, keyFile string) {
+	certFile = filepath.Join(i.dir, name+".crt")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600); err != nil {
+		i.t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	if key == nil {
+		return certFile, ""
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		i.t.Fatalf("x509.MarshalECPrivateKey() failed: %v", err)
+	}
+	keyFile = filepath.Join(i.dir, name+".key")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		i.t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestServerRemoteManagement(t *testing.T) {
+	issuer := newTestCertIssuer(t)
+	caFile, _ := issuer.writeFiles("ca", issuer.caCert, nil)
+	serverCert, serverKey := issuer.issue("mita", []net.IP{net.ParseIP("127.0.0.1")}, false)
+	serverCertFile, serverKeyFile := issuer.writeFiles("server", serverCert, serverKey)
+	adminCert, adminKey := issuer.issue("admin", nil, false)
+	adminCertFile, adminKeyFile := issuer.writeFiles("admin", adminCert, adminKey)
+	guestCert, guestKey := issuer.issue("guest", nil, false)
+	guestCertFile, guestKeyFile := issuer.writeFiles("guest", guestCert, guestKey)
+
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	config := &pb.RemoteManagement{
+		Port:            proto.Int32(int32(port)),
+		BindIP:          proto.String("127.0.0.1"),
+		CertificateFile: proto.String(serverCertFile),
+		PrivateKeyFile:  proto.String(serverKeyFile),
+		ClientCAFile:    proto.String(caFile),
+		Admins:          []string{"admin"},
+	}
+	if err := validateRemoteManagement(config); err != nil {
+		t.Fatalf("validateRemoteManagement() failed: %v", err)
+	}
+	if err := StartServerRemoteManagement(config); err != nil {
+		t.Fatalf("StartServerRemoteManagement() failed: %v", err)
+	}
+	defer stopServerRemoteManagement()
+
+	t.Setenv("MITA_REMOTE_ADDRESS", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	t.Setenv("MITA_REMOTE_CA", caFile)
+	testCases := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		wantCode codes.Code
+	}{
+		{"admin", adminCertFile, adminKeyFile, codes.OK},
+		{"not admin", guestCertFile, guestKeyFile, codes.PermissionDenied},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("MITA_REMOTE_CERT", tc.certFile)
+			t.Setenv("MITA_REMOTE_KEY", tc.keyFile)
+			client, err := NewServerManagementRPCClient()
+			if err != nil {
+				t.Fatalf("NewServerManagementRPCClient() failed: %v", err)
+			}
+			ctx, cancelFunc := context.WithTimeout(context.Background(), RPCTimeout)
+			defer cancelFunc()
+			_, err = client.GetVersion(ctx, &emptypb.Empty{})
+			if code := status.Code(err); code != tc.wantCode {
+				t.Errorf("GetVersion() got code %v, want %v: %v", code, tc.wantCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	clientStartAction     = "Run `mieru start` to start the proxy client."
+	serverStartAction     = "Run `mita start` to start the proxy service."
+	serverFixConfigAction = "Fix the server config with `mita apply config <FILE>`, then run `mita start` again."
+)
+
+// NewRPCError returns an error that carries a typed error detail
+// to the caller of the management RPC.
+func NewRPCError(code pb.ErrorCode, retriable bool, suggestedAction string, err error) error {
+	detail := &pb.ErrorDetail{
+		Code:      code.Enum(),
+		Message:   proto.String(err.Error()),
+		Retriable: proto.Bool(retriable),
+	}
+	if suggestedAction != "" {
+		detail.SuggestedAction = proto.String(suggestedAction)
+	}
+	st, e := status.New(errorCodeToGRPCCode(code), err.Error()).WithDetails(detail)
+	if e != nil {
+		return err
+	}
+	return st.Err()
+}
+
+// GetRPCErrorDetail returns the typed error detail of an error returned
+// by the management RPC. It returns nil if the error has no detail.
+func GetRPCErrorDetail(err error) *pb.ErrorDetail {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	for _, d := range st.Details() {
+		if detail, ok := d.(*pb.ErrorDetail); ok {
+			return detail
+		}
+	}
+	return nil
+}
+
+// RPCErrorInterceptor attaches a typed error detail to the errors
+// returned by the management RPC that don't have one.
+func RPCErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil || GetRPCErrorDetail(err) != nil {
+		return resp, err
+	}
+	st, _ := status.FromError(err)
+	var retriable bool
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		retriable = true
+	}
+	withDetail, e := st.WithDetails(&pb.ErrorDetail{
+		Code:      pb.ErrorCode_UNKNOWN_ERROR_CODE.Enum(),
+		Message:   proto.String(st.Message()),
+		Retriable: proto.Bool(retriable),
+	})
+	if e != nil {
+		return resp, err
+	}
+	return resp, withDetail.Err()
+}
+
+// userRPCError converts an error of user management to an RPC error.
+// Errors that already have a status code are returned unchanged.
+func userRPCError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return NewRPCError(pb.ErrorCode_INTERNAL_ERROR, false, "", err)
+}
+
+func errorCodeToGRPCCode(code pb.ErrorCode) codes.Code {
+	switch code {
+	case pb.ErrorCode_INVALID_CONFIG, pb.ErrorCode_FEATURE_NOT_ENABLED:
+		return codes.FailedPrecondition
+	case pb.ErrorCode_SERVICE_UNAVAILABLE:
+		return codes.Unavailable
+	case pb.ErrorCode_INTERNAL_ERROR:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
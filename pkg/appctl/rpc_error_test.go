@@ -0,0 +1,114 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRPCErrorDetail(t *testing.T) {
+	err := NewRPCError(pb.ErrorCode_SERVICE_UNAVAILABLE, true, serverStartAction, fmt.Errorf("server multiplexier is unavailable"))
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("status code = %v, want %v", status.Code(err), codes.Unavailable)
+	}
+
+	// The detail can be found from a wrapped error.
+	detail := GetRPCErrorDetail(fmt.Errorf("get sessions failed: %w", err))
+	if detail == nil {
+		t.Fatalf("GetRPCErrorDetail() returned nil")
+	}
+	if detail.GetCode() != pb.ErrorCode_SERVICE_UNAVAILABLE {
+		t.Errorf("code = %v, want %v", detail.GetCode(), pb.ErrorCode_SERVICE_UNAVAILABLE)
+	}
+	if detail.GetMessage() != "server multiplexier is unavailable" {
+		t.Errorf("message = %q, want %q", detail.GetMessage(), "server multiplexier is unavailable")
+	}
+	if !detail.GetRetriable() {
+		t.Errorf("retriable = false, want true")
+	}
+	if detail.GetSuggestedAction() != serverStartAction {
+		t.Errorf("suggested action = %q, want %q", detail.GetSuggestedAction(), serverStartAction)
+	}
+
+	if GetRPCErrorDetail(fmt.Errorf("plain error")) != nil {
+		t.Errorf("GetRPCErrorDetail() of a plain error is not nil")
+	}
+	if GetRPCErrorDetail(nil) != nil {
+		t.Errorf("GetRPCErrorDetail(nil) is not nil")
+	}
+}
+
+func TestRPCErrorInterceptor(t *testing.T) {
+	testCases := []struct {
+		name          string
+		err           error
+		wantCode      pb.ErrorCode
+		wantRetriable bool
+	}{
+		{
+			name:     "plain error",
+			err:      fmt.Errorf("something is wrong"),
+			wantCode: pb.ErrorCode_UNKNOWN_ERROR_CODE,
+		},
+		{
+			name:          "status error",
+			err:           status.Error(codes.Unavailable, "try again"),
+			wantCode:      pb.ErrorCode_UNKNOWN_ERROR_CODE,
+			wantRetriable: true,
+		},
+		{
+			name:     "typed error",
+			err:      NewRPCError(pb.ErrorCode_FEATURE_NOT_ENABLED, false, "enable it", fmt.Errorf("not enabled")),
+			wantCode: pb.ErrorCode_FEATURE_NOT_ENABLED,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := func(ctx context.Context, req any) (any, error) {
+				return nil, tc.err
+			}
+			_, err := RPCErrorInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+			detail := GetRPCErrorDetail(err)
+			if detail == nil {
+				t.Fatalf("GetRPCErrorDetail() returned nil")
+			}
+			if detail.GetCode() != tc.wantCode {
+				t.Errorf("code = %v, want %v", detail.GetCode(), tc.wantCode)
+			}
+			if detail.GetRetriable() != tc.wantRetriable {
+				t.Errorf("retriable = %v, want %v", detail.GetRetriable(), tc.wantRetriable)
+			}
+			if status.Code(err) != status.Code(tc.err) {
+				t.Errorf("status code = %v, want %v", status.Code(err), status.Code(tc.err))
+			}
+		})
+	}
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	}
+	resp, err := RPCErrorInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	if err != nil || resp != "ok" {
+		t.Errorf("RPCErrorInterceptor() = %v, %v, want ok, nil", resp, err)
+	}
+}
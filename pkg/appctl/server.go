@@ -17,12 +17,22 @@ package appctl
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"io"
+	"math/big"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -30,15 +40,23 @@ import (
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlgrpc"
 	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/blocklist"
 	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/geoip"
 	"github.com/enfein/mieru/v3/pkg/log"
 	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/minisign"
+	"github.com/enfein/mieru/v3/pkg/privilege"
 	"github.com/enfein/mieru/v3/pkg/protocol"
+	"github.com/enfein/mieru/v3/pkg/replay"
 	"github.com/enfein/mieru/v3/pkg/socks5"
 	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/tracing"
 	"github.com/enfein/mieru/v3/pkg/version"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -54,6 +72,10 @@ var (
 	// serverIOLock is required to load server config and store server config.
 	serverIOLock sync.Mutex
 
+	// serverConfigUpdateLock serializes the load, modification and store
+	// of server config, so concurrent updates are not lost.
+	serverConfigUpdateLock sync.Mutex
+
 	// serverRPCServerRef holds a pointer to server RPC server.
 	serverRPCServerRef atomic.Pointer[grpc.Server]
 
@@ -62,6 +84,38 @@ var (
 
 	// serverMuxRef holds a pointer to server multiplexier.
 	serverMuxRef atomic.Pointer[protocol.Mux]
+
+	// serverMaintenanceRef holds a pointer to server maintenance window.
+	serverMaintenanceRef atomic.Pointer[ServerMaintenance]
+
+	// serverCountryStatsRef holds a pointer to server country traffic statistics.
+	serverCountryStatsRef atomic.Pointer[metrics.DestinationStats]
+
+	// serverBlocklistRef holds a pointer to server blocklist.
+	serverBlocklistRef atomic.Pointer[blocklist.Blocklist]
+
+	// serverAuditLogRef holds a pointer to server audit log.
+	serverAuditLogRef atomic.Pointer[socks5.AuditLog]
+
+	// serverLogFileRef holds a pointer to server log file.
+	serverLogFileRef atomic.Pointer[log.RotatingFile]
+)
+
+const (
+	// ServerDataDir is the directory to store server data, e.g. metrics.
+	ServerDataDir = "/var/lib/mita"
+
+	// serverCountryTrafficHours is the number of hours to keep
+	// the server country traffic statistics.
+	serverCountryTrafficHours = 7 * 24
+
+	// defaultReplayCacheDumpInterval is the default interval to save
+	// the replay caches to disk.
+	defaultReplayCacheDumpInterval = time.Minute
+
+	// minBlocklistRefreshInterval is the minimum interval to download
+	// blocklists from URLs again.
+	minBlocklistRefreshInterval = 10 * time.Minute
 )
 
 func SetServerRPCServerRef(server *grpc.Server) {
@@ -76,6 +130,37 @@ func SetServerMuxRef(mux *protocol.Mux) {
 	serverMuxRef.Store(mux)
 }
 
+func SetServerMaintenanceRef(maintenance *ServerMaintenance) {
+	serverMaintenanceRef.Store(maintenance)
+}
+
+func SetServerCountryStatsRef(stats *metrics.DestinationStats) {
+	serverCountryStatsRef.Store(stats)
+}
+
+func SetServerBlocklistRef(b *blocklist.Blocklist) {
+	serverBlocklistRef.Store(b)
+}
+
+func SetServerAuditLogRef(a *socks5.AuditLog) {
+	serverAuditLogRef.Store(a)
+}
+
+// SetServerLogFormat sets the formatter of server logs.
+// Environment variable MITA_LOG_NO_TIMESTAMP removes the time from
+// text logs, because systemd journal already records it.
+func SetServerLogFormat(format pb.LogFormat) {
+	if format == pb.LogFormat_LOG_FORMAT_JSON {
+		log.SetFormatter(&log.JSONFormatter{Component: "mita"})
+		return
+	}
+	if _, found := os.LookupEnv("MITA_LOG_NO_TIMESTAMP"); found {
+		log.SetFormatter(&log.DaemonFormatter{NoTimestamp: true})
+	} else {
+		log.SetFormatter(&log.DaemonFormatter{})
+	}
+}
+
 // ServerUDS returns the UNIX domain socket that mita server
 // is listening to RPC requests.
 func ServerUDS() string {
@@ -100,15 +185,19 @@ func (s *serverManagementService) Start(ctx context.Context, req *emptypb.Empty)
 	log.Infof("received Start request from RPC caller")
 	config, err := LoadServerConfig()
 	if err != nil {
-		return &emptypb.Empty{}, fmt.Errorf("LoadServerConfig() failed: %w", err)
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, "Set the server config with `mita apply config <FILE>`, then run `mita start` again.", fmt.Errorf("LoadServerConfig() failed: %w", err))
 	}
 	if err = ValidateFullServerConfig(config); err != nil {
-		return &emptypb.Empty{}, fmt.Errorf("ValidateFullServerConfig() failed: %w", err)
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, fmt.Errorf("ValidateFullServerConfig() failed: %w", err))
 	}
 	loggingLevel := config.GetLoggingLevel().String()
 	if loggingLevel != pb.LoggingLevel_DEFAULT.String() {
 		log.SetLevel(loggingLevel)
 	}
+	SetServerLogFormat(config.GetLogFormat())
+	if err := SetServerLogFile(config.GetLogFile()); err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
 	if socks5ServerRef.Load() != nil {
 		log.Infof("socks5 server already exist")
 		return &emptypb.Empty{}, nil
@@ -116,17 +205,57 @@ func (s *serverManagementService) Start(ctx context.Context, req *emptypb.Empty)
 
 	SetAppStatus(pb.AppStatus_STARTING)
 
-	mux := protocol.NewMux(false).SetServerUsers(UserListToMap(config.GetUsers()))
+	if err := ApplyReplayCacheConfig(config.GetAdvancedSettings().GetReplayCache()); err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
+	mux := protocol.NewMux(false).
+		SetServerUsers(UserListToMap(config.GetUsers())).
+		SetServerUserGroups(UserGroupsByUserName(config.GetUserGroups())).
+		SetServerOverloadLimits(appctlcommon.OverloadLimitsFromConfig(config.GetAdvancedSettings())).
+		SetServerAcceptLimit(appctlcommon.AcceptLimitFromConfig(config.GetAdvancedSettings().GetAcceptLimit())).
+		SetKeyRotation(appctlcommon.KeyRotationFromConfig(config.GetKeyRotation()))
 	SetServerMuxRef(mux)
 	mtu := common.DefaultMTU
 	if config.GetMtu() != 0 {
 		mtu = int(config.GetMtu())
 	}
-	endpoints, err := PortBindingsToUnderlayProperties(config.GetPortBindings(), mtu)
+	endpoints, err := PortBindingsToUnderlayProperties(config.GetPortBindings(), mtu, config.GetWebSocket(), config.GetTlsCamouflage(), config.GetDecoy(), config.GetProxyProtocol())
+	if err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
+	endpoints, err = ServerSIP003Endpoints(config, endpoints)
 	if err != nil {
-		return &emptypb.Empty{}, err
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
+	if err := mux.SetServerPortKnocking(appctlcommon.PortKnockingOptionsFromConfig(config.GetPortKnocking())); err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
 	}
 	mux.SetEndpoints(endpoints)
+	udpRelay, err := UDPRelayToSocks5(config.GetUdpRelay())
+	if err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
+	countryStats, geoIP, err := NewServerCountryStats(config.GetCountryTraffic())
+	if err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
+	SetServerCountryStatsRef(countryStats)
+	egressGeoIP, egressGeosite, err := appctlcommon.LoadGeoDatabases(config.GetGeoDatabases())
+	if err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
+	serverBlocklist := blocklist.New()
+	if err := UpdateServerBlocklist(serverBlocklist, config.GetBlocklist()); err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
+	auditLog, err := NewServerAuditLog(config.GetAuditLog())
+	if err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
+	clusterSync, err := newServerClusterSync(config.GetCluster())
+	if err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, serverFixConfigAction, err)
+	}
 
 	// Create the egress socks5 server.
 	socks5Config := &socks5.Config{
@@ -135,14 +264,26 @@ func (s *serverManagementService) Start(ctx context.Context, req *emptypb.Empty)
 		},
 		DualStackPreference: common.DualStackPreference(config.GetDns().GetDualStack()),
 		Egress:              config.GetEgress(),
+		EnableTestEndpoints: config.GetAdvancedSettings().GetEnableTestEndpoints(),
 		HandshakeTimeout:    10 * time.Second,
 		Users:               UserListToMap(config.GetUsers()),
+		UserGroups:          UserGroupsByUserName(config.GetUserGroups()),
+		UDPRelay:            udpRelay,
+		CountryStats:        countryStats,
+		GeoIP:               geoIP,
+		EgressGeoIP:         egressGeoIP,
+		EgressGeosite:       egressGeosite,
+		Blocklist:           serverBlocklist,
+		AuditLog:            auditLog,
 	}
 	socks5Server, err := socks5.New(socks5Config)
 	if err != nil {
 		return &emptypb.Empty{}, fmt.Errorf(stderror.CreateSocks5ServerFailedErr, err)
 	}
 	SetSocks5Server(socks5Server)
+	SetServerBlocklistRef(serverBlocklist)
+	SetServerAuditLogRef(auditLog)
+	serverBlocklist.Start()
 
 	// Run the egress socks5 server in the background.
 	var initProxyTasks sync.WaitGroup
@@ -162,6 +303,14 @@ func (s *serverManagementService) Start(ctx context.Context, req *emptypb.Empty)
 
 	initProxyTasks.Wait()
 
+	if err := DropServerPrivileges(config.GetDropPrivileges(), mux); err != nil {
+		// Don't keep running with the privileges that should be dropped.
+		log.Fatalf("Failed to drop privileges: %v", err)
+	}
+	if err := SandboxServer(config); err != nil {
+		log.Fatalf("Failed to apply sandbox: %v", err)
+	}
+
 	if config.GetAdvancedSettings().GetMetricsLoggingInterval() != "" {
 		metricsDuration, err := time.ParseDuration(config.GetAdvancedSettings().GetMetricsLoggingInterval())
 		if err != nil {
@@ -174,6 +323,21 @@ func (s *serverManagementService) Start(ctx context.Context, req *emptypb.Empty)
 	}
 	metrics.EnableLogging()
 
+	if config.GetAdvancedSettings().GetOtlpTraceEndpoint() != "" {
+		exporter, err := tracing.NewExporter(config.GetAdvancedSettings().GetOtlpTraceEndpoint(), "mita")
+		if err != nil {
+			log.Warnf("Failed to create OpenTelemetry trace exporter: %v", err)
+		} else {
+			tracing.SetExporter(exporter)
+		}
+	}
+
+	if maintenance := serverMaintenanceRef.Load(); maintenance != nil {
+		maintenance.Update(config)
+	}
+
+	setServerClusterSync(clusterSync)
+
 	SetAppStatus(pb.AppStatus_RUNNING)
 	log.Infof("completed Start request from RPC caller")
 	return &emptypb.Empty{}, nil
@@ -191,6 +355,20 @@ func (s *serverManagementService) Stop(ctx context.Context, req *emptypb.Empty)
 	} else {
 		log.Infof("active socks5 servers not found")
 	}
+	if serverBlocklist := serverBlocklistRef.Load(); serverBlocklist != nil {
+		serverBlocklist.Close()
+		SetServerBlocklistRef(nil)
+	}
+	if auditLog := serverAuditLogRef.Load(); auditLog != nil {
+		auditLog.Close()
+		SetServerAuditLogRef(nil)
+	}
+	CloseServerSIP003Plugins()
+	stopServerClusterSync()
+	if err := replay.DumpNow(); err != nil {
+		log.Debugf("Replay cache DumpNow() failed: %v", err)
+	}
+	tracing.SetExporter(nil)
 	SetAppStatus(pb.AppStatus_IDLE)
 	log.Infof("completed Stop request from RPC caller")
 	return &emptypb.Empty{}, nil
@@ -217,36 +395,8 @@ func (s *serverManagementService) SetConfig(ctx context.Context, req *pb.ServerC
 
 func (s *serverManagementService) Reload(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
 	log.Infof("received Reload request from RPC caller")
-	config, err := LoadServerConfig()
-	if err != nil {
-		return &emptypb.Empty{}, fmt.Errorf("LoadServerConfig() failed: %w", err)
-	}
-	if err = ValidateFullServerConfig(config); err != nil {
-		return &emptypb.Empty{}, fmt.Errorf("ValidateFullServerConfig() failed: %w", err)
-	}
-
-	// Adjust loggingLevel.
-	// This needs to happen before adjusting other settings.
-	loggingLevel := config.GetLoggingLevel().String()
-	if loggingLevel != pb.LoggingLevel_DEFAULT.String() {
-		log.SetLevel(loggingLevel)
-	}
-
-	mux := serverMuxRef.Load()
-	if mux != nil {
-		// Adjust portBindings.
-		mtu := common.DefaultMTU
-		if config.GetMtu() != 0 {
-			mtu = int(config.GetMtu())
-		}
-		endpoints, err := PortBindingsToUnderlayProperties(config.GetPortBindings(), mtu)
-		if err != nil {
-			return &emptypb.Empty{}, err
-		}
-		mux.SetEndpoints(endpoints)
-
-		// Adjust users.
-		mux.SetServerUsers(UserListToMap(config.GetUsers()))
+	if err := ReloadServerConfig(); err != nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_INVALID_CONFIG, false, "Fix the server config with `mita apply config <FILE>`, then run `mita reload` again.", err)
 	}
 	log.Infof("completed Reload request from RPC caller")
 	return &emptypb.Empty{}, nil
@@ -264,11 +414,24 @@ func (s *serverManagementService) Exit(ctx context.Context, req *emptypb.Empty)
 	} else {
 		log.Infof("active socks5 servers not found")
 	}
+	if serverBlocklist := serverBlocklistRef.Load(); serverBlocklist != nil {
+		serverBlocklist.Close()
+		SetServerBlocklistRef(nil)
+	}
+	if auditLog := serverAuditLogRef.Load(); auditLog != nil {
+		auditLog.Close()
+		SetServerAuditLogRef(nil)
+	}
+	CloseServerSIP003Plugins()
+	stopServerClusterSync()
 	SetAppStatus(pb.AppStatus_IDLE)
 
 	grpcServer := serverRPCServerRef.Load()
 	if grpcServer != nil {
 		log.Infof("stopping RPC server")
+		stopFollowLogs()
+		stopServerRemoteManagement()
+		stopServerManagementGateway()
 		go grpcServer.GracefulStop()
 	} else {
 		log.Infof("RPC server reference not found")
@@ -288,11 +451,23 @@ func (s *serverManagementService) GetMetrics(ctx context.Context, req *emptypb.E
 func (s *serverManagementService) GetSessionInfoList(context.Context, *emptypb.Empty) (*pb.SessionInfoList, error) {
 	mux := serverMuxRef.Load()
 	if mux == nil {
-		return &pb.SessionInfoList{}, fmt.Errorf("server multiplexier is unavailable")
+		return &pb.SessionInfoList{}, NewRPCError(pb.ErrorCode_SERVICE_UNAVAILABLE, true, serverStartAction, fmt.Errorf("server multiplexier is unavailable"))
 	}
 	return mux.ExportSessionInfoList(), nil
 }
 
+func (s *serverManagementService) KillSession(ctx context.Context, req *pb.KillSessionRequest) (*emptypb.Empty, error) {
+	mux := serverMuxRef.Load()
+	if mux == nil {
+		return &emptypb.Empty{}, NewRPCError(pb.ErrorCode_SERVICE_UNAVAILABLE, true, serverStartAction, fmt.Errorf("server multiplexier is unavailable"))
+	}
+	if mux.CloseSession(req.GetId()) == 0 {
+		return &emptypb.Empty{}, status.Errorf(codes.NotFound, "session %d is not found", req.GetId())
+	}
+	log.Infof("session %d is killed by RPC caller", req.GetId())
+	return &emptypb.Empty{}, nil
+}
+
 func (s *serverManagementService) GetUsers(context.Context, *emptypb.Empty) (*pb.UserWithMetricsList, error) {
 	config, err := LoadServerConfig()
 	if err != nil {
@@ -312,6 +487,87 @@ func (s *serverManagementService) GetUsers(context.Context, *emptypb.Empty) (*pb
 	return &pb.UserWithMetricsList{Items: items}, nil
 }
 
+func (s *serverManagementService) AddUser(ctx context.Context, req *pb.User) (*emptypb.Empty, error) {
+	log.Infof("received AddUser request from RPC caller")
+	if err := AddServerUser(req); err != nil {
+		return &emptypb.Empty{}, userRPCError(err)
+	}
+	log.Infof("added user %q", req.GetName())
+	return &emptypb.Empty{}, nil
+}
+
+func (s *serverManagementService) UpdateUser(ctx context.Context, req *pb.User) (*emptypb.Empty, error) {
+	log.Infof("received UpdateUser request from RPC caller")
+	if err := UpdateServerUser(req); err != nil {
+		return &emptypb.Empty{}, userRPCError(err)
+	}
+	log.Infof("updated user %q", req.GetName())
+	return &emptypb.Empty{}, nil
+}
+
+func (s *serverManagementService) DeleteUsers(ctx context.Context, req *pb.UserNameList) (*emptypb.Empty, error) {
+	log.Infof("received DeleteUsers request from RPC caller")
+	if err := DeleteServerUsers(req.GetNames()); err != nil {
+		return &emptypb.Empty{}, userRPCError(err)
+	}
+	log.Infof("deleted users %v", req.GetNames())
+	return &emptypb.Empty{}, nil
+}
+
+func (s *serverManagementService) GetUserGroups(context.Context, *emptypb.Empty) (*pb.UserGroupWithMetricsList, error) {
+	config, err := LoadServerConfig()
+	if err != nil {
+		return &pb.UserGroupWithMetricsList{}, fmt.Errorf("LoadServerConfig() failed: %w", err)
+	}
+	var items []*pb.UserGroupWithMetrics
+	for _, group := range config.GetUserGroups() {
+		item := &pb.UserGroupWithMetrics{
+			Group: group,
+		}
+		for _, gm := range metrics.GetMetricsForUserGroup(group.GetName()) {
+			item.Metrics = append(item.Metrics, metrics.ToMetricPB(gm))
+		}
+		items = append(items, item)
+	}
+	return &pb.UserGroupWithMetricsList{Items: items}, nil
+}
+
+func (s *serverManagementService) GetCountryTraffic(context.Context, *emptypb.Empty) (*pb.CountryTrafficList, error) {
+	stats := serverCountryStatsRef.Load()
+	if stats == nil {
+		return &pb.CountryTrafficList{}, NewRPCError(pb.ErrorCode_FEATURE_NOT_ENABLED, false, "Set countryTraffic in the server config, then restart the proxy service with `mita stop` and `mita start`.", fmt.Errorf("country traffic statistics is not enabled"))
+	}
+	list := &pb.CountryTrafficList{}
+	for _, t := range stats.Top(0) {
+		list.Items = append(list.Items, &pb.CountryTraffic{
+			Country:       proto.String(t.Destination),
+			UploadBytes:   proto.Int64(t.UploadBytes),
+			DownloadBytes: proto.Int64(t.DownloadBytes),
+		})
+	}
+	return list, nil
+}
+
+func (s *serverManagementService) GetPortBindings(context.Context, *emptypb.Empty) (*pb.PortBindingStatusList, error) {
+	mux := serverMuxRef.Load()
+	if mux == nil {
+		return &pb.PortBindingStatusList{}, NewRPCError(pb.ErrorCode_SERVICE_UNAVAILABLE, true, serverStartAction, fmt.Errorf("server multiplexier is unavailable"))
+	}
+	return mux.ExportPortBindingStatusList(), nil
+}
+
+func (s *serverManagementService) RotateKeys(context.Context, *emptypb.Empty) (*pb.KeyEpoch, error) {
+	mux := serverMuxRef.Load()
+	if mux == nil {
+		return &pb.KeyEpoch{}, NewRPCError(pb.ErrorCode_SERVICE_UNAVAILABLE, true, serverStartAction, fmt.Errorf("server multiplexier is unavailable"))
+	}
+	epoch, err := mux.RotateKeys()
+	if err != nil {
+		return &pb.KeyEpoch{}, NewRPCError(pb.ErrorCode_FEATURE_NOT_ENABLED, false, "Set keyRotation in the server config with `mita apply config <FILE>`.", err)
+	}
+	return &pb.KeyEpoch{Epoch: proto.Int64(epoch)}, nil
+}
+
 func (s *serverManagementService) GetThreadDump(ctx context.Context, req *emptypb.Empty) (*pb.ThreadDump, error) {
 	return &pb.ThreadDump{ThreadDump: proto.String(common.GetAllStackTrace())}, nil
 }
@@ -347,13 +603,22 @@ func (s *serverManagementService) GetVersion(ctx context.Context, req *emptypb.E
 	}, nil
 }
 
+func (s *serverManagementService) FollowLogs(req *pb.FollowLogsRequest, stream appctlgrpc.ServerManagementService_FollowLogsServer) error {
+	return followLogs(req, stream)
+}
+
 // NewServerManagementService creates a new ServerManagementService RPC server.
 func NewServerManagementService() *serverManagementService {
 	return &serverManagementService{}
 }
 
 // NewServerManagementRPCClient creates a new ServerManagementService RPC client.
+// If environment variable MITA_REMOTE_ADDRESS is set, it connects to
+// the remote management endpoint at that address.
 func NewServerManagementRPCClient() (appctlgrpc.ServerManagementServiceClient, error) {
+	if addr, found := os.LookupEnv("MITA_REMOTE_ADDRESS"); found {
+		return newServerRemoteManagementRPCClient(addr)
+	}
 	rpcAddr := "unix://" + ServerUDS()
 	conn, err := grpc.NewClient(rpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(MaxRecvMsgSize)))
 	if err != nil {
@@ -413,6 +678,51 @@ func GetJSONServerConfig() (string, error) {
 	return string(b), nil
 }
 
+// RedactServerConfig returns a copy of the server config, where the
+// passwords and tokens are replaced by RedactedSecret.
+func RedactServerConfig(config *pb.ServerConfig) *pb.ServerConfig {
+	redacted := proto.Clone(config).(*pb.ServerConfig)
+	forEachServerConfigSecret(redacted, func(secret *string) error {
+		*secret = RedactedSecret
+		return nil
+	})
+	return redacted
+}
+
+// forEachServerConfigSecret calls f with each password and token
+// in the server config. Empty secrets are skipped.
+func forEachServerConfigSecret(config *pb.ServerConfig, f func(secret *string) error) error {
+	var secrets []*string
+	for _, user := range config.GetUsers() {
+		secrets = append(secrets, user.Password, user.HashedPassword)
+	}
+	for _, proxy := range config.GetEgress().GetProxies() {
+		if auth := proxy.GetSocks5Authentication(); auth != nil {
+			secrets = append(secrets, auth.Password)
+		}
+	}
+	if gateway := config.GetManagementGateway(); gateway != nil {
+		for i := range gateway.Tokens {
+			secrets = append(secrets, &gateway.Tokens[i])
+		}
+		for i := range gateway.ClusterTokens {
+			secrets = append(secrets, &gateway.ClusterTokens[i])
+		}
+	}
+	if cluster := config.GetCluster(); cluster != nil {
+		secrets = append(secrets, cluster.Token)
+	}
+	for _, secret := range secrets {
+		if secret == nil || *secret == "" {
+			continue
+		}
+		if err := f(secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // LoadServerConfig reads server config from disk.
 func LoadServerConfig() (*pb.ServerConfig, error) {
 	serverIOLock.Lock()
@@ -453,6 +763,16 @@ func LoadServerConfig() (*pb.ServerConfig, error) {
 	default:
 		return nil, fmt.Errorf("config file type is invalid")
 	}
+	if version := s.GetSchemaVersion(); version > ConfigSchemaVersion {
+		log.Warnf("server config is stored by a newer version of mieru with schema version %d, some settings may be ignored", version)
+	} else if version < ConfigSchemaVersion {
+		if err := migrateServerConfig(s); err != nil {
+			return nil, fmt.Errorf("migrateServerConfig() failed: %w", err)
+		}
+		if err := rewriteMigratedConfigFile(fileName, fileType, b, version, s); err != nil {
+			log.Warnf("failed to store upgraded server config: %v", err)
+		}
+	}
 
 	return s, nil
 }
@@ -466,6 +786,7 @@ func StoreServerConfig(config *pb.ServerConfig) error {
 		return fmt.Errorf("ServerConfig is nil")
 	}
 	config.Users = HashUserPasswords(config.GetUsers(), false)
+	config.SchemaVersion = proto.Uint32(ConfigSchemaVersion)
 
 	fileName, fileType, err := serverConfigFilePath()
 	if err != nil {
@@ -489,68 +810,279 @@ func StoreServerConfig(config *pb.ServerConfig) error {
 		return fmt.Errorf("config file type is invalid")
 	}
 
-	err = os.WriteFile(fileName, b, 0660)
+	// Write to a temporary file and rename it, so the server never reads
+	// a partially written config file.
+	tmp, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".tmp*")
 	if err != nil {
-		return fmt.Errorf("os.WriteFile(%q) failed: %w", fileName, err)
+		return fmt.Errorf("os.CreateTemp() failed: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Write() failed: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Sync() failed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Close() failed: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0660); err != nil {
+		return fmt.Errorf("os.Chmod() failed: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), fileName); err != nil {
+		return fmt.Errorf("os.Rename() failed: %w", err)
 	}
 	return nil
 }
 
-// ApplyJSONServerConfig applies user provided JSON server config from path.
+// ApplyJSONServerConfig applies user provided server config from path.
+// The file can be JSON, YAML or TOML.
 func ApplyJSONServerConfig(path string) error {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("os.ReadFile(%q) failed: %w", path, err)
 	}
 	s := &pb.ServerConfig{}
-	if err = common.UnmarshalJSON(b, s); err != nil {
-		return fmt.Errorf("common.UnmarshalJSON() failed: %w", err)
+	if err = common.UnmarshalConfig(path, b, s); err != nil {
+		return fmt.Errorf("common.UnmarshalConfig() failed: %w", err)
+	}
+	if err = migrateConfigPatch(s); err != nil {
+		return fmt.Errorf("migrateConfigPatch() failed: %w", err)
 	}
-	if err := ValidateServerConfigPatch(s); err != nil {
+	return applyServerConfigPatch(s)
+}
+
+// applyServerConfigPatch validates the patch, merges it into
+// the server config on disk, and stores the result.
+func applyServerConfigPatch(patch *pb.ServerConfig) error {
+	if err := ValidateServerConfigPatch(patch); err != nil {
 		return fmt.Errorf("ValidateServerConfigPatch() failed: %w", err)
 	}
+	_, err := updateServerConfig(func(config *pb.ServerConfig) error {
+		if err := mergeServerConfig(config, patch); err != nil {
+			return fmt.Errorf("mergeServerConfig() failed: %w", err)
+		}
+		return nil
+	})
+	return err
+}
+
+// updateServerConfig loads the server config from disk, modifies it with
+// the update function, and stores the result if it is valid. The updated
+// server config is returned. An invalid result is reported with
+// the InvalidArgument status code.
+func updateServerConfig(update func(config *pb.ServerConfig) error) (*pb.ServerConfig, error) {
+	serverConfigUpdateLock.Lock()
+	defer serverConfigUpdateLock.Unlock()
+
 	config, err := LoadServerConfig()
 	if err != nil {
-		return fmt.Errorf("LoadServerConfig() failed: %w", err)
+		return nil, fmt.Errorf("LoadServerConfig() failed: %w", err)
 	}
-	if err = mergeServerConfig(config, s); err != nil {
-		return fmt.Errorf("mergeServerConfig() failed: %w", err)
+	if err = update(config); err != nil {
+		return nil, err
 	}
 	if err = ValidateFullServerConfig(config); err != nil {
-		return fmt.Errorf("ValidateFullServerConfig() failed: %w", err)
+		return nil, status.Errorf(codes.InvalidArgument, "ValidateFullServerConfig() failed: %v", err)
 	}
 	if err = StoreServerConfig(config); err != nil {
-		return fmt.Errorf("StoreServerConfig() failed: %w", err)
+		return nil, fmt.Errorf("StoreServerConfig() failed: %w", err)
+	}
+	return config, nil
+}
+
+// AddServerUser adds a user to the server config, and applies it to
+// the running proxy. The user must not exist.
+func AddServerUser(user *pb.User) error {
+	return setServerUser(user, true, false)
+}
+
+// UpdateServerUser replaces the settings of an existing user in the
+// server config, and applies it to the running proxy.
+func UpdateServerUser(user *pb.User) error {
+	return setServerUser(user, false, true)
+}
+
+// setServerUser stores the user in the server config. If create is false,
+// the user must exist. If replace is false, the user must not exist.
+func setServerUser(user *pb.User, create, replace bool) error {
+	if user.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "user name is not set")
+	}
+	config, err := updateServerConfig(func(config *pb.ServerConfig) error {
+		for i, u := range config.GetUsers() {
+			if u.GetName() == user.GetName() {
+				if !replace {
+					return status.Errorf(codes.AlreadyExists, "user %q already exists", user.GetName())
+				}
+				config.Users[i] = user
+				return nil
+			}
+		}
+		if !create {
+			return status.Errorf(codes.NotFound, "user %q is not found", user.GetName())
+		}
+		config.Users = append(config.Users, user)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	applyServerUsers(config)
 	return nil
 }
 
-// DeleteServerUsers deletes the list of users from server config.
+// DeleteServerUsers deletes the list of users from server config,
+// and applies the remaining users to the running proxy.
+// The users that don't exist are ignored.
 func DeleteServerUsers(names []string) error {
+	config, err := updateServerConfig(func(config *pb.ServerConfig) error {
+		users := config.GetUsers()
+		remaining := make([]*pb.User, 0)
+		// The complexity of the following algorithm is O(total_users * users_to_delete).
+		// This seems to be high, however in reality the number of users to delete is typically 1,
+		// so it is faster than using a set to find the difference, then sort the users by name.
+		for _, user := range users {
+			shouldDelete := false
+			for _, toDelete := range names {
+				if user.GetName() == toDelete {
+					shouldDelete = true
+					break
+				}
+			}
+			if !shouldDelete {
+				remaining = append(remaining, user)
+			}
+		}
+		config.Users = remaining
+		RemoveUsersFromGroups(config.GetUserGroups(), names)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	applyServerUsers(config)
+	return nil
+}
+
+// applyServerUsers applies the users and user groups to the running proxy.
+// Established connections of deleted users are not closed.
+func applyServerUsers(config *pb.ServerConfig) {
+	if mux := serverMuxRef.Load(); mux != nil {
+		mux.SetServerUsers(UserListToMap(config.GetUsers()))
+		mux.SetServerUserGroups(UserGroupsByUserName(config.GetUserGroups()))
+	}
+	if socks5Server := socks5ServerRef.Load(); socks5Server != nil {
+		socks5Server.SetUsers(UserListToMap(config.GetUsers()))
+		socks5Server.SetUserGroups(UserGroupsByUserName(config.GetUserGroups()))
+	}
+}
+
+// ReloadServerConfig reads the server config from disk, and applies
+// the logging level, port bindings, users, egress, blocklist, overload
+// limits, maintenance window and cluster synchronization to the running proxy.
+// Only the listeners of changed port bindings are restarted, so sessions
+// from other port bindings are not impacted.
+func ReloadServerConfig() error {
 	config, err := LoadServerConfig()
 	if err != nil {
 		return fmt.Errorf("LoadServerConfig() failed: %w", err)
 	}
-	users := config.GetUsers()
-	remaining := make([]*pb.User, 0)
-	// The complexity of the following algorithm is O(total_users * users_to_delete).
-	// This seems to be high, however in reality the number of users to delete is typically 1,
-	// so it is faster than using a set to find the difference, then sort the users by name.
-	for _, user := range users {
-		shouldDelete := false
-		for _, toDelete := range names {
-			if user.GetName() == toDelete {
-				shouldDelete = true
-				break
+	if err = ValidateFullServerConfig(config); err != nil {
+		return fmt.Errorf("ValidateFullServerConfig() failed: %w", err)
+	}
+
+	// Adjust loggingLevel.
+	// This needs to happen before adjusting other settings.
+	loggingLevel := config.GetLoggingLevel().String()
+	if loggingLevel != pb.LoggingLevel_DEFAULT.String() {
+		log.SetLevel(loggingLevel)
+	}
+	SetServerLogFormat(config.GetLogFormat())
+	if err := SetServerLogFile(config.GetLogFile()); err != nil {
+		return err
+	}
+
+	mux := serverMuxRef.Load()
+	if mux != nil {
+		// Adjust portBindings.
+		mtu := common.DefaultMTU
+		if config.GetMtu() != 0 {
+			mtu = int(config.GetMtu())
+		}
+		endpoints, err := PortBindingsToUnderlayProperties(config.GetPortBindings(), mtu, config.GetWebSocket(), config.GetTlsCamouflage(), config.GetDecoy(), config.GetProxyProtocol())
+		if err != nil {
+			return err
+		}
+		endpoints, err = ServerSIP003Endpoints(config, endpoints)
+		if err != nil {
+			return err
+		}
+		mux.SetEndpoints(endpoints)
+
+		// Adjust users and user groups.
+		mux.SetServerUsers(UserListToMap(config.GetUsers()))
+		mux.SetServerUserGroups(UserGroupsByUserName(config.GetUserGroups()))
+
+		// Adjust max sessions, CPU usage and bandwidth.
+		mux.SetServerOverloadLimits(appctlcommon.OverloadLimitsFromConfig(config.GetAdvancedSettings()))
+
+		// Adjust key rotation.
+		mux.SetKeyRotation(appctlcommon.KeyRotationFromConfig(config.GetKeyRotation()))
+
+		// Adjust port knocking.
+		if err := mux.SetServerPortKnocking(appctlcommon.PortKnockingOptionsFromConfig(config.GetPortKnocking())); err != nil {
+			return err
+		}
+
+		// Adjust replay cache.
+		if err := ApplyReplayCacheConfig(config.GetAdvancedSettings().GetReplayCache()); err != nil {
+			return err
+		}
+
+		// Adjust cluster synchronization.
+		clusterSync, err := newServerClusterSync(config.GetCluster())
+		if err != nil {
+			return err
+		}
+		setServerClusterSync(clusterSync)
+	}
+
+	socks5Server := socks5ServerRef.Load()
+	if socks5Server != nil {
+		// Adjust users, user groups and egress used by proxy decisions.
+		socks5Server.SetUsers(UserListToMap(config.GetUsers()))
+		socks5Server.SetUserGroups(UserGroupsByUserName(config.GetUserGroups()))
+		socks5Server.SetEgress(config.GetEgress())
+		egressGeoIP, egressGeosite, err := appctlcommon.LoadGeoDatabases(config.GetGeoDatabases())
+		if err != nil {
+			return err
+		}
+		socks5Server.SetEgressGeoDatabases(egressGeoIP, egressGeosite)
+		if serverBlocklist := serverBlocklistRef.Load(); serverBlocklist != nil {
+			if err := UpdateServerBlocklist(serverBlocklist, config.GetBlocklist()); err != nil {
+				return err
 			}
 		}
-		if !shouldDelete {
-			remaining = append(remaining, user)
+
+		// Adjust UDP relay used by new UDP associations.
+		udpRelay, err := UDPRelayToSocks5(config.GetUdpRelay())
+		if err != nil {
+			return err
+		}
+		socks5Server.SetUDPRelay(udpRelay)
+
+		// Adjust audit log.
+		if err := updateServerAuditLog(socks5Server, config.GetAuditLog()); err != nil {
+			return err
 		}
 	}
-	config.Users = remaining
-	if err := StoreServerConfig(config); err != nil {
-		return fmt.Errorf("StoreServerConfig() failed: %w", err)
+
+	// Adjust maintenance window.
+	if maintenance := serverMaintenanceRef.Load(); maintenance != nil {
+		maintenance.Update(config)
 	}
 	return nil
 }
@@ -558,13 +1090,19 @@ func DeleteServerUsers(names []string) error {
 // ValidateServerConfigPatch validates a patch of server config.
 //
 // A server config patch must satisfy:
-// 1. port bindings are valid
+// 1. port bindings are valid, and don't use AUTO protocol
 // 2. for each user
 // 2.1. user name is not empty
 // 2.2. user has either a password or a hashed password
 // 2.3. for each quota
 // 2.3.1. number of days is valid
 // 2.3.2. traffic volume in megabyte is valid
+// 2.4. if set, expire time is in RFC 3339 format
+// 2.5. for each destination rule
+// 2.5.1. action is ALLOW or DENY
+// 2.5.2. each IP range is either "*" or a valid IP CIDR
+// 2.5.3. each domain name is not empty, and does not begin or end with a dot
+// 2.5.4. each port or port range is valid
 // 3. if set, MTU is valid
 // 4. for each egress proxy
 // 4.1. name is not empty
@@ -577,11 +1115,73 @@ func DeleteServerUsers(names []string) error {
 // 5.1. each IP range is either "*" or a valid IP CIDR
 // 5.2. each domain name is not empty, and does not begin or end with a dot
 // 5.3. if the action is "PROXY", the proxy is defined
+// 5.4. each country is a 2 letter country code
+// 5.5. each site category is not empty
 // 6. if set, metrics logging interval is valid, and it is not less than 1 second
+// 7. if set, max sessions and max bandwidth are not negative, and max CPU percent is between 0 and 100
+// 8. if set, OTLP trace endpoint is valid
+// 9. for each user group
+// 9.1. name is not empty
+// 9.2. name is unique
+// 9.3. each user belongs to at most one group
+// 9.4. egress proxies and rules are valid, same as 4 and 5
+// 9.5. quotas are valid, same as 2.3
+// 10. if set, maintenance window is valid
+// 10.1. if set, daily start time is in "HH:MM" format
+// 10.2. announce minutes is not negative, and not more than 720
+// 11. if set, UDP relay is valid
+// 11.1. if set, port range is valid
+// 11.2. if set, bind IP is a valid IP address
+// 11.3. bind IP and bind interface are not both set
+// 12. if country traffic statistics is enabled, GeoIP database is set
+// 13. if dropping privileges is not enabled, user, group, chroot and capabilities
+// to keep are not set, and if set, chroot is an absolute path
+// 14. sandbox writable paths are absolute paths, and seccomp allowlist is not set
+// if sandbox is not enabled
+// 15. if set, WebSocket path is valid, and TLS certificate and private key are set together
+// 16. if set, TLS camouflage server name, ALPN and fallback address are valid,
+// and TLS certificate and private key are set together
+// 17. if set, decoy has exactly one of static directory and reverse proxy URL,
+// static directory is an absolute path, and reverse proxy URL is an HTTP or HTTPS URL
+// 18. if set, replay cache capacity is not negative, expire interval is valid and
+// not less than the default value, and dump interval is valid and not less than 1 second
+// 19. if set, key rotation period is not less than 10 minutes, and overlap is not
+// larger than half of the period
+// 20. if set, port knocking sequence has at least 2 TCP or UDP steps not used by
+// port bindings, and timeout and open duration are positive
+// 21. if set, GeoIP and geosite database paths are absolute paths, and download URLs
+// are HTTP or HTTPS URLs
+// 22. if set, blocklist files are absolute paths, URLs are HTTP or HTTPS URLs, and
+// refresh interval is not less than 10 minutes
+// 23. if set, PROXY protocol trusted sources are IP addresses or CIDR ranges
+// 24. if set, audit log path is an absolute path, and max size, max backups
+// and max age are not negative
+// 25. if set, log file path is an absolute path, and max size, max backups
+// and max age are not negative
+// 26. if remote management is enabled, port and bind IP are valid, certificate,
+// private key and client CA files are absolute paths, and admin names are not empty
+// 27. if management gateway is enabled, port and bind IP are valid, certificate and
+// private key files are set together as absolute paths, bind IP is a loopback address
+// if TLS is not used, there is at least one token or cluster token, each token has
+// at least 16 characters, and cluster tokens are different from the other tokens
+// 28. if set, egress bind IP is a valid IP address, including the egress of user groups
+// 29. if SIP003 plugins are set, each command is set, TCP and WEBSOCKET port
+// bindings don't use port range, and sandbox and chroot are not enabled
+// 30. if cluster primary URL is set, it is an HTTPS URL or an HTTP URL of a loopback
+// host, the token has at least 16 characters, sync interval is valid, and CA file is
+// an absolute path
+// 31. if set, accept limit handshake timeout is valid and not less than 1 second,
+// and max half-open connections and accept rate are not negative
 func ValidateServerConfigPatch(patch *pb.ServerConfig) error {
-	if _, err := appctlcommon.FlatPortBindings(patch.GetPortBindings()); err != nil {
+	portBindings, err := appctlcommon.FlatPortBindings(patch.GetPortBindings())
+	if err != nil {
 		return err
 	}
+	for _, binding := range portBindings {
+		if binding.GetProtocol() == pb.TransportProtocol_AUTO {
+			return fmt.Errorf("protocol %s is only supported by proxy client", binding.GetProtocol().String())
+		}
+	}
 	for _, user := range patch.GetUsers() {
 		if user.GetName() == "" {
 			return fmt.Errorf("user name is not set")
@@ -589,22 +1189,351 @@ func ValidateServerConfigPatch(patch *pb.ServerConfig) error {
 		if user.GetPassword() == "" && user.GetHashedPassword() == "" {
 			return fmt.Errorf("user password is not set")
 		}
-		for _, quota := range user.GetQuotas() {
-			if quota.GetDays() <= 0 {
-				return fmt.Errorf("quota: number of days %d is invalid", quota.GetDays())
-			}
-			if quota.GetMegabytes() <= 0 {
-				return fmt.Errorf("quota: traffic volume in megabyte %d is invalid", quota.GetMegabytes())
+		if err := validateQuotas(user.GetQuotas()); err != nil {
+			return err
+		}
+		if user.GetExpireTime() != "" {
+			if _, err := time.Parse(time.RFC3339, user.GetExpireTime()); err != nil {
+				return fmt.Errorf("user %q expire time %q is invalid: %w", user.GetName(), user.GetExpireTime(), err)
 			}
 		}
+		if err := validateDestinationRules(user.GetDestinationRules()); err != nil {
+			return fmt.Errorf("user %q: %w", user.GetName(), err)
+		}
 	}
 	if patch.GetMtu() != 0 && (patch.GetMtu() < 1280 || patch.GetMtu() > 1500) {
 		return fmt.Errorf("MTU value %d is out of range, valid range is [1280, 1500]", patch.GetMtu())
 	}
-	usedProxyNames := map[string]bool{}
-	for _, proxy := range patch.GetEgress().GetProxies() {
-		if proxy.GetName() == "" {
-			return fmt.Errorf("egress proxy name is empty")
+	if err := validateEgress(patch.GetEgress()); err != nil {
+		return err
+	}
+	if patch.GetAdvancedSettings().GetMetricsLoggingInterval() != "" {
+		d, err := time.ParseDuration(patch.GetAdvancedSettings().GetMetricsLoggingInterval())
+		if err != nil {
+			return fmt.Errorf("metrics logging interval %q is invalid: %w", patch.GetAdvancedSettings().GetMetricsLoggingInterval(), err)
+		}
+		if d < time.Second {
+			return fmt.Errorf("metrics logging interval %q is less than 1 second", patch.GetAdvancedSettings().GetMetricsLoggingInterval())
+		}
+	}
+	if err := appctlcommon.ValidateOverloadLimits(patch.GetAdvancedSettings()); err != nil {
+		return err
+	}
+	if err := appctlcommon.ValidateAcceptLimitConfig(patch.GetAdvancedSettings().GetAcceptLimit()); err != nil {
+		return err
+	}
+	if patch.GetAdvancedSettings().GetOtlpTraceEndpoint() != "" {
+		if err := tracing.ValidateEndpoint(patch.GetAdvancedSettings().GetOtlpTraceEndpoint()); err != nil {
+			return err
+		}
+	}
+	usedGroupNames := map[string]bool{}
+	groupOfUser := map[string]string{}
+	for _, group := range patch.GetUserGroups() {
+		if group.GetName() == "" {
+			return fmt.Errorf("user group name is empty")
+		}
+		if usedGroupNames[group.GetName()] {
+			return fmt.Errorf("found duplicate user group name %q", group.GetName())
+		}
+		usedGroupNames[group.GetName()] = true
+		for _, userName := range group.GetUsers() {
+			if other, found := groupOfUser[userName]; found {
+				return fmt.Errorf("user %q belongs to both user group %q and %q", userName, other, group.GetName())
+			}
+			groupOfUser[userName] = group.GetName()
+		}
+		if err := validateEgress(group.GetEgress()); err != nil {
+			return fmt.Errorf("user group %q: %w", group.GetName(), err)
+		}
+		if err := validateQuotas(group.GetQuotas()); err != nil {
+			return fmt.Errorf("user group %q: %w", group.GetName(), err)
+		}
+	}
+	if patch.GetMaintenance().GetDailyStartTime() != "" {
+		if _, err := time.Parse(maintenanceTimeLayout, patch.GetMaintenance().GetDailyStartTime()); err != nil {
+			return fmt.Errorf("maintenance daily start time %q is invalid: %w", patch.GetMaintenance().GetDailyStartTime(), err)
+		}
+	}
+	if patch.GetMaintenance().GetAnnounceMinutes() < 0 || patch.GetMaintenance().GetAnnounceMinutes() > maxMaintenanceAnnounceMinutes {
+		return fmt.Errorf("maintenance announce minutes %d is out of range [0, %d]", patch.GetMaintenance().GetAnnounceMinutes(), maxMaintenanceAnnounceMinutes)
+	}
+	if relay := patch.GetUdpRelay(); relay != nil {
+		if relay.GetPortRange() != "" {
+			if _, _, err := appctlcommon.ParsePortRange(relay.GetPortRange()); err != nil {
+				return fmt.Errorf("UDP relay port range is invalid: %w", err)
+			}
+		}
+		if relay.GetBindIP() != "" && net.ParseIP(relay.GetBindIP()) == nil {
+			return fmt.Errorf("UDP relay bind IP %q is invalid", relay.GetBindIP())
+		}
+		if relay.GetBindIP() != "" && relay.GetBindInterface() != "" {
+			return fmt.Errorf("UDP relay bind IP and bind interface can't be set at the same time")
+		}
+	}
+	if patch.GetCountryTraffic().GetEnable() && patch.GetCountryTraffic().GetGeoIPDatabase() == "" {
+		return fmt.Errorf("GeoIP database is not set for country traffic statistics")
+	}
+	if drop := patch.GetDropPrivileges(); !drop.GetEnable() && (drop.GetUser() != "" || drop.GetGroup() != "" || drop.GetChroot() != "" || drop.GetKeepNetBindService()) {
+		return fmt.Errorf("dropping privileges is not enabled, but user, group, chroot or capabilities to keep is set")
+	}
+	if chroot := patch.GetDropPrivileges().GetChroot(); chroot != "" && !filepath.IsAbs(chroot) {
+		return fmt.Errorf("chroot directory %q is not an absolute path", chroot)
+	}
+	for _, path := range patch.GetSandbox().GetWritablePaths() {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("sandbox writable path %q is not an absolute path", path)
+		}
+	}
+	if !patch.GetSandbox().GetEnable() && patch.GetSandbox().GetSeccompAllowlist() {
+		return fmt.Errorf("sandbox is not enabled, but seccomp allowlist is set")
+	}
+	if err := appctlcommon.ValidateWebSocketConfig(patch.GetWebSocket()); err != nil {
+		return err
+	}
+	if err := appctlcommon.ValidateTLSCamouflageConfig(patch.GetTlsCamouflage()); err != nil {
+		return err
+	}
+	if err := validateDecoy(patch.GetDecoy()); err != nil {
+		return err
+	}
+	if err := validateReplayCache(patch.GetAdvancedSettings().GetReplayCache()); err != nil {
+		return err
+	}
+	if err := appctlcommon.ValidateKeyRotationConfig(patch.GetKeyRotation()); err != nil {
+		return err
+	}
+	if err := appctlcommon.ValidatePortKnockingConfig(patch.GetPortKnocking(), portBindings); err != nil {
+		return err
+	}
+	if err := appctlcommon.ValidateGeoDatabasesConfig(patch.GetGeoDatabases()); err != nil {
+		return err
+	}
+	if err := validateBlocklist(patch.GetBlocklist()); err != nil {
+		return err
+	}
+	if _, err := serverProxyProtocolOptions(patch.GetProxyProtocol()); err != nil {
+		return err
+	}
+	if err := validateAuditLog(patch.GetAuditLog()); err != nil {
+		return err
+	}
+	if err := validateLogFile(patch.GetLogFile()); err != nil {
+		return err
+	}
+	if err := validateRemoteManagement(patch.GetRemoteManagement()); err != nil {
+		return err
+	}
+	if err := validateManagementGateway(patch.GetManagementGateway()); err != nil {
+		return err
+	}
+	if err := validateServerSIP003Plugins(patch); err != nil {
+		return err
+	}
+	if err := validateCluster(patch.GetCluster()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateServerSIP003Plugins validates the SIP003 plugins of proxy server.
+// A plugin process is started for each TCP and WEBSOCKET port, so port
+// ranges are not supported. The sandbox and chroot block restarting the plugins.
+func validateServerSIP003Plugins(config *pb.ServerConfig) error {
+	if len(config.GetSip003Plugins()) == 0 {
+		return nil
+	}
+	for _, plugin := range config.GetSip003Plugins() {
+		if plugin.GetCommand() == "" {
+			return fmt.Errorf("SIP003 plugin command is not set")
+		}
+	}
+	if config.GetSandbox().GetEnable() {
+		return fmt.Errorf("sandbox can't be used with SIP003 plugins")
+	}
+	if config.GetDropPrivileges().GetChroot() != "" {
+		return fmt.Errorf("chroot can't be used with SIP003 plugins")
+	}
+	for _, binding := range config.GetPortBindings() {
+		if binding.GetProtocol() != pb.TransportProtocol_UDP && binding.GetPortRange() != "" {
+			return fmt.Errorf("SIP003 plugins don't support port range %q of %s protocol", binding.GetPortRange(), binding.GetProtocol().String())
+		}
+	}
+	return nil
+}
+
+// validateAuditLog validates the audit log path and rotation.
+func validateAuditLog(config *pb.AuditLog) error {
+	return validateRotatingFile("audit log", config.GetPath(), config.GetMaxSizeMB(), config.GetMaxBackups(), config.GetMaxAgeDays())
+}
+
+// validateLogFile validates the server log file path and rotation.
+func validateLogFile(config *pb.LogFile) error {
+	return validateRotatingFile("log file", config.GetPath(), config.GetMaxSizeMB(), config.GetMaxBackups(), config.GetMaxAgeDays())
+}
+
+func validateRotatingFile(name, path string, maxSizeMB, maxBackups, maxAgeDays int32) error {
+	if path != "" && !filepath.IsAbs(path) {
+		return fmt.Errorf("%s path %q is not an absolute path", name, path)
+	}
+	if maxSizeMB < 0 {
+		return fmt.Errorf("%s max size %d is negative", name, maxSizeMB)
+	}
+	if maxBackups < 0 {
+		return fmt.Errorf("%s max backups %d is negative", name, maxBackups)
+	}
+	if maxAgeDays < 0 {
+		return fmt.Errorf("%s max age %d is negative", name, maxAgeDays)
+	}
+	return nil
+}
+
+// rotationOptions converts the rotation settings in the config.
+func rotationOptions(maxSizeMB, maxAgeDays, maxBackups int32, compress bool) log.RotationOptions {
+	return log.RotationOptions{
+		MaxSize:    int64(maxSizeMB) * 1024 * 1024,
+		MaxBackups: int(maxBackups),
+		MaxAge:     time.Duration(maxAgeDays) * 24 * time.Hour,
+		Compress:   compress,
+	}
+}
+
+// SetServerLogFile writes server logs to the file in the config,
+// or to standard output if the file is not set.
+// The previous log file is closed.
+func SetServerLogFile(config *pb.LogFile) error {
+	var output io.Writer = os.Stdout
+	var f *log.RotatingFile
+	if config.GetPath() != "" {
+		var err error
+		f, err = log.NewRotatingFile(config.GetPath(), rotationOptions(config.GetMaxSizeMB(), config.GetMaxAgeDays(), config.GetMaxBackups(), config.GetCompress()))
+		if err != nil {
+			return fmt.Errorf("failed to open server log file: %w", err)
+		}
+		output = f
+	}
+	log.SetOutput(output)
+	if previous := serverLogFileRef.Swap(f); previous != nil {
+		previous.Close()
+	}
+	return nil
+}
+
+// NewServerAuditLog opens the audit log file. It returns nil
+// if the audit log is not enabled.
+func NewServerAuditLog(config *pb.AuditLog) (*socks5.AuditLog, error) {
+	if config.GetPath() == "" {
+		return nil, nil
+	}
+	f, err := openServerAuditLogFile(config)
+	if err != nil {
+		return nil, err
+	}
+	return socks5.NewAuditLog(f), nil
+}
+
+// updateServerAuditLog applies the audit log config to the socks5 server.
+// If the audit log is still enabled, the file is opened again, and the
+// records of established connections are written to the new file.
+func updateServerAuditLog(socks5Server *socks5.Server, config *pb.AuditLog) error {
+	auditLog := serverAuditLogRef.Load()
+	if config.GetPath() == "" {
+		if auditLog != nil {
+			socks5Server.SetAuditLog(nil)
+			auditLog.Close()
+			SetServerAuditLogRef(nil)
+		}
+		return nil
+	}
+	if auditLog == nil {
+		newAuditLog, err := NewServerAuditLog(config)
+		if err != nil {
+			return err
+		}
+		socks5Server.SetAuditLog(newAuditLog)
+		SetServerAuditLogRef(newAuditLog)
+		return nil
+	}
+	f, err := openServerAuditLogFile(config)
+	if err != nil {
+		return err
+	}
+	if err := auditLog.SetWriter(f); err != nil {
+		log.Debugf("failed to close previous audit log file: %v", err)
+	}
+	return nil
+}
+
+func openServerAuditLogFile(config *pb.AuditLog) (*log.RotatingFile, error) {
+	f, err := log.NewRotatingFile(config.GetPath(), rotationOptions(config.GetMaxSizeMB(), config.GetMaxAgeDays(), config.GetMaxBackups(), config.GetCompress()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return f, nil
+}
+
+// validateBlocklist validates the blocklist files, URLs and refresh interval.
+func validateBlocklist(config *pb.Blocklist) error {
+	for _, path := range config.GetFiles() {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("blocklist file %q is not an absolute path", path)
+		}
+	}
+	for _, blocklistURL := range config.GetUrls() {
+		u, err := url.Parse(blocklistURL)
+		if err != nil {
+			return fmt.Errorf("blocklist URL %q is invalid: %w", blocklistURL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("blocklist URL %q is not an HTTP or HTTPS URL", blocklistURL)
+		}
+	}
+	if config.GetRefreshInterval() != "" {
+		interval, err := time.ParseDuration(config.GetRefreshInterval())
+		if err != nil {
+			return fmt.Errorf("blocklist refresh interval %q is invalid: %w", config.GetRefreshInterval(), err)
+		}
+		if interval < minBlocklistRefreshInterval {
+			return fmt.Errorf("blocklist refresh interval %v is less than %v", interval, minBlocklistRefreshInterval)
+		}
+	}
+	return nil
+}
+
+// validateDecoy validates the decoy web site.
+func validateDecoy(decoy *pb.DecoyConfig) error {
+	if decoy == nil {
+		return nil
+	}
+	dir := decoy.GetStaticDirectory()
+	proxyURL := decoy.GetReverseProxyURL()
+	if (dir == "") == (proxyURL == "") {
+		return fmt.Errorf("decoy must set exactly one of static directory and reverse proxy URL")
+	}
+	if dir != "" && !filepath.IsAbs(dir) {
+		return fmt.Errorf("decoy static directory %q is not an absolute path", dir)
+	}
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("decoy reverse proxy URL %q is invalid: %w", proxyURL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("decoy reverse proxy URL %q is not an HTTP or HTTPS URL", proxyURL)
+		}
+	}
+	return nil
+}
+
+// validateEgress validates egress proxies, rules and local binding.
+func validateEgress(egress *pb.Egress) error {
+	if egress.GetBindIP() != "" && net.ParseIP(egress.GetBindIP()) == nil {
+		return fmt.Errorf("egress bind IP %q is invalid", egress.GetBindIP())
+	}
+	usedProxyNames := map[string]bool{}
+	for _, proxy := range egress.GetProxies() {
+		if proxy.GetName() == "" {
+			return fmt.Errorf("egress proxy name is empty")
 		}
 		if _, found := usedProxyNames[proxy.GetName()]; found {
 			return fmt.Errorf("found duplicate egress proxy name %q", proxy.GetName())
@@ -628,7 +1557,7 @@ func ValidateServerConfigPatch(patch *pb.ServerConfig) error {
 			return fmt.Errorf("egress proxy socks5 authentication password is not set")
 		}
 	}
-	for _, rule := range patch.GetEgress().GetRules() {
+	for _, rule := range egress.GetRules() {
 		for _, ipRange := range rule.GetIpRanges() {
 			if ipRange != "*" {
 				if _, _, err := net.ParseCIDR(ipRange); err != nil {
@@ -647,6 +1576,16 @@ func ValidateServerConfigPatch(patch *pb.ServerConfig) error {
 				return fmt.Errorf("egress rule: domain name %q must not end with a dot", domain)
 			}
 		}
+		for _, country := range rule.GetCountries() {
+			if len(country) != 2 || strings.Trim(strings.ToUpper(country), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+				return fmt.Errorf("egress rule: invalid country code %q", country)
+			}
+		}
+		for _, category := range rule.GetSiteCategories() {
+			if category == "" {
+				return fmt.Errorf("egress rule: site category is empty")
+			}
+		}
 		if rule.GetAction() == pb.EgressAction_PROXY {
 			if len(rule.GetProxyNames()) == 0 {
 				return fmt.Errorf("egress rule: proxy name list is empty for PROXY action")
@@ -662,13 +1601,53 @@ func ValidateServerConfigPatch(patch *pb.ServerConfig) error {
 			}
 		}
 	}
-	if patch.GetAdvancedSettings().GetMetricsLoggingInterval() != "" {
-		d, err := time.ParseDuration(patch.GetAdvancedSettings().GetMetricsLoggingInterval())
-		if err != nil {
-			return fmt.Errorf("metrics logging interval %q is invalid: %w", patch.GetAdvancedSettings().GetMetricsLoggingInterval(), err)
+	return nil
+}
+
+// validateDestinationRules validates the destination rules of a user.
+func validateDestinationRules(rules []*pb.DestinationRule) error {
+	for _, rule := range rules {
+		if rule.GetAction() != pb.DestinationAction_ALLOW && rule.GetAction() != pb.DestinationAction_DENY {
+			return fmt.Errorf("destination rule: action must be ALLOW or DENY")
 		}
-		if d < time.Second {
-			return fmt.Errorf("metrics logging interval %q is less than 1 second", patch.GetAdvancedSettings().GetMetricsLoggingInterval())
+		for _, ipRange := range rule.GetIpRanges() {
+			if ipRange != "*" {
+				if _, _, err := net.ParseCIDR(ipRange); err != nil {
+					return fmt.Errorf("destination rule: invalid IP CIDR: %s", ipRange)
+				}
+			}
+		}
+		for _, domain := range rule.GetDomainNames() {
+			if domain == "" {
+				return fmt.Errorf("destination rule: domain name is empty")
+			}
+			if domain[0] == '.' || domain[len(domain)-1] == '.' {
+				return fmt.Errorf("destination rule: domain name %q must not begin or end with a dot", domain)
+			}
+		}
+		for _, port := range rule.GetPorts() {
+			if strings.Contains(port, "-") {
+				if _, _, err := appctlcommon.ParsePortRange(port); err != nil {
+					return fmt.Errorf("destination rule: %w", err)
+				}
+				continue
+			}
+			if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+				return fmt.Errorf("destination rule: port %q is invalid", port)
+			}
+		}
+	}
+	return nil
+}
+
+// validateQuotas validates the quotas of a user or a user group.
+func validateQuotas(quotas []*pb.Quota) error {
+	for _, quota := range quotas {
+		if quota.GetDays() <= 0 {
+			return fmt.Errorf("quota: number of days %d is invalid", quota.GetDays())
+		}
+		if quota.GetMegabytes() <= 0 {
+			return fmt.Errorf("quota: traffic volume in megabyte %d is invalid", quota.GetMegabytes())
 		}
 	}
 	return nil
@@ -678,6 +1657,8 @@ func ValidateServerConfigPatch(patch *pb.ServerConfig) error {
 //
 // In addition to ValidateServerConfigPatch, it also validates:
 // 1. there is at least 1 port binding
+// 2. users of each user group are registered
+// 3. if egress rules use countries or site categories, the GeoIP or geosite database is set
 //
 // It is not an error if no user is configured. However mita won't be functional.
 func ValidateFullServerConfig(config *pb.ServerConfig) error {
@@ -691,30 +1672,171 @@ func ValidateFullServerConfig(config *pb.ServerConfig) error {
 	if len(config.GetPortBindings()) == 0 {
 		return fmt.Errorf("server port binding is not set")
 	}
+	users := UserListToMap(config.GetUsers())
+	for _, group := range config.GetUserGroups() {
+		for _, userName := range group.GetUsers() {
+			if _, found := users[userName]; !found {
+				return fmt.Errorf("user %q of user group %q is not registered", userName, group.GetName())
+			}
+		}
+	}
+	rules := config.GetEgress().GetRules()
+	for _, group := range config.GetUserGroups() {
+		rules = append(rules[:len(rules):len(rules)], group.GetEgress().GetRules()...)
+	}
+	for _, rule := range rules {
+		if len(rule.GetCountries()) > 0 && config.GetGeoDatabases().GetGeoIPDatabase() == "" {
+			return fmt.Errorf("egress rule uses countries, but GeoIP database is not set")
+		}
+		if len(rule.GetSiteCategories()) > 0 && config.GetGeoDatabases().GetGeositeDatabase() == "" {
+			return fmt.Errorf("egress rule uses site categories, but geosite database is not set")
+		}
+	}
 	return nil
 }
 
+// validateReplayCache validates the options of replay cache.
+func validateReplayCache(config *pb.ReplayCacheConfig) error {
+	if config == nil {
+		return nil
+	}
+	if config.GetCapacity() < 0 {
+		return fmt.Errorf("replay cache capacity %d is negative", config.GetCapacity())
+	}
+	if config.GetExpireInterval() != "" {
+		d, err := time.ParseDuration(config.GetExpireInterval())
+		if err != nil {
+			return fmt.Errorf("replay cache expire interval %q is invalid: %w", config.GetExpireInterval(), err)
+		}
+		if d < replay.DefaultExpireInterval {
+			return fmt.Errorf("replay cache expire interval %q is less than %v", config.GetExpireInterval(), replay.DefaultExpireInterval)
+		}
+	}
+	if config.GetDumpInterval() != "" {
+		d, err := time.ParseDuration(config.GetDumpInterval())
+		if err != nil {
+			return fmt.Errorf("replay cache dump interval %q is invalid: %w", config.GetDumpInterval(), err)
+		}
+		if d < time.Second {
+			return fmt.Errorf("replay cache dump interval %q is less than 1 second", config.GetDumpInterval())
+		}
+	}
+	return nil
+}
+
+// ApplyReplayCacheConfig updates the capacity and expire interval of
+// the replay caches. The default values are used if they are not set.
+func ApplyReplayCacheConfig(config *pb.ReplayCacheConfig) error {
+	capacity := replay.DefaultCapacity
+	if config.GetCapacity() > 0 {
+		capacity = int(config.GetCapacity())
+	}
+	expireInterval := replay.DefaultExpireInterval
+	if config.GetExpireInterval() != "" {
+		d, err := time.ParseDuration(config.GetExpireInterval())
+		if err != nil {
+			return fmt.Errorf("time.ParseDuration() failed: %w", err)
+		}
+		expireInterval = d
+	}
+	return replay.SetCacheOptions(capacity, expireInterval)
+}
+
+// ReplayCacheDumpInterval returns the interval to save the replay caches
+// to disk, or 0 if the replay caches are not saved.
+func ReplayCacheDumpInterval(config *pb.ReplayCacheConfig) time.Duration {
+	if config.GetDisableDump() {
+		return 0
+	}
+	if config.GetDumpInterval() != "" {
+		if d, err := time.ParseDuration(config.GetDumpInterval()); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultReplayCacheDumpInterval
+}
+
 // PortBindingsToUnderlayProperties converts port bindings to underlay properties.
-func PortBindingsToUnderlayProperties(portBindings []*pb.PortBinding, mtu int) ([]protocol.UnderlayProperties, error) {
+// The WebSocket config is used by port bindings with the WEBSOCKET protocol.
+// If the TLS camouflage config is set, TCP port bindings are wrapped in TLS.
+// If the decoy config is set, it is used by TCP and WEBSOCKET port bindings.
+// If the PROXY protocol config is set, TCP and WEBSOCKET port bindings
+// accept PROXY protocol headers. UDP ports in a port range with port
+// hopping only accept new sessions of a user on the active port.
+func PortBindingsToUnderlayProperties(portBindings []*pb.PortBinding, mtu int, webSocket *pb.WebSocketConfig, tlsCamouflage *pb.TLSCamouflageConfig, decoy *pb.DecoyConfig, proxyProtocol *pb.ProxyProtocolConfig) ([]protocol.UnderlayProperties, error) {
 	endpoints := make([]protocol.UnderlayProperties, 0)
 	listenIP := net.ParseIP(common.AllIPAddr())
 	if listenIP == nil {
 		return endpoints, fmt.Errorf(stderror.ParseIPFailed)
 	}
+	hoppingOpts := appctlcommon.PortHoppingOptionsFromConfig(portBindings)
 	portBindings, err := appctlcommon.FlatPortBindings(portBindings)
 	if err != nil {
 		return endpoints, fmt.Errorf(stderror.InvalidPortBindingsErr, err)
 	}
+	var webSocketOpts *protocol.WebSocketOptions
+	var tlsCamouflageOpts *protocol.TLSCamouflageOptions
+	var decoyOpts *protocol.DecoyOptions
+	if decoy != nil {
+		decoyOpts = &protocol.DecoyOptions{
+			StaticDirectory: decoy.GetStaticDirectory(),
+			ReverseProxyURL: decoy.GetReverseProxyURL(),
+		}
+	}
+	if tlsCamouflage != nil {
+		tlsCamouflageOpts, err = serverTLSCamouflageOptions(tlsCamouflage)
+		if err != nil {
+			return []protocol.UnderlayProperties{}, err
+		}
+	}
+	var proxyProtocolOpts *protocol.ProxyProtocolOptions
+	if proxyProtocol != nil {
+		proxyProtocolOpts, err = serverProxyProtocolOptions(proxyProtocol)
+		if err != nil {
+			return []protocol.UnderlayProperties{}, err
+		}
+	}
 	n := len(portBindings)
 	for i := 0; i < n; i++ {
 		proto := portBindings[i].GetProtocol()
 		port := portBindings[i].GetPort()
 		switch proto {
 		case pb.TransportProtocol_TCP:
-			endpoint := protocol.NewUnderlayProperties(mtu, common.StreamTransport, &net.TCPAddr{IP: listenIP, Port: int(port)}, nil)
+			var endpoint protocol.UnderlayProperties
+			if tlsCamouflageOpts != nil {
+				endpoint = protocol.NewTLSCamouflageUnderlayProperties(mtu, &net.TCPAddr{IP: listenIP, Port: int(port)}, nil, tlsCamouflageOpts)
+			} else {
+				endpoint = protocol.NewUnderlayProperties(mtu, common.StreamTransport, &net.TCPAddr{IP: listenIP, Port: int(port)}, nil)
+			}
+			if decoyOpts != nil {
+				endpoint = protocol.WithDecoy(endpoint, decoyOpts)
+			}
+			if proxyProtocolOpts != nil {
+				endpoint = protocol.WithProxyProtocol(endpoint, proxyProtocolOpts)
+			}
 			endpoints = append(endpoints, endpoint)
 		case pb.TransportProtocol_UDP:
 			endpoint := protocol.NewUnderlayProperties(mtu, common.PacketTransport, &net.UDPAddr{IP: listenIP, Port: int(port)}, nil)
+			for _, opts := range hoppingOpts {
+				if opts.Contains(int(port)) {
+					endpoint = protocol.WithPortHopping(endpoint, opts)
+				}
+			}
+			endpoints = append(endpoints, endpoint)
+		case pb.TransportProtocol_WEBSOCKET:
+			if webSocketOpts == nil {
+				webSocketOpts, err = serverWebSocketOptions(webSocket)
+				if err != nil {
+					return []protocol.UnderlayProperties{}, err
+				}
+			}
+			endpoint := protocol.NewWebSocketUnderlayProperties(mtu, &net.TCPAddr{IP: listenIP, Port: int(port)}, nil, webSocketOpts)
+			if decoyOpts != nil {
+				endpoint = protocol.WithDecoy(endpoint, decoyOpts)
+			}
+			if proxyProtocolOpts != nil {
+				endpoint = protocol.WithProxyProtocol(endpoint, proxyProtocolOpts)
+			}
 			endpoints = append(endpoints, endpoint)
 		default:
 			return []protocol.UnderlayProperties{}, fmt.Errorf(stderror.InvalidTransportProtocol)
@@ -723,6 +1845,302 @@ func PortBindingsToUnderlayProperties(portBindings []*pb.PortBinding, mtu int) (
 	return endpoints, nil
 }
 
+// serverProxyProtocolOptions converts the PROXY protocol config to the
+// options used by the proxy server.
+func serverProxyProtocolOptions(config *pb.ProxyProtocolConfig) (*protocol.ProxyProtocolOptions, error) {
+	opts := &protocol.ProxyProtocolOptions{
+		Require: config.GetRequire(),
+	}
+	for _, source := range config.GetTrustedSources() {
+		ipNet, err := parseIPOrCIDR(source)
+		if err != nil {
+			return nil, fmt.Errorf("PROXY protocol trusted source %q is invalid: %w", source, err)
+		}
+		opts.TrustedSources = append(opts.TrustedSources, ipNet)
+	}
+	return opts, nil
+}
+
+// parseIPOrCIDR parses an IP address or a CIDR range.
+func parseIPOrCIDR(s string) (*net.IPNet, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(s)
+	return ipNet, err
+}
+
+// serverWebSocketOptions converts the WebSocket config to the options
+// used by the proxy server. The TLS certificate is loaded if it is set.
+func serverWebSocketOptions(config *pb.WebSocketConfig) (*protocol.WebSocketOptions, error) {
+	opts := &protocol.WebSocketOptions{
+		Path: config.GetPath(),
+	}
+	if config.GetCertificateFile() != "" || config.GetPrivateKeyFile() != "" {
+		cert, err := tls.LoadX509KeyPair(config.GetCertificateFile(), config.GetPrivateKeyFile())
+		if err != nil {
+			return nil, fmt.Errorf("tls.LoadX509KeyPair() failed: %w", err)
+		}
+		opts.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+	return opts, nil
+}
+
+// serverTLSCamouflageOptions converts the TLS camouflage config to the
+// options used by the proxy server. If the certificate file is not set,
+// a self-signed certificate of the server name is created.
+func serverTLSCamouflageOptions(config *pb.TLSCamouflageConfig) (*protocol.TLSCamouflageOptions, error) {
+	var cert tls.Certificate
+	var err error
+	if config.GetCertificateFile() != "" || config.GetPrivateKeyFile() != "" {
+		cert, err = tls.LoadX509KeyPair(config.GetCertificateFile(), config.GetPrivateKeyFile())
+		if err != nil {
+			return nil, fmt.Errorf("tls.LoadX509KeyPair() failed: %w", err)
+		}
+	} else {
+		cert, err = selfSignedCertificate(config.GetServerName())
+		if err != nil {
+			return nil, err
+		}
+	}
+	alpn := config.GetAlpn()
+	if len(alpn) == 0 {
+		alpn = []string{"http/1.1"}
+	}
+	return &protocol.TLSCamouflageOptions{
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   alpn,
+			MinVersion:   tls.VersionTLS12,
+		},
+		FallbackAddress: config.GetFallbackAddress(),
+	}, nil
+}
+
+// selfSignedCertificates caches the self-signed certificates by host name,
+// so reloading the server config doesn't restart the port bindings.
+var (
+	selfSignedCertificates     = make(map[string]tls.Certificate)
+	selfSignedCertificatesLock sync.Mutex
+)
+
+// selfSignedCertificate creates a self-signed certificate of the host name.
+// The certificate is valid for one year.
+func selfSignedCertificate(host string) (tls.Certificate, error) {
+	selfSignedCertificatesLock.Lock()
+	defer selfSignedCertificatesLock.Unlock()
+	if cert, found := selfSignedCertificates[host]; found {
+		return cert, nil
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("ecdsa.GenerateKey() failed: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("rand.Int() failed: %w", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: host},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	if host != "" {
+		template.DNSNames = []string{host}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("x509.CreateCertificate() failed: %w", err)
+	}
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	selfSignedCertificates[host] = cert
+	return cert, nil
+}
+
+// UDPRelayToSocks5 converts the UDP relay config to the socks5 UDP relay.
+// If a bind interface is given, the first IP address of the interface is used.
+func UDPRelayToSocks5(relay *pb.UDPRelay) (socks5.UDPRelay, error) {
+	var res socks5.UDPRelay
+	if relay.GetPortRange() != "" {
+		minPort, maxPort, err := appctlcommon.ParsePortRange(relay.GetPortRange())
+		if err != nil {
+			return res, fmt.Errorf("UDP relay port range is invalid: %w", err)
+		}
+		res.MinPort = minPort
+		res.MaxPort = maxPort
+	}
+	if relay.GetBindIP() != "" {
+		res.IP = net.ParseIP(relay.GetBindIP())
+		if res.IP == nil {
+			return res, fmt.Errorf("UDP relay bind IP %q is invalid", relay.GetBindIP())
+		}
+	}
+	if relay.GetBindInterface() != "" {
+		iface, err := net.InterfaceByName(relay.GetBindInterface())
+		if err != nil {
+			return res, fmt.Errorf("net.InterfaceByName() failed: %w", err)
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return res, fmt.Errorf("Addrs() failed: %w", err)
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				res.IP = ipNet.IP
+				break
+			}
+		}
+		if res.IP == nil {
+			return res, fmt.Errorf("network interface %q has no IP address", relay.GetBindInterface())
+		}
+	}
+	return res, nil
+}
+
+// NewServerCountryStats loads the GeoIP database and creates the country
+// traffic statistics. If the statistics is not enabled, nil is returned.
+func NewServerCountryStats(config *pb.CountryTrafficStatistics) (*metrics.DestinationStats, *geoip.Database, error) {
+	if !config.GetEnable() {
+		return nil, nil, nil
+	}
+	db, err := geoip.Load(config.GetGeoIPDatabase())
+	if err != nil {
+		return nil, nil, fmt.Errorf("geoip.Load() failed: %w", err)
+	}
+	log.Infof("Loaded GeoIP database %q: %s", config.GetGeoIPDatabase(), db.Version())
+	return metrics.NewDestinationStats(serverCountryTrafficHours), db, nil
+}
+
+// UpdateServerBlocklist loads the blocklist files from the config.
+// The blocklists from URLs are downloaded in the background.
+func UpdateServerBlocklist(b *blocklist.Blocklist, config *pb.Blocklist) error {
+	var refreshInterval time.Duration
+	if config.GetRefreshInterval() != "" {
+		var err error
+		refreshInterval, err = time.ParseDuration(config.GetRefreshInterval())
+		if err != nil {
+			return fmt.Errorf("time.ParseDuration() failed: %w", err)
+		}
+	}
+	if err := b.Update(config.GetFiles(), config.GetUrls(), refreshInterval); err != nil {
+		return err
+	}
+	if len(config.GetFiles()) > 0 {
+		log.Infof("Loaded %d blocklist files with %d domain names", len(config.GetFiles()), b.Len())
+	}
+	return nil
+}
+
+// DropServerPrivileges waits until the mux listens to all the port bindings,
+// and then drops the privileges of the server process, if it is enabled.
+func DropServerPrivileges(config *pb.DropPrivileges, mux *protocol.Mux) error {
+	if !config.GetEnable() {
+		return nil
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFunc()
+	if err := mux.WaitListening(ctx); err != nil {
+		return fmt.Errorf("WaitListening() failed: %w", err)
+	}
+	if err := privilege.Drop(privilege.Options{
+		User:               config.GetUser(),
+		Group:              config.GetGroup(),
+		Chroot:             config.GetChroot(),
+		KeepNetBindService: config.GetKeepNetBindService(),
+	}); err != nil {
+		return fmt.Errorf("privilege.Drop() failed: %w", err)
+	}
+	return nil
+}
+
+// SandboxServer applies the sandbox to the server process, if it is enabled.
+// The server can read the system configuration in /etc, and write the
+// server config directory, server data and the RPC socket.
+func SandboxServer(config *pb.ServerConfig) error {
+	if !config.GetSandbox().GetEnable() {
+		return nil
+	}
+	opts := privilege.SandboxOptions{
+		ReadOnlyPaths:    []string{"/etc", "/usr/share/ca-certificates", "/usr/share/zoneinfo"},
+		ReadWritePaths:   []string{ServerDataDir, filepath.Dir(ServerUDS())},
+		SeccompAllowlist: config.GetSandbox().GetSeccompAllowlist(),
+	}
+	if path := config.GetCountryTraffic().GetGeoIPDatabase(); path != "" {
+		opts.ReadOnlyPaths = append(opts.ReadOnlyPaths, path)
+	}
+	// "mita update geo-databases" replaces the files, so allow reading
+	// the directories to load the new files after reload.
+	for _, path := range []string{config.GetGeoDatabases().GetGeoIPDatabase(), config.GetGeoDatabases().GetGeositeDatabase()} {
+		if path != "" {
+			opts.ReadOnlyPaths = append(opts.ReadOnlyPaths, filepath.Dir(path))
+		}
+	}
+	if path := config.GetWebSocket().GetCertificateFile(); path != "" {
+		opts.ReadOnlyPaths = append(opts.ReadOnlyPaths, path, config.GetWebSocket().GetPrivateKeyFile())
+	}
+	if path := config.GetTlsCamouflage().GetCertificateFile(); path != "" {
+		opts.ReadOnlyPaths = append(opts.ReadOnlyPaths, path, config.GetTlsCamouflage().GetPrivateKeyFile())
+	}
+	if path := config.GetDecoy().GetStaticDirectory(); path != "" {
+		opts.ReadOnlyPaths = append(opts.ReadOnlyPaths, path)
+	}
+	opts.ReadOnlyPaths = append(opts.ReadOnlyPaths, config.GetBlocklist().GetFiles()...)
+	// Rotation creates new files in the directories.
+	for _, path := range []string{config.GetAuditLog().GetPath(), config.GetLogFile().GetPath()} {
+		if path != "" {
+			opts.ReadWritePaths = append(opts.ReadWritePaths, filepath.Dir(path))
+		}
+	}
+	serverIOLock.Lock()
+	configPath, _, err := serverConfigFilePath()
+	serverIOLock.Unlock()
+	if err == nil {
+		// The config file is replaced when it is stored.
+		opts.ReadWritePaths = append(opts.ReadWritePaths, filepath.Dir(configPath))
+	}
+	opts.ReadWritePaths = append(opts.ReadWritePaths, config.GetSandbox().GetWritablePaths()...)
+	layers, err := privilege.Sandbox(opts)
+	if err != nil {
+		return fmt.Errorf("privilege.Sandbox() failed: %w", err)
+	}
+	if len(layers) == 0 {
+		log.Warnf("Sandbox is not supported in this system")
+	} else {
+		log.Infof("Applied sandbox features: %s", strings.Join(layers, ", "))
+	}
+	return nil
+}
+
+// ServerUpdaterHistoryPath returns the file path to retrieve
+// server updater history.
+func ServerUpdaterHistoryPath() (string, error) {
+	if err := checkServerConfigDir(); err != nil {
+		return "", err
+	}
+	return filepath.Join(cachedServerConfigDir, "server.updater.pb"), nil
+}
+
+// ServerTrustStore returns the public keys trusted by the server
+// to verify signatures.
+func ServerTrustStore() (*minisign.TrustStore, error) {
+	if err := checkServerConfigDir(); err != nil {
+		return nil, err
+	}
+	return minisign.LoadTrustStore(filepath.Join(cachedServerConfigDir, "trusted_keys.json"), version.ReleasePublicKeys)
+}
+
 // checkServerConfigDir validates if server config directory exists.
 func checkServerConfigDir() error {
 	_, err := os.Stat(cachedServerConfigDir)
@@ -808,6 +2226,132 @@ func mergeServerConfig(dst, src *pb.ServerConfig) error {
 	} else {
 		dns = dst.GetDns()
 	}
+	var maintenance *pb.MaintenanceWindow
+	if src.Maintenance != nil {
+		maintenance = src.GetMaintenance()
+	} else {
+		maintenance = dst.GetMaintenance()
+	}
+	var udpRelay *pb.UDPRelay
+	if src.UdpRelay != nil {
+		udpRelay = src.GetUdpRelay()
+	} else {
+		udpRelay = dst.GetUdpRelay()
+	}
+	var countryTraffic *pb.CountryTrafficStatistics
+	if src.CountryTraffic != nil {
+		countryTraffic = src.GetCountryTraffic()
+	} else {
+		countryTraffic = dst.GetCountryTraffic()
+	}
+	var dropPrivileges *pb.DropPrivileges
+	if src.DropPrivileges != nil {
+		dropPrivileges = src.GetDropPrivileges()
+	} else {
+		dropPrivileges = dst.GetDropPrivileges()
+	}
+	var sandbox *pb.Sandbox
+	if src.Sandbox != nil {
+		sandbox = src.GetSandbox()
+	} else {
+		sandbox = dst.GetSandbox()
+	}
+	var webSocket *pb.WebSocketConfig
+	if src.WebSocket != nil {
+		webSocket = src.GetWebSocket()
+	} else {
+		webSocket = dst.GetWebSocket()
+	}
+	var tlsCamouflage *pb.TLSCamouflageConfig
+	if src.TlsCamouflage != nil {
+		tlsCamouflage = src.GetTlsCamouflage()
+	} else {
+		tlsCamouflage = dst.GetTlsCamouflage()
+	}
+	var decoy *pb.DecoyConfig
+	if src.Decoy != nil {
+		decoy = src.GetDecoy()
+	} else {
+		decoy = dst.GetDecoy()
+	}
+	var keyRotation *pb.KeyRotationConfig
+	if src.KeyRotation != nil {
+		keyRotation = src.GetKeyRotation()
+	} else {
+		keyRotation = dst.GetKeyRotation()
+	}
+	var portKnocking *pb.PortKnockingConfig
+	if src.PortKnocking != nil {
+		portKnocking = src.GetPortKnocking()
+	} else {
+		portKnocking = dst.GetPortKnocking()
+	}
+	var geoDatabases *pb.GeoDatabases
+	if src.GeoDatabases != nil {
+		geoDatabases = src.GetGeoDatabases()
+	} else {
+		geoDatabases = dst.GetGeoDatabases()
+	}
+	var blocklist *pb.Blocklist
+	if src.Blocklist != nil {
+		blocklist = src.GetBlocklist()
+	} else {
+		blocklist = dst.GetBlocklist()
+	}
+	var proxyProtocol *pb.ProxyProtocolConfig
+	if src.ProxyProtocol != nil {
+		proxyProtocol = src.GetProxyProtocol()
+	} else {
+		proxyProtocol = dst.GetProxyProtocol()
+	}
+	var logFile *pb.LogFile
+	if src.LogFile != nil {
+		logFile = src.GetLogFile()
+	} else {
+		logFile = dst.GetLogFile()
+	}
+	var logFormat *pb.LogFormat
+	if src.LogFormat != nil {
+		logFormat = src.LogFormat
+	} else {
+		logFormat = dst.LogFormat
+	}
+	var auditLog *pb.AuditLog
+	if src.AuditLog != nil {
+		auditLog = src.GetAuditLog()
+	} else {
+		auditLog = dst.GetAuditLog()
+	}
+	var remoteManagement *pb.RemoteManagement
+	if src.RemoteManagement != nil {
+		remoteManagement = src.GetRemoteManagement()
+	} else {
+		remoteManagement = dst.GetRemoteManagement()
+	}
+	var managementGateway *pb.ManagementGateway
+	if src.ManagementGateway != nil {
+		managementGateway = src.GetManagementGateway()
+	} else {
+		managementGateway = dst.GetManagementGateway()
+	}
+
+	// User groups: merge src into dst.
+	mergedUserGroupMapping := map[string]*pb.UserGroup{}
+	for _, group := range dst.GetUserGroups() {
+		mergedUserGroupMapping[group.GetName()] = group
+	}
+	for _, group := range src.GetUserGroups() {
+		mergedUserGroupMapping[group.GetName()] = group
+	}
+	groupNames := make([]string, 0, len(mergedUserGroupMapping))
+	for name := range mergedUserGroupMapping {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+	mergedUserGroups := make([]*pb.UserGroup, 0, len(mergedUserGroupMapping))
+	for _, name := range groupNames {
+		mergedUserGroups = append(mergedUserGroups, mergedUserGroupMapping[name])
+	}
 
 	proto.Reset(dst)
 	dst.PortBindings = portBindings
@@ -817,6 +2361,25 @@ func mergeServerConfig(dst, src *pb.ServerConfig) error {
 	dst.Mtu = proto.Int32(mtu)
 	dst.Egress = egress
 	dst.Dns = dns
+	dst.UserGroups = mergedUserGroups
+	dst.Maintenance = maintenance
+	dst.UdpRelay = udpRelay
+	dst.CountryTraffic = countryTraffic
+	dst.DropPrivileges = dropPrivileges
+	dst.Sandbox = sandbox
+	dst.WebSocket = webSocket
+	dst.TlsCamouflage = tlsCamouflage
+	dst.Decoy = decoy
+	dst.KeyRotation = keyRotation
+	dst.PortKnocking = portKnocking
+	dst.GeoDatabases = geoDatabases
+	dst.Blocklist = blocklist
+	dst.ProxyProtocol = proxyProtocol
+	dst.AuditLog = auditLog
+	dst.LogFormat = logFormat
+	dst.LogFile = logFile
+	dst.RemoteManagement = remoteManagement
+	dst.ManagementGateway = managementGateway
 	return nil
 }
 
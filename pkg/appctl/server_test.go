@@ -16,12 +16,19 @@
 package appctl
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
 	"github.com/enfein/mieru/v3/pkg/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -66,22 +73,84 @@ func TestApply2ServerConfig(t *testing.T) {
 	afterServerTest(t)
 }
 
+func TestMergeServerConfigKeepsFields(t *testing.T) {
+	dst := &pb.ServerConfig{
+		WebSocket:         &pb.WebSocketConfig{Path: proto.String("/ws")},
+		TlsCamouflage:     &pb.TLSCamouflageConfig{},
+		Decoy:             &pb.DecoyConfig{ReverseProxyURL: proto.String("https://www.example.com")},
+		KeyRotation:       &pb.KeyRotationConfig{Period: proto.String("12h")},
+		PortKnocking:      &pb.PortKnockingConfig{Timeout: proto.String("10s")},
+		ProxyProtocol:     &pb.ProxyProtocolConfig{Require: proto.Bool(true)},
+		AuditLog:          &pb.AuditLog{Path: proto.String("/var/log/mita/audit.log")},
+		LogFormat:         pb.LogFormat_LOG_FORMAT_JSON.Enum(),
+		LogFile:           &pb.LogFile{Path: proto.String("/var/log/mita/mita.log"), Compress: proto.Bool(true)},
+		RemoteManagement:  &pb.RemoteManagement{Port: proto.Int32(9443), Admins: []string{"admin"}},
+		ManagementGateway: &pb.ManagementGateway{Port: proto.Int32(8080), Tokens: []string{"0123456789abcdef"}},
+	}
+	want := proto.Clone(dst).(*pb.ServerConfig)
+	want.LoggingLevel = pb.LoggingLevel_DEFAULT.Enum()
+	want.Mtu = proto.Int32(0)
+	want.Users = []*pb.User{}
+	want.UserGroups = []*pb.UserGroup{}
+	if err := mergeServerConfig(dst, &pb.ServerConfig{}); err != nil {
+		t.Fatalf("mergeServerConfig() failed: %v", err)
+	}
+	if !proto.Equal(dst, want) {
+		got, _ := common.MarshalJSON(dst)
+		t.Errorf("mergeServerConfig() dropped fields: %s", string(got))
+	}
+}
+
 func TestServerApplyReject(t *testing.T) {
 	cases := []string{
+		"testdata/server_reject_audit_log_relative_path.json",
+		"testdata/server_reject_blocklist_short_refresh_interval.json",
+		"testdata/server_reject_cluster_http_public_host.json",
+		"testdata/server_reject_country_traffic_no_geoip_database.json",
+		"testdata/server_reject_decoy_both_set.json",
+		"testdata/server_reject_decoy_invalid_reverse_proxy_url.json",
+		"testdata/server_reject_decoy_relative_static_directory.json",
+		"testdata/server_reject_drop_privileges_not_enabled.json",
+		"testdata/server_reject_duplicate_user_group_name.json",
+		"testdata/server_reject_egress_rule_invalid_country.json",
+		"testdata/server_reject_egress_rule_no_geosite_database.json",
+		"testdata/server_reject_invalid_destination_rule_port.json",
+		"testdata/server_reject_invalid_maintenance_start_time.json",
 		"testdata/server_reject_invalid_metrics_logging_interval.json",
+		"testdata/server_reject_invalid_otlp_trace_endpoint.json",
 		"testdata/server_reject_invalid_port_range_1.json",
 		"testdata/server_reject_invalid_port_range_2.json",
 		"testdata/server_reject_invalid_port_range_3.json",
 		"testdata/server_reject_invalid_quota_days.json",
 		"testdata/server_reject_invalid_quota_megabytes.json",
+		"testdata/server_reject_invalid_udp_relay_port_range.json",
+		"testdata/server_reject_invalid_user_expire_time.json",
+		"testdata/server_reject_key_rotation_large_overlap.json",
+		"testdata/server_reject_log_file_negative_max_age.json",
+		"testdata/server_reject_management_gateway_cluster_token_reused.json",
+		"testdata/server_reject_management_gateway_no_tls_public_ip.json",
+		"testdata/server_reject_port_knocking_used_port.json",
+		"testdata/server_reject_proxy_protocol_invalid_trusted_source.json",
+		"testdata/server_reject_max_cpu_percent_too_large.json",
 		"testdata/server_reject_metrics_logging_interval_too_small.json",
 		"testdata/server_reject_mtu_too_big.json",
 		"testdata/server_reject_mtu_too_small.json",
+		"testdata/server_reject_negative_max_sessions.json",
 		"testdata/server_reject_no_password.json",
 		"testdata/server_reject_no_port_bindings.json",
 		"testdata/server_reject_no_port.json",
 		"testdata/server_reject_no_protocol.json",
 		"testdata/server_reject_no_user_name.json",
+		"testdata/server_reject_remote_management_no_client_ca.json",
+		"testdata/server_reject_replay_cache_short_expire_interval.json",
+		"testdata/server_reject_sandbox_relative_writable_path.json",
+		"testdata/server_reject_sandbox_seccomp_allowlist_not_enabled.json",
+		"testdata/server_reject_tls_camouflage_invalid_fallback_address.json",
+		"testdata/server_reject_udp_relay_bind_ip_and_interface.json",
+		"testdata/server_reject_user_group_unknown_user.json",
+		"testdata/server_reject_user_in_multiple_groups.json",
+		"testdata/server_reject_websocket_certificate_without_key.json",
+		"testdata/server_reject_websocket_tcp_same_port.json",
 	}
 
 	for _, c := range cases {
@@ -123,6 +192,50 @@ func TestServerDeleteUser(t *testing.T) {
 	afterServerTest(t)
 }
 
+func TestServerAddUpdateUser(t *testing.T) {
+	beforeServerTest(t)
+
+	configFile := "testdata/server_apply_config_2.json"
+	if err := ApplyJSONServerConfig(configFile); err != nil {
+		t.Fatalf("ApplyJSONServerConfig() failed: %v", err)
+	}
+
+	newUser := &pb.User{Name: proto.String("user5"), Password: proto.String("5d8e1f2a9c3b")}
+	if err := AddServerUser(newUser); err != nil {
+		t.Fatalf("AddServerUser() failed: %v", err)
+	}
+	if err := AddServerUser(&pb.User{Name: proto.String("user1"), Password: proto.String("5d8e1f2a9c3b")}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("AddServerUser() with existing user got %v, want %v", err, codes.AlreadyExists)
+	}
+	if err := AddServerUser(&pb.User{Name: proto.String("user6")}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("AddServerUser() without password got %v, want %v", err, codes.InvalidArgument)
+	}
+	if err := UpdateServerUser(&pb.User{Name: proto.String("user7"), Password: proto.String("5d8e1f2a9c3b")}); status.Code(err) != codes.NotFound {
+		t.Errorf("UpdateServerUser() with missing user got %v, want %v", err, codes.NotFound)
+	}
+	if err := UpdateServerUser(&pb.User{Name: proto.String("user2"), Password: proto.String("0a1b2c3d4e5f")}); err != nil {
+		t.Fatalf("UpdateServerUser() failed: %v", err)
+	}
+
+	config, err := LoadServerConfig()
+	if err != nil {
+		t.Fatalf("LoadServerConfig() failed: %v", err)
+	}
+	users := UserListToMap(config.GetUsers())
+	if len(users) != 3 {
+		t.Fatalf("want 3 users, got %d user(s)", len(users))
+	}
+	if users["user5"].GetHashedPassword() == "" || users["user5"].GetPassword() != "" {
+		t.Errorf("password of the added user is not hashed")
+	}
+	want := HashUserPassword(&pb.User{Name: proto.String("user2"), Password: proto.String("0a1b2c3d4e5f")}, false)
+	if !proto.Equal(users["user2"], want) {
+		t.Errorf("updated user = %v, want %v", users["user2"], want)
+	}
+
+	afterServerTest(t)
+}
+
 func TestServerHashUserPassword(t *testing.T) {
 	beforeServerTest(t)
 
@@ -158,6 +271,99 @@ func TestServerGetVersion(t *testing.T) {
 	}
 }
 
+func TestUDPRelayToSocks5(t *testing.T) {
+	relay, err := UDPRelayToSocks5(nil)
+	if err != nil {
+		t.Fatalf("UDPRelayToSocks5() failed: %v", err)
+	}
+	if relay.IP != nil || relay.MinPort != 0 || relay.MaxPort != 0 {
+		t.Errorf("UDPRelayToSocks5(nil) = %+v, want zero value", relay)
+	}
+
+	relay, err = UDPRelayToSocks5(&pb.UDPRelay{
+		PortRange: proto.String("20000-20999"),
+		BindIP:    proto.String("192.168.1.2"),
+	})
+	if err != nil {
+		t.Fatalf("UDPRelayToSocks5() failed: %v", err)
+	}
+	if !relay.IP.Equal(net.ParseIP("192.168.1.2")) || relay.MinPort != 20000 || relay.MaxPort != 20999 {
+		t.Errorf("UDPRelayToSocks5() = %+v, want 192.168.1.2 with port range 20000-20999", relay)
+	}
+
+	if _, err = UDPRelayToSocks5(&pb.UDPRelay{BindInterface: proto.String("no-such-interface")}); err == nil {
+		t.Errorf("UDPRelayToSocks5() succeeded with unknown network interface")
+	}
+}
+
+func TestServerTLSCamouflageOptions(t *testing.T) {
+	config := &pb.TLSCamouflageConfig{
+		ServerName: proto.String("www.example.com"),
+	}
+	opts, err := serverTLSCamouflageOptions(config)
+	if err != nil {
+		t.Fatalf("serverTLSCamouflageOptions() failed: %v", err)
+	}
+	if len(opts.TLSConfig.Certificates) != 1 {
+		t.Fatalf("got %d certificates, want 1", len(opts.TLSConfig.Certificates))
+	}
+	cert, err := x509.ParseCertificate(opts.TLSConfig.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate() failed: %v", err)
+	}
+	if err := cert.VerifyHostname("www.example.com"); err != nil {
+		t.Errorf("VerifyHostname() failed: %v", err)
+	}
+	if len(opts.TLSConfig.NextProtos) != 1 || opts.TLSConfig.NextProtos[0] != "http/1.1" {
+		t.Errorf("ALPN = %v, want [http/1.1]", opts.TLSConfig.NextProtos)
+	}
+
+	// The self-signed certificate is reused, so the port bindings
+	// are not restarted when the server config is reloaded.
+	opts2, err := serverTLSCamouflageOptions(config)
+	if err != nil {
+		t.Fatalf("serverTLSCamouflageOptions() failed: %v", err)
+	}
+	if !bytes.Equal(opts.TLSConfig.Certificates[0].Certificate[0], opts2.TLSConfig.Certificates[0].Certificate[0]) {
+		t.Errorf("self-signed certificate is not reused")
+	}
+
+	config.CertificateFile = proto.String("/no/such/cert.pem")
+	config.PrivateKeyFile = proto.String("/no/such/key.pem")
+	if _, err := serverTLSCamouflageOptions(config); err == nil {
+		t.Errorf("serverTLSCamouflageOptions() succeeded without certificate file")
+	}
+}
+
+func TestNewServerCountryStats(t *testing.T) {
+	stats, db, err := NewServerCountryStats(&pb.CountryTrafficStatistics{GeoIPDatabase: proto.String("geoip.csv")})
+	if err != nil || stats != nil || db != nil {
+		t.Errorf("NewServerCountryStats() = %v, %v, %v, want all nil when disabled", stats, db, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "geoip.csv")
+	if err := os.WriteFile(path, []byte("1.0.0.0,1.0.0.255,AU\n"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	stats, db, err = NewServerCountryStats(&pb.CountryTrafficStatistics{
+		Enable:        proto.Bool(true),
+		GeoIPDatabase: proto.String(path),
+	})
+	if err != nil {
+		t.Fatalf("NewServerCountryStats() failed: %v", err)
+	}
+	if stats == nil || db == nil || db.Len() != 1 {
+		t.Errorf("NewServerCountryStats() didn't create statistics with the GeoIP database")
+	}
+
+	if _, _, err = NewServerCountryStats(&pb.CountryTrafficStatistics{
+		Enable:        proto.Bool(true),
+		GeoIPDatabase: proto.String(filepath.Join(t.TempDir(), "not-exist.csv")),
+	}); err == nil {
+		t.Errorf("NewServerCountryStats() succeeded with a missing GeoIP database")
+	}
+}
+
 func beforeServerTest(t *testing.T) {
 	dir := os.TempDir()
 	if dir == "" {
@@ -178,3 +384,48 @@ func afterServerTest(t *testing.T) {
 		t.Fatalf("failed to clean server config file after the test")
 	}
 }
+
+func TestRedactServerConfig(t *testing.T) {
+	secrets := []string{"userpassword", "gatewaytoken0123456789", "clustertoken0123456789", "egresspassword", "gatewayclustertoken0123"}
+	config := &pb.ServerConfig{
+		Users: []*pb.User{
+			{
+				Name:     proto.String("user1"),
+				Password: proto.String(secrets[0]),
+			},
+		},
+		ManagementGateway: &pb.ManagementGateway{
+			Port:          proto.Int32(8080),
+			Tokens:        []string{secrets[1]},
+			ClusterTokens: []string{secrets[4]},
+		},
+		Cluster: &pb.ClusterConfig{
+			PrimaryURL: proto.String("https://primary.example.com:8080"),
+			Token:      proto.String(secrets[2]),
+		},
+		Egress: &pb.Egress{
+			Proxies: []*pb.EgressProxy{
+				{
+					Name: proto.String("cloudflare"),
+					Socks5Authentication: &pb.Auth{
+						User:     proto.String("shilishanlu"),
+						Password: proto.String(secrets[3]),
+					},
+				},
+			},
+		},
+	}
+	redacted := RedactServerConfig(config)
+	text := redacted.String()
+	for _, secret := range secrets {
+		if strings.Contains(text, secret) {
+			t.Errorf("%q is not redacted", secret)
+		}
+	}
+	if redacted.GetUsers()[0].GetName() != "user1" {
+		t.Errorf("user name is redacted")
+	}
+	if config.GetUsers()[0].GetPassword() != secrets[0] {
+		t.Errorf("RedactServerConfig() modified the original config")
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"github.com/enfein/mieru/v3/pkg/sockopts"
+	"github.com/enfein/mieru/v3/pkg/socks5"
+)
+
+// ClientSocks5Listeners runs the additional local socks5 listeners
+// in the client config. Each listener has its own socks5 server.
+type ClientSocks5Listeners struct {
+	listeners []*pb.Socks5Listener
+	servers   []*socks5.Server
+	muxes     []*protocol.Mux // multiplexers owned by the listeners
+}
+
+// NewClientSocks5Listeners creates the socks5 servers of the additional
+// listeners. base is the config of the main socks5 server, and mux is
+// the multiplexer of the active profile. The listeners without a profile
+// share the multiplexer and the tunnel observer of the main socks5 server.
+func NewClientSocks5Listeners(config *pb.ClientConfig, mux *protocol.Mux, base socks5.Config) (*ClientSocks5Listeners, error) {
+	l := &ClientSocks5Listeners{}
+	for _, listener := range config.GetSocks5Listeners() {
+		c := base
+		if listener.GetProfile() != "" {
+			profile, err := GetActiveProfileFromConfig(config, listener.GetProfile())
+			if err != nil {
+				l.Close()
+				return nil, err
+			}
+			profileMux, err := newClientProfileMux(config, profile, base.Resolver)
+			if err != nil {
+				l.Close()
+				return nil, fmt.Errorf("failed to create multiplexer of profile %q: %w", profile.GetProfileName(), err)
+			}
+			l.muxes = append(l.muxes, profileMux)
+			c.ProxyMux = profileMux
+			c.TunnelObserver = nil
+		} else {
+			c.ProxyMux = mux
+		}
+		if listener.Bypass != nil {
+			bypass, err := BypassListFromConfig(listener.GetBypass(), config.GetGeoDatabases())
+			if err != nil {
+				l.Close()
+				return nil, err
+			}
+			c.Bypass = bypass
+		}
+		c.ResolveLocally = listener.GetDnsMode() == pb.DNSMode_DNS_MODE_LOCAL
+		c.DirectFallback = listener.GetDirectFallback()
+		server, err := socks5.New(&c)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		l.listeners = append(l.listeners, listener)
+		l.servers = append(l.servers, server)
+	}
+	return l, nil
+}
+
+// Start listens to the ports of the socks5 listeners, and serves
+// the requests in the background.
+func (l *ClientSocks5Listeners) Start() error {
+	for i, listener := range l.listeners {
+		addr := common.MaybeDecorateIPv6(common.LocalIPAddr()) + ":" + strconv.Itoa(int(listener.GetPort()))
+		if listener.GetListenLAN() {
+			addr = common.MaybeDecorateIPv6(common.AllIPAddr()) + ":" + strconv.Itoa(int(listener.GetPort()))
+		}
+		tcpAddr, err := apicommon.ResolveTCPAddr(&net.Resolver{}, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("resolve socks5 listener address %q failed: %w", addr, err)
+		}
+		tcpListener, err := net.ListenTCP("tcp", tcpAddr)
+		if err != nil {
+			return fmt.Errorf("listen on socks5 listener address %q failed: %w", addr, err)
+		}
+		if err := sockopts.ApplyTCPControls(tcpListener); err != nil {
+			tcpListener.Close()
+			return fmt.Errorf("ApplyTCPControls() failed: %w", err)
+		}
+		server := l.servers[i]
+		go func() {
+			log.Infof("mieru client socks5 listener %v is running", addr)
+			if err := server.Serve(tcpListener); err != nil {
+				log.Errorf("run socks5 listener %v failed: %v", addr, err)
+			}
+		}()
+	}
+	return nil
+}
+
+// SetIngressCredentials updates the socks5 authentication of all the listeners.
+func (l *ClientSocks5Listeners) SetIngressCredentials(credentials []socks5.Credential) {
+	for _, server := range l.servers {
+		server.SetIngressCredentials(credentials)
+	}
+}
+
+// SetGSSAPIAcceptor updates the socks5 GSSAPI authentication of all the listeners.
+func (l *ClientSocks5Listeners) SetGSSAPIAcceptor(acceptor *socks5.GSSAPIAcceptor) {
+	for _, server := range l.servers {
+		server.SetGSSAPIAcceptor(acceptor)
+	}
+}
+
+// Close stops the socks5 listeners and their multiplexers.
+func (l *ClientSocks5Listeners) Close() {
+	for _, server := range l.servers {
+		server.Close()
+	}
+	for _, mux := range l.muxes {
+		mux.Close()
+	}
+}
+
+// newClientProfileMux creates a client multiplexer that connects to the
+// proxy servers of the profile.
+func newClientProfileMux(config *pb.ClientConfig, profile *pb.ClientProfile, resolver apicommon.DNSResolver) (*protocol.Mux, error) {
+	multiplexFactor := 1
+	switch profile.GetMultiplexing().GetLevel() {
+	case pb.MultiplexingLevel_MULTIPLEXING_OFF:
+		multiplexFactor = 0
+	case pb.MultiplexingLevel_MULTIPLEXING_LOW:
+		multiplexFactor = 1
+	case pb.MultiplexingLevel_MULTIPLEXING_MIDDLE:
+		multiplexFactor = 2
+	case pb.MultiplexingLevel_MULTIPLEXING_HIGH:
+		multiplexFactor = 3
+	}
+	mux := protocol.NewMux(true)
+	mux = mux.SetClientMultiplexFactor(multiplexFactor)
+	mux = mux.SetClientMaxUnderlays(int(profile.GetMultiplexing().GetMaxConnections()))
+	mux = mux.SetClientPreferLowLatency(config.GetAdvancedSettings().GetSelectServerByLatency())
+	mux = mux.SetClientPathMTUDiscovery(profile.GetPathMTUDiscovery())
+	mux = mux.SetClientMultipathDevices(profile.GetMultipath().GetInterfaces())
+	mux = mux.SetClientLocalBinding(appctlcommon.ClientLocalBinding(profile))
+	mux = mux.SetClientHeartbeat(appctlcommon.HeartbeatFromConfig(profile.GetHeartbeat()))
+	mux = mux.SetClientReconnect(appctlcommon.ReconnectPolicyFromConfig(profile.GetReconnect()))
+	if err := applyClientProfileToMux(mux, profile, resolver); err != nil {
+		mux.Close()
+		return nil, err
+	}
+	return mux, nil
+}
@@ -0,0 +1,340 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultSubscriptionRefreshInterval = 6 * time.Hour
+	minSubscriptionRefreshInterval     = 5 * time.Minute
+
+	// subscriptionCheckInterval is how often the updater checks
+	// if a subscription should be fetched.
+	subscriptionCheckInterval = time.Minute
+
+	// subscriptionFetchTimeout is the maximum duration to fetch
+	// a subscription document.
+	subscriptionFetchTimeout = 30 * time.Second
+
+	// maxSubscriptionDocumentSize is the maximum size of
+	// a subscription document in bytes.
+	maxSubscriptionDocumentSize = 1024 * 1024
+)
+
+var (
+	SubscriptionUpdates        = metrics.RegisterMetric("subscription", "Updates", metrics.COUNTER)
+	SubscriptionUpdateFailures = metrics.RegisterMetric("subscription", "UpdateFailures", metrics.COUNTER)
+)
+
+// subscriptionHTTPClient fetches the subscription documents.
+// Subscriptions are fetched directly rather than through the proxy,
+// so they can be updated when the proxy servers are not reachable.
+var subscriptionHTTPClient = &http.Client{Timeout: subscriptionFetchTimeout}
+
+// SubscriptionProfileName returns the name of a profile from
+// a subscription in the client config.
+func SubscriptionProfileName(subscription, profile string) string {
+	return subscription + "/" + profile
+}
+
+// validateSubscription validates a subscription in the client config.
+func validateSubscription(subscription *pb.Subscription) error {
+	name := subscription.GetName()
+	if name == "" {
+		return fmt.Errorf("subscription name is not set")
+	}
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("subscription name %q contains \"/\"", name)
+	}
+	u, err := url.Parse(subscription.GetUrl())
+	if err != nil {
+		return fmt.Errorf("subscription %q URL is invalid: %w", name, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("subscription %q URL %q is not an HTTPS URL", name, subscription.GetUrl())
+	}
+	if _, err := subscriptionRefreshInterval(subscription); err != nil {
+		return err
+	}
+	return nil
+}
+
+// subscriptionRefreshInterval returns the interval to fetch the subscription.
+func subscriptionRefreshInterval(subscription *pb.Subscription) (time.Duration, error) {
+	if subscription.GetRefreshInterval() == "" {
+		return defaultSubscriptionRefreshInterval, nil
+	}
+	d, err := time.ParseDuration(subscription.GetRefreshInterval())
+	if err != nil {
+		return 0, fmt.Errorf("subscription %q refresh interval %q is invalid: %w", subscription.GetName(), subscription.GetRefreshInterval(), err)
+	}
+	if d < minSubscriptionRefreshInterval {
+		return 0, fmt.Errorf("subscription %q refresh interval %q is less than %v", subscription.GetName(), subscription.GetRefreshInterval(), minSubscriptionRefreshInterval)
+	}
+	return d, nil
+}
+
+// fetchSubscription downloads and parses the subscription document.
+func fetchSubscription(ctx context.Context, subscription *pb.Subscription) (*pb.SubscriptionDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, subscription.GetUrl(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext() failed: %w", err)
+	}
+	resp, err := subscriptionHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch subscription %q failed: %w", subscription.GetName(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch subscription %q failed: HTTP status %q", subscription.GetName(), resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxSubscriptionDocumentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch subscription %q failed: %w", subscription.GetName(), err)
+	}
+	if len(b) > maxSubscriptionDocumentSize {
+		return nil, fmt.Errorf("subscription %q document is larger than %d bytes", subscription.GetName(), maxSubscriptionDocumentSize)
+	}
+	doc := &pb.SubscriptionDocument{}
+	if err := common.UnmarshalJSON(b, doc); err != nil {
+		return nil, fmt.Errorf("subscription %q document is invalid: %w", subscription.GetName(), err)
+	}
+	return doc, nil
+}
+
+// mergeSubscription replaces the profiles of the subscription in the
+// client config with the profiles in the document.
+//
+// The profiles of the subscription are added to the end of the failover
+// backup profiles, so the client switches to them when the profiles with
+// higher priority are not reachable. A profile that is removed from the
+// subscription is kept if it is still used by the client. If the client
+// config doesn't have an active profile, the first profile in the
+// document becomes the active profile.
+func mergeSubscription(config *pb.ClientConfig, subscription *pb.Subscription, doc *pb.SubscriptionDocument) error {
+	if len(doc.GetProfiles()) == 0 {
+		return fmt.Errorf("subscription %q document has no profile", subscription.GetName())
+	}
+	fetched := make(map[string]*pb.ClientProfile)
+	var fetchedNames []string
+	for _, profile := range doc.GetProfiles() {
+		if err := appctlcommon.ValidateClientConfigSingleProfile(profile); err != nil {
+			return fmt.Errorf("subscription %q has invalid profile: %w", subscription.GetName(), err)
+		}
+		p := proto.Clone(profile).(*pb.ClientProfile)
+		p.ProfileName = proto.String(SubscriptionProfileName(subscription.GetName(), profile.GetProfileName()))
+		// Same as the stored profiles, so an unchanged document
+		// doesn't change the client config.
+		p.User = HashUserPassword(p.GetUser(), true)
+		if _, found := fetched[p.GetProfileName()]; found {
+			return fmt.Errorf("subscription %q has duplicated profile %q", subscription.GetName(), profile.GetProfileName())
+		}
+		fetched[p.GetProfileName()] = p
+		fetchedNames = append(fetchedNames, p.GetProfileName())
+	}
+
+	inUse := map[string]bool{config.GetActiveProfile(): true}
+	for _, listener := range config.GetSocks5Listeners() {
+		inUse[listener.GetProfile()] = true
+	}
+	prefix := SubscriptionProfileName(subscription.GetName(), "")
+	profiles := make([]*pb.ClientProfile, 0, len(config.GetProfiles())+len(fetched))
+	for _, profile := range config.GetProfiles() {
+		name := profile.GetProfileName()
+		if strings.HasPrefix(name, prefix) {
+			if _, found := fetched[name]; found {
+				continue
+			}
+			if !inUse[name] {
+				log.Infof("Profile %q is removed from subscription %q", name, subscription.GetName())
+				continue
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	for _, name := range fetchedNames {
+		profiles = append(profiles, fetched[name])
+	}
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].GetProfileName() < profiles[j].GetProfileName()
+	})
+	config.Profiles = profiles
+
+	if config.GetActiveProfile() == "" {
+		config.ActiveProfile = proto.String(fetchedNames[0])
+	}
+	exists := make(map[string]bool)
+	for _, profile := range profiles {
+		exists[profile.GetProfileName()] = true
+	}
+	var backups []string
+	listed := map[string]bool{config.GetActiveProfile(): true}
+	for _, name := range config.GetFailover().GetBackupProfiles() {
+		if exists[name] && !listed[name] {
+			backups = append(backups, name)
+			listed[name] = true
+		}
+	}
+	for _, name := range fetchedNames {
+		if !listed[name] {
+			backups = append(backups, name)
+			listed[name] = true
+		}
+	}
+	if config.Failover == nil {
+		config.Failover = &pb.ProfileFailover{}
+	}
+	config.Failover.BackupProfiles = backups
+	return nil
+}
+
+// UpdateClientSubscriptions fetches all the subscriptions in the client
+// config, and stores the merged profiles. It returns true if the client
+// config is changed. A subscription that fails to update doesn't stop
+// the others.
+func UpdateClientSubscriptions(ctx context.Context) (bool, error) {
+	return updateClientSubscriptions(ctx, func(*pb.Subscription) bool { return true })
+}
+
+// updateClientSubscriptions fetches the subscriptions selected by due,
+// and stores the merged profiles.
+func updateClientSubscriptions(ctx context.Context, due func(*pb.Subscription) bool) (bool, error) {
+	config, err := LoadClientConfig()
+	if err != nil {
+		return false, fmt.Errorf("LoadClientConfig() failed: %w", err)
+	}
+	if len(config.GetSubscriptions()) == 0 {
+		return false, fmt.Errorf("client config has no subscription")
+	}
+	updated := proto.Clone(config).(*pb.ClientConfig)
+	var errs []error
+	for _, subscription := range config.GetSubscriptions() {
+		if !due(subscription) {
+			continue
+		}
+		fetchCtx, cancelFunc := context.WithTimeout(ctx, subscriptionFetchTimeout)
+		doc, err := fetchSubscription(fetchCtx, subscription)
+		cancelFunc()
+		if err == nil {
+			err = mergeSubscription(updated, subscription, doc)
+		}
+		if err != nil {
+			SubscriptionUpdateFailures.Add(1)
+			errs = append(errs, err)
+			continue
+		}
+		SubscriptionUpdates.Add(1)
+		log.Infof("Subscription %q is updated with %d profiles", subscription.GetName(), len(doc.GetProfiles()))
+	}
+	if proto.Equal(config, updated) {
+		return false, errors.Join(errs...)
+	}
+	if err := ValidateFullClientConfig(updated); err != nil {
+		return false, fmt.Errorf("ValidateFullClientConfig() failed: %w", err)
+	}
+	if err := StoreClientConfig(updated); err != nil {
+		return false, fmt.Errorf("StoreClientConfig() failed: %w", err)
+	}
+	return true, errors.Join(errs...)
+}
+
+// ClientSubscriptionUpdater fetches the subscriptions of the running
+// client when they are due, and reloads the client config if the
+// profiles are changed.
+type ClientSubscriptionUpdater struct {
+	mu         sync.Mutex
+	lastUpdate map[string]time.Time // subscription name -> time of last fetch
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewClientSubscriptionUpdater creates a ClientSubscriptionUpdater.
+func NewClientSubscriptionUpdater() *ClientSubscriptionUpdater {
+	return &ClientSubscriptionUpdater{
+		lastUpdate: make(map[string]time.Time),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start fetches the subscriptions in the background until Close is called.
+// The subscriptions are fetched once at the beginning.
+func (u *ClientSubscriptionUpdater) Start() {
+	go func() {
+		for {
+			u.update()
+			select {
+			case <-time.After(subscriptionCheckInterval):
+			case <-u.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops fetching the subscriptions.
+func (u *ClientSubscriptionUpdater) Close() {
+	u.closeOnce.Do(func() {
+		close(u.done)
+	})
+}
+
+func (u *ClientSubscriptionUpdater) update() {
+	config, err := LoadClientConfig()
+	if err != nil || len(config.GetSubscriptions()) == 0 {
+		return
+	}
+	now := time.Now()
+	changed, err := updateClientSubscriptions(context.Background(), func(subscription *pb.Subscription) bool {
+		interval, err := subscriptionRefreshInterval(subscription)
+		if err != nil {
+			return false
+		}
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		if last, found := u.lastUpdate[subscription.GetName()]; found && now.Sub(last) < interval {
+			return false
+		}
+		u.lastUpdate[subscription.GetName()] = now
+		return true
+	})
+	if err != nil {
+		log.Warnf("Failed to update subscriptions: %v", err)
+	}
+	if changed {
+		if err := ReloadClientConfig(false); err != nil {
+			log.Errorf("Failed to reload client config after subscriptions are updated: %v", err)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func subscriptionTestProfile(name string) *pb.ClientProfile {
+	return &pb.ClientProfile{
+		ProfileName: proto.String(name),
+		User: &pb.User{
+			Name:     proto.String("user1"),
+			Password: proto.String("fa7206ed2a94"),
+		},
+		Servers: []*pb.ServerEndpoint{
+			{
+				IpAddress: proto.String("192.0.2.1"),
+				PortBindings: []*pb.PortBinding{
+					{Port: proto.Int32(8964), Protocol: pb.TransportProtocol_TCP.Enum()},
+				},
+			},
+		},
+	}
+}
+
+func profileNames(config *pb.ClientConfig) []string {
+	var names []string
+	for _, profile := range config.GetProfiles() {
+		names = append(names, profile.GetProfileName())
+	}
+	return names
+}
+
+func TestValidateSubscription(t *testing.T) {
+	testcases := []struct {
+		subscription *pb.Subscription
+		wantErr      bool
+	}{
+		{&pb.Subscription{Name: proto.String("sub"), Url: proto.String("https://example.com/sub.json")}, false},
+		{&pb.Subscription{Name: proto.String("sub"), Url: proto.String("https://example.com/sub.json"), RefreshInterval: proto.String("1h")}, false},
+		{&pb.Subscription{Url: proto.String("https://example.com/sub.json")}, true},
+		{&pb.Subscription{Name: proto.String("a/b"), Url: proto.String("https://example.com/sub.json")}, true},
+		{&pb.Subscription{Name: proto.String("sub"), Url: proto.String("http://example.com/sub.json")}, true},
+		{&pb.Subscription{Name: proto.String("sub"), Url: proto.String("https://example.com/sub.json"), RefreshInterval: proto.String("1m")}, true},
+	}
+	for _, tc := range testcases {
+		err := validateSubscription(tc.subscription)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("validateSubscription(%v) returned error %v, want error %v", tc.subscription, err, tc.wantErr)
+		}
+	}
+}
+
+func TestMergeSubscription(t *testing.T) {
+	config := &pb.ClientConfig{
+		ActiveProfile: proto.String("default"),
+		Profiles: []*pb.ClientProfile{
+			subscriptionTestProfile("default"),
+			subscriptionTestProfile("sub/old"),
+			subscriptionTestProfile("sub/used"),
+			subscriptionTestProfile("sub/b"),
+		},
+		Failover: &pb.ProfileFailover{
+			BackupProfiles: []string{"sub/old", "sub/b"},
+		},
+		Socks5Listeners: []*pb.Socks5Listener{
+			{Port: proto.Int32(1081), Profile: proto.String("sub/used")},
+		},
+	}
+	subscription := &pb.Subscription{Name: proto.String("sub"), Url: proto.String("https://example.com/sub.json")}
+	doc := &pb.SubscriptionDocument{
+		Profiles: []*pb.ClientProfile{
+			subscriptionTestProfile("b"),
+			subscriptionTestProfile("a"),
+		},
+	}
+	if err := mergeSubscription(config, subscription, doc); err != nil {
+		t.Fatalf("mergeSubscription() failed: %v", err)
+	}
+	wantProfiles := []string{"default", "sub/a", "sub/b", "sub/used"}
+	if got := profileNames(config); !reflect.DeepEqual(got, wantProfiles) {
+		t.Errorf("got profiles %v, want %v", got, wantProfiles)
+	}
+	wantBackups := []string{"sub/b", "sub/a"}
+	if got := config.GetFailover().GetBackupProfiles(); !reflect.DeepEqual(got, wantBackups) {
+		t.Errorf("got backup profiles %v, want %v", got, wantBackups)
+	}
+	if config.GetActiveProfile() != "default" {
+		t.Errorf("active profile is changed to %q", config.GetActiveProfile())
+	}
+
+	// Reject a document with duplicated profiles.
+	doc.Profiles = append(doc.Profiles, subscriptionTestProfile("a"))
+	if err := mergeSubscription(config, subscription, doc); err == nil {
+		t.Errorf("mergeSubscription() didn't reject duplicated profiles")
+	}
+}
+
+func TestUpdateClientSubscriptions(t *testing.T) {
+	beforeClientTest(t)
+
+	var doc atomic.Value
+	doc.Store(`{"profiles": [{"profileName": "p1", "user": {"name": "user1", "password": "fa7206ed2a94"}, "servers": [{"ipAddress": "192.0.2.1", "portBindings": [{"port": 8964, "protocol": "TCP"}]}]}]}`)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(doc.Load().(string)))
+	}))
+	defer server.Close()
+	origClient := subscriptionHTTPClient
+	subscriptionHTTPClient = server.Client()
+	defer func() {
+		subscriptionHTTPClient = origClient
+	}()
+
+	// Apply a config without profiles. The subscription is fetched.
+	patch := &pb.ClientConfig{
+		RpcPort:    proto.Int32(8964),
+		Socks5Port: proto.Int32(1080),
+		Subscriptions: []*pb.Subscription{
+			{Name: proto.String("sub"), Url: proto.String(server.URL)},
+		},
+	}
+	if err := applyClientConfig(patch); err != nil {
+		t.Fatalf("applyClientConfig() failed: %v", err)
+	}
+	config, err := LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+	if config.GetActiveProfile() != "sub/p1" {
+		t.Errorf("active profile is %q, want %q", config.GetActiveProfile(), "sub/p1")
+	}
+
+	changed, err := UpdateClientSubscriptions(context.Background())
+	if err != nil {
+		t.Fatalf("UpdateClientSubscriptions() failed: %v", err)
+	}
+	if changed {
+		t.Errorf("UpdateClientSubscriptions() changed the config, but the subscription is the same")
+	}
+
+	doc.Store(`{"profiles": [{"profileName": "p2", "user": {"name": "user1", "password": "fa7206ed2a94"}, "servers": [{"domainName": "example.com", "portBindings": [{"port": 8964, "protocol": "TCP"}]}]}]}`)
+	changed, err = UpdateClientSubscriptions(context.Background())
+	if err != nil {
+		t.Fatalf("UpdateClientSubscriptions() failed: %v", err)
+	}
+	if !changed {
+		t.Errorf("UpdateClientSubscriptions() didn't change the config")
+	}
+	config, err = LoadClientConfig()
+	if err != nil {
+		t.Fatalf("LoadClientConfig() failed: %v", err)
+	}
+	// The active profile is kept.
+	want := []string{"sub/p1", "sub/p2"}
+	if got := profileNames(config); !reflect.DeepEqual(got, want) {
+		t.Errorf("got profiles %v, want %v", got, want)
+	}
+
+	afterClientTest(t)
+}
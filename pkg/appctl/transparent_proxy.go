@@ -0,0 +1,256 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os/user"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+)
+
+// transparentProxyChain is the iptables chain that redirects TCP
+// connections to the transparent proxy.
+const transparentProxyChain = "MIERU"
+
+// directIPv4CIDRs and directIPv6CIDRs are the destinations that are never
+// sent to the proxy: local, private, link local, multicast and reserved
+// addresses.
+var (
+	directIPv4CIDRs = []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"224.0.0.0/4",
+		"240.0.0.0/4",
+	}
+	directIPv6CIDRs = []string{
+		"::1/128",
+		"fc00::/7",
+		"fe80::/10",
+		"ff00::/8",
+	}
+)
+
+// transparentProxyUserPattern matches a user name or a numeric user ID.
+var transparentProxyUserPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// currentUser returns the user that runs this process.
+// It is replaced in tests.
+var currentUser = user.Current
+
+// shellSafePattern matches the strings that don't need quoting in a shell.
+var shellSafePattern = regexp.MustCompile(`^[A-Za-z0-9_./:@=+-]+$`)
+
+// validateTransparentProxy validates the users and cgroups of the
+// transparent proxy.
+func validateTransparentProxy(config *pb.TransparentProxy) error {
+	for _, user := range config.GetUsers() {
+		if !transparentProxyUserPattern.MatchString(user) {
+			return fmt.Errorf("transparent proxy user %q is invalid", user)
+		}
+	}
+	for _, cgroup := range config.GetCgroups() {
+		if strings.Trim(cgroup, "/") == "" {
+			return fmt.Errorf("transparent proxy cgroup %q is invalid", cgroup)
+		}
+		for _, name := range strings.Split(cgroup, "/") {
+			if name == "." || name == ".." {
+				return fmt.Errorf("transparent proxy cgroup %q is invalid", cgroup)
+			}
+		}
+		if strings.IndexFunc(cgroup, unicode.IsControl) >= 0 {
+			return fmt.Errorf("transparent proxy cgroup %q contains a control character", cgroup)
+		}
+	}
+	return nil
+}
+
+// TransparentProxyFirewallRules returns the iptables and ip6tables commands
+// that redirect the TCP connections to the transparent proxy. If remove is
+// true, the commands delete the rules instead. The connections to proxy
+// servers, upstream proxies and bootstrap DNS over HTTPS servers, the
+// connections of the user that runs mieru client, the connections with the
+// routing mark of a profile, and the connections to local, private and
+// multicast addresses are not redirected.
+func TransparentProxyFirewallRules(ctx context.Context, config *pb.ClientConfig, resolver apicommon.DNSResolver, remove bool) ([]string, error) {
+	tproxy := config.GetTransparentProxy()
+	if tproxy.GetPort() == 0 {
+		return nil, fmt.Errorf("transparent proxy is not enabled")
+	}
+	directIPs, err := transparentProxyDirectIPs(ctx, config, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	// The direct connections of mieru client, for example the connections
+	// matching bypass rules, must not be redirected back to the transparent
+	// proxy. They are excluded by the user ID, so the rules must be printed
+	// by the user that runs mieru client.
+	owner, err := currentUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	for _, u := range tproxy.GetUsers() {
+		if u == owner.Uid || u == owner.Username {
+			return nil, fmt.Errorf("transparent proxy user %q runs mieru client, its connections can't be redirected", u)
+		}
+	}
+
+	marks := make(map[uint32]struct{})
+	var markList []uint32
+	for _, profile := range config.GetProfiles() {
+		if mark := profile.GetRoutingMark(); mark != 0 {
+			if _, found := marks[mark]; !found {
+				marks[mark] = struct{}{}
+				markList = append(markList, mark)
+			}
+		}
+	}
+
+	// Connections that match these rules in the OUTPUT chain
+	// jump to the transparent proxy chain.
+	var matches []string
+	for _, user := range tproxy.GetUsers() {
+		matches = append(matches, "-p tcp -m owner --uid-owner "+user)
+	}
+	for _, cgroup := range tproxy.GetCgroups() {
+		matches = append(matches, "-p tcp -m cgroup --path "+shellQuote(cgroup))
+	}
+	if len(matches) == 0 {
+		matches = append(matches, "-p tcp")
+	}
+
+	var rules []string
+	for _, cmd := range []string{"iptables", "ip6tables"} {
+		nat := cmd + " -t nat "
+		if remove {
+			for _, match := range matches {
+				rules = append(rules, nat+"-D OUTPUT "+match+" -j "+transparentProxyChain)
+			}
+			rules = append(rules, nat+"-F "+transparentProxyChain, nat+"-X "+transparentProxyChain)
+			continue
+		}
+		rules = append(rules, nat+"-N "+transparentProxyChain)
+		rules = append(rules, nat+"-A "+transparentProxyChain+" -m owner --uid-owner "+owner.Uid+" -j RETURN")
+		directCIDRs := directIPv4CIDRs
+		if cmd == "ip6tables" {
+			directCIDRs = directIPv6CIDRs
+		}
+		for _, cidr := range directCIDRs {
+			rules = append(rules, nat+"-A "+transparentProxyChain+" -d "+cidr+" -j RETURN")
+		}
+		for _, ip := range directIPs {
+			if (ip.To4() != nil) == (cmd == "iptables") {
+				rules = append(rules, nat+"-A "+transparentProxyChain+" -d "+ip.String()+" -j RETURN")
+			}
+		}
+		for _, mark := range markList {
+			rules = append(rules, nat+"-A "+transparentProxyChain+" -m mark --mark "+strconv.FormatUint(uint64(mark), 10)+" -j RETURN")
+		}
+		rules = append(rules, nat+"-A "+transparentProxyChain+" -p tcp -j REDIRECT --to-ports "+strconv.Itoa(int(tproxy.GetPort())))
+		for _, match := range matches {
+			rules = append(rules, nat+"-A OUTPUT "+match+" -j "+transparentProxyChain)
+		}
+	}
+	return rules, nil
+}
+
+// transparentProxyDirectIPs returns the IP addresses of the proxy servers,
+// upstream proxies and bootstrap DNS over HTTPS servers in all the profiles.
+// The connections to them must not be redirected, otherwise the client
+// connects to itself.
+func transparentProxyDirectIPs(ctx context.Context, config *pb.ClientConfig, resolver apicommon.DNSResolver) ([]net.IP, error) {
+	var ips []net.IP
+	seen := make(map[string]struct{})
+	add := func(host string) error {
+		var hostIPs []net.IP
+		if ip := net.ParseIP(host); ip != nil {
+			hostIPs = []net.IP{ip}
+		} else {
+			var err error
+			hostIPs, err = resolver.LookupIP(ctx, "ip", host)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %q: %w", host, err)
+			}
+		}
+		for _, ip := range hostIPs {
+			if _, found := seen[ip.String()]; !found {
+				seen[ip.String()] = struct{}{}
+				ips = append(ips, ip)
+			}
+		}
+		return nil
+	}
+	for _, profile := range config.GetProfiles() {
+		for _, server := range profile.GetServers() {
+			if server.GetDomainName() != "" {
+				if err := add(server.GetDomainName()); err != nil {
+					return nil, err
+				}
+			} else if server.GetIpAddress() != "" {
+				if err := add(server.GetIpAddress()); err != nil {
+					return nil, err
+				}
+			}
+			for _, ip := range server.GetPinnedIpAddresses() {
+				if err := add(ip); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if address := profile.GetUpstreamProxy().GetAddress(); address != "" {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, fmt.Errorf("upstream proxy address %q is invalid: %w", address, err)
+			}
+			if err := add(host); err != nil {
+				return nil, err
+			}
+		}
+		if dohURL := profile.GetBootstrapDoHURL(); dohURL != "" {
+			u, err := url.Parse(dohURL)
+			if err != nil {
+				return nil, fmt.Errorf("bootstrap DNS over HTTPS URL %q is invalid: %w", dohURL, err)
+			}
+			if err := add(u.Hostname()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ips, nil
+}
+
+// shellQuote quotes the string with single quotes if it is not safe
+// to use in a shell command as is.
+func shellQuote(s string) string {
+	if shellSafePattern.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
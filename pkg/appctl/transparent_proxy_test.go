@@ -0,0 +1,184 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/user"
+	"reflect"
+	"testing"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+type transparentProxyResolver map[string][]net.IP
+
+func (r transparentProxyResolver) LookupIP(_ context.Context, _, host string) ([]net.IP, error) {
+	if ips, ok := r[host]; ok {
+		return ips, nil
+	}
+	return nil, fmt.Errorf("host %q is not found", host)
+}
+
+func mockCurrentUser(t *testing.T, uid, name string) {
+	saved := currentUser
+	currentUser = func() (*user.User, error) {
+		return &user.User{Uid: uid, Username: name}, nil
+	}
+	t.Cleanup(func() { currentUser = saved })
+}
+
+func TestTransparentProxyFirewallRules(t *testing.T) {
+	mockCurrentUser(t, "990", "mieru")
+	resolver := transparentProxyResolver{
+		"proxy.example.com": {net.ParseIP("203.0.113.2"), net.ParseIP("2001:db8::2")},
+	}
+	config := &pb.ClientConfig{
+		Profiles: []*pb.ClientProfile{
+			{
+				ProfileName: proto.String("default"),
+				Servers: []*pb.ServerEndpoint{
+					{IpAddress: proto.String("203.0.113.1")},
+					{DomainName: proto.String("proxy.example.com"), PinnedIpAddresses: []string{"203.0.113.1"}},
+				},
+				RoutingMark: proto.Uint32(100),
+			},
+		},
+		TransparentProxy: &pb.TransparentProxy{
+			Port:    proto.Int32(12345),
+			Users:   []string{"alice", "1001"},
+			Cgroups: []string{"user.slice/app-firefox.scope", "system.slice/docker-a b.scope"},
+		},
+	}
+
+	rules, err := TransparentProxyFirewallRules(context.Background(), config, resolver, false)
+	if err != nil {
+		t.Fatalf("TransparentProxyFirewallRules() failed: %v", err)
+	}
+	want := []string{
+		"iptables -t nat -N MIERU",
+		"iptables -t nat -A MIERU -m owner --uid-owner 990 -j RETURN",
+		"iptables -t nat -A MIERU -d 0.0.0.0/8 -j RETURN",
+		"iptables -t nat -A MIERU -d 10.0.0.0/8 -j RETURN",
+		"iptables -t nat -A MIERU -d 100.64.0.0/10 -j RETURN",
+		"iptables -t nat -A MIERU -d 127.0.0.0/8 -j RETURN",
+		"iptables -t nat -A MIERU -d 169.254.0.0/16 -j RETURN",
+		"iptables -t nat -A MIERU -d 172.16.0.0/12 -j RETURN",
+		"iptables -t nat -A MIERU -d 192.168.0.0/16 -j RETURN",
+		"iptables -t nat -A MIERU -d 224.0.0.0/4 -j RETURN",
+		"iptables -t nat -A MIERU -d 240.0.0.0/4 -j RETURN",
+		"iptables -t nat -A MIERU -d 203.0.113.1 -j RETURN",
+		"iptables -t nat -A MIERU -d 203.0.113.2 -j RETURN",
+		"iptables -t nat -A MIERU -m mark --mark 100 -j RETURN",
+		"iptables -t nat -A MIERU -p tcp -j REDIRECT --to-ports 12345",
+		"iptables -t nat -A OUTPUT -p tcp -m owner --uid-owner alice -j MIERU",
+		"iptables -t nat -A OUTPUT -p tcp -m owner --uid-owner 1001 -j MIERU",
+		"iptables -t nat -A OUTPUT -p tcp -m cgroup --path user.slice/app-firefox.scope -j MIERU",
+		"iptables -t nat -A OUTPUT -p tcp -m cgroup --path 'system.slice/docker-a b.scope' -j MIERU",
+		"ip6tables -t nat -N MIERU",
+		"ip6tables -t nat -A MIERU -m owner --uid-owner 990 -j RETURN",
+		"ip6tables -t nat -A MIERU -d ::1/128 -j RETURN",
+		"ip6tables -t nat -A MIERU -d fc00::/7 -j RETURN",
+		"ip6tables -t nat -A MIERU -d fe80::/10 -j RETURN",
+		"ip6tables -t nat -A MIERU -d ff00::/8 -j RETURN",
+		"ip6tables -t nat -A MIERU -d 2001:db8::2 -j RETURN",
+		"ip6tables -t nat -A MIERU -m mark --mark 100 -j RETURN",
+		"ip6tables -t nat -A MIERU -p tcp -j REDIRECT --to-ports 12345",
+		"ip6tables -t nat -A OUTPUT -p tcp -m owner --uid-owner alice -j MIERU",
+		"ip6tables -t nat -A OUTPUT -p tcp -m owner --uid-owner 1001 -j MIERU",
+		"ip6tables -t nat -A OUTPUT -p tcp -m cgroup --path user.slice/app-firefox.scope -j MIERU",
+		"ip6tables -t nat -A OUTPUT -p tcp -m cgroup --path 'system.slice/docker-a b.scope' -j MIERU",
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("TransparentProxyFirewallRules() = %q, want %q", rules, want)
+	}
+
+	rules, err = TransparentProxyFirewallRules(context.Background(), config, resolver, true)
+	if err != nil {
+		t.Fatalf("TransparentProxyFirewallRules() failed: %v", err)
+	}
+	want = []string{
+		"iptables -t nat -D OUTPUT -p tcp -m owner --uid-owner alice -j MIERU",
+		"iptables -t nat -D OUTPUT -p tcp -m owner --uid-owner 1001 -j MIERU",
+		"iptables -t nat -D OUTPUT -p tcp -m cgroup --path user.slice/app-firefox.scope -j MIERU",
+		"iptables -t nat -D OUTPUT -p tcp -m cgroup --path 'system.slice/docker-a b.scope' -j MIERU",
+		"iptables -t nat -F MIERU",
+		"iptables -t nat -X MIERU",
+		"ip6tables -t nat -D OUTPUT -p tcp -m owner --uid-owner alice -j MIERU",
+		"ip6tables -t nat -D OUTPUT -p tcp -m owner --uid-owner 1001 -j MIERU",
+		"ip6tables -t nat -D OUTPUT -p tcp -m cgroup --path user.slice/app-firefox.scope -j MIERU",
+		"ip6tables -t nat -D OUTPUT -p tcp -m cgroup --path 'system.slice/docker-a b.scope' -j MIERU",
+		"ip6tables -t nat -F MIERU",
+		"ip6tables -t nat -X MIERU",
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("TransparentProxyFirewallRules() = %q, want %q", rules, want)
+	}
+}
+
+func TestTransparentProxyFirewallRulesAllUsers(t *testing.T) {
+	mockCurrentUser(t, "990", "mieru")
+	config := &pb.ClientConfig{
+		Profiles: []*pb.ClientProfile{
+			{
+				ProfileName: proto.String("default"),
+				Servers:     []*pb.ServerEndpoint{{IpAddress: proto.String("2001:db8::1")}},
+			},
+		},
+		TransparentProxy: &pb.TransparentProxy{Port: proto.Int32(12345)},
+	}
+	rules, err := TransparentProxyFirewallRules(context.Background(), config, transparentProxyResolver{}, false)
+	if err != nil {
+		t.Fatalf("TransparentProxyFirewallRules() failed: %v", err)
+	}
+	found := map[string]bool{}
+	for _, rule := range rules {
+		found[rule] = true
+	}
+	for _, rule := range []string{
+		"iptables -t nat -A MIERU -m owner --uid-owner 990 -j RETURN",
+		"iptables -t nat -A OUTPUT -p tcp -j MIERU",
+		"ip6tables -t nat -A MIERU -d 2001:db8::1 -j RETURN",
+		"ip6tables -t nat -A OUTPUT -p tcp -j MIERU",
+	} {
+		if !found[rule] {
+			t.Errorf("rule %q is not found in %q", rule, rules)
+		}
+	}
+
+	config.TransparentProxy = nil
+	if _, err := TransparentProxyFirewallRules(context.Background(), config, transparentProxyResolver{}, false); err == nil {
+		t.Errorf("TransparentProxyFirewallRules() succeeded when transparent proxy is not enabled")
+	}
+}
+
+func TestTransparentProxyFirewallRulesOwnerIsRedirected(t *testing.T) {
+	mockCurrentUser(t, "990", "mieru")
+	for _, u := range []string{"990", "mieru"} {
+		config := &pb.ClientConfig{
+			TransparentProxy: &pb.TransparentProxy{
+				Port:  proto.Int32(12345),
+				Users: []string{"alice", u},
+			},
+		}
+		if _, err := TransparentProxyFirewallRules(context.Background(), config, transparentProxyResolver{}, false); err == nil {
+			t.Errorf("TransparentProxyFirewallRules() succeeded when user %q runs mieru client", u)
+		}
+	}
+}
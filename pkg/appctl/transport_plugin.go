@@ -0,0 +1,133 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"fmt"
+	"sync"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"github.com/enfein/mieru/v3/pkg/transportplugin"
+	"google.golang.org/protobuf/proto"
+)
+
+// runningTransportPlugin is a transport plugin started for a client profile.
+type runningTransportPlugin struct {
+	config *pb.TransportPlugin
+	plugin *transportplugin.Plugin
+}
+
+var (
+	// clientTransportPlugins holds the running transport plugins,
+	// indexed by client profile name.
+	clientTransportPlugins   = map[string]runningTransportPlugin{}
+	clientTransportPluginsMu sync.Mutex
+
+	// clientSIP003Dialers holds the dialers that run SIP003 plugins,
+	// indexed by client profile name.
+	clientSIP003Dialers = map[string]*transportplugin.SIP003Dialer{}
+
+	// serverSIP003 runs the SIP003 plugins of proxy server.
+	serverSIP003 = transportplugin.NewSIP003Server()
+)
+
+// ClientProfileDialer returns the dialer to connect to the proxy servers
+// of the client profile. If the profile has an upstream proxy, proxy servers
+// are connected through it. If the profile has a transport plugin or SIP003
+// plugins, the plugins are started, or reused if they are already running
+// with the same config. Proxy servers and the upstream proxy are connected
+// from the local binding of the profile.
+func ClientProfileDialer(profile *pb.ClientProfile) (apicommon.Dialer, error) {
+	dialer := protocol.NewLocalBindingDialer(appctlcommon.ClientLocalBinding(profile))
+	if upstream := profile.GetUpstreamProxy(); upstream != nil {
+		return appctlcommon.UpstreamProxyDialer(upstream, dialer), nil
+	}
+	if len(profile.GetSip003Plugins()) > 0 {
+		return clientSIP003Dialer(profile), nil
+	}
+	config := profile.GetTransportPlugin()
+	if config == nil {
+		return dialer, nil
+	}
+	clientTransportPluginsMu.Lock()
+	defer clientTransportPluginsMu.Unlock()
+	name := profile.GetProfileName()
+	if running, ok := clientTransportPlugins[name]; ok {
+		if proto.Equal(running.config, config) {
+			return running.plugin, nil
+		}
+		running.plugin.Close()
+		delete(clientTransportPlugins, name)
+	}
+	plugin, err := transportplugin.Start(config)
+	if err != nil {
+		return nil, fmt.Errorf("transportplugin.Start() failed: %w", err)
+	}
+	clientTransportPlugins[name] = runningTransportPlugin{
+		config: proto.Clone(config).(*pb.TransportPlugin),
+		plugin: plugin,
+	}
+	return plugin, nil
+}
+
+// clientSIP003Dialer returns the dialer that runs the SIP003 plugins of
+// the client profile. The dialer is reused if the plugins are not changed.
+func clientSIP003Dialer(profile *pb.ClientProfile) *transportplugin.SIP003Dialer {
+	clientTransportPluginsMu.Lock()
+	defer clientTransportPluginsMu.Unlock()
+	name := profile.GetProfileName()
+	if dialer, ok := clientSIP003Dialers[name]; ok {
+		if transportplugin.SIP003ConfigsEqual(dialer.Configs(), profile.GetSip003Plugins()) {
+			return dialer
+		}
+		dialer.Close()
+	}
+	dialer := transportplugin.NewSIP003Dialer(profile.GetSip003Plugins())
+	clientSIP003Dialers[name] = dialer
+	return dialer
+}
+
+// CloseClientTransportPlugins stops all the running transport plugins
+// and SIP003 plugins.
+func CloseClientTransportPlugins() {
+	clientTransportPluginsMu.Lock()
+	defer clientTransportPluginsMu.Unlock()
+	for name, running := range clientTransportPlugins {
+		running.plugin.Close()
+		delete(clientTransportPlugins, name)
+	}
+	for name, dialer := range clientSIP003Dialers {
+		dialer.Close()
+		delete(clientSIP003Dialers, name)
+	}
+}
+
+// ServerSIP003Endpoints starts the SIP003 plugins of the server config to
+// listen to the TCP and WEBSOCKET port bindings, and returns the endpoints
+// that mieru listens to. The plugins of unchanged port bindings keep
+// running. If the server config has no SIP003 plugin, the endpoints are
+// returned as is.
+func ServerSIP003Endpoints(config *pb.ServerConfig, endpoints []protocol.UnderlayProperties) ([]protocol.UnderlayProperties, error) {
+	return serverSIP003.Update(config.GetSip003Plugins(), endpoints)
+}
+
+// CloseServerSIP003Plugins stops all the SIP003 plugins of proxy server.
+func CloseServerSIP003Plugins() {
+	serverSIP003.Close()
+}
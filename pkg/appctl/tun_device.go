@@ -0,0 +1,183 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+)
+
+const (
+	defaultTUNDeviceName        = "mieru0"
+	defaultTUNDeviceIPv4Address = "198.18.0.1/30"
+	defaultTUNDeviceMTU         = 1500
+
+	// tunDeviceRoutingTable is the routing table that sends all the traffic
+	// to the TUN device.
+	tunDeviceRoutingTable = "6437"
+
+	// tunDeviceDirectPriority is the priority of the routing policy rules
+	// that skip the TUN device. tunDevicePriority is the priority of the
+	// routing policy rule that looks up tunDeviceRoutingTable.
+	tunDeviceDirectPriority = "6436"
+	tunDevicePriority       = "6437"
+)
+
+// tunDeviceNamePattern matches a valid network interface name.
+var tunDeviceNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,15}$`)
+
+// TUNDeviceOptions are the options of the TUN device with default values
+// applied.
+type TUNDeviceOptions struct {
+	Name string
+	IPv4 netip.Prefix
+	IPv6 netip.Prefix
+	MTU  int
+}
+
+// GetTUNDeviceOptions returns the options of the TUN device.
+func GetTUNDeviceOptions(config *pb.TUNDevice) (*TUNDeviceOptions, error) {
+	opts := &TUNDeviceOptions{
+		Name: defaultTUNDeviceName,
+		MTU:  defaultTUNDeviceMTU,
+	}
+	if config.GetName() != "" {
+		opts.Name = config.GetName()
+	}
+	if !tunDeviceNamePattern.MatchString(opts.Name) {
+		return nil, fmt.Errorf("TUN device name %q is invalid", opts.Name)
+	}
+	ipv4Address := defaultTUNDeviceIPv4Address
+	if config.GetIpv4Address() != "" {
+		ipv4Address = config.GetIpv4Address()
+	}
+	var err error
+	if opts.IPv4, err = parseTUNDeviceAddress(ipv4Address, true); err != nil {
+		return nil, err
+	}
+	if config.GetIpv6Address() != "" {
+		if opts.IPv6, err = parseTUNDeviceAddress(config.GetIpv6Address(), false); err != nil {
+			return nil, err
+		}
+	}
+	if config.GetMtu() != 0 {
+		opts.MTU = int(config.GetMtu())
+	}
+	minMTU := 576
+	if opts.IPv6.IsValid() {
+		minMTU = 1280
+	}
+	if opts.MTU < minMTU || opts.MTU > 65535 {
+		return nil, fmt.Errorf("TUN device MTU %d is not in range [%d, 65535]", opts.MTU, minMTU)
+	}
+	return opts, nil
+}
+
+// parseTUNDeviceAddress parses the address and prefix length of the TUN
+// device. The next address in the prefix must be available.
+func parseTUNDeviceAddress(s string, ipv4 bool) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("TUN device address %q is invalid: %w", s, err)
+	}
+	if ipv4 && !prefix.Addr().Is4() {
+		return netip.Prefix{}, fmt.Errorf("TUN device address %q is not an IPv4 address", s)
+	}
+	if !ipv4 && (!prefix.Addr().Is6() || prefix.Addr().Is4In6()) {
+		return netip.Prefix{}, fmt.Errorf("TUN device address %q is not an IPv6 address", s)
+	}
+	if next := prefix.Addr().Next(); !next.IsValid() || !prefix.Contains(next) {
+		return netip.Prefix{}, fmt.Errorf("TUN device address %q has no room for another address in the prefix", s)
+	}
+	return prefix, nil
+}
+
+// TUNDeviceRules returns the ip commands that create the TUN device and
+// route all the traffic to it. If remove is true, the commands delete the
+// TUN device and the routing rules instead. The traffic of the user that
+// runs mieru client, and the traffic to local, private and multicast
+// addresses are not routed to the TUN device.
+func TUNDeviceRules(config *pb.ClientConfig, remove bool) ([]string, error) {
+	if config.GetTunDevice() == nil {
+		return nil, fmt.Errorf("TUN device is not enabled")
+	}
+	opts, err := GetTUNDeviceOptions(config.GetTunDevice())
+	if err != nil {
+		return nil, err
+	}
+
+	// The traffic of mieru client, including the proxy tunnel and the
+	// direct connections of bypass rules, must not be routed to the TUN
+	// device. It is excluded by the user ID, so the rules must be printed
+	// by the user that runs mieru client. That user can't be root,
+	// otherwise the traffic of all the system services is excluded.
+	owner, err := currentUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	if owner.Uid == "0" {
+		return nil, fmt.Errorf("TUN device mode doesn't capture the traffic of the user that runs mieru client, so mieru client must not be run by root")
+	}
+
+	var rules []string
+	for _, cmd := range []string{"ip", "ip -6"} {
+		directCIDRs := directIPv4CIDRs
+		if cmd == "ip -6" {
+			directCIDRs = directIPv6CIDRs
+		}
+		action := "add"
+		if remove {
+			action = "del"
+		}
+		rules = append(rules, cmd+" rule "+action+" uidrange "+owner.Uid+"-"+owner.Uid+" lookup main priority "+tunDeviceDirectPriority)
+		for _, cidr := range directCIDRs {
+			rules = append(rules, cmd+" rule "+action+" to "+cidr+" lookup main priority "+tunDeviceDirectPriority)
+		}
+		rules = append(rules, cmd+" rule "+action+" lookup "+tunDeviceRoutingTable+" priority "+tunDevicePriority)
+	}
+	if remove {
+		// The routes to the TUN device are deleted together with the device.
+		if !opts.IPv6.IsValid() {
+			rules = append(rules, "ip -6 route del unreachable default table "+tunDeviceRoutingTable)
+		}
+		return append(rules, "ip tuntap del dev "+opts.Name+" mode tun"), nil
+	}
+
+	device := []string{
+		"ip tuntap add dev " + opts.Name + " mode tun user " + owner.Uid,
+		"ip link set dev " + opts.Name + " mtu " + strconv.Itoa(opts.MTU),
+		"ip addr add " + opts.IPv4.String() + " dev " + opts.Name,
+	}
+	if opts.IPv6.IsValid() {
+		device = append(device, "ip -6 addr add "+opts.IPv6.String()+" dev "+opts.Name+" nodad")
+	}
+	device = append(device,
+		"ip link set dev "+opts.Name+" up",
+		"ip route add default dev "+opts.Name+" table "+tunDeviceRoutingTable,
+	)
+	if opts.IPv6.IsValid() {
+		device = append(device, "ip -6 route add default dev "+opts.Name+" table "+tunDeviceRoutingTable)
+	} else {
+		// Block IPv6 traffic, so it doesn't bypass the proxy.
+		device = append(device, "ip -6 route add unreachable default table "+tunDeviceRoutingTable)
+	}
+	// Create the device and the routes before the routing policy rules.
+	return append(device, rules...), nil
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"reflect"
+	"testing"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGetTUNDeviceOptions(t *testing.T) {
+	opts, err := GetTUNDeviceOptions(&pb.TUNDevice{})
+	if err != nil {
+		t.Fatalf("GetTUNDeviceOptions() failed: %v", err)
+	}
+	if opts.Name != "mieru0" || opts.IPv4.String() != "198.18.0.1/30" || opts.IPv6.IsValid() || opts.MTU != 1500 {
+		t.Errorf("GetTUNDeviceOptions() = %+v, want default options", opts)
+	}
+
+	invalid := []*pb.TUNDevice{
+		{Name: proto.String("mieru0/1")},
+		{Name: proto.String("a-very-long-name-0")},
+		{Ipv4Address: proto.String("198.18.0.1")},
+		{Ipv4Address: proto.String("198.18.0.1/31")},
+		{Ipv4Address: proto.String("fdfe:dcba:9876::1/126")},
+		{Ipv6Address: proto.String("198.18.0.1/30")},
+		{Ipv6Address: proto.String("::ffff:198.18.0.1/126")},
+		{Ipv6Address: proto.String("fdfe:dcba:9876::1/128")},
+		{Mtu: proto.Int32(500)},
+		{Mtu: proto.Int32(1200), Ipv6Address: proto.String("fdfe:dcba:9876::1/126")},
+		{Mtu: proto.Int32(65536)},
+	}
+	for _, config := range invalid {
+		if _, err := GetTUNDeviceOptions(config); err == nil {
+			t.Errorf("GetTUNDeviceOptions(%v) succeeded", config)
+		}
+	}
+}
+
+func TestTUNDeviceRules(t *testing.T) {
+	mockCurrentUser(t, "990", "mieru")
+	config := &pb.ClientConfig{
+		TunDevice: &pb.TUNDevice{
+			Name:        proto.String("tun7"),
+			Ipv6Address: proto.String("fdfe:dcba:9876::1/126"),
+			Mtu:         proto.Int32(1400),
+		},
+	}
+	rules, err := TUNDeviceRules(config, false)
+	if err != nil {
+		t.Fatalf("TUNDeviceRules() failed: %v", err)
+	}
+	want := []string{
+		"ip tuntap add dev tun7 mode tun user 990",
+		"ip link set dev tun7 mtu 1400",
+		"ip addr add 198.18.0.1/30 dev tun7",
+		"ip -6 addr add fdfe:dcba:9876::1/126 dev tun7 nodad",
+		"ip link set dev tun7 up",
+		"ip route add default dev tun7 table 6437",
+		"ip -6 route add default dev tun7 table 6437",
+		"ip rule add uidrange 990-990 lookup main priority 6436",
+		"ip rule add to 0.0.0.0/8 lookup main priority 6436",
+		"ip rule add to 10.0.0.0/8 lookup main priority 6436",
+		"ip rule add to 100.64.0.0/10 lookup main priority 6436",
+		"ip rule add to 127.0.0.0/8 lookup main priority 6436",
+		"ip rule add to 169.254.0.0/16 lookup main priority 6436",
+		"ip rule add to 172.16.0.0/12 lookup main priority 6436",
+		"ip rule add to 192.168.0.0/16 lookup main priority 6436",
+		"ip rule add to 224.0.0.0/4 lookup main priority 6436",
+		"ip rule add to 240.0.0.0/4 lookup main priority 6436",
+		"ip rule add lookup 6437 priority 6437",
+		"ip -6 rule add uidrange 990-990 lookup main priority 6436",
+		"ip -6 rule add to ::1/128 lookup main priority 6436",
+		"ip -6 rule add to fc00::/7 lookup main priority 6436",
+		"ip -6 rule add to fe80::/10 lookup main priority 6436",
+		"ip -6 rule add to ff00::/8 lookup main priority 6436",
+		"ip -6 rule add lookup 6437 priority 6437",
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("TUNDeviceRules() = %q, want %q", rules, want)
+	}
+
+	rules, err = TUNDeviceRules(config, true)
+	if err != nil {
+		t.Fatalf("TUNDeviceRules() failed: %v", err)
+	}
+	if len(rules) != 18 || rules[0] != "ip rule del uidrange 990-990 lookup main priority 6436" || rules[17] != "ip tuntap del dev tun7 mode tun" {
+		t.Errorf("TUNDeviceRules() = %q, want the commands to delete the rules and the device", rules)
+	}
+
+	// IPv6 traffic is blocked if it is not captured.
+	config.TunDevice.Ipv6Address = nil
+	rules, err = TUNDeviceRules(config, false)
+	if err != nil {
+		t.Fatalf("TUNDeviceRules() failed: %v", err)
+	}
+	found := false
+	for _, rule := range rules {
+		if rule == "ip -6 route add unreachable default table 6437" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("TUNDeviceRules() = %q, IPv6 traffic is not blocked", rules)
+	}
+	rules, err = TUNDeviceRules(config, true)
+	if err != nil {
+		t.Fatalf("TUNDeviceRules() failed: %v", err)
+	}
+	if rules[len(rules)-2] != "ip -6 route del unreachable default table 6437" {
+		t.Errorf("TUNDeviceRules() = %q, the route that blocks IPv6 traffic is not deleted", rules)
+	}
+}
+
+func TestTUNDeviceRulesRejectRoot(t *testing.T) {
+	mockCurrentUser(t, "0", "root")
+	config := &pb.ClientConfig{TunDevice: &pb.TUNDevice{}}
+	if _, err := TUNDeviceRules(config, false); err == nil {
+		t.Errorf("TUNDeviceRules() succeeded when mieru client is run by root")
+	}
+	if _, err := TUNDeviceRules(&pb.ClientConfig{}, false); err == nil {
+		t.Errorf("TUNDeviceRules() succeeded when TUN device is not enabled")
+	}
+}
@@ -34,13 +34,31 @@ func ClientConfigToURL(config *pb.ClientConfig) (string, error) {
 	if config == nil {
 		return "", stderror.ErrNullPointer
 	}
-	b, err := proto.Marshal(config)
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(config)
 	if err != nil {
 		return "", fmt.Errorf("proto.Marshal() failed: %w", err)
 	}
 	return "mieru://" + base64.StdEncoding.EncodeToString(b), nil
 }
 
+// ClientProfileToURL creates a URL to share a single client profile.
+// The URL has the same format as the one created by ClientConfigToURL,
+// and the profile is set as the active profile.
+func ClientProfileToURL(profile *pb.ClientProfile) (string, error) {
+	if profile == nil {
+		return "", stderror.ErrNullPointer
+	}
+	p := proto.Clone(profile).(*pb.ClientProfile)
+	if p.GetUser().GetPassword() != "" {
+		// The hashed password is computed from the password.
+		p.User.HashedPassword = nil
+	}
+	return ClientConfigToURL(&pb.ClientConfig{
+		Profiles:      []*pb.ClientProfile{p},
+		ActiveProfile: proto.String(p.GetProfileName()),
+	})
+}
+
 // ClientProfileToMultiURLs creates a list of human readable URLs to share
 // the client profile configuration.
 func ClientProfileToMultiURLs(profile *pb.ClientProfile) (urls []string, err error) {
@@ -84,6 +102,10 @@ func ClientProfileToMultiURLs(profile *pb.ClientProfile) (urls []string, err err
 		if profile.Multiplexing != nil && profile.Multiplexing.Level != nil {
 			q.Add("multiplexing", profile.GetMultiplexing().GetLevel().String())
 		}
+		if profile.GetUser().CipherSuite != nil {
+			q.Add("cipher", profile.GetUser().GetCipherSuite().String())
+		}
+		hasHopping := false
 		for _, binding := range server.GetPortBindings() {
 			if binding.GetPortRange() != "" {
 				q.Add("port", binding.GetPortRange())
@@ -91,6 +113,15 @@ func ClientProfileToMultiURLs(profile *pb.ClientProfile) (urls []string, err err
 				q.Add("port", strconv.Itoa(int(binding.GetPort())))
 			}
 			q.Add("protocol", binding.GetProtocol().String())
+			if binding.GetPortHoppingInterval() != "" {
+				hasHopping = true
+			}
+		}
+		if hasHopping {
+			// Port hopping interval is empty for port bindings without port hopping.
+			for _, binding := range server.GetPortBindings() {
+				q.Add("hopping", binding.GetPortHoppingInterval())
+			}
 		}
 		u.RawQuery = q.Encode()
 		urls = append(urls, u.String())
@@ -181,12 +212,23 @@ func URLToClientProfile(s string) (*pb.ClientProfile, error) {
 			Level: &level,
 		}
 	}
+	if q.Get("cipher") != "" {
+		suite, ok := pb.CipherSuite_value[q.Get("cipher")]
+		if !ok {
+			return nil, fmt.Errorf("URL has invalid cipher suite %q", q.Get("cipher"))
+		}
+		p.User.CipherSuite = pb.CipherSuite(suite).Enum()
+	}
 
 	portList := q["port"]
 	protocolList := q["protocol"]
 	if len(portList) != len(protocolList) {
 		return nil, fmt.Errorf("URL has mismatched number of port and number of protocol")
 	}
+	hoppingList := q["hopping"]
+	if len(hoppingList) > 0 && len(hoppingList) != len(portList) {
+		return nil, fmt.Errorf("URL has mismatched number of port and number of hopping")
+	}
 	for idx, port := range portList {
 		portNum, err := strconv.Atoi(port)
 		if err != nil {
@@ -211,14 +253,21 @@ func URLToClientProfile(s string) (*pb.ClientProfile, error) {
 			if beginPort > endPort {
 				return nil, fmt.Errorf("URL's begin port number %d is greater than end port number %d", beginPort, endPort)
 			}
-			server.PortBindings = append(server.PortBindings, &pb.PortBinding{
+			binding := &pb.PortBinding{
 				PortRange: proto.String(fmt.Sprintf("%d-%d", beginPort, endPort)),
 				Protocol:  pb.TransportProtocol(pb.TransportProtocol_value[protocolList[idx]]).Enum(),
-			})
+			}
+			if len(hoppingList) > 0 && hoppingList[idx] != "" {
+				binding.PortHoppingInterval = proto.String(hoppingList[idx])
+			}
+			server.PortBindings = append(server.PortBindings, binding)
 		} else {
 			if portNum < 1 || portNum > 65535 {
 				return nil, fmt.Errorf("URL has invalid port number %d", portNum)
 			}
+			if len(hoppingList) > 0 && hoppingList[idx] != "" {
+				return nil, fmt.Errorf("URL has port hopping on a single port %d", portNum)
+			}
 			server.PortBindings = append(server.PortBindings, &pb.PortBinding{
 				Port:     proto.Int32(int32(portNum)),
 				Protocol: pb.TransportProtocol(pb.TransportProtocol_value[protocolList[idx]]).Enum(),
@@ -71,8 +71,9 @@ func TestClientProfileWithMultiURLs(t *testing.T) {
 	p := &pb.ClientProfile{
 		ProfileName: proto.String("default"),
 		User: &pb.User{
-			Name:     proto.String("abcABC123:<>[]{}|_ !$&'()*+,;=.~-"),
-			Password: proto.String("defDEF456:<>[]{}|_ !$&'()*+,;=.~-"),
+			Name:        proto.String("abcABC123:<>[]{}|_ !$&'()*+,;=.~-"),
+			Password:    proto.String("defDEF456:<>[]{}|_ !$&'()*+,;=.~-"),
+			CipherSuite: pb.CipherSuite_CHACHA20_POLY1305.Enum(),
 		},
 		Servers: []*pb.ServerEndpoint{
 			{
@@ -83,8 +84,9 @@ func TestClientProfileWithMultiURLs(t *testing.T) {
 						Protocol: pb.TransportProtocol_TCP.Enum(),
 					},
 					{
-						PortRange: proto.String("8964-8965"),
-						Protocol:  pb.TransportProtocol_UDP.Enum(),
+						PortRange:           proto.String("8964-8965"),
+						Protocol:            pb.TransportProtocol_UDP.Enum(),
+						PortHoppingInterval: proto.String("30s"),
 					},
 				},
 			},
@@ -107,8 +109,9 @@ func TestClientProfileWithMultiURLs(t *testing.T) {
 	p0 := &pb.ClientProfile{
 		ProfileName: proto.String("default"),
 		User: &pb.User{
-			Name:     proto.String("abcABC123:<>[]{}|_ !$&'()*+,;=.~-"),
-			Password: proto.String("defDEF456:<>[]{}|_ !$&'()*+,;=.~-"),
+			Name:        proto.String("abcABC123:<>[]{}|_ !$&'()*+,;=.~-"),
+			Password:    proto.String("defDEF456:<>[]{}|_ !$&'()*+,;=.~-"),
+			CipherSuite: pb.CipherSuite_CHACHA20_POLY1305.Enum(),
 		},
 		Servers: []*pb.ServerEndpoint{
 			{
@@ -119,8 +122,9 @@ func TestClientProfileWithMultiURLs(t *testing.T) {
 						Protocol: pb.TransportProtocol_TCP.Enum(),
 					},
 					{
-						PortRange: proto.String("8964-8965"),
-						Protocol:  pb.TransportProtocol_UDP.Enum(),
+						PortRange:           proto.String("8964-8965"),
+						Protocol:            pb.TransportProtocol_UDP.Enum(),
+						PortHoppingInterval: proto.String("30s"),
 					},
 				},
 			},
@@ -134,8 +138,9 @@ func TestClientProfileWithMultiURLs(t *testing.T) {
 	p1 := &pb.ClientProfile{
 		ProfileName: proto.String("default"),
 		User: &pb.User{
-			Name:     proto.String("abcABC123:<>[]{}|_ !$&'()*+,;=.~-"),
-			Password: proto.String("defDEF456:<>[]{}|_ !$&'()*+,;=.~-"),
+			Name:        proto.String("abcABC123:<>[]{}|_ !$&'()*+,;=.~-"),
+			Password:    proto.String("defDEF456:<>[]{}|_ !$&'()*+,;=.~-"),
+			CipherSuite: pb.CipherSuite_CHACHA20_POLY1305.Enum(),
 		},
 		Servers: []*pb.ServerEndpoint{
 			{
@@ -52,3 +52,35 @@ func HashUserPasswords(users []*pb.User, keepPlaintext bool) []*pb.User {
 	}
 	return users
 }
+
+// UserGroupsByUserName converts a slice of UserGroup to a map of
+// <user name, UserGroup>.
+func UserGroupsByUserName(groups []*pb.UserGroup) map[string]*pb.UserGroup {
+	m := map[string]*pb.UserGroup{}
+	for _, group := range groups {
+		for _, userName := range group.GetUsers() {
+			m[userName] = group
+		}
+	}
+	return m
+}
+
+// RemoveUsersFromGroups removes the users from all the user groups.
+func RemoveUsersFromGroups(groups []*pb.UserGroup, names []string) {
+	for _, group := range groups {
+		remaining := make([]string, 0, len(group.GetUsers()))
+		for _, userName := range group.GetUsers() {
+			shouldDelete := false
+			for _, toDelete := range names {
+				if userName == toDelete {
+					shouldDelete = true
+					break
+				}
+			}
+			if !shouldDelete {
+				remaining = append(remaining, userName)
+			}
+		}
+		group.Users = remaining
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/stderror"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// verifyResolveTimeout is the maximum duration to resolve the domain
+	// name of a proxy server when verifying client config.
+	verifyResolveTimeout = 5 * time.Second
+
+	// diffContextLines is the number of unchanged lines printed
+	// before and after each change of the config diff.
+	diffContextLines = 3
+
+	// maxDiffCells limits the memory used to compute the config diff.
+	// If the changed part is larger, it is printed as a whole.
+	maxDiffCells = 1 << 22
+)
+
+// ConfigVerification is the result of verifying a config patch
+// without applying it.
+type ConfigVerification struct {
+	// Warnings are the likely mistakes that don't prevent the patch
+	// from being applied.
+	Warnings []string
+
+	// Diff is the change of the stored config after the patch is applied.
+	// Unchanged lines are prefixed with "  ", removed lines with "- " and
+	// added lines with "+ ". Passwords are redacted. It is empty if the
+	// stored config is not changed.
+	Diff string
+}
+
+// VerifyJSONClientConfig validates the client config patch from path
+// in the same way as ApplyJSONClientConfig, but the result is not stored.
+func VerifyJSONClientConfig(path string) (*ConfigVerification, error) {
+	patch := &pb.ClientConfig{}
+	if err := readJSONConfigPatch(path, patch); err != nil {
+		return nil, err
+	}
+	if err := ValidateClientConfigPatch(patch); err != nil {
+		return nil, fmt.Errorf("ValidateClientConfigPatch() failed: %w", err)
+	}
+	stored, err := LoadClientConfig()
+	if err == stderror.ErrFileNotExist {
+		stored = &pb.ClientConfig{}
+	} else if err != nil {
+		return nil, fmt.Errorf("LoadClientConfig() failed: %w", err)
+	}
+	merged := proto.Clone(stored).(*pb.ClientConfig)
+	mergeClientConfigByProfile(merged, patch)
+	if err := ValidateFullClientConfig(merged); err != nil {
+		return nil, fmt.Errorf("ValidateFullClientConfig() failed: %w", err)
+	}
+
+	v := &ConfigVerification{}
+	profileNames := make(map[string]bool)
+	for _, profile := range patch.GetProfiles() {
+		if profileNames[profile.GetProfileName()] {
+			v.Warnings = append(v.Warnings, fmt.Sprintf("profile %q is defined more than once, only the last one is used", profile.GetProfileName()))
+		}
+		profileNames[profile.GetProfileName()] = true
+		var resolver apicommon.DNSResolver = &net.Resolver{}
+		if profile.GetBootstrapDoHURL() != "" {
+			resolver = &common.DoHResolver{URL: profile.GetBootstrapDoHURL()}
+		}
+		for _, server := range profile.GetServers() {
+			for _, overlap := range overlappingPortBindings(server.GetPortBindings()) {
+				v.Warnings = append(v.Warnings, fmt.Sprintf("profile %q: %s", profile.GetProfileName(), overlap))
+			}
+			if server.GetDomainName() == "" {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), verifyResolveTimeout)
+			ips, err := resolver.LookupIP(ctx, "ip", server.GetDomainName())
+			cancel()
+			if err == nil && len(ips) == 0 {
+				err = fmt.Errorf(stderror.IPAddressNotFound, server.GetDomainName())
+			}
+			if err != nil {
+				v.Warnings = append(v.Warnings, fmt.Sprintf("profile %q: failed to resolve proxy server %q: %v", profile.GetProfileName(), server.GetDomainName(), err))
+			}
+		}
+	}
+	// Passwords are hashed and schema version is set when the config is stored.
+	for _, profile := range merged.GetProfiles() {
+		profile.User = HashUserPassword(profile.GetUser(), true)
+	}
+	merged.SchemaVersion = proto.Uint32(ConfigSchemaVersion)
+	v.Diff, err = configDiff(RedactClientConfig(stored), RedactClientConfig(merged))
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// VerifyJSONServerConfig validates the server config patch from path
+// in the same way as ApplyJSONServerConfig, but the result is not stored.
+func VerifyJSONServerConfig(path string) (*ConfigVerification, error) {
+	patch := &pb.ServerConfig{}
+	if err := readJSONConfigPatch(path, patch); err != nil {
+		return nil, err
+	}
+	if err := ValidateServerConfigPatch(patch); err != nil {
+		return nil, fmt.Errorf("ValidateServerConfigPatch() failed: %w", err)
+	}
+	stored, err := LoadServerConfig()
+	if err == stderror.ErrFileNotExist {
+		stored = &pb.ServerConfig{}
+	} else if err != nil {
+		return nil, fmt.Errorf("LoadServerConfig() failed: %w", err)
+	}
+	merged := proto.Clone(stored).(*pb.ServerConfig)
+	if err := mergeServerConfig(merged, patch); err != nil {
+		return nil, fmt.Errorf("mergeServerConfig() failed: %w", err)
+	}
+	if err := ValidateFullServerConfig(merged); err != nil {
+		return nil, fmt.Errorf("ValidateFullServerConfig() failed: %w", err)
+	}
+
+	v := &ConfigVerification{}
+	userNames := make(map[string]bool)
+	for _, user := range patch.GetUsers() {
+		if userNames[user.GetName()] {
+			v.Warnings = append(v.Warnings, fmt.Sprintf("user %q is defined more than once, only the last one is used", user.GetName()))
+		}
+		userNames[user.GetName()] = true
+	}
+	v.Warnings = append(v.Warnings, overlappingPortBindings(merged.GetPortBindings())...)
+	managementPorts := []struct {
+		name string
+		port int32
+	}{
+		{"remote management", merged.GetRemoteManagement().GetPort()},
+		{"management gateway", merged.GetManagementGateway().GetPort()},
+	}
+	for _, m := range managementPorts {
+		if m.port != 0 && portBindingsContainTCP(merged.GetPortBindings(), m.port) {
+			v.Warnings = append(v.Warnings, fmt.Sprintf("%s port %d is also used by a TCP or WEBSOCKET port binding", m.name, m.port))
+		}
+	}
+	// Passwords are hashed and schema version is set when the config is stored.
+	merged.Users = HashUserPasswords(merged.GetUsers(), false)
+	merged.SchemaVersion = proto.Uint32(ConfigSchemaVersion)
+	v.Diff, err = configDiff(RedactServerConfig(stored), RedactServerConfig(merged))
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// readJSONConfigPatch reads the JSON, YAML or TOML config patch from path.
+func readJSONConfigPatch(path string, patch protoreflect.ProtoMessage) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("os.ReadFile(%q) failed: %w", path, err)
+	}
+	if err = common.UnmarshalConfig(path, b, patch); err != nil {
+		return fmt.Errorf("common.UnmarshalConfig() failed: %w", err)
+	}
+	if err = migrateConfigPatch(patch); err != nil {
+		return fmt.Errorf("migrateConfigPatch() failed: %w", err)
+	}
+	return nil
+}
+
+// overlappingPortBindings returns a message for each port that is used
+// by more than one port binding of the same transport protocol.
+// Such port bindings are merged silently when the config is applied.
+func overlappingPortBindings(bindings []*pb.PortBinding) []string {
+	type key struct {
+		protocol pb.TransportProtocol
+		port     int32
+	}
+	count := make(map[key]int)
+	var keys []key
+	for _, binding := range bindings {
+		begin, end := int(binding.GetPort()), int(binding.GetPort())
+		if binding.GetPort() == 0 {
+			var err error
+			if begin, end, err = appctlcommon.ParsePortRange(binding.GetPortRange()); err != nil {
+				continue
+			}
+		}
+		for port := begin; port <= end; port++ {
+			k := key{binding.GetProtocol(), int32(port)}
+			count[k]++
+			if count[k] == 2 {
+				keys = append(keys, k)
+			}
+		}
+	}
+	res := make([]string, 0, len(keys))
+	for _, k := range keys {
+		res = append(res, fmt.Sprintf("%s port %d is used by more than one port binding", k.protocol.String(), k.port))
+	}
+	return res
+}
+
+// portBindingsContainTCP returns true if a TCP or WEBSOCKET port binding
+// uses the port.
+func portBindingsContainTCP(bindings []*pb.PortBinding, port int32) bool {
+	for _, binding := range bindings {
+		if binding.GetProtocol() != pb.TransportProtocol_TCP && binding.GetProtocol() != pb.TransportProtocol_WEBSOCKET {
+			continue
+		}
+		if binding.GetPort() == port {
+			return true
+		}
+		if binding.GetPort() == 0 {
+			if begin, end, err := appctlcommon.ParsePortRange(binding.GetPortRange()); err == nil && int(port) >= begin && int(port) <= end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// configDiff returns the line difference between the canonical JSON
+// of two configs.
+func configDiff(before, after protoreflect.ProtoMessage) (string, error) {
+	a, err := common.MarshalCanonicalJSON(before)
+	if err != nil {
+		return "", fmt.Errorf("common.MarshalCanonicalJSON() failed: %w", err)
+	}
+	b, err := common.MarshalCanonicalJSON(after)
+	if err != nil {
+		return "", fmt.Errorf("common.MarshalCanonicalJSON() failed: %w", err)
+	}
+	return lineDiff(strings.Split(strings.TrimSuffix(string(a), "\n"), "\n"), strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")), nil
+}
+
+// lineDiff returns the changed lines from a to b with the surrounding
+// context. Separate changes are divided by a "..." line.
+func lineDiff(a, b []string) string {
+	// Skip the common prefix and suffix, and compute the longest
+	// common subsequence of the rest.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	if prefix == len(a) && prefix == len(b) {
+		return ""
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// Each line is prefixed with the operation.
+	lines := make([]string, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, "  "+line)
+	}
+	if (len(midA)+1)*(len(midB)+1) > maxDiffCells {
+		for _, line := range midA {
+			lines = append(lines, "- "+line)
+		}
+		for _, line := range midB {
+			lines = append(lines, "+ "+line)
+		}
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence
+		// of midA[i:] and midB[j:].
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				lines = append(lines, "  "+midA[i])
+				i++
+				j++
+			case j == len(midB) || (i < len(midA) && lcs[i+1][j] >= lcs[i][j+1]):
+				lines = append(lines, "- "+midA[i])
+				i++
+			default:
+				lines = append(lines, "+ "+midB[j])
+				j++
+			}
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, "  "+line)
+	}
+
+	// Only print the changed lines and the context.
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "  ") {
+			for k := i - diffContextLines; k <= i+diffContextLines; k++ {
+				if k >= 0 && k < len(lines) {
+					keep[k] = true
+				}
+			}
+		}
+	}
+	var sb strings.Builder
+	for i, line := range lines {
+		if !keep[i] {
+			continue
+		}
+		if i > 0 && !keep[i-1] && sb.Len() > 0 {
+			sb.WriteString("...\n")
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
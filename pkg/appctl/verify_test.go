@@ -0,0 +1,133 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package appctl
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestVerifyServerConfigNotStored(t *testing.T) {
+	beforeServerTest(t)
+
+	if err := ApplyJSONServerConfig("testdata/server_apply_config_1.json"); err != nil {
+		t.Fatalf("ApplyJSONServerConfig() failed: %v", err)
+	}
+	before, err := LoadServerConfig()
+	if err != nil {
+		t.Fatalf("LoadServerConfig() failed: %v", err)
+	}
+	v, err := VerifyJSONServerConfig("testdata/server_apply_config_2.json")
+	if err != nil {
+		t.Fatalf("VerifyJSONServerConfig() failed: %v", err)
+	}
+	if v.Diff == "" {
+		t.Errorf("VerifyJSONServerConfig() returned empty diff")
+	}
+	if strings.Contains(v.Diff, "fa7206ed2a94") {
+		t.Errorf("diff contains user password:\n%s", v.Diff)
+	}
+	after, err := LoadServerConfig()
+	if err != nil {
+		t.Fatalf("LoadServerConfig() failed: %v", err)
+	}
+	if !proto.Equal(before, after) {
+		t.Errorf("VerifyJSONServerConfig() changed the stored server config")
+	}
+
+	// Verify the config that is already applied.
+	v, err = VerifyJSONServerConfig("testdata/server_apply_config_1.json")
+	if err != nil {
+		t.Fatalf("VerifyJSONServerConfig() failed: %v", err)
+	}
+	if v.Diff != "" {
+		t.Errorf("got diff of the applied config:\n%s", v.Diff)
+	}
+
+	// Invalid config is rejected.
+	if _, err := VerifyJSONServerConfig("testdata/server_reject_no_password.json"); err == nil {
+		t.Errorf("VerifyJSONServerConfig() didn't reject invalid config")
+	}
+
+	afterServerTest(t)
+}
+
+func TestVerifyServerConfigWarnings(t *testing.T) {
+	beforeServerTest(t)
+
+	v, err := VerifyJSONServerConfig("testdata/server_verify_config_warnings.json")
+	if err != nil {
+		t.Fatalf("VerifyJSONServerConfig() failed: %v", err)
+	}
+	want := []string{
+		`user "user1" is defined more than once, only the last one is used`,
+		"TCP port 8000 is used by more than one port binding",
+	}
+	if len(v.Warnings) != len(want) {
+		t.Fatalf("got warnings %v, want %v", v.Warnings, want)
+	}
+	for i := range want {
+		if v.Warnings[i] != want[i] {
+			t.Errorf("got warning %q, want %q", v.Warnings[i], want[i])
+		}
+	}
+
+	afterServerTest(t)
+}
+
+func TestOverlappingPortBindings(t *testing.T) {
+	bindings := []*pb.PortBinding{
+		{PortRange: proto.String("2000-2002"), Protocol: pb.TransportProtocol_TCP.Enum()},
+		{Port: proto.Int32(2002), Protocol: pb.TransportProtocol_TCP.Enum()},
+		{Port: proto.Int32(2001), Protocol: pb.TransportProtocol_UDP.Enum()},
+	}
+	got := overlappingPortBindings(bindings)
+	if len(got) != 1 || got[0] != "TCP port 2002 is used by more than one port binding" {
+		t.Errorf("overlappingPortBindings() = %v", got)
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	testcases := []struct {
+		a    []string
+		b    []string
+		want string
+	}{
+		{
+			a:    []string{"a", "b", "c"},
+			b:    []string{"a", "b", "c"},
+			want: "",
+		},
+		{
+			a:    []string{"a", "b", "c"},
+			b:    []string{"a", "x", "c"},
+			want: "  a\n- b\n+ x\n  c\n",
+		},
+		{
+			a:    []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"},
+			b:    []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"},
+			want: "+ 0\n  1\n  2\n  3\n...\n  7\n  8\n  9\n- 10\n",
+		},
+	}
+	for _, tc := range testcases {
+		if got := lineDiff(tc.a, tc.b); got != tc.want {
+			t.Errorf("lineDiff(%v, %v) = %q, want %q", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
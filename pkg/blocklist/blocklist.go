@@ -0,0 +1,345 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package blocklist blocks the domain names of ad and malware sites.
+package blocklist
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+const (
+	// DefaultRefreshInterval is the interval to download the blocklists
+	// from URLs again, if it is not configured.
+	DefaultRefreshInterval = 24 * time.Hour
+
+	// refreshCheckInterval is the interval to check if the blocklists
+	// should be downloaded again.
+	refreshCheckInterval = time.Minute
+
+	// downloadTimeout is the maximum time to download a blocklist.
+	downloadTimeout = 5 * time.Minute
+
+	// maxDownloadSize is the maximum size of a downloaded blocklist.
+	maxDownloadSize = 64 * 1024 * 1024
+)
+
+var (
+	Domains         = metrics.RegisterMetric("blocklist", "Domains", metrics.GAUGE)
+	Refreshes       = metrics.RegisterMetric("blocklist", "Refreshes", metrics.COUNTER)
+	RefreshFailures = metrics.RegisterMetric("blocklist", "RefreshFailures", metrics.COUNTER)
+)
+
+// hostsFileLocalNames are the host names in hosts files that are not blocked.
+var hostsFileLocalNames = map[string]struct{}{
+	"localhost":             {},
+	"localhost.localdomain": {},
+	"local":                 {},
+	"broadcasthost":         {},
+	"ip6-localhost":         {},
+	"ip6-loopback":          {},
+	"ip6-localnet":          {},
+	"ip6-mcastprefix":       {},
+	"ip6-allnodes":          {},
+	"ip6-allrouters":        {},
+	"ip6-allhosts":          {},
+}
+
+// DomainSet is a set of blocked domain names.
+type DomainSet struct {
+	fulls    map[string]struct{} // the domain itself
+	suffixes map[string]struct{} // the domain and its subdomains
+}
+
+// Parse reads a blocklist. Each line is in one of the formats
+//
+//	0.0.0.0 ads.example.com tracker.example.com
+//	example.com
+//
+// The first format is a hosts file. The host names after the IP address
+// are blocked, but not their subdomains. The second format is a domain
+// suffix. The domain and all its subdomains are blocked. Text after "#"
+// is a comment. Lines in other formats are ignored.
+func Parse(r io.Reader) (*DomainSet, error) {
+	d := &DomainSet{
+		fulls:    make(map[string]struct{}),
+		suffixes: make(map[string]struct{}),
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) == 1:
+			if domain, ok := normalizeDomain(strings.TrimPrefix(strings.TrimPrefix(fields[0], "*"), ".")); ok {
+				d.suffixes[domain] = struct{}{}
+			}
+		case net.ParseIP(fields[0]) != nil:
+			for _, host := range fields[1:] {
+				domain, ok := normalizeDomain(host)
+				if !ok {
+					continue
+				}
+				if _, found := hostsFileLocalNames[domain]; found {
+					continue
+				}
+				d.fulls[domain] = struct{}{}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read blocklist: %w", err)
+	}
+	return d, nil
+}
+
+// Len returns the number of blocked domain names.
+func (d *DomainSet) Len() int {
+	return len(d.fulls) + len(d.suffixes)
+}
+
+// Match returns true if the domain name is blocked.
+func (d *DomainSet) Match(domain string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if _, found := d.fulls[domain]; found {
+		return true
+	}
+	for suffix := domain; ; {
+		if _, found := d.suffixes[suffix]; found {
+			return true
+		}
+		var ok bool
+		if _, suffix, ok = strings.Cut(suffix, "."); !ok {
+			return false
+		}
+	}
+}
+
+// normalizeDomain returns the domain name in lower case.
+// It returns false if the value is not a domain name.
+func normalizeDomain(v string) (string, bool) {
+	v = strings.TrimSuffix(strings.ToLower(v), ".")
+	if v == "" || net.ParseIP(v) != nil {
+		return "", false
+	}
+	for _, c := range v {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '-' && c != '_' && c != '.' {
+			return "", false
+		}
+	}
+	if strings.HasPrefix(v, ".") || strings.Contains(v, "..") {
+		return "", false
+	}
+	return v, true
+}
+
+// Blocklist is a set of blocked domain names loaded from files and URLs.
+// The blocklists from URLs are downloaded again periodically after
+// Start is called.
+type Blocklist struct {
+	mu              sync.RWMutex
+	urls            []string
+	refreshInterval time.Duration
+	lists           map[string]*DomainSet // key is file path or URL
+	nextRefresh     time.Time             // zero if URLs should be downloaded now
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates an empty Blocklist.
+func New() *Blocklist {
+	return &Blocklist{
+		lists: make(map[string]*DomainSet),
+		done:  make(chan struct{}),
+	}
+}
+
+// Update replaces the blocklist files and URLs. The files are loaded
+// immediately. The new URLs are downloaded by the next refresh, and
+// the URLs that are removed stop blocking immediately.
+// If refreshInterval is not positive, DefaultRefreshInterval is used.
+func (b *Blocklist) Update(files, urls []string, refreshInterval time.Duration) error {
+	fileLists := make(map[string]*DomainSet)
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("os.Open() failed: %w", err)
+		}
+		d, err := Parse(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to load blocklist %q: %w", path, err)
+		}
+		fileLists[path] = d
+	}
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lists := make(map[string]*DomainSet)
+	for path, d := range fileLists {
+		lists[path] = d
+	}
+	for _, u := range urls {
+		if d, found := b.lists[u]; found {
+			lists[u] = d
+		} else {
+			b.nextRefresh = time.Time{}
+		}
+	}
+	b.urls = urls
+	b.refreshInterval = refreshInterval
+	b.lists = lists
+	Domains.Store(int64(b.lenLocked()))
+	return nil
+}
+
+// Start downloads the blocklists from URLs in the background,
+// and downloads them again periodically until Close is called.
+func (b *Blocklist) Start() {
+	go func() {
+		b.maybeRefresh(time.Now())
+		ticker := time.NewTicker(refreshCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				b.maybeRefresh(now)
+			case <-b.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops downloading the blocklists.
+func (b *Blocklist) Close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+}
+
+// Refresh downloads the blocklists from all the URLs. If a blocklist
+// can't be downloaded, the previous one from the same URL is kept,
+// and the first error is returned.
+func (b *Blocklist) Refresh(ctx context.Context) error {
+	b.mu.RLock()
+	urls := b.urls
+	b.mu.RUnlock()
+
+	var firstErr error
+	downloaded := make(map[string]*DomainSet)
+	for _, u := range urls {
+		d, err := download(ctx, u)
+		if err != nil {
+			RefreshFailures.Add(1)
+			log.Warnf("Failed to download blocklist %q: %v", u, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		Refreshes.Add(1)
+		log.Infof("Downloaded blocklist %q with %d domain names", u, d.Len())
+		downloaded[u] = d
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, u := range b.urls {
+		// Skip the URLs that are removed by Update during the download.
+		if d, found := downloaded[u]; found {
+			b.lists[u] = d
+		}
+	}
+	Domains.Store(int64(b.lenLocked()))
+	return firstErr
+}
+
+// Len returns the number of blocked domain names.
+// A domain name in multiple blocklists is counted multiple times.
+func (b *Blocklist) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lenLocked()
+}
+
+// Match returns true if the domain name is blocked.
+func (b *Blocklist) Match(domain string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, d := range b.lists {
+		if d.Match(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Blocklist) lenLocked() int {
+	n := 0
+	for _, d := range b.lists {
+		n += d.Len()
+	}
+	return n
+}
+
+// maybeRefresh downloads the blocklists if the refresh interval has passed.
+func (b *Blocklist) maybeRefresh(now time.Time) {
+	b.mu.Lock()
+	if len(b.urls) == 0 || (!b.nextRefresh.IsZero() && now.Before(b.nextRefresh)) {
+		b.mu.Unlock()
+		return
+	}
+	b.nextRefresh = now.Add(b.refreshInterval)
+	b.mu.Unlock()
+	b.Refresh(context.Background())
+}
+
+func download(ctx context.Context, u string) (*DomainSet, error) {
+	ctx, cancelFunc := context.WithTimeout(ctx, downloadTimeout)
+	defer cancelFunc()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext() failed: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %q failed: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %q failed: HTTP status %q", u, resp.Status)
+	}
+	return Parse(io.LimitReader(resp.Body, maxDownloadSize))
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package blocklist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	d, err := Parse(strings.NewReader(`
+# Hosts file format.
+127.0.0.1 localhost
+::1 localhost ip6-localhost
+0.0.0.0 ads.example.com Tracker.Example.com # trailing comment
+0.0.0.0 0.0.0.0
+
+# Domain suffix format.
+doubleclick.net
+*.malware.example.org.
+||not-supported^
+two fields
+`))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if d.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", d.Len())
+	}
+	testcases := []struct {
+		domain string
+		want   bool
+	}{
+		{"ads.example.com", true},
+		{"ADS.example.com.", true},
+		{"www.ads.example.com", false},
+		{"tracker.example.com", true},
+		{"example.com", false},
+		{"localhost", false},
+		{"doubleclick.net", true},
+		{"stats.g.doubleclick.net", true},
+		{"notdoubleclick.net", false},
+		{"malware.example.org", true},
+		{"a.malware.example.org", true},
+		{"not-supported", false},
+	}
+	for _, tc := range testcases {
+		if got := d.Match(tc.domain); got != tc.want {
+			t.Errorf("Match(%q) = %v, want %v", tc.domain, got, tc.want)
+		}
+	}
+}
+
+func TestBlocklistUpdateAndRefresh(t *testing.T) {
+	var body atomic.Value
+	body.Store("ads.example.com\n")
+	var fail atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(body.Load().(string)))
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "hosts")
+	if err := os.WriteFile(path, []byte("0.0.0.0 tracker.example.com\n"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	b := New()
+	if err := b.Update([]string{path}, []string{ts.URL}, 0); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if !b.Match("tracker.example.com") {
+		t.Errorf("domain from file is not blocked")
+	}
+	if b.Match("ads.example.com") {
+		t.Errorf("domain from URL is blocked before refresh")
+	}
+
+	if err := b.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() failed: %v", err)
+	}
+	if !b.Match("www.ads.example.com") {
+		t.Errorf("domain from URL is not blocked after refresh")
+	}
+
+	// A failed refresh keeps the previous blocklist.
+	body.Store("malware.example.com\n")
+	fail.Store(true)
+	if err := b.Refresh(context.Background()); err == nil {
+		t.Errorf("Refresh() returned no error")
+	}
+	if !b.Match("ads.example.com") || b.Match("malware.example.com") {
+		t.Errorf("previous blocklist is not kept after failed refresh")
+	}
+	fail.Store(false)
+	if err := b.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() failed: %v", err)
+	}
+	if b.Match("ads.example.com") || !b.Match("malware.example.com") {
+		t.Errorf("blocklist is not replaced after refresh")
+	}
+
+	// Removed sources stop blocking.
+	if err := b.Update(nil, nil, 0); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if b.Len() != 0 || b.Match("tracker.example.com") || b.Match("malware.example.com") {
+		t.Errorf("removed blocklists are still used")
+	}
+
+	if err := b.Update([]string{filepath.Join(t.TempDir(), "not-exist")}, nil, 0); err == nil {
+		t.Errorf("Update() with a missing file returned no error")
+	}
+}
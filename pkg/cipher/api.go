@@ -18,6 +18,7 @@ package cipher
 import (
 	"crypto/sha256"
 	"fmt"
+	"time"
 
 	"github.com/enfein/mieru/v3/pkg/metrics"
 )
@@ -27,6 +28,9 @@ const (
 	DefaultOverhead  = 16 // 16 bytes
 	DefaultKeyLen    = 32 // 256 bits
 
+	// DefaultAEADType is the AEAD algorithm used if it is not specified.
+	DefaultAEADType = XChaCha20Poly1305
+
 	ClientDecryptionMetricGroupName = "cipher - client"
 	ServerDecryptionMetricGroupName = "cipher - server"
 )
@@ -119,7 +123,13 @@ func HashPassword(rawPassword, uniqueValue []byte) []byte {
 // BlockCipherFromPassword creates a BlockCipher object from the password
 // with the default settings.
 func BlockCipherFromPassword(password []byte, stateless bool) (BlockCipher, error) {
-	cipherList, err := getBlockCipherList(password, stateless)
+	return BlockCipherFromPasswordWithAEAD(password, DefaultAEADType, stateless)
+}
+
+// BlockCipherFromPasswordWithAEAD is like BlockCipherFromPassword,
+// but uses the given AEAD algorithm.
+func BlockCipherFromPasswordWithAEAD(password []byte, aeadType AEADType, stateless bool) (BlockCipher, error) {
+	cipherList, err := getBlockCipherList(password, aeadType, stateless)
 	if err != nil {
 		return nil, err
 	}
@@ -129,15 +139,33 @@ func BlockCipherFromPassword(password []byte, stateless bool) (BlockCipher, erro
 // BlockCipherListFromPassword creates three BlockCipher objects using different salts
 // from the password with the default settings.
 func BlockCipherListFromPassword(password []byte, stateless bool) ([]BlockCipher, error) {
-	return getBlockCipherList(password, stateless)
+	return getBlockCipherList(password, DefaultAEADType, stateless)
+}
+
+// BlockCipherListFromPasswordWithAEAD is like BlockCipherListFromPassword,
+// but uses the given AEAD algorithm.
+func BlockCipherListFromPasswordWithAEAD(password []byte, aeadType AEADType, stateless bool) ([]BlockCipher, error) {
+	return getBlockCipherList(password, aeadType, stateless)
+}
+
+// BlockCipherListFromPasswordAt is like BlockCipherListFromPassword,
+// but uses the salts of the given time. The result is not cached.
+func BlockCipherListFromPasswordAt(password []byte, stateless bool, t time.Time) ([]BlockCipher, error) {
+	blocks, _, err := newBlockCipherList(password, DefaultAEADType, stateless, t)
+	return blocks, err
 }
 
 // TryDecrypt tries to decrypt the data with all possible keys generated from the password.
 // If successful, returns the block cipher as well as the decrypted results.
 func TryDecrypt(data, password []byte, stateless bool) (BlockCipher, []byte, error) {
-	blocks, err := BlockCipherListFromPassword(password, stateless)
+	return TryDecryptWithAEAD(data, password, DefaultAEADType, stateless)
+}
+
+// TryDecryptWithAEAD is like TryDecrypt, but uses the given AEAD algorithm.
+func TryDecryptWithAEAD(data, password []byte, aeadType AEADType, stateless bool) (BlockCipher, []byte, error) {
+	blocks, err := getBlockCipherList(password, aeadType, stateless)
 	if err != nil {
-		return nil, nil, fmt.Errorf("BlockCipherListFromPassword() failed: %w", err)
+		return nil, nil, fmt.Errorf("getBlockCipherList() failed: %w", err)
 	}
 	return SelectDecrypt(data, blocks)
 }
@@ -0,0 +1,116 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cipher
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// benchmarkBlockSize is the size of each message in the benchmark.
+	benchmarkBlockSize = 16 * 1024
+
+	// benchmarkDuration is the time to measure each AEAD algorithm
+	// when picking the fastest one.
+	benchmarkDuration = 50 * time.Millisecond
+
+	// minSpeedup is the minimum throughput ratio over the default AEAD
+	// algorithm to pick another one.
+	minSpeedup = 1.1
+)
+
+// SupportedAEADTypes are the AEAD algorithms supported by mieru protocol.
+// The default algorithm is the first one.
+var SupportedAEADTypes = []AEADType{XChaCha20Poly1305, AES128GCM, AES256GCM, ChaCha20Poly1305}
+
+var (
+	fastestAEADType     AEADType
+	fastestAEADTypeOnce sync.Once
+)
+
+// AEADThroughput is the measured throughput of an AEAD algorithm.
+type AEADThroughput struct {
+	AEADType AEADType
+
+	// MBps is the number of megabytes encrypted and decrypted per second.
+	MBps float64
+}
+
+// MeasureAEADThroughput measures the throughput of each supported
+// AEAD algorithm on this host for the given duration.
+func MeasureAEADThroughput(d time.Duration) []AEADThroughput {
+	results := make([]AEADThroughput, 0, len(SupportedAEADTypes))
+	for _, aeadType := range SupportedAEADTypes {
+		results = append(results, AEADThroughput{
+			AEADType: aeadType,
+			MBps:     measureAEAD(aeadType, d),
+		})
+	}
+	return results
+}
+
+// FastestAEADType returns the AEAD algorithm with the highest throughput
+// on this host. The default algorithm is returned unless another one is
+// at least 10% faster. The benchmark only runs on the first call.
+func FastestAEADType() AEADType {
+	fastestAEADTypeOnce.Do(func() {
+		fastestAEADType = pickFastest(MeasureAEADThroughput(benchmarkDuration))
+	})
+	return fastestAEADType
+}
+
+// pickFastest returns the fastest AEAD algorithm from the results.
+func pickFastest(results []AEADThroughput) AEADType {
+	best := DefaultAEADType
+	var defaultMBps, bestMBps float64
+	for _, r := range results {
+		if r.AEADType == DefaultAEADType {
+			defaultMBps = r.MBps
+		}
+	}
+	for _, r := range results {
+		if r.MBps > bestMBps && r.MBps >= defaultMBps*minSpeedup {
+			best = r.AEADType
+			bestMBps = r.MBps
+		}
+	}
+	return best
+}
+
+// measureAEAD returns the throughput of the AEAD algorithm in MB/s,
+// or 0 if the algorithm can't be used.
+func measureAEAD(aeadType AEADType, d time.Duration) float64 {
+	block, err := newBlockCipher(aeadType, make([]byte, aeadType.KeyLen()))
+	if err != nil {
+		return 0
+	}
+	nonce := make([]byte, block.NonceSize())
+	plaintext := make([]byte, benchmarkBlockSize)
+	var n int
+	start := time.Now()
+	for time.Since(start) < d {
+		ciphertext, err := block.EncryptWithNonce(plaintext, nonce)
+		if err != nil {
+			return 0
+		}
+		if _, err := block.DecryptWithNonce(ciphertext, nonce); err != nil {
+			return 0
+		}
+		n += len(plaintext)
+	}
+	return float64(n) / 1024 / 1024 / time.Since(start).Seconds()
+}
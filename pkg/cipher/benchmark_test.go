@@ -0,0 +1,58 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cipher
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMeasureAEADThroughput(t *testing.T) {
+	results := MeasureAEADThroughput(5 * time.Millisecond)
+	if len(results) != len(SupportedAEADTypes) {
+		t.Fatalf("got %d results, want %d", len(results), len(SupportedAEADTypes))
+	}
+	for _, r := range results {
+		if r.MBps <= 0 {
+			t.Errorf("throughput of %v is %f MB/s", r.AEADType, r.MBps)
+		}
+	}
+}
+
+func TestPickFastest(t *testing.T) {
+	testCases := []struct {
+		results []AEADThroughput
+		want    AEADType
+	}{
+		{
+			[]AEADThroughput{{XChaCha20Poly1305, 100}, {AES128GCM, 105}, {ChaCha20Poly1305, 104}},
+			XChaCha20Poly1305,
+		},
+		{
+			[]AEADThroughput{{XChaCha20Poly1305, 100}, {AES128GCM, 300}, {AES256GCM, 250}, {ChaCha20Poly1305, 120}},
+			AES128GCM,
+		},
+		{
+			[]AEADThroughput{{XChaCha20Poly1305, 100}, {AES128GCM, 0}, {ChaCha20Poly1305, 120}},
+			ChaCha20Poly1305,
+		},
+	}
+	for _, tc := range testCases {
+		if got := pickFastest(tc.results); got != tc.want {
+			t.Errorf("pickFastest(%v) = %v, want %v", tc.results, got, tc.want)
+		}
+	}
+}
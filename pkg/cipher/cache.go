@@ -23,6 +23,11 @@ import (
 
 const cacheValidInterval = KeyRefreshInterval / 4
 
+type cacheKey struct {
+	password string
+	aeadType AEADType
+}
+
 type cachedCiphers struct {
 	cipherList []BlockCipher
 	createTime time.Time
@@ -30,10 +35,10 @@ type cachedCiphers struct {
 
 var blockCipherCache = sync.Map{}
 
-// getBlockCipherList returns three BlockCipher.
+// getBlockCipherList returns three BlockCipher of the AEAD algorithm.
 // It uses cache so it doesn't need to generate BlockCipher each time.
-func getBlockCipherList(password []byte, stateless bool) ([]BlockCipher, error) {
-	pw := string(password)
+func getBlockCipherList(password []byte, aeadType AEADType, stateless bool) ([]BlockCipher, error) {
+	pw := cacheKey{password: string(password), aeadType: aeadType}
 
 	// Try to find []BlockCipher from cache.
 	c, ok := blockCipherCache.Load(pw)
@@ -56,7 +61,7 @@ func getBlockCipherList(password []byte, stateless bool) ([]BlockCipher, error)
 	}
 
 	// If not found, generate the stateless []BlockCipher.
-	blockCiphers, t, err := newBlockCipherList(password, true)
+	blockCiphers, t, err := newBlockCipherList(password, aeadType, true, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("newBlockCipherList() failed: %v", err)
 	}
@@ -80,8 +85,7 @@ func getBlockCipherList(password []byte, stateless bool) ([]BlockCipher, error)
 	return blocks, nil
 }
 
-func newBlockCipherList(password []byte, stateless bool) ([]BlockCipher, time.Time, error) {
-	t := time.Now()
+func newBlockCipherList(password []byte, aeadType AEADType, stateless bool, t time.Time) ([]BlockCipher, time.Time, error) {
 	salts := saltFromTime(t)
 	blockCiphers := make([]BlockCipher, 0, 3)
 	for i := 0; i < 3; i++ {
@@ -89,13 +93,13 @@ func newBlockCipherList(password []byte, stateless bool) ([]BlockCipher, time.Ti
 			Salt: salts[i],
 			Iter: KeyIter,
 		}
-		cipherKey, err := keygen.NewKey(password, DefaultKeyLen)
+		cipherKey, err := keygen.NewKey(password, aeadType.KeyLen())
 		if err != nil {
 			return nil, t, fmt.Errorf("NewKey() failed: %w", err)
 		}
-		blockCipher, err := newXChaCha20Poly1305BlockCipher(cipherKey)
+		blockCipher, err := newBlockCipher(aeadType, cipherKey)
 		if err != nil {
-			return nil, t, fmt.Errorf("newXChaCha20Poly1305BlockCipher() failed: %w", err)
+			return nil, t, fmt.Errorf("newBlockCipher() failed: %w", err)
 		}
 		if !stateless {
 			blockCipher.SetImplicitNonceMode(true)
@@ -19,7 +19,7 @@ import "testing"
 
 func TestGetBlockCipherList(t *testing.T) {
 	password := []byte{0x08, 0x09, 0x06, 0x04}
-	ciphers, err := getBlockCipherList(password, true)
+	ciphers, err := getBlockCipherList(password, DefaultAEADType, true)
 	if err != nil {
 		t.Fatalf("getBlockCipherList() failed: %v", err)
 	}
@@ -32,7 +32,7 @@ func TestGetBlockCipherList(t *testing.T) {
 		}
 	}
 
-	ciphers, err = getBlockCipherList(password, false)
+	ciphers, err = getBlockCipherList(password, DefaultAEADType, false)
 	if err != nil {
 		t.Fatalf("getBlockCipherList() failed: %v", err)
 	}
@@ -41,8 +41,17 @@ const (
 
 var (
 	_ BlockCipher = &AEADBlockCipher{}
+	_ cipher.AEAD = extendedNonceAEAD{}
 )
 
+// KeyLen returns the length of the key used by the AEAD algorithm.
+func (t AEADType) KeyLen() int {
+	if t == AES128GCM {
+		return 16
+	}
+	return DefaultKeyLen
+}
+
 // AEADBlockCipher implements BlockCipher interface with one AEAD algorithm.
 type AEADBlockCipher struct {
 	aead                cipher.AEAD
@@ -50,10 +59,73 @@ type AEADBlockCipher struct {
 	enableImplicitNonce bool
 	key                 []byte
 	implicitNonce       []byte
+	extendedNonce       bool // if true, aead is an extendedNonceAEAD
 	mu                  sync.Mutex
 	ctx                 BlockContext
 }
 
+// newBlockCipher creates a new block cipher used by mieru protocol.
+// The nonce size is DefaultNonceSize regardless of the AEAD algorithm,
+// so the ciphertext has the same layout. The key length must be
+// aeadType.KeyLen().
+func newBlockCipher(aeadType AEADType, key []byte) (*AEADBlockCipher, error) {
+	var c *AEADBlockCipher
+	var err error
+	switch aeadType {
+	case AES128GCM, AES256GCM:
+		if len(key) != aeadType.KeyLen() {
+			return nil, fmt.Errorf("%v key length is %d bytes, want %d bytes", aeadType, len(key), aeadType.KeyLen())
+		}
+		c, err = newAESGCMBlockCipher(key)
+	case ChaCha20Poly1305:
+		c, err = newChaCha20Poly1305BlockCipher(key)
+	case XChaCha20Poly1305:
+		return newXChaCha20Poly1305BlockCipher(key)
+	default:
+		return nil, fmt.Errorf("invalid AEAD type %d", aeadType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.aead = extendedNonceAEAD{c.aead}
+	c.extendedNonce = true
+	return c, nil
+}
+
+// extendedNonceAEAD extends the nonce of an AEAD algorithm to
+// DefaultNonceSize bytes. The last bytes of the extended nonce are
+// used as the nonce of the algorithm, and the leading bytes are
+// authenticated as a prefix of the additional data.
+type extendedNonceAEAD struct {
+	cipher.AEAD
+}
+
+func (a extendedNonceAEAD) NonceSize() int {
+	return DefaultNonceSize
+}
+
+func (a extendedNonceAEAD) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	n, ad := a.split(nonce, additionalData)
+	return a.AEAD.Seal(dst, n, plaintext, ad)
+}
+
+func (a extendedNonceAEAD) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	n, ad := a.split(nonce, additionalData)
+	return a.AEAD.Open(dst, n, ciphertext, ad)
+}
+
+// split returns the nonce and additional data used by the AEAD algorithm.
+func (a extendedNonceAEAD) split(nonce, additionalData []byte) ([]byte, []byte) {
+	if len(nonce) != DefaultNonceSize {
+		panic(fmt.Sprintf("extended nonce size is %d bytes, want %d bytes", len(nonce), DefaultNonceSize))
+	}
+	prefixLen := DefaultNonceSize - a.AEAD.NonceSize()
+	ad := make([]byte, 0, prefixLen+len(additionalData))
+	ad = append(ad, nonce[:prefixLen]...)
+	ad = append(ad, additionalData...)
+	return nonce[prefixLen:], ad
+}
+
 // newAESGCMBlockCipher creates a new AES-GCM cipher with the supplied key.
 func newAESGCMBlockCipher(key []byte) (*AEADBlockCipher, error) {
 	keyLen := len(key)
@@ -254,6 +326,10 @@ func (c *AEADBlockCipher) Clone() BlockCipher {
 	if err != nil {
 		panic(err)
 	}
+	if c.extendedNonce {
+		newCipher.aead = extendedNonceAEAD{newCipher.aead}
+		newCipher.extendedNonce = true
+	}
 
 	newCipher.enableImplicitNonce = c.enableImplicitNonce
 	if len(c.implicitNonce) != 0 {
@@ -52,6 +52,59 @@ func TestDefaultOverhead(t *testing.T) {
 	}
 }
 
+func TestNewBlockCipher(t *testing.T) {
+	for _, aeadType := range []AEADType{AES128GCM, AES256GCM, ChaCha20Poly1305, XChaCha20Poly1305} {
+		key := make([]byte, aeadType.KeyLen())
+		if _, err := crand.Read(key); err != nil {
+			t.Fatalf("fail to generate key: %v", err)
+		}
+		block, err := newBlockCipher(aeadType, key)
+		if err != nil {
+			t.Fatalf("newBlockCipher(%v) failed: %v", aeadType, err)
+		}
+		if block.NonceSize() != DefaultNonceSize {
+			t.Errorf("%v: got nonce size %d; want %d", aeadType, block.NonceSize(), DefaultNonceSize)
+		}
+		if block.Overhead() != DefaultOverhead {
+			t.Errorf("%v: got overhead size %d; want %d", aeadType, block.Overhead(), DefaultOverhead)
+		}
+
+		data := make([]byte, 256)
+		if _, err := crand.Read(data); err != nil {
+			t.Fatalf("fail to generate data: %v", err)
+		}
+		ciphertext, err := block.Encrypt(data)
+		if err != nil {
+			t.Fatalf("%v: Encrypt() failed: %v", aeadType, err)
+		}
+		if len(ciphertext) != len(data)+DefaultNonceSize+DefaultOverhead {
+			t.Errorf("%v: got ciphertext size %d; want %d", aeadType, len(ciphertext), len(data)+DefaultNonceSize+DefaultOverhead)
+		}
+		plaintext, err := block.Clone().Decrypt(ciphertext)
+		if err != nil {
+			t.Fatalf("%v: Decrypt() with clone failed: %v", aeadType, err)
+		}
+		if !bytes.Equal(data, plaintext) {
+			t.Errorf("%v: plaintext doesn't match", aeadType)
+		}
+
+		// The leading bytes of the nonce are authenticated.
+		ciphertext[0] ^= 0x01
+		if _, err := block.Decrypt(ciphertext); err == nil {
+			t.Errorf("%v: Decrypt() succeeded with modified nonce", aeadType)
+		}
+	}
+}
+
+func TestNewBlockCipherWrongKeyLength(t *testing.T) {
+	if _, err := newBlockCipher(AES256GCM, make([]byte, 16)); err == nil {
+		t.Errorf("newBlockCipher() succeeded with 16 bytes AES-256-GCM key")
+	}
+	if _, err := newBlockCipher(AEADType(0), make([]byte, 32)); err == nil {
+		t.Errorf("newBlockCipher() succeeded with invalid AEAD type")
+	}
+}
+
 func TestAEADBlockCipherEncryptDecrypt(t *testing.T) {
 	for i := 0; i < 1000; i++ {
 		key := make([]byte, 32)
@@ -0,0 +1,135 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cipher
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// KeyVector is a conformance test vector of generating cipher keys
+// from the user name and password.
+type KeyVector struct {
+	UserName string `json:"userName"`
+	Password string `json:"password"`
+	UnixTime int64  `json:"unixTime"`
+
+	// The following fields are hex encoded.
+	HashedPassword string   `json:"hashedPassword"`
+	Salts          []string `json:"salts"`
+	Keys           []string `json:"keys"`
+}
+
+// AEADVector is a conformance test vector of sealing a plaintext
+// with an AEAD algorithm. All the byte fields are hex encoded.
+type AEADVector struct {
+	Algorithm  string `json:"algorithm"`
+	Key        string `json:"key"`
+	Nonce      string `json:"nonce"`
+	Plaintext  string `json:"plaintext"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// String returns the name of the AEAD algorithm.
+func (t AEADType) String() string {
+	switch t {
+	case AES128GCM:
+		return "AES-128-GCM"
+	case AES256GCM:
+		return "AES-256-GCM"
+	case ChaCha20Poly1305:
+		return "ChaCha20-Poly1305"
+	case XChaCha20Poly1305:
+		return "XChaCha20-Poly1305"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// NewKeyVector returns the conformance test vector of generating
+// cipher keys at the given time. The keys are ordered in the same way
+// as BlockCipherListFromPassword: previous, current and next salt.
+func NewKeyVector(userName, password string, t time.Time) (KeyVector, error) {
+	hashedPassword := HashPassword([]byte(password), []byte(userName))
+	v := KeyVector{
+		UserName:       userName,
+		Password:       password,
+		UnixTime:       t.Unix(),
+		HashedPassword: hex.EncodeToString(hashedPassword),
+	}
+	for _, salt := range saltFromTime(t) {
+		keygen := pbkdf2Gen{
+			Salt: salt,
+			Iter: KeyIter,
+		}
+		key, err := keygen.NewKey(hashedPassword, DefaultKeyLen)
+		if err != nil {
+			return v, fmt.Errorf("NewKey() failed: %w", err)
+		}
+		v.Salts = append(v.Salts, hex.EncodeToString(salt))
+		v.Keys = append(v.Keys, hex.EncodeToString(key))
+	}
+	return v, nil
+}
+
+// NewAEADVectors returns the conformance test vectors of all the
+// supported AEAD algorithms. The keys and nonces are fixed byte patterns.
+func NewAEADVectors(plaintext []byte) ([]AEADVector, error) {
+	var vectors []AEADVector
+	for _, aeadType := range []AEADType{AES128GCM, AES256GCM, ChaCha20Poly1305, XChaCha20Poly1305} {
+		keyLen := DefaultKeyLen
+		if aeadType == AES128GCM {
+			keyLen = 16
+		}
+		key := bytePattern(keyLen, 0x00)
+		var block *AEADBlockCipher
+		var err error
+		switch aeadType {
+		case AES128GCM, AES256GCM:
+			block, err = newAESGCMBlockCipher(key)
+		case ChaCha20Poly1305:
+			block, err = newChaCha20Poly1305BlockCipher(key)
+		case XChaCha20Poly1305:
+			block, err = newXChaCha20Poly1305BlockCipher(key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("create %v block cipher failed: %w", aeadType, err)
+		}
+		nonce := bytePattern(block.NonceSize(), 0x80)
+		ciphertext, err := block.EncryptWithNonce(plaintext, nonce)
+		if err != nil {
+			return nil, fmt.Errorf("EncryptWithNonce() failed: %w", err)
+		}
+		vectors = append(vectors, AEADVector{
+			Algorithm:  aeadType.String(),
+			Key:        hex.EncodeToString(key),
+			Nonce:      hex.EncodeToString(nonce),
+			Plaintext:  hex.EncodeToString(plaintext),
+			Ciphertext: hex.EncodeToString(ciphertext),
+		})
+	}
+	return vectors, nil
+}
+
+// bytePattern returns n bytes counting up from start.
+func bytePattern(n int, start byte) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = start + byte(i)
+	}
+	return b
+}
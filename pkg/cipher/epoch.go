@@ -0,0 +1,116 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cipher
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// KeyRotation divides the time into key epochs with the same length.
+// The keys of each epoch are derived from the password and the epoch
+// number, so the keys of an old epoch stop working after the rotation.
+//
+// Around the boundary of two epochs, proxy server accepts the keys of
+// both epochs during the overlap window, so proxy clients with a small
+// clock difference are not broken. Underlays created in an old epoch
+// keep working until they are closed.
+type KeyRotation struct {
+	// Period is the length of a key epoch.
+	Period time.Duration
+
+	// Overlap is the time before and after the epoch boundary
+	// when the keys of the adjacent epoch are also accepted.
+	Overlap time.Duration
+
+	// minEpoch is the oldest epoch accepted by the proxy server.
+	minEpoch atomic.Int64
+}
+
+// NewKeyRotation creates a new key rotation schedule.
+// Key rotation is disabled if period is not positive.
+func NewKeyRotation(period, overlap time.Duration) *KeyRotation {
+	if overlap < 0 {
+		overlap = 0
+	}
+	if overlap > period/2 {
+		overlap = period / 2
+	}
+	return &KeyRotation{
+		Period:  period,
+		Overlap: overlap,
+	}
+}
+
+// Enabled returns true if keys are rotated.
+func (r *KeyRotation) Enabled() bool {
+	return r != nil && r.Period > 0
+}
+
+// Epoch returns the key epoch of the given time.
+func (r *KeyRotation) Epoch(t time.Time) int64 {
+	if !r.Enabled() {
+		return 0
+	}
+	return t.UnixNano() / r.Period.Nanoseconds()
+}
+
+// EpochEnd returns the time when the key epoch of the given time ends.
+func (r *KeyRotation) EpochEnd(t time.Time) time.Time {
+	if !r.Enabled() {
+		return time.Time{}
+	}
+	return time.Unix(0, (r.Epoch(t)+1)*r.Period.Nanoseconds())
+}
+
+// AcceptedEpochs returns the key epochs accepted by proxy server
+// at the given time. The current epoch is always the first one.
+func (r *KeyRotation) AcceptedEpochs(t time.Time) []int64 {
+	if !r.Enabled() {
+		return []int64{0}
+	}
+	epoch := r.Epoch(t)
+	epochs := []int64{epoch}
+	start := time.Unix(0, epoch*r.Period.Nanoseconds())
+	if t.Sub(start) < r.Overlap && epoch-1 >= r.minEpoch.Load() {
+		epochs = append(epochs, epoch-1)
+	}
+	if r.EpochEnd(t).Sub(t) < r.Overlap {
+		epochs = append(epochs, epoch+1)
+	}
+	return epochs
+}
+
+// ForceRotate stops accepting the keys of the epochs before the
+// current one immediately, without waiting for the overlap window.
+// It returns the current epoch.
+func (r *KeyRotation) ForceRotate(t time.Time) int64 {
+	epoch := r.Epoch(t)
+	if r.Enabled() {
+		r.minEpoch.Store(epoch)
+	}
+	return epoch
+}
+
+// EpochPassword returns the password used to generate the keys of the
+// key epoch. If key rotation is disabled, the password is not changed.
+func (r *KeyRotation) EpochPassword(password []byte, epoch int64) []byte {
+	if !r.Enabled() {
+		return password
+	}
+	return HashPassword(append([]byte(nil), password...), []byte("key epoch "+strconv.FormatInt(epoch, 10)))
+}
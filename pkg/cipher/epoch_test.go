@@ -0,0 +1,90 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cipher
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeyRotationDisabled(t *testing.T) {
+	var r *KeyRotation
+	if r.Enabled() {
+		t.Errorf("nil key rotation is enabled")
+	}
+	password := []byte("password")
+	if got := r.EpochPassword(password, 100); !bytes.Equal(got, password) {
+		t.Errorf("EpochPassword() = %v, want %v", got, password)
+	}
+	if got := NewKeyRotation(0, time.Minute).AcceptedEpochs(time.Now()); !reflect.DeepEqual(got, []int64{0}) {
+		t.Errorf("AcceptedEpochs() = %v, want [0]", got)
+	}
+}
+
+func TestKeyRotationAcceptedEpochs(t *testing.T) {
+	r := NewKeyRotation(time.Hour, 10*time.Minute)
+	start := time.Unix(0, 0).Add(1000 * time.Hour)
+	testCases := []struct {
+		t    time.Time
+		want []int64
+	}{
+		{start.Add(30 * time.Minute), []int64{1000}},
+		{start.Add(5 * time.Minute), []int64{1000, 999}},
+		{start.Add(55 * time.Minute), []int64{1000, 1001}},
+		{start.Add(10 * time.Minute), []int64{1000}},
+	}
+	for _, tc := range testCases {
+		if got := r.AcceptedEpochs(tc.t); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("AcceptedEpochs(%v) = %v, want %v", tc.t.Sub(start), got, tc.want)
+		}
+	}
+	if got := r.EpochEnd(start.Add(30 * time.Minute)); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("EpochEnd() = %v, want %v", got, start.Add(time.Hour))
+	}
+
+	// After force rotation, the previous epoch is not accepted.
+	if got := r.ForceRotate(start.Add(5 * time.Minute)); got != 1000 {
+		t.Errorf("ForceRotate() = %d, want 1000", got)
+	}
+	if got := r.AcceptedEpochs(start.Add(5 * time.Minute)); !reflect.DeepEqual(got, []int64{1000}) {
+		t.Errorf("AcceptedEpochs() after ForceRotate() = %v, want [1000]", got)
+	}
+}
+
+func TestKeyRotationEpochPassword(t *testing.T) {
+	r := NewKeyRotation(time.Hour, 10*time.Minute)
+	password := []byte("password")
+	p1 := r.EpochPassword(password, 1)
+	p2 := r.EpochPassword(password, 2)
+	if bytes.Equal(p1, p2) {
+		t.Errorf("passwords of different epochs are the same")
+	}
+	if !bytes.Equal(p1, r.EpochPassword(password, 1)) {
+		t.Errorf("passwords of the same epoch are different")
+	}
+	if !bytes.Equal(password, []byte("password")) {
+		t.Errorf("EpochPassword() modified the input password")
+	}
+}
+
+func TestNewKeyRotationLimitsOverlap(t *testing.T) {
+	r := NewKeyRotation(time.Hour, 2*time.Hour)
+	if r.Overlap != 30*time.Minute {
+		t.Errorf("Overlap = %v, want %v", r.Overlap, 30*time.Minute)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cipher
+
+import (
+	"crypto/ecdh"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// HybridRequestSize is the size of the key exchange request,
+	// which is a X25519 public key followed by a ML-KEM-768
+	// encapsulation key.
+	HybridRequestSize = x25519PublicKeySize + mlkem768EncapsulationKeySize
+
+	// HybridResponseSize is the size of the key exchange response,
+	// which is a X25519 public key followed by a ML-KEM-768 ciphertext.
+	HybridResponseSize = x25519PublicKeySize + mlkem768CiphertextSize
+
+	x25519PublicKeySize          = 32
+	mlkem768EncapsulationKeySize = 1184
+	mlkem768CiphertextSize       = 1088
+
+	hybridSecretInfo      = "mieru hybrid key exchange"
+	hybridBlockCipherInfo = "mieru hybrid block cipher"
+)
+
+// errHybridUnsupported is returned when ML-KEM is not available
+// in the Go version that builds mieru.
+var errHybridUnsupported = errors.New("hybrid key exchange requires mieru built with Go 1.24 or newer")
+
+// mlkemDecapsulator is the ML-KEM-768 private key of the client.
+type mlkemDecapsulator interface {
+	// EncapsulationKey returns the public key sent to the server.
+	EncapsulationKey() []byte
+
+	// Decapsulate returns the shared key from the server's ciphertext.
+	Decapsulate(ciphertext []byte) ([]byte, error)
+}
+
+// HybridKeyExchange is the client side of a hybrid X25519 + ML-KEM-768
+// key exchange. The shared secret is safe as long as one of
+// the two algorithms is not broken.
+type HybridKeyExchange struct {
+	x25519  *ecdh.PrivateKey
+	mlkem   mlkemDecapsulator
+	request []byte
+}
+
+// NewHybridKeyExchange creates the ephemeral keys of a new key exchange.
+func NewHybridKeyExchange() (*HybridKeyExchange, error) {
+	if !HybridKeyExchangeSupported {
+		return nil, errHybridUnsupported
+	}
+	x25519Key, err := ecdh.X25519().GenerateKey(crand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("X25519 GenerateKey() failed: %w", err)
+	}
+	mlkemKey, err := newMLKEM768Key()
+	if err != nil {
+		return nil, fmt.Errorf("ML-KEM-768 GenerateKey() failed: %w", err)
+	}
+	request := make([]byte, 0, HybridRequestSize)
+	request = append(request, x25519Key.PublicKey().Bytes()...)
+	request = append(request, mlkemKey.EncapsulationKey()...)
+	return &HybridKeyExchange{
+		x25519:  x25519Key,
+		mlkem:   mlkemKey,
+		request: request,
+	}, nil
+}
+
+// Request returns the message sent to the server.
+func (k *HybridKeyExchange) Request() []byte {
+	return k.request
+}
+
+// Finish returns the shared secret from the response of the server.
+func (k *HybridKeyExchange) Finish(response []byte) ([]byte, error) {
+	if len(response) != HybridResponseSize {
+		return nil, fmt.Errorf("hybrid key exchange response size is %d, want %d", len(response), HybridResponseSize)
+	}
+	peerKey, err := ecdh.X25519().NewPublicKey(response[:x25519PublicKeySize])
+	if err != nil {
+		return nil, fmt.Errorf("X25519 NewPublicKey() failed: %w", err)
+	}
+	x25519Secret, err := k.x25519.ECDH(peerKey)
+	if err != nil {
+		return nil, fmt.Errorf("X25519 ECDH() failed: %w", err)
+	}
+	mlkemSecret, err := k.mlkem.Decapsulate(response[x25519PublicKeySize:])
+	if err != nil {
+		return nil, fmt.Errorf("ML-KEM-768 Decapsulate() failed: %w", err)
+	}
+	return hybridSecret(x25519Secret, mlkemSecret, k.request, response)
+}
+
+// HybridRespond runs the server side of a hybrid key exchange.
+// It returns the response sent to the client and the shared secret.
+func HybridRespond(request []byte) (response, secret []byte, err error) {
+	if !HybridKeyExchangeSupported {
+		return nil, nil, errHybridUnsupported
+	}
+	if len(request) != HybridRequestSize {
+		return nil, nil, fmt.Errorf("hybrid key exchange request size is %d, want %d", len(request), HybridRequestSize)
+	}
+	peerKey, err := ecdh.X25519().NewPublicKey(request[:x25519PublicKeySize])
+	if err != nil {
+		return nil, nil, fmt.Errorf("X25519 NewPublicKey() failed: %w", err)
+	}
+	x25519Key, err := ecdh.X25519().GenerateKey(crand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("X25519 GenerateKey() failed: %w", err)
+	}
+	x25519Secret, err := x25519Key.ECDH(peerKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("X25519 ECDH() failed: %w", err)
+	}
+	mlkemSecret, ciphertext, err := mlkem768Encapsulate(request[x25519PublicKeySize:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("ML-KEM-768 Encapsulate() failed: %w", err)
+	}
+	response = make([]byte, 0, HybridResponseSize)
+	response = append(response, x25519Key.PublicKey().Bytes()...)
+	response = append(response, ciphertext...)
+	secret, err = hybridSecret(x25519Secret, mlkemSecret, request, response)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, secret, nil
+}
+
+// HybridBlockCipher returns a block cipher that uses a key derived from
+// the key of the given block cipher and the secret of a hybrid key exchange.
+// The new block cipher uses the same AEAD algorithm, and continues from
+// the implicit nonce of the given block cipher.
+func HybridBlockCipher(block BlockCipher, secret []byte) (BlockCipher, error) {
+	c, ok := block.(*AEADBlockCipher)
+	if !ok {
+		return nil, fmt.Errorf("hybrid key exchange doesn't support %T", block)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := make([]byte, c.aeadType.KeyLen())
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, c.key, []byte(hybridBlockCipherInfo)), key); err != nil {
+		return nil, fmt.Errorf("HKDF failed: %w", err)
+	}
+	newCipher, err := newBlockCipher(c.aeadType, key)
+	if err != nil {
+		return nil, err
+	}
+	newCipher.enableImplicitNonce = c.enableImplicitNonce
+	if len(c.implicitNonce) != 0 {
+		newCipher.implicitNonce = make([]byte, len(c.implicitNonce))
+		copy(newCipher.implicitNonce, c.implicitNonce)
+	}
+	newCipher.ctx = c.ctx
+	return newCipher, nil
+}
+
+// hybridSecret combines the secrets of X25519 and ML-KEM-768,
+// and binds them to the messages of the key exchange.
+func hybridSecret(x25519Secret, mlkemSecret, request, response []byte) ([]byte, error) {
+	ikm := make([]byte, 0, len(x25519Secret)+len(mlkemSecret))
+	ikm = append(ikm, x25519Secret...)
+	ikm = append(ikm, mlkemSecret...)
+	transcript := sha256.New()
+	transcript.Write(request)
+	transcript.Write(response)
+	info := append([]byte(hybridSecretInfo), transcript.Sum(nil)...)
+	secret := make([]byte, DefaultKeyLen)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, nil, info), secret); err != nil {
+		return nil, fmt.Errorf("HKDF failed: %w", err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build go1.24
+
+package cipher
+
+import (
+	"crypto/mlkem"
+)
+
+// HybridKeyExchangeSupported is true if this build of mieru
+// supports the hybrid X25519 + ML-KEM-768 key exchange.
+const HybridKeyExchangeSupported = true
+
+type mlkem768Key struct {
+	key *mlkem.DecapsulationKey768
+}
+
+func (k mlkem768Key) EncapsulationKey() []byte {
+	return k.key.EncapsulationKey().Bytes()
+}
+
+func (k mlkem768Key) Decapsulate(ciphertext []byte) ([]byte, error) {
+	return k.key.Decapsulate(ciphertext)
+}
+
+func newMLKEM768Key() (mlkemDecapsulator, error) {
+	key, err := mlkem.GenerateKey768()
+	if err != nil {
+		return nil, err
+	}
+	return mlkem768Key{key: key}, nil
+}
+
+func mlkem768Encapsulate(encapsulationKey []byte) (sharedKey, ciphertext []byte, err error) {
+	key, err := mlkem.NewEncapsulationKey768(encapsulationKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedKey, ciphertext = key.Encapsulate()
+	return sharedKey, ciphertext, nil
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cipher
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHybridKeyExchange(t *testing.T) {
+	if !HybridKeyExchangeSupported {
+		t.Skip("hybrid key exchange is not supported")
+	}
+	client, err := NewHybridKeyExchange()
+	if err != nil {
+		t.Fatalf("NewHybridKeyExchange() failed: %v", err)
+	}
+	if len(client.Request()) != HybridRequestSize {
+		t.Fatalf("request size is %d, want %d", len(client.Request()), HybridRequestSize)
+	}
+	response, serverSecret, err := HybridRespond(client.Request())
+	if err != nil {
+		t.Fatalf("HybridRespond() failed: %v", err)
+	}
+	if len(response) != HybridResponseSize {
+		t.Fatalf("response size is %d, want %d", len(response), HybridResponseSize)
+	}
+	clientSecret, err := client.Finish(response)
+	if err != nil {
+		t.Fatalf("Finish() failed: %v", err)
+	}
+	if !bytes.Equal(clientSecret, serverSecret) {
+		t.Errorf("client and server secrets are different")
+	}
+
+	// A modified response must not produce the same secret.
+	response[len(response)-1] ^= 0xff
+	if modified, err := client.Finish(response); err == nil && bytes.Equal(modified, serverSecret) {
+		t.Errorf("modified response produces the same secret")
+	}
+	if _, err := client.Finish(response[:10]); err == nil {
+		t.Errorf("Finish() with a short response succeeded")
+	}
+	if _, _, err := HybridRespond(client.Request()[:10]); err == nil {
+		t.Errorf("HybridRespond() with a short request succeeded")
+	}
+}
+
+func TestHybridBlockCipher(t *testing.T) {
+	if !HybridKeyExchangeSupported {
+		t.Skip("hybrid key exchange is not supported")
+	}
+	client, err := NewHybridKeyExchange()
+	if err != nil {
+		t.Fatalf("NewHybridKeyExchange() failed: %v", err)
+	}
+	_, secret, err := HybridRespond(client.Request())
+	if err != nil {
+		t.Fatalf("HybridRespond() failed: %v", err)
+	}
+
+	for _, aeadType := range SupportedAEADTypes {
+		block, err := BlockCipherFromPasswordWithAEAD([]byte("password"), aeadType, false)
+		if err != nil {
+			t.Fatalf("BlockCipherFromPasswordWithAEAD() failed: %v", err)
+		}
+		block.SetBlockContext(BlockContext{UserName: "xiaochitang"})
+		send := block.Clone()
+		recv := block.Clone()
+		encrypted, err := send.Encrypt([]byte("before"))
+		if err != nil {
+			t.Fatalf("Encrypt() failed: %v", err)
+		}
+		if _, err := recv.Decrypt(encrypted); err != nil {
+			t.Fatalf("Decrypt() failed: %v", err)
+		}
+
+		hybridSend, err := HybridBlockCipher(send, secret)
+		if err != nil {
+			t.Fatalf("HybridBlockCipher() failed: %v", err)
+		}
+		hybridRecv, err := HybridBlockCipher(recv, secret)
+		if err != nil {
+			t.Fatalf("HybridBlockCipher() failed: %v", err)
+		}
+		if hybridSend.BlockContext().UserName != "xiaochitang" {
+			t.Errorf("%v: block context is not kept", aeadType)
+		}
+		encrypted, err = hybridSend.Encrypt([]byte("after"))
+		if err != nil {
+			t.Fatalf("Encrypt() failed: %v", err)
+		}
+		if len(encrypted) != len("after")+DefaultOverhead {
+			t.Errorf("%v: nonce is sent again after the key exchange", aeadType)
+		}
+		if _, err := recv.Clone().Decrypt(encrypted); err == nil {
+			t.Errorf("%v: the old key decrypted data of the new key", aeadType)
+		}
+		decrypted, err := hybridRecv.Decrypt(encrypted)
+		if err != nil {
+			t.Fatalf("%v: Decrypt() failed: %v", aeadType, err)
+		}
+		if string(decrypted) != "after" {
+			t.Errorf("%v: got %q, want %q", aeadType, decrypted, "after")
+		}
+	}
+}
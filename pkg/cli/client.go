@@ -17,7 +17,6 @@ package cli
 
 import (
 	"context"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -26,13 +25,13 @@ import (
 	"os"
 	"os/exec"
 	"runtime/pprof"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	apicommon "github.com/enfein/mieru/v3/apis/common"
-	"github.com/enfein/mieru/v3/apis/constant"
 	"github.com/enfein/mieru/v3/pkg/appctl"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlgrpc"
@@ -45,7 +44,10 @@ import (
 	"github.com/enfein/mieru/v3/pkg/sockopts"
 	"github.com/enfein/mieru/v3/pkg/socks5"
 	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/tracing"
+	"github.com/enfein/mieru/v3/pkg/tun"
 	"github.com/enfein/mieru/v3/pkg/version/updater"
+	"github.com/enfein/mieru/v3/pkg/version/updater/updaterpb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/proto"
@@ -64,14 +66,14 @@ func RegisterClientCommands() {
 	RegisterCallback(
 		[]string{"", "start"},
 		func(s []string) error {
-			return unexpectedArgsError(s, 2)
+			return configOverrideFlagsError(s, 2)
 		},
 		clientStartFunc,
 	)
 	RegisterCallback(
 		[]string{"", "run"},
 		func(s []string) error {
-			return unexpectedArgsError(s, 2)
+			return configOverrideFlagsError(s, 2)
 		},
 		clientRunFunc,
 	)
@@ -82,9 +84,22 @@ func RegisterClientCommands() {
 		},
 		clientStopFunc,
 	)
+	RegisterCallback(
+		[]string{"", "reload"},
+		func(s []string) error {
+			if len(s) == 3 && s[2] == "--migrate-now" {
+				return nil
+			}
+			return unexpectedArgsError(s, 2)
+		},
+		clientReloadFunc,
+	)
 	RegisterCallback(
 		[]string{"", "status"},
 		func(s []string) error {
+			if len(s) == 3 && s[2] == "--json" {
+				return nil
+			}
 			return unexpectedArgsError(s, 2)
 		},
 		clientStatusFunc,
@@ -116,6 +131,16 @@ func RegisterClientCommands() {
 		},
 		clientApplyConfigFunc,
 	)
+	RegisterCallback(
+		[]string{"", "verify", "config"},
+		func(s []string) error {
+			if len(s) != 4 {
+				return fmt.Errorf("usage: mieru verify config <FILE>")
+			}
+			return nil
+		},
+		clientVerifyConfigFunc,
+	)
 	RegisterCallback(
 		[]string{"", "describe", "config"},
 		func(s []string) error {
@@ -123,6 +148,27 @@ func RegisterClientCommands() {
 		},
 		clientDescribeConfigFunc,
 	)
+	RegisterCallback(
+		[]string{"", "probe"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 2)
+		},
+		clientProbeFunc,
+	)
+	RegisterCallback(
+		[]string{"", "describe", "traffic"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		clientDescribeTrafficFunc,
+	)
+	RegisterCallback(
+		[]string{"", "describe", "network"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		clientDescribeNetworkFunc,
+	)
 	RegisterCallback(
 		[]string{"", "import", "config"},
 		func(s []string) error {
@@ -145,10 +191,31 @@ func RegisterClientCommands() {
 	RegisterCallback(
 		[]string{"", "export", "config"},
 		func(s []string) error {
-			return unexpectedArgsError(s, 3)
+			_, _, err := parseExportConfigArgs(s)
+			return err
 		},
 		clientExportConfigFunc,
 	)
+	RegisterCallback(
+		[]string{"", "import", "profile"},
+		func(s []string) error {
+			if len(s) < 4 {
+				return fmt.Errorf("usage: mieru import profile <URL_OR_FILE>. No URL or file is provided")
+			} else if len(s) > 4 {
+				return fmt.Errorf("usage: mieru import profile <URL_OR_FILE>. More than 1 URL or file is provided")
+			}
+			return nil
+		},
+		clientImportProfileFunc,
+	)
+	RegisterCallback(
+		[]string{"", "export", "profile"},
+		func(s []string) error {
+			_, _, err := parseExportProfileArgs(s)
+			return err
+		},
+		clientExportProfileFunc,
+	)
 	RegisterCallback(
 		[]string{"", "delete", "profile"},
 		func(s []string) error {
@@ -190,12 +257,77 @@ func RegisterClientCommands() {
 		describeBuildFunc,
 	)
 	RegisterCallback(
-		[]string{"", "check", "update"},
+		[]string{"", "describe", "ciphers"},
 		func(s []string) error {
 			return unexpectedArgsError(s, 3)
 		},
+		describeCiphersFunc,
+	)
+	RegisterCallback(
+		[]string{"", "check", "update"},
+		func(s []string) error {
+			_, err := parseUpdateChannelArgs(s, 3, "")
+			return err
+		},
 		clientCheckUpdateFunc,
 	)
+	RegisterCallback(
+		[]string{"", "get", "geo-databases"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		clientGetGeoDatabasesFunc,
+	)
+	RegisterCallback(
+		[]string{"", "update", "geo-databases"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		clientUpdateGeoDatabasesFunc,
+	)
+	RegisterCallback(
+		[]string{"", "update", "subscriptions"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		clientUpdateSubscriptionsFunc,
+	)
+	RegisterCallback(
+		[]string{"", "trust", "list"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		trustListFunc(appctl.ClientTrustStore),
+	)
+	RegisterCallback(
+		[]string{"", "trust", "add"},
+		func(s []string) error {
+			if len(s) < 4 {
+				return fmt.Errorf("usage: mieru trust add <PUBLIC_KEY_OR_FILE>. public key is not provided")
+			}
+			return unexpectedArgsError(s, 4)
+		},
+		trustAddFunc(appctl.ClientTrustStore),
+	)
+	RegisterCallback(
+		[]string{"", "trust", "remove"},
+		func(s []string) error {
+			if len(s) < 4 {
+				return fmt.Errorf("usage: mieru trust remove <KEY_ID>. key ID is not provided")
+			}
+			return unexpectedArgsError(s, 4)
+		},
+		trustRemoveFunc(appctl.ClientTrustStore),
+	)
+	// This must be registered after the other update commands.
+	RegisterCallback(
+		[]string{"", "update"},
+		func(s []string) error {
+			_, _, err := parseSelfUpdateArgs(s, 2, "")
+			return err
+		},
+		clientSelfUpdateFunc,
+	)
 	RegisterCallback(
 		[]string{"", "get", "metrics"},
 		func(s []string) error {
@@ -210,6 +342,44 @@ func RegisterClientCommands() {
 		},
 		clientGetConnectionsFunc,
 	)
+	RegisterCallback(
+		[]string{"", "get", "proxy-connections"},
+		func(s []string) error {
+			if len(s) == 4 && s[3] == "--watch" {
+				return nil
+			}
+			return unexpectedArgsError(s, 3)
+		},
+		clientGetProxyConnectionsFunc,
+	)
+	RegisterCallback(
+		[]string{"", "get", "transparent-proxy-rules"},
+		func(s []string) error {
+			if len(s) == 4 && s[3] == "--delete" {
+				return nil
+			}
+			return unexpectedArgsError(s, 3)
+		},
+		clientGetTransparentProxyRulesFunc,
+	)
+	RegisterCallback(
+		[]string{"", "get", "tun-device-rules"},
+		func(s []string) error {
+			if len(s) == 4 && s[3] == "--delete" {
+				return nil
+			}
+			return unexpectedArgsError(s, 3)
+		},
+		clientGetTUNDeviceRulesFunc,
+	)
+	RegisterCallback(
+		[]string{"", "follow", "logs"},
+		func(s []string) error {
+			_, err := followLogsLevel(s)
+			return err
+		},
+		clientFollowLogsFunc,
+	)
 	RegisterCallback(
 		[]string{"", "get", "thread-dump"},
 		func(s []string) error {
@@ -266,32 +436,78 @@ var clientHelpFunc = func(s []string) error {
 				help: []string{"Show mieru client help."},
 			},
 			{
-				cmd:  "start",
-				help: []string{"Start mieru client in background."},
+				cmd: "start [--FIELD=VALUE]...",
+				help: []string{
+					"Start mieru client in background.",
+					"Flags like --socks5-port=1080 and environment variables like MIERU_SOCKS5_PORT=1080 override the stored configuration.",
+				},
 			},
 			{
 				cmd:  "stop",
 				help: []string{"Stop mieru client."},
 			},
 			{
-				cmd:  "status",
-				help: []string{"Check mieru client status."},
+				cmd: "reload [--migrate-now]",
+				help: []string{
+					"Reload mieru client configuration without stopping proxy service.",
+					"Existing connections keep using the current proxy servers.",
+					"Use --migrate-now to move existing connections to the new proxy servers immediately.",
+				},
+			},
+			{
+				cmd: "status [--json]",
+				help: []string{
+					"Check mieru client status.",
+					"Show the connections to proxy servers, throughput, last error and update availability.",
+					"With --json, print the status in JSON format.",
+				},
 			},
 			{
-				cmd:  "test [URL]",
-				help: []string{"Test mieru client connection to the Internet via proxy server."},
+				cmd: "test [URL]",
+				help: []string{
+					"Test mieru client connection to the Internet via proxy server.",
+					"It checks the local socks5 listener, the tunnel handshake to each proxy server, and fetches the URL through the proxy.",
+					"If a check fails, the reason is printed and the remaining checks are skipped.",
+				},
 			},
 			{
-				cmd: "apply config <JSON_FILE>",
+				cmd: "apply config <FILE>",
 				help: []string{
-					"Apply client configuration patch from a file.",
+					"Apply client configuration patch from a JSON, YAML or TOML file.",
 					"It merges the patch with existing client configuration.",
 				},
 			},
+			{
+				cmd: "verify config <FILE>",
+				help: []string{
+					"Validate client configuration patch from a file without applying it.",
+					"It prints the likely mistakes and the changes to existing client configuration.",
+				},
+			},
 			{
 				cmd:  "describe config",
 				help: []string{"Show current client configuration."},
 			},
+			{
+				cmd: "describe traffic",
+				help: []string{
+					"Show the destinations with the most traffic in the last 24 hours.",
+					"It requires collectDestinationTraffic in advanced settings.",
+				},
+			},
+			{
+				cmd: "describe network",
+				help: []string{
+					"Show the IP address families of this machine, the NAT64 prefix of DNS64,",
+					"and the IP address used to connect to each proxy server in the active profile.",
+				},
+			},
+			{
+				cmd: "probe",
+				help: []string{
+					"Measure the handshake latency of each proxy server in the active profile.",
+				},
+			},
 			{
 				cmd: "import config <URL>",
 				help: []string{
@@ -305,8 +521,26 @@ var clientHelpFunc = func(s []string) error {
 				help: []string{"Export client configuration as URLs in simple format."},
 			},
 			{
-				cmd:  "export config",
-				help: []string{"Export client configuration as a URL."},
+				cmd: "export config [--format json|yaml|uri] [--redact]",
+				help: []string{
+					"Export client configuration. The default format is a URL.",
+					"The output is stable and can be stored in version control.",
+					"Use --redact to hide the passwords and subscription URLs, for example when sharing the configuration in a bug report.",
+				},
+			},
+			{
+				cmd: "import profile <URL_OR_FILE>",
+				help: []string{
+					"Import a client profile from a URL or a JSON file, and use it as the active profile.",
+					"Please use quotation marks to wrap the URL, so it can be parsed correctly.",
+				},
+			},
+			{
+				cmd: "export profile [PROFILE_NAME] [--format url|qrcode|json]",
+				help: []string{
+					"Export a client profile to share it with another client. The default is the active profile.",
+					"The default format is a URL. The qrcode format prints the URL as a QR code.",
+				},
 			},
 			{
 				cmd:  "delete profile <PROFILE_NAME>",
@@ -334,27 +568,114 @@ var clientHelpFunc = func(s []string) error {
 				cmd:  "get connections",
 				help: []string{"Get mieru client connections."},
 			},
+			{
+				cmd: "get proxy-connections [--watch]",
+				help: []string{
+					"Get active proxied connections of mieru client, including destination, duration, traffic and matched bypass rule.",
+					"With --watch, refresh the connections every second until interrupted.",
+				},
+			},
+			{
+				cmd: "get transparent-proxy-rules [--delete]",
+				help: []string{
+					"Print the iptables and ip6tables commands that redirect TCP connections to the transparent proxy.",
+					"With --delete, print the commands that remove the rules.",
+				},
+			},
+			{
+				cmd: "get tun-device-rules [--delete]",
+				help: []string{
+					"Print the ip commands that create the TUN device and route all the traffic to it.",
+					"With --delete, print the commands that remove the TUN device and the routing rules.",
+				},
+			},
+			{
+				cmd: "get geo-databases",
+				help: []string{
+					"Get the version of GeoIP and geosite databases used by bypass rules.",
+					"It requires geoDatabases in client configuration.",
+				},
+			},
+			{
+				cmd: "update geo-databases",
+				help: []string{
+					"Download GeoIP and geosite databases used by bypass rules.",
+					"Run \"mieru reload\" to apply the new databases.",
+				},
+			},
+			{
+				cmd: "update subscriptions",
+				help: []string{
+					"Fetch the profiles from the subscriptions now.",
+					"If mieru client is running, it is reloaded to use the new profiles.",
+				},
+			},
 			{
 				cmd:  "version",
 				help: []string{"Show mieru client version."},
 			},
 			{
-				cmd:  "check update",
-				help: []string{"Check mieru client update."},
+				cmd: "check update [--channel stable|beta]",
+				help: []string{
+					"Check mieru client update.",
+					"The default channel is set by advancedSettings.updateChannel in client configuration.",
+				},
+			},
+			{
+				cmd: "update [--channel stable|beta] [--insecure-skip-signature]",
+				help: []string{
+					"Download and install the latest mieru client release.",
+					"The release is verified with the SHA-256 checksum and the signature of a trusted public key before the binary is replaced.",
+					"If no public key is pinned in the binary or trusted, the release is not installed, unless --insecure-skip-signature is set.",
+					"Run \"mieru stop\" and \"mieru start\" to use the new version.",
+				},
 			},
 		},
 		advanced: []helpCmdEntry{
 			{
-				cmd: "run",
+				cmd: "run [--FIELD=VALUE]...",
 				help: []string{
 					"Run mieru client in foreground.",
 					"Use environment variable MIERU_CONFIG_JSON_FILE to load configuration.",
+					"Flags and MIERU_* environment variables override the stored configuration, like the start command.",
 				},
 			},
 			{
 				cmd:  "describe build",
 				help: []string{"Show mieru build info."},
 			},
+			{
+				cmd: "describe ciphers",
+				help: []string{
+					"Measure the throughput of each cipher suite on this device.",
+					"The cipher suite used by AUTO_CIPHER_SUITE is marked with \"*\".",
+				},
+			},
+			{
+				cmd:  "trust list",
+				help: []string{"List the minisign public keys trusted to verify signatures of releases and geo databases."},
+			},
+			{
+				cmd: "trust add <PUBLIC_KEY_OR_FILE>",
+				help: []string{
+					"Trust a minisign public key, or the public key in a file.",
+					"A revoked pinned public key is trusted again.",
+				},
+			},
+			{
+				cmd: "trust remove <KEY_ID>",
+				help: []string{
+					"Stop trusting the minisign public key with the key ID.",
+					"A pinned public key shipped in the binary is revoked.",
+				},
+			},
+			{
+				cmd: "follow logs [LEVEL]",
+				help: []string{
+					"Print live logs of mieru client until interrupted.",
+					"Only logs at LEVEL or more severe are printed. The default level is INFO.",
+				},
+			},
 			{
 				cmd:  "get thread-dump",
 				help: []string{"Get mieru client thread dump."},
@@ -376,14 +697,32 @@ var clientHelpFunc = func(s []string) error {
 				help: []string{"Stop mieru client CPU profile."},
 			},
 		},
+		options: []helpCmdEntry{
+			{
+				cmd: "--verbose",
+				help: []string{
+					"Print each step of a long running command and the time it takes.",
+					"Without this option, a step is only printed when it takes more than 2 seconds.",
+				},
+			},
+		},
 	}
 	helpFmt.print()
 	return nil
 }
 
 var clientStartFunc = func(s []string) error {
+	p := newProgress()
+	defer p.done()
+
 	// Load and verify client config.
-	config, err := appctl.LoadClientConfig()
+	p.step("Loading client config")
+	overrides, err := appctl.ParseClientConfigOverrides(os.Environ(), s[2:])
+	if err != nil {
+		return fmt.Errorf(stderror.InvalidConfigOverridesErr, err)
+	}
+	appctl.SetClientConfigOverrides(overrides)
+	config, err := appctl.LoadClientConfigWithOverrides()
 	if err != nil {
 		if err == stderror.ErrFileNotExist {
 			return fmt.Errorf(stderror.ClientConfigNotExist)
@@ -395,6 +734,7 @@ var clientStartFunc = func(s []string) error {
 		return fmt.Errorf(stderror.ValidateFullClientConfigFailedErr, err)
 	}
 
+	p.step("Checking client status")
 	if err = appctl.IsClientDaemonRunning(context.Background()); err == nil {
 		if config.GetSocks5ListenLAN() {
 			log.Infof("mieru client is running, listening to socks5://0.0.0.0:%d", config.GetSocks5Port())
@@ -404,7 +744,8 @@ var clientStartFunc = func(s []string) error {
 		return nil
 	}
 
-	cmd := exec.Command(s[0], "run")
+	p.step("Starting client process")
+	cmd := exec.Command(s[0], append([]string{"run"}, s[2:]...)...)
 	if errors.Is(cmd.Err, exec.ErrDot) {
 		cmd.Err = nil
 	}
@@ -415,6 +756,7 @@ var clientStartFunc = func(s []string) error {
 	}
 
 	// Wait until client daemon is running.
+	p.step("Waiting for client to be ready")
 	// The maximum waiting time is 10 seconds.
 	var lastErr error
 	for i := 0; i < 100; i++ {
@@ -427,10 +769,12 @@ var clientStartFunc = func(s []string) error {
 			}
 
 			if should, _ := clientShouldCheckUpdate(config); should {
-				msg, _ := clientCheckUpdateAndUpdateHistory(fmt.Sprintf("socks5://127.0.0.1:%d", config.GetSocks5Port()))
+				p.step("Checking update")
+				record, msg, _ := clientCheckUpdateAndUpdateHistory(fmt.Sprintf("socks5://127.0.0.1:%d", config.GetSocks5Port()), clientUpdateChannel(config))
 				if msg != updater.UpToDateMessage {
 					log.Infof("")
 					log.Infof(msg)
+					printNewRelease(record)
 				}
 			}
 			return nil
@@ -461,7 +805,12 @@ var clientRunFunc = func(s []string) error {
 	}
 
 	// Load and verify client config.
-	config, err := appctl.LoadClientConfig()
+	overrides, err := appctl.ParseClientConfigOverrides(os.Environ(), s[2:])
+	if err != nil {
+		return fmt.Errorf(stderror.InvalidConfigOverridesErr, err)
+	}
+	appctl.SetClientConfigOverrides(overrides)
+	config, err := appctl.LoadClientConfigWithOverrides()
 	if err != nil {
 		if err == stderror.ErrFileNotExist {
 			return fmt.Errorf(stderror.ClientConfigNotExist)
@@ -476,11 +825,12 @@ var clientRunFunc = func(s []string) error {
 		return fmt.Errorf(stderror.ValidateFullClientConfigFailedErr, err)
 	}
 
-	// Set logging level based on client config.
+	// Set logging level and format based on client config.
 	loggingLevel := config.GetLoggingLevel().String()
 	if loggingLevel != appctlpb.LoggingLevel_DEFAULT.String() {
 		log.SetLevel(loggingLevel)
 	}
+	appctl.SetClientLogFormat(config.GetLogFormat())
 
 	// Disable server side metrics.
 	if serverDecryptionMetricGroup := metrics.GetMetricGroupByName(cipher.ServerDecryptionMetricGroupName); serverDecryptionMetricGroup != nil {
@@ -509,7 +859,7 @@ var clientRunFunc = func(s []string) error {
 			if err := sockopts.ApplyTCPControls(rpcListener); err != nil {
 				log.Fatalf("ApplyTCPControls() failed: %v", err)
 			}
-			grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(appctl.MaxRecvMsgSize))
+			grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(appctl.MaxRecvMsgSize), grpc.UnaryInterceptor(appctl.RPCErrorInterceptor))
 			appctl.SetClientRPCServerRef(grpcServer)
 			appctlgrpc.RegisterClientManagementServiceServer(grpcServer, appctl.NewClientManagementService())
 			reflection.Register(grpcServer)
@@ -532,16 +882,12 @@ var clientRunFunc = func(s []string) error {
 		return fmt.Errorf(stderror.ClientGetActiveProfileFailedErr, err)
 	}
 	user := activeProfile.GetUser()
-	var hashedPassword []byte
-	if user.GetHashedPassword() != "" {
-		hashedPassword, err = hex.DecodeString(user.GetHashedPassword())
-		if err != nil {
-			return fmt.Errorf(stderror.DecodeHashedPasswordFailedErr, err)
-		}
-	} else {
-		hashedPassword = cipher.HashPassword([]byte(user.GetPassword()), []byte(user.GetName()))
+	hashedPassword, err := appctl.ClientUserHashedPassword(user)
+	if err != nil {
+		return err
 	}
 	mux = mux.SetClientUserNamePassword(user.GetName(), hashedPassword)
+	mux = mux.SetClientCipherSuite(user.GetCipherSuite())
 
 	multiplexFactor := 1
 	switch activeProfile.GetMultiplexing().GetLevel() {
@@ -555,69 +901,90 @@ var clientRunFunc = func(s []string) error {
 		multiplexFactor = 3
 	}
 	mux = mux.SetClientMultiplexFactor(multiplexFactor)
-
-	mtu := common.DefaultMTU
-	if activeProfile.GetMtu() != 0 {
-		mtu = int(activeProfile.GetMtu())
-	}
-	endpoints := make([]protocol.UnderlayProperties, 0)
-	for _, serverInfo := range activeProfile.GetServers() {
-		var proxyHost string
-		var proxyIP net.IP
-		if serverInfo.GetDomainName() != "" {
-			proxyHost = serverInfo.GetDomainName()
-			proxyIPs, err := resolver.LookupIP(context.Background(), "ip", proxyHost)
-			if err != nil {
-				return fmt.Errorf(stderror.LookupIPFailedErr, err)
-			}
-			if len(proxyIPs) == 0 {
-				return fmt.Errorf(stderror.IPAddressNotFound, proxyHost)
-			}
-			proxyIP = proxyIPs[0]
-		} else {
-			proxyHost = serverInfo.GetIpAddress()
-			proxyIP = net.ParseIP(proxyHost)
-			if proxyIP == nil {
-				return fmt.Errorf(stderror.ParseIPFailed)
-			}
-		}
-		portBindings, err := appctlcommon.FlatPortBindings(serverInfo.GetPortBindings())
-		if err != nil {
-			return fmt.Errorf(stderror.InvalidPortBindingsErr, err)
-		}
-		for _, bindingInfo := range portBindings {
-			proxyPort := bindingInfo.GetPort()
-			switch bindingInfo.GetProtocol() {
-			case appctlpb.TransportProtocol_TCP:
-				endpoint := protocol.NewUnderlayProperties(mtu, common.StreamTransport, nil, &net.TCPAddr{IP: proxyIP, Port: int(proxyPort)})
-				endpoints = append(endpoints, endpoint)
-			case appctlpb.TransportProtocol_UDP:
-				endpoint := protocol.NewUnderlayProperties(mtu, common.PacketTransport, nil, &net.UDPAddr{IP: proxyIP, Port: int(proxyPort)})
-				endpoints = append(endpoints, endpoint)
-			default:
-				return fmt.Errorf(stderror.InvalidTransportProtocol)
-			}
-		}
+	mux = mux.SetClientMaxUnderlays(int(activeProfile.GetMultiplexing().GetMaxConnections()))
+	mux = mux.SetClientPreferLowLatency(config.GetAdvancedSettings().GetSelectServerByLatency())
+	mux = mux.SetClientPathMTUDiscovery(activeProfile.GetPathMTUDiscovery())
+	mux = mux.SetClientHybridKeyExchange(activeProfile.GetHybridKeyExchange())
+	mux = mux.SetClientMultipathDevices(activeProfile.GetMultipath().GetInterfaces())
+	mux = mux.SetClientLocalBinding(appctlcommon.ClientLocalBinding(activeProfile))
+	mux = mux.SetClientHeartbeat(appctlcommon.HeartbeatFromConfig(activeProfile.GetHeartbeat()))
+	mux = mux.SetClientReconnect(appctlcommon.ReconnectPolicyFromConfig(activeProfile.GetReconnect()))
+	mux = mux.SetKeyRotation(appctlcommon.KeyRotationFromConfig(activeProfile.GetKeyRotation()))
+
+	endpoints, err := appctl.ClientProfileToUnderlayProperties(activeProfile, resolver)
+	if err != nil {
+		return err
 	}
 	mux.SetEndpoints(endpoints)
 
+	// Connect to proxy servers through the transport plugin if it is configured.
+	defer appctl.CloseClientTransportPlugins()
+	dialer, err := appctl.ClientProfileDialer(activeProfile)
+	if err != nil {
+		return err
+	}
+	mux.SetDialer(dialer)
+
+	// The latency is also used to select the transport protocol of AUTO port bindings.
+	probeLatency := config.GetAdvancedSettings().GetSelectServerByLatency() || appctl.ClientConfigUsesAutoTransport(config)
+	if probeLatency {
+		appctl.StartClientLatencyProbe(mux.Context(), mux)
+	}
+
+	// Switch to backup profiles when proxy servers are not reachable.
+	failover, err := appctl.NewClientFailover(config, mux)
+	if err != nil {
+		return err
+	}
+	appctl.SetClientFailoverRef(failover)
+	failover.Start()
+	defer failover.Close()
+
+	// Reconnect to proxy servers when the network is changed.
+	if config.GetAdvancedSettings().GetDetectNetworkChange() {
+		monitor := appctl.StartClientNetworkMonitor(mux, failover, probeLatency)
+		defer monitor.Close()
+	}
+
 	// Create the local socks5 server.
-	var socks5IngressCredentials []socks5.Credential
-	for _, auth := range config.GetSocks5Authentication() {
-		socks5IngressCredentials = append(socks5IngressCredentials, socks5.Credential{
-			User:     auth.GetUser(),
-			Password: auth.GetPassword(),
-		})
+	socks5IngressCredentials := appctl.Socks5AuthenticationToCredentials(config.GetSocks5Authentication())
+	socks5GSSAPI, err := appctl.Socks5GSSAPIFromConfig(config.GetSocks5GSSAPI())
+	if err != nil {
+		return fmt.Errorf(stderror.CreateSocks5ServerFailedErr, err)
+	}
+	var destinationStats *metrics.DestinationStats
+	if config.GetAdvancedSettings().GetCollectDestinationTraffic() {
+		destinationStats = metrics.NewDestinationStats(24)
+		appctl.SetClientDestinationStatsRef(destinationStats)
 	}
+	bypass, err := appctl.BypassListFromConfig(config.GetBypass(), config.GetGeoDatabases())
+	if err != nil {
+		return fmt.Errorf(stderror.CreateSocks5ServerFailedErr, err)
+	}
+	connections := socks5.NewConnectionTracker()
+	appctl.SetClientConnectionsRef(connections)
+	fairShare := common.NewFairShareLimiter(int64(config.GetFairShareBandwidthMbps()) * 1000 * 1000 / 8)
 	socks5Config := &socks5.Config{
 		UseProxy: true,
 		AuthOpts: socks5.Auth{
 			ClientSideAuthentication: true,
 			IngressCredentials:       socks5IngressCredentials,
+			GSSAPI:                   socks5GSSAPI,
 		},
 		ProxyMux:         mux,
 		Resolver:         resolver,
 		HandshakeTimeout: 10 * time.Second,
+		DestinationStats: destinationStats,
+		TunnelObserver: func(err error) {
+			failover.ReportTunnelResult(err == nil)
+			appctl.ReportClientTunnelError(err)
+		},
+		FairShare:       fairShare,
+		UDPSourceFilter: config.GetSocks5UDPSourceFilter(),
+		KeepaliveRules:  appctl.KeepaliveRulesFromConfig(config.GetKeepaliveRules()),
+		Bypass:          bypass,
+		Connections:     connections,
+		AcceptLimit:     appctlcommon.AcceptLimitFromConfig(config.GetAdvancedSettings().GetAcceptLimit()),
 	}
 	socks5Server, err := socks5.New(socks5Config)
 	if err != nil {
@@ -654,32 +1021,156 @@ var clientRunFunc = func(s []string) error {
 		wg.Done()
 	}(socks5Addr)
 
-	// If HTTP proxy is enabled, run the local HTTP server in the background.
-	if config.GetHttpProxyPort() != 0 {
-		// HTTP proxy is not compatible with socks5 user password authentication.
-		if len(config.GetSocks5Authentication()) > 0 {
-			log.Fatalf(`HTTP(S) proxy is not compatible with socks5 user password authentication. Please run "mieru delete socks5 authentication" to stop using user password authentication, or run "mieru delete http proxy" command to stop using HTTP(S) proxy.`)
+	// If set, the local socks5 server also listens to a unix domain socket.
+	if socket := config.GetSocks5UnixSocket(); socket != nil {
+		socks5UnixListener, err := listenUnixSocket(socket)
+		if err != nil {
+			return fmt.Errorf(stderror.CreateSocks5ServerFailedErr, err)
 		}
-		wg.Add(1)
-		go func(socks5Addr string) {
-			var httpServerAddr string
-			if config.GetHttpProxyListenLAN() {
-				httpServerAddr = common.MaybeDecorateIPv6(common.AllIPAddr()) + ":" + strconv.Itoa(int(config.GetHttpProxyPort()))
-			} else {
-				httpServerAddr = common.MaybeDecorateIPv6(common.LocalIPAddr()) + ":" + strconv.Itoa(int(config.GetHttpProxyPort()))
-			}
-			httpServer := socks5.NewHTTPProxyServer(httpServerAddr, &socks5.HTTPProxy{
-				ProxyURI: "socks5://" + socks5Addr + "?timeout=10s",
-			})
-			log.Infof("mieru client HTTP proxy server is running")
-			wg.Done()
-			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-				log.Fatalf("run HTTP proxy server failed: %v", err)
+		go func() {
+			log.Infof("mieru client socks5 server is listening to unix domain socket %q", socket.GetPath())
+			if err := socks5Server.Serve(socks5UnixListener); err != nil {
+				log.Fatalf("run socks5 server on unix domain socket failed: %v", err)
 			}
-		}(socks5Addr)
+		}()
 	}
 
-	<-appctl.ClientSocks5ServerStarted
+	// Run the additional local socks5 listeners in the background.
+	if len(config.GetSocks5Listeners()) > 0 {
+		socks5Listeners, err := appctl.NewClientSocks5Listeners(config, mux, *socks5Config)
+		if err != nil {
+			return fmt.Errorf(stderror.CreateSocks5ServerFailedErr, err)
+		}
+		defer socks5Listeners.Close()
+		if err := socks5Listeners.Start(); err != nil {
+			return fmt.Errorf(stderror.CreateSocks5ServerFailedErr, err)
+		}
+		appctl.SetClientSocks5ListenersRef(socks5Listeners)
+	}
+
+	// Fetch the subscriptions periodically.
+	if len(config.GetSubscriptions()) > 0 {
+		subscriptionUpdater := appctl.NewClientSubscriptionUpdater()
+		subscriptionUpdater.Start()
+		defer subscriptionUpdater.Close()
+	}
+
+	// If HTTP proxy is enabled, run the local HTTP server in the background.
+	if config.GetHttpProxyPort() != 0 || config.GetHttpProxyUnixSocket() != nil {
+		// HTTP proxy is not compatible with socks5 user password authentication.
+		if len(config.GetSocks5Authentication()) > 0 {
+			log.Fatalf(`HTTP(S) proxy is not compatible with socks5 user password authentication. Please run "mieru delete socks5 authentication" to stop using user password authentication, or run "mieru delete http proxy" command to stop using HTTP(S) proxy.`)
+		}
+		if config.GetSocks5GSSAPI() != nil {
+			log.Fatalf(`HTTP(S) proxy is not compatible with socks5 GSSAPI authentication. Please remove "socks5GSSAPI" from the client config, or run "mieru delete http proxy" command to stop using HTTP(S) proxy.`)
+		}
+		httpProxy := &socks5.HTTPProxy{
+			ProxyURI:  "socks5://" + socks5Addr + "?timeout=10s",
+			FairShare: fairShare,
+		}
+		if config.GetHttpProxyPort() != 0 {
+			wg.Add(1)
+			go func() {
+				var httpServerAddr string
+				if config.GetHttpProxyListenLAN() {
+					httpServerAddr = common.MaybeDecorateIPv6(common.AllIPAddr()) + ":" + strconv.Itoa(int(config.GetHttpProxyPort()))
+				} else {
+					httpServerAddr = common.MaybeDecorateIPv6(common.LocalIPAddr()) + ":" + strconv.Itoa(int(config.GetHttpProxyPort()))
+				}
+				httpServer := socks5.NewHTTPProxyServer(httpServerAddr, httpProxy)
+				log.Infof("mieru client HTTP proxy server is running")
+				wg.Done()
+				if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Fatalf("run HTTP proxy server failed: %v", err)
+				}
+			}()
+		}
+		if socket := config.GetHttpProxyUnixSocket(); socket != nil {
+			httpUnixListener, err := listenUnixSocket(socket)
+			if err != nil {
+				return fmt.Errorf("listen to HTTP proxy unix domain socket failed: %w", err)
+			}
+			go func() {
+				httpServer := socks5.NewHTTPProxyServer("", httpProxy)
+				log.Infof("mieru client HTTP proxy server is listening to unix domain socket %q", socket.GetPath())
+				if err := httpServer.Serve(httpUnixListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Fatalf("run HTTP proxy server on unix domain socket failed: %v", err)
+				}
+			}()
+		}
+	}
+
+	// If transparent proxy is enabled, forward the connections redirected
+	// by the firewall in the background.
+	if tproxy := config.GetTransparentProxy(); tproxy != nil {
+		if !socks5.TransparentProxySupported {
+			return fmt.Errorf("transparent proxy is only supported on Linux")
+		}
+		// Transparent proxy is not compatible with socks5 authentication.
+		if len(config.GetSocks5Authentication()) > 0 || config.GetSocks5GSSAPI() != nil {
+			log.Fatalf(`Transparent proxy is not compatible with socks5 authentication. Please remove "transparentProxy" or socks5 authentication from the client config.`)
+		}
+		transparentProxy := &socks5.TransparentProxy{
+			ProxyURI:  "socks5://" + socks5Addr + "?timeout=10s",
+			FairShare: fairShare,
+		}
+		// The firewall redirects IPv4 connections to 127.0.0.1,
+		// and IPv6 connections to ::1.
+		for _, ip := range []string{"127.0.0.1", "::1"} {
+			addr := net.JoinHostPort(ip, strconv.Itoa(int(tproxy.GetPort())))
+			l, err := net.Listen("tcp", addr)
+			if err != nil {
+				if ip == "::1" {
+					log.Warnf("Transparent proxy can't listen to %s, IPv6 connections are not forwarded: %v", addr, err)
+					continue
+				}
+				return fmt.Errorf("listen to transparent proxy address %s failed: %w", addr, err)
+			}
+			defer l.Close()
+			go func() {
+				log.Infof("mieru client transparent proxy is listening to %s", addr)
+				if err := transparentProxy.Serve(l); err != nil {
+					log.Fatalf("run transparent proxy failed: %v", err)
+				}
+			}()
+		}
+	}
+
+	// If TUN device is enabled, forward the traffic captured by the
+	// TUN device in the background.
+	if config.GetTunDevice() != nil {
+		if !tun.Supported {
+			return fmt.Errorf("TUN device mode is only supported on Linux")
+		}
+		// TUN device mode is not compatible with socks5 authentication.
+		if len(config.GetSocks5Authentication()) > 0 || config.GetSocks5GSSAPI() != nil {
+			log.Fatalf(`TUN device mode is not compatible with socks5 authentication. Please remove "tunDevice" or socks5 authentication from the client config.`)
+		}
+		opts, err := appctl.GetTUNDeviceOptions(config.GetTunDevice())
+		if err != nil {
+			return err
+		}
+		device, err := tun.OpenDevice(opts.Name)
+		if err != nil {
+			return fmt.Errorf(`%w. Run "mieru get tun-device-rules" to print the commands that create the TUN device`, err)
+		}
+		stack := &tun.Stack{
+			Device:     device,
+			IPv4:       opts.IPv4,
+			IPv6:       opts.IPv6,
+			MTU:        opts.MTU,
+			Socks5Addr: common.LocalIPAddr() + ":" + strconv.Itoa(int(config.GetSocks5Port())),
+		}
+		defer stack.Close()
+		go func() {
+			log.Infof("mieru client is forwarding the traffic of TUN device %s", opts.Name)
+			if err := stack.Run(); err != nil {
+				log.Fatalf("run TUN device failed: %v", err)
+			}
+		}()
+	}
+
+	<-appctl.ClientSocks5ServerStarted
 
 	if config.GetAdvancedSettings().GetMetricsLoggingInterval() != "" {
 		metricsDuration, err := time.ParseDuration(config.GetAdvancedSettings().GetMetricsLoggingInterval())
@@ -693,6 +1184,20 @@ var clientRunFunc = func(s []string) error {
 	}
 	metrics.EnableLogging()
 
+	if config.GetAdvancedSettings().GetOtlpTraceEndpoint() != "" {
+		exporter, err := tracing.NewExporter(config.GetAdvancedSettings().GetOtlpTraceEndpoint(), "mieru")
+		if err != nil {
+			log.Warnf("Failed to create OpenTelemetry trace exporter: %v", err)
+		} else {
+			tracing.SetExporter(exporter)
+		}
+	}
+
+	reloadOnSIGHUP(func() error {
+		return appctl.ReloadClientConfig(false)
+	})
+
+	appctl.StartClientStatusTracking()
 	appctl.SetAppStatus(appctlpb.AppStatus_RUNNING)
 	log.Debugf("Started proxy after %v", appctl.Elapsed())
 	wg.Wait()
@@ -723,6 +1228,25 @@ var clientStopFunc = func(s []string) error {
 	return nil
 }
 
+var clientReloadFunc = func(s []string) error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	client, running, err := newClientManagementRPCClient(ctx)
+	if !running {
+		return fmt.Errorf(stderror.ClientNotRunning)
+	}
+	if err != nil {
+		return err
+	}
+
+	migrateNow := len(s) == 3 && s[2] == "--migrate-now"
+	if _, err = client.Reload(ctx, &appctlpb.ReloadClientRequest{MigrateNow: proto.Bool(migrateNow)}); err != nil {
+		return fmt.Errorf(stderror.ReloadClientFailedErr, err)
+	}
+	log.Infof("mieru client is reloaded")
+	return nil
+}
+
 var clientStatusFunc = func(s []string) error {
 	if err := appctl.IsClientDaemonRunning(context.Background()); err != nil {
 		if stderror.IsConnRefused(err) {
@@ -735,7 +1259,38 @@ var clientStatusFunc = func(s []string) error {
 			return fmt.Errorf(stderror.ClientNotRunningErr, err)
 		}
 	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	client, err := appctl.NewClientManagementRPCClient()
+	if err != nil {
+		return fmt.Errorf(stderror.CreateClientManagementRPCClientFailedErr, err)
+	}
+	status, err := client.GetStatus(ctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.ClientNotRunningErr, err)
+	}
+	var latest *updaterpb.UpdateRecord
+	if historyFile, err := appctl.ClientUpdaterHistoryPath(); err == nil {
+		h := updater.NewHistory()
+		if err := h.LoadFrom(historyFile); err == nil {
+			latest = h.Latest()
+		}
+	}
+	summary := newClientStatusSummary(status, latest, time.Now())
+
+	if len(s) == 3 && s[2] == "--json" {
+		out, err := summary.JSON()
+		if err != nil {
+			return err
+		}
+		log.Infof("%s", out)
+		return nil
+	}
 	log.Infof("mieru client is running")
+	for _, line := range summary.Lines(useColor()) {
+		log.Infof("%s", line)
+	}
 	return nil
 }
 
@@ -747,47 +1302,37 @@ var clientTestFunc = func(s []string) error {
 	if err != nil {
 		return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
 	}
-
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			Dial: socks5.Dial(fmt.Sprintf("socks5://127.0.0.1:%d", config.GetSocks5Port()), constant.Socks5ConnectCmd),
-		},
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return nil
-		},
-		Timeout: appctl.RPCTimeout,
-	}
-
 	destination := "https://google.com/generate_204"
 	if len(s) == 3 {
 		destination = s[2]
 	}
-	beginTime := time.Now()
-	resp, err := httpClient.Get(destination)
-	if err != nil {
-		return err
-	}
-	endTime := time.Now()
-	d := endTime.Sub(beginTime).Round(time.Millisecond)
-	defer resp.Body.Close()
-	io.ReadAll(resp.Body)
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("received unexpected status code %d after %v", resp.StatusCode, d)
-	}
-	log.Infof("Connected to %q after %v", destination, d)
-	return nil
+	return runClientSelfTest(config, destination)
 }
 
 var clientApplyConfigFunc = func(s []string) error {
+	p := newProgress()
+	defer p.done()
+
+	p.step("Loading client config")
 	if _, err := appctl.LoadClientConfig(); err == stderror.ErrFileNotExist {
+		p.step("Creating client config")
 		if err = appctl.StoreClientConfig(&appctlpb.ClientConfig{}); err != nil {
 			return fmt.Errorf(stderror.StoreClientConfigFailedErr, err)
 		}
 	}
+	p.step("Applying %s", s[3])
 	return appctl.ApplyJSONClientConfig(s[3])
 }
 
+var clientVerifyConfigFunc = func(s []string) error {
+	v, err := appctl.VerifyJSONClientConfig(s[3])
+	if err != nil {
+		return fmt.Errorf(stderror.VerifyConfigFailedErr, err)
+	}
+	printConfigVerification(v)
+	return nil
+}
+
 var clientDescribeConfigFunc = func(s []string) error {
 	if _, err := appctl.LoadClientConfig(); err == stderror.ErrFileNotExist {
 		if err = appctl.StoreClientConfig(&appctlpb.ClientConfig{}); err != nil {
@@ -834,7 +1379,42 @@ var clientExportConfigSimpleFunc = func(s []string) error {
 }
 
 var clientExportConfigFunc = func(s []string) error {
-	_, err := appctl.LoadClientConfig()
+	format, redact, err := parseExportConfigArgs(s)
+	if err != nil {
+		return err
+	}
+	_, err = appctl.LoadClientConfig()
+	if err != nil {
+		if err == stderror.ErrFileNotExist {
+			return fmt.Errorf(stderror.ClientConfigNotExist)
+		} else {
+			return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
+		}
+	}
+	out, err := appctl.ExportClientConfig(format, redact)
+	if err != nil {
+		return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
+	}
+	log.Infof("%s", out)
+	return nil
+}
+
+var clientImportProfileFunc = func(s []string) error {
+	name, err := appctl.ImportClientProfile(s[3])
+	if err != nil {
+		return err
+	}
+	log.Infof("Profile %q is imported and used as the active profile.", name)
+	log.Infof("If mieru client is running, run command \"mieru stop\" and \"mieru start\" to use the new profile.")
+	return nil
+}
+
+var clientExportProfileFunc = func(s []string) error {
+	name, format, err := parseExportProfileArgs(s)
+	if err != nil {
+		return err
+	}
+	_, err = appctl.LoadClientConfig()
 	if err != nil {
 		if err == stderror.ErrFileNotExist {
 			return fmt.Errorf(stderror.ClientConfigNotExist)
@@ -842,7 +1422,7 @@ var clientExportConfigFunc = func(s []string) error {
 			return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
 		}
 	}
-	out, err := appctl.GetURLClientConfig()
+	out, err := appctl.ExportClientProfile(name, format)
 	if err != nil {
 		return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
 	}
@@ -863,12 +1443,13 @@ var clientDeleteHTTPProxyFunc = func(_ []string) error {
 	if err != nil {
 		return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
 	}
-	if config.HttpProxyPort == nil && config.HttpProxyListenLAN == nil {
+	if config.HttpProxyPort == nil && config.HttpProxyListenLAN == nil && config.HttpProxyUnixSocket == nil {
 		log.Infof("HTTP proxy is already deleted from client config.")
 		return nil
 	}
 	config.HttpProxyPort = nil
 	config.HttpProxyListenLAN = nil
+	config.HttpProxyUnixSocket = nil
 	if err := appctl.StoreClientConfig(config); err != nil {
 		return fmt.Errorf(stderror.StoreClientConfigFailedErr, err)
 	}
@@ -893,18 +1474,78 @@ var clientDeleteSocks5AuthenticationFunc = func(_ []string) error {
 	return nil
 }
 
+var clientGetGeoDatabasesFunc = func(_ []string) error {
+	config, err := appctl.LoadClientConfig()
+	if err != nil {
+		return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
+	}
+	return printGeoDatabases(config.GetGeoDatabases())
+}
+
+var clientUpdateGeoDatabasesFunc = func(_ []string) error {
+	config, err := appctl.LoadClientConfig()
+	if err != nil {
+		return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
+	}
+	trust, err := appctl.ClientTrustStore()
+	if err != nil {
+		return fmt.Errorf("appctl.ClientTrustStore() failed: %w", err)
+	}
+	return updateGeoDatabases(config.GetGeoDatabases(), trust)
+}
+
+var clientUpdateSubscriptionsFunc = func(_ []string) error {
+	p := newProgress()
+	p.step("Fetching subscriptions")
+	changed, err := appctl.UpdateClientSubscriptions(context.Background())
+	p.done()
+	if err != nil {
+		if !changed {
+			return fmt.Errorf(stderror.UpdateSubscriptionsFailedErr, err)
+		}
+		log.Warnf("%v", err)
+	}
+	if !changed {
+		log.Infof("Profiles from subscriptions are not changed.")
+		return nil
+	}
+	log.Infof("Profiles from subscriptions are updated.")
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	client, running, err := newClientManagementRPCClient(ctx)
+	if !running || err != nil {
+		return nil
+	}
+	if _, err = client.Reload(ctx, &appctlpb.ReloadClientRequest{}); err != nil {
+		return fmt.Errorf(stderror.ReloadClientFailedErr, err)
+	}
+	log.Infof("mieru client is reloaded")
+	return nil
+}
+
 var clientCheckUpdateFunc = func(s []string) error {
+	p := newProgress()
+	defer p.done()
+
 	var socks5ProxyURI string
-	if err := appctl.IsClientDaemonRunning(context.Background()); err == nil {
+	p.step("Checking client status")
+	config, configErr := appctl.LoadClientConfig()
+	if err := appctl.IsClientDaemonRunning(context.Background()); err == nil && configErr == nil {
 		// Client is running. Use the socks5 proxy to check update.
-		config, err := appctl.LoadClientConfig()
-		if err == nil {
-			socks5ProxyURI = fmt.Sprintf("socks5://127.0.0.1:%d", config.GetSocks5Port())
-		}
-		// Otherwise, silently drop the error.
+		socks5ProxyURI = fmt.Sprintf("socks5://127.0.0.1:%d", config.GetSocks5Port())
+	}
+	channel, err := parseUpdateChannelArgs(s, 3, clientUpdateChannel(config))
+	if err != nil {
+		return err
 	}
 
-	msg, err := clientCheckUpdateAndUpdateHistory(socks5ProxyURI)
+	if socks5ProxyURI == "" {
+		p.step("Querying latest release from GitHub")
+	} else {
+		p.step("Querying latest release from GitHub via %s", socks5ProxyURI)
+	}
+	record, msg, err := clientCheckUpdateAndUpdateHistory(socks5ProxyURI, channel)
 	if err != nil {
 		if socks5ProxyURI == "" {
 			return fmt.Errorf("check update without proxy failed: %w; please start mieru proxy client and try again", err)
@@ -913,6 +1554,63 @@ var clientCheckUpdateFunc = func(s []string) error {
 		}
 	}
 	log.Infof("%s", msg)
+	printNewRelease(record)
+	return nil
+}
+
+var clientSelfUpdateFunc = func(s []string) error {
+	p := newProgress()
+	defer p.done()
+
+	var socks5ProxyURI string
+	p.step("Checking client status")
+	config, configErr := appctl.LoadClientConfig()
+	running := appctl.IsClientDaemonRunning(context.Background()) == nil
+	if running && configErr == nil {
+		// Client is running. Use the socks5 proxy to download the update.
+		socks5ProxyURI = fmt.Sprintf("socks5://127.0.0.1:%d", config.GetSocks5Port())
+	}
+	channel, skipSignature, err := parseSelfUpdateArgs(s, 2, clientUpdateChannel(config))
+	if err != nil {
+		return err
+	}
+
+	if socks5ProxyURI == "" {
+		p.step("Downloading latest release from GitHub")
+	} else {
+		p.step("Downloading latest release from GitHub via %s", socks5ProxyURI)
+	}
+	trust, err := appctl.ClientTrustStore()
+	if err != nil {
+		return fmt.Errorf("appctl.ClientTrustStore() failed: %w", err)
+	}
+	historyFile, _ := appctl.ClientUpdaterHistoryPath()
+	record, msg, err := runUpdater(historyFile, func(h *updater.History) (*updaterpb.UpdateRecord, string, error) {
+		return updater.SelfUpdate(updater.SelfUpdateOptions{
+			CheckUpdateOptions: updater.CheckUpdateOptions{
+				App:            "mieru",
+				Channel:        channel,
+				Socks5ProxyURI: socks5ProxyURI,
+				History:        h,
+			},
+			TrustStore:            trust,
+			InsecureSkipSignature: skipSignature,
+		})
+	})
+	if errors.Is(err, updater.ErrNoTrustedKey) {
+		return selfUpdateError(err)
+	}
+	if err != nil {
+		if socks5ProxyURI == "" {
+			return fmt.Errorf("update without proxy failed: %w; please start mieru proxy client and try again", err)
+		} else {
+			return fmt.Errorf("update with proxy %s failed: %w", socks5ProxyURI, err)
+		}
+	}
+	if record.GetInstalledVersion() != "" && running {
+		msg += "; run \"mieru stop\" and \"mieru start\" to use the new version"
+	}
+	log.Infof("%s", msg)
 	return nil
 }
 
@@ -954,6 +1652,249 @@ var clientGetConnectionsFunc = func(s []string) error {
 	return nil
 }
 
+var clientGetProxyConnectionsFunc = func(s []string) error {
+	watch := len(s) == 4 && s[3] == "--watch"
+	for {
+		if err := printClientProxyConnections(watch); err != nil {
+			return err
+		}
+		if !watch {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// printClientProxyConnections prints the active proxied connections.
+// If clearScreen is true, the terminal is cleared before printing.
+func printClientProxyConnections(clearScreen bool) error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	client, running, err := newClientManagementRPCClient(ctx)
+	if !running {
+		return fmt.Errorf(stderror.ClientNotRunning)
+	}
+	if err != nil {
+		return err
+	}
+
+	list, err := client.GetProxyConnections(ctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.GetProxyConnectionsFailedErr, err)
+	}
+	table := make([][]string, 0)
+	table = append(table, []string{"SessionID", "Destination", "Protocol", "Route", "Rule", "Duration", "Upload", "Download"})
+	now := time.Now()
+	for _, c := range list.GetItems() {
+		rule := c.GetRule()
+		if rule == "" {
+			rule = "-"
+		}
+		table = append(table, []string{
+			strconv.FormatUint(uint64(c.GetSessionID()), 10),
+			c.GetDestination(),
+			c.GetProtocol(),
+			c.GetRoute(),
+			rule,
+			now.Sub(c.GetStartTime().AsTime()).Truncate(time.Second).String(),
+			common.ByteCountIEC(c.GetUploadBytes()),
+			common.ByteCountIEC(c.GetDownloadBytes()),
+		})
+	}
+	if clearScreen {
+		fmt.Print("\033[H\033[2J")
+	}
+	printTable(table, "  ")
+	return nil
+}
+
+var clientGetTransparentProxyRulesFunc = func(s []string) error {
+	config, err := appctl.LoadClientConfig()
+	if err != nil {
+		return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
+	}
+	remove := len(s) == 4 && s[3] == "--delete"
+	rules, err := appctl.TransparentProxyFirewallRules(context.Background(), config, &net.Resolver{}, remove)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		fmt.Println(rule)
+	}
+	return nil
+}
+
+var clientGetTUNDeviceRulesFunc = func(s []string) error {
+	config, err := appctl.LoadClientConfig()
+	if err != nil {
+		return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
+	}
+	rules, err := appctl.TUNDeviceRules(config, len(s) == 4 && s[3] == "--delete")
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		fmt.Println(rule)
+	}
+	return nil
+}
+
+var clientDescribeTrafficFunc = func(s []string) error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	client, running, err := newClientManagementRPCClient(ctx)
+	if !running {
+		return fmt.Errorf(stderror.ClientNotRunning)
+	}
+	if err != nil {
+		return err
+	}
+
+	list, err := client.GetDestinationTraffic(ctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.GetDestinationTrafficFailedErr, err)
+	}
+	table := make([][]string, 0)
+	table = append(table, []string{"Destination", "Download", "Upload", "Total"})
+	for _, t := range list.GetItems() {
+		table = append(table, []string{
+			t.GetDestination(),
+			common.ByteCountIEC(t.GetDownloadBytes()),
+			common.ByteCountIEC(t.GetUploadBytes()),
+			common.ByteCountIEC(t.GetDownloadBytes() + t.GetUploadBytes()),
+		})
+	}
+	printTable(table, "  ")
+	return nil
+}
+
+var clientDescribeNetworkFunc = func(s []string) error {
+	config, err := appctl.LoadClientConfigWithOverrides()
+	if err != nil {
+		if err == stderror.ErrFileNotExist {
+			return fmt.Errorf(stderror.ClientConfigNotExist)
+		}
+		return fmt.Errorf(stderror.GetClientConfigFailedErr, err)
+	}
+	profile, err := appctl.GetActiveProfileFromConfig(config, config.GetActiveProfile())
+	if err != nil {
+		return fmt.Errorf(stderror.ClientGetActiveProfileFailedErr, err)
+	}
+	var resolver apicommon.DNSResolver = &net.Resolver{}
+	if profile.GetBootstrapDoHURL() != "" {
+		resolver = &common.DoHResolver{URL: profile.GetBootstrapDoHURL()}
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+
+	log.Infof("Address families: %v", common.DetectAddressFamilies())
+	prefix, err := common.DetectNAT64Prefix(ctx, resolver)
+	if err != nil {
+		log.Infof("NAT64 prefix: discovery failed: %v", err)
+	} else if prefix == nil {
+		log.Infof("NAT64 prefix: not found")
+	} else {
+		log.Infof("NAT64 prefix: %v", prefix)
+	}
+
+	table := make([][]string, 0)
+	table = append(table, []string{"Server", "Address", "Family"})
+	for _, server := range profile.GetServers() {
+		name := server.GetDomainName()
+		if name == "" {
+			name = server.GetIpAddress()
+		}
+		ips, err := appctlcommon.ResolveServerIPs(ctx, server, resolver)
+		if err != nil {
+			table = append(table, []string{name, err.Error(), "-"})
+			continue
+		}
+		for _, ip := range ips {
+			family := "IPv6"
+			if ip.To4() != nil {
+				family = "IPv4"
+			} else if prefix != nil && common.ExtractNAT64IPv4(prefix, ip) != nil {
+				family = fmt.Sprintf("IPv6 (NAT64 of %v)", common.ExtractNAT64IPv4(prefix, ip))
+			}
+			table = append(table, []string{name, ip.String(), family})
+		}
+	}
+	printTable(table, "  ")
+	return nil
+}
+
+var clientProbeFunc = func(s []string) error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	client, running, err := newClientManagementRPCClient(ctx)
+	if !running {
+		return fmt.Errorf(stderror.ClientNotRunning)
+	}
+	if err != nil {
+		return err
+	}
+
+	list, err := client.ProbeServers(ctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.ProbeServersFailedErr, err)
+	}
+	items := list.GetItems()
+	sort.SliceStable(items, func(i, j int) bool {
+		// Reachable servers first, then from the lowest latency to the highest.
+		if items[i].RttMicros == nil || items[j].RttMicros == nil {
+			return items[i].RttMicros != nil
+		}
+		return items[i].GetRttMicros() < items[j].GetRttMicros()
+	})
+	table := make([][]string, 0)
+	table = append(table, []string{"Server", "Protocol", "RTT", "Error"})
+	for _, item := range items {
+		rtt := "-"
+		if item.RttMicros != nil {
+			rtt = (time.Duration(item.GetRttMicros()) * time.Microsecond).Round(100 * time.Microsecond).String()
+		}
+		table = append(table, []string{
+			common.MaybeDecorateIPv6(item.GetIpAddress()) + ":" + strconv.Itoa(int(item.GetPort())),
+			item.GetProtocol().String(),
+			rtt,
+			item.GetError(),
+		})
+	}
+	printTable(table, "  ")
+	return nil
+}
+
+var clientFollowLogsFunc = func(s []string) error {
+	level, err := followLogsLevel(s)
+	if err != nil {
+		return err
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	client, running, err := newClientManagementRPCClient(timedctx)
+	if !running {
+		return fmt.Errorf(stderror.ClientNotRunning)
+	}
+	if err != nil {
+		return err
+	}
+
+	stream, err := client.FollowLogs(context.Background(), &appctlpb.FollowLogsRequest{Level: level.Enum()})
+	if err != nil {
+		return fmt.Errorf(stderror.FollowLogsFailedErr, err)
+	}
+	for {
+		entry, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf(stderror.FollowLogsFailedErr, err)
+		}
+		log.Infof("%s", formatLogEntry(entry))
+	}
+}
+
 var clientGetThreadDumpFunc = func(s []string) error {
 	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
 	defer cancelFunc()
@@ -1078,16 +2019,87 @@ func clientShouldCheckUpdate(config *appctlpb.ClientConfig) (bool, error) {
 	return h.ShouldCheckUpdate(), nil
 }
 
-func clientCheckUpdateAndUpdateHistory(socks5ProxyURI string) (string, error) {
+func clientCheckUpdateAndUpdateHistory(socks5ProxyURI, channel string) (*updaterpb.UpdateRecord, string, error) {
 	historyFile, err := appctl.ClientUpdaterHistoryPath()
 	if err != nil {
-		return "", fmt.Errorf("failed to get client updater history file path")
+		return nil, "", fmt.Errorf("failed to get client updater history file path")
+	}
+	return runUpdater(historyFile, func(h *updater.History) (*updaterpb.UpdateRecord, string, error) {
+		return updater.CheckUpdate(updater.CheckUpdateOptions{
+			App:            "mieru",
+			Channel:        channel,
+			Socks5ProxyURI: socks5ProxyURI,
+			History:        h,
+		})
+	})
+}
+
+// clientUpdateChannel returns the update channel in the client config.
+func clientUpdateChannel(config *appctlpb.ClientConfig) string {
+	if config.GetAdvancedSettings().GetUpdateChannel() == appctlpb.UpdateChannel_UPDATE_CHANNEL_BETA {
+		return updater.ChannelBeta
 	}
-	h := updater.NewHistory()
-	h.LoadFrom(historyFile) // OK to fail. No side effect.
-	record, msg, checkErr := updater.CheckUpdate(socks5ProxyURI)
-	h.Insert(record)
-	h.Trim()
-	h.StoreTo(historyFile) // OK to fail.
-	return msg, checkErr
+	return updater.ChannelStable
+}
+
+// parseExportConfigArgs returns the format and whether to redact secrets
+// from the arguments of "mieru export config" command.
+func parseExportConfigArgs(s []string) (format string, redact bool, err error) {
+	format = appctl.ExportFormatURI
+	for i := 3; i < len(s); i++ {
+		switch s[i] {
+		case "--format":
+			if i+1 >= len(s) {
+				return "", false, fmt.Errorf("usage: mieru export config [--format json|yaml|uri] [--redact]. format is not provided")
+			}
+			i++
+			format = s[i]
+			if format != appctl.ExportFormatJSON && format != appctl.ExportFormatYAML && format != appctl.ExportFormatURI {
+				return "", false, fmt.Errorf("usage: mieru export config [--format json|yaml|uri] [--redact]. format %q is not supported", format)
+			}
+		case "--redact":
+			redact = true
+		default:
+			return "", false, fmt.Errorf("usage: mieru export config [--format json|yaml|uri] [--redact]. unexpected argument %q", s[i])
+		}
+	}
+	return
+}
+
+// parseExportProfileArgs returns the profile name and the format
+// from the arguments of "mieru export profile" command.
+func parseExportProfileArgs(s []string) (name, format string, err error) {
+	const usage = "usage: mieru export profile [PROFILE_NAME] [--format url|qrcode|json]"
+	format = appctl.ProfileExportFormatURL
+	for i := 3; i < len(s); i++ {
+		switch {
+		case s[i] == "--format" || strings.HasPrefix(s[i], "--format="):
+			if s[i] == "--format" {
+				if i+1 >= len(s) {
+					return "", "", fmt.Errorf("%s. format is not provided", usage)
+				}
+				i++
+				format = s[i]
+			} else {
+				format = strings.TrimPrefix(s[i], "--format=")
+			}
+			if format != appctl.ProfileExportFormatURL && format != appctl.ProfileExportFormatQRCode && format != appctl.ProfileExportFormatJSON {
+				return "", "", fmt.Errorf("%s. format %q is not supported", usage, format)
+			}
+		case strings.HasPrefix(s[i], "--") || name != "":
+			return "", "", fmt.Errorf("%s. unexpected argument %q", usage, s[i])
+		default:
+			name = s[i]
+		}
+	}
+	return
+}
+
+// listenUnixSocket listens to the unix domain socket of a local proxy.
+func listenUnixSocket(socket *appctlpb.UnixSocket) (net.Listener, error) {
+	mode, err := common.ParseUnixSocketMode(socket.GetMode())
+	if err != nil {
+		return nil, err
+	}
+	return common.ListenUnixSocket(socket.GetPath(), mode)
 }
@@ -21,6 +21,7 @@ type helpFormatter struct {
 	appName  string
 	entries  []helpCmdEntry
 	advanced []helpCmdEntry
+	options  []helpCmdEntry
 }
 
 type helpCmdEntry struct {
@@ -53,4 +54,14 @@ func (m helpFormatter) print() {
 			log.Infof("")
 		}
 	}
+	if len(m.options) != 0 {
+		log.Infof("Options:")
+		for _, entry := range m.options {
+			log.Infof("  %s", entry.cmd)
+			for _, line := range entry.help {
+				log.Infof("        %s", line)
+			}
+			log.Infof("")
+		}
+	}
 }
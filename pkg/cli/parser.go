@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/enfein/mieru/v3/pkg/appctl"
 )
 
 // binaryName is the name of this program.
@@ -33,6 +35,13 @@ type matchProcessor struct {
 // hooks contains registered callbacks.
 var hooks = make([]matchProcessor, 0)
 
+// verboseFlag is the command line flag that prints the timing of
+// each step of a long running command.
+const verboseFlag = "--verbose"
+
+// verbose is true if the verbose flag is present in the command line.
+var verbose bool
+
 // RegisterCallback registers a CLI parser callback before the CLI arguments are processed.
 //
 // exactMatches is a list of strings that must match os.Args for the callback to be selected.
@@ -53,7 +62,7 @@ func RegisterCallback(exactMatches []string, validator func([]string) error, cal
 // ParseAndExecute runs the command coming from args.
 // This function will wait for the command to finish before return.
 func ParseAndExecute() error {
-	args := os.Args
+	args := stripVerboseFlag(os.Args)
 	found := false
 	for _, hook := range hooks {
 		if !doExactMatch(args, hook.matches) {
@@ -65,7 +74,7 @@ func ParseAndExecute() error {
 		}
 		err := hook.callback(args)
 		if err != nil {
-			return err
+			return withSuggestedAction(err)
 		}
 		break
 	}
@@ -76,6 +85,36 @@ func ParseAndExecute() error {
 	return nil
 }
 
+// withSuggestedAction appends the guidance carried by a management
+// RPC error, if any, to the error message.
+func withSuggestedAction(err error) error {
+	detail := appctl.GetRPCErrorDetail(err)
+	if detail == nil {
+		return err
+	}
+	if detail.GetSuggestedAction() != "" {
+		err = fmt.Errorf("%w\n%s", err, detail.GetSuggestedAction())
+	}
+	if detail.GetRetriable() {
+		err = fmt.Errorf("%w\nThis error may be temporary. Retry the command later.", err)
+	}
+	return err
+}
+
+// stripVerboseFlag removes the verbose flag from args and
+// turns on verbose mode if the flag is found.
+func stripVerboseFlag(args []string) []string {
+	stripped := make([]string, 0, len(args))
+	for i, arg := range args {
+		if i > 0 && arg == verboseFlag {
+			verbose = true
+			continue
+		}
+		stripped = append(stripped, arg)
+	}
+	return stripped
+}
+
 // doExactMatch checks whether `input` has the exact prefix as `want`.
 func doExactMatch(input, want []string) bool {
 	if len(input) < len(want) {
@@ -98,3 +137,14 @@ func unexpectedArgsError(args []string, length int) error {
 	}
 	return nil
 }
+
+// configOverrideFlagsError returns an error if any argument after `length`
+// is not a config override flag in the format --NAME=VALUE.
+func configOverrideFlagsError(args []string, length int) error {
+	for _, arg := range args[length:] {
+		if !strings.HasPrefix(arg, "--") || !strings.Contains(arg, "=") {
+			return fmt.Errorf("unexpected argument %q after %q, config override flag must be in the format --NAME=VALUE", arg, strings.Join(args[:length], " "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// slowStepThreshold is the duration after which a step is reported
+// as in progress even if verbose mode is off.
+const slowStepThreshold = 2 * time.Second
+
+// progressOutput is where the progress is printed.
+// Progress is not printed to stdout, so the command output
+// can still be consumed by other programs.
+var progressOutput io.Writer = os.Stderr
+
+// progress reports the steps of a long running command.
+//
+// In verbose mode, each step and its duration are printed.
+// Otherwise, a step is only printed when it takes longer than
+// slowStepThreshold, so the user knows what the command is waiting for.
+type progress struct {
+	mu        sync.Mutex
+	start     time.Time
+	name      string
+	stepStart time.Time
+	timer     *time.Timer
+	verbose   bool
+	threshold time.Duration
+}
+
+// newProgress returns a progress that uses the verbose flag from command line.
+func newProgress() *progress {
+	return &progress{
+		start:     time.Now(),
+		verbose:   verbose,
+		threshold: slowStepThreshold,
+	}
+}
+
+// step finishes the current step and starts a new step.
+func (p *progress) step(format string, a ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.finishLocked()
+	p.name = fmt.Sprintf(format, a...)
+	p.stepStart = time.Now()
+	if p.verbose {
+		fmt.Fprintf(progressOutput, "[%8.3fs] %s ...\n", time.Since(p.start).Seconds(), p.name)
+		return
+	}
+	name := p.name
+	p.timer = time.AfterFunc(p.threshold, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		fmt.Fprintf(progressOutput, "%s ...\n", name)
+	})
+}
+
+// done finishes the current step.
+func (p *progress) done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.finishLocked()
+	if p.verbose {
+		fmt.Fprintf(progressOutput, "[%8.3fs] completed\n", time.Since(p.start).Seconds())
+	}
+}
+
+func (p *progress) finishLocked() {
+	if p.name == "" {
+		return
+	}
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	if p.verbose {
+		fmt.Fprintf(progressOutput, "[%8.3fs] %s took %v\n", time.Since(p.start).Seconds(), p.name, time.Since(p.stepStart).Round(time.Millisecond))
+	}
+	p.name = ""
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStripVerboseFlag(t *testing.T) {
+	verbose = false
+	defer func() { verbose = false }()
+	got := stripVerboseFlag([]string{"mita", "apply", "config", "--verbose", "server.json"})
+	if strings.Join(got, " ") != "mita apply config server.json" {
+		t.Errorf("stripVerboseFlag() = %v", got)
+	}
+	if !verbose {
+		t.Errorf("verbose mode is not turned on")
+	}
+}
+
+func TestProgressVerbose(t *testing.T) {
+	var buf bytes.Buffer
+	progressOutput = &buf
+	defer func() { progressOutput = os.Stderr }()
+
+	p := newProgress()
+	p.verbose = true
+	p.step("Loading %s", "config")
+	p.step("Sending config")
+	p.done()
+	out := buf.String()
+	for _, want := range []string{"Loading config ...", "Loading config took", "Sending config took", "completed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q doesn't contain %q", out, want)
+		}
+	}
+}
+
+func TestProgressSlowStep(t *testing.T) {
+	var buf bytes.Buffer
+	progressOutput = &buf
+	defer func() { progressOutput = os.Stderr }()
+
+	p := newProgress()
+	p.threshold = 10 * time.Millisecond
+	p.step("Fast step")
+	p.step("Slow step")
+	time.Sleep(100 * time.Millisecond)
+	p.done()
+	p.mu.Lock()
+	out := buf.String()
+	p.mu.Unlock()
+	if out != "Slow step ...\n" {
+		t.Errorf("got output %q, want %q", out, "Slow step ...\n")
+	}
+}
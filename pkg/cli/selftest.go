@@ -0,0 +1,174 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/appctl"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/socks5"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// selfTestTimeout is the maximum duration of the local socks5 listener
+// stage of the connectivity self-test.
+const selfTestTimeout = 5 * time.Second
+
+// selfTestStageError is returned when a stage of the connectivity
+// self-test fails. The following stages are not run.
+type selfTestStageError struct {
+	stage string
+	hint  string
+	err   error
+}
+
+func (e selfTestStageError) Error() string {
+	return fmt.Sprintf("%s failed: %v. %s", e.stage, e.err, e.hint)
+}
+
+func (e selfTestStageError) Unwrap() error {
+	return e.err
+}
+
+// runClientSelfTest checks the connectivity of the running client in
+// stages: the local socks5 listener, the tunnel handshake to each proxy
+// server, and an HTTP request to the destination through the proxy.
+func runClientSelfTest(config *appctlpb.ClientConfig, destination string) error {
+	socks5Addr := net.JoinHostPort(common.LocalIPAddr(), strconv.Itoa(int(config.GetSocks5Port())))
+
+	stage := "[1/3] Local socks5 listener " + socks5Addr
+	if err := checkSocks5Listener(socks5Addr); err != nil {
+		return selfTestStageError{stage: stage, hint: "Check the log of mieru client with `mieru follow logs`.", err: err}
+	}
+	log.Infof("%s: OK", stage)
+
+	stage = "[2/3] Tunnel handshake to proxy servers"
+	servers, err := checkProxyServers()
+	if err != nil {
+		return selfTestStageError{stage: stage, hint: "Check the server address and port, the user name and password of the active profile, the firewall of the server, and if mita server is running.", err: err}
+	}
+	log.Infof("%s: %s", stage, servers)
+
+	stage = "[3/3] Fetch " + destination + " through proxy"
+	d, err := fetchThroughSocks5(config, socks5Addr, destination)
+	if err != nil {
+		return selfTestStageError{stage: stage, hint: "The proxy tunnel works. Check the DNS and the network of the server, and the egress rules of the server.", err: err}
+	}
+	log.Infof("%s: OK after %v", stage, d)
+	return nil
+}
+
+// checkSocks5Listener returns nil if the address accepts a socks5
+// method selection request.
+func checkSocks5Listener(addr string) error {
+	conn, err := net.DialTimeout("tcp", addr, selfTestTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(selfTestTimeout))
+	if _, err := conn.Write([]byte{constant.Socks5Version, 2, constant.Socks5NoAuth, constant.Socks5UserPassAuth}); err != nil {
+		return fmt.Errorf("send socks5 method selection request failed: %w", err)
+	}
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return fmt.Errorf("read socks5 method selection response failed: %w", err)
+	}
+	if resp[0] != constant.Socks5Version {
+		return fmt.Errorf("listener responded with socks version %d, it is not a socks5 server", resp[0])
+	}
+	if resp[1] == constant.Socks5NoAcceptableAuth {
+		return fmt.Errorf("socks5 authentication method is not accepted")
+	}
+	return nil
+}
+
+// checkProxyServers makes a tunnel handshake to each proxy server of the
+// active profile. It returns the number of reachable servers, or an error
+// if no server is reachable. The unreachable servers are printed.
+func checkProxyServers() (string, error) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	client, running, err := newClientManagementRPCClient(ctx)
+	if !running || err != nil {
+		return "", fmt.Errorf("mieru client is not running")
+	}
+	list, err := client.ProbeServers(ctx, &emptypb.Empty{})
+	if err != nil {
+		return "", fmt.Errorf("probe proxy servers failed: %w", err)
+	}
+	items := list.GetItems()
+	if len(items) == 0 {
+		return "", fmt.Errorf("active profile has no proxy server")
+	}
+	reachable := 0
+	var lastErr string
+	for _, item := range items {
+		server := common.MaybeDecorateIPv6(item.GetIpAddress()) + ":" + strconv.Itoa(int(item.GetPort())) + "/" + item.GetProtocol().String()
+		if item.Error != nil {
+			lastErr = fmt.Sprintf("%s: %s", server, item.GetError())
+			log.Infof("    %s", lastErr)
+			continue
+		}
+		reachable++
+		log.Infof("    %s: OK after %v", server, (time.Duration(item.GetRttMicros()) * time.Microsecond).Round(time.Millisecond))
+	}
+	if reachable == 0 {
+		return "", fmt.Errorf("no proxy server is reachable, last error is %s", lastErr)
+	}
+	return fmt.Sprintf("%d of %d reachable", reachable, len(items)), nil
+}
+
+// fetchThroughSocks5 sends an HTTP GET request to the destination through
+// the local socks5 listener, and returns the duration of the request.
+func fetchThroughSocks5(config *appctlpb.ClientConfig, socks5Addr, destination string) (time.Duration, error) {
+	proxyURL := &url.URL{Scheme: "socks5", Host: socks5Addr}
+	if auth := config.GetSocks5Authentication(); len(auth) > 0 {
+		proxyURL.User = url.UserPassword(auth[0].GetUser(), auth[0].GetPassword())
+	}
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			Dial: socks5.Dial(proxyURL.String(), constant.Socks5ConnectCmd),
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return nil
+		},
+		Timeout: appctl.RPCTimeout,
+	}
+	beginTime := time.Now()
+	resp, err := httpClient.Get(destination)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.ReadAll(resp.Body)
+	d := time.Since(beginTime).Round(time.Millisecond)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("received unexpected status code %d after %v", resp.StatusCode, d)
+	}
+	return d, nil
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"io"
+	"net"
+	"testing"
+)
+
+func TestCheckSocks5Listener(t *testing.T) {
+	testCases := []struct {
+		name     string
+		response []byte
+		wantErr  bool
+	}{
+		{"no authentication", []byte{5, 0}, false},
+		{"user password authentication", []byte{5, 2}, false},
+		{"no acceptable method", []byte{5, 255}, true},
+		{"not socks5", []byte("HTTP/1.1 400 Bad Request\r\n"), true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("net.Listen() failed: %v", err)
+			}
+			defer l.Close()
+			go func() {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				io.ReadFull(conn, make([]byte, 4))
+				conn.Write(tc.response)
+			}()
+			if err := checkSocks5Listener(l.Addr().String()); (err != nil) != tc.wantErr {
+				t.Errorf("checkSocks5Listener() = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	if err := checkSocks5Listener(addr); err == nil {
+		t.Errorf("checkSocks5Listener() succeeded when the port is closed")
+	}
+}
@@ -18,9 +18,11 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/user"
+	"path/filepath"
 	"runtime/pprof"
 	"strconv"
 	"strings"
@@ -29,16 +31,23 @@ import (
 	"time"
 
 	"github.com/enfein/mieru/v3/pkg/appctl"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlgrpc"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/blocklist"
 	"github.com/enfein/mieru/v3/pkg/cipher"
 	"github.com/enfein/mieru/v3/pkg/common"
 	"github.com/enfein/mieru/v3/pkg/log"
 	"github.com/enfein/mieru/v3/pkg/metrics"
 	"github.com/enfein/mieru/v3/pkg/protocol"
+	"github.com/enfein/mieru/v3/pkg/replay"
+	"github.com/enfein/mieru/v3/pkg/service"
 	"github.com/enfein/mieru/v3/pkg/socks5"
 	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/systemd"
+	"github.com/enfein/mieru/v3/pkg/tracing"
 	"github.com/enfein/mieru/v3/pkg/version/updater"
+	"github.com/enfein/mieru/v3/pkg/version/updater/updaterpb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/protobuf/proto"
@@ -65,6 +74,9 @@ func RegisterServerCommands() {
 	RegisterCallback(
 		[]string{"", "run"},
 		func(s []string) error {
+			if len(s) == 3 && s[2] == "--supervise" {
+				return nil
+			}
 			return unexpectedArgsError(s, 2)
 		},
 		serverRunFunc,
@@ -90,6 +102,27 @@ func RegisterServerCommands() {
 		},
 		serverStatusFunc,
 	)
+	RegisterCallback(
+		[]string{"", "service", "install"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverServiceInstallFunc,
+	)
+	RegisterCallback(
+		[]string{"", "service", "uninstall"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverServiceUninstallFunc,
+	)
+	RegisterCallback(
+		[]string{"", "service", "status"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverServiceStatusFunc,
+	)
 	RegisterCallback(
 		[]string{"", "apply", "config"},
 		func(s []string) error {
@@ -102,6 +135,16 @@ func RegisterServerCommands() {
 		},
 		serverApplyConfigFunc,
 	)
+	RegisterCallback(
+		[]string{"", "verify", "config"},
+		func(s []string) error {
+			if len(s) != 4 {
+				return fmt.Errorf("usage: mita verify config <FILE>")
+			}
+			return nil
+		},
+		serverVerifyConfigFunc,
+	)
 	RegisterCallback(
 		[]string{"", "describe", "config"},
 		func(s []string) error {
@@ -109,6 +152,26 @@ func RegisterServerCommands() {
 		},
 		serverDescribeConfigFunc,
 	)
+	RegisterCallback(
+		[]string{"", "add", "user"},
+		func(s []string) error {
+			if len(s) != 5 {
+				return fmt.Errorf("usage: mita add user <USER_NAME> <PASSWORD>")
+			}
+			return nil
+		},
+		serverAddUserFunc,
+	)
+	RegisterCallback(
+		[]string{"", "update", "user"},
+		func(s []string) error {
+			if len(s) != 5 {
+				return fmt.Errorf("usage: mita update user <USER_NAME> <PASSWORD>")
+			}
+			return nil
+		},
+		serverUpdateUserFunc,
+	)
 	RegisterCallback(
 		[]string{"", "delete", "user"},
 		func(s []string) error {
@@ -119,6 +182,16 @@ func RegisterServerCommands() {
 		},
 		serverDeleteUserFunc,
 	)
+	RegisterCallback(
+		[]string{"", "delete", "group"},
+		func(s []string) error {
+			if len(s) < 4 {
+				return fmt.Errorf("usage: mita delete group <GROUP_NAME>. no user group is provided")
+			}
+			return nil
+		},
+		serverDeleteUserGroupFunc,
+	)
 	RegisterCallback(
 		[]string{"", "version"},
 		func(s []string) error {
@@ -134,10 +207,18 @@ func RegisterServerCommands() {
 		describeBuildFunc,
 	)
 	RegisterCallback(
-		[]string{"", "check", "update"},
+		[]string{"", "describe", "ciphers"},
 		func(s []string) error {
 			return unexpectedArgsError(s, 3)
 		},
+		describeCiphersFunc,
+	)
+	RegisterCallback(
+		[]string{"", "check", "update"},
+		func(s []string) error {
+			_, err := parseUpdateChannelArgs(s, 3, "")
+			return err
+		},
 		serverCheckUpdateFunc,
 	)
 	RegisterCallback(
@@ -154,6 +235,26 @@ func RegisterServerCommands() {
 		},
 		serverGetConnectionsFunc,
 	)
+	RegisterCallback(
+		[]string{"", "get", "sessions"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverGetSessionsFunc,
+	)
+	RegisterCallback(
+		[]string{"", "kill", "session"},
+		func(s []string) error {
+			if len(s) != 4 {
+				return fmt.Errorf("usage: mita kill session <SESSION_ID>")
+			}
+			if _, err := strconv.ParseUint(s[3], 10, 32); err != nil {
+				return fmt.Errorf("session ID %q is invalid", s[3])
+			}
+			return nil
+		},
+		serverKillSessionFunc,
+	)
 	RegisterCallback(
 		[]string{"", "get", "users"},
 		func(s []string) error {
@@ -161,6 +262,13 @@ func RegisterServerCommands() {
 		},
 		serverGetUsersFunc,
 	)
+	RegisterCallback(
+		[]string{"", "get", "groups"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverGetUserGroupsFunc,
+	)
 	RegisterCallback(
 		[]string{"", "get", "quotas"},
 		func(s []string) error {
@@ -168,6 +276,85 @@ func RegisterServerCommands() {
 		},
 		serverGetQuotasFunc,
 	)
+	RegisterCallback(
+		[]string{"", "get", "country-traffic"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverGetCountryTrafficFunc,
+	)
+	RegisterCallback(
+		[]string{"", "get", "geo-databases"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverGetGeoDatabasesFunc,
+	)
+	RegisterCallback(
+		[]string{"", "update", "geo-databases"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverUpdateGeoDatabasesFunc,
+	)
+	RegisterCallback(
+		[]string{"", "trust", "list"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		trustListFunc(appctl.ServerTrustStore),
+	)
+	RegisterCallback(
+		[]string{"", "trust", "add"},
+		func(s []string) error {
+			if len(s) < 4 {
+				return fmt.Errorf("usage: mita trust add <PUBLIC_KEY_OR_FILE>. public key is not provided")
+			}
+			return unexpectedArgsError(s, 4)
+		},
+		trustAddFunc(appctl.ServerTrustStore),
+	)
+	RegisterCallback(
+		[]string{"", "trust", "remove"},
+		func(s []string) error {
+			if len(s) < 4 {
+				return fmt.Errorf("usage: mita trust remove <KEY_ID>. key ID is not provided")
+			}
+			return unexpectedArgsError(s, 4)
+		},
+		trustRemoveFunc(appctl.ServerTrustStore),
+	)
+	// This must be registered after the other update commands.
+	RegisterCallback(
+		[]string{"", "update"},
+		func(s []string) error {
+			_, _, err := parseSelfUpdateArgs(s, 2, "")
+			return err
+		},
+		serverSelfUpdateFunc,
+	)
+	RegisterCallback(
+		[]string{"", "get", "port-bindings"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverGetPortBindingsFunc,
+	)
+	RegisterCallback(
+		[]string{"", "rotate", "keys"},
+		func(s []string) error {
+			return unexpectedArgsError(s, 3)
+		},
+		serverRotateKeysFunc,
+	)
+	RegisterCallback(
+		[]string{"", "follow", "logs"},
+		func(s []string) error {
+			_, err := followLogsLevel(s)
+			return err
+		},
+		serverFollowLogsFunc,
+	)
 	RegisterCallback(
 		[]string{"", "get", "thread-dump"},
 		func(s []string) error {
@@ -240,19 +427,41 @@ var serverHelpFunc = func(s []string) error {
 				help: []string{"Check mita server proxy service status."},
 			},
 			{
-				cmd: "apply config <JSON_FILE>",
+				cmd: "apply config <FILE>",
 				help: []string{
-					"Apply server configuration patch from a file.",
+					"Apply server configuration patch from a JSON, YAML or TOML file.",
 					"It merges the patch with existing server configuration.",
 				},
 			},
+			{
+				cmd: "verify config <FILE>",
+				help: []string{
+					"Validate server configuration patch from a file without applying it.",
+					"It prints the likely mistakes and the changes to existing server configuration.",
+				},
+			},
 			{
 				cmd:  "describe config",
 				help: []string{"Show current server configuration."},
 			},
+			{
+				cmd: "add user <USER_NAME> <PASSWORD>",
+				help: []string{
+					"Add a user to the running server and server configuration.",
+					"The user can connect without reloading the server.",
+				},
+			},
+			{
+				cmd:  "update user <USER_NAME> <PASSWORD>",
+				help: []string{"Change the password of a user in the running server and server configuration."},
+			},
 			{
 				cmd:  "delete user <USER_NAME>",
-				help: []string{"Delete a user from server configuration."},
+				help: []string{"Delete a user from the running server and server configuration."},
+			},
+			{
+				cmd:  "delete group <GROUP_NAME>",
+				help: []string{"Delete a user group from server configuration. Users in the group are not deleted."},
 			},
 			{
 				cmd:  "get metrics",
@@ -262,35 +471,137 @@ var serverHelpFunc = func(s []string) error {
 				cmd:  "get connections",
 				help: []string{"Get mita server connections."},
 			},
+			{
+				cmd:  "get sessions",
+				help: []string{"Get active sessions of mita server, including user, client address, traffic and destination."},
+			},
+			{
+				cmd:  "kill session <SESSION_ID>",
+				help: []string{"Close an active session of mita server."},
+			},
 			{
 				cmd:  "get users",
 				help: []string{"Get mita server registered users."},
 			},
+			{
+				cmd:  "get groups",
+				help: []string{"Get mita server user groups."},
+			},
 			{
 				cmd:  "get quotas",
 				help: []string{"Get mita server user quotas."},
 			},
+			{
+				cmd: "get country-traffic",
+				help: []string{
+					"Get mita server egress traffic of each destination country in the last 7 days.",
+					"It requires countryTraffic in server configuration.",
+				},
+			},
+			{
+				cmd: "get geo-databases",
+				help: []string{
+					"Get the version of GeoIP and geosite databases used by egress rules.",
+					"It requires geoDatabases in server configuration.",
+				},
+			},
+			{
+				cmd: "update geo-databases",
+				help: []string{
+					"Download GeoIP and geosite databases used by egress rules.",
+					"Run \"mita reload\" to apply the new databases.",
+				},
+			},
+			{
+				cmd:  "get port-bindings",
+				help: []string{"Get mita server port bindings and their listening status."},
+			},
+			{
+				cmd: "rotate keys",
+				help: []string{
+					"Stop accepting keys of the previous key epochs immediately.",
+					"It requires keyRotation in server configuration.",
+				},
+			},
 			{
 				cmd:  "version",
 				help: []string{"Show mita server version."},
 			},
 			{
-				cmd:  "check update",
+				cmd:  "check update [--channel stable|beta]",
 				help: []string{"Check mita server update."},
 			},
+			{
+				cmd: "update [--channel stable|beta] [--insecure-skip-signature]",
+				help: []string{
+					"Download and install the latest mita server release.",
+					"The release is verified with the SHA-256 checksum and the signature of a trusted public key before the binary is replaced.",
+					"If no public key is pinned in the binary or trusted, the release is not installed, unless --insecure-skip-signature is set.",
+					"Run \"systemctl restart mita\" to use the new version.",
+				},
+			},
 		},
 		advanced: []helpCmdEntry{
 			{
-				cmd: "run",
+				cmd: "run [--supervise]",
 				help: []string{
 					"Run mita server in foreground.",
 					"Use environment variable MITA_CONFIG_JSON_FILE to load configuration.",
+					"With --supervise, restart mita server after a crash. The delay before a restart doubles after each crash, up to 1 minute.",
+				},
+			},
+			{
+				cmd: "service install",
+				help: []string{
+					"Install mita server as a service of the operating system, and start it.",
+					"It uses systemd in Linux, launchd in macOS, and the service control manager in Windows.",
+					"The service starts with the system and restarts after a crash.",
 				},
 			},
+			{
+				cmd:  "service uninstall",
+				help: []string{"Stop and remove the service installed by \"mita service install\"."},
+			},
+			{
+				cmd:  "service status",
+				help: []string{"Show if mita service is installed and running."},
+			},
 			{
 				cmd:  "describe build",
 				help: []string{"Show mita build info."},
 			},
+			{
+				cmd: "describe ciphers",
+				help: []string{
+					"Measure the throughput of each cipher suite on this device.",
+					"The cipher suite used by AUTO_CIPHER_SUITE is marked with \"*\".",
+				},
+			},
+			{
+				cmd:  "trust list",
+				help: []string{"List the minisign public keys trusted to verify signatures of releases and geo databases."},
+			},
+			{
+				cmd: "trust add <PUBLIC_KEY_OR_FILE>",
+				help: []string{
+					"Trust a minisign public key, or the public key in a file.",
+					"A revoked pinned public key is trusted again.",
+				},
+			},
+			{
+				cmd: "trust remove <KEY_ID>",
+				help: []string{
+					"Stop trusting the minisign public key with the key ID.",
+					"A pinned public key shipped in the binary is revoked.",
+				},
+			},
+			{
+				cmd: "follow logs [LEVEL]",
+				help: []string{
+					"Print live logs of mita server until interrupted.",
+					"Only logs at LEVEL or more severe are printed. The default level is INFO.",
+				},
+			},
 			{
 				cmd:  "get thread-dump",
 				help: []string{"Get mita server thread dump."},
@@ -312,12 +623,25 @@ var serverHelpFunc = func(s []string) error {
 				help: []string{"Stop mita server CPU profile."},
 			},
 		},
+		options: []helpCmdEntry{
+			{
+				cmd: "--verbose",
+				help: []string{
+					"Print each step of a long running command and the time it takes.",
+					"Without this option, a step is only printed when it takes more than 2 seconds.",
+				},
+			},
+		},
 	}
 	helpFmt.print()
 	return nil
 }
 
 var serverStartFunc = func(s []string) error {
+	p := newProgress()
+	defer p.done()
+
+	p.step("Getting server status")
 	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
 	if err != nil {
 		if stderror.IsConnRefused(err) {
@@ -334,6 +658,7 @@ var serverStartFunc = func(s []string) error {
 	}
 
 	// Start server proxy.
+	p.step("Starting server proxy")
 	client, err := appctl.NewServerManagementRPCClient()
 	if err != nil {
 		return fmt.Errorf(stderror.CreateServerManagementRPCClientFailedErr, err)
@@ -349,11 +674,16 @@ var serverStartFunc = func(s []string) error {
 }
 
 var serverRunFunc = func(s []string) error {
-	if _, found := os.LookupEnv("MITA_LOG_NO_TIMESTAMP"); found {
-		log.SetFormatter(&log.DaemonFormatter{NoTimestamp: true})
-	} else {
-		log.SetFormatter(&log.DaemonFormatter{})
+	if len(s) == 3 && s[2] == "--supervise" {
+		return superviseServer(s)
 	}
+	return service.Run(serverServiceName, func() error {
+		return serverRunDaemonFunc(s)
+	}, stopServerDaemon)
+}
+
+var serverRunDaemonFunc = func(s []string) error {
+	appctl.SetServerLogFormat(appctlpb.LogFormat_LOG_FORMAT_TEXT)
 
 	appctl.SetAppStatus(appctlpb.AppStatus_IDLE)
 
@@ -363,26 +693,32 @@ var serverRunFunc = func(s []string) error {
 	// Run the RPC server in the background.
 	go func() {
 		rpcAddr := appctl.ServerUDS()
-		if err := syscall.Unlink(rpcAddr); err != nil {
-			// Unlink() fails when the file path doesn't exist, which is not a big problem.
-			log.Debugf("syscall.Unlink(%q) failed: %v", rpcAddr, err)
-		}
-		rpcListener, err := net.Listen("unix", rpcAddr)
-		if err != nil {
-			log.Fatalf("listen on RPC address %q failed: %v", rpcAddr, err)
-		}
-		if _, found := os.LookupEnv("MITA_INSECURE_UDS"); !found {
-			if err = updateServerUDSPermission(); err != nil {
-				log.Fatalf("update server unix domain socket permission failed: %v", err)
+		// With socket activation, systemd creates the socket file
+		// and sets its permission.
+		rpcListener := systemd.TakeListener("unix", rpcAddr)
+		if rpcListener == nil {
+			if err := syscall.Unlink(rpcAddr); err != nil {
+				// Unlink() fails when the file path doesn't exist, which is not a big problem.
+				log.Debugf("syscall.Unlink(%q) failed: %v", rpcAddr, err)
+			}
+			var err error
+			rpcListener, err = net.Listen("unix", rpcAddr)
+			if err != nil {
+				log.Fatalf("listen on RPC address %q failed: %v", rpcAddr, err)
+			}
+			if _, found := os.LookupEnv("MITA_INSECURE_UDS"); !found {
+				if err = updateServerUDSPermission(); err != nil {
+					log.Fatalf("update server unix domain socket permission failed: %v", err)
+				}
 			}
 		}
-		grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(appctl.MaxRecvMsgSize))
+		grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(appctl.MaxRecvMsgSize), grpc.UnaryInterceptor(appctl.RPCErrorInterceptor))
 		appctl.SetServerRPCServerRef(grpcServer)
 		appctlgrpc.RegisterServerManagementServiceServer(grpcServer, appctl.NewServerManagementService())
 		reflection.Register(grpcServer)
 		close(appctl.ServerRPCServerStarted)
 		log.Infof("mita server daemon RPC server is running")
-		if err = grpcServer.Serve(rpcListener); err != nil {
+		if err := grpcServer.Serve(rpcListener); err != nil {
 			log.Fatalf("run gRPC server failed: %v", err)
 		}
 		log.Infof("mita server daemon RPC server is stopped")
@@ -408,15 +744,28 @@ var serverRunFunc = func(s []string) error {
 		}
 	}
 
-	// Set logging level based on server config.
+	// Set logging level and format based on server config.
 	loggingLevel := config.GetLoggingLevel().String()
 	if loggingLevel != appctlpb.LoggingLevel_DEFAULT.String() {
 		log.SetLevel(loggingLevel)
 	}
+	appctl.SetServerLogFormat(config.GetLogFormat())
+	if err := appctl.SetServerLogFile(config.GetLogFile()); err != nil {
+		return err
+	}
+
+	// Serve the management RPC to remote administrators and
+	// the HTTP JSON API if enabled.
+	if err := appctl.StartServerRemoteManagement(config.GetRemoteManagement()); err != nil {
+		return err
+	}
+	if err := appctl.StartServerManagementGateway(config.GetManagementGateway()); err != nil {
+		return err
+	}
 
 	// Load previous metrics if possible.
-	if err := os.MkdirAll("/var/lib/mita", 0775); err == nil {
-		const metricsDumpPath = "/var/lib/mita/metrics.pb"
+	if err := os.MkdirAll(appctl.ServerDataDir, 0775); err == nil {
+		metricsDumpPath := filepath.Join(appctl.ServerDataDir, "metrics.pb")
 		metrics.SetMetricsDumpFilePath(metricsDumpPath)
 		if err := metrics.LoadMetricsFromDump(); err == nil {
 			log.Infof("Loaded previous metrics from %s", metricsDumpPath)
@@ -426,6 +775,20 @@ var serverRunFunc = func(s []string) error {
 		if err := metrics.EnableMetricsDump(); err != nil {
 			log.Warnf("Failed to enable metrics dump: %v", err)
 		}
+
+		// Load replay cache, so handshakes captured before restart can't be replayed.
+		if interval := appctl.ReplayCacheDumpInterval(config.GetAdvancedSettings().GetReplayCache()); interval > 0 {
+			replayDumpPath := filepath.Join(appctl.ServerDataDir, "replay.pb")
+			replay.SetDumpFilePath(replayDumpPath)
+			if err := replay.LoadFromDump(); err == nil {
+				log.Infof("Loaded previous replay cache from %s", replayDumpPath)
+			} else {
+				log.Infof("Unable to load previous replay cache: %v", err)
+			}
+			if err := replay.EnableDump(interval); err != nil {
+				log.Warnf("Failed to enable replay cache dump: %v", err)
+			}
+		}
 	}
 
 	// Disable client side metrics.
@@ -441,21 +804,64 @@ var serverRunFunc = func(s []string) error {
 		log.Infof("TCP congestion control algorithm is %q", algo)
 	}
 
+	// Run the daily maintenance window.
+	maintenance := appctl.NewServerMaintenance(config)
+	appctl.SetServerMaintenanceRef(maintenance)
+	maintenance.Start()
+	defer maintenance.Close()
+
 	// Start proxy if server config is valid.
 	if err = appctl.ValidateFullServerConfig(config); err == nil {
 		appctl.SetAppStatus(appctlpb.AppStatus_STARTING)
 
-		mux := protocol.NewMux(false).SetServerUsers(appctl.UserListToMap(config.GetUsers()))
+		if err := appctl.ApplyReplayCacheConfig(config.GetAdvancedSettings().GetReplayCache()); err != nil {
+			return err
+		}
+		mux := protocol.NewMux(false).
+			SetServerUsers(appctl.UserListToMap(config.GetUsers())).
+			SetServerUserGroups(appctl.UserGroupsByUserName(config.GetUserGroups())).
+			SetServerOverloadLimits(appctlcommon.OverloadLimitsFromConfig(config.GetAdvancedSettings())).
+			SetServerAcceptLimit(appctlcommon.AcceptLimitFromConfig(config.GetAdvancedSettings().GetAcceptLimit())).
+			SetKeyRotation(appctlcommon.KeyRotationFromConfig(config.GetKeyRotation()))
 		appctl.SetServerMuxRef(mux)
 		mtu := common.DefaultMTU
 		if config.GetMtu() != 0 {
 			mtu = int(config.GetMtu())
 		}
-		endpoints, err := appctl.PortBindingsToUnderlayProperties(config.GetPortBindings(), mtu)
+		endpoints, err := appctl.PortBindingsToUnderlayProperties(config.GetPortBindings(), mtu, config.GetWebSocket(), config.GetTlsCamouflage(), config.GetDecoy(), config.GetProxyProtocol())
+		if err != nil {
+			return err
+		}
+		endpoints, err = appctl.ServerSIP003Endpoints(config, endpoints)
 		if err != nil {
 			return err
 		}
+		defer appctl.CloseServerSIP003Plugins()
+		if err := mux.SetServerPortKnocking(appctlcommon.PortKnockingOptionsFromConfig(config.GetPortKnocking())); err != nil {
+			return err
+		}
 		mux.SetEndpoints(endpoints)
+		udpRelay, err := appctl.UDPRelayToSocks5(config.GetUdpRelay())
+		if err != nil {
+			return err
+		}
+		countryStats, geoIP, err := appctl.NewServerCountryStats(config.GetCountryTraffic())
+		if err != nil {
+			return err
+		}
+		appctl.SetServerCountryStatsRef(countryStats)
+		egressGeoIP, egressGeosite, err := appctlcommon.LoadGeoDatabases(config.GetGeoDatabases())
+		if err != nil {
+			return err
+		}
+		serverBlocklist := blocklist.New()
+		if err := appctl.UpdateServerBlocklist(serverBlocklist, config.GetBlocklist()); err != nil {
+			return err
+		}
+		auditLog, err := appctl.NewServerAuditLog(config.GetAuditLog())
+		if err != nil {
+			return err
+		}
 
 		// Create the egress socks5 server.
 		socks5Config := &socks5.Config{
@@ -464,14 +870,26 @@ var serverRunFunc = func(s []string) error {
 			},
 			DualStackPreference: common.DualStackPreference(config.GetDns().GetDualStack()),
 			Egress:              config.GetEgress(),
+			EnableTestEndpoints: config.GetAdvancedSettings().GetEnableTestEndpoints(),
 			HandshakeTimeout:    10 * time.Second,
 			Users:               appctl.UserListToMap(config.GetUsers()),
+			UserGroups:          appctl.UserGroupsByUserName(config.GetUserGroups()),
+			UDPRelay:            udpRelay,
+			CountryStats:        countryStats,
+			GeoIP:               geoIP,
+			EgressGeoIP:         egressGeoIP,
+			EgressGeosite:       egressGeosite,
+			Blocklist:           serverBlocklist,
+			AuditLog:            auditLog,
 		}
 		socks5Server, err := socks5.New(socks5Config)
 		if err != nil {
 			return fmt.Errorf(stderror.CreateSocks5ServerFailedErr, err)
 		}
 		appctl.SetSocks5Server(socks5Server)
+		appctl.SetServerBlocklistRef(serverBlocklist)
+		appctl.SetServerAuditLogRef(auditLog)
+		serverBlocklist.Start()
 
 		// Run the egress socks5 server in the background.
 		var proxyTasks sync.WaitGroup
@@ -493,6 +911,16 @@ var serverRunFunc = func(s []string) error {
 		}()
 
 		initProxyTasks.Wait()
+		for _, addr := range systemd.Unused() {
+			log.Warnf("socket %s %v from systemd is not used by any port binding", addr.Network(), addr)
+		}
+
+		if err := appctl.DropServerPrivileges(config.GetDropPrivileges(), mux); err != nil {
+			return err
+		}
+		if err := appctl.SandboxServer(config); err != nil {
+			return err
+		}
 
 		if config.GetAdvancedSettings().GetMetricsLoggingInterval() != "" {
 			metricsDuration, err := time.ParseDuration(config.GetAdvancedSettings().GetMetricsLoggingInterval())
@@ -506,6 +934,17 @@ var serverRunFunc = func(s []string) error {
 		}
 		metrics.EnableLogging()
 
+		if config.GetAdvancedSettings().GetOtlpTraceEndpoint() != "" {
+			exporter, err := tracing.NewExporter(config.GetAdvancedSettings().GetOtlpTraceEndpoint(), "mita")
+			if err != nil {
+				log.Warnf("Failed to create OpenTelemetry trace exporter: %v", err)
+			} else {
+				tracing.SetExporter(exporter)
+			}
+		}
+
+		reloadOnSIGHUP(appctl.ReloadServerConfig)
+
 		appctl.SetAppStatus(appctlpb.AppStatus_RUNNING)
 		log.Debugf("Started proxy after %v", appctl.Elapsed())
 		proxyTasks.Wait()
@@ -517,6 +956,12 @@ var serverRunFunc = func(s []string) error {
 
 	rpcTasks.Wait()
 
+	// Save replay cache, if replay cache dump is enabled.
+	replay.DisableDump()
+	if err := replay.DumpNow(); err != nil {
+		log.Debugf("Replay cache DumpNow() failed: %v", err)
+	}
+
 	// Stop CPU profiling, if previously started.
 	pprof.StopCPUProfile()
 
@@ -606,6 +1051,10 @@ var serverStatusFunc = func(s []string) error {
 }
 
 var serverApplyConfigFunc = func(s []string) error {
+	p := newProgress()
+	defer p.done()
+
+	p.step("Getting server status")
 	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
 	if err != nil {
 		if stderror.IsConnRefused(err) {
@@ -618,18 +1067,20 @@ var serverApplyConfigFunc = func(s []string) error {
 	}
 
 	path := s[3]
+	p.step("Validating %s", path)
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("os.ReadFile(%q) failed: %w", path, err)
 	}
 	patch := &appctlpb.ServerConfig{}
-	if err = common.UnmarshalJSON(b, patch); err != nil {
-		return fmt.Errorf("common.UnmarshalJSON() failed: %w", err)
+	if err = common.UnmarshalConfig(path, b, patch); err != nil {
+		return fmt.Errorf("common.UnmarshalConfig() failed: %w", err)
 	}
 	if err := appctl.ValidateServerConfigPatch(patch); err != nil {
 		return fmt.Errorf(stderror.ValidateServerConfigPatchFailedErr, err)
 	}
 
+	p.step("Sending config to mita daemon")
 	client, err := appctl.NewServerManagementRPCClient()
 	if err != nil {
 		return fmt.Errorf(stderror.CreateServerManagementRPCClientFailedErr, err)
@@ -643,6 +1094,15 @@ var serverApplyConfigFunc = func(s []string) error {
 	return nil
 }
 
+var serverVerifyConfigFunc = func(s []string) error {
+	v, err := appctl.VerifyJSONServerConfig(s[3])
+	if err != nil {
+		return fmt.Errorf(stderror.VerifyConfigFailedErr, err)
+	}
+	printConfigVerification(v)
+	return nil
+}
+
 var serverDescribeConfigFunc = func(s []string) error {
 	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
 	if err != nil {
@@ -673,11 +1133,91 @@ var serverDescribeConfigFunc = func(s []string) error {
 	return nil
 }
 
-var serverDeleteUserFunc = func(s []string) error {
-	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
+var serverAddUserFunc = func(s []string) error {
+	client, err := newRunningServerManagementRPCClient()
 	if err != nil {
-		if stderror.IsConnRefused(err) {
-			return fmt.Errorf(stderror.ServerNotRunningWithCommand)
+		return err
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	user := &appctlpb.User{
+		Name:     proto.String(s[3]),
+		Password: proto.String(s[4]),
+	}
+	if _, err = client.AddUser(timedctx, user); err != nil {
+		return fmt.Errorf(stderror.AddUserFailedErr, err)
+	}
+	return nil
+}
+
+var serverUpdateUserFunc = func(s []string) error {
+	client, err := newRunningServerManagementRPCClient()
+	if err != nil {
+		return err
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	config, err := client.GetConfig(timedctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.GetServerConfigFailedErr, err)
+	}
+	var user *appctlpb.User
+	for _, u := range config.GetUsers() {
+		if u.GetName() == s[3] {
+			user = u
+			break
+		}
+	}
+	if user == nil {
+		return fmt.Errorf(stderror.UpdateUserFailedErr, fmt.Errorf("user %q is not found", s[3]))
+	}
+	// Keep the other settings of the user.
+	user.Password = proto.String(s[4])
+	user.HashedPassword = nil
+	if _, err = client.UpdateUser(timedctx, user); err != nil {
+		return fmt.Errorf(stderror.UpdateUserFailedErr, err)
+	}
+	return nil
+}
+
+var serverDeleteUserFunc = func(s []string) error {
+	client, err := newRunningServerManagementRPCClient()
+	if err != nil {
+		return err
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	if _, err = client.DeleteUsers(timedctx, &appctlpb.UserNameList{Names: s[3:]}); err != nil {
+		return fmt.Errorf(stderror.DeleteUsersFailedErr, err)
+	}
+	return nil
+}
+
+// newRunningServerManagementRPCClient returns a server management RPC client
+// if mita server daemon is running.
+func newRunningServerManagementRPCClient() (appctlgrpc.ServerManagementServiceClient, error) {
+	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
+	if err != nil {
+		if stderror.IsConnRefused(err) {
+			return nil, fmt.Errorf(stderror.ServerNotRunningWithCommand)
+		}
+		return nil, fmt.Errorf(stderror.GetServerStatusFailedErr, err)
+	}
+	if err := appctl.IsServerDaemonRunning(appStatus); err != nil {
+		return nil, fmt.Errorf(stderror.ServerNotRunningErr, err)
+	}
+	client, err := appctl.NewServerManagementRPCClient()
+	if err != nil {
+		return nil, fmt.Errorf(stderror.CreateServerManagementRPCClientFailedErr, err)
+	}
+	return client, nil
+}
+
+var serverDeleteUserGroupFunc = func(s []string) error {
+	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
+	if err != nil {
+		if stderror.IsConnRefused(err) {
+			return fmt.Errorf(stderror.ServerNotRunningWithCommand)
 		}
 		return fmt.Errorf(stderror.GetServerStatusFailedErr, err)
 	}
@@ -695,21 +1235,20 @@ var serverDeleteUserFunc = func(s []string) error {
 	if err != nil {
 		return fmt.Errorf(stderror.GetServerConfigFailedErr, err)
 	}
-	users := config.GetUsers()
-	remaining := make([]*appctlpb.User, 0)
-	for _, user := range users {
+	remaining := make([]*appctlpb.UserGroup, 0)
+	for _, group := range config.GetUserGroups() {
 		shouldDelete := false
 		for _, toDelete := range s[3:] {
-			if user.GetName() == toDelete {
+			if group.GetName() == toDelete {
 				shouldDelete = true
 				break
 			}
 		}
 		if !shouldDelete {
-			remaining = append(remaining, user)
+			remaining = append(remaining, group)
 		}
 	}
-	config.Users = remaining
+	config.UserGroups = remaining
 	_, err = client.SetConfig(timedctx, config)
 	if err != nil {
 		return fmt.Errorf(stderror.SetServerConfigFailedErr, err)
@@ -718,11 +1257,62 @@ var serverDeleteUserFunc = func(s []string) error {
 }
 
 var serverCheckUpdateFunc = func(s []string) error {
-	_, msg, err := updater.CheckUpdate("")
+	p := newProgress()
+	defer p.done()
+
+	channel, err := parseUpdateChannelArgs(s, 3, updater.ChannelStable)
+	if err != nil {
+		return err
+	}
+	p.step("Querying latest release from GitHub")
+	historyFile, _ := appctl.ServerUpdaterHistoryPath()
+	record, msg, err := runUpdater(historyFile, func(h *updater.History) (*updaterpb.UpdateRecord, string, error) {
+		return updater.CheckUpdate(updater.CheckUpdateOptions{
+			App:     "mita",
+			Channel: channel,
+			History: h,
+		})
+	})
 	if err != nil {
 		return fmt.Errorf("check update failed: %w", err)
 	}
 	log.Infof("%s", msg)
+	printNewRelease(record)
+	return nil
+}
+
+var serverSelfUpdateFunc = func(s []string) error {
+	p := newProgress()
+	defer p.done()
+
+	channel, skipSignature, err := parseSelfUpdateArgs(s, 2, updater.ChannelStable)
+	if err != nil {
+		return err
+	}
+	p.step("Downloading latest release from GitHub")
+	trust, err := appctl.ServerTrustStore()
+	if err != nil {
+		return fmt.Errorf("appctl.ServerTrustStore() failed: %w", err)
+	}
+	historyFile, _ := appctl.ServerUpdaterHistoryPath()
+	record, msg, err := runUpdater(historyFile, func(h *updater.History) (*updaterpb.UpdateRecord, string, error) {
+		return updater.SelfUpdate(updater.SelfUpdateOptions{
+			CheckUpdateOptions: updater.CheckUpdateOptions{
+				App:     "mita",
+				Channel: channel,
+				History: h,
+			},
+			TrustStore:            trust,
+			InsecureSkipSignature: skipSignature,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("update failed: %w", selfUpdateError(err))
+	}
+	if record.GetInstalledVersion() != "" {
+		msg += "; run \"systemctl restart mita\" to use the new version"
+	}
+	log.Infof("%s", msg)
 	return nil
 }
 
@@ -778,6 +1368,74 @@ var serverGetConnectionsFunc = func(s []string) error {
 	return nil
 }
 
+var serverGetSessionsFunc = func(_ []string) error {
+	client, err := newRunningServerManagementRPCClient()
+	if err != nil {
+		return err
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	info, err := client.GetSessionInfoList(timedctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.GetSessionsFailedErr, err)
+	}
+
+	table := make([][]string, 0)
+	table = append(table, []string{
+		"SessionID",
+		"User",
+		"Client",
+		"Protocol",
+		"Duration",
+		"Up",
+		"Down",
+		"Destination",
+	})
+	now := time.Now()
+	for _, si := range info.GetItems() {
+		row := make([]string, 8)
+		row[0] = si.GetId()
+		row[1] = si.GetUser()
+		row[2] = si.GetRemoteAddr()
+		row[3] = si.GetProtocol()
+		if si.GetStartTime() != nil {
+			row[4] = now.Sub(si.GetStartTime().AsTime()).Truncate(time.Second).String()
+		} else {
+			row[4] = "-"
+		}
+		row[5] = common.ByteCountIEC(si.GetRecvBytes())
+		row[6] = common.ByteCountIEC(si.GetSendBytes())
+		row[7] = si.GetDestination()
+		if row[1] == "" {
+			row[1] = "-"
+		}
+		if row[7] == "" {
+			row[7] = "-"
+		}
+		table = append(table, row)
+	}
+	printTable(table, "  ")
+	return nil
+}
+
+var serverKillSessionFunc = func(s []string) error {
+	id, err := strconv.ParseUint(s[3], 10, 32)
+	if err != nil {
+		return fmt.Errorf("session ID %q is invalid", s[3])
+	}
+	client, err := newRunningServerManagementRPCClient()
+	if err != nil {
+		return err
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	if _, err = client.KillSession(timedctx, &appctlpb.KillSessionRequest{Id: proto.Uint32(uint32(id))}); err != nil {
+		return fmt.Errorf(stderror.KillSessionFailedErr, err)
+	}
+	log.Infof("Session %d is closed.", id)
+	return nil
+}
+
 var serverGetUsersFunc = func(_ []string) error {
 	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
 	if err != nil {
@@ -872,6 +1530,90 @@ var serverGetUsersFunc = func(_ []string) error {
 	return nil
 }
 
+var serverGetUserGroupsFunc = func(_ []string) error {
+	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
+	if err != nil {
+		if stderror.IsConnRefused(err) {
+			return fmt.Errorf(stderror.ServerNotRunningWithCommand)
+		}
+		return fmt.Errorf(stderror.GetServerStatusFailedErr, err)
+	}
+	if err := appctl.IsServerDaemonRunning(appStatus); err != nil {
+		return fmt.Errorf(stderror.ServerNotRunningErr, err)
+	}
+
+	client, err := appctl.NewServerManagementRPCClient()
+	if err != nil {
+		return fmt.Errorf(stderror.CreateServerManagementRPCClientFailedErr, err)
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	groupWithMetricsList, err := client.GetUserGroups(timedctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.GetUserGroupsFailedErr, err)
+	}
+
+	header := []string{
+		"Group",
+		"Users",
+		"Egress",
+		"1DayDown",
+		"1DayUp",
+		"30DaysDown",
+		"30DaysUp",
+	}
+	table := make([][]string, 0)
+	table = append(table, header)
+	for _, groupWithMetrics := range groupWithMetricsList.GetItems() {
+		group := groupWithMetrics.GetGroup()
+		row := make([]string, 7)
+		row[0] = group.GetName()
+		row[1] = strconv.Itoa(len(group.GetUsers()))
+		if group.Egress != nil {
+			row[2] = "group"
+		} else {
+			row[2] = "server"
+		}
+
+		// Collect download and upload metrics of this user group.
+		var down, up *metrics.Counter
+		for _, metric := range groupWithMetrics.GetMetrics() {
+			switch metric.GetName() {
+			case metrics.UserMetricDownloadBytes:
+				downMetric, err := metrics.FromMetricPB(metric)
+				if err != nil {
+					return fmt.Errorf("metrics.FromMetricPB() failed: %w", err)
+				}
+				down = downMetric.(*metrics.Counter)
+			case metrics.UserMetricUploadBytes:
+				upMetric, err := metrics.FromMetricPB(metric)
+				if err != nil {
+					return fmt.Errorf("metrics.FromMetricPB() failed: %w", err)
+				}
+				up = upMetric.(*metrics.Counter)
+			}
+		}
+		now := time.Now()
+		if down != nil {
+			row[3] = common.ByteCountIEC(down.DeltaBetween(now.Add(-24*time.Hour), now))
+			row[5] = common.ByteCountIEC(down.DeltaBetween(now.Add(-720*time.Hour), now))
+		} else {
+			row[3] = "-"
+			row[5] = "-"
+		}
+		if up != nil {
+			row[4] = common.ByteCountIEC(up.DeltaBetween(now.Add(-24*time.Hour), now))
+			row[6] = common.ByteCountIEC(up.DeltaBetween(now.Add(-720*time.Hour), now))
+		} else {
+			row[4] = "-"
+			row[6] = "-"
+		}
+		table = append(table, row)
+	}
+	printTable(table, "  ")
+	return nil
+}
+
 var serverGetQuotasFunc = func(_ []string) error {
 	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
 	if err != nil {
@@ -943,6 +1685,189 @@ var serverGetQuotasFunc = func(_ []string) error {
 	return nil
 }
 
+var serverGetCountryTrafficFunc = func(_ []string) error {
+	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
+	if err != nil {
+		if stderror.IsConnRefused(err) {
+			return fmt.Errorf(stderror.ServerNotRunningWithCommand)
+		}
+		return fmt.Errorf(stderror.GetServerStatusFailedErr, err)
+	}
+	if err := appctl.IsServerDaemonRunning(appStatus); err != nil {
+		return fmt.Errorf(stderror.ServerNotRunningErr, err)
+	}
+
+	client, err := appctl.NewServerManagementRPCClient()
+	if err != nil {
+		return fmt.Errorf(stderror.CreateServerManagementRPCClientFailedErr, err)
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	list, err := client.GetCountryTraffic(timedctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.GetCountryTrafficFailedErr, err)
+	}
+	table := make([][]string, 0)
+	table = append(table, []string{"Country", "Download", "Upload", "Total"})
+	for _, t := range list.GetItems() {
+		table = append(table, []string{
+			t.GetCountry(),
+			common.ByteCountIEC(t.GetDownloadBytes()),
+			common.ByteCountIEC(t.GetUploadBytes()),
+			common.ByteCountIEC(t.GetDownloadBytes() + t.GetUploadBytes()),
+		})
+	}
+	printTable(table, "  ")
+	return nil
+}
+
+var serverGetGeoDatabasesFunc = func(_ []string) error {
+	config, err := serverGetConfigWithRPC()
+	if err != nil {
+		return err
+	}
+	return printGeoDatabases(config.GetGeoDatabases())
+}
+
+var serverUpdateGeoDatabasesFunc = func(_ []string) error {
+	config, err := serverGetConfigWithRPC()
+	if err != nil {
+		return err
+	}
+	trust, err := appctl.ServerTrustStore()
+	if err != nil {
+		return fmt.Errorf("appctl.ServerTrustStore() failed: %w", err)
+	}
+	return updateGeoDatabases(config.GetGeoDatabases(), trust)
+}
+
+// serverGetConfigWithRPC returns the configuration of the running server.
+func serverGetConfigWithRPC() (*appctlpb.ServerConfig, error) {
+	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
+	if err != nil {
+		if stderror.IsConnRefused(err) {
+			return nil, fmt.Errorf(stderror.ServerNotRunningWithCommand)
+		}
+		return nil, fmt.Errorf(stderror.GetServerStatusFailedErr, err)
+	}
+	if err := appctl.IsServerDaemonRunning(appStatus); err != nil {
+		return nil, fmt.Errorf(stderror.ServerNotRunningErr, err)
+	}
+
+	client, err := appctl.NewServerManagementRPCClient()
+	if err != nil {
+		return nil, fmt.Errorf(stderror.CreateServerManagementRPCClientFailedErr, err)
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	config, err := client.GetConfig(timedctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf(stderror.GetServerConfigFailedErr, err)
+	}
+	return config, nil
+}
+
+var serverGetPortBindingsFunc = func(_ []string) error {
+	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
+	if err != nil {
+		if stderror.IsConnRefused(err) {
+			return fmt.Errorf(stderror.ServerNotRunningWithCommand)
+		}
+		return fmt.Errorf(stderror.GetServerStatusFailedErr, err)
+	}
+	if err := appctl.IsServerDaemonRunning(appStatus); err != nil {
+		return fmt.Errorf(stderror.ServerNotRunningErr, err)
+	}
+
+	client, err := appctl.NewServerManagementRPCClient()
+	if err != nil {
+		return fmt.Errorf(stderror.CreateServerManagementRPCClientFailedErr, err)
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	list, err := client.GetPortBindings(timedctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.GetPortBindingsFailedErr, err)
+	}
+	table := make([][]string, 0)
+	table = append(table, []string{"Address", "Protocols", "State", "Underlays", "Sessions", "Error"})
+	for _, b := range list.GetItems() {
+		protocols := make([]string, 0, len(b.GetProtocols()))
+		for _, p := range b.GetProtocols() {
+			protocols = append(protocols, p.String())
+		}
+		table = append(table, []string{
+			b.GetAddress(),
+			strings.Join(protocols, "+"),
+			strings.TrimPrefix(b.GetState().String(), "PORT_BINDING_"),
+			strconv.FormatInt(b.GetUnderlays(), 10),
+			strconv.FormatInt(b.GetSessions(), 10),
+			b.GetError(),
+		})
+	}
+	printTable(table, "  ")
+	return nil
+}
+
+var serverRotateKeysFunc = func(_ []string) error {
+	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
+	if err != nil {
+		if stderror.IsConnRefused(err) {
+			return fmt.Errorf(stderror.ServerNotRunningWithCommand)
+		}
+		return fmt.Errorf(stderror.GetServerStatusFailedErr, err)
+	}
+	if err := appctl.IsServerDaemonRunning(appStatus); err != nil {
+		return fmt.Errorf(stderror.ServerNotRunningErr, err)
+	}
+
+	client, err := appctl.NewServerManagementRPCClient()
+	if err != nil {
+		return fmt.Errorf(stderror.CreateServerManagementRPCClientFailedErr, err)
+	}
+	timedctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	epoch, err := client.RotateKeys(timedctx, &emptypb.Empty{})
+	if err != nil {
+		return fmt.Errorf(stderror.RotateKeysFailedErr, err)
+	}
+	log.Infof("mita server only accepts keys of key epoch %d and later", epoch.GetEpoch())
+	return nil
+}
+
+var serverFollowLogsFunc = func(s []string) error {
+	level, err := followLogsLevel(s)
+	if err != nil {
+		return err
+	}
+	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
+	if err != nil {
+		return fmt.Errorf(stderror.GetServerStatusFailedErr, err)
+	}
+	if err := appctl.IsServerDaemonRunning(appStatus); err != nil {
+		return fmt.Errorf(stderror.ServerNotRunningErr, err)
+	}
+
+	client, err := appctl.NewServerManagementRPCClient()
+	if err != nil {
+		return fmt.Errorf(stderror.CreateServerManagementRPCClientFailedErr, err)
+	}
+	stream, err := client.FollowLogs(context.Background(), &appctlpb.FollowLogsRequest{Level: level.Enum()})
+	if err != nil {
+		return fmt.Errorf(stderror.FollowLogsFailedErr, err)
+	}
+	for {
+		entry, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf(stderror.FollowLogsFailedErr, err)
+		}
+		log.Infof("%s", formatLogEntry(entry))
+	}
+}
+
 var serverGetThreadDumpFunc = func(s []string) error {
 	appStatus, err := appctl.GetServerStatusWithRPC(context.Background())
 	if err != nil {
@@ -0,0 +1,119 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+
+	"github.com/enfein/mieru/v3/pkg/appctl"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/service"
+	"github.com/enfein/mieru/v3/pkg/stderror"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// serverServiceName is the name of mita in the service manager.
+const serverServiceName = "mita"
+
+// serverServiceConfig returns the service config to run mita server
+// with the current executable.
+func serverServiceConfig() (service.Config, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return service.Config{}, fmt.Errorf("os.Executable() failed: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	config := service.Config{
+		Name:        serverServiceName,
+		Description: "Mieru proxy server",
+		Executable:  exe,
+		Args:        []string{"run"},
+	}
+	if runtime.GOOS == "linux" {
+		// Same as the service installed by the deb and rpm packages.
+		config.Env = []string{"MITA_LOG_NO_TIMESTAMP=true"}
+		config.User = "mita"
+		config.Directories = []string{"/etc/mita", appctl.ServerDataDir, filepath.Dir(appctl.ServerUDS())}
+	}
+	return config, nil
+}
+
+var serverServiceInstallFunc = func(s []string) error {
+	config, err := serverServiceConfig()
+	if err != nil {
+		return fmt.Errorf(stderror.InstallServiceFailedErr, err)
+	}
+	if err := service.Install(config); err != nil {
+		return fmt.Errorf(stderror.InstallServiceFailedErr, err)
+	}
+	log.Infof("mita service is installed and started, it will start with the system")
+	return nil
+}
+
+var serverServiceUninstallFunc = func(s []string) error {
+	if err := service.Uninstall(serverServiceName); err != nil {
+		return fmt.Errorf(stderror.UninstallServiceFailedErr, err)
+	}
+	log.Infof("mita service is stopped and uninstalled")
+	return nil
+}
+
+var serverServiceStatusFunc = func(s []string) error {
+	state, err := service.Status(serverServiceName)
+	if err != nil {
+		return fmt.Errorf(stderror.GetServiceStatusFailedErr, err)
+	}
+	log.Infof("mita service is %s", state)
+	return nil
+}
+
+// superviseServer runs "mita run" in a child process, and restarts it
+// after a crash, until mita receives SIGINT or SIGTERM.
+func superviseServer(s []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return service.Supervise(ctx, func() *exec.Cmd {
+		cmd := exec.Command(s[0], "run")
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd
+	})
+}
+
+// stopServerDaemon asks the mita server daemon of this process to exit.
+// It is called when the service manager stops the service.
+func stopServerDaemon() {
+	client, err := appctl.NewServerManagementRPCClient()
+	if err != nil {
+		log.Errorf("create server management RPC client failed: %v", err)
+		return
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), appctl.RPCTimeout)
+	defer cancelFunc()
+	if _, err := client.Exit(ctx, &emptypb.Empty{}); err != nil {
+		log.Errorf("exit mita server daemon failed: %v", err)
+	}
+}
@@ -16,15 +16,28 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"runtime/debug"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/enfein/mieru/v3/pkg/appctl"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlcommon"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/cipher"
+	"github.com/enfein/mieru/v3/pkg/common"
 	"github.com/enfein/mieru/v3/pkg/log"
 	"github.com/enfein/mieru/v3/pkg/mathext"
+	"github.com/enfein/mieru/v3/pkg/minisign"
 	"github.com/enfein/mieru/v3/pkg/version"
+	"github.com/enfein/mieru/v3/pkg/version/updater"
+	"github.com/enfein/mieru/v3/pkg/version/updater/updaterpb"
 )
 
 var versionFunc = func(_ []string) error {
@@ -41,6 +54,128 @@ var describeBuildFunc = func(_ []string) error {
 	return nil
 }
 
+// describeCiphersFunc measures the throughput of each cipher suite on
+// this device. The cipher suite picked by AUTO is marked with "*".
+var describeCiphersFunc = func(_ []string) error {
+	suites := map[cipher.AEADType]appctlpb.CipherSuite{
+		cipher.XChaCha20Poly1305: appctlpb.CipherSuite_XCHACHA20_POLY1305,
+		cipher.AES128GCM:         appctlpb.CipherSuite_AES_128_GCM,
+		cipher.AES256GCM:         appctlpb.CipherSuite_AES_256_GCM,
+		cipher.ChaCha20Poly1305:  appctlpb.CipherSuite_CHACHA20_POLY1305,
+	}
+	fastest := cipher.FastestAEADType()
+	table := make([][]string, 0)
+	table = append(table, []string{"CipherSuite", "Algorithm", "Throughput", "Auto"})
+	for _, r := range cipher.MeasureAEADThroughput(200 * time.Millisecond) {
+		auto := ""
+		if r.AEADType == fastest {
+			auto = "*"
+		}
+		table = append(table, []string{
+			suites[r.AEADType].String(),
+			r.AEADType.String(),
+			fmt.Sprintf("%.0f MB/s", r.MBps),
+			auto,
+		})
+	}
+	printTable(table, "  ")
+	return nil
+}
+
+// parseUpdateChannelArgs returns the update channel from the
+// "--channel stable|beta" argument after a command of the given number
+// of words. If the argument is not provided, defaultChannel is returned.
+func parseUpdateChannelArgs(s []string, length int, defaultChannel string) (string, error) {
+	usage := fmt.Sprintf("usage: %s [--channel stable|beta]", strings.Join(append([]string{binaryName}, s[1:length]...), " "))
+	channel := defaultChannel
+	for i := length; i < len(s); i++ {
+		switch {
+		case s[i] == "--channel":
+			if i+1 >= len(s) {
+				return "", fmt.Errorf("%s. channel is not provided", usage)
+			}
+			i++
+			channel = s[i]
+		case strings.HasPrefix(s[i], "--channel="):
+			channel = strings.TrimPrefix(s[i], "--channel=")
+		default:
+			return "", fmt.Errorf("%s. unexpected argument %q", usage, s[i])
+		}
+		if channel != updater.ChannelStable && channel != updater.ChannelBeta {
+			return "", fmt.Errorf("%s. channel %q is not supported", usage, channel)
+		}
+	}
+	return channel, nil
+}
+
+// insecureSkipSignatureFlag allows the update command to install a
+// release that is only verified by the SHA-256 checksum, if no public
+// key is trusted to verify the signature.
+const insecureSkipSignatureFlag = "--insecure-skip-signature"
+
+// parseSelfUpdateArgs returns the update channel, and whether the
+// signature verification is skipped, from the arguments of the update command.
+func parseSelfUpdateArgs(s []string, length int, defaultChannel string) (string, bool, error) {
+	skipSignature := false
+	args := make([]string, 0, len(s))
+	for i, arg := range s {
+		if i >= length && arg == insecureSkipSignatureFlag {
+			skipSignature = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	channel, err := parseUpdateChannelArgs(args, length, defaultChannel)
+	return channel, skipSignature, err
+}
+
+// selfUpdateError adds a suggestion to the error of the update command
+// if no public key is pinned or trusted.
+func selfUpdateError(err error) error {
+	if errors.Is(err, updater.ErrNoPinnedKey) {
+		return fmt.Errorf("%w; install an official release, or build %s with RELEASE_PUBLIC_KEY set to the public key that signs the release, or run \"%s update %s\" to only verify the SHA-256 checksum", err, binaryName, binaryName, insecureSkipSignatureFlag)
+	}
+	if errors.Is(err, updater.ErrNoTrustedKey) {
+		return fmt.Errorf("%w; trust the public key that signs the release with \"%s trust add <PUBLIC_KEY_OR_FILE>\", or run \"%s update %s\" to only verify the SHA-256 checksum", err, binaryName, binaryName, insecureSkipSignatureFlag)
+	}
+	return err
+}
+
+// runUpdater loads the update history from the file, runs the check
+// update or the update, and stores the result to the update history.
+// If the history file is empty, the update history is not used.
+func runUpdater(historyFile string, run func(h *updater.History) (*updaterpb.UpdateRecord, string, error)) (*updaterpb.UpdateRecord, string, error) {
+	h := updater.NewHistory()
+	if historyFile != "" {
+		h.LoadFrom(historyFile) // OK to fail. No side effect.
+	}
+	record, msg, err := run(h)
+	if record != nil {
+		h.Insert(record)
+		h.Trim()
+	}
+	if historyFile != "" {
+		h.StoreTo(historyFile) // OK to fail.
+	}
+	return record, msg, err
+}
+
+// printNewRelease prints the release notes and the download URL
+// if a new release is found.
+func printNewRelease(record *updaterpb.UpdateRecord) {
+	if !record.GetNewReleaseFound() {
+		return
+	}
+	if record.GetDownloadURL() != "" {
+		log.Infof("Download: %s", record.GetDownloadURL())
+	}
+	if notes := strings.TrimSpace(record.GetReleaseNotes()); notes != "" {
+		log.Infof("")
+		log.Infof("Release notes of version %s:", record.GetLatestVersion())
+		log.Infof("%s", notes)
+	}
+}
+
 func printSessionInfoList(info *appctlpb.SessionInfoList) {
 	header := []string{
 		"SessionID",
@@ -83,6 +218,121 @@ func printSessionInfoList(info *appctlpb.SessionInfoList) {
 	printTable(table, "  ")
 }
 
+// printConfigVerification prints the warnings and the changes
+// of a verified config patch.
+func printConfigVerification(v *appctl.ConfigVerification) {
+	for _, warning := range v.Warnings {
+		log.Warnf("%s", warning)
+	}
+	if v.Diff == "" {
+		log.Infof("Config is valid. Applying it doesn't change the stored config.")
+		return
+	}
+	log.Infof("Config is valid. Applying it changes the stored config as follows.")
+	log.Infof("%s", strings.TrimSuffix(v.Diff, "\n"))
+}
+
+// printGeoDatabases prints the version of the GeoIP and geosite databases.
+func printGeoDatabases(config *appctlpb.GeoDatabases) error {
+	infos := appctlcommon.DescribeGeoDatabases(config)
+	if len(infos) == 0 {
+		return fmt.Errorf("no GeoIP or geosite database is set in geoDatabases")
+	}
+	table := make([][]string, 0)
+	table = append(table, []string{"Database", "Path", "Version", "Size", "Modified"})
+	for _, info := range infos {
+		version := info.Version
+		if info.Err != nil {
+			version = fmt.Sprintf("(error: %v)", info.Err)
+		}
+		size, modified := "-", "-"
+		if !info.Modified.IsZero() {
+			size = common.ByteCountIEC(info.Size)
+			modified = info.Modified.Format(time.RFC3339)
+		}
+		table = append(table, []string{info.Name, info.Path, version, size, modified})
+	}
+	printTable(table, "  ")
+	return nil
+}
+
+// updateGeoDatabases downloads the GeoIP and geosite databases,
+// and prints their versions.
+func updateGeoDatabases(config *appctlpb.GeoDatabases, trust *minisign.TrustStore) error {
+	p := newProgress()
+	p.step("Downloading geo databases")
+	err := appctlcommon.UpdateGeoDatabases(context.Background(), config, trust)
+	p.done()
+	if err != nil {
+		return err
+	}
+	return printGeoDatabases(config)
+}
+
+// trustListFunc prints the public keys in the trust store.
+func trustListFunc(load func() (*minisign.TrustStore, error)) func([]string) error {
+	return func(_ []string) error {
+		ts, err := load()
+		if err != nil {
+			return fmt.Errorf("failed to load trust store: %w", err)
+		}
+		keys := ts.Keys()
+		if len(keys) == 0 {
+			log.Infof("No public key is trusted.")
+			return nil
+		}
+		table := make([][]string, 0)
+		table = append(table, []string{"KeyID", "Pinned", "PublicKey"})
+		for _, k := range keys {
+			table = append(table, []string{k.ID.String(), fmt.Sprintf("%t", k.Pinned), k.PublicKey.String()})
+		}
+		printTable(table, "  ")
+		return nil
+	}
+}
+
+// trustAddFunc trusts the public key, or the public key in the file.
+func trustAddFunc(load func() (*minisign.TrustStore, error)) func([]string) error {
+	return func(s []string) error {
+		input := s[3]
+		if b, err := os.ReadFile(input); err == nil {
+			input = string(b)
+		}
+		pk, err := minisign.ParsePublicKey(input)
+		if err != nil {
+			return fmt.Errorf("failed to parse public key: %w", err)
+		}
+		ts, err := load()
+		if err != nil {
+			return fmt.Errorf("failed to load trust store: %w", err)
+		}
+		if err := ts.Add(pk); err != nil {
+			return err
+		}
+		log.Infof("public key %s is trusted", pk.ID)
+		return nil
+	}
+}
+
+// trustRemoveFunc stops trusting the public key with the key ID.
+func trustRemoveFunc(load func() (*minisign.TrustStore, error)) func([]string) error {
+	return func(s []string) error {
+		id, err := minisign.ParseKeyID(s[3])
+		if err != nil {
+			return err
+		}
+		ts, err := load()
+		if err != nil {
+			return fmt.Errorf("failed to load trust store: %w", err)
+		}
+		if err := ts.Remove(id); err != nil {
+			return err
+		}
+		log.Infof("public key %s is no longer trusted", id)
+		return nil
+	}
+}
+
 func printTable(table [][]string, delim string) {
 	nRow := len(table)
 	if nRow == 0 {
@@ -117,3 +367,56 @@ func printTable(table [][]string, delim string) {
 		log.Infof("%s", strings.Join(rowWithPadding, delim))
 	}
 }
+
+// reloadOnSIGHUP calls the reload function in the background
+// each time the process receives SIGHUP.
+func reloadOnSIGHUP(reload func() error) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		for range sigCh {
+			log.Infof("Received SIGHUP, reloading configuration")
+			if err := reload(); err != nil {
+				log.Errorf("Reload configuration failed: %v", err)
+			} else {
+				log.Infof("Configuration is reloaded")
+			}
+		}
+	}()
+}
+
+// followLogsLevel returns the level argument of "follow logs" command.
+// s is the full command line.
+func followLogsLevel(s []string) (appctlpb.LoggingLevel, error) {
+	if len(s) < 4 {
+		return appctlpb.LoggingLevel_DEFAULT, nil
+	}
+	if len(s) > 4 {
+		return appctlpb.LoggingLevel_DEFAULT, fmt.Errorf("usage: %s follow logs [LEVEL]. more than 1 level is provided", binaryName)
+	}
+	level, ok := appctlpb.LoggingLevel_value[strings.ToUpper(s[3])]
+	if !ok || level == int32(appctlpb.LoggingLevel_DEFAULT) {
+		return appctlpb.LoggingLevel_DEFAULT, fmt.Errorf("invalid log level %q. valid levels are FATAL, ERROR, WARN, INFO, DEBUG and TRACE", s[3])
+	}
+	return appctlpb.LoggingLevel(level), nil
+}
+
+// formatLogEntry returns a line of the followed log entry.
+// Fields are sorted by key.
+func formatLogEntry(e *appctlpb.LogEntry) string {
+	var b strings.Builder
+	b.WriteString(e.GetTime().AsTime().Local().Format("2006-01-02T15:04:05.000Z07:00"))
+	b.WriteString(" ")
+	b.WriteString(e.GetLevel().String())
+	b.WriteString(" ")
+	b.WriteString(e.GetMessage())
+	keys := make([]string, 0, len(e.GetFields()))
+	for k := range e.GetFields() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%s", k, e.GetFields()[k])
+	}
+	return b.String()
+}
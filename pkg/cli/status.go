@@ -0,0 +1,172 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/version"
+	"github.com/enfein/mieru/v3/pkg/version/updater/updaterpb"
+)
+
+// ANSI escape codes to colorize terminal output.
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+)
+
+// clientStatusSummary is the output of "mieru status" command.
+type clientStatusSummary struct {
+	Status                 string                `json:"status"`
+	UptimeSeconds          int64                 `json:"uptimeSeconds,omitempty"`
+	Profile                string                `json:"profile,omitempty"`
+	Servers                []serverStatusSummary `json:"servers"`
+	DownloadBytesPerSecond int64                 `json:"downloadBytesPerSecond"`
+	UploadBytesPerSecond   int64                 `json:"uploadBytesPerSecond"`
+	LastError              string                `json:"lastError,omitempty"`
+	LastErrorTime          string                `json:"lastErrorTime,omitempty"`
+	Version                string                `json:"version"`
+	LatestVersion          string                `json:"latestVersion,omitempty"`
+	UpdateAvailable        bool                  `json:"updateAvailable"`
+
+	lastErrorAge time.Duration
+}
+
+type serverStatusSummary struct {
+	Protocol   string `json:"protocol"`
+	RemoteAddr string `json:"remoteAddr"`
+	Sessions   int32  `json:"sessions"`
+}
+
+// newClientStatusSummary creates the status summary from the client status
+// and the latest check update record, which can be nil.
+func newClientStatusSummary(msg *appctlpb.AppStatusMsg, latest *updaterpb.UpdateRecord, now time.Time) *clientStatusSummary {
+	s := &clientStatusSummary{
+		Status:                 msg.GetStatus().String(),
+		Profile:                msg.GetProfile(),
+		Servers:                make([]serverStatusSummary, 0),
+		DownloadBytesPerSecond: msg.GetDownloadBytesPerSecond(),
+		UploadBytesPerSecond:   msg.GetUploadBytesPerSecond(),
+		LastError:              msg.GetLastError(),
+		Version:                version.AppVersion,
+	}
+	if msg.StartTimeUnix != nil {
+		s.UptimeSeconds = now.Unix() - msg.GetStartTimeUnix()
+	}
+	for _, server := range msg.GetServers() {
+		s.Servers = append(s.Servers, serverStatusSummary{
+			Protocol:   server.GetProtocol(),
+			RemoteAddr: server.GetRemoteAddr(),
+			Sessions:   server.GetSessions(),
+		})
+	}
+	if msg.LastErrorTimeUnix != nil {
+		t := time.Unix(msg.GetLastErrorTimeUnix(), 0)
+		s.LastErrorTime = t.UTC().Format(time.RFC3339)
+		s.lastErrorAge = now.Sub(t)
+	}
+	if latest != nil && latest.GetError() == "" {
+		s.LatestVersion = latest.GetLatestVersion()
+		// The update command may have installed the latest version.
+		s.UpdateAvailable = latest.GetNewReleaseFound() && latest.GetLatestVersion() != version.AppVersion
+	}
+	return s
+}
+
+// JSON returns the status summary in JSON format.
+func (s *clientStatusSummary) JSON() (string, error) {
+	b, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return "", fmt.Errorf("json.MarshalIndent() failed: %w", err)
+	}
+	return string(b), nil
+}
+
+// Lines returns the human readable status summary.
+func (s *clientStatusSummary) Lines(color bool) []string {
+	paint := func(text, code string) string {
+		if !color {
+			return text
+		}
+		return code + text + colorReset
+	}
+
+	table := make([][]string, 0)
+	status := s.Status
+	if s.Status == appctlpb.AppStatus_RUNNING.String() {
+		status = paint(status, colorGreen)
+		if s.UptimeSeconds > 0 {
+			status += ", up " + (time.Duration(s.UptimeSeconds) * time.Second).String()
+		}
+	} else {
+		status = paint(status, colorYellow)
+	}
+	table = append(table, []string{"Status", status})
+	if s.Profile != "" {
+		table = append(table, []string{"Profile", s.Profile})
+	}
+	if len(s.Servers) == 0 {
+		table = append(table, []string{"Servers", "no active connection"})
+	}
+	for i, server := range s.Servers {
+		label := ""
+		if i == 0 {
+			label = "Servers"
+		}
+		sessions := fmt.Sprintf("%d sessions", server.Sessions)
+		if server.Sessions == 1 {
+			sessions = "1 session"
+		}
+		table = append(table, []string{label, fmt.Sprintf("%s %s (%s)", server.Protocol, server.RemoteAddr, sessions)})
+	}
+	table = append(table, []string{"Throughput", fmt.Sprintf("download %s/s, upload %s/s", common.ByteCountIEC(s.DownloadBytesPerSecond), common.ByteCountIEC(s.UploadBytesPerSecond))})
+	if s.LastError == "" {
+		table = append(table, []string{"Last error", "none"})
+	} else {
+		table = append(table, []string{"Last error", paint(s.LastError, colorRed) + fmt.Sprintf(" (%v ago)", s.lastErrorAge.Round(time.Second))})
+	}
+	if s.UpdateAvailable {
+		table = append(table, []string{"Version", s.Version + ", " + paint(fmt.Sprintf("new version %s is available", s.LatestVersion), colorYellow)})
+	} else {
+		table = append(table, []string{"Version", s.Version})
+	}
+
+	lines := make([]string, 0, len(table))
+	for _, row := range table {
+		lines = append(lines, fmt.Sprintf("  %-12s%s", row[0], row[1]))
+	}
+	return lines
+}
+
+// useColor returns true if the standard output is a terminal
+// that can display colors.
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
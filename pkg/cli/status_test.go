@@ -0,0 +1,80 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/version/updater/updaterpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestClientStatusSummary(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	msg := &appctlpb.AppStatusMsg{
+		Status:        appctlpb.AppStatus_RUNNING.Enum(),
+		StartTimeUnix: proto.Int64(now.Unix() - 3600),
+		Profile:       proto.String("default"),
+		Servers: []*appctlpb.ServerConnectionStatus{
+			{Protocol: proto.String("TCP"), RemoteAddr: proto.String("1.2.3.4:2027"), Sessions: proto.Int32(1)},
+		},
+		LastError:              proto.String("mux DialContext() failed"),
+		LastErrorTimeUnix:      proto.Int64(now.Unix() - 60),
+		DownloadBytesPerSecond: proto.Int64(2048),
+		UploadBytesPerSecond:   proto.Int64(10),
+	}
+	latest := &updaterpb.UpdateRecord{
+		LatestVersion:   proto.String("99.0.0"),
+		NewReleaseFound: proto.Bool(true),
+	}
+	summary := newClientStatusSummary(msg, latest, now)
+
+	text := strings.Join(summary.Lines(false), "\n")
+	for _, want := range []string{
+		"RUNNING, up 1h0m0s",
+		"default",
+		"TCP 1.2.3.4:2027 (1 session)",
+		"download 2.0KiB/s, upload 10B/s",
+		"mux DialContext() failed (1m0s ago)",
+		"new version 99.0.0 is available",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("status output doesn't contain %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "\033[") {
+		t.Errorf("status output contains color when color is disabled")
+	}
+	if colored := strings.Join(summary.Lines(true), "\n"); !strings.Contains(colored, colorGreen+"RUNNING"+colorReset) {
+		t.Errorf("status is not colored")
+	}
+
+	out, err := summary.JSON()
+	if err != nil {
+		t.Fatalf("JSON() failed: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+	if decoded["status"] != "RUNNING" || decoded["uptimeSeconds"] != float64(3600) || decoded["updateAvailable"] != true {
+		t.Errorf("unexpected JSON output: %s", out)
+	}
+}
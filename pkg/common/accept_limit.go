@@ -0,0 +1,197 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+// AcceptLimit protects a listener from resource exhaustion attacks,
+// such as slowloris, which open many connections and never finish
+// the handshake. A zero value doesn't limit anything.
+type AcceptLimit struct {
+	// If positive, close the connections that don't finish the handshake
+	// in this duration.
+	HandshakeTimeout time.Duration
+
+	// If positive, the maximum number of accepted connections that
+	// haven't finished the handshake. New connections beyond the limit
+	// are closed.
+	MaxHalfOpen int
+
+	// If positive, the maximum number of connections accepted per second.
+	// New connections beyond the rate are closed.
+	AcceptRate int
+}
+
+// AcceptLimitMetrics are the metrics updated by a listener with AcceptLimit.
+type AcceptLimitMetrics struct {
+	HalfOpen          metrics.Metric // GAUGE of connections that haven't finished the handshake
+	HandshakeTimeouts metrics.Metric // COUNTER of connections closed by the handshake timeout
+	HalfOpenRejected  metrics.Metric // COUNTER of connections closed by the half-open limit
+	RateLimited       metrics.Metric // COUNTER of connections closed by the accept rate limit
+}
+
+// HandshakeConn is a connection accepted by a listener with AcceptLimit.
+type HandshakeConn interface {
+	net.Conn
+
+	// HandshakeDone marks the handshake as finished. The connection is
+	// no longer closed by the handshake timeout, and no longer counted
+	// as half-open. It is safe to call it multiple times.
+	HandshakeDone()
+}
+
+// HandshakeDone marks the handshake of the connection as finished,
+// if the connection is accepted by a listener with AcceptLimit.
+// Connections that wrap another connection, such as *tls.Conn, are
+// unwrapped by their NetConn method.
+func HandshakeDone(conn net.Conn) {
+	for conn != nil {
+		if hc, ok := conn.(HandshakeConn); ok {
+			hc.HandshakeDone()
+			return
+		}
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return
+		}
+		conn = wrapper.NetConn()
+	}
+}
+
+// NewLimitedListener returns a listener that applies the AcceptLimit to
+// the connections accepted from l. It returns l if nothing is limited.
+// The connections returned by the listener implement HandshakeConn.
+func NewLimitedListener(l net.Listener, limit AcceptLimit, m AcceptLimitMetrics) net.Listener {
+	if limit.HandshakeTimeout <= 0 && limit.MaxHalfOpen <= 0 && limit.AcceptRate <= 0 {
+		return l
+	}
+	return &limitedListener{
+		Listener: l,
+		limit:    limit,
+		metrics:  m,
+		tokens:   float64(limit.AcceptRate),
+		last:     time.Now(),
+	}
+}
+
+type limitedListener struct {
+	net.Listener
+	limit    AcceptLimit
+	metrics  AcceptLimitMetrics
+	halfOpen atomic.Int64
+
+	mu     sync.Mutex
+	tokens float64   // available tokens of the accept rate limiter
+	last   time.Time // last time tokens are added
+}
+
+func (l *limitedListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if !l.allow() {
+			l.metrics.RateLimited.Add(1)
+			conn.Close()
+			continue
+		}
+		if l.limit.MaxHalfOpen > 0 && l.halfOpen.Load() >= int64(l.limit.MaxHalfOpen) {
+			l.metrics.HalfOpenRejected.Add(1)
+			conn.Close()
+			continue
+		}
+		l.halfOpen.Add(1)
+		l.metrics.HalfOpen.Add(1)
+		c := &handshakeConn{Conn: conn, listener: l}
+		if l.limit.HandshakeTimeout > 0 {
+			c.timer = time.AfterFunc(l.limit.HandshakeTimeout, c.expire)
+		}
+		return c, nil
+	}
+}
+
+// allow returns true if a new connection is allowed by the accept rate.
+// The burst size is the number of connections per second.
+func (l *limitedListener) allow() bool {
+	if l.limit.AcceptRate <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.limit.AcceptRate)
+	if l.tokens > float64(l.limit.AcceptRate) {
+		l.tokens = float64(l.limit.AcceptRate)
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// handshakeConn is a connection that is half-open until the handshake
+// is done, the handshake timeout is reached, or it is closed.
+type handshakeConn struct {
+	net.Conn
+	listener *limitedListener
+	timer    *time.Timer
+	once     sync.Once
+}
+
+var _ HandshakeConn = (*handshakeConn)(nil)
+
+func (c *handshakeConn) HandshakeDone() {
+	if c.finish() && c.timer != nil {
+		c.timer.Stop()
+	}
+}
+
+func (c *handshakeConn) Close() error {
+	c.HandshakeDone()
+	return c.Conn.Close()
+}
+
+// expire closes the connection if the handshake is not done.
+// It runs in the timer goroutine, which may start before c.timer is set,
+// so it doesn't use the timer.
+func (c *handshakeConn) expire() {
+	if c.finish() {
+		c.listener.metrics.HandshakeTimeouts.Add(1)
+		c.Conn.Close()
+	}
+}
+
+// finish ends the half-open state. It returns true if the state
+// is ended by this call.
+func (c *handshakeConn) finish() bool {
+	finished := false
+	c.once.Do(func() {
+		c.listener.halfOpen.Add(-1)
+		c.listener.metrics.HalfOpen.Add(-1)
+		finished = true
+	})
+	return finished
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+func newTestAcceptLimitMetrics(group string) AcceptLimitMetrics {
+	return AcceptLimitMetrics{
+		HalfOpen:          metrics.RegisterMetric(group, "HalfOpen", metrics.GAUGE),
+		HandshakeTimeouts: metrics.RegisterMetric(group, "HandshakeTimeouts", metrics.COUNTER),
+		HalfOpenRejected:  metrics.RegisterMetric(group, "HalfOpenRejected", metrics.COUNTER),
+		RateLimited:       metrics.RegisterMetric(group, "RateLimited", metrics.COUNTER),
+	}
+}
+
+// startLimitedListener returns a limited listener and a channel of the
+// connections accepted by it.
+func startLimitedListener(t *testing.T, limit AcceptLimit, m AcceptLimitMetrics) (net.Listener, chan net.Conn) {
+	t.Helper()
+	rawListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	l := NewLimitedListener(rawListener, limit, m)
+	t.Cleanup(func() { l.Close() })
+	accepted := make(chan net.Conn, 16)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+	return l, accepted
+}
+
+// dialAndWaitClose connects to the listener and returns true if the
+// connection is closed by the listener before the timeout.
+func dialAndWaitClose(t *testing.T, l net.Listener, timeout time.Duration) bool {
+	t.Helper()
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	_, err = conn.Read(make([]byte, 1))
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return false
+	}
+	return true
+}
+
+func TestNewLimitedListenerNoLimit(t *testing.T) {
+	rawListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer rawListener.Close()
+	if l := NewLimitedListener(rawListener, AcceptLimit{}, AcceptLimitMetrics{}); l != rawListener {
+		t.Errorf("NewLimitedListener() wrapped the listener without any limit")
+	}
+}
+
+func TestLimitedListenerHandshakeTimeout(t *testing.T) {
+	m := newTestAcceptLimitMetrics("test accept limit handshake timeout")
+	l, accepted := startLimitedListener(t, AcceptLimit{HandshakeTimeout: 100 * time.Millisecond}, m)
+	timeouts := m.HandshakeTimeouts.Load()
+
+	// The connection is closed if the handshake is not done.
+	if !dialAndWaitClose(t, l, 3*time.Second) {
+		t.Errorf("connection is not closed after the handshake timeout")
+	}
+	<-accepted
+	if got := m.HandshakeTimeouts.Load() - timeouts; got != 1 {
+		t.Errorf("HandshakeTimeouts increased by %d, want 1", got)
+	}
+	if got := m.HalfOpen.Load(); got != 0 {
+		t.Errorf("HalfOpen = %d, want 0", got)
+	}
+
+	// The connection is kept if the handshake is done.
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	serverConn := <-accepted
+	HandshakeDone(WrapHierarchyConn(serverConn))
+	conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Fatalf("Read() got data, want timeout")
+	} else if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+		t.Errorf("connection is closed after the handshake is done: %v", err)
+	}
+	serverConn.Close()
+	if got := m.HandshakeTimeouts.Load() - timeouts; got != 1 {
+		t.Errorf("HandshakeTimeouts increased by %d, want 1", got)
+	}
+}
+
+func TestHandshakeDoneUnwrapsConn(t *testing.T) {
+	m := newTestAcceptLimitMetrics("test accept limit unwrap")
+	l, accepted := startLimitedListener(t, AcceptLimit{HandshakeTimeout: time.Minute}, m)
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	serverConn := <-accepted
+	defer serverConn.Close()
+	if got := m.HalfOpen.Load(); got != 1 {
+		t.Fatalf("HalfOpen = %d, want 1", got)
+	}
+
+	// *tls.Conn doesn't implement HandshakeConn, but has a NetConn method.
+	HandshakeDone(tls.Server(serverConn, &tls.Config{}))
+	if got := m.HalfOpen.Load(); got != 0 {
+		t.Errorf("HalfOpen = %d, want 0", got)
+	}
+}
+
+func TestLimitedListenerMaxHalfOpen(t *testing.T) {
+	m := newTestAcceptLimitMetrics("test accept limit max half open")
+	l, accepted := startLimitedListener(t, AcceptLimit{MaxHalfOpen: 2}, m)
+	rejected := m.HalfOpenRejected.Load()
+
+	var conns []net.Conn
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatalf("net.Dial() failed: %v", err)
+		}
+		defer conn.Close()
+		serverConn := <-accepted
+		defer serverConn.Close()
+		conns = append(conns, serverConn)
+	}
+	if got := m.HalfOpen.Load(); got != 2 {
+		t.Errorf("HalfOpen = %d, want 2", got)
+	}
+	if !dialAndWaitClose(t, l, 3*time.Second) {
+		t.Errorf("connection beyond the half-open limit is not closed")
+	}
+	if got := m.HalfOpenRejected.Load() - rejected; got != 1 {
+		t.Errorf("HalfOpenRejected increased by %d, want 1", got)
+	}
+
+	// A slot is released after the handshake is done.
+	conns[0].(HandshakeConn).HandshakeDone()
+	if dialAndWaitClose(t, l, 200*time.Millisecond) {
+		t.Errorf("connection is closed after a half-open slot is released")
+	}
+	(<-accepted).Close()
+}
+
+func TestLimitedListenerAcceptRate(t *testing.T) {
+	m := newTestAcceptLimitMetrics("test accept limit accept rate")
+	l, accepted := startLimitedListener(t, AcceptLimit{AcceptRate: 2}, m)
+	rateLimited := m.RateLimited.Load()
+
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatalf("net.Dial() failed: %v", err)
+		}
+		defer conn.Close()
+		(<-accepted).Close()
+	}
+	if !dialAndWaitClose(t, l, 3*time.Second) {
+		t.Errorf("connection beyond the accept rate is not closed")
+	}
+	if got := m.RateLimited.Load() - rateLimited; got != 1 {
+		t.Errorf("RateLimited increased by %d, want 1", got)
+	}
+}
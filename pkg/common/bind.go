@@ -0,0 +1,202 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/sockopts"
+)
+
+// LocalBinding is the local network interface, the local IP address and
+// the firewall mark of outgoing connections. It is useful on multi-homed
+// hosts and with policy routing. The zero value doesn't bind to anything.
+type LocalBinding struct {
+	// Name of the local network interface, e.g. "eth1".
+	// On Linux, sockets are bound to the interface with SO_BINDTODEVICE.
+	// On other operating systems, an IP address of the interface is used
+	// as the local IP address, unless IP is set.
+	Interface string
+
+	// Local IP address.
+	IP net.IP
+
+	// Firewall mark (SO_MARK) of the sockets. It is only supported on
+	// Linux, and it needs the CAP_NET_ADMIN capability. 0 means no mark.
+	Mark int
+}
+
+// IsZero returns true if the binding doesn't bind to anything.
+func (b LocalBinding) IsZero() bool {
+	return b.Interface == "" && b.IP == nil && b.Mark == 0
+}
+
+// String returns a human readable representation of the binding.
+func (b LocalBinding) String() string {
+	var parts []string
+	if b.Interface != "" {
+		parts = append(parts, "interface "+b.Interface)
+	}
+	if b.IP != nil {
+		parts = append(parts, fmt.Sprintf("IP %v", b.IP))
+	}
+	if b.Mark != 0 {
+		parts = append(parts, fmt.Sprintf("mark %d", b.Mark))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// LocalIP returns the local IP address to connect to the remote IP address.
+// It returns nil if any local IP address can be used. If remote is nil,
+// the address family of the remote is unknown.
+func (b LocalBinding) LocalIP(remote net.IP) (net.IP, error) {
+	if b.IP != nil {
+		if remote != nil && (b.IP.To4() != nil) != (remote.To4() != nil) {
+			return nil, fmt.Errorf("local IP %v and remote IP %v are not in the same address family", b.IP, remote)
+		}
+		return b.IP, nil
+	}
+	if b.Interface == "" || sockopts.BindToDeviceSupported || remote == nil {
+		return nil, nil
+	}
+	return InterfaceIP(b.Interface, remote.To4() != nil)
+}
+
+// Control returns the socket control function that binds the socket to
+// the network interface and sets the firewall mark. It returns nil if
+// neither of them is set.
+func (b LocalBinding) Control() sockopts.Control {
+	if b.Interface == "" && b.Mark == 0 {
+		return nil
+	}
+	return func(network, address string, c syscall.RawConn) error {
+		if b.Interface != "" {
+			if err := sockopts.BindToDevice(b.Interface)(network, address, c); err != nil {
+				return fmt.Errorf("bind to interface %s failed: %w", b.Interface, err)
+			}
+		}
+		if b.Mark != 0 {
+			if err := sockopts.SetMark(b.Mark)(network, address, c); err != nil {
+				return fmt.Errorf("set mark %d failed: %w", b.Mark, err)
+			}
+		}
+		return nil
+	}
+}
+
+// Dialer returns a dialer that makes connections from the binding.
+// The timeout, keep alive and socket control of the base dialer are used.
+// If the binding is zero, the base dialer is returned as is.
+func (b LocalBinding) Dialer(base *net.Dialer) apicommon.Dialer {
+	if b.IsZero() {
+		return base
+	}
+	return &localBindingDialer{binding: b, base: base}
+}
+
+// ListenUDP creates a UDP socket from the binding to send packets to the
+// remote IP address.
+func (b LocalBinding) ListenUDP(network string, remote net.IP) (*net.UDPConn, error) {
+	ip, err := b.LocalIP(remote)
+	if err != nil {
+		return nil, err
+	}
+	localAddr := &net.UDPAddr{IP: ip}
+	control := b.Control()
+	if control == nil {
+		return net.ListenUDP(network, localAddr)
+	}
+	lc := net.ListenConfig{Control: control}
+	conn, err := lc.ListenPacket(context.Background(), network, localAddr.String())
+	if err != nil {
+		return nil, err
+	}
+	return conn.(*net.UDPConn), nil
+}
+
+// localBindingDialer makes connections from a local binding.
+type localBindingDialer struct {
+	binding LocalBinding
+	base    *net.Dialer
+}
+
+var _ apicommon.Dialer = &localBindingDialer{}
+
+// DialContext connects to the address from the local binding.
+func (d *localBindingDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	var remote net.IP
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		remote = net.ParseIP(host)
+	}
+	ip, err := d.binding.LocalIP(remote)
+	if err != nil {
+		return nil, err
+	}
+	dialer := *d.base
+	if ip != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+	if bindControl := d.binding.Control(); bindControl != nil {
+		baseControl := d.base.Control
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			if baseControl != nil {
+				if err := baseControl(network, address, c); err != nil {
+					return err
+				}
+			}
+			return bindControl(network, address, c)
+		}
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// InterfaceIP returns an IP address of the network interface in the
+// given address family. Link local addresses are not used.
+func InterfaceIP(name string, ipv4 bool) (net.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("net.InterfaceByName() failed: %w", err)
+	}
+	if iface.Flags&net.FlagUp == 0 {
+		return nil, fmt.Errorf("network interface %s is down", name)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("Addrs() failed: %w", err)
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ipv4 == (ipNet.IP.To4() != nil) {
+			return ipNet.IP, nil
+		}
+	}
+	family := "IPv6"
+	if ipv4 {
+		family = "IPv4"
+	}
+	return nil, fmt.Errorf("network interface %s has no %s address", name, family)
+}
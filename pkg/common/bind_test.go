@@ -0,0 +1,103 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestLocalBindingLocalIP(t *testing.T) {
+	b := LocalBinding{IP: net.ParseIP("192.0.2.1")}
+	ip, err := b.LocalIP(net.ParseIP("198.51.100.1"))
+	if err != nil {
+		t.Fatalf("LocalIP() failed: %v", err)
+	}
+	if !ip.Equal(b.IP) {
+		t.Errorf("LocalIP() = %v, want %v", ip, b.IP)
+	}
+	if _, err := b.LocalIP(net.ParseIP("2001:db8::1")); err == nil {
+		t.Errorf("LocalIP() with IPv6 remote address succeeded, want error")
+	}
+	ip, err = LocalBinding{}.LocalIP(net.ParseIP("198.51.100.1"))
+	if err != nil || ip != nil {
+		t.Errorf("LocalIP() of zero binding = %v, %v, want nil, nil", ip, err)
+	}
+}
+
+func TestLocalBindingDialer(t *testing.T) {
+	base := &net.Dialer{}
+	if d := (LocalBinding{}).Dialer(base); d != base {
+		t.Errorf("Dialer() of zero binding is not the base dialer")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	d := LocalBinding{IP: net.ParseIP("127.0.0.1")}.Dialer(base)
+	conn, err := d.DialContext(context.Background(), "tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	if got := conn.LocalAddr().(*net.TCPAddr).IP; !got.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("local IP = %v, want 127.0.0.1", got)
+	}
+	if _, err := d.DialContext(context.Background(), "tcp", "[::1]:443"); err == nil {
+		t.Errorf("DialContext() to IPv6 address succeeded, want error")
+	}
+}
+
+func TestLocalBindingListenUDP(t *testing.T) {
+	b := LocalBinding{IP: net.ParseIP("127.0.0.1")}
+	conn, err := b.ListenUDP("udp", net.ParseIP("127.0.0.1"))
+	if err != nil {
+		t.Fatalf("ListenUDP() failed: %v", err)
+	}
+	defer conn.Close()
+	if got := conn.LocalAddr().(*net.UDPAddr).IP; !got.Equal(b.IP) {
+		t.Errorf("local IP = %v, want %v", got, b.IP)
+	}
+}
+
+func TestLocalBindingString(t *testing.T) {
+	testCases := []struct {
+		binding LocalBinding
+		want    string
+	}{
+		{LocalBinding{}, "none"},
+		{LocalBinding{Interface: "eth1"}, "interface eth1"},
+		{LocalBinding{Interface: "eth1", IP: net.ParseIP("192.0.2.1"), Mark: 100}, "interface eth1, IP 192.0.2.1, mark 100"},
+	}
+	for _, tc := range testCases {
+		if got := tc.binding.String(); got != tc.want {
+			t.Errorf("String() = %q, want %q", got, tc.want)
+		}
+	}
+}
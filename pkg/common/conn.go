@@ -79,8 +79,10 @@ type hierarchyConn struct {
 }
 
 var (
-	_ HierarchyConn = (*hierarchyConn)(nil)
-	_ UserContext   = (*hierarchyConn)(nil)
+	_ HierarchyConn  = (*hierarchyConn)(nil)
+	_ UserContext    = (*hierarchyConn)(nil)
+	_ SessionContext = (*hierarchyConn)(nil)
+	_ HandshakeConn  = (*hierarchyConn)(nil)
 )
 
 func (h *hierarchyConn) Close() error {
@@ -112,6 +114,17 @@ func (h *hierarchyConn) UserName() string {
 	return ""
 }
 
+func (h *hierarchyConn) HandshakeDone() {
+	HandshakeDone(h.Conn)
+}
+
+func (h *hierarchyConn) SessionID() uint32 {
+	if sessionCtx, ok := h.Conn.(SessionContext); ok {
+		return sessionCtx.SessionID()
+	}
+	return 0
+}
+
 // WrapHierarchyConn wraps an existing connection with HierarchyConn.
 func WrapHierarchyConn(conn net.Conn) HierarchyConn {
 	return &hierarchyConn{Conn: conn}
@@ -17,18 +17,23 @@ package common
 
 import (
 	"io"
+	"net"
 )
 
 // BidiCopy does bi-directional data copy.
+//
+// If both connections are TCP connections, the data is moved by the
+// operating system kernel when it is supported, without copying
+// through user space buffers.
 func BidiCopy(conn1, conn2 io.ReadWriteCloser) error {
 	errCh := make(chan error, 2)
 	go func() {
-		_, err := io.Copy(conn1, conn2)
+		_, err := copyConn(conn1, conn2)
 		conn1.Close()
 		errCh <- err
 	}()
 	go func() {
-		_, err := io.Copy(conn2, conn1)
+		_, err := copyConn(conn2, conn1)
 		conn2.Close()
 		errCh <- err
 	}()
@@ -37,3 +42,16 @@ func BidiCopy(conn1, conn2 io.ReadWriteCloser) error {
 	<-errCh
 	return err
 }
+
+// copyConn copies from src to dst until either EOF is reached on src
+// or an error occurs. It uses splice when possible.
+func copyConn(dst io.Writer, src io.Reader) (int64, error) {
+	dstTCP, ok1 := dst.(*net.TCPConn)
+	srcTCP, ok2 := src.(*net.TCPConn)
+	if ok1 && ok2 {
+		if n, handled, err := spliceTCP(dstTCP, srcTCP); handled {
+			return n, err
+		}
+	}
+	return io.Copy(dst, src)
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"io"
+	"net"
+	"runtime"
+	"testing"
+)
+
+// tcpConnPair returns two connected TCP connections.
+func tcpConnPair(t *testing.T) (*net.TCPConn, *net.TCPConn) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer l.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, _ := l.Accept()
+		accepted <- conn
+	}()
+	dialed, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	conn := <-accepted
+	if conn == nil {
+		t.Fatalf("Accept() failed")
+	}
+	return dialed.(*net.TCPConn), conn.(*net.TCPConn)
+}
+
+func TestBidiCopyTCP(t *testing.T) {
+	// client <-> proxyIn [BidiCopy] proxyOut <-> server
+	client, proxyIn := tcpConnPair(t)
+	proxyOut, server := tcpConnPair(t)
+	defer client.Close()
+	defer server.Close()
+	go BidiCopy(proxyIn, proxyOut)
+
+	data := make([]byte, 1024*1024)
+	if _, err := crand.Read(data); err != nil {
+		t.Fatalf("crand.Read() failed: %v", err)
+	}
+	go func() {
+		// Echo the data back.
+		io.Copy(server, server)
+	}()
+	go client.Write(data)
+	got := make([]byte, len(data))
+	if _, err := io.ReadFull(client, got); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("data is changed after copy")
+	}
+}
+
+func TestSpliceTCP(t *testing.T) {
+	src, srcPeer := tcpConnPair(t)
+	dst, dstPeer := tcpConnPair(t)
+	defer src.Close()
+	defer dst.Close()
+	defer dstPeer.Close()
+
+	data := []byte("splice test")
+	go func() {
+		srcPeer.Write(data)
+		srcPeer.Close()
+	}()
+	n, handled, err := spliceTCP(dst, src)
+	if handled != (runtime.GOOS == "linux") {
+		t.Fatalf("spliceTCP() handled = %v on %s", handled, runtime.GOOS)
+	}
+	if !handled {
+		return
+	}
+	if err != nil {
+		t.Fatalf("spliceTCP() failed: %v", err)
+	}
+	if n != int64(len(data)) {
+		t.Errorf("spliceTCP() moved %d bytes, want %d", n, len(data))
+	}
+	got := make([]byte, len(data))
+	if _, err := io.ReadFull(dstPeer, got); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %q, want %q", got, data)
+	}
+}
+
+func TestBidiCopyFallback(t *testing.T) {
+	// client <-> proxyIn [BidiCopy] proxyOut <-> server
+	// proxyIn is not a TCP connection, so splice is not used.
+	client, proxyIn := net.Pipe()
+	proxyOut, server := tcpConnPair(t)
+	defer client.Close()
+	defer server.Close()
+	go BidiCopy(proxyIn, proxyOut)
+
+	data := make([]byte, 1024*1024)
+	if _, err := crand.Read(data); err != nil {
+		t.Fatalf("crand.Read() failed: %v", err)
+	}
+	go func() {
+		// Echo the data back.
+		io.Copy(server, server)
+	}()
+	go client.Write(data)
+	got := make([]byte, len(data))
+	if _, err := io.ReadFull(client, got); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("data is changed after copy")
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	dohContentType = "application/dns-message"
+	dohTimeout     = 10 * time.Second
+	dohMaxRespSize = 65535
+)
+
+// DoHResolver looks up IP addresses with DNS over HTTPS (RFC 8484).
+// It doesn't use the DNS resolver of the operating system.
+type DoHResolver struct {
+	// URL of the DoH server, e.g. https://1.1.1.1/dns-query.
+	// The host must be an IP address, so it is not needed to resolve
+	// the DoH server itself.
+	URL string
+
+	// Client sends the DoH requests. If nil, a default client is used.
+	Client *http.Client
+}
+
+var _ apicommon.DNSResolver = (*DoHResolver)(nil)
+
+// ValidateDoHURL returns an error if the URL can't be used by DoHResolver.
+func ValidateDoHURL(dohURL string) error {
+	u, err := url.Parse(dohURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse DoH URL %q: %w", dohURL, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("DoH URL %q scheme is not https", dohURL)
+	}
+	if net.ParseIP(u.Hostname()) == nil {
+		return fmt.Errorf("DoH URL %q host is not an IP address", dohURL)
+	}
+	return nil
+}
+
+// LookupIP implements the apicommon.DNSResolver interface.
+func (r *DoHResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	var qtypes []dnsmessage.Type
+	switch network {
+	case "ip":
+		qtypes = []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA}
+	case "ip4":
+		qtypes = []dnsmessage.Type{dnsmessage.TypeA}
+	case "ip6":
+		qtypes = []dnsmessage.Type{dnsmessage.TypeAAAA}
+	default:
+		return nil, net.UnknownNetworkError(network)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	var ips []net.IP
+	var firstErr error
+	for _, qtype := range qtypes {
+		result, err := r.query(ctx, host, qtype)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		ips = append(ips, result...)
+	}
+	if len(ips) == 0 {
+		if firstErr != nil {
+			return nil, firstErr
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, Server: r.URL, IsNotFound: true}
+	}
+	return ips, nil
+}
+
+// query sends a single DoH request and returns the IP addresses
+// in the answer section.
+func (r *DoHResolver) query(ctx context.Context, host string, qtype dnsmessage.Type) ([]net.IP, error) {
+	if !strings.HasSuffix(host, ".") {
+		host = host + "."
+	}
+	name, err := dnsmessage.NewName(host)
+	if err != nil {
+		return nil, fmt.Errorf("dnsmessage.NewName() failed: %w", err)
+	}
+	// The message ID is 0 as recommended by RFC 8484 section 4.1.
+	msg := dnsmessage.Message{
+		Header: dnsmessage.Header{RecursionDesired: true},
+		Questions: []dnsmessage.Question{
+			{Name: name, Type: qtype, Class: dnsmessage.ClassINET},
+		},
+	}
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("Pack() failed: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(packed))
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext() failed: %w", err)
+	}
+	req.Header.Set("Content-Type", dohContentType)
+	req.Header.Set("Accept", dohContentType)
+	client := r.Client
+	if client == nil {
+		client = &http.Client{
+			Timeout: dohTimeout,
+			Transport: &http.Transport{
+				Proxy: nil,
+			},
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("DoH request to %s failed: %w", r.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server %s returned HTTP status %d", r.URL, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dohMaxRespSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DoH response: %w", err)
+	}
+
+	var answer dnsmessage.Message
+	if err := answer.Unpack(body); err != nil {
+		return nil, fmt.Errorf("Unpack() failed: %w", err)
+	}
+	if answer.Header.RCode != dnsmessage.RCodeSuccess {
+		return nil, fmt.Errorf("DoH server %s returned DNS response code %v for %s", r.URL, answer.Header.RCode, host)
+	}
+	var ips []net.IP
+	for _, rr := range answer.Answers {
+		switch body := rr.Body.(type) {
+		case *dnsmessage.AResource:
+			ips = append(ips, net.IP(body.A[:]))
+		case *dnsmessage.AAAAResource:
+			ips = append(ips, net.IP(body.AAAA[:]))
+		}
+	}
+	return ips, nil
+}
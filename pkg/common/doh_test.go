@@ -0,0 +1,102 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestDoHResolver(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != dohContentType {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var msg dnsmessage.Message
+		if err := msg.Unpack(body); err != nil || len(msg.Questions) != 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		q := msg.Questions[0]
+		msg.Header.Response = true
+		if q.Name.String() != "mieru.example.com." {
+			msg.Header.RCode = dnsmessage.RCodeNameError
+		} else {
+			header := dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class, TTL: 60}
+			switch q.Type {
+			case dnsmessage.TypeA:
+				msg.Answers = append(msg.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}})
+			case dnsmessage.TypeAAAA:
+				aaaa := [16]byte{}
+				copy(aaaa[:], net.ParseIP("2001:db8::1"))
+				msg.Answers = append(msg.Answers, dnsmessage.Resource{Header: header, Body: &dnsmessage.AAAAResource{AAAA: aaaa}})
+			}
+		}
+		resp, err := msg.Pack()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", dohContentType)
+		w.Write(resp)
+	}))
+	defer server.Close()
+
+	if err := ValidateDoHURL(server.URL); err != nil {
+		t.Fatalf("ValidateDoHURL() failed: %v", err)
+	}
+	r := &DoHResolver{URL: server.URL, Client: server.Client()}
+	ips, err := r.LookupIP(context.Background(), "ip", "mieru.example.com")
+	if err != nil {
+		t.Fatalf("LookupIP() failed: %v", err)
+	}
+	if len(ips) != 2 || !ips[0].Equal(net.ParseIP("192.0.2.1")) || !ips[1].Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("LookupIP() = %v, want [192.0.2.1 2001:db8::1]", ips)
+	}
+	ips, err = r.LookupIP(context.Background(), "ip6", "mieru.example.com")
+	if err != nil || len(ips) != 1 {
+		t.Errorf("LookupIP() = %v, %v, want 1 IPv6 address", ips, err)
+	}
+	if _, err := r.LookupIP(context.Background(), "ip", "unknown.example.com"); err == nil {
+		t.Errorf("LookupIP() of unknown domain succeeded")
+	}
+}
+
+func TestValidateDoHURL(t *testing.T) {
+	testcases := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://1.1.1.1/dns-query", false},
+		{"https://[2606:4700:4700::1111]/dns-query", false},
+		{"http://1.1.1.1/dns-query", true},
+		{"https://cloudflare-dns.com/dns-query", true},
+		{"://", true},
+	}
+	for _, tc := range testcases {
+		if err := ValidateDoHURL(tc.url); (err != nil) != tc.wantErr {
+			t.Errorf("ValidateDoHURL(%q) error = %v, want error %v", tc.url, err, tc.wantErr)
+		}
+	}
+}
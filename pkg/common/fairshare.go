@@ -0,0 +1,191 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+const (
+	// fairShareActiveWindow determines how long a source is considered
+	// active after it last received data.
+	fairShareActiveWindow = time.Second
+
+	// fairShareMaxBurst is the maximum duration of traffic that a source
+	// can receive at once after it is idle.
+	fairShareMaxBurst = 100 * time.Millisecond
+)
+
+var (
+	// Number of source IP addresses receiving data recently.
+	FairShareActiveSources = metrics.RegisterMetric("fair share", "ActiveSources", metrics.GAUGE)
+
+	// Accumulated time in milliseconds that downloads are delayed.
+	FairShareDelayMillis = metrics.RegisterMetric("fair share", "DelayMillis", metrics.COUNTER)
+)
+
+// FairShareLimiter splits the download bandwidth equally among the
+// source IP addresses that are actively downloading. If only one source
+// is active, it can use the whole bandwidth.
+//
+// A single limiter is shared by all the proxy ingresses. An ingress that
+// forwards connections through a local socks5 server registers an alias,
+// so the traffic is counted for the original client instead of the ingress.
+// A nil limiter doesn't limit anything.
+type FairShareLimiter struct {
+	rate    float64 // total bytes per second
+	mu      sync.Mutex
+	sources map[string]*sourceBucket // source IP -> token bucket
+	aliases map[string]string        // local socket address -> original client address
+}
+
+// sourceBucket is the token bucket of a source IP address.
+type sourceBucket struct {
+	tokens     float64 // can be negative, which means the source is in debt
+	lastUpdate time.Time
+	lastActive time.Time
+}
+
+// NewFairShareLimiter returns a limiter of the total download bandwidth
+// in bytes per second. It returns nil if the bandwidth is not positive.
+func NewFairShareLimiter(bytesPerSecond int64) *FairShareLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &FairShareLimiter{
+		rate:    float64(bytesPerSecond),
+		sources: make(map[string]*sourceBucket),
+		aliases: make(map[string]string),
+	}
+}
+
+// Alias counts the traffic of connections from the local address as the
+// traffic of the client. The returned function removes the alias.
+// Clients without an IP address, such as unix domain socket clients,
+// are not aliased.
+func (l *FairShareLimiter) Alias(local, client net.Addr) func() {
+	if l == nil || local == nil || client == nil {
+		return func() {}
+	}
+	if _, _, err := net.SplitHostPort(client.String()); err != nil {
+		return func() {}
+	}
+	key := local.String()
+	l.mu.Lock()
+	l.aliases[key] = client.String()
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		delete(l.aliases, key)
+		l.mu.Unlock()
+	}
+}
+
+// Wait blocks until the client at the address is allowed to receive n bytes.
+func (l *FairShareLimiter) Wait(client net.Addr, n int) {
+	if l == nil || client == nil {
+		return
+	}
+	if delay := l.reserve(l.source(client), n, time.Now()); delay > 0 {
+		FairShareDelayMillis.Add(delay.Milliseconds())
+		time.Sleep(delay)
+	}
+}
+
+// source returns the IP address of the client, following the alias.
+func (l *FairShareLimiter) source(client net.Addr) string {
+	addr := client.String()
+	l.mu.Lock()
+	if original, found := l.aliases[addr]; found {
+		addr = original
+	}
+	l.mu.Unlock()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// reserve takes n bytes from the bucket of the source, and returns
+// how long the source should wait before the bytes are received.
+func (l *FairShareLimiter) reserve(source string, n int, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	active := 0
+	for key, b := range l.sources {
+		if key != source && now.Sub(b.lastActive) < fairShareActiveWindow {
+			active++
+		}
+	}
+	active++ // the source itself
+	FairShareActiveSources.Store(int64(active))
+	share := l.rate / float64(active)
+	burst := share * fairShareMaxBurst.Seconds()
+
+	// An inactive source is forgotten only after its debt is repaid,
+	// so a source can't reset its penalty by pausing for a moment.
+	for key, b := range l.sources {
+		if key != source && now.Sub(b.lastActive) >= fairShareActiveWindow && b.tokens+now.Sub(b.lastUpdate).Seconds()*share >= burst {
+			delete(l.sources, key)
+		}
+	}
+
+	b, found := l.sources[source]
+	if !found {
+		b = &sourceBucket{tokens: burst, lastUpdate: now}
+		l.sources[source] = b
+	}
+	b.lastActive = now
+	b.tokens += now.Sub(b.lastUpdate).Seconds() * share
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.lastUpdate = now
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / share * float64(time.Second))
+}
+
+// FairShareConn limits the speed of reading the download traffic of a client.
+type FairShareConn struct {
+	net.Conn
+	limiter *FairShareLimiter
+	client  net.Addr
+}
+
+// NewFairShareConn wraps the connection that carries the download traffic
+// of the client. It returns the connection itself if the limiter is nil.
+func NewFairShareConn(conn net.Conn, limiter *FairShareLimiter, client net.Addr) net.Conn {
+	if limiter == nil {
+		return conn
+	}
+	return &FairShareConn{Conn: conn, limiter: limiter, client: client}
+}
+
+func (c *FairShareConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.limiter.Wait(c.client, n)
+	}
+	return n, err
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFairShareLimiter(t *testing.T) {
+	l := NewFairShareLimiter(1000)
+	now := time.Now()
+
+	// A single source can use the whole bandwidth, with a small burst.
+	if delay := l.reserve("a", 100, now); delay != 0 {
+		t.Errorf("first reserve() delay = %v, want 0", delay)
+	}
+	if delay := l.reserve("a", 1000, now); delay != time.Second {
+		t.Errorf("reserve() delay = %v, want %v", delay, time.Second)
+	}
+
+	// When another source becomes active, the bandwidth is shared.
+	now = now.Add(500 * time.Millisecond)
+	if delay := l.reserve("b", 100, now); delay != 100*time.Millisecond {
+		t.Errorf("reserve() delay = %v, want %v", delay, 100*time.Millisecond)
+	}
+	if delay := l.reserve("a", 500, now); delay != 2500*time.Millisecond {
+		t.Errorf("reserve() delay = %v, want %v", delay, 2500*time.Millisecond)
+	}
+
+	// Idle sources don't take a share.
+	now = now.Add(3 * time.Second)
+	l.reserve("a", 0, now)
+	if delay := l.reserve("a", 1000, now); delay != 900*time.Millisecond {
+		t.Errorf("reserve() delay = %v, want %v", delay, 900*time.Millisecond)
+	}
+	if _, found := l.sources["b"]; found {
+		t.Errorf("idle source is not removed")
+	}
+}
+
+func TestFairShareLimiterKeepDebt(t *testing.T) {
+	l := NewFairShareLimiter(1000)
+	now := time.Now()
+
+	// Source a gets into debt of 3 seconds.
+	l.reserve("a", 100, now)
+	if delay := l.reserve("a", 3000, now); delay != 3*time.Second {
+		t.Errorf("reserve() delay = %v, want %v", delay, 3*time.Second)
+	}
+
+	// Source a pauses, while source b is downloading.
+	now = now.Add(1500 * time.Millisecond)
+	l.reserve("b", 0, now)
+	if _, found := l.sources["a"]; !found {
+		t.Fatalf("source in debt is removed")
+	}
+
+	// Source a still needs to repay the debt when it comes back.
+	if delay := l.reserve("a", 0, now); delay != 4500*time.Millisecond {
+		t.Errorf("reserve() delay = %v, want %v", delay, 4500*time.Millisecond)
+	}
+
+	// The bucket is removed after the debt is repaid.
+	now = now.Add(10 * time.Second)
+	l.reserve("b", 0, now)
+	if _, found := l.sources["a"]; found {
+		t.Errorf("source without debt is not removed")
+	}
+}
+
+func TestFairShareLimiterAlias(t *testing.T) {
+	l := NewFairShareLimiter(1000)
+	local := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 40000}
+	client := &net.TCPAddr{IP: net.ParseIP("192.168.1.10"), Port: 50000}
+
+	if got := l.source(local); got != "127.0.0.1" {
+		t.Errorf("source() = %q, want %q", got, "127.0.0.1")
+	}
+	remove := l.Alias(local, client)
+	if got := l.source(local); got != "192.168.1.10" {
+		t.Errorf("source() with alias = %q, want %q", got, "192.168.1.10")
+	}
+	remove()
+	if got := l.source(local); got != "127.0.0.1" {
+		t.Errorf("source() after alias is removed = %q, want %q", got, "127.0.0.1")
+	}
+}
+
+func TestNilFairShareLimiter(t *testing.T) {
+	l := NewFairShareLimiter(0)
+	if l != nil {
+		t.Fatalf("NewFairShareLimiter(0) = %v, want nil", l)
+	}
+	l.Alias(&net.TCPAddr{}, &net.TCPAddr{})()
+	l.Wait(&net.TCPAddr{}, 1<<20)
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	if conn := NewFairShareConn(c1, l, c2.LocalAddr()); conn != c1 {
+		t.Errorf("NewFairShareConn() with nil limiter wraps the connection")
+	}
+}
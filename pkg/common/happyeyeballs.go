@@ -0,0 +1,105 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+)
+
+// HappyEyeballsAttemptDelay is the time to wait for a connection attempt
+// before starting the next one, as recommended by RFC 8305.
+const HappyEyeballsAttemptDelay = 250 * time.Millisecond
+
+// DialHappyEyeballs connects to the addresses in order. A new connection
+// attempt starts when the previous one fails, or it doesn't finish within
+// the attempt delay. The first established connection is returned, and
+// the other attempts are canceled. If all attempts fail, the error of the
+// first attempt is returned.
+func DialHappyEyeballs(ctx context.Context, dialer apicommon.Dialer, network string, addrs []string, attemptDelay time.Duration) (net.Conn, error) {
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no address to dial")
+	}
+	if len(addrs) == 1 {
+		return dialer.DialContext(ctx, network, addrs[0])
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, len(addrs))
+	started := 0
+	startNext := func() {
+		addr := addrs[started]
+		started++
+		go func() {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			results <- result{conn: conn, err: err}
+		}()
+	}
+
+	startNext()
+	timer := time.NewTimer(attemptDelay)
+	defer timer.Stop()
+	var firstErr error
+	finished := 0
+	for finished < len(addrs) {
+		select {
+		case r := <-results:
+			finished++
+			if r.err == nil {
+				// Close the connections established by other attempts.
+				go func(pending int) {
+					for i := 0; i < pending; i++ {
+						if r := <-results; r.conn != nil {
+							r.conn.Close()
+						}
+					}
+				}(started - finished)
+				return r.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if started < len(addrs) {
+				startNext()
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(attemptDelay)
+			} else if finished == started {
+				return nil, firstErr
+			}
+		case <-timer.C:
+			if started < len(addrs) {
+				startNext()
+				timer.Reset(attemptDelay)
+			}
+		}
+	}
+	return nil, firstErr
+}
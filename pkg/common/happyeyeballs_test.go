@@ -0,0 +1,138 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDialer returns a pipe connection after the configured delay, or
+// the configured error.
+type fakeDialer struct {
+	delays map[string]time.Duration
+	errs   map[string]error
+
+	// ignoreCancel makes the dial complete even if the context is canceled.
+	ignoreCancel bool
+
+	mu     sync.Mutex
+	dialed []string
+	closed int
+}
+
+func (d *fakeDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.mu.Lock()
+	d.dialed = append(d.dialed, address)
+	d.mu.Unlock()
+	if d.ignoreCancel {
+		time.Sleep(d.delays[address])
+	} else {
+		select {
+		case <-time.After(d.delays[address]):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err := d.errs[address]; err != nil {
+		return nil, err
+	}
+	c1, c2 := net.Pipe()
+	c2.Close()
+	return &closeCountConn{Conn: c1, d: d}, nil
+}
+
+type closeCountConn struct {
+	net.Conn
+	d *fakeDialer
+}
+
+func (c *closeCountConn) Close() error {
+	c.d.mu.Lock()
+	c.d.closed++
+	c.d.mu.Unlock()
+	return c.Conn.Close()
+}
+
+func TestDialHappyEyeballsFallback(t *testing.T) {
+	// The first address hangs, the second address wins.
+	d := &fakeDialer{
+		delays: map[string]time.Duration{"a": time.Minute, "b": 0},
+	}
+	start := time.Now()
+	conn, err := DialHappyEyeballs(context.Background(), d, "tcp", []string{"a", "b"}, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("DialHappyEyeballs() failed: %v", err)
+	}
+	defer conn.Close()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("DialHappyEyeballs() took %v", elapsed)
+	}
+}
+
+func TestDialHappyEyeballsFailFast(t *testing.T) {
+	// The first address fails immediately, the second address is dialed
+	// without waiting for the attempt delay.
+	d := &fakeDialer{
+		errs: map[string]error{"a": errors.New("refused")},
+	}
+	start := time.Now()
+	conn, err := DialHappyEyeballs(context.Background(), d, "tcp", []string{"a", "b"}, time.Minute)
+	if err != nil {
+		t.Fatalf("DialHappyEyeballs() failed: %v", err)
+	}
+	defer conn.Close()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("DialHappyEyeballs() took %v", elapsed)
+	}
+}
+
+func TestDialHappyEyeballsAllFailed(t *testing.T) {
+	errA := errors.New("a failed")
+	d := &fakeDialer{
+		errs: map[string]error{"a": errA, "b": errors.New("b failed"), "c": errors.New("c failed")},
+	}
+	_, err := DialHappyEyeballs(context.Background(), d, "tcp", []string{"a", "b", "c"}, 10*time.Millisecond)
+	if !errors.Is(err, errA) {
+		t.Errorf("DialHappyEyeballs() error = %v, want %v", err, errA)
+	}
+	if len(d.dialed) != 3 {
+		t.Errorf("dialed %v, want 3 addresses", d.dialed)
+	}
+}
+
+func TestDialHappyEyeballsCloseLoser(t *testing.T) {
+	// Both attempts succeed. The connection that loses the race is closed.
+	d := &fakeDialer{
+		delays:       map[string]time.Duration{"a": 30 * time.Millisecond, "b": 0},
+		ignoreCancel: true,
+	}
+	conn, err := DialHappyEyeballs(context.Background(), d, "tcp", []string{"a", "b"}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("DialHappyEyeballs() failed: %v", err)
+	}
+	defer conn.Close()
+	time.Sleep(100 * time.Millisecond)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed != 1 {
+		t.Errorf("closed %d connections, want 1", d.closed)
+	}
+}
@@ -141,3 +141,47 @@ func isIPv6(addr string) bool {
 	}
 	return ip.To4() == nil
 }
+
+// SortIPsForHappyEyeballs returns the IP addresses in the order to make
+// connection attempts, as described in RFC 8305. Addresses not allowed by
+// the DualStackPreference are removed. The preferred address family goes
+// first, then the two address families are interleaved.
+func SortIPsForHappyEyeballs(ips []net.IP, strategy DualStackPreference) []net.IP {
+	var ipv4, ipv6 []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			ipv4 = append(ipv4, ip)
+		} else {
+			ipv6 = append(ipv6, ip)
+		}
+	}
+
+	var first, second []net.IP
+	switch strategy {
+	case PREFER_IPv4:
+		first, second = ipv4, ipv6
+	case PREFER_IPv6:
+		first, second = ipv6, ipv4
+	case ONLY_IPv4:
+		first = ipv4
+	case ONLY_IPv6:
+		first = ipv6
+	default:
+		if len(ips) > 0 && ips[0].To4() != nil {
+			first, second = ipv4, ipv6
+		} else {
+			first, second = ipv6, ipv4
+		}
+	}
+
+	sorted := make([]net.IP, 0, len(first)+len(second))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			sorted = append(sorted, first[i])
+		}
+		if i < len(second) {
+			sorted = append(sorted, second[i])
+		}
+	}
+	return sorted
+}
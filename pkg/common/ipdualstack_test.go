@@ -172,3 +172,40 @@ func TestSelectIPFromList(t *testing.T) {
 		}
 	}
 }
+
+func TestSortIPsForHappyEyeballs(t *testing.T) {
+	v4a := net.ParseIP("192.0.2.1")
+	v4b := net.ParseIP("192.0.2.2")
+	v6a := net.ParseIP("2001:db8::1")
+	v6b := net.ParseIP("2001:db8::2")
+	ips := []net.IP{v4a, v4b, v6a, v6b}
+
+	testcases := []struct {
+		name     string
+		ips      []net.IP
+		strategy DualStackPreference
+		want     []net.IP
+	}{
+		{"use first ip", ips, USE_FIRST_IP, []net.IP{v4a, v6a, v4b, v6b}},
+		{"use first ip ipv6", []net.IP{v6a, v4a, v4b}, USE_FIRST_IP, []net.IP{v6a, v4a, v4b}},
+		{"prefer ipv4", ips, PREFER_IPv4, []net.IP{v4a, v6a, v4b, v6b}},
+		{"prefer ipv6", ips, PREFER_IPv6, []net.IP{v6a, v4a, v6b, v4b}},
+		{"only ipv4", ips, ONLY_IPv4, []net.IP{v4a, v4b}},
+		{"only ipv6", ips, ONLY_IPv6, []net.IP{v6a, v6b}},
+		{"only ipv6 without ipv6", []net.IP{v4a}, ONLY_IPv6, []net.IP{}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := SortIPsForHappyEyeballs(tc.ips, tc.strategy)
+			if len(got) != len(tc.want) {
+				t.Fatalf("SortIPsForHappyEyeballs() = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tc.want[i]) {
+					t.Fatalf("SortIPsForHappyEyeballs() = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
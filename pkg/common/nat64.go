@@ -0,0 +1,171 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"context"
+	"net"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+)
+
+// AddressFamilies are the IP address families that can reach
+// the Internet from this machine.
+type AddressFamilies struct {
+	IPv4 bool
+	IPv6 bool
+}
+
+// IPv6Only returns true if the Internet is only reachable with IPv6.
+func (f AddressFamilies) IPv6Only() bool {
+	return f.IPv6 && !f.IPv4
+}
+
+// IPv4Only returns true if the Internet is only reachable with IPv4.
+func (f AddressFamilies) IPv4Only() bool {
+	return f.IPv4 && !f.IPv6
+}
+
+func (f AddressFamilies) String() string {
+	switch {
+	case f.IPv4 && f.IPv6:
+		return "IPv4 and IPv6"
+	case f.IPv4:
+		return "IPv4 only"
+	case f.IPv6:
+		return "IPv6 only"
+	default:
+		return "none"
+	}
+}
+
+// DetectAddressFamilies returns the IP address families that have a route
+// to the Internet. It doesn't send any packet.
+func DetectAddressFamilies() AddressFamilies {
+	return AddressFamilies{
+		IPv4: hasGlobalRoute("udp4", "8.8.8.8:53"),
+		IPv6: hasGlobalRoute("udp6", "[2001:4860:4860::8888]:53"),
+	}
+}
+
+// hasGlobalRoute returns true if the address is routable from a global
+// unicast address of this machine. Connecting a UDP socket only looks up
+// the route.
+func hasGlobalRoute(network, addr string) bool {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	udpAddr, ok := conn.LocalAddr().(*net.UDPAddr)
+	return ok && udpAddr.IP.IsGlobalUnicast()
+}
+
+// WellKnownNAT64Prefix is the well-known prefix 64:ff9b::/96 of RFC 6052.
+var WellKnownNAT64Prefix = &net.IPNet{IP: net.ParseIP("64:ff9b::"), Mask: net.CIDRMask(96, 128)}
+
+// nat64DiscoveryHost is the domain name to discover the NAT64 prefix
+// from a DNS64 resolver, as described in RFC 7050.
+const nat64DiscoveryHost = "ipv4only.arpa"
+
+// nat64DiscoveryIPs are the IPv4 addresses of nat64DiscoveryHost.
+var nat64DiscoveryIPs = []net.IP{net.IPv4(192, 0, 0, 170).To4(), net.IPv4(192, 0, 0, 171).To4()}
+
+// nat64PrefixLengths are the NAT64 prefix lengths allowed by RFC 6052.
+var nat64PrefixLengths = []int{96, 64, 56, 48, 40, 32}
+
+// DetectNAT64Prefix discovers the NAT64 prefix used by the DNS64 resolver,
+// as described in RFC 7050. It returns nil if the resolver doesn't
+// synthesize IPv6 addresses.
+func DetectNAT64Prefix(ctx context.Context, resolver apicommon.DNSResolver) (*net.IPNet, error) {
+	ips, err := resolver.LookupIP(ctx, "ip6", nat64DiscoveryHost)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, ip := range ips {
+		if prefix := extractNAT64Prefix(ip); prefix != nil {
+			return prefix, nil
+		}
+	}
+	return nil, nil
+}
+
+// extractNAT64Prefix returns the NAT64 prefix if the IPv6 address
+// embeds one of nat64DiscoveryIPs.
+func extractNAT64Prefix(ip net.IP) *net.IPNet {
+	if ip.To4() != nil || len(ip) != net.IPv6len {
+		return nil
+	}
+	for _, length := range nat64PrefixLengths {
+		prefix := &net.IPNet{IP: ip.Mask(net.CIDRMask(length, 128)), Mask: net.CIDRMask(length, 128)}
+		embedded := ExtractNAT64IPv4(prefix, ip)
+		for _, discoveryIP := range nat64DiscoveryIPs {
+			if embedded.Equal(discoveryIP) {
+				return prefix
+			}
+		}
+	}
+	return nil
+}
+
+// nat64IPv4Positions returns the byte positions of the IPv4 address
+// in an IPv6 address synthesized with the NAT64 prefix length.
+// Byte 8 (bits 64 to 71) is reserved by RFC 6052 and always skipped.
+func nat64IPv4Positions(length int) []int {
+	var positions []int
+	for i := length / 8; len(positions) < 4; i++ {
+		if i != 8 {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// SynthesizeNAT64 returns the IPv6 address that reaches the IPv4 address
+// through the NAT64 prefix, as described in RFC 6052. If the input is not
+// an IPv4 address, it is returned as is.
+func SynthesizeNAT64(prefix *net.IPNet, ip net.IP) net.IP {
+	ipv4 := ip.To4()
+	if ipv4 == nil {
+		return ip
+	}
+	length, _ := prefix.Mask.Size()
+	res := make(net.IP, net.IPv6len)
+	copy(res, prefix.IP.To16())
+	for i, pos := range nat64IPv4Positions(length) {
+		res[pos] = ipv4[i]
+	}
+	return res
+}
+
+// ExtractNAT64IPv4 returns the IPv4 address embedded in the IPv6 address
+// synthesized with the NAT64 prefix. It returns nil if the IPv6 address
+// is not in the prefix.
+func ExtractNAT64IPv4(prefix *net.IPNet, ip net.IP) net.IP {
+	if !prefix.Contains(ip) || ip.To4() != nil {
+		return nil
+	}
+	length, _ := prefix.Mask.Size()
+	ip = ip.To16()
+	res := make(net.IP, net.IPv4len)
+	for i, pos := range nat64IPv4Positions(length) {
+		res[i] = ip[pos]
+	}
+	return res
+}
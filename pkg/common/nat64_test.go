@@ -0,0 +1,112 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+type nat64Resolver map[string][]net.IP
+
+func (r nat64Resolver) LookupIP(_ context.Context, _, host string) ([]net.IP, error) {
+	if ips, ok := r[host]; ok {
+		return ips, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestSynthesizeNAT64(t *testing.T) {
+	// Examples from RFC 6052 section 2.4.
+	ipv4 := net.ParseIP("192.0.2.33")
+	testCases := []struct {
+		prefix string
+		want   string
+	}{
+		{"2001:db8::/32", "2001:db8:c000:221::"},
+		{"2001:db8:100::/40", "2001:db8:1c0:2:21::"},
+		{"2001:db8:122::/48", "2001:db8:122:c000:2:2100::"},
+		{"2001:db8:122:300::/56", "2001:db8:122:3c0:0:221::"},
+		{"2001:db8:122:344::/64", "2001:db8:122:344:c0:2:2100:0"},
+		{"2001:db8:122:344::/96", "2001:db8:122:344::192.0.2.33"},
+		{"64:ff9b::/96", "64:ff9b::192.0.2.33"},
+	}
+	for _, tc := range testCases {
+		_, prefix, err := net.ParseCIDR(tc.prefix)
+		if err != nil {
+			t.Fatalf("ParseCIDR() failed: %v", err)
+		}
+		got := SynthesizeNAT64(prefix, ipv4)
+		if !got.Equal(net.ParseIP(tc.want)) {
+			t.Errorf("SynthesizeNAT64(%s) = %v, want %s", tc.prefix, got, tc.want)
+		}
+		if back := ExtractNAT64IPv4(prefix, got); !back.Equal(ipv4) {
+			t.Errorf("ExtractNAT64IPv4(%s, %v) = %v, want %v", tc.prefix, got, back, ipv4)
+		}
+		if found := extractNAT64Prefix(SynthesizeNAT64(prefix, net.IPv4(192, 0, 0, 170))); found == nil || found.String() != prefix.String() {
+			t.Errorf("extractNAT64Prefix() = %v, want %v", found, prefix)
+		}
+	}
+
+	ipv6 := net.ParseIP("2001:db8::1")
+	if got := SynthesizeNAT64(WellKnownNAT64Prefix, ipv6); !got.Equal(ipv6) {
+		t.Errorf("SynthesizeNAT64() changed IPv6 address to %v", got)
+	}
+}
+
+func TestDetectNAT64Prefix(t *testing.T) {
+	prefix, err := DetectNAT64Prefix(context.Background(), nat64Resolver{})
+	if err != nil || prefix != nil {
+		t.Errorf("DetectNAT64Prefix() = %v, %v, want no prefix", prefix, err)
+	}
+
+	resolver := nat64Resolver{
+		nat64DiscoveryHost: {net.ParseIP("64:ff9b::c000:aa"), net.ParseIP("64:ff9b::c000:ab")},
+	}
+	prefix, err = DetectNAT64Prefix(context.Background(), resolver)
+	if err != nil {
+		t.Fatalf("DetectNAT64Prefix() failed: %v", err)
+	}
+	if prefix == nil || prefix.String() != WellKnownNAT64Prefix.String() {
+		t.Errorf("DetectNAT64Prefix() = %v, want %v", prefix, WellKnownNAT64Prefix)
+	}
+}
+
+func TestAddressFamilies(t *testing.T) {
+	testCases := []struct {
+		families AddressFamilies
+		ipv6Only bool
+		ipv4Only bool
+		str      string
+	}{
+		{AddressFamilies{IPv4: true, IPv6: true}, false, false, "IPv4 and IPv6"},
+		{AddressFamilies{IPv4: true}, false, true, "IPv4 only"},
+		{AddressFamilies{IPv6: true}, true, false, "IPv6 only"},
+		{AddressFamilies{}, false, false, "none"},
+	}
+	for _, tc := range testCases {
+		if got := tc.families.IPv6Only(); got != tc.ipv6Only {
+			t.Errorf("%v IPv6Only() = %v, want %v", tc.families, got, tc.ipv6Only)
+		}
+		if got := tc.families.IPv4Only(); got != tc.ipv4Only {
+			t.Errorf("%v IPv4Only() = %v, want %v", tc.families, got, tc.ipv4Only)
+		}
+		if got := tc.families.String(); got != tc.str {
+			t.Errorf("String() = %q, want %q", got, tc.str)
+		}
+	}
+}
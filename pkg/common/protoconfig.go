@@ -0,0 +1,93 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFormat is the file format of a configuration.
+type ConfigFormat string
+
+const (
+	ConfigFormatJSON ConfigFormat = "JSON"
+	ConfigFormatYAML ConfigFormat = "YAML"
+	ConfigFormatTOML ConfigFormat = "TOML"
+)
+
+// DetectConfigFormat returns the format of the configuration. The file
+// extension of path is used if it is known. Otherwise the format is
+// guessed from the content.
+func DetectConfigFormat(path string, b []byte) ConfigFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ConfigFormatJSON
+	case ".yaml", ".yml":
+		return ConfigFormatYAML
+	case ".toml":
+		return ConfigFormatTOML
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		return ConfigFormatJSON
+	}
+	// A YAML mapping is never a valid TOML document, but a TOML document
+	// may be parsed as a YAML string.
+	var v map[string]any
+	if _, err := toml.Decode(string(b), &v); err == nil {
+		return ConfigFormatTOML
+	}
+	return ConfigFormatYAML
+}
+
+// UnmarshalConfig writes protobuf based on JSON, YAML or TOML data.
+// The format is detected by DetectConfigFormat. Field names of YAML and
+// TOML are the same as the JSON representation.
+func UnmarshalConfig(path string, b []byte, m protoreflect.ProtoMessage) error {
+	format := DetectConfigFormat(path, b)
+	if format == ConfigFormatJSON {
+		return UnmarshalJSON(b, m)
+	}
+	var v map[string]any
+	switch format {
+	case ConfigFormatYAML:
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return fmt.Errorf("invalid YAML: %w", err)
+		}
+	case ConfigFormatTOML:
+		if _, err := toml.Decode(string(b), &v); err != nil {
+			return fmt.Errorf("invalid TOML: %w", err)
+		}
+	}
+	if v == nil {
+		v = map[string]any{}
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("unable to convert %s to JSON: %w", format, err)
+	}
+	if err := UnmarshalJSON(j, m); err != nil {
+		return fmt.Errorf("invalid %s: %w", format, err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+const testClientConfigTOML = `activeProfile = "default"
+socks5Port = 1080
+socks5ListenLAN = false
+
+[failover]
+
+[[profiles]]
+profileName = "default"
+
+[profiles.user]
+name = "alice"
+password = "<secret>"
+
+[[profiles.servers]]
+ipAddress = "1.2.3.4"
+portBindings = [{ port = 8964, protocol = "TCP" }]
+`
+
+func TestDetectConfigFormat(t *testing.T) {
+	testCases := []struct {
+		path    string
+		content string
+		want    ConfigFormat
+	}{
+		{"config.json", "", ConfigFormatJSON},
+		{"config.YAML", "", ConfigFormatYAML},
+		{"config.yml", "", ConfigFormatYAML},
+		{"config.toml", "", ConfigFormatTOML},
+		{"config", "  {\"socks5Port\": 1080}", ConfigFormatJSON},
+		{"config", "socks5Port: 1080\n", ConfigFormatYAML},
+		{"config", "socks5Port = 1080\n", ConfigFormatTOML},
+		{"config.txt", testClientConfigTOML, ConfigFormatTOML},
+	}
+	for _, tc := range testCases {
+		if got := DetectConfigFormat(tc.path, []byte(tc.content)); got != tc.want {
+			t.Errorf("DetectConfigFormat(%q, %q) = %s, want %s", tc.path, tc.content, got, tc.want)
+		}
+	}
+}
+
+func TestUnmarshalConfig(t *testing.T) {
+	b, err := MarshalYAML(testClientConfig)
+	if err != nil {
+		t.Fatalf("MarshalYAML() failed: %v", err)
+	}
+	testCases := []struct {
+		path    string
+		content []byte
+	}{
+		{"config.yaml", b},
+		{"config", b},
+		{"config.toml", []byte(testClientConfigTOML)},
+		{"config", []byte(testClientConfigTOML)},
+	}
+	for _, tc := range testCases {
+		got := &appctlpb.ClientConfig{}
+		if err := UnmarshalConfig(tc.path, tc.content, got); err != nil {
+			t.Fatalf("UnmarshalConfig(%q) failed: %v", tc.path, err)
+		}
+		if !proto.Equal(got, testClientConfig) {
+			t.Errorf("UnmarshalConfig(%q) = %v, want %v", tc.path, got, testClientConfig)
+		}
+	}
+}
+
+func TestUnmarshalConfigUnknownField(t *testing.T) {
+	got := &appctlpb.ClientConfig{}
+	if err := UnmarshalConfig("config.yaml", []byte("socks5Port: 1080\nunknownField: 1\n"), got); err == nil {
+		t.Errorf("UnmarshalConfig() with unknown field succeeded, want error")
+	}
+	if err := UnmarshalConfig("config.toml", []byte("socks5Port = "), got); err == nil {
+		t.Errorf("UnmarshalConfig() with invalid TOML succeeded, want error")
+	}
+}
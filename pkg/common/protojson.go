@@ -16,6 +16,9 @@
 package common
 
 import (
+	"bytes"
+	"encoding/json"
+
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
@@ -49,3 +52,37 @@ func MarshalJSON(m protoreflect.ProtoMessage) ([]byte, error) {
 func UnmarshalJSON(b []byte, m protoreflect.ProtoMessage) error {
 	return jsonUnmarshalOption.Unmarshal(b, m)
 }
+
+// MarshalCanonicalJSON returns a stable JSON representation of protobuf.
+// Object keys are sorted, and the output is indented with 4 spaces.
+// The same protobuf always produces the same output.
+func MarshalCanonicalJSON(m protoreflect.ProtoMessage) ([]byte, error) {
+	v, err := protoToGeneric(m)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// protoToGeneric converts protobuf to JSON values like map[string]any,
+// []any, string, json.Number, bool and nil.
+func protoToGeneric(m protoreflect.ProtoMessage) (any, error) {
+	b, err := jsonMarshalOption.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+var testClientConfig = &appctlpb.ClientConfig{
+	Profiles: []*appctlpb.ClientProfile{
+		{
+			ProfileName: proto.String("default"),
+			User: &appctlpb.User{
+				Name:     proto.String("alice"),
+				Password: proto.String("<secret>"),
+			},
+			Servers: []*appctlpb.ServerEndpoint{
+				{
+					IpAddress: proto.String("1.2.3.4"),
+					PortBindings: []*appctlpb.PortBinding{
+						{Port: proto.Int32(8964), Protocol: appctlpb.TransportProtocol_TCP.Enum()},
+					},
+				},
+			},
+		},
+	},
+	ActiveProfile:   proto.String("default"),
+	Socks5Port:      proto.Int32(1080),
+	Socks5ListenLAN: proto.Bool(false),
+	Failover:        &appctlpb.ProfileFailover{},
+}
+
+func TestMarshalCanonicalJSON(t *testing.T) {
+	want := `{
+    "activeProfile": "default",
+    "failover": {},
+    "profiles": [
+        {
+            "profileName": "default",
+            "servers": [
+                {
+                    "ipAddress": "1.2.3.4",
+                    "portBindings": [
+                        {
+                            "port": 8964,
+                            "protocol": "TCP"
+                        }
+                    ]
+                }
+            ],
+            "user": {
+                "name": "alice",
+                "password": "<secret>"
+            }
+        }
+    ],
+    "socks5ListenLAN": false,
+    "socks5Port": 1080
+}
+`
+	for i := 0; i < 10; i++ {
+		b, err := MarshalCanonicalJSON(testClientConfig)
+		if err != nil {
+			t.Fatalf("MarshalCanonicalJSON() failed: %v", err)
+		}
+		if string(b) != want {
+			t.Fatalf("MarshalCanonicalJSON() = %s, want %s", b, want)
+		}
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	want := `activeProfile: "default"
+failover: {}
+profiles:
+  - profileName: "default"
+    servers:
+      - ipAddress: "1.2.3.4"
+        portBindings:
+          - port: 8964
+            protocol: "TCP"
+    user:
+      name: "alice"
+      password: "<secret>"
+socks5ListenLAN: false
+socks5Port: 1080
+`
+	b, err := MarshalYAML(testClientConfig)
+	if err != nil {
+		t.Fatalf("MarshalYAML() failed: %v", err)
+	}
+	if string(b) != want {
+		t.Errorf("MarshalYAML() = %s, want %s", b, want)
+	}
+}
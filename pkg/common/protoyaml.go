@@ -0,0 +1,144 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MarshalYAML returns a stable YAML representation of protobuf.
+// Field names are the same as the JSON representation. Keys are sorted,
+// and strings are always double quoted.
+func MarshalYAML(m protoreflect.ProtoMessage) ([]byte, error) {
+	v, err := protoToGeneric(m)
+	if err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	if err := writeYAML(&sb, v, 0); err != nil {
+		return nil, err
+	}
+	return []byte(sb.String()), nil
+}
+
+// writeYAML writes the YAML block of a JSON value.
+// Each line of the block starts with the indent.
+func writeYAML(sb *strings.Builder, v any, indent int) error {
+	prefix := strings.Repeat(" ", indent)
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			sb.WriteString(prefix + "{}\n")
+			return nil
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := val[k]
+			if isYAMLBlock(child) {
+				sb.WriteString(prefix + k + ":\n")
+				if err := writeYAML(sb, child, indent+2); err != nil {
+					return err
+				}
+			} else {
+				s, err := yamlScalar(child)
+				if err != nil {
+					return err
+				}
+				sb.WriteString(prefix + k + ": " + s + "\n")
+			}
+		}
+	case []any:
+		if len(val) == 0 {
+			sb.WriteString(prefix + "[]\n")
+			return nil
+		}
+		for _, item := range val {
+			if isYAMLBlock(item) {
+				// Write the item with a deeper indent, then replace
+				// the indent of the first line with the list marker.
+				var itemSB strings.Builder
+				if err := writeYAML(&itemSB, item, indent+2); err != nil {
+					return err
+				}
+				sb.WriteString(prefix + "- " + strings.TrimPrefix(itemSB.String(), prefix+"  "))
+			} else {
+				s, err := yamlScalar(item)
+				if err != nil {
+					return err
+				}
+				sb.WriteString(prefix + "- " + s + "\n")
+			}
+		}
+	default:
+		s, err := yamlScalar(val)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(prefix + s + "\n")
+	}
+	return nil
+}
+
+// isYAMLBlock returns true if the value is a non-empty map or list.
+func isYAMLBlock(v any) bool {
+	switch val := v.(type) {
+	case map[string]any:
+		return len(val) > 0
+	case []any:
+		return len(val) > 0
+	default:
+		return false
+	}
+}
+
+func yamlScalar(v any) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		if val {
+			return "true", nil
+		}
+		return "false", nil
+	case json.Number:
+		return val.String(), nil
+	case string:
+		// A JSON string is also a valid YAML double quoted string.
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(val); err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	case map[string]any:
+		return "{}", nil
+	case []any:
+		return "[]", nil
+	default:
+		return "", fmt.Errorf("unsupported YAML value type %T", v)
+	}
+}
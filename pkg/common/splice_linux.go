@@ -0,0 +1,99 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+
+package common
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// maxSpliceSize is the maximum number of bytes moved by a single splice call.
+// It is the default capacity of a pipe.
+const maxSpliceSize = 64 * 1024
+
+// spliceTCP moves data from src to dst through a pipe with splice(2),
+// so the data doesn't go through user space. It returns handled = false
+// if splice can't be used before any data is moved, and the caller
+// should fall back to a regular copy.
+func spliceTCP(dst, src *net.TCPConn) (written int64, handled bool, err error) {
+	srcRaw, err := src.SyscallConn()
+	if err != nil {
+		return 0, false, nil
+	}
+	dstRaw, err := dst.SyscallConn()
+	if err != nil {
+		return 0, false, nil
+	}
+	var pipe [2]int
+	if err := unix.Pipe2(pipe[:], unix.O_CLOEXEC|unix.O_NONBLOCK); err != nil {
+		return 0, false, nil
+	}
+	defer unix.Close(pipe[0])
+	defer unix.Close(pipe[1])
+
+	for {
+		// Move data from the source socket to the pipe.
+		var n int64
+		var spliceErr error
+		if err := srcRaw.Read(func(fd uintptr) bool {
+			n, spliceErr = splice(int(fd), pipe[1], maxSpliceSize)
+			return spliceErr != unix.EAGAIN
+		}); err != nil {
+			return written, true, err
+		}
+		if spliceErr != nil {
+			if written == 0 && (spliceErr == unix.EINVAL || spliceErr == unix.ENOSYS) {
+				return 0, false, nil
+			}
+			return written, true, spliceErr
+		}
+		if n == 0 {
+			// EOF.
+			return written, true, nil
+		}
+
+		// Move all the data in the pipe to the destination socket.
+		for n > 0 {
+			var m int64
+			if err := dstRaw.Write(func(fd uintptr) bool {
+				m, spliceErr = splice(pipe[0], int(fd), int(n))
+				return spliceErr != unix.EAGAIN
+			}); err != nil {
+				return written, true, err
+			}
+			if spliceErr != nil {
+				return written, true, spliceErr
+			}
+			n -= m
+			written += m
+		}
+	}
+}
+
+// splice moves up to n bytes from rfd to wfd without blocking.
+// It retries if the call is interrupted by a signal.
+func splice(rfd, wfd, n int) (int64, error) {
+	for {
+		// The type of the returned size depends on the architecture.
+		m, err := unix.Splice(rfd, nil, wfd, nil, n, unix.SPLICE_F_MOVE|unix.SPLICE_F_NONBLOCK)
+		if err != unix.EINTR {
+			return int64(m), err
+		}
+	}
+}
@@ -21,4 +21,9 @@ const (
 	UnknownTransport TransportProtocol = iota
 	StreamTransport
 	PacketTransport
+
+	// WebSocketTransport is a stream transport tunneled inside WebSocket.
+	// It is only used by endpoints. Underlays created from the endpoints
+	// use StreamTransport.
+	WebSocketTransport
 )
@@ -0,0 +1,64 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// DefaultUnixSocketMode is the file permission of a unix domain socket
+// if it is not configured.
+const DefaultUnixSocketMode os.FileMode = 0600
+
+// ParseUnixSocketMode parses the file permission of a unix domain socket
+// in octal, e.g. "0660". If the value is empty, DefaultUnixSocketMode
+// is returned.
+func ParseUnixSocketMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return DefaultUnixSocketMode, nil
+	}
+	v, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || v > 0777 {
+		return 0, fmt.Errorf("unix domain socket mode %q is not an octal file permission", mode)
+	}
+	return os.FileMode(v), nil
+}
+
+// ListenUnixSocket listens to a unix domain socket and sets its file
+// permission. A socket file left by a previous process is removed,
+// but other types of files at the path are not touched.
+func ListenUnixSocket(path string, mode os.FileMode) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%q exists and it is not a unix domain socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("os.Remove() failed: %w", err)
+		}
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("net.Listen() failed: %w", err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("os.Chmod() failed: %w", err)
+	}
+	return l, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseUnixSocketMode(t *testing.T) {
+	testcases := []struct {
+		input   string
+		want    os.FileMode
+		wantErr bool
+	}{
+		{"", DefaultUnixSocketMode, false},
+		{"0660", 0660, false},
+		{"777", 0777, false},
+		{"0800", 0, true},
+		{"01777", 0, true},
+		{"rw-rw----", 0, true},
+	}
+	for _, tc := range testcases {
+		got, err := ParseUnixSocketMode(tc.input)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseUnixSocketMode(%q) error = %v, want error %v", tc.input, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseUnixSocketMode(%q) = %o, want %o", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestListenUnixSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permission of unix domain socket is not supported on windows")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "proxy.sock")
+
+	// A socket file left by a previous listener is replaced.
+	old, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	old.(*net.UnixListener).SetUnlinkOnClose(false)
+	old.Close()
+
+	l, err := ListenUnixSocket(path, 0660)
+	if err != nil {
+		t.Fatalf("ListenUnixSocket() failed: %v", err)
+	}
+	defer l.Close()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("os.Stat() failed: %v", err)
+	}
+	if info.Mode().Perm() != 0660 {
+		t.Errorf("socket permission = %o, want %o", info.Mode().Perm(), 0660)
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	conn.Close()
+
+	// A regular file is not removed.
+	regular := filepath.Join(dir, "regular")
+	if err := os.WriteFile(regular, []byte("data"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	if _, err := ListenUnixSocket(regular, 0600); err == nil {
+		t.Errorf("ListenUnixSocket() on a regular file returned no error")
+	}
+	if _, err := os.Stat(regular); err != nil {
+		t.Errorf("regular file is removed: %v", err)
+	}
+}
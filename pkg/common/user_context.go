@@ -20,3 +20,17 @@ type UserContext interface {
 	// UserName returns the user name from the context.
 	UserName() string
 }
+
+// SessionContext provides the ID of a proxy session.
+type SessionContext interface {
+	// SessionID returns the ID of the proxy session. The proxy client
+	// and the proxy server use the same ID for a session.
+	SessionID() uint32
+}
+
+// DestinationContext records the destination of a proxy session.
+type DestinationContext interface {
+	// SetDestination records the destination of the proxy request
+	// in the session.
+	SetDestination(destination string)
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package geoip looks up the country of IP addresses.
+package geoip
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// UnknownCountry is returned when the country of an IP address
+// is not found from the database.
+const UnknownCountry = "(unknown)"
+
+// Database maps IP address ranges to ISO 3166-1 alpha-2 country codes.
+type Database struct {
+	ranges []ipRange   // sorted by the first IP address
+	mmdb   *mmdbReader // set if the database is in MaxMind DB format
+}
+
+type ipRange struct {
+	first   net.IP // 16 bytes
+	last    net.IP // 16 bytes
+	country string
+}
+
+// Load reads a GeoIP database from a MaxMind DB (.mmdb) file, such as
+// GeoLite2-Country.mmdb, or from a CSV file. See Parse for the CSV format.
+func Load(path string) (*Database, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile() failed: %w", err)
+	}
+	if isMMDB(b) {
+		r, err := parseMMDB(b)
+		if err != nil {
+			return nil, err
+		}
+		return &Database{mmdb: r}, nil
+	}
+	return Parse(bytes.NewReader(b))
+}
+
+// Parse reads a GeoIP database in CSV format. Each record is
+//
+//	<first IP address>,<last IP address>,<country code>
+//
+// IPv4 and IPv6 ranges can be mixed. Additional columns are ignored,
+// and lines beginning with "#" are comments. This is compatible with
+// the free "IP to Country" CSV databases published by several vendors.
+func Parse(r io.Reader) (*Database, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	d := &Database{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Read() failed: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < 3 {
+			return nil, fmt.Errorf("line %d: expect at least 3 columns, got %d", line, len(record))
+		}
+		first := net.ParseIP(record[0])
+		last := net.ParseIP(record[1])
+		if first == nil || last == nil {
+			return nil, fmt.Errorf("line %d: invalid IP address range %s - %s", line, record[0], record[1])
+		}
+		if (first.To4() == nil) != (last.To4() == nil) {
+			return nil, fmt.Errorf("line %d: IP address range %s - %s mixes IPv4 and IPv6", line, record[0], record[1])
+		}
+		first, last = first.To16(), last.To16()
+		if bytes.Compare(first, last) > 0 {
+			return nil, fmt.Errorf("line %d: first IP address %s is greater than last IP address %s", line, record[0], record[1])
+		}
+		country := strings.ToUpper(record[2])
+		if !isCountryCode(country) {
+			return nil, fmt.Errorf("line %d: invalid country code %q", line, record[2])
+		}
+		d.ranges = append(d.ranges, ipRange{first: first, last: last, country: country})
+	}
+	sort.Slice(d.ranges, func(i, j int) bool {
+		return bytes.Compare(d.ranges[i].first, d.ranges[j].first) < 0
+	})
+	for i := 1; i < len(d.ranges); i++ {
+		if bytes.Compare(d.ranges[i-1].last, d.ranges[i].first) >= 0 {
+			return nil, fmt.Errorf("IP address range %v - %v overlaps with %v - %v", d.ranges[i-1].first, d.ranges[i-1].last, d.ranges[i].first, d.ranges[i].last)
+		}
+	}
+	return d, nil
+}
+
+// Len returns the number of IP address ranges in the database.
+// It is 0 if the database is in MaxMind DB format.
+func (d *Database) Len() int {
+	return len(d.ranges)
+}
+
+// Version describes the type and build time of a MaxMind DB database,
+// or the number of IP address ranges of a CSV database.
+func (d *Database) Version() string {
+	if d.mmdb != nil {
+		return d.mmdb.version()
+	}
+	return fmt.Sprintf("CSV with %d IP address ranges", len(d.ranges))
+}
+
+// Country returns the country code of the IP address.
+// If the IP address is not found, UnknownCountry is returned.
+func (d *Database) Country(ip net.IP) string {
+	if ip.To16() == nil {
+		return UnknownCountry
+	}
+	if d.mmdb != nil {
+		return d.mmdb.country(ip)
+	}
+	ip = ip.To16()
+	// Find the first range that begins after the IP address.
+	i := sort.Search(len(d.ranges), func(i int) bool {
+		return bytes.Compare(d.ranges[i].first, ip) > 0
+	})
+	if i == 0 || bytes.Compare(d.ranges[i-1].last, ip) < 0 {
+		return UnknownCountry
+	}
+	return d.ranges[i-1].country
+}
+
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package geoip
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDatabase = `# first,last,country
+1.0.0.0,1.0.0.255,AU
+1.0.1.0,1.0.3.255,cn
+8.8.8.0,8.8.8.255,US,Mountain View
+2001:db8::,2001:db8::ffff,JP
+`
+
+func TestCountry(t *testing.T) {
+	d, err := Parse(strings.NewReader(testDatabase))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if d.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", d.Len())
+	}
+	testcases := []struct {
+		ip   string
+		want string
+	}{
+		{"1.0.0.0", "AU"},
+		{"1.0.0.255", "AU"},
+		{"1.0.2.1", "CN"},
+		{"1.0.4.0", UnknownCountry},
+		{"0.255.255.255", UnknownCountry},
+		{"8.8.8.8", "US"},
+		{"2001:db8::1", "JP"},
+		{"2001:db8::1:0", UnknownCountry},
+		{"::1", UnknownCountry},
+	}
+	for _, tc := range testcases {
+		if got := d.Country(net.ParseIP(tc.ip)); got != tc.want {
+			t.Errorf("Country(%s) = %q, want %q", tc.ip, got, tc.want)
+		}
+	}
+	if got := d.Country(nil); got != UnknownCountry {
+		t.Errorf("Country(nil) = %q, want %q", got, UnknownCountry)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	testcases := []string{
+		"1.0.0.0,1.0.0.255\n",
+		"1.0.0.0,abc,AU\n",
+		"1.0.0.0,2001:db8::,AU\n",
+		"1.0.0.255,1.0.0.0,AU\n",
+		"1.0.0.0,1.0.0.255,AUS\n",
+		"1.0.0.0,1.0.0.255,AU\n1.0.0.128,1.0.1.0,CN\n",
+	}
+	for _, tc := range testcases {
+		if _, err := Parse(strings.NewReader(tc)); err == nil {
+			t.Errorf("Parse(%q) returned no error", tc)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geoip.csv")
+	if err := os.WriteFile(path, []byte(testDatabase), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	d, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if d.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", d.Len())
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "not-exist.csv")); err == nil {
+		t.Errorf("Load() returned no error for a missing file")
+	}
+}
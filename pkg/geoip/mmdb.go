@@ -0,0 +1,354 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"time"
+)
+
+// mmdbMetadataMarker begins the metadata section at the end of
+// a MaxMind DB file.
+var mmdbMetadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// mmdbDataSectionSeparator is the number of zero bytes between
+// the search tree and the data section.
+const mmdbDataSectionSeparator = 16
+
+// Types of the MaxMind DB data section.
+const (
+	mmdbExtended = 0
+	mmdbPointer  = 1
+	mmdbString   = 2
+	mmdbDouble   = 3
+	mmdbBytes    = 4
+	mmdbUint16   = 5
+	mmdbUint32   = 6
+	mmdbMap      = 7
+	mmdbInt32    = 8
+	mmdbUint64   = 9
+	mmdbUint128  = 10
+	mmdbArray    = 11
+	mmdbBool     = 14
+	mmdbFloat    = 15
+)
+
+// mmdbReader looks up IP addresses from a MaxMind DB file.
+// See https://maxmind.github.io/MaxMind-DB/ for the format.
+type mmdbReader struct {
+	tree         []byte
+	data         []byte
+	nodeCount    uint
+	recordSize   uint
+	ipVersion    uint
+	ipv4Start    uint // node to look up IPv4 addresses in an IPv6 tree
+	databaseType string
+	buildTime    time.Time
+}
+
+// isMMDB returns true if the content is a MaxMind DB file.
+func isMMDB(b []byte) bool {
+	return bytes.LastIndex(b, mmdbMetadataMarker) >= 0
+}
+
+// parseMMDB reads a MaxMind DB file. The content must not be modified
+// after this call.
+func parseMMDB(b []byte) (*mmdbReader, error) {
+	i := bytes.LastIndex(b, mmdbMetadataMarker)
+	if i < 0 {
+		return nil, fmt.Errorf("MaxMind DB metadata is not found")
+	}
+	metadataDecoder := mmdbDecoder{buf: b[i+len(mmdbMetadataMarker):]}
+	v, _, err := metadataDecoder.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode MaxMind DB metadata: %w", err)
+	}
+	metadata, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("MaxMind DB metadata is not a map")
+	}
+	r := &mmdbReader{
+		nodeCount:  mmdbUint(metadata["node_count"]),
+		recordSize: mmdbUint(metadata["record_size"]),
+		ipVersion:  mmdbUint(metadata["ip_version"]),
+	}
+	r.databaseType, _ = metadata["database_type"].(string)
+	if epoch := mmdbUint(metadata["build_epoch"]); epoch > 0 {
+		r.buildTime = time.Unix(int64(epoch), 0).UTC()
+	}
+	if r.recordSize != 24 && r.recordSize != 28 && r.recordSize != 32 {
+		return nil, fmt.Errorf("MaxMind DB record size %d is not supported", r.recordSize)
+	}
+	if r.ipVersion != 4 && r.ipVersion != 6 {
+		return nil, fmt.Errorf("MaxMind DB IP version %d is not supported", r.ipVersion)
+	}
+	treeSize := r.nodeCount * r.recordSize / 4
+	if treeSize+mmdbDataSectionSeparator > uint(i) {
+		return nil, fmt.Errorf("MaxMind DB search tree size %d exceeds the file size", treeSize)
+	}
+	r.tree = b[:treeSize]
+	r.data = b[treeSize+mmdbDataSectionSeparator : i]
+
+	// An IPv4 address is looked up in an IPv6 tree as ::a.b.c.d.
+	if r.ipVersion == 6 {
+		for j := 0; j < 96 && r.ipv4Start < r.nodeCount; j++ {
+			r.ipv4Start = r.readRecord(r.ipv4Start, 0)
+		}
+	}
+	return r, nil
+}
+
+// country returns the country code of the IP address.
+func (r *mmdbReader) country(ip net.IP) string {
+	node := uint(0)
+	bitCount := 128
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+		bitCount = 32
+		node = r.ipv4Start
+	} else if r.ipVersion == 4 {
+		return UnknownCountry
+	}
+	for i := 0; i < bitCount && node < r.nodeCount; i++ {
+		bit := uint(ip[i>>3]>>(7-uint(i&7))) & 1
+		node = r.readRecord(node, bit)
+	}
+	if node <= r.nodeCount {
+		return UnknownCountry
+	}
+	offset := node - r.nodeCount - mmdbDataSectionSeparator
+	decoder := mmdbDecoder{buf: r.data}
+	v, _, err := decoder.decode(offset)
+	if err != nil {
+		return UnknownCountry
+	}
+	record, ok := v.(map[string]interface{})
+	if !ok {
+		return UnknownCountry
+	}
+	// GeoIP2 and GeoLite2 databases store the country in
+	// {"country": {"iso_code": "US"}}. Some other vendors store it in
+	// {"country": "US"} or {"country_code": "US"}.
+	for _, key := range []string{"country", "registered_country", "country_code"} {
+		var code string
+		switch c := record[key].(type) {
+		case string:
+			code = c
+		case map[string]interface{}:
+			code, _ = c["iso_code"].(string)
+		}
+		code = strings.ToUpper(code)
+		if isCountryCode(code) {
+			return code
+		}
+	}
+	return UnknownCountry
+}
+
+// readRecord returns the left (bit 0) or right (bit 1) record of the node.
+func (r *mmdbReader) readRecord(node, bit uint) uint {
+	nodeSize := r.recordSize / 4
+	if (node+1)*nodeSize > uint(len(r.tree)) {
+		return r.nodeCount
+	}
+	b := r.tree[node*nodeSize : (node+1)*nodeSize]
+	switch r.recordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[bit*4:]))
+	}
+}
+
+func (r *mmdbReader) version() string {
+	if r.buildTime.IsZero() {
+		return r.databaseType
+	}
+	return r.databaseType + " built at " + r.buildTime.Format(time.RFC3339)
+}
+
+// mmdbDecoder decodes values from the data section of a MaxMind DB file.
+type mmdbDecoder struct {
+	buf []byte
+}
+
+// decode returns the value at the offset, and the offset of the next value.
+func (d *mmdbDecoder) decode(offset uint) (interface{}, uint, error) {
+	typ, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if typ == mmdbPointer {
+		pointer, next, err := d.decodePointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		if pointer < uint(len(d.buf)) && d.buf[pointer]>>5 == mmdbPointer {
+			return nil, 0, fmt.Errorf("pointer at offset %d points to another pointer", offset)
+		}
+		v, _, err := d.decode(pointer)
+		return v, next, err
+	}
+	return d.decodeValue(typ, size, offset)
+}
+
+func (d *mmdbDecoder) decodeControl(offset uint) (typ, size, next uint, err error) {
+	if offset >= uint(len(d.buf)) {
+		return 0, 0, 0, fmt.Errorf("offset %d is out of range", offset)
+	}
+	control := d.buf[offset]
+	offset++
+	typ = uint(control >> 5)
+	if typ == mmdbExtended {
+		if offset >= uint(len(d.buf)) {
+			return 0, 0, 0, fmt.Errorf("extended type at offset %d is truncated", offset)
+		}
+		typ = 7 + uint(d.buf[offset])
+		offset++
+	}
+	size = uint(control & 0x1F)
+	if typ == mmdbPointer || size < 29 {
+		return typ, size, offset, nil
+	}
+	n := size - 28
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, 0, fmt.Errorf("size at offset %d is truncated", offset)
+	}
+	b := d.buf[offset : offset+n]
+	switch n {
+	case 1:
+		size = 29 + uint(b[0])
+	case 2:
+		size = 285 + (uint(b[0])<<8 | uint(b[1]))
+	default:
+		size = 65821 + (uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]))
+	}
+	return typ, size, offset + n, nil
+}
+
+func (d *mmdbDecoder) decodePointer(size, offset uint) (uint, uint, error) {
+	n := (size>>3)&0x3 + 1
+	if offset+n > uint(len(d.buf)) {
+		return 0, 0, fmt.Errorf("pointer at offset %d is truncated", offset)
+	}
+	var pointer uint
+	if n != 4 {
+		pointer = size & 0x7
+	}
+	for _, c := range d.buf[offset : offset+n] {
+		pointer = pointer<<8 | uint(c)
+	}
+	switch n {
+	case 2:
+		pointer += 2048
+	case 3:
+		pointer += 526336
+	}
+	return pointer, offset + n, nil
+}
+
+func (d *mmdbDecoder) decodeValue(typ, size, offset uint) (interface{}, uint, error) {
+	switch typ {
+	case mmdbMap:
+		m := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			k, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("map key at offset %d is not a string", offset)
+			}
+			m[key], offset, err = d.decode(next)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		return m, offset, nil
+	case mmdbArray:
+		a := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			v, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, v)
+			offset = next
+		}
+		return a, offset, nil
+	case mmdbBool:
+		return size != 0, offset, nil
+	}
+	if offset+size > uint(len(d.buf)) {
+		return nil, 0, fmt.Errorf("value at offset %d is truncated", offset)
+	}
+	b := d.buf[offset : offset+size]
+	next := offset + size
+	switch typ {
+	case mmdbString:
+		return string(b), next, nil
+	case mmdbBytes:
+		return b, next, nil
+	case mmdbDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("double at offset %d has size %d", offset, size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case mmdbFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("float at offset %d has size %d", offset, size)
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), next, nil
+	case mmdbUint16, mmdbUint32, mmdbUint64, mmdbInt32:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("integer at offset %d has size %d", offset, size)
+		}
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+		if typ == mmdbInt32 {
+			return int32(v), next, nil
+		}
+		return v, next, nil
+	case mmdbUint128:
+		// Not needed to look up countries.
+		return b, next, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported data type %d at offset %d", typ, offset)
+	}
+}
+
+// mmdbUint converts a decoded unsigned integer to uint.
+// It returns 0 if the value is not an unsigned integer.
+func mmdbUint(v interface{}) uint {
+	if u, ok := v.(uint64); ok {
+		return uint(u)
+	}
+	return 0
+}
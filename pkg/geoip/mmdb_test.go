@@ -0,0 +1,194 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package geoip
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testMMDBWriter builds a small MaxMind DB file with an IPv6 search tree
+// and 24 bit records.
+type testMMDBWriter struct {
+	nodes   [][2]int // >= 0: node index, < 0: -(data index + 1), emptyRecord: no data
+	data    [][]byte
+	records map[string]int // country code to data index
+}
+
+const emptyRecord = -1 << 30
+
+func newTestMMDBWriter() *testMMDBWriter {
+	return &testMMDBWriter{
+		nodes:   [][2]int{{emptyRecord, emptyRecord}},
+		records: map[string]int{},
+	}
+}
+
+// insert maps the CIDR range to the data record.
+func (w *testMMDBWriter) insert(t *testing.T, cidr string, record []byte) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatalf("net.ParseCIDR() failed: %v", err)
+	}
+	ones, bits := ipNet.Mask.Size()
+	ip := ipNet.IP.To16()
+	if bits == 32 {
+		ones += 96
+		ip = append(make(net.IP, 12), ipNet.IP.To4()...)
+	}
+	w.data = append(w.data, record)
+	dataIndex := len(w.data) - 1
+	node := 0
+	for i := 0; i < ones; i++ {
+		bit := int(ip[i/8]>>(7-uint(i%8))) & 1
+		if i == ones-1 {
+			w.nodes[node][bit] = -(dataIndex + 1)
+			break
+		}
+		if w.nodes[node][bit] < 0 {
+			w.nodes = append(w.nodes, [2]int{emptyRecord, emptyRecord})
+			w.nodes[node][bit] = len(w.nodes) - 1
+		}
+		node = w.nodes[node][bit]
+	}
+}
+
+func (w *testMMDBWriter) bytes(databaseType string, buildEpoch uint64) []byte {
+	nodeCount := len(w.nodes)
+	var data bytes.Buffer
+	offsets := make([]int, len(w.data))
+	for i, d := range w.data {
+		offsets[i] = data.Len()
+		data.Write(d)
+	}
+	var out bytes.Buffer
+	for _, node := range w.nodes {
+		for _, r := range node {
+			var v int
+			switch {
+			case r == emptyRecord:
+				v = nodeCount
+			case r < 0:
+				v = nodeCount + mmdbDataSectionSeparator + offsets[-r-1]
+			default:
+				v = r
+			}
+			out.Write([]byte{byte(v >> 16), byte(v >> 8), byte(v)})
+		}
+	}
+	out.Write(make([]byte, mmdbDataSectionSeparator))
+	out.Write(data.Bytes())
+	out.Write(mmdbMetadataMarker)
+	out.Write(mmdbTestMap(5))
+	out.Write(mmdbTestString("node_count"))
+	out.Write(mmdbTestUint(mmdbUint32, uint64(nodeCount)))
+	out.Write(mmdbTestString("record_size"))
+	out.Write(mmdbTestUint(mmdbUint16, 24))
+	out.Write(mmdbTestString("ip_version"))
+	out.Write(mmdbTestUint(mmdbUint16, 6))
+	out.Write(mmdbTestString("database_type"))
+	out.Write(mmdbTestString(databaseType))
+	out.Write(mmdbTestString("build_epoch"))
+	out.Write(mmdbTestUint(mmdbUint64, buildEpoch))
+	return out.Bytes()
+}
+
+func mmdbTestMap(size int) []byte {
+	return []byte{mmdbMap<<5 | byte(size)}
+}
+
+func mmdbTestString(s string) []byte {
+	return append([]byte{mmdbString<<5 | byte(len(s))}, s...)
+}
+
+func mmdbTestUint(typ int, v uint64) []byte {
+	var b []byte
+	for ; v > 0; v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	if typ >= 8 {
+		return append([]byte{byte(len(b)), byte(typ - 7)}, b...)
+	}
+	return append([]byte{byte(typ<<5) | byte(len(b))}, b...)
+}
+
+// mmdbTestGeoIP2Record returns {"country": {"iso_code": <country>}}.
+func mmdbTestGeoIP2Record(country string) []byte {
+	var b []byte
+	b = append(b, mmdbTestMap(1)...)
+	b = append(b, mmdbTestString("country")...)
+	b = append(b, mmdbTestMap(1)...)
+	b = append(b, mmdbTestString("iso_code")...)
+	b = append(b, mmdbTestString(country)...)
+	return b
+}
+
+func TestMMDBCountry(t *testing.T) {
+	w := newTestMMDBWriter()
+	w.insert(t, "1.0.1.0/24", mmdbTestGeoIP2Record("CN"))
+	w.insert(t, "8.8.8.0/24", append(mmdbTestMap(1), append(mmdbTestString("country_code"), mmdbTestString("us")...)...))
+	w.insert(t, "2001:db8::/32", mmdbTestGeoIP2Record("JP"))
+	path := filepath.Join(t.TempDir(), "country.mmdb")
+	if err := os.WriteFile(path, w.bytes("Test-Country", 1700000000), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+
+	d, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !strings.HasPrefix(d.Version(), "Test-Country built at 2023-11-14") {
+		t.Errorf("Version() = %q, want the database type and build time", d.Version())
+	}
+	testcases := []struct {
+		ip   string
+		want string
+	}{
+		{"1.0.1.1", "CN"},
+		{"1.0.2.1", UnknownCountry},
+		{"8.8.8.8", "US"},
+		{"2001:db8::1", "JP"},
+		{"2001:db9::1", UnknownCountry},
+		{"::1", UnknownCountry},
+	}
+	for _, tc := range testcases {
+		if got := d.Country(net.ParseIP(tc.ip)); got != tc.want {
+			t.Errorf("Country(%s) = %q, want %q", tc.ip, got, tc.want)
+		}
+	}
+}
+
+func TestMMDBInvalid(t *testing.T) {
+	w := newTestMMDBWriter()
+	w.insert(t, "1.0.1.0/24", mmdbTestGeoIP2Record("CN"))
+	b := w.bytes("Test-Country", 1700000000)
+	i := bytes.LastIndex(b, mmdbMetadataMarker)
+	testcases := [][]byte{
+		// Metadata is truncated.
+		b[:len(b)-4],
+		// Search tree is larger than the file.
+		b[i-mmdbDataSectionSeparator:],
+	}
+	for _, tc := range testcases {
+		if _, err := parseMMDB(tc); err == nil {
+			t.Errorf("parseMMDB() returned no error")
+		}
+	}
+}
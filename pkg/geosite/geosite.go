@@ -0,0 +1,248 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package geosite looks up the categories of domain names.
+package geosite
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Types of domain rules in a geosite.dat file.
+const (
+	domainPlain  = 0 // the domain contains the value
+	domainRegex  = 1 // the domain matches the regular expression
+	domainSuffix = 2 // the domain is the value or its subdomain
+	domainFull   = 3 // the domain is the value
+)
+
+// Database maps category names to domain rules.
+type Database struct {
+	categories map[string]*category // key is upper case category name
+	rules      int
+}
+
+type category struct {
+	suffixes map[string]struct{}
+	fulls    map[string]struct{}
+	keywords []string
+	regexps  []*regexp.Regexp
+}
+
+// Load reads a geosite database from a file. See Parse for the format.
+func Load(path string) (*Database, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile() failed: %w", err)
+	}
+	return Parse(b)
+}
+
+// Parse reads a geosite database in the geosite.dat format used by
+// V2Ray and its derivatives, which is the protobuf message
+//
+//	message GeoSiteList { repeated GeoSite entry = 1; }
+//	message GeoSite { string country_code = 1; repeated Domain domain = 2; }
+//	message Domain { Type type = 1; string value = 2; }
+//
+// Domain attributes are ignored.
+func Parse(b []byte) (*Database, error) {
+	d := &Database{categories: make(map[string]*category)}
+	err := parseFields(b, func(num protowire.Number, v []byte) error {
+		if num != 1 {
+			return nil
+		}
+		return d.parseSite(v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Len returns the number of categories.
+func (d *Database) Len() int {
+	return len(d.categories)
+}
+
+// Version describes the number of categories and domain rules.
+func (d *Database) Version() string {
+	return fmt.Sprintf("%d categories with %d domain rules", len(d.categories), d.rules)
+}
+
+// Categories returns the sorted category names in lower case.
+func (d *Database) Categories() []string {
+	names := make([]string, 0, len(d.categories))
+	for name := range d.categories {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Contains returns true if the category is in the database.
+// The category name is case insensitive.
+func (d *Database) Contains(name string) bool {
+	_, found := d.categories[strings.ToUpper(name)]
+	return found
+}
+
+// Match returns true if the domain name belongs to the category.
+func (d *Database) Match(name, domain string) bool {
+	c, found := d.categories[strings.ToUpper(name)]
+	if !found {
+		return false
+	}
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if _, found := c.fulls[domain]; found {
+		return true
+	}
+	for suffix := domain; ; {
+		if _, found := c.suffixes[suffix]; found {
+			return true
+		}
+		var ok bool
+		if _, suffix, ok = strings.Cut(suffix, "."); !ok {
+			break
+		}
+	}
+	for _, keyword := range c.keywords {
+		if strings.Contains(domain, keyword) {
+			return true
+		}
+	}
+	for _, re := range c.regexps {
+		if re.MatchString(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Database) parseSite(b []byte) error {
+	var name string
+	var domains [][]byte
+	err := parseFields(b, func(num protowire.Number, v []byte) error {
+		switch num {
+		case 1:
+			name = strings.ToUpper(string(v))
+		case 2:
+			domains = append(domains, v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return fmt.Errorf("geosite category has no name")
+	}
+	c, found := d.categories[name]
+	if !found {
+		c = &category{
+			suffixes: make(map[string]struct{}),
+			fulls:    make(map[string]struct{}),
+		}
+		d.categories[name] = c
+	}
+	for _, domain := range domains {
+		if err := c.parseDomain(domain); err != nil {
+			return fmt.Errorf("geosite category %q: %w", name, err)
+		}
+		d.rules++
+	}
+	return nil
+}
+
+func (c *category) parseDomain(b []byte) error {
+	var typ uint64
+	var value string
+	for len(b) > 0 {
+		num, wireType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		switch {
+		case num == 1 && wireType == protowire.VarintType:
+			typ, n = protowire.ConsumeVarint(b)
+		case num == 2 && wireType == protowire.BytesType:
+			var v []byte
+			v, n = protowire.ConsumeBytes(b)
+			value = string(v)
+		default:
+			n = protowire.ConsumeFieldValue(num, wireType, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+	if typ != domainRegex {
+		value = strings.ToLower(value)
+	}
+	switch typ {
+	case domainPlain:
+		c.keywords = append(c.keywords, value)
+	case domainRegex:
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return fmt.Errorf("invalid regular expression %q: %w", value, err)
+		}
+		c.regexps = append(c.regexps, re)
+	case domainSuffix:
+		c.suffixes[value] = struct{}{}
+	case domainFull:
+		c.fulls[value] = struct{}{}
+	default:
+		return fmt.Errorf("unknown domain type %d", typ)
+	}
+	return nil
+}
+
+// parseFields calls fn with the value of each length delimited field
+// of the protobuf message. Other fields are skipped.
+func parseFields(b []byte, fn func(protowire.Number, []byte) error) error {
+	for len(b) > 0 {
+		num, wireType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if wireType != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, wireType, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(num, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package geosite
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type testDomain struct {
+	typ   uint64
+	value string
+}
+
+func buildTestDatabase(sites map[string][]testDomain) []byte {
+	var b []byte
+	for name, domains := range sites {
+		var site []byte
+		site = protowire.AppendTag(site, 1, protowire.BytesType)
+		site = protowire.AppendString(site, name)
+		for _, d := range domains {
+			var domain []byte
+			domain = protowire.AppendTag(domain, 1, protowire.VarintType)
+			domain = protowire.AppendVarint(domain, d.typ)
+			domain = protowire.AppendTag(domain, 2, protowire.BytesType)
+			domain = protowire.AppendString(domain, d.value)
+			// Attributes are ignored.
+			domain = protowire.AppendTag(domain, 3, protowire.BytesType)
+			domain = protowire.AppendBytes(domain, []byte{0x0a, 0x03, 'a', 'd', 's'})
+			site = protowire.AppendTag(site, 2, protowire.BytesType)
+			site = protowire.AppendBytes(site, domain)
+		}
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, site)
+	}
+	return b
+}
+
+func TestMatch(t *testing.T) {
+	b := buildTestDatabase(map[string][]testDomain{
+		"ADS": {
+			{domainSuffix, "doubleclick.net"},
+			{domainFull, "ads.example.com"},
+			{domainPlain, "adserver"},
+			{domainRegex, `^ad[0-9]+\.example\.org$`},
+		},
+		"CN": {
+			{domainSuffix, "cn"},
+		},
+	})
+	path := filepath.Join(t.TempDir(), "geosite.dat")
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	d, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if got := d.Categories(); !reflect.DeepEqual(got, []string{"ads", "cn"}) {
+		t.Errorf("Categories() = %v, want [ads cn]", got)
+	}
+	if !d.Contains("Ads") || d.Contains("gfw") {
+		t.Errorf("Contains() returned unexpected result")
+	}
+	testcases := []struct {
+		category string
+		domain   string
+		want     bool
+	}{
+		{"ads", "doubleclick.net", true},
+		{"ads", "stats.g.doubleclick.net.", true},
+		{"ads", "notdoubleclick.net", false},
+		{"ads", "ads.example.com", true},
+		{"ads", "www.ads.example.com", false},
+		{"ads", "my-adserver.example.com", true},
+		{"ads", "ad12.example.org", true},
+		{"ads", "ad.example.org", false},
+		{"cn", "www.gov.cn", true},
+		{"cn", "doubleclick.net", false},
+		{"gfw", "www.gov.cn", false},
+	}
+	for _, tc := range testcases {
+		if got := d.Match(tc.category, tc.domain); got != tc.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tc.category, tc.domain, got, tc.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	testcases := [][]byte{
+		{0x0a, 0x10}, // truncated
+		buildTestDatabase(map[string][]testDomain{"": {{domainFull, "example.com"}}}),
+		buildTestDatabase(map[string][]testDomain{"ADS": {{domainRegex, "("}}}),
+		buildTestDatabase(map[string][]testDomain{"ADS": {{9, "example.com"}}}),
+	}
+	for _, tc := range testcases {
+		if _, err := Parse(tc); err == nil {
+			t.Errorf("Parse(%v) returned no error", tc)
+		}
+	}
+}
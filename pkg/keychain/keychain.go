@@ -0,0 +1,26 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package keychain stores small secrets, like encryption keys, in the
+// secret storage of the operating system. It uses the Keychain in macOS,
+// the Secret Service (libsecret) in Linux, and DPAPI in Windows.
+package keychain
+
+import (
+	"errors"
+)
+
+// ErrNotFound is returned by Get if the secret doesn't exist.
+var ErrNotFound = errors.New("secret is not found in keychain")
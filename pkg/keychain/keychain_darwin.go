@@ -0,0 +1,64 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package keychain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Available returns true if the security command is available.
+func Available() bool {
+	_, err := exec.LookPath("security")
+	return err == nil
+}
+
+// Get returns the secret from the login keychain.
+func Get(service, account string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+			// errSecItemNotFound
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("security find-generic-password failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	secret, err := hex.DecodeString(strings.TrimSpace(stdout.String()))
+	if err != nil {
+		return nil, fmt.Errorf("secret in keychain is invalid: %w", err)
+	}
+	return secret, nil
+}
+
+// Set stores the secret to the login keychain. An existing secret
+// is replaced.
+func Set(service, account string, secret []byte) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("security", "add-generic-password", "-U", "-s", service, "-a", account, "-w", hex.EncodeToString(secret))
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("security add-generic-password failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
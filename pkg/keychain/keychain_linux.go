@@ -0,0 +1,66 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package keychain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Available returns true if the secret-tool command of libsecret
+// is available.
+func Available() bool {
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+// Get returns the secret from the Secret Service.
+func Get(service, account string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if stdout.Len() == 0 && stderr.Len() == 0 {
+			// secret-tool exits with 1 and prints nothing
+			// if the secret is not found.
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("secret-tool lookup failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	secret, err := hex.DecodeString(strings.TrimSpace(stdout.String()))
+	if err != nil {
+		return nil, fmt.Errorf("secret in keychain is invalid: %w", err)
+	}
+	return secret, nil
+}
+
+// Set stores the secret to the Secret Service. An existing secret
+// is replaced.
+func Set(service, account string, secret []byte) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "store", "--label", service+" "+account, "service", service, "account", account)
+	// The secret is read from stdin, so it is not visible in the process list.
+	cmd.Stdin = strings.NewReader(hex.EncodeToString(secret))
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("secret-tool store failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build !darwin && !linux && !windows
+
+package keychain
+
+import (
+	"github.com/enfein/mieru/v3/pkg/stderror"
+)
+
+// Available returns false in this platform.
+func Available() bool {
+	return false
+}
+
+// Get is not supported in this platform.
+func Get(service, account string) ([]byte, error) {
+	return nil, stderror.ErrUnsupported
+}
+
+// Set is not supported in this platform.
+func Set(service, account string, secret []byte) error {
+	return stderror.ErrUnsupported
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package keychain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Available returns true. DPAPI is always available in Windows.
+func Available() bool {
+	return true
+}
+
+// Get returns the secret protected by DPAPI of the current user.
+func Get(service, account string) ([]byte, error) {
+	path, err := secretFilePath(service, account)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("os.ReadFile() failed: %w", err)
+	}
+	secret, err := unprotect(b)
+	if err != nil {
+		return nil, fmt.Errorf("CryptUnprotectData() failed: %w", err)
+	}
+	return secret, nil
+}
+
+// Set protects the secret with DPAPI of the current user, and stores it
+// in the user config directory. An existing secret is replaced.
+func Set(service, account string, secret []byte) error {
+	path, err := secretFilePath(service, account)
+	if err != nil {
+		return err
+	}
+	b, err := protect(secret)
+	if err != nil {
+		return fmt.Errorf("CryptProtectData() failed: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("os.MkdirAll() failed: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		return fmt.Errorf("os.WriteFile() failed: %w", err)
+	}
+	return nil
+}
+
+func secretFilePath(service, account string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("os.UserConfigDir() failed: %w", err)
+	}
+	return filepath.Join(dir, service, account+".dpapi"), nil
+}
+
+func protect(data []byte) ([]byte, error) {
+	in := newBlob(data)
+	var out windows.DataBlob
+	if err := windows.CryptProtectData(in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeBlob(&out), nil
+}
+
+func unprotect(data []byte) ([]byte, error) {
+	in := newBlob(data)
+	var out windows.DataBlob
+	if err := windows.CryptUnprotectData(in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeBlob(&out), nil
+}
+
+func newBlob(data []byte) *windows.DataBlob {
+	if len(data) == 0 {
+		return &windows.DataBlob{}
+	}
+	return &windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+}
+
+// takeBlob copies the data out of a blob allocated by DPAPI,
+// and frees the blob.
+func takeBlob(blob *windows.DataBlob) []byte {
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(blob.Data)))
+	return append([]byte(nil), unsafe.Slice(blob.Data, blob.Size)...)
+}
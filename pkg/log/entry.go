@@ -278,6 +278,7 @@ func (entry *Entry) write() {
 	if _, err := entry.Logger.Out.Write(serialized); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
 	}
+	entry.Logger.followers.notify(entry)
 }
 
 func (entry *Entry) Logf(level Level, format string, args ...interface{}) {
@@ -91,6 +91,13 @@ func WithFields(fields Fields) *Entry {
 	return std.WithFields(fields)
 }
 
+// Follow returns a channel that receives the log entries written by the
+// standard logger at the level or more severe. Call the returned function
+// to stop following.
+func Follow(level Level) (<-chan FollowedEntry, func() int64) {
+	return std.Follow(level)
+}
+
 // WithTime creates an entry from the standard logger and overrides the time of
 // logs generated with it.
 //
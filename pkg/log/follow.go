@@ -0,0 +1,114 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// followerBufferSize is the number of log entries buffered for a follower.
+// New entries are dropped when the buffer is full.
+const followerBufferSize = 256
+
+// FollowedEntry is a copy of a log entry sent to a follower.
+type FollowedEntry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  map[string]string
+}
+
+// follower receives the log entries at a level or more severe.
+type follower struct {
+	level   Level
+	entries chan FollowedEntry
+	dropped atomic.Int64
+}
+
+// followerSet holds the followers of a logger.
+type followerSet struct {
+	mu        sync.Mutex
+	followers map[*follower]struct{}
+	count     atomic.Int32 // avoid locking the mutex when there is no follower
+}
+
+func (s *followerSet) add(f *follower) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.followers == nil {
+		s.followers = make(map[*follower]struct{})
+	}
+	s.followers[f] = struct{}{}
+	s.count.Store(int32(len(s.followers)))
+}
+
+func (s *followerSet) remove(f *follower) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.followers, f)
+	s.count.Store(int32(len(s.followers)))
+}
+
+// notify sends a copy of the entry to the followers. It never blocks.
+func (s *followerSet) notify(entry *Entry) {
+	if s.count.Load() == 0 {
+		return
+	}
+	var fields map[string]string
+	if len(entry.Data) > 0 {
+		fields = make(map[string]string, len(entry.Data))
+		for k, v := range entry.Data {
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+	e := FollowedEntry{
+		Time:    entry.Time,
+		Level:   entry.Level,
+		Message: entry.Message,
+		Fields:  fields,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for f := range s.followers {
+		if e.Level > f.level {
+			continue
+		}
+		select {
+		case f.entries <- e:
+		default:
+			f.dropped.Add(1)
+		}
+	}
+}
+
+// Follow returns a channel that receives the log entries written by the
+// logger at the level or more severe. Entries are dropped if they are
+// not received fast enough. Call the returned function to stop following,
+// which returns the number of dropped entries.
+func (logger *Logger) Follow(level Level) (<-chan FollowedEntry, func() int64) {
+	f := &follower{
+		level:   level,
+		entries: make(chan FollowedEntry, followerBufferSize),
+	}
+	logger.followers.add(f)
+	return f.entries, func() int64 {
+		logger.followers.remove(f)
+		return f.dropped.Load()
+	}
+}
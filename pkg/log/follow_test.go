@@ -0,0 +1,53 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"io"
+	"testing"
+)
+
+func TestFollow(t *testing.T) {
+	logger := New()
+	logger.Out = io.Discard
+	logger.Level = DebugLevel
+	entries, stop := logger.Follow(InfoLevel)
+
+	logger.Debugf("debug")
+	logger.WithField(FieldKeySessionID, 1234).Warnf("warn")
+	select {
+	case e := <-entries:
+		if e.Level != WarnLevel || e.Message != "warn" {
+			t.Errorf("got entry %v %q, want %v %q", e.Level, e.Message, WarnLevel, "warn")
+		}
+		if e.Fields[FieldKeySessionID] != "1234" {
+			t.Errorf("got fields %v, want session_id=1234", e.Fields)
+		}
+	default:
+		t.Fatalf("followed entry is not received")
+	}
+
+	for i := 0; i < followerBufferSize+10; i++ {
+		logger.Infof("info")
+	}
+	if dropped := stop(); dropped != 10 {
+		t.Errorf("dropped %d entries, want 10", dropped)
+	}
+	logger.Infof("after stop")
+	if len(entries) != followerBufferSize {
+		t.Errorf("got %d buffered entries, want %d", len(entries), followerBufferSize)
+	}
+}
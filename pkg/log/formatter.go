@@ -2,6 +2,7 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
@@ -17,6 +18,11 @@ const (
 	FieldKeyTime           = "time"
 	FieldKeyFunc           = "func"
 	FieldKeyFile           = "file"
+
+	// Keys of the fields that are printed when they are set.
+	FieldKeyComponent = "component"
+	FieldKeySessionID = "session_id"
+	FieldKeyUser      = "user"
 )
 
 // LogPrefix is a fixed string printed at the beginning of each line
@@ -128,6 +134,56 @@ func (f *DaemonFormatter) Format(entry *Entry) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// JSONFormatter is a log formatter that prints a JSON object in each line,
+// so the logs can be collected without parsing text. Time, level and
+// message are always printed. Other fields, such as component, session_id
+// and user, are printed if they are set.
+type JSONFormatter struct {
+	// Component is the name of the program, e.g. "mita".
+	// It is printed if the entry doesn't have a component field.
+	Component string
+}
+
+func (f *JSONFormatter) Format(entry *Entry) ([]byte, error) {
+	data := make(Fields, len(entry.Data)+6)
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			// Errors are not serialized by encoding/json.
+			data[k] = err.Error()
+		} else {
+			data[k] = v
+		}
+	}
+	data[FieldKeyTime] = entry.Time.Format(time.RFC3339Nano)
+	data[FieldKeyLevel] = entry.Level.String()
+	data[FieldKeyMsg] = entry.Message
+	if _, ok := data[FieldKeyComponent]; !ok && f.Component != "" {
+		data[FieldKeyComponent] = f.Component
+	}
+	if entry.HasCaller() {
+		data[FieldKeyFile] = fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
+		data[FieldKeyFunc] = entry.Caller.Function
+	}
+
+	var buf *bytes.Buffer
+	if entry.Buffer != nil {
+		buf = entry.Buffer
+	} else {
+		buf = &bytes.Buffer{}
+	}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to encode log entry to JSON: %w", err)
+	}
+
+	if entry.Level == FatalLevel {
+		fmt.Fprint(os.Stderr, buf.String())
+	}
+
+	return buf.Bytes(), nil
+}
+
 // NilFormatter prints no log. It disables logging.
 type NilFormatter struct{}
 
@@ -17,6 +17,8 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -103,6 +105,43 @@ func TestDaemonFormatter(t *testing.T) {
 	buf.Reset()
 }
 
+func TestJSONFormatter(t *testing.T) {
+	SetFormatter(&JSONFormatter{Component: "mita"})
+	SetLevel("TRACE")
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer func() {
+		SetFormatter(&CliFormatter{})
+		SetLevel("INFO")
+		SetOutput(os.Stdout)
+	}()
+
+	WithFields(Fields{FieldKeySessionID: 1234, FieldKeyUser: "alice", ErrorKey: errors.New("broken pipe")}).Warnf("This is a <test> message")
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(%q) failed: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		FieldKeyLevel:     "warning",
+		FieldKeyMsg:       "This is a <test> message",
+		FieldKeyComponent: "mita",
+		FieldKeySessionID: float64(1234),
+		FieldKeyUser:      "alice",
+		ErrorKey:          "broken pipe",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("field %q = %v, want %v", k, got[k], v)
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, got[FieldKeyTime].(string)); err != nil {
+		t.Errorf("Invalid timestamp: %v, %v", got[FieldKeyTime], err)
+	}
+	if !strings.HasSuffix(buf.String(), "}\n") {
+		t.Errorf("log %q doesn't end with a new line", buf.String())
+	}
+}
+
 func TestNilFormatter(t *testing.T) {
 	SetFormatter(&NilFormatter{})
 	SetLevel("TRACE")
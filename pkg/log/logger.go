@@ -48,6 +48,9 @@ type Logger struct {
 	// The buffer pool used to format the log. If it is nil, the default global
 	// buffer pool will be used.
 	BufferPool BufferPool
+
+	// Followers receive a copy of the log entries.
+	followers followerSet
 }
 
 type exitFunc func(int)
@@ -0,0 +1,247 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// compressedSuffix is the file name suffix of compressed rotated files.
+const compressedSuffix = ".gz"
+
+// RotationOptions controls when a RotatingFile is rotated, and how long
+// the rotated files are kept.
+type RotationOptions struct {
+	// Rotate the file before its size exceeds MaxSize bytes.
+	// If it is 0, the file is not rotated.
+	MaxSize int64
+
+	// Maximum number of rotated files to keep.
+	MaxBackups int
+
+	// Remove the rotated files older than MaxAge.
+	// If it is 0, rotated files are not removed by age.
+	MaxAge time.Duration
+
+	// Compress the rotated files with gzip.
+	Compress bool
+}
+
+// RotatingFile is a file that is rotated when its size exceeds a limit.
+// The rotated files are named <path>.1, <path>.2 and so on, and <path>.1
+// is the most recent one. If compression is enabled, ".gz" is appended
+// to the names of rotated files.
+type RotatingFile struct {
+	path string
+	opts RotationOptions
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// cleanup is running in the background if it is not nil.
+	// It is closed when the cleanup completes.
+	cleanup chan struct{}
+}
+
+var _ io.WriteCloser = &RotatingFile{}
+
+// NewRotatingFile opens the file at the path for appending.
+func NewRotatingFile(path string, opts RotationOptions) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll() failed: %w", err)
+	}
+	f := &RotatingFile{
+		path: path,
+		opts: opts,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	if opts.MaxAge > 0 {
+		// Remove the files left by previous processes.
+		f.removeExpiredBackups()
+	}
+	return f, nil
+}
+
+// Write appends the data to the file. The data is never split
+// between two files.
+func (f *RotatingFile) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.opts.MaxSize > 0 && f.size > 0 && f.size+int64(len(b)) > f.opts.MaxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(b)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the file. It waits for the compression of
+// the last rotated file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.waitCleanup()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %q: %w", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to get status of log file %q: %w", f.path, err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file to <path>.1 and opens a new file.
+// The oldest rotated file is removed if there are too many of them.
+// Compression and removal by age run in the background.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file %q: %w", f.path, err)
+	}
+	f.file = nil
+
+	// Rotated files can't be renamed while one of them is compressed.
+	f.waitCleanup()
+
+	if f.opts.MaxBackups <= 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("os.Remove() failed: %w", err)
+		}
+		return f.open()
+	}
+	for _, suffix := range []string{"", compressedSuffix} {
+		os.Remove(f.backupPath(f.opts.MaxBackups) + suffix)
+		for i := f.opts.MaxBackups - 1; i >= 1; i-- {
+			if err := os.Rename(f.backupPath(i)+suffix, f.backupPath(i+1)+suffix); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("os.Rename() failed: %w", err)
+			}
+		}
+	}
+	if err := os.Rename(f.path, f.backupPath(1)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("os.Rename() failed: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	if f.opts.Compress || f.opts.MaxAge > 0 {
+		done := make(chan struct{})
+		f.cleanup = done
+		go func() {
+			defer close(done)
+			if f.opts.Compress {
+				if err := compressFile(f.backupPath(1)); err != nil {
+					// Don't write to the logger, which may be writing to this file.
+					fmt.Fprintf(os.Stderr, "failed to compress rotated log file: %v\n", err)
+				}
+			}
+			if f.opts.MaxAge > 0 {
+				f.removeExpiredBackups()
+			}
+		}()
+	}
+	return nil
+}
+
+// waitCleanup waits for the background compression and removal.
+func (f *RotatingFile) waitCleanup() {
+	if f.cleanup != nil {
+		<-f.cleanup
+		f.cleanup = nil
+	}
+}
+
+// removeExpiredBackups removes the rotated files older than MaxAge.
+func (f *RotatingFile) removeExpiredBackups() {
+	deadline := time.Now().Add(-f.opts.MaxAge)
+	for i := 1; i <= f.opts.MaxBackups; i++ {
+		for _, suffix := range []string{"", compressedSuffix} {
+			path := f.backupPath(i) + suffix
+			if info, err := os.Stat(path); err == nil && info.ModTime().Before(deadline) {
+				os.Remove(path)
+			}
+		}
+	}
+}
+
+func (f *RotatingFile) backupPath(i int) string {
+	return f.path + "." + strconv.Itoa(i)
+}
+
+// compressFile compresses the file to <path>.gz, and removes the file.
+// The modification time is kept, so removal by age still works.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("os.Open() failed: %w", err)
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return fmt.Errorf("Stat() failed: %w", err)
+	}
+	dstPath := path + compressedSuffix
+	dst, err := os.OpenFile(dstPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("os.OpenFile() failed: %w", err)
+	}
+	w := gzip.NewWriter(dst)
+	if _, err := io.Copy(w, src); err != nil {
+		dst.Close()
+		os.Remove(dstPath)
+		return fmt.Errorf("io.Copy() failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		dst.Close()
+		os.Remove(dstPath)
+		return fmt.Errorf("gzip Close() failed: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dstPath)
+		return fmt.Errorf("Close() failed: %w", err)
+	}
+	os.Chtimes(dstPath, info.ModTime(), info.ModTime())
+	src.Close()
+	return os.Remove(path)
+}
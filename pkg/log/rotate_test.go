@@ -0,0 +1,121 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.log")
+	f, err := NewRotatingFile(path, RotationOptions{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile() failed: %v", err)
+	}
+	defer f.Close()
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for p, content := range want {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("os.ReadFile() failed: %v", err)
+		}
+		if string(b) != content {
+			t.Errorf("content of %s = %q, want %q", p, b, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 exists, but max backups is 2", path)
+	}
+
+	// The size of the existing file is counted after open again.
+	f.Close()
+	f, err = NewRotatingFile(path, RotationOptions{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewRotatingFile() failed: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte("fifth\n")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	b, _ := os.ReadFile(path + ".1")
+	if string(b) != "fourth\n" {
+		t.Errorf("content of %s.1 = %q, want %q", path, b, "fourth\n")
+	}
+}
+
+func TestRotatingFileCompressAndMaxAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mita.log")
+
+	// An expired rotated file left by a previous process is removed.
+	if err := os.WriteFile(path+".2.gz", []byte("expired"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(path+".2.gz", old, old); err != nil {
+		t.Fatalf("os.Chtimes() failed: %v", err)
+	}
+	f, err := NewRotatingFile(path, RotationOptions{MaxSize: 10, MaxBackups: 3, MaxAge: 24 * time.Hour, Compress: true})
+	if err != nil {
+		t.Fatalf("NewRotatingFile() failed: %v", err)
+	}
+	if _, err := os.Stat(path + ".2.gz"); !os.IsNotExist(err) {
+		t.Errorf("expired rotated file is not removed")
+	}
+
+	for _, line := range []string{"first\n", "second\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("rotated file is not removed after compression")
+	}
+	gz, err := os.Open(path + ".1.gz")
+	if err != nil {
+		t.Fatalf("os.Open() failed: %v", err)
+	}
+	defer gz.Close()
+	r, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() failed: %v", err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("io.ReadAll() failed: %v", err)
+	}
+	if string(b) != "first\n" {
+		t.Errorf("content of %s.1.gz = %q, want %q", path, b, "first\n")
+	}
+}
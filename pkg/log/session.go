@@ -0,0 +1,41 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package log
+
+import "context"
+
+type sessionIDKey struct{}
+
+// ContextWithSessionID returns a context that carries the session ID.
+// The logs written with WithSession(ctx) have the session_id field.
+func ContextWithSessionID(ctx context.Context, id uint32) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, id)
+}
+
+// SessionIDFromContext returns the session ID carried by the context.
+func SessionIDFromContext(ctx context.Context) (uint32, bool) {
+	id, ok := ctx.Value(sessionIDKey{}).(uint32)
+	return id, ok
+}
+
+// WithSession creates an entry from the standard logger. If the context
+// carries a session ID, it is added to the entry as the session_id field.
+func WithSession(ctx context.Context) *Entry {
+	if id, ok := SessionIDFromContext(ctx); ok {
+		return std.WithField(FieldKeySessionID, id)
+	}
+	return NewEntry(std)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWithSession(t *testing.T) {
+	SetFormatter(&DaemonFormatter{NoTimestamp: true})
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer func() {
+		SetFormatter(&CliFormatter{})
+		SetOutput(os.Stdout)
+	}()
+
+	if _, ok := SessionIDFromContext(context.Background()); ok {
+		t.Errorf("SessionIDFromContext() found session ID from empty context")
+	}
+	WithSession(context.Background()).Infof("no session")
+	if strings.Contains(buf.String(), FieldKeySessionID) {
+		t.Errorf("log %q has session ID", buf.String())
+	}
+	buf.Reset()
+
+	ctx := ContextWithSessionID(context.Background(), 1234)
+	if id, ok := SessionIDFromContext(ctx); !ok || id != 1234 {
+		t.Errorf("SessionIDFromContext() = %d, %v, want 1234, true", id, ok)
+	}
+	WithSession(ctx).Infof("with session")
+	if !strings.Contains(buf.String(), "session_id=1234") {
+		t.Errorf("log %q doesn't have session ID", buf.String())
+	}
+}
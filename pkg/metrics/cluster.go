@@ -0,0 +1,225 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/metrics/metricspb"
+	"google.golang.org/protobuf/proto"
+)
+
+// remoteTraffic holds the user and user group traffic reported by the
+// other servers of a cluster. It maps the name of the source to the
+// traffic history, indexed by metric group name and metric name.
+var (
+	remoteTraffic   = map[string]map[string]map[string][]*pb.History{}
+	remoteTrafficMu sync.Mutex
+)
+
+// LocalUserTraffic returns the upload and download traffic of users and
+// user groups recorded by this process after the given time. The history
+// is rolled up to hours, and to days if it is older than 8 days.
+func LocalUserTraffic(since time.Time) []*pb.MetricGroup {
+	var groups []*pb.MetricGroup
+	metricMap.Range(func(k, v any) bool {
+		group := v.(*MetricGroup)
+		if !strings.HasPrefix(group.name, userMetricGroupPrefix) && !strings.HasPrefix(group.name, userGroupMetricGroupPrefix) {
+			return true
+		}
+		pbGroup := &pb.MetricGroup{Name: proto.String(group.name)}
+		for _, name := range []string{UserMetricUploadBytes, UserMetricDownloadBytes} {
+			m, ok := group.GetMetric(name)
+			if !ok || m.Type() != COUNTER_TIME_SERIES {
+				continue
+			}
+			src := ToMetricPB(m)
+			history := rollUpTraffic([][]*pb.History{src.GetHistory()}, since, time.Now())
+			if len(history) == 0 {
+				continue
+			}
+			pbGroup.Metrics = append(pbGroup.Metrics, &pb.Metric{
+				Name:    proto.String(name),
+				Type:    pb.MetricType_COUNTER_TIME_SERIES.Enum(),
+				Value:   proto.Int64(sumHistory(history)),
+				History: history,
+			})
+		}
+		if len(pbGroup.GetMetrics()) > 0 {
+			groups = append(groups, pbGroup)
+		}
+		return true
+	})
+	sort.Slice(groups, func(i, j int) bool { return groups[i].GetName() < groups[j].GetName() })
+	return groups
+}
+
+// SetRemoteTraffic replaces the traffic reported by the source.
+// Only time series counters of users and user groups are kept.
+func SetRemoteTraffic(source string, groups []*pb.MetricGroup) {
+	traffic := map[string]map[string][]*pb.History{}
+	for _, group := range groups {
+		name := group.GetName()
+		if !strings.HasPrefix(name, userMetricGroupPrefix) && !strings.HasPrefix(name, userGroupMetricGroupPrefix) {
+			continue
+		}
+		for _, m := range group.GetMetrics() {
+			if m.GetType() != pb.MetricType_COUNTER_TIME_SERIES {
+				continue
+			}
+			if m.GetName() != UserMetricUploadBytes && m.GetName() != UserMetricDownloadBytes {
+				continue
+			}
+			if traffic[name] == nil {
+				traffic[name] = map[string][]*pb.History{}
+			}
+			traffic[name][m.GetName()] = rollUpTraffic([][]*pb.History{traffic[name][m.GetName()], m.GetHistory()}, time.Time{}, time.Now())
+		}
+	}
+	remoteTrafficMu.Lock()
+	defer remoteTrafficMu.Unlock()
+	if len(traffic) == 0 {
+		delete(remoteTraffic, source)
+	} else {
+		remoteTraffic[source] = traffic
+	}
+}
+
+// RemoteTraffic returns the sum of the traffic reported by all the
+// sources except the given one.
+func RemoteTraffic(except string) []*pb.MetricGroup {
+	remoteTrafficMu.Lock()
+	defer remoteTrafficMu.Unlock()
+	var lists [][]*pb.MetricGroup
+	for source, traffic := range remoteTraffic {
+		if source == except {
+			continue
+		}
+		var groups []*pb.MetricGroup
+		for groupName, metrics := range traffic {
+			pbGroup := &pb.MetricGroup{Name: proto.String(groupName)}
+			for metricName, history := range metrics {
+				pbGroup.Metrics = append(pbGroup.Metrics, &pb.Metric{
+					Name:    proto.String(metricName),
+					Type:    pb.MetricType_COUNTER_TIME_SERIES.Enum(),
+					Value:   proto.Int64(sumHistory(history)),
+					History: history,
+				})
+			}
+			groups = append(groups, pbGroup)
+		}
+		lists = append(lists, groups)
+	}
+	return MergeTraffic(lists...)
+}
+
+// RemoteDeltaBetween returns the increment of the metric between t1 and t2,
+// reported by all the sources.
+func RemoteDeltaBetween(groupName, metricName string, t1, t2 time.Time) int64 {
+	remoteTrafficMu.Lock()
+	defer remoteTrafficMu.Unlock()
+	var sum int64
+	for _, traffic := range remoteTraffic {
+		for _, h := range traffic[groupName][metricName] {
+			t := time.UnixMilli(h.GetTimeUnixMilli())
+			if t.After(t1) && !t.After(t2) {
+				sum += h.GetDelta()
+			}
+		}
+	}
+	return sum
+}
+
+// MergeTraffic returns the sum of the traffic in the lists,
+// grouped by metric group name and metric name.
+func MergeTraffic(lists ...[]*pb.MetricGroup) []*pb.MetricGroup {
+	histories := map[string]map[string][][]*pb.History{}
+	for _, groups := range lists {
+		for _, group := range groups {
+			if histories[group.GetName()] == nil {
+				histories[group.GetName()] = map[string][][]*pb.History{}
+			}
+			for _, m := range group.GetMetrics() {
+				histories[group.GetName()][m.GetName()] = append(histories[group.GetName()][m.GetName()], m.GetHistory())
+			}
+		}
+	}
+	now := time.Now()
+	var groups []*pb.MetricGroup
+	for groupName, metrics := range histories {
+		pbGroup := &pb.MetricGroup{Name: proto.String(groupName)}
+		for metricName, list := range metrics {
+			history := rollUpTraffic(list, time.Time{}, now)
+			pbGroup.Metrics = append(pbGroup.Metrics, &pb.Metric{
+				Name:    proto.String(metricName),
+				Type:    pb.MetricType_COUNTER_TIME_SERIES.Enum(),
+				Value:   proto.Int64(sumHistory(history)),
+				History: history,
+			})
+		}
+		sort.Slice(pbGroup.Metrics, func(i, j int) bool { return pbGroup.Metrics[i].GetName() < pbGroup.Metrics[j].GetName() })
+		groups = append(groups, pbGroup)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].GetName() < groups[j].GetName() })
+	return groups
+}
+
+// rollUpTraffic sums the history after the given time into hourly buckets,
+// or daily buckets if they are older than 8 days.
+func rollUpTraffic(lists [][]*pb.History, since, now time.Time) []*pb.History {
+	buckets := map[int64]*pb.History{}
+	for _, list := range lists {
+		for _, h := range list {
+			t := time.UnixMilli(h.GetTimeUnixMilli())
+			if !t.After(since) || h.GetDelta() <= 0 {
+				continue
+			}
+			label := pb.RollUpLabel_ROLL_UP_TO_HOUR
+			t = t.Truncate(time.Hour)
+			if now.Sub(t) > rollUpHourToDay {
+				label = pb.RollUpLabel_ROLL_UP_TO_DAY
+				t = t.Truncate(24 * time.Hour)
+			}
+			key := t.UnixMilli()
+			if b, ok := buckets[key]; ok {
+				b.Delta = proto.Int64(b.GetDelta() + h.GetDelta())
+			} else {
+				buckets[key] = &pb.History{
+					TimeUnixMilli: proto.Int64(key),
+					Delta:         proto.Int64(h.GetDelta()),
+					RollUp:        label.Enum(),
+				}
+			}
+		}
+	}
+	history := make([]*pb.History, 0, len(buckets))
+	for _, b := range buckets {
+		history = append(history, b)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].GetTimeUnixMilli() < history[j].GetTimeUnixMilli() })
+	return history
+}
+
+func sumHistory(history []*pb.History) int64 {
+	var sum int64
+	for _, h := range history {
+		sum += h.GetDelta()
+	}
+	return sum
+}
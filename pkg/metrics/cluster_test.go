@@ -0,0 +1,124 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	pb "github.com/enfein/mieru/v3/pkg/metrics/metricspb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLocalUserTraffic(t *testing.T) {
+	groupName := fmt.Sprintf(UserMetricGroupFormat, "cluster-local")
+	upload := RegisterMetric(groupName, UserMetricUploadBytes, COUNTER_TIME_SERIES).(*Counter)
+	now := time.Now()
+	upload.addWithTime(100, now.Add(-48*time.Hour))
+	upload.addWithTime(200, now.Add(-time.Minute))
+	upload.addWithTime(300, now)
+
+	var found *pb.MetricGroup
+	for _, group := range LocalUserTraffic(now.Add(-24 * time.Hour)) {
+		if group.GetName() == groupName {
+			found = group
+		}
+	}
+	if found == nil {
+		t.Fatalf("traffic of %q is not found", groupName)
+	}
+	if len(found.GetMetrics()) != 1 {
+		t.Fatalf("got %d metrics, want 1", len(found.GetMetrics()))
+	}
+	if got := found.GetMetrics()[0].GetValue(); got != 500 {
+		t.Errorf("got traffic %d after the given time, want 500", got)
+	}
+}
+
+func TestRemoteTraffic(t *testing.T) {
+	groupName := fmt.Sprintf(UserMetricGroupFormat, "cluster-remote")
+	now := time.Now()
+	traffic := func(delta int64) []*pb.MetricGroup {
+		return []*pb.MetricGroup{
+			{
+				Name: proto.String(groupName),
+				Metrics: []*pb.Metric{
+					{
+						Name:  proto.String(UserMetricDownloadBytes),
+						Type:  pb.MetricType_COUNTER_TIME_SERIES.Enum(),
+						Value: proto.Int64(delta),
+						History: []*pb.History{
+							{TimeUnixMilli: proto.Int64(now.Add(-time.Minute).UnixMilli()), Delta: proto.Int64(delta)},
+						},
+					},
+				},
+			},
+			{
+				Name: proto.String("connections"),
+				Metrics: []*pb.Metric{
+					{Name: proto.String("ActiveOpens"), Type: pb.MetricType_COUNTER.Enum(), Value: proto.Int64(1)},
+				},
+			},
+		}
+	}
+	SetRemoteTraffic("node-a", traffic(1000))
+	SetRemoteTraffic("node-b", traffic(2000))
+	defer SetRemoteTraffic("node-a", nil)
+	defer SetRemoteTraffic("node-b", nil)
+
+	if got := RemoteDeltaBetween(groupName, UserMetricDownloadBytes, now.Add(-24*time.Hour), now); got != 3000 {
+		t.Errorf("RemoteDeltaBetween() = %d, want 3000", got)
+	}
+	if got := RemoteDeltaBetween(groupName, UserMetricUploadBytes, now.Add(-24*time.Hour), now); got != 0 {
+		t.Errorf("RemoteDeltaBetween() = %d, want 0", got)
+	}
+
+	// Traffic reported by node-a is excluded.
+	groups := RemoteTraffic("node-a")
+	if len(groups) != 1 || groups[0].GetName() != groupName {
+		t.Fatalf("RemoteTraffic() returned %v, want only %q", groups, groupName)
+	}
+	if got := groups[0].GetMetrics()[0].GetValue(); got != 2000 {
+		t.Errorf("RemoteTraffic() value is %d, want 2000", got)
+	}
+
+	// Replace the traffic of node-b.
+	SetRemoteTraffic("node-b", traffic(500))
+	if got := RemoteDeltaBetween(groupName, UserMetricDownloadBytes, now.Add(-24*time.Hour), now); got != 1500 {
+		t.Errorf("RemoteDeltaBetween() = %d, want 1500", got)
+	}
+}
+
+func TestMergeTraffic(t *testing.T) {
+	now := time.Now().Truncate(time.Hour)
+	history := func(delta int64) []*pb.History {
+		return []*pb.History{{TimeUnixMilli: proto.Int64(now.Add(time.Minute).UnixMilli()), Delta: proto.Int64(delta)}}
+	}
+	a := []*pb.MetricGroup{{Name: proto.String("user - a"), Metrics: []*pb.Metric{{Name: proto.String(UserMetricUploadBytes), History: history(10)}}}}
+	b := []*pb.MetricGroup{{Name: proto.String("user - a"), Metrics: []*pb.Metric{{Name: proto.String(UserMetricUploadBytes), History: history(20)}}}}
+	merged := MergeTraffic(a, b)
+	if len(merged) != 1 || len(merged[0].GetMetrics()) != 1 {
+		t.Fatalf("MergeTraffic() returned %v, want 1 group with 1 metric", merged)
+	}
+	m := merged[0].GetMetrics()[0]
+	if m.GetValue() != 30 || len(m.GetHistory()) != 1 {
+		t.Errorf("got value %d and %d history, want 30 and 1 history", m.GetValue(), len(m.GetHistory()))
+	}
+	if m.GetHistory()[0].GetTimeUnixMilli() != now.UnixMilli() {
+		t.Errorf("history is not rolled up to hour")
+	}
+}
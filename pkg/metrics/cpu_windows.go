@@ -0,0 +1,41 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build windows
+
+package metrics
+
+import (
+	"syscall"
+	"time"
+)
+
+// ProcessCPUTime returns the user and kernel CPU time used by this process.
+func ProcessCPUTime() (time.Duration, error) {
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return 0, err
+	}
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(process, &creation, &exit, &kernel, &user); err != nil {
+		return 0, err
+	}
+	return filetimeDuration(kernel) + filetimeDuration(user), nil
+}
+
+// filetimeDuration converts a duration counted in 100-nanosecond intervals.
+func filetimeDuration(ft syscall.Filetime) time.Duration {
+	return time.Duration(int64(ft.HighDateTime)<<32|int64(ft.LowDateTime)) * 100
+}
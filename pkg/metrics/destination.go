@@ -0,0 +1,144 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// maxDestinationsPerHour is the maximum number of destinations
+	// recorded in one hour. Traffic of other destinations is recorded
+	// with OtherDestinations.
+	maxDestinationsPerHour = 4096
+
+	// OtherDestinations aggregates the traffic of destinations
+	// that can't be recorded individually.
+	OtherDestinations = "(others)"
+)
+
+// DestinationTraffic is the traffic volume of a destination.
+type DestinationTraffic struct {
+	Destination   string
+	UploadBytes   int64
+	DownloadBytes int64
+}
+
+// TotalBytes returns the sum of upload and download bytes.
+func (t DestinationTraffic) TotalBytes() int64 {
+	return t.UploadBytes + t.DownloadBytes
+}
+
+// DestinationStats collects the traffic volume of each destination.
+// The traffic is aggregated hourly and stored in a ring buffer,
+// so only the most recent hours are kept.
+type DestinationStats struct {
+	buckets []destinationBucket
+	mu      sync.Mutex
+}
+
+type destinationBucket struct {
+	hour    time.Time // beginning of the hour
+	traffic map[string]*DestinationTraffic
+}
+
+// NewDestinationStats creates a new DestinationStats that keeps
+// the traffic of the given number of hours.
+func NewDestinationStats(hours int) *DestinationStats {
+	if hours <= 0 {
+		panic("number of hours must be positive")
+	}
+	return &DestinationStats{
+		buckets: make([]destinationBucket, hours),
+	}
+}
+
+// Add records the traffic of a destination.
+func (s *DestinationStats) Add(destination string, uploadBytes, downloadBytes int64) {
+	s.addWithTime(destination, uploadBytes, downloadBytes, time.Now())
+}
+
+// Top returns the destinations with the most traffic,
+// sorted by total bytes in descending order.
+// If n is not positive, all the destinations are returned.
+func (s *DestinationStats) Top(n int) []DestinationTraffic {
+	return s.topWithTime(n, time.Now())
+}
+
+func (s *DestinationStats) addWithTime(destination string, uploadBytes, downloadBytes int64, now time.Time) {
+	if uploadBytes == 0 && downloadBytes == 0 {
+		return
+	}
+	hour := now.Truncate(time.Hour)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := &s.buckets[int(hour.Unix()/3600)%len(s.buckets)]
+	if !b.hour.Equal(hour) {
+		b.hour = hour
+		b.traffic = make(map[string]*DestinationTraffic)
+	}
+	t, found := b.traffic[destination]
+	if !found {
+		if len(b.traffic) >= maxDestinationsPerHour {
+			destination = OtherDestinations
+			t, found = b.traffic[destination]
+		}
+		if !found {
+			t = &DestinationTraffic{Destination: destination}
+			b.traffic[destination] = t
+		}
+	}
+	t.UploadBytes += uploadBytes
+	t.DownloadBytes += downloadBytes
+}
+
+func (s *DestinationStats) topWithTime(n int, now time.Time) []DestinationTraffic {
+	oldest := now.Truncate(time.Hour).Add(-time.Duration(len(s.buckets)-1) * time.Hour)
+	sum := make(map[string]*DestinationTraffic)
+	s.mu.Lock()
+	for _, b := range s.buckets {
+		if b.hour.Before(oldest) {
+			continue
+		}
+		for dst, t := range b.traffic {
+			if total, found := sum[dst]; found {
+				total.UploadBytes += t.UploadBytes
+				total.DownloadBytes += t.DownloadBytes
+			} else {
+				copied := *t
+				sum[dst] = &copied
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	res := make([]DestinationTraffic, 0, len(sum))
+	for _, t := range sum {
+		res = append(res, *t)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].TotalBytes() != res[j].TotalBytes() {
+			return res[i].TotalBytes() > res[j].TotalBytes()
+		}
+		return res[i].Destination < res[j].Destination
+	})
+	if n > 0 && len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
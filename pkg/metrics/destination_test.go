@@ -0,0 +1,80 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDestinationStatsTop(t *testing.T) {
+	s := NewDestinationStats(2)
+	now := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+	s.addWithTime("a.com", 100, 1000, now.Add(-time.Hour))
+	s.addWithTime("b.com", 10, 10, now)
+	s.addWithTime("a.com", 1, 1, now)
+	s.addWithTime("1.2.3.4", 500, 0, now)
+	s.addWithTime("c.com", 0, 0, now)
+
+	top := s.topWithTime(0, now)
+	if len(top) != 3 {
+		t.Fatalf("got %d destinations, want 3", len(top))
+	}
+	want := []DestinationTraffic{
+		{Destination: "a.com", UploadBytes: 101, DownloadBytes: 1001},
+		{Destination: "1.2.3.4", UploadBytes: 500},
+		{Destination: "b.com", UploadBytes: 10, DownloadBytes: 10},
+	}
+	for i := range want {
+		if top[i] != want[i] {
+			t.Errorf("top[%d] = %+v, want %+v", i, top[i], want[i])
+		}
+	}
+
+	top = s.topWithTime(1, now)
+	if len(top) != 1 || top[0].Destination != "a.com" {
+		t.Errorf("Top(1) = %+v, want a.com only", top)
+	}
+
+	// The traffic of the earliest hour is out of the window.
+	top = s.topWithTime(0, now.Add(time.Hour))
+	if len(top) != 3 || top[2] != (DestinationTraffic{Destination: "a.com", UploadBytes: 1, DownloadBytes: 1}) {
+		t.Errorf("Top() after one hour = %+v", top)
+	}
+
+	// The bucket of the earliest hour is reused.
+	s.addWithTime("d.com", 1, 0, now.Add(time.Hour))
+	top = s.topWithTime(0, now.Add(time.Hour))
+	if len(top) != 4 {
+		t.Errorf("got %d destinations, want 4", len(top))
+	}
+}
+
+func TestDestinationStatsOthers(t *testing.T) {
+	s := NewDestinationStats(1)
+	now := time.Now()
+	for i := 0; i < maxDestinationsPerHour+10; i++ {
+		s.addWithTime(fmt.Sprintf("%d.com", i), 1, 0, now)
+	}
+	top := s.topWithTime(0, now)
+	if len(top) != maxDestinationsPerHour+1 {
+		t.Fatalf("got %d destinations, want %d", len(top), maxDestinationsPerHour+1)
+	}
+	if top[0] != (DestinationTraffic{Destination: OtherDestinations, UploadBytes: 10}) {
+		t.Errorf("top[0] = %+v, want %s", top[0], OtherDestinations)
+	}
+}
@@ -250,6 +250,7 @@ func FromMetricPB(src *pb.Metric) (Metric, error) {
 // LogMetricsNow writes the current metrics to log.
 // This function can be called when (periodic) logging is disabled.
 func LogMetricsNow() {
+	UpdateRuntimeMetrics()
 	log.Infof("[metrics]")
 	list := MetricGroupList{}
 	metricMap.Range(func(k, v any) bool {
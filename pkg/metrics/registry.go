@@ -67,8 +67,18 @@ func GetMetricsForUser(userName string) []Metric {
 	return group.GetAll()
 }
 
+// GetMetricsForUserGroup returns all metrics related to the user group.
+func GetMetricsForUserGroup(groupName string) []Metric {
+	group := GetMetricGroupByName(fmt.Sprintf(UserGroupMetricGroupFormat, groupName))
+	if group == nil {
+		return nil
+	}
+	return group.GetAll()
+}
+
 // GetMetricsAsJSON returns a JSON representation of all the metrics.
 func GetMetricsAsJSON() ([]byte, error) {
+	UpdateRuntimeMetrics()
 	list := MetricGroupList{}
 	metricMap.Range(func(k, v any) bool {
 		group := v.(*MetricGroup)
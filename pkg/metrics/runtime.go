@@ -0,0 +1,99 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// Number of bytes in in-use heap spans.
+	HeapInUseBytes = RegisterMetric("runtime", "HeapInUseBytes", GAUGE)
+
+	// Number of goroutines that currently exist.
+	Goroutines = RegisterMetric("runtime", "Goroutines", GAUGE)
+
+	// The 99th percentile of recent GC stop-the-world pause time, in microseconds.
+	GCPauseP99Micros = RegisterMetric("runtime", "GCPauseP99Micros", GAUGE)
+
+	// Number of open file descriptors, including network sockets.
+	// It is not updated if the operating system doesn't support it.
+	OpenFDs = RegisterMetric("runtime", "OpenFDs", GAUGE)
+)
+
+// runtimeMetricsMinInterval is the minimum interval to refresh runtime
+// metrics. runtime.ReadMemStats() stops the world, so it should not be
+// called too often.
+const runtimeMetricsMinInterval = time.Second
+
+var (
+	runtimeMetricsUpdateTime time.Time
+	runtimeMetricsMu         sync.Mutex
+)
+
+// UpdateRuntimeMetrics refreshes the runtime metrics.
+// It is called before the metrics are logged or exported.
+func UpdateRuntimeMetrics() {
+	runtimeMetricsMu.Lock()
+	defer runtimeMetricsMu.Unlock()
+	if time.Since(runtimeMetricsUpdateTime) < runtimeMetricsMinInterval {
+		return
+	}
+	runtimeMetricsUpdateTime = time.Now()
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	HeapInUseBytes.Store(int64(stats.HeapInuse))
+	Goroutines.Store(int64(runtime.NumGoroutine()))
+	GCPauseP99Micros.Store(gcPauseP99(&stats).Microseconds())
+	if n, err := openFDCount(); err == nil {
+		OpenFDs.Store(int64(n))
+	}
+}
+
+// gcPauseP99 returns the 99th percentile of GC pause time among the
+// most recent garbage collections recorded in the memory statistics.
+func gcPauseP99(stats *runtime.MemStats) time.Duration {
+	n := int(stats.NumGC)
+	if n > len(stats.PauseNs) {
+		n = len(stats.PauseNs)
+	}
+	if n == 0 {
+		return 0
+	}
+	pauses := make([]uint64, n)
+	copy(pauses, stats.PauseNs[:n])
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i] < pauses[j] })
+	return time.Duration(pauses[(n*99-1)/100])
+}
+
+// openFDCount returns the number of open file descriptors of this process.
+func openFDCount() (int, error) {
+	var err error
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		var entries []os.DirEntry
+		entries, err = os.ReadDir(dir)
+		if err == nil {
+			// Exclude the descriptor used to read the directory.
+			return len(entries) - 1, nil
+		}
+	}
+	return 0, err
+}
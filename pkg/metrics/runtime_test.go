@@ -0,0 +1,55 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestUpdateRuntimeMetrics(t *testing.T) {
+	runtime.GC()
+	runtimeMetricsMu.Lock()
+	runtimeMetricsUpdateTime = time.Time{}
+	runtimeMetricsMu.Unlock()
+
+	UpdateRuntimeMetrics()
+	if HeapInUseBytes.Load() <= 0 {
+		t.Errorf("HeapInUseBytes = %d, want > 0", HeapInUseBytes.Load())
+	}
+	if Goroutines.Load() <= 0 {
+		t.Errorf("Goroutines = %d, want > 0", Goroutines.Load())
+	}
+	if runtime.GOOS == "linux" && OpenFDs.Load() <= 0 {
+		t.Errorf("OpenFDs = %d, want > 0", OpenFDs.Load())
+	}
+}
+
+func TestGCPauseP99(t *testing.T) {
+	stats := &runtime.MemStats{}
+	if got := gcPauseP99(stats); got != 0 {
+		t.Errorf("gcPauseP99() = %v, want 0", got)
+	}
+	stats.NumGC = 1000
+	for i := range stats.PauseNs {
+		stats.PauseNs[i] = uint64(i + 1)
+	}
+	// 256 pauses are recorded, the 99th percentile is the 254th.
+	if got := gcPauseP99(stats); got != 254 {
+		t.Errorf("gcPauseP99() = %d, want 254", got)
+	}
+}
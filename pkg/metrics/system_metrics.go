@@ -16,12 +16,17 @@
 package metrics
 
 const (
-	userMetricGroupPrefix = "user - "
+	userMetricGroupPrefix      = "user - "
+	userGroupMetricGroupPrefix = "user group - "
 )
 
 const (
 	UserMetricGroupFormat = userMetricGroupPrefix + "%s"
 
+	// UserGroupMetricGroupFormat is the metric group of a user group.
+	// It has the same metrics as a user.
+	UserGroupMetricGroupFormat = userGroupMetricGroupPrefix + "%s"
+
 	UserMetricDownloadBytes = "DownloadBytes"
 	UserMetricUploadBytes   = "UploadBytes"
 )
@@ -39,6 +44,18 @@ var (
 	// Current number of established connections.
 	CurrEstablished = RegisterMetric("connections", "CurrEstablished", GAUGE)
 
+	// Number of sessions rejected by server because it is overloaded.
+	OverloadRejects = RegisterMetric("connections", "OverloadRejects", COUNTER)
+
+	// Number of sessions rejected by server because the user is disabled or expired.
+	DisabledUserRejects = RegisterMetric("connections", "DisabledUserRejects", COUNTER)
+
+	// Number of retry later signals received from server.
+	RetryLaterReceived = RegisterMetric("connections", "RetryLaterReceived", COUNTER)
+
+	// Number of sessions that received maintenance announcement from server.
+	MaintenanceReceived = RegisterMetric("connections", "MaintenanceReceived", COUNTER)
+
 	// Number of bytes from server to client.
 	DownloadBytes = RegisterMetric("traffic", "DownloadBytes", COUNTER)
 
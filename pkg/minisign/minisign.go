@@ -0,0 +1,204 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+// Package minisign verifies signatures created by the minisign tool,
+// which uses Ed25519. See https://jedisct1.github.io/minisign/ for the
+// format of public keys and signatures.
+package minisign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	untrustedCommentPrefix = "untrusted comment: "
+	trustedCommentPrefix   = "trusted comment: "
+
+	keyIDSize = 8
+)
+
+var (
+	// algorithmEd25519 signs the message.
+	algorithmEd25519 = [2]byte{'E', 'd'}
+
+	// algorithmHashedEd25519 signs the BLAKE2b-512 hash of the message.
+	algorithmHashedEd25519 = [2]byte{'E', 'D'}
+
+	// ErrUntrustedKey is returned when the signature is not created by
+	// any of the trusted public keys.
+	ErrUntrustedKey = errors.New("signature is not created by a trusted public key")
+)
+
+// KeyID identifies a key pair.
+type KeyID [keyIDSize]byte
+
+// String returns the key ID in the format printed by minisign,
+// for example 5F0B6AA8AF02E7F2.
+func (id KeyID) String() string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(id[:]))
+}
+
+// ParseKeyID parses the key ID in the format printed by minisign.
+func ParseKeyID(s string) (KeyID, error) {
+	var id KeyID
+	var n uint64
+	if len(s) != 16 {
+		return id, fmt.Errorf("key ID %q doesn't have 16 hex digits", s)
+	}
+	if _, err := fmt.Sscanf(s, "%016X", &n); err != nil {
+		return id, fmt.Errorf("key ID %q is invalid: %w", s, err)
+	}
+	binary.LittleEndian.PutUint64(id[:], n)
+	return id, nil
+}
+
+// PublicKey is a minisign public key.
+type PublicKey struct {
+	ID  KeyID
+	Key ed25519.PublicKey
+}
+
+// ParsePublicKey parses a public key. It can be the base64 encoded
+// public key, or the content of a minisign public key file.
+func ParsePublicKey(s string) (PublicKey, error) {
+	var pk PublicKey
+	var encoded string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, untrustedCommentPrefix) {
+			continue
+		}
+		if encoded != "" {
+			return pk, fmt.Errorf("public key has more than one line")
+		}
+		encoded = line
+	}
+	b, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return pk, fmt.Errorf("public key is not base64 encoded: %w", err)
+	}
+	if len(b) != 2+keyIDSize+ed25519.PublicKeySize {
+		return pk, fmt.Errorf("public key has %d bytes, want %d bytes", len(b), 2+keyIDSize+ed25519.PublicKeySize)
+	}
+	if !bytes.Equal(b[:2], algorithmEd25519[:]) {
+		return pk, fmt.Errorf("public key algorithm %q is not supported", b[:2])
+	}
+	copy(pk.ID[:], b[2:2+keyIDSize])
+	pk.Key = ed25519.PublicKey(append([]byte{}, b[2+keyIDSize:]...))
+	return pk, nil
+}
+
+// String returns the base64 encoded public key.
+func (pk PublicKey) String() string {
+	b := make([]byte, 0, 2+keyIDSize+ed25519.PublicKeySize)
+	b = append(b, algorithmEd25519[:]...)
+	b = append(b, pk.ID[:]...)
+	b = append(b, pk.Key...)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// Signature is a minisign signature.
+type Signature struct {
+	Algorithm       [2]byte
+	KeyID           KeyID
+	Signature       []byte
+	TrustedComment  string
+	GlobalSignature []byte
+}
+
+// ParseSignature parses the content of a minisign signature file.
+func ParseSignature(b []byte) (*Signature, error) {
+	lines := strings.Split(strings.TrimRight(string(b), "\r\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+	if len(lines) != 4 {
+		return nil, fmt.Errorf("signature has %d lines, want 4 lines", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], untrustedCommentPrefix) {
+		return nil, fmt.Errorf("untrusted comment is not found in signature")
+	}
+	if !strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return nil, fmt.Errorf("trusted comment is not found in signature")
+	}
+	sigBytes, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil {
+		return nil, fmt.Errorf("signature is not base64 encoded: %w", err)
+	}
+	if len(sigBytes) != 2+keyIDSize+ed25519.SignatureSize {
+		return nil, fmt.Errorf("signature has %d bytes, want %d bytes", len(sigBytes), 2+keyIDSize+ed25519.SignatureSize)
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil {
+		return nil, fmt.Errorf("global signature is not base64 encoded: %w", err)
+	}
+	if len(globalSig) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("global signature has %d bytes, want %d bytes", len(globalSig), ed25519.SignatureSize)
+	}
+	sig := &Signature{
+		Signature:       sigBytes[2+keyIDSize:],
+		TrustedComment:  strings.TrimPrefix(lines[2], trustedCommentPrefix),
+		GlobalSignature: globalSig,
+	}
+	copy(sig.Algorithm[:], sigBytes[:2])
+	copy(sig.KeyID[:], sigBytes[2:2+keyIDSize])
+	if sig.Algorithm != algorithmEd25519 && sig.Algorithm != algorithmHashedEd25519 {
+		return nil, fmt.Errorf("signature algorithm %q is not supported", sig.Algorithm[:])
+	}
+	return sig, nil
+}
+
+// Verify checks the signature of the message with the public key.
+func (pk PublicKey) Verify(message []byte, sig *Signature) error {
+	if sig.KeyID != pk.ID {
+		return fmt.Errorf("signature is created by key %s, not key %s", sig.KeyID, pk.ID)
+	}
+	signed := message
+	if sig.Algorithm == algorithmHashedEd25519 {
+		h := blake2b.Sum512(message)
+		signed = h[:]
+	}
+	if !ed25519.Verify(pk.Key, signed, sig.Signature) {
+		return fmt.Errorf("signature verification failed")
+	}
+	global := append(append([]byte{}, sig.Signature...), sig.TrustedComment...)
+	if !ed25519.Verify(pk.Key, global, sig.GlobalSignature) {
+		return fmt.Errorf("trusted comment verification failed")
+	}
+	return nil
+}
+
+// Verify checks the signature of the message with the public key that
+// has the same key ID as the signature. It returns ErrUntrustedKey if
+// no public key matches.
+func Verify(keys []PublicKey, message, signature []byte) (PublicKey, error) {
+	sig, err := ParseSignature(signature)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	for _, pk := range keys {
+		if pk.ID == sig.KeyID {
+			return pk, pk.Verify(message, sig)
+		}
+	}
+	return PublicKey{}, fmt.Errorf("%w: key ID is %s", ErrUntrustedKey, sig.KeyID)
+}
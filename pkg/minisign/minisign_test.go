@@ -0,0 +1,103 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package minisign
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/enfein/mieru/v3/pkg/testtool"
+)
+
+func newTestSigner(t *testing.T) *testtool.MinisignSigner {
+	t.Helper()
+	s, err := testtool.NewMinisignSigner()
+	if err != nil {
+		t.Fatalf("NewMinisignSigner() failed: %v", err)
+	}
+	return s
+}
+
+func TestKeyID(t *testing.T) {
+	id, err := ParseKeyID("5F0B6AA8AF02E7F2")
+	if err != nil {
+		t.Fatalf("ParseKeyID() failed: %v", err)
+	}
+	if id.String() != "5F0B6AA8AF02E7F2" {
+		t.Errorf("KeyID.String() = %q, want %q", id.String(), "5F0B6AA8AF02E7F2")
+	}
+	if id[0] != 0xF2 {
+		t.Errorf("key ID is not little endian")
+	}
+	if _, err := ParseKeyID("5F0B"); err == nil {
+		t.Errorf("ParseKeyID() didn't reject short key ID")
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	s := newTestSigner(t)
+	pk, err := ParsePublicKey(s.PublicKeyFile())
+	if err != nil {
+		t.Fatalf("ParsePublicKey() failed: %v", err)
+	}
+	if pk.ID.String() != s.KeyID() {
+		t.Errorf("key ID is %s, want %s", pk.ID, s.KeyID())
+	}
+	again, err := ParsePublicKey(pk.String())
+	if err != nil {
+		t.Fatalf("ParsePublicKey() failed: %v", err)
+	}
+	if again.ID != pk.ID || !again.Key.Equal(pk.Key) {
+		t.Errorf("public key doesn't match after String()")
+	}
+	if _, err := ParsePublicKey("not a key"); err == nil {
+		t.Errorf("ParsePublicKey() didn't reject invalid public key")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	s := newTestSigner(t)
+	other := newTestSigner(t)
+	pk, _ := ParsePublicKey(s.PublicKeyFile())
+	otherPK, _ := ParsePublicKey(other.PublicKeyFile())
+	message := []byte("mieru release")
+
+	for _, hashed := range []bool{false, true} {
+		sig := s.Sign(message, hashed, "timestamp:1700000000\tfile:mieru.tar.gz")
+		got, err := Verify([]PublicKey{otherPK, pk}, message, sig)
+		if err != nil {
+			t.Fatalf("Verify() failed: %v", err)
+		}
+		if got.ID != pk.ID {
+			t.Errorf("Verify() returned key %s, want %s", got.ID, pk.ID)
+		}
+		if _, err := Verify([]PublicKey{pk}, []byte("tampered"), sig); err == nil {
+			t.Errorf("Verify() didn't reject tampered message")
+		}
+		if _, err := Verify([]PublicKey{otherPK}, message, sig); !errors.Is(err, ErrUntrustedKey) {
+			t.Errorf("Verify() returned %v, want %v", err, ErrUntrustedKey)
+		}
+	}
+
+	// Trusted comment is protected by the global signature.
+	sig, err := ParseSignature(s.Sign(message, true, "original"))
+	if err != nil {
+		t.Fatalf("ParseSignature() failed: %v", err)
+	}
+	sig.TrustedComment = "changed"
+	if err := pk.Verify(message, sig); err == nil {
+		t.Errorf("Verify() didn't reject changed trusted comment")
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package minisign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// TrustedKey is a public key in the trust store.
+type TrustedKey struct {
+	PublicKey
+
+	// Pinned is true if the key is shipped in the binary.
+	Pinned bool
+}
+
+// TrustStore holds the public keys that are trusted to sign files.
+// It has the pinned public keys shipped in the binary, and the changes
+// made by the user, which are stored in a file. A pinned public key
+// can be revoked by the user.
+type TrustStore struct {
+	mu      sync.Mutex
+	path    string
+	pinned  []PublicKey
+	added   []PublicKey
+	revoked map[KeyID]struct{}
+}
+
+// trustFile is the JSON format of the trust store file.
+type trustFile struct {
+	PublicKeys    []string `json:"publicKeys,omitempty"`
+	RevokedKeyIDs []string `json:"revokedKeyIDs,omitempty"`
+}
+
+// LoadTrustStore creates a trust store with the pinned public keys,
+// and applies the changes stored in the file. It is not an error if
+// the file doesn't exist. If the path is empty, the trust store only
+// has the pinned public keys and can't be changed.
+func LoadTrustStore(path string, pinned []string) (*TrustStore, error) {
+	ts := &TrustStore{
+		path:    path,
+		revoked: make(map[KeyID]struct{}),
+	}
+	for _, s := range pinned {
+		pk, err := ParsePublicKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("pinned public key %q is invalid: %w", s, err)
+		}
+		ts.pinned = append(ts.pinned, pk)
+	}
+	if path == "" {
+		return ts, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ts, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("os.ReadFile(%q) failed: %w", path, err)
+	}
+	var f trustFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal() failed: %w", err)
+	}
+	for _, s := range f.PublicKeys {
+		pk, err := ParsePublicKey(s)
+		if err != nil {
+			return nil, fmt.Errorf("public key %q in %q is invalid: %w", s, path, err)
+		}
+		ts.added = append(ts.added, pk)
+	}
+	for _, s := range f.RevokedKeyIDs {
+		id, err := ParseKeyID(s)
+		if err != nil {
+			return nil, fmt.Errorf("revoked key ID in %q is invalid: %w", path, err)
+		}
+		ts.revoked[id] = struct{}{}
+	}
+	return ts, nil
+}
+
+// Keys returns the trusted public keys sorted by key ID.
+// The revoked pinned public keys are not included.
+func (ts *TrustStore) Keys() []TrustedKey {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.keysLocked()
+}
+
+func (ts *TrustStore) keysLocked() []TrustedKey {
+	var keys []TrustedKey
+	seen := make(map[KeyID]struct{})
+	for _, pk := range ts.pinned {
+		if _, ok := ts.revoked[pk.ID]; ok {
+			continue
+		}
+		seen[pk.ID] = struct{}{}
+		keys = append(keys, TrustedKey{PublicKey: pk, Pinned: true})
+	}
+	for _, pk := range ts.added {
+		if _, ok := seen[pk.ID]; ok {
+			continue
+		}
+		seen[pk.ID] = struct{}{}
+		keys = append(keys, TrustedKey{PublicKey: pk})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID.String() < keys[j].ID.String()
+	})
+	return keys
+}
+
+// Empty returns true if no public key is trusted.
+func (ts *TrustStore) Empty() bool {
+	return len(ts.Keys()) == 0
+}
+
+// Add trusts the public key. If it is a revoked pinned public key,
+// it is trusted again. The change is stored to the file.
+func (ts *TrustStore) Add(pk PublicKey) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.path == "" {
+		return fmt.Errorf("trust store can't be changed")
+	}
+	delete(ts.revoked, pk.ID)
+	pinned := false
+	for _, p := range ts.pinned {
+		if p.ID == pk.ID && p.Key.Equal(pk.Key) {
+			pinned = true
+		}
+	}
+	if !pinned {
+		for _, p := range ts.added {
+			if p.ID == pk.ID {
+				return fmt.Errorf("public key with key ID %s is already trusted", pk.ID)
+			}
+		}
+		ts.added = append(ts.added, pk)
+	}
+	return ts.storeLocked()
+}
+
+// Remove stops trusting the public key with the key ID. A pinned
+// public key is revoked. The change is stored to the file.
+func (ts *TrustStore) Remove(id KeyID) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.path == "" {
+		return fmt.Errorf("trust store can't be changed")
+	}
+	found := false
+	for i, pk := range ts.added {
+		if pk.ID == id {
+			ts.added = append(ts.added[:i], ts.added[i+1:]...)
+			found = true
+			break
+		}
+	}
+	for _, pk := range ts.pinned {
+		if pk.ID == id {
+			ts.revoked[id] = struct{}{}
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("public key with key ID %s is not trusted", id)
+	}
+	return ts.storeLocked()
+}
+
+func (ts *TrustStore) storeLocked() error {
+	var f trustFile
+	for _, pk := range ts.added {
+		f.PublicKeys = append(f.PublicKeys, pk.String())
+	}
+	for id := range ts.revoked {
+		f.RevokedKeyIDs = append(f.RevokedKeyIDs, id.String())
+	}
+	sort.Strings(f.RevokedKeyIDs)
+	b, err := json.MarshalIndent(f, "", "    ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent() failed: %w", err)
+	}
+	if err := os.WriteFile(ts.path, b, 0644); err != nil {
+		return fmt.Errorf("os.WriteFile(%q) failed: %w", ts.path, err)
+	}
+	return nil
+}
+
+// Verify checks the signature of the message with the trusted public
+// keys. It returns the public key that created the signature.
+func (ts *TrustStore) Verify(message, signature []byte) (PublicKey, error) {
+	var keys []PublicKey
+	for _, k := range ts.Keys() {
+		keys = append(keys, k.PublicKey)
+	}
+	return Verify(keys, message, signature)
+}
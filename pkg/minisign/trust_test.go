@@ -0,0 +1,78 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package minisign
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrustStore(t *testing.T) {
+	pinned := newTestSigner(t)
+	added := newTestSigner(t)
+	pinnedPK, _ := ParsePublicKey(pinned.PublicKeyFile())
+	addedPK, _ := ParsePublicKey(added.PublicKeyFile())
+	path := filepath.Join(t.TempDir(), "trusted_keys.json")
+	message := []byte("geosite.dat")
+
+	ts, err := LoadTrustStore(path, []string{pinnedPK.String()})
+	if err != nil {
+		t.Fatalf("LoadTrustStore() failed: %v", err)
+	}
+	if keys := ts.Keys(); len(keys) != 1 || !keys[0].Pinned {
+		t.Fatalf("trust store keys are unexpected: %v", keys)
+	}
+	if _, err := ts.Verify(message, added.Sign(message, true, "")); !errors.Is(err, ErrUntrustedKey) {
+		t.Errorf("Verify() returned %v, want %v", err, ErrUntrustedKey)
+	}
+
+	// Rotate the trust from the pinned key to the added key.
+	if err := ts.Add(addedPK); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if err := ts.Add(addedPK); err == nil {
+		t.Errorf("Add() didn't reject duplicated key")
+	}
+	if err := ts.Remove(pinnedPK.ID); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+
+	// The changes are stored.
+	ts, err = LoadTrustStore(path, []string{pinnedPK.String()})
+	if err != nil {
+		t.Fatalf("LoadTrustStore() failed: %v", err)
+	}
+	if keys := ts.Keys(); len(keys) != 1 || keys[0].ID != addedPK.ID || keys[0].Pinned {
+		t.Fatalf("trust store keys are unexpected: %v", keys)
+	}
+	if _, err := ts.Verify(message, added.Sign(message, true, "")); err != nil {
+		t.Errorf("Verify() failed: %v", err)
+	}
+	if _, err := ts.Verify(message, pinned.Sign(message, true, "")); !errors.Is(err, ErrUntrustedKey) {
+		t.Errorf("Verify() returned %v, want %v", err, ErrUntrustedKey)
+	}
+
+	// A revoked pinned key can be trusted again.
+	if err := ts.Add(pinnedPK); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if len(ts.Keys()) != 2 {
+		t.Errorf("got %d keys, want 2", len(ts.Keys()))
+	}
+	if err := ts.Remove(KeyID{}); err == nil {
+		t.Errorf("Remove() didn't reject unknown key")
+	}
+}
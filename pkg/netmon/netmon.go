@@ -0,0 +1,193 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package netmon watches the network interfaces and IP addresses of the
+// system, and reports when the network is changed, for example when a
+// laptop switches from Wi-Fi to a mobile hotspot.
+package netmon
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+const (
+	// debounceDelay is the time to wait after the last event before
+	// checking the network. A network switch usually produces a burst
+	// of interface, address and route events.
+	debounceDelay = time.Second
+
+	// pollInterval is the interval to check the network if the events
+	// of the system can't be watched.
+	pollInterval = 5 * time.Second
+)
+
+var (
+	// NetworkChanges is the number of times the network is changed.
+	NetworkChanges = metrics.RegisterMetric("network monitor", "Changes", metrics.COUNTER)
+
+	// NetworkEvents is the number of interface, address and route events
+	// received from the system.
+	NetworkEvents = metrics.RegisterMetric("network monitor", "Events", metrics.COUNTER)
+)
+
+// Monitor calls the handler when the network is changed. The network is
+// changed if the IP addresses of the active network interfaces are
+// different from the last check.
+type Monitor struct {
+	handler     func()
+	fingerprint func() string
+
+	mu      sync.Mutex
+	last    string
+	events  chan struct{}
+	done    chan struct{}
+	closeMu sync.Once
+}
+
+// New returns a network monitor that calls the handler when the network
+// is changed. The monitor doesn't run until Start is called.
+func New(handler func()) *Monitor {
+	return &Monitor{
+		handler:     handler,
+		fingerprint: networkFingerprint,
+		events:      make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start runs the monitor in the background. It watches the events of the
+// system if it is supported: netlink on Linux, route socket on macOS and
+// FreeBSD, and IP helper notifications on Windows. Otherwise, the network
+// is checked periodically.
+func (m *Monitor) Start() {
+	m.mu.Lock()
+	m.last = m.fingerprint()
+	m.mu.Unlock()
+	go func() {
+		err := watch(m.done, m.notify)
+		select {
+		case <-m.done:
+			return
+		default:
+		}
+		log.Infof("Unable to watch network events, check network every %v: %v", pollInterval, err)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.check()
+			case <-m.done:
+				return
+			}
+		}
+	}()
+	go m.debounceLoop()
+}
+
+// Close stops the monitor. The handler is not called after Close returns,
+// unless it is already running.
+func (m *Monitor) Close() error {
+	m.closeMu.Do(func() {
+		close(m.done)
+	})
+	return nil
+}
+
+// notify is called by the system watcher when an event is received.
+func (m *Monitor) notify() {
+	NetworkEvents.Add(1)
+	select {
+	case m.events <- struct{}{}:
+	default:
+	}
+}
+
+// debounceLoop checks the network after the events stop for a while.
+func (m *Monitor) debounceLoop() {
+	timer := time.NewTimer(debounceDelay)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-m.events:
+			timer.Reset(debounceDelay)
+		case <-timer.C:
+			m.check()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// check calls the handler if the network is changed since the last check.
+// It returns true if the network is changed.
+func (m *Monitor) check() bool {
+	current := m.fingerprint()
+	m.mu.Lock()
+	changed := current != m.last
+	previous := m.last
+	m.last = current
+	m.mu.Unlock()
+	if !changed {
+		return false
+	}
+	select {
+	case <-m.done:
+		return false
+	default:
+	}
+	NetworkChanges.Add(1)
+	log.Infof("Network is changed from [%s] to [%s]", previous, current)
+	m.handler()
+	return true
+}
+
+// networkFingerprint returns the sorted IP addresses of the network
+// interfaces that are up. Loopback interfaces and link local addresses
+// are excluded, because they don't reach the proxy server.
+func networkFingerprint() string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		log.Debugf("net.Interfaces() failed: %v", err)
+		return ""
+	}
+	var addrs []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		ifaceAddrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range ifaceAddrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || !ipNet.IP.IsGlobalUnicast() {
+				continue
+			}
+			addrs = append(addrs, iface.Name+"/"+ipNet.IP.String())
+		}
+	}
+	sort.Strings(addrs)
+	return strings.Join(addrs, " ")
+}
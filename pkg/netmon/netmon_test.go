@@ -0,0 +1,84 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package netmon
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMonitorCheck(t *testing.T) {
+	var calls atomic.Int32
+	var fingerprint atomic.Value
+	fingerprint.Store("eth0/192.168.1.2")
+	m := New(func() { calls.Add(1) })
+	m.fingerprint = func() string { return fingerprint.Load().(string) }
+	m.Start()
+	defer m.Close()
+
+	if m.check() {
+		t.Errorf("check() = true, want false when network is not changed")
+	}
+	fingerprint.Store("wlan0/172.20.10.2")
+	if !m.check() {
+		t.Errorf("check() = false, want true when network is changed")
+	}
+	if m.check() {
+		t.Errorf("check() = true, want false after network change is reported")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler is called %d times, want 1", got)
+	}
+}
+
+func TestMonitorDebounce(t *testing.T) {
+	var calls atomic.Int32
+	var fingerprint atomic.Value
+	fingerprint.Store("eth0/192.168.1.2")
+	m := New(func() { calls.Add(1) })
+	m.fingerprint = func() string { return fingerprint.Load().(string) }
+	m.Start()
+	defer m.Close()
+
+	// A burst of events is checked once.
+	fingerprint.Store("wlan0/172.20.10.2")
+	for i := 0; i < 10; i++ {
+		m.notify()
+	}
+	deadline := time.Now().Add(5 * debounceDelay)
+	for calls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler is called %d times, want 1", got)
+	}
+
+	m.Close()
+	fingerprint.Store("eth0/192.168.1.2")
+	m.notify()
+	time.Sleep(2 * debounceDelay)
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler is called %d times after Close(), want 1", got)
+	}
+}
+
+func TestNetworkFingerprint(t *testing.T) {
+	// The fingerprint is stable if the network is not changed.
+	if a, b := networkFingerprint(), networkFingerprint(); a != b {
+		t.Errorf("networkFingerprint() = %q and %q, want the same", a, b)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build darwin || freebsd
+
+package netmon
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// watch receives the interface, address and route messages from a route
+// socket until done is closed.
+func watch(done <-chan struct{}, notify func()) error {
+	fd, err := unix.Socket(unix.AF_ROUTE, unix.SOCK_RAW, unix.AF_UNSPEC)
+	if err != nil {
+		return fmt.Errorf("create route socket failed: %w", err)
+	}
+	defer unix.Close(fd)
+	unix.CloseOnExec(fd)
+	// Wake up periodically to check if the monitor is closed.
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{Sec: 1}); err != nil {
+		return fmt.Errorf("set route socket receive timeout failed: %w", err)
+	}
+	buf := make([]byte, 65536)
+	for {
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+		n, err := unix.Read(fd, buf)
+		if err != nil {
+			if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+				continue
+			}
+			if errors.Is(err, unix.ENOBUFS) {
+				notify()
+				continue
+			}
+			return fmt.Errorf("read from route socket failed: %w", err)
+		}
+		// The message type is the 4th byte of the routing message header.
+		if n < 4 {
+			continue
+		}
+		switch int(buf[3]) {
+		case unix.RTM_ADD, unix.RTM_DELETE, unix.RTM_CHANGE, unix.RTM_NEWADDR, unix.RTM_DELADDR, unix.RTM_IFINFO:
+			notify()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package netmon
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// watch receives the link, address and route events from a netlink
+// socket until done is closed.
+func watch(done <-chan struct{}, notify func()) error {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return fmt.Errorf("create netlink socket failed: %w", err)
+	}
+	defer unix.Close(fd)
+	sa := &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: unix.RTMGRP_LINK | unix.RTMGRP_IPV4_IFADDR | unix.RTMGRP_IPV6_IFADDR | unix.RTMGRP_IPV4_ROUTE | unix.RTMGRP_IPV6_ROUTE,
+	}
+	if err := unix.Bind(fd, sa); err != nil {
+		return fmt.Errorf("bind netlink socket failed: %w", err)
+	}
+	// Wake up periodically to check if the monitor is closed.
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{Sec: 1}); err != nil {
+		return fmt.Errorf("set netlink socket receive timeout failed: %w", err)
+	}
+	buf := make([]byte, 65536)
+	for {
+		select {
+		case <-done:
+			return nil
+		default:
+		}
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+				continue
+			}
+			if errors.Is(err, unix.ENOBUFS) {
+				// Some events are dropped, but the network is checked anyway.
+				notify()
+				continue
+			}
+			return fmt.Errorf("receive from netlink socket failed: %w", err)
+		}
+		if n > 0 {
+			notify()
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package netmon
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	// notifyFuncs are the watchers to call from the IP helper callbacks.
+	notifyFuncs   = make(map[*func()]struct{})
+	notifyFuncsMu sync.Mutex
+
+	// notifyCallback is created once, because the number of callbacks
+	// that can be created in a process is limited.
+	notifyCallback = windows.NewCallback(func(callerContext, row uintptr, notificationType uint32) uintptr {
+		notifyFuncsMu.Lock()
+		defer notifyFuncsMu.Unlock()
+		for f := range notifyFuncs {
+			(*f)()
+		}
+		return 0
+	})
+)
+
+// watch registers the IP interface and unicast IP address change
+// notifications until done is closed.
+func watch(done <-chan struct{}, notify func()) error {
+	notifyFuncsMu.Lock()
+	notifyFuncs[&notify] = struct{}{}
+	notifyFuncsMu.Unlock()
+	defer func() {
+		notifyFuncsMu.Lock()
+		delete(notifyFuncs, &notify)
+		notifyFuncsMu.Unlock()
+	}()
+
+	var interfaceHandle, addressHandle windows.Handle
+	if err := windows.NotifyIpInterfaceChange(windows.AF_UNSPEC, notifyCallback, nil, false, &interfaceHandle); err != nil {
+		return fmt.Errorf("NotifyIpInterfaceChange() failed: %w", err)
+	}
+	defer windows.CancelMibChangeNotify2(interfaceHandle)
+	if err := windows.NotifyUnicastIpAddressChange(windows.AF_UNSPEC, notifyCallback, nil, false, &addressHandle); err != nil {
+		return fmt.Errorf("NotifyUnicastIpAddressChange() failed: %w", err)
+	}
+	defer windows.CancelMibChangeNotify2(addressHandle)
+	<-done
+	return nil
+}
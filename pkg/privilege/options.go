@@ -0,0 +1,53 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package privilege drops the privileges of the running process,
+// after it has acquired the resources that need privileges,
+// e.g. listening to ports below 1024.
+package privilege
+
+// Options specifies how to drop privileges.
+type Options struct {
+	// If not empty, switch to this user and its primary group.
+	// This requires the process to run as root.
+	User string
+
+	// If not empty, switch to this group instead of the primary group
+	// of the user. This requires the process to run as root.
+	Group string
+
+	// If not empty, change the root directory of the process to this
+	// directory before switching the user. This requires the process
+	// to run as root.
+	Chroot string
+
+	// Keep the capability to listen to ports below 1024,
+	// if the process has it.
+	KeepNetBindService bool
+}
+
+// SandboxOptions specifies the file system access allowed in the sandbox.
+// Paths that don't exist are ignored.
+type SandboxOptions struct {
+	// Files and directories that can be read.
+	ReadOnlyPaths []string
+
+	// Files and directories that can be read and written.
+	ReadWritePaths []string
+
+	// Only allow the system calls needed by the Go runtime and a proxy,
+	// instead of blocking the system calls known to be dangerous.
+	SeccompAllowlist bool
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+
+package privilege
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	"golang.org/x/sys/unix"
+)
+
+// ErrAllThreadsUnsupported is returned when the system calls can't be
+// applied to all the threads. This happens if the program is built with cgo.
+var ErrAllThreadsUnsupported = errors.New("system call on all threads is not supported, build the program with CGO_ENABLED=0")
+
+// Drop changes the root directory and switches to the user and group
+// given by the options, if any, and then drops all the Linux capabilities
+// except the ones to keep. It applies to all the threads of the process.
+// It returns an error if the privileges can't be fully dropped.
+func Drop(opts Options) error {
+	keep := uint32(0)
+	if opts.KeepNetBindService {
+		keep = 1 << unix.CAP_NET_BIND_SERVICE
+	}
+	permitted, err := permittedCapabilities()
+	if err != nil {
+		return err
+	}
+	keep &= permitted
+
+	// Look up the user before changing the root directory,
+	// which may not have the user database.
+	var cred *credential
+	if opts.User != "" || opts.Group != "" {
+		if cred, err = lookupCredential(opts.User, opts.Group); err != nil {
+			return err
+		}
+	}
+	if opts.Chroot != "" {
+		if err := changeRoot(opts.Chroot); err != nil {
+			return err
+		}
+	}
+	if cred != nil {
+		if err := switchUser(*cred, keep != 0); err != nil {
+			return err
+		}
+	}
+
+	// Clear ambient capabilities, so they are not passed to child processes.
+	if err := allThreadsSyscall(syscall.SYS_PRCTL, unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0); err != nil && err != syscall.EINVAL {
+		return fmt.Errorf("prctl(PR_CAP_AMBIENT_CLEAR_ALL) failed: %w", err)
+	}
+	if err := setCapabilities(keep); err != nil {
+		return err
+	}
+	log.Infof("Dropped privileges, running as uid %d gid %d with capabilities %#x", os.Getuid(), os.Getgid(), keep)
+	return nil
+}
+
+// credential is the user and group to switch to.
+type credential struct {
+	uid int
+	gid int
+}
+
+// lookupCredential returns the IDs of the user and group. If the user name
+// is empty, the current user is used. If the group name is empty, the
+// primary group of the user is used.
+func lookupCredential(userName, groupName string) (*credential, error) {
+	cred := &credential{uid: os.Getuid(), gid: os.Getgid()}
+	if userName != "" {
+		u, err := user.Lookup(userName)
+		if err != nil {
+			return nil, fmt.Errorf("user.Lookup() failed: %w", err)
+		}
+		if cred.uid, err = strconv.Atoi(u.Uid); err != nil {
+			return nil, fmt.Errorf("invalid uid %q of user %q", u.Uid, userName)
+		}
+		if cred.gid, err = strconv.Atoi(u.Gid); err != nil {
+			return nil, fmt.Errorf("invalid gid %q of user %q", u.Gid, userName)
+		}
+	}
+	if groupName != "" {
+		g, err := user.LookupGroup(groupName)
+		if err != nil {
+			return nil, fmt.Errorf("user.LookupGroup() failed: %w", err)
+		}
+		if cred.gid, err = strconv.Atoi(g.Gid); err != nil {
+			return nil, fmt.Errorf("invalid gid %q of group %q", g.Gid, groupName)
+		}
+	}
+	return cred, nil
+}
+
+// changeRoot changes the root directory and the working directory
+// of the process to the directory.
+func changeRoot(dir string) error {
+	if os.Geteuid() != 0 {
+		return fmt.Errorf("changing root directory to %q requires root", dir)
+	}
+	// Go applies these system calls to all the threads.
+	if err := syscall.Chroot(dir); err != nil {
+		return fmt.Errorf("syscall.Chroot(%q) failed: %w", dir, err)
+	}
+	if err := syscall.Chdir("/"); err != nil {
+		return fmt.Errorf("syscall.Chdir() failed: %w", err)
+	}
+	return nil
+}
+
+// switchUser changes the user, group and supplementary groups of the process.
+func switchUser(cred credential, keepCaps bool) error {
+	uid, gid := cred.uid, cred.gid
+	if os.Getuid() == uid && os.Getgid() == gid {
+		return nil
+	}
+	if os.Geteuid() != 0 {
+		return fmt.Errorf("switching to uid %d gid %d requires root", uid, gid)
+	}
+	if keepCaps {
+		// Without this, all the capabilities are cleared when uid changes.
+		if err := allThreadsSyscall(syscall.SYS_PRCTL, unix.PR_SET_KEEPCAPS, 1, 0); err != nil {
+			return fmt.Errorf("prctl(PR_SET_KEEPCAPS) failed: %w", err)
+		}
+	}
+	// Go applies these system calls to all the threads.
+	if err := syscall.Setgroups([]int{gid}); err != nil {
+		return fmt.Errorf("syscall.Setgroups() failed: %w", err)
+	}
+	if err := syscall.Setgid(gid); err != nil {
+		return fmt.Errorf("syscall.Setgid() failed: %w", err)
+	}
+	if err := syscall.Setuid(uid); err != nil {
+		return fmt.Errorf("syscall.Setuid() failed: %w", err)
+	}
+	return nil
+}
+
+// permittedCapabilities returns the lower 32 bits of the permitted
+// capability set of the calling thread.
+func permittedCapabilities() (uint32, error) {
+	header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	var data [2]unix.CapUserData
+	if err := unix.Capget(&header, &data[0]); err != nil {
+		return 0, fmt.Errorf("unix.Capget() failed: %w", err)
+	}
+	return data[0].Permitted, nil
+}
+
+// setCapabilities sets the effective and permitted capability sets
+// of all the threads, and clears the inheritable set.
+func setCapabilities(caps uint32) error {
+	header := &unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	data := &[2]unix.CapUserData{}
+	data[0].Effective = caps
+	data[0].Permitted = caps
+	err := allThreadsSyscall(syscall.SYS_CAPSET, uintptr(unsafe.Pointer(header)), uintptr(unsafe.Pointer(&data[0])), 0)
+	runtime.KeepAlive(header)
+	runtime.KeepAlive(data)
+	if err != nil {
+		return fmt.Errorf("capset() failed: %w", err)
+	}
+	return nil
+}
+
+// allThreadsSyscall runs the system call in all the threads of the process.
+// It returns the errno if the system call failed.
+func allThreadsSyscall(trap, a1, a2, a3 uintptr) error {
+	_, _, errno := syscall.AllThreadsSyscall(trap, a1, a2, a3)
+	if errno == syscall.ENOTSUP {
+		return ErrAllThreadsUnsupported
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
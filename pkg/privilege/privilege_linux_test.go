@@ -0,0 +1,316 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+
+package privilege
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	helperEnv    = "MIERU_PRIVILEGE_TEST_HELPER"
+	helperDirEnv = "MIERU_PRIVILEGE_TEST_DIR"
+
+	// exitUnsupported is the exit code of the helper process
+	// if the feature under test is not supported.
+	exitUnsupported = 2
+)
+
+// TestMain runs the test binary as a helper process that drops its
+// privileges, so the test process itself is not impacted.
+func TestMain(m *testing.M) {
+	var err error
+	switch os.Getenv(helperEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "drop":
+		err = dropHelper()
+	case "sandbox":
+		err = sandboxHelper(os.Getenv(helperDirEnv), false)
+	case "sandbox-allowlist":
+		err = sandboxHelper(os.Getenv(helperDirEnv), true)
+	case "chroot":
+		err = chrootHelper(os.Getenv(helperDirEnv))
+	default:
+		err = fmt.Errorf("unknown helper %q", os.Getenv(helperEnv))
+	}
+	if err != nil {
+		println(err.Error())
+		if errors.Is(err, ErrAllThreadsUnsupported) {
+			os.Exit(exitUnsupported)
+		}
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func dropHelper() error {
+	if err := Drop(Options{}); err != nil {
+		return fmt.Errorf("Drop() failed: %w", err)
+	}
+	// Check the capabilities from several threads.
+	var wg sync.WaitGroup
+	errCh := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			caps, err := permittedCapabilities()
+			if err != nil {
+				errCh <- err
+			} else if caps != 0 {
+				errCh <- fmt.Errorf("permitted capabilities %#x are not dropped", caps)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+	return <-errCh
+}
+
+func chrootHelper(dir string) error {
+	if err := Drop(Options{User: "nobody", Chroot: dir}); err != nil {
+		return fmt.Errorf("Drop() failed: %w", err)
+	}
+	if os.Getuid() == 0 || os.Geteuid() == 0 {
+		return fmt.Errorf("still running as root")
+	}
+	if _, err := os.Stat("/marker"); err != nil {
+		return fmt.Errorf("root directory is not changed: %w", err)
+	}
+	if err := syscall.Chroot("/"); err == nil {
+		return fmt.Errorf("chroot succeeded after privileges are dropped")
+	}
+	return nil
+}
+
+func sandboxHelper(dir string, allowlist bool) error {
+	readOnlyDir := filepath.Join(dir, "ro")
+	readWriteDir := filepath.Join(dir, "rw")
+	layers, err := Sandbox(SandboxOptions{
+		ReadOnlyPaths:    []string{readOnlyDir},
+		ReadWritePaths:   []string{readWriteDir, filepath.Join(dir, "not-exist")},
+		SeccompAllowlist: allowlist,
+	})
+	if err != nil {
+		return fmt.Errorf("Sandbox() failed: %w", err)
+	}
+	if len(layers) == 0 {
+		return ErrAllThreadsUnsupported
+	}
+	applied := map[string]bool{}
+	for _, layer := range layers {
+		applied[layer] = true
+	}
+
+	// Allowed operations.
+	if err := os.WriteFile(filepath.Join(readWriteDir, "b"), []byte("b"), 0644); err != nil {
+		return fmt.Errorf("write to read write directory failed: %w", err)
+	}
+	if _, err := os.ReadFile(filepath.Join(readOnlyDir, "a")); err != nil {
+		return fmt.Errorf("read from read only directory failed: %w", err)
+	}
+	if err := os.Rename(filepath.Join(readWriteDir, "b"), filepath.Join(readWriteDir, "c")); err != nil {
+		return fmt.Errorf("rename in read write directory failed: %w", err)
+	}
+	if err := echoHelper(); err != nil {
+		return err
+	}
+	runtime.GC()
+
+	// Denied operations.
+	if applied["landlock"] {
+		if err := os.WriteFile(filepath.Join(readOnlyDir, "b"), []byte("b"), 0644); err == nil {
+			return fmt.Errorf("write to read only directory succeeded")
+		}
+		if _, err := os.ReadFile(filepath.Join(dir, "outside")); err == nil {
+			return fmt.Errorf("read outside of allowed paths succeeded")
+		}
+	}
+	if applied["seccomp"] || applied["seccomp-allowlist"] {
+		if err := exec.Command("/bin/true").Run(); err == nil {
+			return fmt.Errorf("run a program succeeded")
+		}
+	}
+	if applied["seccomp-allowlist"] {
+		if _, err := unix.Getpriority(unix.PRIO_PROCESS, 0); err != unix.EPERM {
+			return fmt.Errorf("getpriority() returned %v, want %v", err, unix.EPERM)
+		}
+	} else if allowlist && applied["seccomp"] {
+		return fmt.Errorf("seccomp denylist is applied instead of allowlist")
+	}
+	return nil
+}
+
+// echoHelper sends data through TCP and UDP connections from several
+// goroutines, so the system calls used by a proxy are called.
+func echoHelper() error {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("net.Listen() failed: %w", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	var wg sync.WaitGroup
+	errCh := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				errCh <- fmt.Errorf("net.Dial() failed: %w", err)
+				return
+			}
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(10 * time.Second))
+			want := []byte("mieru")
+			if _, err := conn.Write(want); err != nil {
+				errCh <- fmt.Errorf("Write() failed: %w", err)
+				return
+			}
+			got := make([]byte, len(want))
+			if _, err := io.ReadFull(conn, got); err != nil {
+				errCh <- fmt.Errorf("ReadFull() failed: %w", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+	if err := <-errCh; err != nil {
+		return err
+	}
+
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return fmt.Errorf("net.ListenUDP() failed: %w", err)
+	}
+	defer udpConn.Close()
+	if _, err := udpConn.WriteTo([]byte("mieru"), udpConn.LocalAddr()); err != nil {
+		return fmt.Errorf("WriteTo() failed: %w", err)
+	}
+	udpConn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	buf := make([]byte, 16)
+	if _, _, err := udpConn.ReadFrom(buf); err != nil {
+		return fmt.Errorf("ReadFrom() failed: %w", err)
+	}
+	return nil
+}
+
+// runHelper runs the test binary as a helper process.
+func runHelper(t *testing.T, helper string, env ...string) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), helperEnv+"="+helper)
+	cmd.Env = append(cmd.Env, env...)
+	out, err := cmd.CombinedOutput()
+	if cmd.ProcessState != nil && cmd.ProcessState.ExitCode() == exitUnsupported {
+		t.Skipf("%s", out)
+	}
+	if err != nil {
+		t.Fatalf("helper process failed: %v, output: %s", err, out)
+	}
+}
+
+func TestDrop(t *testing.T) {
+	runHelper(t, "drop")
+}
+
+func TestSandbox(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "ro"), 0755); err != nil {
+		t.Fatalf("os.Mkdir() failed: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "rw"), 0755); err != nil {
+		t.Fatalf("os.Mkdir() failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ro", "a"), []byte("a"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "outside"), []byte("outside"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	runHelper(t, "sandbox", helperDirEnv+"="+dir)
+}
+
+func TestSandboxSeccompAllowlist(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"ro", "rw"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatalf("os.Mkdir() failed: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ro", "a"), []byte("a"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "outside"), []byte("outside"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	runHelper(t, "sandbox-allowlist", helperDirEnv+"="+dir)
+}
+
+func TestSwitchUserNotFound(t *testing.T) {
+	if _, err := lookupCredential("mieru-no-such-user", ""); err == nil {
+		t.Errorf("lookupCredential() succeeded with a user that doesn't exist")
+	}
+	if _, err := lookupCredential("", "mieru-no-such-group"); err == nil {
+		t.Errorf("lookupCredential() succeeded with a group that doesn't exist")
+	}
+}
+
+func TestDropChroot(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("chroot requires root")
+	}
+	if _, err := lookupCredential("nobody", ""); err != nil {
+		t.Skipf("user nobody is not found: %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0755); err != nil {
+		t.Fatalf("os.Chmod() failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "marker"), []byte("marker"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	runHelper(t, "chroot", helperDirEnv+"="+dir)
+}
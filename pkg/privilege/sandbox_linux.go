@@ -0,0 +1,163 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+
+package privilege
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	landlockReadDir = unix.LANDLOCK_ACCESS_FS_READ_DIR | unix.LANDLOCK_ACCESS_FS_READ_FILE
+
+	landlockWriteDir = unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+	// landlockFileRights are the rights that can be granted to a file.
+	landlockFileRights = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE
+
+	// landlockABI1Rights are the rights handled by Landlock ABI version 1.
+	landlockABI1Rights = 1<<13 - 1
+)
+
+// sandboxOnce makes sure the sandbox is only applied once,
+// so the restrictions are not stacked when the proxy restarts.
+var (
+	sandboxOnce   sync.Once
+	sandboxLayers []string
+	sandboxErr    error
+)
+
+// Sandbox restricts the running process and all its threads.
+// It sets no new privileges, restricts the file system access with
+// Landlock, and blocks system calls not needed by a proxy with seccomp.
+// The features not supported by the kernel are skipped.
+// It returns the names of the features applied.
+func Sandbox(opts SandboxOptions) ([]string, error) {
+	sandboxOnce.Do(func() {
+		sandboxLayers, sandboxErr = sandbox(opts)
+	})
+	return sandboxLayers, sandboxErr
+}
+
+func sandbox(opts SandboxOptions) ([]string, error) {
+	var layers []string
+	if err := allThreadsSyscall(syscall.SYS_PRCTL, unix.PR_SET_NO_NEW_PRIVS, 1, 0); err != nil {
+		if err == ErrAllThreadsUnsupported {
+			log.Warnf("Sandbox is not applied: %v", err)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("prctl(PR_SET_NO_NEW_PRIVS) failed: %w", err)
+	}
+	layers = append(layers, "no-new-privs")
+
+	applied, err := applyLandlock(opts)
+	if err != nil {
+		return layers, err
+	}
+	if applied {
+		layers = append(layers, "landlock")
+	}
+
+	applied, err = applySeccomp(opts.SeccompAllowlist)
+	if err != nil {
+		return layers, err
+	}
+	if applied && opts.SeccompAllowlist {
+		layers = append(layers, "seccomp-allowlist")
+	} else if applied {
+		layers = append(layers, "seccomp")
+	}
+	return layers, nil
+}
+
+// applyLandlock restricts the file system access. It returns false
+// if Landlock is not supported or not enabled by the kernel.
+func applyLandlock(opts SandboxOptions) (bool, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno == unix.ENOSYS || errno == unix.EOPNOTSUPP {
+		log.Infof("Landlock is not supported by the kernel")
+		return false, nil
+	}
+	if errno != 0 {
+		return false, fmt.Errorf("landlock_create_ruleset() failed: %w", errno)
+	}
+	handled := uint64(landlockABI1Rights)
+	if abi >= 3 {
+		handled |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	attr := &unix.LandlockRulesetAttr{Access_fs: handled}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(attr)), unsafe.Sizeof(*attr), 0)
+	if errno != 0 {
+		return false, fmt.Errorf("landlock_create_ruleset() failed: %w", errno)
+	}
+	defer unix.Close(int(fd))
+
+	for _, path := range opts.ReadOnlyPaths {
+		if err := addLandlockRule(int(fd), path, landlockReadDir&handled); err != nil {
+			return false, err
+		}
+	}
+	for _, path := range opts.ReadWritePaths {
+		if err := addLandlockRule(int(fd), path, (landlockReadDir|landlockWriteDir)&handled); err != nil {
+			return false, err
+		}
+	}
+	if err := allThreadsSyscall(unix.SYS_LANDLOCK_RESTRICT_SELF, fd, 0, 0); err != nil {
+		return false, fmt.Errorf("landlock_restrict_self() failed: %w", err)
+	}
+	return true, nil
+}
+
+// addLandlockRule allows the access to a file or directory.
+func addLandlockRule(rulesetFd int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("open %q failed: %w", path, err)
+	}
+	defer unix.Close(fd)
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("fstat %q failed: %w", path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFileRights
+	}
+	rule := &unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFd), unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(rule)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("landlock_add_rule() for %q failed: %w", path, errno)
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux && (amd64 || arm64 || riscv64)
+
+package privilege
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	"golang.org/x/sys/unix"
+)
+
+// seccompDeniedSyscalls are the system calls blocked by the sandbox.
+// A proxy never needs them, while they are useful to an attacker who
+// takes over the process.
+var seccompDeniedSyscalls = []uintptr{
+	unix.SYS_ACCT,
+	unix.SYS_ADD_KEY,
+	unix.SYS_ADJTIMEX,
+	unix.SYS_BPF,
+	unix.SYS_CHROOT,
+	unix.SYS_CLOCK_ADJTIME,
+	unix.SYS_CLOCK_SETTIME,
+	unix.SYS_DELETE_MODULE,
+	unix.SYS_EXECVE,
+	unix.SYS_EXECVEAT,
+	unix.SYS_FANOTIFY_INIT,
+	unix.SYS_FINIT_MODULE,
+	unix.SYS_INIT_MODULE,
+	unix.SYS_KEXEC_FILE_LOAD,
+	unix.SYS_KEXEC_LOAD,
+	unix.SYS_KEYCTL,
+	unix.SYS_MOUNT,
+	unix.SYS_NAME_TO_HANDLE_AT,
+	unix.SYS_OPEN_BY_HANDLE_AT,
+	unix.SYS_PERF_EVENT_OPEN,
+	unix.SYS_PIDFD_GETFD,
+	unix.SYS_PIVOT_ROOT,
+	unix.SYS_PROCESS_VM_READV,
+	unix.SYS_PROCESS_VM_WRITEV,
+	unix.SYS_PTRACE,
+	unix.SYS_QUOTACTL,
+	unix.SYS_REBOOT,
+	unix.SYS_REQUEST_KEY,
+	unix.SYS_SETDOMAINNAME,
+	unix.SYS_SETHOSTNAME,
+	unix.SYS_SETNS,
+	unix.SYS_SETTIMEOFDAY,
+	unix.SYS_SWAPOFF,
+	unix.SYS_SWAPON,
+	unix.SYS_UMOUNT2,
+	unix.SYS_UNSHARE,
+	unix.SYS_USERFAULTFD,
+	unix.SYS_VHANGUP,
+}
+
+// seccompAllowedSyscalls are the system calls allowed by the allowlist
+// profile on all the supported CPU architectures. They cover the Go
+// runtime, network I/O and file I/O of the proxy server. The system calls
+// that only exist in some CPU architectures are in seccompArchSyscalls.
+var seccompAllowedSyscalls = []uintptr{
+	unix.SYS_ACCEPT,
+	unix.SYS_ACCEPT4,
+	unix.SYS_BIND,
+	unix.SYS_BRK,
+	unix.SYS_CAPGET,
+	unix.SYS_CLOCK_GETRES,
+	unix.SYS_CLOCK_GETTIME,
+	unix.SYS_CLOCK_NANOSLEEP,
+	unix.SYS_CLONE,
+	unix.SYS_CLOSE,
+	unix.SYS_CONNECT,
+	unix.SYS_COPY_FILE_RANGE,
+	unix.SYS_DUP,
+	unix.SYS_DUP3,
+	unix.SYS_EPOLL_CREATE1,
+	unix.SYS_EPOLL_CTL,
+	unix.SYS_EPOLL_PWAIT,
+	unix.SYS_EPOLL_PWAIT2,
+	unix.SYS_EVENTFD2,
+	unix.SYS_EXIT,
+	unix.SYS_EXIT_GROUP,
+	unix.SYS_FACCESSAT,
+	unix.SYS_FACCESSAT2,
+	unix.SYS_FCHMOD,
+	unix.SYS_FCHMODAT,
+	unix.SYS_FCHOWN,
+	unix.SYS_FCHOWNAT,
+	unix.SYS_FCNTL,
+	unix.SYS_FDATASYNC,
+	unix.SYS_FLOCK,
+	unix.SYS_FSTAT,
+	unix.SYS_FSTATFS,
+	unix.SYS_FSYNC,
+	unix.SYS_FTRUNCATE,
+	unix.SYS_FUTEX,
+	unix.SYS_GETCWD,
+	unix.SYS_GETDENTS64,
+	unix.SYS_GETEGID,
+	unix.SYS_GETEUID,
+	unix.SYS_GETGID,
+	unix.SYS_GETGROUPS,
+	unix.SYS_GETITIMER,
+	unix.SYS_GETPEERNAME,
+	unix.SYS_GETPID,
+	unix.SYS_GETPPID,
+	unix.SYS_GETRANDOM,
+	unix.SYS_GETRLIMIT,
+	unix.SYS_GETRUSAGE,
+	unix.SYS_GETSOCKNAME,
+	unix.SYS_GETSOCKOPT,
+	unix.SYS_GETTID,
+	unix.SYS_GETTIMEOFDAY,
+	unix.SYS_GETUID,
+	unix.SYS_IOCTL,
+	unix.SYS_KILL,
+	unix.SYS_LISTEN,
+	unix.SYS_LSEEK,
+	unix.SYS_MADVISE,
+	unix.SYS_MEMBARRIER,
+	unix.SYS_MINCORE,
+	unix.SYS_MKDIRAT,
+	unix.SYS_MMAP,
+	unix.SYS_MPROTECT,
+	unix.SYS_MREMAP,
+	unix.SYS_MUNMAP,
+	unix.SYS_NANOSLEEP,
+	unix.SYS_NEWFSTATAT,
+	unix.SYS_OPENAT,
+	unix.SYS_PIPE2,
+	unix.SYS_PPOLL,
+	unix.SYS_PRCTL,
+	unix.SYS_PREAD64,
+	unix.SYS_PRLIMIT64,
+	unix.SYS_PSELECT6,
+	unix.SYS_PWRITE64,
+	unix.SYS_READ,
+	unix.SYS_READLINKAT,
+	unix.SYS_READV,
+	unix.SYS_RECVFROM,
+	unix.SYS_RECVMMSG,
+	unix.SYS_RECVMSG,
+	unix.SYS_RENAMEAT2,
+	unix.SYS_RESTART_SYSCALL,
+	unix.SYS_RSEQ,
+	unix.SYS_RT_SIGACTION,
+	unix.SYS_RT_SIGPROCMASK,
+	unix.SYS_RT_SIGRETURN,
+	unix.SYS_SCHED_GETAFFINITY,
+	unix.SYS_SCHED_YIELD,
+	unix.SYS_SENDFILE,
+	unix.SYS_SENDMMSG,
+	unix.SYS_SENDMSG,
+	unix.SYS_SENDTO,
+	unix.SYS_SET_ROBUST_LIST,
+	unix.SYS_SET_TID_ADDRESS,
+	unix.SYS_SETITIMER,
+	unix.SYS_SETSOCKOPT,
+	unix.SYS_SHUTDOWN,
+	unix.SYS_SIGALTSTACK,
+	unix.SYS_SOCKET,
+	unix.SYS_SOCKETPAIR,
+	unix.SYS_SPLICE,
+	unix.SYS_STATFS,
+	unix.SYS_STATX,
+	unix.SYS_SYSINFO,
+	unix.SYS_TGKILL,
+	unix.SYS_TIMER_CREATE,
+	unix.SYS_TIMER_DELETE,
+	unix.SYS_TIMER_GETOVERRUN,
+	unix.SYS_TIMER_GETTIME,
+	unix.SYS_TIMER_SETTIME,
+	unix.SYS_TKILL,
+	unix.SYS_UNAME,
+	unix.SYS_UNLINKAT,
+	unix.SYS_UTIMENSAT,
+	unix.SYS_WRITE,
+	unix.SYS_WRITEV,
+}
+
+// seccompX32SyscallBit is set in the system call numbers of x32 ABI.
+// The system call numbers of other architectures never have this bit.
+const seccompX32SyscallBit = 0x40000000
+
+// applySeccomp installs a seccomp filter to all the threads. The blocked
+// system calls fail with EPERM. If allowlist is true, only the system calls
+// in the allowlist are allowed, otherwise only the system calls in the
+// denylist are blocked. It returns false if seccomp filter is not supported
+// by the kernel.
+func applySeccomp(allowlist bool) (bool, error) {
+	var filter []unix.SockFilter
+	if allowlist {
+		syscalls := append([]uintptr{}, seccompAllowedSyscalls...)
+		filter = seccompFilter(append(syscalls, seccompArchSyscalls...), true)
+	} else {
+		filter = seccompFilter(seccompDeniedSyscalls, false)
+	}
+	prog := &unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER, unix.SECCOMP_FILTER_FLAG_TSYNC, uintptr(unsafe.Pointer(prog)))
+	runtime.KeepAlive(filter)
+	if errno == unix.ENOSYS || errno == unix.EINVAL {
+		log.Infof("seccomp filter is not supported by the kernel")
+		return false, nil
+	}
+	if errno != 0 {
+		return false, fmt.Errorf("seccomp() failed: %w", errno)
+	}
+	return true, nil
+}
+
+// seccompFilter returns the BPF program of the seccomp filter. If allowlist
+// is true, the syscalls are allowed and the others are blocked, otherwise
+// the syscalls are blocked and the others are allowed. System calls of
+// other CPU architectures and x32 ABI are always blocked.
+func seccompFilter(syscalls []uintptr, allowlist bool) []unix.SockFilter {
+	var arch uint32 = unix.AUDIT_ARCH_X86_64
+	switch runtime.GOARCH {
+	case "arm64":
+		arch = unix.AUDIT_ARCH_AARCH64
+	case "riscv64":
+		arch = unix.AUDIT_ARCH_RISCV64
+	}
+	// Layout: load arch, check arch, load nr, check x32, check each
+	// syscall, the action if no syscall matches, the action of a match.
+	// BPF jumps are limited to 255 instructions, which allows up to
+	// 254 syscalls.
+	n := len(syscalls)
+	if n > 254 {
+		panic(fmt.Sprintf("too many syscalls in seccomp filter: %d", n))
+	}
+	allow, deny := 4+n, 4+n+1
+	match := deny
+	if allowlist {
+		allow, deny = deny, allow
+		match = allow
+	}
+	filter := make([]unix.SockFilter, 0, n+6)
+	jump := func(code uint16, k uint32, jt, jf int) {
+		idx := len(filter)
+		var t, f uint8
+		if jt >= 0 {
+			t = uint8(jt - idx - 1)
+		}
+		if jf >= 0 {
+			f = uint8(jf - idx - 1)
+		}
+		filter = append(filter, unix.SockFilter{Code: code, Jt: t, Jf: f, K: k})
+	}
+	stmt := func(code uint16, k uint32) {
+		filter = append(filter, unix.SockFilter{Code: code, K: k})
+	}
+	// Offsets of seccomp_data fields.
+	const nrOffset, archOffset = 0, 4
+	stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, archOffset)
+	jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, arch, -1, deny)
+	stmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, nrOffset)
+	jump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, seccompX32SyscallBit, deny, -1)
+	for _, nr := range syscalls {
+		jump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, uint32(nr), match, -1)
+	}
+	if allowlist {
+		stmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ERRNO|uint32(unix.EPERM))
+		stmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ALLOW)
+	} else {
+		stmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ALLOW)
+		stmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ERRNO|uint32(unix.EPERM))
+	}
+	return filter
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux && (amd64 || arm64 || riscv64)
+
+package privilege
+
+import (
+	"runtime"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// runSeccompFilter runs the BPF program of a seccomp filter
+// and returns the action.
+func runSeccompFilter(t *testing.T, filter []unix.SockFilter, arch, nr uint32) uint32 {
+	t.Helper()
+	var acc uint32
+	for pc := 0; pc < len(filter); pc++ {
+		ins := filter[pc]
+		switch ins.Code {
+		case unix.BPF_LD | unix.BPF_W | unix.BPF_ABS:
+			switch ins.K {
+			case 0:
+				acc = nr
+			case 4:
+				acc = arch
+			default:
+				t.Fatalf("unexpected load offset %d", ins.K)
+			}
+		case unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K:
+			if acc == ins.K {
+				pc += int(ins.Jt)
+			} else {
+				pc += int(ins.Jf)
+			}
+		case unix.BPF_JMP | unix.BPF_JGE | unix.BPF_K:
+			if acc >= ins.K {
+				pc += int(ins.Jt)
+			} else {
+				pc += int(ins.Jf)
+			}
+		case unix.BPF_RET | unix.BPF_K:
+			return ins.K
+		default:
+			t.Fatalf("unexpected instruction code %#x", ins.Code)
+		}
+	}
+	t.Fatalf("seccomp filter doesn't return")
+	return 0
+}
+
+func TestSeccompFilter(t *testing.T) {
+	var arch uint32 = unix.AUDIT_ARCH_X86_64
+	switch runtime.GOARCH {
+	case "arm64":
+		arch = unix.AUDIT_ARCH_AARCH64
+	case "riscv64":
+		arch = unix.AUDIT_ARCH_RISCV64
+	}
+	const allow = unix.SECCOMP_RET_ALLOW
+	const deny = unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM)
+	allowed := append(append([]uintptr{}, seccompAllowedSyscalls...), seccompArchSyscalls...)
+	testCases := []struct {
+		name      string
+		syscalls  []uintptr
+		allowlist bool
+		arch      uint32
+		nr        uint32
+		want      uint32
+	}{
+		{"denylist allows read", seccompDeniedSyscalls, false, arch, unix.SYS_READ, allow},
+		{"denylist blocks execve", seccompDeniedSyscalls, false, arch, unix.SYS_EXECVE, deny},
+		{"denylist blocks last syscall", seccompDeniedSyscalls, false, arch, uint32(seccompDeniedSyscalls[len(seccompDeniedSyscalls)-1]), deny},
+		{"denylist blocks other arch", seccompDeniedSyscalls, false, arch + 1, unix.SYS_READ, deny},
+		{"denylist blocks x32", seccompDeniedSyscalls, false, arch, seccompX32SyscallBit | unix.SYS_READ, deny},
+		{"allowlist allows read", allowed, true, arch, unix.SYS_READ, allow},
+		{"allowlist allows last syscall", allowed, true, arch, uint32(allowed[len(allowed)-1]), allow},
+		{"allowlist blocks execve", allowed, true, arch, unix.SYS_EXECVE, deny},
+		{"allowlist blocks getpriority", allowed, true, arch, unix.SYS_GETPRIORITY, deny},
+		{"allowlist blocks other arch", allowed, true, arch + 1, unix.SYS_READ, deny},
+		{"allowlist blocks x32", allowed, true, arch, seccompX32SyscallBit | unix.SYS_READ, deny},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter := seccompFilter(tc.syscalls, tc.allowlist)
+			if got := runSeccompFilter(t, filter, tc.arch, tc.nr); got != tc.want {
+				t.Errorf("seccomp filter returned %#x, want %#x", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSeccompSyscallListsDisjoint(t *testing.T) {
+	denied := map[uintptr]bool{}
+	for _, nr := range seccompDeniedSyscalls {
+		denied[nr] = true
+	}
+	for _, nr := range append(append([]uintptr{}, seccompAllowedSyscalls...), seccompArchSyscalls...) {
+		if denied[nr] {
+			t.Errorf("system call %d is in both the allowlist and the denylist", nr)
+		}
+	}
+}
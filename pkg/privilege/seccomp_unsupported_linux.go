@@ -0,0 +1,28 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux && !(amd64 || arm64 || riscv64)
+
+package privilege
+
+import (
+	"github.com/enfein/mieru/v3/pkg/log"
+)
+
+// applySeccomp does nothing in unsupported CPU architectures.
+func applySeccomp(allowlist bool) (bool, error) {
+	log.Infof("seccomp filter is not supported in this CPU architecture")
+	return false, nil
+}
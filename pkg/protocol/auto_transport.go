@@ -0,0 +1,107 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"net"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+var (
+	// AutoTransportSwitches is the number of times the proxy client
+	// switched the transport protocol of an AUTO port binding.
+	AutoTransportSwitches = metrics.RegisterMetric("auto transport", "Switches", metrics.COUNTER)
+)
+
+// autoTransportKey returns the key of an AUTO endpoint. The TCP endpoint
+// and the UDP endpoint of the same address and port have the same key.
+func autoTransportKey(addr net.Addr) string {
+	return addr.String()
+}
+
+// updateAutoTransport selects the transport protocol with the lowest
+// latency for each address and port of AUTO endpoints. If the latency
+// of both transport protocols is unknown or unreachable, nothing is
+// selected and both are used. When the selection is changed, the
+// underlays of the other transport protocol don't accept new sessions,
+// so new sessions move to the selected transport protocol.
+// This method MUST be called only when holding the mu lock.
+func (m *Mux) updateAutoTransport() {
+	best := make(map[string]time.Duration)
+	selection := make(map[string]string)
+	m.pathLatencyMu.Lock()
+	for _, p := range m.endpoints {
+		if !isAutoTransport(p) {
+			continue
+		}
+		latency, found := m.pathLatency[pathKey(p.RemoteAddr())]
+		if !found || latency < 0 {
+			continue
+		}
+		key := autoTransportKey(p.RemoteAddr())
+		if lowest, found := best[key]; !found || latency < lowest {
+			best[key] = latency
+			selection[key] = p.RemoteAddr().Network()
+		}
+	}
+	m.pathLatencyMu.Unlock()
+
+	for key, network := range selection {
+		prev, found := m.autoTransport[key]
+		if found && prev == network {
+			continue
+		}
+		if found {
+			AutoTransportSwitches.Add(1)
+			log.Infof("Switch the transport protocol of %s from %s to %s", key, prev, network)
+		} else {
+			log.Infof("Select %s as the transport protocol of %s", network, key)
+		}
+		for _, underlay := range m.underlays {
+			if autoTransportKey(underlay.RemoteAddr()) == key && underlay.RemoteAddr().Network() != network {
+				underlay.Scheduler().Disable()
+			}
+		}
+	}
+	m.autoTransport = selection
+}
+
+// autoTransportEndpoints removes the AUTO endpoints that don't use the
+// selected transport protocol of their address and port. If all the
+// endpoints are removed, the input is returned.
+// This method MUST be called only when holding the mu lock.
+func (m *Mux) autoTransportEndpoints(endpoints []UnderlayProperties) []UnderlayProperties {
+	if len(m.autoTransport) == 0 {
+		return endpoints
+	}
+	selected := make([]UnderlayProperties, 0, len(endpoints))
+	for _, p := range endpoints {
+		if isAutoTransport(p) {
+			network, found := m.autoTransport[autoTransportKey(p.RemoteAddr())]
+			if found && network != p.RemoteAddr().Network() {
+				continue
+			}
+		}
+		selected = append(selected, p)
+	}
+	if len(selected) == 0 {
+		return endpoints
+	}
+	return selected
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/common"
+)
+
+func TestAutoTransportSelection(t *testing.T) {
+	tcpEndpoint := WithAutoTransport(NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9000}))
+	udpEndpoint := WithAutoTransport(NewUnderlayProperties(1400, common.PacketTransport, nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 9000}))
+	otherEndpoint := NewUnderlayProperties(1400, common.PacketTransport, nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.2"), Port: 9000})
+	mux := NewMux(true).SetEndpoints([]UnderlayProperties{tcpEndpoint, udpEndpoint, otherEndpoint})
+	defer mux.Close()
+
+	networks := func() map[string]int {
+		mux.mu.Lock()
+		defer mux.mu.Unlock()
+		res := make(map[string]int)
+		for _, p := range mux.autoTransportEndpoints(mux.endpoints) {
+			res[p.RemoteAddr().Network()+"://"+p.RemoteAddr().String()]++
+		}
+		return res
+	}
+
+	// Both transport protocols are used before the latency is measured.
+	if got := networks(); len(got) != 3 {
+		t.Errorf("endpoints before measurement = %v, want all the endpoints", got)
+	}
+
+	// The first reachable transport protocol is selected.
+	mux.SetPathLatency(udpEndpoint, 20*time.Millisecond, true)
+	got := networks()
+	if len(got) != 2 || got["udp://127.0.0.1:9000"] != 1 || got["udp://127.0.0.2:9000"] != 1 {
+		t.Errorf("endpoints after UDP is measured = %v, want UDP endpoints", got)
+	}
+
+	// Switch to the transport protocol with lower latency.
+	switches := AutoTransportSwitches.Load()
+	mux.SetPathLatency(tcpEndpoint, 10*time.Millisecond, true)
+	got = networks()
+	if len(got) != 2 || got["tcp://127.0.0.1:9000"] != 1 || got["udp://127.0.0.2:9000"] != 1 {
+		t.Errorf("endpoints after TCP is measured = %v, want TCP endpoint and the other endpoint", got)
+	}
+	if AutoTransportSwitches.Load() != switches+1 {
+		t.Errorf("AutoTransportSwitches = %d, want %d", AutoTransportSwitches.Load(), switches+1)
+	}
+
+	// Switch back if the selected transport protocol is unreachable.
+	mux.SetPathLatency(tcpEndpoint, 0, false)
+	got = networks()
+	if len(got) != 2 || got["udp://127.0.0.1:9000"] != 1 {
+		t.Errorf("endpoints after TCP is unreachable = %v, want UDP endpoints", got)
+	}
+
+	// Nothing is selected if both are unreachable.
+	mux.SetPathLatency(udpEndpoint, 0, false)
+	if got := networks(); len(got) != 3 {
+		t.Errorf("endpoints after both are unreachable = %v, want all the endpoints", got)
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/cipher"
+)
+
+// ConformanceVectors are golden test vectors of mieru protocol.
+// Alternative client and server implementations can use them
+// to verify the compatibility with this package.
+type ConformanceVectors struct {
+	Description string              `json:"description"`
+	Keys        []cipher.KeyVector  `json:"keys"`
+	AEAD        []cipher.AEADVector `json:"aead"`
+	Metadata    []MetadataVector    `json:"metadata"`
+	Transcripts []TranscriptVector  `json:"transcripts"`
+}
+
+// MetadataVector is the wire format of a metadata before encryption.
+type MetadataVector struct {
+	Protocol string            `json:"protocol"`
+	Fields   map[string]uint64 `json:"fields"`
+	Encoded  string            `json:"encoded"` // hex encoded
+}
+
+// TranscriptVector is a sequence of segments exchanged by the client
+// and the server in one underlay connection. The segments are encrypted
+// by the cipher key generated from the current salt.
+type TranscriptVector struct {
+	Transport string        `json:"transport"`
+	UserName  string        `json:"userName"`
+	Password  string        `json:"password"`
+	UnixTime  int64         `json:"unixTime"`
+	Segments  []FrameVector `json:"segments"`
+}
+
+// FrameVector is one segment of a transcript. All the byte fields
+// are hex encoded.
+type FrameVector struct {
+	Direction     string `json:"direction"`
+	Nonce         string `json:"nonce"`
+	Metadata      string `json:"metadata"`
+	Payload       string `json:"payload"`
+	PrefixPadding string `json:"prefixPadding"`
+	SuffixPadding string `json:"suffixPadding"`
+	Wire          string `json:"wire"`
+}
+
+const (
+	conformanceUserName = "conformance"
+	conformancePassword = "mieru-test-vectors"
+)
+
+var (
+	// conformanceTime is the time used to generate keys and metadata timestamps.
+	conformanceTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Initial nonces of the transcripts. The first 8 bytes are printable
+	// ASCII characters, same as the nonces generated by the cipher.
+	conformanceClientNonce = []byte("client-nonce-0123456789A")
+	conformanceServerNonce = []byte("server-nonce-0123456789A")
+	conformancePacketNonce = []byte("packet-nonce-0123456789A")
+)
+
+// conformanceFrame is a segment to send in the transcripts.
+type conformanceFrame struct {
+	clientToServer bool
+	metadata       metadata
+	payload        []byte
+	prefixPadding  []byte
+	suffixPadding  []byte
+}
+
+// conformanceFrames returns a session that opens, exchanges data and closes.
+// Each metadata protocol is used once.
+func conformanceFrames() []conformanceFrame {
+	// socks5 CONNECT request to example.com:443, and the reply.
+	connectRequest := []byte{5, 1, 0, 3, 11, 'e', 'x', 'a', 'm', 'p', 'l', 'e', '.', 'c', 'o', 'm', 1, 187}
+	connectReply := []byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}
+	frames := []conformanceFrame{
+		{
+			clientToServer: true,
+			metadata:       &sessionStruct{baseStruct: baseStruct{protocol: uint8(openSessionRequest)}, sessionID: 0x01020304, seq: 0},
+			payload:        connectRequest,
+			suffixPadding:  []byte("abc"),
+		},
+		{
+			clientToServer: false,
+			metadata:       &sessionStruct{baseStruct: baseStruct{protocol: uint8(openSessionResponse)}, sessionID: 0x01020304, seq: 0, statusCode: uint8(statusOK)},
+			payload:        connectReply,
+			suffixPadding:  []byte("xyz"),
+		},
+		{
+			clientToServer: true,
+			metadata:       &dataAckStruct{baseStruct: baseStruct{protocol: uint8(dataClientToServer)}, sessionID: 0x01020304, seq: 1, unAckSeq: 1, windowSize: 256},
+			payload:        []byte("ping"),
+			prefixPadding:  []byte("0"),
+			suffixPadding:  []byte("12"),
+		},
+		{
+			clientToServer: false,
+			metadata:       &dataAckStruct{baseStruct: baseStruct{protocol: uint8(ackServerToClient)}, sessionID: 0x01020304, seq: 2, unAckSeq: 2, windowSize: 256},
+		},
+		{
+			clientToServer: false,
+			metadata:       &dataAckStruct{baseStruct: baseStruct{protocol: uint8(dataServerToClient)}, sessionID: 0x01020304, seq: 1, unAckSeq: 1, windowSize: 256},
+			payload:        []byte("pong"),
+			suffixPadding:  []byte("!"),
+		},
+		{
+			clientToServer: true,
+			metadata:       &dataAckStruct{baseStruct: baseStruct{protocol: uint8(ackClientToServer)}, sessionID: 0x01020304, seq: 2, unAckSeq: 2, windowSize: 256},
+			prefixPadding:  []byte("~"),
+		},
+		{
+			clientToServer: true,
+			metadata:       &sessionStruct{baseStruct: baseStruct{protocol: uint8(closeSessionRequest)}, sessionID: 0x01020304, seq: 2},
+		},
+		{
+			clientToServer: false,
+			metadata:       &sessionStruct{baseStruct: baseStruct{protocol: uint8(closeSessionResponse)}, sessionID: 0x01020304, seq: 2, statusCode: uint8(statusOK)},
+		},
+	}
+	for _, f := range frames {
+		if ss, ok := toSessionStruct(f.metadata); ok {
+			ss.payloadLen = uint16(len(f.payload))
+			ss.suffixLen = uint8(len(f.suffixPadding))
+		} else if das, ok := toDataAckStruct(f.metadata); ok {
+			das.prefixLen = uint8(len(f.prefixPadding))
+			das.payloadLen = uint16(len(f.payload))
+			das.suffixLen = uint8(len(f.suffixPadding))
+		}
+	}
+	return frames
+}
+
+// GenerateConformanceVectors returns the golden test vectors of mieru protocol.
+// The result only depends on the protocol, so it is the same in every run.
+func GenerateConformanceVectors() (*ConformanceVectors, error) {
+	keyVector, err := cipher.NewKeyVector(conformanceUserName, conformancePassword, conformanceTime)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewKeyVector() failed: %w", err)
+	}
+	aeadVectors, err := cipher.NewAEADVectors([]byte("mieru conformance test"))
+	if err != nil {
+		return nil, fmt.Errorf("cipher.NewAEADVectors() failed: %w", err)
+	}
+	v := &ConformanceVectors{
+		Description: "Test vectors of mieru protocol. Byte strings are hex encoded. Transcripts use the key generated from the current salt, which is the second key.",
+		Keys:        []cipher.KeyVector{keyVector},
+		AEAD:        aeadVectors,
+	}
+	for _, f := range conformanceFrames() {
+		v.Metadata = append(v.Metadata, MetadataVector{
+			Protocol: f.metadata.Protocol().String(),
+			Fields:   metadataFields(f.metadata),
+			Encoded:  hex.EncodeToString(marshalMetadataAt(f.metadata, conformanceTime)),
+		})
+	}
+
+	hashedPassword := cipher.HashPassword([]byte(conformancePassword), []byte(conformanceUserName))
+	blocks, err := cipher.BlockCipherListFromPasswordAt(hashedPassword, true, conformanceTime)
+	if err != nil {
+		return nil, fmt.Errorf("cipher.BlockCipherListFromPasswordAt() failed: %w", err)
+	}
+	block := blocks[1]
+	tcp, err := streamTranscript(block, conformanceFrames())
+	if err != nil {
+		return nil, err
+	}
+	udp, err := packetTranscript(block, conformanceFrames())
+	if err != nil {
+		return nil, err
+	}
+	v.Transcripts = []TranscriptVector{tcp, udp}
+	return v, nil
+}
+
+// JSON returns the indented JSON format of the test vectors.
+func (v *ConformanceVectors) JSON() ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("json.MarshalIndent() failed: %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+// streamTranscript encrypts the frames with the TCP segment rules.
+// The nonce is only sent in the first segment of each direction,
+// and it is increased by 1 after each encryption.
+func streamTranscript(block cipher.BlockCipher, frames []conformanceFrame) (TranscriptVector, error) {
+	t := newTranscriptVector("TCP")
+	nonces := map[bool][]byte{
+		true:  conformanceClientNonce,
+		false: conformanceServerNonce,
+	}
+	nonceSent := map[bool]bool{}
+	seal := func(clientToServer bool, plaintext []byte) ([]byte, error) {
+		nonce := nonces[clientToServer]
+		nonces[clientToServer] = increaseNonce(nonce)
+		sealed, err := block.EncryptWithNonce(plaintext, nonce)
+		if err != nil {
+			return nil, fmt.Errorf("EncryptWithNonce() failed: %w", err)
+		}
+		if !nonceSent[clientToServer] {
+			nonceSent[clientToServer] = true
+			return append(append([]byte{}, nonce...), sealed...), nil
+		}
+		return sealed, nil
+	}
+
+	for _, f := range frames {
+		nonce := nonces[f.clientToServer]
+		plaintextMetadata := marshalMetadataAt(f.metadata, conformanceTime)
+		wire, err := seal(f.clientToServer, plaintextMetadata)
+		if err != nil {
+			return t, err
+		}
+		wire = append(wire, f.prefixPadding...)
+		if len(f.payload) > 0 {
+			encryptedPayload, err := seal(f.clientToServer, f.payload)
+			if err != nil {
+				return t, err
+			}
+			wire = append(wire, encryptedPayload...)
+		}
+		wire = append(wire, f.suffixPadding...)
+		t.Segments = append(t.Segments, newFrameVector(f, nonce, plaintextMetadata, wire))
+	}
+	return t, nil
+}
+
+// packetTranscript encrypts the frames with the UDP segment rules.
+// Each segment has its own nonce, which is used to encrypt both
+// the metadata and the payload.
+func packetTranscript(block cipher.BlockCipher, frames []conformanceFrame) (TranscriptVector, error) {
+	t := newTranscriptVector("UDP")
+	nonce := conformancePacketNonce
+	for _, f := range frames {
+		plaintextMetadata := marshalMetadataAt(f.metadata, conformanceTime)
+		encryptedMetadata, err := block.EncryptWithNonce(plaintextMetadata, nonce)
+		if err != nil {
+			return t, fmt.Errorf("EncryptWithNonce() failed: %w", err)
+		}
+		wire := append(append([]byte{}, nonce...), encryptedMetadata...)
+		wire = append(wire, f.prefixPadding...)
+		if len(f.payload) > 0 {
+			encryptedPayload, err := block.EncryptWithNonce(f.payload, nonce)
+			if err != nil {
+				return t, fmt.Errorf("EncryptWithNonce() failed: %w", err)
+			}
+			wire = append(wire, encryptedPayload...)
+		}
+		wire = append(wire, f.suffixPadding...)
+		t.Segments = append(t.Segments, newFrameVector(f, nonce, plaintextMetadata, wire))
+		nonce = increaseNonce(nonce)
+	}
+	return t, nil
+}
+
+func newTranscriptVector(transport string) TranscriptVector {
+	return TranscriptVector{
+		Transport: transport,
+		UserName:  conformanceUserName,
+		Password:  conformancePassword,
+		UnixTime:  conformanceTime.Unix(),
+	}
+}
+
+func newFrameVector(f conformanceFrame, nonce, plaintextMetadata, wire []byte) FrameVector {
+	direction := "serverToClient"
+	if f.clientToServer {
+		direction = "clientToServer"
+	}
+	return FrameVector{
+		Direction:     direction,
+		Nonce:         hex.EncodeToString(nonce),
+		Metadata:      hex.EncodeToString(plaintextMetadata),
+		Payload:       hex.EncodeToString(f.payload),
+		PrefixPadding: hex.EncodeToString(f.prefixPadding),
+		SuffixPadding: hex.EncodeToString(f.suffixPadding),
+		Wire:          hex.EncodeToString(wire),
+	}
+}
+
+// marshalMetadataAt serializes the metadata with the timestamp of the given time.
+func marshalMetadataAt(m metadata, t time.Time) []byte {
+	b := m.Marshal()
+	binary.BigEndian.PutUint32(b[2:], uint32(t.Unix()/60))
+	return b
+}
+
+// metadataFields returns the fields of the metadata, excluding the timestamp.
+func metadataFields(m metadata) map[string]uint64 {
+	if ss, ok := toSessionStruct(m); ok {
+		return map[string]uint64{
+			"protocol":   uint64(ss.protocol),
+			"flags":      uint64(ss.flags),
+			"sessionID":  uint64(ss.sessionID),
+			"seq":        uint64(ss.seq),
+			"statusCode": uint64(ss.statusCode),
+			"payloadLen": uint64(ss.payloadLen),
+			"suffixLen":  uint64(ss.suffixLen),
+		}
+	}
+	if das, ok := toDataAckStruct(m); ok {
+		return map[string]uint64{
+			"protocol":   uint64(das.protocol),
+			"flags":      uint64(das.flags),
+			"sessionID":  uint64(das.sessionID),
+			"seq":        uint64(das.seq),
+			"unAckSeq":   uint64(das.unAckSeq),
+			"windowSize": uint64(das.windowSize),
+			"fragment":   uint64(das.fragment),
+			"prefixLen":  uint64(das.prefixLen),
+			"payloadLen": uint64(das.payloadLen),
+			"suffixLen":  uint64(das.suffixLen),
+		}
+	}
+	return nil
+}
+
+// increaseNonce returns a copy of the nonce increased by 1 in big endian.
+func increaseNonce(nonce []byte) []byte {
+	res := append([]byte{}, nonce...)
+	for i := len(res) - 1; i >= 0; i-- {
+		res[i]++
+		if res[i] != 0 {
+			break
+		}
+	}
+	return res
+}
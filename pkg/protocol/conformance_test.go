@@ -0,0 +1,130 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/enfein/mieru/v3/pkg/cipher"
+)
+
+const conformanceVectorsFile = "testdata/conformance_vectors.json"
+
+func TestConformanceVectorsGolden(t *testing.T) {
+	vectors, err := GenerateConformanceVectors()
+	if err != nil {
+		t.Fatalf("GenerateConformanceVectors() failed: %v", err)
+	}
+	got, err := vectors.JSON()
+	if err != nil {
+		t.Fatalf("JSON() failed: %v", err)
+	}
+	want, err := os.ReadFile(conformanceVectorsFile)
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s is outdated. Run \"go run ./test/cmd/conformancevectors\" from the root of the repository to update it.", conformanceVectorsFile)
+	}
+}
+
+// TestConformanceTranscripts decrypts the golden transcripts with the
+// block ciphers used by the underlays.
+func TestConformanceTranscripts(t *testing.T) {
+	b, err := os.ReadFile(conformanceVectorsFile)
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	var vectors ConformanceVectors
+	if err := json.Unmarshal(b, &vectors); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+
+	for _, transcript := range vectors.Transcripts {
+		t.Run(transcript.Transport, func(t *testing.T) {
+			hashedPassword := cipher.HashPassword([]byte(transcript.Password), []byte(transcript.UserName))
+			stateless := transcript.Transport == "UDP"
+			recv := map[string]cipher.BlockCipher{}
+			for _, direction := range []string{"clientToServer", "serverToClient"} {
+				blocks, err := cipher.BlockCipherListFromPasswordAt(hashedPassword, stateless, conformanceTime)
+				if err != nil {
+					t.Fatalf("BlockCipherListFromPasswordAt() failed: %v", err)
+				}
+				recv[direction] = blocks[1]
+			}
+			nonceReceived := map[string]bool{}
+			for i, segment := range transcript.Segments {
+				wire := mustDecodeHex(t, segment.Wire)
+				prefixPadding := mustDecodeHex(t, segment.PrefixPadding)
+				suffixPadding := mustDecodeHex(t, segment.SuffixPadding)
+				wantMetadata := mustDecodeHex(t, segment.Metadata)
+				wantPayload := mustDecodeHex(t, segment.Payload)
+				block := recv[segment.Direction]
+
+				metadataLen := MetadataLength + cipher.DefaultOverhead
+				if stateless || !nonceReceived[segment.Direction] {
+					metadataLen += cipher.DefaultNonceSize
+					nonceReceived[segment.Direction] = true
+				}
+				gotMetadata, err := block.Decrypt(wire[:metadataLen])
+				if err != nil {
+					t.Fatalf("segment %d: decrypt metadata failed: %v", i, err)
+				}
+				if !bytes.Equal(gotMetadata, wantMetadata) {
+					t.Errorf("segment %d: metadata = %x, want %x", i, gotMetadata, wantMetadata)
+				}
+				wire = wire[metadataLen:]
+				if !bytes.HasPrefix(wire, prefixPadding) {
+					t.Fatalf("segment %d: prefix padding not found", i)
+				}
+				wire = wire[len(prefixPadding):]
+				payloadLen := len(wire) - len(suffixPadding)
+				if len(wantPayload) > 0 {
+					var gotPayload []byte
+					if stateless {
+						gotPayload, err = block.DecryptWithNonce(wire[:payloadLen], mustDecodeHex(t, segment.Nonce))
+					} else {
+						gotPayload, err = block.Decrypt(wire[:payloadLen])
+					}
+					if err != nil {
+						t.Fatalf("segment %d: decrypt payload failed: %v", i, err)
+					}
+					if !bytes.Equal(gotPayload, wantPayload) {
+						t.Errorf("segment %d: payload = %x, want %x", i, gotPayload, wantPayload)
+					}
+				} else if payloadLen != 0 {
+					t.Errorf("segment %d: got %d bytes of unexpected payload", i, payloadLen)
+				}
+				if !bytes.Equal(wire[payloadLen:], suffixPadding) {
+					t.Errorf("segment %d: suffix padding = %x, want %x", i, wire[payloadLen:], suffixPadding)
+				}
+			}
+		})
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("hex.DecodeString() failed: %v", err)
+	}
+	return b
+}
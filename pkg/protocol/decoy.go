@@ -0,0 +1,240 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"io"
+	stdlog "log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+const (
+	// decoyAuthTimeout is the maximum time to receive the first mieru
+	// metadata from a TCP connection before it is handed over to the decoy.
+	decoyAuthTimeout = 10 * time.Second
+
+	// decoyIdleTimeout is the maximum time to wait for the next HTTP
+	// request on a connection served by the decoy.
+	decoyIdleTimeout = 60 * time.Second
+)
+
+var (
+	// DecoyServed is the number of TCP connections served by the decoy,
+	// plus the number of HTTP requests to WebSocket listeners served by it.
+	DecoyServed = metrics.RegisterMetric("decoy", "Served", metrics.COUNTER)
+)
+
+// DecoyOptions are the options of a web server that answers the
+// connections failing authentication, so the proxy server looks like
+// an ordinary web site to active probers.
+//
+// At most one of StaticDirectory and ReverseProxyURL should be set.
+type DecoyOptions struct {
+	// StaticDirectory is a directory of static web pages to serve.
+	StaticDirectory string
+
+	// ReverseProxyURL is the URL of a web site, e.g. "https://www.example.com".
+	// HTTP requests are forwarded to it.
+	ReverseProxyURL string
+}
+
+// handler returns the HTTP handler of the decoy,
+// or nil if the decoy is not configured.
+func (o *DecoyOptions) handler() http.Handler {
+	if o == nil {
+		return nil
+	}
+	if o.StaticDirectory != "" {
+		return http.FileServer(http.Dir(o.StaticDirectory))
+	}
+	if o.ReverseProxyURL != "" {
+		target, err := url.Parse(o.ReverseProxyURL)
+		if err != nil {
+			log.Warnf("Decoy reverse proxy URL %q is invalid: %v", o.ReverseProxyURL, err)
+			return nil
+		}
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		director := proxy.Director
+		proxy.Director = func(req *http.Request) {
+			director(req)
+			// The web site may only serve its own host name.
+			req.Host = target.Host
+		}
+		proxy.ErrorLog = stdlog.New(io.Discard, "", 0)
+		return proxy
+	}
+	return nil
+}
+
+// decoyServer serves HTTP on the connections handed over to it.
+type decoyServer struct {
+	addr   net.Addr
+	server *http.Server
+	conns  chan net.Conn
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// decoyServer is the listener of its own HTTP server.
+var _ net.Listener = &decoyServer{}
+
+// newDecoyServer creates a decoy server with the options.
+// It returns nil if the decoy is not configured.
+func newDecoyServer(opts *DecoyOptions, addr net.Addr) *decoyServer {
+	handler := opts.handler()
+	if handler == nil {
+		return nil
+	}
+	s := &decoyServer{
+		addr:  addr,
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+	s.server = &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: decoyAuthTimeout,
+		IdleTimeout:       decoyIdleTimeout,
+		MaxHeaderBytes:    1 << 16,
+		ErrorLog:          stdlog.New(io.Discard, "", 0),
+	}
+	go s.server.Serve(s)
+	return s
+}
+
+// serve hands over the connection to the HTTP server, which has its
+// own timeouts. The connection is no longer limited by the handshake
+// timeout, so the decoy behaves like a normal web server.
+func (s *decoyServer) serve(conn net.Conn) {
+	DecoyServed.Add(1)
+	common.HandshakeDone(conn)
+	select {
+	case s.conns <- conn:
+	case <-s.done:
+		conn.Close()
+	}
+}
+
+// stop stops the HTTP server and closes the connections it serves.
+func (s *decoyServer) stop() {
+	s.server.Close()
+}
+
+func (s *decoyServer) Accept() (net.Conn, error) {
+	select {
+	case conn := <-s.conns:
+		return conn, nil
+	case <-s.done:
+		return nil, io.ErrClosedPipe
+	}
+}
+
+func (s *decoyServer) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+func (s *decoyServer) Addr() net.Addr {
+	return s.addr
+}
+
+// decoyListener accepts TCP connections from a TCP listener.
+// The first encrypted metadata from the client is checked.
+// Connections that fail authentication are served by the decoy.
+type decoyListener struct {
+	rawListener  net.Listener
+	decoy        *decoyServer
+	authenticate func(encryptedMeta []byte) bool
+	conns        chan net.Conn
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var _ net.Listener = &decoyListener{}
+
+func newDecoyListener(rawListener net.Listener, decoy *decoyServer, authenticate func([]byte) bool) *decoyListener {
+	l := &decoyListener{
+		rawListener:  rawListener,
+		decoy:        decoy,
+		authenticate: authenticate,
+		conns:        make(chan net.Conn),
+		done:         make(chan struct{}),
+	}
+	go l.acceptLoop()
+	return l
+}
+
+func (l *decoyListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, io.ErrClosedPipe
+	}
+}
+
+func (l *decoyListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.done)
+		err = l.rawListener.Close()
+		l.decoy.stop()
+	})
+	return err
+}
+
+func (l *decoyListener) Addr() net.Addr {
+	return l.rawListener.Addr()
+}
+
+func (l *decoyListener) acceptLoop() {
+	defer l.Close()
+	for {
+		conn, err := l.rawListener.Accept()
+		if err != nil {
+			return
+		}
+		go l.handleConn(conn)
+	}
+}
+
+func (l *decoyListener) handleConn(conn net.Conn) {
+	conn.SetDeadline(time.Now().Add(decoyAuthTimeout))
+	prefix, authenticated := readStreamAuthPrefix(conn, l.authenticate)
+	conn.SetDeadline(time.Time{})
+	if authenticated {
+		select {
+		case l.conns <- &prefixConn{Conn: conn, prefix: prefix}:
+		case <-l.done:
+			conn.Close()
+		}
+		return
+	}
+	log.Debugf("Connection from %v failed authentication, serving decoy", conn.RemoteAddr())
+	l.decoy.serve(&prefixConn{Conn: conn, prefix: prefix})
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/testtool"
+)
+
+// httpGetBody sends a HTTP GET request and returns the response body.
+func httpGetBody(t *testing.T, url string) string {
+	t.Helper()
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("io.ReadAll() failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status code = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	return string(body)
+}
+
+func TestDecoyStaticDirectory(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>hello</h1>"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverProperties := WithDecoy(NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil), &DecoyOptions{StaticDirectory: dir})
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	time.Sleep(100 * time.Millisecond)
+	go testServer.Serve(serverMux)
+	defer testServer.Close()
+
+	// Proxy client is not impacted by the decoy.
+	clientProperties := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	runClient(t, clientProperties, []byte("xiaochitang"), []byte("kuiranbudong"), 4)
+
+	// A web browser sees the static web site.
+	served := DecoyServed.Load()
+	if body := httpGetBody(t, fmt.Sprintf("http://127.0.0.1:%d/", port)); body != "<h1>hello</h1>" {
+		t.Errorf("got response %q, want %q", body, "<h1>hello</h1>")
+	}
+	if DecoyServed.Load() == served {
+		t.Errorf("decoy connection is not counted")
+	}
+}
+
+func TestDecoyReverseProxy(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("host=" + r.Host + " path=" + r.URL.Path))
+	}))
+	defer site.Close()
+	siteHost := site.Listener.Addr().String()
+
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverProperties := WithDecoy(NewWebSocketUnderlayProperties(1400, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil, &WebSocketOptions{Path: "/ws"}), &DecoyOptions{ReverseProxyURL: site.URL})
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	time.Sleep(100 * time.Millisecond)
+	go testServer.Serve(serverMux)
+	defer testServer.Close()
+
+	clientProperties := NewWebSocketUnderlayProperties(1400, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, &WebSocketOptions{Path: "/ws"})
+	runClient(t, clientProperties, []byte("xiaochitang"), []byte("kuiranbudong"), 4)
+
+	// Requests to other paths and non-WebSocket requests to the WebSocket
+	// path are forwarded to the web site.
+	for _, path := range []string{"/index.html", "/ws"} {
+		want := "host=" + siteHost + " path=" + path
+		if body := httpGetBody(t, fmt.Sprintf("http://127.0.0.1:%d%s", port, path)); body != want {
+			t.Errorf("got response %q, want %q", body, want)
+		}
+	}
+}
+
+func TestDecoyOptionsHandler(t *testing.T) {
+	var nilOpts *DecoyOptions
+	if nilOpts.handler() != nil {
+		t.Errorf("handler() of nil options is not nil")
+	}
+	if (&DecoyOptions{}).handler() != nil {
+		t.Errorf("handler() of empty options is not nil")
+	}
+	if (&DecoyOptions{ReverseProxyURL: "http://[::1"}).handler() != nil {
+		t.Errorf("handler() of invalid URL is not nil")
+	}
+	if (&DecoyOptions{StaticDirectory: t.TempDir()}).handler() == nil {
+		t.Errorf("handler() of static directory is nil")
+	}
+}
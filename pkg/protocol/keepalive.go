@@ -0,0 +1,167 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"context"
+	"net"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/mathext"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/sockopts"
+)
+
+var (
+	KeepalivesSent     = metrics.RegisterMetric("session keepalive", "Sent", metrics.COUNTER)
+	KeepalivesReceived = metrics.RegisterMetric("session keepalive", "Received", metrics.COUNTER)
+
+	// HeartbeatsMissed is the number of heartbeats sent over packet
+	// transport without receiving anything from the peer since the
+	// previous segment.
+	HeartbeatsMissed = metrics.RegisterMetric("session keepalive", "MissedHeartbeats", metrics.COUNTER)
+
+	// DeadPeerSessions is the number of sessions over packet transport
+	// closed because nothing is received from the peer within the dead
+	// peer timeout.
+	DeadPeerSessions = metrics.RegisterMetric("session keepalive", "DeadPeerSessions", metrics.COUNTER)
+)
+
+// SetKeepalive sends a keepalive to the peer after the session has
+// nothing to send for the interval, so the state of NAT devices and
+// firewalls between the client and the server is not expired.
+// Keepalives are not delivered to the application of the peer.
+// Use 0 to disable keepalives.
+//
+// Sessions over packet transport always send heartbeats, and the
+// interval only takes effect if it is shorter than the heartbeat interval.
+func (s *Session) SetKeepalive(interval time.Duration) {
+	s.keepalive.Store(int64(mathext.Max(interval, 0)))
+}
+
+// SetHeartbeat sets the interval to send heartbeats over packet transport,
+// and the time without receiving anything from the peer before the session
+// is considered dead and closed. Use 0 to use the default values.
+func (s *Session) SetHeartbeat(interval, deadPeerTimeout time.Duration) {
+	s.heartbeat.Store(int64(mathext.Max(interval, 0)))
+	s.deadPeerTimeout.Store(int64(mathext.Max(deadPeerTimeout, 0)))
+}
+
+// heartbeatInterval returns the maximum idle time of the session
+// before an ACK is sent over packet transport.
+func (s *Session) heartbeatInterval() time.Duration {
+	heartbeat := sessionHeartbeatInterval
+	if interval := time.Duration(s.heartbeat.Load()); interval > 0 {
+		heartbeat = interval
+	}
+	if interval := time.Duration(s.keepalive.Load()); interval > 0 {
+		return mathext.Min(interval, heartbeat)
+	}
+	return heartbeat
+}
+
+// deadPeerTimeoutOrDefault returns the maximum time without receiving
+// anything from the peer before the session is closed.
+func (s *Session) deadPeerTimeoutOrDefault() time.Duration {
+	if timeout := time.Duration(s.deadPeerTimeout.Load()); timeout > 0 {
+		return timeout
+	}
+	return idleSessionTimeout
+}
+
+// keepaliveDue returns true if a keepalive should be sent over
+// stream transport.
+func (s *Session) keepaliveDue() bool {
+	interval := time.Duration(s.keepalive.Load())
+	return interval > 0 && s.isState(sessionEstablished) && time.Since(s.lastTXTime) > interval
+}
+
+// outputKeepalive sends a keepalive over stream transport. It is an ACK
+// segment with the keepalive flag, which is ignored by the peer.
+func (s *Session) outputKeepalive() error {
+	baseStruct := baseStruct{
+		flags: flagKeepalive,
+	}
+	if s.isClient {
+		baseStruct.protocol = uint8(ackClientToServer)
+	} else {
+		baseStruct.protocol = uint8(ackServerToClient)
+	}
+	s.oLock.Lock()
+	defer s.oLock.Unlock()
+	seg := &segment{
+		metadata: &dataAckStruct{
+			baseStruct: baseStruct,
+			sessionID:  s.id,
+			seq:        uint32(mathext.Max(0, int(s.nextSend)-1)),
+			unAckSeq:   s.nextRecv,
+			windowSize: uint16(mathext.Max(0, int(s.legacysendAlgorithm.CongestionWindowSize())-s.recvBuf.Len())),
+		},
+		transport: s.conn.TransportProtocol(),
+	}
+	if err := s.output(seg, nil); err != nil {
+		return err
+	}
+	KeepalivesSent.Add(1)
+	return nil
+}
+
+// tcpHeartbeatDialer enables TCP keepalive on the connections to the
+// proxy server, so dead peers are detected over stream transport.
+type tcpHeartbeatDialer struct {
+	apicommon.Dialer
+	interval        time.Duration // TCP keepalive interval, 0 to use the default
+	deadPeerTimeout time.Duration // TCP user timeout, 0 to use the default
+}
+
+var _ apicommon.Dialer = &tcpHeartbeatDialer{}
+
+// DialContext connects to the address and sets the TCP keepalive interval
+// and the user timeout. Connections that are not TCP are returned as is.
+func (d *tcpHeartbeatDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := d.Dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return conn, nil
+	}
+	if d.interval > 0 {
+		if err := tcpConn.SetKeepAlive(true); err != nil {
+			log.Debugf("SetKeepAlive() failed: %v", err)
+		}
+		if err := tcpConn.SetKeepAlivePeriod(d.interval); err != nil {
+			log.Debugf("SetKeepAlivePeriod() failed: %v", err)
+		}
+	}
+	if d.deadPeerTimeout > 0 {
+		rawConn, err := tcpConn.SyscallConn()
+		if err == nil {
+			err = rawConn.Control(func(fd uintptr) {
+				if ctrlErr := sockopts.TCPUserTimeoutRawErr(d.deadPeerTimeout)(fd); ctrlErr != nil {
+					log.Debugf("TCPUserTimeoutRawErr() failed: %v", ctrlErr)
+				}
+			})
+		}
+		if err != nil {
+			log.Debugf("set TCP user timeout failed: %v", err)
+		}
+	}
+	return conn, nil
+}
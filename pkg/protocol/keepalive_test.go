@@ -0,0 +1,150 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/cipher"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/testtool"
+)
+
+func TestTCPSessionKeepalive(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil),
+		})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	time.Sleep(100 * time.Millisecond)
+	go testServer.Serve(serverMux)
+	defer testServer.Close()
+
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}),
+		})
+	defer clientMux.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := clientMux.DialContext(ctx)
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	session, ok := conn.(*Session)
+	if !ok {
+		t.Fatalf("connection type is %T, want *Session", conn)
+	}
+
+	// Establish the session.
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	buf := make([]byte, 64)
+	if _, err := conn.Read(buf); err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+
+	sent := KeepalivesSent.Load()
+	received := KeepalivesReceived.Load()
+	session.SetKeepalive(100 * time.Millisecond)
+	time.Sleep(time.Second)
+	if got := KeepalivesSent.Load() - sent; got < 3 {
+		t.Errorf("sent %d keepalives, want at least 3", got)
+	}
+	if got := KeepalivesReceived.Load() - received; got < 3 {
+		t.Errorf("received %d keepalives, want at least 3", got)
+	}
+
+	// Keepalives are not delivered to the application.
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if n, err := conn.Read(buf); err == nil {
+		t.Errorf("Read() returned %d bytes from keepalives", n)
+	}
+
+	session.SetKeepalive(0)
+	time.Sleep(200 * time.Millisecond)
+	sent = KeepalivesSent.Load()
+	time.Sleep(500 * time.Millisecond)
+	if got := KeepalivesSent.Load() - sent; got != 0 {
+		t.Errorf("sent %d keepalives after keepalive is disabled, want 0", got)
+	}
+}
+
+func TestSessionHeartbeat(t *testing.T) {
+	s := NewSession(1, true, 1400, nil)
+	if got := s.heartbeatInterval(); got != sessionHeartbeatInterval {
+		t.Errorf("heartbeatInterval() = %v, want %v", got, sessionHeartbeatInterval)
+	}
+	if got := s.deadPeerTimeoutOrDefault(); got != idleSessionTimeout {
+		t.Errorf("deadPeerTimeoutOrDefault() = %v, want %v", got, idleSessionTimeout)
+	}
+
+	s.SetHeartbeat(15*time.Second, 20*time.Second)
+	if got := s.heartbeatInterval(); got != 15*time.Second {
+		t.Errorf("heartbeatInterval() = %v, want %v", got, 15*time.Second)
+	}
+	if got := s.deadPeerTimeoutOrDefault(); got != 20*time.Second {
+		t.Errorf("deadPeerTimeoutOrDefault() = %v, want %v", got, 20*time.Second)
+	}
+
+	// The shorter keepalive interval is used.
+	s.SetKeepalive(10 * time.Second)
+	if got := s.heartbeatInterval(); got != 10*time.Second {
+		t.Errorf("heartbeatInterval() = %v, want %v", got, 10*time.Second)
+	}
+}
+
+func TestTCPHeartbeatDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	d := &tcpHeartbeatDialer{Dialer: &net.Dialer{}, interval: 2 * time.Second, deadPeerTimeout: 10 * time.Second}
+	conn, err := d.DialContext(context.Background(), "tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	conn.Close()
+}
@@ -0,0 +1,309 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"context"
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+const (
+	// knockStepInterval is the time between two probes sent by proxy
+	// client, so the probes arrive at proxy server in order.
+	knockStepInterval = 50 * time.Millisecond
+
+	// knockDialTimeout is the maximum time of a TCP probe.
+	knockDialTimeout = 5 * time.Second
+
+	// knockCleanInterval is the interval to remove expired knocking states.
+	knockCleanInterval = time.Minute
+)
+
+var (
+	// KnockCompleted is the number of port knocking sequences finished
+	// by clients.
+	KnockCompleted = metrics.RegisterMetric("port knocking", "Completed", metrics.COUNTER)
+
+	// KnockRejected is the number of new underlays rejected because
+	// the source IP address didn't finish port knocking.
+	KnockRejected = metrics.RegisterMetric("port knocking", "Rejected", metrics.COUNTER)
+)
+
+// KnockStep is one probe of a port knocking sequence.
+type KnockStep struct {
+	// Network is either "tcp" or "udp".
+	Network string
+
+	Port int
+}
+
+func (s KnockStep) String() string {
+	return s.Network + "/" + strconv.Itoa(s.Port)
+}
+
+// PortKnockingOptions are the options of port knocking. Proxy server
+// only accepts new TCP and UDP underlays from an IP address after it
+// probes the ports of the sequence in order, so the port bindings look
+// closed to scanners.
+type PortKnockingOptions struct {
+	// Sequence is the ports to probe in order.
+	Sequence []KnockStep
+
+	// Timeout is the maximum time to finish the sequence.
+	// This is only used by proxy server.
+	Timeout time.Duration
+
+	// OpenDuration is the time that proxy server accepts new underlays
+	// from the IP address after it finishes the sequence.
+	// This is only used by proxy server.
+	OpenDuration time.Duration
+}
+
+// knock sends the probes of the sequence to the host.
+func (o *PortKnockingOptions) knock(ctx context.Context, dialer apicommon.Dialer, host string) error {
+	for i, step := range o.Sequence {
+		if i > 0 {
+			select {
+			case <-time.After(knockStepInterval):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		addr := net.JoinHostPort(host, strconv.Itoa(step.Port))
+		switch step.Network {
+		case "tcp":
+			dialCtx, cancel := context.WithTimeout(ctx, knockDialTimeout)
+			conn, err := dialer.DialContext(dialCtx, "tcp", addr)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("knock %v failed: %w", step, err)
+			}
+			conn.Close()
+		case "udp":
+			conn, err := dialer.DialContext(ctx, "udp", addr)
+			if err != nil {
+				return fmt.Errorf("knock %v failed: %w", step, err)
+			}
+			probe := make([]byte, 32)
+			crand.Read(probe)
+			_, err = conn.Write(probe)
+			conn.Close()
+			if err != nil {
+				return fmt.Errorf("knock %v failed: %w", step, err)
+			}
+		default:
+			return fmt.Errorf("knock %v failed: unsupported network", step)
+		}
+	}
+	return nil
+}
+
+// knockProgress is the port knocking progress of an IP address.
+type knockProgress struct {
+	next     int // index of the next step in the sequence
+	deadline time.Time
+}
+
+// portKnocker listens to the ports of the sequence at proxy server,
+// and tracks the progress of each source IP address.
+type portKnocker struct {
+	opts     PortKnockingOptions
+	mu       sync.Mutex
+	progress map[string]knockProgress // IP address -> progress
+	open     map[string]time.Time     // IP address -> time to stop accepting new underlays
+	closers  []io.Closer
+	done     chan struct{}
+}
+
+func newPortKnocker(opts PortKnockingOptions) *portKnocker {
+	return &portKnocker{
+		opts:     opts,
+		progress: make(map[string]knockProgress),
+		open:     make(map[string]time.Time),
+		done:     make(chan struct{}),
+	}
+}
+
+// start listens to the ports of the sequence on the IP address.
+func (k *portKnocker) start(listenIP string) error {
+	listened := make(map[KnockStep]struct{})
+	for _, step := range k.opts.Sequence {
+		if _, found := listened[step]; found {
+			continue
+		}
+		listened[step] = struct{}{}
+		addr := net.JoinHostPort(listenIP, strconv.Itoa(step.Port))
+		switch step.Network {
+		case "tcp":
+			l, err := net.Listen("tcp", addr)
+			if err != nil {
+				k.stop()
+				return fmt.Errorf("net.Listen() failed: %w", err)
+			}
+			k.closers = append(k.closers, l)
+			go k.serveTCP(l, step)
+		case "udp":
+			conn, err := net.ListenPacket("udp", addr)
+			if err != nil {
+				k.stop()
+				return fmt.Errorf("net.ListenPacket() failed: %w", err)
+			}
+			k.closers = append(k.closers, conn)
+			go k.serveUDP(conn, step)
+		default:
+			k.stop()
+			return fmt.Errorf("unsupported port knocking network %q", step.Network)
+		}
+	}
+	go k.cleanLoop()
+	log.Infof("Port knocking is listening to %v", k.opts.Sequence)
+	return nil
+}
+
+// stop closes the listeners of the sequence.
+func (k *portKnocker) stop() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	select {
+	case <-k.done:
+		return
+	default:
+	}
+	close(k.done)
+	for _, c := range k.closers {
+		c.Close()
+	}
+}
+
+func (k *portKnocker) serveTCP(l net.Listener, step KnockStep) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+			k.knock(addr.IP, step, time.Now())
+		}
+		conn.Close()
+	}
+}
+
+func (k *portKnocker) serveUDP(conn net.PacketConn, step KnockStep) {
+	buf := make([]byte, 1500)
+	for {
+		_, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if udpAddr, ok := addr.(*net.UDPAddr); ok {
+			k.knock(udpAddr.IP, step, time.Now())
+		}
+	}
+}
+
+// knock records a probe from the IP address. A probe that doesn't
+// match the next step of the sequence restarts the sequence.
+func (k *portKnocker) knock(ip net.IP, step KnockStep, now time.Time) {
+	key := ip.String()
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	p, found := k.progress[key]
+	if !found || now.After(p.deadline) {
+		p = knockProgress{deadline: now.Add(k.opts.Timeout)}
+	}
+	if k.opts.Sequence[p.next] == step {
+		p.next++
+	} else if k.opts.Sequence[0] == step {
+		p = knockProgress{next: 1, deadline: now.Add(k.opts.Timeout)}
+	} else {
+		delete(k.progress, key)
+		return
+	}
+	if p.next == len(k.opts.Sequence) {
+		delete(k.progress, key)
+		k.open[key] = now.Add(k.opts.OpenDuration)
+		KnockCompleted.Add(1)
+		log.Debugf("Port knocking is finished by %s", key)
+		return
+	}
+	k.progress[key] = p
+}
+
+// allowed returns true if proxy server accepts new underlays from
+// the IP address.
+func (k *portKnocker) allowed(ip net.IP, now time.Time) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	until, found := k.open[ip.String()]
+	return found && now.Before(until)
+}
+
+// cleanLoop removes the expired states until the knocker is stopped.
+func (k *portKnocker) cleanLoop() {
+	ticker := time.NewTicker(knockCleanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			k.mu.Lock()
+			for key, p := range k.progress {
+				if now.After(p.deadline) {
+					delete(k.progress, key)
+				}
+			}
+			for key, until := range k.open {
+				if now.After(until) {
+					delete(k.open, key)
+				}
+			}
+			k.mu.Unlock()
+		case <-k.done:
+			return
+		}
+	}
+}
+
+// knockListener only returns the connections from the IP addresses
+// that finished port knocking. Other connections are closed.
+type knockListener struct {
+	net.Listener
+	allowed func(net.Addr) bool
+}
+
+func (l *knockListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.allowed(conn.RemoteAddr()) {
+			return conn, nil
+		}
+		KnockRejected.Add(1)
+		conn.Close()
+	}
+}
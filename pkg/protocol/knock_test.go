@@ -0,0 +1,114 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+)
+
+func TestPortKnockerSequence(t *testing.T) {
+	opts := PortKnockingOptions{
+		Sequence: []KnockStep{
+			{Network: "udp", Port: 7000},
+			{Network: "tcp", Port: 8000},
+			{Network: "udp", Port: 9000},
+		},
+		Timeout:      10 * time.Second,
+		OpenDuration: 30 * time.Second,
+	}
+	k := newPortKnocker(opts)
+	ip := net.ParseIP("192.0.2.1")
+	now := time.Now()
+
+	// Wrong order restarts the sequence.
+	k.knock(ip, opts.Sequence[0], now)
+	k.knock(ip, opts.Sequence[2], now)
+	k.knock(ip, opts.Sequence[1], now)
+	if k.allowed(ip, now) {
+		t.Fatalf("allowed() = true after wrong sequence")
+	}
+
+	// The sequence must be finished before timeout.
+	k.knock(ip, opts.Sequence[0], now)
+	k.knock(ip, opts.Sequence[1], now)
+	k.knock(ip, opts.Sequence[2], now.Add(11*time.Second))
+	if k.allowed(ip, now.Add(11*time.Second)) {
+		t.Fatalf("allowed() = true after timeout")
+	}
+
+	// Finish the sequence.
+	k.knock(ip, opts.Sequence[0], now)
+	k.knock(ip, opts.Sequence[1], now)
+	k.knock(ip, opts.Sequence[2], now)
+	if !k.allowed(ip, now) {
+		t.Fatalf("allowed() = false after the sequence is finished")
+	}
+	if k.allowed(net.ParseIP("192.0.2.2"), now) {
+		t.Errorf("allowed() = true for another IP address")
+	}
+	if k.allowed(ip, now.Add(31*time.Second)) {
+		t.Errorf("allowed() = true after open duration")
+	}
+}
+
+func TestPortKnockerKnock(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	tcpPort, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	udpPort, err := common.UnusedUDPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedUDPPort() failed: %v", err)
+	}
+	opts := PortKnockingOptions{
+		Sequence: []KnockStep{
+			{Network: "udp", Port: udpPort},
+			{Network: "tcp", Port: tcpPort},
+		},
+		Timeout:      10 * time.Second,
+		OpenDuration: 30 * time.Second,
+	}
+	k := newPortKnocker(opts)
+	if err := k.start("127.0.0.1"); err != nil {
+		t.Fatalf("start() failed: %v", err)
+	}
+	defer k.stop()
+
+	ip := net.ParseIP("127.0.0.1")
+	if k.allowed(ip, time.Now()) {
+		t.Fatalf("allowed() = true before knocking")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := opts.knock(ctx, &net.Dialer{}, "127.0.0.1"); err != nil {
+		t.Fatalf("knock() failed: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !k.allowed(ip, time.Now()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("allowed() = false after knocking")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
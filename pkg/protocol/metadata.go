@@ -36,6 +36,8 @@ const (
 	dataServerToClient   protocolType = 7
 	ackClientToServer    protocolType = 8
 	ackServerToClient    protocolType = 9
+	keyExchangeRequest   protocolType = 10
+	keyExchangeResponse  protocolType = 11
 )
 
 func (p protocolType) Equals(other byte) bool {
@@ -64,6 +66,10 @@ func (p protocolType) String() string {
 		return "ackClientToServer"
 	case ackServerToClient:
 		return "ackServerToClient"
+	case keyExchangeRequest:
+		return "keyExchangeRequest"
+	case keyExchangeResponse:
+		return "keyExchangeResponse"
 	default:
 		return "UNKNOWN"
 	}
@@ -74,6 +80,8 @@ type statusCode byte
 const (
 	statusOK             statusCode = 0
 	statusQuotaExhausted statusCode = 1
+	statusRetryLater     statusCode = 2
+	statusUserDisabled   statusCode = 3
 )
 
 func (c statusCode) String() string {
@@ -82,11 +90,29 @@ func (c statusCode) String() string {
 		return "OK"
 	case statusQuotaExhausted:
 		return "quotaExhausted"
+	case statusRetryLater:
+		return "retryLater"
+	case statusUserDisabled:
+		return "userDisabled"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+const (
+	// flagMaintenance is set by the server to announce an upcoming
+	// maintenance. The client should not open new sessions to the server.
+	flagMaintenance uint8 = 1 << 0
+
+	// flagPathMTUProbe is set in ACK segments used by path MTU discovery.
+	// The payload of these segments is not application data.
+	flagPathMTUProbe uint8 = 1 << 1
+
+	// flagKeepalive is set in ACK segments sent over stream transport
+	// to keep an idle session alive.
+	flagKeepalive uint8 = 1 << 2
+)
+
 const (
 	// Number of bytes used by metadata before encryption.
 	MetadataLength = 32
@@ -119,7 +145,7 @@ var (
 // baseStruct is shared by all metadata struct.
 type baseStruct struct {
 	protocol  uint8  // byte 0: protocol type
-	_         uint8  // byte 1: reserved
+	flags     uint8  // byte 1: flags
 	timestamp uint32 // byte 2 - 5: timestamp, number of minutes after UNIX epoch
 }
 
@@ -131,6 +157,7 @@ type sessionStruct struct {
 	statusCode uint8  // byte 14: status of opening or closing session
 	payloadLen uint16 // byte 15 - 16: length of encapsulated payload, not including auth tag
 	suffixLen  uint8  // byte 17: length of suffix padding
+	mtu        uint16 // byte 18 - 19: maximum packet size to the sender, 0 if unknown
 }
 
 func (ss *sessionStruct) Protocol() protocolType {
@@ -140,6 +167,7 @@ func (ss *sessionStruct) Protocol() protocolType {
 func (ss *sessionStruct) Marshal() []byte {
 	b := make([]byte, MetadataLength)
 	b[0] = ss.baseStruct.protocol
+	b[1] = ss.baseStruct.flags
 	ss.baseStruct.timestamp = uint32(time.Now().Unix() / 60)
 	binary.BigEndian.PutUint32(b[2:], ss.baseStruct.timestamp)
 	binary.BigEndian.PutUint32(b[6:], ss.sessionID)
@@ -147,6 +175,7 @@ func (ss *sessionStruct) Marshal() []byte {
 	b[14] = ss.statusCode
 	binary.BigEndian.PutUint16(b[15:], ss.payloadLen)
 	b[17] = ss.suffixLen
+	binary.BigEndian.PutUint16(b[18:], ss.mtu)
 	return b
 }
 
@@ -169,17 +198,19 @@ func (ss *sessionStruct) Unmarshal(b []byte) error {
 
 	// Do unmarshal.
 	ss.baseStruct.protocol = b[0]
+	ss.baseStruct.flags = b[1]
 	ss.baseStruct.timestamp = originalTimestamp
 	ss.sessionID = binary.BigEndian.Uint32(b[6:])
 	ss.seq = binary.BigEndian.Uint32(b[10:])
 	ss.statusCode = b[14]
 	ss.payloadLen = binary.BigEndian.Uint16(b[15:])
 	ss.suffixLen = b[17]
+	ss.mtu = binary.BigEndian.Uint16(b[18:])
 	return nil
 }
 
 func (ss *sessionStruct) String() string {
-	return fmt.Sprintf("sessionStruct{protocol=%v, sessionID=%v, seq=%v, statusCode=%v, payloadLen=%v, suffixLen=%v}", protocolType(ss.protocol), ss.sessionID, ss.seq, ss.statusCode, ss.payloadLen, ss.suffixLen)
+	return fmt.Sprintf("sessionStruct{protocol=%v, sessionID=%v, seq=%v, statusCode=%v, payloadLen=%v, suffixLen=%v, mtu=%v}", protocolType(ss.protocol), ss.sessionID, ss.seq, ss.statusCode, ss.payloadLen, ss.suffixLen, ss.mtu)
 }
 
 func isSessionProtocol(p protocolType) bool {
@@ -193,7 +224,7 @@ func toSessionStruct(m metadata) (*sessionStruct, bool) {
 	return nil, false
 }
 
-// dataAckStruct is used by data and ack protocols.
+// dataAckStruct is used by data, ack and key exchange protocols.
 type dataAckStruct struct {
 	baseStruct
 	sessionID  uint32 // byte 6 - 9: session ID number
@@ -213,6 +244,7 @@ func (das *dataAckStruct) Protocol() protocolType {
 func (das *dataAckStruct) Marshal() []byte {
 	b := make([]byte, MetadataLength)
 	b[0] = das.baseStruct.protocol
+	b[1] = das.baseStruct.flags
 	das.baseStruct.timestamp = uint32(time.Now().Unix() / 60)
 	binary.BigEndian.PutUint32(b[2:], das.baseStruct.timestamp)
 	binary.BigEndian.PutUint32(b[6:], das.sessionID)
@@ -231,7 +263,7 @@ func (das *dataAckStruct) Unmarshal(b []byte) error {
 	if len(b) != MetadataLength {
 		return fmt.Errorf("input bytes: %d, want %d", len(b), MetadataLength)
 	}
-	if !dataClientToServer.Equals(b[0]) && !dataServerToClient.Equals(b[0]) && !ackClientToServer.Equals(b[0]) && !ackServerToClient.Equals(b[0]) {
+	if !isDataAckProtocol(protocolType(b[0])) && !isKeyExchangeProtocol(protocolType(b[0])) {
 		return fmt.Errorf("invalid protocol %d", b[0])
 	}
 	originalTimestamp := binary.BigEndian.Uint32(b[2:])
@@ -242,6 +274,7 @@ func (das *dataAckStruct) Unmarshal(b []byte) error {
 
 	// Do unmarshal.
 	das.baseStruct.protocol = b[0]
+	das.baseStruct.flags = b[1]
 	das.baseStruct.timestamp = originalTimestamp
 	das.sessionID = binary.BigEndian.Uint32(b[6:])
 	das.seq = binary.BigEndian.Uint32(b[10:])
@@ -262,8 +295,14 @@ func isDataAckProtocol(p protocolType) bool {
 	return p == dataClientToServer || p == dataServerToClient || p == ackClientToServer || p == ackServerToClient
 }
 
+// isKeyExchangeProtocol returns true if the protocol is used by
+// the hybrid key exchange of stream underlays.
+func isKeyExchangeProtocol(p protocolType) bool {
+	return p == keyExchangeRequest || p == keyExchangeResponse
+}
+
 func toDataAckStruct(m metadata) (*dataAckStruct, bool) {
-	if isDataAckProtocol(m.Protocol()) {
+	if isDataAckProtocol(m.Protocol()) || isKeyExchangeProtocol(m.Protocol()) {
 		return m.(*dataAckStruct), true
 	}
 	return nil, false
@@ -25,12 +25,14 @@ func TestSessionStruct(t *testing.T) {
 	s := &sessionStruct{
 		baseStruct: baseStruct{
 			protocol: uint8(closeSessionRequest),
+			flags:    flagMaintenance,
 		},
 		sessionID:  mrand.Uint32(),
 		statusCode: uint8(mrand.Uint32()),
 		seq:        mrand.Uint32(),
 		payloadLen: uint16(mrand.Uint32()),
 		suffixLen:  uint8(mrand.Uint32()),
+		mtu:        uint16(mrand.Uint32()),
 	}
 	b := s.Marshal()
 	s2 := &sessionStruct{}
@@ -49,6 +51,7 @@ func TestDataAckStruct(t *testing.T) {
 	s := &dataAckStruct{
 		baseStruct: baseStruct{
 			protocol: uint8(dataServerToClient),
+			flags:    flagMaintenance,
 		},
 		sessionID:  mrand.Uint32(),
 		seq:        mrand.Uint32(),
@@ -0,0 +1,342 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/sockopts"
+	"github.com/enfein/mieru/v3/pkg/stderror"
+)
+
+const (
+	// multipathRetryInterval is the time to wait before using a path again
+	// after it fails.
+	multipathRetryInterval = 5 * time.Second
+
+	// multipathStaleTimeout is the time after which a path that sends
+	// packets but doesn't receive any is considered broken, if other
+	// paths are still receiving packets.
+	multipathStaleTimeout = 15 * time.Second
+
+	// multipathRecvChanCapacity is the number of received packets
+	// waiting to be read.
+	multipathRecvChanCapacity = 256
+)
+
+var (
+	MultipathPathUp   = metrics.RegisterMetric("multipath", "PathUp", metrics.COUNTER)
+	MultipathPathDown = metrics.RegisterMetric("multipath", "PathDown", metrics.COUNTER)
+)
+
+// multipathPacket is a packet received by one of the paths.
+type multipathPacket struct {
+	b    []byte
+	addr net.Addr
+}
+
+// multipathPath is a UDP socket bound to a local network interface.
+type multipathPath struct {
+	device string
+
+	mu        sync.Mutex
+	conn      *net.UDPConn // nil if the path is not available
+	downUntil time.Time    // don't open the path before this time
+	lastSend  time.Time    // last time a packet is sent
+	lastRecv  atomic.Int64 // last time a packet is received, in UNIX nano
+}
+
+// multipathConn is a net.PacketConn that sends packets over several local
+// network interfaces. Packets are scheduled across the paths in turn.
+// A path is skipped for a while after it fails to send packets, or
+// after it stops receiving packets while other paths still do.
+// Packets received by any path are returned by ReadFrom.
+type multipathConn struct {
+	network string // "udp4" or "udp6"
+	paths   []*multipathPath
+	next    atomic.Uint32
+
+	recvChan     chan multipathPacket
+	readDeadline atomic.Int64 // UNIX nano, 0 means no deadline
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+var _ net.PacketConn = (*multipathConn)(nil)
+
+// newMultipathConn creates a multipathConn over the network devices.
+// network is "udp4" or "udp6", which decides the address family of
+// the local addresses. It fails if no path can be opened.
+func newMultipathConn(network string, devices []string) (*multipathConn, error) {
+	if network != "udp4" && network != "udp6" {
+		return nil, fmt.Errorf("network %s is not supported by multipath", network)
+	}
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("no network device is provided")
+	}
+	c := &multipathConn{
+		network:  network,
+		recvChan: make(chan multipathPacket, multipathRecvChanCapacity),
+		done:     make(chan struct{}),
+	}
+	var lastErr error
+	for _, device := range devices {
+		p := &multipathPath{device: device}
+		c.paths = append(c.paths, p)
+		p.mu.Lock()
+		if err := c.openLocked(p); err != nil {
+			log.Debugf("multipath: unable to open path over %s: %v", device, err)
+			lastErr = err
+		}
+		p.mu.Unlock()
+	}
+	if c.upPaths() == 0 {
+		return nil, fmt.Errorf("no path is available: %w", lastErr)
+	}
+	return c, nil
+}
+
+// ReadFrom implements net.PacketConn.
+func (c *multipathConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	var timeC <-chan time.Time
+	if deadline := c.readDeadline.Load(); deadline != 0 {
+		timer := time.NewTimer(time.Until(time.Unix(0, deadline)))
+		defer timer.Stop()
+		timeC = timer.C
+	}
+	select {
+	case pkt := <-c.recvChan:
+		return copy(b, pkt.b), pkt.addr, nil
+	case <-c.done:
+		return 0, nil, net.ErrClosed
+	case <-timeC:
+		return 0, nil, stderror.ErrTimeout
+	}
+}
+
+// WriteTo implements net.PacketConn.
+func (c *multipathConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	select {
+	case <-c.done:
+		return 0, net.ErrClosed
+	default:
+	}
+	now := time.Now()
+	n := len(c.paths)
+	start := int(c.next.Add(1))
+	var lastErr error = stderror.ErrNotReady
+	for i := 0; i < n; i++ {
+		p := c.paths[(start+i)%n]
+		if c.isStale(p, now) {
+			p.mu.Lock()
+			c.closeLocked(p, now, "no packet is received")
+			p.mu.Unlock()
+			continue
+		}
+		written, err := c.writeTo(p, b, addr, now)
+		if err == nil {
+			return written, nil
+		}
+		lastErr = err
+	}
+	return 0, lastErr
+}
+
+// Close implements net.PacketConn.
+func (c *multipathConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		for _, p := range c.paths {
+			p.mu.Lock()
+			if p.conn != nil {
+				p.conn.Close()
+				p.conn = nil
+			}
+			p.mu.Unlock()
+		}
+	})
+	return nil
+}
+
+// LocalAddr implements net.PacketConn. It returns the local address
+// of the first available path.
+func (c *multipathConn) LocalAddr() net.Addr {
+	for _, p := range c.paths {
+		p.mu.Lock()
+		conn := p.conn
+		p.mu.Unlock()
+		if conn != nil {
+			return conn.LocalAddr()
+		}
+	}
+	return common.NilNetAddr()
+}
+
+// SetDeadline implements net.PacketConn.
+func (c *multipathConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+// SetReadDeadline implements net.PacketConn.
+func (c *multipathConn) SetReadDeadline(t time.Time) error {
+	if t.IsZero() {
+		c.readDeadline.Store(0)
+	} else {
+		c.readDeadline.Store(t.UnixNano())
+	}
+	return nil
+}
+
+// SetWriteDeadline implements net.PacketConn. Writing to UDP
+// sockets doesn't block, so the deadline is ignored.
+func (c *multipathConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// writeTo sends the packet with the path. The path is opened if needed.
+func (c *multipathConn) writeTo(p *multipathPath, b []byte, addr net.Addr, now time.Time) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		if now.Before(p.downUntil) {
+			return 0, stderror.ErrNotReady
+		}
+		if err := c.openLocked(p); err != nil {
+			p.downUntil = now.Add(multipathRetryInterval)
+			return 0, err
+		}
+	}
+	n, err := p.conn.WriteTo(b, addr)
+	if err != nil {
+		c.closeLocked(p, now, err.Error())
+		return 0, fmt.Errorf("WriteTo() over %s failed: %w", p.device, err)
+	}
+	p.lastSend = now
+	return n, nil
+}
+
+// isStale returns true if the path keeps sending packets without
+// receiving any, while another path received packets recently.
+func (c *multipathConn) isStale(p *multipathPath, now time.Time) bool {
+	p.mu.Lock()
+	if p.conn == nil || p.lastSend.IsZero() {
+		p.mu.Unlock()
+		return false
+	}
+	lastSend := p.lastSend
+	p.mu.Unlock()
+	lastRecv := time.Unix(0, p.lastRecv.Load())
+	if lastSend.Sub(lastRecv) < multipathStaleTimeout {
+		return false
+	}
+	for _, other := range c.paths {
+		if other != p && now.Sub(time.Unix(0, other.lastRecv.Load())) < multipathStaleTimeout {
+			return true
+		}
+	}
+	return false
+}
+
+// upPaths returns the number of available paths.
+func (c *multipathConn) upPaths() int {
+	n := 0
+	for _, p := range c.paths {
+		p.mu.Lock()
+		if p.conn != nil {
+			n++
+		}
+		p.mu.Unlock()
+	}
+	return n
+}
+
+// openLocked creates the socket of the path. The caller must hold p.mu.
+func (c *multipathConn) openLocked(p *multipathPath) error {
+	ip, err := common.InterfaceIP(p.device, c.network == "udp4")
+	if err != nil {
+		return err
+	}
+	localAddr := net.JoinHostPort(ip.String(), "0")
+	lc := net.ListenConfig{Control: sockopts.BindToDevice(p.device)}
+	pc, err := lc.ListenPacket(context.Background(), c.network, localAddr)
+	if err != nil {
+		// Binding to a device may require extra privileges.
+		// Fall back to only bind to the address of the device.
+		log.Debugf("multipath: unable to bind to %s, use local address %s only: %v", p.device, localAddr, err)
+		pc, err = net.ListenPacket(c.network, localAddr)
+		if err != nil {
+			return fmt.Errorf("ListenPacket() failed: %w", err)
+		}
+	}
+	conn := pc.(*net.UDPConn)
+	if err := sockopts.ApplyUDPControls(conn); err != nil {
+		conn.Close()
+		return fmt.Errorf("ApplyUDPControls() failed: %w", err)
+	}
+	p.conn = conn
+	// Don't consider the path stale before it has a chance to receive.
+	p.lastSend = time.Time{}
+	p.lastRecv.Store(time.Now().UnixNano())
+	MultipathPathUp.Add(1)
+	log.Debugf("multipath: path over %s is up with local address %v", p.device, conn.LocalAddr())
+	go c.readLoop(p, conn)
+	return nil
+}
+
+// closeLocked closes the socket of the path. The caller must hold p.mu.
+func (c *multipathConn) closeLocked(p *multipathPath, now time.Time, reason string) {
+	if p.conn == nil {
+		return
+	}
+	p.conn.Close()
+	p.conn = nil
+	p.downUntil = now.Add(multipathRetryInterval)
+	MultipathPathDown.Add(1)
+	log.Debugf("multipath: path over %s is down: %s", p.device, reason)
+}
+
+// readLoop delivers the packets received by the socket of the path,
+// until the socket is closed.
+func (c *multipathConn) readLoop(p *multipathPath, conn *net.UDPConn) {
+	for {
+		b := make([]byte, maxPathMTU)
+		n, addr, err := conn.ReadFrom(b)
+		if err != nil {
+			if !stderror.IsClosed(err) {
+				p.mu.Lock()
+				if p.conn == conn {
+					c.closeLocked(p, time.Now(), err.Error())
+				}
+				p.mu.Unlock()
+			}
+			return
+		}
+		p.lastRecv.Store(time.Now().UnixNano())
+		select {
+		case c.recvChan <- multipathPacket{b: b[:n], addr: addr}:
+		case <-c.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/stderror"
+)
+
+func loopbackDevice(t *testing.T) string {
+	t.Helper()
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatalf("net.Interfaces() failed: %v", err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 && iface.Flags&net.FlagUp != 0 {
+			return iface.Name
+		}
+	}
+	t.Skip("loopback network device is not found")
+	return ""
+}
+
+func TestMultipathConn(t *testing.T) {
+	lo := loopbackDevice(t)
+	server, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("net.ListenUDP() failed: %v", err)
+	}
+	defer server.Close()
+	go func() {
+		b := make([]byte, 1500)
+		for {
+			n, addr, err := server.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			server.WriteTo(b[:n], addr)
+		}
+	}()
+
+	// The path over the device that doesn't exist is skipped.
+	c, err := newMultipathConn("udp4", []string{lo, "mieru-not-exist", lo})
+	if err != nil {
+		t.Fatalf("newMultipathConn() failed: %v", err)
+	}
+	defer c.Close()
+	if got := c.upPaths(); got != 2 {
+		t.Errorf("upPaths() = %d, want 2", got)
+	}
+
+	sources := make(map[string]struct{})
+	for i := 0; i < 10; i++ {
+		payload := []byte{byte(i)}
+		if _, err := c.WriteTo(payload, server.LocalAddr()); err != nil {
+			t.Fatalf("WriteTo() failed: %v", err)
+		}
+		c.SetReadDeadline(time.Now().Add(time.Second))
+		b := make([]byte, 1500)
+		n, addr, err := c.ReadFrom(b)
+		if err != nil {
+			t.Fatalf("ReadFrom() failed: %v", err)
+		}
+		if !bytes.Equal(b[:n], payload) {
+			t.Errorf("ReadFrom() got %v, want %v", b[:n], payload)
+		}
+		if addr.String() != server.LocalAddr().String() {
+			t.Errorf("ReadFrom() got packet from %v, want %v", addr, server.LocalAddr())
+		}
+	}
+	for _, p := range c.paths {
+		p.mu.Lock()
+		if !p.lastSend.IsZero() {
+			sources[p.device+p.conn.LocalAddr().String()] = struct{}{}
+		}
+		p.mu.Unlock()
+	}
+	if len(sources) != 2 {
+		t.Errorf("packets are sent over %d paths, want 2", len(sources))
+	}
+
+	// Read timeout.
+	c.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	if _, _, err := c.ReadFrom(make([]byte, 1500)); !stderror.IsTimeout(err) {
+		t.Errorf("ReadFrom() returns error %v, want timeout", err)
+	}
+}
+
+func TestMultipathConnNoPath(t *testing.T) {
+	if _, err := newMultipathConn("udp4", []string{"mieru-not-exist"}); err == nil {
+		t.Errorf("newMultipathConn() succeeded without available path")
+	}
+	if _, err := newMultipathConn("tcp", []string{"lo"}); err == nil {
+		t.Errorf("newMultipathConn() succeeded with TCP network")
+	}
+}
+
+func TestMultipathConnStalePath(t *testing.T) {
+	lo := loopbackDevice(t)
+	c, err := newMultipathConn("udp4", []string{lo, lo})
+	if err != nil {
+		t.Fatalf("newMultipathConn() failed: %v", err)
+	}
+	defer c.Close()
+
+	// The first path keeps sending without receiving,
+	// while the second path is receiving.
+	now := time.Now()
+	stale, healthy := c.paths[0], c.paths[1]
+	stale.mu.Lock()
+	stale.lastSend = now
+	stale.mu.Unlock()
+	stale.lastRecv.Store(now.Add(-2 * multipathStaleTimeout).UnixNano())
+	healthy.lastRecv.Store(now.UnixNano())
+	if !c.isStale(stale, now) {
+		t.Errorf("isStale() = false, want true")
+	}
+	if c.isStale(healthy, now) {
+		t.Errorf("isStale() = true, want false")
+	}
+
+	// If no path is receiving, the path is not considered stale.
+	healthy.lastRecv.Store(now.Add(-2 * multipathStaleTimeout).UnixNano())
+	if c.isStale(stale, now) {
+		t.Errorf("isStale() = true when no path is receiving, want false")
+	}
+}
+
+func TestSessionMigrate(t *testing.T) {
+	addr1 := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 2), Port: 1000}
+	addr2 := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 2000}
+	s := NewSession(1, false, 1400, nil)
+	s.remoteAddr = addr1
+	if s.hasPath(addr2) {
+		t.Errorf("hasPath(%v) = true before migration", addr2)
+	}
+	s.migrate(addr2)
+	if s.RemoteAddr().String() != addr2.String() {
+		t.Errorf("RemoteAddr() = %v, want %v", s.RemoteAddr(), addr2)
+	}
+	if !s.hasPath(addr1) || !s.hasPath(addr2) {
+		t.Errorf("hasPath() = false for a known path")
+	}
+
+	// The oldest path is forgotten when the limit is reached.
+	for i := 0; i < maxSessionPaths; i++ {
+		s.migrate(&net.UDPAddr{IP: net.IPv4(172, 16, 0, byte(i)), Port: 3000})
+	}
+	if s.hasPath(addr1) {
+		t.Errorf("hasPath(%v) = true, want the oldest path to be forgotten", addr1)
+	}
+}
@@ -16,6 +16,7 @@
 package protocol
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"fmt"
@@ -23,6 +24,7 @@ import (
 	mrand "math/rand"
 	"net"
 	"reflect"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -35,10 +37,19 @@ import (
 	"github.com/enfein/mieru/v3/pkg/mathext"
 	"github.com/enfein/mieru/v3/pkg/sockopts"
 	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/systemd"
 )
 
 const (
 	idleUnderlayTickerInterval = 5 * time.Second
+
+	// retryLaterBackoff is the duration that the client avoids creating
+	// new underlays to a server endpoint after it signals retry later.
+	retryLaterBackoff = 30 * time.Second
+
+	// maintenanceBackoff is the duration that the client avoids creating
+	// new underlays to a server endpoint after it announces maintenance.
+	maintenanceBackoff = 10 * time.Minute
 )
 
 // Mux manages the sessions and underlays.
@@ -58,14 +69,71 @@ type Mux struct {
 	ctxCancelFunc context.CancelFunc // function to cancel master context when mux is closed
 	mu            sync.Mutex
 	cleaner       *time.Ticker
+	keyRotation   atomic.Pointer[cipher.KeyRotation] // nil if keys are not rotated
+	knocker       atomic.Pointer[portKnocker]        // nil if port knocking is disabled
 
 	// ---- client only fields ----
 	username        string
 	password        []byte
+	cipherSuite     appctlpb.CipherSuite
 	multiplexFactor int
+	maxUnderlays    int
+
+	retryLater   map[string]time.Time // endpoint -> time before which new underlays avoid it
+	retryLaterMu sync.Mutex
+
+	hybridKeyExchange atomic.Bool // if true, new stream underlays run the hybrid key exchange
+
+	preferLowLatency bool
+	pathMTUDiscovery bool
+	multipathDevices []string                 // local network devices to send UDP packets
+	localBinding     common.LocalBinding      // local interface, IP address and mark of UDP sockets
+	heartbeat        time.Duration            // interval to send heartbeats, 0 to use the default
+	deadPeerTimeout  time.Duration            // time without receiving from the server to close a session, 0 to use the default
+	reconnect        ReconnectPolicy          // how to retry when the proxy tunnel can't be established
+	dialFailures     atomic.Int32             // number of consecutive failures to establish the proxy tunnel
+	pathLatency      map[string]time.Duration // path -> handshake latency, negative if unreachable
+	pathLatencyMu    sync.Mutex
+	autoTransport    map[string]string     // address of AUTO endpoints -> network of the selected transport protocol
+	oldCredentials   map[Underlay]struct{} // underlays created with replaced credentials
 
 	// ---- server only fields ----
-	users map[string]*appctlpb.User
+	users       map[string]*appctlpb.User
+	userGroups  map[string]*appctlpb.UserGroup // user name -> user group
+	overload    overloadState                  // limits and recent load of the server
+	acceptLimit common.AcceptLimit             // limits of TCP listeners
+	maintenance atomic.Bool                    // if true, announce the maintenance to clients
+	bindings    map[string]*portBinding        // local address -> port binding
+}
+
+// endpointListener controls the listening socket of a server endpoint.
+type endpointListener struct {
+	cancel    context.CancelFunc // cancel the context of the endpoint
+	mu        sync.Mutex
+	socket    io.Closer     // TCP listener or UDP connection, if created
+	ready     chan struct{} // closed after trying to create the socket
+	readyOnce sync.Once
+}
+
+// stop closes the listening socket and all the underlays of the endpoint.
+func (l *endpointListener) stop() {
+	l.cancel()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.socket != nil {
+		l.socket.Close()
+	}
+}
+
+func (l *endpointListener) setSocket(socket io.Closer) {
+	l.mu.Lock()
+	l.socket = socket
+	l.mu.Unlock()
+	l.setReady()
+}
+
+func (l *endpointListener) setReady() {
+	l.readyOnce.Do(func() { close(l.ready) })
 }
 
 var _ net.Listener = &Mux{}
@@ -78,21 +146,34 @@ func NewMux(isClinet bool) *Mux {
 		log.Infof("Initializing server multiplexer")
 	}
 	mux := &Mux{
-		isClient:  isClinet,
-		underlays: make([]Underlay, 0),
-		dialer:    &net.Dialer{Timeout: 10 * time.Second, Control: sockopts.ReuseAddrPort()},
-		resolver:  &net.Resolver{},
-		chAccept:  make(chan net.Conn, sessionChanCapacity),
-		acceptErr: make(chan error),
-		done:      make(chan struct{}),
-		cleaner:   time.NewTicker(idleUnderlayTickerInterval),
+		isClient:       isClinet,
+		underlays:      make([]Underlay, 0),
+		retryLater:     make(map[string]time.Time),
+		pathLatency:    make(map[string]time.Duration),
+		autoTransport:  make(map[string]string),
+		oldCredentials: make(map[Underlay]struct{}),
+		bindings:       make(map[string]*portBinding),
+		dialer:         NewDefaultDialer(),
+		resolver:       &net.Resolver{},
+		chAccept:       make(chan net.Conn, sessionChanCapacity),
+		acceptErr:      make(chan error),
+		done:           make(chan struct{}),
+		cleaner:        time.NewTicker(idleUnderlayTickerInterval),
 	}
 	mux.ctx, mux.ctxCancelFunc = context.WithCancel(context.Background())
 
 	// Run maintenance tasks in the background.
+	var sampleTicker *time.Ticker
+	var sampleC <-chan time.Time
+	if !isClinet {
+		sampleTicker = time.NewTicker(overloadSampleInterval)
+		sampleC = sampleTicker.C
+	}
 	go func() {
 		for {
 			select {
+			case now := <-sampleC:
+				mux.overload.sample(now)
 			case <-mux.cleaner.C:
 				mux.mu.Lock()
 				if isClinet {
@@ -103,6 +184,9 @@ func NewMux(isClinet bool) *Mux {
 				mux.mu.Unlock()
 			case <-mux.done:
 				mux.cleaner.Stop()
+				if sampleTicker != nil {
+					sampleTicker.Stop()
+				}
 				return
 			}
 		}
@@ -111,32 +195,69 @@ func NewMux(isClinet bool) *Mux {
 }
 
 // SetEndpoints updates the endpoints that mux is listening to.
-// If mux is started and new endpoints are added, mux also starts
-// to listen to those new endpoints. In that case, old endpoints
+// If mux is started, mux starts to listen to the new endpoints,
+// and stops listening to the removed endpoints. Underlays accepted
+// from the removed endpoints are closed. Endpoints sharing the same
+// local address belong to one port binding, and if any of them is
+// changed, the whole port binding is restarted. Other port bindings
 // are not impacted.
+//
+// For client, new sessions are created from the new endpoints.
+// Existing underlays to the removed endpoints stop accepting new
+// sessions, but they are kept until all the sessions on them are
+// finished.
 func (m *Mux) SetEndpoints(endpoints []UnderlayProperties) *Mux {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	new := m.newEndpoints(m.endpoints, endpoints)
-	if len(new) > 0 {
-		if m.used {
-			select {
-			case <-m.done:
-				log.Infof("Unable to add new endpoint after multiplexer is closed")
-			default:
-				for _, p := range new {
-					go m.acceptUnderlayLoop(m.ctx, p)
-				}
-				m.endpoints = endpoints
+	if m.isClient {
+		m.endpoints = endpoints
+		if stale := m.staleUnderlays(); len(stale) > 0 {
+			for _, underlay := range stale {
+				underlay.Scheduler().Disable()
+			}
+			log.Infof("Mux pinned %d underlays to existing sessions", len(stale))
+		}
+		log.Infof("Mux now has %d endpoints", len(m.endpoints))
+		return m
+	}
+	if m.used {
+		select {
+		case <-m.done:
+			log.Infof("Unable to change endpoints after multiplexer is closed")
+			return m
+		default:
+		}
+		// Stop the changed port bindings first, so they can listen
+		// to the same address again.
+		_, oldGroups := groupPortBindings(m.endpoints)
+		addrs, newGroups := groupPortBindings(endpoints)
+		for addr, group := range oldGroups {
+			if !reflect.DeepEqual(group, newGroups[addr]) {
+				m.stopListening(addr)
+			}
+		}
+		for _, addr := range addrs {
+			if _, found := m.bindings[addr]; !found {
+				m.startListening(addr, newGroups[addr])
 			}
-		} else {
-			m.endpoints = new
 		}
 	}
+	m.endpoints = endpoints
 	log.Infof("Mux now has %d endpoints", len(m.endpoints))
 	return m
 }
 
+// NewDefaultDialer returns the dialer used by a new mux.
+func NewDefaultDialer() apicommon.Dialer {
+	return NewLocalBindingDialer(common.LocalBinding{})
+}
+
+// NewLocalBindingDialer returns the dialer used by a new mux,
+// which makes connections from the local binding.
+func NewLocalBindingDialer(binding common.LocalBinding) apicommon.Dialer {
+	return binding.Dialer(&net.Dialer{Timeout: 10 * time.Second, Control: sockopts.ReuseAddrPort()})
+}
+
 // SetDialer updates the dialer used by the mux.
 func (m *Mux) SetDialer(dialer apicommon.Dialer) *Mux {
 	m.mu.Lock()
@@ -170,6 +291,48 @@ func (m *Mux) SetClientUserNamePassword(username string, password []byte) *Mux {
 	return m
 }
 
+// SetClientCredentials replaces the user name, password and cipher suite
+// used by new underlays, even if mux is already started. Existing underlays
+// stop accepting new sessions, but they are kept until all the sessions on
+// them are finished.
+func (m *Mux) SetClientCredentials(username string, password []byte, suite appctlpb.CipherSuite) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set client credentials in server mux")
+	}
+	if m.username == username && bytes.Equal(m.password, password) && m.cipherSuite == suite {
+		return m
+	}
+	m.username = username
+	m.password = password
+	m.cipherSuite = suite
+	if m.used && len(m.underlays) > 0 {
+		for _, underlay := range m.underlays {
+			underlay.Scheduler().Disable()
+			m.oldCredentials[underlay] = struct{}{}
+		}
+		log.Infof("Mux pinned %d underlays to existing sessions", len(m.underlays))
+	}
+	log.Infof("Mux client credentials have been updated")
+	return m
+}
+
+// SetClientCipherSuite sets the AEAD algorithm used by new underlays.
+// It must be the same as the cipher suite of the user at proxy server.
+func (m *Mux) SetClientCipherSuite(suite appctlpb.CipherSuite) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set cipher suite in server mux")
+	}
+	m.cipherSuite = suite
+	if suite == appctlpb.CipherSuite_AUTO_CIPHER_SUITE {
+		log.Infof("Mux uses %v for AUTO cipher suite", cipher.FastestAEADType())
+	}
+	return m
+}
+
 // SetClientMultiplexFactor panics if the mux is already started.
 func (m *Mux) SetClientMultiplexFactor(n int) *Mux {
 	m.mu.Lock()
@@ -185,6 +348,204 @@ func (m *Mux) SetClientMultiplexFactor(n int) *Mux {
 	return m
 }
 
+// SetClientMaxUnderlays sets the maximum number of underlays the client
+// can create. 0 means unlimited. It panics if the mux is already started.
+func (m *Mux) SetClientMaxUnderlays(n int) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set max underlays in server mux")
+	}
+	if m.used {
+		panic("Can't set max underlays after mux is used")
+	}
+	m.maxUnderlays = mathext.Max(n, 0)
+	if m.maxUnderlays > 0 {
+		log.Infof("Mux maximum number of underlays is set to %d", m.maxUnderlays)
+	}
+	return m
+}
+
+// SetClientPreferLowLatency sets if new underlays are created to the
+// endpoints with the lowest measured latency. The latency is provided
+// by SetPathLatency. Endpoints without measured latency are not preferred.
+func (m *Mux) SetClientPreferLowLatency(prefer bool) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set prefer low latency in server mux")
+	}
+	m.preferLowLatency = prefer
+	return m
+}
+
+// SetClientPathMTUDiscovery sets if new UDP underlays search the path MTU
+// to the server, instead of always using the MTU of the endpoint.
+// The MTU of the endpoint is the upper bound of the search.
+func (m *Mux) SetClientPathMTUDiscovery(enable bool) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set path MTU discovery in server mux")
+	}
+	m.pathMTUDiscovery = enable
+	return m
+}
+
+// SetClientHybridKeyExchange sets if new TCP and WebSocket underlays
+// run a hybrid X25519 + ML-KEM-768 key exchange with the server, and
+// encrypt the traffic with a key derived from both the password and
+// the exchanged secret. UDP underlays are not affected.
+func (m *Mux) SetClientHybridKeyExchange(enable bool) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set hybrid key exchange in server mux")
+	}
+	m.hybridKeyExchange.Store(enable)
+	return m
+}
+
+// SetClientMultipathDevices sets the local network devices used by new
+// UDP underlays. Packets are sent over these devices in turn, and the
+// server reorders them. If devices is empty, the default route is used.
+func (m *Mux) SetClientMultipathDevices(devices []string) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set multipath devices in server mux")
+	}
+	m.multipathDevices = append([]string(nil), devices...)
+	return m
+}
+
+// SetClientHeartbeat sets the interval to send heartbeats, and the time
+// without receiving anything from the proxy server before a session is
+// closed. Over packet transport, they apply to new sessions. Over stream
+// transport, they are the TCP keepalive interval and the TCP user timeout
+// of new underlays. Use 0 to use the default values.
+func (m *Mux) SetClientHeartbeat(interval, deadPeerTimeout time.Duration) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set heartbeat in server mux")
+	}
+	m.heartbeat = mathext.Max(interval, 0)
+	m.deadPeerTimeout = mathext.Max(deadPeerTimeout, 0)
+	if m.heartbeat > 0 || m.deadPeerTimeout > 0 {
+		log.Infof("Mux heartbeat interval is set to %v, dead peer timeout is set to %v", m.heartbeat, m.deadPeerTimeout)
+	}
+	return m
+}
+
+// SetClientReconnect sets how to retry when the proxy tunnel can't be
+// established. Pending requests in DialContext wait for the retries.
+func (m *Mux) SetClientReconnect(policy ReconnectPolicy) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set reconnect policy in server mux")
+	}
+	m.reconnect = policy
+	if policy.Enabled() {
+		log.Infof("Mux reconnects with initial delay %v, max delay %v and max wait %v", policy.InitialDelay, policy.MaxDelay, policy.MaxWait)
+	}
+	return m
+}
+
+// SetClientLocalBinding sets the local network interface, the local IP
+// address and the firewall mark used by new UDP underlays, if multipath
+// devices are not set.
+// TCP underlays use the binding of the dialer, see NewLocalBindingDialer.
+func (m *Mux) SetClientLocalBinding(binding common.LocalBinding) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't set local binding in server mux")
+	}
+	m.localBinding = binding
+	if !binding.IsZero() {
+		log.Infof("Mux UDP underlays are bound to %s", binding)
+	}
+	return m
+}
+
+// SetKeyRotation sets the key rotation schedule, even if mux is already
+// started. Use nil to disable key rotation.
+//
+// For client, new underlays use the keys of the current key epoch, and
+// underlays created in an old epoch stop accepting new sessions after
+// the epoch ends. For server, new underlays and sessions are accepted
+// with the keys of the accepted epochs, and existing ones still work.
+func (m *Mux) SetKeyRotation(rotation *cipher.KeyRotation) *Mux {
+	if !rotation.Enabled() {
+		rotation = nil
+	}
+	m.keyRotation.Store(rotation)
+	if rotation != nil {
+		log.Infof("Mux key rotation period is set to %v with overlap %v", rotation.Period, rotation.Overlap)
+	}
+	return m
+}
+
+// SetServerPortKnocking sets the port knocking sequence required before
+// accepting new underlays, even if mux is already started. Use nil to
+// disable port knocking. Existing underlays and sessions are not affected.
+// New WebSocket underlays are not checked, because the source IP address
+// can be a reverse proxy.
+func (m *Mux) SetServerPortKnocking(opts *PortKnockingOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.isClient {
+		panic("Can't set port knocking in client mux")
+	}
+	var knocker *portKnocker
+	if opts != nil && len(opts.Sequence) > 0 {
+		knocker = newPortKnocker(*opts)
+		if err := knocker.start(common.AllIPAddr()); err != nil {
+			return err
+		}
+	}
+	if old := m.knocker.Swap(knocker); old != nil {
+		old.stop()
+	}
+	return nil
+}
+
+// isKnockAllowed returns true if new underlays are accepted from the
+// network address.
+func (m *Mux) isKnockAllowed(addr net.Addr) bool {
+	knocker := m.knocker.Load()
+	if knocker == nil {
+		return true
+	}
+	var ip net.IP
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		ip = a.IP
+	case *net.UDPAddr:
+		ip = a.IP
+	default:
+		return false
+	}
+	return knocker.allowed(ip, time.Now())
+}
+
+// RotateKeys makes the server stop accepting the keys of the previous
+// key epochs immediately. It returns the current key epoch.
+func (m *Mux) RotateKeys() (int64, error) {
+	if m.isClient {
+		return 0, stderror.ErrInvalidOperation
+	}
+	rotation := m.keyRotation.Load()
+	if rotation == nil {
+		return 0, fmt.Errorf("key rotation is not enabled")
+	}
+	epoch := rotation.ForceRotate(time.Now())
+	log.Infof("Mux only accepts keys of key epoch %d and later", epoch)
+	return epoch, nil
+}
+
 // SetServerUsers updates the registered users, even if mux is already started.
 func (m *Mux) SetServerUsers(users map[string]*appctlpb.User) *Mux {
 	m.mu.Lock()
@@ -206,6 +567,125 @@ func (m *Mux) SetServerUsers(users map[string]*appctlpb.User) *Mux {
 	return m
 }
 
+// SetServerUserGroups updates the user groups, indexed by user name,
+// even if mux is already started. Like users, existing TCPUnderlay and
+// Session are not updated.
+func (m *Mux) SetServerUserGroups(userGroups map[string]*appctlpb.UserGroup) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.isClient {
+		panic("Can't set server user groups in client mux")
+	}
+	m.userGroups = userGroups
+	if m.used {
+		for _, underlay := range m.underlays {
+			if udpUnderlay, ok := underlay.(*PacketUnderlay); ok {
+				udpUnderlay.userGroups = m.userGroups
+			}
+		}
+	}
+	return m
+}
+
+// SetServerOverloadLimits updates the limits of concurrent sessions, CPU
+// usage and bandwidth, even if mux is already started. New sessions beyond
+// the limits are rejected with a retry later signal. Existing underlays
+// use the new limits immediately.
+func (m *Mux) SetServerOverloadLimits(limits OverloadLimits) *Mux {
+	if m.isClient {
+		panic("Can't set overload limits in client mux")
+	}
+	m.overload.setLimits(limits)
+	if limits.MaxSessions > 0 {
+		log.Infof("Mux max sessions is set to %d", limits.MaxSessions)
+	}
+	if limits.MaxCPUPercent > 0 {
+		log.Infof("Mux max CPU usage is set to %d%%", limits.MaxCPUPercent)
+	}
+	if limits.MaxBandwidthMbps > 0 {
+		log.Infof("Mux max bandwidth is set to %d Mbps", limits.MaxBandwidthMbps)
+	}
+	return m
+}
+
+// SetServerAcceptLimit sets the limits of the TCP listeners of the port
+// bindings, to protect them from resource exhaustion attacks. It only
+// applies to the port bindings listened after it is set.
+func (m *Mux) SetServerAcceptLimit(limit common.AcceptLimit) *Mux {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.isClient {
+		panic("Can't set accept limit in client mux")
+	}
+	m.acceptLimit = limit
+	return m
+}
+
+// SetServerMaintenance starts or stops announcing an upcoming maintenance
+// to clients, even if mux is already started. Clients stop opening new
+// sessions to the server after they receive the announcement.
+func (m *Mux) SetServerMaintenance(announce bool) *Mux {
+	if m.isClient {
+		panic("Can't set maintenance in client mux")
+	}
+	if m.maintenance.Swap(announce) != announce {
+		if announce {
+			log.Infof("Mux starts to announce maintenance to clients")
+		} else {
+			log.Infof("Mux stops to announce maintenance to clients")
+		}
+	}
+	return m
+}
+
+// CloseStaleUnderlays closes the client underlays whose remote address
+// is not in the endpoints, or which are created with replaced credentials.
+// The sessions on those underlays are closed.
+// It returns the number of closed underlays.
+func (m *Mux) CloseStaleUnderlays() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't close stale underlays in server mux")
+	}
+	stale := m.staleUnderlays()
+	for _, underlay := range stale {
+		underlay.Close()
+	}
+	m.cleanUnderlay(false)
+	if len(stale) > 0 {
+		log.Infof("Mux closed %d stale underlays", len(stale))
+	}
+	return len(stale)
+}
+
+// CloseUnderlaysOnNetworkChange closes all the client underlays, because
+// they are bound to the local address of the previous network. The
+// sessions on those underlays are closed, and new sessions create
+// underlays on the current network. The latency measured on the previous
+// network is discarded. It returns the number of closed underlays.
+func (m *Mux) CloseUnderlaysOnNetworkChange() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.isClient {
+		panic("Can't close underlays on network change in server mux")
+	}
+	n := len(m.underlays)
+	for _, underlay := range m.underlays {
+		underlay.Close()
+	}
+	m.underlays = make([]Underlay, 0)
+	m.dialFailures.Store(0)
+	m.pathLatencyMu.Lock()
+	m.pathLatency = make(map[string]time.Duration)
+	m.pathLatencyMu.Unlock()
+	m.autoTransport = make(map[string]string)
+	if n > 0 {
+		log.Infof("Mux closed %d underlays on network change", n)
+	}
+	return n
+}
+
 func (m *Mux) Accept() (net.Conn, error) {
 	select {
 	case <-m.acceptErr:
@@ -235,11 +715,19 @@ func (m *Mux) Close() error {
 		underlay.Close()
 	}
 	m.underlays = make([]Underlay, 0)
+	if knocker := m.knocker.Swap(nil); knocker != nil {
+		knocker.stop()
+	}
 	m.ctxCancelFunc()
 	close(m.done)
 	return nil
 }
 
+// Context returns a context that is cancelled when the mux is closed.
+func (m *Mux) Context() context.Context {
+	return m.ctx
+}
+
 // Addr is not supported by Mux.
 func (m *Mux) Addr() net.Addr {
 	return common.NilNetAddr()
@@ -267,14 +755,37 @@ func (m *Mux) Start() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.used = true
-	for _, p := range m.endpoints {
-		go m.acceptUnderlayLoop(m.ctx, p)
+	addrs, groups := groupPortBindings(m.endpoints)
+	for _, addr := range addrs {
+		m.startListening(addr, groups[addr])
+	}
+	return nil
+}
+
+// WaitListening blocks until the mux has tried to listen to all the
+// server endpoints, or the context is done. After that, privileges
+// required to listen to the endpoints can be dropped.
+func (m *Mux) WaitListening(ctx context.Context) error {
+	m.mu.Lock()
+	listeners := make([]*endpointListener, 0, len(m.bindings))
+	for _, b := range m.bindings {
+		listeners = append(listeners, b.listeners...)
+	}
+	m.mu.Unlock()
+	for _, l := range listeners {
+		select {
+		case <-l.ready:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 	return nil
 }
 
 // DialContext returns a network connection for the client to consume.
 // The connection may be a session established from an existing underlay.
+// If the proxy tunnel can't be established and the reconnect policy is
+// enabled, it retries with backoff until the maximum wait time.
 func (m *Mux) DialContext(ctx context.Context) (net.Conn, error) {
 	if !m.isClient {
 		return nil, stderror.ErrInvalidOperation
@@ -291,6 +802,48 @@ func (m *Mux) DialContext(ctx context.Context) (net.Conn, error) {
 		}
 	}
 
+	start := time.Now()
+	retried := false
+	for {
+		conn, err := m.dialSession(ctx)
+		if err == nil {
+			m.dialFailures.Store(0)
+			if retried {
+				ReconnectRecovered.Add(1)
+				log.Debugf("Proxy tunnel is established after %v", time.Since(start))
+			}
+			return conn, nil
+		}
+		m.mu.Lock()
+		policy := m.reconnect
+		m.mu.Unlock()
+		if !policy.Enabled() || ctx.Err() != nil {
+			return nil, err
+		}
+		delay := policy.delay(int(m.dialFailures.Add(1)))
+		if time.Since(start)+delay > policy.MaxWait {
+			ReconnectGaveUp.Add(1)
+			return nil, err
+		}
+		log.Debugf("Failed to establish proxy tunnel, retry after %v: %v", delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-m.done:
+			timer.Stop()
+			return nil, err
+		}
+		ReconnectRetries.Add(1)
+		retried = true
+	}
+}
+
+// dialSession returns a session established from an existing underlay
+// or a new underlay.
+func (m *Mux) dialSession(ctx context.Context) (net.Conn, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.used = true
@@ -321,13 +874,104 @@ func (m *Mux) DialContext(ctx context.Context) (net.Conn, error) {
 	defer func() {
 		underlay.Scheduler().DecPending()
 	}()
-	session := NewSession(mrand.Uint32(), true, underlay.MTU(), m.users)
+	// Use the session ID from the caller, so the logs of the proxy client
+	// and the proxy server can be correlated.
+	sessionID, ok := log.SessionIDFromContext(ctx)
+	if !ok {
+		sessionID = mrand.Uint32()
+	}
+	session := NewSession(sessionID, true, underlay.MTU(), m.users)
+	session.SetHeartbeat(m.heartbeat, m.deadPeerTimeout)
+	session.onRetryLater = func() {
+		m.onRetryLater(underlay)
+	}
+	endpoints := m.endpoints
+	session.onMaintenance = func() {
+		m.onMaintenance(underlay, endpoints)
+	}
 	if err := underlay.AddSession(session, nil); err != nil {
 		return nil, fmt.Errorf("AddSession() failed: %v", err)
 	}
 	return session, nil
 }
 
+// DialEndpointContext returns a session on a new underlay to the endpoint.
+// The underlay is not used by other sessions, and it is closed when
+// the session is closed. This is used to measure the latency of an endpoint.
+func (m *Mux) DialEndpointContext(ctx context.Context, p UnderlayProperties) (net.Conn, error) {
+	if !m.isClient {
+		return nil, stderror.ErrInvalidOperation
+	}
+	m.mu.Lock()
+	username := m.username
+	password := m.password
+	suite := m.cipherSuite
+	devices := m.multipathDevices
+	binding := m.localBinding
+	m.mu.Unlock()
+	if len(password) == 0 {
+		return nil, fmt.Errorf("client password is not set")
+	}
+	underlay, err := m.dialUnderlay(ctx, p, username, password, suite, devices, binding)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		err := underlay.RunEventLoop(context.Background())
+		if err != nil && !stderror.IsEOF(err) && !stderror.IsClosed(err) {
+			log.Debugf("%v RunEventLoop(): %v", underlay, err)
+		}
+		underlay.Close()
+	}()
+	session := NewSession(mrand.Uint32(), true, underlay.MTU(), nil)
+	if err := underlay.AddSession(session, nil); err != nil {
+		underlay.Close()
+		return nil, fmt.Errorf("AddSession() failed: %v", err)
+	}
+	return &dedicatedUnderlaySession{Session: session, underlay: underlay}, nil
+}
+
+// ProbeTargets returns one endpoint of each network path.
+// The endpoints with the same IP address and transport protocol
+// share the same network path.
+func (m *Mux) ProbeTargets() []UnderlayProperties {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	paths := make(map[string][]UnderlayProperties)
+	keys := make([]string, 0)
+	for _, p := range m.endpoints {
+		key := pathKey(p.RemoteAddr())
+		if _, found := paths[key]; !found {
+			keys = append(keys, key)
+		}
+		paths[key] = append(paths[key], p)
+	}
+	targets := make([]UnderlayProperties, 0, len(keys))
+	for _, key := range keys {
+		targets = append(targets, paths[key][mrand.Intn(len(paths[key]))])
+	}
+	return targets
+}
+
+// SetPathLatency records the handshake latency of the network path to
+// the endpoint. If the endpoint can't be reached, ok is false.
+// If the endpoint is an AUTO endpoint, the transport protocol of
+// AUTO endpoints is selected again.
+func (m *Mux) SetPathLatency(p UnderlayProperties, latency time.Duration, ok bool) {
+	m.pathLatencyMu.Lock()
+	if ok {
+		m.pathLatency[pathKey(p.RemoteAddr())] = latency
+	} else {
+		m.pathLatency[pathKey(p.RemoteAddr())] = -1
+	}
+	m.pathLatencyMu.Unlock()
+	if isAutoTransport(p) {
+		m.mu.Lock()
+		m.updateAutoTransport()
+		m.mu.Unlock()
+	}
+}
+
 func (m *Mux) ExportSessionInfoList() *appctlpb.SessionInfoList {
 	items := make([]*appctlpb.SessionInfo, 0)
 	m.mu.Lock()
@@ -338,6 +982,42 @@ func (m *Mux) ExportSessionInfoList() *appctlpb.SessionInfoList {
 	return &appctlpb.SessionInfoList{Items: items}
 }
 
+// CloseSession closes the sessions with the ID, and returns the number
+// of closed sessions. The server may have multiple sessions with the same
+// ID from different clients.
+func (m *Mux) CloseSession(id uint32) int {
+	m.mu.Lock()
+	underlays := make([]Underlay, 0, len(m.underlays))
+	underlays = append(underlays, m.underlays...)
+	m.mu.Unlock()
+	n := 0
+	for _, underlay := range underlays {
+		if underlay.CloseSession(id) {
+			n++
+		}
+	}
+	return n
+}
+
+// ExportPortBindingStatusList returns the status of each server
+// port binding, sorted by the local address.
+func (m *Mux) ExportPortBindingStatusList() *appctlpb.PortBindingStatusList {
+	m.mu.Lock()
+	bindings := make([]*portBinding, 0, len(m.bindings))
+	for _, b := range m.bindings {
+		bindings = append(bindings, b)
+	}
+	m.mu.Unlock()
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].addr < bindings[j].addr
+	})
+	items := make([]*appctlpb.PortBindingStatus, 0, len(bindings))
+	for _, b := range bindings {
+		items = append(items, b.status())
+	}
+	return &appctlpb.PortBindingStatusList{Items: items}
+}
+
 func (m *Mux) newEndpoints(old, new []UnderlayProperties) []UnderlayProperties {
 	newEndpoints := []UnderlayProperties{}
 
@@ -357,13 +1037,52 @@ func (m *Mux) newEndpoints(old, new []UnderlayProperties) []UnderlayProperties {
 	return newEndpoints
 }
 
-func (m *Mux) acceptUnderlayLoop(ctx context.Context, properties UnderlayProperties) {
+// startListening starts to accept underlays from all the endpoints
+// of a server port binding.
+// This method MUST be called only when holding the mu lock.
+func (m *Mux) startListening(addr string, endpoints []UnderlayProperties) {
+	b := newPortBinding(addr, endpoints)
+	ctxs := make([]context.Context, len(endpoints))
+	for i := range endpoints {
+		ctx, cancel := context.WithCancel(m.ctx)
+		ctxs[i] = ctx
+		b.listeners = append(b.listeners, &endpointListener{cancel: cancel, ready: make(chan struct{})})
+	}
+	m.bindings[addr] = b
+	for i, p := range endpoints {
+		go m.acceptUnderlayLoop(ctxs[i], p, b, b.listeners[i])
+	}
+}
+
+// stopListening stops accepting underlays from a server port binding,
+// and closes the underlays accepted from it.
+// This method MUST be called only when holding the mu lock.
+func (m *Mux) stopListening(addr string) {
+	b, found := m.bindings[addr]
+	if !found {
+		return
+	}
+	delete(m.bindings, addr)
+	b.stop()
+	log.Infof("Mux stopped listening to port binding %s", addr)
+}
+
+// onListenError stops the port binding after one of its endpoints
+// is unable to listen, and reports the error to Accept().
+func (m *Mux) onListenError(b *portBinding, err error) {
+	if b.fail(err) {
+		log.Errorf("Mux failed to listen to port binding %s: %v", b.addr, err)
+	}
+	if m.acceptHasErr.CompareAndSwap(false, true) {
+		close(m.acceptErr)
+	}
+}
+
+func (m *Mux) acceptUnderlayLoop(ctx context.Context, properties UnderlayProperties, b *portBinding, l *endpointListener) {
+	defer l.setReady()
 	laddr := properties.LocalAddr().String()
 	if laddr == "" {
-		log.Errorf("Underlay local address is empty")
-		if m.acceptHasErr.CompareAndSwap(false, true) {
-			close(m.acceptErr)
-		}
+		m.onListenError(b, fmt.Errorf("underlay local address is empty"))
 		return
 	}
 
@@ -372,42 +1091,63 @@ func (m *Mux) acceptUnderlayLoop(ctx context.Context, properties UnderlayPropert
 	case "tcp", "tcp4", "tcp6":
 		tcpAddr, err := apicommon.ResolveTCPAddr(m.resolver, "tcp", laddr)
 		if err != nil {
-			log.Errorf("ResolveTCPAddr() failed: %v", err)
-			if m.acceptHasErr.CompareAndSwap(false, true) {
-				close(m.acceptErr)
-			}
+			m.onListenError(b, fmt.Errorf("ResolveTCPAddr() failed: %w", err))
 			return
 		}
-		rawListener, err := net.ListenTCP("tcp", tcpAddr)
+		rawListener, err := systemd.ListenTCP("tcp", tcpAddr)
 		if err != nil {
-			log.Errorf("ListenTCP() failed: %v", err)
-			if m.acceptHasErr.CompareAndSwap(false, true) {
-				close(m.acceptErr)
-			}
+			m.onListenError(b, fmt.Errorf("ListenTCP() failed: %w", err))
 			return
 		}
 		if err := sockopts.ApplyTCPControls(rawListener); err != nil {
-			log.Errorf("ApplyTCPControls() failed: %v", err)
-			if m.acceptHasErr.CompareAndSwap(false, true) {
-				close(m.acceptErr)
-			}
+			m.onListenError(b, fmt.Errorf("ApplyTCPControls() failed: %w", err))
 			log.Infof("Closing TCPListener %v", rawListener.Addr())
 			rawListener.Close()
 			return
 		}
-		log.Infof("Mux is listening to endpoint %s %s", network, laddr)
+		// The accept limit is applied to the raw connections, so the
+		// handshake timeout also covers the PROXY protocol header, TLS and
+		// WebSocket handshakes. The handshake is done when the first
+		// segment is authenticated.
+		m.mu.Lock()
+		acceptLimit := m.acceptLimit
+		m.mu.Unlock()
+		limitedListener := common.NewLimitedListener(rawListener, acceptLimit, UnderlayAcceptMetrics)
+		// The PROXY protocol header is removed first, so the following
+		// listeners see the address of the client behind the load balancer.
+		baseListener := limitedListener
+		if opts := proxyProtocolOptionsOf(properties); opts != nil {
+			baseListener = newProxyProtocolListener(limitedListener, opts)
+		}
+		var listener net.Listener
+		knockListener := &knockListener{Listener: baseListener, allowed: m.isKnockAllowed}
+		decoyOpts := decoyOptionsOf(properties)
+		if properties.TransportProtocol() == common.WebSocketTransport {
+			listener = newWebSocketListener(baseListener, webSocketOptionsOf(properties), decoyOpts.handler())
+			log.Infof("Mux is listening to WebSocket endpoint %s %s", network, laddr)
+		} else if opts := tlsCamouflageOptionsOf(properties); opts != nil {
+			listener = newTLSCamouflageListener(knockListener, opts, newDecoyServer(decoyOpts, rawListener.Addr()), m.isAuthenticatedStream)
+			log.Infof("Mux is listening to TLS endpoint %s %s", network, laddr)
+		} else if decoy := newDecoyServer(decoyOpts, rawListener.Addr()); decoy != nil {
+			listener = newDecoyListener(knockListener, decoy, m.isAuthenticatedStream)
+			log.Infof("Mux is listening to endpoint %s %s with decoy", network, laddr)
+		} else {
+			listener = knockListener
+			log.Infof("Mux is listening to endpoint %s %s", network, laddr)
+		}
+		l.setSocket(listener)
 
-		// Close the rawListener if the master context is canceled.
+		// Close the listener if the endpoint context is canceled.
 		// This can break the forever loop below.
-		go func(ctx context.Context, l *net.TCPListener) {
+		go func(ctx context.Context, l net.Listener) {
 			<-ctx.Done()
-			log.Infof("Closing TCPListener %v", rawListener.Addr())
-			rawListener.Close()
-		}(ctx, rawListener)
+			log.Infof("Closing TCPListener %v", l.Addr())
+			l.Close()
+		}(ctx, listener)
 
 		for {
 			// A new underlay should be established.
-			underlay, err := m.acceptTCPUnderlay(rawListener, properties)
+			underlay, err := m.acceptTCPUnderlay(listener, properties)
 			if err != nil {
 				log.Debugf("%v", err)
 				break
@@ -418,6 +1158,7 @@ func (m *Mux) acceptUnderlayLoop(ctx context.Context, properties UnderlayPropert
 			m.cleanUnderlay(false)
 			m.mu.Unlock()
 			UnderlayPassiveOpens.Add(1)
+			b.underlays.Add(1)
 			currEst := UnderlayCurrEstablished.Add(1)
 			maxConn := UnderlayMaxConn.Load()
 			if currEst > maxConn {
@@ -445,6 +1186,7 @@ func (m *Mux) acceptUnderlayLoop(ctx context.Context, properties UnderlayPropert
 					}
 					select {
 					case m.chAccept <- conn:
+						b.sessions.Add(1)
 					case <-ctx.Done():
 						return
 					}
@@ -454,25 +1196,16 @@ func (m *Mux) acceptUnderlayLoop(ctx context.Context, properties UnderlayPropert
 	case "udp", "udp4", "udp6":
 		udpAddr, err := apicommon.ResolveUDPAddr(m.resolver, "udp", laddr)
 		if err != nil {
-			log.Errorf("ResolveUDPAddr() failed: %v", err)
-			if m.acceptHasErr.CompareAndSwap(false, true) {
-				close(m.acceptErr)
-			}
+			m.onListenError(b, fmt.Errorf("ResolveUDPAddr() failed: %w", err))
 			return
 		}
-		conn, err := net.ListenUDP(network, udpAddr)
+		conn, err := systemd.ListenUDP(network, udpAddr)
 		if err != nil {
-			log.Errorf("ListenUDP() failed: %v", err)
-			if m.acceptHasErr.CompareAndSwap(false, true) {
-				close(m.acceptErr)
-			}
+			m.onListenError(b, fmt.Errorf("ListenUDP() failed: %w", err))
 			return
 		}
 		if err := sockopts.ApplyUDPControls(conn); err != nil {
-			log.Errorf("ApplyUDPControls() failed: %v", err)
-			if m.acceptHasErr.CompareAndSwap(false, true) {
-				close(m.acceptErr)
-			}
+			m.onListenError(b, fmt.Errorf("ApplyUDPControls() failed: %w", err))
 			log.Infof("Closing UDPConn %v", conn.LocalAddr())
 			conn.Close()
 			return
@@ -483,13 +1216,21 @@ func (m *Mux) acceptUnderlayLoop(ctx context.Context, properties UnderlayPropert
 			conn:              conn,
 			idleSessionTicker: time.NewTicker(idleSessionTickerInterval),
 			users:             m.users,
+			userGroups:        m.userGroups,
+			overload:          &m.overload,
+			maintenance:       &m.maintenance,
+			keyRotation:       &m.keyRotation,
+			knockAllowed:      m.isKnockAllowed,
+			portHopping:       portHoppingOptionsOf(properties),
 		}
 		log.Infof("Created new server underlay %v", underlay)
+		l.setSocket(underlay)
 		m.mu.Lock()
 		m.underlays = append(m.underlays, underlay)
 		m.cleanUnderlay(false)
 		m.mu.Unlock()
 		UnderlayPassiveOpens.Add(1)
+		b.underlays.Add(1)
 		currEst := UnderlayCurrEstablished.Add(1)
 		maxConn := UnderlayMaxConn.Load()
 		if currEst > maxConn {
@@ -517,16 +1258,14 @@ func (m *Mux) acceptUnderlayLoop(ctx context.Context, properties UnderlayPropert
 				}
 				select {
 				case m.chAccept <- conn:
+					b.sessions.Add(1)
 				case <-ctx.Done():
 					return
 				}
 			}
 		}(ctx, underlay)
 	default:
-		log.Errorf("Unsupported underlay network type %q", network)
-		if m.acceptHasErr.CompareAndSwap(false, true) {
-			close(m.acceptErr)
-		}
+		m.onListenError(b, fmt.Errorf("unsupported underlay network type %q", network))
 	}
 }
 
@@ -539,90 +1278,318 @@ func (m *Mux) acceptTCPUnderlay(rawListener net.Listener, properties UnderlayPro
 }
 
 func (m *Mux) serverWrapTCPConn(rawConn net.Conn, mtu int, users map[string]*appctlpb.User) Underlay {
-	var err error
-	var blocks []cipher.BlockCipher
-	for _, user := range users {
-		var password []byte
-		password, err = hex.DecodeString(user.GetHashedPassword())
-		if err != nil {
-			log.Debugf("Unable to decode hashed password %q from user %q", user.GetHashedPassword(), user.GetName())
-			continue
-		}
-		if len(password) == 0 {
-			password = cipher.HashPassword([]byte(user.GetPassword()), []byte(user.GetName()))
-		}
-		blocksFromUser, err := cipher.BlockCipherListFromPassword(password, false)
-		if err != nil {
-			log.Debugf("Unable to create block cipher of user %q", user.GetName())
-			continue
-		}
-		for _, block := range blocksFromUser {
-			block.SetBlockContext(cipher.BlockContext{
-				UserName: user.GetName(),
-			})
-		}
-		blocks = append(blocks, blocksFromUser...)
-	}
 	return &StreamUnderlay{
 		baseUnderlay: *newBaseUnderlay(false, mtu),
 		conn:         rawConn,
-		candidates:   blocks,
+		candidates:   serverBlockCiphers(users, m.keyRotation.Load()),
 		users:        users,
+		userGroups:   m.userGroups,
+		overload:     &m.overload,
+		maintenance:  &m.maintenance,
+	}
+}
+
+// isAuthenticatedStream returns true if the first encrypted metadata
+// of a stream underlay can be decrypted by a user.
+func (m *Mux) isAuthenticatedStream(encryptedMeta []byte) bool {
+	m.mu.Lock()
+	users := m.users
+	m.mu.Unlock()
+	_, _, err := cipher.SelectDecrypt(encryptedMeta, serverBlockCiphers(users, m.keyRotation.Load()))
+	return err == nil
+}
+
+// serverBlockCiphers returns the block ciphers of all the users
+// in the key epochs accepted now.
+func serverBlockCiphers(users map[string]*appctlpb.User, rotation *cipher.KeyRotation) []cipher.BlockCipher {
+	var blocks []cipher.BlockCipher
+	for _, user := range users {
+		for _, password := range userPasswords(user, rotation) {
+			for _, aeadType := range aeadTypesOf(user.GetCipherSuite()) {
+				blocksFromUser, err := cipher.BlockCipherListFromPasswordWithAEAD(password, aeadType, false)
+				if err != nil {
+					log.Debugf("Unable to create block cipher of user %q", user.GetName())
+					continue
+				}
+				for _, block := range blocksFromUser {
+					block.SetBlockContext(cipher.BlockContext{
+						UserName: user.GetName(),
+					})
+				}
+				blocks = append(blocks, blocksFromUser...)
+			}
+		}
+	}
+	return blocks
+}
+
+// aeadTypeOf returns the AEAD algorithm used by proxy client.
+// For the AUTO cipher suite, it is the fastest one on this device.
+func aeadTypeOf(suite appctlpb.CipherSuite) cipher.AEADType {
+	switch suite {
+	case appctlpb.CipherSuite_AUTO_CIPHER_SUITE:
+		return cipher.FastestAEADType()
+	case appctlpb.CipherSuite_AES_128_GCM:
+		return cipher.AES128GCM
+	case appctlpb.CipherSuite_AES_256_GCM:
+		return cipher.AES256GCM
+	case appctlpb.CipherSuite_CHACHA20_POLY1305:
+		return cipher.ChaCha20Poly1305
+	default:
+		return cipher.DefaultAEADType
+	}
+}
+
+// aeadTypesOf returns the AEAD algorithms accepted by proxy server.
+func aeadTypesOf(suite appctlpb.CipherSuite) []cipher.AEADType {
+	if suite == appctlpb.CipherSuite_AUTO_CIPHER_SUITE {
+		return cipher.SupportedAEADTypes
+	}
+	return []cipher.AEADType{aeadTypeOf(suite)}
+}
+
+// userPasswords returns the hashed passwords of the user in the key
+// epochs accepted now. The current epoch is the first one.
+// If key rotation is disabled, it only returns the hashed password.
+func userPasswords(user *appctlpb.User, rotation *cipher.KeyRotation) [][]byte {
+	password, err := hex.DecodeString(user.GetHashedPassword())
+	if err != nil {
+		log.Debugf("Unable to decode hashed password %q from user %q", user.GetHashedPassword(), user.GetName())
+		return nil
+	}
+	if len(password) == 0 {
+		password = cipher.HashPassword([]byte(user.GetPassword()), []byte(user.GetName()))
+	}
+	if rotation == nil {
+		return [][]byte{password}
 	}
+	var passwords [][]byte
+	for _, epoch := range rotation.AcceptedEpochs(time.Now()) {
+		passwords = append(passwords, rotation.EpochPassword(password, epoch))
+	}
+	return passwords
 }
 
 // newUnderlay returns a new underlay.
 // This method MUST be called only when holding the mu lock.
 func (m *Mux) newUnderlay(ctx context.Context) (Underlay, error) {
+	p := m.pickEndpoint()
+	underlay, err := m.dialUnderlay(ctx, p, m.username, m.password, m.cipherSuite, m.multipathDevices, m.localBinding)
+	if err != nil {
+		return nil, err
+	}
+	if packetUnderlay, ok := underlay.(*PacketUnderlay); ok && m.pathMTUDiscovery {
+		packetUnderlay.enablePathMTUDiscovery()
+	}
+	m.underlays = append(m.underlays, underlay)
+	UnderlayActiveOpens.Add(1)
+	currEst := UnderlayCurrEstablished.Add(1)
+	maxConn := UnderlayMaxConn.Load()
+	if currEst > maxConn {
+		UnderlayMaxConn.Store(currEst)
+	}
+	go func() {
+		// This is a long running loop, detach from client dial context.
+		err := underlay.RunEventLoop(context.Background())
+		if err != nil && !stderror.IsEOF(err) && !stderror.IsClosed(err) {
+			log.Debugf("%v RunEventLoop(): %v", underlay, err)
+		}
+		underlay.Close()
+	}()
+	return underlay, nil
+}
+
+// dialUnderlay creates a new client underlay to the endpoint.
+// If devices is not empty, UDP underlays send packets over these
+// local network devices. Otherwise, UDP underlays send packets from
+// the local binding.
+func (m *Mux) dialUnderlay(ctx context.Context, p UnderlayProperties, username string, password []byte, suite appctlpb.CipherSuite, devices []string, binding common.LocalBinding) (Underlay, error) {
+	now := time.Now()
+	userKey := password
+	rotation := m.keyRotation.Load()
+	if rotation != nil {
+		password = rotation.EpochPassword(password, rotation.Epoch(now))
+	}
+	if opts := portKnockingOptionsOf(p); opts != nil {
+		host, _, err := net.SplitHostPort(p.RemoteAddr().String())
+		if err != nil {
+			return nil, fmt.Errorf("net.SplitHostPort() failed: %w", err)
+		}
+		if err := opts.knock(ctx, m.dialer, host); err != nil {
+			return nil, err
+		}
+	}
+	dialer := m.dialer
+	if m.heartbeat > 0 || m.deadPeerTimeout > 0 {
+		dialer = &tcpHeartbeatDialer{Dialer: dialer, interval: m.heartbeat, deadPeerTimeout: m.deadPeerTimeout}
+	}
 	var underlay Underlay
-	i := mrand.Intn(len(m.endpoints))
-	p := m.endpoints[i]
 	switch p.TransportProtocol() {
 	case common.StreamTransport:
-		block, err := cipher.BlockCipherFromPassword(m.password, false)
+		block, err := cipher.BlockCipherFromPasswordWithAEAD(password, aeadTypeOf(suite), false)
 		if err != nil {
-			return nil, fmt.Errorf("cipher.BlockCipherFromPassword() failed: %v", err)
+			return nil, fmt.Errorf("cipher.BlockCipherFromPasswordWithAEAD() failed: %v", err)
 		}
 		block.SetBlockContext(cipher.BlockContext{
-			UserName: m.username,
+			UserName: username,
 		})
-		underlay, err = NewStreamUnderlay(ctx, m.dialer, p.RemoteAddr().Network(), p.RemoteAddr().String(), p.MTU(), block)
+		if opts := tlsCamouflageOptionsOf(p); opts != nil {
+			underlay, err = newTLSCamouflageUnderlay(ctx, dialer, p.RemoteAddr().Network(), p.RemoteAddr().String(), p.MTU(), block, opts)
+			if err != nil {
+				return nil, fmt.Errorf("newTLSCamouflageUnderlay() failed: %v", err)
+			}
+			break
+		}
+		underlay, err = NewStreamUnderlay(ctx, dialer, p.RemoteAddr().Network(), p.RemoteAddr().String(), p.MTU(), block)
 		if err != nil {
 			return nil, fmt.Errorf("NewTCPUnderlay() failed: %v", err)
 		}
+	case common.WebSocketTransport:
+		block, err := cipher.BlockCipherFromPasswordWithAEAD(password, aeadTypeOf(suite), false)
+		if err != nil {
+			return nil, fmt.Errorf("cipher.BlockCipherFromPasswordWithAEAD() failed: %v", err)
+		}
+		block.SetBlockContext(cipher.BlockContext{
+			UserName: username,
+		})
+		underlay, err = newWebSocketUnderlay(ctx, dialer, p.RemoteAddr().Network(), p.RemoteAddr().String(), p.MTU(), block, webSocketOptionsOf(p))
+		if err != nil {
+			return nil, fmt.Errorf("newWebSocketUnderlay() failed: %v", err)
+		}
 	case common.PacketTransport:
-		block, err := cipher.BlockCipherFromPassword(m.password, true)
+		block, err := cipher.BlockCipherFromPasswordWithAEAD(password, aeadTypeOf(suite), true)
 		if err != nil {
-			return nil, fmt.Errorf("cipher.BlockCipherFromPassword() failed: %v", err)
+			return nil, fmt.Errorf("cipher.BlockCipherFromPasswordWithAEAD() failed: %v", err)
 		}
 		block.SetBlockContext(cipher.BlockContext{
-			UserName: m.username,
+			UserName: username,
 		})
-		underlay, err = NewPacketUnderlay(ctx, p.RemoteAddr().Network(), p.RemoteAddr().String(), p.MTU(), block, m.resolver)
+		addr := p.RemoteAddr().String()
+		hopping := portHoppingOptionsOf(p)
+		if hopping != nil {
+			addr, err = hopping.hopAddr(addr, userKey, now)
+			if err != nil {
+				return nil, fmt.Errorf("hopAddr() failed: %v", err)
+			}
+		}
+		underlay, err = newPacketUnderlay(ctx, p.RemoteAddr().Network(), addr, p.MTU(), block, m.resolver, devices, binding)
 		if err != nil {
 			return nil, fmt.Errorf("NewUDPUnderlay() failed: %v", err)
 		}
+		if hopping != nil {
+			PortHoppingDials.Add(1)
+			// Move new sessions to an underlay of the next active port.
+			underlay.Scheduler().SetRemainingTime(hopping.SlotEnd(now).Sub(time.Now()))
+		}
 	default:
 		return nil, fmt.Errorf("unsupport transport protocol %v", p.TransportProtocol())
 	}
-	m.underlays = append(m.underlays, underlay)
-	UnderlayActiveOpens.Add(1)
-	currEst := UnderlayCurrEstablished.Add(1)
-	maxConn := UnderlayMaxConn.Load()
-	if currEst > maxConn {
-		UnderlayMaxConn.Store(currEst)
-	}
-	go func() {
-		// This is a long running loop, detach from client dial context.
-		err := underlay.RunEventLoop(context.Background())
-		if err != nil && !stderror.IsEOF(err) && !stderror.IsClosed(err) {
-			log.Debugf("%v RunEventLoop(): %v", underlay, err)
+	if streamUnderlay, ok := underlay.(*StreamUnderlay); ok && m.hybridKeyExchange.Load() {
+		if err := streamUnderlay.exchangeHybridKey(ctx); err != nil {
+			streamUnderlay.Close()
+			return nil, fmt.Errorf("exchangeHybridKey() failed: %w", err)
 		}
-		underlay.Close()
-	}()
+	}
+	if rotation != nil {
+		// Move new sessions to an underlay of the next key epoch.
+		underlay.Scheduler().SetRemainingTime(rotation.EpochEnd(now).Sub(time.Now()))
+	}
 	return underlay, nil
 }
 
+// pickEndpoint returns a random endpoint to create a new underlay.
+// Endpoints that recently requested to retry later are avoided,
+// unless all the endpoints are in that state. AUTO endpoints that
+// don't use the selected transport protocol are avoided. If low
+// latency is preferred, the endpoints with the lowest latency are used.
+// This method MUST be called only when holding the mu lock.
+func (m *Mux) pickEndpoint() UnderlayProperties {
+	m.retryLaterMu.Lock()
+	defer m.retryLaterMu.Unlock()
+	available := make([]UnderlayProperties, 0)
+	now := time.Now()
+	for _, p := range m.endpoints {
+		key := endpointKey(p.RemoteAddr())
+		if until, found := m.retryLater[key]; found {
+			if now.Before(until) {
+				continue
+			}
+			delete(m.retryLater, key)
+		}
+		available = append(available, p)
+	}
+	if len(available) == 0 {
+		log.Debugf("All %d endpoints requested to retry later", len(m.endpoints))
+		available = m.endpoints
+	}
+	available = m.autoTransportEndpoints(available)
+	if m.preferLowLatency {
+		available = m.lowestLatencyEndpoints(available)
+	}
+	return available[mrand.Intn(len(available))]
+}
+
+// lowestLatencyEndpoints returns the endpoints with the lowest measured
+// latency. If the latency of all endpoints is unknown, or they are all
+// unreachable, the input is returned.
+func (m *Mux) lowestLatencyEndpoints(endpoints []UnderlayProperties) []UnderlayProperties {
+	m.pathLatencyMu.Lock()
+	defer m.pathLatencyMu.Unlock()
+	var lowest time.Duration = -1
+	for _, p := range endpoints {
+		latency, found := m.pathLatency[pathKey(p.RemoteAddr())]
+		if found && latency >= 0 && (lowest < 0 || latency < lowest) {
+			lowest = latency
+		}
+	}
+	if lowest < 0 {
+		return endpoints
+	}
+	selected := make([]UnderlayProperties, 0)
+	for _, p := range endpoints {
+		if latency, found := m.pathLatency[pathKey(p.RemoteAddr())]; found && latency == lowest {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// onRetryLater is invoked when a server endpoint requests to retry later.
+// New sessions are not scheduled to the underlay, and the endpoint
+// is avoided when creating new underlays for a while.
+func (m *Mux) onRetryLater(underlay Underlay) {
+	underlay.Scheduler().Disable()
+	key := endpointKey(underlay.RemoteAddr())
+	m.retryLaterMu.Lock()
+	defer m.retryLaterMu.Unlock()
+	m.retryLater[key] = time.Now().Add(retryLaterBackoff)
+	log.Infof("Server endpoint %s is overloaded, avoid it for %v", key, retryLaterBackoff)
+}
+
+// onMaintenance is invoked when a server endpoint announces an upcoming
+// maintenance. The endpoint is avoided when creating new underlays for
+// a while. If other endpoints are available, new sessions are not
+// scheduled to the underlay. Existing sessions are not impacted.
+func (m *Mux) onMaintenance(underlay Underlay, endpoints []UnderlayProperties) {
+	key := endpointKey(underlay.RemoteAddr())
+	m.retryLaterMu.Lock()
+	defer m.retryLaterMu.Unlock()
+	now := time.Now()
+	m.retryLater[key] = now.Add(maintenanceBackoff)
+	available := 0
+	for _, p := range endpoints {
+		if until, found := m.retryLater[endpointKey(p.RemoteAddr())]; !found || !now.Before(until) {
+			available++
+		}
+	}
+	if available > 0 {
+		underlay.Scheduler().Disable()
+		log.Infof("Server endpoint %s announced maintenance, avoid it for %v", key, maintenanceBackoff)
+	} else {
+		log.Infof("Server endpoint %s announced maintenance, but no other endpoint is available", key)
+	}
+}
+
 // maybePickExistingUnderlay returns either an existing underlay that
 // can be used by a session, or nil. In the later case a new underlay
 // should be created.
@@ -639,6 +1606,16 @@ func (m *Mux) maybePickExistingUnderlay() Underlay {
 		}
 	}
 
+	if m.maxUnderlays > 0 && len(active) >= m.maxUnderlays {
+		// Reuse the underlay with the fewest sessions.
+		var picked Underlay
+		for _, underlay := range active {
+			if picked == nil || underlay.SessionCount() < picked.SessionCount() {
+				picked = underlay
+			}
+		}
+		return picked
+	}
 	if m.multiplexFactor > 0 {
 		reuseUnderlayFactor := len(active) * m.multiplexFactor
 		n := mrand.Intn(reuseUnderlayFactor + 1)
@@ -649,6 +1626,71 @@ func (m *Mux) maybePickExistingUnderlay() Underlay {
 	return nil
 }
 
+// pathKey returns the network path to the address. The addresses with
+// the same IP address and network share the same path.
+func pathKey(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	return addr.Network() + "://" + host
+}
+
+func endpointKey(addr net.Addr) string {
+	return addr.Network() + "://" + addr.String()
+}
+
+// staleUnderlays returns the client underlays that are not
+// connected to any of the endpoints, or are created with replaced
+// credentials.
+// This method MUST be called only when holding the mu lock.
+func (m *Mux) staleUnderlays() []Underlay {
+	keys := make(map[string]struct{})
+	for _, p := range m.endpoints {
+		keys[endpointKey(p.RemoteAddr())] = struct{}{}
+	}
+	stale := make([]Underlay, 0)
+	current := make(map[Underlay]struct{}, len(m.underlays))
+	for _, underlay := range m.underlays {
+		current[underlay] = struct{}{}
+		if _, old := m.oldCredentials[underlay]; old {
+			stale = append(stale, underlay)
+			continue
+		}
+		if _, found := keys[endpointKey(underlay.RemoteAddr())]; !found && !m.isHoppingEndpoint(underlay.RemoteAddr()) {
+			stale = append(stale, underlay)
+		}
+	}
+	// Forget the underlays that are already removed.
+	for underlay := range m.oldCredentials {
+		if _, found := current[underlay]; !found {
+			delete(m.oldCredentials, underlay)
+		}
+	}
+	return stale
+}
+
+// isHoppingEndpoint returns true if the address is in the port range
+// of an endpoint with port hopping.
+// This method MUST be called only when holding the mu lock.
+func (m *Mux) isHoppingEndpoint(addr net.Addr) bool {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return false
+	}
+	for _, p := range m.endpoints {
+		opts := portHoppingOptionsOf(p)
+		if opts == nil || !opts.Contains(udpAddr.Port) {
+			continue
+		}
+		host, _, err := net.SplitHostPort(p.RemoteAddr().String())
+		if err == nil && net.ParseIP(host).Equal(udpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
 // cleanUnderlay removes closed underlays.
 // This method MUST be called only when holding the mu lock.
 func (m *Mux) cleanUnderlay(alsoDisableIdleUnderlay bool) {
@@ -680,3 +1722,16 @@ func (m *Mux) cleanUnderlay(alsoDisableIdleUnderlay bool) {
 		log.Debugf("Mux cleaned %d underlays", close)
 	}
 }
+
+// dedicatedUnderlaySession is a session that owns the underlay.
+// The underlay is closed when the session is closed.
+type dedicatedUnderlaySession struct {
+	*Session
+	underlay Underlay
+}
+
+func (s *dedicatedUnderlaySession) Close() error {
+	err := s.Session.Close()
+	s.underlay.Close()
+	return err
+}
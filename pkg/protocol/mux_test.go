@@ -22,6 +22,7 @@ import (
 	mrand "math/rand"
 	"net"
 	"reflect"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -124,6 +125,91 @@ func TestIPv4TCPUnderlay(t *testing.T) {
 	}
 }
 
+func TestWaitListening(t *testing.T) {
+	tcpPort, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	udpPort, err := common.UnusedUDPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedUDPPort() failed: %v", err)
+	}
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: tcpPort}, nil),
+			NewUnderlayProperties(1400, common.PacketTransport, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: udpPort}, nil),
+		})
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := serverMux.WaitListening(ctx); err != nil {
+		t.Fatalf("WaitListening() failed: %v", err)
+	}
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(tcpPort)))
+	if err != nil {
+		t.Fatalf("net.Dial() failed after WaitListening(): %v", err)
+	}
+	conn.Close()
+}
+
+func TestClientMaxUnderlays(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverProperties := NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	go func() {
+		testServer.Serve(serverMux)
+	}()
+	defer testServer.Close()
+	defer serverMux.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	// Multiplexing is turned off, but the number of underlays is limited.
+	clientProperties := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetClientMultiplexFactor(0).
+		SetClientMaxUnderlays(2).
+		SetEndpoints([]UnderlayProperties{clientProperties})
+	defer clientMux.Close()
+
+	dialCtx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+	for i := 0; i < 6; i++ {
+		conn, err := clientMux.DialContext(dialCtx)
+		if err != nil {
+			t.Fatalf("DialContext() failed: %v", err)
+		}
+		defer conn.Close()
+	}
+
+	clientMux.mu.Lock()
+	defer clientMux.mu.Unlock()
+	if len(clientMux.underlays) != 2 {
+		t.Fatalf("got %d underlays, want 2", len(clientMux.underlays))
+	}
+	for _, underlay := range clientMux.underlays {
+		if underlay.SessionCount() != 3 {
+			t.Errorf("underlay has %d sessions, want 3", underlay.SessionCount())
+		}
+	}
+}
+
 func TestIPv6TCPUnderlay(t *testing.T) {
 	log.SetOutputToTest(t)
 	log.SetLevel("DEBUG")
@@ -188,6 +274,200 @@ func TestIPv4UDPUnderlay(t *testing.T) {
 	}
 }
 
+func TestUDPUnderlayPathMTUDiscovery(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedUDPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedUDPPort() failed: %v", err)
+	}
+	serverProperties := NewUnderlayProperties(1400, common.PacketTransport, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	go func() {
+		if err := testServer.Serve(serverMux); err != nil {
+			t.Errorf("Serve() failed: %v", err)
+		}
+	}()
+	defer testServer.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	clientProperties := NewUnderlayProperties(1400, common.PacketTransport, nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetClientPathMTUDiscovery(true).
+		SetEndpoints([]UnderlayProperties{clientProperties})
+	defer clientMux.Close()
+	conn, err := clientMux.DialContext(context.Background())
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	payload := testtool.TestHelperGenRot13Input(64)
+	if _, err := conn.Write(payload); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if _, err := io.ReadFull(conn, make([]byte, len(payload))); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+
+	// The loopback interface can deliver packets of the configured MTU.
+	clientMux.mu.Lock()
+	underlay := clientMux.underlays[0].(*PacketUnderlay)
+	clientMux.mu.Unlock()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && underlay.prober.mtu() <= 1400-pathMTUSearchPrecision {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := underlay.prober.mtu(); got <= 1400-pathMTUSearchPrecision {
+		t.Errorf("path MTU is %d, want a value larger than %d", got, 1400-pathMTUSearchPrecision)
+	}
+	if got := conn.(*Session).pathMTU(); got != underlay.prober.mtu() {
+		t.Errorf("session path MTU is %d, want %d", got, underlay.prober.mtu())
+	}
+}
+
+func TestUDPUnderlayMTUNegotiation(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	testcases := []struct {
+		name      string
+		serverMTU int
+		clientMTU int
+	}{
+		{"smaller client MTU", 1450, 1300},
+		{"smaller server MTU", 1300, 1450},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			port, err := common.UnusedUDPPort()
+			if err != nil {
+				t.Fatalf("common.UnusedUDPPort() failed: %v", err)
+			}
+			serverProperties := NewUnderlayProperties(tc.serverMTU, common.PacketTransport, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+			serverMux := NewMux(false).
+				SetServerUsers(users).
+				SetEndpoints([]UnderlayProperties{serverProperties})
+			testServer := testtool.NewTestHelperServer()
+			if err := serverMux.Start(); err != nil {
+				t.Fatalf("Start() failed: %v", err)
+			}
+			defer serverMux.Close()
+			go func() {
+				if err := testServer.Serve(serverMux); err != nil {
+					t.Errorf("Serve() failed: %v", err)
+				}
+			}()
+			defer testServer.Close()
+			time.Sleep(100 * time.Millisecond)
+
+			clientProperties := NewUnderlayProperties(tc.clientMTU, common.PacketTransport, nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+			clientMux := NewMux(true).
+				SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+				SetEndpoints([]UnderlayProperties{clientProperties})
+			defer clientMux.Close()
+			conn, err := clientMux.DialContext(context.Background())
+			if err != nil {
+				t.Fatalf("DialContext() failed: %v", err)
+			}
+			defer conn.Close()
+			payload := testtool.TestHelperGenRot13Input(4096)
+			if _, err := conn.Write(payload); err != nil {
+				t.Fatalf("Write() failed: %v", err)
+			}
+			if _, err := io.ReadFull(conn, make([]byte, len(payload))); err != nil {
+				t.Fatalf("io.ReadFull() failed: %v", err)
+			}
+
+			// Both sides use the smaller MTU.
+			want := tc.serverMTU
+			if tc.clientMTU < want {
+				want = tc.clientMTU
+			}
+			if got := conn.(*Session).pathMTU(); got != want {
+				t.Errorf("client path MTU is %d, want %d", got, want)
+			}
+			clientMux.mu.Lock()
+			clientUnderlay := clientMux.underlays[0].(*PacketUnderlay)
+			clientMux.mu.Unlock()
+			serverMux.mu.Lock()
+			serverUnderlay := serverMux.underlays[0].(*PacketUnderlay)
+			serverMux.mu.Unlock()
+			clientAddr := &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: clientUnderlay.LocalAddr().(*net.UDPAddr).Port}
+			if got := serverUnderlay.pathMTU(clientAddr); got != want {
+				t.Errorf("server path MTU is %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestUDPUnderlayMultipath(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	lo := loopbackDevice(t)
+	port, err := common.UnusedUDPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedUDPPort() failed: %v", err)
+	}
+	serverProperties := NewUnderlayProperties(1400, common.PacketTransport, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	go func() {
+		if err := testServer.Serve(serverMux); err != nil {
+			t.Errorf("Serve() failed: %v", err)
+		}
+	}()
+	defer testServer.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	clientProperties := NewUnderlayProperties(1400, common.PacketTransport, nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetClientMultipathDevices([]string{lo, lo}).
+		SetEndpoints([]UnderlayProperties{clientProperties})
+	defer clientMux.Close()
+	conn, err := clientMux.DialContext(context.Background())
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	for i := 0; i < 20; i++ {
+		payload := testtool.TestHelperGenRot13Input(mrand.Intn(maxPDU) + 1)
+		if _, err := conn.Write(payload); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+		resp := make([]byte, len(payload))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			t.Fatalf("io.ReadFull() failed: %v", err)
+		}
+		rot13, err := testtool.TestHelperRot13(resp)
+		if err != nil {
+			t.Fatalf("TestHelperRot13() failed: %v", err)
+		}
+		if !bytes.Equal(payload, rot13) {
+			t.Fatalf("Received unexpected response")
+		}
+	}
+	clientMux.mu.Lock()
+	_, ok := clientMux.underlays[0].(*PacketUnderlay).conn.(*multipathConn)
+	clientMux.mu.Unlock()
+	if !ok {
+		t.Errorf("client underlay doesn't use multipath connection")
+	}
+}
+
 func TestIPv6UDPUnderlay(t *testing.T) {
 	log.SetOutputToTest(t)
 	log.SetLevel("DEBUG")
@@ -220,6 +500,309 @@ func TestIPv6UDPUnderlay(t *testing.T) {
 	}
 }
 
+func TestKeyRotation(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	tcpPort, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	udpPort, err := common.UnusedUDPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedUDPPort() failed: %v", err)
+	}
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: tcpPort}, nil),
+			NewUnderlayProperties(1400, common.PacketTransport, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: udpPort}, nil),
+		})
+	if _, err := serverMux.RotateKeys(); err == nil {
+		t.Errorf("RotateKeys() succeeded without key rotation")
+	}
+	serverMux.SetKeyRotation(cipher.NewKeyRotation(time.Hour, 10*time.Minute))
+	testServer := testtool.NewTestHelperServer()
+
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("[%s] Start() failed: %v", time.Now().Format(testtool.TimeLayout), err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	go func() {
+		if err := testServer.Serve(serverMux); err != nil {
+			t.Errorf("[%s] Serve() failed: %v", time.Now().Format(testtool.TimeLayout), err)
+		}
+	}()
+	defer testServer.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	epoch, err := serverMux.RotateKeys()
+	if err != nil {
+		t.Fatalf("RotateKeys() failed: %v", err)
+	}
+	if want := time.Now().UnixNano() / time.Hour.Nanoseconds(); epoch != want {
+		t.Errorf("RotateKeys() = %d, want %d", epoch, want)
+	}
+
+	for _, properties := range []UnderlayProperties{
+		NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: tcpPort}),
+		NewUnderlayProperties(1400, common.PacketTransport, nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: udpPort}),
+	} {
+		username := []byte("xiaochitang")
+		clientMux := NewMux(true).
+			SetClientUserNamePassword(string(username), cipher.HashPassword([]byte("kuiranbudong"), username)).
+			SetKeyRotation(cipher.NewKeyRotation(time.Hour, 10*time.Minute)).
+			SetEndpoints([]UnderlayProperties{properties})
+		ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+		conn, err := clientMux.DialContext(ctx)
+		if err != nil {
+			t.Fatalf("DialContext() failed: %v", err)
+		}
+		payload := testtool.TestHelperGenRot13Input(1024)
+		if _, err := conn.Write(payload); err != nil {
+			t.Errorf("Write() failed: %v", err)
+		}
+		resp := make([]byte, len(payload))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			t.Errorf("io.ReadFull() failed: %v", err)
+		}
+		if rot13, err := testtool.TestHelperRot13(resp); err != nil || !bytes.Equal(payload, rot13) {
+			t.Errorf("Received unexpected response")
+		}
+		conn.Close()
+		cancelFunc()
+		clientMux.Close()
+	}
+	if err := serverMux.Close(); err != nil {
+		t.Errorf("Server mux close failed: %v", err)
+	}
+}
+
+func TestCipherSuites(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("INFO")
+	for _, suite := range []appctlpb.CipherSuite{
+		appctlpb.CipherSuite_AES_128_GCM,
+		appctlpb.CipherSuite_AES_256_GCM,
+		appctlpb.CipherSuite_CHACHA20_POLY1305,
+		appctlpb.CipherSuite_AUTO_CIPHER_SUITE,
+	} {
+		tcpPort, err := common.UnusedTCPPort()
+		if err != nil {
+			t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+		}
+		udpPort, err := common.UnusedUDPPort()
+		if err != nil {
+			t.Fatalf("common.UnusedUDPPort() failed: %v", err)
+		}
+		serverMux := NewMux(false).
+			SetServerUsers(map[string]*appctlpb.User{
+				"xiaochitang": {
+					Name:        proto.String("xiaochitang"),
+					Password:    proto.String("kuiranbudong"),
+					CipherSuite: suite.Enum(),
+				},
+			}).
+			SetEndpoints([]UnderlayProperties{
+				NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: tcpPort}, nil),
+				NewUnderlayProperties(1400, common.PacketTransport, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: udpPort}, nil),
+			})
+		testServer := testtool.NewTestHelperServer()
+		if err := serverMux.Start(); err != nil {
+			t.Fatalf("[%s] Start() failed: %v", time.Now().Format(testtool.TimeLayout), err)
+		}
+		time.Sleep(100 * time.Millisecond)
+		go func() {
+			if err := testServer.Serve(serverMux); err != nil {
+				t.Errorf("[%s] Serve() failed: %v", time.Now().Format(testtool.TimeLayout), err)
+			}
+		}()
+		time.Sleep(100 * time.Millisecond)
+
+		for _, properties := range []UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: tcpPort}),
+			NewUnderlayProperties(1400, common.PacketTransport, nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: udpPort}),
+		} {
+			username := []byte("xiaochitang")
+			clientMux := NewMux(true).
+				SetClientUserNamePassword(string(username), cipher.HashPassword([]byte("kuiranbudong"), username)).
+				SetClientCipherSuite(suite).
+				SetEndpoints([]UnderlayProperties{properties})
+			ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+			conn, err := clientMux.DialContext(ctx)
+			if err != nil {
+				t.Fatalf("%v: DialContext() failed: %v", suite, err)
+			}
+			payload := testtool.TestHelperGenRot13Input(4096)
+			if _, err := conn.Write(payload); err != nil {
+				t.Errorf("%v: Write() failed: %v", suite, err)
+			}
+			resp := make([]byte, len(payload))
+			if _, err := io.ReadFull(conn, resp); err != nil {
+				t.Errorf("%v: io.ReadFull() failed: %v", suite, err)
+			}
+			if rot13, err := testtool.TestHelperRot13(resp); err != nil || !bytes.Equal(payload, rot13) {
+				t.Errorf("%v: received unexpected response", suite)
+			}
+			conn.Close()
+			cancelFunc()
+			clientMux.Close()
+		}
+		testServer.Close()
+		if err := serverMux.Close(); err != nil {
+			t.Errorf("Server mux close failed: %v", err)
+		}
+	}
+}
+
+func TestHybridKeyExchange(t *testing.T) {
+	if !cipher.HybridKeyExchangeSupported {
+		t.Skip("hybrid key exchange is not supported")
+	}
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil),
+		})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("[%s] Start() failed: %v", time.Now().Format(testtool.TimeLayout), err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	go func() {
+		if err := testServer.Serve(serverMux); err != nil {
+			t.Errorf("[%s] Serve() failed: %v", time.Now().Format(testtool.TimeLayout), err)
+		}
+	}()
+	defer testServer.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	exchanges := HybridKeyExchanges.Load()
+	username := []byte("xiaochitang")
+	clientMux := NewMux(true).
+		SetClientUserNamePassword(string(username), cipher.HashPassword([]byte("kuiranbudong"), username)).
+		SetClientHybridKeyExchange(true).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}),
+		})
+	defer clientMux.Close()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+	conn, err := clientMux.DialContext(ctx)
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	for i := 0; i < 10; i++ {
+		payload := testtool.TestHelperGenRot13Input(mrand.Intn(maxPDU) + 1)
+		if _, err := conn.Write(payload); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+		resp := make([]byte, len(payload))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			t.Fatalf("io.ReadFull() failed: %v", err)
+		}
+		if rot13, err := testtool.TestHelperRot13(resp); err != nil || !bytes.Equal(payload, rot13) {
+			t.Fatalf("received unexpected response")
+		}
+	}
+
+	// Both the client and the server finished the key exchange.
+	if got := HybridKeyExchanges.Load() - exchanges; got != 2 {
+		t.Errorf("HybridKeyExchanges increased by %d, want 2", got)
+	}
+	if err := serverMux.Close(); err != nil {
+		t.Errorf("Server mux close failed: %v", err)
+	}
+}
+
+func TestServerAcceptLimit(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetServerAcceptLimit(common.AcceptLimit{HandshakeTimeout: 500 * time.Millisecond}).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil),
+		})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("[%s] Start() failed: %v", time.Now().Format(testtool.TimeLayout), err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	go func() {
+		if err := testServer.Serve(serverMux); err != nil {
+			t.Errorf("[%s] Serve() failed: %v", time.Now().Format(testtool.TimeLayout), err)
+		}
+	}()
+	defer testServer.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	// A connection that doesn't send anything is closed.
+	timeouts := UnderlayAcceptMetrics.HandshakeTimeouts.Load()
+	idleConn, err := net.DialTCP("tcp", nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	defer idleConn.Close()
+	idleConn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := idleConn.Read(make([]byte, 1)); err == nil {
+		t.Fatalf("Read() got data from an idle connection")
+	} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		t.Fatalf("idle connection is not closed after the handshake timeout")
+	}
+	if got := UnderlayAcceptMetrics.HandshakeTimeouts.Load() - timeouts; got != 1 {
+		t.Errorf("HandshakeTimeouts increased by %d, want 1", got)
+	}
+
+	// An authenticated underlay is kept after the handshake timeout.
+	username := []byte("xiaochitang")
+	clientMux := NewMux(true).
+		SetClientUserNamePassword(string(username), cipher.HashPassword([]byte("kuiranbudong"), username)).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}),
+		})
+	defer clientMux.Close()
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+	conn, err := clientMux.DialContext(ctx)
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			time.Sleep(time.Second)
+		}
+		payload := testtool.TestHelperGenRot13Input(mrand.Intn(maxPDU) + 1)
+		if _, err := conn.Write(payload); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+		resp := make([]byte, len(payload))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			t.Fatalf("io.ReadFull() failed: %v", err)
+		}
+		if rot13, err := testtool.TestHelperRot13(resp); err != nil || !bytes.Equal(payload, rot13) {
+			t.Fatalf("received unexpected response")
+		}
+	}
+	if got := UnderlayAcceptMetrics.HandshakeTimeouts.Load() - timeouts; got != 1 {
+		t.Errorf("HandshakeTimeouts increased by %d, want 1", got)
+	}
+	if err := serverMux.Close(); err != nil {
+		t.Errorf("Server mux close failed: %v", err)
+	}
+}
+
 func TestNewEndpoints(t *testing.T) {
 	cases := []struct {
 		old []UnderlayProperties
@@ -270,3 +853,466 @@ func TestNewEndpoints(t *testing.T) {
 		}
 	}
 }
+
+func TestPickEndpointAvoidRetryLater(t *testing.T) {
+	ep1 := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8964})
+	ep2 := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8965})
+	mux := NewMux(true).SetEndpoints([]UnderlayProperties{ep1, ep2})
+	defer mux.Close()
+
+	mux.retryLater[endpointKey(ep1.RemoteAddr())] = time.Now().Add(time.Minute)
+	for i := 0; i < 10; i++ {
+		if p := mux.pickEndpoint(); p != ep2 {
+			t.Fatalf("pickEndpoint() = %v, want %v", p.RemoteAddr(), ep2.RemoteAddr())
+		}
+	}
+
+	// If all endpoints requested to retry later, any of them can be used.
+	mux.retryLater[endpointKey(ep2.RemoteAddr())] = time.Now().Add(time.Minute)
+	if p := mux.pickEndpoint(); p != ep1 && p != ep2 {
+		t.Errorf("pickEndpoint() returned unknown endpoint %v", p.RemoteAddr())
+	}
+
+	// Expired records are removed.
+	mux.retryLater[endpointKey(ep1.RemoteAddr())] = time.Now().Add(-time.Second)
+	if p := mux.pickEndpoint(); p != ep1 {
+		t.Errorf("pickEndpoint() = %v, want %v", p.RemoteAddr(), ep1.RemoteAddr())
+	}
+	if _, found := mux.retryLater[endpointKey(ep1.RemoteAddr())]; found {
+		t.Errorf("expired retry later record is not removed")
+	}
+}
+
+func TestServerMaintenanceAnnouncement(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverProperties := NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties}).
+		SetServerMaintenance(true)
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	go func() {
+		testServer.Serve(serverMux)
+	}()
+	defer testServer.Close()
+	defer serverMux.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	// The second endpoint is unreachable and avoided, so the first one is used.
+	ep1 := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	ep2 := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port + 1})
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetClientMultiplexFactor(2).
+		SetEndpoints([]UnderlayProperties{ep1, ep2})
+	defer clientMux.Close()
+	clientMux.retryLater[endpointKey(ep2.RemoteAddr())] = time.Now().Add(time.Minute)
+
+	dialCtx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+	conn, err := clientMux.DialContext(dialCtx)
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	payload := testtool.TestHelperGenRot13Input(64)
+	if _, err := conn.Write(payload); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	resp := make([]byte, len(payload))
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+
+	clientMux.retryLaterMu.Lock()
+	until, found := clientMux.retryLater[endpointKey(ep1.RemoteAddr())]
+	clientMux.retryLaterMu.Unlock()
+	if !found || time.Until(until) < maintenanceBackoff-time.Minute {
+		t.Errorf("endpoint with maintenance is not avoided")
+	}
+	// No other endpoint is available, so the underlay can still be used.
+	clientMux.mu.Lock()
+	defer clientMux.mu.Unlock()
+	for _, underlay := range clientMux.underlays {
+		if underlay.Scheduler().IsDisabled() {
+			t.Errorf("underlay is disabled when no other endpoint is available")
+		}
+	}
+}
+
+func TestPickEndpointPreferLowLatency(t *testing.T) {
+	ep1 := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8964})
+	ep2 := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8965})
+	ep3 := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.2"), Port: 8964})
+	mux := NewMux(true).SetEndpoints([]UnderlayProperties{ep1, ep2, ep3}).SetClientPreferLowLatency(true)
+	defer mux.Close()
+
+	// Endpoints on the same IP address share one network path.
+	if targets := mux.ProbeTargets(); len(targets) != 2 {
+		t.Fatalf("got %d probe targets, want 2", len(targets))
+	}
+
+	mux.SetPathLatency(ep1, 50*time.Millisecond, true)
+	mux.SetPathLatency(ep3, 10*time.Millisecond, true)
+	for i := 0; i < 10; i++ {
+		if p := mux.pickEndpoint(); p != ep3 {
+			t.Fatalf("pickEndpoint() = %v, want %v", p.RemoteAddr(), ep3.RemoteAddr())
+		}
+	}
+
+	// Unreachable paths are avoided.
+	mux.SetPathLatency(ep3, 0, false)
+	for i := 0; i < 10; i++ {
+		if p := mux.pickEndpoint(); p != ep1 && p != ep2 {
+			t.Fatalf("pickEndpoint() = %v, want an endpoint on 127.0.0.1", p.RemoteAddr())
+		}
+	}
+}
+
+func TestClientSetEndpointsPinUnderlay(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverProperties := NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	time.Sleep(100 * time.Millisecond)
+	go func() {
+		testServer.Serve(serverMux)
+	}()
+	defer testServer.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	clientProperties := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetEndpoints([]UnderlayProperties{clientProperties})
+	defer clientMux.Close()
+	conn, err := clientMux.DialContext(context.Background())
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+
+	roundTrip := func() error {
+		payload := testtool.TestHelperGenRot13Input(64)
+		if _, err := conn.Write(payload); err != nil {
+			return err
+		}
+		resp := make([]byte, len(payload))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			return err
+		}
+		return nil
+	}
+	if err := roundTrip(); err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+
+	// Move to another endpoint. The existing session is not impacted.
+	newProperties := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port + 1})
+	clientMux.SetEndpoints([]UnderlayProperties{newProperties})
+	clientMux.mu.Lock()
+	stale := clientMux.staleUnderlays()
+	clientMux.mu.Unlock()
+	if len(stale) != 1 {
+		t.Fatalf("got %d stale underlays, want 1", len(stale))
+	}
+	if !stale[0].Scheduler().IsDisabled() {
+		t.Errorf("stale underlay is not disabled")
+	}
+	if err := roundTrip(); err != nil {
+		t.Fatalf("round trip after SetEndpoints() failed: %v", err)
+	}
+
+	// Migrate now closes the existing session.
+	if n := clientMux.CloseStaleUnderlays(); n != 1 {
+		t.Errorf("CloseStaleUnderlays() = %d, want 1", n)
+	}
+	if err := roundTrip(); err == nil {
+		t.Errorf("round trip after CloseStaleUnderlays() succeeded, want error")
+	}
+}
+
+func TestClientCloseUnderlaysOnNetworkChange(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverProperties := NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	if err := serverMux.WaitListening(context.Background()); err != nil {
+		t.Fatalf("WaitListening() failed: %v", err)
+	}
+	go testServer.Serve(serverMux)
+	defer testServer.Close()
+
+	clientProperties := NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetEndpoints([]UnderlayProperties{clientProperties})
+	defer clientMux.Close()
+	clientMux.SetPathLatency(clientProperties, time.Millisecond, true)
+	conn, err := clientMux.DialContext(context.Background())
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+
+	if n := clientMux.CloseUnderlaysOnNetworkChange(); n != 1 {
+		t.Errorf("CloseUnderlaysOnNetworkChange() = %d, want 1", n)
+	}
+	if _, err := conn.Write(testtool.TestHelperGenRot13Input(64)); err == nil {
+		t.Errorf("Write() after CloseUnderlaysOnNetworkChange() succeeded, want error")
+	}
+	clientMux.pathLatencyMu.Lock()
+	n := len(clientMux.pathLatency)
+	clientMux.pathLatencyMu.Unlock()
+	if n != 0 {
+		t.Errorf("got %d path latency records after network change, want 0", n)
+	}
+
+	// New sessions use a new underlay.
+	conn2, err := clientMux.DialContext(context.Background())
+	if err != nil {
+		t.Fatalf("DialContext() after network change failed: %v", err)
+	}
+	defer conn2.Close()
+	payload := testtool.TestHelperGenRot13Input(64)
+	if _, err := conn2.Write(payload); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if _, err := io.ReadFull(conn2, make([]byte, len(payload))); err != nil {
+		t.Fatalf("ReadFull() failed: %v", err)
+	}
+}
+
+func TestServerSetEndpointsReplaceListener(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port1, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	port2, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	ep1 := NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port1}, nil)
+	ep2 := NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port2}, nil)
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{ep1})
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	canDial := func(port int) bool {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), time.Second)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+	if !canDial(port1) {
+		t.Fatalf("endpoint %d is not listening", port1)
+	}
+
+	serverMux.SetEndpoints([]UnderlayProperties{ep2})
+	time.Sleep(100 * time.Millisecond)
+	if canDial(port1) {
+		t.Errorf("removed endpoint %d is still listening", port1)
+	}
+	if !canDial(port2) {
+		t.Errorf("new endpoint %d is not listening", port2)
+	}
+
+	// Changing the MTU reopens the listener on the same address.
+	ep2 = NewUnderlayProperties(1300, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port2}, nil)
+	serverMux.SetEndpoints([]UnderlayProperties{ep2})
+	time.Sleep(100 * time.Millisecond)
+	if !canDial(port2) {
+		t.Errorf("changed endpoint %d is not listening", port2)
+	}
+	serverMux.mu.Lock()
+	if len(serverMux.bindings) != 1 {
+		t.Errorf("got %d port bindings, want 1", len(serverMux.bindings))
+	}
+	serverMux.mu.Unlock()
+}
+
+func TestServerPortBinding(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	tcpEndpoint := NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+	udpEndpoint := NewUnderlayProperties(1400, common.PacketTransport, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+
+	// Occupy the UDP port, so the port binding fails to listen.
+	blocker, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	if err != nil {
+		t.Skipf("UDP port %d is not available: %v", port, err)
+	}
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{tcpEndpoint, udpEndpoint})
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := serverMux.WaitListening(ctx); err != nil {
+		t.Fatalf("WaitListening() failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	list := serverMux.ExportPortBindingStatusList()
+	if len(list.GetItems()) != 1 {
+		t.Fatalf("got %d port bindings, want 1", len(list.GetItems()))
+	}
+	status := list.GetItems()[0]
+	wantAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	if status.GetAddress() != wantAddr {
+		t.Errorf("port binding address = %q, want %q", status.GetAddress(), wantAddr)
+	}
+	wantProtocols := []appctlpb.TransportProtocol{appctlpb.TransportProtocol_TCP, appctlpb.TransportProtocol_UDP}
+	if !reflect.DeepEqual(status.GetProtocols(), wantProtocols) {
+		t.Errorf("port binding protocols = %v, want %v", status.GetProtocols(), wantProtocols)
+	}
+	if status.GetState() != appctlpb.PortBindingState_PORT_BINDING_FAILED {
+		t.Errorf("port binding state = %v, want %v", status.GetState(), appctlpb.PortBindingState_PORT_BINDING_FAILED)
+	}
+	if status.GetError() == "" {
+		t.Errorf("port binding error is empty")
+	}
+	if conn, err := net.DialTimeout("tcp", wantAddr, time.Second); err == nil {
+		conn.Close()
+		t.Errorf("TCP endpoint of a failed port binding is still listening")
+	}
+	if _, err := serverMux.Accept(); err == nil {
+		t.Errorf("Accept() succeeded after port binding failed")
+	}
+
+	// After the UDP port is released, the port binding listens to
+	// both transport protocols.
+	blocker.Close()
+	serverMux2 := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{tcpEndpoint, udpEndpoint})
+	if err := serverMux2.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux2.Close()
+	if err := serverMux2.WaitListening(ctx); err != nil {
+		t.Fatalf("WaitListening() failed: %v", err)
+	}
+	status = serverMux2.ExportPortBindingStatusList().GetItems()[0]
+	if status.GetState() != appctlpb.PortBindingState_PORT_BINDING_LISTENING {
+		t.Errorf("port binding state = %v, want %v", status.GetState(), appctlpb.PortBindingState_PORT_BINDING_LISTENING)
+	}
+	conn, err := net.DialTimeout("tcp", wantAddr, time.Second)
+	if err != nil {
+		t.Fatalf("net.DialTimeout() failed: %v", err)
+	}
+	conn.Close()
+	time.Sleep(100 * time.Millisecond)
+	status = serverMux2.ExportPortBindingStatusList().GetItems()[0]
+	// One underlay from the UDP socket, and one from the TCP connection.
+	if status.GetUnderlays() != 2 {
+		t.Errorf("port binding underlays = %d, want 2", status.GetUnderlays())
+	}
+}
+
+func TestMuxSetClientCredentials(t *testing.T) {
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverMux := NewMux(false).
+		SetServerUsers(map[string]*appctlpb.User{
+			"xiaochitang": users["xiaochitang"],
+			"shilishanlu": {
+				Name:     proto.String("shilishanlu"),
+				Password: proto.String("buhuanjian"),
+			},
+		}).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil),
+		})
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	if err := serverMux.WaitListening(context.Background()); err != nil {
+		t.Fatalf("WaitListening() failed: %v", err)
+	}
+	testServer := testtool.NewTestHelperServer()
+	go testServer.Serve(serverMux)
+	defer testServer.Close()
+
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}),
+		})
+	defer clientMux.Close()
+	echo := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := clientMux.DialContext(ctx)
+		if err != nil {
+			t.Fatalf("DialContext() failed: %v", err)
+		}
+		defer conn.Close()
+		if _, err := conn.Write([]byte("hello")); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+		buf := make([]byte, 5)
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			t.Fatalf("io.ReadFull() failed: %v", err)
+		}
+	}
+	echo()
+
+	// Credentials can be replaced after the mux is used.
+	clientMux.SetClientCredentials("shilishanlu", cipher.HashPassword([]byte("buhuanjian"), []byte("shilishanlu")), appctlpb.CipherSuite_DEFAULT_CIPHER_SUITE)
+	echo()
+	if n := clientMux.CloseStaleUnderlays(); n != 1 {
+		t.Errorf("CloseStaleUnderlays() = %d, want 1", n)
+	}
+	echo()
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/mathext"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+// overloadSampleInterval is the interval to measure the CPU usage and
+// the bandwidth of the server.
+const overloadSampleInterval = time.Second
+
+// OverloadLimits are the limits of the server. When any of them is
+// reached, new sessions are rejected with a retry later signal.
+// A limit of 0 is not enforced.
+type OverloadLimits struct {
+	// Maximum number of concurrent sessions.
+	MaxSessions int
+
+	// Maximum CPU usage of the server process, in percent of all CPU cores.
+	MaxCPUPercent int
+
+	// Maximum traffic of download plus upload, in megabits per second.
+	MaxBandwidthMbps int
+}
+
+// overloadState holds the limits and the recent load of the server.
+// It is owned by the mux. Underlays and sessions read it through a
+// pointer, so the limits can be changed while the mux is running.
+type overloadState struct {
+	maxSessions   atomic.Int64
+	maxCPUPercent atomic.Int64
+	maxBandwidth  atomic.Int64 // bytes per second
+
+	cpuPercent atomic.Int64 // CPU usage in the last sample interval
+	bandwidth  atomic.Int64 // bytes per second in the last sample interval
+
+	// The following fields are only used by sample().
+	lastSample  time.Time
+	lastCPUTime time.Duration
+	lastBytes   int64
+}
+
+func (o *overloadState) setLimits(limits OverloadLimits) {
+	o.maxSessions.Store(int64(mathext.Max(limits.MaxSessions, 0)))
+	o.maxCPUPercent.Store(int64(mathext.Max(limits.MaxCPUPercent, 0)))
+	o.maxBandwidth.Store(int64(mathext.Max(limits.MaxBandwidthMbps, 0)) * 1000 * 1000 / 8)
+	if limits.MaxCPUPercent <= 0 {
+		o.cpuPercent.Store(0)
+	}
+	if limits.MaxBandwidthMbps <= 0 {
+		o.bandwidth.Store(0)
+	}
+}
+
+// sample measures the CPU usage and the bandwidth since the previous
+// sample. It must be called from a single goroutine. Nothing is measured
+// if the related limit is not set.
+func (o *overloadState) sample(now time.Time) {
+	measureCPU := o.maxCPUPercent.Load() > 0
+	measureBandwidth := o.maxBandwidth.Load() > 0
+	if !measureCPU && !measureBandwidth {
+		o.lastSample = time.Time{}
+		return
+	}
+	cpuTime, cpuErr := metrics.ProcessCPUTime()
+	bytes := metrics.DownloadBytes.Load() + metrics.UploadBytes.Load()
+	if !o.lastSample.IsZero() {
+		elapsed := now.Sub(o.lastSample)
+		if elapsed > 0 {
+			if measureCPU && cpuErr == nil {
+				available := elapsed * time.Duration(runtime.NumCPU())
+				o.cpuPercent.Store(int64((cpuTime - o.lastCPUTime) * 100 / available))
+			}
+			if measureBandwidth {
+				o.bandwidth.Store((bytes - o.lastBytes) * int64(time.Second) / int64(elapsed))
+			}
+		}
+	}
+	o.lastSample = now
+	o.lastCPUTime = cpuTime
+	o.lastBytes = bytes
+}
+
+// exceeded returns an error if any limit is reached.
+func (o *overloadState) exceeded() error {
+	if limit := o.maxSessions.Load(); limit > 0 {
+		// The current session is already counted.
+		if curr := metrics.CurrEstablished.Load(); curr > limit {
+			return fmt.Errorf("%d sessions exceed the limit %d", curr, limit)
+		}
+	}
+	if limit := o.maxCPUPercent.Load(); limit > 0 {
+		if curr := o.cpuPercent.Load(); curr >= limit {
+			return fmt.Errorf("CPU usage %d%% reaches the limit %d%%", curr, limit)
+		}
+	}
+	if limit := o.maxBandwidth.Load(); limit > 0 {
+		if curr := o.bandwidth.Load(); curr >= limit {
+			return fmt.Errorf("bandwidth %d bytes per second reaches the limit %d", curr, limit)
+		}
+	}
+	return nil
+}
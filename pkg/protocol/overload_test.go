@@ -0,0 +1,93 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+func TestOverloadMaxSessions(t *testing.T) {
+	var o overloadState
+	base := metrics.CurrEstablished.Load()
+	metrics.CurrEstablished.Add(2)
+	defer metrics.CurrEstablished.Add(-2)
+
+	o.setLimits(OverloadLimits{MaxSessions: int(base) + 1})
+	if err := o.exceeded(); err == nil {
+		t.Errorf("exceeded() = nil, want error")
+	}
+	o.setLimits(OverloadLimits{MaxSessions: int(base) + 2})
+	if err := o.exceeded(); err != nil {
+		t.Errorf("exceeded() = %v, want nil", err)
+	}
+}
+
+func TestOverloadBandwidth(t *testing.T) {
+	var o overloadState
+	o.setLimits(OverloadLimits{MaxBandwidthMbps: 8})
+	now := time.Now()
+	o.sample(now)
+	if err := o.exceeded(); err != nil {
+		t.Fatalf("exceeded() = %v before traffic", err)
+	}
+	metrics.DownloadBytes.Add(2 * 1000 * 1000)
+	o.sample(now.Add(time.Second))
+	if got := o.bandwidth.Load(); got < 2*1000*1000 {
+		t.Errorf("bandwidth = %d, want at least %d", got, 2*1000*1000)
+	}
+	if err := o.exceeded(); err == nil {
+		t.Errorf("exceeded() = nil, want error")
+	}
+
+	// Removing the limit clears the measured load.
+	o.setLimits(OverloadLimits{})
+	if err := o.exceeded(); err != nil {
+		t.Errorf("exceeded() = %v after the limit is removed", err)
+	}
+}
+
+func TestOverloadCPU(t *testing.T) {
+	var o overloadState
+	o.setLimits(OverloadLimits{MaxCPUPercent: 100})
+	now := time.Now()
+	o.sample(now)
+	o.sample(now.Add(time.Second))
+	if got := o.cpuPercent.Load(); got < 0 || got > 100 {
+		t.Errorf("CPU percent = %d, want between 0 and 100", got)
+	}
+	o.cpuPercent.Store(100)
+	if err := o.exceeded(); err == nil {
+		t.Errorf("exceeded() = nil, want error")
+	}
+}
+
+func TestSetServerOverloadLimitsOfRunningUnderlay(t *testing.T) {
+	mux := NewMux(false)
+	defer mux.Close()
+	underlay := mux.serverWrapTCPConn(nil, 1400, nil).(*StreamUnderlay)
+
+	mux.SetServerOverloadLimits(OverloadLimits{MaxSessions: 10})
+	if got := underlay.overload.maxSessions.Load(); got != 10 {
+		t.Errorf("max sessions of underlay = %d, want 10", got)
+	}
+	mux.SetServerOverloadLimits(OverloadLimits{})
+	if got := underlay.overload.maxSessions.Load(); got != 0 {
+		t.Errorf("max sessions of underlay = %d, want 0", got)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+const (
+	// minPathMTU is the packet size that is assumed to be deliverable
+	// on every path. Path MTU discovery starts the search from this size.
+	minPathMTU = 1280
+
+	// maxPathMTU is the largest packet size that can be received
+	// by the packet underlay.
+	maxPathMTU = 1500
+
+	// pathMTUProbeAttempts is the number of probes sent with the same size
+	// before the size is considered not deliverable.
+	pathMTUProbeAttempts = 3
+
+	// pathMTUSearchPrecision stops the search when the distance between
+	// the confirmed size and the upper bound is smaller than this value.
+	pathMTUSearchPrecision = 8
+
+	// pathMTUSearchInterval is the time to wait before searching
+	// a larger path MTU again.
+	pathMTUSearchInterval = 10 * time.Minute
+
+	// pathMTUBlackholeTxCount is the number of transmissions of a segment
+	// larger than minPathMTU, after which the path is considered
+	// to drop packets of the current size.
+	pathMTUBlackholeTxCount = 5
+
+	// pathMTUProbeHeaderLength is the number of bytes at the beginning of
+	// the probe payload. It contains the probe size and the path MTU
+	// confirmed by the client.
+	pathMTUProbeHeaderLength = 4
+)
+
+var (
+	PathMTUProbes          = metrics.RegisterMetric("path MTU discovery", "Probes", metrics.COUNTER)
+	PathMTUProbesConfirmed = metrics.RegisterMetric("path MTU discovery", "ProbesConfirmed", metrics.COUNTER)
+	PathMTUProbesLost      = metrics.RegisterMetric("path MTU discovery", "ProbesLost", metrics.COUNTER)
+	PathMTUBlackholes      = metrics.RegisterMetric("path MTU discovery", "Blackholes", metrics.COUNTER)
+)
+
+// pathMTUProber searches the largest packet size that can be delivered
+// in both directions of a path. A probe is an ACK segment with the
+// flagPathMTUProbe flag, padded with payload to the probe size.
+// The peer replies with a probe of the same size. The size is confirmed
+// when the reply is received.
+//
+// The search is a binary search between minPathMTU and the configured MTU.
+type pathMTUProber struct {
+	mu sync.Mutex
+
+	maxMTU int // upper bound of the search
+	low    int // largest confirmed size
+	high   int // largest size that may be deliverable
+
+	probing  int       // size of the probe waiting for reply, 0 if none
+	attempts int       // number of probes sent with the current size
+	sentTime time.Time // last time a probe is sent
+
+	searching     bool      // if the search is in progress
+	nextSearch    time.Time // time to start the next search
+	reportPending bool      // if the confirmed size is not reported to the peer
+}
+
+// newPathMTUProber creates a new pathMTUProber. maxMTU is the largest
+// packet size to search.
+func newPathMTUProber(maxMTU int) *pathMTUProber {
+	if maxMTU > maxPathMTU {
+		maxMTU = maxPathMTU
+	}
+	if maxMTU < minPathMTU {
+		maxMTU = minPathMTU
+	}
+	return &pathMTUProber{
+		maxMTU:    maxMTU,
+		low:       minPathMTU,
+		high:      maxMTU,
+		searching: true,
+	}
+}
+
+// mtu returns the largest confirmed packet size.
+func (p *pathMTUProber) mtu() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.low
+}
+
+// nextProbe returns the size of the probe to send now.
+// It returns 0 if no probe should be sent. timeout is the time to wait
+// for the reply of a probe.
+func (p *pathMTUProber) nextProbe(now time.Time, timeout time.Duration) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.probing != 0 {
+		if now.Sub(p.sentTime) < timeout {
+			return 0
+		}
+		if p.attempts < pathMTUProbeAttempts {
+			p.attempts++
+			p.sentTime = now
+			return p.probing
+		}
+		PathMTUProbesLost.Add(1)
+		p.high = p.probing - 1
+		p.probing = 0
+	}
+
+	if p.high-p.low < pathMTUSearchPrecision {
+		if p.searching {
+			p.searching = false
+			p.nextSearch = now.Add(pathMTUSearchInterval)
+			p.reportPending = true
+		}
+		if p.reportPending {
+			// The reply of this probe doesn't change the confirmed size.
+			p.reportPending = false
+			return p.low
+		}
+		if now.Before(p.nextSearch) {
+			return 0
+		}
+		p.high = p.maxMTU
+		p.searching = true
+		if p.high-p.low < pathMTUSearchPrecision {
+			p.nextSearch = now.Add(pathMTUSearchInterval)
+			p.searching = false
+			return 0
+		}
+	}
+
+	p.probing = (p.low + p.high + 1) / 2
+	p.attempts = 1
+	p.sentTime = now
+	return p.probing
+}
+
+// onProbeReply updates the search after receiving the reply of a probe.
+func (p *pathMTUProber) onProbeReply(size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.probing == 0 || size != p.probing {
+		return
+	}
+	PathMTUProbesConfirmed.Add(1)
+	p.low = size
+	p.probing = 0
+}
+
+// onBlackhole restarts the search from minPathMTU, because packets of
+// the confirmed size are no longer delivered.
+func (p *pathMTUProber) onBlackhole() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.low <= minPathMTU {
+		return
+	}
+	PathMTUBlackholes.Add(1)
+	p.high = p.low - 1
+	p.low = minPathMTU
+	p.probing = 0
+	p.searching = true
+}
+
+// newPathMTUProbePayload returns the payload of a probe with the given
+// size. confirmed is the path MTU confirmed by the sender, or 0 if unknown.
+func newPathMTUProbePayload(size, confirmed int) []byte {
+	b := make([]byte, size-packetOverhead)
+	binary.BigEndian.PutUint16(b, uint16(size))
+	binary.BigEndian.PutUint16(b[2:], uint16(confirmed))
+	return b
+}
+
+// parsePathMTUProbePayload returns the probe size and the confirmed
+// path MTU from the payload of a probe.
+func parsePathMTUProbePayload(b []byte) (size, confirmed int, ok bool) {
+	if len(b) < pathMTUProbeHeaderLength {
+		return 0, 0, false
+	}
+	size = int(binary.BigEndian.Uint16(b))
+	confirmed = int(binary.BigEndian.Uint16(b[2:]))
+	if size != len(b)+packetOverhead {
+		return 0, 0, false
+	}
+	return size, confirmed, true
+}
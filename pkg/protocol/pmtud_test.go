@@ -0,0 +1,119 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPathMTUProberSearch(t *testing.T) {
+	p := newPathMTUProber(1400)
+	if got := p.mtu(); got != minPathMTU {
+		t.Fatalf("mtu() = %d, want %d", got, minPathMTU)
+	}
+
+	// The path can deliver packets up to 1350 bytes.
+	now := time.Now()
+	timeout := time.Second
+	for i := 0; i < 100; i++ {
+		size := p.nextProbe(now, timeout)
+		if size == 0 {
+			now = now.Add(timeout)
+			continue
+		}
+		if size > 1400 {
+			t.Fatalf("probe size %d is larger than the upper bound", size)
+		}
+		if size <= 1350 {
+			p.onProbeReply(size)
+		}
+	}
+	if got := p.mtu(); got > 1350 || got <= 1350-pathMTUSearchPrecision {
+		t.Errorf("mtu() = %d, want a value in (%d, 1350]", got, 1350-pathMTUSearchPrecision)
+	}
+
+	// No more probe until the next search.
+	if size := p.nextProbe(now, timeout); size != 0 {
+		t.Errorf("nextProbe() = %d after search is finished, want 0", size)
+	}
+	if size := p.nextProbe(now.Add(pathMTUSearchInterval), timeout); size <= p.mtu() {
+		t.Errorf("nextProbe() = %d when next search starts, want a value larger than %d", size, p.mtu())
+	}
+}
+
+func TestPathMTUProberRetry(t *testing.T) {
+	p := newPathMTUProber(1400)
+	now := time.Now()
+	timeout := time.Second
+	first := p.nextProbe(now, timeout)
+	if first == 0 {
+		t.Fatalf("nextProbe() = 0, want a probe")
+	}
+	if size := p.nextProbe(now, timeout); size != 0 {
+		t.Errorf("nextProbe() = %d before timeout, want 0", size)
+	}
+	for i := 1; i < pathMTUProbeAttempts; i++ {
+		now = now.Add(timeout)
+		if size := p.nextProbe(now, timeout); size != first {
+			t.Errorf("nextProbe() = %d at retry %d, want %d", size, i, first)
+		}
+	}
+	now = now.Add(timeout)
+	if size := p.nextProbe(now, timeout); size >= first {
+		t.Errorf("nextProbe() = %d after probe is lost, want a value smaller than %d", size, first)
+	}
+
+	// Reply of a lost probe is ignored.
+	p.onProbeReply(first)
+	if got := p.mtu(); got != minPathMTU {
+		t.Errorf("mtu() = %d, want %d", got, minPathMTU)
+	}
+}
+
+func TestPathMTUProberBlackhole(t *testing.T) {
+	p := newPathMTUProber(1400)
+	now := time.Now()
+	size := p.nextProbe(now, time.Second)
+	p.onProbeReply(size)
+	if got := p.mtu(); got != size {
+		t.Fatalf("mtu() = %d, want %d", got, size)
+	}
+	p.onBlackhole()
+	if got := p.mtu(); got != minPathMTU {
+		t.Errorf("mtu() = %d after blackhole, want %d", got, minPathMTU)
+	}
+	if next := p.nextProbe(now, time.Second); next >= size {
+		t.Errorf("nextProbe() = %d after blackhole, want a value smaller than %d", next, size)
+	}
+}
+
+func TestPathMTUProbePayload(t *testing.T) {
+	b := newPathMTUProbePayload(1300, 1280)
+	if len(b)+packetOverhead != 1300 {
+		t.Fatalf("probe size is %d, want 1300", len(b)+packetOverhead)
+	}
+	size, confirmed, ok := parsePathMTUProbePayload(b)
+	if !ok || size != 1300 || confirmed != 1280 {
+		t.Errorf("parsePathMTUProbePayload() = %d, %d, %v, want 1300, 1280, true", size, confirmed, ok)
+	}
+	if _, _, ok := parsePathMTUProbePayload(b[:len(b)-1]); ok {
+		t.Errorf("parsePathMTUProbePayload() succeeded with truncated payload")
+	}
+	if _, _, ok := parsePathMTUProbePayload(b[:2]); ok {
+		t.Errorf("parsePathMTUProbePayload() succeeded with short payload")
+	}
+}
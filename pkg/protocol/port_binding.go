@@ -0,0 +1,136 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// portBindingMetricGroupFormat is the name format of the metric group
+	// shared by all the transport protocols of a port binding.
+	portBindingMetricGroupFormat = "port binding %s"
+
+	portBindingMetricUnderlays = "Underlays"
+	portBindingMetricSessions  = "Sessions"
+)
+
+// portBinding is a local address that the server listens to.
+// The TCP and UDP endpoints that share the same address are managed
+// as one logical binding. They are started and stopped together,
+// share the same metric group, and report a single status. If any of
+// them fails to listen, all the endpoints of the binding are stopped.
+type portBinding struct {
+	addr      string
+	endpoints []UnderlayProperties
+	listeners []*endpointListener
+
+	underlays metrics.Metric // number of underlays accepted from the binding
+	sessions  metrics.Metric // number of sessions accepted from the binding
+
+	mu  sync.Mutex
+	err error // the first error that stops the binding from listening
+}
+
+func newPortBinding(addr string, endpoints []UnderlayProperties) *portBinding {
+	group := fmt.Sprintf(portBindingMetricGroupFormat, addr)
+	return &portBinding{
+		addr:      addr,
+		endpoints: endpoints,
+		underlays: metrics.RegisterMetric(group, portBindingMetricUnderlays, metrics.COUNTER),
+		sessions:  metrics.RegisterMetric(group, portBindingMetricSessions, metrics.COUNTER),
+	}
+}
+
+// stop closes the listening sockets and all the underlays of the binding.
+func (b *portBinding) stop() {
+	for _, l := range b.listeners {
+		l.stop()
+	}
+}
+
+// fail records the error of an endpoint and stops the binding.
+// It returns false if the binding has failed before.
+func (b *portBinding) fail(err error) bool {
+	b.mu.Lock()
+	if b.err != nil {
+		b.mu.Unlock()
+		return false
+	}
+	b.err = err
+	b.mu.Unlock()
+	b.stop()
+	return true
+}
+
+// status returns the runtime status of the binding.
+func (b *portBinding) status() *appctlpb.PortBindingStatus {
+	s := &appctlpb.PortBindingStatus{
+		Address:   proto.String(b.addr),
+		Underlays: proto.Int64(b.underlays.Load()),
+		Sessions:  proto.Int64(b.sessions.Load()),
+	}
+	for _, p := range b.endpoints {
+		switch p.TransportProtocol() {
+		case common.StreamTransport:
+			s.Protocols = append(s.Protocols, appctlpb.TransportProtocol_TCP)
+		case common.PacketTransport:
+			s.Protocols = append(s.Protocols, appctlpb.TransportProtocol_UDP)
+		case common.WebSocketTransport:
+			s.Protocols = append(s.Protocols, appctlpb.TransportProtocol_WEBSOCKET)
+		}
+	}
+	b.mu.Lock()
+	err := b.err
+	b.mu.Unlock()
+	if err != nil {
+		s.State = appctlpb.PortBindingState_PORT_BINDING_FAILED.Enum()
+		s.Error = proto.String(err.Error())
+		return s
+	}
+	s.State = appctlpb.PortBindingState_PORT_BINDING_LISTENING.Enum()
+	for _, l := range b.listeners {
+		select {
+		case <-l.ready:
+		default:
+			s.State = appctlpb.PortBindingState_PORT_BINDING_STARTING.Enum()
+		}
+	}
+	return s
+}
+
+// groupPortBindings groups the endpoints by the local address.
+// The addresses are returned in sorted order.
+func groupPortBindings(endpoints []UnderlayProperties) ([]string, map[string][]UnderlayProperties) {
+	groups := make(map[string][]UnderlayProperties)
+	for _, p := range endpoints {
+		addr := p.LocalAddr().String()
+		groups[addr] = append(groups[addr], p)
+	}
+	addrs := make([]string, 0, len(groups))
+	for addr := range groups {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	return addrs, groups
+}
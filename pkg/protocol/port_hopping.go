@@ -0,0 +1,106 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+var (
+	// PortHoppingDials is the number of client underlays created on the
+	// active port of a port hopping range.
+	PortHoppingDials = metrics.RegisterMetric("port hopping", "Dials", metrics.COUNTER)
+
+	// PortHoppingRejected is the number of new sessions rejected by proxy
+	// server because they arrived at a port that is not active for the user.
+	PortHoppingRejected = metrics.RegisterMetric("port hopping", "Rejected", metrics.COUNTER)
+)
+
+// PortHoppingOptions are the options of UDP port hopping. The time is
+// divided into slots with the same length. In each slot, proxy client
+// creates new underlays to one port of the range, which is derived from
+// the user key and the slot number. Proxy server accepts new sessions of
+// the user on the ports of the current slot and the adjacent slots, so
+// proxy clients with a small clock difference are not broken. Existing
+// underlays keep working after the port hops.
+type PortHoppingOptions struct {
+	// BeginPort is the first port of the range.
+	BeginPort int
+
+	// EndPort is the last port of the range.
+	EndPort int
+
+	// Interval is the length of a slot.
+	Interval time.Duration
+}
+
+// Contains returns true if the port is in the range.
+func (o *PortHoppingOptions) Contains(port int) bool {
+	return port >= o.BeginPort && port <= o.EndPort
+}
+
+// Slot returns the slot number of the given time.
+func (o *PortHoppingOptions) Slot(t time.Time) int64 {
+	return t.UnixNano() / o.Interval.Nanoseconds()
+}
+
+// SlotEnd returns the time when the slot of the given time ends.
+func (o *PortHoppingOptions) SlotEnd(t time.Time) time.Time {
+	return time.Unix(0, (o.Slot(t)+1)*o.Interval.Nanoseconds())
+}
+
+// Port returns the active port of the user in the slot.
+// The password is the hashed password of the user.
+func (o *PortHoppingOptions) Port(password []byte, slot int64) int {
+	mac := hmac.New(sha256.New, password)
+	var b [24]byte
+	binary.BigEndian.PutUint64(b[0:], uint64(slot))
+	binary.BigEndian.PutUint64(b[8:], uint64(o.BeginPort))
+	binary.BigEndian.PutUint64(b[16:], uint64(o.EndPort))
+	mac.Write([]byte("mieru port hopping"))
+	mac.Write(b[:])
+	n := binary.BigEndian.Uint64(mac.Sum(nil))
+	return o.BeginPort + int(n%uint64(o.EndPort-o.BeginPort+1))
+}
+
+// accepts returns true if proxy server accepts a new session of the user
+// on the port at the given time.
+func (o *PortHoppingOptions) accepts(password []byte, port int, t time.Time) bool {
+	slot := o.Slot(t)
+	for _, s := range []int64{slot, slot - 1, slot + 1} {
+		if o.Port(password, s) == port {
+			return true
+		}
+	}
+	return false
+}
+
+// hopAddr returns the address to create a new client underlay at the
+// given time. The host of the address is not changed.
+func (o *PortHoppingOptions) hopAddr(addr string, password []byte, t time.Time) (string, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(o.Port(password, o.Slot(t)))), nil
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/cipher"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/testtool"
+)
+
+func TestPortHoppingPort(t *testing.T) {
+	opts := &PortHoppingOptions{BeginPort: 20000, EndPort: 20099, Interval: time.Minute}
+	password := cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))
+	other := cipher.HashPassword([]byte("kuiranbudong"), []byte("dahuangmao"))
+	ports := make(map[int]struct{})
+	different := false
+	for slot := int64(0); slot < 100; slot++ {
+		port := opts.Port(password, slot)
+		if !opts.Contains(port) {
+			t.Fatalf("Port() = %d, want in range [%d, %d]", port, opts.BeginPort, opts.EndPort)
+		}
+		if opts.Port(password, slot) != port {
+			t.Fatalf("Port() is not stable")
+		}
+		if opts.Port(other, slot) != port {
+			different = true
+		}
+		ports[port] = struct{}{}
+	}
+	if len(ports) < 10 {
+		t.Errorf("got %d different ports in 100 slots, want at least 10", len(ports))
+	}
+	if !different {
+		t.Errorf("two users have the same schedule")
+	}
+}
+
+func TestPortHoppingAccepts(t *testing.T) {
+	opts := &PortHoppingOptions{BeginPort: 20000, EndPort: 20999, Interval: time.Minute}
+	password := cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))
+	now := time.Now()
+	slot := opts.Slot(now)
+	for _, s := range []int64{slot - 1, slot, slot + 1} {
+		if port := opts.Port(password, s); !opts.accepts(password, port, now) {
+			t.Errorf("port %d of slot %d is not accepted in slot %d", port, s, slot)
+		}
+	}
+	if port := opts.Port(password, slot+2); port != opts.Port(password, slot) && port != opts.Port(password, slot+1) && port != opts.Port(password, slot-1) && opts.accepts(password, port, now) {
+		t.Errorf("port %d of slot %d is accepted in slot %d", port, slot+2, slot)
+	}
+	if end := opts.SlotEnd(now); opts.Slot(end) != slot+1 || !end.After(now) {
+		t.Errorf("SlotEnd() = %v, want the start of the next slot", end)
+	}
+}
+
+// unusedUDPPortRange returns n consecutive UDP ports that are not used.
+func unusedUDPPortRange(t *testing.T, n int) int {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		begin, err := common.UnusedUDPPort()
+		if err != nil {
+			t.Fatalf("common.UnusedUDPPort() failed: %v", err)
+		}
+		if begin+n-1 > 65535 {
+			continue
+		}
+		ok := true
+		for port := begin; port < begin+n; port++ {
+			conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+			if err != nil {
+				ok = false
+				break
+			}
+			conn.Close()
+		}
+		if ok {
+			return begin
+		}
+	}
+	t.Fatalf("unable to find %d consecutive unused UDP ports", n)
+	return 0
+}
+
+func TestUDPUnderlayPortHopping(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	begin := unusedUDPPortRange(t, 4)
+	opts := &PortHoppingOptions{BeginPort: begin, EndPort: begin + 3, Interval: time.Minute}
+	var serverEndpoints []UnderlayProperties
+	for port := begin; port <= begin+3; port++ {
+		p := NewUnderlayProperties(1400, common.PacketTransport, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil)
+		serverEndpoints = append(serverEndpoints, WithPortHopping(p, opts))
+	}
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints(serverEndpoints)
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	if err := serverMux.WaitListening(context.Background()); err != nil {
+		t.Fatalf("WaitListening() failed: %v", err)
+	}
+	go testServer.Serve(serverMux)
+	defer testServer.Close()
+
+	// The client hops to the active port.
+	password := cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))
+	clientProperties := WithPortHopping(NewUnderlayProperties(1400, common.PacketTransport, nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: begin}), opts)
+	dials := PortHoppingDials.Load()
+	runClient(t, clientProperties, []byte("xiaochitang"), []byte("kuiranbudong"), 2)
+	if PortHoppingDials.Load() == dials {
+		t.Errorf("PortHoppingDials is not increased")
+	}
+
+	// The server rejects new sessions on an inactive port.
+	inactive := 0
+	for port := begin; port <= begin+3; port++ {
+		if !opts.accepts(password, port, time.Now()) {
+			inactive = port
+			break
+		}
+	}
+	if inactive == 0 {
+		t.Fatalf("all ports are active")
+	}
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", password).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.PacketTransport, nil, &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: inactive}),
+		})
+	defer clientMux.Close()
+	rejected := PortHoppingRejected.Load()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := clientMux.DialContext(ctx)
+	if err == nil {
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		conn.Write([]byte("hello"))
+		if _, err := io.ReadFull(conn, make([]byte, 5)); err == nil {
+			t.Errorf("round trip on inactive port succeeded, want error")
+		}
+	}
+	if PortHoppingRejected.Load() == rejected {
+		t.Errorf("PortHoppingRejected is not increased")
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+const (
+	// proxyProtocolHeaderTimeout is the maximum time to receive
+	// the PROXY protocol header from a TCP connection.
+	proxyProtocolHeaderTimeout = 10 * time.Second
+
+	// proxyProtocolV1MaxLength is the maximum length of a v1 header,
+	// including the trailing CRLF.
+	proxyProtocolV1MaxLength = 107
+)
+
+var (
+	// proxyProtocolV1Prefix is the beginning of a v1 header.
+	proxyProtocolV1Prefix = []byte("PROXY ")
+
+	// proxyProtocolV2Signature is the beginning of a v2 header.
+	proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+var (
+	// ProxyProtocolHeaders is the number of TCP connections with
+	// a valid PROXY protocol header.
+	ProxyProtocolHeaders = metrics.RegisterMetric("PROXY protocol", "Headers", metrics.COUNTER)
+
+	// ProxyProtocolRejected is the number of TCP connections closed
+	// because the PROXY protocol header is invalid or missing.
+	ProxyProtocolRejected = metrics.RegisterMetric("PROXY protocol", "Rejected", metrics.COUNTER)
+)
+
+// ProxyProtocolOptions are the options to accept the PROXY protocol
+// headers sent by a TCP load balancer in front of the proxy server.
+// The source address in the header is used as the remote address
+// of the connection.
+type ProxyProtocolOptions struct {
+	// Require closes the connections without a header.
+	Require bool
+
+	// TrustedSources are the addresses of the load balancers.
+	// Headers from other addresses are not accepted.
+	// If it is empty, headers from any address are accepted.
+	TrustedSources []*net.IPNet
+}
+
+// trusted returns true if the PROXY protocol header from the address
+// should be accepted.
+func (o *ProxyProtocolOptions) trusted(addr net.Addr) bool {
+	if len(o.TrustedSources) == 0 {
+		return true
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, ipNet := range o.TrustedSources {
+		if ipNet.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyProtocolListener accepts TCP connections from a TCP listener,
+// and removes the PROXY protocol header of the connections.
+type proxyProtocolListener struct {
+	rawListener net.Listener
+	opts        *ProxyProtocolOptions
+	conns       chan net.Conn
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var _ net.Listener = &proxyProtocolListener{}
+
+func newProxyProtocolListener(rawListener net.Listener, opts *ProxyProtocolOptions) *proxyProtocolListener {
+	l := &proxyProtocolListener{
+		rawListener: rawListener,
+		opts:        opts,
+		conns:       make(chan net.Conn),
+		done:        make(chan struct{}),
+	}
+	go l.acceptLoop()
+	return l
+}
+
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, io.ErrClosedPipe
+	}
+}
+
+func (l *proxyProtocolListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.done)
+		err = l.rawListener.Close()
+	})
+	return err
+}
+
+func (l *proxyProtocolListener) Addr() net.Addr {
+	return l.rawListener.Addr()
+}
+
+func (l *proxyProtocolListener) acceptLoop() {
+	defer l.Close()
+	for {
+		conn, err := l.rawListener.Accept()
+		if err != nil {
+			return
+		}
+		go l.handleConn(conn)
+	}
+}
+
+func (l *proxyProtocolListener) handleConn(conn net.Conn) {
+	if !l.opts.trusted(conn.RemoteAddr()) {
+		if l.opts.Require {
+			log.Debugf("Closing connection from %v that is not a trusted PROXY protocol source", conn.RemoteAddr())
+			ProxyProtocolRejected.Add(1)
+			conn.Close()
+			return
+		}
+		l.deliver(conn)
+		return
+	}
+
+	conn.SetReadDeadline(time.Now().Add(proxyProtocolHeaderTimeout))
+	r := bufio.NewReader(conn)
+	src, found, err := readProxyProtocolHeader(r)
+	conn.SetReadDeadline(time.Time{})
+	if err == nil && !found && l.opts.Require {
+		err = fmt.Errorf("PROXY protocol header is not found")
+	}
+	if err != nil {
+		log.Debugf("Closing connection from %v: %v", conn.RemoteAddr(), err)
+		ProxyProtocolRejected.Add(1)
+		conn.Close()
+		return
+	}
+	buffered, _ := r.Peek(r.Buffered())
+	c := &proxyProtocolConn{prefixConn: prefixConn{Conn: conn, prefix: buffered}}
+	if found {
+		ProxyProtocolHeaders.Add(1)
+		c.remoteAddr = src
+	}
+	l.deliver(c)
+}
+
+func (l *proxyProtocolListener) deliver(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		conn.Close()
+	}
+}
+
+// proxyProtocolConn is a connection whose PROXY protocol header is removed.
+type proxyProtocolConn struct {
+	prefixConn
+	remoteAddr net.Addr // nil if the header is not found or the source is unknown
+}
+
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// readProxyProtocolHeader reads a PROXY protocol v1 or v2 header.
+// It returns false if the connection doesn't start with a header, and
+// nothing is consumed from the reader. The returned source address is nil
+// if the header doesn't carry the address of a TCP client, for example
+// a health check from the load balancer itself.
+func readProxyProtocolHeader(r *bufio.Reader) (net.Addr, bool, error) {
+	b, err := r.Peek(len(proxyProtocolV1Prefix))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read PROXY protocol header: %w", err)
+	}
+	if bytes.Equal(b, proxyProtocolV1Prefix) {
+		src, err := readProxyProtocolV1Header(r)
+		return src, true, err
+	}
+	if b[0] != proxyProtocolV2Signature[0] {
+		return nil, false, nil
+	}
+	b, err = r.Peek(len(proxyProtocolV2Signature))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read PROXY protocol header: %w", err)
+	}
+	if bytes.Equal(b, proxyProtocolV2Signature) {
+		src, err := readProxyProtocolV2Header(r)
+		return src, true, err
+	}
+	return nil, false, nil
+}
+
+// readProxyProtocolV1Header reads a header like
+// "PROXY TCP4 192.0.2.1 198.51.100.1 56324 443\r\n".
+func readProxyProtocolV1Header(r *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) >= proxyProtocolV1MaxLength {
+			return nil, fmt.Errorf("PROXY protocol v1 header is too long")
+		}
+		c, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PROXY protocol v1 header: %w", err)
+		}
+		line = append(line, c)
+	}
+	fields := strings.Split(strings.TrimSuffix(string(line), "\r\n"), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("PROXY protocol v1 header %q is invalid", line)
+	}
+	ip := net.ParseIP(fields[2])
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if ip == nil || err != nil || (fields[1] == "TCP4") != (ip.To4() != nil) {
+		return nil, fmt.Errorf("PROXY protocol v1 header %q has invalid source address", line)
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readProxyProtocolV2Header reads a binary header.
+func readProxyProtocolV2Header(r *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read PROXY protocol v2 header: %w", err)
+	}
+	verCmd, family := header[12], header[13]
+	addrs := make([]byte, binary.BigEndian.Uint16(header[14:]))
+	if _, err := io.ReadFull(r, addrs); err != nil {
+		return nil, fmt.Errorf("failed to read PROXY protocol v2 addresses: %w", err)
+	}
+	if verCmd>>4 != 2 {
+		return nil, fmt.Errorf("PROXY protocol version %d is not supported", verCmd>>4)
+	}
+	switch verCmd & 0xf {
+	case 0: // LOCAL
+		return nil, nil
+	case 1: // PROXY
+	default:
+		return nil, fmt.Errorf("PROXY protocol v2 command %d is not supported", verCmd&0xf)
+	}
+	switch family {
+	case 0x11: // TCP over IPv4
+		if len(addrs) < 12 {
+			return nil, fmt.Errorf("PROXY protocol v2 IPv4 addresses are too short")
+		}
+		return &net.TCPAddr{IP: net.IP(addrs[0:4]), Port: int(binary.BigEndian.Uint16(addrs[8:]))}, nil
+	case 0x21: // TCP over IPv6
+		if len(addrs) < 36 {
+			return nil, fmt.Errorf("PROXY protocol v2 IPv6 addresses are too short")
+		}
+		return &net.TCPAddr{IP: net.IP(addrs[0:16]), Port: int(binary.BigEndian.Uint16(addrs[32:]))}, nil
+	default:
+		// Other address families don't have a TCP source address.
+		return nil, nil
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadProxyProtocolHeader(t *testing.T) {
+	v2IPv4 := append([]byte{}, proxyProtocolV2Signature...)
+	v2IPv4 = append(v2IPv4, 0x21, 0x11, 0, 12, 192, 0, 2, 1, 198, 51, 100, 1, 0xdc, 0x04, 0x01, 0xbb)
+	v2Local := append([]byte{}, proxyProtocolV2Signature...)
+	v2Local = append(v2Local, 0x20, 0x00, 0, 0)
+	testCases := []struct {
+		name      string
+		input     []byte
+		wantFound bool
+		wantSrc   string
+		wantErr   bool
+	}{
+		{"v1 TCP4", []byte("PROXY TCP4 192.0.2.1 198.51.100.1 56324 443\r\n"), true, "192.0.2.1:56324", false},
+		{"v1 TCP6", []byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\n"), true, "[2001:db8::1]:56324", false},
+		{"v1 UNKNOWN", []byte("PROXY UNKNOWN\r\n"), true, "", false},
+		{"v1 invalid", []byte("PROXY TCP4 2001:db8::1 198.51.100.1 56324 443\r\n"), true, "", true},
+		{"v1 too long", []byte("PROXY " + strings.Repeat("A", 200)), true, "", true},
+		{"v2 IPv4", v2IPv4, true, "192.0.2.1:56324", false},
+		{"v2 LOCAL", v2Local, true, "", false},
+		{"no header", []byte("\r\n\r\n\x00\r\nXXXX\n"), false, "", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := bufio.NewReader(bytes.NewReader(append(tc.input, []byte("payload")...)))
+			src, found, err := readProxyProtocolHeader(r)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("readProxyProtocolHeader() error = %v, want error %v", err, tc.wantErr)
+			}
+			if found != tc.wantFound {
+				t.Errorf("found = %v, want %v", found, tc.wantFound)
+			}
+			if tc.wantErr {
+				return
+			}
+			gotSrc := ""
+			if src != nil {
+				gotSrc = src.String()
+			}
+			if gotSrc != tc.wantSrc {
+				t.Errorf("source = %q, want %q", gotSrc, tc.wantSrc)
+			}
+			rest, _ := io.ReadAll(r)
+			wantRest := "payload"
+			if !found {
+				wantRest = string(tc.input) + "payload"
+			}
+			if string(rest) != wantRest {
+				t.Errorf("remaining data = %q, want %q", rest, wantRest)
+			}
+		})
+	}
+}
+
+func TestProxyProtocolListener(t *testing.T) {
+	rawListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	l := newProxyProtocolListener(rawListener, &ProxyProtocolOptions{Require: true})
+	defer l.Close()
+
+	// The connection without a header is closed.
+	before := ProxyProtocolRejected.Load()
+	noHeader, err := net.Dial("tcp", rawListener.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	defer noHeader.Close()
+	noHeader.Write([]byte("hello world"))
+	noHeader.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := noHeader.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Read() from connection without header got %v, want %v", err, io.EOF)
+	}
+	if ProxyProtocolRejected.Load() != before+1 {
+		t.Errorf("ProxyProtocolRejected = %d, want %d", ProxyProtocolRejected.Load(), before+1)
+	}
+
+	// The connection with a header uses the source address in the header.
+	conn, err := net.Dial("tcp", rawListener.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("PROXY TCP4 192.0.2.1 198.51.100.1 56324 443\r\nhello"))
+	accepted, err := l.Accept()
+	if err != nil {
+		t.Fatalf("Accept() failed: %v", err)
+	}
+	defer accepted.Close()
+	if accepted.RemoteAddr().String() != "192.0.2.1:56324" {
+		t.Errorf("RemoteAddr() = %v, want 192.0.2.1:56324", accepted.RemoteAddr())
+	}
+	b := make([]byte, 5)
+	if _, err := io.ReadFull(accepted, b); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("Read() = %q, want %q", b, "hello")
+	}
+}
+
+func TestProxyProtocolOptionsTrusted(t *testing.T) {
+	_, ipNet, _ := net.ParseCIDR("10.0.0.0/8")
+	opts := &ProxyProtocolOptions{TrustedSources: []*net.IPNet{ipNet}}
+	if !opts.trusted(&net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 1000}) {
+		t.Errorf("address in trusted sources is not trusted")
+	}
+	if opts.trusted(&net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1000}) {
+		t.Errorf("address not in trusted sources is trusted")
+	}
+	if !(&ProxyProtocolOptions{}).trusted(&net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1000}) {
+		t.Errorf("address is not trusted when trusted sources are empty")
+	}
+}
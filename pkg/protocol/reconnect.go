@@ -0,0 +1,75 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	mrand "math/rand"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+var (
+	// ReconnectRetries is the number of times the client retries to
+	// establish the proxy tunnel for a pending request.
+	ReconnectRetries = metrics.RegisterMetric("reconnect", "Retries", metrics.COUNTER)
+
+	// ReconnectRecovered is the number of pending requests that got the
+	// proxy tunnel after at least one retry.
+	ReconnectRecovered = metrics.RegisterMetric("reconnect", "Recovered", metrics.COUNTER)
+
+	// ReconnectGaveUp is the number of pending requests that failed
+	// because the proxy tunnel was not established within the maximum
+	// wait time.
+	ReconnectGaveUp = metrics.RegisterMetric("reconnect", "GaveUp", metrics.COUNTER)
+)
+
+// ReconnectPolicy controls how the client retries to establish the proxy
+// tunnel when it is broken. The delay before a retry starts from the
+// initial delay, doubles after each consecutive failure until it reaches
+// the maximum delay, and is randomized between half of it and itself.
+// The zero value disables retries.
+type ReconnectPolicy struct {
+	// Delay before the first retry.
+	InitialDelay time.Duration
+
+	// Maximum delay before a retry.
+	MaxDelay time.Duration
+
+	// Maximum time a request waits for the proxy tunnel.
+	// The request fails if the next retry would start after it.
+	MaxWait time.Duration
+}
+
+// Enabled returns true if the client retries to establish the proxy tunnel.
+func (p ReconnectPolicy) Enabled() bool {
+	return p.InitialDelay > 0 && p.MaxWait > 0
+}
+
+// delay returns the randomized delay before the next retry, after the
+// given number of consecutive failures.
+func (p ReconnectPolicy) delay(failures int) time.Duration {
+	d := p.InitialDelay
+	for i := 1; i < failures && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	// Spread the retries of pending requests, so they don't reach the
+	// proxy server at the same time.
+	return d/2 + time.Duration(mrand.Int63n(int64(d/2)+1))
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/cipher"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/testtool"
+)
+
+func TestReconnectPolicyDelay(t *testing.T) {
+	p := ReconnectPolicy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, MaxWait: time.Minute}
+	testCases := []struct {
+		failures int
+		max      time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second},
+		{100, time.Second},
+	}
+	for _, tc := range testCases {
+		for i := 0; i < 10; i++ {
+			d := p.delay(tc.failures)
+			if d < tc.max/2 || d > tc.max {
+				t.Errorf("delay(%d) = %v, want in range [%v, %v]", tc.failures, d, tc.max/2, tc.max)
+			}
+		}
+	}
+	if (ReconnectPolicy{}).Enabled() {
+		t.Errorf("zero ReconnectPolicy is enabled")
+	}
+}
+
+func TestMuxReconnect(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetClientReconnect(ReconnectPolicy{InitialDelay: 100 * time.Millisecond, MaxDelay: 200 * time.Millisecond, MaxWait: 10 * time.Second}).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}),
+		})
+	defer clientMux.Close()
+
+	// The proxy server starts after the client begins to dial.
+	retries := ReconnectRetries.Load()
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		conn, err := clientMux.DialContext(ctx)
+		results <- result{conn: conn, err: err}
+	}()
+	time.Sleep(500 * time.Millisecond)
+
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil),
+		})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	go testServer.Serve(serverMux)
+	defer testServer.Close()
+
+	r := <-results
+	if r.err != nil {
+		t.Fatalf("DialContext() failed: %v", r.err)
+	}
+	defer r.conn.Close()
+	if ReconnectRetries.Load() == retries {
+		t.Errorf("DialContext() succeeded without retry")
+	}
+	if _, err := r.conn.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	buf := make([]byte, 64)
+	if _, err := r.conn.Read(buf); err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+}
+
+func TestMuxReconnectGiveUp(t *testing.T) {
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	clientMux := NewMux(true).
+		SetClientUserNamePassword("xiaochitang", cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang"))).
+		SetClientReconnect(ReconnectPolicy{InitialDelay: 100 * time.Millisecond, MaxDelay: 100 * time.Millisecond, MaxWait: 500 * time.Millisecond}).
+		SetEndpoints([]UnderlayProperties{
+			NewUnderlayProperties(1400, common.StreamTransport, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}),
+		})
+	defer clientMux.Close()
+	gaveUp := ReconnectGaveUp.Load()
+	begin := time.Now()
+	if _, err := clientMux.DialContext(context.Background()); err == nil {
+		t.Fatalf("DialContext() succeeded without proxy server")
+	}
+	if d := time.Since(begin); d > 2*time.Second {
+		t.Errorf("DialContext() failed after %v, want less than 2s", d)
+	}
+	if ReconnectGaveUp.Load() == gaveUp {
+		t.Errorf("ReconnectGaveUp is not increased")
+	}
+}
@@ -88,13 +88,27 @@ func (c *ScheduleController) TryDisable() (successful bool) {
 	return true
 }
 
+// Disable immediately disables scheduling new sessions.
+func (c *ScheduleController) Disable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.disableTime.IsZero() && time.Since(c.disableTime) > 0 {
+		return // already disabled
+	}
+	c.disableTime = time.Now()
+}
+
 // SetRemainingTime disables the scheduler after the given duration.
-// Do nothing if the scheduler has already been disabled.
+// If the scheduler is already set to be disabled earlier, do nothing.
 func (c *ScheduleController) SetRemainingTime(d time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if d <= 0 || !c.disableTime.IsZero() {
+	if d <= 0 {
+		return
+	}
+	t := time.Now().Add(d)
+	if !c.disableTime.IsZero() && !t.Before(c.disableTime) {
 		return
 	}
-	c.disableTime = time.Now().Add(d)
+	c.disableTime = t
 }
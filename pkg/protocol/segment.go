@@ -102,6 +102,28 @@ func (s *segment) Fragment() uint8 {
 	return das.fragment
 }
 
+// Flags returns the flags of the segment.
+func (s *segment) Flags() uint8 {
+	switch m := s.metadata.(type) {
+	case *sessionStruct:
+		return m.flags
+	case *dataAckStruct:
+		return m.flags
+	default:
+		return 0
+	}
+}
+
+// setFlags sets the flags of the segment.
+func (s *segment) setFlags(flags uint8) {
+	switch m := s.metadata.(type) {
+	case *sessionStruct:
+		m.flags = flags
+	case *dataAckStruct:
+		m.flags = flags
+	}
+}
+
 // Less tests whether the current item is less than the given argument.
 func (s *segment) Less(other *segment) bool {
 	mySeq, err := s.Seq()
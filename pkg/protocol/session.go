@@ -52,6 +52,18 @@ const (
 	maxRetransmissionBatchSize = 16   // maximum number of segments in a retransmission batch
 	txTimeoutBackOff           = 1.25 // tx timeout back off multiplier
 	maxBackOffMultiplier       = 20.0 // maximum back off multiplier
+
+	maxSessionPaths = 8 // maximum number of previous remote addresses of a UDP session
+)
+
+var (
+	// PacketSegmentsSent is the number of segments sent for the first time
+	// by sessions using packet transport.
+	PacketSegmentsSent = metrics.RegisterMetric("packet session", "SegmentsSent", metrics.COUNTER)
+
+	// PacketSegmentsRetransmitted is the number of segments retransmitted
+	// by sessions using packet transport.
+	PacketSegmentsRetransmitted = metrics.RegisterMetric("packet session", "SegmentsRetransmitted", metrics.COUNTER)
 )
 
 type sessionState byte
@@ -86,11 +98,20 @@ type Session struct {
 	isClient   bool         // if this session is owned by client
 	mtu        int          // L2 maxinum transmission unit
 	remoteAddr net.Addr     // specify remote network address, used by UDP
+	paths      []string     // previous remote addresses, used by UDP server
+	addrLock   sync.Mutex   // protect remoteAddr and paths
 	state      sessionState // session state
 	status     statusCode   // session status
 
-	users    map[string]*appctlpb.User // all registered users, only used by server
-	userName string                    // user that owns this session, only used by server
+	users      map[string]*appctlpb.User      // all registered users, only used by server
+	userName   string                         // user that owns this session, only used by server
+	userGroups map[string]*appctlpb.UserGroup // user name -> user group, only used by server
+
+	overload     *overloadState // limits and recent load, only used by server
+	onRetryLater func()         // invoked when server requests to retry later, only used by client
+
+	onMaintenance      func()      // invoked when server announces maintenance, only used by client
+	maintenanceNoticed atomic.Bool // server maintenance announcement has been received, only used by client
 
 	ready          chan struct{} // indicate the session is ready to use
 	closeRequested atomic.Bool   // the session is being closed or has been closed
@@ -116,8 +137,19 @@ type Session struct {
 	ackOnDataRecv atomic.Bool // whether ack should be sent due to receive of new data
 	unreadBuf     []byte      // payload removed from the recvQueue that haven't been read by application
 
-	uploadBytes   metrics.Metric // number of bytes from client to server, only used by server
-	downloadBytes metrics.Metric // number of bytes from server to client, only used by server
+	keepalive       atomic.Int64 // interval to send keepalives on idle session, 0 if disabled
+	heartbeat       atomic.Int64 // interval to send heartbeats over packet transport, 0 to use the default
+	deadPeerTimeout atomic.Int64 // time without receiving from the peer to close the session, 0 to use the default
+
+	startTime    time.Time              // time when the session is created
+	readBytes    atomic.Int64           // number of bytes read by application
+	writtenBytes atomic.Int64           // number of bytes written by application
+	destination  atomic.Pointer[string] // destination of the proxy request, only used by server
+
+	uploadBytes        metrics.Metric // number of bytes from client to server, only used by server
+	downloadBytes      metrics.Metric // number of bytes from server to client, only used by server
+	groupUploadBytes   metrics.Metric // number of bytes from client to server of the user group, only used by server
+	groupDownloadBytes metrics.Metric // number of bytes from server to client of the user group, only used by server
 
 	rttStat             *congestion.RTTStats
 	legacysendAlgorithm *congestion.CubicSendAlgorithm
@@ -136,6 +168,12 @@ var (
 
 	// Session implements common.UserContext interface.
 	_ common.UserContext = (*Session)(nil)
+
+	// Session implements common.SessionContext interface.
+	_ common.SessionContext = (*Session)(nil)
+
+	// Session implements common.DestinationContext interface.
+	_ common.DestinationContext = (*Session)(nil)
 )
 
 // NewSession creates a new session.
@@ -165,6 +203,7 @@ func NewSession(id uint32, isClient bool, mtu int, users map[string]*appctlpb.Us
 		recvChan:            make(chan *segment, segmentChanCapacity),
 		lastRXTime:          time.Now(),
 		lastTXTime:          time.Now(),
+		startTime:           time.Now(),
 		rttStat:             rttStat,
 		legacysendAlgorithm: congestion.NewCubicSendAlgorithm(minWindowSize, maxWindowSize),
 		sendAlgorithm:       congestion.NewBBRSender(fmt.Sprintf("%d", id), rttStat),
@@ -205,6 +244,10 @@ func (s *Session) Read(b []byte) (n int, err error) {
 		if !s.isClient && s.uploadBytes != nil {
 			s.uploadBytes.Add(int64(n))
 		}
+		if !s.isClient && s.groupUploadBytes != nil {
+			s.groupUploadBytes.Add(int64(n))
+		}
+		s.readBytes.Add(int64(n))
 		return n, nil
 	}
 
@@ -260,6 +303,10 @@ func (s *Session) Read(b []byte) (n int, err error) {
 	if !s.isClient && s.uploadBytes != nil {
 		s.uploadBytes.Add(int64(n))
 	}
+	if !s.isClient && s.groupUploadBytes != nil {
+		s.groupUploadBytes.Add(int64(n))
+	}
+	s.readBytes.Add(int64(n))
 	return n, nil
 }
 
@@ -289,6 +336,7 @@ func (s *Session) Write(b []byte) (n int, err error) {
 				},
 				sessionID: s.id,
 				seq:       s.nextSend,
+				mtu:       uint16(s.pathMTU()),
 			},
 			transport: s.conn.TransportProtocol(),
 		}
@@ -308,6 +356,7 @@ func (s *Session) Write(b []byte) (n int, err error) {
 			s.oLock.Unlock()
 		}
 		if len(seg.payload) > 0 {
+			s.writtenBytes.Add(int64(len(seg.payload)))
 			return len(seg.payload), nil
 		}
 	}
@@ -329,6 +378,10 @@ func (s *Session) Write(b []byte) (n int, err error) {
 	if !s.isClient && s.downloadBytes != nil {
 		s.downloadBytes.Add(int64(n))
 	}
+	if !s.isClient && s.groupDownloadBytes != nil {
+		s.groupDownloadBytes.Add(int64(n))
+	}
+	s.writtenBytes.Add(int64(n))
 	return n, nil
 }
 
@@ -342,8 +395,11 @@ func (s *Session) LocalAddr() net.Addr {
 }
 
 func (s *Session) RemoteAddr() net.Addr {
-	if !common.IsNilNetAddr(s.remoteAddr) {
-		return s.remoteAddr
+	s.addrLock.Lock()
+	remoteAddr := s.remoteAddr
+	s.addrLock.Unlock()
+	if !common.IsNilNetAddr(remoteAddr) {
+		return remoteAddr
 	}
 	return s.conn.RemoteAddr()
 }
@@ -373,6 +429,17 @@ func (s *Session) UserName() string {
 	return s.userName
 }
 
+// SetDestination records the destination of the proxy request
+// in this session.
+func (s *Session) SetDestination(destination string) {
+	s.destination.Store(&destination)
+}
+
+// SessionID returns the ID of this session.
+func (s *Session) SessionID() uint32 {
+	return s.id
+}
+
 // ToSessionInfo creates related SessionInfo protobuf object.
 func (s *Session) ToSessionInfo() *appctlpb.SessionInfo {
 	info := &appctlpb.SessionInfo{
@@ -384,6 +451,15 @@ func (s *Session) ToSessionInfo() *appctlpb.SessionInfo {
 		RecvBuf:    proto.Uint32(uint32(s.recvBuf.Len())),
 		SendQ:      proto.Uint32(uint32(s.sendQueue.Len())),
 		SendBuf:    proto.Uint32(uint32(s.sendBuf.Len())),
+		StartTime:  timestamppb.New(s.startTime),
+		RecvBytes:  proto.Int64(s.readBytes.Load()),
+		SendBytes:  proto.Int64(s.writtenBytes.Load()),
+	}
+	if s.userName != "" {
+		info.User = proto.String(s.userName)
+	}
+	if destination := s.destination.Load(); destination != nil {
+		info.Destination = proto.String(*destination)
 	}
 	if s.conn != nil {
 		info.LocalAddr = proto.String(s.conn.LocalAddr().String())
@@ -467,7 +543,7 @@ func (s *Session) writeChunk(b []byte) (n int, err error) {
 
 	// Determine number of fragments to write.
 	nFragment := 1
-	fragmentSize := MaxFragmentSize(s.mtu, s.conn.TransportProtocol())
+	fragmentSize := MaxFragmentSize(s.pathMTU(), s.conn.TransportProtocol())
 	if len(b) > fragmentSize {
 		nFragment = (len(b)-1)/fragmentSize + 1
 	}
@@ -627,7 +703,13 @@ func (s *Session) runOutputOnceStream() {
 			}
 			s.oLock.Unlock() // s.oLock can be acquired by s.closeWithError().
 			s.closeWithError(err)
-			break
+			return
+		}
+	}
+
+	if s.keepaliveDue() {
+		if err := s.outputKeepalive(); err != nil {
+			log.Debugf("%v outputKeepalive() failed: %v", s, err)
 		}
 	}
 }
@@ -671,6 +753,9 @@ func (s *Session) runOutputOncePacket() {
 			}
 			iter.ackCount = 0
 			iter.txCount++
+			if iter.txCount == pathMTUBlackholeTxCount && packetOverhead+len(iter.payload) > minPathMTU {
+				s.onPathMTUBlackhole()
+			}
 			iter.txTime = time.Now()
 			iter.txTimeout = s.rttStat.RTO() * time.Duration(mathext.Min(math.Pow(txTimeoutBackOff, float64(iter.txCount)), maxBackOffMultiplier))
 			if isDataAckProtocol(iter.metadata.Protocol()) {
@@ -688,6 +773,7 @@ func (s *Session) runOutputOncePacket() {
 			}
 			bytesInFlight += int64(packetOverhead + len(iter.payload))
 			retransmissionCount++
+			PacketSegmentsRetransmitted.Add(1)
 			return true
 		}
 		return true
@@ -719,6 +805,7 @@ func (s *Session) runOutputOncePacket() {
 				das, _ := toDataAckStruct(seg.metadata)
 				das.unAckSeq = s.nextRecv
 			}
+			PacketSegmentsSent.Add(1)
 			if !s.sendBuf.Insert(seg) {
 				s.oLock.Unlock()
 				err := fmt.Errorf("output() failed: insert %v to send buffer failed", seg)
@@ -760,7 +847,11 @@ func (s *Session) runOutputOncePacket() {
 	}
 
 	// Send ACK or heartbeat if needed.
-	exceedHeartbeatInterval := time.Since(s.lastTXTime) > sessionHeartbeatInterval
+	exceedHeartbeatInterval := time.Since(s.lastTXTime) > s.heartbeatInterval()
+	if exceedHeartbeatInterval && s.lastRXTime.Before(s.lastTXTime) {
+		// The peer sent nothing since the previous segment.
+		HeartbeatsMissed.Add(1)
+	}
 	if s.ackOnDataRecv.Load() || exceedHeartbeatInterval {
 		baseStruct := baseStruct{}
 		if s.isClient {
@@ -806,6 +897,17 @@ func (s *Session) runOutputOncePacket() {
 		}
 		s.ackOnDataRecv.Store(false)
 	}
+
+	// Send path MTU probe if needed.
+	if s.isClient && s.isState(sessionEstablished) {
+		if u, ok := s.conn.(*PacketUnderlay); ok && u.prober != nil {
+			if size := u.prober.nextProbe(time.Now(), s.rttStat.RTO()); size > 0 {
+				if err := s.outputPathMTUProbe(size, u.prober.mtu()); err != nil {
+					log.Debugf("%v outputPathMTUProbe() failed: %v", s, err)
+				}
+			}
+		}
+	}
 }
 
 // input reads incoming packets from network and assemble
@@ -822,6 +924,13 @@ func (s *Session) input(seg *segment) error {
 		}
 	}
 
+	if s.isClient && seg.Flags()&flagMaintenance != 0 && s.maintenanceNoticed.CompareAndSwap(false, true) {
+		metrics.MaintenanceReceived.Add(1)
+		if s.onMaintenance != nil {
+			s.onMaintenance()
+		}
+	}
+
 	if seg.block != nil {
 		// Validate cipher block user name is consistent.
 		if s.block.Load() != nil {
@@ -851,10 +960,17 @@ func (s *Session) input(seg *segment) error {
 			if s.downloadBytes == nil {
 				s.downloadBytes = metrics.RegisterMetric(fmt.Sprintf(metrics.UserMetricGroupFormat, (*s.block.Load()).BlockContext().UserName), metrics.UserMetricDownloadBytes, metrics.COUNTER_TIME_SERIES)
 			}
+			if group, ok := s.userGroups[s.userName]; ok && s.groupUploadBytes == nil {
+				s.groupUploadBytes = metrics.RegisterMetric(fmt.Sprintf(metrics.UserGroupMetricGroupFormat, group.GetName()), metrics.UserMetricUploadBytes, metrics.COUNTER_TIME_SERIES)
+				s.groupDownloadBytes = metrics.RegisterMetric(fmt.Sprintf(metrics.UserGroupMetricGroupFormat, group.GetName()), metrics.UserMetricDownloadBytes, metrics.COUNTER_TIME_SERIES)
+			}
 		}
 	}
 
 	s.lastRXTime = time.Now()
+	if ss, ok := seg.metadata.(*sessionStruct); ok && (protocol == openSessionRequest || protocol == openSessionResponse) {
+		s.onPeerMTU(int(ss.mtu))
+	}
 	if protocol == openSessionRequest || protocol == openSessionResponse || protocol == dataServerToClient || protocol == dataClientToServer {
 		return s.inputData(seg)
 	} else if protocol == ackServerToClient || protocol == ackClientToServer {
@@ -942,7 +1058,23 @@ func (s *Session) inputData(seg *segment) error {
 			// Server needs to send open session response.
 			// Check user quota if we can identify the user.
 			s.oLock.Lock()
+			if err := s.checkOverload(); err != nil {
+				s.status = statusRetryLater
+				metrics.OverloadRejects.Add(1)
+				log.Debugf("Closing %v because server is overloaded: %v", s, err)
+				s.oLock.Unlock()
+				s.Close()
+				return nil
+			}
 			if s.userName != "" {
+				if err := s.checkUserEnabled(s.userName); err != nil {
+					s.status = statusUserDisabled
+					metrics.DisabledUserRejects.Add(1)
+					log.Debugf("Closing %v: %v", s, err)
+					s.oLock.Unlock()
+					s.Close()
+					return nil
+				}
 				quotaOK, err := s.checkQuota(s.userName)
 				if err != nil {
 					log.Debugf("%v checkQuota() failed: %v", s, err)
@@ -962,6 +1094,7 @@ func (s *Session) inputData(seg *segment) error {
 					},
 					sessionID: s.id,
 					seq:       s.nextSend,
+					mtu:       uint16(s.mtu),
 				},
 				transport: s.conn.TransportProtocol(),
 			}
@@ -985,8 +1118,15 @@ func (s *Session) inputAck(seg *segment) error {
 	switch s.conn.TransportProtocol() {
 	case common.StreamTransport:
 		// Do nothing when receive ACK from TCP protocol.
+		if seg.Flags()&flagKeepalive != 0 {
+			KeepalivesReceived.Add(1)
+		}
 		return nil
 	case common.PacketTransport:
+		if seg.Flags()&flagPathMTUProbe != 0 {
+			return s.inputPathMTUProbe(seg)
+		}
+
 		// Delete all previous acknowledged segments from sendBuf.
 		var priorInFlight int64
 		s.sendBuf.Ascend(func(iter *segment) bool {
@@ -1035,6 +1175,33 @@ func (s *Session) inputAck(seg *segment) error {
 	}
 }
 
+// inputPathMTUProbe handles a path MTU probe. The client updates the
+// search with the reply. The server records the path MTU confirmed by
+// the client and replies with a probe of the same size.
+func (s *Session) inputPathMTUProbe(seg *segment) error {
+	u, ok := s.conn.(*PacketUnderlay)
+	if !ok {
+		return nil
+	}
+	size, confirmed, ok := parsePathMTUProbePayload(seg.payload)
+	if !ok {
+		return nil
+	}
+	if s.isClient {
+		if u.prober != nil {
+			u.prober.onProbeReply(size)
+		}
+		return nil
+	}
+	if confirmed >= minPathMTU && confirmed <= u.mtu {
+		u.setPeerMTU(s.RemoteAddr(), confirmed)
+	}
+	if size > maxPathMTU {
+		return nil
+	}
+	return s.outputPathMTUProbe(size, 0)
+}
+
 func (s *Session) inputClose(seg *segment) error {
 	s.oLock.Lock()
 	if seg.metadata.Protocol() == closeSessionRequest {
@@ -1058,12 +1225,21 @@ func (s *Session) inputClose(seg *segment) error {
 			return fmt.Errorf("output() failed: %v", err)
 		}
 		// Immediately shutdown event loop.
-		if seg.metadata.(*sessionStruct).statusCode == uint8(statusQuotaExhausted) {
+		status := statusCode(seg.metadata.(*sessionStruct).statusCode)
+		if status == statusQuotaExhausted {
 			log.Infof("Remote requested to shut down the session because user has exhausted quota")
+		} else if status == statusUserDisabled {
+			log.Infof("Remote requested to shut down the session because user is disabled or expired")
+		} else if status == statusRetryLater {
+			metrics.RetryLaterReceived.Add(1)
+			log.Infof("Remote requested to shut down the session because server is overloaded, retry later")
 		} else {
 			log.Debugf("Remote requested to shut down %v", s)
 		}
 		s.oLock.Unlock()
+		if status == statusRetryLater && s.isClient && s.onRetryLater != nil {
+			s.onRetryLater()
+		}
 		s.Close()
 	} else if seg.metadata.Protocol() == closeSessionResponse {
 		// Immediately shutdown event loop.
@@ -1099,6 +1275,108 @@ func (s *Session) output(seg *segment, remoteAddr net.Addr) error {
 	return nil
 }
 
+// hasPath returns true if the session has received segments
+// from the remote address.
+func (s *Session) hasPath(addr net.Addr) bool {
+	s.addrLock.Lock()
+	defer s.addrLock.Unlock()
+	if s.remoteAddr != nil && s.remoteAddr.String() == addr.String() {
+		return true
+	}
+	return s.hasPathLocked(addr.String())
+}
+
+// migrate changes the remote address to the address of the latest
+// segment received by the server. If the client sends segments over
+// multiple paths, the replies are also spread over these paths.
+func (s *Session) migrate(addr net.Addr) {
+	s.addrLock.Lock()
+	if s.remoteAddr == nil || s.remoteAddr.String() == addr.String() {
+		s.addrLock.Unlock()
+		return
+	}
+	isNewPath := !s.hasPathLocked(addr.String())
+	if !s.hasPathLocked(s.remoteAddr.String()) {
+		if len(s.paths) >= maxSessionPaths {
+			s.paths = s.paths[1:]
+		}
+		s.paths = append(s.paths, s.remoteAddr.String())
+	}
+	s.remoteAddr = addr
+	s.addrLock.Unlock()
+	if isNewPath {
+		log.Debugf("Session %d received segment from new path %v", s.id, addr)
+	}
+}
+
+func (s *Session) hasPathLocked(path string) bool {
+	for _, p := range s.paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// outputPathMTUProbe sends a path MTU probe with the given size.
+// confirmed is the path MTU confirmed by the client, or 0 if unknown.
+func (s *Session) outputPathMTUProbe(size, confirmed int) error {
+	baseStruct := baseStruct{
+		flags: flagPathMTUProbe,
+	}
+	if s.isClient {
+		baseStruct.protocol = uint8(ackClientToServer)
+	} else {
+		baseStruct.protocol = uint8(ackServerToClient)
+	}
+	payload := newPathMTUProbePayload(size, confirmed)
+	s.oLock.Lock()
+	defer s.oLock.Unlock()
+	probe := &segment{
+		metadata: &dataAckStruct{
+			baseStruct: baseStruct,
+			sessionID:  s.id,
+			seq:        uint32(mathext.Max(0, int(s.nextSend)-1)),
+			unAckSeq:   s.nextRecv,
+			windowSize: uint16(mathext.Max(0, int(s.legacysendAlgorithm.CongestionWindowSize())-s.recvBuf.Len())),
+			payloadLen: uint16(len(payload)),
+		},
+		payload:   payload,
+		transport: s.conn.TransportProtocol(),
+	}
+	PathMTUProbes.Add(1)
+	return s.output(probe, s.RemoteAddr())
+}
+
+// pathMTU returns the maximum packet size to the peer.
+func (s *Session) pathMTU() int {
+	if u, ok := s.conn.(*PacketUnderlay); ok {
+		return mathext.Min(s.mtu, u.pathMTU(s.RemoteAddr()))
+	}
+	return s.mtu
+}
+
+// onPeerMTU is called when the peer announces the maximum packet size
+// in the open session request or response. Packets sent to the peer use
+// the smaller one of the announced value and the local value. A peer that
+// doesn't announce the value sends 0, which is ignored.
+func (s *Session) onPeerMTU(mtu int) {
+	u, ok := s.conn.(*PacketUnderlay)
+	if !ok || mtu < minPathMTU || mtu > maxPathMTU {
+		return
+	}
+	u.setPeerMTU(s.RemoteAddr(), mathext.Min(mtu, u.mtu))
+}
+
+// onPathMTUBlackhole is called when a segment larger than minPathMTU
+// is retransmitted too many times.
+func (s *Session) onPathMTUBlackhole() {
+	if u, ok := s.conn.(*PacketUnderlay); ok && u.prober != nil {
+		log.Debugf("%v suspects path MTU %d is not deliverable", s, u.prober.mtu())
+		u.prober.onBlackhole()
+	}
+}
+
 func (s *Session) closeWithError(err error) error {
 	if !s.closeRequested.CompareAndSwap(false, true) {
 		// This function has been called before.
@@ -1167,6 +1445,35 @@ func (s *Session) closeWithError(err error) error {
 	return nil
 }
 
+// checkOverload returns an error if the server can't accept more sessions.
+func (s *Session) checkOverload() error {
+	if s.isClient || s.overload == nil {
+		return nil
+	}
+	return s.overload.exceeded()
+}
+
+// checkUserEnabled returns an error if the user is disabled or expired.
+func (s *Session) checkUserEnabled(userName string) error {
+	user, found := s.users[userName]
+	if !found {
+		return nil
+	}
+	if user.GetDisabled() {
+		return fmt.Errorf("user %s is disabled", userName)
+	}
+	if user.GetExpireTime() != "" {
+		expireTime, err := time.Parse(time.RFC3339, user.GetExpireTime())
+		if err != nil {
+			return fmt.Errorf("user %s expire time is invalid: %w", userName, err)
+		}
+		if !time.Now().Before(expireTime) {
+			return fmt.Errorf("user %s expired at %s", userName, user.GetExpireTime())
+		}
+	}
+	return nil
+}
+
 func (s *Session) checkQuota(userName string) (ok bool, err error) {
 	if len(s.users) == 0 {
 		return true, fmt.Errorf("no registered user")
@@ -1175,11 +1482,25 @@ func (s *Session) checkQuota(userName string) (ok bool, err error) {
 	if !found {
 		return true, fmt.Errorf("user %s is not found", userName)
 	}
-	if len(user.GetQuotas()) == 0 {
+	ok, err = quotaOK(fmt.Sprintf(metrics.UserMetricGroupFormat, userName), user.GetQuotas())
+	if !ok {
+		return false, err
+	}
+	// The quotas of user group are shared by all the users in the group.
+	if group, found := s.userGroups[userName]; found {
+		return quotaOK(fmt.Sprintf(metrics.UserGroupMetricGroupFormat, group.GetName()), group.GetQuotas())
+	}
+	return true, err
+}
+
+// quotaOK returns true if the traffic recorded in the metric group,
+// including the traffic reported by the other servers of the cluster,
+// doesn't exceed any of the quotas.
+func quotaOK(metricGroupName string, quotas []*appctlpb.Quota) (bool, error) {
+	if len(quotas) == 0 {
 		return true, nil
 	}
 
-	metricGroupName := fmt.Sprintf(metrics.UserMetricGroupFormat, userName)
 	metricGroup := metrics.GetMetricGroupByName(metricGroupName)
 	if metricGroup == nil {
 		return true, fmt.Errorf("metric group %s is not found", metricGroupName)
@@ -1192,11 +1513,14 @@ func (s *Session) checkQuota(userName string) (ok bool, err error) {
 	if !found {
 		return true, fmt.Errorf("metric %s in group %s is not found", metrics.UserMetricDownloadBytes, metricGroupName)
 	}
-	for _, quota := range user.GetQuotas() {
+	for _, quota := range quotas {
 		now := time.Now()
 		then := now.Add(-time.Duration(quota.GetDays()) * 24 * time.Hour)
 		totalBytes := uploadBytes.(*metrics.Counter).DeltaBetween(then, now)
 		totalBytes += downloadBytes.(*metrics.Counter).DeltaBetween(then, now)
+		// Add the traffic on the other servers of the cluster.
+		totalBytes += metrics.RemoteDeltaBetween(metricGroupName, metrics.UserMetricUploadBytes, then, now)
+		totalBytes += metrics.RemoteDeltaBetween(metricGroupName, metrics.UserMetricDownloadBytes, then, now)
 		if totalBytes/1048576 > int64(quota.GetMegabytes()) {
 			return false, nil
 		}
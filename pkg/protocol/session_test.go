@@ -0,0 +1,81 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSessionCheckUserEnabled(t *testing.T) {
+	users := map[string]*appctlpb.User{
+		"active": {
+			Name:       proto.String("active"),
+			ExpireTime: proto.String(time.Now().Add(time.Hour).Format(time.RFC3339)),
+		},
+		"disabled": {
+			Name:     proto.String("disabled"),
+			Disabled: proto.Bool(true),
+		},
+		"expired": {
+			Name:       proto.String("expired"),
+			ExpireTime: proto.String(time.Now().Add(-time.Hour).Format(time.RFC3339)),
+		},
+	}
+	s := NewSession(1, false, 1400, users)
+	testCases := []struct {
+		user    string
+		wantErr bool
+	}{
+		{"active", false},
+		{"disabled", true},
+		{"expired", true},
+	}
+	for _, tc := range testCases {
+		if err := s.checkUserEnabled(tc.user); (err != nil) != tc.wantErr {
+			t.Errorf("checkUserEnabled(%q) = %v, want error %v", tc.user, err, tc.wantErr)
+		}
+	}
+}
+
+func TestSessionToSessionInfo(t *testing.T) {
+	s := NewSession(2, true, 1400, nil)
+	info := s.ToSessionInfo()
+	if info.User != nil || info.Destination != nil {
+		t.Errorf("user and destination are set in a new session: %v", info)
+	}
+	if info.GetStartTime() == nil {
+		t.Fatalf("start time is not set")
+	}
+
+	s.userName = "xiaochitang"
+	s.SetDestination("example.com:443")
+	s.readBytes.Add(100)
+	s.writtenBytes.Add(200)
+	info = s.ToSessionInfo()
+	if info.GetUser() != "xiaochitang" {
+		t.Errorf("user = %q, want %q", info.GetUser(), "xiaochitang")
+	}
+	if info.GetDestination() != "example.com:443" {
+		t.Errorf("destination = %q, want %q", info.GetDestination(), "example.com:443")
+	}
+	if info.GetRecvBytes() != 100 || info.GetSendBytes() != 200 {
+		t.Errorf("recv bytes = %d, send bytes = %d, want 100 and 200", info.GetRecvBytes(), info.GetSendBytes())
+	}
+}
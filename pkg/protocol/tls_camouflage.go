@@ -0,0 +1,287 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/cipher"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+const (
+	// tlsCamouflageHandshakeTimeout is the maximum time to complete the
+	// TLS handshake and receive the first mieru metadata from the client.
+	tlsCamouflageHandshakeTimeout = 10 * time.Second
+
+	// streamAuthPrefixLength is the length of the first encrypted metadata
+	// sent by the client, which is used to authenticate the connection.
+	streamAuthPrefixLength = MetadataLength + cipher.DefaultOverhead + cipher.DefaultNonceSize
+)
+
+var (
+	// TLSCamouflageAuthenticated is the number of TLS connections that
+	// are authenticated as mieru clients.
+	TLSCamouflageAuthenticated = metrics.RegisterMetric("tls camouflage", "Authenticated", metrics.COUNTER)
+
+	// TLSCamouflageFallbacks is the number of TLS connections that are
+	// forwarded to the fallback address.
+	TLSCamouflageFallbacks = metrics.RegisterMetric("tls camouflage", "Fallbacks", metrics.COUNTER)
+
+	// TLSCamouflageRejected is the number of TLS connections that are
+	// closed because they fail authentication or TLS handshake.
+	TLSCamouflageRejected = metrics.RegisterMetric("tls camouflage", "Rejected", metrics.COUNTER)
+
+	// TLSCamouflageResumed is the number of TLS connections made by proxy
+	// client that resume a previous TLS session.
+	TLSCamouflageResumed = metrics.RegisterMetric("tls camouflage", "Resumed", metrics.COUNTER)
+)
+
+// tlsClientSessionCache keeps the TLS session tickets issued by proxy
+// servers. When the client connects to the same server again, for example
+// after the underlay is closed because it is idle or the network is changed,
+// it resumes the TLS session instead of running a full handshake. The server
+// skips sending and signing the certificate, and the handshake is smaller.
+var tlsClientSessionCache = tls.NewLRUClientSessionCache(0)
+
+// TLSCamouflageOptions are the options of a TCP underlay that is wrapped
+// in a TLS layer, so the handshake looks like an ordinary HTTPS one.
+//
+// The TLS layer is only used for camouflage. The mieru protocol inside it
+// is encrypted and authenticated, so proxy client doesn't verify the
+// certificate of the server.
+type TLSCamouflageOptions struct {
+	// TLSConfig is the configuration of the TLS layer.
+	// Proxy client presents the ServerName and NextProtos in the handshake.
+	// Proxy server uses the Certificates and NextProtos.
+	TLSConfig *tls.Config
+
+	// FallbackAddress is the address of a web server. Proxy server forwards
+	// connections that fail authentication to it. If it is empty, these
+	// connections are served by the decoy, or closed if there is no decoy.
+	FallbackAddress string
+}
+
+// prefixConn is a network connection that returns the prefix
+// before the data from the connection.
+type prefixConn struct {
+	net.Conn
+	prefix []byte
+}
+
+func (c *prefixConn) Read(b []byte) (int, error) {
+	if len(c.prefix) > 0 {
+		n := copy(b, c.prefix)
+		c.prefix = c.prefix[n:]
+		return n, nil
+	}
+	return c.Conn.Read(b)
+}
+
+// NetConn returns the wrapped connection.
+func (c *prefixConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// newTLSCamouflageUnderlay connects to the remote address with TLS,
+// and creates a stream underlay on top of it.
+//
+// This function is only used by proxy client.
+func newTLSCamouflageUnderlay(ctx context.Context, dialer apicommon.Dialer, network, addr string, mtu int, block cipher.BlockCipher, opts *TLSCamouflageOptions) (*StreamUnderlay, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("network %s is not supported by TLS camouflage underlay", network)
+	}
+	if block.IsStateless() {
+		return nil, fmt.Errorf("stream underlay block cipher must be stateful")
+	}
+	rawConn, err := dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("DialContext() failed: %w", err)
+	}
+	tlsConfig := &tls.Config{}
+	if opts.TLSConfig != nil {
+		tlsConfig = opts.TLSConfig.Clone()
+	}
+	tlsConfig.InsecureSkipVerify = true
+	if tlsConfig.ClientSessionCache == nil {
+		tlsConfig.ClientSessionCache = tlsClientSessionCache
+	}
+	conn := tls.Client(rawConn, tlsConfig)
+	handshakeCtx, cancel := context.WithTimeout(ctx, tlsCamouflageHandshakeTimeout)
+	defer cancel()
+	if err := conn.HandshakeContext(handshakeCtx); err != nil {
+		rawConn.Close()
+		return nil, fmt.Errorf("TLS HandshakeContext() failed: %w", err)
+	}
+	if conn.ConnectionState().DidResume {
+		TLSCamouflageResumed.Add(1)
+	}
+	t := &StreamUnderlay{
+		baseUnderlay: *newBaseUnderlay(true, mtu),
+		conn:         conn,
+		candidates:   []cipher.BlockCipher{block},
+	}
+	return t, nil
+}
+
+// tlsCamouflageListener accepts TLS connections from a TCP listener.
+// After the TLS handshake, the first encrypted metadata from the client
+// is checked. Connections that fail authentication are forwarded to the
+// fallback address or the decoy, so they see an ordinary web server.
+type tlsCamouflageListener struct {
+	rawListener  net.Listener
+	opts         *TLSCamouflageOptions
+	decoy        *decoyServer
+	authenticate func(encryptedMeta []byte) bool
+	conns        chan net.Conn
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var _ net.Listener = &tlsCamouflageListener{}
+
+func newTLSCamouflageListener(rawListener net.Listener, opts *TLSCamouflageOptions, decoy *decoyServer, authenticate func([]byte) bool) *tlsCamouflageListener {
+	l := &tlsCamouflageListener{
+		rawListener:  rawListener,
+		opts:         opts,
+		decoy:        decoy,
+		authenticate: authenticate,
+		conns:        make(chan net.Conn),
+		done:         make(chan struct{}),
+	}
+	go l.acceptLoop()
+	return l
+}
+
+func (l *tlsCamouflageListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, io.ErrClosedPipe
+	}
+}
+
+func (l *tlsCamouflageListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.done)
+		err = l.rawListener.Close()
+		if l.decoy != nil {
+			l.decoy.stop()
+		}
+	})
+	return err
+}
+
+func (l *tlsCamouflageListener) Addr() net.Addr {
+	return l.rawListener.Addr()
+}
+
+func (l *tlsCamouflageListener) acceptLoop() {
+	defer l.Close()
+	for {
+		rawConn, err := l.rawListener.Accept()
+		if err != nil {
+			return
+		}
+		go l.handleConn(rawConn)
+	}
+}
+
+func (l *tlsCamouflageListener) handleConn(rawConn net.Conn) {
+	rawConn.SetDeadline(time.Now().Add(tlsCamouflageHandshakeTimeout))
+	conn := tls.Server(rawConn, l.opts.TLSConfig)
+	if err := conn.Handshake(); err != nil {
+		log.Debugf("TLS handshake with %v failed: %v", rawConn.RemoteAddr(), err)
+		TLSCamouflageRejected.Add(1)
+		rawConn.Close()
+		return
+	}
+	prefix, authenticated := readStreamAuthPrefix(conn, l.authenticate)
+	rawConn.SetDeadline(time.Time{})
+	if authenticated {
+		TLSCamouflageAuthenticated.Add(1)
+		select {
+		case l.conns <- &prefixConn{Conn: conn, prefix: prefix}:
+		case <-l.done:
+			conn.Close()
+		}
+		return
+	}
+	l.fallback(conn, prefix)
+}
+
+// readStreamAuthPrefix reads the first encrypted metadata from the
+// connection, and returns true if it is authenticated. It stops early if
+// the data is a complete HTTP request header, or the connection is idle
+// until the deadline.
+func readStreamAuthPrefix(conn net.Conn, authenticate func([]byte) bool) ([]byte, bool) {
+	buf := make([]byte, streamAuthPrefixLength)
+	n := 0
+	for n < len(buf) {
+		m, err := conn.Read(buf[n:])
+		n += m
+		if err != nil || bytes.Contains(buf[:n], []byte("\r\n\r\n")) {
+			return buf[:n], false
+		}
+	}
+	return buf, authenticate(buf)
+}
+
+// fallback forwards the connection to the fallback address, or serves it
+// by the decoy. The data already read from the connection is sent first.
+func (l *tlsCamouflageListener) fallback(conn net.Conn, prefix []byte) {
+	if l.opts.FallbackAddress == "" && l.decoy != nil {
+		l.decoy.serve(&prefixConn{Conn: conn, prefix: prefix})
+		return
+	}
+	if l.opts.FallbackAddress == "" {
+		TLSCamouflageRejected.Add(1)
+		conn.Close()
+		return
+	}
+	TLSCamouflageFallbacks.Add(1)
+	fallbackConn, err := net.DialTimeout("tcp", l.opts.FallbackAddress, tlsCamouflageHandshakeTimeout)
+	if err != nil {
+		log.Debugf("Dial fallback address %s failed: %v", l.opts.FallbackAddress, err)
+		conn.Close()
+		return
+	}
+	if len(prefix) > 0 {
+		if _, err := fallbackConn.Write(prefix); err != nil {
+			conn.Close()
+			fallbackConn.Close()
+			return
+		}
+	}
+	// The fallback server has its own timeouts.
+	common.HandshakeDone(conn)
+	common.BidiCopy(conn, fallbackConn)
+}
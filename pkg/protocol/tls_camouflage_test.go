@@ -0,0 +1,184 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/cipher"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/testtool"
+)
+
+func TestTLSCamouflageUnderlay(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	decoy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("welcome"))
+	}))
+	defer decoy.Close()
+
+	cert, _ := newTestCertificate(t, "www.example.com")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverProperties := NewTLSCamouflageUnderlayProperties(1400, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil, &TLSCamouflageOptions{
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{"http/1.1"},
+		},
+		FallbackAddress: decoy.Listener.Addr().String(),
+	})
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	time.Sleep(100 * time.Millisecond)
+	go testServer.Serve(serverMux)
+	defer testServer.Close()
+
+	authenticated := TLSCamouflageAuthenticated.Load()
+	clientProperties := NewTLSCamouflageUnderlayProperties(1400, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, &TLSCamouflageOptions{
+		TLSConfig: &tls.Config{
+			ServerName: "www.example.com",
+			NextProtos: []string{"h2", "http/1.1"},
+		},
+	})
+	runClient(t, clientProperties, []byte("xiaochitang"), []byte("kuiranbudong"), 4)
+	if TLSCamouflageAuthenticated.Load() == authenticated {
+		t.Errorf("no TLS connection is authenticated")
+	}
+
+	// A web browser sees the decoy website.
+	fallbacks := TLSCamouflageFallbacks.Load()
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{ServerName: "www.example.com", NextProtos: []string{"http/1.1"}, InsecureSkipVerify: true},
+		},
+		Timeout: 5 * time.Second,
+	}
+	resp, err := client.Get(fmt.Sprintf("https://127.0.0.1:%d/", port))
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("io.ReadAll() failed: %v", err)
+	}
+	if string(body) != "welcome" {
+		t.Errorf("got response %q, want %q", string(body), "welcome")
+	}
+	if resp.TLS == nil || resp.TLS.NegotiatedProtocol != "http/1.1" {
+		t.Errorf("negotiated protocol is not http/1.1")
+	}
+	if TLSCamouflageFallbacks.Load() == fallbacks {
+		t.Errorf("fallback is not counted")
+	}
+}
+
+func TestTLSCamouflageRejectWithoutFallback(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	cert, _ := newTestCertificate(t, "www.example.com")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverProperties := NewTLSCamouflageUnderlayProperties(1400, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil, &TLSCamouflageOptions{
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	})
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer serverMux.Close()
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := tls.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port), &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("tls.Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	random := make([]byte, streamAuthPrefixLength)
+	if _, err := conn.Write(random); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err == nil {
+		t.Errorf("connection is not closed after authentication failure")
+	}
+}
+
+func TestTLSCamouflageSessionResumption(t *testing.T) {
+	cert, _ := newTestCertificate(t, "resume.example.com")
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatalf("tls.Listen() failed: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.Write([]byte{0})
+				io.Copy(io.Discard, conn)
+			}()
+		}
+	}()
+
+	block, err := cipher.BlockCipherFromPassword(cipher.HashPassword([]byte("kuiranbudong"), []byte("xiaochitang")), false)
+	if err != nil {
+		t.Fatalf("cipher.BlockCipherFromPassword() failed: %v", err)
+	}
+	opts := &TLSCamouflageOptions{TLSConfig: &tls.Config{ServerName: "resume.example.com"}}
+	resumed := TLSCamouflageResumed.Load()
+	for i := 0; i < 2; i++ {
+		underlay, err := newTLSCamouflageUnderlay(context.Background(), &net.Dialer{}, "tcp", listener.Addr().String(), 1400, block, opts)
+		if err != nil {
+			t.Fatalf("newTLSCamouflageUnderlay() failed: %v", err)
+		}
+		// The session ticket is received after the handshake.
+		underlay.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, err := underlay.conn.Read(make([]byte, 1)); err != nil {
+			t.Fatalf("Read() failed: %v", err)
+		}
+		underlay.conn.Close()
+	}
+	if got := TLSCamouflageResumed.Load() - resumed; got != 1 {
+		t.Errorf("got %d resumed TLS sessions, want 1", got)
+	}
+}
@@ -31,6 +31,19 @@ var (
 	UnderlayCurrEstablished = metrics.RegisterMetric("underlay", "CurrEstablished", metrics.GAUGE)
 	UnderlayMalformedUDP    = metrics.RegisterMetric("underlay", "UnderlayMalformedUDP", metrics.COUNTER)
 	UnderlayUnsolicitedUDP  = metrics.RegisterMetric("underlay", "UnsolicitedUDP", metrics.COUNTER)
+
+	// HybridKeyExchanges is the number of stream underlays that finished
+	// the hybrid X25519 + ML-KEM-768 key exchange.
+	HybridKeyExchanges = metrics.RegisterMetric("underlay", "HybridKeyExchanges", metrics.COUNTER)
+
+	// UnderlayAcceptMetrics are the metrics of the accept limit
+	// of TCP and WEBSOCKET port bindings.
+	UnderlayAcceptMetrics = common.AcceptLimitMetrics{
+		HalfOpen:          metrics.RegisterMetric("underlay accept", "HalfOpen", metrics.GAUGE),
+		HandshakeTimeouts: metrics.RegisterMetric("underlay accept", "HandshakeTimeouts", metrics.COUNTER),
+		HalfOpenRejected:  metrics.RegisterMetric("underlay accept", "HalfOpenRejected", metrics.COUNTER),
+		RateLimited:       metrics.RegisterMetric("underlay accept", "RateLimited", metrics.COUNTER),
+	}
 )
 
 // UnderlayProperties defines network properties of a underlay.
@@ -72,6 +85,10 @@ type Underlay interface {
 	// Returns detailed information of all the sessions.
 	SessionInfos() []*appctlpb.SessionInfo
 
+	// Close the session with the ID.
+	// Returns false if the session is not found.
+	CloseSession(id uint32) bool
+
 	// Run event loop.
 	// The underlay needs to be closed when this returns.
 	RunEventLoop(context.Context) error
@@ -89,6 +106,13 @@ type underlayDescriptor struct {
 	transportProtocol common.TransportProtocol
 	localAddr         net.Addr
 	remoteAddr        net.Addr
+	webSocket         *WebSocketOptions
+	tlsCamouflage     *TLSCamouflageOptions
+	decoy             *DecoyOptions
+	portKnocking      *PortKnockingOptions
+	proxyProtocol     *ProxyProtocolOptions
+	portHopping       *PortHoppingOptions
+	autoTransport     bool
 }
 
 var _ UnderlayProperties = &underlayDescriptor{}
@@ -125,3 +149,162 @@ func NewUnderlayProperties(mtu int, transportProtocol common.TransportProtocol,
 	}
 	return d
 }
+
+// NewWebSocketUnderlayProperties creates a new instance of UnderlayProperties
+// that tunnels the mieru protocol inside WebSocket.
+func NewWebSocketUnderlayProperties(mtu int, localAddr net.Addr, remoteAddr net.Addr, opts *WebSocketOptions) UnderlayProperties {
+	d := NewUnderlayProperties(mtu, common.WebSocketTransport, localAddr, remoteAddr).(*underlayDescriptor)
+	d.webSocket = opts
+	if d.webSocket == nil {
+		d.webSocket = &WebSocketOptions{}
+	}
+	return d
+}
+
+// NewTLSCamouflageUnderlayProperties creates a new instance of UnderlayProperties
+// that wraps the TCP underlay in a TLS layer.
+func NewTLSCamouflageUnderlayProperties(mtu int, localAddr net.Addr, remoteAddr net.Addr, opts *TLSCamouflageOptions) UnderlayProperties {
+	d := NewUnderlayProperties(mtu, common.StreamTransport, localAddr, remoteAddr).(*underlayDescriptor)
+	d.tlsCamouflage = opts
+	return d
+}
+
+// WithDecoy returns a copy of the underlay properties. The connections
+// to the TCP listener of the underlay that fail authentication are served
+// by the decoy. It has no effect on UDP underlays.
+func WithDecoy(p UnderlayProperties, opts *DecoyOptions) UnderlayProperties {
+	d, ok := p.(*underlayDescriptor)
+	if !ok {
+		return p
+	}
+	c := *d
+	c.decoy = opts
+	return &c
+}
+
+// decoyOptionsOf returns the decoy options of the underlay properties,
+// or nil if there is no decoy.
+func decoyOptionsOf(p UnderlayProperties) *DecoyOptions {
+	if d, ok := p.(*underlayDescriptor); ok {
+		return d.decoy
+	}
+	return nil
+}
+
+// WithPortKnocking returns a copy of the underlay properties. Before
+// proxy client creates a new underlay, it sends the port knocking
+// sequence to the server. It has no effect on proxy server.
+func WithPortKnocking(p UnderlayProperties, opts *PortKnockingOptions) UnderlayProperties {
+	d, ok := p.(*underlayDescriptor)
+	if !ok {
+		return p
+	}
+	c := *d
+	c.portKnocking = opts
+	return &c
+}
+
+// portKnockingOptionsOf returns the port knocking options of the
+// underlay properties, or nil if there is no port knocking.
+func portKnockingOptionsOf(p UnderlayProperties) *PortKnockingOptions {
+	if d, ok := p.(*underlayDescriptor); ok {
+		return d.portKnocking
+	}
+	return nil
+}
+
+// WithPortHopping returns a copy of the underlay properties. Proxy client
+// creates new UDP underlays to the active port of the range instead of
+// the port of the remote address. Proxy server only accepts new sessions
+// of a user if the local port of the underlay is active for the user.
+// It has no effect on TCP and WebSocket underlays.
+func WithPortHopping(p UnderlayProperties, opts *PortHoppingOptions) UnderlayProperties {
+	d, ok := p.(*underlayDescriptor)
+	if !ok {
+		return p
+	}
+	c := *d
+	c.portHopping = opts
+	return &c
+}
+
+// portHoppingOptionsOf returns the port hopping options of the underlay
+// properties, or nil if there is no port hopping.
+func portHoppingOptionsOf(p UnderlayProperties) *PortHoppingOptions {
+	if d, ok := p.(*underlayDescriptor); ok && d.transportProtocol == common.PacketTransport {
+		return d.portHopping
+	}
+	return nil
+}
+
+// WithAutoTransport returns a copy of the underlay properties. The
+// endpoint is created from a port binding with AUTO protocol, which has
+// a TCP endpoint and a UDP endpoint of the same address and port. Proxy
+// client creates new underlays to the endpoint with lower latency.
+func WithAutoTransport(p UnderlayProperties) UnderlayProperties {
+	d, ok := p.(*underlayDescriptor)
+	if !ok {
+		return p
+	}
+	c := *d
+	c.autoTransport = true
+	return &c
+}
+
+// isAutoTransport returns true if the underlay properties are created
+// from a port binding with AUTO protocol.
+func isAutoTransport(p UnderlayProperties) bool {
+	d, ok := p.(*underlayDescriptor)
+	return ok && d.autoTransport
+}
+
+// WithProxyProtocol returns a copy of the underlay properties. The TCP
+// listener of the underlay accepts PROXY protocol headers from a load
+// balancer. It has no effect on UDP underlays and proxy client.
+func WithProxyProtocol(p UnderlayProperties, opts *ProxyProtocolOptions) UnderlayProperties {
+	d, ok := p.(*underlayDescriptor)
+	if !ok {
+		return p
+	}
+	c := *d
+	c.proxyProtocol = opts
+	return &c
+}
+
+// proxyProtocolOptionsOf returns the PROXY protocol options of the
+// underlay properties, or nil if PROXY protocol is not accepted.
+func proxyProtocolOptionsOf(p UnderlayProperties) *ProxyProtocolOptions {
+	if d, ok := p.(*underlayDescriptor); ok {
+		return d.proxyProtocol
+	}
+	return nil
+}
+
+// WithLocalAddr returns a copy of the underlay properties with a
+// different local address. Other properties are not changed.
+func WithLocalAddr(p UnderlayProperties, localAddr net.Addr) UnderlayProperties {
+	d, ok := p.(*underlayDescriptor)
+	if !ok {
+		return p
+	}
+	c := *d
+	c.localAddr = localAddr
+	return &c
+}
+
+// tlsCamouflageOptionsOf returns the TLS camouflage options of the
+// underlay properties, or nil if the underlay is not wrapped in TLS.
+func tlsCamouflageOptionsOf(p UnderlayProperties) *TLSCamouflageOptions {
+	if d, ok := p.(*underlayDescriptor); ok {
+		return d.tlsCamouflage
+	}
+	return nil
+}
+
+// webSocketOptionsOf returns the WebSocket options of the underlay properties.
+func webSocketOptionsOf(p UnderlayProperties) *WebSocketOptions {
+	if d, ok := p.(*underlayDescriptor); ok && d.webSocket != nil {
+		return d.webSocket
+	}
+	return &WebSocketOptions{}
+}
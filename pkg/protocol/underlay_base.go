@@ -184,6 +184,15 @@ func (b *baseUnderlay) SessionInfos() []*appctlpb.SessionInfo {
 	return res
 }
 
+func (b *baseUnderlay) CloseSession(id uint32) bool {
+	v, found := b.sessionMap.Load(id)
+	if !found {
+		return false
+	}
+	v.(*Session).Close()
+	return true
+}
+
 func (b *baseUnderlay) RunEventLoop(ctx context.Context) error {
 	return stderror.ErrUnsupported
 }
@@ -17,10 +17,11 @@ package protocol
 
 import (
 	"context"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"net"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	apicommon "github.com/enfein/mieru/v3/apis/common"
@@ -28,6 +29,7 @@ import (
 	"github.com/enfein/mieru/v3/pkg/cipher"
 	"github.com/enfein/mieru/v3/pkg/common"
 	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/mathext"
 	"github.com/enfein/mieru/v3/pkg/metrics"
 	"github.com/enfein/mieru/v3/pkg/replay"
 	"github.com/enfein/mieru/v3/pkg/sockopts"
@@ -44,7 +46,7 @@ const (
 	readOneSegmentTimeout = 5 * time.Second
 )
 
-var packetReplayCache = replay.NewCache(4*1024*1024, cipher.KeyRefreshInterval*3)
+var packetReplayCache = replay.RegisterCache("packet", replay.DefaultCapacity, replay.DefaultExpireInterval)
 
 type PacketUnderlay struct {
 	// ---- common fields ----
@@ -56,9 +58,17 @@ type PacketUnderlay struct {
 	// ---- client fields ----
 	serverAddr net.Addr
 	block      cipher.BlockCipher
+	prober     *pathMTUProber // nil if path MTU discovery is disabled
 
 	// ---- server fields ----
-	users map[string]*appctlpb.User
+	users        map[string]*appctlpb.User
+	userGroups   map[string]*appctlpb.UserGroup
+	overload     *overloadState // limits and recent load of the server
+	maintenance  *atomic.Bool   // if true, announce the maintenance to clients
+	keyRotation  *atomic.Pointer[cipher.KeyRotation]
+	knockAllowed func(net.Addr) bool // nil if port knocking is disabled
+	portHopping  *PortHoppingOptions // nil if port hopping is disabled
+	peerMTUs     sync.Map            // Map<remote address, path MTU announced or confirmed by peer>
 }
 
 var _ Underlay = &PacketUnderlay{}
@@ -69,6 +79,13 @@ var _ Underlay = &PacketUnderlay{}
 //
 // This function is only used by proxy client.
 func NewPacketUnderlay(ctx context.Context, network, addr string, mtu int, block cipher.BlockCipher, resolver apicommon.DNSResolver) (*PacketUnderlay, error) {
+	return newPacketUnderlay(ctx, network, addr, mtu, block, resolver, nil, common.LocalBinding{})
+}
+
+// newPacketUnderlay creates a client packet underlay. If devices is not
+// empty, packets are sent over these local network devices in turn.
+// Otherwise, packets are sent from the local binding.
+func newPacketUnderlay(ctx context.Context, network, addr string, mtu int, block cipher.BlockCipher, resolver apicommon.DNSResolver, devices []string, binding common.LocalBinding) (*PacketUnderlay, error) {
 	switch network {
 	case "udp", "udp4", "udp6":
 	default:
@@ -77,18 +94,30 @@ func NewPacketUnderlay(ctx context.Context, network, addr string, mtu int, block
 	if !block.IsStateless() {
 		return nil, fmt.Errorf("packet underlay block cipher must be stateless")
 	}
-	localAddr := &net.UDPAddr{}
 	remoteAddr, err := apicommon.ResolveUDPAddr(resolver, "udp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("ResolveUDPAddr() failed: %w", err)
 	}
 
-	conn, err := net.ListenUDP(network, localAddr)
-	if err != nil {
-		return nil, fmt.Errorf("net.ListenUDP() failed: %w", err)
-	}
-	if err := sockopts.ApplyUDPControls(conn); err != nil {
-		return nil, fmt.Errorf("ApplyUDPControls() failed: %w", err)
+	var conn net.PacketConn
+	if len(devices) > 0 {
+		family := "udp6"
+		if remoteAddr.IP.To4() != nil {
+			family = "udp4"
+		}
+		conn, err = newMultipathConn(family, devices)
+		if err != nil {
+			return nil, fmt.Errorf("newMultipathConn() failed: %w", err)
+		}
+	} else {
+		udpConn, err := binding.ListenUDP(network, remoteAddr.IP)
+		if err != nil {
+			return nil, fmt.Errorf("ListenUDP() failed: %w", err)
+		}
+		if err := sockopts.ApplyUDPControls(udpConn); err != nil {
+			return nil, fmt.Errorf("ApplyUDPControls() failed: %w", err)
+		}
+		conn = udpConn
 	}
 	u := &PacketUnderlay{
 		baseUnderlay:      *newBaseUnderlay(true, mtu),
@@ -102,6 +131,57 @@ func NewPacketUnderlay(ctx context.Context, network, addr string, mtu int, block
 	return u, nil
 }
 
+// isActiveHoppingPort returns true if the local port of the server
+// underlay is active for the user, or port hopping is disabled.
+func (u *PacketUnderlay) isActiveHoppingPort(user *appctlpb.User) bool {
+	if u.portHopping == nil {
+		return true
+	}
+	localAddr, ok := u.conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return true
+	}
+	passwords := userPasswords(user, nil)
+	if len(passwords) == 0 {
+		return false
+	}
+	return u.portHopping.accepts(passwords[0], localAddr.Port, time.Now())
+}
+
+// enablePathMTUDiscovery starts to search the path MTU to the server.
+// The MTU of the underlay is the upper bound of the search.
+// Before a larger size is confirmed, minPathMTU is used.
+//
+// This function is only used by proxy client.
+func (u *PacketUnderlay) enablePathMTUDiscovery() {
+	if !u.isClient {
+		panic("Can't enable path MTU discovery in server packet underlay")
+	}
+	u.prober = newPathMTUProber(u.mtu)
+}
+
+// pathMTU returns the maximum packet size to the remote address.
+func (u *PacketUnderlay) pathMTU(addr net.Addr) int {
+	mtu := u.mtu
+	if u.isClient && u.prober != nil {
+		mtu = u.prober.mtu()
+	}
+	if v, ok := u.peerMTUs.Load(addr.String()); ok {
+		mtu = mathext.Min(mtu, v.(int))
+	}
+	return mtu
+}
+
+// setPeerMTU records the path MTU to the remote address. The proxy server
+// records the value announced by the client when a session is opened,
+// and the value confirmed by path MTU discovery. The proxy client records
+// the value announced by the server.
+func (u *PacketUnderlay) setPeerMTU(addr net.Addr, mtu int) {
+	if prev, ok := u.peerMTUs.Swap(addr.String(), mtu); !ok || prev.(int) != mtu {
+		log.Debugf("%v set path MTU to %v as %d", u, addr, mtu)
+	}
+}
+
 func (u *PacketUnderlay) String() string {
 	if u.conn == nil {
 		return "PacketUnderlay{}"
@@ -237,6 +317,15 @@ func (u *PacketUnderlay) RunEventLoop(ctx context.Context) error {
 				}
 				continue
 			}
+			if !u.isClient {
+				s := session.(*Session)
+				if seg.block != nil && s.block.Load() != nil && seg.block.BlockContext().UserName != (*s.block.Load()).BlockContext().UserName {
+					log.Debugf("%v received segment of %v from user %q", u, s, seg.block.BlockContext().UserName)
+					continue
+				}
+				// Follow the path used by the client.
+				s.migrate(addr)
+			}
 			session.(*Session).recvChan <- seg
 		} else {
 			log.Debugf("Ignore unknown protocol %d", seg.metadata.Protocol())
@@ -261,6 +350,8 @@ func (u *PacketUnderlay) onOpenSessionRequest(seg *segment, remoteAddr net.Addr)
 		return nil
 	}
 	session := NewSession(sessionID, false, u.MTU(), u.users)
+	session.userGroups = u.userGroups
+	session.overload = u.overload
 	u.AddSession(session, remoteAddr)
 	session.recvChan <- seg
 	u.readySessions <- session
@@ -366,12 +457,14 @@ func (u *PacketUnderlay) readOneSegment() (*segment, net.Addr, error) {
 			}
 		} else {
 			var decrypted bool
+			var knownPath bool
 			var err error
 			// Try existing sessions.
 			cipher.ServerIterateDecrypt.Add(1)
 			u.sessionMap.Range(func(k, v any) bool {
 				session := v.(*Session)
-				if session.block.Load() != nil && session.RemoteAddr().String() == addr.String() {
+				if session.block.Load() != nil && session.hasPath(addr) {
+					knownPath = true
 					decryptedMeta, err = (*session.block.Load()).Decrypt(encryptedMeta)
 					if err == nil {
 						decrypted = true
@@ -381,25 +474,37 @@ func (u *PacketUnderlay) readOneSegment() (*segment, net.Addr, error) {
 				}
 				return true
 			})
+			// Port knocking is not required if the address already has a session.
+			if !decrypted && !knownPath && u.knockAllowed != nil && !u.knockAllowed(addr) {
+				KnockRejected.Add(1)
+				continue
+			}
 			if !decrypted {
 				// This is a new session. Try all registered users.
+				var rotation *cipher.KeyRotation
+				if u.keyRotation != nil {
+					rotation = u.keyRotation.Load()
+				}
+			users:
 				for _, user := range u.users {
-					var password []byte
-					password, err = hex.DecodeString(user.GetHashedPassword())
-					if err != nil {
-						log.Debugf("Unable to decode hashed password %q from user %q", user.GetHashedPassword(), user.GetName())
-						continue
-					}
-					if len(password) == 0 {
-						password = cipher.HashPassword([]byte(user.GetPassword()), []byte(user.GetName()))
-					}
-					blockCipher, decryptedMeta, err = cipher.TryDecrypt(encryptedMeta, password, true)
-					if err == nil {
-						decrypted = true
-						blockCipher.SetBlockContext(cipher.BlockContext{
-							UserName: user.GetName(),
-						})
-						break
+					for _, password := range userPasswords(user, rotation) {
+						for _, aeadType := range aeadTypesOf(user.GetCipherSuite()) {
+							blockCipher, decryptedMeta, err = cipher.TryDecryptWithAEAD(encryptedMeta, password, aeadType, true)
+							if err == nil {
+								if !u.isActiveHoppingPort(user) {
+									PortHoppingRejected.Add(1)
+									if log.IsLevelEnabled(log.TraceLevel) {
+										log.Tracef("%v rejected new session of user %s from %v on inactive port", u, user.GetName(), addr)
+									}
+									break users
+								}
+								decrypted = true
+								blockCipher.SetBlockContext(cipher.BlockContext{
+									UserName: user.GetName(),
+								})
+								break users
+							}
+						}
 					}
 				}
 			}
@@ -593,6 +698,9 @@ func (u *PacketUnderlay) writeOneSegment(seg *segment, addr net.Addr) error {
 	u.sendMutex.Lock()
 	defer u.sendMutex.Unlock()
 
+	if !u.isClient && u.maintenance != nil && u.maintenance.Load() {
+		seg.setFlags(seg.Flags() | flagMaintenance)
+	}
 	var blockCipher cipher.BlockCipher
 	if u.isClient {
 		if u.block == nil {
@@ -626,8 +734,9 @@ func (u *PacketUnderlay) writeOneSegment(seg *segment, addr net.Addr) error {
 		panic(fmt.Sprintf("%v cipher block is not ready", u))
 	}
 
+	mtu := u.pathMTU(addr)
 	if ss, ok := toSessionStruct(seg.metadata); ok {
-		maxPaddingSize := MaxPaddingSize(u.mtu, u.TransportProtocol(), int(ss.payloadLen), 0)
+		maxPaddingSize := MaxPaddingSize(mtu, u.TransportProtocol(), int(ss.payloadLen), 0)
 		padding := newPadding(
 			buildRecommendedPaddingOpts(maxPaddingSize, packetOverhead+int(ss.payloadLen), blockCipher.BlockContext().UserName),
 		)
@@ -661,14 +770,18 @@ func (u *PacketUnderlay) writeOneSegment(seg *segment, addr net.Addr) error {
 		}
 		metrics.OutputPaddingBytes.Add(int64(len(padding)))
 	} else if das, ok := toDataAckStruct(seg.metadata); ok {
-		padding1 := newPadding(paddingOpts{
-			maxLen: MaxPaddingSize(u.mtu, u.TransportProtocol(), int(das.payloadLen), 0),
-			ascii:  &asciiPaddingOpts{},
-		})
-		padding2 := newPadding(paddingOpts{
-			maxLen: MaxPaddingSize(u.mtu, u.TransportProtocol(), int(das.payloadLen), len(padding1)),
-			ascii:  &asciiPaddingOpts{},
-		})
+		var padding1, padding2 []byte
+		if das.flags&flagPathMTUProbe == 0 {
+			// The size of path MTU probe must be exact.
+			padding1 = newPadding(paddingOpts{
+				maxLen: MaxPaddingSize(mtu, u.TransportProtocol(), int(das.payloadLen), 0),
+				ascii:  &asciiPaddingOpts{},
+			})
+			padding2 = newPadding(paddingOpts{
+				maxLen: MaxPaddingSize(mtu, u.TransportProtocol(), int(das.payloadLen), len(padding1)),
+				ascii:  &asciiPaddingOpts{},
+			})
+		}
 		das.prefixLen = uint8(len(padding1))
 		das.suffixLen = uint8(len(padding2))
 		if log.IsLevelEnabled(log.TraceLevel) {
@@ -707,6 +820,7 @@ func (u *PacketUnderlay) writeOneSegment(seg *segment, addr net.Addr) error {
 }
 
 func (u *PacketUnderlay) closeIdleSessions() {
+	activeAddrs := make(map[string]struct{})
 	u.sessionMap.Range(func(k, v any) bool {
 		session := v.(*Session)
 		select {
@@ -717,11 +831,22 @@ func (u *PacketUnderlay) closeIdleSessions() {
 			}
 		default:
 		}
-		if time.Since(session.lastRXTime) > idleSessionTimeout {
+		if time.Since(session.lastRXTime) > session.deadPeerTimeoutOrDefault() {
 			log.Debugf("Found idle %v", session)
+			DeadPeerSessions.Add(1)
 			if err := u.RemoveSession(session); err != nil {
 				log.Debugf("%v RemoveSession() failed: %v", u, err)
 			}
+		} else {
+			activeAddrs[session.RemoteAddr().String()] = struct{}{}
+		}
+		return true
+	})
+
+	// Forget the path MTU of remote addresses without session.
+	u.peerMTUs.Range(func(k, _ any) bool {
+		if _, ok := activeAddrs[k.(string)]; !ok {
+			u.peerMTUs.Delete(k)
 		}
 		return true
 	})
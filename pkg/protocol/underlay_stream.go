@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"sync/atomic"
 	"time"
 
 	apicommon "github.com/enfein/mieru/v3/apis/common"
@@ -35,9 +36,13 @@ import (
 
 const (
 	streamOverhead = MetadataLength + cipher.DefaultOverhead*2
+
+	// hybridKeyExchangeTimeout is the maximum time for the client
+	// to finish the hybrid key exchange.
+	hybridKeyExchangeTimeout = 10 * time.Second
 )
 
-var streamReplayCache = replay.NewCache(4*1024*1024, cipher.KeyRefreshInterval*3)
+var streamReplayCache = replay.RegisterCache("stream", replay.DefaultCapacity, replay.DefaultExpireInterval)
 
 type StreamUnderlay struct {
 	baseUnderlay
@@ -51,7 +56,10 @@ type StreamUnderlay struct {
 	candidates []cipher.BlockCipher
 
 	// ---- server fields ----
-	users map[string]*appctlpb.User
+	users       map[string]*appctlpb.User
+	userGroups  map[string]*appctlpb.UserGroup
+	overload    *overloadState // limits and recent load of the server
+	maintenance *atomic.Bool   // if true, announce the maintenance to clients
 }
 
 var _ Underlay = &StreamUnderlay{}
@@ -154,6 +162,7 @@ func (t *StreamUnderlay) RunEventLoop(ctx context.Context) error {
 		return stderror.ErrNullPointer
 	}
 
+	handshakeDone := false
 	for {
 		select {
 		case <-ctx.Done():
@@ -162,7 +171,13 @@ func (t *StreamUnderlay) RunEventLoop(ctx context.Context) error {
 			return nil
 		default:
 		}
+		isFirstSegment := !handshakeDone
 		seg, err := t.readOneSegment()
+		if err == nil && !handshakeDone {
+			// The client is authenticated by the first segment.
+			common.HandshakeDone(t.conn)
+			handshakeDone = true
+		}
 		if err != nil {
 			errType := stderror.GetErrorType(err)
 			if errType == stderror.NO_ERROR {
@@ -220,6 +235,14 @@ func (t *StreamUnderlay) RunEventLoop(ctx context.Context) error {
 				continue
 			}
 			session.(*Session).recvChan <- seg
+		} else if isKeyExchangeProtocol(seg.metadata.Protocol()) {
+			// The key exchange can only happen before any session is opened.
+			if seg.metadata.Protocol() != keyExchangeRequest || !isFirstSegment {
+				return fmt.Errorf("unexpected %v segment", seg.metadata.Protocol())
+			}
+			if err := t.onKeyExchangeRequest(seg); err != nil {
+				return fmt.Errorf("onKeyExchangeRequest() failed: %w", err)
+			}
 		} else {
 			log.Debugf("Ignore unknown protocol %d", seg.metadata.Protocol())
 		}
@@ -242,6 +265,8 @@ func (t *StreamUnderlay) onOpenSessionRequest(seg *segment) error {
 		return fmt.Errorf("%v received open session request, but session ID %d is already used", t, sessionID)
 	}
 	session := NewSession(sessionID, false, t.MTU(), t.users)
+	session.userGroups = t.userGroups
+	session.overload = t.overload
 	t.AddSession(session, nil)
 	session.recvChan <- seg
 	t.readySessions <- session
@@ -279,6 +304,99 @@ func (t *StreamUnderlay) onCloseSession(seg *segment) error {
 	return nil
 }
 
+// exchangeHybridKey runs a hybrid X25519 + ML-KEM-768 key exchange with
+// the server. After that, the block ciphers use a key derived from both
+// the pre-shared key and the exchanged secret.
+//
+// This method is only used by proxy client. It must be called before
+// the event loop is started.
+func (t *StreamUnderlay) exchangeHybridKey(ctx context.Context) error {
+	if !t.isClient {
+		return stderror.ErrInvalidOperation
+	}
+	kex, err := cipher.NewHybridKeyExchange()
+	if err != nil {
+		return fmt.Errorf("cipher.NewHybridKeyExchange() failed: %w", err)
+	}
+	deadline := time.Now().Add(hybridKeyExchangeTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	t.conn.SetDeadline(deadline)
+	defer t.conn.SetDeadline(time.Time{})
+
+	request := &segment{
+		metadata: &dataAckStruct{
+			baseStruct: baseStruct{
+				protocol: uint8(keyExchangeRequest),
+			},
+			payloadLen: uint16(len(kex.Request())),
+		},
+		payload:   kex.Request(),
+		transport: t.TransportProtocol(),
+	}
+	if err := t.writeOneSegment(request); err != nil {
+		return fmt.Errorf("writeOneSegment() failed: %w", err)
+	}
+	response, err := t.readOneSegment()
+	if err != nil {
+		return fmt.Errorf("readOneSegment() failed: %w", err)
+	}
+	if response.metadata.Protocol() != keyExchangeResponse {
+		return fmt.Errorf("got %v segment, want %v", response.metadata.Protocol(), keyExchangeResponse)
+	}
+	secret, err := kex.Finish(response.payload)
+	if err != nil {
+		return fmt.Errorf("Finish() failed: %w", err)
+	}
+	return t.useHybridKey(secret)
+}
+
+func (t *StreamUnderlay) onKeyExchangeRequest(seg *segment) error {
+	if t.isClient {
+		return stderror.ErrInvalidOperation
+	}
+
+	payload, secret, err := cipher.HybridRespond(seg.payload)
+	if err != nil {
+		return fmt.Errorf("cipher.HybridRespond() failed: %w", err)
+	}
+	response := &segment{
+		metadata: &dataAckStruct{
+			baseStruct: baseStruct{
+				protocol: uint8(keyExchangeResponse),
+			},
+			payloadLen: uint16(len(payload)),
+		},
+		payload:   payload,
+		transport: t.TransportProtocol(),
+	}
+	if err := t.writeOneSegment(response); err != nil {
+		return fmt.Errorf("writeOneSegment() failed: %w", err)
+	}
+	return t.useHybridKey(secret)
+}
+
+// useHybridKey replaces the block ciphers with the ones derived from
+// the secret of the hybrid key exchange.
+func (t *StreamUnderlay) useHybridKey(secret []byte) error {
+	t.sendMutex.Lock()
+	defer t.sendMutex.Unlock()
+
+	send, err := cipher.HybridBlockCipher(t.send, secret)
+	if err != nil {
+		return fmt.Errorf("cipher.HybridBlockCipher() failed: %w", err)
+	}
+	recv, err := cipher.HybridBlockCipher(t.recv, secret)
+	if err != nil {
+		return fmt.Errorf("cipher.HybridBlockCipher() failed: %w", err)
+	}
+	t.send = send
+	t.recv = recv
+	HybridKeyExchanges.Add(1)
+	return nil
+}
+
 func (t *StreamUnderlay) readOneSegment() (*segment, error) {
 	var firstRead bool
 	var err error
@@ -365,7 +483,7 @@ func (t *StreamUnderlay) readOneSegment() (*segment, error) {
 			return nil, stderror.WrapErrorWithType(err, stderror.PROTOCOL_ERROR)
 		}
 		return t.readSessionSegment(ss)
-	} else if isDataAckProtocol(protocolType(p)) {
+	} else if isDataAckProtocol(protocolType(p)) || isKeyExchangeProtocol(protocolType(p)) {
 		das := &dataAckStruct{}
 		if err := das.Unmarshal(decryptedMeta); err != nil {
 			err = fmt.Errorf("Unmarshal() to dataAckStruct failed: %w", err)
@@ -508,6 +626,9 @@ func (t *StreamUnderlay) writeOneSegment(seg *segment) error {
 	t.sendMutex.Lock()
 	defer t.sendMutex.Unlock()
 
+	if !t.isClient && t.maintenance != nil && t.maintenance.Load() {
+		seg.setFlags(seg.Flags() | flagMaintenance)
+	}
 	if err := t.maybeInitSendBlockCipher(); err != nil {
 		return fmt.Errorf("maybeInitSendBlockCipher() failed: %w", err)
 	}
@@ -614,6 +735,10 @@ func (t *StreamUnderlay) maybeInitSendBlockCipher() error {
 // drainAfterError continues to read some data from the stream network connection
 // after an error happened to confuse possible attacks.
 func (t *StreamUnderlay) drainAfterError() {
+	// The drain has its own deadline. Closing the connection at the
+	// handshake timeout would make the drain time predictable.
+	common.HandshakeDone(t.conn)
+
 	// Set read deadline to avoid being blocked forever.
 	timeoutMillis := rng.IntRange(1000, 10000)
 	timeoutMillis += rng.FixedIntPerHost(50000) // Maximum 60 seconds.
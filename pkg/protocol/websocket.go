@@ -0,0 +1,306 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/cipher"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// webSocketHandshakeTimeout is the maximum time to complete
+	// the TLS and WebSocket handshakes.
+	webSocketHandshakeTimeout = 30 * time.Second
+)
+
+type webSocketConnContextKey struct{}
+
+// WebSocketOptions are the options of an underlay that tunnels
+// the mieru protocol inside WebSocket.
+type WebSocketOptions struct {
+	// Path is the HTTP path of the WebSocket endpoint.
+	// If it is empty, "/" is used.
+	Path string
+
+	// Host is the Host header of the WebSocket handshake request.
+	// It is only used by proxy client.
+	Host string
+
+	// TLSConfig enables TLS if it is not nil.
+	// Proxy client uses it to connect to the server,
+	// and proxy server uses it to accept connections.
+	TLSConfig *tls.Config
+}
+
+func (o *WebSocketOptions) path() string {
+	if o.Path == "" {
+		return "/"
+	}
+	return o.Path
+}
+
+// webSocketConn is a WebSocket connection that carries a byte stream
+// with binary frames. The addresses of the underlying network
+// connection are reported, rather than the WebSocket URLs.
+type webSocketConn struct {
+	*websocket.Conn
+	localAddr  net.Addr
+	remoteAddr net.Addr
+	netConn    net.Conn // nil if the connection is dialed by the client
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+var _ net.Conn = &webSocketConn{}
+
+func newWebSocketConn(ws *websocket.Conn, localAddr, remoteAddr net.Addr) *webSocketConn {
+	ws.PayloadType = websocket.BinaryFrame
+	return &webSocketConn{
+		Conn:       ws,
+		localAddr:  localAddr,
+		remoteAddr: remoteAddr,
+		closed:     make(chan struct{}),
+	}
+}
+
+func (c *webSocketConn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+func (c *webSocketConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// NetConn returns the network connection accepted by the server.
+func (c *webSocketConn) NetConn() net.Conn {
+	return c.netConn
+}
+
+func (c *webSocketConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.Conn.Close()
+		close(c.closed)
+	})
+	return err
+}
+
+// dialWebSocket connects to the WebSocket endpoint at the remote address.
+func dialWebSocket(ctx context.Context, dialer apicommon.Dialer, network, addr string, opts *WebSocketOptions) (net.Conn, error) {
+	host := opts.Host
+	if host == "" {
+		var err error
+		host, _, err = net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("net.SplitHostPort() failed: %w", err)
+		}
+	}
+	rawConn, err := dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("DialContext() failed: %w", err)
+	}
+	deadline := time.Now().Add(webSocketHandshakeTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	rawConn.SetDeadline(deadline)
+
+	conn := rawConn
+	scheme := "ws"
+	if opts.TLSConfig != nil {
+		tlsConfig := opts.TLSConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = host
+		}
+		if tlsConfig.ClientSessionCache == nil {
+			tlsConfig.ClientSessionCache = tlsClientSessionCache
+		}
+		tlsConn := tls.Client(rawConn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			rawConn.Close()
+			return nil, fmt.Errorf("TLS HandshakeContext() failed: %w", err)
+		}
+		conn = tlsConn
+		scheme = "wss"
+	}
+	location := &url.URL{Scheme: scheme, Host: host, Path: opts.path()}
+	origin := &url.URL{Scheme: "https", Host: host}
+	config, err := websocket.NewConfig(location.String(), origin.String())
+	if err != nil {
+		rawConn.Close()
+		return nil, fmt.Errorf("websocket.NewConfig() failed: %w", err)
+	}
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		rawConn.Close()
+		return nil, fmt.Errorf("websocket.NewClient() failed: %w", err)
+	}
+	rawConn.SetDeadline(time.Time{})
+	return newWebSocketConn(ws, rawConn.LocalAddr(), rawConn.RemoteAddr()), nil
+}
+
+// newWebSocketUnderlay connects to the WebSocket endpoint at the remote
+// address, and creates a stream underlay on top of it.
+//
+// This function is only used by proxy client.
+func newWebSocketUnderlay(ctx context.Context, dialer apicommon.Dialer, network, addr string, mtu int, block cipher.BlockCipher, opts *WebSocketOptions) (*StreamUnderlay, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("network %s is not supported by WebSocket underlay", network)
+	}
+	if block.IsStateless() {
+		return nil, fmt.Errorf("stream underlay block cipher must be stateful")
+	}
+	conn, err := dialWebSocket(ctx, dialer, network, addr, opts)
+	if err != nil {
+		return nil, err
+	}
+	t := &StreamUnderlay{
+		baseUnderlay: *newBaseUnderlay(true, mtu),
+		conn:         conn,
+		candidates:   []cipher.BlockCipher{block},
+	}
+	return t, nil
+}
+
+// webSocketListener accepts WebSocket connections from a TCP listener.
+// Other HTTP requests are served by the decoy handler if it is set,
+// otherwise requests to other HTTP paths are answered with 404 not found.
+type webSocketListener struct {
+	rawListener net.Listener
+	server      *http.Server
+	conns       chan *webSocketConn
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var _ net.Listener = &webSocketListener{}
+
+func newWebSocketListener(rawListener net.Listener, opts *WebSocketOptions, decoy http.Handler) *webSocketListener {
+	l := &webSocketListener{
+		rawListener: rawListener,
+		conns:       make(chan *webSocketConn),
+		done:        make(chan struct{}),
+	}
+	handler := http.NewServeMux()
+	webSocketHandler := websocket.Server{Handler: l.serveWebSocket}
+	if decoy != nil {
+		handler.HandleFunc(opts.path(), func(w http.ResponseWriter, r *http.Request) {
+			if !isWebSocketUpgrade(r) {
+				serveWebSocketDecoy(decoy, w, r)
+				return
+			}
+			webSocketHandler.ServeHTTP(w, r)
+		})
+		if opts.path() != "/" {
+			handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				serveWebSocketDecoy(decoy, w, r)
+			})
+		}
+	} else {
+		handler.Handle(opts.path(), webSocketHandler)
+	}
+	l.server = &http.Server{
+		Handler: handler,
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, webSocketConnContextKey{}, c)
+		},
+		ReadHeaderTimeout: webSocketHandshakeTimeout,
+		MaxHeaderBytes:    1 << 16,
+	}
+	go func() {
+		if opts.TLSConfig != nil {
+			l.server.Serve(tls.NewListener(rawListener, opts.TLSConfig))
+		} else {
+			l.server.Serve(rawListener)
+		}
+		l.Close()
+	}()
+	return l
+}
+
+func (l *webSocketListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, io.ErrClosedPipe
+	}
+}
+
+func (l *webSocketListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.done)
+		err = l.server.Close()
+	})
+	return err
+}
+
+func (l *webSocketListener) Addr() net.Addr {
+	return l.rawListener.Addr()
+}
+
+// serveWebSocketDecoy serves the HTTP request that is not a WebSocket
+// handshake by the decoy. The connection is no longer limited by the
+// handshake timeout, like the other HTTP servers.
+func serveWebSocketDecoy(decoy http.Handler, w http.ResponseWriter, r *http.Request) {
+	DecoyServed.Add(1)
+	if c, ok := r.Context().Value(webSocketConnContextKey{}).(net.Conn); ok {
+		common.HandshakeDone(c)
+	}
+	decoy.ServeHTTP(w, r)
+}
+
+// isWebSocketUpgrade returns true if the HTTP request is a WebSocket handshake.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// serveWebSocket hands over the WebSocket connection to Accept.
+// The connection is closed by the HTTP server when this returns,
+// so it waits until the connection is closed by the underlay.
+func (l *webSocketListener) serveWebSocket(ws *websocket.Conn) {
+	localAddr, remoteAddr := ws.LocalAddr(), ws.RemoteAddr()
+	c, ok := ws.Request().Context().Value(webSocketConnContextKey{}).(net.Conn)
+	if ok {
+		localAddr, remoteAddr = c.LocalAddr(), c.RemoteAddr()
+	}
+	conn := newWebSocketConn(ws, localAddr, remoteAddr)
+	conn.netConn = c
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		return
+	}
+	<-conn.closed
+}
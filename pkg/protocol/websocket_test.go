@@ -0,0 +1,147 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package protocol
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/testtool"
+)
+
+func runWebSocketServer(t *testing.T, port int, opts *WebSocketOptions) *Mux {
+	serverProperties := NewWebSocketUnderlayProperties(1400, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, nil, opts)
+	serverMux := NewMux(false).
+		SetServerUsers(users).
+		SetEndpoints([]UnderlayProperties{serverProperties})
+	testServer := testtool.NewTestHelperServer()
+	if err := serverMux.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	go testServer.Serve(serverMux)
+	t.Cleanup(func() { testServer.Close() })
+	return serverMux
+}
+
+func TestWebSocketUnderlay(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverMux := runWebSocketServer(t, port, &WebSocketOptions{Path: "/ws"})
+
+	clientProperties := NewWebSocketUnderlayProperties(1400, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, &WebSocketOptions{Path: "/ws", Host: "example.com"})
+	runClient(t, clientProperties, []byte("xiaochitang"), []byte("kuiranbudong"), 4)
+
+	status := serverMux.ExportPortBindingStatusList().GetItems()
+	if len(status) != 1 || len(status[0].GetProtocols()) != 1 || status[0].GetProtocols()[0].String() != "WEBSOCKET" {
+		t.Errorf("port binding status = %v, want a single WEBSOCKET binding", status)
+	}
+	if err := serverMux.Close(); err != nil {
+		t.Errorf("Server mux close failed: %v", err)
+	}
+}
+
+func TestWebSocketUnderlayTLS(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	cert, pool := newTestCertificate(t, "cdn.example.com")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverMux := runWebSocketServer(t, port, &WebSocketOptions{
+		Path:      "/ws",
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	})
+
+	clientProperties := NewWebSocketUnderlayProperties(1400, nil, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}, &WebSocketOptions{
+		Path:      "/ws",
+		Host:      "cdn.example.com",
+		TLSConfig: &tls.Config{RootCAs: pool},
+	})
+	runClient(t, clientProperties, []byte("xiaochitang"), []byte("kuiranbudong"), 4)
+	if err := serverMux.Close(); err != nil {
+		t.Errorf("Server mux close failed: %v", err)
+	}
+}
+
+func TestWebSocketListenerUnknownPath(t *testing.T) {
+	log.SetOutputToTest(t)
+	log.SetLevel("DEBUG")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	serverMux := runWebSocketServer(t, port, &WebSocketOptions{Path: "/ws"})
+	defer serverMux.Close()
+
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/", port))
+	if err != nil {
+		t.Fatalf("http.Get() failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status code = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+// newTestCertificate creates a self-signed certificate of the host name,
+// and a certificate pool that trusts it.
+func newTestCertificate(t *testing.T, host string) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() failed: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate() failed: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("x509.ParseCertificate() failed: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	pb "github.com/enfein/mieru/v3/pkg/replay/replaypb"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// registry holds the replay caches that are saved to the dump file,
+	// indexed by name.
+	registry     = make(map[string]*ReplayCache)
+	registryLock sync.Mutex
+
+	dumpFilePath string
+	dumpTicker   *time.Ticker
+	stopDump     chan struct{}
+	dumpLock     sync.Mutex
+)
+
+// RegisterCache creates a new replay cache with a unique name.
+// The registered replay caches are saved to the dump file,
+// so the signatures are not lost when the process restarts.
+// If the same name is registered multiple times, the first cache is returned.
+func RegisterCache(name string, capacity int, expireInterval time.Duration) *ReplayCache {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if c, ok := registry[name]; ok {
+		return c
+	}
+	c := NewCache(capacity, expireInterval)
+	registry[name] = c
+	return c
+}
+
+// SetCacheOptions updates the capacity and expire interval
+// of all the registered replay caches.
+func SetCacheOptions(capacity int, expireInterval time.Duration) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	for name, c := range registry {
+		if err := c.SetOptions(capacity, expireInterval); err != nil {
+			return fmt.Errorf("update replay cache %q failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// SetDumpFilePath sets the file to save the registered replay caches.
+func SetDumpFilePath(path string) {
+	dumpLock.Lock()
+	defer dumpLock.Unlock()
+	dumpFilePath = path
+}
+
+// LoadFromDump adds the signatures in the dump file to the registered
+// replay caches. The expired signatures are ignored.
+func LoadFromDump() error {
+	dumpLock.Lock()
+	path := dumpFilePath
+	dumpLock.Unlock()
+	if path == "" {
+		return fmt.Errorf("can't load replay cache dump: file path is not set")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("os.ReadFile() failed: %w", err)
+	}
+	dump := &pb.ReplayCacheDump{}
+	if err := proto.Unmarshal(b, dump); err != nil {
+		return fmt.Errorf("proto.Unmarshal() failed: %w", err)
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	for _, src := range dump.GetCaches() {
+		if c, ok := registry[src.GetName()]; ok {
+			c.merge(src)
+		}
+	}
+	return nil
+}
+
+// DumpNow writes the registered replay caches to the dump file.
+// The file is replaced atomically, so a crash doesn't leave a partial file.
+func DumpNow() error {
+	dumpLock.Lock()
+	path := dumpFilePath
+	dumpLock.Unlock()
+	if path == "" {
+		return fmt.Errorf("can't dump replay cache: file path is not set")
+	}
+
+	dump := &pb.ReplayCacheDump{
+		TimeUnix: proto.Int64(time.Now().Unix()),
+	}
+	registryLock.Lock()
+	for name, c := range registry {
+		dump.Caches = append(dump.Caches, c.export(name))
+	}
+	registryLock.Unlock()
+	b, err := proto.Marshal(dump)
+	if err != nil {
+		return fmt.Errorf("proto.Marshal() failed: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp() failed: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Write() failed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Close() failed: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0660); err != nil {
+		return fmt.Errorf("os.Chmod() failed: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("os.Rename() failed: %w", err)
+	}
+	return nil
+}
+
+// EnableDump saves the registered replay caches to the dump file
+// periodically with the given interval.
+func EnableDump(interval time.Duration) error {
+	if interval.Nanoseconds() <= 0 {
+		return fmt.Errorf("replay cache dump interval must be a positive time range")
+	}
+	dumpLock.Lock()
+	defer dumpLock.Unlock()
+	if dumpFilePath == "" {
+		return fmt.Errorf("can't enable replay cache dump: file path is not set")
+	}
+	if dumpTicker != nil {
+		dumpTicker.Reset(interval)
+		return nil
+	}
+	dumpTicker = time.NewTicker(interval)
+	stopDump = make(chan struct{})
+	go dumpLoop(dumpTicker, stopDump)
+	log.Infof("enabled replay cache dump with interval %v", interval)
+	return nil
+}
+
+// DisableDump stops saving the replay caches periodically.
+func DisableDump() {
+	dumpLock.Lock()
+	defer dumpLock.Unlock()
+	if dumpTicker != nil {
+		dumpTicker.Stop()
+		close(stopDump)
+		dumpTicker = nil
+		log.Infof("disabled replay cache dump")
+	}
+}
+
+func dumpLoop(ticker *time.Ticker, stop chan struct{}) {
+	for {
+		select {
+		case <-ticker.C:
+			if err := DumpNow(); err != nil {
+				log.Warnf("Replay cache DumpNow() failed: %v", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// export returns the protobuf representation of the replay cache.
+func (c *ReplayCache) export(name string) *pb.ReplayCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dst := &pb.ReplayCache{
+		Name:               proto.String(name),
+		ExpireTimeUnixNano: proto.Int64(c.expireTime.UnixNano()),
+		Current:            make([]*pb.ReplaySignature, 0, len(c.current)),
+		Previous:           make([]*pb.ReplaySignature, 0, len(c.previous)),
+	}
+	for signature, tag := range c.current {
+		dst.Current = append(dst.Current, &pb.ReplaySignature{Hash: proto.Uint64(signature), Tag: proto.String(tag)})
+	}
+	for signature, tag := range c.previous {
+		dst.Previous = append(dst.Previous, &pb.ReplaySignature{Hash: proto.Uint64(signature), Tag: proto.String(tag)})
+	}
+	return dst
+}
+
+// merge adds the signatures that are not expired to the replay cache.
+// The current set of signatures becomes the previous set after the
+// expire time, and both sets are dropped after another expire interval.
+func (c *ReplayCache) merge(src *pb.ReplayCache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expireTime := time.Unix(0, src.GetExpireTimeUnixNano())
+	now := time.Now()
+	if now.Sub(expireTime) > c.expireInterval {
+		return
+	}
+	current, previous := src.GetCurrent(), src.GetPrevious()
+	if now.After(expireTime) {
+		current, previous = nil, current
+	}
+	for _, s := range current {
+		c.current[s.GetHash()] = s.GetTag()
+	}
+	for _, s := range previous {
+		c.previous[s.GetHash()] = s.GetTag()
+	}
+}
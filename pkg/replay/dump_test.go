@@ -0,0 +1,136 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package replay_test
+
+import (
+	crand "crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/replay"
+)
+
+func TestDumpAndLoad(t *testing.T) {
+	cache := replay.RegisterCache("TestDumpAndLoad", 10, time.Minute)
+	if replay.RegisterCache("TestDumpAndLoad", 20, time.Hour) != cache {
+		t.Fatalf("RegisterCache() with the same name returns a different cache")
+	}
+	data := make([]byte, 32)
+	if _, err := crand.Read(data); err != nil {
+		t.Fatalf("rand.Read() failed: %v", err)
+	}
+	if cache.IsDuplicate(data, "tag1") {
+		t.Fatalf("IsDuplicate() = true, want false")
+	}
+
+	replay.SetDumpFilePath(filepath.Join(t.TempDir(), "replay.pb"))
+	if err := replay.DumpNow(); err != nil {
+		t.Fatalf("DumpNow() failed: %v", err)
+	}
+
+	// Simulate a restart.
+	cache.Clear()
+	if err := replay.LoadFromDump(); err != nil {
+		t.Fatalf("LoadFromDump() failed: %v", err)
+	}
+	if curr, prev := cache.Sizes(); curr != 1 || prev != 0 {
+		t.Errorf("cache sizes are %d %d, want 1 0.", curr, prev)
+	}
+	if !cache.IsDuplicate(data, replay.EmptyTag) {
+		t.Errorf("IsDuplicate() = false after loading from dump, want true")
+	}
+	if cache.IsDuplicate(data, "tag1") {
+		t.Errorf("IsDuplicate() = true with the same tag, want false")
+	}
+}
+
+func TestLoadExpiredDump(t *testing.T) {
+	cache := replay.RegisterCache("TestLoadExpiredDump", 10, 50*time.Millisecond)
+	data := make([]byte, 32)
+	if _, err := crand.Read(data); err != nil {
+		t.Fatalf("rand.Read() failed: %v", err)
+	}
+	cache.IsDuplicate(data, replay.EmptyTag)
+	replay.SetDumpFilePath(filepath.Join(t.TempDir(), "replay.pb"))
+	if err := replay.DumpNow(); err != nil {
+		t.Fatalf("DumpNow() failed: %v", err)
+	}
+
+	// The current set becomes the previous set after the expire time.
+	cache.Clear()
+	time.Sleep(75 * time.Millisecond)
+	if err := replay.LoadFromDump(); err != nil {
+		t.Fatalf("LoadFromDump() failed: %v", err)
+	}
+	if curr, prev := cache.Sizes(); curr != 0 || prev != 1 {
+		t.Errorf("cache sizes are %d %d, want 0 1.", curr, prev)
+	}
+
+	// Both sets are expired after another expire interval.
+	cache.Clear()
+	time.Sleep(100 * time.Millisecond)
+	if err := replay.LoadFromDump(); err != nil {
+		t.Fatalf("LoadFromDump() failed: %v", err)
+	}
+	if curr, prev := cache.Sizes(); curr != 0 || prev != 0 {
+		t.Errorf("cache sizes are %d %d, want 0 0.", curr, prev)
+	}
+}
+
+func TestEnableDump(t *testing.T) {
+	replay.RegisterCache("TestEnableDump", 10, time.Minute)
+	path := filepath.Join(t.TempDir(), "replay.pb")
+	replay.SetDumpFilePath(path)
+	if err := replay.EnableDump(0); err == nil {
+		t.Errorf("EnableDump() with zero interval succeeded, want error")
+	}
+	if err := replay.EnableDump(10 * time.Millisecond); err != nil {
+		t.Fatalf("EnableDump() failed: %v", err)
+	}
+	defer replay.DisableDump()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dump file is not created")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSetOptions(t *testing.T) {
+	cache := replay.NewCache(10, time.Minute)
+	if err := cache.SetOptions(-1, time.Minute); err == nil {
+		t.Errorf("SetOptions() with negative capacity succeeded, want error")
+	}
+	if err := cache.SetOptions(10, 0); err == nil {
+		t.Errorf("SetOptions() with zero expire interval succeeded, want error")
+	}
+	if err := cache.SetOptions(1, time.Minute); err != nil {
+		t.Fatalf("SetOptions() failed: %v", err)
+	}
+	a := []byte{1}
+	b := []byte{2}
+	cache.IsDuplicate(a, replay.EmptyTag)
+	cache.IsDuplicate(b, replay.EmptyTag)
+	if curr, prev := cache.Sizes(); curr != 1 || prev != 1 {
+		t.Errorf("cache sizes are %d %d, want 1 1.", curr, prev)
+	}
+}
@@ -16,15 +16,27 @@
 package replay
 
 import (
+	"fmt"
 	"hash/fnv"
 	"sync"
 	"time"
 
+	"github.com/enfein/mieru/v3/pkg/cipher"
 	"github.com/enfein/mieru/v3/pkg/metrics"
 )
 
 const (
 	EmptyTag = ""
+
+	// DefaultCapacity is the default maximum number of entries
+	// in one set of signatures.
+	DefaultCapacity = 4 * 1024 * 1024
+
+	// DefaultExpireInterval is the default interval to replace the
+	// previous set of signatures. A packet can be decrypted within
+	// 3 key refresh intervals, so the signature must be kept for
+	// at least this long.
+	DefaultExpireInterval = cipher.KeyRefreshInterval * 3
 )
 
 var (
@@ -123,6 +135,25 @@ func (c *ReplayCache) IsDuplicate(data []byte, tag string) bool {
 	return false
 }
 
+// SetOptions updates the capacity and expire interval of the replay cache.
+// The existing signatures are kept.
+func (c *ReplayCache) SetOptions(capacity int, expireInterval time.Duration) error {
+	if capacity < 0 {
+		return fmt.Errorf("replay cache capacity can't be negative")
+	}
+	if expireInterval.Nanoseconds() <= 0 {
+		return fmt.Errorf("replay cache expire interval must be a positive time range")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.expireInterval != expireInterval {
+		c.expireTime = c.expireTime.Add(expireInterval - c.expireInterval)
+	}
+	c.capacity = capacity
+	c.expireInterval = expireInterval
+	return nil
+}
+
 // Sizes returns the number of entries in `current` map and `previous` map.
 func (c *ReplayCache) Sizes() (int, int) {
 	c.mu.Lock()
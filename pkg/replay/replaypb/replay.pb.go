@@ -0,0 +1,359 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        v4.22.3
+// source: replay/proto/replay.proto
+
+package replaypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ReplayCacheDump struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Time in UNIX second when this dump is created.
+	TimeUnix *int64         `protobuf:"varint,1,opt,name=timeUnix,proto3,oneof" json:"timeUnix,omitempty"`
+	Caches   []*ReplayCache `protobuf:"bytes,2,rep,name=caches,proto3" json:"caches,omitempty"`
+}
+
+func (x *ReplayCacheDump) Reset() {
+	*x = ReplayCacheDump{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_replay_proto_replay_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayCacheDump) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayCacheDump) ProtoMessage() {}
+
+func (x *ReplayCacheDump) ProtoReflect() protoreflect.Message {
+	mi := &file_replay_proto_replay_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayCacheDump.ProtoReflect.Descriptor instead.
+func (*ReplayCacheDump) Descriptor() ([]byte, []int) {
+	return file_replay_proto_replay_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ReplayCacheDump) GetTimeUnix() int64 {
+	if x != nil && x.TimeUnix != nil {
+		return *x.TimeUnix
+	}
+	return 0
+}
+
+func (x *ReplayCacheDump) GetCaches() []*ReplayCache {
+	if x != nil {
+		return x.Caches
+	}
+	return nil
+}
+
+type ReplayCache struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the replay cache.
+	Name *string `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	// Time in UNIX nanosecond when the current set of signatures
+	// replaces the previous set of signatures.
+	ExpireTimeUnixNano *int64 `protobuf:"varint,2,opt,name=expireTimeUnixNano,proto3,oneof" json:"expireTimeUnixNano,omitempty"`
+	// The current set of signatures.
+	Current []*ReplaySignature `protobuf:"bytes,3,rep,name=current,proto3" json:"current,omitempty"`
+	// The previous set of signatures.
+	Previous []*ReplaySignature `protobuf:"bytes,4,rep,name=previous,proto3" json:"previous,omitempty"`
+}
+
+func (x *ReplayCache) Reset() {
+	*x = ReplayCache{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_replay_proto_replay_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayCache) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayCache) ProtoMessage() {}
+
+func (x *ReplayCache) ProtoReflect() protoreflect.Message {
+	mi := &file_replay_proto_replay_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayCache.ProtoReflect.Descriptor instead.
+func (*ReplayCache) Descriptor() ([]byte, []int) {
+	return file_replay_proto_replay_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReplayCache) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *ReplayCache) GetExpireTimeUnixNano() int64 {
+	if x != nil && x.ExpireTimeUnixNano != nil {
+		return *x.ExpireTimeUnixNano
+	}
+	return 0
+}
+
+func (x *ReplayCache) GetCurrent() []*ReplaySignature {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+func (x *ReplayCache) GetPrevious() []*ReplaySignature {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+type ReplaySignature struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Hash of the data.
+	Hash *uint64 `protobuf:"varint,1,opt,name=hash,proto3,oneof" json:"hash,omitempty"`
+	// Tag of the data. It can be empty.
+	Tag *string `protobuf:"bytes,2,opt,name=tag,proto3,oneof" json:"tag,omitempty"`
+}
+
+func (x *ReplaySignature) Reset() {
+	*x = ReplaySignature{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_replay_proto_replay_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplaySignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaySignature) ProtoMessage() {}
+
+func (x *ReplaySignature) ProtoReflect() protoreflect.Message {
+	mi := &file_replay_proto_replay_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaySignature.ProtoReflect.Descriptor instead.
+func (*ReplaySignature) Descriptor() ([]byte, []int) {
+	return file_replay_proto_replay_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReplaySignature) GetHash() uint64 {
+	if x != nil && x.Hash != nil {
+		return *x.Hash
+	}
+	return 0
+}
+
+func (x *ReplaySignature) GetTag() string {
+	if x != nil && x.Tag != nil {
+		return *x.Tag
+	}
+	return ""
+}
+
+var File_replay_proto_replay_proto protoreflect.FileDescriptor
+
+var file_replay_proto_replay_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72,
+	0x65, 0x70, 0x6c, 0x61, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x6d, 0x69, 0x65,
+	0x72, 0x75, 0x2e, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x22, 0x72, 0x0a, 0x0f, 0x52, 0x65, 0x70,
+	0x6c, 0x61, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x1f, 0x0a, 0x08,
+	0x74, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00,
+	0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x88, 0x01, 0x01, 0x12, 0x31, 0x0a,
+	0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x70,
+	0x6c, 0x61, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x73,
+	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x22, 0xef, 0x01,
+	0x0a, 0x0b, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x12, 0x17, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x12, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x48, 0x01, 0x52, 0x12, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x07, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d,
+	0x69, 0x65, 0x72, 0x75, 0x2e, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c,
+	0x61, 0x79, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x07, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x12, 0x39, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2e, 0x72,
+	0x65, 0x70, 0x6c, 0x61, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x22,
+	0x52, 0x0a, 0x0f, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x12, 0x17, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x48, 0x00, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x74,
+	0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x03, 0x74, 0x61, 0x67, 0x88,
+	0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x42, 0x06, 0x0a, 0x04, 0x5f,
+	0x74, 0x61, 0x67, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x65, 0x6e, 0x66, 0x65, 0x69, 0x6e, 0x2f, 0x6d, 0x69, 0x65, 0x72, 0x75, 0x2f, 0x76,
+	0x33, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x2f, 0x72, 0x65, 0x70,
+	0x6c, 0x61, 0x79, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_replay_proto_replay_proto_rawDescOnce sync.Once
+	file_replay_proto_replay_proto_rawDescData = file_replay_proto_replay_proto_rawDesc
+)
+
+func file_replay_proto_replay_proto_rawDescGZIP() []byte {
+	file_replay_proto_replay_proto_rawDescOnce.Do(func() {
+		file_replay_proto_replay_proto_rawDescData = protoimpl.X.CompressGZIP(file_replay_proto_replay_proto_rawDescData)
+	})
+	return file_replay_proto_replay_proto_rawDescData
+}
+
+var file_replay_proto_replay_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_replay_proto_replay_proto_goTypes = []interface{}{
+	(*ReplayCacheDump)(nil), // 0: mieru.replay.ReplayCacheDump
+	(*ReplayCache)(nil),     // 1: mieru.replay.ReplayCache
+	(*ReplaySignature)(nil), // 2: mieru.replay.ReplaySignature
+}
+var file_replay_proto_replay_proto_depIdxs = []int32{
+	1, // 0: mieru.replay.ReplayCacheDump.caches:type_name -> mieru.replay.ReplayCache
+	2, // 1: mieru.replay.ReplayCache.current:type_name -> mieru.replay.ReplaySignature
+	2, // 2: mieru.replay.ReplayCache.previous:type_name -> mieru.replay.ReplaySignature
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_replay_proto_replay_proto_init() }
+func file_replay_proto_replay_proto_init() {
+	if File_replay_proto_replay_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_replay_proto_replay_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayCacheDump); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_replay_proto_replay_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayCache); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_replay_proto_replay_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplaySignature); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_replay_proto_replay_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	file_replay_proto_replay_proto_msgTypes[1].OneofWrappers = []interface{}{}
+	file_replay_proto_replay_proto_msgTypes[2].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_replay_proto_replay_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_replay_proto_replay_proto_goTypes,
+		DependencyIndexes: file_replay_proto_replay_proto_depIdxs,
+		MessageInfos:      file_replay_proto_replay_proto_msgTypes,
+	}.Build()
+	File_replay_proto_replay_proto = out.File
+	file_replay_proto_replay_proto_rawDesc = nil
+	file_replay_proto_replay_proto_goTypes = nil
+	file_replay_proto_replay_proto_depIdxs = nil
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package service installs a program as a service of the operating system,
+// so it starts with the system and restarts after a crash. It uses systemd
+// in Linux, launchd in macOS, and the service control manager in Windows.
+package service
+
+import (
+	"errors"
+)
+
+// ErrUnsupported is returned if the operating system is not supported.
+var ErrUnsupported = errors.New("service management is not supported on this operating system")
+
+// Config describes the service to install.
+type Config struct {
+	// Name is the name of the service.
+	Name string
+
+	// Description is a human readable description of the service.
+	Description string
+
+	// Executable is the absolute path of the program.
+	Executable string
+
+	// Args are the arguments of the program.
+	Args []string
+
+	// Env are the environment variables of the program,
+	// in the format KEY=VALUE.
+	Env []string
+
+	// User runs the program. The user is created if it doesn't exist.
+	// It is only used in Linux. If empty, the program runs as root.
+	User string
+
+	// Directories are created and owned by User before the service is
+	// started. It is only used in Linux.
+	Directories []string
+}
+
+// State is the state of an installed service.
+type State string
+
+const (
+	StateNotInstalled State = "not installed"
+	StateStopped      State = "stopped"
+	StateRunning      State = "running"
+)
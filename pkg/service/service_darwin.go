@@ -0,0 +1,134 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// launchdDaemonDir is the directory of the launchd daemons installed by
+// Install.
+const launchdDaemonDir = "/Library/LaunchDaemons"
+
+// launchdThrottleInterval is the minimum number of seconds between two
+// starts of the program. launchd restarts the program after a crash.
+const launchdThrottleInterval = 5
+
+func launchdPlistPath(name string) string {
+	return filepath.Join(launchdDaemonDir, name+".plist")
+}
+
+// launchdPlist returns the content of the launchd property list.
+func launchdPlist(config Config) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	buf.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+	writeKey := func(key string) {
+		buf.WriteString("    <key>")
+		xml.EscapeText(&buf, []byte(key))
+		buf.WriteString("</key>\n")
+	}
+	writeString := func(indent, value string) {
+		buf.WriteString(indent + "<string>")
+		xml.EscapeText(&buf, []byte(value))
+		buf.WriteString("</string>\n")
+	}
+	writeKey("Label")
+	writeString("    ", config.Name)
+	writeKey("ProgramArguments")
+	buf.WriteString("    <array>\n")
+	for _, arg := range append([]string{config.Executable}, config.Args...) {
+		writeString("        ", arg)
+	}
+	buf.WriteString("    </array>\n")
+	if len(config.Env) > 0 {
+		writeKey("EnvironmentVariables")
+		buf.WriteString("    <dict>\n")
+		for _, kv := range config.Env {
+			k, v, _ := strings.Cut(kv, "=")
+			buf.WriteString("        <key>")
+			xml.EscapeText(&buf, []byte(k))
+			buf.WriteString("</key>\n")
+			writeString("        ", v)
+		}
+		buf.WriteString("    </dict>\n")
+	}
+	writeKey("RunAtLoad")
+	buf.WriteString("    <true/>\n")
+	writeKey("KeepAlive")
+	buf.WriteString("    <dict>\n        <key>SuccessfulExit</key>\n        <false/>\n    </dict>\n")
+	writeKey("ThrottleInterval")
+	buf.WriteString(fmt.Sprintf("    <integer>%d</integer>\n", launchdThrottleInterval))
+	writeKey("StandardOutPath")
+	writeString("    ", "/var/log/"+config.Name+".log")
+	writeKey("StandardErrorPath")
+	writeString("    ", "/var/log/"+config.Name+".log")
+	buf.WriteString("</dict>\n</plist>\n")
+	return buf.Bytes(), nil
+}
+
+// Install creates a launchd daemon and loads it.
+func Install(config Config) error {
+	path := launchdPlistPath(config.Name)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("service %s is already installed at %s", config.Name, path)
+	}
+	plist, err := launchdPlist(config)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, plist, 0644); err != nil {
+		return err
+	}
+	_, err = runCommand("launchctl", "bootstrap", "system", path)
+	return err
+}
+
+// Uninstall unloads the launchd daemon created by Install, and removes it.
+func Uninstall(name string) error {
+	path := launchdPlistPath(name)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	if _, err := runCommand("launchctl", "bootout", "system/"+name); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Status returns the state of the launchd daemon.
+func Status(name string) (State, error) {
+	if _, err := os.Stat(launchdPlistPath(name)); err != nil {
+		return StateNotInstalled, nil
+	}
+	out, err := runCommand("launchctl", "print", "system/"+name)
+	if err != nil {
+		// The daemon is not loaded.
+		return StateStopped, nil
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == "state = running" {
+			return StateRunning, nil
+		}
+	}
+	return StateStopped, nil
+}
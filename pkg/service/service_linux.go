@@ -0,0 +1,189 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// systemdUnitDir is the directory of the systemd units installed by Install.
+const systemdUnitDir = "/etc/systemd/system"
+
+// packageUnitDirs are the directories of the systemd units installed by
+// the deb and rpm packages. Those units are managed by the package manager.
+var packageUnitDirs = []string{"/lib/systemd/system", "/usr/lib/systemd/system"}
+
+// systemdUnitTemplate is the same as the unit in the deb and rpm packages.
+// systemd restarts the program 5 seconds after a crash, and gives up after
+// 5 crashes in 60 seconds.
+var systemdUnitTemplate = template.Must(template.New("unit").Parse(`[Unit]
+Description={{.Description}}
+After=network-online.target network.service networking.service NetworkManager.service systemd-networkd.service
+Wants=network-online.target
+StartLimitBurst=5
+StartLimitIntervalSec=60
+
+[Service]
+Type=exec
+{{- if .User}}
+User={{.User}}
+Group={{.User}}
+AmbientCapabilities=CAP_NET_BIND_SERVICE
+{{- end}}
+{{- range .Env}}
+Environment="{{.}}"
+{{- end}}
+{{- range .RuntimeDirectories}}
+ExecStartPre=+/usr/bin/mkdir -p {{.}}
+{{- if $.User}}
+ExecStartPre=+/usr/bin/chown -R {{$.User}}:{{$.User}} {{.}}
+{{- end}}
+ExecStartPre=+/usr/bin/chmod 775 {{.}}
+{{- end}}
+ExecStart={{.ExecStart}}
+Nice=-10
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+func systemdUnitPath(name string) string {
+	return filepath.Join(systemdUnitDir, name+".service")
+}
+
+// systemdUnit returns the content of the systemd unit. The directories
+// under /var/run are created each time the service is started, because
+// they are removed when the system is restarted.
+func systemdUnit(config Config) ([]byte, error) {
+	execStart := []string{config.Executable}
+	execStart = append(execStart, config.Args...)
+	var runtimeDirs []string
+	for _, dir := range config.Directories {
+		if strings.HasPrefix(dir, "/var/run/") || strings.HasPrefix(dir, "/run/") {
+			runtimeDirs = append(runtimeDirs, dir)
+		}
+	}
+	var buf bytes.Buffer
+	err := systemdUnitTemplate.Execute(&buf, struct {
+		Config
+		ExecStart          string
+		RuntimeDirectories []string
+	}{
+		Config:             config,
+		ExecStart:          strings.Join(execStart, " "),
+		RuntimeDirectories: runtimeDirs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Install creates a systemd unit, then enables and starts it.
+func Install(config Config) error {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return fmt.Errorf("systemd is not available: %w", err)
+	}
+	for _, dir := range append([]string{systemdUnitDir}, packageUnitDirs...) {
+		path := filepath.Join(dir, config.Name+".service")
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("service %s is already installed at %s", config.Name, path)
+		}
+	}
+	if config.User != "" {
+		if _, err := runCommand("id", config.User); err != nil {
+			if _, err := runCommand("useradd", "--no-create-home", "--user-group", config.User); err != nil {
+				return err
+			}
+		}
+	}
+	for _, dir := range config.Directories {
+		if err := os.MkdirAll(dir, 0775); err != nil {
+			return err
+		}
+		if config.User != "" {
+			if _, err := runCommand("chown", "-R", config.User+":"+config.User, dir); err != nil {
+				return err
+			}
+		}
+	}
+	unit, err := systemdUnit(config)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(systemdUnitPath(config.Name), unit, 0644); err != nil {
+		return err
+	}
+	if _, err := runCommand("systemctl", "daemon-reload"); err != nil {
+		return err
+	}
+	_, err = runCommand("systemctl", "enable", "--now", config.Name+".service")
+	return err
+}
+
+// Uninstall stops and disables the systemd unit created by Install,
+// and removes it.
+func Uninstall(name string) error {
+	path := systemdUnitPath(name)
+	if _, err := os.Stat(path); err != nil {
+		for _, dir := range packageUnitDirs {
+			if _, err := os.Stat(filepath.Join(dir, name+".service")); err == nil {
+				return fmt.Errorf("service %s is installed by the package manager, remove the package instead", name)
+			}
+		}
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	if _, err := runCommand("systemctl", "disable", "--now", name+".service"); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	_, err := runCommand("systemctl", "daemon-reload")
+	return err
+}
+
+// Status returns the state of the systemd unit.
+func Status(name string) (State, error) {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return StateNotInstalled, fmt.Errorf("systemd is not available: %w", err)
+	}
+	out, err := runCommand("systemctl", "show", "--property=LoadState,ActiveState", name+".service")
+	if err != nil {
+		return StateNotInstalled, err
+	}
+	properties := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		if k, v, found := strings.Cut(strings.TrimSpace(line), "="); found {
+			properties[k] = v
+		}
+	}
+	if properties["LoadState"] != "loaded" {
+		return StateNotInstalled, nil
+	}
+	if properties["ActiveState"] == "active" {
+		return StateRunning, nil
+	}
+	return StateStopped, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSystemdUnit(t *testing.T) {
+	unit, err := systemdUnit(Config{
+		Name:        "mita",
+		Description: "Mieru proxy server",
+		Executable:  "/usr/local/bin/mita",
+		Args:        []string{"run"},
+		Env:         []string{"MITA_LOG_NO_TIMESTAMP=true"},
+		User:        "mita",
+		Directories: []string{"/etc/mita", "/var/run/mita"},
+	})
+	if err != nil {
+		t.Fatalf("systemdUnit() failed: %v", err)
+	}
+	want := `[Unit]
+Description=Mieru proxy server
+After=network-online.target network.service networking.service NetworkManager.service systemd-networkd.service
+Wants=network-online.target
+StartLimitBurst=5
+StartLimitIntervalSec=60
+
+[Service]
+Type=exec
+User=mita
+Group=mita
+AmbientCapabilities=CAP_NET_BIND_SERVICE
+Environment="MITA_LOG_NO_TIMESTAMP=true"
+ExecStartPre=+/usr/bin/mkdir -p /var/run/mita
+ExecStartPre=+/usr/bin/chown -R mita:mita /var/run/mita
+ExecStartPre=+/usr/bin/chmod 775 /var/run/mita
+ExecStart=/usr/local/bin/mita run
+Nice=-10
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=multi-user.target
+`
+	if string(unit) != want {
+		t.Errorf("systemdUnit() = %s, want %s", unit, want)
+	}
+}
+
+func TestSystemdUnitRoot(t *testing.T) {
+	unit, err := systemdUnit(Config{
+		Name:       "mita",
+		Executable: "/usr/local/bin/mita",
+		Args:       []string{"run"},
+	})
+	if err != nil {
+		t.Fatalf("systemdUnit() failed: %v", err)
+	}
+	if strings.Contains(string(unit), "User=") || strings.Contains(string(unit), "ExecStartPre=") {
+		t.Errorf("systemdUnit() without user = %s, want no User and ExecStartPre", unit)
+	}
+}
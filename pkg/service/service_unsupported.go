@@ -0,0 +1,39 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build !darwin && !linux && !windows
+
+package service
+
+// Install installs the service and starts it.
+func Install(config Config) error {
+	return ErrUnsupported
+}
+
+// Uninstall stops the service and removes it.
+func Uninstall(name string) error {
+	return ErrUnsupported
+}
+
+// Status returns the state of the service.
+func Status(name string) (State, error) {
+	return StateNotInstalled, ErrUnsupported
+}
+
+// Run calls run. If the program is started by the service manager, stop
+// is called when the service manager stops the service.
+func Run(name string, run func() error, stop func()) error {
+	return run()
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// windowsRecoveryActions restart the program after a crash, with
+// increasing delays. The failure count is reset after the program runs
+// for windowsRecoveryResetPeriod seconds.
+var windowsRecoveryActions = []mgr.RecoveryAction{
+	{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+	{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+	{Type: mgr.ServiceRestart, Delay: 60 * time.Second},
+}
+
+const windowsRecoveryResetPeriod = 60
+
+// Install creates a Windows service, then starts it.
+func Install(config Config) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connect to service control manager failed: %w", err)
+	}
+	defer m.Disconnect()
+	if s, err := m.OpenService(config.Name); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s is already installed", config.Name)
+	}
+	s, err := m.CreateService(config.Name, config.Executable, mgr.Config{
+		DisplayName: config.Name,
+		Description: config.Description,
+		StartType:   mgr.StartAutomatic,
+	}, config.Args...)
+	if err != nil {
+		return fmt.Errorf("create service failed: %w", err)
+	}
+	defer s.Close()
+	if err := s.SetRecoveryActions(windowsRecoveryActions, windowsRecoveryResetPeriod); err != nil {
+		return fmt.Errorf("set recovery actions failed: %w", err)
+	}
+	if err := s.SetRecoveryActionsOnNonCrashFailures(true); err != nil {
+		return fmt.Errorf("set recovery actions failed: %w", err)
+	}
+	if len(config.Env) > 0 {
+		key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Services\`+config.Name, registry.SET_VALUE)
+		if err != nil {
+			return fmt.Errorf("open service registry key failed: %w", err)
+		}
+		defer key.Close()
+		if err := key.SetStringsValue("Environment", config.Env); err != nil {
+			return fmt.Errorf("set service environment variables failed: %w", err)
+		}
+	}
+	return s.Start()
+}
+
+// Uninstall stops the Windows service and deletes it.
+func Uninstall(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connect to service control manager failed: %w", err)
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	defer s.Close()
+	if status, err := s.Query(); err == nil && status.State != svc.Stopped {
+		if _, err := s.Control(svc.Stop); err != nil {
+			return fmt.Errorf("stop service failed: %w", err)
+		}
+	}
+	return s.Delete()
+}
+
+// Status returns the state of the Windows service.
+func Status(name string) (State, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return StateNotInstalled, fmt.Errorf("connect to service control manager failed: %w", err)
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(name)
+	if errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+		return StateNotInstalled, nil
+	} else if err != nil {
+		return StateNotInstalled, err
+	}
+	defer s.Close()
+	status, err := s.Query()
+	if err != nil {
+		return StateNotInstalled, err
+	}
+	if status.State == svc.Running {
+		return StateRunning, nil
+	}
+	return StateStopped, nil
+}
+
+// Run calls run. If the program is started by the service control
+// manager, it reports the service status, and stop is called when the
+// service is stopped.
+func Run(name string, run func() error, stop func()) error {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return run()
+	}
+	handler := &windowsService{run: run, stop: stop}
+	if err := svc.Run(name, handler); err != nil {
+		return err
+	}
+	return handler.err
+}
+
+// windowsService implements svc.Handler.
+type windowsService struct {
+	run  func() error
+	stop func()
+	err  error
+}
+
+func (s *windowsService) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.run()
+	}()
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case s.err = <-done:
+			if s.err != nil {
+				// A non-zero exit code triggers the recovery actions.
+				return true, 1
+			}
+			return false, 0
+		case c := <-r:
+			switch c.Cmd {
+			case svc.Interrogate:
+				changes <- c.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending}
+				s.stop()
+				s.err = <-done
+				return false, 0
+			}
+		}
+	}
+}
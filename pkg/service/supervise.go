@@ -0,0 +1,85 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+)
+
+var (
+	// minRestartDelay is the delay before the first restart.
+	minRestartDelay = time.Second
+
+	// maxRestartDelay is the maximum delay between two restarts.
+	maxRestartDelay = time.Minute
+
+	// stableRunDuration resets the restart delay if the program runs
+	// longer than it.
+	stableRunDuration = time.Minute
+)
+
+// Supervise runs the command created by newCmd, and restarts it when it
+// exits with an error. The delay before a restart doubles after each
+// crash, up to maxRestartDelay. Supervise returns when the command exits
+// successfully, or after ctx is done and the command is stopped.
+func Supervise(ctx context.Context, newCmd func() *exec.Cmd) error {
+	delay := minRestartDelay
+	for {
+		cmd := newCmd()
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		startTime := time.Now()
+		done := make(chan error, 1)
+		go func() {
+			done <- cmd.Wait()
+		}()
+
+		var err error
+		select {
+		case err = <-done:
+		case <-ctx.Done():
+			if err := cmd.Process.Signal(os.Interrupt); err != nil {
+				// os.Interrupt is not supported in Windows.
+				cmd.Process.Kill()
+			}
+			<-done
+			return nil
+		}
+		if err == nil {
+			return nil
+		}
+
+		if time.Since(startTime) >= stableRunDuration {
+			delay = minRestartDelay
+		}
+		log.Warnf("%s exited after %v: %v. Restarting in %v", cmd.Path, time.Since(startTime).Round(time.Second), err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil
+		}
+		delay *= 2
+		if delay > maxRestartDelay {
+			delay = maxRestartDelay
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess is not a real test. It is the command supervised by
+// the other tests. It fails until it has been started the given times.
+func TestHelperProcess(t *testing.T) {
+	counterFile := os.Getenv("SERVICE_TEST_COUNTER_FILE")
+	if counterFile == "" {
+		return
+	}
+	b, _ := os.ReadFile(counterFile)
+	n, _ := strconv.Atoi(string(b))
+	n++
+	os.WriteFile(counterFile, []byte(strconv.Itoa(n)), 0644)
+	succeedAfter, _ := strconv.Atoi(os.Getenv("SERVICE_TEST_SUCCEED_AFTER"))
+	if n < succeedAfter {
+		os.Exit(1)
+	}
+	if os.Getenv("SERVICE_TEST_BLOCK") != "" {
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+func helperCommand(counterFile string, succeedAfter int, block bool) func() *exec.Cmd {
+	return func() *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+		cmd.Env = append(os.Environ(),
+			"SERVICE_TEST_COUNTER_FILE="+counterFile,
+			"SERVICE_TEST_SUCCEED_AFTER="+strconv.Itoa(succeedAfter),
+		)
+		if block {
+			cmd.Env = append(cmd.Env, "SERVICE_TEST_BLOCK=1")
+		}
+		return cmd
+	}
+}
+
+func readCounter(t *testing.T, counterFile string) int {
+	b, err := os.ReadFile(counterFile)
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	n, err := strconv.Atoi(string(b))
+	if err != nil {
+		t.Fatalf("strconv.Atoi() failed: %v", err)
+	}
+	return n
+}
+
+func TestSuperviseRestartsAfterCrash(t *testing.T) {
+	oldMin, oldMax := minRestartDelay, maxRestartDelay
+	minRestartDelay, maxRestartDelay = 10*time.Millisecond, 20*time.Millisecond
+	defer func() {
+		minRestartDelay, maxRestartDelay = oldMin, oldMax
+	}()
+
+	counterFile := filepath.Join(t.TempDir(), "counter")
+	if err := Supervise(context.Background(), helperCommand(counterFile, 3, false)); err != nil {
+		t.Fatalf("Supervise() failed: %v", err)
+	}
+	if n := readCounter(t, counterFile); n != 3 {
+		t.Errorf("command is started %d times, want 3", n)
+	}
+}
+
+func TestSuperviseStopsWithContext(t *testing.T) {
+	counterFile := filepath.Join(t.TempDir(), "counter")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Supervise(ctx, helperCommand(counterFile, 0, true))
+	}()
+	time.Sleep(500 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Supervise() failed: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Supervise() doesn't return after context is done")
+	}
+	if n := readCounter(t, counterFile); n != 1 {
+		t.Errorf("command is started %d times, want 1", n)
+	}
+}
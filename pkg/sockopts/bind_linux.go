@@ -0,0 +1,44 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package sockopts
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// BindToDeviceSupported is true if BindToDevice binds the socket to
+// the network device.
+const BindToDeviceSupported = true
+
+// BindToDevice sets SO_BINDTODEVICE option to a given connection,
+// such that packets are only sent and received by the network device.
+func BindToDevice(device string) Control {
+	return func(network, address string, conn syscall.RawConn) error {
+		var err error
+		if ctrlErr := conn.Control(func(fd uintptr) { err = BindToDeviceRawErr(device)(fd) }); ctrlErr != nil {
+			return ctrlErr
+		}
+		return err
+	}
+}
+
+func BindToDeviceRawErr(device string) RawControlErr {
+	return func(fd uintptr) error {
+		return unix.BindToDevice(int(fd), device)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build !linux
+
+package sockopts
+
+import (
+	"syscall"
+)
+
+// MarkSupported is true if SetMark sets the firewall mark of the socket.
+const MarkSupported = false
+
+// SetMark does nothing in unsupported platforms.
+func SetMark(mark int) Control {
+	return func(network, address string, conn syscall.RawConn) error {
+		return nil
+	}
+}
+
+func SetMarkRawErr(mark int) RawControlErr {
+	return func(fd uintptr) error { return nil }
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package sockopts
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// TCPUserTimeoutRawErr sets TCP_USER_TIMEOUT option to a given TCP
+// connection, such that the connection is closed if the transmitted data
+// or keepalive probes are not acknowledged by the peer within the timeout.
+func TCPUserTimeoutRawErr(timeout time.Duration) RawControlErr {
+	return func(fd uintptr) error {
+		return unix.SetsockoptInt(int(fd), unix.IPPROTO_TCP, unix.TCP_USER_TIMEOUT, int(timeout.Milliseconds()))
+	}
+}
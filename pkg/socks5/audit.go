@@ -0,0 +1,156 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+var (
+	// AuditLogRecords is the number of records written to the audit log.
+	AuditLogRecords = metrics.RegisterMetric("audit log", "Records", metrics.COUNTER)
+
+	// AuditLogWriteErrors is the number of records failed to write
+	// to the audit log.
+	AuditLogWriteErrors = metrics.RegisterMetric("audit log", "WriteErrors", metrics.COUNTER)
+)
+
+// AuditRecord is a line of the audit log. It describes a socks5 request
+// received by the proxy server.
+type AuditRecord struct {
+	SessionID     uint32    `json:"session_id"`
+	User          string    `json:"user"`
+	ClientAddr    string    `json:"client_addr"`
+	Command       string    `json:"command"`
+	Destination   string    `json:"destination"`
+	Action        string    `json:"action"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	UploadBytes   int64     `json:"upload_bytes"`
+	DownloadBytes int64     `json:"download_bytes"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// AuditLog writes a JSON line for each socks5 request received by
+// the proxy server.
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.WriteCloser
+}
+
+// NewAuditLog creates an audit log that writes to w.
+func NewAuditLog(w io.WriteCloser) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Write appends a record to the audit log.
+func (a *AuditLog) Write(record *AuditRecord) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		AuditLogWriteErrors.Add(1)
+		log.Debugf("failed to encode audit record: %v", err)
+		return
+	}
+	b = append(b, '\n')
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(b); err != nil {
+		AuditLogWriteErrors.Add(1)
+		log.Debugf("failed to write audit record: %v", err)
+		return
+	}
+	AuditLogRecords.Add(1)
+}
+
+// SetWriter replaces the underlying writer and closes the previous one.
+// The records of established connections are written to the new writer.
+func (a *AuditLog) SetWriter(w io.WriteCloser) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	previous := a.w
+	a.w = w
+	return previous.Close()
+}
+
+// Close closes the underlying writer.
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.w.Close()
+}
+
+// auditConn counts the traffic of a proxied connection for the audit log.
+// Upload is the traffic read from the connection.
+type auditConn struct {
+	net.Conn
+	upload   atomic.Int64
+	download atomic.Int64
+}
+
+func (c *auditConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.upload.Add(int64(n))
+	return n, err
+}
+
+func (c *auditConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.download.Add(int64(n))
+	return n, err
+}
+
+// newAuditRecord creates the audit record of a socks5 request.
+// The end time and the traffic are filled when the request completes.
+func newAuditRecord(conn net.Conn, sessionID uint32, user string, request *Request) *AuditRecord {
+	record := &AuditRecord{
+		SessionID:   sessionID,
+		User:        user,
+		Destination: request.DstAddr.String(),
+		StartTime:   time.Now(),
+	}
+	if remoteAddr := conn.RemoteAddr(); !common.IsNilNetAddr(remoteAddr) {
+		record.ClientAddr = remoteAddr.String()
+	}
+	switch request.Command {
+	case constant.Socks5ConnectCmd:
+		record.Command = "CONNECT"
+	case constant.Socks5BindCmd:
+		record.Command = "BIND"
+	case constant.Socks5UDPAssociateCmd:
+		record.Command = "UDP_ASSOCIATE"
+	}
+	return record
+}
+
+// finish fills the result of the socks5 request to the audit record.
+func (r *AuditRecord) finish(conn *auditConn, err error) {
+	r.EndTime = time.Now()
+	r.UploadBytes = conn.upload.Load()
+	r.DownloadBytes = conn.download.Load()
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/blocklist"
+	"github.com/enfein/mieru/v3/pkg/testtool"
+)
+
+type bufferWriteCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferWriteCloser) Close() error {
+	return nil
+}
+
+func TestAuditLogBlockedRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte("ads.example.com\n"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	b := blocklist.New()
+	if err := b.Update([]string{path}, nil, 0); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	out := &bufferWriteCloser{}
+	s := &Server{
+		config: &Config{
+			AuthOpts: Auth{
+				ClientSideAuthentication: true,
+			},
+			Blocklist: b,
+			AuditLog:  NewAuditLog(out),
+		},
+	}
+
+	clientConn, serverConn := testtool.BufPipe()
+	domain := "ads.example.com"
+	req := []byte{constant.Socks5Version, constant.Socks5ConnectCmd, 0, constant.Socks5FQDNAddress, byte(len(domain))}
+	req = append(req, domain...)
+	req = append(req, 0, 80)
+	clientConn.Write(req)
+
+	records := AuditLogRecords.Load()
+	if err := s.serverServeConn(serverConn); err == nil {
+		t.Errorf("serverServeConn() returned no error")
+	}
+	if AuditLogRecords.Load() != records+1 {
+		t.Errorf("AuditLogRecords value is not changed")
+	}
+	var record AuditRecord
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+	if record.Command != "CONNECT" {
+		t.Errorf("Command = %q, want %q", record.Command, "CONNECT")
+	}
+	if record.Destination != "ads.example.com:80" {
+		t.Errorf("Destination = %q, want %q", record.Destination, "ads.example.com:80")
+	}
+	if record.Action != "BLOCK" {
+		t.Errorf("Action = %q, want %q", record.Action, "BLOCK")
+	}
+	if record.DownloadBytes != 10 {
+		t.Errorf("DownloadBytes = %d, want %d", record.DownloadBytes, 10)
+	}
+	if record.Error == "" {
+		t.Errorf("Error is empty")
+	}
+	if record.EndTime.Before(record.StartTime) {
+		t.Errorf("EndTime %v is before StartTime %v", record.EndTime, record.StartTime)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/enfein/mieru/v3/apis/constant"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
 	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/metrics"
 )
 
 // Auth provide authentication settings to socks5 server.
@@ -37,6 +38,11 @@ type Auth struct {
 	// Credential to dial an outgoing socks5 connection.
 	// If nil, username password authentication is not used.
 	EgressCredential *Credential
+
+	// Acceptor to authenticate incoming requests with GSSAPI.
+	// If nil, GSSAPI authentication is not supported.
+	// If set, requests without authentication are rejected.
+	GSSAPI *GSSAPIAcceptor
 }
 
 // Credential stores socks5 credential for user password authentication.
@@ -48,124 +54,183 @@ type Credential struct {
 	Password string
 }
 
-func (s *Server) handleAuthentication(conn net.Conn) error {
+// handleAuthentication negotiates the authentication method with the
+// socks5 client and authenticates it. It returns the connection to read
+// the socks5 request, which is different from the input connection if
+// GSSAPI integrity protection is used.
+func (s *Server) handleAuthentication(conn net.Conn) (net.Conn, error) {
 	// Read the version byte and ensure we are compatible.
 	common.SetReadTimeout(conn, s.config.HandshakeTimeout)
 	defer common.SetReadTimeout(conn, 0)
 	version := []byte{0}
 	if _, err := io.ReadFull(conn, version); err != nil {
 		HandshakeErrors.Add(1)
-		return fmt.Errorf("get socks version failed: %w", err)
+		recordNegotiationFailure(conn, NegotiationMalformed)
+		return nil, fmt.Errorf("get socks version failed: %w", err)
 	}
 	if version[0] != constant.Socks5Version {
 		HandshakeErrors.Add(1)
-		return fmt.Errorf("unsupported socks version: %v", version)
+		recordNegotiationFailure(conn, NegotiationUnsupportedVersion)
+		return nil, fmt.Errorf("unsupported socks version: %v", version)
 	}
 
 	// Authenticate the connection.
 	nAuthMethods := []byte{0}
 	if _, err := io.ReadFull(conn, nAuthMethods); err != nil {
 		HandshakeErrors.Add(1)
-		return fmt.Errorf("get number of authentication method failed: %w", err)
+		recordNegotiationFailure(conn, NegotiationMalformed)
+		return nil, fmt.Errorf("get number of authentication method failed: %w", err)
 	}
 	if nAuthMethods[0] == 0 {
 		HandshakeErrors.Add(1)
-		return fmt.Errorf("number of authentication method is 0")
+		recordNegotiationFailure(conn, NegotiationMalformed)
+		return nil, fmt.Errorf("number of authentication method is 0")
 	}
 
+	s.configMu.RLock()
+	ingressCredentials := s.config.AuthOpts.IngressCredentials
+	gssapiAcceptor := s.config.AuthOpts.GSSAPI
+	s.configMu.RUnlock()
+
 	// Collect authentication methods.
 	requestNoAuth := false
+	requestGSSAPIAuth := false
 	requestUserPassAuth := false
 	authMethods := make([]byte, nAuthMethods[0])
 	if _, err := io.ReadFull(conn, authMethods); err != nil {
 		HandshakeErrors.Add(1)
-		return fmt.Errorf("get authentication method failed: %w", err)
+		recordNegotiationFailure(conn, NegotiationMalformed)
+		return nil, fmt.Errorf("get authentication method failed: %w", err)
 	}
 	for _, method := range authMethods {
 		if method == constant.Socks5NoAuth {
 			requestNoAuth = true
 		}
+		if method == constant.Socks5GSSAPIAuth {
+			requestGSSAPIAuth = true
+		}
 		if method == constant.Socks5UserPassAuth {
 			requestUserPassAuth = true
 		}
 	}
 
+	if requestGSSAPIAuth && gssapiAcceptor != nil {
+		return s.handleGSSAPIAuthentication(conn, gssapiAcceptor)
+	}
+	if gssapiAcceptor != nil {
+		// GSSAPI authentication is required, unless user password
+		// authentication is used.
+		requestNoAuth = false
+	}
+
 	if !requestNoAuth && !requestUserPassAuth {
 		HandshakeErrors.Add(1)
+		recordNegotiationFailure(conn, NegotiationNoAcceptableAuth)
 		if _, err := conn.Write([]byte{constant.Socks5Version, constant.Socks5NoAcceptableAuth}); err != nil {
-			return fmt.Errorf("write authentication response (no acceptable methods) failed: %w", err)
+			return nil, fmt.Errorf("write authentication response (no acceptable methods) failed: %w", err)
 		}
-		return fmt.Errorf("socks5 client provided authentication is not supported by socks5 server")
+		return nil, fmt.Errorf("socks5 client provided authentication is not supported by socks5 server")
 	}
 	if requestNoAuth {
 		// Handle no authentication. This has higher priority than user password authentication.
-		if !requestUserPassAuth && len(s.config.AuthOpts.IngressCredentials) > 0 {
+		if !requestUserPassAuth && len(ingressCredentials) > 0 {
 			HandshakeErrors.Add(1)
-			return fmt.Errorf("socks5 client requested no authentication, but user and password are required by socks5 server")
+			recordNegotiationFailure(conn, NegotiationNoAcceptableAuth)
+			return nil, fmt.Errorf("socks5 client requested no authentication, but user and password are required by socks5 server")
 		}
 		if _, err := conn.Write([]byte{constant.Socks5Version, constant.Socks5NoAuth}); err != nil {
 			HandshakeErrors.Add(1)
-			return fmt.Errorf("write authentication response (no authentication required) failed: %w", err)
+			return nil, fmt.Errorf("write authentication response (no authentication required) failed: %w", err)
 		}
+		NegotiationSuccess.Add(1)
 	} else if requestUserPassAuth {
 		// Handle user password authentication.
-		if len(s.config.AuthOpts.IngressCredentials) == 0 {
+		if len(ingressCredentials) == 0 {
 			HandshakeErrors.Add(1)
-			return fmt.Errorf("there is no registered socks5 server user")
+			recordNegotiationFailure(conn, NegotiationNoAcceptableAuth)
+			return nil, fmt.Errorf("there is no registered socks5 server user")
 		}
 
 		// Tell the client to use user password authentication.
 		if _, err := conn.Write([]byte{constant.Socks5Version, constant.Socks5UserPassAuth}); err != nil {
 			HandshakeErrors.Add(1)
-			return fmt.Errorf("write user password authentication request failed: %w", err)
+			return nil, fmt.Errorf("write user password authentication request failed: %w", err)
 		}
 
 		// Get the authentication version.
 		header := []byte{0}
 		if _, err := io.ReadFull(conn, header); err != nil {
-			return fmt.Errorf("get user password authentication version failed: %w", err)
+			recordNegotiationFailure(conn, NegotiationMalformed)
+			return nil, fmt.Errorf("get user password authentication version failed: %w", err)
 		}
 		if header[0] != constant.Socks5UserPassAuthVersion {
-			return fmt.Errorf("user password authentication version %d is not supported by socks5 server", header[0])
+			recordNegotiationFailure(conn, NegotiationUnsupportedVersion)
+			return nil, fmt.Errorf("user password authentication version %d is not supported by socks5 server", header[0])
 		}
 
 		// Get user.
 		if _, err := io.ReadFull(conn, header); err != nil {
-			return fmt.Errorf("get user length failed: %w", err)
+			recordNegotiationFailure(conn, NegotiationMalformed)
+			return nil, fmt.Errorf("get user length failed: %w", err)
 		}
 		user := make([]byte, header[0])
 		if _, err := io.ReadFull(conn, user); err != nil {
-			return fmt.Errorf("read user failed: %w", err)
+			recordNegotiationFailure(conn, NegotiationMalformed)
+			return nil, fmt.Errorf("read user failed: %w", err)
 		}
 
 		// Get password.
 		if _, err := io.ReadFull(conn, header); err != nil {
-			return fmt.Errorf("get password length failed: %w", err)
+			recordNegotiationFailure(conn, NegotiationMalformed)
+			return nil, fmt.Errorf("get password length failed: %w", err)
 		}
 		password := make([]byte, header[0])
 		if _, err := io.ReadFull(conn, password); err != nil {
-			return fmt.Errorf("read password failed: %w", err)
+			recordNegotiationFailure(conn, NegotiationMalformed)
+			return nil, fmt.Errorf("read password failed: %w", err)
 		}
 
 		// Verify user and password.
 		userStr := string(user)
 		passwordStr := string(password)
-		for _, c := range s.config.AuthOpts.IngressCredentials {
+		for _, c := range ingressCredentials {
 			if c.User == userStr && c.Password == passwordStr {
 				if _, err := conn.Write([]byte{constant.Socks5UserPassAuthVersion, constant.Socks5AuthSuccess}); err != nil {
 					HandshakeErrors.Add(1)
-					return fmt.Errorf("write user password authentication success response failed: %w", err)
+					return nil, fmt.Errorf("write user password authentication success response failed: %w", err)
 				}
-				return nil
+				NegotiationSuccess.Add(1)
+				return conn, nil
 			}
 		}
 		HandshakeErrors.Add(1)
+		recordNegotiationFailure(conn, NegotiationAuthFailure)
 		if _, err := conn.Write([]byte{constant.Socks5UserPassAuthVersion, constant.Socks5AuthFailure}); err != nil {
-			return fmt.Errorf("write user password authentication failure response failed: %w", err)
+			return nil, fmt.Errorf("write user password authentication failure response failed: %w", err)
 		}
-		return fmt.Errorf("user password authentication failed: invalid user or password")
+		return nil, fmt.Errorf("user password authentication failed: invalid user or password")
+	}
+	return conn, nil
+}
+
+// recordNegotiationFailure updates the metrics of a failed socks5 negotiation
+// by the outcome and the source IP address of the connection.
+func recordNegotiationFailure(conn net.Conn, outcome metrics.Metric) {
+	outcome.Add(1)
+	var ip net.IP
+	if tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		ip = tcpAddr.IP
+	}
+	switch {
+	case ip == nil:
+		// Unable to classify the source, e.g. unix domain socket.
+	case ip.IsLoopback():
+		NegotiationFailuresFromLoopback.Add(1)
+	case ip.IsPrivate() || ip.IsLinkLocalUnicast():
+		NegotiationFailuresFromLAN.Add(1)
+	default:
+		NegotiationFailuresFromPublic.Add(1)
 	}
-	return nil
 }
 
 // dialWithAuthentication dials to another socks5 server with given credential.
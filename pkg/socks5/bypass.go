@@ -0,0 +1,369 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/enfein/mieru/v3/apis/model"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/geoip"
+	"github.com/enfein/mieru/v3/pkg/geosite"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/tracing"
+)
+
+var (
+	SplitTunnelMetricGroupName = "split tunnel"
+
+	// DirectConnections is the number of connections that bypass the proxy tunnel.
+	DirectConnections = metrics.RegisterMetric(SplitTunnelMetricGroupName, "DirectConnections", metrics.COUNTER)
+
+	// DirectUploadBytes is the number of bytes sent to destinations that bypass the proxy tunnel.
+	DirectUploadBytes = metrics.RegisterMetric(SplitTunnelMetricGroupName, "DirectUploadBytes", metrics.COUNTER)
+
+	// DirectDownloadBytes is the number of bytes received from destinations that bypass the proxy tunnel.
+	DirectDownloadBytes = metrics.RegisterMetric(SplitTunnelMetricGroupName, "DirectDownloadBytes", metrics.COUNTER)
+
+	// ProxiedUploadBytes is the number of bytes sent to destinations through the proxy tunnel.
+	ProxiedUploadBytes = metrics.RegisterMetric(SplitTunnelMetricGroupName, "ProxiedUploadBytes", metrics.COUNTER)
+
+	// ProxiedDownloadBytes is the number of bytes received from destinations through the proxy tunnel.
+	ProxiedDownloadBytes = metrics.RegisterMetric(SplitTunnelMetricGroupName, "ProxiedDownloadBytes", metrics.COUNTER)
+
+	// DirectFallbackConnections is the number of connections sent directly because the proxy tunnel is unavailable.
+	DirectFallbackConnections = metrics.RegisterMetric(SplitTunnelMetricGroupName, "DirectFallbackConnections", metrics.COUNTER)
+
+	// LocalDNSResolveErrors is the number of domain names that the client failed to resolve.
+	LocalDNSResolveErrors = metrics.RegisterMetric(SplitTunnelMetricGroupName, "LocalDNSResolveErrors", metrics.COUNTER)
+)
+
+// BypassList is the destinations that the socks5 client connects to
+// directly instead of through the proxy tunnel.
+//
+// Each rule is one of the following:
+//
+//	example.com          the domain and all its subdomains
+//	domain:example.com   same as above
+//	full:example.com     only the domain itself
+//	10.0.0.0/8           IP addresses in the CIDR range
+//	192.168.1.1          a single IP address
+//	ip:10.0.0.0/8        same as the CIDR range or IP address
+//	country:CN           IP addresses in the country
+//	category:cn          domain names in the geosite category
+//
+// Domain and category rules only match requests with a domain name,
+// and IP and country rules only match requests with an IP address.
+// Domain names are not resolved. Country and category rules need
+// the databases set by UseDatabases.
+type BypassList struct {
+	domains     map[string]struct{} // match the domain and its subdomains
+	fullDomains map[string]struct{} // match the domain only
+	ipNets      []*net.IPNet
+	countries   map[string]struct{}
+	categories  []string
+
+	geoIP   *geoip.Database
+	geosite *geosite.Database
+}
+
+// NewBypassList creates a bypass list from the rules.
+func NewBypassList(rules []string) (*BypassList, error) {
+	l := &BypassList{
+		domains:     make(map[string]struct{}),
+		fullDomains: make(map[string]struct{}),
+		countries:   make(map[string]struct{}),
+	}
+	for _, rule := range rules {
+		if err := l.Add(rule); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// Add adds a rule to the bypass list. Empty rules and comments that
+// start with "#" are ignored.
+func (l *BypassList) Add(rule string) error {
+	rule = strings.TrimSpace(rule)
+	if rule == "" || strings.HasPrefix(rule, "#") {
+		return nil
+	}
+	if ipNet, ok := parseBypassIPNet(rule); ok {
+		l.ipNets = append(l.ipNets, ipNet)
+		return nil
+	}
+	kind, value, found := strings.Cut(rule, ":")
+	if !found {
+		kind, value = "domain", rule
+	}
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch kind {
+	case "domain":
+		return l.addDomain(l.domains, rule, value)
+	case "full":
+		return l.addDomain(l.fullDomains, rule, value)
+	case "ip":
+		ipNet, ok := parseBypassIPNet(value)
+		if !ok {
+			return fmt.Errorf("bypass rule %q has invalid IP address or CIDR", rule)
+		}
+		l.ipNets = append(l.ipNets, ipNet)
+		return nil
+	case "country":
+		country := strings.ToUpper(value)
+		if len(country) != 2 || strings.Trim(country, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+			return fmt.Errorf("bypass rule %q has invalid country code", rule)
+		}
+		l.countries[country] = struct{}{}
+		return nil
+	case "category":
+		if value == "" {
+			return fmt.Errorf("bypass rule %q has no category", rule)
+		}
+		l.categories = append(l.categories, value)
+		return nil
+	default:
+		return fmt.Errorf("bypass rule %q has unknown type %q", rule, kind)
+	}
+}
+
+// Load adds the rules from the reader, one rule per line.
+func (l *BypassList) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if err := l.Add(scanner.Text()); err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Len returns the number of rules.
+func (l *BypassList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.domains) + len(l.fullDomains) + len(l.ipNets) + len(l.countries) + len(l.categories)
+}
+
+// NeedsGeoDatabases returns true if the bypass list has country
+// or category rules.
+func (l *BypassList) NeedsGeoDatabases() bool {
+	return len(l.countries) > 0 || len(l.categories) > 0
+}
+
+// UseDatabases sets the GeoIP and geosite databases to look up
+// country and category rules. It returns an error if a database
+// needed by the rules is missing, or a category is not found.
+func (l *BypassList) UseDatabases(geoIP *geoip.Database, geosite *geosite.Database) error {
+	if len(l.countries) > 0 && geoIP == nil {
+		return fmt.Errorf("bypass country rules need a GeoIP database")
+	}
+	if len(l.categories) > 0 && geosite == nil {
+		return fmt.Errorf("bypass category rules need a geosite database")
+	}
+	for _, category := range l.categories {
+		if !geosite.Contains(category) {
+			return fmt.Errorf("bypass category %q is not found in the geosite database", category)
+		}
+	}
+	l.geoIP = geoIP
+	l.geosite = geosite
+	return nil
+}
+
+// Match returns true if the destination should bypass the proxy tunnel.
+func (l *BypassList) Match(dst model.AddrSpec) bool {
+	_, matched := l.MatchRule(dst)
+	return matched
+}
+
+// MatchRule returns the rule that matches the destination, in the form
+// of "<type>:<value>". It returns false if the destination should not
+// bypass the proxy tunnel.
+func (l *BypassList) MatchRule(dst model.AddrSpec) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+	if dst.FQDN != "" {
+		domain := strings.TrimSuffix(strings.ToLower(dst.FQDN), ".")
+		if _, found := l.fullDomains[domain]; found {
+			return "full:" + domain, true
+		}
+		for suffix := domain; ; {
+			if _, found := l.domains[suffix]; found {
+				return "domain:" + suffix, true
+			}
+			var ok bool
+			if _, suffix, ok = strings.Cut(suffix, "."); !ok {
+				break
+			}
+		}
+		if l.geosite != nil {
+			for _, category := range l.categories {
+				if l.geosite.Match(category, domain) {
+					return "category:" + category, true
+				}
+			}
+		}
+		return "", false
+	}
+	for _, ipNet := range l.ipNets {
+		if ipNet.Contains(dst.IP) {
+			return "ip:" + ipNet.String(), true
+		}
+	}
+	if l.geoIP != nil && len(l.countries) > 0 {
+		country := l.geoIP.Country(dst.IP)
+		if _, found := l.countries[country]; found {
+			return "country:" + country, true
+		}
+	}
+	return "", false
+}
+
+func (l *BypassList) addDomain(domains map[string]struct{}, rule, domain string) error {
+	domain = strings.TrimSuffix(domain, ".")
+	if domain == "" || strings.ContainsAny(domain, "/: ") {
+		return fmt.Errorf("bypass rule %q has invalid domain name", rule)
+	}
+	domains[domain] = struct{}{}
+	return nil
+}
+
+// parseBypassIPNet parses an IP address or a CIDR range.
+func parseBypassIPNet(s string) (*net.IPNet, bool) {
+	if ip := net.ParseIP(s); ip != nil {
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, true
+	}
+	if _, ipNet, err := net.ParseCIDR(s); err == nil {
+		return ipNet, true
+	}
+	return nil, false
+}
+
+// bypassConnect connects to the destination of the socks5 CONNECT
+// request directly, and transfers data between the socks5 client and
+// the destination. acceptedConn is the connection before it is wrapped
+// by the authentication method.
+func (s *Server) bypassConnect(ctx context.Context, conn, acceptedConn net.Conn, dst model.AddrSpec, info Connection) error {
+	DirectConnections.Add(1)
+	_, dialSpan := tracing.Start(ctx, "destination dial", tracing.SpanKindClient)
+	target, err := (&net.Dialer{}).DialContext(ctx, "tcp", dst.String())
+	dialSpan.End(err)
+	if err != nil {
+		resp := hostUnreachable
+		if strings.Contains(err.Error(), "refused") {
+			resp = connectionRefused
+		} else if strings.Contains(err.Error(), "network is unreachable") {
+			resp = networkUnreachable
+		}
+		if err := sendReply(conn, resp, nil); err != nil {
+			return fmt.Errorf("failed to send reply: %w", err)
+		}
+		return fmt.Errorf("connect to %v directly failed: %w", dst, err)
+	}
+	defer target.Close()
+	log.WithSession(ctx).Debugf("Connected to %v directly, bypassing proxy tunnel", dst)
+	trackedTarget, untrack := s.config.Connections.track(target, info)
+	defer untrack()
+
+	local := target.LocalAddr().(*net.TCPAddr)
+	bind := model.AddrSpec{IP: local.IP, Port: local.Port}
+	if err := sendReply(conn, successReply, &bind); err != nil {
+		HandshakeErrors.Add(1)
+		return fmt.Errorf("failed to send reply: %w", err)
+	}
+	common.HandshakeDone(acceptedConn)
+	trackedTarget = common.NewFairShareConn(trackedTarget, s.config.FairShare, conn.RemoteAddr())
+	return bidiCopy(ctx, conn, &trafficCounterConn{Conn: trackedTarget, upload: DirectUploadBytes, download: DirectDownloadBytes})
+}
+
+// parseSocks5ConnReq returns the command and destination of a socks5
+// connection request.
+func parseSocks5ConnReq(connReq []byte) (byte, model.AddrSpec, error) {
+	var dst model.AddrSpec
+	if err := dst.ReadFromSocks5(bytes.NewReader(connReq[3:])); err != nil {
+		return 0, dst, fmt.Errorf("ReadFromSocks5() failed: %w", err)
+	}
+	return connReq[1], dst, nil
+}
+
+// resolveSocks5ConnReq resolves the domain name of the socks5 connection
+// request, and returns the connection request with the IP address.
+// dst is updated with the IP address. If the domain name can't be
+// resolved, a reply is sent to the socks5 client.
+func (s *Server) resolveSocks5ConnReq(ctx context.Context, conn net.Conn, connReq []byte, dst *model.AddrSpec) ([]byte, error) {
+	_, resolveSpan := tracing.Start(ctx, "dns resolve", tracing.SpanKindClient)
+	ips, err := s.config.Resolver.LookupIP(ctx, "ip", dst.FQDN)
+	resolveSpan.End(err)
+	if err != nil || len(ips) == 0 {
+		LocalDNSResolveErrors.Add(1)
+		if err := sendReply(conn, hostUnreachable, nil); err != nil {
+			return nil, fmt.Errorf("failed to send reply: %w", err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve destination %q: %w", dst.FQDN, err)
+		}
+		return nil, fmt.Errorf(stderror.IPAddressNotFound, dst.FQDN)
+	}
+	ip := common.SelectIPFromList(ips, common.PREFER_IPv4)
+	log.WithSession(ctx).Debugf("Resolved domain name %s to IP address %v locally", dst.FQDN, ip)
+	resolved := model.AddrSpec{IP: ip, Port: dst.Port}
+	var b bytes.Buffer
+	b.Write(connReq[:3])
+	if err := resolved.WriteToSocks5(&b); err != nil {
+		return nil, fmt.Errorf("WriteToSocks5() failed: %w", err)
+	}
+	*dst = resolved
+	return b.Bytes(), nil
+}
+
+// trafficCounterConn adds the traffic of the connection to the metrics.
+// Upload is the traffic sent to the connection.
+type trafficCounterConn struct {
+	net.Conn
+	upload   metrics.Metric
+	download metrics.Metric
+}
+
+func (c *trafficCounterConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.download.Add(int64(n))
+	return n, err
+}
+
+func (c *trafficCounterConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.upload.Add(int64(n))
+	return n, err
+}
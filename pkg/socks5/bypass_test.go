@@ -0,0 +1,296 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/apis/model"
+	"github.com/enfein/mieru/v3/pkg/geoip"
+	"github.com/enfein/mieru/v3/pkg/geosite"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestBypassListMatch(t *testing.T) {
+	bypass, err := NewBypassList([]string{
+		"example.com",
+		"full:www.example.org",
+		"192.168.0.0/16",
+		"ip:10.1.2.3",
+		"fd00::/8",
+	})
+	if err != nil {
+		t.Fatalf("NewBypassList() failed: %v", err)
+	}
+	if bypass.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", bypass.Len())
+	}
+
+	testCases := []struct {
+		dst  model.AddrSpec
+		want bool
+	}{
+		{model.AddrSpec{FQDN: "example.com", Port: 443}, true},
+		{model.AddrSpec{FQDN: "WWW.Example.COM.", Port: 443}, true},
+		{model.AddrSpec{FQDN: "notexample.com", Port: 443}, false},
+		{model.AddrSpec{FQDN: "www.example.org", Port: 443}, true},
+		{model.AddrSpec{FQDN: "mail.www.example.org", Port: 443}, false},
+		{model.AddrSpec{FQDN: "example.org", Port: 443}, false},
+		{model.AddrSpec{IP: net.ParseIP("192.168.1.1"), Port: 80}, true},
+		{model.AddrSpec{IP: net.ParseIP("10.1.2.3"), Port: 80}, true},
+		{model.AddrSpec{IP: net.ParseIP("10.1.2.4"), Port: 80}, false},
+		{model.AddrSpec{IP: net.ParseIP("fd12::1"), Port: 80}, true},
+		{model.AddrSpec{IP: net.ParseIP("2001:db8::1"), Port: 80}, false},
+	}
+	for _, tc := range testCases {
+		if got := bypass.Match(tc.dst); got != tc.want {
+			t.Errorf("Match(%v) = %v, want %v", tc.dst, got, tc.want)
+		}
+	}
+
+	var empty *BypassList
+	if empty.Match(model.AddrSpec{FQDN: "example.com"}) {
+		t.Errorf("nil bypass list matches a destination")
+	}
+}
+
+func TestBypassListMatchRule(t *testing.T) {
+	bypass, err := NewBypassList([]string{
+		"example.com",
+		"full:www.example.org",
+		"ip:10.1.2.3",
+	})
+	if err != nil {
+		t.Fatalf("NewBypassList() failed: %v", err)
+	}
+	testCases := []struct {
+		dst  model.AddrSpec
+		want string
+	}{
+		{model.AddrSpec{FQDN: "mail.Example.com", Port: 443}, "domain:example.com"},
+		{model.AddrSpec{FQDN: "www.example.org", Port: 443}, "full:www.example.org"},
+		{model.AddrSpec{IP: net.ParseIP("10.1.2.3"), Port: 80}, "ip:10.1.2.3/32"},
+		{model.AddrSpec{IP: net.ParseIP("10.1.2.4"), Port: 80}, ""},
+	}
+	for _, tc := range testCases {
+		got, matched := bypass.MatchRule(tc.dst)
+		if got != tc.want || matched != (tc.want != "") {
+			t.Errorf("MatchRule(%v) = %q, %v, want %q", tc.dst, got, matched, tc.want)
+		}
+	}
+}
+
+func TestBypassListLoad(t *testing.T) {
+	bypass, err := NewBypassList(nil)
+	if err != nil {
+		t.Fatalf("NewBypassList() failed: %v", err)
+	}
+	rules := `# LAN
+192.168.0.0/16
+
+domain:lan
+`
+	if err := bypass.Load(strings.NewReader(rules)); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if bypass.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", bypass.Len())
+	}
+	if !bypass.Match(model.AddrSpec{FQDN: "printer.lan"}) {
+		t.Errorf("printer.lan is not matched")
+	}
+
+	if err := bypass.Load(strings.NewReader("example.com\nregexp:.*\n")); err == nil {
+		t.Errorf("Load() with unknown rule type succeeded")
+	}
+}
+
+func TestBypassListRejectInvalidRule(t *testing.T) {
+	for _, rule := range []string{"ip:example.com", "full:", "domain:a/b", "cidr:10.0.0.0/8", "country:CHN", "category:"} {
+		if _, err := NewBypassList([]string{rule}); err == nil {
+			t.Errorf("NewBypassList() with rule %q succeeded", rule)
+		}
+	}
+}
+
+func TestBypassListGeoRules(t *testing.T) {
+	bypass, err := NewBypassList([]string{"country:cn", "category:CN"})
+	if err != nil {
+		t.Fatalf("NewBypassList() failed: %v", err)
+	}
+	if !bypass.NeedsGeoDatabases() {
+		t.Errorf("NeedsGeoDatabases() = false, want true")
+	}
+	if err := bypass.UseDatabases(nil, nil); err == nil {
+		t.Errorf("UseDatabases() without databases succeeded")
+	}
+
+	geoIP, err := geoip.Parse(strings.NewReader("1.0.1.0,1.0.3.255,CN\n"))
+	if err != nil {
+		t.Fatalf("geoip.Parse() failed: %v", err)
+	}
+	var domain, site []byte
+	domain = protowire.AppendTag(domain, 1, protowire.VarintType)
+	domain = protowire.AppendVarint(domain, 2) // domain and its subdomains
+	domain = protowire.AppendTag(domain, 2, protowire.BytesType)
+	domain = protowire.AppendString(domain, "cn")
+	site = protowire.AppendTag(site, 1, protowire.BytesType)
+	site = protowire.AppendString(site, "CN")
+	site = protowire.AppendTag(site, 2, protowire.BytesType)
+	site = protowire.AppendBytes(site, domain)
+	geositeDB, err := geosite.Parse(protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), site))
+	if err != nil {
+		t.Fatalf("geosite.Parse() failed: %v", err)
+	}
+	if err := bypass.UseDatabases(geoIP, geositeDB); err != nil {
+		t.Fatalf("UseDatabases() failed: %v", err)
+	}
+
+	testCases := []struct {
+		dst  model.AddrSpec
+		want bool
+	}{
+		{model.AddrSpec{IP: net.ParseIP("1.0.2.1"), Port: 443}, true},
+		{model.AddrSpec{IP: net.ParseIP("8.8.8.8"), Port: 443}, false},
+		{model.AddrSpec{FQDN: "www.gov.cn", Port: 443}, true},
+		{model.AddrSpec{FQDN: "example.com", Port: 443}, false},
+	}
+	for _, tc := range testCases {
+		if got := bypass.Match(tc.dst); got != tc.want {
+			t.Errorf("Match(%v) = %v, want %v", tc.dst, got, tc.want)
+		}
+	}
+
+	unknown, err := NewBypassList([]string{"category:gfw"})
+	if err != nil {
+		t.Fatalf("NewBypassList() failed: %v", err)
+	}
+	if err := unknown.UseDatabases(geoIP, geositeDB); err == nil {
+		t.Errorf("UseDatabases() with unknown category succeeded")
+	}
+}
+
+type staticResolver map[string][]net.IP
+
+func (r staticResolver) LookupIP(_ context.Context, _, host string) ([]net.IP, error) {
+	ips, found := r[host]
+	if !found {
+		return nil, fmt.Errorf("host %q not found", host)
+	}
+	return ips, nil
+}
+
+func TestResolveSocks5ConnReq(t *testing.T) {
+	s := &Server{
+		config: &Config{
+			Resolver: staticResolver{
+				"example.com": {net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1")},
+			},
+		},
+	}
+	connReq := []byte{constant.Socks5Version, constant.Socks5ConnectCmd, 0, constant.Socks5FQDNAddress, 11}
+	connReq = append(connReq, []byte("example.com")...)
+	connReq = append(connReq, 0, 80)
+	dst := model.AddrSpec{FQDN: "example.com", Port: 80}
+	got, err := s.resolveSocks5ConnReq(context.Background(), nil, connReq, &dst)
+	if err != nil {
+		t.Fatalf("resolveSocks5ConnReq() failed: %v", err)
+	}
+	want := []byte{constant.Socks5Version, constant.Socks5ConnectCmd, 0, constant.Socks5IPv4Address, 192, 0, 2, 1, 0, 80}
+	if !bytes.Equal(got, want) {
+		t.Errorf("resolveSocks5ConnReq() = %v, want %v", got, want)
+	}
+	if dst.String() != "192.0.2.1:80" {
+		t.Errorf("destination = %v, want 192.0.2.1:80", dst)
+	}
+
+	// The socks5 client gets a reply if the domain name is not resolved.
+	appConn, localConn := net.Pipe()
+	defer appConn.Close()
+	defer localConn.Close()
+	dst = model.AddrSpec{FQDN: "unknown.example.com", Port: 80}
+	go func() {
+		s.resolveSocks5ConnReq(context.Background(), localConn, connReq, &dst)
+	}()
+	resp := make([]byte, 10)
+	appConn.SetDeadline(time.Now().Add(time.Second))
+	if _, err := io.ReadFull(appConn, resp); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if resp[1] != hostUnreachable {
+		t.Errorf("reply = %d, want %d", resp[1], hostUnreachable)
+	}
+}
+
+func TestClientDirectFallback(t *testing.T) {
+	// Create a local listener as the destination target.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+
+	// The multiplexer has no proxy server, so the tunnel can't be established.
+	mux := protocol.NewMux(true)
+	defer mux.Close()
+	s, err := New(&Config{
+		UseProxy:       true,
+		AuthOpts:       Auth{ClientSideAuthentication: true},
+		ProxyMux:       mux,
+		DirectFallback: true,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	appConn, localConn := net.Pipe()
+	defer appConn.Close()
+	go s.ServeConn(localConn)
+
+	before := DirectFallbackConnections.Load()
+	req := []byte{constant.Socks5Version, 1, constant.Socks5NoAuth}
+	req = append(req, constant.Socks5Version, constant.Socks5ConnectCmd, 0, constant.Socks5IPv4Address, 127, 0, 0, 1, 0, 0)
+	binary.BigEndian.PutUint16(req[len(req)-2:], uint16(l.Addr().(*net.TCPAddr).Port))
+	appConn.SetDeadline(time.Now().Add(time.Second))
+	go appConn.Write(append(req, []byte("ping")...))
+	resp := make([]byte, 2+10+4)
+	if _, err := io.ReadFull(appConn, resp); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if resp[3] != successReply || string(resp[12:]) != "ping" {
+		t.Errorf("got %v, want success reply and echo", resp)
+	}
+	if DirectFallbackConnections.Load() != before+1 {
+		t.Errorf("DirectFallbackConnections = %d, want %d", DirectFallbackConnections.Load(), before+1)
+	}
+}
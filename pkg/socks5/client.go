@@ -132,6 +132,46 @@ func (c *Client) dialSocks5Long(targetAddr string) (conn net.Conn, udpConn *net.
 		}
 	}()
 
+	resp, err := c.handshake(ctx, conn, targetAddr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if c.CmdType == constant.Socks5UDPAssociateCmd {
+		// Get the endpoint to relay UDP packets.
+		var ip net.IP
+		var port uint16
+		switch resp[3] {
+		case constant.Socks5IPv4Address:
+			ip = net.IP(resp[4:8])
+			port = uint16(resp[8])<<8 + uint16(resp[9])
+		case constant.Socks5IPv6Address:
+			if len(resp) < 22 {
+				return nil, nil, nil, fmt.Errorf("server response is too short")
+			}
+			ip = net.IP(resp[4:20])
+			port = uint16(resp[20])<<8 + uint16(resp[21])
+		default:
+			return nil, nil, nil, fmt.Errorf("unsupported bind address")
+		}
+		proxyUDPAddr = &net.UDPAddr{IP: ip, Port: int(port)}
+
+		// Listen to a new UDP endpoint.
+		udpAddr := &net.UDPAddr{IP: net.IP{0, 0, 0, 0}, Port: 0}
+		udpConn, err = net.ListenUDP("udp4", udpAddr)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return conn, udpConn, proxyUDPAddr, nil
+	}
+
+	return conn, nil, nil, nil
+}
+
+// handshake negotiates the authentication method and sends the command
+// over an established connection to the socks5 server.
+// It returns the response of the command.
+func (c *Client) handshake(ctx context.Context, conn net.Conn, targetAddr string) ([]byte, error) {
 	// Prepare the first request.
 	var req bytes.Buffer
 	version := byte(constant.Socks5Version)
@@ -146,16 +186,15 @@ func (c *Client) dialSocks5Long(targetAddr string) (conn net.Conn, udpConn *net.
 	})
 
 	// Process the first response.
-	var resp []byte
-	resp, err = common.RoundTrip(ctx, conn, req.Bytes(), 4)
+	resp, err := common.RoundTrip(ctx, conn, req.Bytes(), 4)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	} else if len(resp) != 2 {
-		return nil, nil, nil, fmt.Errorf("server does not respond properly")
+		return nil, fmt.Errorf("server does not respond properly")
 	} else if resp[0] != version {
-		return nil, nil, nil, fmt.Errorf("server does not support socks5")
+		return nil, fmt.Errorf("server does not support socks5")
 	} else if resp[1] != method {
-		return nil, nil, nil, fmt.Errorf("socks method negotiation failed")
+		return nil, fmt.Errorf("socks method negotiation failed")
 	}
 	if c.Credential != nil {
 		version := byte(constant.Socks5UserPassAuthVersion)
@@ -168,24 +207,24 @@ func (c *Client) dialSocks5Long(targetAddr string) (conn net.Conn, udpConn *net.
 
 		resp, err = common.RoundTrip(ctx, conn, req.Bytes(), 4)
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, err
 		} else if len(resp) != 2 {
-			return nil, nil, nil, fmt.Errorf("server does not respond properly")
+			return nil, fmt.Errorf("server does not respond properly")
 		} else if resp[0] != version {
-			return nil, nil, nil, fmt.Errorf("server does not support user/password version 1")
+			return nil, fmt.Errorf("server does not support user/password version 1")
 		} else if resp[1] != constant.Socks5AuthSuccess {
-			return nil, nil, nil, fmt.Errorf("user/password login failed")
+			return nil, fmt.Errorf("user/password login failed")
 		}
 	}
 
 	// Prepare the second request.
 	host, portStr, err := net.SplitHostPort(targetAddr)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
 	portInt, err := strconv.ParseUint(portStr, 10, 16)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
 	port := uint16(portInt)
 
@@ -221,39 +260,12 @@ func (c *Client) dialSocks5Long(targetAddr string) (conn net.Conn, udpConn *net.
 	// Process the second response.
 	resp, err = common.RoundTrip(ctx, conn, req.Bytes(), 512)
 	if err != nil {
-		return
+		return nil, err
 	} else if len(resp) < 10 {
-		return nil, nil, nil, fmt.Errorf("server response is too short")
+		return nil, fmt.Errorf("server response is too short")
 	} else if resp[1] != 0 {
-		return nil, nil, nil, fmt.Errorf("socks5 connection is not successful")
+		return nil, fmt.Errorf("socks5 connection is not successful")
 	}
 
-	if c.CmdType == constant.Socks5UDPAssociateCmd {
-		// Get the endpoint to relay UDP packets.
-		var ip net.IP
-		switch resp[3] {
-		case constant.Socks5IPv4Address:
-			ip = net.IP(resp[4:8])
-			port = uint16(resp[8])<<8 + uint16(resp[9])
-		case constant.Socks5IPv6Address:
-			if len(resp) < 22 {
-				return nil, nil, nil, fmt.Errorf("server response is too short")
-			}
-			ip = net.IP(resp[4:20])
-			port = uint16(resp[20])<<8 + uint16(resp[21])
-		default:
-			return nil, nil, nil, fmt.Errorf("unsupported bind address")
-		}
-		proxyUDPAddr = &net.UDPAddr{IP: ip, Port: int(port)}
-
-		// Listen to a new UDP endpoint.
-		udpAddr := &net.UDPAddr{IP: net.IP{0, 0, 0, 0}, Port: 0}
-		udpConn, err = net.ListenUDP("udp4", udpAddr)
-		if err != nil {
-			return nil, nil, nil, err
-		}
-		return conn, udpConn, proxyUDPAddr, nil
-	}
-
-	return conn, nil, nil, nil
+	return resp, nil
 }
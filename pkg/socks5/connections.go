@@ -0,0 +1,118 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// RouteProxy means the connection is sent through the proxy tunnel.
+	RouteProxy = "PROXY"
+
+	// RouteBypass means the destination matches a bypass rule,
+	// and the connection is sent directly.
+	RouteBypass = "BYPASS"
+
+	// RouteDirectFallback means the proxy tunnel is unavailable,
+	// and the connection is sent directly.
+	RouteDirectFallback = "DIRECT_FALLBACK"
+)
+
+// Connection describes an active socks5 request of the proxy client.
+type Connection struct {
+	SessionID     uint32
+	Source        string // address of the socks5 client
+	Destination   string
+	Protocol      string // "TCP" or "UDP"
+	Route         string // one of RouteProxy, RouteBypass and RouteDirectFallback
+	Rule          string // bypass rule that matches the destination
+	StartTime     time.Time
+	UploadBytes   int64
+	DownloadBytes int64
+}
+
+// ConnectionTracker records the active socks5 requests of the proxy
+// client. It can be shared by multiple socks5 servers.
+type ConnectionTracker struct {
+	mu    sync.Mutex
+	conns map[*trackedConn]struct{}
+}
+
+// NewConnectionTracker creates an empty ConnectionTracker.
+func NewConnectionTracker() *ConnectionTracker {
+	return &ConnectionTracker{conns: make(map[*trackedConn]struct{})}
+}
+
+// List returns the active connections, sorted by the start time.
+func (t *ConnectionTracker) List() []Connection {
+	t.mu.Lock()
+	res := make([]Connection, 0, len(t.conns))
+	for c := range t.conns {
+		info := c.info
+		info.UploadBytes = c.upload.Load()
+		info.DownloadBytes = c.download.Load()
+		res = append(res, info)
+	}
+	t.mu.Unlock()
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].StartTime.Before(res[j].StartTime)
+	})
+	return res
+}
+
+// track records the connection to the proxy server or the destination
+// until the returned function is called. The traffic is counted by the
+// returned connection. It does nothing if the tracker is nil.
+func (t *ConnectionTracker) track(conn net.Conn, info Connection) (net.Conn, func()) {
+	if t == nil {
+		return conn, func() {}
+	}
+	c := &trackedConn{Conn: conn, info: info}
+	t.mu.Lock()
+	t.conns[c] = struct{}{}
+	t.mu.Unlock()
+	return c, func() {
+		t.mu.Lock()
+		delete(t.conns, c)
+		t.mu.Unlock()
+	}
+}
+
+// trackedConn counts the traffic of a tracked connection.
+// Upload is the traffic written to the connection.
+type trackedConn struct {
+	net.Conn
+	info     Connection
+	upload   atomic.Int64
+	download atomic.Int64
+}
+
+func (c *trackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.download.Add(int64(n))
+	return n, err
+}
+
+func (c *trackedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.upload.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestConnectionTracker(t *testing.T) {
+	tracker := NewConnectionTracker()
+	now := time.Now()
+
+	c1, peer1 := net.Pipe()
+	defer peer1.Close()
+	tracked1, untrack1 := tracker.track(c1, Connection{SessionID: 1, Destination: "example.com:443", Route: RouteProxy, StartTime: now})
+	c2, peer2 := net.Pipe()
+	defer peer2.Close()
+	_, untrack2 := tracker.track(c2, Connection{SessionID: 2, Destination: "10.1.2.3:80", Route: RouteBypass, Rule: "ip:10.1.2.3/32", StartTime: now.Add(-time.Minute)})
+
+	go func() {
+		io.ReadFull(peer1, make([]byte, 5))
+		peer1.Write([]byte("world!"))
+	}()
+	if _, err := tracked1.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if _, err := io.ReadFull(tracked1, make([]byte, 6)); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+
+	conns := tracker.List()
+	if len(conns) != 2 {
+		t.Fatalf("got %d connections, want 2", len(conns))
+	}
+	if conns[0].SessionID != 2 || conns[0].Rule != "ip:10.1.2.3/32" {
+		t.Errorf("the oldest connection is %+v, want session 2", conns[0])
+	}
+	if conns[1].UploadBytes != 5 || conns[1].DownloadBytes != 6 {
+		t.Errorf("upload bytes = %d, download bytes = %d, want 5 and 6", conns[1].UploadBytes, conns[1].DownloadBytes)
+	}
+
+	untrack1()
+	untrack2()
+	if len(tracker.List()) != 0 {
+		t.Errorf("connections are not removed from the tracker")
+	}
+
+	var nilTracker *ConnectionTracker
+	if conn, untrack := nilTracker.track(c1, Connection{}); conn != c1 {
+		t.Errorf("nil tracker changed the connection")
+	} else {
+		untrack()
+	}
+}
@@ -16,18 +16,35 @@
 package socks5
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net"
 	"sync/atomic"
+	"time"
 
 	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/common"
 	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
 	"github.com/enfein/mieru/v3/pkg/stderror"
 )
 
+// bidiCopy does bi-directional data copy between two connections, and logs
+// the result with the session ID in the context.
+func bidiCopy(ctx context.Context, conn1, conn2 io.ReadWriteCloser) error {
+	start := time.Now()
+	err := common.BidiCopy(conn1, conn2)
+	log.WithSession(ctx).Debugf("Data transfer completed after %v: %v", time.Since(start).Round(time.Millisecond), err)
+	return err
+}
+
 // BidiCopyUDP does bi-directional data copy between a proxy client UDP endpoint
-// and the proxy tunnel.
-func BidiCopyUDP(udpConn *net.UDPConn, tunnelConn *apicommon.PacketOverStreamTunnel) error {
+// and the proxy tunnel. The first UDP endpoint accepted by the accept function
+// is used by the association, and datagrams from other endpoints are dropped.
+// If accept is nil, any UDP endpoint can be the first one.
+func BidiCopyUDP(udpConn *net.UDPConn, tunnelConn *apicommon.PacketOverStreamTunnel, accept func(net.Addr) bool) error {
 	var addr atomic.Value
 	errCh := make(chan error, 2)
 
@@ -41,16 +58,26 @@ func BidiCopyUDP(udpConn *net.UDPConn, tunnelConn *apicommon.PacketOverStreamTun
 				errCh <- fmt.Errorf("ReadFrom UDP connection failed: %w", err)
 				break
 			}
-			UDPAssociateUploadPackets.Add(1)
-			UDPAssociateUploadBytes.Add(int64(n))
 			udpAddr := addr.Load()
 			if udpAddr == nil {
+				if accept != nil && !accept(a) {
+					UDPAssociateRejectedPackets.Add(1)
+					log.Debugf("UDP association %v dropped datagram from unexpected endpoint %s", udpConn.LocalAddr(), a.String())
+					continue
+				}
 				addr.Store(a)
 			} else if udpAddr.(net.Addr).String() != a.String() {
-				errCh <- fmt.Errorf("ReadFrom new UDP endpoint %s, first use is %s", a.String(), udpAddr.(net.Addr).String())
-				UDPAssociateErrors.Add(1)
-				break
+				UDPAssociateRejectedPackets.Add(1)
+				log.Debugf("UDP association %v dropped datagram from new endpoint %s, first use is %s", udpConn.LocalAddr(), a.String(), udpAddr.(net.Addr).String())
+				continue
 			}
+			if payloadLen := n - udpAssociateHeaderLength(buf[:n]); payloadLen > constant.Socks5UDPMaxPayload {
+				UDPAssociateOversizedPackets.Add(1)
+				log.Debugf("UDP association %v dropped %d bytes payload from %s: exceed limit %d", udpConn.LocalAddr(), payloadLen, a.String(), constant.Socks5UDPMaxPayload)
+				continue
+			}
+			UDPAssociateUploadPackets.Add(1)
+			UDPAssociateUploadBytes.Add(int64(n))
 			if _, err = tunnelConn.Write(buf[:n]); err != nil {
 				errCh <- fmt.Errorf("Write tunnel failed: %w", err)
 				break
@@ -95,3 +122,43 @@ func BidiCopyUDP(udpConn *net.UDPConn, tunnelConn *apicommon.PacketOverStreamTun
 	}
 	return nil
 }
+
+// destinationStatsConn records the traffic of a proxy connection
+// to the destination statistics.
+type destinationStatsConn struct {
+	net.Conn
+	destination string
+	stats       *metrics.DestinationStats
+}
+
+func (c *destinationStatsConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.stats.Add(c.destination, 0, int64(n))
+	return n, err
+}
+
+func (c *destinationStatsConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.stats.Add(c.destination, int64(n), 0)
+	return n, err
+}
+
+// countryStatsConn records the traffic of a connection to the destination
+// to the country statistics. Upload is the traffic sent to the destination.
+type countryStatsConn struct {
+	net.Conn
+	country string
+	stats   *metrics.DestinationStats
+}
+
+func (c *countryStatsConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.stats.Add(c.country, 0, int64(n))
+	return n, err
+}
+
+func (c *countryStatsConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.stats.Add(c.country, int64(n), 0)
+	return n, err
+}
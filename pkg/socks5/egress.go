@@ -23,7 +23,10 @@ import (
 
 	"github.com/enfein/mieru/v3/apis/constant"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
 	"github.com/enfein/mieru/v3/pkg/egress"
+	"github.com/enfein/mieru/v3/pkg/geoip"
+	"github.com/enfein/mieru/v3/pkg/geosite"
 	"github.com/enfein/mieru/v3/pkg/log"
 )
 
@@ -139,7 +142,9 @@ func (s *Server) rejectPrivateAndLoopbackIPAction(_ context.Context, in egress.I
 			Action: appctlpb.EgressAction_REJECT,
 		}
 	}
+	s.configMu.RLock()
 	user, ok := s.config.Users[userName]
+	s.configMu.RUnlock()
 	if !ok {
 		// User is not registered.
 		// By default, we reject the request.
@@ -178,8 +183,13 @@ func (s *Server) forwardToProxyAction(_ context.Context, in egress.Input) egress
 		return egress.Action{Action: appctlpb.EgressAction_DIRECT}
 	}
 
-	for _, rule := range s.config.Egress.GetRules() {
-		if s.matchEgressRule(addr, domain, rule) {
+	s.configMu.RLock()
+	egressConfig := s.egressConfigLocked(in.Env["user"])
+	geoIP := s.config.EgressGeoIP
+	site := s.config.EgressGeosite
+	s.configMu.RUnlock()
+	for _, rule := range egressConfig.GetRules() {
+		if matchEgressRule(addr, domain, rule, geoIP, site) {
 			if rule.GetAction() == appctlpb.EgressAction_PROXY {
 				allProxyNames := rule.GetProxyNames()
 				var selectedProxyName string
@@ -187,7 +197,7 @@ func (s *Server) forwardToProxyAction(_ context.Context, in egress.Input) egress
 					idx := mrand.Intn(len(allProxyNames))
 					selectedProxyName = allProxyNames[idx]
 				}
-				for _, proxy := range s.config.Egress.GetProxies() {
+				for _, proxy := range egressConfig.GetProxies() {
 					if proxy.GetName() == selectedProxyName {
 						return egress.Action{
 							Action: rule.GetAction(),
@@ -203,7 +213,10 @@ func (s *Server) forwardToProxyAction(_ context.Context, in egress.Input) egress
 	return egress.Action{Action: appctlpb.EgressAction_DIRECT}
 }
 
-func (s *Server) matchEgressRule(addr net.IP, domain string, rule *appctlpb.EgressRule) bool {
+// matchEgressRule returns true if the destination matches the rule.
+// Countries are looked up from the GeoIP database, and site categories
+// are looked up from the geosite database, if the database is not nil.
+func matchEgressRule(addr net.IP, domain string, rule *appctlpb.EgressRule, geoIP *geoip.Database, site *geosite.Database) bool {
 	if addr != nil {
 		// IP based rule
 		for _, ipRange := range rule.GetIpRanges() {
@@ -218,6 +231,14 @@ func (s *Server) matchEgressRule(addr net.IP, domain string, rule *appctlpb.Egre
 				return true
 			}
 		}
+		if geoIP != nil && len(rule.GetCountries()) > 0 {
+			country := geoIP.Country(addr)
+			for _, c := range rule.GetCountries() {
+				if strings.EqualFold(c, country) {
+					return true
+				}
+			}
+		}
 	} else if domain != "" {
 		// Domain name based rule.
 		for _, d := range rule.GetDomainNames() {
@@ -228,6 +249,37 @@ func (s *Server) matchEgressRule(addr net.IP, domain string, rule *appctlpb.Egre
 				return true
 			}
 		}
+		if site != nil {
+			for _, category := range rule.GetSiteCategories() {
+				if site.Match(category, domain) {
+					return true
+				}
+			}
+		}
 	}
 	return false
 }
+
+// egressConfigLocked returns the egress configuration of the user.
+// If the group of the user has egress configuration, it is used.
+// The caller must hold configMu.
+func (s *Server) egressConfigLocked(user string) *appctlpb.Egress {
+	if group, ok := s.config.UserGroups[user]; ok && group.Egress != nil {
+		return group.GetEgress()
+	}
+	return s.config.Egress
+}
+
+// egressLocalBinding returns the local network interface, the local IP
+// address and the firewall mark to connect to destinations and egress
+// proxies for the user.
+func (s *Server) egressLocalBinding(user string) common.LocalBinding {
+	s.configMu.RLock()
+	egressConfig := s.egressConfigLocked(user)
+	s.configMu.RUnlock()
+	return common.LocalBinding{
+		Interface: egressConfig.GetBindInterface(),
+		IP:        net.ParseIP(egressConfig.GetBindIP()),
+		Mark:      int(egressConfig.GetRoutingMark()),
+	}
+}
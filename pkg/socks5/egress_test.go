@@ -19,12 +19,16 @@ import (
 	"context"
 	crand "crypto/rand"
 	mrand "math/rand"
+	"strings"
 	"testing"
 
 	apicommon "github.com/enfein/mieru/v3/apis/common"
 	"github.com/enfein/mieru/v3/apis/constant"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
 	"github.com/enfein/mieru/v3/pkg/egress"
+	"github.com/enfein/mieru/v3/pkg/geoip"
+	"github.com/enfein/mieru/v3/pkg/geosite"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -155,6 +159,42 @@ func TestEgressRule(t *testing.T) {
 	}
 }
 
+func TestUserGroupEgressRule(t *testing.T) {
+	controller := &Server{
+		config: &Config{
+			Egress: &appctlpb.Egress{},
+			UserGroups: map[string]*appctlpb.UserGroup{
+				"dengxiaoping": {
+					Name:  proto.String("reform"),
+					Users: []string{"dengxiaoping"},
+					Egress: &appctlpb.Egress{
+						Rules: []*appctlpb.EgressRule{
+							{
+								DomainNames: []string{"google.com"},
+								Action:      appctlpb.EgressAction_REJECT.Enum(),
+							},
+						},
+					},
+				},
+			},
+			Resolver: apicommon.NilDNSResolver{},
+		},
+	}
+	groupInput := egress.Input{
+		Protocol: inputDomainName.Protocol,
+		Data:     inputDomainName.Data,
+		Env:      map[string]string{"user": "dengxiaoping"},
+	}
+	action := controller.FindAction(context.Background(), groupInput)
+	if action.Action != appctlpb.EgressAction_REJECT {
+		t.Errorf("got action %v for user in group, want REJECT", action.Action)
+	}
+	action = controller.FindAction(context.Background(), inputDomainName)
+	if action.Action != appctlpb.EgressAction_DIRECT {
+		t.Errorf("got action %v for user not in group, want DIRECT", action.Action)
+	}
+}
+
 func TestMatchEgressRule(t *testing.T) {
 	controller := &Server{
 		config: &Config{
@@ -262,3 +302,90 @@ func TestMatchEgressRule(t *testing.T) {
 		})
 	}
 }
+
+func TestGeoEgressRule(t *testing.T) {
+	geoIP, err := geoip.Parse(strings.NewReader("1.0.1.0,1.0.3.255,CN\n"))
+	if err != nil {
+		t.Fatalf("geoip.Parse() failed: %v", err)
+	}
+	var site, domain []byte
+	domain = protowire.AppendTag(domain, 1, protowire.VarintType)
+	domain = protowire.AppendVarint(domain, 2) // domain and its subdomains
+	domain = protowire.AppendTag(domain, 2, protowire.BytesType)
+	domain = protowire.AppendString(domain, "doubleclick.net")
+	site = protowire.AppendTag(site, 1, protowire.BytesType)
+	site = protowire.AppendString(site, "ADS")
+	site = protowire.AppendTag(site, 2, protowire.BytesType)
+	site = protowire.AppendBytes(site, domain)
+	geositeDB, err := geosite.Parse(protowire.AppendBytes(protowire.AppendTag(nil, 1, protowire.BytesType), site))
+	if err != nil {
+		t.Fatalf("geosite.Parse() failed: %v", err)
+	}
+	controller := &Server{
+		config: &Config{
+			Egress: &appctlpb.Egress{
+				Rules: []*appctlpb.EgressRule{
+					{
+						SiteCategories: []string{"ads"},
+						Action:         appctlpb.EgressAction_REJECT.Enum(),
+					},
+					{
+						Countries: []string{"cn"},
+						Action:    appctlpb.EgressAction_DIRECT.Enum(),
+					},
+					{
+						IpRanges:    []string{"*"},
+						DomainNames: []string{"*"},
+						Action:      appctlpb.EgressAction_REJECT.Enum(),
+					},
+				},
+			},
+			Resolver:      apicommon.NilDNSResolver{},
+			EgressGeoIP:   geoIP,
+			EgressGeosite: geositeDB,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		wantAction appctlpb.EgressAction
+	}{
+		{
+			name:       "IP in country",
+			data:       []byte{5, 1, 0, 1, 1, 0, 2, 1, 1, 187},
+			wantAction: appctlpb.EgressAction_DIRECT,
+		},
+		{
+			name:       "IP not in country",
+			data:       []byte{5, 1, 0, 1, 8, 8, 8, 8, 1, 187},
+			wantAction: appctlpb.EgressAction_REJECT,
+		},
+		{
+			name:       "Domain in site category",
+			data:       append([]byte{5, 1, 0, 3, 19}, append([]byte("ad.doubleclick.net."), 1, 187)...),
+			wantAction: appctlpb.EgressAction_REJECT,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := controller.FindAction(context.Background(), egress.Input{
+				Protocol: appctlpb.ProxyProtocol_SOCKS5_PROXY_PROTOCOL,
+				Data:     tt.data,
+			})
+			if action.Action != tt.wantAction {
+				t.Errorf("expected %v, but got %v", tt.wantAction, action.Action)
+			}
+		})
+	}
+
+	// Without databases, the country and category rules don't match.
+	controller.SetEgressGeoDatabases(nil, nil)
+	action := controller.FindAction(context.Background(), egress.Input{
+		Protocol: appctlpb.ProxyProtocol_SOCKS5_PROXY_PROTOCOL,
+		Data:     []byte{5, 1, 0, 1, 1, 0, 2, 1, 1, 187},
+	})
+	if action.Action != appctlpb.EgressAction_REJECT {
+		t.Errorf("expected %v, but got %v", appctlpb.EgressAction_REJECT, action.Action)
+	}
+}
@@ -0,0 +1,437 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net"
+	"sync"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/jcmturner/gofork/encoding/asn1"
+	"github.com/jcmturner/gokrb5/v8/asn1tools"
+	"github.com/jcmturner/gokrb5/v8/crypto"
+	"github.com/jcmturner/gokrb5/v8/gssapi"
+	"github.com/jcmturner/gokrb5/v8/iana"
+	"github.com/jcmturner/gokrb5/v8/iana/asnAppTag"
+	"github.com/jcmturner/gokrb5/v8/iana/chksumtype"
+	"github.com/jcmturner/gokrb5/v8/iana/keyusage"
+	"github.com/jcmturner/gokrb5/v8/iana/msgtype"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/messages"
+	"github.com/jcmturner/gokrb5/v8/service"
+	"github.com/jcmturner/gokrb5/v8/types"
+)
+
+const (
+	// Token IDs of the Kerberos GSSAPI mechanism defined in RFC 1964.
+	gssapiTokenIDAPReq uint16 = 0x0100
+	gssapiTokenIDAPRep uint16 = 0x0200
+
+	// Flags of RFC 4121 wrap tokens.
+	gssapiWrapFlagSentByAcceptor byte = 0x01
+	gssapiWrapFlagSealed         byte = 0x02
+
+	// gssapiMaxDataPayload is the maximum payload of a GSSAPI
+	// encapsulated data message. The wrap token, including the header
+	// and the checksum, must fit into a message of 65535 bytes.
+	gssapiMaxDataPayload = 32 * 1024
+)
+
+// GSSAPIAcceptor authenticates socks5 clients with Kerberos using the
+// GSSAPI authentication method defined in RFC 1961.
+//
+// Only the Kerberos mechanism is supported. The socks5 client must send
+// the Kerberos token directly, without SPNEGO. Per-message integrity
+// protection uses the wrap tokens defined in RFC 4121, so it requires
+// AES encryption types. Per-message confidentiality is not supported.
+type GSSAPIAcceptor struct {
+	keytab            *keytab.Keytab
+	servicePrincipal  string
+	allowedPrincipals map[string]struct{}
+	requireIntegrity  bool
+}
+
+// NewGSSAPIAcceptor returns a GSSAPI acceptor that verifies Kerberos
+// tickets with the keys in the keytab. If servicePrincipal is empty, the
+// service principal in the ticket is used to find the key. If
+// allowedPrincipals is empty, any client principal is allowed.
+func NewGSSAPIAcceptor(kt *keytab.Keytab, servicePrincipal string, allowedPrincipals []string, requireIntegrity bool) *GSSAPIAcceptor {
+	a := &GSSAPIAcceptor{
+		keytab:            kt,
+		servicePrincipal:  servicePrincipal,
+		allowedPrincipals: make(map[string]struct{}),
+		requireIntegrity:  requireIntegrity,
+	}
+	for _, p := range allowedPrincipals {
+		a.allowedPrincipals[p] = struct{}{}
+	}
+	return a
+}
+
+// gssapiContext is an established GSSAPI security context.
+type gssapiContext struct {
+	// client is the authenticated client principal with realm.
+	client string
+
+	// key protects the per-message tokens.
+	key types.EncryptionKey
+
+	// Sequence numbers of the next sent and received wrap tokens.
+	sendSeq uint64
+	recvSeq uint64
+}
+
+// acceptSecContext verifies the Kerberos AP-REQ token from the client.
+// If the client requests mutual authentication, it also returns the
+// AP-REP token to send back.
+func (a *GSSAPIAcceptor) acceptSecContext(token []byte, remoteAddr net.Addr) (*gssapiContext, []byte, error) {
+	apReq, err := unmarshalGSSAPIAPReq(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	settings := []func(*service.Settings){service.DecodePAC(false)}
+	if a.servicePrincipal != "" {
+		settings = append(settings, service.KeytabPrincipal(a.servicePrincipal))
+	}
+	if tcpAddr, ok := remoteAddr.(*net.TCPAddr); ok {
+		settings = append(settings, service.ClientAddress(types.HostAddressFromNetIP(tcpAddr.IP)))
+	}
+	ok, creds, err := service.VerifyAPREQ(apReq, service.NewSettings(a.keytab, settings...))
+	if err != nil {
+		return nil, nil, fmt.Errorf("verify Kerberos ticket failed: %w", err)
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("Kerberos ticket is not valid")
+	}
+	client := creds.CName().PrincipalNameString() + "@" + creds.Domain()
+	if len(a.allowedPrincipals) > 0 {
+		if _, found := a.allowedPrincipals[client]; !found {
+			return nil, nil, fmt.Errorf("client principal %s is not allowed", client)
+		}
+	}
+
+	auth := apReq.Authenticator
+	ctx := &gssapiContext{
+		client:  client,
+		key:     apReq.Ticket.DecryptedEncPart.Key,
+		recvSeq: uint64(auth.SeqNumber),
+		sendSeq: uint64(auth.SeqNumber),
+	}
+	if auth.SubKey.KeyType != 0 {
+		ctx.key = auth.SubKey
+	}
+	if auth.Cksum.CksumType != chksumtype.GSSAPI || len(auth.Cksum.Checksum) < 24 {
+		return nil, nil, fmt.Errorf("Kerberos authenticator doesn't have a GSSAPI checksum")
+	}
+	flags := binary.LittleEndian.Uint32(auth.Cksum.Checksum[20:24])
+	if flags&gssapi.ContextFlagMutual == 0 {
+		return ctx, nil, nil
+	}
+	seq, err := rand.Int(rand.Reader, big.NewInt(math.MaxUint32))
+	if err != nil {
+		return nil, nil, fmt.Errorf("rand.Int() failed: %w", err)
+	}
+	ctx.sendSeq = seq.Uint64() & 0x3fffffff
+	reply, err := marshalGSSAPIAPRep(apReq, int64(ctx.sendSeq))
+	if err != nil {
+		return nil, nil, err
+	}
+	return ctx, reply, nil
+}
+
+// wrap returns the integrity protected wrap token of the payload.
+func (c *gssapiContext) wrap(payload []byte) ([]byte, error) {
+	etype, err := crypto.GetEtype(c.key.KeyType)
+	if err != nil {
+		return nil, err
+	}
+	token := &gssapi.WrapToken{
+		Flags:     gssapiWrapFlagSentByAcceptor,
+		EC:        uint16(etype.GetHMACBitLength() / 8),
+		SndSeqNum: c.sendSeq,
+		Payload:   payload,
+	}
+	if err := token.SetCheckSum(c.key, keyusage.GSSAPI_ACCEPTOR_SEAL); err != nil {
+		return nil, err
+	}
+	c.sendSeq++
+	return token.Marshal()
+}
+
+// unwrap verifies the wrap token from the client and returns the payload.
+func (c *gssapiContext) unwrap(b []byte) ([]byte, error) {
+	if len(b) > gssapi.HdrLen {
+		// Undo the right rotation of the payload and the checksum.
+		rrc := int(binary.BigEndian.Uint16(b[6:8]))
+		if rrc > 0 {
+			body := b[gssapi.HdrLen:]
+			rrc %= len(body)
+			rotated := append(append([]byte{}, body[rrc:]...), body[:rrc]...)
+			b = append(append([]byte{}, b[:gssapi.HdrLen]...), rotated...)
+		}
+	}
+	var token gssapi.WrapToken
+	if err := token.Unmarshal(b, false); err != nil {
+		return nil, err
+	}
+	if token.Flags&gssapiWrapFlagSealed != 0 {
+		return nil, fmt.Errorf("GSSAPI confidentiality protection is not supported")
+	}
+	if ok, err := token.Verify(c.key, keyusage.GSSAPI_INITIATOR_SEAL); !ok {
+		return nil, err
+	}
+	if token.SndSeqNum != c.recvSeq {
+		return nil, fmt.Errorf("GSSAPI wrap token sequence number is %d, want %d", token.SndSeqNum, c.recvSeq)
+	}
+	c.recvSeq++
+	return token.Payload, nil
+}
+
+// handleGSSAPIAuthentication runs the GSSAPI authentication after the
+// method is selected. If integrity protection is negotiated, the
+// returned connection encapsulates the data in wrap tokens.
+func (s *Server) handleGSSAPIAuthentication(conn net.Conn, acceptor *GSSAPIAcceptor) (net.Conn, error) {
+	if _, err := conn.Write([]byte{constant.Socks5Version, constant.Socks5GSSAPIAuth}); err != nil {
+		HandshakeErrors.Add(1)
+		return nil, fmt.Errorf("write GSSAPI authentication request failed: %w", err)
+	}
+
+	// Establish the security context.
+	token, err := readGSSAPIMessage(conn, constant.Socks5GSSAPIAuthMessage)
+	if err != nil {
+		HandshakeErrors.Add(1)
+		recordNegotiationFailure(conn, NegotiationMalformed)
+		return nil, fmt.Errorf("read GSSAPI authentication message failed: %w", err)
+	}
+	ctx, reply, err := acceptor.acceptSecContext(token, conn.RemoteAddr())
+	if err != nil {
+		HandshakeErrors.Add(1)
+		recordNegotiationFailure(conn, NegotiationAuthFailure)
+		conn.Write([]byte{constant.Socks5GSSAPIVersion, constant.Socks5GSSAPIAbortMessage})
+		return nil, fmt.Errorf("GSSAPI authentication failed: %w", err)
+	}
+	if reply != nil {
+		if err := writeGSSAPIMessage(conn, constant.Socks5GSSAPIAuthMessage, reply); err != nil {
+			HandshakeErrors.Add(1)
+			return nil, fmt.Errorf("write GSSAPI authentication message failed: %w", err)
+		}
+	}
+
+	// Negotiate the protection level. The token is wrapped, unless the
+	// client uses the unwrapped format of NEC reference implementation.
+	token, err = readGSSAPIMessage(conn, constant.Socks5GSSAPIProtectionMessage)
+	if err != nil {
+		HandshakeErrors.Add(1)
+		recordNegotiationFailure(conn, NegotiationMalformed)
+		return nil, fmt.Errorf("read GSSAPI protection level message failed: %w", err)
+	}
+	nec := len(token) == 1
+	level := token
+	if !nec {
+		if level, err = ctx.unwrap(token); err != nil || len(level) != 1 {
+			HandshakeErrors.Add(1)
+			recordNegotiationFailure(conn, NegotiationMalformed)
+			conn.Write([]byte{constant.Socks5GSSAPIVersion, constant.Socks5GSSAPIAbortMessage})
+			return nil, fmt.Errorf("GSSAPI protection level message is invalid: %v", err)
+		}
+	}
+	selected := constant.Socks5GSSAPINoProtection
+	if acceptor.requireIntegrity {
+		if level[0] == constant.Socks5GSSAPINoProtection {
+			HandshakeErrors.Add(1)
+			recordNegotiationFailure(conn, NegotiationAuthFailure)
+			conn.Write([]byte{constant.Socks5GSSAPIVersion, constant.Socks5GSSAPIAbortMessage})
+			return nil, fmt.Errorf("GSSAPI client %s doesn't support integrity protection", ctx.client)
+		}
+		selected = constant.Socks5GSSAPIIntegrityProtection
+	}
+	reply = []byte{selected}
+	if !nec {
+		if reply, err = ctx.wrap(reply); err != nil {
+			HandshakeErrors.Add(1)
+			return nil, fmt.Errorf("wrap GSSAPI protection level failed: %w", err)
+		}
+	}
+	if err := writeGSSAPIMessage(conn, constant.Socks5GSSAPIProtectionMessage, reply); err != nil {
+		HandshakeErrors.Add(1)
+		return nil, fmt.Errorf("write GSSAPI protection level message failed: %w", err)
+	}
+	NegotiationSuccess.Add(1)
+	if selected == constant.Socks5GSSAPINoProtection {
+		return conn, nil
+	}
+	return &gssapiConn{Conn: conn, ctx: ctx}, nil
+}
+
+// gssapiConn encapsulates the data in GSSAPI wrap tokens.
+type gssapiConn struct {
+	net.Conn
+
+	ctx *gssapiContext
+
+	readBuf []byte
+
+	// writeMu serializes writes, so the sequence numbers of the
+	// sent tokens match the order on the wire.
+	writeMu sync.Mutex
+}
+
+func (c *gssapiConn) Read(b []byte) (int, error) {
+	for len(c.readBuf) == 0 {
+		token, err := readGSSAPIMessage(c.Conn, constant.Socks5GSSAPIDataMessage)
+		if err != nil {
+			return 0, err
+		}
+		if c.readBuf, err = c.ctx.unwrap(token); err != nil {
+			return 0, fmt.Errorf("GSSAPI data message is invalid: %w", err)
+		}
+	}
+	n := copy(b, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+func (c *gssapiConn) Write(b []byte) (int, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	n := 0
+	for n < len(b) {
+		payload := b[n:]
+		if len(payload) > gssapiMaxDataPayload {
+			payload = payload[:gssapiMaxDataPayload]
+		}
+		token, err := c.ctx.wrap(payload)
+		if err != nil {
+			return n, fmt.Errorf("wrap GSSAPI data message failed: %w", err)
+		}
+		if err := writeGSSAPIMessage(c.Conn, constant.Socks5GSSAPIDataMessage, token); err != nil {
+			return n, err
+		}
+		n += len(payload)
+	}
+	return n, nil
+}
+
+// readGSSAPIMessage reads a GSSAPI message of the type and returns
+// the token.
+func readGSSAPIMessage(r io.Reader, msgType byte) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if header[0] != constant.Socks5GSSAPIVersion {
+		return nil, fmt.Errorf("GSSAPI message version %d is not supported", header[0])
+	}
+	if header[1] == constant.Socks5GSSAPIAbortMessage {
+		return nil, fmt.Errorf("GSSAPI client aborted the authentication")
+	}
+	if header[1] != msgType {
+		return nil, fmt.Errorf("got GSSAPI message type %d, want %d", header[1], msgType)
+	}
+	length := make([]byte, 2)
+	if _, err := io.ReadFull(r, length); err != nil {
+		return nil, err
+	}
+	token := make([]byte, binary.BigEndian.Uint16(length))
+	if _, err := io.ReadFull(r, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// writeGSSAPIMessage writes a GSSAPI message of the type with the token.
+func writeGSSAPIMessage(w io.Writer, msgType byte, token []byte) error {
+	if len(token) > math.MaxUint16 {
+		return fmt.Errorf("GSSAPI token of %d bytes is too large", len(token))
+	}
+	b := make([]byte, 4+len(token))
+	b[0] = constant.Socks5GSSAPIVersion
+	b[1] = msgType
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(token)))
+	copy(b[4:], token)
+	_, err := w.Write(b)
+	return err
+}
+
+// unmarshalGSSAPIAPReq returns the AP-REQ in the initial context token
+// of the Kerberos mechanism.
+func unmarshalGSSAPIAPReq(token []byte) (*messages.APReq, error) {
+	var oid asn1.ObjectIdentifier
+	rest, err := asn1.UnmarshalWithParams(token, &oid, "application,explicit,tag:0")
+	if err != nil {
+		return nil, fmt.Errorf("GSSAPI token is malformed: %w", err)
+	}
+	if !oid.Equal(gssapi.OIDKRB5.OID()) {
+		return nil, fmt.Errorf("GSSAPI mechanism %s is not supported, only Kerberos is supported", oid.String())
+	}
+	if len(rest) < 2 || binary.BigEndian.Uint16(rest[:2]) != gssapiTokenIDAPReq {
+		return nil, errors.New("GSSAPI token is not a Kerberos AP-REQ")
+	}
+	var apReq messages.APReq
+	if err := apReq.Unmarshal(rest[2:]); err != nil {
+		return nil, fmt.Errorf("unmarshal Kerberos AP-REQ failed: %w", err)
+	}
+	return &apReq, nil
+}
+
+// marshalGSSAPIAPRep returns the context token of the Kerberos mechanism
+// with the AP-REP for mutual authentication.
+func marshalGSSAPIAPRep(apReq *messages.APReq, seq int64) ([]byte, error) {
+	encPart := messages.EncAPRepPart{
+		CTime:          apReq.Authenticator.CTime,
+		Cusec:          apReq.Authenticator.Cusec,
+		SequenceNumber: seq,
+	}
+	b, err := asn1.Marshal(encPart)
+	if err != nil {
+		return nil, fmt.Errorf("marshal Kerberos AP-REP encrypted part failed: %w", err)
+	}
+	b = asn1tools.AddASNAppTag(b, asnAppTag.EncAPRepPart)
+	ed, err := crypto.GetEncryptedData(b, apReq.Ticket.DecryptedEncPart.Key, keyusage.AP_REP_ENCPART, 0)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt Kerberos AP-REP failed: %w", err)
+	}
+	apRep := messages.APRep{
+		PVNO:    iana.PVNO,
+		MsgType: msgtype.KRB_AP_REP,
+		EncPart: ed,
+	}
+	b, err = asn1.Marshal(apRep)
+	if err != nil {
+		return nil, fmt.Errorf("marshal Kerberos AP-REP failed: %w", err)
+	}
+	b = asn1tools.AddASNAppTag(b, asnAppTag.APREP)
+	return marshalGSSAPIToken(gssapiTokenIDAPRep, b)
+}
+
+// marshalGSSAPIToken returns the context token of the Kerberos mechanism
+// with the token ID and the Kerberos message.
+func marshalGSSAPIToken(tokenID uint16, msg []byte) ([]byte, error) {
+	b, err := asn1.Marshal(gssapi.OIDKRB5.OID())
+	if err != nil {
+		return nil, err
+	}
+	b = binary.BigEndian.AppendUint16(b, tokenID)
+	b = append(b, msg...)
+	return asn1tools.AddASNAppTag(b, 0), nil
+}
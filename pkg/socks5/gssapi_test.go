@@ -0,0 +1,419 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"github.com/jcmturner/gofork/encoding/asn1"
+	"github.com/jcmturner/gokrb5/v8/crypto"
+	"github.com/jcmturner/gokrb5/v8/gssapi"
+	"github.com/jcmturner/gokrb5/v8/iana/chksumtype"
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/iana/keyusage"
+	"github.com/jcmturner/gokrb5/v8/iana/nametype"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/messages"
+	"github.com/jcmturner/gokrb5/v8/types"
+)
+
+const (
+	testGSSAPIRealm   = "EXAMPLE.COM"
+	testGSSAPIService = "rcmd/proxy.example.com"
+)
+
+// testGSSAPIClient is the initiator side of a GSSAPI security context.
+type testGSSAPIClient struct {
+	key     types.EncryptionKey
+	sendSeq uint64
+	recvSeq uint64
+}
+
+func newTestGSSAPIKeytab(t *testing.T) *keytab.Keytab {
+	t.Helper()
+	kt := keytab.New()
+	if err := kt.AddEntry(testGSSAPIService, testGSSAPIRealm, "service-password", time.Now(), 1, etypeID.AES256_CTS_HMAC_SHA1_96); err != nil {
+		t.Fatalf("AddEntry() failed: %v", err)
+	}
+	return kt
+}
+
+// newTestGSSAPIToken returns the initial context token of the client
+// principal, and the client side of the security context.
+func newTestGSSAPIToken(t *testing.T, kt *keytab.Keytab, client string, flags uint32) ([]byte, *testGSSAPIClient) {
+	t.Helper()
+	cname := types.NewPrincipalName(nametype.KRB_NT_PRINCIPAL, client)
+	sname := types.NewPrincipalName(nametype.KRB_NT_SRV_INST, testGSSAPIService)
+	now := time.Now().UTC()
+	tkt, sessionKey, err := messages.NewTicket(cname, testGSSAPIRealm, sname, testGSSAPIRealm, types.NewKrbFlags(), kt, etypeID.AES256_CTS_HMAC_SHA1_96, 1, now, now, now.Add(time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("NewTicket() failed: %v", err)
+	}
+	auth, err := types.NewAuthenticator(testGSSAPIRealm, cname)
+	if err != nil {
+		t.Fatalf("NewAuthenticator() failed: %v", err)
+	}
+	checksum := make([]byte, 24)
+	binary.LittleEndian.PutUint32(checksum[:4], 16)
+	binary.LittleEndian.PutUint32(checksum[20:24], flags)
+	auth.Cksum = types.Checksum{CksumType: chksumtype.GSSAPI, Checksum: checksum}
+	apReq, err := messages.NewAPReq(tkt, sessionKey, auth)
+	if err != nil {
+		t.Fatalf("NewAPReq() failed: %v", err)
+	}
+	b, err := apReq.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	token, err := marshalGSSAPIToken(gssapiTokenIDAPReq, b)
+	if err != nil {
+		t.Fatalf("marshalGSSAPIToken() failed: %v", err)
+	}
+	seq := uint64(auth.SeqNumber)
+	return token, &testGSSAPIClient{key: sessionKey, sendSeq: seq, recvSeq: seq}
+}
+
+// verifyAPRep verifies the AP-REP token from the server, and sets the
+// sequence number of the server.
+func (c *testGSSAPIClient) verifyAPRep(t *testing.T, token []byte) {
+	t.Helper()
+	var oid asn1.ObjectIdentifier
+	rest, err := asn1.UnmarshalWithParams(token, &oid, "application,explicit,tag:0")
+	if err != nil {
+		t.Fatalf("UnmarshalWithParams() failed: %v", err)
+	}
+	if binary.BigEndian.Uint16(rest[:2]) != gssapiTokenIDAPRep {
+		t.Fatalf("token ID is %x, want AP-REP", rest[:2])
+	}
+	var apRep messages.APRep
+	if err := apRep.Unmarshal(rest[2:]); err != nil {
+		t.Fatalf("APRep.Unmarshal() failed: %v", err)
+	}
+	b, err := crypto.DecryptEncPart(apRep.EncPart, c.key, keyusage.AP_REP_ENCPART)
+	if err != nil {
+		t.Fatalf("DecryptEncPart() failed: %v", err)
+	}
+	var encPart messages.EncAPRepPart
+	if err := encPart.Unmarshal(b); err != nil {
+		t.Fatalf("EncAPRepPart.Unmarshal() failed: %v", err)
+	}
+	c.recvSeq = uint64(encPart.SequenceNumber)
+}
+
+func (c *testGSSAPIClient) wrap(t *testing.T, payload []byte) []byte {
+	t.Helper()
+	token := &gssapi.WrapToken{EC: 12, SndSeqNum: c.sendSeq, Payload: payload}
+	if err := token.SetCheckSum(c.key, keyusage.GSSAPI_INITIATOR_SEAL); err != nil {
+		t.Fatalf("SetCheckSum() failed: %v", err)
+	}
+	c.sendSeq++
+	b, err := token.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	return b
+}
+
+func (c *testGSSAPIClient) unwrap(t *testing.T, b []byte) []byte {
+	t.Helper()
+	var token gssapi.WrapToken
+	if err := token.Unmarshal(b, true); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if ok, err := token.Verify(c.key, keyusage.GSSAPI_ACCEPTOR_SEAL); !ok {
+		t.Fatalf("Verify() failed: %v", err)
+	}
+	if token.SndSeqNum != c.recvSeq {
+		t.Fatalf("sequence number is %d, want %d", token.SndSeqNum, c.recvSeq)
+	}
+	c.recvSeq++
+	return token.Payload
+}
+
+// startTestGSSAPIAuthentication runs the socks5 authentication of the
+// server in the background, and sends the method selection request.
+func startTestGSSAPIAuthentication(t *testing.T, acceptor *GSSAPIAcceptor, methods ...byte) (net.Conn, chan net.Conn, chan error) {
+	t.Helper()
+	s, err := New(&Config{AuthOpts: Auth{GSSAPI: acceptor}, HandshakeTimeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	connCh := make(chan net.Conn, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		conn, err := s.handleAuthentication(server)
+		connCh <- conn
+		errCh <- err
+	}()
+	req := append([]byte{constant.Socks5Version, byte(len(methods))}, methods...)
+	if _, err := client.Write(req); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	return client, connCh, errCh
+}
+
+func TestGSSAPIAuthenticationWithIntegrity(t *testing.T) {
+	kt := newTestGSSAPIKeytab(t)
+	acceptor := NewGSSAPIAcceptor(kt, testGSSAPIService, []string{"alice@" + testGSSAPIRealm}, true)
+	client, connCh, errCh := startTestGSSAPIAuthentication(t, acceptor, constant.Socks5NoAuth, constant.Socks5GSSAPIAuth)
+
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(client, resp); err != nil {
+		t.Fatalf("ReadFull() failed: %v", err)
+	}
+	if resp[1] != constant.Socks5GSSAPIAuth {
+		t.Fatalf("selected method is %d, want GSSAPI", resp[1])
+	}
+	token, gssClient := newTestGSSAPIToken(t, kt, "alice", gssapi.ContextFlagMutual|gssapi.ContextFlagInteg)
+	if err := writeGSSAPIMessage(client, constant.Socks5GSSAPIAuthMessage, token); err != nil {
+		t.Fatalf("writeGSSAPIMessage() failed: %v", err)
+	}
+	reply, err := readGSSAPIMessage(client, constant.Socks5GSSAPIAuthMessage)
+	if err != nil {
+		t.Fatalf("readGSSAPIMessage() failed: %v", err)
+	}
+	gssClient.verifyAPRep(t, reply)
+
+	// Propose integrity and confidentiality protection.
+	if err := writeGSSAPIMessage(client, constant.Socks5GSSAPIProtectionMessage, gssClient.wrap(t, []byte{2})); err != nil {
+		t.Fatalf("writeGSSAPIMessage() failed: %v", err)
+	}
+	reply, err = readGSSAPIMessage(client, constant.Socks5GSSAPIProtectionMessage)
+	if err != nil {
+		t.Fatalf("readGSSAPIMessage() failed: %v", err)
+	}
+	if level := gssClient.unwrap(t, reply); !bytes.Equal(level, []byte{constant.Socks5GSSAPIIntegrityProtection}) {
+		t.Fatalf("selected protection level is %v, want integrity", level)
+	}
+	conn := <-connCh
+	if err := <-errCh; err != nil {
+		t.Fatalf("handleAuthentication() failed: %v", err)
+	}
+	if _, ok := conn.(*gssapiConn); !ok {
+		t.Fatalf("connection is %T, want *gssapiConn", conn)
+	}
+
+	// Transfer data in both directions.
+	go writeGSSAPIMessage(client, constant.Socks5GSSAPIDataMessage, gssClient.wrap(t, []byte("ping")))
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("Read() failed: %v", err)
+	}
+	if string(buf) != "ping" {
+		t.Errorf("server got %q, want %q", buf, "ping")
+	}
+	go conn.Write([]byte("pong"))
+	reply, err = readGSSAPIMessage(client, constant.Socks5GSSAPIDataMessage)
+	if err != nil {
+		t.Fatalf("readGSSAPIMessage() failed: %v", err)
+	}
+	if got := gssClient.unwrap(t, reply); string(got) != "pong" {
+		t.Errorf("client got %q, want %q", got, "pong")
+	}
+}
+
+func TestGSSAPIAuthenticationWithoutProtection(t *testing.T) {
+	kt := newTestGSSAPIKeytab(t)
+	acceptor := NewGSSAPIAcceptor(kt, "", nil, false)
+	client, connCh, errCh := startTestGSSAPIAuthentication(t, acceptor, constant.Socks5GSSAPIAuth)
+
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(client, resp); err != nil {
+		t.Fatalf("ReadFull() failed: %v", err)
+	}
+	token, _ := newTestGSSAPIToken(t, kt, "bob", 0)
+	if err := writeGSSAPIMessage(client, constant.Socks5GSSAPIAuthMessage, token); err != nil {
+		t.Fatalf("writeGSSAPIMessage() failed: %v", err)
+	}
+
+	// Without mutual authentication, there is no reply token.
+	// The protection level uses the unwrapped NEC format.
+	if err := writeGSSAPIMessage(client, constant.Socks5GSSAPIProtectionMessage, []byte{1}); err != nil {
+		t.Fatalf("writeGSSAPIMessage() failed: %v", err)
+	}
+	reply, err := readGSSAPIMessage(client, constant.Socks5GSSAPIProtectionMessage)
+	if err != nil {
+		t.Fatalf("readGSSAPIMessage() failed: %v", err)
+	}
+	if !bytes.Equal(reply, []byte{constant.Socks5GSSAPINoProtection}) {
+		t.Errorf("selected protection level is %v, want no protection", reply)
+	}
+	conn := <-connCh
+	if err := <-errCh; err != nil {
+		t.Fatalf("handleAuthentication() failed: %v", err)
+	}
+	if _, ok := conn.(*gssapiConn); ok {
+		t.Errorf("connection is *gssapiConn, want the input connection")
+	}
+}
+
+func TestGSSAPIAuthenticationRejected(t *testing.T) {
+	kt := newTestGSSAPIKeytab(t)
+	acceptor := NewGSSAPIAcceptor(kt, testGSSAPIService, []string{"alice@" + testGSSAPIRealm}, false)
+
+	t.Run("principal not allowed", func(t *testing.T) {
+		client, _, errCh := startTestGSSAPIAuthentication(t, acceptor, constant.Socks5GSSAPIAuth)
+		resp := make([]byte, 2)
+		if _, err := io.ReadFull(client, resp); err != nil {
+			t.Fatalf("ReadFull() failed: %v", err)
+		}
+		token, _ := newTestGSSAPIToken(t, kt, "mallory", 0)
+		if err := writeGSSAPIMessage(client, constant.Socks5GSSAPIAuthMessage, token); err != nil {
+			t.Fatalf("writeGSSAPIMessage() failed: %v", err)
+		}
+		if _, err := io.ReadFull(client, resp); err != nil {
+			t.Fatalf("ReadFull() failed: %v", err)
+		}
+		if resp[1] != constant.Socks5GSSAPIAbortMessage {
+			t.Errorf("got message type %d, want abort", resp[1])
+		}
+		if err := <-errCh; err == nil {
+			t.Errorf("handleAuthentication() succeeded, want error")
+		}
+	})
+
+	t.Run("no authentication", func(t *testing.T) {
+		client, _, errCh := startTestGSSAPIAuthentication(t, acceptor, constant.Socks5NoAuth)
+		resp := make([]byte, 2)
+		if _, err := io.ReadFull(client, resp); err != nil {
+			t.Fatalf("ReadFull() failed: %v", err)
+		}
+		if resp[1] != constant.Socks5NoAcceptableAuth {
+			t.Errorf("selected method is %d, want no acceptable method", resp[1])
+		}
+		if err := <-errCh; err == nil {
+			t.Errorf("handleAuthentication() succeeded, want error")
+		}
+	})
+}
+
+// TestGSSAPIHandshakeDone checks that a connection protected by GSSAPI
+// is not closed by the handshake timeout of the accept limit.
+func TestGSSAPIHandshakeDone(t *testing.T) {
+	// Create a local listener as the destination target.
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer target.Close()
+	go func() {
+		conn, err := target.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+
+	kt := newTestGSSAPIKeytab(t)
+	bypass, err := NewBypassList([]string{"127.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("NewBypassList() failed: %v", err)
+	}
+	mux := protocol.NewMux(true)
+	defer mux.Close()
+	s, err := New(&Config{
+		UseProxy: true,
+		AuthOpts: Auth{
+			ClientSideAuthentication: true,
+			GSSAPI:                   NewGSSAPIAcceptor(kt, testGSSAPIService, nil, true),
+		},
+		ProxyMux:         mux,
+		Bypass:           bypass,
+		HandshakeTimeout: 5 * time.Second,
+		AcceptLimit:      common.AcceptLimit{HandshakeTimeout: 500 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer l.Close()
+	go s.Serve(l)
+
+	client, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	defer client.Close()
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := client.Write([]byte{constant.Socks5Version, 1, constant.Socks5GSSAPIAuth}); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(client, resp); err != nil {
+		t.Fatalf("ReadFull() failed: %v", err)
+	}
+	token, gssClient := newTestGSSAPIToken(t, kt, "alice", gssapi.ContextFlagMutual|gssapi.ContextFlagInteg)
+	if err := writeGSSAPIMessage(client, constant.Socks5GSSAPIAuthMessage, token); err != nil {
+		t.Fatalf("writeGSSAPIMessage() failed: %v", err)
+	}
+	reply, err := readGSSAPIMessage(client, constant.Socks5GSSAPIAuthMessage)
+	if err != nil {
+		t.Fatalf("readGSSAPIMessage() failed: %v", err)
+	}
+	gssClient.verifyAPRep(t, reply)
+	if err := writeGSSAPIMessage(client, constant.Socks5GSSAPIProtectionMessage, gssClient.wrap(t, []byte{2})); err != nil {
+		t.Fatalf("writeGSSAPIMessage() failed: %v", err)
+	}
+	reply, err = readGSSAPIMessage(client, constant.Socks5GSSAPIProtectionMessage)
+	if err != nil {
+		t.Fatalf("readGSSAPIMessage() failed: %v", err)
+	}
+	gssClient.unwrap(t, reply)
+
+	// Connect to the target, which is in the bypass list.
+	req := []byte{constant.Socks5Version, constant.Socks5ConnectCmd, 0, constant.Socks5IPv4Address, 127, 0, 0, 1, 0, 0}
+	binary.BigEndian.PutUint16(req[len(req)-2:], uint16(target.Addr().(*net.TCPAddr).Port))
+	if err := writeGSSAPIMessage(client, constant.Socks5GSSAPIDataMessage, gssClient.wrap(t, req)); err != nil {
+		t.Fatalf("writeGSSAPIMessage() failed: %v", err)
+	}
+	reply, err = readGSSAPIMessage(client, constant.Socks5GSSAPIDataMessage)
+	if err != nil {
+		t.Fatalf("readGSSAPIMessage() failed: %v", err)
+	}
+	if got := gssClient.unwrap(t, reply); got[1] != successReply {
+		t.Fatalf("got reply %v, want success", got)
+	}
+
+	// The connection is still usable after the handshake timeout.
+	time.Sleep(time.Second)
+	if err := writeGSSAPIMessage(client, constant.Socks5GSSAPIDataMessage, gssClient.wrap(t, []byte("ping"))); err != nil {
+		t.Fatalf("writeGSSAPIMessage() failed: %v", err)
+	}
+	reply, err = readGSSAPIMessage(client, constant.Socks5GSSAPIDataMessage)
+	if err != nil {
+		t.Fatalf("readGSSAPIMessage() failed: %v", err)
+	}
+	if got := gssClient.unwrap(t, reply); string(got) != "ping" {
+		t.Errorf("client got %q, want %q", got, "ping")
+	}
+}
@@ -56,8 +56,13 @@ var hopByHopHeaders = []string{
 
 type HTTPProxy struct {
 	ProxyURI string
-	client   *http.Client // cached HTTP client
-	mu       sync.Mutex
+
+	// If set, the traffic of HTTPS requests is counted for the HTTP client
+	// instead of this proxy.
+	FairShare *common.FairShareLimiter
+
+	client *http.Client // cached HTTP client
+	mu     sync.Mutex
 }
 
 var (
@@ -127,6 +132,7 @@ func (p *HTTPProxy) ServeHTTP(res http.ResponseWriter, req *http.Request) {
 			log.Debugf("HTTP proxy dial to socks5 server failed: %v", err)
 			return
 		}
+		defer p.FairShare.Alias(socksConn.LocalAddr(), httpConn.RemoteAddr())()
 		httpConn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n"))
 		common.BidiCopy(httpConn, socksConn)
 	} else {
@@ -0,0 +1,77 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"net"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+)
+
+// KeepaliveRule sends keepalives on idle proxied TCP connections
+// to the matching destination ports.
+type KeepaliveRule struct {
+	// Destination port ranges. Each item is the begin and end port.
+	// If empty, the rule matches all the destination ports.
+	Ports [][2]int
+
+	// Send a keepalive after the connection is idle for this duration.
+	Interval time.Duration
+}
+
+// matches returns true if the destination port matches the rule.
+func (r KeepaliveRule) matches(port int) bool {
+	if len(r.Ports) == 0 {
+		return true
+	}
+	for _, p := range r.Ports {
+		if port >= p[0] && port <= p[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// keepaliveConn is a proxy connection that can send keepalives
+// without forwarding bytes to the destination.
+type keepaliveConn interface {
+	SetKeepalive(interval time.Duration)
+}
+
+// keepaliveInterval returns the keepalive interval of the destination port,
+// or 0 if no rule matches.
+func (s *Server) keepaliveInterval(port int) time.Duration {
+	for _, rule := range s.config.KeepaliveRules {
+		if rule.matches(port) {
+			return rule.Interval
+		}
+	}
+	return 0
+}
+
+// maybeEnableKeepalive enables keepalives on the proxy connection
+// if the destination port matches a keepalive rule.
+func (s *Server) maybeEnableKeepalive(proxyConn net.Conn, port int) {
+	interval := s.keepaliveInterval(port)
+	if interval <= 0 {
+		return
+	}
+	if conn, ok := proxyConn.(keepaliveConn); ok {
+		conn.SetKeepalive(interval)
+		log.Debugf("Enabled keepalive with interval %v on %v to destination port %d", interval, proxyConn.RemoteAddr(), port)
+	}
+}
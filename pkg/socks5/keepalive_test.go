@@ -0,0 +1,81 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+type fakeKeepaliveConn struct {
+	net.Conn
+	interval time.Duration
+}
+
+func (c *fakeKeepaliveConn) SetKeepalive(interval time.Duration) {
+	c.interval = interval
+}
+
+func (c *fakeKeepaliveConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2027}
+}
+
+func TestKeepaliveInterval(t *testing.T) {
+	s := &Server{config: &Config{
+		KeepaliveRules: []KeepaliveRule{
+			{Ports: [][2]int{{22, 22}, {993, 995}}, Interval: 30 * time.Second},
+			{Interval: 5 * time.Minute},
+		},
+	}}
+	testCases := []struct {
+		port int
+		want time.Duration
+	}{
+		{22, 30 * time.Second},
+		{993, 30 * time.Second},
+		{995, 30 * time.Second},
+		{996, 5 * time.Minute},
+		{443, 5 * time.Minute},
+	}
+	for _, tc := range testCases {
+		if got := s.keepaliveInterval(tc.port); got != tc.want {
+			t.Errorf("keepaliveInterval(%d) = %v, want %v", tc.port, got, tc.want)
+		}
+	}
+
+	s.config.KeepaliveRules = s.config.KeepaliveRules[:1]
+	if got := s.keepaliveInterval(443); got != 0 {
+		t.Errorf("keepaliveInterval(443) = %v, want 0", got)
+	}
+}
+
+func TestMaybeEnableKeepalive(t *testing.T) {
+	s := &Server{config: &Config{
+		KeepaliveRules: []KeepaliveRule{
+			{Ports: [][2]int{{143, 143}}, Interval: 20 * time.Second},
+		},
+	}}
+	conn := &fakeKeepaliveConn{}
+	s.maybeEnableKeepalive(conn, 80)
+	if conn.interval != 0 {
+		t.Errorf("keepalive interval = %v, want 0", conn.interval)
+	}
+	s.maybeEnableKeepalive(conn, 143)
+	if conn.interval != 20*time.Second {
+		t.Errorf("keepalive interval = %v, want %v", conn.interval, 20*time.Second)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+)
+
+type destinationRulesKey struct{}
+
+// withDestinationRules returns a context that carries the destination
+// rules of the user.
+func withDestinationRules(ctx context.Context, rules []*appctlpb.DestinationRule) context.Context {
+	return context.WithValue(ctx, destinationRulesKey{}, rules)
+}
+
+// destinationRulesFromContext returns the destination rules of the user
+// carried by the context.
+func destinationRulesFromContext(ctx context.Context) []*appctlpb.DestinationRule {
+	rules, _ := ctx.Value(destinationRulesKey{}).([]*appctlpb.DestinationRule)
+	return rules
+}
+
+// destinationRules returns the destination rules of the user.
+func (s *Server) destinationRules(userName string) []*appctlpb.DestinationRule {
+	if userName == "" {
+		return nil
+	}
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config.Users[userName].GetDestinationRules()
+}
+
+// destinationAllowed returns true if the destination is allowed by the
+// first matched rule, or no rule is matched. The destination is either
+// an IP address or a domain name.
+func destinationAllowed(rules []*appctlpb.DestinationRule, ip net.IP, domain string, port int) bool {
+	for _, rule := range rules {
+		if matchDestinationRule(rule, ip, domain, port) {
+			return rule.GetAction() != appctlpb.DestinationAction_DENY
+		}
+	}
+	return true
+}
+
+func matchDestinationRule(rule *appctlpb.DestinationRule, ip net.IP, domain string, port int) bool {
+	if !matchPorts(rule.GetPorts(), port) {
+		return false
+	}
+	if len(rule.GetIpRanges()) == 0 && len(rule.GetDomainNames()) == 0 {
+		return true
+	}
+	if ip != nil {
+		for _, ipRange := range rule.GetIpRanges() {
+			if ipRange == "*" {
+				return true
+			}
+			_, cidr, err := net.ParseCIDR(ipRange)
+			if err == nil && cidr.Contains(ip) {
+				return true
+			}
+		}
+	} else if domain != "" {
+		for _, d := range rule.GetDomainNames() {
+			if d == "*" || domain == d || strings.HasSuffix(domain, "."+d) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchPorts returns true if the port is in one of the ports or port ranges.
+// An empty list matches all ports.
+func matchPorts(ports []string, port int) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, p := range ports {
+		begin, end, found := strings.Cut(p, "-")
+		if !found {
+			end = begin
+		}
+		b, err := strconv.Atoi(begin)
+		if err != nil {
+			continue
+		}
+		e, err := strconv.Atoi(end)
+		if err != nil {
+			continue
+		}
+		if port >= b && port <= e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"net"
+	"testing"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+)
+
+func TestDestinationAllowed(t *testing.T) {
+	// Only allow HTTPS, except the internal network.
+	rules := []*appctlpb.DestinationRule{
+		{
+			IpRanges: []string{"10.0.0.0/8"},
+			Action:   appctlpb.DestinationAction_DENY.Enum(),
+		},
+		{
+			DomainNames: []string{"example.com"},
+			Ports:       []string{"8000-8999"},
+			Action:      appctlpb.DestinationAction_ALLOW.Enum(),
+		},
+		{
+			Ports:  []string{"443"},
+			Action: appctlpb.DestinationAction_ALLOW.Enum(),
+		},
+		{
+			IpRanges:    []string{"*"},
+			DomainNames: []string{"*"},
+			Action:      appctlpb.DestinationAction_DENY.Enum(),
+		},
+	}
+	testCases := []struct {
+		name   string
+		ip     net.IP
+		domain string
+		port   int
+		want   bool
+	}{
+		{"HTTPS IP", net.ParseIP("203.0.113.1"), "", 443, true},
+		{"HTTPS domain", nil, "www.example.org", 443, true},
+		{"HTTP domain", nil, "www.example.org", 80, false},
+		{"internal network", net.ParseIP("10.1.2.3"), "", 443, false},
+		{"port range of subdomain", nil, "api.example.com", 8080, true},
+		{"port range of other domain", nil, "example.net", 8080, false},
+		{"port range of IP", net.ParseIP("203.0.113.1"), "", 8080, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := destinationAllowed(rules, tc.ip, tc.domain, tc.port); got != tc.want {
+				t.Errorf("destinationAllowed() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+	if !destinationAllowed(nil, nil, "example.com", 80) {
+		t.Errorf("destination is not allowed without rules")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	apicommon "github.com/enfein/mieru/v3/apis/common"
 	"github.com/enfein/mieru/v3/apis/constant"
@@ -18,6 +19,7 @@ import (
 	"github.com/enfein/mieru/v3/pkg/common"
 	"github.com/enfein/mieru/v3/pkg/log"
 	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/tracing"
 )
 
 // socks5 error types.
@@ -43,6 +45,14 @@ type Request struct {
 	DstAddr *model.AddrSpec
 	// Raw request bytes.
 	Raw []byte
+
+	// candidateIPs are the resolved IP addresses of the destination domain
+	// name, in the order to make connection attempts.
+	candidateIPs []net.IP
+
+	// binding is the local network interface, the local IP address and
+	// the firewall mark to connect to the destination or the egress proxy.
+	binding common.LocalBinding
 }
 
 // newRequest creates a new Request from the connection.
@@ -85,7 +95,9 @@ func (s *Server) handleRequest(ctx context.Context, req *Request, conn net.Conn)
 	// Resolve the address if we have a FQDN.
 	dst := req.DstAddr
 	if dst.FQDN != "" {
+		_, resolveSpan := tracing.Start(ctx, "dns resolve", tracing.SpanKindClient)
 		ips, err := s.config.Resolver.LookupIP(ctx, "ip", dst.FQDN)
+		resolveSpan.End(err)
 		if err != nil || len(ips) == 0 {
 			DNSResolveErrors.Add(1)
 			if err := sendReply(conn, hostUnreachable, nil); err != nil {
@@ -97,15 +109,16 @@ func (s *Server) handleRequest(ctx context.Context, req *Request, conn net.Conn)
 				return fmt.Errorf(stderror.IPAddressNotFound, dst.FQDN)
 			}
 		} else {
-			dst.IP = common.SelectIPFromList(ips, s.config.DualStackPreference)
-			if dst.IP == nil {
+			req.candidateIPs = common.SortIPsForHappyEyeballs(ips, s.config.DualStackPreference)
+			if len(req.candidateIPs) == 0 {
 				DNSResolveErrors.Add(1)
 				if err := sendReply(conn, networkUnreachable, nil); err != nil {
 					return fmt.Errorf("failed to send reply: %w", err)
 				}
 				return fmt.Errorf("resolved domain name %s to IP addresses %v, but no IP address satisfy DNS dual stack preference", dst.FQDN, ips)
 			}
-			log.Debugf("Resolved domain name %s to IP addresses %v, selected IP address %v", dst.FQDN, ips, dst.IP)
+			dst.IP = req.candidateIPs[0]
+			log.WithSession(ctx).Debugf("Resolved domain name %s to IP addresses %v, connection attempt order %v", dst.FQDN, ips, req.candidateIPs)
 		}
 	}
 
@@ -128,8 +141,18 @@ func (s *Server) handleRequest(ctx context.Context, req *Request, conn net.Conn)
 
 // handleConnect is used to handle a connect command.
 func (s *Server) handleConnect(ctx context.Context, req *Request, conn net.Conn) error {
-	var d net.Dialer
-	target, err := d.DialContext(ctx, "tcp", req.DstAddr.String())
+	addrs := []string{req.DstAddr.String()}
+	if len(req.candidateIPs) > 1 {
+		// Race the connections to all resolved IP addresses with
+		// Happy Eyeballs, so a broken address family doesn't block us.
+		addrs = make([]string, 0, len(req.candidateIPs))
+		for _, ip := range req.candidateIPs {
+			addrs = append(addrs, model.AddrSpec{IP: ip, Port: req.DstAddr.Port}.String())
+		}
+	}
+	_, dialSpan := tracing.Start(ctx, "destination dial", tracing.SpanKindClient)
+	target, err := common.DialHappyEyeballs(ctx, req.binding.Dialer(&net.Dialer{}), "tcp", addrs, common.HappyEyeballsAttemptDelay)
+	dialSpan.End(err)
 	if err != nil {
 		msg := err.Error()
 		var resp uint8
@@ -149,6 +172,9 @@ func (s *Server) handleConnect(ctx context.Context, req *Request, conn net.Conn)
 		return fmt.Errorf("connect to %v failed: %w", req.DstAddr, err)
 	}
 	defer target.Close()
+	if len(addrs) > 1 {
+		log.WithSession(ctx).Debugf("Connected to %v via %v", req.DstAddr, target.RemoteAddr())
+	}
 
 	// Send success.
 	local := target.LocalAddr().(*net.TCPAddr)
@@ -158,7 +184,14 @@ func (s *Server) handleConnect(ctx context.Context, req *Request, conn net.Conn)
 		return fmt.Errorf("failed to send reply: %w", err)
 	}
 
-	return common.BidiCopy(conn, target)
+	if remote, ok := target.RemoteAddr().(*net.TCPAddr); ok && s.config.CountryStats != nil && s.config.GeoIP != nil {
+		target = &countryStatsConn{Conn: target, country: s.config.GeoIP.Country(remote.IP), stats: s.config.CountryStats}
+	}
+
+	_, transferSpan := tracing.Start(ctx, "data transfer", tracing.SpanKindInternal)
+	err = bidiCopy(ctx, conn, target)
+	transferSpan.End(err)
+	return err
 }
 
 // handleBind is used to handle a bind command.
@@ -172,15 +205,10 @@ func (s *Server) handleBind(_ context.Context, _ *Request, conn net.Conn) error
 }
 
 // handleAssociate is used to handle a associate command.
-func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) error {
-	// Create a UDP listener on a random port.
+func (s *Server) handleAssociate(ctx context.Context, _ *Request, conn net.Conn) error {
+	// Create a UDP listener on a random port, or a port in the configured range.
 	// All the requests associated to this connection will go through this port.
-	udpListenerAddr, err := apicommon.ResolveUDPAddr(s.config.Resolver, "udp", common.MaybeDecorateIPv6(common.AllIPAddr())+":0")
-	if err != nil {
-		UDPAssociateErrors.Add(1)
-		return fmt.Errorf("failed to resolve UDP address: %w", err)
-	}
-	udpConn, err := net.ListenUDP("udp", udpListenerAddr)
+	udpConn, err := s.listenUDPRelay()
 	if err != nil {
 		UDPAssociateErrors.Add(1)
 		return fmt.Errorf("failed to listen UDP: %w", err)
@@ -209,8 +237,11 @@ func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) e
 	conn = apicommon.NewPacketOverStreamTunnel(conn)
 	var udpErr atomic.Value
 
-	// addrMap maps the UDPAddr in string to the bytes in UDP associate header.
-	var addrMap sync.Map
+	// natTable records the remote peers and the UDP associate headers
+	// used to reach them.
+	natTable := newUDPNATTable()
+	defer natTable.close()
+	rules := destinationRulesFromContext(ctx)
 
 	var wg sync.WaitGroup
 	wg.Add(2)
@@ -257,6 +288,11 @@ func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) e
 				UDPAssociateErrors.Add(1)
 				return
 			}
+			if payloadLen := n - udpAssociateHeaderLength(buf[:n]); payloadLen > constant.Socks5UDPMaxPayload {
+				log.Debugf("UDP associate %v dropped %d bytes payload from proxy client: exceed limit %d", udpConn.LocalAddr(), payloadLen, constant.Socks5UDPMaxPayload)
+				UDPAssociateOversizedPackets.Add(1)
+				continue
+			}
 
 			// Get target address and send data.
 			switch addrType {
@@ -265,7 +301,11 @@ func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) e
 					IP:   net.IP(buf[4:8]),
 					Port: int(buf[8])<<8 + int(buf[9]),
 				}
-				addrMap.Store(dstAddr.String(), buf[:10])
+				if !destinationAllowed(rules, dstAddr.IP, "", dstAddr.Port) {
+					UDPAssociateBlockedPackets.Add(1)
+					break
+				}
+				natTable.add(dstAddr, buf[:10], time.Now())
 				ws, err := udpConn.WriteToUDP(buf[10:n], dstAddr)
 				if err != nil {
 					log.Debugf("UDP associate [%v - %v] WriteToUDP() failed: %v", udpConn.LocalAddr(), dstAddr, err)
@@ -273,17 +313,36 @@ func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) e
 				} else {
 					UDPAssociateUploadPackets.Add(1)
 					UDPAssociateUploadBytes.Add(int64(ws))
+					s.addCountryTraffic(dstAddr.IP, int64(ws), 0)
 				}
 			case 0x03:
 				fqdnLen := buf[4]
 				fqdn := string(buf[5 : 5+fqdnLen])
-				dstAddr, err := apicommon.ResolveUDPAddr(s.config.Resolver, "udp", fqdn+":"+strconv.Itoa(int(buf[5+fqdnLen])<<8+int(buf[6+fqdnLen])))
+				if isTestEndpoint(fqdn) && testEndpointsEnabled(ctx) {
+					UDPAssociateUploadPackets.Add(1)
+					UDPAssociateUploadBytes.Add(int64(n - 7 - int(fqdnLen)))
+					if fqdn == constant.Socks5EchoDestination {
+						if _, err := conn.Write(buf[:n]); err != nil {
+							udpErr.Store(err)
+							return
+						}
+						UDPAssociateDownloadPackets.Add(1)
+						UDPAssociateDownloadBytes.Add(int64(n - 7 - int(fqdnLen)))
+					}
+					break
+				}
+				dstPort := int(buf[5+fqdnLen])<<8 + int(buf[6+fqdnLen])
+				if s.isBlocked(fqdn) || !destinationAllowed(rules, nil, fqdn, dstPort) {
+					UDPAssociateBlockedPackets.Add(1)
+					break
+				}
+				dstAddr, err := apicommon.ResolveUDPAddr(s.config.Resolver, "udp", fqdn+":"+strconv.Itoa(dstPort))
 				if err != nil {
 					log.Debugf("UDP associate %v ResolveUDPAddr() failed: %v", udpConn.LocalAddr(), err)
 					UDPAssociateErrors.Add(1)
 					break
 				}
-				addrMap.Store(dstAddr.String(), buf[:7+fqdnLen])
+				natTable.add(dstAddr, buf[:7+fqdnLen], time.Now())
 				ws, err := udpConn.WriteToUDP(buf[7+fqdnLen:n], dstAddr)
 				if err != nil {
 					log.Debugf("UDP associate [%v - %v] WriteToUDP() failed: %v", udpConn.LocalAddr(), dstAddr, err)
@@ -291,13 +350,18 @@ func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) e
 				} else {
 					UDPAssociateUploadPackets.Add(1)
 					UDPAssociateUploadBytes.Add(int64(ws))
+					s.addCountryTraffic(dstAddr.IP, int64(ws), 0)
 				}
 			case 0x04:
 				dstAddr := &net.UDPAddr{
 					IP:   net.IP(buf[4:20]),
 					Port: int(buf[20])<<8 + int(buf[21]),
 				}
-				addrMap.Store(dstAddr.String(), buf[:22])
+				if !destinationAllowed(rules, dstAddr.IP, "", dstAddr.Port) {
+					UDPAssociateBlockedPackets.Add(1)
+					break
+				}
+				natTable.add(dstAddr, buf[:22], time.Now())
 				ws, err := udpConn.WriteToUDP(buf[22:n], dstAddr)
 				if err != nil {
 					log.Debugf("UDP associate [%v - %v] WriteToUDP() failed: %v", udpConn.LocalAddr(), dstAddr, err)
@@ -305,6 +369,7 @@ func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) e
 				} else {
 					UDPAssociateUploadPackets.Add(1)
 					UDPAssociateUploadBytes.Add(int64(ws))
+					s.addCountryTraffic(dstAddr.IP, int64(ws), 0)
 				}
 			}
 		}
@@ -330,15 +395,13 @@ func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) e
 				}
 				return
 			}
-			var header []byte
-			v, ok := addrMap.Load(addr.String())
-			if ok {
-				header = v.([]byte)
-			} else {
-				header = udpAddrToHeader(addr)
-				addrMap.Store(addr.String(), header)
+			if n > constant.Socks5UDPMaxPayload {
+				log.Debugf("UDP associate %v dropped %d bytes payload from %v: exceed limit %d", udpConn.LocalAddr(), n, addr, constant.Socks5UDPMaxPayload)
+				UDPAssociateOversizedPackets.Add(1)
+				continue
 			}
-			_, err = conn.Write(append(header, buf[:n]...))
+			header := natTable.replyHeader(addr, time.Now())
+			_, err = conn.Write(append(append([]byte{}, header...), buf[:n]...))
 			if err != nil {
 				log.Debugf("UDP associate %v Write() to proxy client failed: %v", udpConn.LocalAddr(), err)
 				if udpErr.Load() == nil {
@@ -348,6 +411,7 @@ func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) e
 			}
 			UDPAssociateDownloadPackets.Add(1)
 			UDPAssociateDownloadBytes.Add(int64(n))
+			s.addCountryTraffic(addr.IP, 0, int64(n))
 		}
 	}()
 
@@ -355,10 +419,10 @@ func (s *Server) handleAssociate(_ context.Context, _ *Request, conn net.Conn) e
 	return udpErr.Load().(error)
 }
 
-func (s *Server) handleForwarding(req *Request, conn net.Conn, proxy *appctlpb.EgressProxy) error {
+func (s *Server) handleForwarding(ctx context.Context, req *Request, conn net.Conn, proxy *appctlpb.EgressProxy) error {
 	forwardHost := proxy.GetHost()
 	forwardPort := proxy.GetPort()
-	proxyConn, err := net.Dial("tcp", common.MaybeDecorateIPv6(forwardHost)+":"+strconv.Itoa(int(forwardPort)))
+	proxyConn, err := req.binding.Dialer(&net.Dialer{}).DialContext(ctx, "tcp", common.MaybeDecorateIPv6(forwardHost)+":"+strconv.Itoa(int(forwardPort)))
 	if err != nil {
 		HandshakeErrors.Add(1)
 		return fmt.Errorf("dial to egress proxy failed: %w", err)
@@ -373,7 +437,21 @@ func (s *Server) handleForwarding(req *Request, conn net.Conn, proxy *appctlpb.E
 		proxyConn.Close()
 		return fmt.Errorf("failed to write socks5 request to egress proxy: %w", err)
 	}
-	return common.BidiCopy(conn, proxyConn)
+	return bidiCopy(ctx, conn, proxyConn)
+}
+
+// tunnelError is returned when the socks5 request can't be sent to
+// the proxy server, or the response can't be received from it.
+type tunnelError struct {
+	err error
+}
+
+func (e tunnelError) Error() string {
+	return e.err.Error()
+}
+
+func (e tunnelError) Unwrap() error {
+	return e.err
 }
 
 // proxySocks5AuthReq transfers the socks5 authentication request and response
@@ -420,18 +498,28 @@ func (s *Server) proxySocks5AuthReq(conn, proxyConn net.Conn) error {
 }
 
 // proxySocks5ConnReq transfers the socks5 connection request and response
-// between socks5 client and server. Optionally, if UDP association is used,
-// return the created UDP connection.
-func (s *Server) proxySocks5ConnReq(conn, proxyConn net.Conn) (*net.UDPConn, error) {
-	// Send the connection request to the server.
+// between socks5 client and server. It returns the destination of the
+// request. Optionally, if UDP association is used, return the created
+// UDP relay.
+func (s *Server) proxySocks5ConnReq(conn, proxyConn net.Conn) (model.AddrSpec, *udpAssociation, error) {
+	connReq, err := s.readSocks5ConnReq(conn)
+	if err != nil {
+		return model.AddrSpec{}, nil, err
+	}
+	return s.forwardSocks5ConnReq(conn, proxyConn, connReq)
+}
+
+// readSocks5ConnReq reads the socks5 connection request from the socks5 client.
+func (s *Server) readSocks5ConnReq(conn net.Conn) ([]byte, error) {
 	defer common.SetReadTimeout(conn, 0)
-	defer common.SetReadTimeout(proxyConn, 0)
 	common.SetReadTimeout(conn, s.config.HandshakeTimeout)
 	connReq := make([]byte, 4)
 	if _, err := io.ReadFull(conn, connReq); err != nil {
 		return nil, fmt.Errorf("failed to get socks5 connection request: %w", err)
 	}
-	cmd := connReq[1]
+	if connReq[0] != constant.Socks5Version {
+		return nil, fmt.Errorf("unsupported command version: %v", connReq[0])
+	}
 	reqAddrType := connReq[3]
 	var reqFQDNLen []byte
 	var dstAddr []byte
@@ -443,7 +531,10 @@ func (s *Server) proxySocks5ConnReq(conn, proxyConn net.Conn) (*net.UDPConn, err
 		if _, err := io.ReadFull(conn, reqFQDNLen); err != nil {
 			return nil, fmt.Errorf("failed to get FQDN length: %w", err)
 		}
-		dstAddr = make([]byte, reqFQDNLen[0]+2)
+		if reqFQDNLen[0] == 0 {
+			return nil, fmt.Errorf("FQDN length is 0")
+		}
+		dstAddr = make([]byte, int(reqFQDNLen[0])+2)
 	case constant.Socks5IPv6Address:
 		dstAddr = make([]byte, 18)
 	default:
@@ -456,16 +547,78 @@ func (s *Server) proxySocks5ConnReq(conn, proxyConn net.Conn) (*net.UDPConn, err
 		connReq = append(connReq, reqFQDNLen...)
 	}
 	connReq = append(connReq, dstAddr...)
+	return connReq, nil
+}
+
+// forwardSocks5ConnReq sends the socks5 connection request that is already
+// read from the socks5 client to the server, and transfers the response.
+// See proxySocks5ConnReq for the return values.
+func (s *Server) forwardSocks5ConnReq(conn, proxyConn net.Conn, connReq []byte) (model.AddrSpec, *udpAssociation, error) {
+	// Validate the destination before it is sent to the server.
+	var dst model.AddrSpec
+	if err := dst.ReadFromSocks5(bytes.NewReader(connReq[3:])); err != nil {
+		return model.AddrSpec{}, nil, fmt.Errorf("ReadFromSocks5() failed: %w", err)
+	}
+
+	// Send the connection request to the server.
+	defer common.SetReadTimeout(proxyConn, 0)
+	cmd := connReq[1]
 	if _, err := proxyConn.Write(connReq); err != nil {
-		return nil, fmt.Errorf("failed to write connection request to the server: %w", err)
+		return model.AddrSpec{}, nil, fmt.Errorf("failed to write connection request to the server: %w", tunnelError{err})
 	}
 	log.Debugf("Sent socks5 request %v to server", connReq)
+	if cmd == constant.Socks5ConnectCmd {
+		s.maybeEnableKeepalive(proxyConn, dst.Port)
+	}
 
 	// Get server connection response.
+	connResp, err := s.readProxyConnResp(proxyConn)
+	if err != nil {
+		return model.AddrSpec{}, nil, err
+	}
+
+	var association *udpAssociation
+	if cmd == constant.Socks5UDPAssociateCmd {
+		// Create a UDP listener on a random port in IPv4 network.
+		udpAddr := &net.UDPAddr{IP: net.IP{0, 0, 0, 0}, Port: 0}
+		udpConn, err := net.ListenUDP("udp4", udpAddr)
+		if err != nil {
+			return model.AddrSpec{}, nil, fmt.Errorf("net.ListenUDP() failed: %w", err)
+		}
+		// Get the port number and rewrite the response.
+		_, udpPortStr, err := net.SplitHostPort(udpConn.LocalAddr().String())
+		if err != nil {
+			udpConn.Close()
+			return model.AddrSpec{}, nil, fmt.Errorf("net.SplitHostPort() failed: %w", err)
+		}
+		udpPort, err := strconv.Atoi(udpPortStr)
+		if err != nil {
+			udpConn.Close()
+			return model.AddrSpec{}, nil, fmt.Errorf("strconv.Atoi() failed: %w", err)
+		}
+		lenResp := len(connResp)
+		connResp[lenResp-2] = byte(udpPort >> 8)
+		connResp[lenResp-1] = byte(udpPort)
+		association = &udpAssociation{
+			conn:   udpConn,
+			filter: newUDPSourceFilter(s.config.UDPSourceFilter, dst, conn.RemoteAddr()),
+		}
+	}
+
+	if _, err := conn.Write(connResp); err != nil {
+		return model.AddrSpec{}, nil, fmt.Errorf("failed to write connection response to the socks5 client: %w", err)
+	}
+
+	return dst, association, nil
+}
+
+// readProxyConnResp reads the socks5 connection response from the server.
+func (s *Server) readProxyConnResp(proxyConn net.Conn) ([]byte, error) {
+	defer common.SetReadTimeout(proxyConn, 0)
 	common.SetReadTimeout(proxyConn, s.config.HandshakeTimeout)
 	connResp := make([]byte, 4)
 	if _, err := io.ReadFull(proxyConn, connResp); err != nil {
-		return nil, fmt.Errorf("failed to read connection response from the server: %w", err)
+		return nil, fmt.Errorf("failed to read connection response from the server: %w", tunnelError{err})
 	}
 	respAddrType := connResp[3]
 	var respFQDNLen []byte
@@ -478,7 +631,7 @@ func (s *Server) proxySocks5ConnReq(conn, proxyConn net.Conn) (*net.UDPConn, err
 		if _, err := io.ReadFull(proxyConn, respFQDNLen); err != nil {
 			return nil, fmt.Errorf("failed to get FQDN length: %w", err)
 		}
-		bindAddr = make([]byte, respFQDNLen[0]+2)
+		bindAddr = make([]byte, int(respFQDNLen[0])+2)
 	case constant.Socks5IPv6Address:
 		bindAddr = make([]byte, 18)
 	default:
@@ -490,38 +643,7 @@ func (s *Server) proxySocks5ConnReq(conn, proxyConn net.Conn) (*net.UDPConn, err
 	if len(respFQDNLen) != 0 {
 		connResp = append(connResp, respFQDNLen...)
 	}
-	connResp = append(connResp, bindAddr...)
-
-	var udpConn *net.UDPConn
-	if cmd == constant.Socks5UDPAssociateCmd {
-		// Create a UDP listener on a random port in IPv4 network.
-		var err error
-		udpAddr := &net.UDPAddr{IP: net.IP{0, 0, 0, 0}, Port: 0}
-		udpConn, err = net.ListenUDP("udp4", udpAddr)
-		if err != nil {
-			return nil, fmt.Errorf("net.ListenUDP() failed: %w", err)
-		}
-		// Get the port number and rewrite the response.
-		_, udpPortStr, err := net.SplitHostPort(udpConn.LocalAddr().String())
-		if err != nil {
-			udpConn.Close()
-			return nil, fmt.Errorf("net.SplitHostPort() failed: %w", err)
-		}
-		udpPort, err := strconv.Atoi(udpPortStr)
-		if err != nil {
-			udpConn.Close()
-			return nil, fmt.Errorf("strconv.Atoi() failed: %w", err)
-		}
-		lenResp := len(connResp)
-		connResp[lenResp-2] = byte(udpPort >> 8)
-		connResp[lenResp-1] = byte(udpPort)
-	}
-
-	if _, err := conn.Write(connResp); err != nil {
-		return nil, fmt.Errorf("failed to write connection response to the socks5 client: %w", err)
-	}
-
-	return udpConn, nil
+	return append(connResp, bindAddr...), nil
 }
 
 // sendReply is used to send a reply message.
@@ -6,10 +6,16 @@ import (
 	"encoding/binary"
 	"io"
 	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/blocklist"
+	"github.com/enfein/mieru/v3/pkg/geoip"
+	"github.com/enfein/mieru/v3/pkg/metrics"
 	"github.com/enfein/mieru/v3/pkg/stderror"
 	"github.com/enfein/mieru/v3/pkg/testtool"
 )
@@ -93,6 +99,74 @@ func TestRequestConnect(t *testing.T) {
 	}
 }
 
+func TestRequestConnectCountryStats(t *testing.T) {
+	dst, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	go func() {
+		conn, err := dst.Accept()
+		if err != nil {
+			return
+		}
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+		conn.Write([]byte("pong"))
+	}()
+	dstAddr := dst.Addr().(*net.TCPAddr)
+
+	db, err := geoip.Parse(strings.NewReader("127.0.0.0,127.255.255.255,ZZ\n"))
+	if err != nil {
+		t.Fatalf("geoip.Parse() failed: %v", err)
+	}
+	stats := metrics.NewDestinationStats(1)
+	s := &Server{
+		config: &Config{
+			AllowLoopbackDestination: true,
+			CountryStats:             stats,
+			GeoIP:                    db,
+		},
+	}
+
+	clientConn, serverConn := testtool.BufPipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	clientConn.Write([]byte{5, 1, 0, 1, 127, 0, 0, 1})
+	port := []byte{0, 0}
+	binary.BigEndian.PutUint16(port, uint16(dstAddr.Port))
+	clientConn.Write(port)
+	clientConn.Write([]byte("ping"))
+
+	go func() {
+		req, err := s.newRequest(serverConn)
+		if err != nil {
+			t.Errorf("NewRequest() failed: %v", err)
+			return
+		}
+		s.handleRequest(context.Background(), req, serverConn)
+	}()
+
+	out := make([]byte, 14)
+	if _, err := io.ReadFull(clientConn, out); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	var top []metrics.DestinationTraffic
+	for i := 0; i < 50; i++ {
+		top = stats.Top(0)
+		if len(top) == 1 && top[0].UploadBytes == 4 && top[0].DownloadBytes == 4 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	want := metrics.DestinationTraffic{Destination: "ZZ", UploadBytes: 4, DownloadBytes: 4}
+	if len(top) != 1 || top[0] != want {
+		t.Errorf("country traffic = %v, want %v", top, want)
+	}
+}
+
 func TestRequestUnsupportedCommand(t *testing.T) {
 	testcases := []struct {
 		req  []byte
@@ -138,3 +212,78 @@ func TestRequestUnsupportedCommand(t *testing.T) {
 		}
 	}
 }
+
+func TestReadSocks5ConnReq(t *testing.T) {
+	longFQDN := strings.Repeat("a", 255)
+	testcases := []struct {
+		req     []byte
+		wantErr bool
+	}{
+		{append(append([]byte{5, constant.Socks5ConnectCmd, 0, constant.Socks5FQDNAddress, 255}, longFQDN...), 0, 80), false},
+		{append(append([]byte{5, constant.Socks5ConnectCmd, 0, constant.Socks5FQDNAddress, 254}, longFQDN[:254]...), 0, 80), false},
+		{[]byte{5, constant.Socks5ConnectCmd, 0, constant.Socks5FQDNAddress, 0, 0, 80}, true},
+		{[]byte{4, constant.Socks5ConnectCmd, 0, constant.Socks5IPv4Address, 127, 0, 0, 1, 0, 80}, true},
+	}
+
+	s := &Server{config: &Config{}}
+	for _, tc := range testcases {
+		clientConn, serverConn := testtool.BufPipe()
+		clientConn.Write(tc.req)
+		connReq, err := s.readSocks5ConnReq(serverConn)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("readSocks5ConnReq(%v) succeeded, want error", tc.req)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("readSocks5ConnReq() failed: %v", err)
+		}
+		if !bytes.Equal(connReq, tc.req) {
+			t.Errorf("readSocks5ConnReq() returned %d bytes, want %d bytes", len(connReq), len(tc.req))
+		}
+	}
+}
+
+func TestRequestBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte("ads.example.com\n"), 0644); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	b := blocklist.New()
+	if err := b.Update([]string{path}, nil, 0); err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+
+	// Create a socks server.
+	s := &Server{
+		config: &Config{
+			AuthOpts: Auth{
+				ClientSideAuthentication: true,
+			},
+			Blocklist: b,
+		},
+	}
+
+	// Create the connect request.
+	clientConn, serverConn := testtool.BufPipe()
+	domain := "www.ads.example.com"
+	req := []byte{constant.Socks5Version, constant.Socks5ConnectCmd, 0, constant.Socks5FQDNAddress, byte(len(domain))}
+	req = append(req, domain...)
+	req = append(req, 0, 80)
+	clientConn.Write(req)
+
+	blocked := BlockedRequests.Load()
+	if err := s.serverServeConn(serverConn); err == nil {
+		t.Errorf("serverServeConn() returned no error")
+	}
+	out := make([]byte, 10)
+	clientConn.Read(out)
+	want := []byte{5, notAllowedByRuleSet, 0, 1, 0, 0, 0, 0, 0, 0}
+	if !bytes.Equal(out, want) {
+		t.Errorf("got %v, want %v", out, want)
+	}
+	if BlockedRequests.Load() != blocked+1 {
+		t.Errorf("BlockedRequests value is not changed")
+	}
+}
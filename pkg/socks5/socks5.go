@@ -4,20 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	mrand "math/rand"
 	"net"
 	"strconv"
+	"sync"
 	"time"
 
 	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/apis/constant"
 	"github.com/enfein/mieru/v3/apis/model"
 	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/blocklist"
 	"github.com/enfein/mieru/v3/pkg/common"
 	"github.com/enfein/mieru/v3/pkg/egress"
+	"github.com/enfein/mieru/v3/pkg/geoip"
+	"github.com/enfein/mieru/v3/pkg/geosite"
 	"github.com/enfein/mieru/v3/pkg/log"
 	"github.com/enfein/mieru/v3/pkg/mathext"
 	"github.com/enfein/mieru/v3/pkg/metrics"
 	"github.com/enfein/mieru/v3/pkg/protocol"
 	"github.com/enfein/mieru/v3/pkg/stderror"
+	"github.com/enfein/mieru/v3/pkg/tracing"
 )
 
 var (
@@ -29,11 +36,40 @@ var (
 	ConnectionRefusedErrors  = metrics.RegisterMetric("socks5", "ConnectionRefusedErrors", metrics.COUNTER)
 	UDPAssociateErrors       = metrics.RegisterMetric("socks5", "UDPAssociateErrors", metrics.COUNTER)
 	RejectByRules            = metrics.RegisterMetric("socks5", "RejectByRules", metrics.COUNTER)
+	RejectByUserPolicy       = metrics.RegisterMetric("socks5", "RejectByUserPolicy", metrics.COUNTER)
+	BlockedRequests          = metrics.RegisterMetric("socks5", "BlockedRequests", metrics.COUNTER)
+	TestEndpointRequests     = metrics.RegisterMetric("socks5", "TestEndpointRequests", metrics.COUNTER)
+
+	// AcceptMetrics are the metrics of the accept limit of socks5 listeners.
+	AcceptMetrics = common.AcceptLimitMetrics{
+		HalfOpen:          metrics.RegisterMetric("socks5 accept", "HalfOpen", metrics.GAUGE),
+		HandshakeTimeouts: metrics.RegisterMetric("socks5 accept", "HandshakeTimeouts", metrics.COUNTER),
+		HalfOpenRejected:  metrics.RegisterMetric("socks5 accept", "HalfOpenRejected", metrics.COUNTER),
+		RateLimited:       metrics.RegisterMetric("socks5 accept", "RateLimited", metrics.COUNTER),
+	}
 
-	UDPAssociateUploadBytes     = metrics.RegisterMetric("socks5 UDP associate", "UploadBytes", metrics.COUNTER)
-	UDPAssociateDownloadBytes   = metrics.RegisterMetric("socks5 UDP associate", "DownloadBytes", metrics.COUNTER)
-	UDPAssociateUploadPackets   = metrics.RegisterMetric("socks5 UDP associate", "UploadPackets", metrics.COUNTER)
-	UDPAssociateDownloadPackets = metrics.RegisterMetric("socks5 UDP associate", "DownloadPackets", metrics.COUNTER)
+	// Inbound socks5 negotiation outcomes.
+	NegotiationSuccess            = metrics.RegisterMetric("socks5 negotiation", "Success", metrics.COUNTER)
+	NegotiationNoAcceptableAuth   = metrics.RegisterMetric("socks5 negotiation", "NoAcceptableAuth", metrics.COUNTER)
+	NegotiationUnsupportedVersion = metrics.RegisterMetric("socks5 negotiation", "UnsupportedVersion", metrics.COUNTER)
+	NegotiationMalformed          = metrics.RegisterMetric("socks5 negotiation", "Malformed", metrics.COUNTER)
+	NegotiationAuthFailure        = metrics.RegisterMetric("socks5 negotiation", "AuthFailure", metrics.COUNTER)
+
+	// Failed inbound socks5 negotiations by the source IP address.
+	// The history can be used to compute the rate of failures.
+	NegotiationFailuresFromLoopback = metrics.RegisterMetric("socks5 negotiation failures by source", "Loopback", metrics.COUNTER_TIME_SERIES)
+	NegotiationFailuresFromLAN      = metrics.RegisterMetric("socks5 negotiation failures by source", "LAN", metrics.COUNTER_TIME_SERIES)
+	NegotiationFailuresFromPublic   = metrics.RegisterMetric("socks5 negotiation failures by source", "Public", metrics.COUNTER_TIME_SERIES)
+
+	UDPAssociateUploadBytes        = metrics.RegisterMetric("socks5 UDP associate", "UploadBytes", metrics.COUNTER)
+	UDPAssociateDownloadBytes      = metrics.RegisterMetric("socks5 UDP associate", "DownloadBytes", metrics.COUNTER)
+	UDPAssociateUploadPackets      = metrics.RegisterMetric("socks5 UDP associate", "UploadPackets", metrics.COUNTER)
+	UDPAssociateDownloadPackets    = metrics.RegisterMetric("socks5 UDP associate", "DownloadPackets", metrics.COUNTER)
+	UDPAssociatePeers              = metrics.RegisterMetric("socks5 UDP associate", "Peers", metrics.GAUGE)
+	UDPAssociateUnknownPeerPackets = metrics.RegisterMetric("socks5 UDP associate", "UnknownPeerPackets", metrics.COUNTER)
+	UDPAssociateRejectedPackets    = metrics.RegisterMetric("socks5 UDP associate", "RejectedPackets", metrics.COUNTER)
+	UDPAssociateOversizedPackets   = metrics.RegisterMetric("socks5 UDP associate", "OversizedPackets", metrics.COUNTER)
+	UDPAssociateBlockedPackets     = metrics.RegisterMetric("socks5 UDP associate", "BlockedPackets", metrics.COUNTER)
 )
 
 // Config is used to setup and configure a socks5 server.
@@ -54,6 +90,47 @@ type Config struct {
 	// Resolver can be provided to do custom name resolution.
 	Resolver apicommon.DNSResolver
 
+	// ---- client only fields ----
+
+	// If set, collect the traffic of each destination.
+	DestinationStats *metrics.DestinationStats
+
+	// If set, it is called after each socks5 request is sent over the
+	// proxy tunnel. The argument is nil if the tunnel works, or the error
+	// if the tunnel can't be established or the proxy server doesn't respond.
+	TunnelObserver func(err error)
+
+	// If set, the download bandwidth is shared equally by the source
+	// IP addresses of active clients.
+	FairShare *common.FairShareLimiter
+
+	// Which source addresses can send datagrams to the UDP relay
+	// of a UDP association.
+	UDPSourceFilter appctlpb.UDPSourceFilter
+
+	// Send keepalives on idle proxied TCP connections to the destination
+	// ports matching these rules. The first matching rule is used.
+	KeepaliveRules []KeepaliveRule
+
+	// If set, the CONNECT requests to these destinations are sent directly
+	// instead of through the proxy tunnel.
+	Bypass *BypassList
+
+	// If set, the domain names of CONNECT requests are resolved by Resolver,
+	// and the proxy server connects to the IP address.
+	ResolveLocally bool
+
+	// If set, the CONNECT requests are sent directly to the destination
+	// when the proxy tunnel can't be established.
+	DirectFallback bool
+
+	// If set, the active requests are recorded by the tracker.
+	Connections *ConnectionTracker
+
+	// Limits of the listeners served by the socks5 server. The handshake
+	// is finished when data transfer starts.
+	AcceptLimit common.AcceptLimit
+
 	// ---- server only fields ----
 
 	// Proxy users.
@@ -62,22 +139,49 @@ type Config struct {
 	// Egress configuration.
 	Egress *appctlpb.Egress
 
+	// User groups indexed by user name. If the group of a user has
+	// egress configuration, it is used instead of Egress.
+	UserGroups map[string]*appctlpb.UserGroup
+
+	// Where to listen for the UDP relay of UDP associations.
+	UDPRelay UDPRelay
+
 	// Strategy to select IP address from DNS response.
 	DualStackPreference common.DualStackPreference
 
 	// Allow using socks5 to access resources served from loopback address.
 	// This is for testing purpose.
 	AllowLoopbackDestination bool
+
+	// Serve the echo and sink test endpoints to the users allowed to use them.
+	EnableTestEndpoints bool
+
+	// If set together with GeoIP, collect the traffic of each country
+	// that the server directly connects to. Users are not recorded.
+	CountryStats *metrics.DestinationStats
+
+	// GeoIP database to look up the country of destination IP addresses.
+	GeoIP *geoip.Database
+
+	// GeoIP and geosite databases to match the countries and site
+	// categories of egress rules.
+	EgressGeoIP   *geoip.Database
+	EgressGeosite *geosite.Database
+
+	// If set, the requests and UDP datagrams to the domain names
+	// in the blocklist are rejected.
+	Blocklist *blocklist.Blocklist
+
+	// If set, each socks5 request is recorded in the audit log.
+	AuditLog *AuditLog
 }
 
 // Server is responsible for accepting connections and handling
 // the details of the SOCKS5 protocol
 type Server struct {
-	config      *Config
-	listener    net.Listener
-	chAccept    chan net.Conn
-	chAcceptErr chan error
-	die         chan struct{}
+	config   *Config
+	configMu sync.RWMutex // protect the fields of config that can be updated
+	die      chan struct{}
 }
 
 // New creates a new Server and potentially returns an error.
@@ -104,12 +208,90 @@ func New(conf *Config) (*Server, error) {
 		conf.Egress = &appctlpb.Egress{}
 	}
 
-	return &Server{
-		config:      conf,
-		chAccept:    make(chan net.Conn, 256),
-		chAcceptErr: make(chan error, 1), // non-blocking
-		die:         make(chan struct{}),
-	}, nil
+	s := &Server{
+		config: conf,
+		die:    make(chan struct{}),
+	}
+	return s, nil
+}
+
+// SetIngressCredentials updates the credentials to authenticate incoming requests.
+// Established connections are not impacted.
+func (s *Server) SetIngressCredentials(credentials []Credential) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config.AuthOpts.IngressCredentials = credentials
+}
+
+// SetGSSAPIAcceptor updates the acceptor to authenticate incoming requests
+// with GSSAPI. Established connections are not impacted.
+func (s *Server) SetGSSAPIAcceptor(acceptor *GSSAPIAcceptor) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config.AuthOpts.GSSAPI = acceptor
+}
+
+// SetUsers updates the proxy users. Established connections are not impacted.
+func (s *Server) SetUsers(users map[string]*appctlpb.User) {
+	if users == nil {
+		users = make(map[string]*appctlpb.User)
+	}
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config.Users = users
+}
+
+// SetEgress updates the egress configuration. Established connections are not impacted.
+func (s *Server) SetEgress(egress *appctlpb.Egress) {
+	if egress == nil {
+		egress = &appctlpb.Egress{}
+	}
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config.Egress = egress
+}
+
+// SetUserGroups updates the user groups indexed by user name.
+// Established connections are not impacted.
+func (s *Server) SetUserGroups(userGroups map[string]*appctlpb.UserGroup) {
+	if userGroups == nil {
+		userGroups = make(map[string]*appctlpb.UserGroup)
+	}
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config.UserGroups = userGroups
+}
+
+// SetEgressGeoDatabases updates the GeoIP and geosite databases used by
+// egress rules. Established connections are not impacted.
+func (s *Server) SetEgressGeoDatabases(geoIP *geoip.Database, site *geosite.Database) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config.EgressGeoIP = geoIP
+	s.config.EgressGeosite = site
+}
+
+// SetUDPRelay updates where to listen for the UDP relay of UDP associations.
+// Established UDP associations are not impacted.
+func (s *Server) SetUDPRelay(relay UDPRelay) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config.UDPRelay = relay
+}
+
+// SetAuditLog updates the audit log of new socks5 requests.
+func (s *Server) SetAuditLog(auditLog *AuditLog) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config.AuditLog = auditLog
+}
+
+// SetBypass updates the destinations that bypass the proxy tunnel.
+// Established connections are not impacted.
+func (s *Server) SetBypass(bypass *BypassList) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config.Bypass = bypass
 }
 
 // ListenAndServe is used to create a listener and serve on it.
@@ -122,29 +304,32 @@ func (s *Server) ListenAndServe(network, addr string) error {
 }
 
 // Serve is used to serve connections from a listener.
+// It can be called with multiple listeners at the same time.
 func (s *Server) Serve(l net.Listener) error {
-	s.listener = l
-	go s.acceptLoop()
+	l = common.NewLimitedListener(l, s.config.AcceptLimit, AcceptMetrics)
+	chAccept := make(chan net.Conn, 256)
+	chAcceptErr := make(chan error, 1) // non-blocking
+	go acceptLoop(l, chAccept, chAcceptErr)
 	for {
 		select {
-		case conn := <-s.chAccept:
+		case conn := <-chAccept:
 			go func() {
 				err := s.ServeConn(conn)
 				if err != nil && !stderror.IsEOF(err) && !stderror.IsClosed(err) {
-					log.Debugf("socks5 server listener %v ServeConn() failed: %v", s.listener.Addr(), err)
+					log.Debugf("socks5 server listener %v ServeConn() failed: %v", l.Addr(), err)
 				}
 			}()
-		case err := <-s.chAcceptErr:
+		case err := <-chAcceptErr:
 			log.Errorf("encountered error when socks5 server accept new connection: %v", err)
 			log.Infof("closing socks5 server listener")
-			if err := s.listener.Close(); err != nil {
-				log.Warnf("socks5 server listener %v Close() failed: %v", s.listener.Addr(), err)
+			if err := l.Close(); err != nil {
+				log.Warnf("socks5 server listener %v Close() failed: %v", l.Addr(), err)
 			}
 			return err // the err from chAcceptErr
 		case <-s.die:
 			log.Infof("closing socks5 server listener")
-			if err := s.listener.Close(); err != nil {
-				log.Warnf("socks5 server listener %v Close() failed: %v", s.listener.Addr(), err)
+			if err := l.Close(); err != nil {
+				log.Warnf("socks5 server listener %v Close() failed: %v", l.Addr(), err)
 			}
 			return nil
 		}
@@ -170,70 +355,203 @@ func (s *Server) Close() error {
 	return nil
 }
 
-func (s *Server) acceptLoop() {
+func acceptLoop(l net.Listener, chAccept chan<- net.Conn, chAcceptErr chan<- error) {
 	for {
-		conn, err := s.listener.Accept()
+		conn, err := l.Accept()
 		if err != nil {
-			s.chAcceptErr <- err
+			chAcceptErr <- err
 			return
 		}
-		s.chAccept <- conn
+		chAccept <- conn
 		if log.IsLevelEnabled(log.TraceLevel) {
 			log.Tracef("socks5 server accepted connection [%v - %v]", conn.LocalAddr(), conn.RemoteAddr())
 		}
 	}
 }
 
-func (s *Server) clientServeConn(conn net.Conn) error {
+func (s *Server) clientServeConn(conn net.Conn) (err error) {
+	ctx, span := tracing.Start(context.Background(), "socks5 request", tracing.SpanKindServer)
+	defer func() { span.End(err) }()
+
+	// The proxy tunnel uses the same session ID,
+	// so the request can be found in the proxy server logs.
+	sessionID := mrand.Uint32()
+	ctx = log.ContextWithSessionID(ctx, sessionID)
+	span.SetAttribute("session_id", strconv.FormatUint(uint64(sessionID), 10))
+	info := Connection{SessionID: sessionID, Protocol: "TCP", StartTime: time.Now()}
+	if remoteAddr := conn.RemoteAddr(); !common.IsNilNetAddr(remoteAddr) {
+		info.Source = remoteAddr.String()
+	}
+
+	// The authentication method may wrap the connection, so the handshake
+	// of the accept limit is marked done on the accepted connection.
+	acceptedConn := conn
 	if s.config.AuthOpts.ClientSideAuthentication {
-		if err := s.handleAuthentication(conn); err != nil {
+		authConn, err := s.handleAuthentication(conn)
+		if err != nil {
 			return err
 		}
+		conn = authConn
+	}
+
+	// Connect to the destinations in the bypass list directly.
+	// This needs the connection request before the proxy tunnel is used.
+	s.configMu.RLock()
+	bypass := s.config.Bypass
+	s.configMu.RUnlock()
+	var connReq []byte
+	var cmd byte
+	var dst model.AddrSpec
+	_, gssapiProtected := conn.(*gssapiConn)
+	if s.config.AuthOpts.ClientSideAuthentication && (bypass.Len() > 0 || s.config.ResolveLocally || s.config.DirectFallback || gssapiProtected) {
+		connReq, err = s.readSocks5ConnReq(conn)
+		if err != nil {
+			HandshakeErrors.Add(1)
+			return err
+		}
+		cmd, dst, err = parseSocks5ConnReq(connReq)
+		if err != nil {
+			HandshakeErrors.Add(1)
+			return err
+		}
+		if gssapiProtected && cmd == constant.Socks5UDPAssociateCmd {
+			// The datagrams of the UDP relay can't be protected.
+			HandshakeErrors.Add(1)
+			if err := sendReply(conn, commandNotSupported, nil); err != nil {
+				return fmt.Errorf("failed to send reply for commandNotSupported error: %w", err)
+			}
+			return fmt.Errorf("UDP associate is not supported with GSSAPI integrity protection")
+		}
+		if rule, matched := bypass.MatchRule(dst); cmd == constant.Socks5ConnectCmd && matched {
+			span.SetAttribute("destination", dst.String())
+			span.SetAttribute("bypass", "true")
+			info.Destination, info.Route, info.Rule = dst.String(), RouteBypass, rule
+			return s.bypassConnect(ctx, conn, acceptedConn, dst, info)
+		}
+		if cmd == constant.Socks5ConnectCmd && s.config.ResolveLocally && dst.FQDN != "" {
+			connReq, err = s.resolveSocks5ConnReq(ctx, conn, connReq, &dst)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Forward remaining bytes to proxy.
-	ctx := context.Background()
-	var proxyConn net.Conn
-	var err error
-	proxyConn, err = s.config.ProxyMux.DialContext(ctx)
+	_, dialSpan := tracing.Start(ctx, "tunnel dial", tracing.SpanKindClient)
+	proxyConn, err := s.config.ProxyMux.DialContext(ctx)
+	dialSpan.End(err)
 	if err != nil {
-		return fmt.Errorf("mux DialContext() failed: %w", err)
+		err = fmt.Errorf("mux DialContext() failed: %w", err)
+		s.observeTunnel(err)
+		if s.config.DirectFallback && connReq != nil && cmd == constant.Socks5ConnectCmd {
+			DirectFallbackConnections.Add(1)
+			log.WithSession(ctx).Debugf("Connecting to %v directly because proxy tunnel is unavailable: %v", dst, err)
+			span.SetAttribute("destination", dst.String())
+			span.SetAttribute("direct_fallback", "true")
+			info.Destination, info.Route = dst.String(), RouteDirectFallback
+			return s.bypassConnect(ctx, conn, acceptedConn, dst, info)
+		}
+		return err
 	}
 
+	_, handshakeSpan := tracing.Start(ctx, "socks5 handshake", tracing.SpanKindClient)
 	if !s.config.AuthOpts.ClientSideAuthentication {
 		if err := s.proxySocks5AuthReq(conn, proxyConn); err != nil {
 			HandshakeErrors.Add(1)
+			handshakeSpan.End(err)
 			proxyConn.Close()
 			return err
 		}
 	}
-	udpAssociateConn, err := s.proxySocks5ConnReq(conn, proxyConn)
+	var udpAssociation *udpAssociation
+	if connReq != nil {
+		dst, udpAssociation, err = s.forwardSocks5ConnReq(conn, proxyConn, connReq)
+	} else {
+		dst, udpAssociation, err = s.proxySocks5ConnReq(conn, proxyConn)
+	}
+	handshakeSpan.End(err)
 	if err != nil {
 		HandshakeErrors.Add(1)
+		var tunnelErr tunnelError
+		if errors.As(err, &tunnelErr) {
+			s.observeTunnel(err)
+		}
 		proxyConn.Close()
 		return err
 	}
-
-	if udpAssociateConn != nil {
-		log.Debugf("UDP association is listening on %v", udpAssociateConn.LocalAddr())
-		conn.(common.HierarchyConn).AddSubConnection(udpAssociateConn)
+	s.observeTunnel(nil)
+	dstHost := dst.FQDN
+	if dstHost == "" {
+		dstHost = dst.IP.String()
+	}
+	span.SetAttribute("destination", dstHost)
+	info.Destination, info.Route = dst.String(), RouteProxy
+	if udpAssociation != nil {
+		info.Protocol = "UDP"
+	}
+	proxyConn, untrack := s.config.Connections.track(proxyConn, info)
+	defer untrack()
+
+	common.HandshakeDone(acceptedConn)
+	proxyConn = common.NewFairShareConn(proxyConn, s.config.FairShare, conn.RemoteAddr())
+	_, transferSpan := tracing.Start(ctx, "data transfer", tracing.SpanKindInternal)
+	defer func() { transferSpan.End(err) }()
+	if udpAssociation != nil {
+		log.WithSession(ctx).Debugf("UDP association is listening on %v, accepting datagrams from %v", udpAssociation.conn.LocalAddr(), udpAssociation.filter)
+		conn.(common.HierarchyConn).AddSubConnection(udpAssociation.conn)
 		go func() {
 			common.ReadAllAndDiscard(conn)
 			conn.Close()
 		}()
-		return BidiCopyUDP(udpAssociateConn, apicommon.NewPacketOverStreamTunnel(proxyConn))
+		return BidiCopyUDP(udpAssociation.conn, apicommon.NewPacketOverStreamTunnel(proxyConn), udpAssociation.filter.accept)
+	}
+	if s.config.DestinationStats != nil {
+		proxyConn = &destinationStatsConn{Conn: proxyConn, destination: dstHost, stats: s.config.DestinationStats}
+	}
+	proxyConn = &trafficCounterConn{Conn: proxyConn, upload: ProxiedUploadBytes, download: ProxiedDownloadBytes}
+	return bidiCopy(ctx, conn, proxyConn)
+}
+
+// addCountryTraffic records the traffic of a destination IP address
+// to the country statistics, if it is enabled.
+func (s *Server) addCountryTraffic(ip net.IP, uploadBytes, downloadBytes int64) {
+	if s.config.CountryStats == nil || s.config.GeoIP == nil {
+		return
+	}
+	s.config.CountryStats.Add(s.config.GeoIP.Country(ip), uploadBytes, downloadBytes)
+}
+
+// isBlocked returns true if the domain name is in the blocklist.
+func (s *Server) isBlocked(domain string) bool {
+	return domain != "" && s.config.Blocklist != nil && s.config.Blocklist.Match(domain)
+}
+
+// observeTunnel reports the result of a proxy tunnel handshake.
+func (s *Server) observeTunnel(err error) {
+	if s.config.TunnelObserver != nil {
+		s.config.TunnelObserver(err)
 	}
-	return common.BidiCopy(conn, proxyConn)
 }
 
-func (s *Server) serverServeConn(conn net.Conn) error {
+func (s *Server) serverServeConn(conn net.Conn) (err error) {
+	ctx, span := tracing.Start(context.Background(), "socks5 request", tracing.SpanKindServer)
+	defer func() { span.End(err) }()
+
+	var sessionID uint32
+	if sessionCtx, ok := conn.(common.SessionContext); ok {
+		sessionID = sessionCtx.SessionID()
+		ctx = log.ContextWithSessionID(ctx, sessionID)
+		span.SetAttribute("session_id", strconv.FormatUint(uint64(sessionID), 10))
+	}
+
 	if !s.config.AuthOpts.ClientSideAuthentication {
-		if err := s.handleAuthentication(conn); err != nil {
+		authConn, err := s.handleAuthentication(conn)
+		if err != nil {
 			return err
 		}
+		conn = authConn
 	}
 
-	ctx := context.Background()
 	request, err := s.newRequest(conn)
 	if err != nil {
 		HandshakeErrors.Add(1)
@@ -242,8 +560,21 @@ func (s *Server) serverServeConn(conn net.Conn) error {
 				return fmt.Errorf("failed to send reply for addrTypeNotSupported error: %w", err)
 			}
 		}
+		if errors.Is(err, model.ErrInvalidFQDN) {
+			if err := sendReply(conn, hostUnreachable, nil); err != nil {
+				return fmt.Errorf("failed to send reply for invalid domain name: %w", err)
+			}
+		}
 		return fmt.Errorf("failed to read destination address: %w", err)
 	}
+	span.SetAttribute("destination", request.DstAddr.String())
+	if dstCtx, ok := conn.(common.DestinationContext); ok {
+		if request.Command == constant.Socks5UDPAssociateCmd {
+			dstCtx.SetDestination("UDP associate")
+		} else {
+			dstCtx.SetDestination(request.DstAddr.String())
+		}
+	}
 
 	egressInput := egress.Input{
 		Protocol: appctlpb.ProxyProtocol_SOCKS5_PROXY_PROTOCOL,
@@ -252,9 +583,9 @@ func (s *Server) serverServeConn(conn net.Conn) error {
 	if userCtx, ok := conn.(common.UserContext); ok {
 		userName := userCtx.UserName()
 		if userName == "" {
-			log.Debugf("Failed to determine user name from the connection")
+			log.WithSession(ctx).Debugf("Failed to determine user name from the connection")
 		} else {
-			log.Debugf("User %q initiated the connection", userName)
+			log.WithSession(ctx).WithField(log.FieldKeyUser, userName).Debugf("User %q initiated the connection", userName)
 			egressInput.Env = map[string]string{
 				"user": userName,
 			}
@@ -262,16 +593,66 @@ func (s *Server) serverServeConn(conn net.Conn) error {
 	} else {
 		log.Errorf("%T doesn't implement common.UserContext interface", conn)
 	}
+	if s.testEndpointsAllowed(egressInput.Env["user"]) {
+		ctx = withTestEndpoints(ctx)
+	}
+	if isTestEndpoint(request.DstAddr.FQDN) {
+		return s.handleTestEndpoint(ctx, request, conn)
+	}
+	var auditRecord *AuditRecord
+	s.configMu.RLock()
+	auditLog := s.config.AuditLog
+	s.configMu.RUnlock()
+	if auditLog != nil {
+		auditRecord = newAuditRecord(conn, sessionID, egressInput.Env["user"], request)
+		ac := &auditConn{Conn: conn}
+		conn = ac
+		defer func() {
+			auditRecord.finish(ac, err)
+			auditLog.Write(auditRecord)
+		}()
+	}
+	if s.isBlocked(request.DstAddr.FQDN) {
+		if auditRecord != nil {
+			auditRecord.Action = "BLOCK"
+		}
+		BlockedRequests.Add(1)
+		log.WithSession(ctx).Debugf("socks5 request to %s is blocked by blocklist", request.DstAddr.FQDN)
+		if err := sendReply(conn, notAllowedByRuleSet, nil); err != nil {
+			return fmt.Errorf("failed to send reply for notAllowedByRuleSet error: %w", err)
+		}
+		return fmt.Errorf("connection is rejected by blocklist")
+	}
+	rules := s.destinationRules(egressInput.Env["user"])
+	if len(rules) > 0 {
+		// UDP associate checks the destination of each packet.
+		ctx = withDestinationRules(ctx, rules)
+		if request.Command == constant.Socks5ConnectCmd && !destinationAllowed(rules, request.DstAddr.IP, request.DstAddr.FQDN, request.DstAddr.Port) {
+			if auditRecord != nil {
+				auditRecord.Action = appctlpb.EgressAction_REJECT.String()
+			}
+			RejectByUserPolicy.Add(1)
+			log.WithSession(ctx).Debugf("socks5 request to %s is rejected by destination rules of user %q", request.DstAddr.String(), egressInput.Env["user"])
+			if err := sendReply(conn, notAllowedByRuleSet, nil); err != nil {
+				return fmt.Errorf("failed to send reply for notAllowedByRuleSet error: %w", err)
+			}
+			return fmt.Errorf("connection is rejected by destination rules of user")
+		}
+	}
 	action := s.FindAction(ctx, egressInput)
+	request.binding = s.egressLocalBinding(egressInput.Env["user"])
 	if action.Action == appctlpb.EgressAction_PROXY {
 		proxy := action.Proxy
 		if proxy.GetSocks5Authentication().GetUser() != "" && proxy.GetSocks5Authentication().GetPassword() != "" {
-			log.Debugf("Egress decision of socks5 request %v is %s to %v with user password authentication", request.Raw, action.Action.String(), common.MaybeDecorateIPv6(proxy.GetHost())+":"+strconv.Itoa(int(proxy.GetPort())))
+			log.WithSession(ctx).Debugf("Egress decision of socks5 request %v is %s to %v with user password authentication", request.Raw, action.Action.String(), common.MaybeDecorateIPv6(proxy.GetHost())+":"+strconv.Itoa(int(proxy.GetPort())))
 		} else {
-			log.Debugf("Egress decision of socks5 request %v is %s to %v with no authentication", request.Raw, action.Action.String(), common.MaybeDecorateIPv6(proxy.GetHost())+":"+strconv.Itoa(int(proxy.GetPort())))
+			log.WithSession(ctx).Debugf("Egress decision of socks5 request %v is %s to %v with no authentication", request.Raw, action.Action.String(), common.MaybeDecorateIPv6(proxy.GetHost())+":"+strconv.Itoa(int(proxy.GetPort())))
 		}
 	} else {
-		log.Debugf("Egress decision of socks5 request %v is %s", request.Raw, action.Action.String())
+		log.WithSession(ctx).Debugf("Egress decision of socks5 request %v is %s", request.Raw, action.Action.String())
+	}
+	if auditRecord != nil {
+		auditRecord.Action = action.Action.String()
 	}
 	switch action.Action {
 	case appctlpb.EgressAction_DIRECT:
@@ -282,7 +663,7 @@ func (s *Server) serverServeConn(conn net.Conn) error {
 		if action.Proxy == nil {
 			return fmt.Errorf("egress action is PROXY but proxy info is unavailable")
 		}
-		return s.handleForwarding(request, conn, action.Proxy)
+		return s.handleForwarding(ctx, request, conn, action.Proxy)
 	case appctlpb.EgressAction_REJECT:
 		RejectByRules.Add(1)
 		if err := sendReply(conn, notAllowedByRuleSet, nil); err != nil {
@@ -5,6 +5,8 @@ import (
 	"encoding/binary"
 	"io"
 	"net"
+	"path/filepath"
+	"runtime"
 	"strconv"
 	"testing"
 	"time"
@@ -12,6 +14,7 @@ import (
 	apicommon "github.com/enfein/mieru/v3/apis/common"
 	"github.com/enfein/mieru/v3/apis/constant"
 	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/metrics"
 )
 
 func TestSocks5Connect(t *testing.T) {
@@ -109,6 +112,85 @@ func TestSocks5Connect(t *testing.T) {
 	}
 }
 
+func TestSocks5ServeMultipleListeners(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix domain socket is not tested on windows")
+	}
+	// Create a local listener as the destination target.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+	lAddr := l.Addr().(*net.TCPAddr)
+
+	// Create a socks server that listens to TCP and unix domain socket.
+	serv, err := New(&Config{
+		AllowLoopbackDestination: true,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "socks5.sock")
+	unixListener, err := common.ListenUnixSocket(path, 0600)
+	if err != nil {
+		t.Fatalf("ListenUnixSocket() failed: %v", err)
+	}
+	serveErrs := make(chan error, 2)
+	go func() { serveErrs <- serv.Serve(tcpListener) }()
+	go func() { serveErrs <- serv.Serve(unixListener) }()
+
+	// Dial to socks server via unix domain socket.
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	defer conn.Close()
+	req := bytes.NewBuffer(nil)
+	req.Write([]byte{constant.Socks5Version, 1, constant.Socks5NoAuth})
+	req.Write([]byte{constant.Socks5Version, constant.Socks5ConnectCmd, 0, constant.Socks5IPv4Address, 127, 0, 0, 1})
+	port := []byte{0, 0}
+	binary.BigEndian.PutUint16(port, uint16(lAddr.Port))
+	req.Write(port)
+	req.Write([]byte("ping"))
+	if _, err := conn.Write(req.Bytes()); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	out := make([]byte, 2+10+4)
+	conn.SetDeadline(time.Now().Add(time.Second))
+	if _, err := io.ReadFull(conn, out); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if out[3] != successReply || string(out[12:]) != "ping" {
+		t.Errorf("got %v, want success reply and echo", out)
+	}
+
+	// Close stops serving all the listeners.
+	serv.Close()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-serveErrs:
+			if err != nil {
+				t.Errorf("Serve() failed: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Serve() is not stopped after Close()")
+		}
+	}
+}
+
 func TestSocks5UDPAssociation(t *testing.T) {
 	t.Skipf("Skip due to high failure rate in CI")
 	udpUploadPktsCnt := UDPAssociateUploadPackets.Load()
@@ -243,3 +325,63 @@ func TestSocks5UDPAssociation(t *testing.T) {
 		t.Errorf("UDPAssociateDownloadPackets value %d is not increased", UDPAssociateDownloadPackets.Load())
 	}
 }
+
+func TestSocks5NegotiationMetrics(t *testing.T) {
+	conf := &Config{}
+	serv, err := New(conf)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	serverPort, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	go func() {
+		if err := serv.ListenAndServe("tcp", "127.0.0.1:"+strconv.Itoa(serverPort)); err != nil {
+			t.Errorf("ListenAndServe() failed: %v", err)
+			return
+		}
+	}()
+	defer serv.Close()
+	time.Sleep(200 * time.Millisecond)
+
+	testCases := []struct {
+		name    string
+		req     []byte
+		outcome metrics.Metric
+	}{
+		{"unsupported version", []byte{4, 1, constant.Socks5NoAuth}, NegotiationUnsupportedVersion},
+		{"malformed", []byte{constant.Socks5Version, 0}, NegotiationMalformed},
+		{"no acceptable auth", []byte{constant.Socks5Version, 1, 0x80}, NegotiationNoAcceptableAuth},
+		{"success", []byte{constant.Socks5Version, 1, constant.Socks5NoAuth}, NegotiationSuccess},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := tc.outcome.Load()
+			loopbackBefore := NegotiationFailuresFromLoopback.Load()
+			conn, err := net.Dial("tcp", "127.0.0.1:"+strconv.Itoa(serverPort))
+			if err != nil {
+				t.Fatalf("net.Dial() failed: %v", err)
+			}
+			defer conn.Close()
+			if _, err := conn.Write(tc.req); err != nil {
+				t.Fatalf("Write() failed: %v", err)
+			}
+			// Wait until the server has processed the negotiation.
+			conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			io.ReadFull(conn, make([]byte, 2))
+			time.Sleep(50 * time.Millisecond)
+
+			if got := tc.outcome.Load(); got != before+1 {
+				t.Errorf("outcome counter = %d, want %d", got, before+1)
+			}
+			wantLoopback := loopbackBefore + 1
+			if tc.outcome == NegotiationSuccess {
+				wantLoopback = loopbackBefore
+			}
+			if got := NegotiationFailuresFromLoopback.Load(); got != wantLoopback {
+				t.Errorf("loopback failures = %d, want %d", got, wantLoopback)
+			}
+		})
+	}
+}
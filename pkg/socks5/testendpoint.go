@@ -0,0 +1,90 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/apis/model"
+)
+
+type testEndpointsKey struct{}
+
+// isTestEndpoint returns true if the domain name is a reserved test endpoint.
+func isTestEndpoint(fqdn string) bool {
+	return fqdn == constant.Socks5EchoDestination || fqdn == constant.Socks5SinkDestination
+}
+
+// withTestEndpoints returns a context that allows using the test endpoints.
+func withTestEndpoints(ctx context.Context) context.Context {
+	return context.WithValue(ctx, testEndpointsKey{}, true)
+}
+
+// testEndpointsEnabled returns true if the context allows using the test endpoints.
+func testEndpointsEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(testEndpointsKey{}).(bool)
+	return enabled
+}
+
+// testEndpointsAllowed returns true if the user can use the test endpoints.
+func (s *Server) testEndpointsAllowed(userName string) bool {
+	if !s.config.EnableTestEndpoints || userName == "" {
+		return false
+	}
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config.Users[userName].GetAllowTestEndpoints()
+}
+
+// handleTestEndpoint handles a socks5 request to a test endpoint.
+func (s *Server) handleTestEndpoint(ctx context.Context, req *Request, conn net.Conn) error {
+	if !testEndpointsEnabled(ctx) {
+		RejectByRules.Add(1)
+		if err := sendReply(conn, notAllowedByRuleSet, nil); err != nil {
+			return fmt.Errorf("failed to send reply for notAllowedByRuleSet error: %w", err)
+		}
+		return fmt.Errorf("test endpoint %s is not allowed", req.DstAddr.FQDN)
+	}
+	switch req.Command {
+	case constant.Socks5ConnectCmd:
+		TestEndpointRequests.Add(1)
+		bind := model.AddrSpec{IP: net.IP{0, 0, 0, 0}, Port: 0}
+		if err := sendReply(conn, successReply, &bind); err != nil {
+			HandshakeErrors.Add(1)
+			return fmt.Errorf("failed to send reply: %w", err)
+		}
+		var err error
+		if req.DstAddr.FQDN == constant.Socks5EchoDestination {
+			_, err = io.Copy(conn, conn)
+		} else {
+			_, err = io.Copy(io.Discard, conn)
+		}
+		return err
+	case constant.Socks5UDPAssociateCmd:
+		TestEndpointRequests.Add(1)
+		return s.handleAssociate(ctx, req, conn)
+	default:
+		UnsupportedCommandErrors.Add(1)
+		if err := sendReply(conn, commandNotSupported, nil); err != nil {
+			return fmt.Errorf("failed to send reply: %w", err)
+		}
+		return fmt.Errorf("unsupported command for test endpoint: %v", req.Command)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/apis/model"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestTestEndpointsAllowed(t *testing.T) {
+	s := &Server{
+		config: &Config{
+			Users: map[string]*appctlpb.User{
+				"alice": {Name: proto.String("alice"), AllowTestEndpoints: proto.Bool(true)},
+				"bob":   {Name: proto.String("bob")},
+			},
+		},
+	}
+	if s.testEndpointsAllowed("alice") {
+		t.Errorf("test endpoints are allowed when they are not enabled")
+	}
+	s.config.EnableTestEndpoints = true
+	if !s.testEndpointsAllowed("alice") {
+		t.Errorf("test endpoints are not allowed for alice")
+	}
+	if s.testEndpointsAllowed("bob") {
+		t.Errorf("test endpoints are allowed for bob")
+	}
+	if s.testEndpointsAllowed("") {
+		t.Errorf("test endpoints are allowed for an anonymous user")
+	}
+}
+
+func TestTestEndpointEcho(t *testing.T) {
+	s := &Server{config: &Config{EnableTestEndpoints: true}}
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+	req := &Request{
+		Command: constant.Socks5ConnectCmd,
+		DstAddr: &model.AddrSpec{FQDN: constant.Socks5EchoDestination, Port: 7},
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.handleTestEndpoint(withTestEndpoints(context.Background()), req, serverConn)
+		serverConn.Close()
+	}()
+
+	reply := make([]byte, 10)
+	if _, err := io.ReadFull(clientConn, reply); err != nil {
+		t.Fatalf("failed to read reply: %v", err)
+	}
+	if reply[1] != successReply {
+		t.Fatalf("got reply code %d, want %d", reply[1], successReply)
+	}
+	payload := []byte("hello mieru")
+	if _, err := clientConn.Write(payload); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	got := make([]byte, len(payload))
+	if _, err := io.ReadFull(clientConn, got); err != nil {
+		t.Fatalf("failed to read echo: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Errorf("got %q, want %q", got, payload)
+	}
+	clientConn.Close()
+	<-done
+}
+
+func TestTestEndpointNotAllowed(t *testing.T) {
+	s := &Server{config: &Config{EnableTestEndpoints: true}}
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+	req := &Request{
+		Command: constant.Socks5ConnectCmd,
+		DstAddr: &model.AddrSpec{FQDN: constant.Socks5SinkDestination, Port: 9},
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.handleTestEndpoint(context.Background(), req, serverConn)
+		serverConn.Close()
+	}()
+
+	reply := make([]byte, 10)
+	if _, err := io.ReadFull(clientConn, reply); err != nil {
+		t.Fatalf("failed to read reply: %v", err)
+	}
+	if reply[1] != notAllowedByRuleSet {
+		t.Errorf("got reply code %d, want %d", reply[1], notAllowedByRuleSet)
+	}
+	if err := <-done; err == nil {
+		t.Errorf("handleTestEndpoint() returned nil error")
+	}
+}
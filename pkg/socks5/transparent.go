@@ -0,0 +1,91 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+var (
+	TransparentProxyMetricGroupName = "transparent proxy"
+
+	// TransparentProxyConnections is the number of redirected connections accepted.
+	TransparentProxyConnections = metrics.RegisterMetric(TransparentProxyMetricGroupName, "Connections", metrics.COUNTER)
+
+	// TransparentProxyConnErrors is the number of redirected connections failed
+	// to reach the original destination.
+	TransparentProxyConnErrors = metrics.RegisterMetric(TransparentProxyMetricGroupName, "ConnErrors", metrics.COUNTER)
+)
+
+// TransparentProxy forwards the TCP connections redirected by the firewall
+// to their original destinations through a socks5 server.
+type TransparentProxy struct {
+	// URI of the socks5 server, e.g. "socks5://127.0.0.1:1080?timeout=10s".
+	ProxyURI string
+
+	// If set, the traffic is counted for the device that makes the
+	// connection instead of this proxy.
+	FairShare *common.FairShareLimiter
+}
+
+// Serve accepts the redirected connections from the listener.
+// It returns when the listener is closed.
+func (p *TransparentProxy) Serve(l net.Listener) error {
+	dialFunc := Dial(p.ProxyURI, constant.Socks5ConnectCmd)
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		TransparentProxyConnections.Add(1)
+		go func() {
+			if err := p.serveConn(conn, dialFunc); err != nil {
+				TransparentProxyConnErrors.Add(1)
+				log.Debugf("transparent proxy failed to forward connection from %v: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+func (p *TransparentProxy) serveConn(conn net.Conn, dialFunc func(string, string) (net.Conn, error)) error {
+	defer conn.Close()
+	dst, err := originalDestination(conn)
+	if err != nil {
+		return err
+	}
+	// A connection made to the listener directly is not redirected.
+	// Forwarding it would connect to the listener again.
+	if local, ok := conn.LocalAddr().(*net.TCPAddr); ok && dst.IP.Equal(local.IP) && dst.Port == local.Port {
+		return fmt.Errorf("connection is not redirected by the firewall")
+	}
+	socksConn, err := dialFunc("tcp", dst.String())
+	if err != nil {
+		return fmt.Errorf("dial to socks5 server failed: %w", err)
+	}
+	defer p.FairShare.Alias(socksConn.LocalAddr(), conn.RemoteAddr())()
+	common.BidiCopy(conn, socksConn)
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"fmt"
+	"net"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// TransparentProxySupported is true if the transparent proxy can find
+// the original destination of the redirected connections.
+const TransparentProxySupported = true
+
+// originalDestination returns the destination of a TCP connection before
+// it is redirected by the iptables REDIRECT target or nftables redirect
+// statement.
+var originalDestination = func(conn net.Conn) (*net.TCPAddr, error) {
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil, fmt.Errorf("connection type %T is not TCP", conn)
+	}
+	local, ok := tcpConn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		return nil, fmt.Errorf("local address type %T is not TCP", tcpConn.LocalAddr())
+	}
+	rawConn, err := tcpConn.SyscallConn()
+	if err != nil {
+		return nil, fmt.Errorf("SyscallConn() failed: %w", err)
+	}
+	var dst *net.TCPAddr
+	var sockErr error
+	err = rawConn.Control(func(fd uintptr) {
+		if local.IP.To4() != nil {
+			// The option returns a sockaddr_in, which fits in the
+			// 16 bytes of ipv6_mreq.
+			var mreq *unix.IPv6Mreq
+			mreq, sockErr = unix.GetsockoptIPv6Mreq(int(fd), unix.SOL_IP, unix.SO_ORIGINAL_DST)
+			if sockErr != nil {
+				return
+			}
+			sa := (*unix.RawSockaddrInet4)(unsafe.Pointer(mreq))
+			dst = &net.TCPAddr{IP: net.IP(append([]byte{}, sa.Addr[:]...)), Port: networkPort(sa.Port)}
+		} else {
+			// IP6T_SO_ORIGINAL_DST has the same value as SO_ORIGINAL_DST.
+			// The option returns a sockaddr_in6, which fits in the
+			// 32 bytes of ip6_mtuinfo.
+			var info *unix.IPv6MTUInfo
+			info, sockErr = unix.GetsockoptIPv6MTUInfo(int(fd), unix.SOL_IPV6, unix.SO_ORIGINAL_DST)
+			if sockErr != nil {
+				return
+			}
+			dst = &net.TCPAddr{IP: net.IP(append([]byte{}, info.Addr.Addr[:]...)), Port: networkPort(info.Addr.Port)}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Control() failed: %w", err)
+	}
+	if sockErr != nil {
+		return nil, fmt.Errorf("getsockopt(SO_ORIGINAL_DST) failed: %w", sockErr)
+	}
+	return dst, nil
+}
+
+// networkPort converts a port number in network byte order to int.
+func networkPort(port uint16) int {
+	b := (*[2]byte)(unsafe.Pointer(&port))
+	return int(b[0])<<8 | int(b[1])
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/common"
+)
+
+func TestTransparentProxy(t *testing.T) {
+	// Create a local listener as the destination target.
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	// Create a socks server.
+	serv, err := New(&Config{
+		AllowLoopbackDestination: true,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	serverPort, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	go func() {
+		if err := serv.ListenAndServe("tcp", "127.0.0.1:"+strconv.Itoa(serverPort)); err != nil {
+			t.Errorf("ListenAndServe() failed: %v", err)
+		}
+	}()
+	defer serv.Close()
+	time.Sleep(200 * time.Millisecond)
+
+	// Pretend the connections to the transparent proxy are redirected
+	// from the target.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	var redirected atomic.Bool
+	redirected.Store(true)
+	originalDestinationBackup := originalDestination
+	originalDestination = func(conn net.Conn) (*net.TCPAddr, error) {
+		if redirected.Load() {
+			return target.Addr().(*net.TCPAddr), nil
+		}
+		return conn.LocalAddr().(*net.TCPAddr), nil
+	}
+	defer func() { originalDestination = originalDestinationBackup }()
+	proxy := &TransparentProxy{ProxyURI: "socks5://127.0.0.1:" + strconv.Itoa(serverPort) + "?timeout=10s"}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- proxy.Serve(l) }()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if !bytes.Equal(buf, []byte("ping")) {
+		t.Errorf("got %q, want %q", buf, "ping")
+	}
+	conn.Close()
+
+	// A connection that is not redirected is closed.
+	redirected.Store(false)
+	errorsBefore := TransparentProxyConnErrors.Load()
+	conn, err = net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() failed: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Read(buf); err == nil {
+		t.Errorf("Read() succeeded on a connection that is not redirected")
+	}
+	conn.Close()
+	if TransparentProxyConnErrors.Load() != errorsBefore+1 {
+		t.Errorf("TransparentProxyConnErrors = %d, want %d", TransparentProxyConnErrors.Load(), errorsBefore+1)
+	}
+
+	l.Close()
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			t.Errorf("Serve() failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Serve() is not stopped after the listener is closed")
+	}
+}
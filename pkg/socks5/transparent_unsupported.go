@@ -0,0 +1,32 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build !linux
+
+package socks5
+
+import (
+	"fmt"
+	"net"
+)
+
+// TransparentProxySupported is true if the transparent proxy can find
+// the original destination of the redirected connections.
+const TransparentProxySupported = false
+
+// originalDestination returns an error in unsupported platforms.
+var originalDestination = func(conn net.Conn) (*net.TCPAddr, error) {
+	return nil, fmt.Errorf("transparent proxy is only supported on Linux")
+}
@@ -16,10 +16,73 @@
 package socks5
 
 import (
+	"bytes"
 	"encoding/binary"
+	"fmt"
+	mrand "math/rand"
 	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/apis/model"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+)
+
+const (
+	// udpNATTableCapacity is the maximum number of remote peers
+	// recorded by a UDP association.
+	udpNATTableCapacity = 1024
+
+	// udpNATIdleTimeout is the time to keep a remote peer
+	// that doesn't send or receive any packet.
+	udpNATIdleTimeout = 5 * time.Minute
 )
 
+// UDPRelay specifies where the proxy server listens to relay
+// UDP packets of UDP associations.
+type UDPRelay struct {
+	// Local IP address to listen. If nil, listen to all the interfaces.
+	IP net.IP
+
+	// Range of local ports to listen. If MinPort is 0, a random port is used.
+	MinPort int
+	MaxPort int
+}
+
+// listenUDPRelay creates the UDP listener of a UDP association.
+// If a port range is configured, the ports in the range are tried
+// from a random position until one is available.
+func (s *Server) listenUDPRelay() (*net.UDPConn, error) {
+	s.configMu.RLock()
+	relay := s.config.UDPRelay
+	s.configMu.RUnlock()
+
+	ip := relay.IP
+	if ip == nil {
+		ip = net.ParseIP(common.AllIPAddr())
+	}
+	if relay.MinPort == 0 {
+		return net.ListenUDP("udp", &net.UDPAddr{IP: ip})
+	}
+	if relay.MinPort < 0 || relay.MaxPort < relay.MinPort || relay.MaxPort > 65535 {
+		return nil, fmt.Errorf("invalid UDP relay port range %d-%d", relay.MinPort, relay.MaxPort)
+	}
+	n := relay.MaxPort - relay.MinPort + 1
+	offset := mrand.Intn(n)
+	var lastErr error
+	for i := 0; i < n; i++ {
+		port := relay.MinPort + (offset+i)%n
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: ip, Port: port})
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no UDP port is available in range %d-%d: %w", relay.MinPort, relay.MaxPort, lastErr)
+}
+
 // udpAddrToHeader returns a UDP associate header with the given
 // destination address.
 func udpAddrToHeader(addr *net.UDPAddr) []byte {
@@ -37,3 +100,184 @@ func udpAddrToHeader(addr *net.UDPAddr) []byte {
 	}
 	return binary.BigEndian.AppendUint16(res, uint16(addr.Port))
 }
+
+// udpAssociateHeaderLength returns the length of the UDP associate
+// header at the beginning of b. It returns 0 if the header is invalid
+// or incomplete.
+func udpAssociateHeaderLength(b []byte) int {
+	if len(b) < 4 {
+		return 0
+	}
+	var n int
+	switch b[3] {
+	case 0x01:
+		n = 10
+	case 0x03:
+		if len(b) < 5 {
+			return 0
+		}
+		n = 7 + int(b[4])
+	case 0x04:
+		n = 22
+	default:
+		return 0
+	}
+	if len(b) < n {
+		return 0
+	}
+	return n
+}
+
+// udpAssociation is the UDP relay created by a UDP ASSOCIATE request
+// in the proxy client.
+type udpAssociation struct {
+	conn   *net.UDPConn
+	filter *udpSourceFilter
+}
+
+// udpSourceFilter decides if a datagram received by the UDP relay
+// is sent by the socks5 client that requested the UDP association.
+type udpSourceFilter struct {
+	ip   net.IP // allowed source IP address, nil if any IP address is allowed
+	port int    // allowed source port, 0 if any port is allowed
+}
+
+// newUDPSourceFilter returns the source filter of a UDP association.
+// declared is the client address in the UDP ASSOCIATE request, and
+// controlAddr is the remote address of the socks5 TCP connection.
+func newUDPSourceFilter(mode appctlpb.UDPSourceFilter, declared model.AddrSpec, controlAddr net.Addr) *udpSourceFilter {
+	var controlIP net.IP
+	if host, _, err := net.SplitHostPort(controlAddr.String()); err == nil {
+		controlIP = net.ParseIP(host)
+	}
+	switch mode {
+	case appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_OFF:
+		return &udpSourceFilter{}
+	case appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_STRICT:
+		ip := declared.IP
+		if ip == nil || ip.IsUnspecified() {
+			ip = controlIP
+		}
+		return &udpSourceFilter{ip: ip, port: declared.Port}
+	default:
+		return &udpSourceFilter{ip: controlIP}
+	}
+}
+
+// accept returns true if the datagram from the address can be relayed.
+func (f *udpSourceFilter) accept(addr net.Addr) bool {
+	if f == nil {
+		return true
+	}
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return f.ip == nil && f.port == 0
+	}
+	if f.ip != nil && !f.ip.Equal(udpAddr.IP) {
+		return false
+	}
+	return f.port == 0 || f.port == udpAddr.Port
+}
+
+func (f *udpSourceFilter) String() string {
+	if f.ip == nil && f.port == 0 {
+		return "any"
+	}
+	ip := "*"
+	if f.ip != nil {
+		ip = common.MaybeDecorateIPv6(f.ip.String())
+	}
+	port := "*"
+	if f.port != 0 {
+		port = strconv.Itoa(f.port)
+	}
+	return ip + ":" + port
+}
+
+// udpNATTable records the remote peers of a UDP association. For each peer,
+// it keeps the UDP associate header the proxy client used to reach it,
+// so replies from the peer are sent back with the same address.
+type udpNATTable struct {
+	mu      sync.Mutex
+	entries map[string]*udpNATEntry
+}
+
+type udpNATEntry struct {
+	header   []byte
+	lastUsed time.Time
+}
+
+func newUDPNATTable() *udpNATTable {
+	return &udpNATTable{entries: make(map[string]*udpNATEntry)}
+}
+
+// add records that the proxy client sends a packet to the peer
+// with the UDP associate header. The header is copied.
+func (t *udpNATTable) add(peer *net.UDPAddr, header []byte, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := peer.String()
+	if entry, ok := t.entries[key]; ok {
+		if !bytes.Equal(entry.header, header) {
+			entry.header = append([]byte{}, header...)
+		}
+		entry.lastUsed = now
+		return
+	}
+	if len(t.entries) >= udpNATTableCapacity {
+		t.evict(now)
+	}
+	t.entries[key] = &udpNATEntry{header: append([]byte{}, header...), lastUsed: now}
+	UDPAssociatePeers.Add(1)
+}
+
+// replyHeader returns the UDP associate header of a packet received
+// from the peer. If the peer is unknown, for example a STUN server replies
+// from another address, the header is built from the peer address.
+func (t *udpNATTable) replyHeader(peer *net.UDPAddr, now time.Time) []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if entry, ok := t.entries[peer.String()]; ok {
+		entry.lastUsed = now
+		return entry.header
+	}
+	UDPAssociateUnknownPeerPackets.Add(1)
+	return udpAddrToHeader(peer)
+}
+
+// len returns the number of recorded peers.
+func (t *udpNATTable) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.entries)
+}
+
+// close removes all the recorded peers.
+func (t *udpNATTable) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	UDPAssociatePeers.Add(-int64(len(t.entries)))
+	t.entries = make(map[string]*udpNATEntry)
+}
+
+// evict removes idle peers. If no peer is idle, the least recently
+// used peer is removed. It must be called with the lock held.
+func (t *udpNATTable) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range t.entries {
+		if now.Sub(entry.lastUsed) >= udpNATIdleTimeout {
+			delete(t.entries, key)
+			UDPAssociatePeers.Add(-1)
+			continue
+		}
+		if oldestKey == "" || entry.lastUsed.Before(oldest) {
+			oldestKey = key
+			oldest = entry.lastUsed
+		}
+	}
+	if len(t.entries) >= udpNATTableCapacity {
+		delete(t.entries, oldestKey)
+		UDPAssociatePeers.Add(-1)
+	}
+}
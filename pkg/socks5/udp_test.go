@@ -19,6 +19,10 @@ import (
 	"bytes"
 	"net"
 	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/apis/model"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
 )
 
 func TestUDPAddrToHeader(t *testing.T) {
@@ -43,3 +47,171 @@ func TestUDPAddrToHeader(t *testing.T) {
 		}
 	}
 }
+
+func TestUDPAssociateHeaderLength(t *testing.T) {
+	testcases := []struct {
+		b    []byte
+		want int
+	}{
+		{[]byte{0, 0, 0, 1, 127, 0, 0, 1, 0, 53, 0xff}, 10},
+		{[]byte{0, 0, 0, 3, 3, 'a', 'b', 'c', 0, 53}, 10},
+		{[]byte{0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 53}, 22},
+		{[]byte{0, 0, 0, 1, 127, 0, 0}, 0},
+		{[]byte{0, 0, 0, 3}, 0},
+		{[]byte{0, 0, 0, 2, 0, 0, 0, 0, 0, 0}, 0},
+		{nil, 0},
+	}
+
+	for _, tc := range testcases {
+		if got := udpAssociateHeaderLength(tc.b); got != tc.want {
+			t.Errorf("udpAssociateHeaderLength(%v) = %d, want %d", tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestUDPNATTable(t *testing.T) {
+	table := newUDPNATTable()
+	defer table.close()
+	now := time.Now()
+
+	// The header of a known peer is copied, so reusing the read buffer
+	// doesn't change it.
+	peer1 := &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 3478}
+	buf := []byte{0, 0, 0, 3, 11, 's', 't', 'u', 'n', '.', 'l', '.', 'c', 'o', 'm', 0x0d, 0x96}
+	table.add(peer1, buf, now)
+	want := append([]byte{}, buf...)
+	for i := range buf {
+		buf[i] = 0xff
+	}
+	if got := table.replyHeader(peer1, now); !bytes.Equal(got, want) {
+		t.Errorf("replyHeader(%v) = %v, want %v", peer1, got, want)
+	}
+
+	// The header of an unknown peer is built from the peer address.
+	peer2 := &net.UDPAddr{IP: net.ParseIP("192.0.2.2"), Port: 3479}
+	unknownCnt := UDPAssociateUnknownPeerPackets.Load()
+	if got := table.replyHeader(peer2, now); !bytes.Equal(got, udpAddrToHeader(peer2)) {
+		t.Errorf("replyHeader(%v) = %v, want %v", peer2, got, udpAddrToHeader(peer2))
+	}
+	if UDPAssociateUnknownPeerPackets.Load() != unknownCnt+1 {
+		t.Errorf("UDPAssociateUnknownPeerPackets is not updated")
+	}
+	if table.len() != 1 {
+		t.Errorf("len() = %d, want 1", table.len())
+	}
+}
+
+func TestUDPNATTableEvict(t *testing.T) {
+	table := newUDPNATTable()
+	defer table.close()
+	now := time.Now()
+
+	// Fill the table. The first peer is idle, the second peer is the
+	// least recently used.
+	idle := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 0), Port: 1}
+	table.add(idle, udpAddrToHeader(idle), now.Add(-udpNATIdleTimeout))
+	lru := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 0), Port: 2}
+	table.add(lru, udpAddrToHeader(lru), now.Add(-time.Minute))
+	for i := 3; i <= udpNATTableCapacity; i++ {
+		peer := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 0), Port: i}
+		table.add(peer, udpAddrToHeader(peer), now)
+	}
+	if table.len() != udpNATTableCapacity {
+		t.Fatalf("len() = %d, want %d", table.len(), udpNATTableCapacity)
+	}
+
+	// The idle peer is removed.
+	peer := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1}
+	table.add(peer, udpAddrToHeader(peer), now)
+	if table.len() != udpNATTableCapacity {
+		t.Fatalf("len() = %d, want %d", table.len(), udpNATTableCapacity)
+	}
+	if _, ok := table.entries[idle.String()]; ok {
+		t.Errorf("idle peer is not removed")
+	}
+
+	// The least recently used peer is removed.
+	peer = &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 2}
+	table.add(peer, udpAddrToHeader(peer), now)
+	if table.len() != udpNATTableCapacity {
+		t.Fatalf("len() = %d, want %d", table.len(), udpNATTableCapacity)
+	}
+	if _, ok := table.entries[lru.String()]; ok {
+		t.Errorf("least recently used peer is not removed")
+	}
+}
+
+func TestUDPSourceFilter(t *testing.T) {
+	controlAddr := &net.TCPAddr{IP: net.ParseIP("192.168.1.2"), Port: 50000}
+	declared := model.AddrSpec{IP: net.ParseIP("192.168.1.2"), Port: 6000}
+	unspecified := model.AddrSpec{IP: net.IPv4zero, Port: 0}
+	sameAddr := &net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: 6000}
+	samePort := &net.UDPAddr{IP: net.ParseIP("192.168.1.3"), Port: 6000}
+	sameIP := &net.UDPAddr{IP: net.ParseIP("192.168.1.2"), Port: 7000}
+	other := &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 7000}
+
+	testcases := []struct {
+		mode     appctlpb.UDPSourceFilter
+		declared model.AddrSpec
+		addr     *net.UDPAddr
+		want     bool
+	}{
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_STRICT, declared, sameAddr, true},
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_STRICT, declared, samePort, false},
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_STRICT, declared, sameIP, false},
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_STRICT, unspecified, sameIP, true},
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_STRICT, unspecified, other, false},
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_SAME_IP, declared, sameIP, true},
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_SAME_IP, declared, samePort, false},
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_DEFAULT, unspecified, sameIP, true},
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_DEFAULT, unspecified, other, false},
+		{appctlpb.UDPSourceFilter_UDP_SOURCE_FILTER_OFF, declared, other, true},
+	}
+
+	for _, tc := range testcases {
+		f := newUDPSourceFilter(tc.mode, tc.declared, controlAddr)
+		if got := f.accept(tc.addr); got != tc.want {
+			t.Errorf("filter %v with mode %v: accept(%v) = %v, want %v", f, tc.mode, tc.addr, got, tc.want)
+		}
+	}
+}
+
+func TestListenUDPRelay(t *testing.T) {
+	// Find a free port on the loopback address as the begin of the range.
+	c, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatalf("ListenUDP() failed: %v", err)
+	}
+	minPort := c.LocalAddr().(*net.UDPAddr).Port
+	c.Close()
+	if minPort == 65535 {
+		t.Skipf("no room for the port range")
+	}
+
+	s := &Server{config: &Config{}}
+	s.SetUDPRelay(UDPRelay{IP: net.ParseIP("127.0.0.1"), MinPort: minPort, MaxPort: minPort + 1})
+	conn1, err := s.listenUDPRelay()
+	if err != nil {
+		t.Skipf("listenUDPRelay() failed: %v", err)
+	}
+	defer conn1.Close()
+	conn2, err := s.listenUDPRelay()
+	if err != nil {
+		t.Skipf("listenUDPRelay() failed: %v", err)
+	}
+	defer conn2.Close()
+	addr1 := conn1.LocalAddr().(*net.UDPAddr)
+	addr2 := conn2.LocalAddr().(*net.UDPAddr)
+	if !addr1.IP.Equal(net.ParseIP("127.0.0.1")) || !addr2.IP.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("UDP relay listens to %v and %v, want 127.0.0.1", addr1.IP, addr2.IP)
+	}
+	if addr1.Port == addr2.Port || addr1.Port < minPort || addr1.Port > minPort+1 || addr2.Port < minPort || addr2.Port > minPort+1 {
+		t.Errorf("UDP relay ports %d and %d are not distinct ports in range %d-%d", addr1.Port, addr2.Port, minPort, minPort+1)
+	}
+
+	// All the ports in the range are used.
+	if conn3, err := s.listenUDPRelay(); err == nil {
+		conn3.Close()
+		t.Errorf("listenUDPRelay() succeeded when no port is available")
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+// UpstreamProxyProtocol is the protocol of an upstream proxy.
+type UpstreamProxyProtocol string
+
+const (
+	UpstreamSocks5 UpstreamProxyProtocol = "socks5"
+	UpstreamHTTP   UpstreamProxyProtocol = "http"
+)
+
+// upstreamHandshakeTimeout is the maximum time to set up a tunnel through
+// the upstream proxy if the context has no deadline.
+const upstreamHandshakeTimeout = 10 * time.Second
+
+var (
+	UpstreamMetricGroupName = "upstream proxy"
+
+	// UpstreamDials is the number of connections dialed through the upstream proxy.
+	UpstreamDials = metrics.RegisterMetric(UpstreamMetricGroupName, "Dials", metrics.COUNTER)
+
+	// UpstreamDialErrors is the number of failures to dial through the upstream proxy.
+	UpstreamDialErrors = metrics.RegisterMetric(UpstreamMetricGroupName, "DialErrors", metrics.COUNTER)
+)
+
+// UpstreamDialer dials TCP connections through an upstream socks5 or
+// HTTP CONNECT proxy, e.g. a corporate proxy.
+type UpstreamDialer struct {
+	// Protocol of the upstream proxy.
+	Protocol UpstreamProxyProtocol
+
+	// Address of the upstream proxy in "host:port" format.
+	Address string
+
+	// Credential to authenticate with the upstream proxy. Optional.
+	Credential *Credential
+
+	// Dialer to connect to the upstream proxy.
+	// If nil, a default net.Dialer is used.
+	Dialer apicommon.Dialer
+}
+
+var _ apicommon.Dialer = &UpstreamDialer{}
+
+// DialContext connects to the address through the upstream proxy.
+// Only TCP networks are supported.
+func (d *UpstreamDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("upstream proxy doesn't support network %q", network)
+	}
+	UpstreamDials.Add(1)
+	conn, err := d.dial(ctx, address)
+	if err != nil {
+		UpstreamDialErrors.Add(1)
+		return nil, fmt.Errorf("dial %s through upstream proxy %s failed: %w", address, d.Address, err)
+	}
+	return conn, nil
+}
+
+func (d *UpstreamDialer) dial(ctx context.Context, address string) (net.Conn, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, upstreamHandshakeTimeout)
+		defer cancel()
+	}
+	dialer := d.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", d.Address)
+	if err != nil {
+		return nil, err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	switch d.Protocol {
+	case UpstreamSocks5:
+		c := &Client{Host: d.Address, Credential: d.Credential, CmdType: constant.Socks5ConnectCmd}
+		if _, err := c.handshake(ctx, conn, address); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	case UpstreamHTTP:
+		conn, err = d.httpConnect(conn, address)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		conn.Close()
+		return nil, fmt.Errorf("unsupported upstream proxy protocol %q", d.Protocol)
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// httpConnect sends a HTTP CONNECT request to the upstream proxy.
+// The connection is closed if the request is not successful.
+func (d *UpstreamDialer) httpConnect(conn net.Conn, address string) (net.Conn, error) {
+	var req strings.Builder
+	req.WriteString("CONNECT " + address + " HTTP/1.1\r\n")
+	req.WriteString("Host: " + address + "\r\n")
+	if d.Credential != nil {
+		auth := base64.StdEncoding.EncodeToString([]byte(d.Credential.User + ":" + d.Credential.Password))
+		req.WriteString("Proxy-Authorization: Basic " + auth + "\r\n")
+	}
+	req.WriteString("\r\n")
+	if _, err := conn.Write([]byte(req.String())); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("HTTP CONNECT response status is %q", resp.Status)
+	}
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn returns the data buffered by the reader before reading
+// from the connection.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
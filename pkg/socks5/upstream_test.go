@@ -0,0 +1,101 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package socks5
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/testtool"
+)
+
+func TestUpstreamDialer(t *testing.T) {
+	rot13Listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	rot13Server := testtool.NewTestHelperServer()
+	go rot13Server.Serve(rot13Listener)
+	defer func() {
+		rot13Server.Close()
+		rot13Listener.Close()
+	}()
+
+	socksPort, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	newTestSocksServer(socksPort)
+	httpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	httpServer := NewHTTPProxyServer(httpListener.Addr().String(), &HTTPProxy{
+		ProxyURI: fmt.Sprintf("socks5://127.0.0.1:%d", socksPort),
+	})
+	go httpServer.Serve(httpListener)
+	defer httpServer.Close()
+
+	testCases := []struct {
+		name   string
+		dialer *UpstreamDialer
+	}{
+		{
+			name:   "socks5",
+			dialer: &UpstreamDialer{Protocol: UpstreamSocks5, Address: fmt.Sprintf("127.0.0.1:%d", socksPort)},
+		},
+		{
+			name:   "http",
+			dialer: &UpstreamDialer{Protocol: UpstreamHTTP, Address: httpListener.Addr().String()},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			conn, err := tc.dialer.DialContext(ctx, "tcp", rot13Listener.Addr().String())
+			if err != nil {
+				t.Fatalf("DialContext() failed: %v", err)
+			}
+			defer conn.Close()
+			in := testtool.TestHelperGenRot13Input(64)
+			want, _ := testtool.TestHelperRot13(in)
+			if _, err := conn.Write(in); err != nil {
+				t.Fatalf("Write() failed: %v", err)
+			}
+			got := make([]byte, len(want))
+			if _, err := io.ReadFull(conn, got); err != nil {
+				t.Fatalf("io.ReadFull() failed: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestUpstreamDialerRejectUDP(t *testing.T) {
+	d := &UpstreamDialer{Protocol: UpstreamSocks5, Address: "127.0.0.1:1080"}
+	if _, err := d.DialContext(context.Background(), "udp", "127.0.0.1:53"); err == nil {
+		t.Errorf("DialContext() with UDP network succeeded")
+	}
+}
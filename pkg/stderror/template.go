@@ -16,6 +16,7 @@
 package stderror
 
 const (
+	AddUserFailedErr                         = "add user failed: %w"
 	ClientConfigIsEmpty                      = "mieru client config is empty"
 	ClientConfigNotExist                     = "mieru client config file doesn't exist"
 	ClientGetActiveProfileFailedErr          = "mieru client get active profile failed: %w"
@@ -26,22 +27,37 @@ const (
 	CreateServerManagementRPCClientFailedErr = "create mita server management RPC client failed: %w"
 	CreateSocks5ServerFailedErr              = "create socks5 server failed: %w"
 	DecodeHashedPasswordFailedErr            = "decode hashed password failed: %w"
+	DeleteUsersFailedErr                     = "delete users failed: %w"
 	ExitFailedErr                            = "process exit failed: %w"
+	FollowLogsFailedErr                      = "follow logs failed: %w"
 	GetClientConfigFailedErr                 = "get mieru client config failed: %w"
 	GetConnectionsFailedErr                  = "get connections failed: %w"
+	GetCountryTrafficFailedErr               = "get country traffic failed: %w"
+	GetDestinationTrafficFailedErr           = "get destination traffic failed: %w"
 	GetHeapProfileFailedErr                  = "get heap profile failed: %w"
 	GetMemoryStatisticsFailedErr             = "get memory statistics failed: %w"
 	GetMetricsFailedErr                      = "get metrics failed: %w"
+	GetPortBindingsFailedErr                 = "get port bindings failed: %w"
+	GetProxyConnectionsFailedErr             = "get proxy connections failed: %w"
 	GetServerConfigFailedErr                 = "get mita server config failed: %w"
 	GetServerStatusFailedErr                 = "get mita server status failed: %w"
+	GetServiceStatusFailedErr                = "get mita service status failed: %w"
+	GetSessionsFailedErr                     = "get sessions failed: %w"
 	GetThreadDumpFailedErr                   = "get thread dump failed: %w"
 	GetUsersFailedErr                        = "get users failed: %w"
+	GetUserGroupsFailedErr                   = "get user groups failed: %w"
+	InstallServiceFailedErr                  = "install mita service failed: %w"
+	InvalidConfigOverridesErr                = "invalid config overrides: %w"
 	InvalidPortBindingsErr                   = "invalid port bindings: %w"
 	InvalidTransportProtocol                 = "invalid transport protocol"
 	IPAddressNotFound                        = "IP address not found from domain name %q"
+	KillSessionFailedErr                     = "kill session failed: %w"
 	LookupIPFailedErr                        = "look up IP address failed: %w"
 	ParseIPFailed                            = "parse IP address failed"
+	ProbeServersFailedErr                    = "probe proxy servers failed: %w"
+	ReloadClientFailedErr                    = "reload mieru client failed: %w"
 	ReloadServerFailedErr                    = "reload mita server failed: %w"
+	RotateKeysFailedErr                      = "rotate keys failed: %w"
 	SegmentSizeTooBig                        = "segment size too big"
 	ServerNotRunningErr                      = "mita server daemon is not running: %w"
 	ServerNotRunningWithCommand              = "mita server daemon is not running; please run command \"sudo systemctl restart mita\" to start server daemon; run command \"sudo journalctl -e -u mita --no-pager\" to check log if unable to start"
@@ -52,6 +68,10 @@ const (
 	StartServerProxyFailedErr                = "start mita server proxy failed: %w"
 	StopServerProxyFailedErr                 = "stop mita server proxy failed: %w"
 	StoreClientConfigFailedErr               = "store mieru client config failed: %w"
+	UninstallServiceFailedErr                = "uninstall mita service failed: %w"
+	UpdateSubscriptionsFailedErr             = "update subscriptions failed: %w"
+	UpdateUserFailedErr                      = "update user failed: %w"
 	ValidateFullClientConfigFailedErr        = "validate full client config failed: %w"
 	ValidateServerConfigPatchFailedErr       = "validate server config patch failed: %w"
+	VerifyConfigFailedErr                    = "config is invalid: %w"
 )
@@ -0,0 +1,203 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package systemd receives the sockets passed by systemd socket activation,
+// as described in sd_listen_fds(3). With socket activation, systemd binds
+// the ports and starts the program on demand, so the program doesn't need
+// the privilege to bind privileged ports.
+package systemd
+
+import (
+	"net"
+	"os"
+	"sync"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+)
+
+const (
+	// listenFdsStart is the first file descriptor passed by systemd.
+	listenFdsStart = 3
+)
+
+var (
+	// sockets are the sockets passed by systemd that are not used yet.
+	sockets     []*socket
+	socketsOnce sync.Once
+	socketsMu   sync.Mutex
+)
+
+// socket is a stream socket or a datagram socket passed by systemd.
+type socket struct {
+	name       string
+	listener   net.Listener
+	packetConn net.PacketConn
+}
+
+func (s *socket) addr() net.Addr {
+	if s.listener != nil {
+		return s.listener.Addr()
+	}
+	return s.packetConn.LocalAddr()
+}
+
+// load reads the sockets passed by systemd the first time it is called.
+// The environment variables of socket activation are removed, so they
+// are not inherited by the child processes.
+func load() {
+	socketsOnce.Do(func() {
+		for _, f := range inheritedFiles() {
+			s := &socket{name: f.Name()}
+			if l, err := net.FileListener(f); err == nil {
+				s.listener = l
+			} else if conn, err := net.FilePacketConn(f); err == nil {
+				s.packetConn = conn
+			} else {
+				log.Warnf("socket %q from systemd is not supported: %v", f.Name(), err)
+				f.Close()
+				continue
+			}
+			// The listener and the connection have their own copy of the file descriptor.
+			f.Close()
+			log.Infof("received socket %q %v from systemd", s.name, s.addr())
+			sockets = append(sockets, s)
+		}
+	})
+}
+
+// take removes and returns the first socket that matches.
+func take(match func(*socket) bool) *socket {
+	load()
+	socketsMu.Lock()
+	defer socketsMu.Unlock()
+	for i, s := range sockets {
+		if match(s) {
+			sockets = append(sockets[:i], sockets[i+1:]...)
+			return s
+		}
+	}
+	return nil
+}
+
+// TakeListener returns the stream socket passed by systemd that listens
+// to the address. The network is "tcp", "tcp4", "tcp6" or "unix".
+// It returns nil if there is no such socket. A socket is only returned once.
+func TakeListener(network, address string) net.Listener {
+	var match func(net.Addr) bool
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		laddr, err := net.ResolveTCPAddr(network, address)
+		if err != nil {
+			return nil
+		}
+		match = func(a net.Addr) bool {
+			tcpAddr, ok := a.(*net.TCPAddr)
+			return ok && ipPortMatch(tcpAddr.IP, tcpAddr.Port, laddr.IP, laddr.Port)
+		}
+	case "unix":
+		match = func(a net.Addr) bool {
+			unixAddr, ok := a.(*net.UnixAddr)
+			return ok && unixAddr.Name == address
+		}
+	default:
+		return nil
+	}
+	s := take(func(s *socket) bool {
+		return s.listener != nil && match(s.listener.Addr())
+	})
+	if s == nil {
+		return nil
+	}
+	return s.listener
+}
+
+// Listen returns the stream socket passed by systemd that listens to
+// the address. If there is no such socket, it announces on the address
+// with net.Listen.
+func Listen(network, address string) (net.Listener, error) {
+	if l := TakeListener(network, address); l != nil {
+		return l, nil
+	}
+	return net.Listen(network, address)
+}
+
+// ListenTCP returns the TCP socket passed by systemd that listens to
+// the address. If there is no such socket, it announces on the address
+// with net.ListenTCP.
+func ListenTCP(network string, laddr *net.TCPAddr) (*net.TCPListener, error) {
+	s := take(func(s *socket) bool {
+		if s.listener == nil {
+			return false
+		}
+		tcpAddr, ok := s.listener.Addr().(*net.TCPAddr)
+		return ok && ipPortMatch(tcpAddr.IP, tcpAddr.Port, laddr.IP, laddr.Port)
+	})
+	if s != nil {
+		return s.listener.(*net.TCPListener), nil
+	}
+	return net.ListenTCP(network, laddr)
+}
+
+// ListenUDP returns the UDP socket passed by systemd that listens to
+// the address. If there is no such socket, it announces on the address
+// with net.ListenUDP.
+func ListenUDP(network string, laddr *net.UDPAddr) (*net.UDPConn, error) {
+	s := take(func(s *socket) bool {
+		if s.packetConn == nil {
+			return false
+		}
+		udpAddr, ok := s.packetConn.LocalAddr().(*net.UDPAddr)
+		return ok && ipPortMatch(udpAddr.IP, udpAddr.Port, laddr.IP, laddr.Port)
+	})
+	if s != nil {
+		return s.packetConn.(*net.UDPConn), nil
+	}
+	return net.ListenUDP(network, laddr)
+}
+
+// Unused returns the addresses of the sockets passed by systemd
+// that are not used yet.
+func Unused() []net.Addr {
+	load()
+	socketsMu.Lock()
+	defer socketsMu.Unlock()
+	addrs := make([]net.Addr, 0, len(sockets))
+	for _, s := range sockets {
+		addrs = append(addrs, s.addr())
+	}
+	return addrs
+}
+
+// ipPortMatch returns true if the socket address matches the wanted address.
+// An unspecified IP address only matches another unspecified IP address,
+// because systemd listens to both IPv4 and IPv6 by default.
+func ipPortMatch(ip net.IP, port int, wantIP net.IP, wantPort int) bool {
+	if port != wantPort {
+		return false
+	}
+	if len(wantIP) == 0 || wantIP.IsUnspecified() {
+		return len(ip) == 0 || ip.IsUnspecified()
+	}
+	return ip.Equal(wantIP)
+}
+
+// inheritedFiles returns the files of the sockets passed by systemd,
+// and removes the environment variables of socket activation.
+func inheritedFiles() []*os.File {
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+	defer os.Unsetenv("LISTEN_FDNAMES")
+	return listenFiles(os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), os.Getenv("LISTEN_FDNAMES"), os.Getpid())
+}
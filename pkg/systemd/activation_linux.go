@@ -0,0 +1,53 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+
+package systemd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// listenFiles returns the files of the sockets passed by systemd.
+// It returns nil if the sockets are passed to another process.
+func listenFiles(listenPID, listenFDs, listenFDNames string, pid int) []*os.File {
+	if p, err := strconv.Atoi(listenPID); err != nil || p != pid {
+		return nil
+	}
+	n, err := strconv.Atoi(listenFDs)
+	if err != nil || n <= 0 {
+		return nil
+	}
+	var names []string
+	if listenFDNames != "" {
+		names = strings.Split(listenFDNames, ":")
+	}
+	files := make([]*os.File, 0, n)
+	for i := 0; i < n; i++ {
+		fd := listenFdsStart + i
+		syscall.CloseOnExec(fd)
+		name := fmt.Sprintf("LISTEN_FD_%d", fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		files = append(files, os.NewFile(uintptr(fd), name))
+	}
+	return files
+}
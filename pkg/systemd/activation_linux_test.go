@@ -0,0 +1,32 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build linux
+
+package systemd
+
+import "testing"
+
+func TestListenFiles(t *testing.T) {
+	if files := listenFiles("1", "2", "", 2); files != nil {
+		t.Errorf("got %d files passed to another process, want none", len(files))
+	}
+	if files := listenFiles("2", "0", "", 2); files != nil {
+		t.Errorf("got %d files when no file is passed, want none", len(files))
+	}
+	if files := listenFiles("2", "x", "", 2); files != nil {
+		t.Errorf("got %d files with invalid LISTEN_FDS, want none", len(files))
+	}
+}
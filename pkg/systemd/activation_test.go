@@ -0,0 +1,115 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package systemd
+
+import (
+	"net"
+	"testing"
+)
+
+func addTestSocket(t *testing.T, s *socket) {
+	t.Helper()
+	load()
+	socketsMu.Lock()
+	defer socketsMu.Unlock()
+	sockets = append(sockets, s)
+}
+
+func TestIPPortMatch(t *testing.T) {
+	testCases := []struct {
+		ip       string
+		port     int
+		wantIP   string
+		wantPort int
+		want     bool
+	}{
+		{"::", 443, "", 443, true},
+		{"::", 443, "0.0.0.0", 443, true},
+		{"0.0.0.0", 443, "::", 443, true},
+		{"::", 443, "", 8443, false},
+		{"127.0.0.1", 443, "127.0.0.1", 443, true},
+		{"127.0.0.1", 443, "127.0.0.2", 443, false},
+		{"::", 443, "127.0.0.1", 443, false},
+		{"127.0.0.1", 443, "", 443, false},
+	}
+	for _, tc := range testCases {
+		if got := ipPortMatch(net.ParseIP(tc.ip), tc.port, net.ParseIP(tc.wantIP), tc.wantPort); got != tc.want {
+			t.Errorf("ipPortMatch(%q, %d, %q, %d) = %v, want %v", tc.ip, tc.port, tc.wantIP, tc.wantPort, got, tc.want)
+		}
+	}
+}
+
+func TestListenTCP(t *testing.T) {
+	l, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatalf("net.ListenTCP() failed: %v", err)
+	}
+	defer l.Close()
+	addTestSocket(t, &socket{name: "proxy", listener: l})
+	laddr := l.Addr().(*net.TCPAddr)
+
+	got, err := ListenTCP("tcp", laddr)
+	if err != nil {
+		t.Fatalf("ListenTCP() failed: %v", err)
+	}
+	if got != l {
+		t.Errorf("ListenTCP() didn't return the socket from systemd")
+	}
+
+	// The socket is only returned once.
+	if TakeListener("tcp", laddr.String()) != nil {
+		t.Errorf("socket from systemd is returned twice")
+	}
+	if _, err := ListenTCP("tcp", laddr); err == nil {
+		t.Errorf("ListenTCP() to an address in use succeeded")
+	}
+}
+
+func TestListenUDP(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatalf("net.ListenUDP() failed: %v", err)
+	}
+	defer conn.Close()
+	addTestSocket(t, &socket{name: "proxy", packetConn: conn})
+	laddr := conn.LocalAddr().(*net.UDPAddr)
+
+	// A UDP socket is not a listener.
+	if TakeListener("tcp", laddr.String()) != nil {
+		t.Errorf("TakeListener() returned a UDP socket")
+	}
+	if len(Unused()) == 0 {
+		t.Errorf("Unused() doesn't return the UDP socket")
+	}
+	got, err := ListenUDP("udp", laddr)
+	if err != nil {
+		t.Fatalf("ListenUDP() failed: %v", err)
+	}
+	if got != conn {
+		t.Errorf("ListenUDP() didn't return the socket from systemd")
+	}
+}
+
+func TestListenWithoutSystemd(t *testing.T) {
+	l, err := Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() failed: %v", err)
+	}
+	l.Close()
+	if TakeListener("udp", "127.0.0.1:0") != nil {
+		t.Errorf("TakeListener() with unsupported network returned a socket")
+	}
+}
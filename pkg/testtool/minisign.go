@@ -0,0 +1,73 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package testtool
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// MinisignSigner creates signatures in the same format as minisign.
+type MinisignSigner struct {
+	id  [8]byte
+	pub ed25519.PublicKey
+	key ed25519.PrivateKey
+}
+
+// NewMinisignSigner creates a signer with a random key pair.
+func NewMinisignSigner() (*MinisignSigner, error) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("ed25519.GenerateKey() failed: %w", err)
+	}
+	s := &MinisignSigner{pub: pub, key: key}
+	if _, err := rand.Read(s.id[:]); err != nil {
+		return nil, fmt.Errorf("rand.Read() failed: %w", err)
+	}
+	return s, nil
+}
+
+// KeyID returns the key ID in the format printed by minisign.
+func (s *MinisignSigner) KeyID() string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(s.id[:]))
+}
+
+// PublicKeyFile returns the content of the minisign public key file.
+func (s *MinisignSigner) PublicKeyFile() string {
+	b := append(append([]byte("Ed"), s.id[:]...), s.pub...)
+	return fmt.Sprintf("untrusted comment: minisign public key %s\n%s\n", s.KeyID(), base64.StdEncoding.EncodeToString(b))
+}
+
+// Sign returns the content of the minisign signature file of the message.
+// If hashed is true, the BLAKE2b-512 hash of the message is signed.
+func (s *MinisignSigner) Sign(message []byte, hashed bool, trustedComment string) []byte {
+	alg := []byte("Ed")
+	signed := message
+	if hashed {
+		alg = []byte("ED")
+		h := blake2b.Sum512(message)
+		signed = h[:]
+	}
+	sig := ed25519.Sign(s.key, signed)
+	global := ed25519.Sign(s.key, append(append([]byte{}, sig...), trustedComment...))
+	line := append(append(alg, s.id[:]...), sig...)
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(line), trustedComment, base64.StdEncoding.EncodeToString(global)))
+}
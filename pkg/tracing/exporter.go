@@ -0,0 +1,267 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+)
+
+const (
+	// maxQueuedSpans is the maximum number of spans waiting to be exported.
+	// New spans are dropped when the queue is full.
+	maxQueuedSpans = 4096
+
+	// maxBatchSize is the maximum number of spans in one export request.
+	maxBatchSize = 512
+
+	// exportInterval is the maximum time a span waits before it is exported.
+	exportInterval = 5 * time.Second
+
+	// exportTimeout is the timeout of one export request.
+	exportTimeout = 10 * time.Second
+)
+
+var (
+	// ExportedSpans is the number of spans sent to the collector.
+	ExportedSpans = metrics.RegisterMetric("tracing", "ExportedSpans", metrics.COUNTER)
+
+	// DroppedSpans is the number of spans dropped before they are exported.
+	DroppedSpans = metrics.RegisterMetric("tracing", "DroppedSpans", metrics.COUNTER)
+
+	// ExportErrors is the number of failed export requests.
+	ExportErrors = metrics.RegisterMetric("tracing", "ExportErrors", metrics.COUNTER)
+)
+
+// Exporter sends finished spans to an OpenTelemetry collector
+// using OTLP/HTTP protocol with JSON encoding.
+type Exporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+
+	queue     chan *Span
+	die       chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewExporter creates a new exporter and starts the export loop in background.
+// The endpoint is the base URL of the collector, e.g. http://127.0.0.1:4318
+// Spans are sent to the "/v1/traces" path of the endpoint.
+func NewExporter(endpoint, serviceName string) (*Exporter, error) {
+	if err := ValidateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+	e := &Exporter{
+		url:         strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: exportTimeout},
+		queue:       make(chan *Span, maxQueuedSpans),
+		die:         make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go e.exportLoop()
+	return e, nil
+}
+
+// ValidateEndpoint returns an error if the OTLP endpoint is invalid.
+func ValidateEndpoint(endpoint string) error {
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return fmt.Errorf("OTLP endpoint %q must start with http:// or https://", endpoint)
+	}
+	return nil
+}
+
+// Close exports the remaining spans and stops the export loop.
+func (e *Exporter) Close() {
+	e.closeOnce.Do(func() {
+		close(e.die)
+	})
+	<-e.done
+}
+
+func (e *Exporter) add(s *Span) {
+	select {
+	case e.queue <- s:
+	default:
+		DroppedSpans.Add(1)
+	}
+}
+
+func (e *Exporter) exportLoop() {
+	defer close(e.done)
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+	batch := make([]*Span, 0, maxBatchSize)
+	for {
+		select {
+		case s := <-e.queue:
+			batch = append(batch, s)
+			if len(batch) >= maxBatchSize {
+				e.export(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				e.export(batch)
+				batch = batch[:0]
+			}
+		case <-e.die:
+			for len(e.queue) > 0 {
+				batch = append(batch, <-e.queue)
+			}
+			if len(batch) > 0 {
+				e.export(batch)
+			}
+			return
+		}
+	}
+}
+
+func (e *Exporter) export(batch []*Span) {
+	b, err := json.Marshal(e.toRequest(batch))
+	if err != nil {
+		ExportErrors.Add(1)
+		log.Debugf("json.Marshal() failed: %v", err)
+		return
+	}
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		ExportErrors.Add(1)
+		log.Debugf("export %d spans to %s failed: %v", len(batch), e.url, err)
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		ExportErrors.Add(1)
+		log.Debugf("export %d spans to %s failed: HTTP status %s", len(batch), e.url, resp.Status)
+		return
+	}
+	ExportedSpans.Add(int64(len(batch)))
+}
+
+// The following types map to the JSON encoding of
+// ExportTraceServiceRequest in OpenTelemetry protocol.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+func (e *Exporter) toRequest(batch []*Span) *otlpRequest {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		s.mu.Lock()
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.spanID[:]),
+			Name:              s.name,
+			Kind:              int(s.kind),
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+			Status:            otlpStatus{Code: otlpStatusOK},
+		}
+		if s.parentID != [8]byte{} {
+			span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		for k, v := range s.attributes {
+			span.Attributes = append(span.Attributes, otlpKeyValue{Key: k, Value: otlpValue{StringValue: v}})
+		}
+		if s.err != nil {
+			span.Status = otlpStatus{Code: otlpStatusError, Message: s.err.Error()}
+		}
+		s.mu.Unlock()
+		spans = append(spans, span)
+	}
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpKeyValue{
+						{Key: "service.name", Value: otlpValue{StringValue: e.serviceName}},
+					},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: "github.com/enfein/mieru/v3/pkg/tracing"},
+						Spans: spans,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package tracing records the timing of proxy requests as spans,
+// and exports them to an OpenTelemetry collector.
+package tracing
+
+import (
+	"context"
+	crand "crypto/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SpanKind is the role of a span in a trace.
+type SpanKind int
+
+// The values are the same as SpanKind in OpenTelemetry protocol.
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+type spanContextKey struct{}
+
+// exporterRef holds a pointer to the exporter.
+// If it is nil, tracing is disabled.
+var exporterRef atomic.Pointer[Exporter]
+
+// SetExporter sets the exporter of finished spans.
+// Use nil to disable tracing. The previous exporter, if any, is closed.
+func SetExporter(e *Exporter) {
+	if prev := exporterRef.Swap(e); prev != nil && prev != e {
+		prev.Close()
+	}
+}
+
+// Enabled returns true if tracing is enabled.
+func Enabled() bool {
+	return exporterRef.Load() != nil
+}
+
+// Span records the timing of an operation.
+//
+// All the methods are no-op on a nil span,
+// so callers don't need to check if tracing is enabled.
+type Span struct {
+	traceID    [16]byte
+	spanID     [8]byte
+	parentID   [8]byte
+	name       string
+	kind       SpanKind
+	start      time.Time
+	end        time.Time
+	attributes map[string]string
+	err        error
+	exporter   *Exporter
+
+	mu    sync.Mutex
+	ended bool
+}
+
+// Start creates a new span. If the context contains a span, the new span
+// is a child of that span. It returns a context that contains the new span.
+// If tracing is disabled, the context is returned unchanged and the span is nil.
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	exporter := exporterRef.Load()
+	if exporter == nil {
+		return ctx, nil
+	}
+	s := &Span{
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+		exporter: exporter,
+	}
+	if parent := FromContext(ctx); parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		crand.Read(s.traceID[:])
+	}
+	crand.Read(s.spanID[:])
+	return context.WithValue(ctx, spanContextKey{}, s), s
+}
+
+// FromContext returns the span stored in the context.
+// It returns nil if there is no span.
+func FromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanContextKey{}).(*Span)
+	return s
+}
+
+// SetAttribute adds a key value pair to the span.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.attributes == nil {
+		s.attributes = make(map[string]string)
+	}
+	s.attributes[key] = value
+}
+
+// End finishes the span. If err is not nil, the span is marked as failed.
+// The span is exported when it is ended for the first time.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.err = err
+	s.mu.Unlock()
+	s.exporter.add(s)
+}
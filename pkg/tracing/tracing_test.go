@@ -0,0 +1,110 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestSpanDisabled(t *testing.T) {
+	SetExporter(nil)
+	ctx := context.Background()
+	newCtx, span := Start(ctx, "test", SpanKindInternal)
+	if span != nil {
+		t.Fatalf("Start() returned a span when tracing is disabled")
+	}
+	if newCtx != ctx {
+		t.Errorf("Start() changed the context when tracing is disabled")
+	}
+	// Methods of nil span must not panic.
+	span.SetAttribute("key", "value")
+	span.End(nil)
+}
+
+func TestExportSpans(t *testing.T) {
+	var mu sync.Mutex
+	var got []otlpSpan
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("got path %q, want %q", r.URL.Path, "/v1/traces")
+		}
+		var req otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Decode() failed: %v", err)
+			return
+		}
+		mu.Lock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				got = append(got, ss.Spans...)
+			}
+		}
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	exporter, err := NewExporter(collector.URL, "test")
+	if err != nil {
+		t.Fatalf("NewExporter() failed: %v", err)
+	}
+	SetExporter(exporter)
+	defer SetExporter(nil)
+
+	ctx, parent := Start(context.Background(), "parent", SpanKindServer)
+	_, child := Start(ctx, "child", SpanKindInternal)
+	child.SetAttribute("destination", "example.com")
+	child.End(errors.New("failed"))
+	parent.End(nil)
+	parent.End(nil) // no-op
+	exporter.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 2 {
+		t.Fatalf("got %d spans, want 2", len(got))
+	}
+	c, p := got[0], got[1]
+	if c.Name != "child" || p.Name != "parent" {
+		t.Fatalf("got span names %q and %q", c.Name, p.Name)
+	}
+	if c.TraceID != p.TraceID {
+		t.Errorf("child trace ID %s doesn't match parent trace ID %s", c.TraceID, p.TraceID)
+	}
+	if c.ParentSpanID != p.SpanID {
+		t.Errorf("child parent span ID %s doesn't match parent span ID %s", c.ParentSpanID, p.SpanID)
+	}
+	if p.ParentSpanID != "" {
+		t.Errorf("parent span has parent span ID %s", p.ParentSpanID)
+	}
+	if c.Status.Code != otlpStatusError || c.Status.Message != "failed" {
+		t.Errorf("child status = %+v, want error", c.Status)
+	}
+	if len(c.Attributes) != 1 || c.Attributes[0].Value.StringValue != "example.com" {
+		t.Errorf("child attributes = %+v", c.Attributes)
+	}
+}
+
+func TestNewExporterInvalidEndpoint(t *testing.T) {
+	if _, err := NewExporter("127.0.0.1:4318", "test"); err == nil {
+		t.Errorf("NewExporter() succeeded with an endpoint without scheme")
+	}
+}
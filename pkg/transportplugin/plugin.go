@@ -0,0 +1,295 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package transportplugin runs pluggable transport clients, such as
+// obfs4proxy, as external processes. The plugin process is managed with
+// version 1 of the pluggable transport specification: it is configured by
+// TOR_PT_* environment variables, and it provides a socks5 proxy that
+// carries the connections to proxy servers.
+//
+// It also runs SIP003 plugins of shadowsocks on both proxy client and
+// proxy server. They are configured by SS_* environment variables.
+package transportplugin
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/socks5"
+)
+
+const (
+	// startTimeout is the maximum time to wait for the plugin to report
+	// its socks5 proxy address.
+	startTimeout = 10 * time.Second
+
+	// dialTimeout is the maximum time to establish a connection
+	// through the plugin.
+	dialTimeout = 10 * time.Second
+
+	// maxSocks5FieldLen is the maximum length of socks5 user name and password.
+	maxSocks5FieldLen = 255
+)
+
+// Plugin is a running pluggable transport client.
+type Plugin struct {
+	name       string
+	cmd        *exec.Cmd
+	stdin      io.WriteCloser
+	socksAddr  string
+	credential *socks5.Credential
+	exited     chan struct{}
+	closeOnce  sync.Once
+}
+
+var _ apicommon.Dialer = (*Plugin)(nil)
+
+// Start runs the plugin process, and waits until the plugin reports
+// the socks5 proxy address of the transport.
+func Start(config *appctlpb.TransportPlugin) (*Plugin, error) {
+	if config.GetName() == "" {
+		return nil, fmt.Errorf("transport plugin name is not set")
+	}
+	if config.GetCommand() == "" {
+		return nil, fmt.Errorf("transport plugin command is not set")
+	}
+	credential, err := encodeOptions(config.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+	stateDir := config.GetStateDirectory()
+	if stateDir == "" {
+		stateDir = filepath.Join(os.TempDir(), "mieru-transport-plugin", config.GetName())
+	}
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll() failed: %w", err)
+	}
+
+	cmd := exec.Command(config.GetCommand(), config.GetArgs()...)
+	cmd.Env = append(os.Environ(),
+		"TOR_PT_MANAGED_TRANSPORT_VER=1",
+		"TOR_PT_STATE_LOCATION="+stateDir,
+		"TOR_PT_CLIENT_TRANSPORTS="+config.GetName(),
+		"TOR_PT_EXIT_ON_STDIN_CLOSE=1",
+	)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("StdinPipe() failed: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("StdoutPipe() failed: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start transport plugin %q failed: %w", config.GetCommand(), err)
+	}
+	p := &Plugin{
+		name:       config.GetName(),
+		cmd:        cmd,
+		stdin:      stdin,
+		credential: credential,
+		exited:     make(chan struct{}),
+	}
+
+	type startResult struct {
+		addr string
+		err  error
+	}
+	started := make(chan startResult, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		addr, err := parseStartMessages(scanner, p.name)
+		started <- startResult{addr: addr, err: err}
+		// Keep reading messages, so the plugin is not blocked by a full pipe.
+		for scanner.Scan() {
+			p.logMessage(scanner.Text())
+		}
+	}()
+	go func() {
+		err := cmd.Wait()
+		log.Infof("Transport plugin %q exited: %v", p.name, err)
+		close(p.exited)
+	}()
+
+	select {
+	case res := <-started:
+		if res.err != nil {
+			p.Close()
+			return nil, res.err
+		}
+		p.socksAddr = res.addr
+	case <-time.After(startTimeout):
+		p.Close()
+		return nil, fmt.Errorf("transport plugin %q didn't report the socks5 proxy address in %v", p.name, startTimeout)
+	}
+	log.Infof("Transport plugin %q is running, socks5 proxy address is %s", p.name, p.socksAddr)
+	return p, nil
+}
+
+// DialContext connects to the proxy server through the plugin.
+// Only TCP network is supported.
+func (p *Plugin) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if !strings.HasPrefix(network, "tcp") {
+		return nil, fmt.Errorf("transport plugin %q doesn't support network %q", p.name, network)
+	}
+	select {
+	case <-p.exited:
+		return nil, fmt.Errorf("transport plugin %q is not running", p.name)
+	default:
+	}
+	timeout := dialTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+		if timeout <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+	}
+	dial := socks5.DialSocks5Proxy(&socks5.Client{
+		Host:       p.socksAddr,
+		Credential: p.credential,
+		Timeout:    timeout,
+		CmdType:    constant.Socks5ConnectCmd,
+	})
+	conn, _, _, err := dial("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s through transport plugin %q failed: %w", address, p.name, err)
+	}
+	return conn, nil
+}
+
+// Close stops the plugin process.
+func (p *Plugin) Close() error {
+	p.closeOnce.Do(func() {
+		// The plugin should exit when stdin is closed.
+		p.stdin.Close()
+		select {
+		case <-p.exited:
+		case <-time.After(time.Second):
+			p.cmd.Process.Kill()
+			<-p.exited
+		}
+	})
+	return nil
+}
+
+// logMessage logs a message printed by the plugin after it is started.
+func (p *Plugin) logMessage(line string) {
+	keyword, rest, _ := strings.Cut(line, " ")
+	switch keyword {
+	case "LOG":
+		log.Infof("Transport plugin %q: %s", p.name, rest)
+	case "STATUS":
+		log.Debugf("Transport plugin %q status: %s", p.name, rest)
+	default:
+		log.Debugf("Transport plugin %q: %s", p.name, line)
+	}
+}
+
+// parseStartMessages reads the messages printed by the plugin at start,
+// until "CMETHODS DONE". It returns the socks5 proxy address of the transport.
+func parseStartMessages(scanner *bufio.Scanner, name string) (string, error) {
+	var addr string
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "VERSION":
+			if len(fields) < 2 || fields[1] != "1" {
+				return "", fmt.Errorf("transport plugin %q uses unsupported version %v", name, fields[1:])
+			}
+		case "VERSION-ERROR", "ENV-ERROR", "PROXY-ERROR":
+			return "", fmt.Errorf("transport plugin %q reported %s", name, strings.Join(fields, " "))
+		case "CMETHOD-ERROR":
+			if len(fields) >= 2 && fields[1] == name {
+				return "", fmt.Errorf("transport plugin %q reported %s", name, strings.Join(fields, " "))
+			}
+		case "CMETHOD":
+			// CMETHOD <transport> <'socks4','socks5'> <address:port>
+			if len(fields) < 4 || fields[1] != name {
+				continue
+			}
+			if fields[2] != "socks5" {
+				return "", fmt.Errorf("transport plugin %q provides unsupported proxy type %q", name, fields[2])
+			}
+			addr = fields[3]
+		case "CMETHODS":
+			if len(fields) >= 2 && fields[1] == "DONE" {
+				if addr == "" {
+					return "", fmt.Errorf("transport plugin doesn't provide transport %q", name)
+				}
+				return addr, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("read transport plugin output failed: %w", err)
+	}
+	return "", fmt.Errorf("transport plugin %q exited before it is ready", name)
+}
+
+// encodeOptions encodes the per-connection arguments into socks5 user name
+// and password, as required by the pluggable transport specification.
+// It returns nil if there is no argument.
+func encodeOptions(options []string) (*socks5.Credential, error) {
+	if len(options) == 0 {
+		return nil, nil
+	}
+	var encoded []string
+	for _, option := range options {
+		key, value, ok := strings.Cut(option, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("transport plugin option %q is not in \"key=value\" format", option)
+		}
+		encoded = append(encoded, escapeOption(key)+"="+escapeOption(value))
+	}
+	s := strings.Join(encoded, ";")
+	if len(s) > 2*maxSocks5FieldLen {
+		return nil, fmt.Errorf("transport plugin options are too long")
+	}
+	if len(s) <= maxSocks5FieldLen {
+		// The password can't be empty. Use a single NUL byte.
+		return &socks5.Credential{User: s, Password: "\x00"}, nil
+	}
+	return &socks5.Credential{User: s[:maxSocks5FieldLen], Password: s[maxSocks5FieldLen:]}, nil
+}
+
+// escapeOption escapes the characters that have special meaning in
+// the encoded per-connection arguments.
+func escapeOption(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if c == '\\' || c == '=' || c == ';' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
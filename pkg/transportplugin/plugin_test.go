@@ -0,0 +1,232 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package transportplugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestMain makes the test binary a fake transport plugin
+// or a fake SIP003 plugin when the environment variable is set.
+func TestMain(m *testing.M) {
+	if os.Getenv("MIERU_FAKE_TRANSPORT_PLUGIN") == "1" {
+		runFakePlugin()
+		return
+	}
+	if os.Getenv("MIERU_FAKE_SIP003_PLUGIN") == "1" {
+		runFakeSIP003Plugin()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+func TestPlugin(t *testing.T) {
+	// The destination echoes the data back.
+	echoListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer echoListener.Close()
+	go func() {
+		for {
+			conn, err := echoListener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	t.Setenv("MIERU_FAKE_TRANSPORT_PLUGIN", "1")
+	p, err := Start(&appctlpb.TransportPlugin{
+		Name:           proto.String("fake"),
+		Command:        proto.String(os.Args[0]),
+		Options:        []string{"cert=a;b", "iat-mode=0"},
+		StateDirectory: proto.String(t.TempDir()),
+	})
+	if err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer p.Close()
+
+	conn, err := p.DialContext(context.Background(), "tcp", echoListener.Addr().String())
+	if err != nil {
+		t.Fatalf("DialContext() failed: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatalf("ReadFull() failed: %v", err)
+	}
+	if string(buf) != "hello" {
+		t.Errorf("got %q, want %q", buf, "hello")
+	}
+
+	if _, err := p.DialContext(context.Background(), "udp", echoListener.Addr().String()); err == nil {
+		t.Errorf("DialContext() with UDP network succeeded")
+	}
+}
+
+func TestParseStartMessages(t *testing.T) {
+	testcases := []struct {
+		output  string
+		addr    string
+		wantErr bool
+	}{
+		{"VERSION 1\nCMETHOD obfs4 socks5 127.0.0.1:1080\nCMETHODS DONE\n", "127.0.0.1:1080", false},
+		{"VERSION 1\nCMETHOD meek socks5 127.0.0.1:1081\nCMETHOD obfs4 socks5 127.0.0.1:1080\nCMETHODS DONE\n", "127.0.0.1:1080", false},
+		{"VERSION-ERROR no-version\n", "", true},
+		{"VERSION 1\nCMETHOD-ERROR obfs4 not supported\n", "", true},
+		{"VERSION 1\nCMETHOD obfs4 socks4 127.0.0.1:1080\nCMETHODS DONE\n", "", true},
+		{"VERSION 1\nCMETHODS DONE\n", "", true},
+		{"VERSION 1\n", "", true},
+	}
+	for _, tc := range testcases {
+		addr, err := parseStartMessages(bufio.NewScanner(strings.NewReader(tc.output)), "obfs4")
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseStartMessages(%q) error = %v, want error %v", tc.output, err, tc.wantErr)
+		}
+		if addr != tc.addr {
+			t.Errorf("parseStartMessages(%q) = %q, want %q", tc.output, addr, tc.addr)
+		}
+	}
+}
+
+func TestEncodeOptions(t *testing.T) {
+	credential, err := encodeOptions(nil)
+	if err != nil || credential != nil {
+		t.Errorf("encodeOptions(nil) = %v, %v, want nil, nil", credential, err)
+	}
+
+	credential, err = encodeOptions([]string{"cert=a;b=c\\", "iat-mode=0"})
+	if err != nil {
+		t.Fatalf("encodeOptions() failed: %v", err)
+	}
+	if want := `cert=a\;b\=c\\;iat-mode=0`; credential.User != want || credential.Password != "\x00" {
+		t.Errorf("encodeOptions() = %q, %q, want %q, %q", credential.User, credential.Password, want, "\x00")
+	}
+
+	long := "key=" + strings.Repeat("x", 300)
+	credential, err = encodeOptions([]string{long})
+	if err != nil {
+		t.Fatalf("encodeOptions() failed: %v", err)
+	}
+	if len(credential.User) != maxSocks5FieldLen || credential.User+credential.Password != long {
+		t.Errorf("long options are not split into user name and password")
+	}
+
+	if _, err := encodeOptions([]string{"no-value"}); err == nil {
+		t.Errorf("encodeOptions() succeeded with invalid option")
+	}
+	if _, err := encodeOptions([]string{"key=" + strings.Repeat("x", 600)}); err == nil {
+		t.Errorf("encodeOptions() succeeded with too long options")
+	}
+}
+
+// runFakePlugin implements a transport plugin that forwards the
+// connections to the destination without changing the data.
+func runFakePlugin() {
+	if os.Getenv("TOR_PT_MANAGED_TRANSPORT_VER") != "1" {
+		fmt.Println("VERSION-ERROR no-version")
+		return
+	}
+	name := os.Getenv("TOR_PT_CLIENT_TRANSPORTS")
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Printf("CMETHOD-ERROR %s %v\n", name, err)
+		return
+	}
+	fmt.Println("VERSION 1")
+	fmt.Printf("CMETHOD %s socks5 %s\n", name, l.Addr().String())
+	fmt.Println("CMETHODS DONE")
+
+	// Exit when stdin is closed.
+	go func() {
+		io.Copy(io.Discard, os.Stdin)
+		os.Exit(0)
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go serveFakePluginConn(conn)
+	}
+}
+
+func serveFakePluginConn(conn net.Conn) {
+	defer conn.Close()
+	buf := make([]byte, 512)
+
+	// Method negotiation. Only user name and password authentication is accepted.
+	if _, err := io.ReadFull(conn, buf[:3]); err != nil || !bytes.Equal(buf[:3], []byte{5, 1, 2}) {
+		return
+	}
+	conn.Write([]byte{5, 2})
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return
+	}
+	user := make([]byte, buf[1])
+	if _, err := io.ReadFull(conn, user); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+		return
+	}
+	password := make([]byte, buf[0])
+	if _, err := io.ReadFull(conn, password); err != nil {
+		return
+	}
+	if string(user) != `cert=a\;b;iat-mode=0` || string(password) != "\x00" {
+		conn.Write([]byte{1, 1})
+		return
+	}
+	conn.Write([]byte{1, 0})
+
+	// CONNECT request with IPv4 address.
+	if _, err := io.ReadFull(conn, buf[:10]); err != nil || buf[1] != 1 || buf[3] != 1 {
+		return
+	}
+	dst := net.JoinHostPort(net.IP(buf[4:8]).String(), strconv.Itoa(int(binary.BigEndian.Uint16(buf[8:10]))))
+	target, err := net.Dial("tcp", dst)
+	if err != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+	go io.Copy(target, conn)
+	io.Copy(conn, target)
+}
@@ -0,0 +1,417 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package transportplugin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	apicommon "github.com/enfein/mieru/v3/apis/common"
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// sip003RestartDelay is the time to wait before restarting
+	// a SIP003 plugin that exited unexpectedly.
+	sip003RestartDelay = time.Second
+
+	// sip003ProbeInterval is the interval to check if a SIP003 plugin
+	// is listening.
+	sip003ProbeInterval = 50 * time.Millisecond
+)
+
+// sip003Process is a running SIP003 plugin. The plugin listens to one
+// address, and forwards the connections to the other address. Both
+// addresses are passed by environment variables. The plugin is
+// restarted if it exits unexpectedly.
+type sip003Process struct {
+	config   *appctlpb.SIP003Plugin
+	env      []string
+	die      chan struct{}
+	exited   chan struct{}
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	stopOnce sync.Once
+}
+
+// startSIP003Process starts the plugin with the local address and the
+// remote address defined by SIP003.
+func startSIP003Process(config *appctlpb.SIP003Plugin, localAddr, remoteAddr string) (*sip003Process, error) {
+	localHost, localPort, err := net.SplitHostPort(localAddr)
+	if err != nil {
+		return nil, fmt.Errorf("SIP003 plugin local address %q is invalid: %w", localAddr, err)
+	}
+	remoteHost, remotePort, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return nil, fmt.Errorf("SIP003 plugin remote address %q is invalid: %w", remoteAddr, err)
+	}
+	p := &sip003Process{
+		config: proto.Clone(config).(*appctlpb.SIP003Plugin),
+		env: append(os.Environ(),
+			"SS_LOCAL_HOST="+localHost,
+			"SS_LOCAL_PORT="+localPort,
+			"SS_REMOTE_HOST="+remoteHost,
+			"SS_REMOTE_PORT="+remotePort,
+			"SS_PLUGIN_OPTIONS="+config.GetOptions(),
+		),
+		die:    make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	cmd, err := p.start()
+	if err != nil {
+		return nil, err
+	}
+	go p.run(cmd)
+	return p, nil
+}
+
+// start starts a new plugin process.
+func (p *sip003Process) start() (*exec.Cmd, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-p.die:
+		return nil, fmt.Errorf("SIP003 plugin %q is stopped", p.config.GetCommand())
+	default:
+	}
+	cmd := exec.Command(p.config.GetCommand(), p.config.GetArgs()...)
+	cmd.Env = p.env
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start SIP003 plugin %q failed: %w", p.config.GetCommand(), err)
+	}
+	p.cmd = cmd
+	return cmd, nil
+}
+
+// run waits for the plugin process to exit, and restarts it until
+// the plugin is stopped.
+func (p *sip003Process) run(cmd *exec.Cmd) {
+	defer close(p.exited)
+	for {
+		err := cmd.Wait()
+		select {
+		case <-p.die:
+			return
+		default:
+		}
+		log.Warnf("SIP003 plugin %q exited: %v", p.config.GetCommand(), err)
+		for {
+			select {
+			case <-p.die:
+				return
+			case <-time.After(sip003RestartDelay):
+			}
+			cmd, err = p.start()
+			if err == nil {
+				log.Infof("SIP003 plugin %q is restarted", p.config.GetCommand())
+				break
+			}
+			log.Warnf("%v", err)
+		}
+	}
+}
+
+// stop kills the plugin process, and waits until it exits.
+func (p *sip003Process) stop() {
+	p.stopOnce.Do(func() {
+		close(p.die)
+		p.mu.Lock()
+		p.cmd.Process.Kill()
+		p.mu.Unlock()
+		<-p.exited
+	})
+}
+
+// SIP003Chain is a chain of running SIP003 plugins. The first plugin is
+// the closest to mieru. The remote address of a plugin is the local
+// address of the next plugin. The addresses between plugins are
+// loopback addresses with unused ports.
+type SIP003Chain struct {
+	localAddr string
+	processes []*sip003Process
+}
+
+// StartSIP003Chain runs the SIP003 plugins. The local address of the
+// first plugin is localAddr, and the remote address of the last plugin
+// is remoteAddr. If isServer is true, each plugin listens to the remote
+// address, otherwise it listens to the local address. It returns after
+// all the plugins are listening.
+func StartSIP003Chain(configs []*appctlpb.SIP003Plugin, localAddr, remoteAddr string, isServer bool) (*SIP003Chain, error) {
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("SIP003 plugins are not set")
+	}
+	addrs := []string{localAddr}
+	for i := 1; i < len(configs); i++ {
+		port, err := common.UnusedTCPPort()
+		if err != nil {
+			return nil, fmt.Errorf("common.UnusedTCPPort() failed: %w", err)
+		}
+		addrs = append(addrs, net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	}
+	addrs = append(addrs, remoteAddr)
+
+	c := &SIP003Chain{localAddr: localAddr}
+	for i, config := range configs {
+		if config.GetCommand() == "" {
+			c.Close()
+			return nil, fmt.Errorf("SIP003 plugin command is not set")
+		}
+		p, err := startSIP003Process(config, addrs[i], addrs[i+1])
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.processes = append(c.processes, p)
+		listenAddr := addrs[i]
+		if isServer {
+			listenAddr = addrs[i+1]
+		}
+		if err := waitSIP003Listening(p, listenAddr); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	log.Infof("Started %d SIP003 plugins between %s and %s", len(configs), localAddr, remoteAddr)
+	return c, nil
+}
+
+// LocalAddr returns the local address of the first plugin.
+func (c *SIP003Chain) LocalAddr() string {
+	return c.localAddr
+}
+
+// Close stops all the plugins.
+func (c *SIP003Chain) Close() error {
+	for _, p := range c.processes {
+		p.stop()
+	}
+	return nil
+}
+
+// waitSIP003Listening blocks until the plugin accepts connections
+// from the address.
+func waitSIP003Listening(p *sip003Process, addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		// Connect to the loopback address of the same IP version.
+		if ip.To4() != nil {
+			host = "127.0.0.1"
+		} else {
+			host = "::1"
+		}
+	}
+	addr = net.JoinHostPort(host, port)
+	timeout := time.After(startTimeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, sip003ProbeInterval)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		select {
+		case <-timeout:
+			return fmt.Errorf("SIP003 plugin %q is not listening to %s in %v", p.config.GetCommand(), addr, startTimeout)
+		case <-time.After(sip003ProbeInterval):
+		}
+	}
+}
+
+// SIP003Dialer connects to proxy servers through chains of SIP003
+// plugins. A chain is started for each proxy server address when it
+// is dialed for the first time.
+type SIP003Dialer struct {
+	configs []*appctlpb.SIP003Plugin
+	mu      sync.Mutex
+	chains  map[string]*SIP003Chain
+	closed  bool
+}
+
+var _ apicommon.Dialer = (*SIP003Dialer)(nil)
+
+// NewSIP003Dialer creates a dialer with the SIP003 plugins.
+func NewSIP003Dialer(configs []*appctlpb.SIP003Plugin) *SIP003Dialer {
+	return &SIP003Dialer{
+		configs: cloneSIP003Configs(configs),
+		chains:  make(map[string]*SIP003Chain),
+	}
+}
+
+// Configs returns the SIP003 plugins of the dialer.
+func (d *SIP003Dialer) Configs() []*appctlpb.SIP003Plugin {
+	return d.configs
+}
+
+// DialContext connects to the proxy server through the SIP003 plugins.
+// Only TCP network is supported.
+func (d *SIP003Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if network != "tcp" && network != "tcp4" && network != "tcp6" {
+		return nil, fmt.Errorf("SIP003 plugins don't support network %q", network)
+	}
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return nil, fmt.Errorf("SIP003 dialer is closed")
+	}
+	chain, ok := d.chains[address]
+	if !ok {
+		port, err := common.UnusedTCPPort()
+		if err != nil {
+			d.mu.Unlock()
+			return nil, fmt.Errorf("common.UnusedTCPPort() failed: %w", err)
+		}
+		chain, err = StartSIP003Chain(d.configs, net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), address, false)
+		if err != nil {
+			d.mu.Unlock()
+			return nil, err
+		}
+		d.chains[address] = chain
+	}
+	d.mu.Unlock()
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", chain.LocalAddr())
+}
+
+// Close stops all the SIP003 plugins started by the dialer.
+func (d *SIP003Dialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	for address, chain := range d.chains {
+		chain.Close()
+		delete(d.chains, address)
+	}
+	return nil
+}
+
+// SIP003Server runs the chains of SIP003 plugins in front of the TCP and
+// WebSocket endpoints of proxy server.
+type SIP003Server struct {
+	mu     sync.Mutex
+	chains map[string]runningSIP003Chain
+}
+
+// runningSIP003Chain is a SIP003 chain started for a server endpoint.
+type runningSIP003Chain struct {
+	configs []*appctlpb.SIP003Plugin
+	chain   *SIP003Chain
+}
+
+// NewSIP003Server creates a SIP003Server with no running plugin.
+func NewSIP003Server() *SIP003Server {
+	return &SIP003Server{
+		chains: make(map[string]runningSIP003Chain),
+	}
+}
+
+// Update starts the SIP003 plugins to listen to the local addresses of
+// the TCP and WebSocket endpoints, and returns the endpoints that listen
+// to loopback addresses instead. Other endpoints are not changed. The
+// chains of unchanged endpoints and plugins are reused, and the chains
+// that are no longer used are stopped. If there is no plugin, all the
+// chains are stopped and the endpoints are returned as is.
+func (s *SIP003Server) Update(configs []*appctlpb.SIP003Plugin, endpoints []protocol.UnderlayProperties) ([]protocol.UnderlayProperties, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]protocol.UnderlayProperties, 0, len(endpoints))
+	used := make(map[string]struct{})
+	for _, endpoint := range endpoints {
+		if len(configs) == 0 || endpoint.TransportProtocol() == common.PacketTransport {
+			res = append(res, endpoint)
+			continue
+		}
+		publicAddr := endpoint.LocalAddr().String()
+		running, ok := s.chains[publicAddr]
+		if ok && !SIP003ConfigsEqual(running.configs, configs) {
+			running.chain.Close()
+			delete(s.chains, publicAddr)
+			ok = false
+		}
+		if !ok {
+			port, err := common.UnusedTCPPort()
+			if err != nil {
+				return nil, fmt.Errorf("common.UnusedTCPPort() failed: %w", err)
+			}
+			chain, err := StartSIP003Chain(configs, net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), publicAddr, true)
+			if err != nil {
+				return nil, err
+			}
+			running = runningSIP003Chain{configs: cloneSIP003Configs(configs), chain: chain}
+			s.chains[publicAddr] = running
+		}
+		used[publicAddr] = struct{}{}
+		localAddr, err := net.ResolveTCPAddr("tcp", running.chain.LocalAddr())
+		if err != nil {
+			return nil, fmt.Errorf("net.ResolveTCPAddr() failed: %w", err)
+		}
+		res = append(res, protocol.WithLocalAddr(endpoint, localAddr))
+	}
+	for publicAddr, running := range s.chains {
+		if _, found := used[publicAddr]; !found {
+			running.chain.Close()
+			delete(s.chains, publicAddr)
+		}
+	}
+	return res, nil
+}
+
+// Close stops all the SIP003 plugins.
+func (s *SIP003Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for publicAddr, running := range s.chains {
+		running.chain.Close()
+		delete(s.chains, publicAddr)
+	}
+	return nil
+}
+
+// SIP003ConfigsEqual returns true if the two lists of SIP003 plugins
+// are the same.
+func SIP003ConfigsEqual(a, b []*appctlpb.SIP003Plugin) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// cloneSIP003Configs returns a deep copy of the SIP003 plugins.
+func cloneSIP003Configs(configs []*appctlpb.SIP003Plugin) []*appctlpb.SIP003Plugin {
+	res := make([]*appctlpb.SIP003Plugin, 0, len(configs))
+	for _, config := range configs {
+		res = append(res, proto.Clone(config).(*appctlpb.SIP003Plugin))
+	}
+	return res
+}
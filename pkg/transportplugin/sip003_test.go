@@ -0,0 +1,217 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package transportplugin
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/appctl/appctlpb"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+// runFakeSIP003Plugin forwards the connections between the local address
+// and the remote address, and flips the bits of the data. A client plugin
+// and a server plugin restore the original data.
+func runFakeSIP003Plugin() {
+	local := net.JoinHostPort(os.Getenv("SS_LOCAL_HOST"), os.Getenv("SS_LOCAL_PORT"))
+	remote := net.JoinHostPort(os.Getenv("SS_REMOTE_HOST"), os.Getenv("SS_REMOTE_PORT"))
+	listenAddr, forwardAddr := local, remote
+	if strings.Contains(os.Getenv("SS_PLUGIN_OPTIONS"), "server") {
+		listenAddr, forwardAddr = remote, local
+	}
+	l, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		os.Exit(1)
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			os.Exit(1)
+		}
+		go func() {
+			defer conn.Close()
+			next, err := net.Dial("tcp", forwardAddr)
+			if err != nil {
+				return
+			}
+			defer next.Close()
+			go flipCopy(next, conn)
+			flipCopy(conn, next)
+		}()
+	}
+}
+
+// flipCopy copies the data from src to dst, and flips the bits.
+func flipCopy(dst io.Writer, src io.Reader) {
+	buf := make([]byte, 1024)
+	for {
+		n, err := src.Read(buf)
+		for i := 0; i < n; i++ {
+			buf[i] = ^buf[i]
+		}
+		if n > 0 {
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func fakeSIP003Plugins(options string) []*appctlpb.SIP003Plugin {
+	return []*appctlpb.SIP003Plugin{
+		{Command: proto.String(os.Args[0]), Options: proto.String(options)},
+		{Command: proto.String(os.Args[0]), Options: proto.String(options)},
+	}
+}
+
+func TestSIP003Chain(t *testing.T) {
+	// The destination echoes the data back.
+	echoListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	defer echoListener.Close()
+	go func() {
+		for {
+			conn, err := echoListener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	t.Setenv("MIERU_FAKE_SIP003_PLUGIN", "1")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	publicAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	server, err := StartSIP003Chain(fakeSIP003Plugins("server"), echoListener.Addr().String(), publicAddr, true)
+	if err != nil {
+		t.Fatalf("StartSIP003Chain() failed: %v", err)
+	}
+	defer server.Close()
+
+	d := NewSIP003Dialer(fakeSIP003Plugins(""))
+	defer d.Close()
+	echo := func() error {
+		conn, err := d.DialContext(context.Background(), "tcp", publicAddr)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if _, err := conn.Write([]byte("hello")); err != nil {
+			return err
+		}
+		buf := make([]byte, 5)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return err
+		}
+		if string(buf) != "hello" {
+			t.Errorf("got %q, want %q", buf, "hello")
+		}
+		return nil
+	}
+	if err := echo(); err != nil {
+		t.Fatalf("echo failed: %v", err)
+	}
+
+	// The plugin is restarted after it exits.
+	d.mu.Lock()
+	p := d.chains[publicAddr].processes[0]
+	d.mu.Unlock()
+	p.mu.Lock()
+	p.cmd.Process.Kill()
+	p.mu.Unlock()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		time.Sleep(100 * time.Millisecond)
+		if err = echo(); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("echo failed after the plugin is restarted: %v", err)
+		}
+	}
+
+	if _, err := d.DialContext(context.Background(), "udp", publicAddr); err == nil {
+		t.Errorf("DialContext() with UDP network succeeded")
+	}
+}
+
+func TestSIP003ServerUpdate(t *testing.T) {
+	t.Setenv("MIERU_FAKE_SIP003_PLUGIN", "1")
+	port, err := common.UnusedTCPPort()
+	if err != nil {
+		t.Fatalf("common.UnusedTCPPort() failed: %v", err)
+	}
+	endpoints := []protocol.UnderlayProperties{
+		protocol.NewUnderlayProperties(1400, common.StreamTransport, &net.TCPAddr{IP: net.IPv4zero, Port: port}, nil),
+		protocol.NewUnderlayProperties(1400, common.PacketTransport, &net.UDPAddr{IP: net.IPv4zero, Port: port}, nil),
+	}
+	s := NewSIP003Server()
+	defer s.Close()
+
+	got, err := s.Update(fakeSIP003Plugins("server"), endpoints)
+	if err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	tcpAddr, ok := got[0].LocalAddr().(*net.TCPAddr)
+	if !ok || !tcpAddr.IP.IsLoopback() || tcpAddr.Port == port {
+		t.Errorf("TCP endpoint local address = %v, want a loopback address", got[0].LocalAddr())
+	}
+	if got[1].LocalAddr() != endpoints[1].LocalAddr() {
+		t.Errorf("UDP endpoint local address = %v, want %v", got[1].LocalAddr(), endpoints[1].LocalAddr())
+	}
+
+	// Unchanged plugins are reused.
+	again, err := s.Update(fakeSIP003Plugins("server"), endpoints)
+	if err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if again[0].LocalAddr().String() != got[0].LocalAddr().String() {
+		t.Errorf("TCP endpoint local address = %v, want %v", again[0].LocalAddr(), got[0].LocalAddr())
+	}
+
+	// Without plugins, the endpoints are not changed.
+	none, err := s.Update(nil, endpoints)
+	if err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if none[0].LocalAddr() != endpoints[0].LocalAddr() {
+		t.Errorf("TCP endpoint local address = %v, want %v", none[0].LocalAddr(), endpoints[0].LocalAddr())
+	}
+	if len(s.chains) != 0 {
+		t.Errorf("got %d running chains, want 0", len(s.chains))
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package tun
+
+import (
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+const (
+	// natMinPort and natMaxPort are the range of ports that identify
+	// the TCP connections to the relay.
+	natMinPort = 1024
+	natMaxPort = 65535
+
+	// natIdleTimeout is the time to keep a TCP connection without traffic.
+	natIdleTimeout = 2 * time.Hour
+
+	// natClosedTimeout is the time to keep a TCP connection after it is
+	// reset, or both sides have sent FIN.
+	natClosedTimeout = time.Minute
+)
+
+// natKey is the original address pair of a TCP connection.
+type natKey struct {
+	src netip.AddrPort
+	dst netip.AddrPort
+}
+
+type natEntry struct {
+	key        natKey
+	port       uint16
+	lastActive time.Time
+
+	// finFromApp and finFromRelay record if the application and the relay
+	// have sent FIN.
+	finFromApp   bool
+	finFromRelay bool
+	reset        bool
+}
+
+func (e *natEntry) expired(now time.Time) bool {
+	if e.reset || (e.finFromApp && e.finFromRelay) {
+		return now.Sub(e.lastActive) > natClosedTimeout
+	}
+	return now.Sub(e.lastActive) > natIdleTimeout
+}
+
+// natTable maps the TCP connections captured from the TUN device to
+// the ports of the fake source address used to connect to the relay.
+type natTable struct {
+	mu     sync.Mutex
+	byKey  map[natKey]*natEntry
+	byPort map[uint16]*natEntry
+	next   uint16
+}
+
+func newNATTable() *natTable {
+	return &natTable{
+		byKey:  make(map[natKey]*natEntry),
+		byPort: make(map[uint16]*natEntry),
+		next:   natMinPort,
+	}
+}
+
+// fromApp returns the port assigned to the TCP connection sent by an
+// application. A port is assigned if the connection is new.
+func (t *natTable) fromApp(key natKey, flags byte, now time.Time) (uint16, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.byKey[key]
+	if !ok {
+		port, err := t.allocate(now)
+		if err != nil {
+			return 0, err
+		}
+		e = &natEntry{key: key, port: port}
+		t.byKey[key] = e
+		t.byPort[port] = e
+	}
+	e.lastActive = now
+	e.finFromApp = e.finFromApp || flags&tcpFlagFIN != 0
+	e.reset = e.reset || flags&tcpFlagRST != 0
+	return e.port, nil
+}
+
+// fromRelay returns the original address pair of the TCP connection
+// identified by the port.
+func (t *natTable) fromRelay(port uint16, flags byte, now time.Time) (natKey, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.byPort[port]
+	if !ok {
+		return natKey{}, false
+	}
+	e.lastActive = now
+	e.finFromRelay = e.finFromRelay || flags&tcpFlagFIN != 0
+	e.reset = e.reset || flags&tcpFlagRST != 0
+	return e.key, true
+}
+
+// lookup returns the original address pair of the TCP connection
+// identified by the port without updating it.
+func (t *natTable) lookup(port uint16) (natKey, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.byPort[port]
+	if !ok {
+		return natKey{}, false
+	}
+	return e.key, true
+}
+
+// evict removes the expired TCP connections.
+func (t *natTable) evict(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for port, e := range t.byPort {
+		if e.expired(now) {
+			delete(t.byPort, port)
+			delete(t.byKey, e.key)
+		}
+	}
+}
+
+// len returns the number of TCP connections in the table.
+func (t *natTable) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.byPort)
+}
+
+// allocate returns a port that is not used. It must be called with the lock.
+func (t *natTable) allocate(now time.Time) (uint16, error) {
+	for i := 0; i <= natMaxPort-natMinPort; i++ {
+		port := t.next
+		if t.next == natMaxPort {
+			t.next = natMinPort
+		} else {
+			t.next++
+		}
+		e, ok := t.byPort[port]
+		if !ok {
+			return port, nil
+		}
+		if e.expired(now) {
+			delete(t.byPort, port)
+			delete(t.byKey, e.key)
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("all %d TCP ports of the TUN device are in use", natMaxPort-natMinPort+1)
+}
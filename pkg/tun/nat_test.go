@@ -0,0 +1,114 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package tun
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestNATTable(t *testing.T) {
+	table := newNATTable()
+	now := time.Now()
+	key1 := natKey{
+		src: netip.MustParseAddrPort("198.18.0.1:40000"),
+		dst: netip.MustParseAddrPort("93.184.216.34:443"),
+	}
+	key2 := natKey{
+		src: netip.MustParseAddrPort("198.18.0.1:40001"),
+		dst: netip.MustParseAddrPort("93.184.216.34:443"),
+	}
+
+	port1, err := table.fromApp(key1, 0, now)
+	if err != nil {
+		t.Fatalf("fromApp() failed: %v", err)
+	}
+	port2, err := table.fromApp(key2, 0, now)
+	if err != nil {
+		t.Fatalf("fromApp() failed: %v", err)
+	}
+	if port1 == port2 {
+		t.Errorf("two connections got the same port %d", port1)
+	}
+	if port, _ := table.fromApp(key1, 0, now); port != port1 {
+		t.Errorf("fromApp() = %d for an existing connection, want %d", port, port1)
+	}
+	if key, ok := table.fromRelay(port2, 0, now); !ok || key != key2 {
+		t.Errorf("fromRelay() = %v, %v, want %v, true", key, ok, key2)
+	}
+	if _, ok := table.fromRelay(port2+1, 0, now); ok {
+		t.Errorf("fromRelay() found a port that is not assigned")
+	}
+
+	// A closed connection is removed after natClosedTimeout.
+	table.fromApp(key1, tcpFlagFIN, now)
+	table.fromRelay(port1, tcpFlagFIN, now)
+	table.fromApp(key2, 0, now.Add(natClosedTimeout))
+	table.evict(now.Add(natClosedTimeout + time.Second))
+	if _, ok := table.lookup(port1); ok {
+		t.Errorf("closed connection is not removed")
+	}
+	if _, ok := table.lookup(port2); !ok {
+		t.Errorf("active connection is removed")
+	}
+
+	// An idle connection is removed after natIdleTimeout.
+	table.evict(now.Add(natClosedTimeout + natIdleTimeout + time.Second))
+	if table.len() != 0 {
+		t.Errorf("len() = %d after idle timeout, want 0", table.len())
+	}
+}
+
+func TestNATTableReset(t *testing.T) {
+	table := newNATTable()
+	now := time.Now()
+	key := natKey{
+		src: netip.MustParseAddrPort("[fdfe:dcba:9876::1]:40000"),
+		dst: netip.MustParseAddrPort("[2001:db8::1]:443"),
+	}
+	port, err := table.fromApp(key, 0, now)
+	if err != nil {
+		t.Fatalf("fromApp() failed: %v", err)
+	}
+	table.fromRelay(port, tcpFlagRST, now)
+	table.evict(now.Add(natClosedTimeout + time.Second))
+	if table.len() != 0 {
+		t.Errorf("len() = %d after reset, want 0", table.len())
+	}
+}
+
+func TestNATTableFull(t *testing.T) {
+	table := newNATTable()
+	now := time.Now()
+	src := netip.MustParseAddr("198.18.0.1")
+	dst := netip.MustParseAddrPort("93.184.216.34:443")
+	n := natMaxPort - natMinPort + 1
+	for i := 0; i < n; i++ {
+		key := natKey{src: netip.AddrPortFrom(src, uint16(i)), dst: dst}
+		if _, err := table.fromApp(key, 0, now); err != nil {
+			t.Fatalf("fromApp() failed after %d connections: %v", i, err)
+		}
+	}
+	extra := natKey{src: netip.AddrPortFrom(src, 65535), dst: netip.MustParseAddrPort("93.184.216.34:80")}
+	if _, err := table.fromApp(extra, 0, now); err == nil {
+		t.Errorf("fromApp() succeeded when all the ports are used")
+	}
+	// A port of an expired connection can be used again.
+	if _, err := table.fromApp(extra, 0, now.Add(natIdleTimeout+time.Second)); err != nil {
+		t.Errorf("fromApp() failed after connections are expired: %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package tun captures the IP packets of a TUN device and forwards the
+// TCP and UDP traffic through a socks5 server.
+package tun
+
+import (
+	"encoding/binary"
+	"errors"
+	"net/netip"
+)
+
+const (
+	protocolTCP = 6
+	protocolUDP = 17
+
+	ipv4HeaderLen = 20
+	ipv6HeaderLen = 40
+	tcpHeaderLen  = 20
+	udpHeaderLen  = 8
+
+	tcpFlagFIN = 0x01
+	tcpFlagRST = 0x04
+)
+
+var (
+	errMalformedPacket     = errors.New("malformed IP packet")
+	errFragmentedPacket    = errors.New("fragmented IP packet is not supported")
+	errUnsupportedProtocol = errors.New("IP protocol is not supported")
+)
+
+// packet is an IPv4 or IPv6 packet that carries a TCP segment or
+// a UDP datagram.
+type packet struct {
+	// buf is the whole IP packet.
+	buf []byte
+
+	// protocol is protocolTCP or protocolUDP.
+	protocol byte
+
+	// transport is the offset of the TCP or UDP header in buf.
+	transport int
+
+	src netip.AddrPort
+	dst netip.AddrPort
+}
+
+// parsePacket parses the IP packet in b. The packet shares the memory of b.
+func parsePacket(b []byte) (*packet, error) {
+	if len(b) < 1 {
+		return nil, errMalformedPacket
+	}
+	p := &packet{}
+	var srcIP, dstIP netip.Addr
+	switch b[0] >> 4 {
+	case 4:
+		if len(b) < ipv4HeaderLen {
+			return nil, errMalformedPacket
+		}
+		headerLen := int(b[0]&0x0f) * 4
+		totalLen := int(binary.BigEndian.Uint16(b[2:4]))
+		if headerLen < ipv4HeaderLen || totalLen < headerLen || totalLen > len(b) {
+			return nil, errMalformedPacket
+		}
+		// Either the "more fragments" flag or the fragment offset is set.
+		if binary.BigEndian.Uint16(b[6:8])&0x3fff != 0 {
+			return nil, errFragmentedPacket
+		}
+		p.buf = b[:totalLen]
+		p.protocol = b[9]
+		p.transport = headerLen
+		srcIP = netip.AddrFrom4([4]byte(b[12:16]))
+		dstIP = netip.AddrFrom4([4]byte(b[16:20]))
+	case 6:
+		if len(b) < ipv6HeaderLen {
+			return nil, errMalformedPacket
+		}
+		totalLen := ipv6HeaderLen + int(binary.BigEndian.Uint16(b[4:6]))
+		if totalLen > len(b) {
+			return nil, errMalformedPacket
+		}
+		// Extension headers are not supported.
+		p.buf = b[:totalLen]
+		p.protocol = b[6]
+		p.transport = ipv6HeaderLen
+		srcIP = netip.AddrFrom16([16]byte(b[8:24]))
+		dstIP = netip.AddrFrom16([16]byte(b[24:40]))
+	default:
+		return nil, errMalformedPacket
+	}
+	switch p.protocol {
+	case protocolTCP:
+		if len(p.buf) < p.transport+tcpHeaderLen {
+			return nil, errMalformedPacket
+		}
+	case protocolUDP:
+		if len(p.buf) < p.transport+udpHeaderLen {
+			return nil, errMalformedPacket
+		}
+	default:
+		return nil, errUnsupportedProtocol
+	}
+	t := p.buf[p.transport:]
+	p.src = netip.AddrPortFrom(srcIP, binary.BigEndian.Uint16(t[0:2]))
+	p.dst = netip.AddrPortFrom(dstIP, binary.BigEndian.Uint16(t[2:4]))
+	return p, nil
+}
+
+// isIPv4 returns true if the packet is an IPv4 packet.
+func (p *packet) isIPv4() bool {
+	return p.buf[0]>>4 == 4
+}
+
+// tcpFlags returns the flags of the TCP segment.
+func (p *packet) tcpFlags() byte {
+	return p.buf[p.transport+13]
+}
+
+// payload returns the payload of the TCP segment or the UDP datagram.
+func (p *packet) payload() []byte {
+	if p.protocol == protocolTCP {
+		dataOffset := int(p.buf[p.transport+12]>>4) * 4
+		if p.transport+dataOffset > len(p.buf) {
+			return nil
+		}
+		return p.buf[p.transport+dataOffset:]
+	}
+	return p.buf[p.transport+udpHeaderLen:]
+}
+
+// rewrite replaces the source and destination of the packet, and updates
+// the checksums. The new addresses must be in the same IP family.
+func (p *packet) rewrite(src, dst netip.AddrPort) {
+	if p.isIPv4() {
+		s, d := src.Addr().As4(), dst.Addr().As4()
+		copy(p.buf[12:16], s[:])
+		copy(p.buf[16:20], d[:])
+		binary.BigEndian.PutUint16(p.buf[10:12], 0)
+		binary.BigEndian.PutUint16(p.buf[10:12], checksum(0, p.buf[:p.transport]))
+	} else {
+		s, d := src.Addr().As16(), dst.Addr().As16()
+		copy(p.buf[8:24], s[:])
+		copy(p.buf[24:40], d[:])
+	}
+	t := p.buf[p.transport:]
+	binary.BigEndian.PutUint16(t[0:2], src.Port())
+	binary.BigEndian.PutUint16(t[2:4], dst.Port())
+	p.src = src
+	p.dst = dst
+	p.updateTransportChecksum()
+}
+
+// updateTransportChecksum computes the checksum of the TCP or UDP header.
+func (p *packet) updateTransportChecksum() {
+	t := p.buf[p.transport:]
+	offset := 16
+	if p.protocol == protocolUDP {
+		offset = 6
+	}
+	binary.BigEndian.PutUint16(t[offset:offset+2], 0)
+	sum := pseudoHeaderSum(p.src.Addr(), p.dst.Addr(), p.protocol, len(t))
+	c := checksum(sum, t)
+	if c == 0 && p.protocol == protocolUDP {
+		// Zero means no checksum in UDP.
+		c = 0xffff
+	}
+	binary.BigEndian.PutUint16(t[offset:offset+2], c)
+}
+
+// newUDPPacket builds an IP packet that carries a UDP datagram.
+func newUDPPacket(src, dst netip.AddrPort, payload []byte) []byte {
+	var b []byte
+	var transport int
+	length := udpHeaderLen + len(payload)
+	if src.Addr().Is4() {
+		transport = ipv4HeaderLen
+		b = make([]byte, ipv4HeaderLen+length)
+		b[0] = 0x45
+		binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+		b[8] = 64 // TTL
+		b[9] = protocolUDP
+	} else {
+		transport = ipv6HeaderLen
+		b = make([]byte, ipv6HeaderLen+length)
+		b[0] = 0x60
+		binary.BigEndian.PutUint16(b[4:6], uint16(length))
+		b[6] = protocolUDP
+		b[7] = 64 // hop limit
+	}
+	binary.BigEndian.PutUint16(b[transport+4:transport+6], uint16(length))
+	copy(b[transport+udpHeaderLen:], payload)
+	p := &packet{buf: b, protocol: protocolUDP, transport: transport}
+	p.rewrite(src, dst)
+	return b
+}
+
+// pseudoHeaderSum returns the sum of the pseudo header used by
+// TCP and UDP checksums.
+func pseudoHeaderSum(src, dst netip.Addr, protocol byte, length int) uint32 {
+	var sum uint32
+	if src.Is4() {
+		s, d := src.As4(), dst.As4()
+		sum = checksumAdd(sum, s[:])
+		sum = checksumAdd(sum, d[:])
+	} else {
+		s, d := src.As16(), dst.As16()
+		sum = checksumAdd(sum, s[:])
+		sum = checksumAdd(sum, d[:])
+	}
+	sum += uint32(protocol)
+	sum += uint32(length >> 16)
+	sum += uint32(length & 0xffff)
+	return sum
+}
+
+// checksumAdd adds the 16-bit words of b to sum.
+func checksumAdd(sum uint32, b []byte) uint32 {
+	for len(b) >= 2 {
+		sum += uint32(b[0])<<8 | uint32(b[1])
+		b = b[2:]
+	}
+	if len(b) == 1 {
+		sum += uint32(b[0]) << 8
+	}
+	return sum
+}
+
+// checksum returns the internet checksum of b, starting from sum.
+func checksum(sum uint32, b []byte) uint16 {
+	sum = checksumAdd(sum, b)
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,231 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package tun
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/netip"
+	"testing"
+)
+
+// newTCPPacket builds an IP packet that carries a TCP segment.
+func newTCPPacket(src, dst netip.AddrPort, flags byte, payload []byte) []byte {
+	var b []byte
+	var transport int
+	length := tcpHeaderLen + len(payload)
+	if src.Addr().Is4() {
+		transport = ipv4HeaderLen
+		b = make([]byte, ipv4HeaderLen+length)
+		b[0] = 0x45
+		binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+		b[8] = 64
+		b[9] = protocolTCP
+	} else {
+		transport = ipv6HeaderLen
+		b = make([]byte, ipv6HeaderLen+length)
+		b[0] = 0x60
+		binary.BigEndian.PutUint16(b[4:6], uint16(length))
+		b[6] = protocolTCP
+		b[7] = 64
+	}
+	b[transport+12] = 5 << 4
+	b[transport+13] = flags
+	copy(b[transport+tcpHeaderLen:], payload)
+	p := &packet{buf: b, protocol: protocolTCP, transport: transport}
+	p.rewrite(src, dst)
+	return b
+}
+
+// verifyChecksums reports an error if the IP header checksum or the
+// transport checksum of the packet is invalid.
+func verifyChecksums(t *testing.T, b []byte) {
+	t.Helper()
+	p, err := parsePacket(b)
+	if err != nil {
+		t.Fatalf("parsePacket() failed: %v", err)
+	}
+	if p.isIPv4() && checksum(0, p.buf[:p.transport]) != 0 {
+		t.Errorf("IPv4 header checksum is invalid")
+	}
+	sum := pseudoHeaderSum(p.src.Addr(), p.dst.Addr(), p.protocol, len(p.buf)-p.transport)
+	if checksum(sum, p.buf[p.transport:]) != 0 {
+		t.Errorf("transport checksum is invalid")
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	// A well known example of IPv4 header checksum.
+	header := []byte{
+		0x45, 0x00, 0x00, 0x73, 0x00, 0x00, 0x40, 0x00, 0x40, 0x11,
+		0x00, 0x00, 0xc0, 0xa8, 0x00, 0x01, 0xc0, 0xa8, 0x00, 0xc7,
+	}
+	if got := checksum(0, header); got != 0xb861 {
+		t.Errorf("checksum() = %#x, want %#x", got, 0xb861)
+	}
+	// Odd length.
+	if got := checksum(0, []byte{0x01}); got != 0xfeff {
+		t.Errorf("checksum() = %#x, want %#x", got, 0xfeff)
+	}
+}
+
+func TestParseAndRewritePacket(t *testing.T) {
+	testCases := []struct {
+		name           string
+		src, dst       netip.AddrPort
+		newSrc, newDst netip.AddrPort
+		protocol       byte
+		wantIPv4       bool
+	}{
+		{
+			name:     "TCP over IPv4",
+			src:      netip.MustParseAddrPort("198.18.0.1:40000"),
+			dst:      netip.MustParseAddrPort("93.184.216.34:443"),
+			newSrc:   netip.MustParseAddrPort("198.18.0.2:1024"),
+			newDst:   netip.MustParseAddrPort("198.18.0.1:35000"),
+			protocol: protocolTCP,
+			wantIPv4: true,
+		},
+		{
+			name:     "TCP over IPv6",
+			src:      netip.MustParseAddrPort("[fdfe:dcba:9876::1]:40000"),
+			dst:      netip.MustParseAddrPort("[2001:db8::1]:443"),
+			newSrc:   netip.MustParseAddrPort("[fdfe:dcba:9876::2]:1024"),
+			newDst:   netip.MustParseAddrPort("[fdfe:dcba:9876::1]:35000"),
+			protocol: protocolTCP,
+		},
+		{
+			name:     "UDP over IPv4",
+			src:      netip.MustParseAddrPort("198.18.0.1:40000"),
+			dst:      netip.MustParseAddrPort("8.8.8.8:53"),
+			newSrc:   netip.MustParseAddrPort("8.8.4.4:53"),
+			newDst:   netip.MustParseAddrPort("198.18.0.1:40001"),
+			protocol: protocolUDP,
+			wantIPv4: true,
+		},
+		{
+			name:     "UDP over IPv6",
+			src:      netip.MustParseAddrPort("[fdfe:dcba:9876::1]:40000"),
+			dst:      netip.MustParseAddrPort("[2001:4860:4860::8888]:53"),
+			newSrc:   netip.MustParseAddrPort("[2001:4860:4860::8844]:53"),
+			newDst:   netip.MustParseAddrPort("[fdfe:dcba:9876::1]:40001"),
+			protocol: protocolUDP,
+		},
+	}
+	payload := []byte("hello, mieru")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b []byte
+			if tc.protocol == protocolTCP {
+				b = newTCPPacket(tc.src, tc.dst, tcpFlagFIN, payload)
+			} else {
+				b = newUDPPacket(tc.src, tc.dst, payload)
+			}
+			verifyChecksums(t, b)
+
+			// Trailing bytes after the IP packet are ignored.
+			p, err := parsePacket(append(b, 0, 0, 0))
+			if err != nil {
+				t.Fatalf("parsePacket() failed: %v", err)
+			}
+			if p.isIPv4() != tc.wantIPv4 {
+				t.Errorf("isIPv4() = %v, want %v", p.isIPv4(), tc.wantIPv4)
+			}
+			if p.protocol != tc.protocol {
+				t.Errorf("protocol = %d, want %d", p.protocol, tc.protocol)
+			}
+			if p.src != tc.src || p.dst != tc.dst {
+				t.Errorf("got %v -> %v, want %v -> %v", p.src, p.dst, tc.src, tc.dst)
+			}
+			if !bytes.Equal(p.payload(), payload) {
+				t.Errorf("payload() = %q, want %q", p.payload(), payload)
+			}
+			if tc.protocol == protocolTCP && p.tcpFlags() != tcpFlagFIN {
+				t.Errorf("tcpFlags() = %#x, want %#x", p.tcpFlags(), tcpFlagFIN)
+			}
+
+			p.rewrite(tc.newSrc, tc.newDst)
+			verifyChecksums(t, p.buf)
+			p2, err := parsePacket(p.buf)
+			if err != nil {
+				t.Fatalf("parsePacket() failed: %v", err)
+			}
+			if p2.src != tc.newSrc || p2.dst != tc.newDst {
+				t.Errorf("got %v -> %v after rewrite, want %v -> %v", p2.src, p2.dst, tc.newSrc, tc.newDst)
+			}
+			if !bytes.Equal(p2.payload(), payload) {
+				t.Errorf("payload() = %q after rewrite, want %q", p2.payload(), payload)
+			}
+		})
+	}
+}
+
+func TestParsePacketError(t *testing.T) {
+	src := netip.MustParseAddrPort("198.18.0.1:40000")
+	dst := netip.MustParseAddrPort("8.8.8.8:53")
+	valid := newUDPPacket(src, dst, []byte("payload"))
+
+	fragment := append([]byte{}, valid...)
+	fragment[6] = 0x20 // more fragments
+	icmp := append([]byte{}, valid...)
+	icmp[9] = 1
+	truncated := valid[:len(valid)-1]
+	noTransport := append([]byte{}, valid[:ipv4HeaderLen]...)
+	binary.BigEndian.PutUint16(noTransport[2:4], ipv4HeaderLen)
+
+	testCases := []struct {
+		name string
+		b    []byte
+		want error
+	}{
+		{"empty", nil, errMalformedPacket},
+		{"IP version", []byte{0x50, 0, 0, 0}, errMalformedPacket},
+		{"short IPv4 header", valid[:ipv4HeaderLen-1], errMalformedPacket},
+		{"short IPv6 header", make([]byte, ipv6HeaderLen-1), errMalformedPacket},
+		{"truncated", truncated, errMalformedPacket},
+		{"no transport header", noTransport, errMalformedPacket},
+		{"fragment", fragment, errFragmentedPacket},
+		{"ICMP", icmp, errUnsupportedProtocol},
+	}
+	for _, tc := range testCases {
+		if _, err := parsePacket(tc.b); err != tc.want {
+			t.Errorf("%s: parsePacket() error = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestUDPAssociateHeader(t *testing.T) {
+	for _, addr := range []string{"8.8.8.8:53", "[2001:4860:4860::8888]:53"} {
+		dst := netip.MustParseAddrPort(addr)
+		b := append(udpAssociateHeader(dst), []byte("payload")...)
+		from, payload, err := parseUDPAssociateHeader(b)
+		if err != nil {
+			t.Fatalf("parseUDPAssociateHeader() failed: %v", err)
+		}
+		if from != dst {
+			t.Errorf("parseUDPAssociateHeader() address = %v, want %v", from, dst)
+		}
+		if string(payload) != "payload" {
+			t.Errorf("parseUDPAssociateHeader() payload = %q, want %q", payload, "payload")
+		}
+	}
+	if _, _, err := parseUDPAssociateHeader([]byte{0, 0, 0, 3, 1, 'a', 0, 53}); err == nil {
+		t.Errorf("parseUDPAssociateHeader() succeeded with a domain name address")
+	}
+	if _, _, err := parseUDPAssociateHeader([]byte{0, 0, 0, 1, 8, 8}); err == nil {
+		t.Errorf("parseUDPAssociateHeader() succeeded with a short header")
+	}
+}
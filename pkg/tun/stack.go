@@ -0,0 +1,510 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package tun
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/enfein/mieru/v3/apis/constant"
+	"github.com/enfein/mieru/v3/pkg/common"
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/metrics"
+	"github.com/enfein/mieru/v3/pkg/socks5"
+)
+
+const (
+	// DefaultMTU is the MTU of the TUN device if it is not configured.
+	DefaultMTU = 1500
+
+	// socks5Timeout is the timeout to connect to the socks5 server.
+	socks5Timeout = 10 * time.Second
+
+	// udpIdleTimeout is the time to keep a UDP session without traffic.
+	udpIdleTimeout = 2 * time.Minute
+
+	// udpQueueSize is the number of UDP datagrams that can wait for
+	// a UDP session to be established.
+	udpQueueSize = 64
+
+	// evictInterval is the interval to remove expired TCP connections
+	// and UDP sessions.
+	evictInterval = 30 * time.Second
+)
+
+var (
+	TUNMetricGroupName = "TUN device"
+
+	// TCPConnections is the number of TCP connections forwarded from the TUN device.
+	TCPConnections = metrics.RegisterMetric(TUNMetricGroupName, "TCPConnections", metrics.COUNTER)
+
+	// TCPConnErrors is the number of TCP connections failed to reach the destination.
+	TCPConnErrors = metrics.RegisterMetric(TUNMetricGroupName, "TCPConnErrors", metrics.COUNTER)
+
+	// UDPSessions is the number of UDP sessions forwarded from the TUN device.
+	UDPSessions = metrics.RegisterMetric(TUNMetricGroupName, "UDPSessions", metrics.COUNTER)
+
+	// UDPSessionErrors is the number of UDP sessions failed to start.
+	UDPSessionErrors = metrics.RegisterMetric(TUNMetricGroupName, "UDPSessionErrors", metrics.COUNTER)
+
+	// DroppedPackets is the number of IP packets from the TUN device that
+	// are not forwarded, for example ICMP packets and IP fragments.
+	DroppedPackets = metrics.RegisterMetric(TUNMetricGroupName, "DroppedPackets", metrics.COUNTER)
+)
+
+// Stack forwards the TCP and UDP traffic captured by a TUN device
+// through a socks5 server.
+//
+// TCP segments are not reassembled in user space. The stack rewrites the
+// addresses of each TCP segment and sends it back to the TUN device, so the
+// connection is terminated by a TCP listener of the kernel at the address
+// of the TUN device. The original destination of the accepted connection
+// is found from the NAT table, and the connection is forwarded through the
+// socks5 server. UDP datagrams are forwarded with socks5 UDP associate.
+type Stack struct {
+	// Device reads and writes one IP packet at a time.
+	Device io.ReadWriteCloser
+
+	// IPv4 is the address and prefix of the TUN device, e.g. 198.18.0.1/30.
+	// The next address in the prefix is used as the source address of the
+	// TCP connections to the kernel listener, so it must not be used by
+	// any other host.
+	IPv4 netip.Prefix
+
+	// IPv6 is the IPv6 address and prefix of the TUN device. It is optional.
+	IPv6 netip.Prefix
+
+	// MTU is the MTU of the TUN device.
+	MTU int
+
+	// Socks5Addr is the address of the socks5 server, e.g. 127.0.0.1:1080.
+	Socks5Addr string
+
+	nat       *natTable
+	families  []*relayFamily
+	udpMu     sync.Mutex
+	udp       map[netip.AddrPort]*udpSession
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// relayFamily is the kernel listener that accepts the TCP connections
+// of an IP family.
+type relayFamily struct {
+	// gateway is the address of the TUN device.
+	gateway netip.Addr
+
+	// fake is the source address of the TCP connections to the listener.
+	fake netip.Addr
+
+	// port is the port of the listener.
+	port uint16
+
+	listener net.Listener
+}
+
+// Run forwards the traffic of the TUN device until Close is called.
+// The addresses of the TUN device must be configured before.
+func (s *Stack) Run() error {
+	s.nat = newNATTable()
+	s.udp = make(map[netip.AddrPort]*udpSession)
+	s.done = make(chan struct{})
+	defer s.Close()
+
+	for _, prefix := range []netip.Prefix{s.IPv4, s.IPv6} {
+		if !prefix.IsValid() {
+			continue
+		}
+		f, err := newRelayFamily(prefix)
+		if err != nil {
+			return err
+		}
+		s.families = append(s.families, f)
+		go s.serveTCP(f)
+	}
+	if len(s.families) == 0 {
+		return fmt.Errorf("TUN device has no IP address")
+	}
+	go s.evictLoop()
+
+	mtu := s.MTU
+	if mtu <= 0 {
+		mtu = DefaultMTU
+	}
+	buf := make([]byte, mtu)
+	for {
+		n, err := s.Device.Read(buf)
+		if err != nil {
+			select {
+			case <-s.done:
+				return nil
+			default:
+			}
+			return fmt.Errorf("read from TUN device failed: %w", err)
+		}
+		if err := s.handlePacket(buf[:n], time.Now()); err != nil {
+			DroppedPackets.Add(1)
+			log.Tracef("TUN device dropped packet: %v", err)
+		}
+	}
+}
+
+// Close stops the stack and closes the TUN device.
+func (s *Stack) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		if s.done != nil {
+			close(s.done)
+		}
+		for _, f := range s.families {
+			f.listener.Close()
+		}
+		s.udpMu.Lock()
+		for _, session := range s.udp {
+			session.close()
+		}
+		s.udpMu.Unlock()
+		err = s.Device.Close()
+	})
+	return err
+}
+
+func newRelayFamily(prefix netip.Prefix) (*relayFamily, error) {
+	fake := prefix.Addr().Next()
+	if !fake.IsValid() || !prefix.Contains(fake) {
+		return nil, fmt.Errorf("TUN device address %v has no room for another address", prefix)
+	}
+	l, err := net.Listen("tcp", netip.AddrPortFrom(prefix.Addr(), 0).String())
+	if err != nil {
+		return nil, fmt.Errorf("listen to TUN device address %v failed: %w", prefix.Addr(), err)
+	}
+	return &relayFamily{
+		gateway:  prefix.Addr(),
+		fake:     fake,
+		port:     uint16(l.Addr().(*net.TCPAddr).Port),
+		listener: l,
+	}, nil
+}
+
+// family returns the relay of the IP family of the packet.
+func (s *Stack) family(p *packet) *relayFamily {
+	for _, f := range s.families {
+		if f.gateway.Is4() == p.isIPv4() {
+			return f
+		}
+	}
+	return nil
+}
+
+// handlePacket forwards an IP packet read from the TUN device.
+func (s *Stack) handlePacket(b []byte, now time.Time) error {
+	p, err := parsePacket(b)
+	if err != nil {
+		return err
+	}
+	f := s.family(p)
+	if f == nil {
+		return fmt.Errorf("IP family of %v is not enabled", p.dst.Addr())
+	}
+	if p.protocol == protocolUDP {
+		if p.dst.Addr() == f.fake {
+			return fmt.Errorf("destination %v is reserved", p.dst.Addr())
+		}
+		return s.handleUDP(p, now)
+	}
+
+	if p.src.Addr() == f.gateway && p.src.Port() == f.port {
+		// A segment sent by the kernel listener to the application.
+		key, ok := s.nat.fromRelay(p.dst.Port(), p.tcpFlags(), now)
+		if !ok {
+			return fmt.Errorf("TCP port %d is not found in NAT table", p.dst.Port())
+		}
+		p.rewrite(key.dst, key.src)
+	} else {
+		// A segment sent by the application to the destination.
+		if p.dst.Addr() == f.fake {
+			return fmt.Errorf("destination %v is reserved", p.dst.Addr())
+		}
+		port, err := s.nat.fromApp(natKey{src: p.src, dst: p.dst}, p.tcpFlags(), now)
+		if err != nil {
+			return err
+		}
+		p.rewrite(netip.AddrPortFrom(f.fake, port), netip.AddrPortFrom(f.gateway, f.port))
+	}
+	_, err = s.Device.Write(p.buf)
+	return err
+}
+
+// serveTCP accepts the TCP connections of the kernel listener, and
+// forwards them to the original destinations through the socks5 server.
+func (s *Stack) serveTCP(f *relayFamily) {
+	dialFunc := socks5.Dial("socks5://"+s.Socks5Addr+"?timeout="+socks5Timeout.String(), constant.Socks5ConnectCmd)
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		TCPConnections.Add(1)
+		go func() {
+			if err := s.forwardTCP(conn, f, dialFunc); err != nil {
+				TCPConnErrors.Add(1)
+				log.Debugf("TUN device failed to forward TCP connection: %v", err)
+			}
+		}()
+	}
+}
+
+func (s *Stack) forwardTCP(conn net.Conn, f *relayFamily, dialFunc func(string, string) (net.Conn, error)) error {
+	defer conn.Close()
+	remote, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return fmt.Errorf("remote address type %T is not TCP", conn.RemoteAddr())
+	}
+	remoteAddr := remote.AddrPort()
+	if remoteAddr.Addr().Unmap() != f.fake {
+		return fmt.Errorf("connection from %v is not captured by the TUN device", remoteAddr)
+	}
+	key, ok := s.nat.lookup(remoteAddr.Port())
+	if !ok {
+		return fmt.Errorf("TCP port %d is not found in NAT table", remoteAddr.Port())
+	}
+	proxyConn, err := dialFunc("tcp", key.dst.String())
+	if err != nil {
+		return fmt.Errorf("dial to %v through socks5 server failed: %w", key.dst, err)
+	}
+	common.BidiCopy(conn, proxyConn)
+	return nil
+}
+
+// udpSession forwards the UDP datagrams of an application socket
+// with a socks5 UDP association.
+type udpSession struct {
+	src   netip.AddrPort
+	queue chan []byte
+	done  chan struct{}
+
+	mu         sync.Mutex
+	ctrlConn   net.Conn
+	udpConn    *net.UDPConn
+	lastActive time.Time
+	closed     bool
+}
+
+// handleUDP sends the UDP datagram to the UDP session of the source address.
+func (s *Stack) handleUDP(p *packet, now time.Time) error {
+	s.udpMu.Lock()
+	session, ok := s.udp[p.src]
+	if !ok || session.isClosed() {
+		session = &udpSession{
+			src:        p.src,
+			queue:      make(chan []byte, udpQueueSize),
+			done:       make(chan struct{}),
+			lastActive: now,
+		}
+		s.udp[p.src] = session
+		UDPSessions.Add(1)
+		go s.serveUDP(session)
+	}
+	s.udpMu.Unlock()
+
+	session.mu.Lock()
+	session.lastActive = now
+	session.mu.Unlock()
+	datagram := append(udpAssociateHeader(p.dst), p.payload()...)
+	select {
+	case session.queue <- datagram:
+		return nil
+	default:
+		return fmt.Errorf("UDP session of %v is busy", p.src)
+	}
+}
+
+// serveUDP starts the socks5 UDP association of the session, and forwards
+// the datagrams in both directions until the session is closed.
+func (s *Stack) serveUDP(session *udpSession) {
+	defer s.removeUDP(session)
+	dialFunc := socks5.DialSocks5Proxy(&socks5.Client{
+		Host:    s.Socks5Addr,
+		Timeout: socks5Timeout,
+		CmdType: constant.Socks5UDPAssociateCmd,
+	})
+	ctrlConn, udpConn, proxyAddr, err := dialFunc("tcp", "0.0.0.0:0")
+	if err != nil {
+		UDPSessionErrors.Add(1)
+		log.Debugf("TUN device failed to start UDP session of %v: %v", session.src, err)
+		return
+	}
+	session.mu.Lock()
+	session.ctrlConn = ctrlConn
+	session.udpConn = udpConn
+	closed := session.closed
+	session.mu.Unlock()
+	if closed {
+		ctrlConn.Close()
+		udpConn.Close()
+		return
+	}
+
+	// The UDP association ends when the control connection is closed.
+	go func() {
+		io.Copy(io.Discard, ctrlConn)
+		session.close()
+	}()
+	go func() {
+		for {
+			select {
+			case datagram := <-session.queue:
+				if _, err := udpConn.WriteToUDP(datagram, proxyAddr); err != nil {
+					session.close()
+					return
+				}
+			case <-session.done:
+				return
+			}
+		}
+	}()
+
+	buf := make([]byte, 1<<16)
+	for {
+		n, err := udpConn.Read(buf)
+		if err != nil {
+			return
+		}
+		from, payload, err := parseUDPAssociateHeader(buf[:n])
+		if err != nil {
+			log.Debugf("TUN device received invalid UDP datagram: %v", err)
+			continue
+		}
+		if from.Addr().Is4() != session.src.Addr().Is4() {
+			continue
+		}
+		session.mu.Lock()
+		session.lastActive = time.Now()
+		session.mu.Unlock()
+		if _, err := s.Device.Write(newUDPPacket(from, session.src, payload)); err != nil {
+			return
+		}
+	}
+}
+
+// removeUDP closes the UDP session and removes it from the stack.
+func (s *Stack) removeUDP(session *udpSession) {
+	session.close()
+	s.udpMu.Lock()
+	if s.udp[session.src] == session {
+		delete(s.udp, session.src)
+	}
+	s.udpMu.Unlock()
+}
+
+func (session *udpSession) close() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.closed {
+		return
+	}
+	session.closed = true
+	close(session.done)
+	if session.ctrlConn != nil {
+		session.ctrlConn.Close()
+	}
+	if session.udpConn != nil {
+		session.udpConn.Close()
+	}
+}
+
+func (session *udpSession) isClosed() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.closed
+}
+
+func (session *udpSession) idle(now time.Time) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return now.Sub(session.lastActive) > udpIdleTimeout
+}
+
+// evictLoop removes the expired TCP connections and UDP sessions.
+func (s *Stack) evictLoop() {
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.nat.evict(now)
+			s.udpMu.Lock()
+			for src, session := range s.udp {
+				if session.idle(now) {
+					session.close()
+					delete(s.udp, src)
+				}
+			}
+			s.udpMu.Unlock()
+		}
+	}
+}
+
+// udpAssociateHeader returns the socks5 UDP associate header
+// with the destination address.
+func udpAssociateHeader(dst netip.AddrPort) []byte {
+	b := []byte{0, 0, 0}
+	if dst.Addr().Is4() {
+		ip := dst.Addr().As4()
+		b = append(b, constant.Socks5IPv4Address)
+		b = append(b, ip[:]...)
+	} else {
+		ip := dst.Addr().As16()
+		b = append(b, constant.Socks5IPv6Address)
+		b = append(b, ip[:]...)
+	}
+	return binary.BigEndian.AppendUint16(b, dst.Port())
+}
+
+// parseUDPAssociateHeader returns the source address and the payload
+// of a datagram received from the socks5 server.
+func parseUDPAssociateHeader(b []byte) (netip.AddrPort, []byte, error) {
+	if len(b) < 4 {
+		return netip.AddrPort{}, nil, errors.New("UDP associate header is too short")
+	}
+	var addr netip.Addr
+	var n int
+	switch b[3] {
+	case constant.Socks5IPv4Address:
+		n = 10
+		if len(b) < n {
+			return netip.AddrPort{}, nil, errors.New("UDP associate header is too short")
+		}
+		addr = netip.AddrFrom4([4]byte(b[4:8]))
+	case constant.Socks5IPv6Address:
+		n = 22
+		if len(b) < n {
+			return netip.AddrPort{}, nil, errors.New("UDP associate header is too short")
+		}
+		addr = netip.AddrFrom16([16]byte(b[4:20])).Unmap()
+	default:
+		return netip.AddrPort{}, nil, fmt.Errorf("UDP associate address type %d is not supported", b[3])
+	}
+	return netip.AddrPortFrom(addr, binary.BigEndian.Uint16(b[n-2:n])), b[n:], nil
+}
@@ -0,0 +1,225 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package tun
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+	"time"
+)
+
+// echoSocks5Server is a socks5 server that echoes the data of CONNECT
+// and UDP ASSOCIATE requests back to the client instead of reaching the
+// destinations. It records the destinations of CONNECT requests.
+type echoSocks5Server struct {
+	listener net.Listener
+
+	mu           sync.Mutex
+	destinations []string
+}
+
+func newEchoSocks5Server(t *testing.T) *echoSocks5Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	s := &echoSocks5Server{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+func (s *echoSocks5Server) serve(conn net.Conn) {
+	defer conn.Close()
+	// Method negotiation: accept no authentication.
+	buf := make([]byte, 262)
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return
+	}
+	conn.Write([]byte{5, 0})
+
+	// Request with an IPv4 or IPv6 address.
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+		return
+	}
+	cmd, addrType := buf[1], buf[3]
+	addrLen := 4
+	if addrType == 4 {
+		addrLen = 16
+	}
+	if _, err := io.ReadFull(conn, buf[:addrLen+2]); err != nil {
+		return
+	}
+	ip, _ := netip.AddrFromSlice(buf[:addrLen])
+	dst := netip.AddrPortFrom(ip, binary.BigEndian.Uint16(buf[addrLen:addrLen+2]))
+
+	switch cmd {
+	case 1:
+		s.mu.Lock()
+		s.destinations = append(s.destinations, dst.String())
+		s.mu.Unlock()
+		conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		io.Copy(conn, conn)
+	case 3:
+		udpConn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			return
+		}
+		defer udpConn.Close()
+		port := udpConn.LocalAddr().(*net.UDPAddr).Port
+		conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, byte(port >> 8), byte(port)})
+		go func() {
+			b := make([]byte, 65536)
+			for {
+				n, addr, err := udpConn.ReadFromUDP(b)
+				if err != nil {
+					return
+				}
+				udpConn.WriteToUDP(b[:n], addr)
+			}
+		}()
+		io.Copy(io.Discard, conn)
+	}
+}
+
+func (s *echoSocks5Server) connectDestinations() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.destinations...)
+}
+
+// setupTUNDevice creates a TUN device, and runs the commands to configure
+// it. The test is skipped if the device can't be created.
+func setupTUNDevice(t *testing.T, name string, commands ...[]string) {
+	if os.Geteuid() != 0 {
+		t.Skip("creating a TUN device requires root")
+	}
+	if _, err := exec.LookPath("ip"); err != nil {
+		t.Skip("ip command is not found")
+	}
+	commands = append([][]string{{"ip", "tuntap", "add", "dev", name, "mode", "tun"}}, commands...)
+	for i, args := range commands {
+		if out, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+			if i == 0 {
+				t.Skipf("failed to create TUN device: %v: %s", err, out)
+			}
+			t.Fatalf("%v failed: %v: %s", args, err, out)
+		}
+		if i == 0 {
+			t.Cleanup(func() {
+				exec.Command("ip", "tuntap", "del", "dev", name, "mode", "tun").Run()
+			})
+		}
+	}
+}
+
+func TestStack(t *testing.T) {
+	name := "mierutest0"
+	setupTUNDevice(t, name,
+		[]string{"ip", "addr", "add", "198.18.0.1/30", "dev", name},
+		[]string{"ip", "-6", "addr", "add", "fdfe:dcba:9876::1/126", "dev", name, "nodad"},
+		[]string{"ip", "link", "set", "dev", name, "up"},
+		[]string{"ip", "route", "add", "198.18.5.0/24", "dev", name},
+		[]string{"ip", "-6", "route", "add", "fdfe:dcba:9876:5::/64", "dev", name},
+	)
+	device, err := OpenDevice(name)
+	if err != nil {
+		t.Fatalf("OpenDevice() failed: %v", err)
+	}
+	server := newEchoSocks5Server(t)
+	stack := &Stack{
+		Device:     device,
+		IPv4:       netip.MustParsePrefix("198.18.0.1/30"),
+		IPv6:       netip.MustParsePrefix("fdfe:dcba:9876::1/126"),
+		MTU:        1500,
+		Socks5Addr: server.listener.Addr().String(),
+	}
+	runErr := make(chan error, 1)
+	go func() { runErr <- stack.Run() }()
+	time.Sleep(100 * time.Millisecond)
+
+	for _, dst := range []string{"198.18.5.1", "fdfe:dcba:9876:5::1"} {
+		// TCP.
+		tcpAddr := net.JoinHostPort(dst, "80")
+		conn, err := net.DialTimeout("tcp", tcpAddr, 5*time.Second)
+		if err != nil {
+			t.Fatalf("net.Dial() failed: %v", err)
+		}
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		data := bytes.Repeat([]byte("0123456789"), 1000)
+		go conn.Write(data)
+		buf := make([]byte, len(data))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			t.Fatalf("io.ReadFull() failed: %v", err)
+		}
+		if !bytes.Equal(buf, data) {
+			t.Errorf("TCP data is changed")
+		}
+		conn.Close()
+		if got := server.connectDestinations(); len(got) == 0 || got[len(got)-1] != tcpAddr {
+			t.Errorf("socks5 server got CONNECT requests %v, want %s", got, tcpAddr)
+		}
+
+		// UDP.
+		udpConn, err := net.Dial("udp", net.JoinHostPort(dst, "53"))
+		if err != nil {
+			t.Fatalf("net.Dial() failed: %v", err)
+		}
+		udpConn.SetDeadline(time.Now().Add(5 * time.Second))
+		for i := 0; i < 3; i++ {
+			msg := []byte{'p', 'i', 'n', 'g', byte(i)}
+			if _, err := udpConn.Write(msg); err != nil {
+				t.Fatalf("Write() failed: %v", err)
+			}
+			n, err := udpConn.Read(buf)
+			if err != nil {
+				t.Fatalf("Read() failed: %v", err)
+			}
+			if !bytes.Equal(buf[:n], msg) {
+				t.Errorf("UDP response = %q, want %q", buf[:n], msg)
+			}
+		}
+		udpConn.Close()
+	}
+
+	stack.Close()
+	select {
+	case err := <-runErr:
+		if err != nil {
+			t.Errorf("Run() failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Run() is not stopped after Close()")
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package version
+
+// releasePublicKey is the minisign public key that signs the official
+// release assets. It is set by the release build with
+// -ldflags "-X github.com/enfein/mieru/v3/pkg/version.releasePublicKey=<PUBLIC_KEY>".
+var releasePublicKey string
+
+// ReleasePublicKeys are the minisign public keys pinned in the binary.
+// They are trusted to sign the release assets, and the geo databases
+// when signature verification is enabled. Users can trust more public
+// keys, or revoke these public keys, with the "trust" commands.
+//
+// When no public key is pinned or trusted, a release asset is not
+// installed, unless the user explicitly skips the signature verification.
+var ReleasePublicKeys = releasePublicKeys()
+
+func releasePublicKeys() []string {
+	if releasePublicKey == "" {
+		return []string{}
+	}
+	return []string{releasePublicKey}
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReleasePublicKeys(t *testing.T) {
+	old := releasePublicKey
+	defer func() { releasePublicKey = old }()
+
+	releasePublicKey = ""
+	if got := releasePublicKeys(); len(got) != 0 {
+		t.Errorf("releasePublicKeys() = %v, want empty", got)
+	}
+	releasePublicKey = "RWQf6LRCGA9i53mlYecO4IzT51TGPpvWucNSCh1CBM0QTaLn73Y7GFO3"
+	if got, want := releasePublicKeys(), []string{releasePublicKey}; !reflect.DeepEqual(got, want) {
+		t.Errorf("releasePublicKeys() = %v, want %v", got, want)
+	}
+}
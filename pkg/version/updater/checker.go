@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/enfein/mieru/v3/apis/constant"
@@ -31,22 +33,95 @@ import (
 
 const (
 	UpToDateMessage = "already up to date"
+
+	// ChannelStable includes releases that are not marked as pre-release.
+	ChannelStable = "stable"
+
+	// ChannelBeta includes all releases, including pre-releases.
+	ChannelBeta = "beta"
+
+	// MinCheckInterval is the minimum interval to query GitHub API
+	// when the update history is provided.
+	MinCheckInterval = 10 * time.Minute
+
+	// maxBetaReleases is the number of recent releases to find
+	// the latest release in the beta channel.
+	maxBetaReleases = 20
+)
+
+var (
+	// releasesAPIURL is the GitHub API to list releases.
+	releasesAPIURL = "https://api.github.com/repos/enfein/mieru/releases"
+
+	// releaseDownloadURL is the URL prefix to download release assets.
+	releaseDownloadURL = "https://github.com/enfein/mieru/releases/download"
 )
 
+// CheckUpdateOptions controls the behavior of CheckUpdate.
+type CheckUpdateOptions struct {
+	// App is the name of the binary, "mieru" or "mita".
+	// It is used to find the download URL.
+	App string
+
+	// Channel is ChannelStable or ChannelBeta.
+	// If it is empty, ChannelStable is used.
+	Channel string
+
+	// Socks5ProxyURI is used by the HTTP client if it is not empty.
+	Socks5ProxyURI string
+
+	// History is used to avoid querying GitHub API too often.
+	// If it is not nil and the next check time in the history is not
+	// reached, the latest record of the same channel is returned without
+	// querying GitHub API, or an error is returned if the rate limit was
+	// exceeded. The next check time is updated after the query.
+	History *History
+}
+
 // CheckUpdate fetches the latest mieru / mita version using GitHub API
 // and check if a new release is available.
-// If the proxy URI is not empty, that is used by the HTTP client when
-// sending the HTTP request.
-func CheckUpdate(socks5ProxyURI string) (record *updaterpb.UpdateRecord, msg string, err error) {
+func CheckUpdate(opts CheckUpdateOptions) (record *updaterpb.UpdateRecord, msg string, err error) {
+	channel := opts.Channel
+	if channel == "" {
+		channel = ChannelStable
+	}
+	if channel != ChannelStable && channel != ChannelBeta {
+		return nil, "", fmt.Errorf("update channel %q is invalid, it must be %q or %q", channel, ChannelStable, ChannelBeta)
+	}
+
+	now := time.Now()
+	if opts.History != nil && now.Before(opts.History.NextCheckTime()) {
+		latest := opts.History.Latest()
+		if latest != nil && latest.GetError() == "" && latest.GetChannel() == channel && latest.GetVersion() == version.AppVersion {
+			record = proto.Clone(latest).(*updaterpb.UpdateRecord)
+			return record, checkResultMessage(record), nil
+		}
+		if latest != nil && latest.GetError() != "" && latest.GetLatestVersion() == "" {
+			// The last query exceeded the rate limit.
+			err = fmt.Errorf("GitHub API is not queried again until %s, the last query failed: %s", opts.History.NextCheckTime().Format(time.RFC3339), latest.GetError())
+			return nil, "", err
+		}
+	}
+
 	record = &updaterpb.UpdateRecord{
-		TimeUnix: proto.Int64(time.Now().Unix()),
+		TimeUnix: proto.Int64(now.Unix()),
 		Version:  proto.String(version.AppVersion),
+		Channel:  proto.String(channel),
 	}
 
-	var remoteTag string
-	remoteTag, err = queryLatestVersion(socks5ProxyURI)
+	var release *githubRelease
+	var retryAt time.Time
+	release, retryAt, err = queryLatestRelease(opts.Socks5ProxyURI, channel)
+	if opts.History != nil {
+		if err == nil {
+			opts.History.SetNextCheckTime(now.Add(MinCheckInterval))
+		} else {
+			// Zero time allows to retry at any time.
+			opts.History.SetNextCheckTime(retryAt)
+		}
+	}
 	if err != nil {
-		err = fmt.Errorf("queryLatestVersion() failed: %w", err)
+		err = fmt.Errorf("queryLatestRelease() failed: %w", err)
 		record.Error = proto.String(err.Error())
 		return
 	}
@@ -59,53 +134,142 @@ func CheckUpdate(socks5ProxyURI string) (record *updaterpb.UpdateRecord, msg str
 		record.Error = proto.String(err.Error())
 		return
 	}
-	remoteVersion, err = version.ParseTag(remoteTag)
+	remoteVersion, err = version.ParseTag(release.TagName)
 	if err != nil {
 		err = fmt.Errorf("parse release tag failed: %w", err)
 		record.Error = proto.String(err.Error())
 		return
 	}
 	record.LatestVersion = proto.String(remoteVersion.String())
+	record.ReleaseURL = proto.String(release.HTMLURL)
+	if record.GetReleaseURL() == "" {
+		record.ReleaseURL = proto.String("https://github.com/enfein/mieru/releases/tag/" + release.TagName)
+	}
+	record.ReleaseNotes = proto.String(release.Body)
+	if opts.App != "" {
+		if asset, err := ReleaseAssetName(opts.App, remoteVersion, runtime.GOOS, runtime.GOARCH); err == nil {
+			record.DownloadURL = proto.String(release.assetURL(asset))
+		}
+	}
+	record.NewReleaseFound = proto.Bool(currentVersion.IsLessThan(remoteVersion))
+	return record, checkResultMessage(record), nil
+}
 
-	if currentVersion.IsLessThan(remoteVersion) {
-		record.NewReleaseFound = proto.Bool(true)
-		msg = fmt.Sprintf("update is available at %s", "https://github.com/enfein/mieru/releases/tag/"+remoteTag)
-		return
-	} else {
-		record.NewReleaseFound = proto.Bool(false)
-		msg = UpToDateMessage
-		return
+// checkResultMessage returns the message of a successful check.
+func checkResultMessage(record *updaterpb.UpdateRecord) string {
+	if !record.GetNewReleaseFound() {
+		return UpToDateMessage
 	}
+	return fmt.Sprintf("update is available at %s", record.GetReleaseURL())
 }
 
-func queryLatestVersion(socks5ProxyURI string) (string, error) {
-	httpClient := http.Client{
-		Timeout: 10 * time.Second,
+// newHTTPClient returns an HTTP client that uses the socks5 proxy
+// if the proxy URI is not empty.
+func newHTTPClient(socks5ProxyURI string, timeout time.Duration) *http.Client {
+	httpClient := &http.Client{
+		Timeout: timeout,
 	}
 	if socks5ProxyURI != "" {
 		httpClient.Transport = &http.Transport{
 			Dial: socks5.Dial(socks5ProxyURI, constant.Socks5ConnectCmd),
 		}
 	}
-	resp, err := httpClient.Get("https://api.github.com/repos/enfein/mieru/releases/latest")
+	return httpClient
+}
+
+// queryLatestRelease returns the latest release of the channel.
+// If GitHub API reports the rate limit is exceeded, the time to retry
+// is returned with the error.
+func queryLatestRelease(socks5ProxyURI, channel string) (*githubRelease, time.Time, error) {
+	httpClient := newHTTPClient(socks5ProxyURI, 10*time.Second)
+	url := releasesAPIURL + "/latest"
+	if channel == ChannelBeta {
+		url = fmt.Sprintf("%s?per_page=%d", releasesAPIURL, maxBetaReleases)
+	}
+	resp, err := httpClient.Get(url)
 	if err != nil {
-		return "", fmt.Errorf("http.Get() failed: %v [GitHub may be blocked in your network]", err)
+		return nil, time.Time{}, fmt.Errorf("http.Get() failed: %v [GitHub may be blocked in your network]", err)
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP returned expected status code %d", resp.StatusCode)
+		return nil, rateLimitResetTime(resp), fmt.Errorf("HTTP returned unexpected status code %d", resp.StatusCode)
 	}
-	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("io.ReadAll() failed: %v", err)
+		return nil, time.Time{}, fmt.Errorf("io.ReadAll() failed: %v", err)
+	}
+
+	if channel != ChannelBeta {
+		var release githubRelease
+		if err := json.Unmarshal(body, &release); err != nil {
+			return nil, time.Time{}, fmt.Errorf("json.Unmarshal() failed: %v", err)
+		}
+		return &release, time.Time{}, nil
+	}
+
+	var releases []githubRelease
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, time.Time{}, fmt.Errorf("json.Unmarshal() failed: %v", err)
+	}
+	var latest *githubRelease
+	var latestVersion version.Version
+	for i := range releases {
+		if releases[i].Draft {
+			continue
+		}
+		v, err := version.ParseTag(releases[i].TagName)
+		if err != nil {
+			continue
+		}
+		if latest == nil || latestVersion.IsLessThan(v) {
+			latest = &releases[i]
+			latestVersion = v
+		}
 	}
-	var release latestRelease
-	if err := json.Unmarshal(body, &release); err != nil {
-		return "", fmt.Errorf("json.Unmarshal() failed: %v", err)
+	if latest == nil {
+		return nil, time.Time{}, fmt.Errorf("no release is found")
 	}
-	return release.TagName, nil
+	return latest, time.Time{}, nil
 }
 
-type latestRelease struct {
-	TagName string `json:"tag_name"`
+// rateLimitResetTime returns the time to retry if GitHub API reports
+// the rate limit is exceeded. Otherwise, it returns zero time.
+func rateLimitResetTime(resp *http.Response) time.Time {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}
+	}
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if seconds, err := strconv.Atoi(s); err == nil {
+			return time.Now().Add(time.Duration(seconds) * time.Second)
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Unix(reset, 0)
+		}
+	}
+	return time.Time{}
+}
+
+type githubRelease struct {
+	TagName string        `json:"tag_name"`
+	HTMLURL string        `json:"html_url"`
+	Body    string        `json:"body"`
+	Draft   bool          `json:"draft"`
+	Assets  []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// assetURL returns the URL to download the release asset.
+func (r *githubRelease) assetURL(name string) string {
+	for _, asset := range r.Assets {
+		if asset.Name == name && asset.BrowserDownloadURL != "" {
+			return asset.BrowserDownloadURL
+		}
+	}
+	return releaseDownloadURL + "/" + r.TagName + "/" + name
 }
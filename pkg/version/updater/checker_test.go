@@ -0,0 +1,149 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package updater
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFakeGitHub returns a fake GitHub API server. The number of requests
+// is counted. If rateLimited is true, the requests are rejected.
+func newFakeGitHub(t *testing.T, requests *atomic.Int32, rateLimited *atomic.Bool) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if rateLimited.Load() {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix()))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"tag_name": "v99.0.0", "html_url": "https://example.com/v99.0.0", "body": "stable notes", "assets": [{"name": "mieru_99.0.0_linux_amd64.tar.gz", "browser_download_url": "https://example.com/mieru.tar.gz"}]}`)
+	})
+	mux.HandleFunc("/releases", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `[
+			{"tag_name": "v100.0.0", "draft": true},
+			{"tag_name": "v99.1.0-beta", "prerelease": true, "body": "beta notes"},
+			{"tag_name": "v99.0.0", "body": "stable notes"}
+		]`)
+	})
+	server := httptest.NewServer(mux)
+	oldAPIURL := releasesAPIURL
+	releasesAPIURL = server.URL + "/releases"
+	t.Cleanup(func() {
+		releasesAPIURL = oldAPIURL
+		server.Close()
+	})
+}
+
+func TestCheckUpdateChannel(t *testing.T) {
+	var requests atomic.Int32
+	var rateLimited atomic.Bool
+	newFakeGitHub(t, &requests, &rateLimited)
+
+	record, msg, err := CheckUpdate(CheckUpdateOptions{App: "mieru"})
+	if err != nil {
+		t.Fatalf("CheckUpdate() failed: %v", err)
+	}
+	if record.GetChannel() != ChannelStable || record.GetLatestVersion() != "99.0.0" || !record.GetNewReleaseFound() {
+		t.Errorf("stable channel record is unexpected: %v", record)
+	}
+	if record.GetReleaseNotes() != "stable notes" || record.GetReleaseURL() != "https://example.com/v99.0.0" {
+		t.Errorf("release notes or release URL is unexpected: %v", record)
+	}
+	if record.GetDownloadURL() == "" {
+		t.Errorf("download URL is not set")
+	}
+	if !strings.Contains(msg, "https://example.com/v99.0.0") {
+		t.Errorf("message %q doesn't contain the release URL", msg)
+	}
+
+	record, _, err = CheckUpdate(CheckUpdateOptions{Channel: ChannelBeta})
+	if err != nil {
+		t.Fatalf("CheckUpdate() failed: %v", err)
+	}
+	if record.GetChannel() != ChannelBeta || record.GetLatestVersion() != "99.1.0" || record.GetReleaseNotes() != "beta notes" {
+		t.Errorf("beta channel record is unexpected: %v", record)
+	}
+	if record.GetDownloadURL() != "" {
+		t.Errorf("download URL is set without application name")
+	}
+
+	if _, _, err := CheckUpdate(CheckUpdateOptions{Channel: "nightly"}); err == nil {
+		t.Errorf("CheckUpdate() didn't reject invalid channel")
+	}
+}
+
+func TestCheckUpdateMinInterval(t *testing.T) {
+	var requests atomic.Int32
+	var rateLimited atomic.Bool
+	newFakeGitHub(t, &requests, &rateLimited)
+	h := NewHistory()
+
+	record, _, err := CheckUpdate(CheckUpdateOptions{History: h})
+	if err != nil {
+		t.Fatalf("CheckUpdate() failed: %v", err)
+	}
+	h.Insert(record)
+	if !h.NextCheckTime().After(time.Now()) {
+		t.Errorf("next check time is not set after a successful query")
+	}
+
+	// The cached record is used.
+	cached, _, err := CheckUpdate(CheckUpdateOptions{History: h})
+	if err != nil {
+		t.Fatalf("CheckUpdate() failed: %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("got %d requests, want 1", requests.Load())
+	}
+	if cached.GetLatestVersion() != record.GetLatestVersion() {
+		t.Errorf("cached record is unexpected: %v", cached)
+	}
+
+	// A different channel is queried.
+	if _, _, err := CheckUpdate(CheckUpdateOptions{Channel: ChannelBeta, History: h}); err != nil {
+		t.Fatalf("CheckUpdate() failed: %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("got %d requests, want 2", requests.Load())
+	}
+
+	// After the rate limit is exceeded, GitHub API is not queried
+	// until the rate limit is reset.
+	h.SetNextCheckTime(time.Time{})
+	rateLimited.Store(true)
+	record, _, err = CheckUpdate(CheckUpdateOptions{History: h})
+	if err == nil {
+		t.Fatalf("CheckUpdate() didn't report rate limit error")
+	}
+	h.Insert(record)
+	if h.NextCheckTime().Before(time.Now().Add(30 * time.Minute)) {
+		t.Errorf("next check time %v is not the rate limit reset time", h.NextCheckTime())
+	}
+	if _, _, err := CheckUpdate(CheckUpdateOptions{History: h}); err == nil {
+		t.Errorf("CheckUpdate() didn't report rate limit error")
+	}
+	if requests.Load() != 3 {
+		t.Errorf("got %d requests, want 3", requests.Load())
+	}
+}
@@ -75,7 +75,9 @@ func (h *History) StoreTo(filePath string) error {
 func (h *History) Insert(record *updaterpb.UpdateRecord) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.history.Records = append(h.history.Records, record)
+	// Records are sorted by time. If two records have the same time,
+	// the one inserted later is in the front.
+	h.history.Records = append([]*updaterpb.UpdateRecord{record}, h.history.Records...)
 	h.sort()
 }
 
@@ -89,6 +91,40 @@ func (h *History) Trim() {
 	}
 }
 
+// Latest returns the most recent record, or nil if the history is empty.
+func (h *History) Latest() *updaterpb.UpdateRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.history.Records) == 0 {
+		return nil
+	}
+	h.sort()
+	return h.history.Records[0]
+}
+
+// NextCheckTime returns the time before which GitHub API should not
+// be queried. It returns zero time if it is not set.
+func (h *History) NextCheckTime() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.history.NextCheckTimeUnix == nil {
+		return time.Time{}
+	}
+	return time.Unix(h.history.GetNextCheckTimeUnix(), 0)
+}
+
+// SetNextCheckTime sets the time before which GitHub API should not
+// be queried. Zero time clears the next check time.
+func (h *History) SetNextCheckTime(t time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if t.IsZero() {
+		h.history.NextCheckTimeUnix = nil
+		return
+	}
+	h.history.NextCheckTimeUnix = proto.Int64(t.Unix())
+}
+
 // ShouldCheckUpdate returns true if it is a good time to check update now.
 func (h *History) ShouldCheckUpdate() bool {
 	h.mu.Lock()
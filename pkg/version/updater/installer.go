@@ -0,0 +1,325 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package updater
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/enfein/mieru/v3/pkg/minisign"
+	"github.com/enfein/mieru/v3/pkg/version"
+	"github.com/enfein/mieru/v3/pkg/version/updater/updaterpb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxReleaseAssetSize is the maximum size of a release asset to download.
+	maxReleaseAssetSize = 128 * 1024 * 1024
+
+	// downloadTimeout is the timeout to download a release asset.
+	downloadTimeout = 5 * time.Minute
+)
+
+// SelfUpdateOptions controls the behavior of SelfUpdate.
+type SelfUpdateOptions struct {
+	CheckUpdateOptions
+
+	// ExecutablePath is the binary to replace. If it is empty,
+	// the binary of the current process is replaced.
+	ExecutablePath string
+
+	// TrustStore has the public keys trusted to sign the release.
+	// The release asset must have a minisign signature created by
+	// a trusted public key.
+	TrustStore *minisign.TrustStore
+
+	// If true and TrustStore has no public key, the release asset is
+	// only verified by the SHA-256 checksum. Otherwise, the release
+	// is not installed without a trusted public key.
+	InsecureSkipSignature bool
+}
+
+// ErrNoPinnedKey is returned by SelfUpdate if no release public key is
+// pinned in the binary. Such a binary has no trust anchor to verify
+// the releases.
+var ErrNoPinnedKey = errors.New("no release public key is pinned in the binary")
+
+// ErrNoTrustedKey is returned by SelfUpdate if no public key is trusted
+// to verify the signature of the release asset.
+var ErrNoTrustedKey = errors.New("no public key is trusted to verify the signature of the release")
+
+// SelfUpdate checks if a new release is available. If so, it downloads
+// the release asset of the current operating system and architecture,
+// verifies the SHA-256 checksum and the signature, and replaces the
+// binary with the one in the release asset. The returned record can be inserted into the
+// update history.
+func SelfUpdate(opts SelfUpdateOptions) (record *updaterpb.UpdateRecord, msg string, err error) {
+	record, msg, err = CheckUpdate(opts.CheckUpdateOptions)
+	if err != nil || !record.GetNewReleaseFound() {
+		return
+	}
+	defer func() {
+		if err != nil {
+			record.Error = proto.String(err.Error())
+		}
+	}()
+
+	if len(version.ReleasePublicKeys) == 0 && !opts.InsecureSkipSignature {
+		return record, "", ErrNoPinnedKey
+	}
+	noTrustedKey := opts.TrustStore == nil || opts.TrustStore.Empty()
+	if noTrustedKey && !opts.InsecureSkipSignature {
+		return record, "", ErrNoTrustedKey
+	}
+	remoteVersion, err := version.Parse(record.GetLatestVersion())
+	if err != nil {
+		return record, "", fmt.Errorf("parse latest version failed: %w", err)
+	}
+	asset, err := ReleaseAssetName(opts.App, remoteVersion, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return record, "", err
+	}
+	assetURL := record.GetDownloadURL()
+	if assetURL == "" {
+		assetURL = releaseDownloadURL + "/" + remoteVersion.ToTag() + "/" + asset
+	}
+	exe := opts.ExecutablePath
+	if exe == "" {
+		if exe, err = os.Executable(); err != nil {
+			return record, "", fmt.Errorf("os.Executable() failed: %w", err)
+		}
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	httpClient := newHTTPClient(opts.Socks5ProxyURI, downloadTimeout)
+	checksum, err := download(httpClient, assetURL+".sha256.txt")
+	if err != nil {
+		return record, "", fmt.Errorf("download checksum failed: %w", err)
+	}
+	archive, err := download(httpClient, assetURL)
+	if err != nil {
+		return record, "", fmt.Errorf("download release asset failed: %w", err)
+	}
+	if err = verifySHA256(archive, checksum); err != nil {
+		return record, "", fmt.Errorf("verify release asset %s failed: %w", asset, err)
+	}
+	verified := "SHA-256 checksum only, because signature verification is skipped"
+	if !noTrustedKey {
+		signature, err := download(httpClient, assetURL+".minisig")
+		if err != nil {
+			return record, "", fmt.Errorf("download signature failed: %w", err)
+		}
+		pk, err := opts.TrustStore.Verify(archive, signature)
+		if err != nil {
+			return record, "", fmt.Errorf("verify signature of release asset %s failed: %w", asset, err)
+		}
+		verified = fmt.Sprintf("SHA-256 checksum and the signature of key %s", pk.ID)
+	}
+	binary, err := extractBinary(archive, asset, opts.App)
+	if err != nil {
+		return record, "", fmt.Errorf("extract binary from release asset %s failed: %w", asset, err)
+	}
+	if err = replaceExecutable(exe, binary); err != nil {
+		return record, "", fmt.Errorf("replace %q failed: %w", exe, err)
+	}
+
+	record.InstalledVersion = proto.String(remoteVersion.String())
+	msg = fmt.Sprintf("%s is updated from version %s to %s, verified by %s", opts.App, record.GetVersion(), remoteVersion.String(), verified)
+	return record, msg, nil
+}
+
+// ReleaseAssetName returns the name of the release asset that contains
+// the binary of the application for the operating system and architecture.
+func ReleaseAssetName(app string, v version.Version, goos, goarch string) (string, error) {
+	supported := map[string][]string{
+		"mieru": {"android/arm64", "darwin/amd64", "darwin/arm64", "linux/amd64", "linux/arm64", "linux/arm", "linux/riscv64", "windows/386", "windows/amd64"},
+		"mita":  {"linux/amd64", "linux/arm64"},
+	}
+	platforms, ok := supported[app]
+	if !ok {
+		return "", fmt.Errorf("application %q is not supported", app)
+	}
+	found := false
+	for _, p := range platforms {
+		if p == goos+"/"+goarch {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("%s doesn't have a release for %s/%s", app, goos, goarch)
+	}
+
+	osName := goos
+	if goos == "darwin" {
+		osName = "macos"
+	}
+	archName := goarch
+	switch goarch {
+	case "386":
+		archName = "x86"
+	case "arm":
+		archName = "armv7"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("%s_%s_%s_%s%s", app, v.String(), osName, archName, ext), nil
+}
+
+// download returns the content of the URL.
+func download(httpClient *http.Client, url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("http.Get() failed: %v [GitHub may be blocked in your network]", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP returned unexpected status code %d for %s", resp.StatusCode, url)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxReleaseAssetSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll() failed: %v", err)
+	}
+	if len(b) > maxReleaseAssetSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, maxReleaseAssetSize)
+	}
+	return b, nil
+}
+
+// verifySHA256 checks the data with the checksum file generated by
+// sha256sum. The first field of the checksum file is the hex encoded
+// SHA-256 checksum.
+func verifySHA256(data, checksumFile []byte) error {
+	fields := strings.Fields(string(checksumFile))
+	if len(fields) == 0 {
+		return fmt.Errorf("checksum file is empty")
+	}
+	want, err := hex.DecodeString(fields[0])
+	if err != nil || len(want) != sha256.Size {
+		return fmt.Errorf("checksum %q is invalid", fields[0])
+	}
+	got := sha256.Sum256(data)
+	if !bytes.Equal(got[:], want) {
+		return fmt.Errorf("SHA-256 checksum %x doesn't match the expected checksum %x", got, want)
+	}
+	return nil
+}
+
+// extractBinary returns the binary of the application from the release
+// asset, which is a .tar.gz or a .zip archive.
+func extractBinary(archive []byte, asset, app string) ([]byte, error) {
+	if strings.HasSuffix(asset, ".zip") {
+		r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("zip.NewReader() failed: %w", err)
+		}
+		for _, f := range r.File {
+			if path.Base(f.Name) != app+".exe" || f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("Open() failed: %w", err)
+			}
+			defer rc.Close()
+			return io.ReadAll(io.LimitReader(rc, maxReleaseAssetSize))
+		}
+		return nil, fmt.Errorf("%s.exe is not found", app)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("gzip.NewReader() failed: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s is not found", app)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tar Next() failed: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == app {
+			return io.ReadAll(io.LimitReader(tr, maxReleaseAssetSize))
+		}
+	}
+}
+
+// replaceExecutable atomically replaces the executable with the new
+// binary. The file mode of the executable is kept.
+func replaceExecutable(exe string, binary []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return fmt.Errorf("os.Stat() failed: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(exe), filepath.Base(exe)+".new*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp() failed: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Write() failed: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Sync() failed: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Close() failed: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("os.Chmod() failed: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		// A running executable can't be replaced on Windows,
+		// but it can be renamed.
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return fmt.Errorf("os.Rename() failed: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), exe); err != nil {
+			os.Rename(old, exe)
+			return fmt.Errorf("os.Rename() failed: %w", err)
+		}
+		return nil
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		return fmt.Errorf("os.Rename() failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+package updater
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/enfein/mieru/v3/pkg/minisign"
+	"github.com/enfein/mieru/v3/pkg/testtool"
+	"github.com/enfein/mieru/v3/pkg/version"
+)
+
+func TestReleaseAssetName(t *testing.T) {
+	v := version.Version{Major: 3, Minor: 18, Patch: 0}
+	testCases := []struct {
+		app    string
+		goos   string
+		goarch string
+		want   string
+	}{
+		{"mieru", "linux", "amd64", "mieru_3.18.0_linux_amd64.tar.gz"},
+		{"mieru", "linux", "arm", "mieru_3.18.0_linux_armv7.tar.gz"},
+		{"mieru", "darwin", "arm64", "mieru_3.18.0_macos_arm64.tar.gz"},
+		{"mieru", "windows", "386", "mieru_3.18.0_windows_x86.zip"},
+		{"mita", "linux", "arm64", "mita_3.18.0_linux_arm64.tar.gz"},
+		{"mita", "windows", "amd64", ""},
+		{"mieru", "freebsd", "amd64", ""},
+		{"other", "linux", "amd64", ""},
+	}
+	for _, tc := range testCases {
+		got, err := ReleaseAssetName(tc.app, v, tc.goos, tc.goarch)
+		if tc.want == "" {
+			if err == nil {
+				t.Errorf("ReleaseAssetName(%s, %s/%s) = %q, want error", tc.app, tc.goos, tc.goarch, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ReleaseAssetName(%s, %s/%s) failed: %v", tc.app, tc.goos, tc.goarch, err)
+		} else if got != tc.want {
+			t.Errorf("ReleaseAssetName(%s, %s/%s) = %q, want %q", tc.app, tc.goos, tc.goarch, got, tc.want)
+		}
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	binary := []byte("new binary")
+
+	var tgz bytes.Buffer
+	gw := gzip.NewWriter(&tgz)
+	tw := tar.NewWriter(gw)
+	tw.WriteHeader(&tar.Header{Name: "mieru", Mode: 0755, Size: int64(len(binary)), Typeflag: tar.TypeReg})
+	tw.Write(binary)
+	tw.Close()
+	gw.Close()
+	got, err := extractBinary(tgz.Bytes(), "mieru_3.18.0_linux_amd64.tar.gz", "mieru")
+	if err != nil {
+		t.Fatalf("extractBinary() failed: %v", err)
+	}
+	if !bytes.Equal(got, binary) {
+		t.Errorf("extractBinary() = %q, want %q", got, binary)
+	}
+	if _, err := extractBinary(tgz.Bytes(), "mita_3.18.0_linux_amd64.tar.gz", "mita"); err == nil {
+		t.Errorf("extractBinary() didn't report missing binary")
+	}
+
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	w, _ := zw.Create("mieru.exe")
+	w.Write(binary)
+	zw.Close()
+	got, err = extractBinary(zipped.Bytes(), "mieru_3.18.0_windows_amd64.zip", "mieru")
+	if err != nil {
+		t.Fatalf("extractBinary() failed: %v", err)
+	}
+	if !bytes.Equal(got, binary) {
+		t.Errorf("extractBinary() = %q, want %q", got, binary)
+	}
+}
+
+func TestSelfUpdate(t *testing.T) {
+	latest := version.Version{Major: 99, Minor: 0, Patch: 0}
+	asset, err := ReleaseAssetName("mieru", latest, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		t.Skipf("mieru doesn't have a release for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+	binaryName := "mieru"
+	if runtime.GOOS == "windows" {
+		binaryName = "mieru.exe"
+	}
+	binary := []byte("mieru 99.0.0")
+	var archive bytes.Buffer
+	if runtime.GOOS == "windows" {
+		zw := zip.NewWriter(&archive)
+		w, _ := zw.Create(binaryName)
+		w.Write(binary)
+		zw.Close()
+	} else {
+		gw := gzip.NewWriter(&archive)
+		tw := tar.NewWriter(gw)
+		tw.WriteHeader(&tar.Header{Name: binaryName, Mode: 0755, Size: int64(len(binary)), Typeflag: tar.TypeReg})
+		tw.Write(binary)
+		tw.Close()
+		gw.Close()
+	}
+	checksum := fmt.Sprintf("%x  %s\n", sha256.Sum256(archive.Bytes()), asset)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"tag_name": %q}`, latest.ToTag())
+	})
+	mux.HandleFunc("/download/"+latest.ToTag()+"/"+asset, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive.Bytes())
+	})
+	mux.HandleFunc("/download/"+latest.ToTag()+"/"+asset+".sha256.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, checksum)
+	})
+	var signature []byte
+	mux.HandleFunc("/download/"+latest.ToTag()+"/"+asset+".minisig", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(signature)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	oldAPIURL, oldDownloadURL := releasesAPIURL, releaseDownloadURL
+	releasesAPIURL, releaseDownloadURL = server.URL+"/releases", server.URL+"/download"
+	defer func() {
+		releasesAPIURL, releaseDownloadURL = oldAPIURL, oldDownloadURL
+	}()
+
+	exe := filepath.Join(t.TempDir(), binaryName)
+	if err := os.WriteFile(exe, []byte("mieru old"), 0755); err != nil {
+		t.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	opts := SelfUpdateOptions{
+		CheckUpdateOptions: CheckUpdateOptions{App: "mieru"},
+		ExecutablePath:     exe,
+	}
+
+	// The release is not installed if no public key is pinned in the binary.
+	oldKeys := version.ReleasePublicKeys
+	defer func() { version.ReleasePublicKeys = oldKeys }()
+	version.ReleasePublicKeys = nil
+	if _, _, err := SelfUpdate(opts); !errors.Is(err, ErrNoPinnedKey) {
+		t.Fatalf("SelfUpdate() returned %v, want %v", err, ErrNoPinnedKey)
+	}
+	if b, _ := os.ReadFile(exe); string(b) != "mieru old" {
+		t.Errorf("binary is replaced without a pinned public key")
+	}
+	version.ReleasePublicKeys = []string{"RWQf6LRCGA9i53mlYecO4IzT51TGPpvWucNSCh1CBM0QTaLn73Y7GFO3"}
+
+	// The release is not installed if no public key is trusted.
+	record, _, err := SelfUpdate(opts)
+	if !errors.Is(err, ErrNoTrustedKey) {
+		t.Fatalf("SelfUpdate() returned %v, want %v", err, ErrNoTrustedKey)
+	}
+	if record.GetError() == "" || record.GetInstalledVersion() != "" {
+		t.Errorf("update record is unexpected: %v", record)
+	}
+	if b, _ := os.ReadFile(exe); string(b) != "mieru old" {
+		t.Errorf("binary is replaced without a trusted public key")
+	}
+
+	// Checksum mismatch doesn't replace the binary,
+	// even if the signature verification is skipped.
+	opts.InsecureSkipSignature = true
+	goodChecksum := checksum
+	checksum = fmt.Sprintf("%x  %s\n", sha256.Sum256([]byte("other")), asset)
+	record, _, err = SelfUpdate(opts)
+	if err == nil {
+		t.Fatalf("SelfUpdate() didn't reject checksum mismatch")
+	}
+	if record.GetError() == "" || record.GetInstalledVersion() != "" {
+		t.Errorf("update record is unexpected: %v", record)
+	}
+	if b, _ := os.ReadFile(exe); string(b) != "mieru old" {
+		t.Errorf("binary is replaced after checksum mismatch")
+	}
+
+	checksum = goodChecksum
+	opts.InsecureSkipSignature = false
+
+	// The release must be signed by a trusted key.
+	signer, err := testtool.NewMinisignSigner()
+	if err != nil {
+		t.Fatalf("NewMinisignSigner() failed: %v", err)
+	}
+	untrusted, err := testtool.NewMinisignSigner()
+	if err != nil {
+		t.Fatalf("NewMinisignSigner() failed: %v", err)
+	}
+	opts.TrustStore, err = minisign.LoadTrustStore("", []string{signer.PublicKeyFile()})
+	if err != nil {
+		t.Fatalf("LoadTrustStore() failed: %v", err)
+	}
+	signature = untrusted.Sign(archive.Bytes(), true, "")
+	if _, _, err := SelfUpdate(opts); !errors.Is(err, minisign.ErrUntrustedKey) {
+		t.Fatalf("SelfUpdate() returned %v, want %v", err, minisign.ErrUntrustedKey)
+	}
+	if b, _ := os.ReadFile(exe); string(b) != "mieru old" {
+		t.Errorf("binary is replaced after signature verification failed")
+	}
+
+	signature = signer.Sign(archive.Bytes(), true, "")
+	record, msg, err := SelfUpdate(opts)
+	if err != nil {
+		t.Fatalf("SelfUpdate() failed: %v", err)
+	}
+	if !strings.Contains(msg, signer.KeyID()) {
+		t.Errorf("message %q doesn't contain the signing key ID", msg)
+	}
+	if record.GetInstalledVersion() != latest.String() {
+		t.Errorf("installed version = %q, want %q", record.GetInstalledVersion(), latest.String())
+	}
+	b, err := os.ReadFile(exe)
+	if err != nil {
+		t.Fatalf("os.ReadFile() failed: %v", err)
+	}
+	if !bytes.Equal(b, binary) {
+		t.Errorf("binary is not replaced")
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(exe)
+		if err != nil {
+			t.Fatalf("os.Stat() failed: %v", err)
+		}
+		if info.Mode().Perm() != 0755 {
+			t.Errorf("file mode = %v, want %v", info.Mode().Perm(), os.FileMode(0755))
+		}
+	}
+}
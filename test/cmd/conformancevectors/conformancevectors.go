@@ -0,0 +1,54 @@
+// Copyright (C) 2025  mieru authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// conformancevectors generates the golden test vectors of mieru protocol.
+// Alternative client and server implementations can use the vectors to
+// verify the compatibility with mieru.
+//
+// Run this program from the root of the repository to update the vectors:
+//
+//	go run ./test/cmd/conformancevectors
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/enfein/mieru/v3/pkg/log"
+	"github.com/enfein/mieru/v3/pkg/protocol"
+)
+
+var output = flag.String("output", "pkg/protocol/testdata/conformance_vectors.json", "Path of the output file. Use \"-\" to print to standard output.")
+
+func main() {
+	log.SetFormatter(&log.DaemonFormatter{})
+	flag.Parse()
+	vectors, err := protocol.GenerateConformanceVectors()
+	if err != nil {
+		log.Fatalf("GenerateConformanceVectors() failed: %v", err)
+	}
+	b, err := vectors.JSON()
+	if err != nil {
+		log.Fatalf("JSON() failed: %v", err)
+	}
+	if *output == "-" {
+		os.Stdout.Write(b)
+		return
+	}
+	if err := os.WriteFile(*output, b, 0644); err != nil {
+		log.Fatalf("os.WriteFile() failed: %v", err)
+	}
+	log.Infof("Conformance test vectors are written to %s", *output)
+}
@@ -206,7 +206,7 @@ func handleOneSocks5Conn(c client.Client, conn net.Conn) {
 		}
 
 		tunnel := apicommon.NewPacketOverStreamTunnel(proxyConn)
-		socks5.BidiCopyUDP(udpConn, tunnel)
+		socks5.BidiCopyUDP(udpConn, tunnel, nil)
 	}
 }
 